@@ -0,0 +1,475 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: profitify/v1/daily_summaries.proto
+
+package profitifyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DailySummary is a daily OHLCV bar.
+type DailySummary struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Ticker string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	// Date is the trading date as YYYY-MM-DD.
+	Date string `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	// Timestamp is the start of the session in unix seconds.
+	Timestamp        int64   `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Open             float32 `protobuf:"fixed32,4,opt,name=open,proto3" json:"open,omitempty"`
+	High             float32 `protobuf:"fixed32,5,opt,name=high,proto3" json:"high,omitempty"`
+	Low              float32 `protobuf:"fixed32,6,opt,name=low,proto3" json:"low,omitempty"`
+	Close            float32 `protobuf:"fixed32,7,opt,name=close,proto3" json:"close,omitempty"`
+	Volume           float32 `protobuf:"fixed32,8,opt,name=volume,proto3" json:"volume,omitempty"`
+	Vwap             float32 `protobuf:"fixed32,9,opt,name=vwap,proto3" json:"vwap,omitempty"`
+	TransactionCount int32   `protobuf:"varint,10,opt,name=transaction_count,json=transactionCount,proto3" json:"transaction_count,omitempty"`
+	Otc              bool    `protobuf:"varint,11,opt,name=otc,proto3" json:"otc,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DailySummary) Reset() {
+	*x = DailySummary{}
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DailySummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DailySummary) ProtoMessage() {}
+
+func (x *DailySummary) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DailySummary.ProtoReflect.Descriptor instead.
+func (*DailySummary) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_daily_summaries_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DailySummary) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *DailySummary) GetDate() string {
+	if x != nil {
+		return x.Date
+	}
+	return ""
+}
+
+func (x *DailySummary) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *DailySummary) GetOpen() float32 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *DailySummary) GetHigh() float32 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *DailySummary) GetLow() float32 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *DailySummary) GetClose() float32 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *DailySummary) GetVolume() float32 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *DailySummary) GetVwap() float32 {
+	if x != nil {
+		return x.Vwap
+	}
+	return 0
+}
+
+func (x *DailySummary) GetTransactionCount() int32 {
+	if x != nil {
+		return x.TransactionCount
+	}
+	return 0
+}
+
+func (x *DailySummary) GetOtc() bool {
+	if x != nil {
+		return x.Otc
+	}
+	return false
+}
+
+type ListDailySummariesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Symbol is case insensitive.
+	Symbol string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	// From is the first date as YYYY-MM-DD; empty starts a year before to,
+	// cut to the history the caller's plan allows.
+	From string `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	// To is the last date as YYYY-MM-DD, inclusive; empty is today.
+	To            string `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDailySummariesRequest) Reset() {
+	*x = ListDailySummariesRequest{}
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDailySummariesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDailySummariesRequest) ProtoMessage() {}
+
+func (x *ListDailySummariesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDailySummariesRequest.ProtoReflect.Descriptor instead.
+func (*ListDailySummariesRequest) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_daily_summaries_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListDailySummariesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *ListDailySummariesRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ListDailySummariesRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+type ListDailySummariesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ticker        string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Bars          []*DailySummary        `protobuf:"bytes,2,rep,name=bars,proto3" json:"bars,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDailySummariesResponse) Reset() {
+	*x = ListDailySummariesResponse{}
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDailySummariesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDailySummariesResponse) ProtoMessage() {}
+
+func (x *ListDailySummariesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDailySummariesResponse.ProtoReflect.Descriptor instead.
+func (*ListDailySummariesResponse) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_daily_summaries_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListDailySummariesResponse) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *ListDailySummariesResponse) GetBars() []*DailySummary {
+	if x != nil {
+		return x.Bars
+	}
+	return nil
+}
+
+type GetQuoteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Symbol is case insensitive.
+	Symbol        string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQuoteRequest) Reset() {
+	*x = GetQuoteRequest{}
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQuoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQuoteRequest) ProtoMessage() {}
+
+func (x *GetQuoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQuoteRequest.ProtoReflect.Descriptor instead.
+func (*GetQuoteRequest) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_daily_summaries_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetQuoteRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type Quote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latest        *DailySummary          `protobuf:"bytes,1,opt,name=latest,proto3" json:"latest,omitempty"`
+	PreviousClose float32                `protobuf:"fixed32,2,opt,name=previous_close,json=previousClose,proto3" json:"previous_close,omitempty"`
+	Change        float64                `protobuf:"fixed64,3,opt,name=change,proto3" json:"change,omitempty"`
+	ChangePercent float64                `protobuf:"fixed64,4,opt,name=change_percent,json=changePercent,proto3" json:"change_percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quote) Reset() {
+	*x = Quote{}
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quote) ProtoMessage() {}
+
+func (x *Quote) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_daily_summaries_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quote.ProtoReflect.Descriptor instead.
+func (*Quote) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_daily_summaries_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Quote) GetLatest() *DailySummary {
+	if x != nil {
+		return x.Latest
+	}
+	return nil
+}
+
+func (x *Quote) GetPreviousClose() float32 {
+	if x != nil {
+		return x.PreviousClose
+	}
+	return 0
+}
+
+func (x *Quote) GetChange() float64 {
+	if x != nil {
+		return x.Change
+	}
+	return 0
+}
+
+func (x *Quote) GetChangePercent() float64 {
+	if x != nil {
+		return x.ChangePercent
+	}
+	return 0
+}
+
+var File_profitify_v1_daily_summaries_proto protoreflect.FileDescriptor
+
+const file_profitify_v1_daily_summaries_proto_rawDesc = "" +
+	"\n" +
+	"\"profitify/v1/daily_summaries.proto\x12\fprofitify.v1\"\x93\x02\n" +
+	"\fDailySummary\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x12\n" +
+	"\x04date\x18\x02 \x01(\tR\x04date\x12\x1c\n" +
+	"\ttimestamp\x18\x03 \x01(\x03R\ttimestamp\x12\x12\n" +
+	"\x04open\x18\x04 \x01(\x02R\x04open\x12\x12\n" +
+	"\x04high\x18\x05 \x01(\x02R\x04high\x12\x10\n" +
+	"\x03low\x18\x06 \x01(\x02R\x03low\x12\x14\n" +
+	"\x05close\x18\a \x01(\x02R\x05close\x12\x16\n" +
+	"\x06volume\x18\b \x01(\x02R\x06volume\x12\x12\n" +
+	"\x04vwap\x18\t \x01(\x02R\x04vwap\x12+\n" +
+	"\x11transaction_count\x18\n" +
+	" \x01(\x05R\x10transactionCount\x12\x10\n" +
+	"\x03otc\x18\v \x01(\bR\x03otc\"W\n" +
+	"\x19ListDailySummariesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\"d\n" +
+	"\x1aListDailySummariesResponse\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12.\n" +
+	"\x04bars\x18\x02 \x03(\v2\x1a.profitify.v1.DailySummaryR\x04bars\")\n" +
+	"\x0fGetQuoteRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\xa1\x01\n" +
+	"\x05Quote\x122\n" +
+	"\x06latest\x18\x01 \x01(\v2\x1a.profitify.v1.DailySummaryR\x06latest\x12%\n" +
+	"\x0eprevious_close\x18\x02 \x01(\x02R\rpreviousClose\x12\x16\n" +
+	"\x06change\x18\x03 \x01(\x01R\x06change\x12%\n" +
+	"\x0echange_percent\x18\x04 \x01(\x01R\rchangePercent2\x9d\x02\n" +
+	"\x13DailySummaryService\x12g\n" +
+	"\x12ListDailySummaries\x12'.profitify.v1.ListDailySummariesRequest\x1a(.profitify.v1.ListDailySummariesResponse\x12]\n" +
+	"\x14StreamDailySummaries\x12'.profitify.v1.ListDailySummariesRequest\x1a\x1a.profitify.v1.DailySummary0\x01\x12>\n" +
+	"\bGetQuote\x12\x1d.profitify.v1.GetQuoteRequest\x1a\x13.profitify.v1.QuoteB6Z4profitify-backend/api/proto/profitify/v1;profitifyv1b\x06proto3"
+
+var (
+	file_profitify_v1_daily_summaries_proto_rawDescOnce sync.Once
+	file_profitify_v1_daily_summaries_proto_rawDescData []byte
+)
+
+func file_profitify_v1_daily_summaries_proto_rawDescGZIP() []byte {
+	file_profitify_v1_daily_summaries_proto_rawDescOnce.Do(func() {
+		file_profitify_v1_daily_summaries_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_profitify_v1_daily_summaries_proto_rawDesc), len(file_profitify_v1_daily_summaries_proto_rawDesc)))
+	})
+	return file_profitify_v1_daily_summaries_proto_rawDescData
+}
+
+var file_profitify_v1_daily_summaries_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_profitify_v1_daily_summaries_proto_goTypes = []any{
+	(*DailySummary)(nil),               // 0: profitify.v1.DailySummary
+	(*ListDailySummariesRequest)(nil),  // 1: profitify.v1.ListDailySummariesRequest
+	(*ListDailySummariesResponse)(nil), // 2: profitify.v1.ListDailySummariesResponse
+	(*GetQuoteRequest)(nil),            // 3: profitify.v1.GetQuoteRequest
+	(*Quote)(nil),                      // 4: profitify.v1.Quote
+}
+var file_profitify_v1_daily_summaries_proto_depIdxs = []int32{
+	0, // 0: profitify.v1.ListDailySummariesResponse.bars:type_name -> profitify.v1.DailySummary
+	0, // 1: profitify.v1.Quote.latest:type_name -> profitify.v1.DailySummary
+	1, // 2: profitify.v1.DailySummaryService.ListDailySummaries:input_type -> profitify.v1.ListDailySummariesRequest
+	1, // 3: profitify.v1.DailySummaryService.StreamDailySummaries:input_type -> profitify.v1.ListDailySummariesRequest
+	3, // 4: profitify.v1.DailySummaryService.GetQuote:input_type -> profitify.v1.GetQuoteRequest
+	2, // 5: profitify.v1.DailySummaryService.ListDailySummaries:output_type -> profitify.v1.ListDailySummariesResponse
+	0, // 6: profitify.v1.DailySummaryService.StreamDailySummaries:output_type -> profitify.v1.DailySummary
+	4, // 7: profitify.v1.DailySummaryService.GetQuote:output_type -> profitify.v1.Quote
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_profitify_v1_daily_summaries_proto_init() }
+func file_profitify_v1_daily_summaries_proto_init() {
+	if File_profitify_v1_daily_summaries_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_profitify_v1_daily_summaries_proto_rawDesc), len(file_profitify_v1_daily_summaries_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_profitify_v1_daily_summaries_proto_goTypes,
+		DependencyIndexes: file_profitify_v1_daily_summaries_proto_depIdxs,
+		MessageInfos:      file_profitify_v1_daily_summaries_proto_msgTypes,
+	}.Build()
+	File_profitify_v1_daily_summaries_proto = out.File
+	file_profitify_v1_daily_summaries_proto_goTypes = nil
+	file_profitify_v1_daily_summaries_proto_depIdxs = nil
+}
@@ -0,0 +1,217 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: profitify/v1/daily_summaries.proto
+
+package profitifyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	DailySummaryService_ListDailySummaries_FullMethodName   = "/profitify.v1.DailySummaryService/ListDailySummaries"
+	DailySummaryService_StreamDailySummaries_FullMethodName = "/profitify.v1.DailySummaryService/StreamDailySummaries"
+	DailySummaryService_GetQuote_FullMethodName             = "/profitify.v1.DailySummaryService/GetQuote"
+)
+
+// DailySummaryServiceClient is the client API for DailySummaryService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// DailySummaryService serves the daily bars and quotes of
+// GET /api/tickers/{symbol}/daily and /quote.
+type DailySummaryServiceClient interface {
+	// ListDailySummaries returns the daily bars of a date range, oldest first.
+	ListDailySummaries(ctx context.Context, in *ListDailySummariesRequest, opts ...grpc.CallOption) (*ListDailySummariesResponse, error)
+	// StreamDailySummaries streams the bars of ListDailySummaries as they are
+	// read, so long ranges are never held in memory.
+	StreamDailySummaries(ctx context.Context, in *ListDailySummariesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DailySummary], error)
+	// GetQuote returns the latest daily bar with its change against the
+	// previous session, NOT_FOUND without bars.
+	GetQuote(ctx context.Context, in *GetQuoteRequest, opts ...grpc.CallOption) (*Quote, error)
+}
+
+type dailySummaryServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDailySummaryServiceClient(cc grpc.ClientConnInterface) DailySummaryServiceClient {
+	return &dailySummaryServiceClient{cc}
+}
+
+func (c *dailySummaryServiceClient) ListDailySummaries(ctx context.Context, in *ListDailySummariesRequest, opts ...grpc.CallOption) (*ListDailySummariesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDailySummariesResponse)
+	err := c.cc.Invoke(ctx, DailySummaryService_ListDailySummaries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dailySummaryServiceClient) StreamDailySummaries(ctx context.Context, in *ListDailySummariesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DailySummary], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &DailySummaryService_ServiceDesc.Streams[0], DailySummaryService_StreamDailySummaries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListDailySummariesRequest, DailySummary]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DailySummaryService_StreamDailySummariesClient = grpc.ServerStreamingClient[DailySummary]
+
+func (c *dailySummaryServiceClient) GetQuote(ctx context.Context, in *GetQuoteRequest, opts ...grpc.CallOption) (*Quote, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Quote)
+	err := c.cc.Invoke(ctx, DailySummaryService_GetQuote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DailySummaryServiceServer is the server API for DailySummaryService service.
+// All implementations must embed UnimplementedDailySummaryServiceServer
+// for forward compatibility.
+//
+// DailySummaryService serves the daily bars and quotes of
+// GET /api/tickers/{symbol}/daily and /quote.
+type DailySummaryServiceServer interface {
+	// ListDailySummaries returns the daily bars of a date range, oldest first.
+	ListDailySummaries(context.Context, *ListDailySummariesRequest) (*ListDailySummariesResponse, error)
+	// StreamDailySummaries streams the bars of ListDailySummaries as they are
+	// read, so long ranges are never held in memory.
+	StreamDailySummaries(*ListDailySummariesRequest, grpc.ServerStreamingServer[DailySummary]) error
+	// GetQuote returns the latest daily bar with its change against the
+	// previous session, NOT_FOUND without bars.
+	GetQuote(context.Context, *GetQuoteRequest) (*Quote, error)
+	mustEmbedUnimplementedDailySummaryServiceServer()
+}
+
+// UnimplementedDailySummaryServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDailySummaryServiceServer struct{}
+
+func (UnimplementedDailySummaryServiceServer) ListDailySummaries(context.Context, *ListDailySummariesRequest) (*ListDailySummariesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDailySummaries not implemented")
+}
+func (UnimplementedDailySummaryServiceServer) StreamDailySummaries(*ListDailySummariesRequest, grpc.ServerStreamingServer[DailySummary]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamDailySummaries not implemented")
+}
+func (UnimplementedDailySummaryServiceServer) GetQuote(context.Context, *GetQuoteRequest) (*Quote, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuote not implemented")
+}
+func (UnimplementedDailySummaryServiceServer) mustEmbedUnimplementedDailySummaryServiceServer() {}
+func (UnimplementedDailySummaryServiceServer) testEmbeddedByValue()                             {}
+
+// UnsafeDailySummaryServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DailySummaryServiceServer will
+// result in compilation errors.
+type UnsafeDailySummaryServiceServer interface {
+	mustEmbedUnimplementedDailySummaryServiceServer()
+}
+
+func RegisterDailySummaryServiceServer(s grpc.ServiceRegistrar, srv DailySummaryServiceServer) {
+	// If the following call pancis, it indicates UnimplementedDailySummaryServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&DailySummaryService_ServiceDesc, srv)
+}
+
+func _DailySummaryService_ListDailySummaries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDailySummariesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DailySummaryServiceServer).ListDailySummaries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DailySummaryService_ListDailySummaries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DailySummaryServiceServer).ListDailySummaries(ctx, req.(*ListDailySummariesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DailySummaryService_StreamDailySummaries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListDailySummariesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DailySummaryServiceServer).StreamDailySummaries(m, &grpc.GenericServerStream[ListDailySummariesRequest, DailySummary]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type DailySummaryService_StreamDailySummariesServer = grpc.ServerStreamingServer[DailySummary]
+
+func _DailySummaryService_GetQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DailySummaryServiceServer).GetQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DailySummaryService_GetQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DailySummaryServiceServer).GetQuote(ctx, req.(*GetQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DailySummaryService_ServiceDesc is the grpc.ServiceDesc for DailySummaryService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DailySummaryService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "profitify.v1.DailySummaryService",
+	HandlerType: (*DailySummaryServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListDailySummaries",
+			Handler:    _DailySummaryService_ListDailySummaries_Handler,
+		},
+		{
+			MethodName: "GetQuote",
+			Handler:    _DailySummaryService_GetQuote_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamDailySummaries",
+			Handler:       _DailySummaryService_StreamDailySummaries_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "profitify/v1/daily_summaries.proto",
+}
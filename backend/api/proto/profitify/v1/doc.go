@@ -0,0 +1,6 @@
+// Package profitifyv1 holds the messages and services of the gRPC API,
+// generated from the .proto files next to it with protoc-gen-go and
+// protoc-gen-go-grpc.
+package profitifyv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative profitify/v1/tickers.proto profitify/v1/daily_summaries.proto
@@ -0,0 +1,391 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.7
+// 	protoc        (unknown)
+// source: profitify/v1/tickers.proto
+
+package profitifyv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Ticker is a listed security. Times are unix seconds, zero when unknown.
+type Ticker struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Ticker          string                 `protobuf:"bytes,1,opt,name=ticker,proto3" json:"ticker,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Market          string                 `protobuf:"bytes,3,opt,name=market,proto3" json:"market,omitempty"`
+	Locale          string                 `protobuf:"bytes,4,opt,name=locale,proto3" json:"locale,omitempty"`
+	PrimaryExchange string                 `protobuf:"bytes,5,opt,name=primary_exchange,json=primaryExchange,proto3" json:"primary_exchange,omitempty"`
+	ShareClassFigi  string                 `protobuf:"bytes,6,opt,name=share_class_figi,json=shareClassFigi,proto3" json:"share_class_figi,omitempty"`
+	Type            string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	Sector          string                 `protobuf:"bytes,8,opt,name=sector,proto3" json:"sector,omitempty"`
+	Industry        string                 `protobuf:"bytes,9,opt,name=industry,proto3" json:"industry,omitempty"`
+	Active          bool                   `protobuf:"varint,10,opt,name=active,proto3" json:"active,omitempty"`
+	Cik             string                 `protobuf:"bytes,11,opt,name=cik,proto3" json:"cik,omitempty"`
+	CompositeFigi   string                 `protobuf:"bytes,12,opt,name=composite_figi,json=compositeFigi,proto3" json:"composite_figi,omitempty"`
+	Currency        string                 `protobuf:"bytes,13,opt,name=currency,proto3" json:"currency,omitempty"`
+	DelistedUtc     int64                  `protobuf:"varint,14,opt,name=delisted_utc,json=delistedUtc,proto3" json:"delisted_utc,omitempty"`
+	LastUpdatedUtc  int64                  `protobuf:"varint,15,opt,name=last_updated_utc,json=lastUpdatedUtc,proto3" json:"last_updated_utc,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Ticker) Reset() {
+	*x = Ticker{}
+	mi := &file_profitify_v1_tickers_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Ticker) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Ticker) ProtoMessage() {}
+
+func (x *Ticker) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_tickers_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Ticker.ProtoReflect.Descriptor instead.
+func (*Ticker) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_tickers_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Ticker) GetTicker() string {
+	if x != nil {
+		return x.Ticker
+	}
+	return ""
+}
+
+func (x *Ticker) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Ticker) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *Ticker) GetLocale() string {
+	if x != nil {
+		return x.Locale
+	}
+	return ""
+}
+
+func (x *Ticker) GetPrimaryExchange() string {
+	if x != nil {
+		return x.PrimaryExchange
+	}
+	return ""
+}
+
+func (x *Ticker) GetShareClassFigi() string {
+	if x != nil {
+		return x.ShareClassFigi
+	}
+	return ""
+}
+
+func (x *Ticker) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Ticker) GetSector() string {
+	if x != nil {
+		return x.Sector
+	}
+	return ""
+}
+
+func (x *Ticker) GetIndustry() string {
+	if x != nil {
+		return x.Industry
+	}
+	return ""
+}
+
+func (x *Ticker) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Ticker) GetCik() string {
+	if x != nil {
+		return x.Cik
+	}
+	return ""
+}
+
+func (x *Ticker) GetCompositeFigi() string {
+	if x != nil {
+		return x.CompositeFigi
+	}
+	return ""
+}
+
+func (x *Ticker) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Ticker) GetDelistedUtc() int64 {
+	if x != nil {
+		return x.DelistedUtc
+	}
+	return 0
+}
+
+func (x *Ticker) GetLastUpdatedUtc() int64 {
+	if x != nil {
+		return x.LastUpdatedUtc
+	}
+	return 0
+}
+
+type GetTickerRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Symbol is case insensitive.
+	Symbol        string `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTickerRequest) Reset() {
+	*x = GetTickerRequest{}
+	mi := &file_profitify_v1_tickers_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTickerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTickerRequest) ProtoMessage() {}
+
+func (x *GetTickerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_tickers_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTickerRequest.ProtoReflect.Descriptor instead.
+func (*GetTickerRequest) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_tickers_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetTickerRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type ListActiveTickersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveTickersRequest) Reset() {
+	*x = ListActiveTickersRequest{}
+	mi := &file_profitify_v1_tickers_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveTickersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveTickersRequest) ProtoMessage() {}
+
+func (x *ListActiveTickersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_tickers_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveTickersRequest.ProtoReflect.Descriptor instead.
+func (*ListActiveTickersRequest) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_tickers_proto_rawDescGZIP(), []int{2}
+}
+
+type ListActiveTickersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tickers       []*Ticker              `protobuf:"bytes,1,rep,name=tickers,proto3" json:"tickers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveTickersResponse) Reset() {
+	*x = ListActiveTickersResponse{}
+	mi := &file_profitify_v1_tickers_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveTickersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveTickersResponse) ProtoMessage() {}
+
+func (x *ListActiveTickersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_profitify_v1_tickers_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveTickersResponse.ProtoReflect.Descriptor instead.
+func (*ListActiveTickersResponse) Descriptor() ([]byte, []int) {
+	return file_profitify_v1_tickers_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListActiveTickersResponse) GetTickers() []*Ticker {
+	if x != nil {
+		return x.Tickers
+	}
+	return nil
+}
+
+var File_profitify_v1_tickers_proto protoreflect.FileDescriptor
+
+const file_profitify_v1_tickers_proto_rawDesc = "" +
+	"\n" +
+	"\x1aprofitify/v1/tickers.proto\x12\fprofitify.v1\"\xbb\x03\n" +
+	"\x06Ticker\x12\x16\n" +
+	"\x06ticker\x18\x01 \x01(\tR\x06ticker\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06market\x18\x03 \x01(\tR\x06market\x12\x16\n" +
+	"\x06locale\x18\x04 \x01(\tR\x06locale\x12)\n" +
+	"\x10primary_exchange\x18\x05 \x01(\tR\x0fprimaryExchange\x12(\n" +
+	"\x10share_class_figi\x18\x06 \x01(\tR\x0eshareClassFigi\x12\x12\n" +
+	"\x04type\x18\a \x01(\tR\x04type\x12\x16\n" +
+	"\x06sector\x18\b \x01(\tR\x06sector\x12\x1a\n" +
+	"\bindustry\x18\t \x01(\tR\bindustry\x12\x16\n" +
+	"\x06active\x18\n" +
+	" \x01(\bR\x06active\x12\x10\n" +
+	"\x03cik\x18\v \x01(\tR\x03cik\x12%\n" +
+	"\x0ecomposite_figi\x18\f \x01(\tR\rcompositeFigi\x12\x1a\n" +
+	"\bcurrency\x18\r \x01(\tR\bcurrency\x12!\n" +
+	"\fdelisted_utc\x18\x0e \x01(\x03R\vdelistedUtc\x12(\n" +
+	"\x10last_updated_utc\x18\x0f \x01(\x03R\x0elastUpdatedUtc\"*\n" +
+	"\x10GetTickerRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\x1a\n" +
+	"\x18ListActiveTickersRequest\"K\n" +
+	"\x19ListActiveTickersResponse\x12.\n" +
+	"\atickers\x18\x01 \x03(\v2\x14.profitify.v1.TickerR\atickers2\xb8\x01\n" +
+	"\rTickerService\x12A\n" +
+	"\tGetTicker\x12\x1e.profitify.v1.GetTickerRequest\x1a\x14.profitify.v1.Ticker\x12d\n" +
+	"\x11ListActiveTickers\x12&.profitify.v1.ListActiveTickersRequest\x1a'.profitify.v1.ListActiveTickersResponseB6Z4profitify-backend/api/proto/profitify/v1;profitifyv1b\x06proto3"
+
+var (
+	file_profitify_v1_tickers_proto_rawDescOnce sync.Once
+	file_profitify_v1_tickers_proto_rawDescData []byte
+)
+
+func file_profitify_v1_tickers_proto_rawDescGZIP() []byte {
+	file_profitify_v1_tickers_proto_rawDescOnce.Do(func() {
+		file_profitify_v1_tickers_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_profitify_v1_tickers_proto_rawDesc), len(file_profitify_v1_tickers_proto_rawDesc)))
+	})
+	return file_profitify_v1_tickers_proto_rawDescData
+}
+
+var file_profitify_v1_tickers_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_profitify_v1_tickers_proto_goTypes = []any{
+	(*Ticker)(nil),                    // 0: profitify.v1.Ticker
+	(*GetTickerRequest)(nil),          // 1: profitify.v1.GetTickerRequest
+	(*ListActiveTickersRequest)(nil),  // 2: profitify.v1.ListActiveTickersRequest
+	(*ListActiveTickersResponse)(nil), // 3: profitify.v1.ListActiveTickersResponse
+}
+var file_profitify_v1_tickers_proto_depIdxs = []int32{
+	0, // 0: profitify.v1.ListActiveTickersResponse.tickers:type_name -> profitify.v1.Ticker
+	1, // 1: profitify.v1.TickerService.GetTicker:input_type -> profitify.v1.GetTickerRequest
+	2, // 2: profitify.v1.TickerService.ListActiveTickers:input_type -> profitify.v1.ListActiveTickersRequest
+	0, // 3: profitify.v1.TickerService.GetTicker:output_type -> profitify.v1.Ticker
+	3, // 4: profitify.v1.TickerService.ListActiveTickers:output_type -> profitify.v1.ListActiveTickersResponse
+	3, // [3:5] is the sub-list for method output_type
+	1, // [1:3] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_profitify_v1_tickers_proto_init() }
+func file_profitify_v1_tickers_proto_init() {
+	if File_profitify_v1_tickers_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_profitify_v1_tickers_proto_rawDesc), len(file_profitify_v1_tickers_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_profitify_v1_tickers_proto_goTypes,
+		DependencyIndexes: file_profitify_v1_tickers_proto_depIdxs,
+		MessageInfos:      file_profitify_v1_tickers_proto_msgTypes,
+	}.Build()
+	File_profitify_v1_tickers_proto = out.File
+	file_profitify_v1_tickers_proto_goTypes = nil
+	file_profitify_v1_tickers_proto_depIdxs = nil
+}
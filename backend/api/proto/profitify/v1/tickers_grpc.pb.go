@@ -0,0 +1,167 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: profitify/v1/tickers.proto
+
+package profitifyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TickerService_GetTicker_FullMethodName         = "/profitify.v1.TickerService/GetTicker"
+	TickerService_ListActiveTickers_FullMethodName = "/profitify.v1.TickerService/ListActiveTickers"
+)
+
+// TickerServiceClient is the client API for TickerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TickerService serves the ticker reference data of GET /api/tickers.
+type TickerServiceClient interface {
+	// GetTicker returns a ticker, NOT_FOUND when the symbol is unknown.
+	GetTicker(ctx context.Context, in *GetTickerRequest, opts ...grpc.CallOption) (*Ticker, error)
+	// ListActiveTickers returns the active tickers ordered by symbol.
+	ListActiveTickers(ctx context.Context, in *ListActiveTickersRequest, opts ...grpc.CallOption) (*ListActiveTickersResponse, error)
+}
+
+type tickerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTickerServiceClient(cc grpc.ClientConnInterface) TickerServiceClient {
+	return &tickerServiceClient{cc}
+}
+
+func (c *tickerServiceClient) GetTicker(ctx context.Context, in *GetTickerRequest, opts ...grpc.CallOption) (*Ticker, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Ticker)
+	err := c.cc.Invoke(ctx, TickerService_GetTicker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tickerServiceClient) ListActiveTickers(ctx context.Context, in *ListActiveTickersRequest, opts ...grpc.CallOption) (*ListActiveTickersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActiveTickersResponse)
+	err := c.cc.Invoke(ctx, TickerService_ListActiveTickers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TickerServiceServer is the server API for TickerService service.
+// All implementations must embed UnimplementedTickerServiceServer
+// for forward compatibility.
+//
+// TickerService serves the ticker reference data of GET /api/tickers.
+type TickerServiceServer interface {
+	// GetTicker returns a ticker, NOT_FOUND when the symbol is unknown.
+	GetTicker(context.Context, *GetTickerRequest) (*Ticker, error)
+	// ListActiveTickers returns the active tickers ordered by symbol.
+	ListActiveTickers(context.Context, *ListActiveTickersRequest) (*ListActiveTickersResponse, error)
+	mustEmbedUnimplementedTickerServiceServer()
+}
+
+// UnimplementedTickerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTickerServiceServer struct{}
+
+func (UnimplementedTickerServiceServer) GetTicker(context.Context, *GetTickerRequest) (*Ticker, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTicker not implemented")
+}
+func (UnimplementedTickerServiceServer) ListActiveTickers(context.Context, *ListActiveTickersRequest) (*ListActiveTickersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActiveTickers not implemented")
+}
+func (UnimplementedTickerServiceServer) mustEmbedUnimplementedTickerServiceServer() {}
+func (UnimplementedTickerServiceServer) testEmbeddedByValue()                       {}
+
+// UnsafeTickerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TickerServiceServer will
+// result in compilation errors.
+type UnsafeTickerServiceServer interface {
+	mustEmbedUnimplementedTickerServiceServer()
+}
+
+func RegisterTickerServiceServer(s grpc.ServiceRegistrar, srv TickerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTickerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TickerService_ServiceDesc, srv)
+}
+
+func _TickerService_GetTicker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTickerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TickerServiceServer).GetTicker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TickerService_GetTicker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TickerServiceServer).GetTicker(ctx, req.(*GetTickerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TickerService_ListActiveTickers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveTickersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TickerServiceServer).ListActiveTickers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TickerService_ListActiveTickers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TickerServiceServer).ListActiveTickers(ctx, req.(*ListActiveTickersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TickerService_ServiceDesc is the grpc.ServiceDesc for TickerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TickerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "profitify.v1.TickerService",
+	HandlerType: (*TickerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTicker",
+			Handler:    _TickerService_GetTicker_Handler,
+		},
+		{
+			MethodName: "ListActiveTickers",
+			Handler:    _TickerService_ListActiveTickers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "profitify/v1/tickers.proto",
+}
@@ -0,0 +1,319 @@
+// Command loadtest sends a steady stream of API requests to a running backend
+// and reports throughput, errors and latency.
+//
+//	go run ./cmd/loadtest --duration 1m --concurrency 16
+//	go run ./cmd/loadtest --paths /api/v1/tickers/AAPL/daily,/api/v1/prices?symbols=AAPL,MSFT --rate 200
+//	go run ./cmd/loadtest --soak --duration 4h --max-heap-growth 0.25
+//
+// With --soak it runs for hours while scraping the server's heap and
+// goroutine counts from /metrics, and fails if they keep growing past the
+// thresholds once warmed up: the leaks long-lived connections and caches
+// cause show up as slow growth no short run notices.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"profitify-backend/internal/middleware"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultPaths are read-only routes served from the seeded tables
+var defaultPaths = []string{
+	"/api/v1/tickers",
+	"/api/v1/tickers/AAPL",
+	"/api/v1/tickers/AAPL/daily",
+	"/api/v1/tickers/MSFT/quote",
+	"/api/v1/prices?symbols=AAPL,MSFT,GOOGL",
+}
+
+// defaultSoakDuration is how long --soak runs without --duration
+const defaultSoakDuration = 2 * time.Hour
+
+// options are the command's flags
+type options struct {
+	target      string
+	paths       []string
+	apiKey      string
+	concurrency int
+	rate        int
+	duration    time.Duration
+	timeout     time.Duration
+
+	soak bool
+	soakOptions
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := newRootCommand(os.Stdout).ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand(out io.Writer) *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:          "loadtest",
+		Short:        "Load test a running backend, or soak test it for leaks with --soak",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.soak && !cmd.Flags().Changed("duration") {
+				opts.duration = defaultSoakDuration
+			}
+			if err := opts.validate(); err != nil {
+				return err
+			}
+			if opts.soak {
+				return opts.runSoak(cmd.Context(), out)
+			}
+			return opts.runLoad(cmd.Context(), out)
+		},
+	}
+
+	flags := root.Flags()
+	flags.StringVar(&opts.target, "target", "http://localhost:8080", "base URL of the backend")
+	flags.StringSliceVar(&opts.paths, "paths", defaultPaths, "comma-separated paths to request in turn")
+	flags.StringVar(&opts.apiKey, "api-key", os.Getenv("LOADTEST_API_KEY"), "API key to send, when the backend requires one (default $LOADTEST_API_KEY)")
+	flags.IntVar(&opts.concurrency, "concurrency", 8, "requests in flight at once")
+	flags.IntVar(&opts.rate, "rate", 0, "requests per second across all workers (default as fast as the backend answers)")
+	flags.DurationVar(&opts.duration, "duration", time.Minute, fmt.Sprintf("how long to send requests (default %s with --soak)", defaultSoakDuration))
+	flags.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout of each request")
+
+	flags.BoolVar(&opts.soak, "soak", false, "scrape /metrics while sending requests and fail on heap or goroutine growth")
+	flags.DurationVar(&opts.scrapeInterval, "scrape-interval", time.Minute, "how often --soak scrapes /metrics")
+	flags.DurationVar(&opts.warmup, "warmup", 10*time.Minute, "how long --soak lets caches and pools fill before measuring growth")
+	flags.IntVar(&opts.window, "window", 5, "scrapes whose lowest readings --soak compares, at the start and end of the run")
+	flags.Float64Var(&opts.maxHeapGrowth, "max-heap-growth", 0.5, "fraction the in-use heap may grow by after the warmup")
+	flags.IntVar(&opts.maxGoroutineGrowth, "max-goroutine-growth", 50, "goroutines that may be added after the warmup")
+	flags.StringVar(&opts.samplesFile, "samples", "", "CSV file to write every --soak scrape to, for graphing")
+
+	return root
+}
+
+func (o *options) validate() error {
+	if len(o.paths) == 0 {
+		return errors.New("--paths lists no paths")
+	}
+	for _, path := range o.paths {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("path %q must start with /", path)
+		}
+	}
+	if o.concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+	if o.rate < 0 {
+		return errors.New("--rate must not be negative")
+	}
+	if o.duration <= 0 {
+		return errors.New("--duration must be positive")
+	}
+	if o.soak {
+		return o.soakOptions.validate(o.duration)
+	}
+	return nil
+}
+
+func (o *options) runLoad(ctx context.Context, out io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, o.duration)
+	defer cancel()
+
+	stats := &stats{}
+	o.sendRequests(ctx, stats)
+
+	summary := stats.take()
+	fmt.Fprintln(out, summary)
+	if summary.requests == 0 {
+		return errors.New("no requests were sent")
+	}
+	if summary.failed > 0 {
+		return fmt.Errorf("%d of %d requests failed", summary.failed, summary.requests)
+	}
+	return nil
+}
+
+// sendRequests requests the paths in turn from o.concurrency workers until
+// ctx is done, recording every response in stats
+func (o *options) sendRequests(ctx context.Context, stats *stats) {
+	client := &http.Client{
+		Timeout: o.timeout,
+		Transport: &http.Transport{
+			MaxIdleConnsPerHost: o.concurrency,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	// Without --rate workers send the next request as soon as one is answered
+	var tokens <-chan time.Time
+	if o.rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(o.rate))
+		defer ticker.Stop()
+		tokens = ticker.C
+	}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < o.concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := worker; ; i += o.concurrency {
+				if tokens != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-tokens:
+					}
+				}
+				if ctx.Err() != nil {
+					return
+				}
+				o.request(ctx, client, o.paths[i%len(o.paths)], stats)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// request sends one GET and records its status and latency. Requests cut
+// short by the end of the run are not recorded.
+func (o *options) request(ctx context.Context, client *http.Client, path string, stats *stats) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.target+path, nil)
+	if err != nil {
+		stats.record(0, 0, err)
+		return
+	}
+	if o.apiKey != "" {
+		req.Header.Set(middleware.APIKeyHeader, o.apiKey)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err == nil {
+		// Reading the body to the end lets the connection be reused
+		_, err = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	if ctx.Err() != nil {
+		return
+	}
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	stats.record(status, time.Since(start), err)
+}
+
+// stats collects the responses of the requests sent since it was last taken
+type stats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	failed    int
+	statuses  map[int]int
+	errors    map[string]int
+}
+
+func (s *stats) record(status int, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.statuses == nil {
+		s.statuses = make(map[int]int)
+		s.errors = make(map[string]int)
+	}
+	s.latencies = append(s.latencies, latency)
+	switch {
+	case err != nil:
+		s.failed++
+		s.errors[err.Error()]++
+	case status >= 400:
+		s.failed++
+		s.statuses[status]++
+	default:
+		s.statuses[status]++
+	}
+}
+
+// take summarizes the responses recorded so far and starts over
+func (s *stats) take() summary {
+	s.mu.Lock()
+	latencies, failed, statuses, errs := s.latencies, s.failed, s.statuses, s.errors
+	s.latencies, s.failed, s.statuses, s.errors = nil, 0, nil, nil
+	s.mu.Unlock()
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	return summary{
+		requests: len(latencies),
+		failed:   failed,
+		statuses: statuses,
+		errors:   errs,
+		p50:      percentile(latencies, 0.50),
+		p95:      percentile(latencies, 0.95),
+		p99:      percentile(latencies, 0.99),
+		max:      percentile(latencies, 1),
+	}
+}
+
+// percentile returns the latency p of sorted latencies are at or below
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(p*float64(len(sorted))+0.5) - 1
+	return sorted[min(max(i, 0), len(sorted)-1)]
+}
+
+// summary describes the responses to a batch of requests
+type summary struct {
+	requests           int
+	failed             int
+	statuses           map[int]int
+	errors             map[string]int
+	p50, p95, p99, max time.Duration
+}
+
+func (s summary) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d requests, %d failed, latency p50 %s p95 %s p99 %s max %s",
+		s.requests, s.failed, round(s.p50), round(s.p95), round(s.p99), round(s.max))
+
+	codes := make([]int, 0, len(s.statuses))
+	for code := range s.statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Fprintf(&b, "\n  %d: %d", code, s.statuses[code])
+	}
+
+	errs := make([]string, 0, len(s.errors))
+	for err := range s.errors {
+		errs = append(errs, err)
+	}
+	sort.Strings(errs)
+	for _, err := range errs {
+		fmt.Fprintf(&b, "\n  %s: %d", err, s.errors[err])
+	}
+	return b.String()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
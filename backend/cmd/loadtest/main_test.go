@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/middleware"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 1; i <= 100; i++ {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(latencies, 0.50))
+	assert.Equal(t, 99*time.Millisecond, percentile(latencies, 0.99))
+	assert.Equal(t, 100*time.Millisecond, percentile(latencies, 1))
+	assert.Equal(t, time.Millisecond, percentile(latencies[:1], 0.99))
+	assert.Zero(t, percentile(nil, 0.5))
+}
+
+func TestStats(t *testing.T) {
+	s := &stats{}
+	s.record(http.StatusOK, 2*time.Millisecond, nil)
+	s.record(http.StatusOK, time.Millisecond, nil)
+	s.record(http.StatusServiceUnavailable, 3*time.Millisecond, nil)
+	s.record(0, 0, context.DeadlineExceeded)
+
+	got := s.take()
+	assert.Equal(t, 4, got.requests)
+	assert.Equal(t, 2, got.failed)
+	assert.Equal(t, map[int]int{http.StatusOK: 2, http.StatusServiceUnavailable: 1}, got.statuses)
+	assert.Equal(t, map[string]int{"context deadline exceeded": 1}, got.errors)
+	assert.Equal(t, 3*time.Millisecond, got.max)
+
+	assert.Zero(t, s.take().requests, "taking starts over")
+}
+
+func TestRunLoad(t *testing.T) {
+	var served, withKey atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+		if r.Header.Get(middleware.APIKeyHeader) == "secret" {
+			withKey.Add(1)
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	opts := &options{target: server.URL, paths: []string{"/api/tickers"}, apiKey: "secret", concurrency: 4, rate: 200, duration: 100 * time.Millisecond, timeout: time.Second}
+	require.NoError(t, opts.validate())
+	var out bytes.Buffer
+	require.NoError(t, opts.runLoad(context.Background(), &out))
+	assert.Contains(t, out.String(), "200: ")
+	assert.LessOrEqual(t, served.Load(), int64(25), "--rate limits the requests sent")
+	assert.Equal(t, served.Load(), withKey.Load())
+
+	opts.paths = []string{"/api/tickers", "/missing"}
+	opts.rate = 0
+	assert.ErrorContains(t, opts.runLoad(context.Background(), &out), "requests failed")
+}
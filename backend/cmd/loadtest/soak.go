@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// maxScrapeFailures is how many scrapes in a row may fail before the soak
+// gives up on the server
+const maxScrapeFailures = 3
+
+// ErrLeak is returned when the server's heap or goroutines grow past the
+// soak's thresholds
+var ErrLeak = errors.New("possible leak")
+
+// soakOptions are the flags of --soak
+type soakOptions struct {
+	scrapeInterval     time.Duration
+	warmup             time.Duration
+	window             int
+	maxHeapGrowth      float64
+	maxGoroutineGrowth int
+	samplesFile        string
+}
+
+func (o *soakOptions) validate(duration time.Duration) error {
+	if o.scrapeInterval <= 0 {
+		return errors.New("--scrape-interval must be positive")
+	}
+	if o.warmup < 0 {
+		return errors.New("--warmup must not be negative")
+	}
+	if o.window < 1 {
+		return errors.New("--window must be at least 1")
+	}
+	if o.maxHeapGrowth <= 0 {
+		return errors.New("--max-heap-growth must be positive")
+	}
+	if o.maxGoroutineGrowth < 0 {
+		return errors.New("--max-goroutine-growth must not be negative")
+	}
+	if scrapes := int((duration - o.warmup) / o.scrapeInterval); scrapes < 2*o.window {
+		return fmt.Errorf("--duration leaves %d scrapes after the warmup, but comparing two windows takes %d", max(scrapes, 0), 2*o.window)
+	}
+	return nil
+}
+
+// runSoak sends requests for o.duration while scraping the server's runtime
+// metrics, and fails as soon as they grow past the thresholds
+func (o *options) runSoak(ctx context.Context, out io.Writer) error {
+	scrape := &scraper{client: &http.Client{Timeout: o.timeout}, url: o.target + "/metrics"}
+	if _, err := scrape.sample(ctx); err != nil {
+		return fmt.Errorf("the soak needs the server's metrics: %w", err)
+	}
+
+	var samples *csv.Writer
+	if o.samplesFile != "" {
+		f, err := os.Create(o.samplesFile)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", o.samplesFile, err)
+		}
+		defer f.Close()
+		samples = csv.NewWriter(f)
+		defer samples.Flush()
+		_ = samples.Write([]string{"elapsed_seconds", "requests", "failed", "p99_ms", "heap_inuse_bytes", "goroutines", "resident_bytes"})
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.duration)
+	defer cancel()
+	stats := &stats{}
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		o.sendRequests(ctx, stats)
+	}()
+	defer func() {
+		cancel()
+		<-sent
+	}()
+
+	fmt.Fprintf(out, "soaking %s for %s; growth is measured after a %s warmup\n", o.target, o.duration, o.warmup)
+	detector := &leakDetector{window: o.window, maxHeapGrowth: o.maxHeapGrowth, maxGoroutineGrowth: o.maxGoroutineGrowth}
+	ticker := time.NewTicker(o.scrapeInterval)
+	defer ticker.Stop()
+	start := time.Now()
+	var failedScrapes, requests, failed int
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return ctx.Err()
+			}
+			fmt.Fprintf(out, "sent %d requests, %d failed\n", requests, failed)
+			if err := detector.verdict(); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, detector)
+			return nil
+		case <-ticker.C:
+		}
+
+		elapsed := time.Since(start).Truncate(time.Second)
+		summary := stats.take()
+		requests += summary.requests
+		failed += summary.failed
+
+		sample, err := scrape.sample(ctx)
+		if err != nil {
+			if failedScrapes++; failedScrapes >= maxScrapeFailures {
+				return fmt.Errorf("failed to scrape %s %d times in a row, last: %w", scrape.url, failedScrapes, err)
+			}
+			fmt.Fprintf(out, "%s scrape failed: %v\n", elapsed, err)
+			continue
+		}
+		failedScrapes = 0
+
+		fmt.Fprintf(out, "%s %d requests, %d failed, p99 %s, heap %.1f MiB, %d goroutines\n",
+			elapsed, summary.requests, summary.failed, round(summary.p99), sample.heapInuse/(1<<20), int(sample.goroutines))
+		if samples != nil {
+			_ = samples.Write([]string{
+				strconv.Itoa(int(elapsed.Seconds())), strconv.Itoa(summary.requests), strconv.Itoa(summary.failed),
+				strconv.FormatFloat(float64(summary.p99)/float64(time.Millisecond), 'f', 2, 64),
+				strconv.FormatFloat(sample.heapInuse, 'f', 0, 64), strconv.Itoa(int(sample.goroutines)),
+				strconv.FormatFloat(sample.resident, 'f', 0, 64),
+			})
+			samples.Flush()
+		}
+
+		if elapsed < o.warmup {
+			continue
+		}
+		detector.add(sample)
+		if err := detector.check(); err != nil {
+			return err
+		}
+	}
+}
+
+// runtimeSample is a scrape of the server's Go runtime and process metrics
+type runtimeSample struct {
+	heapInuse  float64
+	goroutines float64
+	// resident is 0 where the process collector cannot read it
+	resident float64
+}
+
+// scraper reads runtime samples from a Prometheus text endpoint
+type scraper struct {
+	client *http.Client
+	url    string
+}
+
+func (s *scraper) sample(ctx context.Context) (runtimeSample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return runtimeSample{}, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return runtimeSample{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return runtimeSample{}, fmt.Errorf("%s answered %s", s.url, resp.Status)
+	}
+	return parseSample(resp.Body)
+}
+
+// parseSample reads the runtime metrics from the Prometheus text format
+func parseSample(r io.Reader) (runtimeSample, error) {
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(r)
+	if err != nil {
+		return runtimeSample{}, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+
+	gauge := func(name string) (float64, bool) {
+		family, ok := families[name]
+		if !ok || family.GetType() != dto.MetricType_GAUGE || len(family.GetMetric()) == 0 {
+			return 0, false
+		}
+		return family.GetMetric()[0].GetGauge().GetValue(), true
+	}
+	heap, ok := gauge("go_memstats_heap_inuse_bytes")
+	if !ok {
+		return runtimeSample{}, errors.New("metrics lack go_memstats_heap_inuse_bytes")
+	}
+	goroutines, ok := gauge("go_goroutines")
+	if !ok {
+		return runtimeSample{}, errors.New("metrics lack go_goroutines")
+	}
+	resident, _ := gauge("process_resident_memory_bytes")
+	return runtimeSample{heapInuse: heap, goroutines: goroutines, resident: resident}, nil
+}
+
+// leakDetector compares the samples of the end of a soak with those of its
+// start. Each window is judged by its lowest readings: the heap drops to
+// what is live after every collection and goroutines to those not serving a
+// request, so only growth that survives both counts.
+type leakDetector struct {
+	window             int
+	maxHeapGrowth      float64
+	maxGoroutineGrowth int
+
+	// baseline holds the first window samples, recent the latest window
+	// samples after them
+	baseline []runtimeSample
+	recent   []runtimeSample
+}
+
+func (d *leakDetector) add(s runtimeSample) {
+	if len(d.baseline) < d.window {
+		d.baseline = append(d.baseline, s)
+		return
+	}
+	d.recent = append(d.recent, s)
+	if len(d.recent) > d.window {
+		d.recent = d.recent[1:]
+	}
+}
+
+// check returns an ErrLeak when the recent window has grown past a threshold
+// of the baseline, and nil until both windows are full
+func (d *leakDetector) check() error {
+	if len(d.recent) < d.window {
+		return nil
+	}
+	base, now := lowest(d.baseline), lowest(d.recent)
+	if now.heapInuse > base.heapInuse*(1+d.maxHeapGrowth) {
+		return fmt.Errorf("%w: in-use heap grew from %.1f to %.1f MiB, more than %.0f%%",
+			ErrLeak, base.heapInuse/(1<<20), now.heapInuse/(1<<20), d.maxHeapGrowth*100)
+	}
+	if now.goroutines > base.goroutines+float64(d.maxGoroutineGrowth) {
+		return fmt.Errorf("%w: goroutines grew from %d to %d, more than %d",
+			ErrLeak, int(base.goroutines), int(now.goroutines), d.maxGoroutineGrowth)
+	}
+	return nil
+}
+
+// verdict judges a finished soak, which needs a full recent window
+func (d *leakDetector) verdict() error {
+	if len(d.recent) < d.window {
+		return fmt.Errorf("only %d scrapes succeeded after the warmup, too few to compare two windows of %d", len(d.baseline)+len(d.recent), d.window)
+	}
+	return d.check()
+}
+
+func (d *leakDetector) String() string {
+	base, now := lowest(d.baseline), lowest(d.recent)
+	return fmt.Sprintf("in-use heap %.1f -> %.1f MiB, goroutines %d -> %d: within thresholds",
+		base.heapInuse/(1<<20), now.heapInuse/(1<<20), int(base.goroutines), int(now.goroutines))
+}
+
+// lowest returns the lowest reading of each metric of samples
+func lowest(samples []runtimeSample) runtimeSample {
+	low := samples[0]
+	for _, s := range samples[1:] {
+		low.heapInuse = min(low.heapInuse, s.heapInuse)
+		low.goroutines = min(low.goroutines, s.goroutines)
+		low.resident = min(low.resident, s.resident)
+	}
+	return low
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func metricsText(heap, goroutines int) string {
+	return fmt.Sprintf(`# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines %d
+# HELP go_memstats_heap_inuse_bytes Number of heap bytes that are in use.
+# TYPE go_memstats_heap_inuse_bytes gauge
+go_memstats_heap_inuse_bytes %d
+# HELP profitify_http_requests_total HTTP requests handled, by route and status.
+# TYPE profitify_http_requests_total counter
+profitify_http_requests_total{method="GET",route="/api/tickers",status="200"} 12
+`, goroutines, heap)
+}
+
+func TestParseSample(t *testing.T) {
+	got, err := parseSample(strings.NewReader(metricsText(8<<20, 42)))
+	require.NoError(t, err)
+	assert.Equal(t, runtimeSample{heapInuse: 8 << 20, goroutines: 42}, got, "resident memory is optional")
+
+	_, err = parseSample(strings.NewReader("# TYPE go_goroutines gauge\ngo_goroutines 3\n"))
+	assert.ErrorContains(t, err, "go_memstats_heap_inuse_bytes")
+	_, err = parseSample(strings.NewReader("not metrics {"))
+	assert.Error(t, err)
+}
+
+func TestLeakDetector(t *testing.T) {
+	sample := func(heapMiB, goroutines float64) runtimeSample {
+		return runtimeSample{heapInuse: heapMiB * (1 << 20), goroutines: goroutines}
+	}
+	newDetector := func() *leakDetector {
+		return &leakDetector{window: 3, maxHeapGrowth: 0.5, maxGoroutineGrowth: 10}
+	}
+
+	t.Run("steady", func(t *testing.T) {
+		d := newDetector()
+		for _, s := range []runtimeSample{sample(10, 20), sample(40, 60), sample(12, 22), sample(30, 50), sample(14, 25), sample(11, 21), sample(35, 70)} {
+			d.add(s)
+			assert.NoError(t, d.check(), "spikes between collections are not growth")
+		}
+		assert.NoError(t, d.verdict())
+		assert.Equal(t, "in-use heap 10.0 -> 11.0 MiB, goroutines 20 -> 21: within thresholds", d.String())
+	})
+
+	t.Run("growing heap", func(t *testing.T) {
+		d := newDetector()
+		for heap := 10.0; heap <= 15; heap++ {
+			d.add(sample(heap, 20))
+		}
+		assert.NoError(t, d.check())
+		for _, heap := range []float64{16, 17} {
+			d.add(sample(heap, 20))
+			assert.NoError(t, d.check(), "growth up to the threshold is allowed")
+		}
+		d.add(sample(18, 20))
+		assert.ErrorIs(t, d.check(), ErrLeak)
+		assert.ErrorContains(t, d.check(), "in-use heap grew from 10.0 to 16.0 MiB")
+	})
+
+	t.Run("growing goroutines", func(t *testing.T) {
+		d := newDetector()
+		for goroutines := 20.0; goroutines < 50; goroutines += 5 {
+			d.add(sample(10, goroutines))
+		}
+		assert.ErrorContains(t, d.check(), "goroutines grew from 20 to 35")
+	})
+
+	t.Run("too few samples", func(t *testing.T) {
+		d := newDetector()
+		for i := 0; i < 4; i++ {
+			d.add(sample(10, 20))
+		}
+		assert.NoError(t, d.check())
+		assert.ErrorContains(t, d.verdict(), "only 4 scrapes")
+	})
+}
+
+func TestSoakOptions_Validate(t *testing.T) {
+	opts := soakOptions{scrapeInterval: time.Minute, warmup: 10 * time.Minute, window: 5, maxHeapGrowth: 0.5}
+	assert.NoError(t, opts.validate(20*time.Minute))
+	assert.ErrorContains(t, opts.validate(19*time.Minute), "leaves 9 scrapes after the warmup")
+	assert.ErrorContains(t, opts.validate(5*time.Minute), "leaves 0 scrapes")
+}
+
+func TestRunSoak(t *testing.T) {
+	soak := func(t *testing.T, grow bool) (string, error) {
+		var scrapes atomic.Int64
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/metrics" {
+				return
+			}
+			heap := 10 << 20
+			if grow {
+				heap += int(scrapes.Add(1)) << 20
+			}
+			fmt.Fprint(w, metricsText(heap, 20))
+		}))
+		defer server.Close()
+
+		opts := &options{
+			target: server.URL, paths: []string{"/api/tickers"}, concurrency: 2, rate: 100, duration: 500 * time.Millisecond, timeout: time.Second,
+			soak:        true,
+			soakOptions: soakOptions{scrapeInterval: 20 * time.Millisecond, window: 3, maxHeapGrowth: 0.25, maxGoroutineGrowth: 5},
+		}
+		require.NoError(t, opts.validate())
+		var out bytes.Buffer
+		err := opts.runSoak(context.Background(), &out)
+		return out.String(), err
+	}
+
+	out, err := soak(t, false)
+	require.NoError(t, err)
+	assert.Contains(t, out, "within thresholds")
+
+	_, err = soak(t, true)
+	assert.ErrorIs(t, err, ErrLeak)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/spf13/cobra"
+)
+
+func newAPIKeyCommand(e *env) *cobra.Command {
+	apikey := &cobra.Command{
+		Use:   "apikey",
+		Short: "Create, list and revoke API keys",
+	}
+
+	var name string
+	var admin bool
+	var scopes []string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create an API key and print it; only its hash is stored, so it cannot be shown again",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			keyScopes := make([]models.APIKeyScope, 0, len(scopes))
+			for _, scope := range scopes {
+				keyScopes = append(keyScopes, models.APIKeyScope(scope))
+			}
+
+			issued, err := service.NewAPIKeyService(deps.APIKeyRepository(), deps.Log).CreateKey(cmd.Context(), name, admin, keyScopes)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(e.out, "id:  %s\nkey: %s\n", issued.ID, issued.Key)
+			return nil
+		},
+	}
+	create.Flags().StringVar(&name, "name", "", "name of the key's holder")
+	create.Flags().BoolVar(&admin, "admin", false, "allow the key to call the admin API")
+	create.Flags().StringSliceVar(&scopes, "scope", nil, fmt.Sprintf("restrict the key to scopes of %v (default full access)", models.APIKeyScopes))
+	_ = create.MarkFlagRequired("name")
+
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "List the API keys, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			keys, err := service.NewAPIKeyService(deps.APIKeyRepository(), deps.Log).ListKeys(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return e.printKeys(keys)
+		},
+	}
+
+	revoke := &cobra.Command{
+		Use:   "revoke <id>",
+		Short: "Revoke an API key by its id, as list shows it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			err = service.NewAPIKeyService(deps.APIKeyRepository(), deps.Log).RevokeKey(cmd.Context(), args[0])
+			if errors.Is(err, service.ErrAPIKeyNotFound) {
+				return fmt.Errorf("no API key has id %s", args[0])
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(e.out, "revoked %s\n", args[0])
+			return nil
+		},
+	}
+
+	apikey.AddCommand(create, list, revoke)
+	return apikey
+}
+
+// printKeys writes a line per key, newest first
+func (e *env) printKeys(keys []models.APIKey) error {
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedUTC > keys[j].CreatedUTC })
+
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSTATUS\tADMIN\tTIER\tSCOPES\tCREATED\tLAST USED")
+	for _, k := range keys {
+		scopes := make([]string, 0, len(k.Scopes))
+		for _, scope := range k.Scopes {
+			scopes = append(scopes, string(scope))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\t%s\t%s\n", k.ID, k.Name, keyStatus(k), k.Admin, k.Tier,
+			orDash(strings.Join(scopes, ",")), formatUnix(k.CreatedUTC), formatUnix(k.LastUsedUTC))
+	}
+	return w.Flush()
+}
+
+// keyStatus is whether a key authenticates, and why not
+func keyStatus(k models.APIKey) string {
+	switch {
+	case k.PurgedUTC != 0:
+		return "purged"
+	case k.DeletedUTC != 0:
+		return "deleted"
+	case k.RevokedUTC != 0:
+		return "revoked"
+	}
+	return "active"
+}
+
+func formatUnix(ts int64) string {
+	if ts == 0 {
+		return "-"
+	}
+	return time.Unix(ts, 0).UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/ingest"
+	"profitify-backend/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// backfillOptions are the flags of the backfill command
+type backfillOptions struct {
+	tickers     []string
+	tickersFile string
+	from        string
+	to          string
+	restart     bool
+}
+
+// newBackfillCommand loads historical daily bars. Progress is checkpointed in
+// the settings table after every chunk of bars, so running the same command
+// again after a crash or ^C resumes where it stopped; --restart starts over.
+func newBackfillCommand(e *env) *cobra.Command {
+	opts := &backfillOptions{}
+
+	root := &cobra.Command{
+		Use:   "backfill",
+		Short: "Load historical daily bars from the market data provider",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context(), e)
+		},
+	}
+
+	flags := root.Flags()
+	flags.StringSliceVar(&opts.tickers, "tickers", nil, "comma-separated tickers to backfill (default every active ticker)")
+	flags.StringVar(&opts.tickersFile, "tickers-file", "", "file of tickers to backfill, one per line; # starts a comment")
+	flags.StringVar(&opts.from, "from", "", "first date to backfill, YYYY-MM-DD")
+	flags.StringVar(&opts.to, "to", "", "last date to backfill, YYYY-MM-DD (default yesterday)")
+	flags.BoolVar(&opts.restart, "restart", false, "discard the checkpoint of an interrupted run and start over")
+	_ = root.MarkFlagRequired("from")
+
+	return root
+}
+
+func (o *backfillOptions) run(ctx context.Context, e *env) error {
+	if e.cfg.PolygonAPIKey == "" && e.cfg.AlphaVantageAPIKey == "" {
+		return errors.New("POLYGON_API_KEY or ALPHA_VANTAGE_API_KEY is required to backfill")
+	}
+	from, to, err := o.dateRange(time.Now())
+	if err != nil {
+		return err
+	}
+	symbols, err := o.symbols()
+	if err != nil {
+		return err
+	}
+
+	deps, err := e.connect(ctx)
+	if err != nil {
+		return err
+	}
+	ingester := ingest.Wire(deps)
+	checkpoints := deps.SettingsService()
+
+	if len(symbols) == 0 {
+		active, err := deps.TickerRepository().GetActiveTickers(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get active tickers: %w", err)
+		}
+		for _, t := range active {
+			symbols = append(symbols, t.Ticker)
+		}
+	}
+	if o.restart {
+		if err := checkpoints.ClearCheckpoint(ctx, ingest.BackfillJob(symbols, from, to)); err != nil {
+			return fmt.Errorf("failed to clear checkpoint: %w", err)
+		}
+	}
+
+	result, err := ingester.Backfill(ctx, checkpoints, symbols, from, to)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "backfilled %d tickers (%d done before resuming), stored %d bars\n", result.Completed, result.Resumed, result.Stored)
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("%d tickers failed: %s", len(result.Failed), strings.Join(result.Failed, ","))
+	}
+	return nil
+}
+
+// dateRange resolves the --from and --to flags; to defaults to the day before
+// now, the last day with a complete session
+func (o *backfillOptions) dateRange(now time.Time) (from, to time.Time, err error) {
+	y, m, d := now.UTC().Date()
+	to = time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC)
+	if o.to != "" {
+		if to, err = time.Parse(models.DateLayout, o.to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date %q, expected YYYY-MM-DD", o.to)
+		}
+	}
+
+	if from, err = time.Parse(models.DateLayout, o.from); err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %q, expected YYYY-MM-DD", o.from)
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from must not be after --to")
+	}
+	return from, to, nil
+}
+
+// symbols returns the tickers of --tickers and --tickers-file; none means
+// every active ticker
+func (o *backfillOptions) symbols() ([]string, error) {
+	symbols := append([]string(nil), o.tickers...)
+	if o.tickersFile == "" {
+		return symbols, nil
+	}
+
+	f, err := os.Open(o.tickersFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tickers file: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if line = strings.TrimSpace(line); line != "" {
+			symbols = append(symbols, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tickers file: %w", err)
+	}
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("tickers file %s lists no tickers", o.tickersFile)
+	}
+	return symbols, nil
+}
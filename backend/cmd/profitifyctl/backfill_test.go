@@ -0,0 +1,36 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillOptions(t *testing.T) {
+	now := time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC)
+
+	from, to, err := (&backfillOptions{from: "2025-01-02"}).dateRange(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC), to, "to defaults to yesterday")
+
+	_, _, err = (&backfillOptions{}).dateRange(now)
+	assert.Error(t, err, "from is required")
+	_, _, err = (&backfillOptions{from: "2025-03-08", to: "2025-03-07"}).dateRange(now)
+	assert.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "symbols.txt")
+	require.NoError(t, os.WriteFile(file, []byte("# watchlist\nNVDA\n\n tsla  # after earnings\n"), 0o600))
+	symbols, err := (&backfillOptions{tickers: []string{"AAPL"}, tickersFile: file}).symbols()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAPL", "NVDA", "tsla"}, symbols)
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	require.NoError(t, os.WriteFile(empty, []byte("# nothing yet\n"), 0o600))
+	_, err = (&backfillOptions{tickersFile: empty}).symbols()
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+// Command profitifyctl runs operational tasks against the backend's tables,
+// through the same configuration and repositories as the server.
+//
+//	go run ./cmd/profitifyctl tables create                  # create missing tables, indexes and TTLs
+//	go run ./cmd/profitifyctl tables describe                # status and size of every table
+//	go run ./cmd/profitifyctl tables export daily-summaries --file summaries.jsonl
+//	go run ./cmd/profitifyctl tickers export --file tickers.json
+//	go run ./cmd/profitifyctl tickers import --file tickers.json
+//	go run ./cmd/profitifyctl apikey create --name ci --scope read:market
+//	go run ./cmd/profitifyctl apikey list
+//	go run ./cmd/profitifyctl apikey revoke <id>
+//	go run ./cmd/profitifyctl backfill --tickers AAPL,MSFT --from 2020-01-01
+//	go run ./cmd/profitifyctl verify-data --from 2025-01-01
+//
+// Tables, AWS settings and the market data provider come from the backend's
+// configuration (TICKERS_TABLE, AWS_ENDPOINT_URL, POLYGON_API_KEY or
+// ALPHA_VANTAGE_API_KEY, ...). Logs go to stderr, so exports written to stdout
+// can be piped.
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"profitify-backend/internal/app"
+	"profitify-backend/pkg/awsclient"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/config"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	e := &env{cfg: cfg, out: os.Stdout}
+	err = newRootCommand(e).ExecuteContext(ctx)
+	e.close()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand(e *env) *cobra.Command {
+	root := &cobra.Command{
+		Use:          "profitifyctl",
+		Short:        "Operate the Profitify backend's tables",
+		SilenceUsage: true,
+	}
+	root.AddCommand(
+		newTablesCommand(e),
+		newTickersCommand(e),
+		newAPIKeyCommand(e),
+		newBackfillCommand(e),
+		newVerifyCommand(e),
+	)
+	return root
+}
+
+// env is what the subcommands share: the configuration, where to write their
+// output, and the dependencies built from the configuration on first use
+type env struct {
+	cfg  *config.Config
+	out  io.Writer
+	deps *app.Deps
+}
+
+// connect builds the DynamoDB client, logger and events publisher the way
+// the server does, once
+func (e *env) connect(ctx context.Context) (app.Deps, error) {
+	if e.deps != nil {
+		return *e.deps, nil
+	}
+	cfg := e.cfg
+
+	if err := logger.Init(&logger.Config{Level: cfg.LogLevel, Environment: cfg.Environment, OutputPaths: []string{"stderr"}}); err != nil {
+		return app.Deps{}, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	db, err := awsclient.NewDynamoDB(ctx, awsclient.Config{
+		Region:      cfg.AWSRegion,
+		EndpointURL: cfg.AWSEndpointURL,
+		HTTP: awsclient.HTTPConfig{
+			MaxConnsPerHost:     cfg.AWSMaxConnsPerHost,
+			MaxIdleConnsPerHost: cfg.AWSMaxIdleConnsPerHost,
+			IdleConnTimeout:     cfg.AWSIdleConnTimeout,
+			DialTimeout:         cfg.AWSDialTimeout,
+			KeepAlive:           cfg.AWSKeepAlive,
+			Timeout:             cfg.AWSHTTPTimeout,
+		},
+	})
+	if err != nil {
+		return app.Deps{}, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	publisher, err := events.Open(ctx, events.Config{
+		Backend:     cfg.EventsBackend,
+		BusName:     cfg.EventBusName,
+		TopicARN:    cfg.EventsTopicARN,
+		Source:      cfg.EventSource,
+		Region:      cfg.AWSRegion,
+		EndpointURL: cfg.AWSEndpointURL,
+		Timeout:     cfg.EventsTimeout,
+	})
+	if err != nil {
+		return app.Deps{}, fmt.Errorf("failed to configure events: %w", err)
+	}
+
+	e.deps = &app.Deps{Config: cfg, DB: db, Log: logger.Get(), Events: publisher, Clock: clock.System}
+	return *e.deps, nil
+}
+
+// close flushes the logger if connect started it
+func (e *env) close() {
+	if e.deps != nil {
+		_ = logger.Sync()
+	}
+}
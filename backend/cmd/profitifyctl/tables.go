@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"profitify-backend/internal/repository"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/spf13/cobra"
+)
+
+func newTablesCommand(e *env) *cobra.Command {
+	tables := &cobra.Command{
+		Use:   "tables",
+		Short: "Create and inspect the DynamoDB tables",
+	}
+
+	var recreate bool
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create missing tables, and the indexes, TTLs and streams existing ones lack",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return repository.EnsureTables(cmd.Context(), deps.DB, e.cfg, recreate, e.out)
+		},
+	}
+	create.Flags().BoolVar(&recreate, "recreate", false, "drop and recreate existing tables, deleting their data")
+
+	describe := &cobra.Command{
+		Use:   "describe",
+		Short: "Show the status, size and indexes of every table; fails if one is missing",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return e.describeTables(cmd.Context(), deps.DB)
+		},
+	}
+
+	var exportFile string
+	var segments int
+	export := &cobra.Command{
+		Use:   "export <table>",
+		Short: "Write every item of a table as a line of JSON, scanning it in parallel segments",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !slices.ContainsFunc(repository.Tables(e.cfg), func(t repository.Table) bool { return t.Name() == args[0] }) {
+				return fmt.Errorf("%s is not a table of the configuration", args[0])
+			}
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			out := e.out
+			if exportFile != "-" {
+				f, err := os.Create(exportFile)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", exportFile, err)
+				}
+				defer f.Close()
+				out = f
+			}
+			w := bufio.NewWriter(out)
+			n, err := exportTable(cmd.Context(), deps.DB, args[0], segments, w)
+			if flushErr := w.Flush(); err == nil && flushErr != nil {
+				err = fmt.Errorf("failed to write %s: %w", args[0], flushErr)
+			}
+			if err != nil {
+				return err
+			}
+			if exportFile != "-" {
+				fmt.Fprintf(e.out, "exported %d items of %s to %s\n", n, args[0], exportFile)
+			}
+			return nil
+		},
+	}
+	export.Flags().StringVar(&exportFile, "file", "-", "JSON lines file to write, - for stdout")
+	export.Flags().IntVar(&segments, "segments", e.cfg.ScanSegments, "segments to scan the table in parallel in")
+
+	tables.AddCommand(create, describe, export)
+	return tables
+}
+
+// exportTable writes every item of table to w as a line of JSON, in the
+// order the segments of the scan read them, and returns how many it wrote.
+// Items are written as they arrive, so tables larger than memory export.
+func exportTable(ctx context.Context, client repository.Scanner, table string, segments int, w io.Writer) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	items := make(chan map[string]types.AttributeValue, 256)
+	scanned := make(chan error, 1)
+	go func() {
+		scanned <- repository.ScanAll(ctx, client, &dynamodb.ScanInput{TableName: aws.String(table)}, segments, items)
+	}()
+
+	enc := json.NewEncoder(w)
+	written := 0
+	var writeErr error
+	for item := range items {
+		if writeErr != nil {
+			continue
+		}
+		var decoded map[string]any
+		if err := attributevalue.UnmarshalMap(item, &decoded); err != nil {
+			writeErr = fmt.Errorf("failed to decode an item of %s: %w", table, err)
+		} else if err := enc.Encode(decoded); err != nil {
+			writeErr = fmt.Errorf("failed to write %s: %w", table, err)
+		}
+		if writeErr != nil {
+			// Stop the scan; the items already read are drained
+			cancel()
+			continue
+		}
+		written++
+	}
+
+	if err := <-scanned; writeErr == nil && err != nil {
+		return written, err
+	}
+	return written, writeErr
+}
+
+// describeTables prints a line per table of the configuration. DynamoDB
+// refreshes item counts and sizes about every six hours.
+func (e *env) describeTables(ctx context.Context, client *dynamodb.Client) error {
+	w := tabwriter.NewWriter(e.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tSTATUS\tITEMS\tBYTES\tINDEXES")
+
+	var missing []string
+	for _, table := range repository.Tables(e.cfg) {
+		described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table.Name())})
+		var notFound *types.ResourceNotFoundException
+		switch {
+		case errors.As(err, &notFound):
+			missing = append(missing, table.Name())
+			fmt.Fprintf(w, "%s\tMISSING\t-\t-\t-\n", table.Name())
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to describe table %s: %w", table.Name(), err)
+		}
+
+		d := described.Table
+		indexes := make([]string, 0, len(d.GlobalSecondaryIndexes))
+		for _, index := range d.GlobalSecondaryIndexes {
+			indexes = append(indexes, fmt.Sprintf("%s(%s)", aws.ToString(index.IndexName), index.IndexStatus))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", table.Name(), d.TableStatus,
+			aws.ToInt64(d.ItemCount), aws.ToInt64(d.TableSizeBytes), orDash(strings.Join(indexes, ",")))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%d tables are missing (run tables create): %s", len(missing), strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
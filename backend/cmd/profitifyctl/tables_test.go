@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentScanner serves one page of items per segment
+type segmentScanner map[int32][]map[string]types.AttributeValue
+
+func (s segmentScanner) Scan(ctx context.Context, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return &dynamodb.ScanOutput{Items: s[aws.ToInt32(input.Segment)]}, nil
+}
+
+func TestExportTable(t *testing.T) {
+	scanner := segmentScanner{
+		0: {{"ticker": &types.AttributeValueMemberS{Value: "AAPL"}, "timestamp": &types.AttributeValueMemberN{Value: "1740960000"}}},
+		1: {{"ticker": &types.AttributeValueMemberS{Value: "MSFT"}, "otc": &types.AttributeValueMemberBOOL{Value: true}}},
+	}
+
+	var out bytes.Buffer
+	n, err := exportTable(context.Background(), scanner, "daily-summaries", 2, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.ElementsMatch(t, []string{
+		`{"ticker":"AAPL","timestamp":1740960000}`,
+		`{"otc":true,"ticker":"MSFT"}`,
+	}, lines)
+}
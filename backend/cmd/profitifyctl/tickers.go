@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"profitify-backend/internal/ingest"
+	"profitify-backend/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+func newTickersCommand(e *env) *cobra.Command {
+	tickers := &cobra.Command{
+		Use:   "tickers",
+		Short: "Import, export and search tickers as JSON",
+	}
+
+	var importFile string
+	var dryRun bool
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Store the tickers of a JSON array, as export writes it; none is stored unless all are valid",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			in, closeIn, err := openInput(importFile)
+			if err != nil {
+				return err
+			}
+			defer closeIn()
+			tickers, err := decodeTickers(in, cmd.ErrOrStderr())
+			if err != nil {
+				return err
+			}
+			if dryRun {
+				fmt.Fprintf(e.out, "%d tickers are valid\n", len(tickers))
+				return nil
+			}
+
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := deps.TickerRepository().PutTickers(cmd.Context(), tickers); err != nil {
+				return fmt.Errorf("failed to store tickers: %w", err)
+			}
+			fmt.Fprintf(e.out, "imported %d tickers\n", len(tickers))
+			return nil
+		},
+	}
+	importCmd.Flags().StringVar(&importFile, "file", "-", "JSON file to import, - for stdin")
+	importCmd.Flags().BoolVar(&dryRun, "dry-run", false, "validate the tickers without storing them")
+
+	var exportFile string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the active tickers as a JSON array, ordered by symbol",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			tickers, err := deps.TickerRepository().GetActiveTickers(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get active tickers: %w", err)
+			}
+			sort.Slice(tickers, func(i, j int) bool { return tickers[i].Ticker < tickers[j].Ticker })
+
+			out := e.out
+			if exportFile != "-" {
+				f, err := os.Create(exportFile)
+				if err != nil {
+					return fmt.Errorf("failed to create %s: %w", exportFile, err)
+				}
+				defer f.Close()
+				out = f
+			}
+			enc := json.NewEncoder(out)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(tickers); err != nil {
+				return fmt.Errorf("failed to write tickers: %w", err)
+			}
+			if exportFile != "-" {
+				fmt.Fprintf(e.out, "exported %d tickers to %s\n", len(tickers), exportFile)
+			}
+			return nil
+		},
+	}
+	exportCmd.Flags().StringVar(&exportFile, "file", "-", "JSON file to write, - for stdout")
+
+	searchCmd := &cobra.Command{
+		Use:   "search <keywords>...",
+		Short: "Write the market data provider's best matches of a symbol or company name as a JSON array, as import reads it",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			deps, err := e.connect(cmd.Context())
+			if err != nil {
+				return err
+			}
+			ingester := ingest.Wire(deps)
+			if ingester == nil {
+				return fmt.Errorf("ALPHA_VANTAGE_API_KEY is required to search symbols")
+			}
+			searcher, ok := ingester.Provider().(ingest.SymbolSearcher)
+			if !ok {
+				return fmt.Errorf("the market data provider does not search symbols; set ALPHA_VANTAGE_API_KEY instead")
+			}
+
+			matches, err := searcher.SearchSymbols(cmd.Context(), strings.Join(args, " "))
+			if err != nil {
+				return fmt.Errorf("failed to search symbols: %w", err)
+			}
+			enc := json.NewEncoder(e.out)
+			enc.SetIndent("", "  ")
+			return enc.Encode(matches)
+		},
+	}
+
+	tickers.AddCommand(importCmd, exportCmd, searchCmd)
+	return tickers
+}
+
+// decodeTickers reads a JSON array of tickers and checks every one, naming
+// each invalid ticker to report
+func decodeTickers(r io.Reader, report io.Writer) ([]models.Ticker, error) {
+	var tickers []models.Ticker
+	if err := json.NewDecoder(r).Decode(&tickers); err != nil {
+		return nil, fmt.Errorf("failed to decode tickers, expected a JSON array: %w", err)
+	}
+	if len(tickers) == 0 {
+		return nil, fmt.Errorf("no tickers to import")
+	}
+
+	seen := make(map[string]bool, len(tickers))
+	var invalid int
+	for i := range tickers {
+		t := &tickers[i]
+		err := t.Validate()
+		if err == nil && seen[t.Ticker] {
+			err = fmt.Errorf("listed more than once")
+		}
+		if err != nil {
+			invalid++
+			fmt.Fprintf(report, "ticker %d (%s): %v\n", i, t.Ticker, err)
+		}
+		seen[t.Ticker] = true
+	}
+	if invalid > 0 {
+		return nil, fmt.Errorf("%d of %d tickers are invalid", invalid, len(tickers))
+	}
+	return tickers, nil
+}
+
+// openInput opens file, or stdin for -
+func openInput(file string) (io.Reader, func(), error) {
+	if file == "-" {
+		return os.Stdin, func() {}, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", file, err)
+	}
+	return f, func() { f.Close() }, nil
+}
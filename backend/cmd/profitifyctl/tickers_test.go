@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTickers(t *testing.T) {
+	var report bytes.Buffer
+	tickers, err := decodeTickers(strings.NewReader(`[
+		{"ticker":"AAPL","name":"Apple Inc.","market":"stocks","locale":"us","active":1},
+		{"ticker":"MSFT","name":"Microsoft Corporation","market":"stocks","locale":"us","active":1}
+	]`), &report)
+	require.NoError(t, err)
+	assert.Len(t, tickers, 2)
+	assert.Empty(t, report.String())
+
+	report.Reset()
+	_, err = decodeTickers(strings.NewReader(`[
+		{"ticker":"AAPL","name":"Apple Inc.","market":"stocks","locale":"us","active":1},
+		{"ticker":"","name":"Nameless","market":"stocks","locale":"us","active":1},
+		{"ticker":"AAPL","name":"Apple Inc.","market":"stocks","locale":"us","active":1}
+	]`), &report)
+	assert.EqualError(t, err, "2 of 3 tickers are invalid")
+	assert.Contains(t, report.String(), "ticker 1 ():")
+	assert.Contains(t, report.String(), "ticker 2 (AAPL): listed more than once")
+
+	_, err = decodeTickers(strings.NewReader(`{"ticker":"AAPL"}`), &report)
+	assert.ErrorContains(t, err, "expected a JSON array")
+	_, err = decodeTickers(strings.NewReader(`[]`), &report)
+	assert.EqualError(t, err, "no tickers to import")
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+
+	"github.com/spf13/cobra"
+)
+
+// verifyOptions are the flags of the verify-data command
+type verifyOptions struct {
+	tickers []string
+	from    string
+	to      string
+}
+
+// defaultVerifyDays is how far back verify-data looks without --from
+const defaultVerifyDays = 30
+
+func newVerifyCommand(e *env) *cobra.Command {
+	opts := &verifyOptions{}
+
+	verify := &cobra.Command{
+		Use:   "verify-data",
+		Short: "Check the stored daily summaries for invalid bars, duplicates and missing trading days",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd.Context(), e)
+		},
+	}
+
+	flags := verify.Flags()
+	flags.StringSliceVar(&opts.tickers, "tickers", nil, "comma-separated tickers to check (default every active ticker)")
+	flags.StringVar(&opts.from, "from", "", fmt.Sprintf("first date to check, YYYY-MM-DD (default %d days before --to)", defaultVerifyDays))
+	flags.StringVar(&opts.to, "to", "", "last date to check, YYYY-MM-DD (default yesterday)")
+
+	return verify
+}
+
+func (o *verifyOptions) run(ctx context.Context, e *env) error {
+	from, to, err := o.dateRange(time.Now())
+	if err != nil {
+		return err
+	}
+
+	deps, err := e.connect(ctx)
+	if err != nil {
+		return err
+	}
+	symbols := o.tickers
+	if len(symbols) == 0 {
+		active, err := deps.TickerRepository().GetActiveTickers(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get active tickers: %w", err)
+		}
+		for _, t := range active {
+			symbols = append(symbols, t.Ticker)
+		}
+	}
+
+	summaries := deps.DailySummaryRepository()
+	var problems int
+	for _, symbol := range symbols {
+		// A day's summary is stamped at midnight of its date, in UTC or in
+		// New York depending on its source, so the last day's may lie hours
+		// past midnight of to
+		stored, err := summaries.GetSummaries(ctx, symbol, from.Unix(), to.AddDate(0, 0, 1).Unix()-1)
+		if err != nil {
+			return fmt.Errorf("failed to get summaries of %s: %w", symbol, err)
+		}
+		for _, problem := range verifySummaries(symbol, stored, from, to) {
+			problems++
+			fmt.Fprintf(e.out, "%s: %s\n", symbol, problem)
+		}
+	}
+
+	if problems > 0 {
+		return fmt.Errorf("found %d problems in the summaries of %d tickers from %s to %s",
+			problems, len(symbols), from.Format(models.DateLayout), to.Format(models.DateLayout))
+	}
+	fmt.Fprintf(e.out, "summaries of %d tickers from %s to %s are complete\n",
+		len(symbols), from.Format(models.DateLayout), to.Format(models.DateLayout))
+	return nil
+}
+
+// dateRange resolves the --from and --to flags; to defaults to yesterday and
+// from to defaultVerifyDays before to
+func (o *verifyOptions) dateRange(now time.Time) (from, to time.Time, err error) {
+	y, m, d := now.UTC().Date()
+	to = time.Date(y, m, d-1, 0, 0, 0, 0, time.UTC)
+	if o.to != "" {
+		if to, err = time.Parse(models.DateLayout, o.to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date %q, expected YYYY-MM-DD", o.to)
+		}
+	}
+
+	from = to.AddDate(0, 0, -defaultVerifyDays)
+	if o.from != "" {
+		if from, err = time.Parse(models.DateLayout, o.from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %q, expected YYYY-MM-DD", o.from)
+		}
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from must not be after --to")
+	}
+	return from, to, nil
+}
+
+// verifySummaries describes what is wrong with a ticker's summaries of the
+// days from to to, oldest first: invalid bars, bars of days the market was
+// closed, days stored more than once and trading days missing since the
+// first stored one. Days before that are not counted as missing, since the
+// ticker may have listed later.
+func verifySummaries(symbol string, summaries []models.DailySummary, from, to time.Time) []string {
+	if len(summaries) == 0 {
+		return []string{fmt.Sprintf("no summaries from %s to %s", from.Format(models.DateLayout), to.Format(models.DateLayout))}
+	}
+
+	var problems []string
+	stored := make(map[string]bool, len(summaries))
+	for i := range summaries {
+		s := &summaries[i]
+		date := s.Date()
+		day, _ := time.Parse(models.DateLayout, date)
+		if err := s.Validate(); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: invalid summary: %v", date, err))
+		}
+		if s.Ticker != symbol {
+			problems = append(problems, fmt.Sprintf("%s: stored with ticker %q", date, s.Ticker))
+		}
+		if !marketcalendar.IsTradingDay(day) {
+			problems = append(problems, fmt.Sprintf("%s: the market was closed", date))
+		}
+		if stored[date] {
+			problems = append(problems, fmt.Sprintf("%s: stored more than once", date))
+		}
+		stored[date] = true
+	}
+
+	first, _ := time.Parse(models.DateLayout, summaries[0].Date())
+	for day := first; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if marketcalendar.IsTradingDay(day) && !stored[day.Format(models.DateLayout)] {
+			problems = append(problems, fmt.Sprintf("%s: missing", day.Format(models.DateLayout)))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summaryOn(ticker, date string) models.DailySummary {
+	day, _ := time.Parse(models.DateLayout, date)
+	return models.DailySummary{Ticker: ticker, Timestamp: day.Unix(), Open: 10, High: 11, Low: 9, Close: 10, Volume: 100}
+}
+
+func TestVerifySummaries(t *testing.T) {
+	// Monday 2025-03-03 to Friday 2025-03-07
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	var complete []models.DailySummary
+	for _, date := range []string{"2025-03-03", "2025-03-04", "2025-03-05", "2025-03-06", "2025-03-07"} {
+		complete = append(complete, summaryOn("AAPL", date))
+	}
+	assert.Empty(t, verifySummaries("AAPL", complete, from, to))
+
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	polygon := summaryOn("AAPL", "2025-03-07")
+	polygon.Timestamp = time.Date(2025, 3, 7, 0, 0, 0, 0, newYork).Unix()
+	assert.Empty(t, verifySummaries("AAPL", append(complete[:4:4], polygon), from, to),
+		"summaries stamped at midnight in New York count for their date")
+
+	invalid := summaryOn("AAPL", "2025-03-05")
+	invalid.Low = 12
+	broken := []models.DailySummary{
+		complete[0],
+		complete[0],
+		invalid,
+		summaryOn("MSFT", "2025-03-06"),
+		summaryOn("AAPL", "2025-03-08"),
+	}
+	assert.Equal(t, []string{
+		"2025-03-03: stored more than once",
+		"2025-03-05: invalid summary: high price cannot be less than low price",
+		"2025-03-06: stored with ticker \"MSFT\"",
+		"2025-03-08: the market was closed",
+		"2025-03-04: missing",
+		"2025-03-07: missing",
+	}, verifySummaries("AAPL", broken, from, to))
+
+	assert.Equal(t, []string{"no summaries from 2025-03-01 to 2025-03-07"}, verifySummaries("AAPL", nil, from, to))
+	assert.Empty(t, verifySummaries("AAPL", complete[2:], from, to), "days before the first stored one are not missing")
+}
+
+func TestVerifyOptions(t *testing.T) {
+	now := time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC)
+
+	from, to, err := (&verifyOptions{}).dateRange(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 2, 4, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC), to)
+
+	_, _, err = (&verifyOptions{from: "2025-03-08", to: "2025-03-07"}).dateRange(now)
+	assert.Error(t, err)
+	_, _, err = (&verifyOptions{from: "03/01/2025"}).dateRange(now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+// Command schemagen generates the Avro and Protocol Buffers schemas of the
+// event payloads in models.EventCatalog, one file per event type and version,
+// so WebSocket, Kafka, Kinesis and internal consumers share one contract.
+//
+//	go generate ./internal/models           # regenerate internal/models/schemas
+//	go run ./cmd/schemagen --check          # fail if the schemas are stale
+//
+// A version's schema may only gain optional fields after its existing ones;
+// any other change to a published version is refused, and must be published
+// as the next version instead, leaving the previous version's files in place.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/schema"
+
+	"github.com/spf13/cobra"
+)
+
+// namespace is the Avro namespace of the events, and with the version the
+// protobuf package
+const namespace = "profitify.events"
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	var dir string
+	var check bool
+
+	root := &cobra.Command{
+		Use:          "schemagen",
+		Short:        "Generate the Avro and protobuf schemas of the published events",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return generate(dir, models.EventCatalog, check)
+		},
+	}
+	root.Flags().StringVar(&dir, "dir", filepath.Join("internal", "models", "schemas"), "directory of the schema files")
+	root.Flags().BoolVar(&check, "check", false, "fail if the files are stale instead of writing them")
+	return root
+}
+
+// schemaFile matches the Avro schema files, capturing the type and version
+var schemaFile = regexp.MustCompile(`^(\w+)\.v(\d+)\.avsc$`)
+
+// generate writes the schema files of every event in catalog to dir, after
+// checking each against the files of the version already published
+func generate(dir string, catalog []models.EventSchema, check bool) error {
+	published, err := publishedVersions(dir)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, event := range catalog {
+		if latest := published[event.Type]; latest > event.Version {
+			errs = append(errs, fmt.Errorf("%s is at v%d but v%d is published", event.Type, event.Version, latest))
+			continue
+		}
+		if err := generateEvent(dir, event, check); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// publishedVersions returns the latest version of each event type in dir
+func publishedVersions(dir string) (map[string]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to list schemas: %w", err)
+	}
+	latest := map[string]int{}
+	for _, entry := range entries {
+		if match := schemaFile.FindStringSubmatch(entry.Name()); match != nil {
+			version, _ := strconv.Atoi(match[2])
+			latest[match[1]] = max(latest[match[1]], version)
+		}
+	}
+	return latest, nil
+}
+
+func generateEvent(dir string, event models.EventSchema, check bool) error {
+	s, err := schema.Reflect(event.Type, event.Version, event.Description, event.Payload)
+	if err != nil {
+		return err
+	}
+	avro, err := s.Avro(namespace)
+	if err != nil {
+		return err
+	}
+	proto, err := s.Proto(fmt.Sprintf("%s.v%d", namespace, event.Version))
+	if err != nil {
+		return err
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%s.v%d", event.Type, event.Version))
+	previous, err := os.ReadFile(base + ".avsc")
+	switch {
+	case err == nil:
+		published, err := schema.ParseAvro(previous)
+		if err != nil {
+			return fmt.Errorf("%s: %w", base+".avsc", err)
+		}
+		if err := schema.Compatible(published, s); err != nil {
+			return err
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read %s: %w", base+".avsc", err)
+	}
+
+	files := map[string][]byte{base + ".avsc": avro, base + ".proto": []byte(proto)}
+	for path, content := range files {
+		if check {
+			current, err := os.ReadFile(path)
+			if err != nil || !bytes.Equal(current, content) {
+				return fmt.Errorf("%s is stale, run go generate ./internal/models", path)
+			}
+			continue
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", dir, err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
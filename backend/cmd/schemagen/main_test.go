@@ -0,0 +1,57 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_SchemasAreCurrent(t *testing.T) {
+	dir := filepath.Join("..", "..", "internal", "models", "schemas")
+	assert.NoError(t, generate(dir, models.EventCatalog, true), "run go generate ./internal/models after changing an event")
+}
+
+type tickerV1 struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+type tickerV1Revised struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Exchange string `json:"exchange,omitempty"`
+}
+
+type tickerV2 struct {
+	Symbol string `json:"symbol"`
+}
+
+func TestGenerate_Versions(t *testing.T) {
+	dir := t.TempDir()
+	catalog := func(version int, payload any) []models.EventSchema {
+		return []models.EventSchema{{Type: "TickerUpdated", Version: version, Payload: payload}}
+	}
+
+	require.NoError(t, generate(dir, catalog(1, tickerV1{}), false))
+	assert.FileExists(t, filepath.Join(dir, "TickerUpdated.v1.avsc"))
+	assert.FileExists(t, filepath.Join(dir, "TickerUpdated.v1.proto"))
+
+	assert.ErrorContains(t, generate(dir, catalog(1, tickerV1Revised{}), true), "is stale")
+	require.NoError(t, generate(dir, catalog(1, tickerV1Revised{}), false), "optional fields may be added")
+
+	err := generate(dir, catalog(1, tickerV2{}), false)
+	assert.ErrorContains(t, err, "TickerUpdated.name was removed")
+	assert.ErrorContains(t, err, "publish the change as v2")
+
+	require.NoError(t, generate(dir, catalog(2, tickerV2{}), false))
+	v1, err := os.ReadFile(filepath.Join(dir, "TickerUpdated.v1.avsc"))
+	require.NoError(t, err)
+	assert.Contains(t, string(v1), "exchange", "the previous version's schema is kept")
+
+	assert.ErrorContains(t, generate(dir, catalog(1, tickerV1Revised{}), false), "is at v1 but v2 is published")
+}
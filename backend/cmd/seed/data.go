@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// batchSize is the most items a BatchWriteItem call accepts
+const batchSize = 25
+
+// seed writes the selected sample tickers and their generated daily summaries
+func (o *options) seed(ctx context.Context, client *dynamodb.Client) error {
+	now := time.Now()
+	tickers, err := o.selectTickers(now)
+	if err != nil {
+		return err
+	}
+	from, to, err := o.dateRange(now)
+	if err != nil {
+		return err
+	}
+	if o.workers < 1 {
+		return fmt.Errorf("--workers must be at least 1")
+	}
+	if o.intradayDays < 0 {
+		return fmt.Errorf("--intraday-days must not be negative")
+	}
+
+	if err := writeItems(ctx, client, o.tickersTable, tickers); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Inserted %d tickers\n", len(tickers))
+
+	fmt.Printf("Generating daily summaries from %s to %s...\n", from.Format(models.DateLayout), to.Format(models.DateLayout))
+
+	jobs := make(chan models.Ticker)
+	errs := make(chan error, len(tickers))
+	var wg sync.WaitGroup
+	for range o.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				summaries, bars := generateTicker(t.Ticker, from, to, o.intradayDays, tickerRand(o.randSeed, t.Ticker))
+				if err := writeItems(ctx, client, o.dailyTable, summaries); err != nil {
+					errs <- fmt.Errorf("%s: %w", t.Ticker, err)
+					continue
+				}
+				fmt.Printf("✓ Inserted %d daily summary records for %s\n", len(summaries), t.Ticker)
+
+				if len(bars) == 0 {
+					continue
+				}
+				if err := writeItems(ctx, client, o.cfg.IntradayBarsTable, bars); err != nil {
+					errs <- fmt.Errorf("%s intraday: %w", t.Ticker, err)
+					continue
+				}
+				fmt.Printf("✓ Inserted %d intraday bars for %s\n", len(bars), t.Ticker)
+			}
+		}()
+	}
+
+	for _, t := range tickers {
+		jobs <- t
+	}
+	close(jobs)
+	wg.Wait()
+	close(errs)
+
+	failed := 0
+	for err := range errs {
+		failed++
+		fmt.Printf("✗ %v\n", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to seed %d of %d tickers", failed, len(tickers))
+	}
+
+	fmt.Println("Seed data loaded successfully!")
+	return nil
+}
+
+// writeItems batch-writes items, retrying items DynamoDB leaves unprocessed
+func writeItems[T any](ctx context.Context, client *dynamodb.Client, table string, items []T) error {
+	for start := 0; start < len(items); start += batchSize {
+		end := min(start+batchSize, len(items))
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, item := range items[start:end] {
+			av, err := attributevalue.MarshalMap(item)
+			if err != nil {
+				return fmt.Errorf("failed to marshal item: %w", err)
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: av}})
+		}
+
+		pending := map[string][]types.WriteRequest{table: requests}
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+				}
+			}
+
+			result, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				return fmt.Errorf("failed to write to %s: %w", table, err)
+			}
+			pending = result.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+func sampleTickers(now time.Time) []models.Ticker {
+	common := models.Ticker{
+		Market:         "stocks",
+		Locale:         "us",
+		Type:           "CS",
+		Active:         1,
+		Currency:       "USD",
+		LastUpdatedUTC: now.Unix(),
+	}
+
+	data := []struct {
+		Symbol   string
+		Name     string
+		Exchange string
+		Cik      string
+		Sector   string
+		Industry string
+	}{
+		{"AAPL", "Apple Inc.", "XNAS", "0000320193", "Technology", "Consumer Electronics"},
+		{"GOOGL", "Alphabet Inc. Class A", "XNAS", "0001652044", "Communication Services", "Internet Content & Information"},
+		{"MSFT", "Microsoft Corporation", "XNAS", "0000789019", "Technology", "Software"},
+		{"AMZN", "Amazon.com Inc.", "XNAS", "0001018724", "Consumer Cyclical", "Internet Retail"},
+		{"TSLA", "Tesla Inc.", "XNAS", "0001318605", "Consumer Cyclical", "Auto Manufacturers"},
+		{"META", "Meta Platforms Inc.", "XNAS", "0001326801", "Communication Services", "Internet Content & Information"},
+		{"NVDA", "NVIDIA Corporation", "XNAS", "0001045810", "Technology", "Semiconductors"},
+		{"JPM", "JPMorgan Chase & Co.", "XNYS", "0000019617", "Financial Services", "Banks"},
+		{"V", "Visa Inc.", "XNYS", "0001403161", "Financial Services", "Credit Services"},
+		{"WMT", "Walmart Inc.", "XNYS", "0000104169", "Consumer Defensive", "Discount Stores"},
+		{"DIS", "The Walt Disney Company", "XNYS", "0001744489", "Communication Services", "Entertainment"},
+		{"NFLX", "Netflix Inc.", "XNAS", "0001065280", "Communication Services", "Entertainment"},
+		{"BA", "The Boeing Company", "XNYS", "0000012927", "Industrials", "Aerospace & Defense"},
+		{"KO", "The Coca-Cola Company", "XNYS", "0000021344", "Consumer Defensive", "Beverages"},
+		{"PFE", "Pfizer Inc.", "XNYS", "0000078003", "Healthcare", "Drug Manufacturers"},
+	}
+
+	tickers := make([]models.Ticker, len(data))
+	for i, d := range data {
+		tickers[i] = common
+		tickers[i].Ticker = d.Symbol
+		tickers[i].Name = d.Name
+		tickers[i].PrimaryExchange = d.Exchange
+		tickers[i].Cik = d.Cik
+		tickers[i].Sector = d.Sector
+		tickers[i].Industry = d.Industry
+	}
+	return tickers
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"profitify-backend/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions(t *testing.T) {
+	now := time.Date(2025, 3, 7, 15, 0, 0, 0, time.UTC)
+
+	opts := &options{tickers: []string{"msft", " KO"}}
+	tickers, err := opts.selectTickers(now)
+	require.NoError(t, err)
+	require.Len(t, tickers, 2)
+	assert.Equal(t, "MSFT", tickers[0].Ticker)
+	assert.Equal(t, "KO", tickers[1].Ticker)
+
+	_, err = (&options{tickers: []string{"ZZZZ"}}).selectTickers(now)
+	assert.Error(t, err)
+
+	from, to, err := (&options{}).dateRange(now)
+	require.NoError(t, err)
+	assert.Equal(t, now, to)
+	assert.Equal(t, now.AddDate(-2, 0, 0), from)
+
+	_, _, err = (&options{from: "2025-03-08", to: "2025-03-07"}).dateRange(now)
+	assert.Error(t, err)
+}
+
+func TestOptions_TableConfig(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	opts := &options{cfg: cfg, tickersTable: "seed-tickers", dailyTable: "seed-daily"}
+
+	tables := opts.tableConfig()
+	assert.Equal(t, "seed-tickers", tables.TickersTable)
+	assert.Equal(t, "seed-daily", tables.DailySummaryTable)
+	assert.Equal(t, cfg.IntradayBarsTable, tables.IntradayBarsTable)
+	assert.NotEqual(t, "seed-tickers", cfg.TickersTable, "the loaded configuration is not changed")
+}
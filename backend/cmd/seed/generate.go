@@ -0,0 +1,152 @@
+package main
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+)
+
+// generateTicker produces the sample data of a ticker: its daily summaries
+// over [from, to] and the intraday bars of the last intradayDays of them. The
+// same rng state always produces the same data; the snapshot tests hold it to
+// that.
+func generateTicker(ticker string, from, to time.Time, intradayDays int, rng *rand.Rand) ([]models.DailySummary, []models.IntradayBar) {
+	summaries := generateDailySummaries(ticker, from, to, rng)
+	var bars []models.IntradayBar
+	if intradayDays > 0 {
+		for _, summary := range summaries[max(len(summaries)-intradayDays, 0):] {
+			bars = append(bars, generateIntradayBars(summary, rng)...)
+		}
+	}
+	return summaries, bars
+}
+
+// tickerRand returns the random source of a ticker's data. A non-zero seed
+// makes it deterministic, so the same flags regenerate the same dataset
+// whichever worker generates the ticker; zero seeds it from the clock.
+func tickerRand(seed int64, ticker string) *rand.Rand {
+	if seed == 0 {
+		return rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	h := fnv.New64a()
+	h.Write([]byte(ticker))
+	return rand.New(rand.NewSource(seed ^ int64(h.Sum64())))
+}
+
+// generateDailySummaries produces a random walk of the trading days in [from, to]
+func generateDailySummaries(ticker string, from, to time.Time, rng *rand.Rand) []models.DailySummary {
+	price := initialPrices[ticker]
+	if price == 0 {
+		price = 100
+	}
+
+	var summaries []models.DailySummary
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		if !marketcalendar.IsTradingDay(d) {
+			continue
+		}
+
+		// Up to ±5% per session
+		price *= 1 + (rng.Float32()-0.5)*0.1
+
+		open := price * (1 + (rng.Float32()-0.5)*0.02)
+		closePrice := price
+		dayRange := price * 0.03
+		high := float32(math.Max(float64(open), float64(closePrice))) + rng.Float32()*dayRange
+		low := float32(math.Min(float64(open), float64(closePrice))) - rng.Float32()*dayRange
+
+		// Between 10M and 100M shares
+		volume := 10000000 + rng.Float32()*90000000
+
+		summaries = append(summaries, models.DailySummary{
+			Ticker:           ticker,
+			Open:             open,
+			High:             high,
+			Low:              low,
+			Close:            closePrice,
+			Volume:           volume,
+			Timestamp:        d.Unix(),
+			TransactionCount: int32(volume / 1000),
+			VWAP:             low + rng.Float32()*(high-low),
+		})
+	}
+	return summaries
+}
+
+// generateIntradayBars produces the minute bars of summary's regular session:
+// a random walk from its open to its close that stays within its high and
+// low, with the session's volume heavier at the open and close
+func generateIntradayBars(summary models.DailySummary, rng *rand.Rand) []models.IntradayBar {
+	session, ok := marketcalendar.SessionOn(time.Unix(summary.Timestamp, 0).UTC())
+	if !ok {
+		return nil
+	}
+	n := int(session.Close.Sub(session.Open) / time.Minute)
+
+	// The walk is pinned to zero at both ends, so the session opens and
+	// closes at the summary's prices
+	walk := make([]float64, n+1)
+	for i := 1; i <= n; i++ {
+		walk[i] = walk[i-1] + rng.NormFloat64()
+	}
+	low, high := float64(summary.Low), float64(summary.High)
+	open, closePrice := float64(summary.Open), float64(summary.Close)
+	dayRange := high - low
+	price := func(i int) float64 {
+		f := float64(i) / float64(n)
+		p := open + (closePrice-open)*f + (walk[i]-walk[n]*f)*dayRange*0.05
+		return math.Min(math.Max(p, low), high)
+	}
+
+	// A U-shaped volume curve: three times as heavy at the bell as at midday
+	weights := make([]float64, n)
+	total := 0.0
+	for i := range weights {
+		x := (float64(i)+0.5)/float64(n)*2 - 1
+		weights[i] = 1 + 2*x*x
+		total += weights[i]
+	}
+
+	bars := make([]models.IntradayBar, 0, n)
+	for i := 0; i < n; i++ {
+		o, c := price(i), price(i+1)
+		h := math.Min(math.Max(o, c)+rng.Float64()*dayRange*0.01, high)
+		l := math.Max(math.Min(o, c)-rng.Float64()*dayRange*0.01, low)
+		volume := math.Round(float64(summary.Volume) * weights[i] / total)
+		bars = append(bars, models.IntradayBar{
+			Ticker:           summary.Ticker,
+			Timestamp:        session.Open.Add(time.Duration(i) * time.Minute).Unix(),
+			Open:             float32(o),
+			High:             float32(h),
+			Low:              float32(l),
+			Close:            float32(c),
+			Volume:           float32(volume),
+			VWAP:             float32((h + l + c) / 3),
+			TransactionCount: int32(volume / 100),
+		})
+	}
+	return bars
+}
+
+// initialPrices start each sample ticker's random walk in a realistic range
+var initialPrices = map[string]float32{
+	"AAPL":  150,
+	"GOOGL": 100,
+	"MSFT":  250,
+	"AMZN":  120,
+	"TSLA":  200,
+	"META":  300,
+	"NVDA":  400,
+	"JPM":   140,
+	"V":     220,
+	"WMT":   150,
+	"DIS":   100,
+	"NFLX":  350,
+	"BA":    200,
+	"KO":    60,
+	"PFE":   40,
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of the snapshot tests")
+
+func TestGenerateDailySummaries(t *testing.T) {
+	from := time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC) // Monday
+	to := time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)  // Sunday
+
+	summaries := generateDailySummaries("AAPL", from, to, rand.New(rand.NewSource(1)))
+
+	require.Len(t, summaries, 10, "weekends are skipped")
+	for _, s := range summaries {
+		assert.NotContains(t, []time.Weekday{time.Saturday, time.Sunday}, time.Unix(s.Timestamp, 0).UTC().Weekday())
+		assert.NoError(t, s.Validate())
+		assert.GreaterOrEqual(t, s.High, max(s.Open, s.Close))
+		assert.LessOrEqual(t, s.Low, min(s.Open, s.Close))
+		assert.True(t, s.VWAP >= s.Low && s.VWAP <= s.High)
+	}
+
+	// Monday 14 April through Monday 21 April 2025 includes Good Friday
+	holidayWeek := generateDailySummaries("AAPL", time.Date(2025, 4, 14, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC), rand.New(rand.NewSource(1)))
+	assert.Len(t, holidayWeek, 5, "market holidays are skipped")
+}
+
+func TestGenerateIntradayBars(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ny := marketcalendar.Location()
+
+	for _, tc := range []struct {
+		name string
+		date time.Time
+		bars int
+	}{
+		{"regular session", time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), 390},
+		{"early close", time.Date(2025, 11, 28, 0, 0, 0, 0, time.UTC), 210},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			summaries := generateDailySummaries("AAPL", tc.date, tc.date, rng)
+			require.Len(t, summaries, 1)
+			day := summaries[0]
+
+			bars := generateIntradayBars(day, rng)
+			require.Len(t, bars, tc.bars)
+			assert.Equal(t, day.Open, bars[0].Open)
+			assert.Equal(t, day.Close, bars[len(bars)-1].Close)
+			assert.Equal(t, time.Date(2025, tc.date.Month(), tc.date.Day(), 9, 30, 0, 0, ny).Unix(), bars[0].Timestamp)
+
+			volume := float32(0)
+			for i, b := range bars {
+				assert.NoError(t, b.Validate())
+				assert.Equal(t, bars[0].Timestamp+int64(i)*60, b.Timestamp)
+				assert.True(t, b.Low >= day.Low && b.High <= day.High, "bar %d leaves the day's range", i)
+				assert.True(t, b.VWAP >= b.Low && b.VWAP <= b.High, "bar %d VWAP", i)
+				volume += b.Volume
+			}
+			assert.InDelta(t, day.Volume, volume, float64(len(bars)))
+			assert.Greater(t, bars[0].Volume, bars[len(bars)/2].Volume, "volume is heavier at the open")
+		})
+	}
+
+	assert.Empty(t, generateIntradayBars(models.DailySummary{
+		Ticker:    "AAPL",
+		Timestamp: time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC).Unix(),
+	}, rng), "no session on a Saturday")
+}
+
+// TestGenerateTicker_Snapshot pins the data a fixed seed generates, so local
+// datasets and the tests built on them do not change silently. After an
+// intended change to the generator, rewrite the snapshot with
+//
+//	go test ./cmd/seed -run Snapshot -update
+func TestGenerateTicker_Snapshot(t *testing.T) {
+	from := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	var b strings.Builder
+	for _, ticker := range []string{"AAPL", "MSFT", "KO"} {
+		summaries, bars := generateTicker(ticker, from, to, 2, tickerRand(42, ticker))
+		require.NotEmpty(t, summaries)
+		require.NotEmpty(t, bars)
+
+		fmt.Fprintf(&b, "%s summaries=%d bars=%d\n", ticker, len(summaries), len(bars))
+		for _, s := range []models.DailySummary{summaries[0], summaries[len(summaries)-1]} {
+			fmt.Fprintf(&b, "  %s o=%.4f h=%.4f l=%.4f c=%.4f v=%.0f vwap=%.4f\n",
+				time.Unix(s.Timestamp, 0).UTC().Format(models.DateLayout), s.Open, s.High, s.Low, s.Close, s.Volume, s.VWAP)
+		}
+		for _, bar := range []models.IntradayBar{bars[0], bars[len(bars)-1]} {
+			fmt.Fprintf(&b, "  %s o=%.4f h=%.4f l=%.4f c=%.4f v=%.0f vwap=%.4f\n",
+				time.Unix(bar.Timestamp, 0).In(marketcalendar.Location()).Format("2006-01-02 15:04"), bar.Open, bar.High, bar.Low, bar.Close, bar.Volume, bar.VWAP)
+		}
+	}
+
+	golden := filepath.Join("testdata", "generate.golden")
+	if *update {
+		require.NoError(t, os.MkdirAll("testdata", 0o755))
+		require.NoError(t, os.WriteFile(golden, []byte(b.String()), 0o644))
+	}
+	want, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), b.String(), "the generated data changed; rerun with -update if that is intended")
+}
+
+func TestTickerRand(t *testing.T) {
+	draw := func(r *rand.Rand) []int64 { return []int64{r.Int63(), r.Int63()} }
+	assert.Equal(t, draw(tickerRand(42, "AAPL")), draw(tickerRand(42, "AAPL")), "a seed repeats")
+	assert.NotEqual(t, draw(tickerRand(42, "AAPL")), draw(tickerRand(42, "MSFT")), "tickers differ")
+	assert.NotEqual(t, draw(tickerRand(42, "AAPL")), draw(tickerRand(43, "AAPL")), "seeds differ")
+}
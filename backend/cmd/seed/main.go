@@ -0,0 +1,183 @@
+// Command seed creates every DynamoDB table the backend reads and loads sample
+// tickers, daily summaries and, optionally, intraday bars into them.
+//
+//	go run ./cmd/seed                      # create missing tables and seed all sample tickers
+//	go run ./cmd/seed tables --recreate    # drop and recreate the tables only
+//	go run ./cmd/seed --tickers AAPL,MSFT --from 2024-01-01 --workers 4
+//	go run ./cmd/seed --intraday-days 5    # also seed minute bars of the last five sessions
+//	go run ./cmd/seed --seed 42            # the same dataset on every run
+//
+// Table names come from the backend's configuration (TICKERS_TABLE,
+// DAILY_SUMMARY_TABLE, LOCKS_TABLE, ...), so seeded data lands where the
+// server reads it.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/awsclient"
+	"profitify-backend/pkg/config"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/spf13/cobra"
+)
+
+// options are the flags shared by all commands
+type options struct {
+	// cfg names the tables without a flag of their own
+	cfg *config.Config
+
+	endpoint     string
+	region       string
+	tickersTable string
+	dailyTable   string
+	recreate     bool
+
+	tickers      []string
+	from         string
+	to           string
+	intradayDays int
+	randSeed     int64
+	workers      int
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Table names default to the server's configuration
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+	if err := newRootCommand(cfg).ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand(cfg *config.Config) *cobra.Command {
+	opts := &options{cfg: cfg}
+
+	root := &cobra.Command{
+		Use:          "seed",
+		Short:        "Create the DynamoDB tables and load sample market data",
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := opts.createTables(cmd.Context(), client); err != nil {
+				return err
+			}
+			return opts.seed(cmd.Context(), client)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.endpoint, "endpoint", envOr(cfg.AWSEndpointURL, "http://localhost:4566"), "DynamoDB endpoint URL")
+	flags.StringVar(&opts.region, "region", envOr(cfg.AWSRegion, "us-east-1"), "AWS region")
+	flags.StringVar(&opts.tickersTable, "tickers-table", cfg.TickersTable, "tickers table name")
+	flags.StringVar(&opts.dailyTable, "daily-table", cfg.DailySummaryTable, "daily summary table name")
+	flags.BoolVar(&opts.recreate, "recreate", false, "drop and recreate existing tables")
+
+	root.Flags().StringSliceVar(&opts.tickers, "tickers", nil, "comma-separated sample tickers to seed (default all)")
+	root.Flags().StringVar(&opts.from, "from", "", "first date of daily summaries, YYYY-MM-DD (default two years ago)")
+	root.Flags().StringVar(&opts.to, "to", "", "last date of daily summaries, YYYY-MM-DD (default today)")
+	root.Flags().IntVar(&opts.intradayDays, "intraday-days", 0, "trading days up to --to to also seed synthetic minute bars for")
+	root.Flags().Int64Var(&opts.randSeed, "seed", 0, "seed of the generated data; the same seed and flags regenerate the same data (default random)")
+	root.Flags().IntVar(&opts.workers, "workers", 10, "concurrent writers")
+
+	root.AddCommand(&cobra.Command{
+		Use:   "tables",
+		Short: "Create the DynamoDB tables without loading data",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := opts.client(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return opts.createTables(cmd.Context(), client)
+		},
+	})
+
+	return root
+}
+
+// tableConfig is the configuration with the table names of the flags
+func (o *options) tableConfig() *config.Config {
+	cfg := *o.cfg
+	cfg.TickersTable, cfg.DailySummaryTable = o.tickersTable, o.dailyTable
+	return &cfg
+}
+
+func (o *options) createTables(ctx context.Context, client *dynamodb.Client) error {
+	return repository.EnsureTables(ctx, client, o.tableConfig(), o.recreate, os.Stdout)
+}
+
+func (o *options) client(ctx context.Context) (*dynamodb.Client, error) {
+	return awsclient.NewDynamoDB(ctx, awsclient.Config{
+		Region:      o.region,
+		EndpointURL: o.endpoint,
+	})
+}
+
+// dateRange resolves the --from and --to flags
+func (o *options) dateRange(now time.Time) (from, to time.Time, err error) {
+	to = now.UTC()
+	if o.to != "" {
+		if to, err = time.Parse(models.DateLayout, o.to); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to date %q, expected YYYY-MM-DD", o.to)
+		}
+	}
+
+	from = to.AddDate(-2, 0, 0)
+	if o.from != "" {
+		if from, err = time.Parse(models.DateLayout, o.from); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from date %q, expected YYYY-MM-DD", o.from)
+		}
+	}
+
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from must not be after --to")
+	}
+	return from, to, nil
+}
+
+// selectTickers returns the sample tickers named by --tickers, or all of them
+func (o *options) selectTickers(now time.Time) ([]models.Ticker, error) {
+	samples := sampleTickers(now)
+	if len(o.tickers) == 0 {
+		return samples, nil
+	}
+
+	bySymbol := make(map[string]models.Ticker, len(samples))
+	for _, t := range samples {
+		bySymbol[t.Ticker] = t
+	}
+
+	selected := make([]models.Ticker, 0, len(o.tickers))
+	for _, symbol := range o.tickers {
+		t, ok := bySymbol[strings.ToUpper(strings.TrimSpace(symbol))]
+		if !ok {
+			return nil, fmt.Errorf("unknown sample ticker %q", symbol)
+		}
+		selected = append(selected, t)
+	}
+	return selected, nil
+}
+
+func envOr(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
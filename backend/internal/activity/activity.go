@@ -0,0 +1,135 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/events"
+)
+
+// Types of the activities in an account's feed
+const (
+	TypeAlertTriggered      = "alert_triggered"
+	TypeTransactionRecorded = "transaction_recorded"
+	TypeImportCompleted     = "import_completed"
+	TypeLogin               = "login"
+)
+
+// Types lists the activity types, which filter the feed
+var Types = []string{TypeAlertTriggered, TypeTransactionRecorded, TypeImportCompleted, TypeLogin}
+
+// Activity is a domain event of an API key's account, as shown in its feed
+type Activity struct {
+	// ID is the ID of the event the activity was recorded from
+	ID string `json:"id" dynamodbav:"id"`
+	// Type is "alert_triggered", "transaction_recorded", "import_completed"
+	// or "login"
+	Type    string `json:"type" dynamodbav:"type"`
+	TimeUTC int64  `json:"timeUTC" dynamodbav:"timeUTC"`
+	// Summary describes the activity in a line, e.g. "Bought 10 AAPL at 187.50"
+	Summary string `json:"summary" dynamodbav:"summary"`
+	// Data is the payload of the event, as published to the event bus
+	Data json.RawMessage `json:"data" dynamodbav:"data"`
+	// KeyID is the API key whose feed the activity is in
+	KeyID string `json:"-" dynamodbav:"keyId"`
+}
+
+// Seq orders the activity in its key's feed, chronologically and then by ID
+func (a *Activity) Seq() string {
+	return fmt.Sprintf("%010d#%s", a.TimeUTC, a.ID)
+}
+
+// validCursor reports whether cursor is the Seq of an activity
+func validCursor(cursor string) bool {
+	timeUTC, id, ok := strings.Cut(cursor, "#")
+	if !ok || len(timeUTC) != 10 || id == "" {
+		return false
+	}
+	for _, r := range timeUTC {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FromEvent returns the activity a domain event is in its account's feed. It
+// reports false for events of other types and for events of no API key, such
+// as the market's end-of-day ingest.
+func FromEvent(event events.Event) (*Activity, bool) {
+	activity := &Activity{ID: event.ID, TimeUTC: event.TimeUTC}
+
+	switch data := event.Data.(type) {
+	case models.AlertTriggeredEvent:
+		activity.Type = TypeAlertTriggered
+		activity.KeyID = data.KeyID
+		activity.Summary = fmt.Sprintf("%s %s alert triggered at %.2f", data.Symbol, alertCondition(data), data.TriggeredClose)
+	case models.TransactionRecordedEvent:
+		verb := "Bought"
+		if data.Type == "sell" {
+			verb = "Sold"
+		}
+		activity.Type = TypeTransactionRecorded
+		activity.KeyID = data.KeyID
+		activity.Summary = fmt.Sprintf("%s %g %s at %.2f", verb, data.Quantity, data.Symbol, data.Price)
+	case models.DailySummaryIngestedEvent:
+		if data.JobID == "" {
+			return nil, false
+		}
+		activity.Type = TypeImportCompleted
+		activity.KeyID = data.KeyID
+		activity.Summary = fmt.Sprintf("Imported %d daily summaries %s", data.Stored, importRange(data))
+	case models.LoggedInEvent:
+		activity.Type = TypeLogin
+		activity.KeyID = data.KeyID
+		activity.Summary = loginSummary(data)
+	default:
+		return nil, false
+	}
+	if activity.KeyID == "" {
+		return nil, false
+	}
+
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return nil, false
+	}
+	activity.Data = data
+	return activity, true
+}
+
+// alertCondition names the condition of a triggered alert, e.g. "price_above"
+// or the signal of a signal alert
+func alertCondition(event models.AlertTriggeredEvent) string {
+	if event.SignalType != "" {
+		return event.SignalType
+	}
+	return event.Condition
+}
+
+// importRange describes the symbol and dates an ingest job stored
+func importRange(event models.DailySummaryIngestedEvent) string {
+	var b strings.Builder
+	if event.Symbol != "" {
+		b.WriteString("of " + event.Symbol + " ")
+	}
+	if event.From == event.To {
+		b.WriteString("for " + event.From)
+	} else {
+		b.WriteString("from " + event.From + " to " + event.To)
+	}
+	return b.String()
+}
+
+func loginSummary(event models.LoggedInEvent) string {
+	switch {
+	case event.Method == models.LoginMethodPassword:
+		return "Logged in with a password"
+	case event.SessionName != "":
+		return fmt.Sprintf("Opened session %q", event.SessionName)
+	default:
+		return "Opened a session"
+	}
+}
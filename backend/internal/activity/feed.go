@@ -0,0 +1,50 @@
+package activity
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListActivity serves a page of the calling key's feed, filtered by a comma
+// separated ?type= and continued from ?cursor=
+func (h *Handler) ListActivity(c *gin.Context) {
+	query := Query{Cursor: c.Query("cursor")}
+	if value := c.Query("type"); value != "" {
+		for _, t := range strings.Split(value, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				query.Types = append(query.Types, t)
+			}
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 {
+			problem.Respond(c, problem.ValidationFailed, "limit must be a positive integer")
+			return
+		}
+		query.Limit = limit
+	}
+
+	page, err := h.activityService.List(c.Request.Context(), query)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidQuery):
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+		case errors.Is(err, ErrAPIKeyRequired):
+			problem.Respond(c, problem.Unauthenticated, "API key required to read the activity feed")
+		default:
+			api.Logger(c, h.log).Errorw("failed to list activity", "error", err)
+			problem.Respond(c, problem.Internal, "Failed to retrieve activity")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
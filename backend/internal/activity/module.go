@@ -0,0 +1,63 @@
+// Package activity serves each account's activity feed: the alerts that
+// triggered, transactions recorded, imports completed and logins of its API
+// key, recorded from the domain events as they are published.
+package activity
+
+import (
+	"net/http"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	activityService Service
+	log             *zap.SugaredLogger
+}
+
+func NewHandler(activity Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		activityService: activity,
+		log:             log,
+	}
+}
+
+// Wire builds the activity module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	repo := NewRepository(deps.DB, deps.Config.ActivityTable, deps.Config.ActivityRetention)
+	return NewHandler(NewService(repo, deps.Log), deps.Log)
+}
+
+// Record returns a publisher recording the activities of the events published
+// through it in their accounts' feeds, then publishing them to next. A nil
+// next publishes nothing further.
+func (h *Handler) Record(next events.Publisher) events.Publisher {
+	return &recorder{activity: h.activityService, next: next, log: h.log}
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.GET("/account/activity", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms(), h.ListActivity)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	doc.Add(http.MethodGet, "/api/v1/account/activity", &openapi.Operation{
+		Tags:    []string{"Account"},
+		Summary: "List the recent activity of the calling key",
+		Description: "Newest first: alerts triggered, portfolio transactions recorded, admin ingest jobs the key queued completing, " +
+			"and logins with a password or by opening a session. `data` is the payload of the domain event the activity was " +
+			"recorded from. Activities are kept for ACTIVITY_RETENTION.",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("type", "Comma separated activity types to list: "+strings.Join(Types, ", "), nil),
+			openapi.QueryParam("limit", "Maximum activities (defaults to 50, at most 200)", &openapi.Schema{Type: "integer"}),
+			openapi.QueryParam("cursor", "nextCursor of the previous page", nil),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(Page{}), http.StatusBadRequest, http.StatusUnauthorized),
+	})
+}
@@ -0,0 +1,169 @@
+package activity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for activity feed operations
+type Repository interface {
+	PutActivity(ctx context.Context, activity *Activity) error
+	// ListActivities returns up to limit activities of keyID ordered before
+	// the seq before, newest first, of the given types or of any when types
+	// is empty. An empty before starts at the newest.
+	ListActivities(ctx context.Context, keyID string, types []string, before string, limit int) ([]Activity, error)
+}
+
+// activityItem is an activity as stored, under its seq with the ttl DynamoDB
+// expires it by
+type activityItem struct {
+	Activity
+	Seq string `dynamodbav:"seq"`
+	TTL int64  `dynamodbav:"ttl"`
+}
+
+// activityRepository implements Repository using a DynamoDB table keyed on
+// "keyId" and "seq"
+type activityRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	retention time.Duration
+}
+
+// NewRepository creates a DynamoDB-backed activity feed whose activities
+// expire after retention
+func NewRepository(client *dynamodb.Client, tableName string, retention time.Duration) Repository {
+	return &activityRepository{
+		client:    client,
+		tableName: tableName,
+		retention: retention,
+	}
+}
+
+// PutActivity stores an activity, replacing one recorded from the same event
+func (r *activityRepository) PutActivity(ctx context.Context, activity *Activity) error {
+	item, err := attributevalue.MarshalMap(activityItem{
+		Activity: *activity,
+		Seq:      activity.Seq(),
+		TTL:      time.Unix(activity.TimeUTC, 0).Add(r.retention).Unix(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal activity: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put activity %s: %w", activity.ID, err)
+	}
+
+	return nil
+}
+
+func (r *activityRepository) ListActivities(ctx context.Context, keyID string, activityTypes []string, before string, limit int) ([]Activity, error) {
+	keyCond := expression.Key("keyId").Equal(expression.Value(keyID))
+	if before != "" {
+		keyCond = keyCond.And(expression.Key("seq").LessThan(expression.Value(before)))
+	}
+	builder := expression.NewBuilder().WithKeyCondition(keyCond)
+	if len(activityTypes) > 0 {
+		operands := make([]expression.OperandBuilder, len(activityTypes))
+		for i, t := range activityTypes {
+			operands[i] = expression.Value(t)
+		}
+		// In takes at least one operand besides the first
+		filter := expression.Name("type").Equal(operands[0])
+		if len(operands) > 1 {
+			filter = expression.Name("type").In(operands[0], operands[1:]...)
+		}
+		builder = builder.WithFilter(filter)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var activities []Activity
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for len(activities) < limit {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ScanIndexForward:          aws.Bool(false),
+			// The filter applies after the limit, so pages may come back short
+			Limit: aws.Int32(int32(limit - len(activities))),
+		}
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query activities of %s: %w", keyID, err)
+		}
+
+		var batch []activityItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal activities: %w", err)
+		}
+		for _, item := range batch {
+			activities = append(activities, item.Activity)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return activities, nil
+}
+
+// activityPurger deletes the feeds of API keys
+type activityPurger struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewPurger creates a purger of the activity feeds of API keys
+func NewPurger(client *dynamodb.Client, tableName string) service.AccountPurger {
+	return &activityPurger{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PurgeAccount deletes the feed of keyID and returns how many activities were
+// deleted
+func (p *activityPurger) PurgeAccount(ctx context.Context, keyID string) (int, error) {
+	keyCond := expression.Key("keyId").Equal(expression.Value(keyID))
+	proj := expression.NamesList(expression.Name("keyId"), expression.Name("seq"))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithProjection(proj).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	return repository.QueryDelete(ctx, p.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(p.tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, nil)
+}
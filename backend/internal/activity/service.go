@@ -0,0 +1,132 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidQuery = errors.New("invalid activity query")
+	// ErrAPIKeyRequired rejects reading a feed without an API key, which
+	// happens when API keys are not required
+	ErrAPIKeyRequired = errors.New("activity feeds belong to API keys")
+)
+
+// Page sizes of the feed
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// Query selects a page of the calling key's feed
+type Query struct {
+	// Types filters the activities by type; empty lists every type
+	Types []string
+	// Limit is the page size, defaulting to 50 and at most 200
+	Limit int
+	// Cursor is the NextCursor of the previous page
+	Cursor string
+}
+
+// Page is a page of a feed, newest first
+type Page struct {
+	Activities []Activity `json:"activities"`
+	Count      int        `json:"count"`
+	// NextCursor continues the feed with older activities, when there are any
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type Service interface {
+	// Record adds the activity of a domain event to its account's feed, if
+	// the event is one the feed shows
+	Record(ctx context.Context, event events.Event) error
+	List(ctx context.Context, query Query) (*Page, error)
+}
+
+type activityService struct {
+	repo Repository
+	log  *zap.SugaredLogger
+}
+
+func NewService(repo Repository, log *zap.SugaredLogger) Service {
+	return &activityService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *activityService) Record(ctx context.Context, event events.Event) error {
+	activity, ok := FromEvent(event)
+	if !ok {
+		return nil
+	}
+	return s.repo.PutActivity(ctx, activity)
+}
+
+func (s *activityService) List(ctx context.Context, query Query) (*Page, error) {
+	key, ok := service.AccountFromContext(ctx)
+	if !ok {
+		return nil, ErrAPIKeyRequired
+	}
+
+	for _, t := range query.Types {
+		if !slices.Contains(Types, t) {
+			return nil, fmt.Errorf("%w: type %q is not one of %v", ErrInvalidQuery, t, Types)
+		}
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultLimit
+	}
+	if query.Limit < 0 || query.Limit > maxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, maxLimit)
+	}
+	if query.Cursor != "" && !validCursor(query.Cursor) {
+		return nil, fmt.Errorf("%w: cursor %q is not a nextCursor", ErrInvalidQuery, query.Cursor)
+	}
+
+	// One more than the page tells whether older activities follow
+	activities, err := s.repo.ListActivities(ctx, key.ID, query.Types, query.Cursor, query.Limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Activities: activities}
+	if len(activities) > query.Limit {
+		page.Activities = activities[:query.Limit]
+		page.NextCursor = page.Activities[query.Limit-1].Seq()
+	}
+	if page.Activities == nil {
+		page.Activities = []Activity{}
+	}
+	page.Count = len(page.Activities)
+	return page, nil
+}
+
+// recorder records the activities of the events published through it before
+// passing them on
+type recorder struct {
+	activity Service
+	next     events.Publisher
+	log      *zap.SugaredLogger
+}
+
+// Publish records the activity of each event, logging failures rather than
+// failing the publish, then publishes the events to the next publisher
+func (r *recorder) Publish(ctx context.Context, published ...events.Event) error {
+	for _, event := range published {
+		if err := r.activity.Record(ctx, event); err != nil {
+			r.log.Warnw("failed to record activity", "event", event.ID, "type", event.Type, "error", err)
+		}
+	}
+	if r.next == nil {
+		return nil
+	}
+	return r.next.Publish(ctx, published...)
+}
@@ -0,0 +1,210 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRepository is a Repository over a slice
+type memoryRepository struct {
+	activities []Activity
+}
+
+func (r *memoryRepository) PutActivity(ctx context.Context, activity *Activity) error {
+	r.activities = append(r.activities, *activity)
+	return nil
+}
+
+func (r *memoryRepository) ListActivities(ctx context.Context, keyID string, types []string, before string, limit int) ([]Activity, error) {
+	var feed []Activity
+	for _, a := range r.activities {
+		if a.KeyID == keyID && (len(types) == 0 || slices.Contains(types, a.Type)) && (before == "" || a.Seq() < before) {
+			feed = append(feed, a)
+		}
+	}
+	sort.Slice(feed, func(i, j int) bool { return feed[i].Seq() > feed[j].Seq() })
+	if len(feed) > limit {
+		feed = feed[:limit]
+	}
+	return feed, nil
+}
+
+func event(id string, timeUTC int64, data any) events.Event {
+	return events.Event{ID: id, Type: "Test", Version: 1, TimeUTC: timeUTC, Data: data}
+}
+
+func keyContext(id string) context.Context {
+	return service.WithAccount(context.Background(), &models.APIKey{ID: id, Name: id})
+}
+
+func TestFromEvent(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    any
+		typ     string
+		summary string
+	}{
+		{
+			name:    "alert",
+			data:    models.AlertTriggeredEvent{AlertID: "a1", KeyID: "alice", Symbol: "AAPL", Condition: "price_above", Threshold: 200, TriggeredClose: 201.5},
+			typ:     TypeAlertTriggered,
+			summary: "AAPL price_above alert triggered at 201.50",
+		},
+		{
+			name:    "signal alert",
+			data:    models.AlertTriggeredEvent{AlertID: "a2", KeyID: "alice", Symbol: "MSFT", Condition: "signal", SignalType: "golden_cross", TriggeredClose: 410},
+			typ:     TypeAlertTriggered,
+			summary: "MSFT golden_cross alert triggered at 410.00",
+		},
+		{
+			name:    "sell",
+			data:    models.TransactionRecordedEvent{PortfolioID: "p1", KeyID: "alice", Symbol: "AAPL", Type: "sell", Quantity: 2.5, Price: 187.5},
+			typ:     TypeTransactionRecorded,
+			summary: "Sold 2.5 AAPL at 187.50",
+		},
+		{
+			name:    "ticker import",
+			data:    models.DailySummaryIngestedEvent{Scope: models.IngestScopeTicker, Symbol: "AAPL", From: "2026-01-02", To: "2026-03-31", Stored: 61, JobID: "j1", KeyID: "alice"},
+			typ:     TypeImportCompleted,
+			summary: "Imported 61 daily summaries of AAPL from 2026-01-02 to 2026-03-31",
+		},
+		{
+			name:    "session login",
+			data:    models.LoggedInEvent{KeyID: "alice", Method: models.LoginMethodSession, SessionID: "s1", SessionName: "iPhone"},
+			typ:     TypeLogin,
+			summary: `Opened session "iPhone"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity, ok := FromEvent(event("e1", 1_700_000_000, tt.data))
+			require.True(t, ok)
+			assert.Equal(t, "e1", activity.ID)
+			assert.Equal(t, "alice", activity.KeyID)
+			assert.Equal(t, tt.typ, activity.Type)
+			assert.Equal(t, tt.summary, activity.Summary)
+			assert.Contains(t, string(activity.Data), `"keyId":"alice"`, "the data is the event's payload")
+		})
+	}
+
+	for name, data := range map[string]any{
+		"market ingest":   models.DailySummaryIngestedEvent{Scope: models.IngestScopeMarket, From: "2026-10-15", To: "2026-10-15", Stored: 9000},
+		"alert of no key": models.AlertTriggeredEvent{AlertID: "a1", Symbol: "AAPL"},
+		"ticker update":   models.TickerUpdatedEvent{Symbol: "AAPL", Change: models.TickerChangeUpdated},
+	} {
+		_, ok := FromEvent(event("e1", 1_700_000_000, data))
+		assert.False(t, ok, name)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	repo := &memoryRepository{}
+	next := &eventstest.RecordingPublisher{}
+	h := NewHandler(NewService(repo, zap.NewNop().Sugar()), zap.NewNop().Sugar())
+
+	published := []events.Event{
+		event("e1", 100, models.LoggedInEvent{KeyID: "alice", Method: models.LoginMethodPassword}),
+		event("e2", 101, models.TickerUpdatedEvent{Symbol: "AAPL"}),
+	}
+	require.NoError(t, h.Record(next).Publish(context.Background(), published...))
+	assert.Equal(t, published, next.Events(), "every event is passed on")
+	require.Len(t, repo.activities, 1)
+	assert.Equal(t, "Logged in with a password", repo.activities[0].Summary)
+
+	require.NoError(t, h.Record(nil).Publish(context.Background(), published[0]), "a nil next publishes nothing further")
+}
+
+// failingRepository fails to store activities
+type failingRepository struct {
+	memoryRepository
+}
+
+func (r *failingRepository) PutActivity(ctx context.Context, activity *Activity) error {
+	return errors.New("table unavailable")
+}
+
+func TestRecorder_FailuresDoNotFailThePublish(t *testing.T) {
+	next := &eventstest.RecordingPublisher{}
+	h := NewHandler(NewService(&failingRepository{}, zap.NewNop().Sugar()), zap.NewNop().Sugar())
+
+	published := event("e1", 100, models.LoggedInEvent{KeyID: "alice", Method: models.LoginMethodPassword})
+	require.NoError(t, h.Record(next).Publish(context.Background(), published))
+	assert.Len(t, next.Events(), 1)
+}
+
+func TestService_List(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	for i, data := range []any{
+		models.LoggedInEvent{KeyID: "alice", Method: models.LoginMethodPassword},
+		models.TransactionRecordedEvent{KeyID: "alice", Symbol: "AAPL", Type: "buy", Quantity: 10, Price: 150},
+		models.AlertTriggeredEvent{KeyID: "alice", Symbol: "AAPL", Condition: "price_above", TriggeredClose: 201},
+		models.LoggedInEvent{KeyID: "bob", Method: models.LoginMethodPassword},
+		models.LoggedInEvent{KeyID: "alice", Method: models.LoginMethodSession},
+	} {
+		require.NoError(t, svc.Record(ctx, event(string(rune('a'+i)), int64(100+i), data)))
+	}
+
+	page, err := svc.List(keyContext("alice"), Query{})
+	require.NoError(t, err)
+	assert.Equal(t, 4, page.Count, "only the calling key's activities are listed")
+	assert.Equal(t, []string{TypeLogin, TypeAlertTriggered, TypeTransactionRecorded, TypeLogin},
+		[]string{page.Activities[0].Type, page.Activities[1].Type, page.Activities[2].Type, page.Activities[3].Type}, "newest first")
+	assert.Empty(t, page.NextCursor)
+
+	t.Run("pages continue from the cursor", func(t *testing.T) {
+		first, err := svc.List(keyContext("alice"), Query{Limit: 3})
+		require.NoError(t, err)
+		assert.Equal(t, 3, first.Count)
+		require.NotEmpty(t, first.NextCursor)
+
+		second, err := svc.List(keyContext("alice"), Query{Limit: 3, Cursor: first.NextCursor})
+		require.NoError(t, err)
+		require.Equal(t, 1, second.Count)
+		assert.Equal(t, "a", second.Activities[0].ID)
+		assert.Empty(t, second.NextCursor)
+	})
+
+	t.Run("filtered by type", func(t *testing.T) {
+		page, err := svc.List(keyContext("alice"), Query{Types: []string{TypeLogin}})
+		require.NoError(t, err)
+		assert.Equal(t, 2, page.Count)
+		for _, a := range page.Activities {
+			assert.Equal(t, TypeLogin, a.Type)
+		}
+	})
+
+	t.Run("empty feeds list no activities", func(t *testing.T) {
+		page, err := svc.List(keyContext("carol"), Query{})
+		require.NoError(t, err)
+		assert.NotNil(t, page.Activities)
+		assert.Zero(t, page.Count)
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		for _, query := range []Query{
+			{Types: []string{"logout"}},
+			{Limit: maxLimit + 1},
+			{Cursor: "yesterday"},
+		} {
+			_, err := svc.List(keyContext("alice"), query)
+			assert.ErrorIs(t, err, ErrInvalidQuery, "%+v", query)
+		}
+		_, err := svc.List(ctx, Query{})
+		assert.ErrorIs(t, err, ErrAPIKeyRequired)
+	})
+}
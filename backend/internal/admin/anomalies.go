@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListHeldSummaries lists the daily summaries flagged as anomalous that
+// ingestion holds for review, oldest first
+func (h *Handler) ListHeldSummaries(c *gin.Context) {
+	held, err := h.anomalyService.ListHeld(c.Request.Context())
+	if err != nil {
+		h.respondAnomalyError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"held":  held,
+		"count": len(held),
+	})
+}
+
+// ApproveHeldSummary stores a held summary and releases it
+func (h *Handler) ApproveHeldSummary(c *gin.Context) {
+	symbol, date, ok := heldSummaryParams(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.anomalyService.Approve(c.Request.Context(), symbol, date)
+	if err != nil {
+		h.respondAnomalyError(c, err)
+		return
+	}
+
+	api.Logger(c, h.log).Infow("held summary approved", "symbol", symbol, "date", date)
+	c.JSON(http.StatusOK, summary)
+}
+
+// RejectHeldSummary discards a held summary without storing it
+func (h *Handler) RejectHeldSummary(c *gin.Context) {
+	symbol, date, ok := heldSummaryParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.anomalyService.Reject(c.Request.Context(), symbol, date); err != nil {
+		h.respondAnomalyError(c, err)
+		return
+	}
+
+	api.Logger(c, h.log).Infow("held summary rejected", "symbol", symbol, "date", date)
+	c.Status(http.StatusNoContent)
+}
+
+// heldSummaryParams reads the ticker and date of a held summary from the
+// path, answering 400 when the date is not YYYY-MM-DD
+func heldSummaryParams(c *gin.Context) (string, string, bool) {
+	date := c.Param("date")
+	if _, err := time.Parse(api.DateLayout, date); err != nil {
+		problem.Respond(c, problem.ValidationFailed, fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", date))
+		return "", "", false
+	}
+	return api.NormalizeSymbol(c.Param("symbol")), date, true
+}
+
+// respondAnomalyError maps anomaly service errors to HTTP responses
+func (h *Handler) respondAnomalyError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrHeldSummaryNotFound):
+		problem.Respond(c, problem.HeldSummaryNotFound, "No daily summary of the ticker and date is held")
+	default:
+		api.Logger(c, h.log).Errorw("failed to review held summaries", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to review held summaries")
+	}
+}
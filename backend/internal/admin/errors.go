@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/errorlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultErrorsWindow is summarized when the window parameter is omitted
+	defaultErrorsWindow = time.Hour
+	// maxErrorsWindow bounds the window; older errors have usually been
+	// overwritten in the log anyway
+	maxErrorsWindow = 7 * 24 * time.Hour
+)
+
+// ErrorReporter summarizes the server errors this replica answered
+type ErrorReporter interface {
+	Summarize(window time.Duration, now time.Time) errorlog.Summary
+}
+
+// GetErrors summarizes the recent 5xx responses of this replica by route and
+// problem code, for on-call triage without log access
+func (h *Handler) GetErrors(c *gin.Context) {
+	window := defaultErrorsWindow
+	if value := c.Query("window"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 || parsed > maxErrorsWindow {
+			problem.Respond(c, problem.ValidationFailed, "window must be a duration such as 15m or 1h, at most "+maxErrorsWindow.String())
+			return
+		}
+		window = parsed
+	}
+
+	c.JSON(http.StatusOK, h.errors.Summarize(window, time.Now()))
+}
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strconv"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventSchema is a published event type with the JSON schema of its data
+type eventSchema struct {
+	models.EventSchema
+	Schema *openapi.Schema `json:"schema"`
+}
+
+// schemaFormats maps the formats event schemas are served in to their file
+// extension and content type
+var schemaFormats = map[string]struct{ extension, contentType string }{
+	"avro":  {".avsc", "application/json"},
+	"proto": {".proto", "text/plain; charset=utf-8"},
+}
+
+// GetEventSchemas lists the domain event types published to the event bus,
+// with the version and JSON schema of each one's data, for the teams
+// subscribing to them
+func (h *Handler) GetEventSchemas(c *gin.Context) {
+	doc := openapi.New(openapi.Info{})
+	schemas := make([]eventSchema, len(models.EventCatalog))
+	for i, event := range models.EventCatalog {
+		schemas[i] = eventSchema{EventSchema: event, Schema: doc.Inline(event.Payload)}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"events": schemas, "count": len(schemas)})
+}
+
+// GetEventSchemaFile serves the generated Avro or protobuf schema of an event
+// type's data, at its current version or any version published before
+func (h *Handler) GetEventSchemaFile(c *gin.Context) {
+	eventType := c.Param("type")
+	format, ok := schemaFormats[c.DefaultQuery("format", "avro")]
+	if !ok {
+		problem.Respond(c, problem.ValidationFailed, "format must be avro or proto")
+		return
+	}
+
+	version := 0
+	for _, event := range models.EventCatalog {
+		if event.Type == eventType {
+			version = event.Version
+		}
+	}
+	if raw := c.Query("version"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			problem.Respond(c, problem.ValidationFailed, "version must be a positive integer")
+			return
+		}
+		version = v
+	}
+
+	file, err := fs.ReadFile(models.EventSchemaFiles, fmt.Sprintf("schemas/%s.v%d%s", eventType, version, format.extension))
+	if err != nil {
+		problem.Respond(c, problem.NotFound, fmt.Sprintf("No schema of %s version %d", eventType, version))
+		return
+	}
+	c.Data(http.StatusOK, format.contentType, file)
+}
@@ -0,0 +1,86 @@
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ingestRequest struct {
+	Symbol string `json:"symbol"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// TriggerIngest queues an immediate refresh of one ticker's daily summaries
+// over an inclusive date range. To defaults to today.
+func (h *Handler) TriggerIngest(c *gin.Context) {
+	var req ingestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	if req.From == "" {
+		problem.Respond(c, problem.ValidationFailed, "from is required")
+		return
+	}
+	from, err := time.Parse(api.DateLayout, req.From)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, fmt.Sprintf("invalid from date %q, expected YYYY-MM-DD", req.From))
+		return
+	}
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != "" {
+		if to, err = time.Parse(api.DateLayout, req.To); err != nil {
+			problem.Respond(c, problem.ValidationFailed, fmt.Sprintf("invalid to date %q, expected YYYY-MM-DD", req.To))
+			return
+		}
+	}
+
+	symbol := api.NormalizeSymbol(req.Symbol)
+	job, err := h.ingestService.Enqueue(c.Request.Context(), symbol, from, to)
+	if err != nil {
+		h.respondIngestError(c, err)
+		return
+	}
+
+	api.Logger(c, h.log).Infow("ingest requested", "symbol", symbol, "job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *Handler) GetIngestJob(c *gin.Context) {
+	job, err := h.ingestService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondIngestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// respondIngestError maps ingest service errors to HTTP responses
+func (h *Handler) respondIngestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTicker):
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+	case errors.Is(err, service.ErrInvalidRange):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, service.ErrIngestJobNotFound):
+		problem.Respond(c, problem.JobNotFound, "Ingest job not found")
+	case errors.Is(err, service.ErrIngestQueueFull):
+		problem.Respond(c, problem.QueueFull, err.Error())
+	case errors.Is(err, service.ErrIngestionUnavailable):
+		problem.Respond(c, problem.Unavailable, "Ingestion is not configured")
+	default:
+		api.Logger(c, h.log).Errorw("ingest request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process ingest")
+	}
+}
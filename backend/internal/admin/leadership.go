@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/lock"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LeadershipReporter reports which replica runs the background workers. It
+// is nil when no election runs, as with the memory storage backend.
+type LeadershipReporter interface {
+	Status(ctx context.Context) (*lock.LeaderStatus, error)
+}
+
+func (h *Handler) GetLeadership(c *gin.Context) {
+	if h.leadership == nil {
+		problem.Respond(c, problem.NotFound, "Leader election is disabled")
+		return
+	}
+
+	status, err := h.leadership.Status(c.Request.Context())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to get leadership", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve leadership")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
@@ -0,0 +1,217 @@
+// Package admin serves operational endpoints: runtime settings, ticker purges,
+// on-demand ingestion, review of the daily summaries ingestion held as
+// anomalous, background worker leadership, background task health,
+// recent server errors and the catalog of published domain events.
+package admin
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/errorlog"
+	"profitify-backend/pkg/lock"
+	"profitify-backend/pkg/openapi"
+	"profitify-backend/pkg/tasks"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	purgeService    service.PurgeService
+	ingestService   service.IngestService
+	anomalyService  service.AnomalyService
+	settingsService service.SettingsService
+	leadership      LeadershipReporter
+	tasks           TaskReporter
+	errors          ErrorReporter
+	log             *zap.SugaredLogger
+}
+
+// Wire builds the admin module from the shared dependencies. A nil source
+// disables on-demand ingestion; otherwise its worker runs as a background task.
+// errorLog is the log the router records server errors in.
+func Wire(deps app.Deps, source service.SummarySource, leadership LeadershipReporter, errorLog ErrorReporter) *Handler {
+	cfg := deps.Config
+	settings := deps.SettingsService()
+
+	ingest := service.NewIngestService(source, deps.DailySummaryRepository(), settings, deps.Events, deps.Log)
+	if source != nil {
+		deps.Tasks.Go("ingest-worker", ingest.Work)
+	}
+
+	return &Handler{
+		purgeService: service.NewPurgeService(
+			deps.DailySummaryRepository(), deps.IntradayBarRepository(), deps.SignalRepository(),
+			settings, deps.Tasks,
+			service.PurgeConfig{
+				WritesPerSecond: cfg.PurgeWritesPerSecond,
+				ConfirmationTTL: cfg.PurgeConfirmationTTL,
+			}, deps.Log),
+		ingestService:   ingest,
+		anomalyService:  deps.AnomalyService(),
+		settingsService: settings,
+		leadership:      leadership,
+		tasks:           deps.Tasks,
+		errors:          errorLog,
+		log:             deps.Log,
+	}
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	admin.GET("/leadership", h.GetLeadership)
+	admin.GET("/tasks", h.GetTasks)
+	admin.GET("/errors", h.GetErrors)
+	admin.GET("/events", h.GetEventSchemas)
+	admin.GET("/events/:type/schema", h.GetEventSchemaFile)
+	admin.GET("/settings", h.ListSettings)
+	admin.GET("/settings/:key", h.GetSetting)
+	admin.PUT("/settings/:key", h.PutSetting)
+	admin.DELETE("/settings/:key", h.DeleteSetting)
+	admin.POST("/tickers/:symbol/purge", h.RequestTickerPurge)
+	admin.POST("/tickers/:symbol/purge/confirm", h.ConfirmTickerPurge)
+	admin.GET("/purges/:id", h.GetPurgeJob)
+	admin.POST("/ingest", h.TriggerIngest)
+	admin.GET("/ingest/:id", h.GetIngestJob)
+	admin.GET("/held-summaries", h.ListHeldSummaries)
+	admin.POST("/held-summaries/:symbol/:date/approve", h.ApproveHeldSummary)
+	admin.DELETE("/held-summaries/:symbol/:date", h.RejectHeldSummary)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Admin"}
+	key := openapi.PathParam("key", "Setting key")
+	symbol := openapi.PathParam("symbol", "Ticker symbol, case insensitive")
+
+	doc.Add(http.MethodGet, "/api/v1/admin/leadership", &openapi.Operation{
+		Tags:      tags,
+		Summary:   "Get which replica runs the background workers",
+		Responses: api.Responses(http.StatusOK, doc.Schema(lock.LeaderStatus{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/admin/errors", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Summarize this replica's recent server errors",
+		Description: "Counts the 5xx responses of the window by route and problem code, most frequent first. Errors are " +
+			"kept in memory, the last 1,000 per replica, so `truncated` marks windows whose oldest errors were overwritten.",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("window", "How far back to summarize, e.g. 15m or 1h (default), at most 168h", nil),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(errorlog.Summary{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/admin/events", &openapi.Operation{
+		Tags:    tags,
+		Summary: "List the domain events published to the event bus",
+		Description: "Each event is published in an envelope of its id, type, version, source, timeUTC and data. The " +
+			"version changes when data changes incompatibly; the schema is that of the current version's data.",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "events", eventSchema{})),
+	})
+	doc.Add(http.MethodGet, "/api/v1/admin/events/:type/schema", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Get the Avro or protobuf schema of an event's data",
+		Description: "Schemas are generated from the event catalog for every version published. Within a version " +
+			"fields are only added, as optional fields after the existing ones.",
+		Parameters: []openapi.Parameter{
+			openapi.PathParam("type", "Event type, e.g. AlertTriggered"),
+			openapi.QueryParam("format", "avro (default) or proto", &openapi.Schema{Type: "string", Enum: []any{"avro", "proto"}}),
+			openapi.QueryParam("version", "Version of the data; defaults to the current one", &openapi.Schema{Type: "integer"}),
+		},
+		Responses: api.Responses(http.StatusOK, &openapi.Schema{Type: "string"}, http.StatusBadRequest, http.StatusNotFound),
+	})
+	// The payloads are documented as components for consumers generating
+	// their types from this document
+	for _, event := range models.EventCatalog {
+		doc.Schema(event.Payload)
+	}
+	doc.Add(http.MethodGet, "/api/v1/admin/tasks", &openapi.Operation{
+		Tags:      tags,
+		Summary:   "List this replica's background tasks and their health",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "tasks", tasks.Status{})),
+	})
+
+	doc.Add(http.MethodGet, "/api/v1/admin/settings", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "List runtime settings",
+		Parameters: []openapi.Parameter{openapi.QueryParam("prefix", "Only keys starting with the prefix", nil)},
+		Responses:  api.Responses(http.StatusOK, api.List(doc, "settings", models.Setting{})),
+	})
+	doc.Add(http.MethodGet, "/api/v1/admin/settings/:key", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get a runtime setting",
+		Parameters: []openapi.Parameter{key},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.Setting{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodPut, "/api/v1/admin/settings/:key", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Write a runtime setting",
+		Description: "With a version the write only succeeds if the stored setting is still at that version.",
+		Parameters:  []openapi.Parameter{key},
+		RequestBody: openapi.JSONBody(doc.Inline(putSettingRequest{})),
+		Responses:   api.Responses(http.StatusOK, doc.Schema(models.Setting{}), http.StatusBadRequest, http.StatusConflict),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/admin/settings/:key", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Delete a runtime setting",
+		Parameters: []openapi.Parameter{key},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+
+	doc.Add(http.MethodPost, "/api/v1/admin/tickers/:symbol/purge", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Request a confirmation token to purge a ticker's market data",
+		Parameters: []openapi.Parameter{symbol},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.PurgeConfirmation{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/tickers/:symbol/purge/confirm", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Start a confirmed purge of a ticker's market data",
+		Parameters:  []openapi.Parameter{symbol},
+		RequestBody: openapi.JSONBody(doc.Inline(confirmPurgeRequest{})),
+		Responses:   api.Responses(http.StatusAccepted, doc.Schema(models.PurgeJob{}), http.StatusBadRequest, http.StatusConflict),
+	})
+	doc.Add(http.MethodGet, "/api/v1/admin/purges/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get a purge job's progress",
+		Parameters: []openapi.Parameter{openapi.PathParam("id", "Purge job ID")},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.PurgeJob{}), http.StatusNotFound),
+	})
+
+	doc.Add(http.MethodPost, "/api/v1/admin/ingest", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Queue a refresh of a ticker's daily bars over a date range",
+		Description: "To defaults to today. Responds 503 when no market data provider is configured.",
+		RequestBody: openapi.JSONBody(doc.Inline(ingestRequest{})),
+		Responses: api.Responses(http.StatusAccepted, doc.Schema(models.IngestJob{}),
+			http.StatusBadRequest, http.StatusTooManyRequests, http.StatusServiceUnavailable),
+	})
+	doc.Add(http.MethodGet, "/api/v1/admin/ingest/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get an ingest job's progress",
+		Parameters: []openapi.Parameter{openapi.PathParam("id", "Ingest job ID")},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.IngestJob{}), http.StatusNotFound, http.StatusServiceUnavailable),
+	})
+
+	heldParams := []openapi.Parameter{symbol, openapi.PathParam("date", "Trading date of the held summary, YYYY-MM-DD")}
+	doc.Add(http.MethodGet, "/api/v1/admin/held-summaries", &openapi.Operation{
+		Tags:    tags,
+		Summary: "List the daily summaries held back from ingestion as anomalous",
+		Description: "With ANOMALY_HOLD, ingested summaries whose close moved improbably far from the previous one, by " +
+			"both its z-score and the interquartile fences of the ticker's recent returns, are held here instead of stored, oldest first.",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "held", models.HeldSummary{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/held-summaries/:symbol/:date/approve", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Store a held daily summary and release it",
+		Description: "The summary is stored still tagged as anomalous and announced with a DailySummaryIngested event.",
+		Parameters:  heldParams,
+		Responses:   api.Responses(http.StatusOK, doc.Schema(models.DailySummary{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/admin/held-summaries/:symbol/:date", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Discard a held daily summary without storing it",
+		Parameters: heldParams,
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusBadRequest, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,70 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type confirmPurgeRequest struct {
+	ConfirmationToken string `json:"confirmationToken"`
+}
+
+func (h *Handler) RequestTickerPurge(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	confirmation, err := h.purgeService.RequestPurge(c.Request.Context(), symbol)
+	if err != nil {
+		h.respondPurgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, confirmation)
+}
+
+func (h *Handler) ConfirmTickerPurge(c *gin.Context) {
+	var req confirmPurgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	job, err := h.purgeService.ConfirmPurge(c.Request.Context(), symbol, req.ConfirmationToken)
+	if err != nil {
+		h.respondPurgeError(c, err)
+		return
+	}
+
+	api.Logger(c, h.log).Infow("ticker purge confirmed", "symbol", symbol, "job", job.ID)
+	c.JSON(http.StatusAccepted, job)
+}
+
+func (h *Handler) GetPurgeJob(c *gin.Context) {
+	job, err := h.purgeService.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondPurgeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// respondPurgeError maps purge service errors to HTTP responses
+func (h *Handler) respondPurgeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTicker):
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+	case errors.Is(err, service.ErrInvalidConfirmation):
+		problem.Respond(c, problem.Conflict, err.Error())
+	case errors.Is(err, service.ErrPurgeJobNotFound):
+		problem.Respond(c, problem.JobNotFound, "Purge job not found")
+	default:
+		api.Logger(c, h.log).Errorw("purge request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process purge")
+	}
+}
@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type putSettingRequest struct {
+	Value string `json:"value"`
+	// Version, when given, makes the write conditional on the stored version
+	Version *int64 `json:"version"`
+}
+
+func (h *Handler) ListSettings(c *gin.Context) {
+	settings, err := h.settingsService.ListSettings(c.Request.Context(), c.Query("prefix"))
+	if err != nil {
+		h.respondSettingError(c, err)
+		return
+	}
+
+	if settings == nil {
+		settings = []models.Setting{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"settings": settings,
+		"count":    len(settings),
+	})
+}
+
+func (h *Handler) GetSetting(c *gin.Context) {
+	setting, err := h.settingsService.GetSetting(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		h.respondSettingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+func (h *Handler) PutSetting(c *gin.Context) {
+	var req putSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	setting, err := h.settingsService.PutSetting(c.Request.Context(), c.Param("key"), req.Value, req.Version)
+	if err != nil {
+		h.respondSettingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, setting)
+}
+
+func (h *Handler) DeleteSetting(c *gin.Context) {
+	if err := h.settingsService.DeleteSetting(c.Request.Context(), c.Param("key")); err != nil {
+		h.respondSettingError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// respondSettingError maps settings service errors to HTTP responses
+func (h *Handler) respondSettingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrSettingNotFound):
+		problem.Respond(c, problem.SettingNotFound, "Setting not found")
+	case errors.Is(err, service.ErrSettingConflict):
+		problem.Respond(c, problem.Conflict, err.Error())
+	case errors.Is(err, service.ErrInvalidSetting):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("settings request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process setting")
+	}
+}
@@ -0,0 +1,23 @@
+package admin
+
+import (
+	"net/http"
+
+	"profitify-backend/pkg/tasks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TaskReporter reports the health of this replica's background tasks
+type TaskReporter interface {
+	Statuses() []tasks.Status
+}
+
+func (h *Handler) GetTasks(c *gin.Context) {
+	statuses := h.tasks.Statuses()
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": statuses,
+		"count": len(statuses),
+	})
+}
@@ -0,0 +1,130 @@
+package alerts
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/schedule"
+)
+
+// Condition is what an alert watches for in the latest daily close
+type Condition string
+
+const (
+	// PriceAbove fires when the close is at or above the threshold
+	PriceAbove Condition = "price_above"
+	// PriceBelow fires when the close is at or below the threshold
+	PriceBelow Condition = "price_below"
+	// ChangeAbove fires when the close moved at least threshold percent,
+	// in either direction, against the prior session's close
+	ChangeAbove Condition = "change_above"
+	// SignalFlagged fires when the market scanner flags the session of the
+	// latest close with the alert's signal type. A positive threshold is the
+	// least gap percent, in either direction, or volume multiple that fires.
+	SignalFlagged Condition = "signal"
+)
+
+// Alert statuses. An alert fires once and then stays triggered.
+const (
+	StatusActive    = "active"
+	StatusTriggered = "triggered"
+)
+
+// Alert watches the daily closes of a ticker for a condition
+type Alert struct {
+	ID        string    `json:"id" dynamodbav:"id"`
+	Symbol    string    `json:"symbol" dynamodbav:"symbol"`
+	Condition Condition `json:"condition" dynamodbav:"condition"`
+	Threshold float64   `json:"threshold" dynamodbav:"threshold"`
+	// SignalType is the scanner signal a signal alert waits for
+	SignalType string `json:"signalType,omitempty" dynamodbav:"signalType,omitempty"`
+	Status     string `json:"status" dynamodbav:"status"`
+	// Note is an optional free-form reminder included in notifications
+	Note string `json:"note,omitempty" dynamodbav:"note,omitempty"`
+	// Schedule checks the alert once a day at a local time in the holder's
+	// time zone, like 08:00 in America/Toronto, instead of on every
+	// evaluation, so it fires, and is notified, only then
+	Schedule   *schedule.Schedule `json:"schedule,omitempty" dynamodbav:"schedule,omitempty"`
+	CreatedUTC int64              `json:"createdUTC" dynamodbav:"createdUTC"`
+	// CheckedUTC is when a scheduled alert was last checked without firing
+	CheckedUTC int64 `json:"checkedUTC,omitempty" dynamodbav:"checkedUTC,omitempty"`
+	// NextCheckUTC is when an active, scheduled alert is next checked; it is
+	// computed on reading, not stored
+	NextCheckUTC int64 `json:"nextCheckUTC,omitempty" dynamodbav:"-"`
+	// TriggeredUTC and TriggeredClose record the close that fired the alert
+	TriggeredUTC   int64   `json:"triggeredUTC,omitempty" dynamodbav:"triggeredUTC,omitempty"`
+	TriggeredClose float32 `json:"triggeredClose,omitempty" dynamodbav:"triggeredClose,omitempty"`
+	// KeyID is the API key that created the alert, counted against its plan
+	KeyID string `json:"-" dynamodbav:"keyId,omitempty"`
+}
+
+// Validate checks if the alert data is valid
+func (a *Alert) Validate() error {
+	if a.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+
+	switch a.Condition {
+	case PriceAbove, PriceBelow, ChangeAbove:
+		if a.Threshold <= 0 || math.IsInf(a.Threshold, 0) || math.IsNaN(a.Threshold) {
+			return fmt.Errorf("threshold must be a positive number")
+		}
+		if a.SignalType != "" {
+			return fmt.Errorf("signal type is only allowed on %s alerts", SignalFlagged)
+		}
+	case SignalFlagged:
+		switch a.SignalType {
+		case models.SignalGapUp, models.SignalGapDown, models.SignalUnusualVolume:
+		default:
+			return fmt.Errorf("signal type must be one of %s, %s or %s", models.SignalGapUp, models.SignalGapDown, models.SignalUnusualVolume)
+		}
+		if a.Threshold < 0 || math.IsInf(a.Threshold, 0) || math.IsNaN(a.Threshold) {
+			return fmt.Errorf("threshold cannot be negative")
+		}
+	default:
+		return fmt.Errorf("condition must be one of %s, %s, %s or %s", PriceAbove, PriceBelow, ChangeAbove, SignalFlagged)
+	}
+
+	if len(a.Note) > 200 {
+		return fmt.Errorf("note must be at most 200 characters")
+	}
+
+	if a.Schedule != nil {
+		if err := a.Schedule.Validate(); err != nil {
+			return fmt.Errorf("schedule: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// nextCheck returns when a scheduled alert is next checked: at the first run
+// of its schedule after it was last checked, or created
+func (a *Alert) nextCheck() (time.Time, error) {
+	since := max(a.CheckedUTC, a.CreatedUTC)
+	return a.Schedule.Next(time.Unix(since, 0))
+}
+
+// Matches reports whether a quote satisfies the alert's price or change
+// condition. Signal alerts are matched by MatchesSignal.
+func (a *Alert) Matches(quote models.Quote) bool {
+	switch a.Condition {
+	case PriceAbove:
+		return float64(quote.Close) >= a.Threshold
+	case PriceBelow:
+		return float64(quote.Close) <= a.Threshold
+	case ChangeAbove:
+		return quote.PreviousClose > 0 && math.Abs(quote.ChangePercent) >= a.Threshold
+	}
+	return false
+}
+
+// MatchesSignal reports whether a scanner signal satisfies a signal alert
+func (a *Alert) MatchesSignal(signal models.Signal) bool {
+	return a.Condition == SignalFlagged &&
+		signal.Ticker == a.Symbol &&
+		signal.Type == a.SignalType &&
+		math.Abs(signal.Value) >= a.Threshold
+}
@@ -0,0 +1,61 @@
+package alerts
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FuzzAlertRule decodes alert request bodies as CreateAlert does and evaluates
+// the valid rules against a quote, checking that no body panics validation or
+// matching and that valid rules are well-formed
+func FuzzAlertRule(f *testing.F) {
+	for _, seed := range []string{
+		`{"symbol":"AAPL","condition":"price_above","threshold":150}`,
+		`{"symbol":"AAPL","condition":"change_above","threshold":1e308}`,
+		`{"symbol":"AAPL","condition":"signal","signalType":"gap_up","threshold":0}`,
+		`{"symbol":"","condition":"price_below","threshold":-1}`,
+		`{"condition":"signal","signalType":"volume","note":"` + string(make([]byte, 201)) + `"}`,
+		`{"threshold":"NaN"}`,
+		`[]`,
+	} {
+		f.Add([]byte(seed), float32(150), float32(148.5))
+	}
+
+	f.Fuzz(func(t *testing.T, body []byte, close, previousClose float32) {
+		var req alertRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			return
+		}
+		alert := Alert{
+			Symbol:     req.Symbol,
+			Condition:  req.Condition,
+			Threshold:  req.Threshold,
+			SignalType: req.SignalType,
+			Note:       req.Note,
+		}
+		if alert.Validate() != nil {
+			return
+		}
+
+		assert.NotEmpty(t, alert.Symbol)
+		assert.False(t, math.IsNaN(alert.Threshold) || math.IsInf(alert.Threshold, 0))
+		assert.GreaterOrEqual(t, alert.Threshold, 0.0)
+
+		quote := models.NewQuote(models.DailySummary{Ticker: alert.Symbol, Close: close}, previousClose)
+		matched := alert.Matches(quote)
+		switch alert.Condition {
+		case PriceAbove:
+			assert.Equal(t, float64(close) >= alert.Threshold, matched)
+		case PriceBelow:
+			assert.Equal(t, float64(close) <= alert.Threshold, matched)
+		case SignalFlagged:
+			assert.False(t, matched, "signal alerts match signals, not quotes")
+		}
+		alert.MatchesSignal(models.Signal{Ticker: alert.Symbol, Type: alert.SignalType, Value: float64(close)})
+	})
+}
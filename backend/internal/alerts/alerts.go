@@ -0,0 +1,94 @@
+package alerts
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/schedule"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type alertRequest struct {
+	Symbol     string             `json:"symbol"`
+	Condition  Condition          `json:"condition"`
+	Threshold  float64            `json:"threshold"`
+	SignalType string             `json:"signalType,omitempty"`
+	Note       string             `json:"note"`
+	Schedule   *schedule.Schedule `json:"schedule,omitempty"`
+}
+
+// ListAlerts returns the caller's alerts, optionally filtered by ?status=active|triggered
+func (h *Handler) ListAlerts(c *gin.Context) {
+	alerts, err := h.alertService.ListAlerts(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		h.respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"alerts": alerts,
+		"count":  len(alerts),
+	})
+}
+
+func (h *Handler) CreateAlert(c *gin.Context) {
+	var req alertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	alert, err := h.alertService.CreateAlert(c.Request.Context(), &Alert{
+		Symbol:     req.Symbol,
+		Condition:  req.Condition,
+		Threshold:  req.Threshold,
+		SignalType: req.SignalType,
+		Note:       req.Note,
+		Schedule:   req.Schedule,
+	})
+	if err != nil {
+		h.respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, alert)
+}
+
+func (h *Handler) GetAlert(c *gin.Context) {
+	alert, err := h.alertService.GetAlert(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondAlertError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, alert)
+}
+
+func (h *Handler) DeleteAlert(c *gin.Context) {
+	if err := h.alertService.DeleteAlert(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondAlertError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondAlertError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrAlertNotFound):
+		problem.Respond(c, problem.AlertNotFound, "Alert not found")
+	case errors.Is(err, ErrInvalidAlert):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, service.ErrUpgradeRequired):
+		problem.Respond(c, problem.UpgradeRequired, err.Error())
+	case errors.Is(err, service.ErrPlanLimitExceeded):
+		problem.Respond(c, problem.PlanLimitExceeded, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("alert request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process alert request")
+	}
+}
@@ -0,0 +1,139 @@
+// Package alerts serves price and daily change alerts on tickers and runs the
+// worker that evaluates them against the latest daily closes.
+package alerts
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/devices"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	alertService Service
+	// interval is how often RunEvaluator evaluates the active alerts
+	interval time.Duration
+	log      *zap.SugaredLogger
+}
+
+func NewHandler(alerts Service, interval time.Duration, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		alertService: alerts,
+		interval:     interval,
+		log:          log,
+	}
+}
+
+// Wire builds the alerts module from the shared dependencies. Triggered
+// alerts are always logged, and also posted to the alert webhook and pushed to
+// registered devices when those are configured.
+func Wire(deps app.Deps) *Handler {
+	cfg := deps.Config
+
+	notifier := notify.Log(deps.Log)
+	if cfg.AlertWebhookURL != "" {
+		notifier = notify.Multi(notifier, notify.Webhook(cfg.AlertWebhookURL, cfg.AlertWebhookTimeout, deps.Templates))
+	}
+	if cfg.SlackWebhookURL != "" {
+		notifier = notify.Multi(notifier, notify.Slack(cfg.SlackWebhookURL, cfg.AlertWebhookTimeout, deps.Templates))
+	}
+
+	return NewHandler(NewService(
+		NewRepository(deps.DB, cfg.AlertsTable),
+		deps.APIKeyRepository(),
+		service.NewDailySummaryService(deps.DailySummaryRepository(), deps.Log),
+		deps.SignalRepository(),
+		devices.WithPush(deps, notifier),
+		deps.Templates,
+		deps.Events,
+		deps.Clock,
+		deps.Log,
+	), cfg.AlertEvalInterval, deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	alerts := api.Group("/alerts", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms())
+	alerts.GET("", h.ListAlerts)
+	alerts.POST("", h.CreateAlert)
+	alerts.GET("/:id", h.GetAlert)
+	alerts.DELETE("/:id", h.DeleteAlert)
+}
+
+// RunEvaluator evaluates the active alerts every interval until ctx is done.
+// A failed round is logged and retried at the next tick. A non-positive
+// interval disables evaluation.
+func (h *Handler) RunEvaluator(ctx context.Context) error {
+	if h.interval <= 0 {
+		h.log.Warnw("alert evaluation disabled", "interval", h.interval)
+		return nil
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		fired, err := h.alertService.Evaluate(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			h.log.Errorw("alert evaluation failed", "fired", fired, "error", err)
+		case fired > 0:
+			h.log.Infow("alerts triggered", "fired", fired)
+		}
+	}
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Alerts"}
+	id := openapi.PathParam("id", "Alert ID")
+
+	doc.Add(http.MethodGet, "/api/v1/alerts", &openapi.Operation{
+		Tags:    tags,
+		Summary: "List the alerts created with the calling key",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("status", "Only alerts in the status", &openapi.Schema{
+				Type: "string",
+				Enum: []any{StatusActive, StatusTriggered},
+			}),
+		},
+		Responses: api.Responses(http.StatusOK, api.List(doc, "alerts", Alert{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/api/v1/alerts", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Create an alert",
+		Description: "Conditions are price_above, price_below, change_above (absolute daily % change) and signal, " +
+			"which fires when the market scanner flags the latest session with signalType (gap_up, gap_down or unusual_volume) " +
+			"and treats a positive threshold as the least gap percent or volume multiple. " +
+			"Active alerts are evaluated against the latest daily close and fire once. " +
+			"An alert with a schedule is only checked once a day, at the localTime (HH:MM) of its IANA timezone, following the zone across daylight saving time changes.",
+		RequestBody: openapi.JSONBody(doc.Inline(alertRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(Alert{}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+	doc.Add(http.MethodGet, "/api/v1/alerts/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get an alert",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(Alert{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/alerts/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Delete an alert",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,224 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// errAlertNotActive is returned when an alert that is no longer active is
+// marked triggered or checked
+var errAlertNotActive = errors.New("alert is not active")
+
+// Repository defines the interface for alert data operations
+type Repository interface {
+	GetAlert(ctx context.Context, id string) (*Alert, error)
+	ListAlerts(ctx context.Context, status string) ([]Alert, error)
+	PutAlert(ctx context.Context, alert *Alert) error
+	MarkTriggered(ctx context.Context, id string, triggeredUTC int64, close float32) error
+	MarkChecked(ctx context.Context, id string, checkedUTC int64) error
+	DeleteAlert(ctx context.Context, id string) error
+}
+
+// alertRepository implements Repository using DynamoDB
+type alertRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed alert repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &alertRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetAlert retrieves a single alert by ID
+func (r *alertRepository) GetAlert(ctx context.Context, id string) (*Alert, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAlertNotFound, id)
+	}
+
+	var alert Alert
+	if err := attributevalue.UnmarshalMap(result.Item, &alert); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal alert: %w", err)
+	}
+
+	return &alert, nil
+}
+
+// ListAlerts retrieves all alerts, or only those with the given status when it is set
+func (r *alertRepository) ListAlerts(ctx context.Context, status string) ([]Alert, error) {
+	var alerts []Alert
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if status != "" {
+			filter := expression.Name("status").Equal(expression.Value(status))
+			expr, err := expression.NewBuilder().WithFilter(filter).Build()
+			if err != nil {
+				return nil, fmt.Errorf("failed to build expression: %w", err)
+			}
+			input.FilterExpression = expr.Filter()
+			input.ExpressionAttributeNames = expr.Names()
+			input.ExpressionAttributeValues = expr.Values()
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alerts: %w", err)
+		}
+
+		var batch []Alert
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alerts: %w", err)
+		}
+
+		alerts = append(alerts, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return alerts, nil
+}
+
+// PutAlert creates or replaces an alert
+func (r *alertRepository) PutAlert(ctx context.Context, alert *Alert) error {
+	item, err := attributevalue.MarshalMap(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put alert %s: %w", alert.ID, err)
+	}
+
+	return nil
+}
+
+// MarkTriggered moves an active alert to triggered. The update is conditional,
+// so when several evaluators race only one of them succeeds; the others get
+// errAlertNotActive.
+func (r *alertRepository) MarkTriggered(ctx context.Context, id string, triggeredUTC int64, close float32) error {
+	update := expression.Set(expression.Name("status"), expression.Value(StatusTriggered)).
+		Set(expression.Name("triggeredUTC"), expression.Value(triggeredUTC)).
+		Set(expression.Name("triggeredClose"), expression.Value(close))
+	cond := expression.Name("status").Equal(expression.Value(StatusActive))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", errAlertNotActive, id)
+		}
+		return fmt.Errorf("failed to mark alert %s triggered: %w", id, err)
+	}
+
+	return nil
+}
+
+// MarkChecked records when an active, scheduled alert was checked without
+// firing. Alerts no longer active get errAlertNotActive.
+func (r *alertRepository) MarkChecked(ctx context.Context, id string, checkedUTC int64) error {
+	update := expression.Set(expression.Name("checkedUTC"), expression.Value(checkedUTC))
+	cond := expression.Name("status").Equal(expression.Value(StatusActive))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", errAlertNotActive, id)
+		}
+		return fmt.Errorf("failed to mark alert %s checked: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteAlert deletes an alert, failing if it does not exist
+func (r *alertRepository) DeleteAlert(ctx context.Context, id string) error {
+	cond := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrAlertNotFound, id)
+		}
+		return fmt.Errorf("failed to delete alert %s: %w", id, err)
+	}
+
+	return nil
+}
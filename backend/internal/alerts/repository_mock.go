@@ -0,0 +1,44 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository mocks the Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetAlert(ctx context.Context, id string) (*Alert, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Alert), args.Error(1)
+}
+
+func (m *MockRepository) ListAlerts(ctx context.Context, status string) ([]Alert, error) {
+	args := m.Called(ctx, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Alert), args.Error(1)
+}
+
+func (m *MockRepository) PutAlert(ctx context.Context, alert *Alert) error {
+	return m.Called(ctx, alert).Error(0)
+}
+
+func (m *MockRepository) MarkTriggered(ctx context.Context, id string, triggeredUTC int64, close float32) error {
+	return m.Called(ctx, id, triggeredUTC, close).Error(0)
+}
+
+func (m *MockRepository) MarkChecked(ctx context.Context, id string, checkedUTC int64) error {
+	return m.Called(ctx, id, checkedUTC).Error(0)
+}
+
+func (m *MockRepository) DeleteAlert(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
@@ -0,0 +1,402 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/schedule"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/notify"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAlertNotFound = errors.New("alert not found")
+	ErrInvalidAlert  = errors.New("invalid alert")
+)
+
+type Service interface {
+	CreateAlert(ctx context.Context, alert *Alert) (*Alert, error)
+	GetAlert(ctx context.Context, id string) (*Alert, error)
+	ListAlerts(ctx context.Context, status string) ([]Alert, error)
+	DeleteAlert(ctx context.Context, id string) error
+	Evaluate(ctx context.Context) (int, error)
+}
+
+// SignalReader reads the market scanner's signals of a trading date
+type SignalReader interface {
+	GetSignals(ctx context.Context, date string) ([]models.Signal, error)
+}
+
+type alertService struct {
+	repo      Repository
+	keys      repository.APIKeyRepository
+	quotes    service.DailySummaryService
+	signals   SignalReader
+	notifier  notify.Notifier
+	templates *notify.Templates
+	events    events.Publisher
+	clock     clock.Clock
+	log       *zap.SugaredLogger
+}
+
+// NewService notifies the holders of fired alerts through notifier, composed
+// with templates, and publishes an AlertTriggered event for each; a nil
+// publisher publishes none. Alerts of deleted accounts, looked up in keys,
+// are not evaluated. Alerts are stamped created and triggered by c.
+func NewService(repo Repository, keys repository.APIKeyRepository, quotes service.DailySummaryService, signals SignalReader, notifier notify.Notifier, templates *notify.Templates, publisher events.Publisher, c clock.Clock, log *zap.SugaredLogger) Service {
+	return &alertService{
+		repo:      repo,
+		keys:      keys,
+		quotes:    quotes,
+		signals:   signals,
+		notifier:  notifier,
+		templates: templates,
+		events:    publisher,
+		clock:     c,
+		log:       log,
+	}
+}
+
+func (s *alertService) CreateAlert(ctx context.Context, alert *Alert) (*Alert, error) {
+	created := *alert
+	created.Symbol = strings.ToUpper(strings.TrimSpace(created.Symbol))
+	created.Condition = Condition(strings.ToLower(string(created.Condition)))
+	created.SignalType = strings.ToLower(strings.TrimSpace(created.SignalType))
+	created.Note = strings.TrimSpace(created.Note)
+	if created.Schedule != nil {
+		created.Schedule = &schedule.Schedule{
+			Timezone:  strings.TrimSpace(created.Schedule.Timezone),
+			LocalTime: strings.TrimSpace(created.Schedule.LocalTime),
+		}
+	}
+	if err := created.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlert, err)
+	}
+
+	if err := s.enforceAlertLimit(ctx); err != nil {
+		return nil, err
+	}
+
+	id, err := service.NewID()
+	if err != nil {
+		return nil, err
+	}
+	created.ID = id
+	created.KeyID = service.CallerKeyID(ctx)
+	created.Status = StatusActive
+	created.CreatedUTC = s.clock.Now().Unix()
+	created.TriggeredUTC, created.TriggeredClose = 0, 0
+	created.CheckedUTC = 0
+	s.setNextCheck(&created)
+
+	if err := s.repo.PutAlert(ctx, &created); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to create alert", "symbol", created.Symbol, "error", err)
+		return nil, fmt.Errorf("failed to create alert: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created alert", "alert", id, "symbol", created.Symbol, "condition", created.Condition, "threshold", created.Threshold)
+	return &created, nil
+}
+
+// enforceAlertLimit checks that the caller's plan allows one more active alert
+func (s *alertService) enforceAlertLimit(ctx context.Context) error {
+	plan, ok := service.LimitedPlan(ctx)
+	if !ok || plan.Alerts == 0 {
+		return nil
+	}
+	keyID := service.CallerKeyID(ctx)
+
+	active, err := s.repo.ListAlerts(ctx, StatusActive)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to count alerts", "error", err)
+		return fmt.Errorf("failed to count alerts: %w", err)
+	}
+	owned := 0
+	for _, alert := range active {
+		if alert.KeyID == keyID {
+			owned++
+		}
+	}
+
+	return service.EnforcePlanLimit(ctx, owned+1, "active alerts", func(p models.Plan) int {
+		return p.Alerts
+	})
+}
+
+func (s *alertService) GetAlert(ctx context.Context, id string) (*Alert, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidAlert)
+	}
+
+	alert, err := s.repo.GetAlert(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrAlertNotFound) {
+			return nil, ErrAlertNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get alert", "alert", id, "error", err)
+		return nil, fmt.Errorf("failed to get alert: %w", err)
+	}
+	// Other keys' alerts are reported missing rather than forbidden so that
+	// their IDs cannot be probed
+	if alert.KeyID != service.CallerKeyID(ctx) {
+		return nil, ErrAlertNotFound
+	}
+
+	s.setNextCheck(alert)
+	return alert, nil
+}
+
+// setNextCheck sets when an active, scheduled alert is next checked
+func (s *alertService) setNextCheck(alert *Alert) {
+	if alert.Status != StatusActive || alert.Schedule == nil {
+		return
+	}
+	if next, err := alert.nextCheck(); err == nil {
+		alert.NextCheckUTC = max(next.Unix(), s.clock.Now().Unix())
+	}
+}
+
+// ListAlerts returns the caller's alerts with the given status, or all of
+// them when it is empty, newest first
+func (s *alertService) ListAlerts(ctx context.Context, status string) ([]Alert, error) {
+	switch status {
+	case "", StatusActive, StatusTriggered:
+	default:
+		return nil, fmt.Errorf("%w: status must be %s or %s", ErrInvalidAlert, StatusActive, StatusTriggered)
+	}
+
+	all, err := s.repo.ListAlerts(ctx, status)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list alerts", "error", err)
+		return nil, fmt.Errorf("failed to list alerts: %w", err)
+	}
+
+	keyID := service.CallerKeyID(ctx)
+	alerts := make([]Alert, 0, len(all))
+	for _, alert := range all {
+		if alert.KeyID == keyID {
+			s.setNextCheck(&alert)
+			alerts = append(alerts, alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedUTC > alerts[j].CreatedUTC
+	})
+	return alerts, nil
+}
+
+func (s *alertService) DeleteAlert(ctx context.Context, id string) error {
+	if _, err := s.GetAlert(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteAlert(ctx, id); err != nil {
+		if errors.Is(err, ErrAlertNotFound) {
+			return ErrAlertNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to delete alert", "alert", id, "error", err)
+		return fmt.Errorf("failed to delete alert: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("deleted alert", "alert", id)
+	return nil
+}
+
+// Evaluate checks every active alert against the latest daily close of its
+// ticker, or the scanner signals of that session for signal alerts, marks
+// the matching ones triggered and notifies about them. It returns how many
+// alerts fired. Alerts are marked before they are notified, so an alert
+// fires at most once even when several replicas evaluate it.
+// Scheduled alerts are only checked once their schedule's next run after the
+// last check has come, and marked checked when they do not fire.
+func (s *alertService) Evaluate(ctx context.Context) (int, error) {
+	active, err := s.repo.ListAlerts(ctx, StatusActive)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active alerts: %w", err)
+	}
+
+	keyIDs := make([]string, len(active))
+	for i, alert := range active {
+		keyIDs[i] = alert.KeyID
+	}
+	deleted, err := service.DeletedAccounts(ctx, s.keys, keyIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	alerts := make([]Alert, 0, len(active))
+	for _, alert := range active {
+		// Deleted accounts are not notified while their data awaits the purge
+		if deleted[alert.KeyID] {
+			continue
+		}
+		if alert.Schedule != nil {
+			next, err := alert.nextCheck()
+			if err != nil {
+				logger.FromContext(ctx, s.log).Warnw("skipping alert with an invalid schedule", "alert", alert.ID, "error", err)
+				continue
+			}
+			if now.Before(next) {
+				continue
+			}
+		}
+		alerts = append(alerts, alert)
+	}
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+
+	var symbols []string
+	seen := make(map[string]bool)
+	for _, alert := range alerts {
+		if !seen[alert.Symbol] {
+			seen[alert.Symbol] = true
+			symbols = append(symbols, alert.Symbol)
+		}
+	}
+
+	results, errs := service.LatestQuotes(ctx, s.quotes, symbols)
+	quotes := make(map[string]*models.Quote, len(symbols))
+	for i, symbol := range symbols {
+		switch {
+		case errs[i] == nil:
+			quotes[symbol] = results[i]
+		case errors.Is(errs[i], service.ErrTickerNotFound):
+			// Alerts on tickers without summaries wait for their first close
+		default:
+			logger.FromContext(ctx, s.log).Warnw("failed to get quote for alerts", "symbol", symbol, "error", errs[i])
+		}
+	}
+
+	signals, err := s.sessionSignals(ctx, alerts, quotes)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for _, alert := range alerts {
+		// Scheduled alerts without a quote stay due and are retried
+		quote, ok := quotes[alert.Symbol]
+		if !ok {
+			continue
+		}
+		var signal *models.Signal
+		var matched bool
+		if alert.Condition == SignalFlagged {
+			signal = matchingSignal(alert, signals[quote.Date()])
+			matched = signal != nil
+		} else {
+			matched = alert.Matches(*quote)
+		}
+		if !matched {
+			if alert.Schedule != nil {
+				s.markChecked(ctx, alert, now)
+			}
+			continue
+		}
+
+		if err := s.repo.MarkTriggered(ctx, alert.ID, now.Unix(), quote.Close); err != nil {
+			if errors.Is(err, errAlertNotActive) {
+				continue
+			}
+			return fired, fmt.Errorf("failed to mark alert %s triggered: %w", alert.ID, err)
+		}
+		fired++
+
+		alert.Status = StatusTriggered
+		alert.TriggeredUTC = now.Unix()
+		alert.TriggeredClose = quote.Close
+		if n, err := alertNotification(s.templates, alert, *quote, signal); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to compose alert notification", "alert", alert.ID, "symbol", alert.Symbol, "error", err)
+		} else if err := s.notifier.Notify(ctx, n); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to deliver alert notification", "alert", alert.ID, "symbol", alert.Symbol, "error", err)
+		}
+		service.PublishEvent(ctx, s.events, s.log, models.EventAlertTriggered, models.EventAlertTriggeredVersion, models.AlertTriggeredEvent{
+			AlertID:        alert.ID,
+			KeyID:          alert.KeyID,
+			Symbol:         alert.Symbol,
+			Condition:      string(alert.Condition),
+			Threshold:      alert.Threshold,
+			SignalType:     alert.SignalType,
+			TriggeredUTC:   alert.TriggeredUTC,
+			TriggeredClose: float64(alert.TriggeredClose),
+		})
+	}
+
+	return fired, nil
+}
+
+// markChecked records that a scheduled alert was checked at now without
+// firing, so it waits for its schedule's next run. A failure is logged and
+// leaves the alert due at the next evaluation.
+func (s *alertService) markChecked(ctx context.Context, alert Alert, now time.Time) {
+	err := s.repo.MarkChecked(ctx, alert.ID, now.Unix())
+	if err != nil && !errors.Is(err, errAlertNotActive) {
+		logger.FromContext(ctx, s.log).Warnw("failed to mark scheduled alert checked", "alert", alert.ID, "error", err)
+	}
+}
+
+// sessionSignals returns the scanner signals of the sessions the signal
+// alerts' latest closes fell on, by trading date. Other alerts need none.
+func (s *alertService) sessionSignals(ctx context.Context, alerts []Alert, quotes map[string]*models.Quote) (map[string][]models.Signal, error) {
+	signals := make(map[string][]models.Signal)
+	for _, alert := range alerts {
+		quote, ok := quotes[alert.Symbol]
+		if alert.Condition != SignalFlagged || !ok {
+			continue
+		}
+		date := quote.Date()
+		if _, ok := signals[date]; ok {
+			continue
+		}
+
+		daily, err := s.signals.GetSignals(ctx, date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signals of %s: %w", date, err)
+		}
+		signals[date] = daily
+	}
+	return signals, nil
+}
+
+// matchingSignal returns the first of a session's signals that fires a signal
+// alert, or nil
+func matchingSignal(alert Alert, signals []models.Signal) *models.Signal {
+	for i := range signals {
+		if alert.MatchesSignal(signals[i]) {
+			return &signals[i]
+		}
+	}
+	return nil
+}
+
+// alertNotification describes a triggered alert and the close, and for signal
+// alerts the scanner signal, that fired it
+func alertNotification(templates *notify.Templates, alert Alert, quote models.Quote, signal *models.Signal) (notify.Notification, error) {
+	data := map[string]any{
+		"alert": alert,
+		"quote": quote,
+	}
+	if signal != nil {
+		data["signal"] = *signal
+	}
+
+	n, err := templates.Compose(notify.KindAlert, data)
+	if err != nil {
+		return notify.Notification{}, err
+	}
+	n.SentUTC = alert.TriggeredUTC
+	n.KeyID = alert.KeyID
+	return n, nil
+}
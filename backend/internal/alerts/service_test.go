@@ -0,0 +1,273 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/schedule"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/events/eventstest"
+	"profitify-backend/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingNotifier collects the notifications it is asked to deliver
+type recordingNotifier struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n notify.Notification) error {
+	r.sent = append(r.sent, n)
+	return r.err
+}
+
+// fixedSignals serves the scanner signals of each trading date
+type fixedSignals map[string][]models.Signal
+
+func (f fixedSignals) GetSignals(ctx context.Context, date string) ([]models.Signal, error) {
+	return f[date], nil
+}
+
+func TestService_CreateAlert(t *testing.T) {
+	tests := []struct {
+		name    string
+		alert   Alert
+		wantErr error
+	}{
+		{
+			name:  "normalizes symbol and condition",
+			alert: Alert{Symbol: " aapl", Condition: "PRICE_ABOVE", Threshold: 200},
+		},
+		{
+			name:    "rejects unknown conditions",
+			alert:   Alert{Symbol: "AAPL", Condition: "volume_above", Threshold: 1},
+			wantErr: ErrInvalidAlert,
+		},
+		{
+			name:    "rejects non-positive thresholds",
+			alert:   Alert{Symbol: "AAPL", Condition: ChangeAbove, Threshold: -5},
+			wantErr: ErrInvalidAlert,
+		},
+		{
+			name:  "accepts signal alerts without a threshold",
+			alert: Alert{Symbol: "AAPL", Condition: SignalFlagged, SignalType: " GAP_UP"},
+		},
+		{
+			name:    "rejects signal alerts without a known signal type",
+			alert:   Alert{Symbol: "AAPL", Condition: SignalFlagged, SignalType: "breakout"},
+			wantErr: ErrInvalidAlert,
+		},
+		{
+			name:    "rejects signal types on price alerts",
+			alert:   Alert{Symbol: "AAPL", Condition: PriceAbove, Threshold: 1, SignalType: models.SignalGapUp},
+			wantErr: ErrInvalidAlert,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			repo.On("PutAlert", mock.Anything, mock.Anything).Return(nil)
+			svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), nil, nil, &recordingNotifier{}, nil, nil, clock.System, zap.NewNop().Sugar())
+
+			alert, err := svc.CreateAlert(context.Background(), &tt.alert)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "PutAlert", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, alert.ID)
+			assert.Equal(t, "AAPL", alert.Symbol)
+			assert.Equal(t, strings.ToLower(string(tt.alert.Condition)), string(alert.Condition))
+			assert.Equal(t, StatusActive, alert.Status)
+		})
+	}
+}
+
+func TestService_Evaluate(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("ListAlerts", mock.Anything, StatusActive).Return([]Alert{
+		{ID: "above", Symbol: "AAPL", Condition: PriceAbove, Threshold: 105},
+		{ID: "below", Symbol: "AAPL", Condition: PriceBelow, Threshold: 100},
+		{ID: "move", Symbol: "AAPL", Condition: ChangeAbove, Threshold: 5},
+		{ID: "raced", Symbol: "MSFT", Condition: PriceAbove, Threshold: 1},
+		{ID: "new", Symbol: "NEW", Condition: PriceAbove, Threshold: 1},
+		{ID: "gap", Symbol: "AAPL", Condition: SignalFlagged, SignalType: models.SignalGapUp, Threshold: 2},
+		{ID: "volume", Symbol: "AAPL", Condition: SignalFlagged, SignalType: models.SignalUnusualVolume},
+		{ID: "small-gap", Symbol: "MSFT", Condition: SignalFlagged, SignalType: models.SignalGapDown, Threshold: 5},
+	}, nil)
+	repo.On("MarkTriggered", mock.Anything, "above", mock.Anything, float32(110)).Return(nil)
+	repo.On("MarkTriggered", mock.Anything, "move", mock.Anything, float32(110)).Return(nil)
+	repo.On("MarkTriggered", mock.Anything, "raced", mock.Anything, float32(400)).Return(errAlertNotActive)
+	repo.On("MarkTriggered", mock.Anything, "gap", mock.Anything, float32(110)).Return(nil)
+
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 2, Close: 110},
+		{Ticker: "AAPL", Timestamp: 1, Close: 100},
+	}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "MSFT", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "MSFT", Timestamp: 2, Close: 400},
+	}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "NEW", mock.Anything, int32(2)).Return([]models.DailySummary{}, nil)
+
+	log := zap.NewNop().Sugar()
+	notifier := &recordingNotifier{err: errors.New("webhook down")}
+	signals := fixedSignals{"1970-01-01": {
+		{Date: "1970-01-01", Ticker: "AAPL", Type: models.SignalGapUp, Value: 3.5},
+		{Date: "1970-01-01", Ticker: "MSFT", Type: models.SignalGapDown, Value: -4},
+	}}
+	publisher := &eventstest.RecordingPublisher{}
+	now := clock.NewFake(time.Date(2025, 3, 5, 21, 0, 0, 0, time.UTC))
+	fired, err := NewService(repo, repository.NewMemoryAPIKeyRepository(), service.NewDailySummaryService(summaries, log), signals, notifier, nil, publisher, now, log).Evaluate(context.Background())
+	require.NoError(t, err, "delivery failures do not fail the evaluation")
+
+	assert.Equal(t, 3, fired)
+	repo.AssertNotCalled(t, "MarkTriggered", mock.Anything, "below", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MarkTriggered", mock.Anything, "volume", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MarkTriggered", mock.Anything, "small-gap", mock.Anything, mock.Anything)
+	require.Len(t, notifier.sent, 3, "alerts claimed by another evaluator are not notified")
+	assert.Equal(t, notify.KindAlert, notifier.sent[0].Kind)
+	assert.Equal(t, "AAPL closed at 110.00, at or above 105", notifier.sent[0].Subject)
+	assert.Equal(t, "AAPL moved +10.00% to 110.00, beyond 5%", notifier.sent[1].Subject)
+	assert.Equal(t, "AAPL flagged gap_up (3.50) on 1970-01-01, closing at 110.00", notifier.sent[2].Subject)
+
+	require.Len(t, publisher.Events(), 3)
+	assert.Equal(t, models.EventAlertTriggered, publisher.Events()[0].Type)
+	triggered := publisher.Events()[2].Data.(models.AlertTriggeredEvent)
+	assert.Equal(t, "gap", triggered.AlertID)
+	assert.Equal(t, string(SignalFlagged), triggered.Condition)
+	assert.Equal(t, models.SignalGapUp, triggered.SignalType)
+	assert.Equal(t, 110.0, triggered.TriggeredClose)
+	assert.Equal(t, now.Now().Unix(), triggered.TriggeredUTC, "alerts trigger at the time of the evaluation")
+}
+
+func TestService_EvaluateSkipsDeletedAccounts(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	keys := repository.NewMemoryAPIKeyRepository()
+	key := &models.APIKey{ID: "key", Name: "deleted"}
+	require.NoError(t, keys.PutKey(ctx, key))
+	_, err := service.NewAccountService(keys, nil, time.Hour, log).Delete(service.WithAccount(ctx, key))
+	require.NoError(t, err)
+
+	repo := new(MockRepository)
+	repo.On("ListAlerts", mock.Anything, StatusActive).Return([]Alert{
+		{ID: "above", Symbol: "AAPL", Condition: PriceAbove, Threshold: 105, KeyID: "key"},
+	}, nil)
+
+	notifier := &recordingNotifier{}
+	publisher := &eventstest.RecordingPublisher{}
+	fired, err := NewService(repo, keys, nil, nil, notifier, nil, publisher, clock.System, log).Evaluate(ctx)
+	require.NoError(t, err)
+
+	assert.Zero(t, fired)
+	assert.Empty(t, notifier.sent, "deleted accounts are not notified while quarantined")
+	assert.Empty(t, publisher.Events())
+	repo.AssertNotCalled(t, "MarkTriggered", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_EvaluateScheduled(t *testing.T) {
+	// 08:00 in Toronto, the first Monday of daylight saving time
+	now := clock.NewFake(time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC))
+	sunday := time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC).Unix()
+	toronto := &schedule.Schedule{Timezone: "America/Toronto", LocalTime: "08:00"}
+
+	repo := new(MockRepository)
+	repo.On("ListAlerts", mock.Anything, StatusActive).Return([]Alert{
+		{ID: "due", Symbol: "AAPL", Condition: PriceAbove, Threshold: 105, Schedule: toronto, CreatedUTC: sunday},
+		{ID: "checked", Symbol: "AAPL", Condition: PriceAbove, Threshold: 105, Schedule: toronto, CreatedUTC: sunday, CheckedUTC: now.Now().Unix()},
+		{ID: "unmatched", Symbol: "AAPL", Condition: PriceBelow, Threshold: 100, Schedule: toronto, CreatedUTC: sunday},
+		{ID: "later", Symbol: "AAPL", Condition: PriceAbove, Threshold: 105, Schedule: &schedule.Schedule{Timezone: "Europe/London", LocalTime: "13:00"}, CreatedUTC: sunday, CheckedUTC: now.Now().Add(-time.Hour).Unix()},
+	}, nil)
+	repo.On("MarkTriggered", mock.Anything, "due", now.Now().Unix(), float32(110)).Return(nil)
+	repo.On("MarkChecked", mock.Anything, "unmatched", now.Now().Unix()).Return(nil)
+
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 2, Close: 110},
+		{Ticker: "AAPL", Timestamp: 1, Close: 100},
+	}, nil)
+
+	log := zap.NewNop().Sugar()
+	notifier := &recordingNotifier{}
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), service.NewDailySummaryService(summaries, log), fixedSignals{}, notifier, nil, nil, now, log)
+	fired, err := svc.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, fired)
+	require.Len(t, notifier.sent, 1)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "MarkTriggered", mock.Anything, "checked", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "MarkTriggered", mock.Anything, "later", mock.Anything, mock.Anything)
+
+	repo.On("GetAlert", mock.Anything, "checked").Return(&Alert{ID: "checked", Status: StatusActive, Schedule: toronto, CheckedUTC: now.Now().Unix()}, nil)
+	alert, err := svc.GetAlert(context.Background(), "checked")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 11, 12, 0, 0, 0, time.UTC).Unix(), alert.NextCheckUTC)
+}
+
+func TestService_ScopesAlertsToTheCallingKey(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetAlert", mock.Anything, "mine").Return(&Alert{ID: "mine", KeyID: "key"}, nil)
+	repo.On("GetAlert", mock.Anything, "theirs").Return(&Alert{ID: "theirs", KeyID: "other"}, nil)
+	repo.On("ListAlerts", mock.Anything, "").Return([]Alert{{ID: "mine", KeyID: "key"}, {ID: "theirs", KeyID: "other"}}, nil)
+	repo.On("DeleteAlert", mock.Anything, "mine").Return(nil)
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), nil, nil, &recordingNotifier{}, nil, nil, clock.System, zap.NewNop().Sugar())
+	ctx := accountContext(models.PlanPro, false)
+
+	alerts, err := svc.ListAlerts(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "mine", alerts[0].ID)
+
+	_, err = svc.GetAlert(ctx, "mine")
+	assert.NoError(t, err)
+	_, err = svc.GetAlert(ctx, "theirs")
+	assert.ErrorIs(t, err, ErrAlertNotFound)
+
+	assert.NoError(t, svc.DeleteAlert(ctx, "mine"))
+	assert.ErrorIs(t, svc.DeleteAlert(ctx, "theirs"), ErrAlertNotFound)
+	repo.AssertNotCalled(t, "DeleteAlert", mock.Anything, "theirs")
+}
+
+func accountContext(tier models.PlanTier, admin bool) context.Context {
+	return service.WithAccount(context.Background(), &models.APIKey{ID: "key", Name: "test", Tier: tier, Admin: admin})
+}
+
+func TestService_PlanLimits(t *testing.T) {
+	free := models.PlanFor(models.PlanFree)
+	owned := make([]Alert, free.Alerts)
+	for i := range owned {
+		owned[i] = Alert{ID: fmt.Sprint(i), KeyID: "key", Status: StatusActive}
+	}
+
+	repo := new(MockRepository)
+	repo.On("ListAlerts", mock.Anything, StatusActive).
+		Return(append(owned, Alert{ID: "other", KeyID: "other"}), nil)
+	repo.On("PutAlert", mock.Anything, mock.Anything).Return(nil)
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), nil, nil, &recordingNotifier{}, nil, nil, clock.System, zap.NewNop().Sugar())
+
+	alert := &Alert{Symbol: "AAPL", Condition: PriceAbove, Threshold: 200}
+
+	_, err := svc.CreateAlert(accountContext(models.PlanFree, false), alert)
+	assert.ErrorIs(t, err, service.ErrUpgradeRequired)
+
+	created, err := svc.CreateAlert(accountContext(models.PlanPro, false), alert)
+	require.NoError(t, err)
+	assert.Equal(t, "key", created.KeyID)
+}
@@ -0,0 +1,66 @@
+package analytics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultAnalyticsDays is the range reported when from is not given
+	defaultAnalyticsDays = 7
+	// defaultAnalyticsLimit is how many values are ranked when limit is not given
+	defaultAnalyticsLimit = 50
+)
+
+// GetAnalytics ranks endpoints, API keys or symbols by request count over an
+// inclusive range of UTC days, which defaults to the last week
+func (h *Handler) GetAnalytics(c *gin.Context) {
+	dimension := models.UsageDimension(c.DefaultQuery("dimension", string(models.UsageByEndpoint)))
+
+	to, err := api.ParseDateQuery(c, "to")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if to.IsZero() {
+		to = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	from, err := api.ParseDateQuery(c, "from")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, 1-defaultAnalyticsDays)
+	}
+
+	limit := defaultAnalyticsLimit
+	if value := c.Query("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			problem.Respond(c, problem.ValidationFailed, "limit must be a positive integer")
+			return
+		}
+	}
+
+	report, err := h.analyticsService.GetReport(c.Request.Context(), dimension, from, to, limit)
+	if err != nil {
+		if errors.Is(err, ErrInvalidAnalyticsQuery) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to get request analytics", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve request analytics")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,94 @@
+// Package analytics counts API requests per endpoint, API key and ticker
+// symbol per day, and serves the aggregated counts to admins.
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// finalFlushTimeout bounds the flush of the last counts on shutdown
+const finalFlushTimeout = 10 * time.Second
+
+type Handler struct {
+	analyticsService Service
+	// interval is how often RunFlusher persists the recorded counts
+	interval time.Duration
+	log      *zap.SugaredLogger
+}
+
+func NewHandler(analytics Service, interval time.Duration, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		analyticsService: analytics,
+		interval:         interval,
+		log:              log,
+	}
+}
+
+// Wire builds the analytics module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	return NewHandler(NewService(
+		NewRepository(deps.DB, deps.Config.AnalyticsTable),
+		deps.Log,
+	), deps.Config.AnalyticsFlushInterval, deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	admin.GET("/analytics", h.GetAnalytics)
+}
+
+// Recorder returns the recorder the router counts requests in
+func (h *Handler) Recorder() middleware.UsageRecorder {
+	return h.analyticsService
+}
+
+// RunFlusher persists the recorded counts every interval until ctx is done,
+// then flushes once more so that counts are not lost on shutdown. With a
+// non-positive interval counts are only persisted on shutdown.
+func (h *Handler) RunFlusher(ctx context.Context) error {
+	var tick <-chan time.Time
+	if h.interval > 0 {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalFlushTimeout)
+			defer cancel()
+			return h.analyticsService.Flush(flushCtx)
+		case <-tick:
+			if err := h.analyticsService.Flush(ctx); err != nil && ctx.Err() == nil {
+				h.log.Warnw("failed to flush request analytics, retrying at the next flush", "error", err)
+			}
+		}
+	}
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	doc.Add(http.MethodGet, "/api/v1/admin/analytics", &openapi.Operation{
+		Tags:        []string{"Admin"},
+		Summary:     "Rank endpoints, API keys or symbols by request count",
+		Description: "Counts are per UTC day over an inclusive range that defaults to the last week.",
+		Parameters: append(api.DateRangeParams(),
+			openapi.QueryParam("dimension", "What to rank (defaults to endpoint)", &openapi.Schema{
+				Type: "string",
+				Enum: []any{models.UsageByEndpoint, models.UsageByKey, models.UsageBySymbol},
+			}),
+			openapi.QueryParam("limit", "Maximum entries (defaults to 50)", &openapi.Schema{Type: "integer"}),
+		),
+		Responses: api.Responses(http.StatusOK, doc.Schema(models.UsageReport{}), http.StatusBadRequest),
+	})
+}
@@ -0,0 +1,118 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for request analytics operations
+type Repository interface {
+	IncrementUsage(ctx context.Context, counter models.UsageCounter) error
+	AddUsage(ctx context.Context, counter models.UsageCounter) (int64, error)
+	GetUsage(ctx context.Context, date, metricPrefix string) ([]models.UsageCounter, error)
+}
+
+// analyticsRepository implements Repository using DynamoDB
+type analyticsRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed analytics repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &analyticsRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// IncrementUsage atomically adds the counter's count to the stored counter of
+// its date and metric, creating it if needed
+func (r *analyticsRepository) IncrementUsage(ctx context.Context, counter models.UsageCounter) error {
+	_, err := r.AddUsage(ctx, counter)
+	return err
+}
+
+// AddUsage is IncrementUsage returning the stored count after the increment
+func (r *analyticsRepository) AddUsage(ctx context.Context, counter models.UsageCounter) (int64, error) {
+	update := expression.Add(expression.Name("count"), expression.Value(counter.Count))
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	result, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"date":   &types.AttributeValueMemberS{Value: counter.Date},
+			"metric": &types.AttributeValueMemberS{Value: counter.Metric},
+		},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              types.ReturnValueUpdatedNew,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment usage %s on %s: %w", counter.Metric, counter.Date, err)
+	}
+
+	var updated models.UsageCounter
+	if err := attributevalue.UnmarshalMap(result.Attributes, &updated); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal usage: %w", err)
+	}
+
+	return updated.Count, nil
+}
+
+// GetUsage retrieves the counters of a date whose metric starts with metricPrefix
+func (r *analyticsRepository) GetUsage(ctx context.Context, date, metricPrefix string) ([]models.UsageCounter, error) {
+	keyCond := expression.Key("date").Equal(expression.Value(date)).
+		And(expression.Key("metric").BeginsWith(metricPrefix))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var counters []models.UsageCounter
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query usage for %s: %w", date, err)
+		}
+
+		var batch []models.UsageCounter
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal usage: %w", err)
+		}
+
+		counters = append(counters, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return counters, nil
+}
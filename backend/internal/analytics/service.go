@@ -0,0 +1,182 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidAnalyticsQuery = errors.New("invalid analytics query")
+
+const (
+	// MaxAnalyticsDays bounds the range of an analytics report
+	MaxAnalyticsDays = 92
+	// maxPendingUsage bounds the counters held between flushes, so that
+	// requests for arbitrary symbols cannot grow memory without limit
+	maxPendingUsage = 10000
+)
+
+// usageKey identifies a pending counter
+type usageKey struct {
+	date   string
+	metric string
+}
+
+type Service interface {
+	// Record counts a request to route made with keyID, for symbol if the route
+	// has one. It only updates memory; Flush persists the counts.
+	Record(route, keyID, symbol string, at time.Time)
+	Flush(ctx context.Context) error
+	GetReport(ctx context.Context, dimension models.UsageDimension, from, to time.Time, limit int) (*models.UsageReport, error)
+}
+
+type analyticsService struct {
+	repo Repository
+	log  *zap.SugaredLogger
+
+	mu      sync.Mutex
+	pending map[usageKey]int64
+	dropped int64
+}
+
+func NewService(repo Repository, log *zap.SugaredLogger) Service {
+	return &analyticsService{
+		repo:    repo,
+		log:     log,
+		pending: make(map[usageKey]int64),
+	}
+}
+
+func (s *analyticsService) Record(route, keyID, symbol string, at time.Time) {
+	date := at.UTC().Format(models.DateLayout)
+	if keyID == "" {
+		keyID = models.UsageAnonymousKey
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(usageKey{date, models.UsageMetric(models.UsageByEndpoint, route)}, 1)
+	s.add(usageKey{date, models.UsageMetric(models.UsageByKey, keyID)}, 1)
+	if symbol != "" {
+		s.add(usageKey{date, models.UsageMetric(models.UsageBySymbol, symbol)}, 1)
+	}
+}
+
+// add increments a pending counter; s.mu must be held
+func (s *analyticsService) add(key usageKey, n int64) {
+	if _, ok := s.pending[key]; !ok && len(s.pending) >= maxPendingUsage {
+		s.dropped += n
+		return
+	}
+	s.pending[key] += n
+}
+
+// Flush persists the counts recorded since the last flush. Counts that could
+// not be written are kept for the next flush.
+func (s *analyticsService) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending, dropped := s.pending, s.dropped
+	s.pending, s.dropped = make(map[usageKey]int64, len(pending)), 0
+	s.mu.Unlock()
+
+	if dropped > 0 {
+		logger.FromContext(ctx, s.log).Warnw("dropped request analytics over the pending limit", "requests", dropped, "limit", maxPendingUsage)
+	}
+
+	for key, count := range pending {
+		err := s.repo.IncrementUsage(ctx, models.UsageCounter{Date: key.date, Metric: key.metric, Count: count})
+		if err != nil {
+			s.mu.Lock()
+			for k, n := range pending {
+				s.add(k, n)
+			}
+			s.mu.Unlock()
+			return fmt.Errorf("failed to flush request analytics: %w", err)
+		}
+		delete(pending, key)
+	}
+
+	return nil
+}
+
+// GetReport ranks the values of a dimension by request count over the UTC
+// days [from, to], returning at most limit values when limit is positive
+func (s *analyticsService) GetReport(ctx context.Context, dimension models.UsageDimension, from, to time.Time, limit int) (*models.UsageReport, error) {
+	if !dimension.Valid() {
+		return nil, fmt.Errorf("%w: dimension must be %s, %s or %s", ErrInvalidAnalyticsQuery,
+			models.UsageByEndpoint, models.UsageByKey, models.UsageBySymbol)
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidAnalyticsQuery)
+	}
+	if to.Sub(from) >= MaxAnalyticsDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: range spans more than %d days", ErrInvalidAnalyticsQuery, MaxAnalyticsDays)
+	}
+
+	report := &models.UsageReport{
+		Dimension: dimension,
+		From:      from.Format(models.DateLayout),
+		To:        to.Format(models.DateLayout),
+		Totals:    make([]models.UsageTotal, 0),
+		Daily:     make([]models.DailyUsage, 0),
+	}
+	totals := make(map[string]int64)
+
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(models.DateLayout)
+
+		// Every request is counted under exactly one endpoint, so the
+		// endpoint counters also give the day's total
+		endpoints, err := s.usage(ctx, date, models.UsageByEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		var requests int64
+		for _, counter := range endpoints {
+			requests += counter.Count
+		}
+		report.Daily = append(report.Daily, models.DailyUsage{Date: date, Count: requests})
+
+		counters := endpoints
+		if dimension != models.UsageByEndpoint {
+			if counters, err = s.usage(ctx, date, dimension); err != nil {
+				return nil, err
+			}
+		}
+		for _, counter := range counters {
+			totals[counter.Value()] += counter.Count
+		}
+	}
+
+	for value, count := range totals {
+		report.Totals = append(report.Totals, models.UsageTotal{Value: value, Count: count})
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		if report.Totals[i].Count != report.Totals[j].Count {
+			return report.Totals[i].Count > report.Totals[j].Count
+		}
+		return report.Totals[i].Value < report.Totals[j].Value
+	})
+	if limit > 0 && len(report.Totals) > limit {
+		report.Totals = report.Totals[:limit]
+	}
+
+	return report, nil
+}
+
+func (s *analyticsService) usage(ctx context.Context, date string, dimension models.UsageDimension) ([]models.UsageCounter, error) {
+	counters, err := s.repo.GetUsage(ctx, date, models.UsageMetricPrefix(dimension))
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get request analytics", "date", date, "dimension", dimension, "error", err)
+		return nil, fmt.Errorf("failed to get request analytics: %w", err)
+	}
+	return counters, nil
+}
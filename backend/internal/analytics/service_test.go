@@ -0,0 +1,106 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockRepository mocks the Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) IncrementUsage(ctx context.Context, counter models.UsageCounter) error {
+	return m.Called(ctx, counter).Error(0)
+}
+
+func (m *MockRepository) AddUsage(ctx context.Context, counter models.UsageCounter) (int64, error) {
+	args := m.Called(ctx, counter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetUsage(ctx context.Context, date, metricPrefix string) ([]models.UsageCounter, error) {
+	args := m.Called(ctx, date, metricPrefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.UsageCounter), args.Error(1)
+}
+
+func TestService_Flush(t *testing.T) {
+	at := time.Date(2025, 3, 7, 23, 0, 0, 0, time.FixedZone("EST", -5*3600))
+
+	repo := new(MockRepository)
+	svc := NewService(repo, zap.NewNop().Sugar())
+	svc.Record("GET /api/tickers/:symbol", "k1", "AAPL", at)
+	svc.Record("GET /api/tickers/:symbol", "", "AAPL", at)
+	svc.Record("GET /api/tickers", "k1", "", at)
+
+	t.Run("keeps counts that failed to flush", func(t *testing.T) {
+		repo.On("IncrementUsage", mock.Anything, mock.Anything).Return(errors.New("throttled")).Once()
+		assert.ErrorContains(t, svc.Flush(context.Background()), "throttled")
+	})
+
+	var flushed []models.UsageCounter
+	repo.On("IncrementUsage", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		flushed = append(flushed, args.Get(1).(models.UsageCounter))
+	}).Return(nil)
+	require.NoError(t, svc.Flush(context.Background()))
+
+	assert.ElementsMatch(t, []models.UsageCounter{
+		{Date: "2025-03-08", Metric: "endpoint#GET /api/tickers/:symbol", Count: 2},
+		{Date: "2025-03-08", Metric: "endpoint#GET /api/tickers", Count: 1},
+		{Date: "2025-03-08", Metric: "key#k1", Count: 2},
+		{Date: "2025-03-08", Metric: "key#anonymous", Count: 1},
+		{Date: "2025-03-08", Metric: "symbol#AAPL", Count: 2},
+	}, flushed, "days are UTC")
+
+	flushed = nil
+	require.NoError(t, svc.Flush(context.Background()))
+	assert.Empty(t, flushed)
+}
+
+func TestService_GetReport(t *testing.T) {
+	from := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+
+	repo := new(MockRepository)
+	repo.On("GetUsage", mock.Anything, "2025-03-07", "endpoint#").Return([]models.UsageCounter{
+		{Metric: "endpoint#GET /api/tickers", Count: 3},
+		{Metric: "endpoint#GET /api/tickers/:symbol", Count: 5},
+	}, nil)
+	repo.On("GetUsage", mock.Anything, "2025-03-08", "endpoint#").Return([]models.UsageCounter{
+		{Metric: "endpoint#GET /api/tickers/:symbol", Count: 4},
+	}, nil)
+	repo.On("GetUsage", mock.Anything, "2025-03-07", "symbol#").Return([]models.UsageCounter{
+		{Metric: "symbol#AAPL", Count: 3},
+		{Metric: "symbol#MSFT", Count: 2},
+	}, nil)
+	repo.On("GetUsage", mock.Anything, "2025-03-08", "symbol#").Return([]models.UsageCounter{
+		{Metric: "symbol#MSFT", Count: 4},
+	}, nil)
+	svc := NewService(repo, zap.NewNop().Sugar())
+
+	report, err := svc.GetReport(context.Background(), models.UsageBySymbol, from, to, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []models.UsageTotal{{Value: "MSFT", Count: 6}, {Value: "AAPL", Count: 3}}, report.Totals)
+	assert.Equal(t, []models.DailyUsage{{Date: "2025-03-07", Count: 8}, {Date: "2025-03-08", Count: 4}}, report.Daily)
+
+	report, err = svc.GetReport(context.Background(), models.UsageByEndpoint, from, to, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []models.UsageTotal{{Value: "GET /api/tickers/:symbol", Count: 9}}, report.Totals)
+
+	_, err = svc.GetReport(context.Background(), "feature", from, to, 0)
+	assert.ErrorIs(t, err, ErrInvalidAnalyticsQuery)
+	_, err = svc.GetReport(context.Background(), models.UsageByKey, from.AddDate(0, 0, -MaxAnalyticsDays), to, 0)
+	assert.ErrorIs(t, err, ErrInvalidAnalyticsQuery)
+}
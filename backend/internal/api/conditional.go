@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotModified sets Last-Modified to lastModified and reports whether the
+// request's If-Modified-Since makes the response unnecessary, in which case
+// it answers 304. HTTP dates have second precision, so lastModified is
+// truncated to the second; an unparsable If-Modified-Since is ignored.
+func NotModified(c *gin.Context, lastModified time.Time) bool {
+	lastModified = lastModified.UTC().Truncate(time.Second)
+	c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	since, err := http.ParseTime(c.GetHeader("If-Modified-Since"))
+	if err != nil || lastModified.After(since) {
+		return false
+	}
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// NotModifiedETag sets ETag to etag and reports whether the request's
+// If-None-Match names it, in which case it answers 304. Tags are compared
+// weakly, as RFC 9110 requires for If-None-Match.
+func NotModifiedETag(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return true
+		}
+	}
+	return false
+}
+
+// IfNoneMatchParam documents the If-None-Match header honored by
+// NotModifiedETag
+func IfNoneMatchParam() openapi.Parameter {
+	return openapi.Parameter{
+		Name:        "If-None-Match",
+		In:          "header",
+		Description: "ETag of a previous response; answered with 304 when the list is unchanged",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+}
+
+// IfModifiedSinceParam documents the If-Modified-Since header honored by
+// NotModified
+func IfModifiedSinceParam() openapi.Parameter {
+	return openapi.Parameter{
+		Name:        "If-Modified-Since",
+		In:          "header",
+		Description: "Last-Modified of a previous response; answered with 304 when nothing changed since",
+		Schema:      &openapi.Schema{Type: "string"},
+	}
+}
+
+// WithNotModified documents the 304 answered by NotModified
+func WithNotModified(responses map[string]*openapi.Response) map[string]*openapi.Response {
+	responses[strconv.Itoa(http.StatusNotModified)] = &openapi.Response{
+		Description: http.StatusText(http.StatusNotModified),
+	}
+	return responses
+}
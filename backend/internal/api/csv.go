@@ -0,0 +1,131 @@
+package api
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CSVContentType is the media type of CSV responses
+const CSVContentType = "text/csv"
+
+// WantsCSV reports whether the request asks for CSV, with ?format=csv or an
+// Accept header preferring text/csv over JSON. An explicit format wins, and
+// JSON wins ties between equal quality values unless text/csv comes first.
+func WantsCSV(c *gin.Context) bool {
+	if format := c.Query("format"); format != "" {
+		return strings.EqualFold(format, "csv")
+	}
+
+	csvQ, csvAt := 0.0, -1
+	jsonQ, jsonAt := 0.0, -1
+	for i, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, q := parseAcceptPart(part)
+		switch mediaType {
+		case CSVContentType:
+			if q > csvQ {
+				csvQ, csvAt = q, i
+			}
+		case gin.MIMEJSON, "application/*", "*/*":
+			if q > jsonQ {
+				jsonQ, jsonAt = q, i
+			}
+		}
+	}
+	if csvQ == 0 {
+		return false
+	}
+	return csvQ > jsonQ || (csvQ == jsonQ && csvAt < jsonAt)
+}
+
+// parseAcceptPart splits one media range of an Accept header into its lower
+// cased type and quality value, which defaults to 1
+func parseAcceptPart(part string) (string, float64) {
+	params := strings.Split(part, ";")
+	q := 1.0
+	for _, param := range params[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(params[0])), q
+}
+
+// CSVWriter streams a CSV attachment to the client. The response, starting
+// with the header row, is only sent on the first Write or on Close, so errors
+// found before any row is ready can still be answered with JSON. Rows reach
+// the client as the CSV writer's buffer fills rather than rendered up front.
+type CSVWriter struct {
+	c        *gin.Context
+	filename string
+	header   []string
+	w        *csv.Writer
+}
+
+// NewCSVWriter returns a writer of the CSV attachment named filename with the
+// given header row
+func NewCSVWriter(c *gin.Context, filename string, header []string) *CSVWriter {
+	return &CSVWriter{c: c, filename: filename, header: header}
+}
+
+// Started reports whether the response has been sent, after which failures
+// cannot be reported to the client
+func (w *CSVWriter) Started() bool {
+	return w.w != nil
+}
+
+// Write sends record as the next row, starting the response if needed
+func (w *CSVWriter) Write(record []string) error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	return w.w.Write(record)
+}
+
+// Close flushes the buffered rows, sending just the header row when nothing
+// was written. Errors are only for logging since the status is already sent.
+func (w *CSVWriter) Close() error {
+	if err := w.start(); err != nil {
+		return err
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *CSVWriter) start() error {
+	if w.w != nil {
+		return nil
+	}
+
+	w.c.Header("Content-Type", CSVContentType+"; charset=utf-8")
+	w.c.Header("Content-Disposition", `attachment; filename="`+w.filename+`"`)
+	w.c.Status(http.StatusOK)
+
+	w.w = csv.NewWriter(w.c.Writer)
+	return w.w.Write(w.header)
+}
+
+// FormatParam documents the format query parameter read by WantsCSV
+func FormatParam() openapi.Parameter {
+	return openapi.QueryParam("format", "Response format (defaults to json, or csv when Accept prefers text/csv)",
+		&openapi.Schema{Type: "string", Enum: []any{"json", "csv"}})
+}
+
+// WithCSV documents the CSV alternative of the status response in responses
+func WithCSV(responses map[string]*openapi.Response, status int, description string) map[string]*openapi.Response {
+	response := responses[strconv.Itoa(status)]
+	response.Content[CSVContentType] = openapi.MediaType{
+		Schema: &openapi.Schema{Type: "string", Description: description},
+	}
+	return responses
+}
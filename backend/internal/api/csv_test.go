@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWantsCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+		want   bool
+	}{
+		{name: "no preference", want: false},
+		{name: "format csv", query: "format=csv", want: true},
+		{name: "format is case insensitive", query: "format=CSV", want: true},
+		{name: "format json", query: "format=json", want: false},
+		{name: "accept csv", accept: "text/csv", want: true},
+		{name: "accept prefers json", accept: "application/json, text/csv;q=0.5", want: false},
+		{name: "accept prefers csv", accept: "application/json;q=0.5, text/csv", want: true},
+		{name: "accept anything", accept: "*/*", want: false},
+		{name: "accept csv first at equal quality", accept: "text/csv, application/json", want: true},
+		{name: "accept csv refused", accept: "text/csv;q=0", want: false},
+		{name: "format wins over accept", query: "format=json", accept: "text/csv", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", "/api/tickers?"+tt.query, nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+
+			assert.Equal(t, tt.want, WantsCSV(c))
+		})
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("sends the header row when nothing is written", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		csv := NewCSVWriter(c, "empty.csv", []string{"a", "b"})
+		assert.False(t, csv.Started())
+		assert.NoError(t, csv.Close())
+
+		assert.True(t, csv.Started())
+		assert.Equal(t, "a,b\n", w.Body.String())
+		assert.Equal(t, `attachment; filename="empty.csv"`, w.Header().Get("Content-Disposition"))
+	})
+
+	t.Run("starts the response on the first row", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		csv := NewCSVWriter(c, "rows.csv", []string{"a", "b"})
+		assert.NoError(t, csv.Write([]string{"1", "x,y"}))
+		assert.True(t, csv.Started())
+		assert.NoError(t, csv.Close())
+
+		assert.Equal(t, "a,b\n1,\"x,y\"\n", w.Body.String())
+	})
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/openapi"
+)
+
+// DateSchema documents a YYYY-MM-DD date
+var DateSchema = &openapi.Schema{Type: "string", Format: "date"}
+
+// Responses documents an operation answering status with body, or with no
+// content when body is nil. Each of errors, and 500, answers with problem
+// details as problem.Respond writes them, listing the codes of that status.
+func Responses(status int, body *openapi.Schema, errors ...int) map[string]*openapi.Response {
+	responses := make(map[string]*openapi.Response, len(errors)+2)
+	if body == nil {
+		responses[strconv.Itoa(status)] = &openapi.Response{Description: http.StatusText(status)}
+	} else {
+		responses[strconv.Itoa(status)] = openapi.JSON(http.StatusText(status), body)
+	}
+
+	for _, code := range append(errors, http.StatusInternalServerError) {
+		responses[strconv.Itoa(code)] = &openapi.Response{
+			Description: http.StatusText(code),
+			Content:     map[string]openapi.MediaType{problem.ContentType: {Schema: problemSchema(code)}},
+		}
+	}
+	return responses
+}
+
+// problemSchema documents the problem details answered with status
+func problemSchema(status int) *openapi.Schema {
+	var codes []any
+	for _, code := range problem.Codes(status) {
+		codes = append(codes, code)
+	}
+	return &openapi.Schema{
+		Type: "object",
+		Properties: map[string]*openapi.Schema{
+			"type":      {Type: "string"},
+			"title":     {Type: "string"},
+			"status":    {Type: "integer"},
+			"detail":    {Type: "string"},
+			"instance":  {Type: "string"},
+			"code":      {Type: "string", Enum: codes},
+			"requestId": {Type: "string", Description: "X-Request-ID of the request, to find its log lines"},
+		},
+		Required: []string{"code", "status", "title", "type"},
+	}
+}
+
+// List documents a list response holding items of v's type under key, along
+// with their count
+func List(doc *openapi.Document, key string, v any) *openapi.Schema {
+	return openapi.Object(map[string]*openapi.Schema{
+		key:     {Type: "array", Items: doc.Schema(v)},
+		"count": {Type: "integer"},
+	})
+}
+
+// DateRangeParams documents the optional from/to dates read by ParseDateRange
+func DateRangeParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		openapi.QueryParam("from", "First day of the range, YYYY-MM-DD", DateSchema),
+		openapi.QueryParam("to", "Last day of the range, YYYY-MM-DD", DateSchema),
+	}
+}
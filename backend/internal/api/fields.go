@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fields is a sparse fieldset: the JSON names of the fields of a list's items
+// a client asked for with ?fields=, in the order asked. Nil selects every
+// field.
+type Fields []string
+
+// ParseFields reads ?fields=, comma-separated JSON names of the fields of a
+// list's items, rejecting names not in known. Without the parameter every
+// field is selected.
+func ParseFields(c *gin.Context, known []string) (Fields, error) {
+	value, ok := c.GetQuery("fields")
+	if !ok {
+		return nil, nil
+	}
+
+	fields := Fields{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(fields, name) {
+			continue
+		}
+		if !slices.Contains(known, name) {
+			return nil, fmt.Errorf("unknown field %q, expected some of %s", name, strings.Join(known, ", "))
+		}
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields must name at least one of %s", strings.Join(known, ", "))
+	}
+	return fields, nil
+}
+
+// All reports whether f selects every field
+func (f Fields) All() bool {
+	return f == nil
+}
+
+// Has reports whether f selects the field name
+func (f Fields) Has(name string) bool {
+	return f == nil || slices.Contains(f, name)
+}
+
+// String returns f as ?fields= takes it, empty when every field is selected
+func (f Fields) String() string {
+	return strings.Join(f, ",")
+}
+
+// Select returns items with only the fields f selects, as JSON objects, or
+// items as they are when f selects every field. A selected field an item
+// leaves out when empty stays out.
+func Select[T any](f Fields, items []T) (any, error) {
+	if f.All() {
+		return items, nil
+	}
+
+	selected := make([]map[string]json.RawMessage, len(items))
+	for i := range items {
+		encoded, err := json.Marshal(items[i])
+		if err != nil {
+			return nil, err
+		}
+		var all map[string]json.RawMessage
+		if err := json.Unmarshal(encoded, &all); err != nil {
+			return nil, err
+		}
+		selected[i] = make(map[string]json.RawMessage, len(f))
+		for _, name := range f {
+			if value, ok := all[name]; ok {
+				selected[i][name] = value
+			}
+		}
+	}
+	return selected, nil
+}
+
+// JSONFields returns the JSON names of the fields of v's struct type,
+// including those of embedded structs, in declaration order
+func JSONFields(v any) []string {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return jsonFields(t)
+}
+
+func jsonFields(t reflect.Type) []string {
+	var names []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			// encoding/json promotes the fields of untagged embedded structs
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				names = append(names, jsonFields(embedded)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// FieldsParam documents the ?fields= read by ParseFields, choosing from known
+func FieldsParam(known []string) openapi.Parameter {
+	return openapi.QueryParam("fields",
+		"Comma-separated fields of the listed items to answer with, in JSON; defaults to every field. One of: "+strings.Join(known, ", "),
+		&openapi.Schema{Type: "string"})
+}
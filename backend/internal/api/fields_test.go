@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldsBase struct {
+	ID string `json:"id"`
+}
+
+type fieldsItem struct {
+	fieldsBase
+	Name    string  `json:"name"`
+	Price   float64 `json:"price,omitempty"`
+	Secret  string  `json:"-"`
+	Untyped int
+	hidden  int
+}
+
+func fieldsContext(query string) *gin.Context {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/items"+query, nil)
+	return c
+}
+
+func TestJSONFields(t *testing.T) {
+	assert.Equal(t, []string{"id", "name", "price", "Untyped"}, JSONFields(&fieldsItem{hidden: 1}))
+}
+
+func TestParseFields(t *testing.T) {
+	known := JSONFields(fieldsItem{})
+
+	fields, err := ParseFields(fieldsContext(""), known)
+	require.NoError(t, err)
+	assert.True(t, fields.All())
+	assert.True(t, fields.Has("price"))
+
+	fields, err = ParseFields(fieldsContext("?fields=name,%20id,,name"), known)
+	require.NoError(t, err)
+	assert.Equal(t, Fields{"name", "id"}, fields, "blank and repeated names are dropped")
+	assert.False(t, fields.Has("price"))
+	assert.Equal(t, "name,id", fields.String())
+
+	_, err = ParseFields(fieldsContext("?fields=name,secret"), known)
+	assert.EqualError(t, err, `unknown field "secret", expected some of id, name, price, Untyped`)
+
+	_, err = ParseFields(fieldsContext("?fields="), known)
+	assert.ErrorContains(t, err, "at least one")
+}
+
+func TestSelect(t *testing.T) {
+	items := []fieldsItem{{fieldsBase: fieldsBase{ID: "a"}, Name: "A", Price: 2}, {fieldsBase: fieldsBase{ID: "b"}, Name: "B"}}
+
+	all, err := Select(nil, items)
+	require.NoError(t, err)
+	assert.Equal(t, items, all)
+
+	selected, err := Select(Fields{"price", "id"}, items)
+	require.NoError(t, err)
+	encoded, err := json.Marshal(selected)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":"a","price":2},{"id":"b"}]`, string(encoded), "empty fields left out stay out")
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/config"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseLimits bound the JSON bodies of list endpoints, which otherwise
+// grow with the data and time out behind the load balancer. Zero disables a
+// limit.
+type ResponseLimits struct {
+	MaxItems int
+	MaxBytes int
+	// Reject answers 413 instead of a truncated page
+	Reject bool
+}
+
+// LimitsFromConfig returns the configured response limits
+func LimitsFromConfig(cfg *config.Config) ResponseLimits {
+	return ResponseLimits{
+		MaxItems: cfg.ResponseMaxItems,
+		MaxBytes: cfg.ResponseMaxBytes,
+		Reject:   cfg.ResponseOversize == "reject",
+	}
+}
+
+// fit returns how many leading items stay within the limits, measuring
+// items by their JSON encoding. At least one item fits, so pages always
+// make progress.
+func fit[T any](l ResponseLimits, items []T) int {
+	n := len(items)
+	if l.MaxItems > 0 && n > l.MaxItems {
+		n = l.MaxItems
+	}
+	if l.MaxBytes <= 0 {
+		return n
+	}
+
+	size := 0
+	for i := 0; i < n; i++ {
+		encoded, err := json.Marshal(items[i])
+		if err != nil {
+			return n
+		}
+		// Separating commas count too
+		size += len(encoded) + 1
+		if size > l.MaxBytes {
+			return max(i, 1)
+		}
+	}
+	return n
+}
+
+// LimitPage cuts items to the response limits. It returns the items to
+// answer with and, when they were cut, the cursor of the first item left out,
+// which the client passes back as ?cursor= to continue. Cut pages carry a
+// Warning header; with Reject, an oversized response is answered with 413
+// instead and ok is false.
+func LimitPage[T any](c *gin.Context, limits ResponseLimits, items []T, cursor func(T) string) (page []T, next string, ok bool) {
+	n := fit(limits, items)
+	if n == len(items) {
+		return items, "", true
+	}
+
+	if limits.Reject {
+		problem.Respond(c, problem.ResponseTooLarge,
+			fmt.Sprintf("The response would hold %d items, more than fit in the size limits; narrow the query", len(items)))
+		return nil, "", false
+	}
+	c.Header("Warning", fmt.Sprintf(`199 - "Response truncated to %d of %d items; continue with the nextCursor"`, n, len(items)))
+	return items[:n], cursor(items[n]), true
+}
+
+// CursorParam documents the ?cursor= continuing a page cut by LimitPage
+func CursorParam() openapi.Parameter {
+	return openapi.QueryParam("cursor", "nextCursor of the previous page, to continue a response cut to the size limits", nil)
+}
+
+// Paged documents the nextCursor of a list object cut by LimitPage. Add
+// 413 to the operation's errors for when oversized responses are rejected.
+func Paged(list *openapi.Schema) *openapi.Schema {
+	list.Properties["nextCursor"] = &openapi.Schema{
+		Type:        "string",
+		Description: "Set when the list was cut to the size limits; pass as cursor to continue",
+	}
+	return list
+}
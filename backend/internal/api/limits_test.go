@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimitPage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	items := []string{"AAPL", "GOOGL", "MSFT", "NVDA"}
+	cursor := func(s string) string { return strings.ToLower(s) }
+
+	tests := []struct {
+		name    string
+		limits  ResponseLimits
+		want    []string
+		next    string
+		status  int
+		warning bool
+	}{
+		{name: "no limits", want: items, status: http.StatusOK},
+		{name: "within the limits", limits: ResponseLimits{MaxItems: 4, MaxBytes: 100}, want: items, status: http.StatusOK},
+		{name: "too many items", limits: ResponseLimits{MaxItems: 3}, want: items[:3], next: "nvda", status: http.StatusOK, warning: true},
+		// "AAPL", and "GOOGL", are 7 and 8 bytes
+		{name: "too many bytes", limits: ResponseLimits{MaxBytes: 16}, want: items[:2], next: "msft", status: http.StatusOK, warning: true},
+		{name: "an item over the bytes", limits: ResponseLimits{MaxBytes: 2}, want: items[:1], next: "googl", status: http.StatusOK, warning: true},
+		{name: "rejected", limits: ResponseLimits{MaxItems: 3, Reject: true}, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/tickers", nil)
+
+			page, next, ok := LimitPage(c, tt.limits, items, cursor)
+
+			assert.Equal(t, tt.status == http.StatusOK, ok)
+			assert.Equal(t, tt.want, page)
+			assert.Equal(t, tt.next, next)
+			assert.Equal(t, tt.warning, w.Header().Get("Warning") != "")
+			if !ok {
+				assert.Equal(t, tt.status, w.Code)
+				assert.Contains(t, w.Body.String(), "RESPONSE_TOO_LARGE")
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package api holds request parsing and logging helpers shared by the feature
+// modules' HTTP handlers.
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"profitify-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DateLayout is the format accepted for date query parameters
+const DateLayout = "2006-01-02"
+
+// ParseDateQuery parses an optional YYYY-MM-DD query parameter into a UTC time.
+// A missing parameter yields the zero time.
+func ParseDateQuery(c *gin.Context, key string) (time.Time, error) {
+	return parseDate(key, c.Query(key))
+}
+
+// ParseBoolQuery parses an optional true/false query parameter. A missing
+// parameter yields false.
+func ParseBoolQuery(c *gin.Context, key string) (bool, error) {
+	value := c.Query(key)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s %q, expected true or false", key, value)
+	}
+	return b, nil
+}
+
+func parseDate(key, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s date %q, expected YYYY-MM-DD", key, value)
+	}
+	return t, nil
+}
+
+// ParseDateRange parses the optional from/to query parameters into unix
+// timestamps. The range is inclusive, so to is moved to the end of its day.
+// Missing bounds are returned as zero.
+func ParseDateRange(c *gin.Context) (from, to int64, err error) {
+	return DateRange(c.Query("from"), c.Query("to"))
+}
+
+// DateRange parses optional YYYY-MM-DD from and to dates as ParseDateRange
+// does, for callers outside of HTTP requests
+func DateRange(fromValue, toValue string) (from, to int64, err error) {
+	fromDate, err := parseDate("from", fromValue)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	toDate, err := parseDate("to", toValue)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if !fromDate.IsZero() {
+		from = fromDate.Unix()
+	}
+	if !toDate.IsZero() {
+		to = toDate.AddDate(0, 0, 1).Unix() - 1
+	}
+
+	if from != 0 && to != 0 && from > to {
+		return 0, 0, fmt.Errorf("from must not be after to")
+	}
+
+	return from, to, nil
+}
+
+// NormalizeSymbol canonicalizes a ticker symbol taken from the request path
+func NormalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// Logger returns the logger of the current request, which carries its request
+// ID, or fallback outside of a request
+func Logger(c *gin.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	return logger.FromContext(c.Request.Context(), fallback)
+}
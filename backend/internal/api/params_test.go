@@ -0,0 +1,77 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateRange(t *testing.T) {
+	from, to, err := DateRange("2024-01-02", "2024-01-02")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC).Unix(), from)
+	assert.Equal(t, time.Date(2024, 1, 2, 23, 59, 59, 0, time.UTC).Unix(), to, "to is inclusive")
+
+	_, _, err = DateRange("2024-01-03", "2024-01-02")
+	assert.ErrorContains(t, err, "from must not be after to")
+	_, _, err = DateRange("2024-1-2", "")
+	assert.ErrorContains(t, err, `invalid from date "2024-1-2"`)
+}
+
+// FuzzDateRange checks that no from/to query values panic the parser, and that
+// accepted ones are whole days in order
+func FuzzDateRange(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"", ""},
+		{"2024-01-02", "2024-12-31"},
+		{"2024-12-31", "2024-01-02"},
+		{"0000-01-01", "9999-12-31"},
+		{"2024-02-30", ""},
+		{"2024-01-02T00:00:00Z", "-1"},
+		{"1969-12-31", "1970-01-01"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, fromValue, toValue string) {
+		from, to, err := DateRange(fromValue, toValue)
+		if err != nil {
+			assert.Zero(t, from)
+			assert.Zero(t, to)
+			return
+		}
+		if fromValue == "" {
+			assert.Zero(t, from)
+		} else if from != 0 {
+			assert.Zero(t, from%86400, "from %d is not the start of a day", from)
+		}
+		if toValue == "" {
+			assert.Zero(t, to)
+		} else if to != 0 {
+			assert.Zero(t, (to+1)%86400, "to %d is not the end of a day", to)
+		}
+		if from != 0 && to != 0 {
+			assert.LessOrEqual(t, from, to)
+		}
+	})
+}
+
+// FuzzNormalizeSymbol checks that normalized symbols are trimmed, upper case
+// and stay the same when normalized again, so cache and storage keys built from
+// them agree
+func FuzzNormalizeSymbol(f *testing.F) {
+	for _, seed := range []string{"aapl", " BRK.B ", "X:BTCUSD", "\tmsft\n", "ǅ", "ß", "\xff", ""} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, symbol string) {
+		normalized := NormalizeSymbol(symbol)
+		assert.Equal(t, normalized, NormalizeSymbol(normalized))
+		assert.Equal(t, strings.TrimFunc(normalized, unicode.IsSpace), normalized)
+		assert.Equal(t, strings.ToUpper(normalized), normalized)
+	})
+}
@@ -0,0 +1,168 @@
+// Package app holds the process-wide dependencies that feature modules are
+// wired from.
+package app
+
+import (
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/cache"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/config"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/lock"
+	"profitify-backend/pkg/metrics"
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/push"
+	"profitify-backend/pkg/tasks"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"go.uber.org/zap"
+)
+
+// Deps are passed to every module's Wire function. Modules build the
+// repositories and services they own from them; the accessors below construct
+// the ones several modules read.
+type Deps struct {
+	Config *config.Config
+	DB     *dynamodb.Client
+	Log    *zap.SugaredLogger
+	// Cache fronts read-mostly repositories; nil disables caching
+	Cache cache.Cache
+	// Metrics holds the Prometheus collectors served on /metrics
+	Metrics *metrics.Metrics
+	// Push holds the senders of the configured push platforms; empty disables push
+	Push push.Senders
+	// Templates render notifications per kind and channel; nil renders them
+	// with the templates shipped in pkg/notify
+	Templates *notify.Templates
+	// Memory holds the in-memory repositories of the memory storage backend;
+	// nil keeps all data in DynamoDB
+	Memory *repository.MemoryStore
+	// Locker holds locks across replicas through the locks table; nil with the
+	// memory storage backend, whose single process needs none
+	Locker *lock.Locker
+	// Tasks runs background work for the lifetime of the server
+	Tasks *tasks.Manager
+	// Events publishes domain events to the configured event bus; nil
+	// publishes none
+	Events events.Publisher
+	// Clock tells the time to services and schedulers; clock.System outside
+	// of tests
+	Clock clock.Clock
+}
+
+// TickerRepository reads tickers, through the active index unless disabled,
+// and through the cache when one is configured. Its writes are recorded in
+// the TickerChangeRepository. The memory storage backend serves them from
+// process memory, uncached.
+func (d Deps) TickerRepository() repository.TickerRepository {
+	if d.Memory != nil {
+		return d.Memory.Tickers
+	}
+	indexes := repository.TickerIndexes{
+		Active:       d.Config.TickersActiveIndex,
+		Exchange:     d.Config.TickersExchangeIndex,
+		Market:       d.Config.TickersMarketIndex,
+		ScanSegments: d.Config.ScanSegments,
+	}
+	if !d.Config.TickersUseActiveIndex {
+		indexes.Active = ""
+	}
+	repo := repository.NewChangeLoggingTickerRepository(
+		repository.NewTickerRepository(d.DB, d.Config.TickersTable, indexes), d.TickerChangeRepository())
+	if d.Cache == nil {
+		return repo
+	}
+	return repository.NewCachedTickerRepository(repo, d.Cache, repository.TickerCacheTTLs{
+		Ticker:        d.Config.TickerCacheTTL,
+		ActiveTickers: d.Config.ActiveTickersCacheTTL,
+	}, d.Log)
+}
+
+// TickerChangeRepository logs the ticker writes clients sync from, in process
+// memory with the memory storage backend
+func (d Deps) TickerChangeRepository() repository.TickerChangeRepository {
+	if d.Memory != nil {
+		return d.Memory.TickerChanges
+	}
+	return repository.NewTickerChangeRepository(d.DB, d.Config.TickerChangesTable, d.Config.TickerChangeRetention)
+}
+
+// APIKeyRepository stores the API keys, in process memory with the memory
+// storage backend
+func (d Deps) APIKeyRepository() repository.APIKeyRepository {
+	if d.Memory != nil {
+		return d.Memory.APIKeys
+	}
+	return repository.NewAPIKeyRepository(d.DB, d.Config.APIKeysTable)
+}
+
+// NonceRepository remembers the nonces of signed requests, in process memory
+// with the memory storage backend
+func (d Deps) NonceRepository() repository.NonceRepository {
+	if d.Memory != nil {
+		return d.Memory.Nonces
+	}
+	return repository.NewNonceRepository(d.DB, d.Config.NoncesTable)
+}
+
+// DailySummaryRepository stores the daily bars. Bars it overwrites with
+// different values are recorded in the SummaryRevisionRepository.
+func (d Deps) DailySummaryRepository() repository.DailySummaryRepository {
+	return repository.NewRevisionLoggingDailySummaryRepository(
+		repository.NewDailySummaryRepository(d.DB, d.Config.DailySummaryTable), d.SummaryRevisionRepository())
+}
+
+// SummaryRevisionRepository logs the corrections of the daily bars
+func (d Deps) SummaryRevisionRepository() repository.SummaryRevisionRepository {
+	return repository.NewSummaryRevisionRepository(d.DB, d.Config.SummaryRevisionsTable)
+}
+
+// AnomalyService screens ingested daily bars for improbable moves and
+// reviews the ones it holds. Flagged bars are logged, posted to the anomaly
+// webhook and Slack when they are configured and counted on /metrics.
+func (d Deps) AnomalyService() service.AnomalyService {
+	cfg := d.Config
+	notifier := notify.Log(d.Log)
+	if cfg.AnomalyWebhookURL != "" {
+		notifier = notify.Multi(notifier, notify.Webhook(cfg.AnomalyWebhookURL, cfg.AlertWebhookTimeout, d.Templates))
+	}
+	if cfg.SlackWebhookURL != "" {
+		notifier = notify.Multi(notifier, notify.Slack(cfg.SlackWebhookURL, cfg.AlertWebhookTimeout, d.Templates))
+	}
+	var observer service.AnomalyObserver
+	if d.Metrics != nil {
+		observer = d.Metrics
+	}
+	return service.NewAnomalyService(d.DailySummaryRepository(),
+		repository.NewHeldSummaryRepository(d.DB, cfg.HeldSummariesTable),
+		service.AnomalyConfig{
+			ZScore:     cfg.AnomalyZScore,
+			IQRFactor:  cfg.AnomalyIQRFactor,
+			Lookback:   cfg.AnomalyLookback,
+			MinHistory: cfg.AnomalyMinHistory,
+			Hold:       cfg.AnomalyHold,
+		}, notifier, d.Templates, observer, d.Events, d.Log)
+}
+
+// ScreenedIngestion returns the AnomalyService when ANOMALY_DETECTION is on,
+// for the ingestion pipeline to screen its bars through, and nil otherwise
+func (d Deps) ScreenedIngestion() service.AnomalyService {
+	if !d.Config.AnomalyDetection {
+		return nil
+	}
+	return d.AnomalyService()
+}
+
+func (d Deps) IntradayBarRepository() repository.IntradayBarRepository {
+	return repository.NewIntradayBarRepository(d.DB, d.Config.IntradayBarsTable)
+}
+
+func (d Deps) SignalRepository() repository.SignalRepository {
+	return repository.NewSignalRepository(d.DB, d.Config.SignalsTable)
+}
+
+// SettingsService stores flags, checkpoints and other small state
+func (d Deps) SettingsService() service.SettingsService {
+	return service.NewSettingsService(repository.NewSettingsRepository(d.DB, d.Config.SettingsTable), d.Log)
+}
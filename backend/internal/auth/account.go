@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteAccount deletes the calling key's account, keeping its data
+// restorable for the retention
+func (h *Handler) DeleteAccount(c *gin.Context) {
+	deletion, err := h.accountService.Delete(c.Request.Context())
+	if err != nil {
+		h.respondAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, deletion)
+}
+
+// RestoreAccount reactivates a deleted account, for support
+func (h *Handler) RestoreAccount(c *gin.Context) {
+	key, err := h.accountService.Restore(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondAccountError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+func (h *Handler) respondAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAccountRequired):
+		problem.Respond(c, problem.Unauthenticated, "API key required to delete the account")
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		problem.Respond(c, problem.APIKeyNotFound, "API key not found")
+	case errors.Is(err, service.ErrAccountNotDeleted), errors.Is(err, service.ErrRestoreWindowClosed):
+		problem.Respond(c, problem.Conflict, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("account request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process account request")
+	}
+}
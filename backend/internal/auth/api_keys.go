@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createAPIKeyRequest struct {
+	Name  string `json:"name"`
+	Admin bool   `json:"admin"`
+	// Scopes restrict the key; omitted for full access
+	Scopes []models.APIKeyScope `json:"scopes,omitempty"`
+}
+
+type setTierRequest struct {
+	Tier models.PlanTier `json:"tier"`
+}
+
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to list api keys", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve API keys")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  keys,
+		"count": len(keys),
+	})
+}
+
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	issued, err := h.apiKeyService.CreateKey(c.Request.Context(), req.Name, req.Admin, req.Scopes)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to create api key", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to create API key")
+		return
+	}
+
+	c.JSON(http.StatusCreated, issued)
+}
+
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	err := h.apiKeyService.RevokeKey(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			problem.Respond(c, problem.APIKeyNotFound, "API key not found")
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to revoke api key", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to revoke API key")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// SetAPIKeyTier moves a key to another plan tier
+func (h *Handler) SetAPIKeyTier(c *gin.Context) {
+	var req setTierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	key, err := h.apiKeyService.SetTier(c.Request.Context(), c.Param("id"), req.Tier)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidAPIKey):
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+		case errors.Is(err, service.ErrAPIKeyNotFound):
+			problem.Respond(c, problem.APIKeyNotFound, "API key not found")
+		default:
+			api.Logger(c, h.log).Errorw("failed to set api key tier", "error", err)
+			problem.Respond(c, problem.Internal, "Failed to set API key tier")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
+
+// IssueSigningSecret replaces a key's signing secret, returning it once
+func (h *Handler) IssueSigningSecret(c *gin.Context) {
+	id := c.Param("id")
+	secret, err := h.apiKeyService.IssueSigningSecret(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			problem.Respond(c, problem.APIKeyNotFound, "API key not found")
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to issue signing secret", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to issue signing secret")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":            id,
+		"signingSecret": secret,
+	})
+}
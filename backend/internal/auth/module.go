@@ -0,0 +1,200 @@
+// Package auth manages the API keys that authenticate requests, the plan tier
+// each key is on, and the deletion of the account each key holds.
+package auth
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/activity"
+	"profitify-backend/internal/analytics"
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/portfolios"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	apiKeyService    service.APIKeyService
+	quotaService     service.QuotaService
+	signatureService service.SignatureService
+	termsService     service.TermsService
+	accountService   service.AccountService
+	log              *zap.SugaredLogger
+}
+
+// Wire builds the auth module from the shared dependencies. Rejected signed
+// requests are counted on the metrics, when given.
+func Wire(deps app.Deps) *Handler {
+	keys := deps.APIKeyRepository()
+
+	// A deleted account's data is purged from every table holding data of
+	// the key
+	cfg := deps.Config
+	purgers := []service.AccountPurger{
+		repository.NewOwnedItemPurger(deps.DB, cfg.WatchlistsTable),
+		repository.NewOwnedItemPurger(deps.DB, cfg.AlertsTable),
+		repository.NewOwnedItemPurger(deps.DB, cfg.DigestsTable),
+		repository.NewOwnedItemPurger(deps.DB, cfg.DevicesTable),
+		repository.NewOwnedItemPurger(deps.DB, cfg.SessionsTable),
+		portfolios.NewPortfolioPurger(deps.DB, cfg.PortfoliosTable, cfg.PortfolioTransactionsTable),
+		portfolios.NewCustomAssetPurger(deps.DB, cfg.CustomAssetsTable, cfg.AssetValuationsTable),
+		activity.NewPurger(deps.DB, cfg.ActivityTable),
+	}
+
+	var observer service.SignatureObserver
+	if deps.Metrics != nil {
+		observer = deps.Metrics
+	}
+
+	return &Handler{
+		apiKeyService: service.NewAPIKeyService(keys, deps.Log),
+		quotaService:  service.NewQuotaService(analytics.NewRepository(deps.DB, deps.Config.AnalyticsTable), deps.Log),
+		signatureService: service.NewSignatureService(
+			keys,
+			deps.NonceRepository(),
+			deps.Config.SignatureClockSkew,
+			observer,
+			deps.Log,
+		),
+		termsService:   service.NewTermsService(keys, cfg.TermsVersion, deps.Log),
+		accountService: service.NewAccountService(keys, purgers, cfg.AccountRetention, deps.Log),
+		log:            deps.Log,
+	}
+}
+
+// Keys returns the API key service, which also authenticates requests
+func (h *Handler) Keys() service.APIKeyService {
+	return h.apiKeyService
+}
+
+// Quotas returns the service counting requests against plan quotas. Counts
+// are kept in the request analytics table.
+func (h *Handler) Quotas() service.QuotaService {
+	return h.quotaService
+}
+
+// Signatures returns the service verifying requests signed with a key's
+// signing secret
+func (h *Handler) Signatures() service.SignatureService {
+	return h.signatureService
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	// Of the account routes, which need the terms accepted, scopes only grant
+	// the portfolio ones
+	terms := api.Group("/account/terms", middleware.RequireScope(models.ScopeWritePortfolio))
+	terms.GET("", h.GetTerms)
+	terms.POST("", h.AcceptTerms)
+
+	api.DELETE("/account", middleware.RequireFullAccess(), h.DeleteAccount)
+
+	admin.GET("/api-keys", h.ListAPIKeys)
+	admin.POST("/api-keys", h.CreateAPIKey)
+	admin.POST("/api-keys/:id/revoke", h.RevokeAPIKey)
+	admin.PUT("/api-keys/:id/tier", h.SetAPIKeyTier)
+	admin.POST("/api-keys/:id/signing-secret", h.IssueSigningSecret)
+	admin.POST("/api-keys/:id/restore", h.RestoreAccount)
+}
+
+// PurgeJobs purges the data of accounts deleted longer ago than
+// ACCOUNT_RETENTION, after the post-close jobs' data is written
+func (h *Handler) PurgeJobs() []jobs.Job {
+	return []jobs.Job{
+		jobs.NewJob("account-purge", func(ctx context.Context, _ time.Time) error {
+			_, err := h.accountService.PurgeExpired(ctx)
+			return err
+		}),
+	}
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	accountTags := []string{"Account"}
+
+	doc.Add(http.MethodGet, "/api/v1/account/terms", &openapi.Operation{
+		Tags:        accountTags,
+		Summary:     "Get the terms the calling key's holder accepted",
+		Description: "`currentVersion` is the version of the terms of service and privacy policy that must be accepted; empty when TERMS_VERSION is not set.",
+		Responses:   api.Responses(http.StatusOK, doc.Schema(service.TermsStatus{}), http.StatusUnauthorized),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/account", &openapi.Operation{
+		Tags:    accountTags,
+		Summary: "Delete the calling key's account",
+		Description: "The key and its sessions stop authenticating at once. Watchlists, alerts, digests, devices and " +
+			"portfolios are kept, quarantined, for ACCOUNT_RETENTION, during which support can restore the account, " +
+			"and are then deleted for good.",
+		Responses: api.Responses(http.StatusAccepted, doc.Schema(service.AccountDeletion{}), http.StatusUnauthorized),
+	})
+	doc.Add(http.MethodPost, "/api/v1/account/terms", &openapi.Operation{
+		Tags:    accountTags,
+		Summary: "Accept the current terms",
+		Description: "Until the current version is accepted, the account routes, such as watchlists, alerts and " +
+			"portfolios, are rejected with 403 TERMS_NOT_ACCEPTED. Only the current version may be accepted; " +
+			"accepting it again keeps the first acceptance.",
+		RequestBody: openapi.JSONBody(doc.Inline(acceptTermsRequest{})),
+		Responses: api.Responses(http.StatusOK, doc.Schema(service.TermsStatus{}),
+			http.StatusBadRequest, http.StatusUnauthorized, http.StatusConflict, http.StatusServiceUnavailable),
+	})
+
+	tags := []string{"Admin"}
+	id := openapi.PathParam("id", "API key ID")
+
+	doc.Add(http.MethodGet, "/api/v1/admin/api-keys", &openapi.Operation{
+		Tags:      tags,
+		Summary:   "List API keys",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "keys", models.APIKey{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/api-keys", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Issue an API key",
+		Description: "The key itself is only returned in this response. New keys are on the free tier. " +
+			"A key given scopes (read:market, write:portfolio, admin) may only call the routes of those scopes; " +
+			"account routes such as watchlists and alerts need a key without scopes.",
+		RequestBody: openapi.JSONBody(doc.Inline(createAPIKeyRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(models.IssuedAPIKey{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/api-keys/:id/revoke", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Revoke an API key",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPut, "/api/v1/admin/api-keys/:id/tier", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Move an API key to another plan tier",
+		Parameters: []openapi.Parameter{id},
+		RequestBody: openapi.JSONBody(openapi.Object(map[string]*openapi.Schema{
+			"tier": {Type: "string", Enum: []any{models.PlanFree, models.PlanPro}},
+		})),
+		Responses: api.Responses(http.StatusOK, doc.Schema(models.APIKey{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/api-keys/:id/signing-secret", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Issue a signing secret for an API key",
+		Description: "Replaces the key's previous secret, which is only returned in this response. Requests signed " +
+			"with it carry X-Key-ID, X-Timestamp (Unix seconds), X-Nonce and X-Signature, the base64 HMAC-SHA256 of " +
+			"the method, path with query, timestamp, nonce and hex SHA-256 of the body, joined by newlines, instead " +
+			"of X-API-Key. Timestamps outside SIGNATURE_CLOCK_SKEW and reused nonces are rejected with 401.",
+		Parameters: []openapi.Parameter{id},
+		Responses: api.Responses(http.StatusCreated, openapi.Object(map[string]*openapi.Schema{
+			"id":            {Type: "string"},
+			"signingSecret": {Type: "string"},
+		}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/api-keys/:id/restore", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Restore a deleted account",
+		Description: "For support: reactivates the key of an account deleted less than ACCOUNT_RETENTION ago, with its data. Purged accounts cannot be restored.",
+		Parameters:  []openapi.Parameter{id},
+		Responses:   api.Responses(http.StatusOK, doc.Schema(models.APIKey{}), http.StatusNotFound, http.StatusConflict),
+	})
+}
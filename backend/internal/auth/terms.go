@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type acceptTermsRequest struct {
+	// Version is the version of the terms shown to the key's holder
+	Version string `json:"version"`
+}
+
+// GetTerms reports whether the calling key's holder accepted the current terms
+func (h *Handler) GetTerms(c *gin.Context) {
+	status, err := h.termsService.Status(c.Request.Context())
+	if err != nil {
+		h.respondTermsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// AcceptTerms records that the calling key's holder accepted the current terms
+func (h *Handler) AcceptTerms(c *gin.Context) {
+	var req acceptTermsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	status, err := h.termsService.Accept(c.Request.Context(), req.Version)
+	if err != nil {
+		h.respondTermsError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (h *Handler) respondTermsError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTermsAccountRequired):
+		problem.Respond(c, problem.Unauthenticated, "API key required to accept the terms")
+	case errors.Is(err, service.ErrStaleTermsVersion):
+		problem.Respond(c, problem.Conflict, err.Error())
+	case errors.Is(err, service.ErrTermsNotConfigured):
+		problem.Respond(c, problem.Unavailable, "Terms acceptance is not configured")
+	default:
+		api.Logger(c, h.log).Errorw("terms request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process terms request")
+	}
+}
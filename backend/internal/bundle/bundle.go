@@ -0,0 +1,22 @@
+package bundle
+
+import (
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/watchlists"
+)
+
+// Bundle is everything a mobile app loads at cold start, in one response
+type Bundle struct {
+	GeneratedUTC int64 `json:"generatedUTC"`
+	// Tickers are the active tickers, ordered by symbol
+	Tickers []models.Ticker `json:"tickers"`
+	// TickersCursor syncs the changes made to Tickers since the bundle was
+	// generated, as the since of GET /api/v1/sync/tickers
+	TickersCursor string `json:"tickersCursor"`
+	// Watchlists are the caller's, sorted by name
+	Watchlists []watchlists.Watchlist `json:"watchlists"`
+	// Quotes are the latest quotes of the symbols in the watchlists, ordered
+	// by symbol; symbols without daily summaries are listed in Missing
+	Quotes  []models.Quote `json:"quotes"`
+	Missing []string       `json:"missing"`
+}
@@ -0,0 +1,118 @@
+// Package bundle serves the snapshot mobile apps load at cold start: the
+// active tickers, the caller's watchlists and their latest quotes in one
+// compressed response.
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/tickers"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	bundleService Service
+	log           *zap.SugaredLogger
+}
+
+func NewHandler(bundles Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		bundleService: bundles,
+		log:           log,
+	}
+}
+
+// Wire builds the bundle module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	cfg := deps.Config
+	summaries := service.NewDailySummaryService(deps.DailySummaryRepository(), deps.Log)
+	return NewHandler(NewService(
+		tickers.NewSyncService(deps.TickerRepository(), deps.TickerChangeRepository(), cfg.TickerChangeRetention, deps.Log),
+		watchlists.NewService(watchlists.NewRepository(deps.DB, cfg.WatchlistsTable), summaries, deps.Log),
+		summaries,
+		deps.Cache,
+		cfg.BundleCacheTTL,
+		deps.Log,
+	), deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.GET("/bundle", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms(), h.GetBundle)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	doc.Add(http.MethodGet, "/api/v1/bundle", &openapi.Operation{
+		Tags:    []string{"Bundle"},
+		Summary: "Get the cold start bundle",
+		Description: "The active tickers, the caller's watchlists and the latest quotes of their symbols in one response, " +
+			"gzip-compressed for clients accepting it. Continue from the tickers with tickersCursor as the since of " +
+			"/api/v1/sync/tickers. Bundles are cached for BUNDLE_CACHE_TTL, so tickers and quotes may lag by that long; " +
+			"edited watchlists are always current.",
+		Responses: api.Responses(http.StatusOK, doc.Schema(Bundle{})),
+	})
+}
+
+// GetBundle answers with the caller's bundle, compressed unless the client
+// does not accept gzip
+func (h *Handler) GetBundle(c *gin.Context) {
+	compressed, err := h.bundleService.Bundle(c.Request.Context())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to build bundle", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to build bundle")
+		return
+	}
+
+	c.Header("Vary", "Accept-Encoding")
+	if acceptsGzip(c.GetHeader("Accept-Encoding")) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json; charset=utf-8", compressed)
+		return
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to decompress bundle", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to build bundle")
+		return
+	}
+	defer zr.Close()
+	c.DataFromReader(http.StatusOK, -1, "application/json; charset=utf-8", zr, nil)
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip with a
+// non-zero quality, by name or else by wildcard
+func acceptsGzip(header string) bool {
+	var named, wildcard *bool
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		accepted := !zeroQuality(params)
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip":
+			named = &accepted
+		case "*":
+			wildcard = &accepted
+		}
+	}
+	if named != nil {
+		return *named
+	}
+	return wildcard != nil && *wildcard
+}
+
+// zeroQuality reports whether a coding's parameters set q=0, which refuses it
+func zeroQuality(params string) bool {
+	q, ok := strings.CutPrefix(strings.ReplaceAll(strings.TrimSpace(params), " ", ""), "q=")
+	return ok && strings.Trim(strings.TrimPrefix(q, "0."), "0") == ""
+}
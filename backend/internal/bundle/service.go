@@ -0,0 +1,168 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/tickers"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/cache"
+	"profitify-backend/pkg/logger"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// bundleCachePrefix namespaces the cached bundles; bump its version when the
+// bundle's encoding changes
+const bundleCachePrefix = "bundle:v1:"
+
+type Service interface {
+	// Bundle returns the caller's bundle as gzip-compressed JSON
+	Bundle(ctx context.Context) ([]byte, error)
+}
+
+type bundleService struct {
+	tickers    tickers.SyncService
+	watchlists watchlists.Service
+	quotes     service.DailySummaryService
+	cache      cache.Cache
+	ttl        time.Duration
+	log        *zap.SugaredLogger
+	now        func() time.Time
+}
+
+// NewService builds bundles from the ticker snapshot the sync service starts
+// from and the caller's watchlists. Compressed bundles are cached for ttl,
+// keyed by the caller's watchlists, so an edited watchlist is never served
+// stale; the tickers and quotes may lag by up to ttl. A nil cache or zero
+// ttl builds every bundle afresh.
+func NewService(tickerSync tickers.SyncService, lists watchlists.Service, quotes service.DailySummaryService, c cache.Cache, ttl time.Duration, log *zap.SugaredLogger) Service {
+	return &bundleService{
+		tickers:    tickerSync,
+		watchlists: lists,
+		quotes:     quotes,
+		cache:      c,
+		ttl:        ttl,
+		log:        log,
+		now:        time.Now,
+	}
+}
+
+func (s *bundleService) Bundle(ctx context.Context) ([]byte, error) {
+	lists, err := s.watchlists.ListWatchlists(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchlists: %w", err)
+	}
+
+	key := bundleKey(ctx, lists)
+	if compressed, ok := s.load(ctx, key); ok {
+		return compressed, nil
+	}
+
+	bundle, err := s.build(ctx, lists)
+	if err != nil {
+		return nil, err
+	}
+	compressed, err := compress(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	s.store(ctx, key, compressed)
+	logger.FromContext(ctx, s.log).Debugw("built bundle", "tickers", len(bundle.Tickers), "watchlists", len(bundle.Watchlists),
+		"quotes", len(bundle.Quotes), "bytes", len(compressed))
+	return compressed, nil
+}
+
+func (s *bundleService) build(ctx context.Context, lists []watchlists.Watchlist) (*Bundle, error) {
+	snapshot, err := s.tickers.Sync(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot tickers: %w", err)
+	}
+
+	var symbols []string
+	for _, list := range lists {
+		symbols = append(symbols, list.Symbols...)
+	}
+	slices.Sort(symbols)
+	symbols = slices.Compact(symbols)
+
+	bundle := &Bundle{
+		GeneratedUTC:  s.now().Unix(),
+		Tickers:       snapshot.Tickers,
+		TickersCursor: snapshot.Cursor,
+		Watchlists:    lists,
+		Quotes:        make([]models.Quote, 0, len(symbols)),
+		Missing:       []string{},
+	}
+	quotes, errs := service.LatestQuotes(ctx, s.quotes, symbols)
+	for i, symbol := range symbols {
+		switch {
+		case errs[i] == nil:
+			bundle.Quotes = append(bundle.Quotes, *quotes[i])
+		case errors.Is(errs[i], service.ErrTickerNotFound):
+			bundle.Missing = append(bundle.Missing, symbol)
+		default:
+			return nil, fmt.Errorf("failed to get quote for %s: %w", symbol, errs[i])
+		}
+	}
+	return bundle, nil
+}
+
+// bundleKey is the cache key of the caller's bundle, which changes whenever
+// one of their watchlists is created, updated or deleted
+func bundleKey(ctx context.Context, lists []watchlists.Watchlist) string {
+	h := sha256.New()
+	if key, ok := service.AccountFromContext(ctx); ok {
+		h.Write([]byte(key.ID))
+	}
+	for _, list := range lists {
+		h.Write([]byte("\n" + list.ID + ":" + strconv.FormatInt(list.UpdatedUTC, 10)))
+	}
+	return bundleCachePrefix + hex.EncodeToString(h.Sum(nil))
+}
+
+func compress(bundle *Bundle) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(bundle); err != nil {
+		return nil, fmt.Errorf("failed to encode bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress bundle: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// load returns the cached bundle under key. A failing cache is logged and
+// bypassed.
+func (s *bundleService) load(ctx context.Context, key string) ([]byte, bool) {
+	if s.cache == nil || s.ttl <= 0 {
+		return nil, false
+	}
+	compressed, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to read cached bundle", "error", err)
+		return nil, false
+	}
+	return compressed, ok
+}
+
+func (s *bundleService) store(ctx context.Context, key string, compressed []byte) {
+	if s.cache == nil || s.ttl <= 0 {
+		return
+	}
+	if err := s.cache.Set(ctx, key, compressed, s.ttl); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to cache bundle", "error", err)
+	}
+}
@@ -0,0 +1,187 @@
+package bundle
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/tickers"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/cache"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type bundleFixture struct {
+	svc       Service
+	lists     *watchlists.MockRepository
+	summaries *repository.MockDailySummaryRepository
+}
+
+func newBundleFixture(c cache.Cache) *bundleFixture {
+	log := zap.NewNop().Sugar()
+	tickerRepo := repository.NewMemoryTickerRepository([]models.Ticker{
+		{Ticker: "MSFT", Name: "Microsoft", Market: "stocks", Locale: "us", Active: 1},
+		{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", Active: 1},
+		{Ticker: "OLD", Name: "Delisted", Market: "stocks", Locale: "us"},
+	})
+	changes := repository.NewMemoryTickerChangeRepository(time.Hour)
+	lists := new(watchlists.MockRepository)
+	summaries := new(repository.MockDailySummaryRepository)
+	quotes := service.NewDailySummaryService(summaries, log)
+
+	return &bundleFixture{
+		svc: NewService(tickers.NewSyncService(tickerRepo, changes, time.Hour, log),
+			watchlists.NewService(lists, quotes, log), quotes, c, time.Minute, log),
+		lists:     lists,
+		summaries: summaries,
+	}
+}
+
+func decompress(t *testing.T, compressed []byte) *Bundle {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	var bundle Bundle
+	require.NoError(t, json.NewDecoder(zr).Decode(&bundle))
+	return &bundle
+}
+
+func TestService_Bundle(t *testing.T) {
+	key := &models.APIKey{ID: "key-1"}
+	ctx := service.WithAccount(context.Background(), key)
+	f := newBundleFixture(cache.NewMemory())
+
+	tech := watchlists.Watchlist{ID: "w1", Name: "Tech", Symbols: []string{"MSFT", "AAPL"}, KeyID: "key-1", UpdatedUTC: 100}
+	f.lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{
+		tech,
+		{ID: "w2", Name: "New", Symbols: []string{"AAPL", "NEW"}, KeyID: "key-1"},
+		{ID: "w3", Name: "Other", Symbols: []string{"TSLA"}, KeyID: "key-2"},
+	}, nil).Once()
+	f.summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Close: 185, Timestamp: 1704258000},
+		{Ticker: "AAPL", Close: 180, Timestamp: 1704171600},
+	}, nil)
+	f.summaries.On("GetLatestSummaries", mock.Anything, "MSFT", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "MSFT", Close: 370, Timestamp: 1704258000},
+	}, nil)
+	f.summaries.On("GetLatestSummaries", mock.Anything, "NEW", mock.Anything, int32(2)).Return([]models.DailySummary{}, nil)
+
+	compressed, err := f.svc.Bundle(ctx)
+	require.NoError(t, err)
+	bundle := decompress(t, compressed)
+
+	require.Len(t, bundle.Tickers, 2, "only active tickers are bundled")
+	assert.Equal(t, "AAPL", bundle.Tickers[0].Ticker)
+	assert.NotEmpty(t, bundle.TickersCursor)
+	require.Len(t, bundle.Watchlists, 2, "only the caller's watchlists are bundled")
+	assert.Equal(t, "New", bundle.Watchlists[0].Name)
+	require.Len(t, bundle.Quotes, 2, "each symbol is quoted once")
+	assert.Equal(t, "AAPL", bundle.Quotes[0].Ticker)
+	assert.Equal(t, float32(180), bundle.Quotes[0].PreviousClose)
+	assert.Equal(t, "MSFT", bundle.Quotes[1].Ticker)
+	assert.Equal(t, []string{"NEW"}, bundle.Missing)
+
+	// The same watchlists are served from the cache
+	f.lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{
+		tech,
+		{ID: "w2", Name: "New", Symbols: []string{"AAPL", "NEW"}, KeyID: "key-1"},
+	}, nil).Once()
+	cached, err := f.svc.Bundle(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, compressed, cached)
+	f.summaries.AssertNumberOfCalls(t, "GetLatestSummaries", 3)
+
+	// An edited watchlist is bundled afresh
+	tech.Symbols = []string{"MSFT"}
+	tech.UpdatedUTC = 200
+	f.lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{tech}, nil).Once()
+	edited, err := f.svc.Bundle(ctx)
+	require.NoError(t, err)
+	bundle = decompress(t, edited)
+	require.Len(t, bundle.Quotes, 1)
+	assert.Equal(t, "MSFT", bundle.Quotes[0].Ticker)
+}
+
+func TestService_BundleErrors(t *testing.T) {
+	ctx := context.Background()
+	f := newBundleFixture(nil)
+
+	f.lists.On("ListWatchlists", mock.Anything).Return(nil, errors.New("table unavailable")).Once()
+	_, err := f.svc.Bundle(ctx)
+	assert.Error(t, err)
+
+	f.lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{
+		{ID: "w1", Name: "Tech", Symbols: []string{"AAPL"}},
+	}, nil).Once()
+	f.summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return(nil, errors.New("throttled"))
+	_, err = f.svc.Bundle(ctx)
+	assert.ErrorContains(t, err, "failed to get quote for AAPL")
+}
+
+func TestHandler_GetBundle(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	f := newBundleFixture(nil)
+	f.lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{}, nil)
+
+	r := gin.New()
+	NewHandler(f.svc, zap.NewNop().Sugar()).RegisterRoutes(r.Group("/api"), r.Group("/api/admin"))
+	get := func(acceptEncoding string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/bundle", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("br, gzip;q=0.8")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Len(t, decompress(t, w.Body.Bytes()).Tickers, 2)
+
+	w = get("gzip;q=0, *")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"), "clients refusing gzip get plain JSON")
+	body, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	var bundle Bundle
+	require.NoError(t, json.Unmarshal(body, &bundle))
+	assert.Len(t, bundle.Tickers, 2)
+	assert.Empty(t, bundle.Watchlists)
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":                    false,
+		"gzip":                true,
+		"GZIP, deflate":       true,
+		"deflate, br":         false,
+		"*":                   true,
+		"gzip;q=0":            false,
+		"gzip; q=0.000":       false,
+		"gzip;q=0.001":        true,
+		"*;q=0":               false,
+		"gzip;q=0.5, *;q=0":   true,
+		"identity, *;q=0.1":   true,
+		"gzip;q=0, *;q=1.0":   false,
+		"deflate;q=0.5, gzip": true,
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, acceptsGzip(header), header)
+	}
+}
@@ -0,0 +1,65 @@
+package devices
+
+import (
+	"fmt"
+)
+
+// Platform is the push service a device is reached through
+type Platform string
+
+const (
+	// PlatformIOS devices are reached through APNs
+	PlatformIOS Platform = "ios"
+	// PlatformAndroid devices are reached through FCM
+	PlatformAndroid Platform = "android"
+)
+
+// maxTokenLength bounds push tokens; APNs and FCM tokens are far shorter
+const maxTokenLength = 4096
+
+// Preferences choose the notifications pushed to a device
+type Preferences struct {
+	Alerts  bool `json:"alerts" dynamodbav:"alerts"`
+	Digests bool `json:"digests" dynamodbav:"digests"`
+}
+
+// Device is a mobile app installation registered for push notifications. Its
+// ID is derived from the platform and token, so re-registering a token
+// updates the same device.
+type Device struct {
+	ID       string   `json:"id" dynamodbav:"id"`
+	Platform Platform `json:"platform" dynamodbav:"platform"`
+	// Token is the APNs device token or FCM registration token; it is never
+	// returned by the API
+	Token       string      `json:"-" dynamodbav:"token"`
+	Name        string      `json:"name,omitempty" dynamodbav:"name,omitempty"`
+	Preferences Preferences `json:"preferences" dynamodbav:"preferences"`
+	CreatedUTC  int64       `json:"createdUTC" dynamodbav:"createdUTC"`
+	UpdatedUTC  int64       `json:"updatedUTC" dynamodbav:"updatedUTC"`
+	// KeyID is the API key that registered the device; it receives the
+	// notifications raised for that key
+	KeyID string `json:"-" dynamodbav:"keyId,omitempty"`
+}
+
+// Validate checks if the device data is valid
+func (d *Device) Validate() error {
+	switch d.Platform {
+	case PlatformIOS, PlatformAndroid:
+	default:
+		return fmt.Errorf("platform must be %s or %s", PlatformIOS, PlatformAndroid)
+	}
+
+	if d.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+
+	if len(d.Token) > maxTokenLength {
+		return fmt.Errorf("token must be at most %d characters", maxTokenLength)
+	}
+
+	if len(d.Name) > 100 {
+		return fmt.Errorf("device name must be at most 100 characters")
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package devices
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+type deviceRequest struct {
+	Platform Platform `json:"platform"`
+	Token    string   `json:"token"`
+	Name     string   `json:"name,omitempty"`
+	// Preferences default to every notification for new devices and are
+	// kept for known ones
+	Preferences *Preferences `json:"preferences,omitempty"`
+}
+
+func (h *Handler) ListDevices(c *gin.Context) {
+	devices, err := h.deviceService.ListDevices(c.Request.Context())
+	if err != nil {
+		h.respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"devices": devices,
+		"count":   len(devices),
+	})
+}
+
+func (h *Handler) RegisterDevice(c *gin.Context) {
+	var req deviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	device, err := h.deviceService.Register(c.Request.Context(), &Device{
+		Platform: req.Platform,
+		Token:    req.Token,
+		Name:     req.Name,
+	}, req.Preferences)
+	if err != nil {
+		h.respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+func (h *Handler) GetDevice(c *gin.Context) {
+	device, err := h.deviceService.GetDevice(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	var prefs Preferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	device, err := h.deviceService.UpdatePreferences(c.Request.Context(), c.Param("id"), prefs)
+	if err != nil {
+		h.respondDeviceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, device)
+}
+
+func (h *Handler) DeleteDevice(c *gin.Context) {
+	if err := h.deviceService.Unregister(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondDeviceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondDeviceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrDeviceNotFound):
+		problem.Respond(c, problem.DeviceNotFound, "Device not found")
+	case errors.Is(err, ErrInvalidDevice):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, ErrDeviceTaken):
+		problem.Respond(c, problem.Conflict, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("device request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process device request")
+	}
+}
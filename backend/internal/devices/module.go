@@ -0,0 +1,91 @@
+// Package devices serves the registration of mobile devices for push
+// notifications of triggered alerts and digests.
+package devices
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	deviceService Service
+	log           *zap.SugaredLogger
+}
+
+func NewHandler(devices Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		deviceService: devices,
+		log:           log,
+	}
+}
+
+// Wire builds the devices module from the shared dependencies. Devices can
+// register while push is not configured; they are reached once it is.
+func Wire(deps app.Deps) *Handler {
+	return NewHandler(NewService(NewRepository(deps.DB, deps.Config.DevicesTable), deps.Log), deps.Log)
+}
+
+// WithPush adds delivery to registered mobile devices to notifier when a push
+// platform is configured
+func WithPush(deps app.Deps, notifier notify.Notifier) notify.Notifier {
+	if len(deps.Push) == 0 {
+		return notifier
+	}
+	return notify.Multi(notifier, NewPushNotifier(NewRepository(deps.DB, deps.Config.DevicesTable), deps.Push, deps.Templates, deps.Log))
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	devices := api.Group("/devices", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms())
+	devices.GET("", h.ListDevices)
+	devices.POST("", h.RegisterDevice)
+	devices.GET("/:id", h.GetDevice)
+	devices.PUT("/:id/preferences", h.UpdatePreferences)
+	devices.DELETE("/:id", h.DeleteDevice)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Devices"}
+	id := openapi.PathParam("id", "Device ID")
+
+	doc.Add(http.MethodGet, "/api/v1/devices", &openapi.Operation{
+		Tags:      tags,
+		Summary:   "List the devices registered with the calling key",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "devices", Device{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/devices", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Register a device's push token",
+		Description: "The token is an APNs device token on ios and an FCM registration token on android. " +
+			"Registering a known token updates its device and keeps its preferences unless new ones are given; " +
+			"new devices receive alerts and digests by default. A token registered with another key is rejected until that key unregisters it.",
+		RequestBody: openapi.JSONBody(doc.Inline(deviceRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(Device{}), http.StatusBadRequest, http.StatusConflict),
+	})
+	doc.Add(http.MethodGet, "/api/v1/devices/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get a device",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(Device{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodPut, "/api/v1/devices/:id/preferences", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Choose the notifications pushed to a device",
+		Parameters:  []openapi.Parameter{id},
+		RequestBody: openapi.JSONBody(doc.Schema(Preferences{})),
+		Responses:   api.Responses(http.StatusOK, doc.Schema(Device{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/devices/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Unregister a device",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,107 @@
+package devices
+
+import (
+	"context"
+	"errors"
+
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/push"
+
+	"go.uber.org/zap"
+)
+
+// pushBodyLimit bounds the body of a push notification in characters; longer
+// bodies, such as rendered digests, are cut short
+const pushBodyLimit = 240
+
+// NewPushNotifier returns a notifier pushing alert and digest notifications,
+// rendered by the push templates of their kinds, to the devices registered
+// by the API key they were raised for, when the device's preferences allow it
+// and its platform has a sender. Tokens the platform reports unregistered are
+// deleted.
+//
+// Push is best effort: delivery failures are logged rather than returned, so
+// an unreachable device never fails, or causes a retry of, the email and
+// webhook deliveries it is combined with.
+func NewPushNotifier(devices Repository, senders push.Senders, templates *notify.Templates, log *zap.SugaredLogger) notify.Notifier {
+	return &pushNotifier{
+		devices:   devices,
+		senders:   senders,
+		templates: templates,
+		log:       log,
+	}
+}
+
+type pushNotifier struct {
+	devices   Repository
+	senders   push.Senders
+	templates *notify.Templates
+	log       *zap.SugaredLogger
+}
+
+func (p *pushNotifier) Notify(ctx context.Context, n notify.Notification) error {
+	if n.Kind != notify.KindAlert && n.Kind != notify.KindDigest {
+		return nil
+	}
+
+	devices, err := p.devices.ListDevices(ctx)
+	if err != nil {
+		p.log.Errorw("failed to list devices for push", "kind", n.Kind, "error", err)
+		return nil
+	}
+
+	rendered, err := p.templates.Render(n, notify.ChannelPush)
+	if err != nil {
+		// The subject and body composed for the other channels still fit
+		p.log.Warnw("failed to render push notification", "kind", n.Kind, "error", err)
+		rendered = notify.Message{Subject: n.Subject, Body: n.Body}
+	}
+	msg := push.Message{
+		Title: rendered.Subject,
+		Body:  truncateRunes(rendered.Body, pushBodyLimit),
+		Data:  map[string]string{"kind": n.Kind},
+	}
+	for _, device := range devices {
+		if device.KeyID != n.KeyID || !wantsPush(device.Preferences, n.Kind) {
+			continue
+		}
+		sender, ok := p.senders[string(device.Platform)]
+		if !ok {
+			continue
+		}
+
+		err := sender.Send(ctx, device.Token, msg)
+		switch {
+		case err == nil:
+		case errors.Is(err, push.ErrUnregistered):
+			p.log.Infow("pruning unregistered device", "device", device.ID, "platform", device.Platform)
+			if err := p.devices.DeleteDevice(ctx, device.ID); err != nil && !errors.Is(err, ErrDeviceNotFound) {
+				p.log.Errorw("failed to prune device", "device", device.ID, "error", err)
+			}
+		default:
+			p.log.Warnw("failed to push notification", "device", device.ID, "platform", device.Platform, "kind", n.Kind, "error", err)
+		}
+	}
+	return nil
+}
+
+// wantsPush reports whether a device's preferences allow notifications of kind
+func wantsPush(prefs Preferences, kind string) bool {
+	switch kind {
+	case notify.KindAlert:
+		return prefs.Alerts
+	case notify.KindDigest:
+		return prefs.Digests
+	}
+	return false
+}
+
+// truncateRunes cuts s to at most limit runes, ending it with an ellipsis
+// when cut
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,173 @@
+package devices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for push device data operations
+type Repository interface {
+	GetDevice(ctx context.Context, id string) (*Device, error)
+	ListDevices(ctx context.Context) ([]Device, error)
+	PutDevice(ctx context.Context, device *Device) error
+	UpdatePreferences(ctx context.Context, id string, prefs Preferences, updatedUTC int64) error
+	DeleteDevice(ctx context.Context, id string) error
+}
+
+// deviceRepository implements Repository using DynamoDB
+type deviceRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed push device repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &deviceRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetDevice retrieves a single device by ID
+func (r *deviceRepository) GetDevice(ctx context.Context, id string) (*Device, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, id)
+	}
+
+	var device Device
+	if err := attributevalue.UnmarshalMap(result.Item, &device); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device: %w", err)
+	}
+
+	return &device, nil
+}
+
+// ListDevices retrieves all devices
+func (r *deviceRepository) ListDevices(ctx context.Context) ([]Device, error) {
+	var devices []Device
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan devices: %w", err)
+		}
+
+		var batch []Device
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal devices: %w", err)
+		}
+
+		devices = append(devices, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return devices, nil
+}
+
+// PutDevice creates or replaces a device
+func (r *deviceRepository) PutDevice(ctx context.Context, device *Device) error {
+	item, err := attributevalue.MarshalMap(device)
+	if err != nil {
+		return fmt.Errorf("failed to marshal device: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put device %s: %w", device.ID, err)
+	}
+
+	return nil
+}
+
+// UpdatePreferences replaces the notification preferences of an existing device
+func (r *deviceRepository) UpdatePreferences(ctx context.Context, id string, prefs Preferences, updatedUTC int64) error {
+	update := expression.Set(expression.Name("preferences"), expression.Value(prefs)).
+		Set(expression.Name("updatedUTC"), expression.Value(updatedUTC))
+	cond := expression.AttributeExists(expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrDeviceNotFound, id)
+		}
+		return fmt.Errorf("failed to update device %s preferences: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteDevice deletes a device, failing if it does not exist
+func (r *deviceRepository) DeleteDevice(ctx context.Context, id string) error {
+	cond := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrDeviceNotFound, id)
+		}
+		return fmt.Errorf("failed to delete device %s: %w", id, err)
+	}
+
+	return nil
+}
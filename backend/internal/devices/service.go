@@ -0,0 +1,178 @@
+package devices
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDeviceNotFound = errors.New("device not found")
+	ErrInvalidDevice  = errors.New("invalid device")
+	// ErrDeviceTaken rejects registering a token another API key registered;
+	// that key has to unregister it first
+	ErrDeviceTaken = errors.New("device is registered with another key")
+)
+
+type Service interface {
+	Register(ctx context.Context, device *Device, prefs *Preferences) (*Device, error)
+	GetDevice(ctx context.Context, id string) (*Device, error)
+	ListDevices(ctx context.Context) ([]Device, error)
+	UpdatePreferences(ctx context.Context, id string, prefs Preferences) (*Device, error)
+	Unregister(ctx context.Context, id string) error
+}
+
+type deviceService struct {
+	repo Repository
+	log  *zap.SugaredLogger
+}
+
+func NewService(repo Repository, log *zap.SugaredLogger) Service {
+	return &deviceService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Register stores a device's push token for the calling API key. Apps
+// register on every launch, so registering a known token updates its device
+// and keeps its preferences unless new ones are given. New devices receive
+// every notification by default. A token registered with another key is not
+// moved to the caller.
+func (s *deviceService) Register(ctx context.Context, device *Device, prefs *Preferences) (*Device, error) {
+	registered := *device
+	registered.Platform = Platform(strings.ToLower(string(registered.Platform)))
+	registered.Token = strings.TrimSpace(registered.Token)
+	registered.Name = strings.TrimSpace(registered.Name)
+	if err := registered.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDevice, err)
+	}
+
+	registered.ID = deviceID(registered.Platform, registered.Token)
+	registered.KeyID = service.CallerKeyID(ctx)
+
+	now := time.Now().Unix()
+	registered.CreatedUTC = now
+	registered.UpdatedUTC = now
+	registered.Preferences = Preferences{Alerts: true, Digests: true}
+
+	existing, err := s.repo.GetDevice(ctx, registered.ID)
+	switch {
+	case err == nil && existing.KeyID != registered.KeyID:
+		return nil, ErrDeviceTaken
+	case err == nil:
+		registered.CreatedUTC = existing.CreatedUTC
+		registered.Preferences = existing.Preferences
+	case !errors.Is(err, ErrDeviceNotFound):
+		logger.FromContext(ctx, s.log).Errorw("failed to get device", "device", registered.ID, "error", err)
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+	if prefs != nil {
+		registered.Preferences = *prefs
+	}
+
+	if err := s.repo.PutDevice(ctx, &registered); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to register device", "device", registered.ID, "platform", registered.Platform, "error", err)
+		return nil, fmt.Errorf("failed to register device: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("registered device", "device", registered.ID, "platform", registered.Platform, "new", existing == nil)
+	return &registered, nil
+}
+
+func (s *deviceService) GetDevice(ctx context.Context, id string) (*Device, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidDevice)
+	}
+
+	device, err := s.repo.GetDevice(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrDeviceNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get device", "device", id, "error", err)
+		return nil, fmt.Errorf("failed to get device: %w", err)
+	}
+	// Other keys' devices are reported missing so that their IDs cannot be
+	// probed
+	if device.KeyID != service.CallerKeyID(ctx) {
+		return nil, ErrDeviceNotFound
+	}
+
+	return device, nil
+}
+
+// ListDevices returns the devices registered with the calling key, oldest
+// first
+func (s *deviceService) ListDevices(ctx context.Context) ([]Device, error) {
+	all, err := s.repo.ListDevices(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list devices", "error", err)
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+
+	keyID := service.CallerKeyID(ctx)
+	devices := make([]Device, 0, len(all))
+	for _, device := range all {
+		if device.KeyID == keyID {
+			devices = append(devices, device)
+		}
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].CreatedUTC < devices[j].CreatedUTC
+	})
+	return devices, nil
+}
+
+func (s *deviceService) UpdatePreferences(ctx context.Context, id string, prefs Preferences) (*Device, error) {
+	device, err := s.GetDevice(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if err := s.repo.UpdatePreferences(ctx, id, prefs, now); err != nil {
+		if errors.Is(err, ErrDeviceNotFound) {
+			return nil, ErrDeviceNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to update device preferences", "device", id, "error", err)
+		return nil, fmt.Errorf("failed to update device preferences: %w", err)
+	}
+
+	device.Preferences = prefs
+	device.UpdatedUTC = now
+	return device, nil
+}
+
+func (s *deviceService) Unregister(ctx context.Context, id string) error {
+	if _, err := s.GetDevice(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteDevice(ctx, id); err != nil {
+		if errors.Is(err, ErrDeviceNotFound) {
+			return ErrDeviceNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to unregister device", "device", id, "error", err)
+		return fmt.Errorf("failed to unregister device: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("unregistered device", "device", id)
+	return nil
+}
+
+// deviceID derives a device's ID from its push token without exposing it
+func deviceID(platform Platform, token string) string {
+	sum := sha256.Sum256([]byte(string(platform) + ":" + token))
+	return hex.EncodeToString(sum[:16])
+}
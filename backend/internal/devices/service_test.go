@@ -0,0 +1,230 @@
+package devices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/push"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockRepository mocks the Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetDevice(ctx context.Context, id string) (*Device, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Device), args.Error(1)
+}
+
+func (m *MockRepository) ListDevices(ctx context.Context) ([]Device, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Device), args.Error(1)
+}
+
+func (m *MockRepository) PutDevice(ctx context.Context, device *Device) error {
+	return m.Called(ctx, device).Error(0)
+}
+
+func (m *MockRepository) UpdatePreferences(ctx context.Context, id string, prefs Preferences, updatedUTC int64) error {
+	return m.Called(ctx, id, prefs, updatedUTC).Error(0)
+}
+
+func (m *MockRepository) DeleteDevice(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+// recordingSender records pushed messages by token and fails for the tokens in errs
+type recordingSender struct {
+	sent map[string]push.Message
+	errs map[string]error
+}
+
+func (r *recordingSender) Send(ctx context.Context, token string, msg push.Message) error {
+	if err, ok := r.errs[token]; ok {
+		return err
+	}
+	if r.sent == nil {
+		r.sent = make(map[string]push.Message)
+	}
+	r.sent[token] = msg
+	return nil
+}
+
+func accountContext(tier models.PlanTier, admin bool) context.Context {
+	return service.WithAccount(context.Background(), &models.APIKey{ID: "key", Name: "test", Tier: tier, Admin: admin})
+}
+
+func TestService_Register(t *testing.T) {
+	ctx := accountContext(models.PlanFree, false)
+	id := deviceID(PlatformIOS, "token")
+	optOut := &Preferences{Alerts: true}
+
+	tests := []struct {
+		name      string
+		device    Device
+		prefs     *Preferences
+		existing  *Device
+		wantPrefs Preferences
+		wantErr   error
+	}{
+		{
+			name:      "new devices receive everything",
+			device:    Device{Platform: "IOS", Token: " token ", Name: "iPhone"},
+			wantPrefs: Preferences{Alerts: true, Digests: true},
+		},
+		{
+			name:      "new devices take the given preferences",
+			device:    Device{Platform: PlatformIOS, Token: "token"},
+			prefs:     optOut,
+			wantPrefs: *optOut,
+		},
+		{
+			name:      "re-registering keeps preferences",
+			device:    Device{Platform: PlatformIOS, Token: "token"},
+			existing:  &Device{ID: id, Preferences: *optOut, CreatedUTC: 100, KeyID: "key"},
+			wantPrefs: *optOut,
+		},
+		{
+			name:     "rejects tokens registered with another key",
+			device:   Device{Platform: PlatformIOS, Token: "token"},
+			existing: &Device{ID: id, CreatedUTC: 100, KeyID: "other"},
+			wantErr:  ErrDeviceTaken,
+		},
+		{
+			name:    "rejects unknown platforms",
+			device:  Device{Platform: "windows", Token: "token"},
+			wantErr: ErrInvalidDevice,
+		},
+		{
+			name:    "rejects missing tokens",
+			device:  Device{Platform: PlatformAndroid, Token: " "},
+			wantErr: ErrInvalidDevice,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			if tt.existing != nil {
+				repo.On("GetDevice", mock.Anything, id).Return(tt.existing, nil)
+			} else {
+				repo.On("GetDevice", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("%w: %s", ErrDeviceNotFound, id))
+			}
+			repo.On("PutDevice", mock.Anything, mock.Anything).Return(nil)
+			svc := NewService(repo, zap.NewNop().Sugar())
+
+			device, err := svc.Register(ctx, &tt.device, tt.prefs)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "PutDevice", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, id, device.ID, "IDs derive from the platform and token")
+			assert.Equal(t, "token", device.Token)
+			assert.Equal(t, "key", device.KeyID)
+			assert.Equal(t, tt.wantPrefs, device.Preferences)
+			if tt.existing != nil {
+				assert.Equal(t, int64(100), device.CreatedUTC)
+			}
+		})
+	}
+}
+
+func TestService_ScopesDevicesToTheCallingKey(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetDevice", mock.Anything, "mine").Return(&Device{ID: "mine", KeyID: "key"}, nil)
+	repo.On("GetDevice", mock.Anything, "theirs").Return(&Device{ID: "theirs", KeyID: "other"}, nil)
+	repo.On("ListDevices", mock.Anything).Return([]Device{{ID: "mine", KeyID: "key"}, {ID: "theirs", KeyID: "other"}}, nil)
+	repo.On("UpdatePreferences", mock.Anything, "mine", mock.Anything, mock.Anything).Return(nil)
+	repo.On("DeleteDevice", mock.Anything, "mine").Return(nil)
+	svc := NewService(repo, zap.NewNop().Sugar())
+	ctx := accountContext(models.PlanFree, false)
+
+	devices, err := svc.ListDevices(ctx)
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	assert.Equal(t, "mine", devices[0].ID)
+
+	_, err = svc.GetDevice(ctx, "theirs")
+	assert.ErrorIs(t, err, ErrDeviceNotFound)
+	_, err = svc.UpdatePreferences(ctx, "theirs", Preferences{})
+	assert.ErrorIs(t, err, ErrDeviceNotFound)
+	assert.ErrorIs(t, svc.Unregister(ctx, "theirs"), ErrDeviceNotFound)
+
+	_, err = svc.UpdatePreferences(ctx, "mine", Preferences{Alerts: true})
+	assert.NoError(t, err)
+	assert.NoError(t, svc.Unregister(ctx, "mine"))
+	repo.AssertNotCalled(t, "UpdatePreferences", mock.Anything, "theirs", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "DeleteDevice", mock.Anything, "theirs")
+}
+
+func TestPushNotifier(t *testing.T) {
+	devices := []Device{
+		{ID: "phone", Platform: PlatformIOS, Token: "phone", Preferences: Preferences{Alerts: true, Digests: true}, KeyID: "key"},
+		{ID: "tablet", Platform: PlatformAndroid, Token: "tablet", Preferences: Preferences{Digests: true}, KeyID: "key"},
+		{ID: "gone", Platform: PlatformAndroid, Token: "gone", Preferences: Preferences{Alerts: true}, KeyID: "key"},
+		{ID: "broken", Platform: PlatformAndroid, Token: "broken", Preferences: Preferences{Alerts: true}, KeyID: "key"},
+		{ID: "other", Platform: PlatformIOS, Token: "other", Preferences: Preferences{Alerts: true}, KeyID: "other"},
+	}
+
+	repo := new(MockRepository)
+	repo.On("ListDevices", mock.Anything).Return(devices, nil)
+	repo.On("DeleteDevice", mock.Anything, "gone").Return(nil)
+
+	ios := &recordingSender{}
+	android := &recordingSender{errs: map[string]error{
+		"gone":   push.ErrUnregistered,
+		"broken": errors.New("fcm unavailable"),
+	}}
+	notifier := NewPushNotifier(repo, push.Senders{push.PlatformIOS: ios, push.PlatformAndroid: android}, nil, zap.NewNop().Sugar())
+
+	err := notifier.Notify(context.Background(), notify.Notification{
+		Kind:    notify.KindAlert,
+		Subject: "AAPL closed at 201.00, at or above 200",
+		Body:    strings.Repeat("x", 1000),
+		KeyID:   "key",
+	})
+	require.NoError(t, err, "push failures are not returned")
+
+	require.Contains(t, ios.sent, "phone")
+	assert.NotContains(t, ios.sent, "other", "devices of other keys are skipped")
+	assert.NotContains(t, android.sent, "tablet", "devices opted out of alerts are skipped")
+	assert.Equal(t, "AAPL closed at 201.00, at or above 200", ios.sent["phone"].Title)
+	assert.Len(t, []rune(ios.sent["phone"].Body), pushBodyLimit)
+	assert.Equal(t, notify.KindAlert, ios.sent["phone"].Data["kind"])
+	repo.AssertCalled(t, "DeleteDevice", mock.Anything, "gone")
+	repo.AssertNotCalled(t, "DeleteDevice", mock.Anything, "broken")
+
+	t.Run("digests reach devices opted in to them", func(t *testing.T) {
+		err := notifier.Notify(context.Background(), notify.Notification{Kind: notify.KindDigest, Subject: "digest", KeyID: "key"})
+		require.NoError(t, err)
+		assert.Contains(t, android.sent, "tablet")
+	})
+
+	t.Run("other kinds are not pushed", func(t *testing.T) {
+		repo := new(MockRepository)
+		notifier := NewPushNotifier(repo, push.Senders{push.PlatformIOS: ios}, nil, zap.NewNop().Sugar())
+		require.NoError(t, notifier.Notify(context.Background(), notify.Notification{Kind: "report"}))
+		repo.AssertNotCalled(t, "ListDevices", mock.Anything)
+	})
+}
@@ -0,0 +1,171 @@
+package digests
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/schedule"
+)
+
+// Frequency is how often a digest subscription is sent
+type Frequency string
+
+const (
+	// Daily is sent after every trading day's close
+	Daily Frequency = "daily"
+	// Weekly is sent after Friday's close and covers five sessions
+	Weekly Frequency = "weekly"
+)
+
+// MaxWatchlists bounds the watchlists of a digest subscription
+const MaxWatchlists = 20
+
+// scheduledLateness is how long after it is due a scheduled digest is still
+// sent, retrying failures or catching up on a leader change; a later one is
+// skipped as stale
+const scheduledLateness = 2 * time.Hour
+
+// Sessions returns how many trading sessions a digest of the frequency covers
+func (f Frequency) Sessions() int {
+	if f == Weekly {
+		return 5
+	}
+	return 1
+}
+
+// Due reports whether a digest of the frequency is sent for the trading day date
+func (f Frequency) Due(date time.Time) bool {
+	switch f {
+	case Daily:
+		return true
+	case Weekly:
+		return date.Weekday() == time.Friday
+	}
+	return false
+}
+
+// Subscription opts an email address in to a periodic summary of
+// watchlists. No watchlist IDs means every watchlist of the subscribing key.
+// Digests are only sent once the address confirmed the subscription.
+type Subscription struct {
+	ID           string    `json:"id" dynamodbav:"id"`
+	Email        string    `json:"email" dynamodbav:"email"`
+	Frequency    Frequency `json:"frequency" dynamodbav:"frequency"`
+	WatchlistIDs []string  `json:"watchlistIds" dynamodbav:"watchlistIds,omitempty"`
+	CreatedUTC   int64     `json:"createdUTC" dynamodbav:"createdUTC"`
+	Confirmed    bool      `json:"confirmed" dynamodbav:"confirmed"`
+	// LastSentDate is the trading day (YYYY-MM-DD) of the last digest sent
+	LastSentDate string `json:"lastSentDate,omitempty" dynamodbav:"lastSentDate,omitempty"`
+	// Schedule sends the digest at a local time in the subscriber's time
+	// zone, like 08:00 in America/Toronto, instead of right after the close
+	Schedule *schedule.Schedule `json:"schedule,omitempty" dynamodbav:"schedule,omitempty"`
+	// NextSendUTC is when the next digest of a confirmed, scheduled
+	// subscription is due; it is computed on reading, not stored
+	NextSendUTC int64 `json:"nextSendUTC,omitempty" dynamodbav:"-"`
+	// KeyID is the API key that subscribed; only the watchlists and alerts it
+	// created are included
+	KeyID string `json:"-" dynamodbav:"keyId,omitempty"`
+}
+
+// Validate checks if the subscription data is valid
+func (s *Subscription) Validate() error {
+	if s.Email == "" {
+		return fmt.Errorf("email is required")
+	}
+
+	if _, err := mail.ParseAddress(s.Email); err != nil {
+		return fmt.Errorf("email is not a valid address")
+	}
+
+	switch s.Frequency {
+	case Daily, Weekly:
+	default:
+		return fmt.Errorf("frequency must be %s or %s", Daily, Weekly)
+	}
+
+	if len(s.WatchlistIDs) > MaxWatchlists {
+		return fmt.Errorf("a digest covers at most %d watchlists", MaxWatchlists)
+	}
+
+	if s.Schedule != nil {
+		if err := s.Schedule.Validate(); err != nil {
+			return fmt.Errorf("schedule: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// dueAt returns when the scheduled digest of the trading day date is due: at
+// the first run of the subscription's schedule once the day's bars are ready,
+// readyAt after midnight market time
+func (s *Subscription) dueAt(date time.Time, readyAt time.Duration) (time.Time, error) {
+	return s.Schedule.Next(readyOn(date, readyAt).Add(-time.Nanosecond))
+}
+
+// nextSend returns when the scheduled digest following the last one sent is
+// due, which is before now while it waits to be sent
+func (s *Subscription) nextSend(now time.Time, readyAt time.Duration) (time.Time, error) {
+	date := lastReadyDay(now, readyAt)
+	for {
+		if s.Frequency.Due(date) && s.LastSentDate != date.Format(models.DateLayout) {
+			due, err := s.dueAt(date, readyAt)
+			if err != nil || now.Sub(due) <= scheduledLateness {
+				return due, err
+			}
+		}
+		date = marketcalendar.NextTradingDay(date)
+	}
+}
+
+// readyOn returns when the bars of the trading day date are ready
+func readyOn(date time.Time, readyAt time.Duration) time.Time {
+	y, m, d := date.Date()
+	// Wall-clock arithmetic keeps the time right on DST changes
+	return time.Date(y, m, d, 0, 0, int(readyAt/time.Second), 0, marketcalendar.Location())
+}
+
+// lastReadyDay returns the latest trading day whose bars are ready at now, as
+// a UTC date
+func lastReadyDay(now time.Time, readyAt time.Duration) time.Time {
+	y, m, d := now.In(marketcalendar.Location()).Date()
+	date := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	if !marketcalendar.IsTradingDay(date) || readyOn(date, readyAt).After(now) {
+		date = marketcalendar.PreviousTradingDay(date)
+	}
+	return date
+}
+
+// Move is a symbol's move over the sessions a digest covers
+type Move struct {
+	Symbol        string  `json:"symbol"`
+	Close         float32 `json:"close"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"changePercent"`
+}
+
+// WatchlistDigest summarizes one watchlist. Gainers and losers are ranked by
+// percent change, biggest changes by absolute percent change.
+type WatchlistDigest struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Gainers        []Move `json:"gainers"`
+	Losers         []Move `json:"losers"`
+	BiggestChanges []Move `json:"biggestChanges"`
+	// Missing lists symbols without enough daily summaries
+	Missing []string `json:"missing"`
+}
+
+// Digest is the summary sent to a subscription for a trading day
+type Digest struct {
+	Frequency       Frequency         `json:"frequency"`
+	Date            string            `json:"date"`
+	Watchlists      []WatchlistDigest `json:"watchlists"`
+	TriggeredAlerts []alerts.Alert    `json:"triggeredAlerts"`
+	// UnsubscribeURL is the signed link deleting the subscription
+	UnsubscribeURL string `json:"unsubscribeUrl"`
+}
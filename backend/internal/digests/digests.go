@@ -0,0 +1,153 @@
+package digests
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/schedule"
+
+	"github.com/gin-gonic/gin"
+)
+
+type digestRequest struct {
+	Email        string             `json:"email"`
+	Frequency    Frequency          `json:"frequency"`
+	WatchlistIDs []string           `json:"watchlistIds"`
+	Schedule     *schedule.Schedule `json:"schedule,omitempty"`
+}
+
+func (h *Handler) ListDigests(c *gin.Context) {
+	subs, err := h.digestService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"digests": subs,
+		"count":   len(subs),
+	})
+}
+
+func (h *Handler) CreateDigest(c *gin.Context) {
+	var req digestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	sub, err := h.digestService.Subscribe(c.Request.Context(), &Subscription{
+		Email:        req.Email,
+		Frequency:    req.Frequency,
+		WatchlistIDs: req.WatchlistIDs,
+		Schedule:     req.Schedule,
+	})
+	if err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+func (h *Handler) GetDigest(c *gin.Context) {
+	sub, err := h.digestService.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// PreviewDigest builds and renders a subscription's digest for ?date=YYYY-MM-DD,
+// today by default, without sending it
+func (h *Handler) PreviewDigest(c *gin.Context) {
+	date, err := api.ParseDateQuery(c, "date")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if date.IsZero() {
+		date = time.Now().UTC().Truncate(24 * time.Hour)
+	}
+
+	ctx := c.Request.Context()
+	sub, err := h.digestService.GetSubscription(ctx, c.Param("id"))
+	if err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	digest, err := h.digestService.BuildDigest(ctx, sub, date)
+	if err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	subject, body, err := h.digestService.RenderDigest(digest)
+	if err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"digest":  digest,
+		"subject": subject,
+		"body":    body,
+	})
+}
+
+func (h *Handler) DeleteDigest(c *gin.Context) {
+	if err := h.digestService.Unsubscribe(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ConfirmDigest confirms a subscription from the link mailed on subscribing
+func (h *Handler) ConfirmDigest(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.digestService.Confirm(c.Request.Context(), id, c.Query("token")); err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":     id,
+		"status": "confirmed",
+	})
+}
+
+// UnsubscribeDigest deletes a subscription from the link in its digests
+func (h *Handler) UnsubscribeDigest(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.digestService.UnsubscribeByLink(c.Request.Context(), id, c.Query("token")); err != nil {
+		h.respondDigestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":     id,
+		"status": "unsubscribed",
+	})
+}
+
+func (h *Handler) respondDigestError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrDigestNotFound):
+		problem.Respond(c, problem.DigestNotFound, "Digest subscription not found")
+	case errors.Is(err, ErrInvalidDigestLink):
+		problem.Respond(c, problem.Forbidden, "Invalid digest link")
+	case errors.Is(err, ErrInvalidDigest):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("digest request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process digest request")
+	}
+}
@@ -0,0 +1,61 @@
+package digests
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// Purposes of the signed links mailed to subscribers. A token signs a purpose
+// and a subscription ID, so a confirmation link cannot unsubscribe.
+const (
+	purposeConfirm     = "confirm"
+	purposeUnsubscribe = "unsubscribe"
+)
+
+// Links builds and verifies the signed confirmation and unsubscribe links
+// mailed to subscribers, which are followed without an API key
+type Links struct {
+	baseURL string
+	secret  []byte
+}
+
+// NewLinks signs links to the public routes served at baseURL with secret
+func NewLinks(baseURL string, secret []byte) *Links {
+	return &Links{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}
+}
+
+// ConfirmURL returns the link confirming a subscription
+func (l *Links) ConfirmURL(id string) string {
+	return l.url(purposeConfirm, id)
+}
+
+// UnsubscribeURL returns the link deleting a subscription
+func (l *Links) UnsubscribeURL(id string) string {
+	return l.url(purposeUnsubscribe, id)
+}
+
+// Verify reports whether token signs purpose for the subscription id
+func (l *Links) Verify(purpose, id, token string) bool {
+	sig, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(sig, l.sign(purpose, id))
+}
+
+func (l *Links) url(purpose, id string) string {
+	token := base64.RawURLEncoding.EncodeToString(l.sign(purpose, id))
+	return l.baseURL + "/api/v1/public/digests/" + url.PathEscape(id) + "/" + purpose + "?token=" + token
+}
+
+func (l *Links) sign(purpose, id string) []byte {
+	mac := hmac.New(sha256.New, l.secret)
+	mac.Write([]byte(purpose + ":" + id))
+	return mac.Sum(nil)
+}
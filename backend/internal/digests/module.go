@@ -0,0 +1,194 @@
+// Package digests serves the opt-in email digests of watchlist performance and
+// sends the ones due after each trading day's close.
+package digests
+
+import (
+	"context"
+	"crypto/rand"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/devices"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	digestService Service
+	// interval is how often RunScheduler sends the scheduled digests due
+	interval time.Duration
+	log      *zap.SugaredLogger
+}
+
+func NewHandler(digests Service, interval time.Duration, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		digestService: digests,
+		interval:      interval,
+		log:           log,
+	}
+}
+
+// Wire builds the digests module from the shared dependencies. Digests are
+// mailed when an SMTP server is configured and only logged otherwise, and
+// pushed to registered devices when push is configured.
+func Wire(deps app.Deps) *Handler {
+	cfg := deps.Config
+
+	secret := []byte(cfg.DigestLinkSecret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		rand.Read(secret)
+		deps.Log.Warnw("DIGEST_LINK_SECRET is not set; mailed digest links stop working after a restart")
+	}
+
+	notifier := notify.Log(deps.Log)
+	if cfg.SMTPHost != "" {
+		notifier = notify.Email(notify.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.DigestFrom,
+			Timeout:  cfg.SMTPTimeout,
+		}, deps.Templates)
+	}
+
+	return NewHandler(NewService(
+		NewRepository(deps.DB, cfg.DigestsTable),
+		deps.APIKeyRepository(),
+		watchlists.NewRepository(deps.DB, cfg.WatchlistsTable),
+		service.NewDailySummaryService(deps.DailySummaryRepository(), deps.Log),
+		alerts.NewRepository(deps.DB, cfg.AlertsTable),
+		NewLinks(cfg.PublicBaseURL, secret),
+		cfg.PostCloseJobsAt,
+		devices.WithPush(deps, notifier),
+		deps.Templates,
+		deps.Log,
+	), cfg.DigestScheduleInterval, deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	digests := api.Group("/digests", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms())
+	digests.GET("", h.ListDigests)
+	digests.POST("", h.CreateDigest)
+	digests.GET("/:id", h.GetDigest)
+	digests.GET("/:id/preview", h.PreviewDigest)
+	digests.DELETE("/:id", h.DeleteDigest)
+}
+
+// RegisterPublicRoutes serves the signed links mailed to subscribers
+func (h *Handler) RegisterPublicRoutes(public *gin.RouterGroup) {
+	digests := public.Group("/digests")
+	digests.GET("/:id/confirm", h.ConfirmDigest)
+	digests.GET("/:id/unsubscribe", h.UnsubscribeDigest)
+}
+
+// PostCloseJobs returns the job sending the unscheduled digests due for the
+// trading day
+func (h *Handler) PostCloseJobs() []jobs.Job {
+	return []jobs.Job{
+		jobs.NewJob("watchlist-digests", func(ctx context.Context, date time.Time) error {
+			_, err := h.digestService.SendDue(ctx, date)
+			return err
+		}),
+	}
+}
+
+// RunScheduler sends the scheduled digests due every interval until ctx is
+// done. It runs on the leader only; a failed round is logged and its digests
+// retried at the next tick. A non-positive interval disables scheduled
+// digests.
+func (h *Handler) RunScheduler(ctx context.Context) {
+	if h.interval <= 0 {
+		h.log.Warnw("scheduled digests disabled", "interval", h.interval)
+		return
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := h.digestService.SendScheduled(ctx, now); err != nil && ctx.Err() == nil {
+				h.log.Errorw("scheduled digests failed", "error", err)
+			}
+		}
+	}
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Digests"}
+	id := openapi.PathParam("id", "Digest subscription ID")
+
+	doc.Add(http.MethodGet, "/api/v1/digests", &openapi.Operation{
+		Tags:      tags,
+		Summary:   "List digest subscriptions",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "digests", Subscription{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/digests", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Subscribe to a daily or weekly watchlist digest",
+		Description: "No watchlist IDs means every watchlist of the calling key. The address is mailed a confirmation link and receives no digest until it confirms. " +
+			"Digests are sent after each close, weekly ones after Friday's, or with a schedule at the first localTime (HH:MM) in its IANA timezone once the close's bars are ready, following the zone across daylight saving time changes.",
+		RequestBody: openapi.JSONBody(doc.Inline(digestRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(Subscription{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/digests/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get a digest subscription",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(Subscription{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/digests/:id/preview", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Build and render a subscription's digest without sending it",
+		Parameters: []openapi.Parameter{
+			id,
+			openapi.QueryParam("date", "Trading day of the digest, YYYY-MM-DD (defaults to today)", api.DateSchema),
+		},
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"digest":  doc.Schema(Digest{}),
+			"subject": {Type: "string"},
+			"body":    {Type: "string"},
+		}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/digests/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Unsubscribe from a digest",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+
+	token := openapi.QueryParam("token", "Signature of the mailed link", nil)
+	status := openapi.Object(map[string]*openapi.Schema{
+		"id":     {Type: "string"},
+		"status": {Type: "string"},
+	})
+	doc.Add(http.MethodGet, "/api/v1/public/digests/:id/confirm", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Confirm a digest subscription from its mailed link",
+		Description: "Served without an API key; the token signs the subscription ID.",
+		Parameters:  []openapi.Parameter{id, token},
+		Responses:   api.Responses(http.StatusOK, status, http.StatusForbidden, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/public/digests/:id/unsubscribe", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Unsubscribe from a digest from the link in its emails",
+		Description: "Served without an API key; the token signs the subscription ID.",
+		Parameters:  []openapi.Parameter{id, token},
+		Responses:   api.Responses(http.StatusOK, status, http.StatusForbidden, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,205 @@
+package digests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for digest subscription data operations
+type Repository interface {
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	PutSubscription(ctx context.Context, sub *Subscription) error
+	Confirm(ctx context.Context, id string) error
+	MarkSent(ctx context.Context, id, date string) error
+	DeleteSubscription(ctx context.Context, id string) error
+}
+
+// digestRepository implements Repository using DynamoDB
+type digestRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed digest subscription repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &digestRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetSubscription retrieves a single subscription by ID
+func (r *digestRepository) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get digest subscription %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDigestNotFound, id)
+	}
+
+	var sub Subscription
+	if err := attributevalue.UnmarshalMap(result.Item, &sub); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal digest subscription: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// ListSubscriptions retrieves all subscriptions
+func (r *digestRepository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	var subs []Subscription
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan digest subscriptions: %w", err)
+		}
+
+		var batch []Subscription
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal digest subscriptions: %w", err)
+		}
+
+		subs = append(subs, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return subs, nil
+}
+
+// PutSubscription creates or replaces a subscription
+func (r *digestRepository) PutSubscription(ctx context.Context, sub *Subscription) error {
+	item, err := attributevalue.MarshalMap(sub)
+	if err != nil {
+		return fmt.Errorf("failed to marshal digest subscription: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put digest subscription %s: %w", sub.ID, err)
+	}
+
+	return nil
+}
+
+// Confirm marks an existing subscription confirmed by its address
+func (r *digestRepository) Confirm(ctx context.Context, id string) error {
+	update := expression.Set(expression.Name("confirmed"), expression.Value(true))
+	cond := expression.AttributeExists(expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrDigestNotFound, id)
+		}
+		return fmt.Errorf("failed to confirm digest subscription %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// MarkSent records the trading day of the last digest sent to an existing
+// subscription
+func (r *digestRepository) MarkSent(ctx context.Context, id, date string) error {
+	update := expression.Set(expression.Name("lastSentDate"), expression.Value(date))
+	cond := expression.AttributeExists(expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrDigestNotFound, id)
+		}
+		return fmt.Errorf("failed to mark digest subscription %s sent: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteSubscription deletes a subscription, failing if it does not exist
+func (r *digestRepository) DeleteSubscription(ctx context.Context, id string) error {
+	cond := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrDigestNotFound, id)
+		}
+		return fmt.Errorf("failed to delete digest subscription %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,578 @@
+package digests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/schedule"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/notify"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrDigestNotFound = errors.New("digest subscription not found")
+	ErrInvalidDigest  = errors.New("invalid digest subscription")
+	// ErrInvalidDigestLink rejects confirmation and unsubscribe links whose
+	// token does not sign the subscription
+	ErrInvalidDigestLink = errors.New("invalid digest link")
+)
+
+// digestTopMoves bounds the gainers, losers and biggest changes listed per watchlist
+const digestTopMoves = 3
+
+type Service interface {
+	Subscribe(ctx context.Context, sub *Subscription) (*Subscription, error)
+	GetSubscription(ctx context.Context, id string) (*Subscription, error)
+	ListSubscriptions(ctx context.Context) ([]Subscription, error)
+	Unsubscribe(ctx context.Context, id string) error
+	// Confirm and UnsubscribeByLink serve the signed links mailed to the
+	// subscribed address
+	Confirm(ctx context.Context, id, token string) error
+	UnsubscribeByLink(ctx context.Context, id, token string) error
+	BuildDigest(ctx context.Context, sub *Subscription, date time.Time) (*Digest, error)
+	RenderDigest(digest *Digest) (subject, body string, err error)
+	SendDue(ctx context.Context, date time.Time) (int, error)
+	SendScheduled(ctx context.Context, now time.Time) (int, error)
+}
+
+type digestService struct {
+	repo       Repository
+	keys       repository.APIKeyRepository
+	watchlists watchlists.Repository
+	summaries  service.DailySummaryService
+	alerts     alerts.Repository
+	links      *Links
+	// readyAt is the time after midnight, market time, scheduled digests of
+	// a trading day wait for
+	readyAt   time.Duration
+	notifier  notify.Notifier
+	templates *notify.Templates
+	log       *zap.SugaredLogger
+}
+
+// NewService mails digests and confirmations through notifier, composed with
+// templates. Scheduled digests of a trading day are sent from readyAt after
+// midnight market time, once the post-close jobs have stored its bars.
+// Subscriptions of deleted accounts, looked up in keys, are not sent.
+func NewService(repo Repository, keys repository.APIKeyRepository, lists watchlists.Repository, summaries service.DailySummaryService, alertRepo alerts.Repository, links *Links, readyAt time.Duration, notifier notify.Notifier, templates *notify.Templates, log *zap.SugaredLogger) Service {
+	return &digestService{
+		repo:       repo,
+		keys:       keys,
+		watchlists: lists,
+		summaries:  summaries,
+		alerts:     alertRepo,
+		links:      links,
+		readyAt:    readyAt,
+		notifier:   notifier,
+		templates:  templates,
+		log:        log,
+	}
+}
+
+// Subscribe opts an email address in to a digest of the given watchlists,
+// which must belong to the caller, and mails the address a link confirming
+// the subscription. Nothing is sent to the address until it confirms.
+func (s *digestService) Subscribe(ctx context.Context, sub *Subscription) (*Subscription, error) {
+	created := *sub
+	created.Email = strings.TrimSpace(created.Email)
+	created.Frequency = Frequency(strings.ToLower(string(created.Frequency)))
+	if created.Schedule != nil {
+		created.Schedule = &schedule.Schedule{
+			Timezone:  strings.TrimSpace(created.Schedule.Timezone),
+			LocalTime: strings.TrimSpace(created.Schedule.LocalTime),
+		}
+	}
+	created.KeyID = service.CallerKeyID(ctx)
+	if err := created.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidDigest, err)
+	}
+
+	for _, id := range created.WatchlistIDs {
+		watchlist, err := s.watchlists.GetWatchlist(ctx, id)
+		if err != nil && !errors.Is(err, watchlists.ErrWatchlistNotFound) {
+			return nil, fmt.Errorf("failed to get watchlist: %w", err)
+		}
+		if err != nil || watchlist.KeyID != created.KeyID {
+			return nil, fmt.Errorf("%w: watchlist %s not found", ErrInvalidDigest, id)
+		}
+	}
+
+	id, err := service.NewID()
+	if err != nil {
+		return nil, err
+	}
+	created.ID = id
+	created.CreatedUTC = time.Now().Unix()
+	created.Confirmed = false
+	created.LastSentDate = ""
+	created.NextSendUTC = 0
+
+	if err := s.repo.PutSubscription(ctx, &created); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to create digest subscription", "frequency", created.Frequency, "error", err)
+		return nil, fmt.Errorf("failed to create digest subscription: %w", err)
+	}
+
+	confirmation, err := s.confirmationNotification(&created, s.links.ConfirmURL(id))
+	if err == nil {
+		err = s.notifier.Notify(ctx, confirmation)
+	}
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to send digest confirmation", "subscription", id, "error", err)
+		if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to delete unconfirmed digest subscription", "subscription", id, "error", err)
+		}
+		return nil, fmt.Errorf("failed to send digest confirmation: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created digest subscription", "subscription", id, "frequency", created.Frequency, "watchlists", len(created.WatchlistIDs))
+	return &created, nil
+}
+
+// confirmation is the data of the email confirming a subscription
+type confirmation struct {
+	Frequency  Frequency `json:"frequency"`
+	ConfirmURL string    `json:"confirmUrl"`
+}
+
+// confirmationNotification asks the subscribed address to confirm a subscription
+func (s *digestService) confirmationNotification(sub *Subscription, confirmURL string) (notify.Notification, error) {
+	n, err := s.templates.Compose(notify.KindDigestConfirmation, confirmation{Frequency: sub.Frequency, ConfirmURL: confirmURL})
+	if err != nil {
+		return notify.Notification{}, err
+	}
+	n.SentUTC = time.Now().Unix()
+	n.To = []string{sub.Email}
+	n.KeyID = sub.KeyID
+	return n, nil
+}
+
+func (s *digestService) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidDigest)
+	}
+
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrDigestNotFound) {
+			return nil, ErrDigestNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get digest subscription", "subscription", id, "error", err)
+		return nil, fmt.Errorf("failed to get digest subscription: %w", err)
+	}
+	if sub.KeyID != service.CallerKeyID(ctx) {
+		return nil, ErrDigestNotFound
+	}
+
+	s.setNextSend(sub, time.Now())
+	return sub, nil
+}
+
+// ListSubscriptions returns the caller's subscriptions, oldest first
+func (s *digestService) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	all, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list digest subscriptions", "error", err)
+		return nil, fmt.Errorf("failed to list digest subscriptions: %w", err)
+	}
+
+	keyID := service.CallerKeyID(ctx)
+	subs := make([]Subscription, 0, len(all))
+	now := time.Now()
+	for _, sub := range all {
+		if sub.KeyID == keyID {
+			s.setNextSend(&sub, now)
+			subs = append(subs, sub)
+		}
+	}
+
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].CreatedUTC < subs[j].CreatedUTC
+	})
+	return subs, nil
+}
+
+// setNextSend sets when the next digest of a confirmed, scheduled
+// subscription is due
+func (s *digestService) setNextSend(sub *Subscription, now time.Time) {
+	if !sub.Confirmed || sub.Schedule == nil {
+		return
+	}
+	if next, err := sub.nextSend(now, s.readyAt); err == nil {
+		sub.NextSendUTC = next.Unix()
+	}
+}
+
+func (s *digestService) Unsubscribe(ctx context.Context, id string) error {
+	if _, err := s.GetSubscription(ctx, id); err != nil {
+		return err
+	}
+	return s.delete(ctx, id)
+}
+
+// Confirm confirms the subscription id when token signs its confirmation link
+func (s *digestService) Confirm(ctx context.Context, id, token string) error {
+	if !s.links.Verify(purposeConfirm, id, token) {
+		return ErrInvalidDigestLink
+	}
+
+	if err := s.repo.Confirm(ctx, id); err != nil {
+		if errors.Is(err, ErrDigestNotFound) {
+			return ErrDigestNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to confirm digest subscription", "subscription", id, "error", err)
+		return fmt.Errorf("failed to confirm digest subscription: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("confirmed digest subscription", "subscription", id)
+	return nil
+}
+
+// UnsubscribeByLink deletes the subscription id when token signs the
+// unsubscribe link of its digests
+func (s *digestService) UnsubscribeByLink(ctx context.Context, id, token string) error {
+	if !s.links.Verify(purposeUnsubscribe, id, token) {
+		return ErrInvalidDigestLink
+	}
+	return s.delete(ctx, id)
+}
+
+func (s *digestService) delete(ctx context.Context, id string) error {
+	if err := s.repo.DeleteSubscription(ctx, id); err != nil {
+		if errors.Is(err, ErrDigestNotFound) {
+			return ErrDigestNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to delete digest subscription", "subscription", id, "error", err)
+		return fmt.Errorf("failed to delete digest subscription: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("deleted digest subscription", "subscription", id)
+	return nil
+}
+
+// BuildDigest summarizes the subscription's watchlists over the sessions its
+// frequency covers, up to the trading day date
+func (s *digestService) BuildDigest(ctx context.Context, sub *Subscription, date time.Time) (*Digest, error) {
+	return s.build(ctx, sub, date, make(map[string]*Move))
+}
+
+// build is BuildDigest sharing moves, keyed by symbol and sessions, across the
+// digests of one run
+func (s *digestService) build(ctx context.Context, sub *Subscription, date time.Time, moves map[string]*Move) (*Digest, error) {
+	lists, err := s.digestWatchlists(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := &Digest{
+		Frequency:       sub.Frequency,
+		Date:            date.Format(models.DateLayout),
+		Watchlists:      make([]WatchlistDigest, 0, len(lists)),
+		TriggeredAlerts: make([]alerts.Alert, 0),
+		UnsubscribeURL:  s.links.UnsubscribeURL(sub.ID),
+	}
+
+	sessions := sub.Frequency.Sessions()
+	for _, watchlist := range lists {
+		wd := WatchlistDigest{
+			ID:      watchlist.ID,
+			Name:    watchlist.Name,
+			Missing: make([]string, 0),
+		}
+
+		var listed []Move
+		for _, symbol := range watchlist.Symbols {
+			key := fmt.Sprintf("%s#%d", symbol, sessions)
+			move, ok := moves[key]
+			if !ok {
+				move, err = s.move(ctx, symbol, sessions, date)
+				if err != nil {
+					return nil, err
+				}
+				moves[key] = move
+			}
+			if move == nil {
+				wd.Missing = append(wd.Missing, symbol)
+				continue
+			}
+			listed = append(listed, *move)
+		}
+
+		wd.Gainers, wd.Losers, wd.BiggestChanges = rankMoves(listed)
+		digest.Watchlists = append(digest.Watchlists, wd)
+	}
+
+	// Alerts triggered over the calendar days the digest covers
+	days := 1
+	if sub.Frequency == Weekly {
+		days = 7
+	}
+	since := date.AddDate(0, 0, -days).Unix()
+
+	triggered, err := s.alerts.ListAlerts(ctx, alerts.StatusTriggered)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list triggered alerts: %w", err)
+	}
+	for _, alert := range triggered {
+		if alert.TriggeredUTC < since || alert.KeyID != sub.KeyID {
+			continue
+		}
+		digest.TriggeredAlerts = append(digest.TriggeredAlerts, alert)
+	}
+	sort.Slice(digest.TriggeredAlerts, func(i, j int) bool {
+		return digest.TriggeredAlerts[i].TriggeredUTC < digest.TriggeredAlerts[j].TriggeredUTC
+	})
+
+	return digest, nil
+}
+
+// digestWatchlists returns the subscription's watchlists, or every watchlist
+// of the subscribing key when it names none. Watchlists deleted since
+// subscribing, or not owned by the key, are skipped.
+func (s *digestService) digestWatchlists(ctx context.Context, sub *Subscription) ([]watchlists.Watchlist, error) {
+	if len(sub.WatchlistIDs) == 0 {
+		all, err := s.watchlists.ListWatchlists(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list watchlists: %w", err)
+		}
+		lists := make([]watchlists.Watchlist, 0, len(all))
+		for _, watchlist := range all {
+			if watchlist.KeyID == sub.KeyID {
+				lists = append(lists, watchlist)
+			}
+		}
+		sort.Slice(lists, func(i, j int) bool {
+			return lists[i].Name < lists[j].Name
+		})
+		return lists, nil
+	}
+
+	lists := make([]watchlists.Watchlist, 0, len(sub.WatchlistIDs))
+	for _, id := range sub.WatchlistIDs {
+		watchlist, err := s.watchlists.GetWatchlist(ctx, id)
+		if err != nil {
+			if errors.Is(err, watchlists.ErrWatchlistNotFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get watchlist %s: %w", id, err)
+		}
+		if watchlist.KeyID != sub.KeyID {
+			continue
+		}
+		lists = append(lists, *watchlist)
+	}
+	return lists, nil
+}
+
+// move returns symbol's move over the last sessions up to date, or nil when
+// there are not enough daily summaries
+func (s *digestService) move(ctx context.Context, symbol string, sessions int, date time.Time) (*Move, error) {
+	// Enough calendar days to span the sessions across weekends and holidays
+	from := date.AddDate(0, 0, -(2*sessions + 7)).Unix()
+	to := date.AddDate(0, 0, 1).Unix() - 1
+
+	bars, err := s.summaries.GetDailySummaries(ctx, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily summaries for %s: %w", symbol, err)
+	}
+	if len(bars) < 2 {
+		return nil, nil
+	}
+
+	latest := bars[len(bars)-1]
+	base := bars[max(0, len(bars)-1-sessions)].Close
+	if base <= 0 {
+		return nil, nil
+	}
+
+	change := float64(latest.Close) - float64(base)
+	return &Move{
+		Symbol:        symbol,
+		Close:         latest.Close,
+		Change:        change,
+		ChangePercent: change / float64(base) * 100,
+	}, nil
+}
+
+// rankMoves returns the top gainers, top losers and biggest absolute movers
+func rankMoves(moves []Move) (gainers, losers, biggest []Move) {
+	gainers, losers = make([]Move, 0), make([]Move, 0)
+	for _, m := range moves {
+		switch {
+		case m.ChangePercent > 0:
+			gainers = append(gainers, m)
+		case m.ChangePercent < 0:
+			losers = append(losers, m)
+		}
+	}
+	sort.SliceStable(gainers, func(i, j int) bool { return gainers[i].ChangePercent > gainers[j].ChangePercent })
+	sort.SliceStable(losers, func(i, j int) bool { return losers[i].ChangePercent < losers[j].ChangePercent })
+
+	biggest = append([]Move{}, moves...)
+	sort.SliceStable(biggest, func(i, j int) bool {
+		return math.Abs(biggest[i].ChangePercent) > math.Abs(biggest[j].ChangePercent)
+	})
+
+	return gainers[:min(len(gainers), digestTopMoves)],
+		losers[:min(len(losers), digestTopMoves)],
+		biggest[:min(len(biggest), digestTopMoves)]
+}
+
+// RenderDigest renders a digest's email subject and plain text body
+func (s *digestService) RenderDigest(digest *Digest) (string, string, error) {
+	n, err := s.templates.Compose(notify.KindDigest, digest)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return n.Subject, n.Body, nil
+}
+
+// SendDue sends the digest of every confirmed, unscheduled subscription due
+// on the trading day date and returns how many were sent. A subscription is
+// sent at most once per date, so reruns of the job only retry failed digests.
+func (s *digestService) SendDue(ctx context.Context, date time.Time) (int, error) {
+	subs, err := s.sendableSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	day := date.Format(models.DateLayout)
+	moves := make(map[string]*Move)
+	sent, failed := 0, 0
+	for i := range subs {
+		sub := &subs[i]
+		if !sub.Confirmed || sub.Schedule != nil || !sub.Frequency.Due(date) || sub.LastSentDate == day {
+			continue
+		}
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		if err := s.send(ctx, sub, date, moves); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to send digest", "subscription", sub.ID, "date", day, "error", err)
+			failed++
+			continue
+		}
+		sent++
+	}
+
+	logger.FromContext(ctx, s.log).Infow("digests sent", "date", day, "sent", sent, "failed", failed)
+	if failed > 0 {
+		return sent, fmt.Errorf("%d of %d digests failed", failed, sent+failed)
+	}
+	return sent, nil
+}
+
+// SendScheduled sends the digest of every confirmed, scheduled subscription
+// due at now, of the latest trading day whose bars are ready, and returns how
+// many were sent. A digest is due at the first run of its schedule after the
+// bars are ready and sent once; failures are retried on later calls for a
+// while, then skipped.
+func (s *digestService) SendScheduled(ctx context.Context, now time.Time) (int, error) {
+	subs, err := s.sendableSubscriptions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	date := lastReadyDay(now, s.readyAt)
+	day := date.Format(models.DateLayout)
+	moves := make(map[string]*Move)
+	sent, failed := 0, 0
+	for i := range subs {
+		sub := &subs[i]
+		if !sub.Confirmed || sub.Schedule == nil || !sub.Frequency.Due(date) || sub.LastSentDate == day {
+			continue
+		}
+		due, err := sub.dueAt(date, s.readyAt)
+		if err != nil {
+			logger.FromContext(ctx, s.log).Warnw("skipping digest with an invalid schedule", "subscription", sub.ID, "error", err)
+			continue
+		}
+		if now.Before(due) || now.Sub(due) > scheduledLateness {
+			continue
+		}
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+
+		if err := s.send(ctx, sub, date, moves); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to send scheduled digest", "subscription", sub.ID, "date", day, "due", due, "error", err)
+			failed++
+			continue
+		}
+		sent++
+	}
+
+	if sent+failed > 0 {
+		logger.FromContext(ctx, s.log).Infow("scheduled digests sent", "date", day, "sent", sent, "failed", failed)
+	}
+	if failed > 0 {
+		return sent, fmt.Errorf("%d of %d scheduled digests failed", failed, sent+failed)
+	}
+	return sent, nil
+}
+
+// sendableSubscriptions returns the subscriptions of every account that is
+// not deleted; deleted accounts are not mailed while their data awaits the
+// purge
+func (s *digestService) sendableSubscriptions(ctx context.Context) ([]Subscription, error) {
+	all, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list digest subscriptions", "error", err)
+		return nil, fmt.Errorf("failed to list digest subscriptions: %w", err)
+	}
+
+	keyIDs := make([]string, len(all))
+	for i, sub := range all {
+		keyIDs[i] = sub.KeyID
+	}
+	deleted, err := service.DeletedAccounts(ctx, s.keys, keyIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	subs := make([]Subscription, 0, len(all))
+	for _, sub := range all {
+		if !deleted[sub.KeyID] {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (s *digestService) send(ctx context.Context, sub *Subscription, date time.Time, moves map[string]*Move) error {
+	digest, err := s.build(ctx, sub, date, moves)
+	if err != nil {
+		return err
+	}
+
+	n, err := s.templates.Compose(notify.KindDigest, digest)
+	if err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+	n.SentUTC = time.Now().Unix()
+	n.To = []string{sub.Email}
+	n.KeyID = sub.KeyID
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		return err
+	}
+
+	if err := s.repo.MarkSent(ctx, sub.ID, digest.Date); err != nil {
+		// Unsubscribed while the digest was sent
+		if errors.Is(err, ErrDigestNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to mark digest sent: %w", err)
+	}
+	return nil
+}
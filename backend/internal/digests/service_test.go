@@ -0,0 +1,402 @@
+package digests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/schedule"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockRepository mocks the Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetSubscription(ctx context.Context, id string) (*Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Subscription), args.Error(1)
+}
+
+func (m *MockRepository) ListSubscriptions(ctx context.Context) ([]Subscription, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Subscription), args.Error(1)
+}
+
+func (m *MockRepository) PutSubscription(ctx context.Context, sub *Subscription) error {
+	return m.Called(ctx, sub).Error(0)
+}
+
+func (m *MockRepository) Confirm(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+func (m *MockRepository) MarkSent(ctx context.Context, id, date string) error {
+	return m.Called(ctx, id, date).Error(0)
+}
+
+func (m *MockRepository) DeleteSubscription(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+// recordingNotifier collects the notifications it is asked to deliver
+type recordingNotifier struct {
+	sent []notify.Notification
+	err  error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, n notify.Notification) error {
+	r.sent = append(r.sent, n)
+	return r.err
+}
+
+// testLinks signs the links of test services
+var testLinks = NewLinks("https://profitify.test/", []byte("secret"))
+
+// testReadyAt is when the bars of a trading day are ready, market time
+const testReadyAt = 16*time.Hour + 30*time.Minute
+
+func accountContext(tier models.PlanTier, admin bool) context.Context {
+	return service.WithAccount(context.Background(), &models.APIKey{ID: "key", Name: "test", Tier: tier, Admin: admin})
+}
+
+func TestService_Subscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     Subscription
+		wantErr error
+	}{
+		{
+			name: "normalizes email and frequency",
+			sub:  Subscription{Email: " jo@example.com ", Frequency: "WEEKLY", WatchlistIDs: []string{"tech"}},
+		},
+		{
+			name:    "rejects invalid addresses",
+			sub:     Subscription{Email: "not-an-address", Frequency: Daily},
+			wantErr: ErrInvalidDigest,
+		},
+		{
+			name:    "rejects unknown frequencies",
+			sub:     Subscription{Email: "jo@example.com", Frequency: "hourly"},
+			wantErr: ErrInvalidDigest,
+		},
+		{
+			name:    "rejects unknown watchlists",
+			sub:     Subscription{Email: "jo@example.com", Frequency: Daily, WatchlistIDs: []string{"gone"}},
+			wantErr: ErrInvalidDigest,
+		},
+		{
+			name:    "rejects watchlists of other keys",
+			sub:     Subscription{Email: "jo@example.com", Frequency: Daily, WatchlistIDs: []string{"theirs"}},
+			wantErr: ErrInvalidDigest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			repo.On("PutSubscription", mock.Anything, mock.Anything).Return(nil)
+			lists := new(watchlists.MockRepository)
+			lists.On("GetWatchlist", mock.Anything, "tech").Return(&watchlists.Watchlist{ID: "tech", KeyID: "key"}, nil)
+			lists.On("GetWatchlist", mock.Anything, "theirs").Return(&watchlists.Watchlist{ID: "theirs", KeyID: "other"}, nil)
+			lists.On("GetWatchlist", mock.Anything, "gone").Return(nil, fmt.Errorf("%w: gone", watchlists.ErrWatchlistNotFound))
+			notifier := &recordingNotifier{}
+			svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), lists, nil, nil, testLinks, testReadyAt, notifier, nil, zap.NewNop().Sugar())
+
+			sub, err := svc.Subscribe(accountContext(models.PlanFree, false), &tt.sub)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "PutSubscription", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, sub.ID)
+			assert.Equal(t, "jo@example.com", sub.Email)
+			assert.Equal(t, Weekly, sub.Frequency)
+			assert.Equal(t, "key", sub.KeyID)
+			assert.False(t, sub.Confirmed, "subscriptions wait for the address to confirm")
+
+			require.Len(t, notifier.sent, 1)
+			confirmation := notifier.sent[0]
+			assert.Equal(t, notify.KindDigestConfirmation, confirmation.Kind)
+			assert.Equal(t, []string{"jo@example.com"}, confirmation.To)
+			assert.Contains(t, confirmation.Body, testLinks.ConfirmURL(sub.ID))
+		})
+	}
+
+	t.Run("drops the subscription when the confirmation cannot be sent", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("PutSubscription", mock.Anything, mock.Anything).Return(nil)
+		repo.On("DeleteSubscription", mock.Anything, mock.Anything).Return(nil)
+		svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), nil, nil, nil, testLinks, testReadyAt, &recordingNotifier{err: errors.New("smtp down")}, nil, zap.NewNop().Sugar())
+
+		_, err := svc.Subscribe(accountContext(models.PlanFree, false), &Subscription{Email: "jo@example.com", Frequency: Daily})
+		assert.ErrorContains(t, err, "smtp down")
+		repo.AssertCalled(t, "DeleteSubscription", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Links(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("Confirm", mock.Anything, "sub").Return(nil)
+	repo.On("DeleteSubscription", mock.Anything, "sub").Return(nil)
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), nil, nil, nil, testLinks, testReadyAt, &recordingNotifier{}, nil, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	token := func(link string) string {
+		_, token, _ := strings.Cut(link, "token=")
+		return token
+	}
+	confirm, unsubscribe := token(testLinks.ConfirmURL("sub")), token(testLinks.UnsubscribeURL("sub"))
+	assert.True(t, strings.HasPrefix(testLinks.ConfirmURL("sub"), "https://profitify.test/api/v1/public/digests/sub/confirm?token="))
+
+	assert.ErrorIs(t, svc.Confirm(ctx, "sub", ""), ErrInvalidDigestLink)
+	assert.ErrorIs(t, svc.Confirm(ctx, "sub", unsubscribe), ErrInvalidDigestLink, "tokens are bound to their purpose")
+	assert.ErrorIs(t, svc.Confirm(ctx, "other", confirm), ErrInvalidDigestLink, "tokens are bound to their subscription")
+	assert.ErrorIs(t, svc.UnsubscribeByLink(ctx, "sub", confirm), ErrInvalidDigestLink)
+	repo.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "DeleteSubscription", mock.Anything, mock.Anything)
+
+	assert.NoError(t, svc.Confirm(ctx, "sub", confirm))
+	assert.NoError(t, svc.UnsubscribeByLink(ctx, "sub", unsubscribe))
+}
+
+func TestService_ScopesSubscriptionsToTheCallingKey(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetSubscription", mock.Anything, "mine").Return(&Subscription{ID: "mine", KeyID: "key"}, nil)
+	repo.On("GetSubscription", mock.Anything, "theirs").Return(&Subscription{ID: "theirs", KeyID: "other"}, nil)
+	repo.On("ListSubscriptions", mock.Anything).Return([]Subscription{{ID: "mine", KeyID: "key"}, {ID: "theirs", KeyID: "other"}}, nil)
+	repo.On("DeleteSubscription", mock.Anything, "mine").Return(nil)
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), nil, nil, nil, testLinks, testReadyAt, &recordingNotifier{}, nil, zap.NewNop().Sugar())
+	ctx := accountContext(models.PlanPro, false)
+
+	subs, err := svc.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Equal(t, "mine", subs[0].ID)
+
+	_, err = svc.GetSubscription(ctx, "theirs")
+	assert.ErrorIs(t, err, ErrDigestNotFound)
+
+	assert.NoError(t, svc.Unsubscribe(ctx, "mine"))
+	assert.ErrorIs(t, svc.Unsubscribe(ctx, "theirs"), ErrDigestNotFound)
+	repo.AssertNotCalled(t, "DeleteSubscription", mock.Anything, "theirs")
+}
+
+func TestService_SendDue(t *testing.T) {
+	friday := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	thursday := friday.AddDate(0, 0, -1)
+
+	repo := new(MockRepository)
+	repo.On("ListSubscriptions", mock.Anything).Return([]Subscription{
+		{ID: "daily", Email: "d@example.com", Frequency: Daily, WatchlistIDs: []string{"tech", "gone", "theirs"}, Confirmed: true, KeyID: "key"},
+		{ID: "weekly", Email: "w@example.com", Frequency: Weekly, Confirmed: true, KeyID: "key"},
+		{ID: "sent", Email: "s@example.com", Frequency: Daily, WatchlistIDs: []string{"tech"}, Confirmed: true, LastSentDate: "2025-03-07", KeyID: "key"},
+		{ID: "unconfirmed", Email: "u@example.com", Frequency: Daily, WatchlistIDs: []string{"tech"}, KeyID: "key"},
+		{ID: "scheduled", Email: "t@example.com", Frequency: Daily, Confirmed: true, KeyID: "key", Schedule: &schedule.Schedule{Timezone: "America/Toronto", LocalTime: "08:00"}},
+	}, nil)
+	repo.On("MarkSent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	lists := new(watchlists.MockRepository)
+	tech := watchlists.Watchlist{ID: "tech", Name: "Tech", Symbols: []string{"AAPL", "MSFT", "NVDA", "NEW"}, KeyID: "key"}
+	theirs := watchlists.Watchlist{ID: "theirs", Name: "Theirs", Symbols: []string{"AAPL"}, KeyID: "other"}
+	lists.On("GetWatchlist", mock.Anything, "tech").Return(&tech, nil)
+	lists.On("GetWatchlist", mock.Anything, "theirs").Return(&theirs, nil)
+	lists.On("GetWatchlist", mock.Anything, "gone").Return(nil, fmt.Errorf("%w: gone", watchlists.ErrWatchlistNotFound))
+	lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{theirs, tech}, nil)
+
+	bars := func(symbol string, closes ...float32) []models.DailySummary {
+		out := make([]models.DailySummary, len(closes))
+		for i, c := range closes {
+			out[i] = models.DailySummary{Ticker: symbol, Close: c, Timestamp: friday.AddDate(0, 0, i-len(closes)+1).Unix()}
+		}
+		return out
+	}
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).Return(bars("AAPL", 100, 101, 102, 103, 104, 110), nil)
+	summaries.On("GetSummaries", mock.Anything, "MSFT", mock.Anything, mock.Anything).Return(bars("MSFT", 400, 400, 400, 400, 400, 380), nil)
+	summaries.On("GetSummaries", mock.Anything, "NVDA", mock.Anything, mock.Anything).Return(bars("NVDA", 50, 50, 50, 50, 50, 50), nil)
+	summaries.On("GetSummaries", mock.Anything, "NEW", mock.Anything, mock.Anything).Return(bars("NEW", 10), nil)
+
+	alertRepo := new(alerts.MockRepository)
+	alertRepo.On("ListAlerts", mock.Anything, alerts.StatusTriggered).Return([]alerts.Alert{
+		{ID: "mine", Symbol: "AAPL", Condition: alerts.PriceAbove, Threshold: 105, TriggeredUTC: friday.Unix(), TriggeredClose: 110, KeyID: "key"},
+		{ID: "other", Symbol: "AAPL", Condition: alerts.PriceAbove, Threshold: 105, TriggeredUTC: friday.Unix(), TriggeredClose: 110, KeyID: "other"},
+		{ID: "old", Symbol: "MSFT", Condition: alerts.PriceBelow, Threshold: 390, TriggeredUTC: thursday.AddDate(0, 0, -3).Unix(), KeyID: "key"},
+	}, nil)
+
+	log := zap.NewNop().Sugar()
+	notifier := &recordingNotifier{}
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), lists, service.NewDailySummaryService(summaries, log), alertRepo, testLinks, testReadyAt, notifier, nil, log)
+
+	sent, err := svc.SendDue(context.Background(), friday)
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	repo.AssertNotCalled(t, "MarkSent", mock.Anything, "sent", "2025-03-07")
+	repo.AssertNotCalled(t, "MarkSent", mock.Anything, "unconfirmed", mock.Anything)
+	repo.AssertNotCalled(t, "MarkSent", mock.Anything, "scheduled", mock.Anything)
+	require.Len(t, notifier.sent, 2, "scheduled digests wait for their local time")
+
+	daily := notifier.sent[0]
+	assert.Equal(t, notify.KindDigest, daily.Kind)
+	assert.Equal(t, []string{"d@example.com"}, daily.To)
+	assert.Equal(t, "Your daily watchlist digest for 2025-03-07", daily.Subject)
+	assert.Contains(t, daily.Body, "Tech")
+	assert.Contains(t, daily.Body, "No data: NEW")
+	assert.Contains(t, daily.Body, testLinks.UnsubscribeURL("daily"))
+
+	digest := daily.Data.(*Digest)
+	require.Len(t, digest.Watchlists, 1, "deleted watchlists and watchlists of other keys are skipped")
+	techDigest := digest.Watchlists[0]
+	require.Len(t, techDigest.Gainers, 1)
+	assert.Equal(t, "AAPL", techDigest.Gainers[0].Symbol)
+	assert.InDelta(t, 5.77, techDigest.Gainers[0].ChangePercent, 0.01)
+	require.Len(t, techDigest.Losers, 1)
+	assert.Equal(t, "MSFT", techDigest.Losers[0].Symbol)
+	assert.Equal(t, []string{"AAPL", "MSFT", "NVDA"}, []string{techDigest.BiggestChanges[0].Symbol, techDigest.BiggestChanges[1].Symbol, techDigest.BiggestChanges[2].Symbol})
+	require.Len(t, digest.TriggeredAlerts, 1, "only recent alerts of the subscribing key")
+	assert.Equal(t, "mine", digest.TriggeredAlerts[0].ID)
+
+	weekly := notifier.sent[1].Data.(*Digest)
+	require.Len(t, weekly.Watchlists, 1, "no watchlist IDs means every watchlist of the key")
+	assert.Equal(t, "tech", weekly.Watchlists[0].ID)
+	assert.InDelta(t, 10, weekly.Watchlists[0].Gainers[0].ChangePercent, 0.01, "weekly moves span five sessions")
+	assert.Len(t, weekly.TriggeredAlerts, 2, "weekly digests cover the alerts of the week")
+
+	t.Run("weekly digests wait for friday", func(t *testing.T) {
+		notifier.sent = nil
+		_, err := svc.SendDue(context.Background(), thursday)
+		require.NoError(t, err)
+		require.Len(t, notifier.sent, 2)
+		for _, n := range notifier.sent {
+			assert.NotEqual(t, []string{"w@example.com"}, n.To)
+		}
+	})
+
+	t.Run("delivery failures are reported and not marked sent", func(t *testing.T) {
+		failing := new(MockRepository)
+		failing.On("ListSubscriptions", mock.Anything).Return([]Subscription{
+			{ID: "daily", Email: "d@example.com", Frequency: Daily, WatchlistIDs: []string{"tech"}, Confirmed: true, KeyID: "key"},
+		}, nil)
+		notifier := &recordingNotifier{err: errors.New("smtp down")}
+		svc := NewService(failing, repository.NewMemoryAPIKeyRepository(), lists, service.NewDailySummaryService(summaries, log), alertRepo, testLinks, testReadyAt, notifier, nil, log)
+
+		sent, err := svc.SendDue(context.Background(), friday)
+		assert.Error(t, err)
+		assert.Equal(t, 0, sent)
+		failing.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_SendScheduled(t *testing.T) {
+	toronto := &schedule.Schedule{Timezone: "America/Toronto", LocalTime: "08:00"}
+	tokyo := &schedule.Schedule{Timezone: "Asia/Tokyo", LocalTime: "07:00"}
+
+	repo := new(MockRepository)
+	repo.On("ListSubscriptions", mock.Anything).Return([]Subscription{
+		{ID: "toronto", Email: "t@example.com", Frequency: Daily, Confirmed: true, KeyID: "key", Schedule: toronto},
+		{ID: "tokyo", Email: "j@example.com", Frequency: Daily, Confirmed: true, KeyID: "key", Schedule: tokyo},
+		{ID: "weekly", Email: "w@example.com", Frequency: Weekly, Confirmed: true, KeyID: "key", Schedule: toronto},
+		{ID: "sent", Email: "s@example.com", Frequency: Daily, Confirmed: true, KeyID: "key", Schedule: toronto, LastSentDate: "2025-03-06"},
+		{ID: "unconfirmed", Email: "u@example.com", Frequency: Daily, KeyID: "key", Schedule: tokyo},
+		{ID: "unscheduled", Email: "d@example.com", Frequency: Daily, Confirmed: true, KeyID: "key"},
+	}, nil)
+	repo.On("MarkSent", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	lists := new(watchlists.MockRepository)
+	lists.On("ListWatchlists", mock.Anything).Return([]watchlists.Watchlist{}, nil)
+	alertRepo := new(alerts.MockRepository)
+	alertRepo.On("ListAlerts", mock.Anything, alerts.StatusTriggered).Return([]alerts.Alert{}, nil)
+
+	notifier := &recordingNotifier{}
+	svc := NewService(repo, repository.NewMemoryAPIKeyRepository(), lists, nil, alertRepo, testLinks, testReadyAt, notifier, nil, zap.NewNop().Sugar())
+	sendAt := func(now time.Time) []string {
+		t.Helper()
+		notifier.sent = nil
+		_, err := svc.SendScheduled(context.Background(), now)
+		require.NoError(t, err)
+		var to []string
+		for _, n := range notifier.sent {
+			to = append(to, n.To...)
+		}
+		return to
+	}
+
+	// Thursday's bars are ready at 16:30 EST, 21:30 UTC
+	assert.Empty(t, sendAt(time.Date(2025, 3, 6, 21, 45, 0, 0, time.UTC)), "07:00 in Tokyo is still to come")
+	assert.Equal(t, []string{"j@example.com"}, sendAt(time.Date(2025, 3, 6, 22, 0, 0, 0, time.UTC)), "07:00 JST Friday")
+	assert.Equal(t, []string{"t@example.com"}, sendAt(time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC)), "08:00 EST Friday; weekly digests wait for Friday's close")
+	repo.AssertCalled(t, "MarkSent", mock.Anything, "toronto", "2025-03-06")
+	assert.Empty(t, sendAt(time.Date(2025, 3, 7, 15, 30, 0, 0, time.UTC)), "digests more than two hours late are skipped")
+
+	// Friday's weekly digest is due at 08:00 on Saturday
+	assert.ElementsMatch(t, []string{"t@example.com", "w@example.com", "s@example.com"}, sendAt(time.Date(2025, 3, 8, 13, 5, 0, 0, time.UTC)))
+	repo.AssertCalled(t, "MarkSent", mock.Anything, "weekly", "2025-03-07")
+}
+
+func TestService_SendSkipsDeletedAccounts(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	keys := repository.NewMemoryAPIKeyRepository()
+	key := &models.APIKey{ID: "key", Name: "deleted"}
+	require.NoError(t, keys.PutKey(ctx, key))
+	_, err := service.NewAccountService(keys, nil, time.Hour, log).Delete(service.WithAccount(ctx, key))
+	require.NoError(t, err)
+
+	repo := new(MockRepository)
+	repo.On("ListSubscriptions", mock.Anything).Return([]Subscription{
+		{ID: "daily", Email: "d@example.com", Frequency: Daily, Confirmed: true, KeyID: "key"},
+		{ID: "scheduled", Email: "t@example.com", Frequency: Daily, Confirmed: true, KeyID: "key", Schedule: &schedule.Schedule{Timezone: "America/Toronto", LocalTime: "08:00"}},
+	}, nil)
+
+	notifier := &recordingNotifier{}
+	svc := NewService(repo, keys, nil, nil, nil, testLinks, testReadyAt, notifier, nil, log)
+
+	sent, err := svc.SendDue(ctx, time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	sent, err = svc.SendScheduled(ctx, time.Date(2025, 3, 7, 13, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	assert.Empty(t, notifier.sent, "deleted accounts are not mailed while quarantined")
+	repo.AssertNotCalled(t, "MarkSent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubscription_NextSend(t *testing.T) {
+	sub := Subscription{Frequency: Daily, Schedule: &schedule.Schedule{Timezone: "America/Toronto", LocalTime: "08:00"}}
+
+	next, err := sub.nextSend(time.Date(2025, 3, 7, 22, 0, 0, 0, time.UTC), testReadyAt)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 8, 13, 0, 0, 0, time.UTC), next.UTC(), "08:00 EST")
+
+	sub.LastSentDate = "2025-03-07"
+	next, err = sub.nextSend(time.Date(2025, 3, 8, 13, 0, 0, 0, time.UTC), testReadyAt)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 3, 11, 12, 0, 0, 0, time.UTC), next.UTC(), "Monday's digest at 08:00 EDT on Tuesday")
+
+	sub.Frequency, sub.LastSentDate = Weekly, ""
+	next, err = sub.nextSend(time.Date(2025, 4, 14, 12, 0, 0, 0, time.UTC), testReadyAt)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 4, 26, 12, 0, 0, 0, time.UTC), next.UTC(), "the market is closed on Good Friday")
+}
@@ -0,0 +1,178 @@
+package indicators
+
+import (
+	"bytes"
+	"math"
+	"strconv"
+
+	"profitify-backend/internal/models"
+)
+
+// featurePeriod is the lookback of the windowed features: the averages,
+// Bollinger Bands, volatility and volume z-score
+const featurePeriod = 20
+
+// FeatureColumns names the features of each session, in the order of a
+// feature table's columns
+var FeatureColumns = []string{
+	"close",
+	"volume",
+	"return1",
+	"return5",
+	"return20",
+	"logReturn1",
+	"volatility20",
+	"sma20",
+	"ema20",
+	"rsi14",
+	"macd",
+	"macdSignal",
+	"macdHistogram",
+	"bollingerUpper",
+	"bollingerLower",
+	"volumeZ20",
+}
+
+// Column holds a feature of each session of a feature table. It is NaN where
+// the feature is not formed yet, which JSON encodes as null.
+type Column []float64
+
+func (c Column) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('[')
+	for i, v := range c {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(FormatFeature(v, "null"))
+	}
+	b.WriteByte(']')
+	return b.Bytes(), nil
+}
+
+// FormatFeature formats a feature's value, or returns missing for NaN
+func FormatFeature(v float64, missing string) string {
+	if math.IsNaN(v) {
+		return missing
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// FeatureTable holds the features of a ticker's sessions, oldest first:
+// Columns[i] is FeatureColumns[i] of each session in Timestamps
+type FeatureTable struct {
+	Timestamps []int64
+	Columns    []Column
+}
+
+func newFeatureTable() *FeatureTable {
+	return &FeatureTable{Timestamps: []int64{}, Columns: make([]Column, len(FeatureColumns))}
+}
+
+func (t *FeatureTable) append(timestamp int64, row []float64) {
+	t.Timestamps = append(t.Timestamps, timestamp)
+	for i, v := range row {
+		t.Columns[i] = append(t.Columns[i], v)
+	}
+}
+
+// Len is the number of sessions in the table
+func (t *FeatureTable) Len() int {
+	return len(t.Timestamps)
+}
+
+// features consumes daily bars oldest first and computes the row of features
+// of each
+type features struct {
+	previous   float64
+	closes5    *window
+	closes20   *window
+	logReturns *window
+	volumes    *window
+	sma        *sma
+	ema        *ema
+	rsi        *rsi
+	macd       *macd
+	bollinger  *bollinger
+}
+
+func newFeatures() *features {
+	return &features{
+		closes5:    newWindow(5 + 1),
+		closes20:   newWindow(featurePeriod + 1),
+		logReturns: newWindow(featurePeriod),
+		volumes:    newWindow(featurePeriod),
+		sma:        newSMA(featurePeriod),
+		ema:        newEMA(featurePeriod),
+		rsi:        newRSI(DefaultPeriod(RSI)),
+		macd:       newMACD(macdFast, macdSlow, macdSignal),
+		bollinger:  newBollinger(featurePeriod, bollingerWidth),
+	}
+}
+
+// lookback is the number of sessions to feed before the first row of
+// interest for all of its features to be fully formed
+func (f *features) lookback() int {
+	return max(f.closes20.size(), f.sma.lookback(), f.ema.lookback(), f.rsi.lookback(), f.macd.lookback(), f.bollinger.lookback())
+}
+
+// add returns the features of bar in the order of FeatureColumns. A bar
+// without a positive close has no returns to measure; it is not fed to the
+// windows and only its close and volume are set.
+func (f *features) add(bar models.DailySummary) []float64 {
+	row := make([]float64, len(FeatureColumns))
+	for i := range row {
+		row[i] = math.NaN()
+	}
+	close, volume := float64(bar.Close), float64(bar.Volume)
+	row[0], row[1] = close, volume
+	if close <= 0 {
+		return row
+	}
+
+	if f.previous > 0 {
+		row[2] = close/f.previous - 1
+		logReturn := math.Log(close / f.previous)
+		row[5] = logReturn
+		f.logReturns.push(logReturn)
+		if f.logReturns.full {
+			row[6] = math.Sqrt(f.logReturns.deviation()/float64(featurePeriod-1)) * math.Sqrt(models.TradingDaysPerYear)
+		}
+	}
+	f.previous = close
+
+	f.closes5.push(close)
+	if f.closes5.full {
+		row[3] = close/f.closes5.oldest() - 1
+	}
+	f.closes20.push(close)
+	if f.closes20.full {
+		row[4] = close/f.closes20.oldest() - 1
+	}
+
+	if p, ok := f.sma.add(close); ok {
+		row[7] = p.Value
+	}
+	if p, ok := f.ema.add(close); ok {
+		row[8] = p.Value
+	}
+	if p, ok := f.rsi.add(close); ok {
+		row[9] = p.Value
+	}
+	if p, ok := f.macd.add(close); ok {
+		row[10], row[11], row[12] = p.Value, *p.Signal, *p.Histogram
+	}
+	if p, ok := f.bollinger.add(close); ok {
+		row[13], row[14] = *p.Upper, *p.Lower
+	}
+
+	// The z-score compares the volume with the sessions before it, so a
+	// spike is measured against the volume it breaks from
+	if f.volumes.full {
+		if stddev := math.Sqrt(f.volumes.deviation() / float64(featurePeriod)); stddev > 0 {
+			row[15] = (volume - f.volumes.mean()) / stddev
+		}
+	}
+	f.volumes.push(volume)
+	return row
+}
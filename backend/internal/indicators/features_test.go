@@ -0,0 +1,124 @@
+package indicators
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// column returns the named feature of table
+func column(t *testing.T, table *FeatureTable, name string) Column {
+	i := slices.Index(FeatureColumns, name)
+	require.GreaterOrEqual(t, i, 0, "no feature %s", name)
+	return table.Columns[i]
+}
+
+func TestFeatures(t *testing.T) {
+	f := newFeatures()
+	table := newFeatureTable()
+	for i := 0; i < 30; i++ {
+		volume := float32(1000)
+		if i%2 == 1 {
+			volume = 3000
+		}
+		if i == 29 {
+			volume = 6000
+		}
+		table.append(int64(i), f.add(models.DailySummary{Close: float32(100 + i), Volume: volume}))
+	}
+	require.Equal(t, 30, table.Len())
+
+	return1 := column(t, table, "return1")
+	assert.True(t, math.IsNaN(return1[0]), "the first session has no return")
+	assert.InDelta(t, 101.0/100-1, return1[1], 1e-12)
+	assert.InDelta(t, math.Log(101.0/100), column(t, table, "logReturn1")[1], 1e-12)
+
+	return5 := column(t, table, "return5")
+	assert.True(t, math.IsNaN(return5[4]))
+	assert.InDelta(t, 105.0/100-1, return5[5], 1e-12)
+	return20 := column(t, table, "return20")
+	assert.True(t, math.IsNaN(return20[19]))
+	assert.InDelta(t, 120.0/100-1, return20[20], 1e-12)
+
+	sma := column(t, table, "sma20")
+	assert.True(t, math.IsNaN(sma[18]))
+	assert.InDelta(t, 109.5, sma[19], 1e-12)
+	upper, lower := column(t, table, "bollingerUpper"), column(t, table, "bollingerLower")
+	assert.InDelta(t, sma[29]*2, upper[29]+lower[29], 1e-9, "the bands are centered on the average")
+
+	volatility := column(t, table, "volatility20")
+	assert.True(t, math.IsNaN(volatility[19]), "20 returns take 21 sessions")
+	bars := make([]models.DailySummary, 21)
+	for i := range bars {
+		bars[i] = models.DailySummary{Close: float32(100 + 9 + i)}
+	}
+	_, summary := models.ComputeReturns(bars, models.ReturnLog)
+	assert.InDelta(t, *summary.Volatility, volatility[29], 1e-6, "volatility is measured as the returns endpoint does")
+
+	// The sessions before alternate between 1000 and 3000, so their mean is
+	// 2000 and their standard deviation 1000
+	volumeZ := column(t, table, "volumeZ20")
+	assert.True(t, math.IsNaN(volumeZ[19]))
+	assert.InDelta(t, 4.0, volumeZ[29], 1e-9)
+
+	assert.True(t, math.IsNaN(column(t, table, "macd")[29]), "macd forms after 34 sessions")
+
+	t.Run("bars without a positive close only set the close and volume", func(t *testing.T) {
+		row := newFeatures().add(models.DailySummary{Volume: 10})
+		assert.Equal(t, []float64{0, 10}, row[:2])
+		for _, v := range row[2:] {
+			assert.True(t, math.IsNaN(v))
+		}
+	})
+}
+
+func TestColumn_MarshalJSON(t *testing.T) {
+	b, err := json.Marshal(Column{math.NaN(), 1.5, -2})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[null, 1.5, -2]`, string(b))
+
+	b, err = json.Marshal(Column{})
+	require.NoError(t, err)
+	assert.Equal(t, `[]`, string(b))
+}
+
+func TestService_Features(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &streamRepository{}
+	for i := 0; i < 300; i++ {
+		day := start.AddDate(0, 0, i)
+		repo.summaries = append(repo.summaries, models.DailySummary{Ticker: "AAPL", Timestamp: day.Unix(), Close: float32(100 + i%7), Volume: float32(1000 + i%3*100)})
+	}
+	svc := NewService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	from := start.AddDate(0, 0, 250).Unix()
+	to := start.AddDate(0, 0, 259).Unix()
+	table, err := svc.Features(ctx, "AAPL", from, to)
+	require.NoError(t, err)
+
+	// Bars before from warm every feature up; only sessions in range are returned
+	require.Equal(t, 10, table.Len())
+	assert.Equal(t, from, table.Timestamps[0])
+	for i, name := range FeatureColumns {
+		for _, v := range table.Columns[i] {
+			assert.False(t, math.IsNaN(v), "%s is formed", name)
+		}
+	}
+
+	_, err = svc.Features(ctx, "AAPL", to, from)
+	assert.ErrorIs(t, err, service.ErrInvalidRange)
+
+	_, err = svc.Features(ctx, "", from, to)
+	assert.ErrorIs(t, err, service.ErrInvalidTicker)
+}
@@ -0,0 +1,178 @@
+package indicators
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+)
+
+// ForecastMethod is the statistical baseline a forecast extrapolates the
+// closes by
+type ForecastMethod string
+
+const (
+	// ForecastDrift is a random walk with drift: the log close moves by the
+	// mean daily log return of the history each session
+	ForecastDrift ForecastMethod = "drift"
+	// ForecastSES is simple exponential smoothing of the closes, the
+	// ARIMA(0,1,1) model: the forecast stays at the smoothed level
+	ForecastSES ForecastMethod = "ses"
+)
+
+const (
+	// MaxForecastHorizon is the most sessions a forecast reaches ahead
+	MaxForecastHorizon = 252
+	// MinForecastHistory is the fewest sessions a forecast is fitted on
+	MinForecastHistory = 30
+
+	// ForecastKind labels every forecast as the statistical baseline it is
+	ForecastKind = "statistical-baseline"
+	// ForecastNotice is served with every forecast
+	ForecastNotice = "A statistical baseline extrapolated from past closes alone, not a prediction or investment advice. " +
+		"The bands are where the model's assumptions would put the close 80% and 95% of the time."
+)
+
+// z-scores of the two-sided 80% and 95% bands of a normal distribution
+const (
+	z80 = 1.2815515655446004
+	z95 = 1.959963984540054
+)
+
+// ForecastPoint is the forecast close of a future session and its bands
+type ForecastPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Date      string  `json:"date"`
+	Value     float64 `json:"value"`
+	Lower80   float64 `json:"lower80"`
+	Upper80   float64 `json:"upper80"`
+	Lower95   float64 `json:"lower95"`
+	Upper95   float64 `json:"upper95"`
+}
+
+// Forecast is a baseline forecast of the closes of the sessions after AsOf
+type Forecast struct {
+	Kind    string         `json:"kind"`
+	Notice  string         `json:"notice"`
+	Method  ForecastMethod `json:"method"`
+	Horizon int            `json:"horizon"`
+	// AsOf is the date of the last close the forecast is fitted on
+	AsOf      string  `json:"asOf"`
+	LastClose float64 `json:"lastClose"`
+	// Sessions is how many closes the forecast is fitted on
+	Sessions int `json:"sessions"`
+	// Drift is the mean daily log return of a drift forecast
+	Drift *float64 `json:"drift,omitempty"`
+	// Alpha is the smoothing factor of an SES forecast
+	Alpha *float64 `json:"alpha,omitempty"`
+	// Sigma is the standard deviation of the one-session errors: of the log
+	// returns for drift, of the closes for SES
+	Sigma  float64         `json:"sigma"`
+	Points []ForecastPoint `json:"points"`
+}
+
+// forecast fits method to closes, oldest first, the last of which closed the
+// session at asOf, and extrapolates it horizon sessions ahead
+func forecast(method ForecastMethod, closes []float64, asOf int64, horizon int) (*Forecast, error) {
+	f := &Forecast{
+		Kind:      ForecastKind,
+		Notice:    ForecastNotice,
+		Method:    method,
+		Horizon:   horizon,
+		AsOf:      time.Unix(asOf, 0).UTC().Format(models.DateLayout),
+		LastClose: closes[len(closes)-1],
+		Sessions:  len(closes),
+		Points:    make([]ForecastPoint, 0, horizon),
+	}
+
+	// band returns the value and bands of the session h ahead
+	var band func(h int) (value, spread80, spread95 float64)
+	switch method {
+	case ForecastDrift:
+		drift, sigma := fitDrift(closes)
+		f.Drift, f.Sigma = &drift, sigma
+		n := float64(len(closes) - 1)
+		level := math.Log(f.LastClose)
+		// The spread grows with the horizon, and with the uncertainty of the
+		// drift estimated from n returns
+		band = func(h int) (float64, float64, float64) {
+			se := sigma * math.Sqrt(float64(h)*(1+float64(h)/n))
+			return level + float64(h)*drift, z80 * se, z95 * se
+		}
+	case ForecastSES:
+		alpha, level, sigma := fitSES(closes)
+		f.Alpha, f.Sigma = &alpha, sigma
+		band = func(h int) (float64, float64, float64) {
+			se := sigma * math.Sqrt(1+float64(h-1)*alpha*alpha)
+			return level, z80 * se, z95 * se
+		}
+	default:
+		return nil, checkForecastMethod(method)
+	}
+
+	day := time.Unix(asOf, 0).UTC()
+	for h := 1; h <= horizon; h++ {
+		day = marketcalendar.NextTradingDay(day)
+		value, spread80, spread95 := band(h)
+		point := ForecastPoint{Timestamp: day.Unix(), Date: day.Format(models.DateLayout)}
+		if method == ForecastDrift {
+			// Drift is fitted on log closes, whose bands are symmetric
+			point.Value = math.Exp(value)
+			point.Lower80, point.Upper80 = math.Exp(value-spread80), math.Exp(value+spread80)
+			point.Lower95, point.Upper95 = math.Exp(value-spread95), math.Exp(value+spread95)
+		} else {
+			// Closes do not fall below zero
+			point.Value = value
+			point.Lower80, point.Upper80 = math.Max(value-spread80, 0), value+spread80
+			point.Lower95, point.Upper95 = math.Max(value-spread95, 0), value+spread95
+		}
+		f.Points = append(f.Points, point)
+	}
+	return f, nil
+}
+
+// checkForecastMethod rejects methods other than drift and SES
+func checkForecastMethod(method ForecastMethod) error {
+	if method != ForecastDrift && method != ForecastSES {
+		return fmt.Errorf("%w: method must be %s or %s", ErrInvalidIndicator, ForecastDrift, ForecastSES)
+	}
+	return nil
+}
+
+// fitDrift returns the mean and sample standard deviation of the daily log
+// returns of closes
+func fitDrift(closes []float64) (drift, sigma float64) {
+	returns := make([]float64, len(closes)-1)
+	for i := 1; i < len(closes); i++ {
+		returns[i-1] = math.Log(closes[i] / closes[i-1])
+		drift += returns[i-1]
+	}
+	drift /= float64(len(returns))
+
+	var squares float64
+	for _, r := range returns {
+		squares += (r - drift) * (r - drift)
+	}
+	return drift, math.Sqrt(squares / float64(len(returns)-1))
+}
+
+// fitSES smooths closes with the factor between 0.01 and 1 that minimizes
+// the squared errors of its one-session forecasts, and returns the factor,
+// the final level and the standard deviation of the errors
+func fitSES(closes []float64) (alpha, level, sigma float64) {
+	bestSSE := math.Inf(1)
+	for step := 1; step <= 100; step++ {
+		a := float64(step) / 100
+		l, sse := closes[0], 0.0
+		for _, c := range closes[1:] {
+			sse += (c - l) * (c - l)
+			l += a * (c - l)
+		}
+		if sse < bestSSE {
+			bestSSE, alpha, level = sse, a, l
+		}
+	}
+	return alpha, level, math.Sqrt(bestSSE / float64(len(closes)-1))
+}
@@ -0,0 +1,139 @@
+package indicators
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// thursday is the session before Good Friday 2025
+var thursday = time.Date(2025, 4, 17, 0, 0, 0, 0, time.UTC).Unix()
+
+func TestForecast_Drift(t *testing.T) {
+	// Closes growing 1% a session have no noise to widen the bands
+	closes := make([]float64, 40)
+	for i := range closes {
+		closes[i] = 100 * math.Pow(1.01, float64(i))
+	}
+	f, err := forecast(ForecastDrift, closes, thursday, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, ForecastKind, f.Kind)
+	assert.NotEmpty(t, f.Notice)
+	assert.Equal(t, "2025-04-17", f.AsOf)
+	assert.Equal(t, 40, f.Sessions)
+	require.NotNil(t, f.Drift)
+	assert.InDelta(t, math.Log(1.01), *f.Drift, 1e-12)
+	assert.Nil(t, f.Alpha)
+	assert.InDelta(t, 0, f.Sigma, 1e-12)
+
+	require.Len(t, f.Points, 3)
+	assert.Equal(t, []string{"2025-04-21", "2025-04-22", "2025-04-23"},
+		[]string{f.Points[0].Date, f.Points[1].Date, f.Points[2].Date}, "the holiday weekend is skipped")
+	for h, p := range f.Points {
+		want := closes[39] * math.Pow(1.01, float64(h+1))
+		assert.InDelta(t, want, p.Value, 1e-9)
+		assert.InDelta(t, want, p.Lower95, 1e-9)
+		assert.InDelta(t, want, p.Upper95, 1e-9)
+	}
+}
+
+func TestForecast_SES(t *testing.T) {
+	closes := []float64{10, 12, 10, 12, 10, 12, 10, 12, 10, 12, 10, 12}
+	f, err := forecast(ForecastSES, closes, thursday, 2)
+	require.NoError(t, err)
+
+	require.NotNil(t, f.Alpha)
+	assert.Nil(t, f.Drift)
+	// Alternating closes are best forecast near their mean, by slow smoothing
+	assert.Less(t, *f.Alpha, 0.5)
+	for _, p := range f.Points {
+		assert.InDelta(t, 11, p.Value, 0.5, "the forecast stays at the smoothed level")
+	}
+	assert.Equal(t, f.Points[0].Value, f.Points[1].Value)
+
+	// A close followed exactly is smoothed with a factor of 1
+	f, err = forecast(ForecastSES, []float64{5, 6, 7, 8, 9, 10}, thursday, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, *f.Alpha)
+	assert.Equal(t, 10.0, f.Points[0].Value)
+
+	_, err = forecast("arima", closes, thursday, 1)
+	assert.ErrorIs(t, err, ErrInvalidIndicator)
+}
+
+func TestForecast_Bands(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	closes := make([]float64, 250)
+	price := 50.0
+	for i := range closes {
+		price *= 1 + (r.Float64()-0.5)/10
+		closes[i] = price
+	}
+
+	for _, method := range []ForecastMethod{ForecastDrift, ForecastSES} {
+		f, err := forecast(method, closes, thursday, 60)
+		require.NoError(t, err)
+		assert.Positive(t, f.Sigma)
+
+		width := 0.0
+		for _, p := range f.Points {
+			assert.True(t, p.Lower95 <= p.Lower80 && p.Lower80 <= p.Value && p.Value <= p.Upper80 && p.Upper80 <= p.Upper95,
+				"%s bands nest around the forecast on %s", method, p.Date)
+			assert.GreaterOrEqual(t, p.Lower95, 0.0)
+			assert.GreaterOrEqual(t, p.Upper95-p.Lower95, width, "%s bands widen with the horizon", method)
+			width = p.Upper95 - p.Lower95
+		}
+	}
+}
+
+func TestService_Forecast(t *testing.T) {
+	repo := &streamRepository{}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for i := range 60 {
+		repo.summaries = append(repo.summaries, models.DailySummary{Ticker: "AAPL", Timestamp: day.Unix(), Close: float32(100 + i)})
+		day = day.AddDate(0, 0, 1)
+	}
+	svc := NewService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+	to := day.Unix()
+
+	f, err := svc.Forecast(ctx, "AAPL", ForecastSES, 5, 0, to)
+	require.NoError(t, err)
+	assert.Equal(t, 60, f.Sessions)
+	assert.Equal(t, 159.0, f.LastClose)
+	assert.Len(t, f.Points, 5)
+
+	_, err = svc.Forecast(ctx, "AAPL", ForecastDrift, 5, repo.summaries[40].Timestamp, to)
+	assert.ErrorIs(t, err, ErrInvalidIndicator, "20 sessions are too few")
+
+	_, err = svc.Forecast(ctx, "AAPL", ForecastDrift, MaxForecastHorizon+1, 0, to)
+	assert.ErrorIs(t, err, ErrInvalidIndicator)
+
+	_, err = svc.Forecast(ctx, "AAPL", "arima", 5, 0, to)
+	assert.ErrorIs(t, err, ErrInvalidIndicator)
+
+	_, err = svc.Forecast(ctx, "AAPL", ForecastDrift, 5, 0, repo.summaries[0].Timestamp-1)
+	assert.ErrorIs(t, err, service.ErrTickerNotFound)
+}
+
+func TestParseHorizon(t *testing.T) {
+	for value, want := range map[string]int{"30d": 30, "30": 30, "6w": 30, "3m": 63, "12m": 252} {
+		got, err := parseHorizon(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+	for _, value := range []string{"", "0d", "-5d", "13m", "253", "1y", "d"} {
+		_, err := parseHorizon(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,186 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetIndicator(c *gin.Context) {
+	t := Type(strings.ToLower(c.Query("type")))
+	if t == "" {
+		problem.Respond(c, problem.ValidationFailed, "type is required")
+		return
+	}
+
+	period := DefaultPeriod(t)
+	if value := c.Query("period"); value != "" {
+		p, err := strconv.Atoi(value)
+		if err != nil {
+			problem.Respond(c, problem.ValidationFailed, "period must be an integer")
+			return
+		}
+		period = p
+	}
+
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	points, err := h.indicatorService.Compute(c.Request.Context(), symbol, t, period, from, to)
+	if err != nil {
+		h.respondError(c, err, "compute indicator", "symbol", symbol, "type", t)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticker": symbol,
+		"type":   t,
+		"period": period,
+		"points": points,
+		"count":  len(points),
+	})
+}
+
+// GetFeatures serves the features of a ticker's sessions in the date range
+// as a wide table, in columns of JSON arrays or as CSV with a row per session
+func (h *Handler) GetFeatures(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	table, err := h.indicatorService.Features(c.Request.Context(), symbol, from, to)
+	if err != nil {
+		h.respondError(c, err, "compute features", "symbol", symbol)
+		return
+	}
+
+	dates := make([]string, table.Len())
+	for i, ts := range table.Timestamps {
+		dates[i] = time.Unix(ts, 0).UTC().Format(models.DateLayout)
+	}
+
+	if api.WantsCSV(c) {
+		w := api.NewCSVWriter(c, symbol+"-features.csv", featureCSVHeader)
+		for i, ts := range table.Timestamps {
+			record := make([]string, 0, len(featureCSVHeader))
+			record = append(record, dates[i], strconv.FormatInt(ts, 10))
+			for _, column := range table.Columns {
+				record = append(record, FormatFeature(column[i], ""))
+			}
+			if err = w.Write(record); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			err = w.Close()
+		}
+		if err != nil {
+			api.Logger(c, h.log).Warnw("failed to write features csv", "symbol", symbol, "error", err)
+		}
+		return
+	}
+
+	data := make(map[string]any, len(featureCSVHeader))
+	data["date"] = dates
+	data["timestamp"] = table.Timestamps
+	for i, name := range FeatureColumns {
+		data[name] = table.Columns[i]
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":  symbol,
+		"columns": featureCSVHeader,
+		"data":    data,
+		"count":   table.Len(),
+	})
+}
+
+// defaultForecastHorizon is the horizon of a forecast when none is given
+const defaultForecastHorizon = "30d"
+
+// GetForecast serves a baseline forecast of a ticker's closes, fitted to the
+// closes in the date range
+func (h *Handler) GetForecast(c *gin.Context) {
+	horizon, err := parseHorizon(c.DefaultQuery("horizon", defaultForecastHorizon))
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	method := ForecastMethod(strings.ToLower(c.DefaultQuery("method", string(ForecastDrift))))
+
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	f, err := h.indicatorService.Forecast(c.Request.Context(), symbol, method, horizon, from, to)
+	if err != nil {
+		h.respondError(c, err, "forecast closes", "symbol", symbol, "method", method)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":   symbol,
+		"forecast": f,
+	})
+}
+
+// parseHorizon reads a horizon of sessions, weeks of 5 sessions or months of
+// 21 sessions, such as 30d, 6w or 3m; a bare number is sessions
+func parseHorizon(value string) (int, error) {
+	unit := 1
+	switch {
+	case strings.HasSuffix(value, "d"):
+		value = strings.TrimSuffix(value, "d")
+	case strings.HasSuffix(value, "w"):
+		value, unit = strings.TrimSuffix(value, "w"), 5
+	case strings.HasSuffix(value, "m"):
+		value, unit = strings.TrimSuffix(value, "m"), 21
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 || n*unit > MaxForecastHorizon {
+		return 0, fmt.Errorf("horizon must be a number of sessions (d), weeks (w) or months (m) up to %d sessions, such as 30d", MaxForecastHorizon)
+	}
+	return n * unit, nil
+}
+
+// featureCSVHeader names the columns of a feature table, in CSV and JSON
+var featureCSVHeader = append([]string{"date", "timestamp"}, FeatureColumns...)
+
+// respondError answers a failure to do what of the indicator service, logging
+// it with keysAndValues when it is not the caller's
+func (h *Handler) respondError(c *gin.Context, err error, what string, keysAndValues ...any) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTicker):
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+	case errors.Is(err, service.ErrTickerNotFound):
+		problem.Respond(c, problem.TickerNotFound, "No daily bars in range")
+	case errors.Is(err, ErrInvalidIndicator), errors.Is(err, service.ErrInvalidRange):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, service.ErrUpgradeRequired):
+		problem.Respond(c, problem.UpgradeRequired, err.Error())
+	case errors.Is(err, service.ErrPlanLimitExceeded):
+		problem.Respond(c, problem.PlanLimitExceeded, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("failed to "+what, append(keysAndValues, "error", err)...)
+		problem.Respond(c, problem.Internal, "Failed to "+what)
+	}
+}
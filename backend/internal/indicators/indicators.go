@@ -0,0 +1,281 @@
+// Package indicators computes technical indicators over a ticker's daily
+// closes and serves them over HTTP.
+package indicators
+
+import (
+	"fmt"
+	"math"
+)
+
+// Type names a technical indicator
+type Type string
+
+const (
+	SMA       Type = "sma"
+	EMA       Type = "ema"
+	RSI       Type = "rsi"
+	MACD      Type = "macd"
+	Bollinger Type = "bollinger"
+)
+
+// MACD uses the conventional 12/26/9 periods; the period parameter does not apply to it
+const (
+	macdFast   = 12
+	macdSlow   = 26
+	macdSignal = 9
+)
+
+// bollingerWidth is the number of standard deviations between the middle and outer bands
+const bollingerWidth = 2
+
+// MaxPeriod bounds the period of an indicator
+const MaxPeriod = 200
+
+// DefaultPeriod returns the period used when none is requested
+func DefaultPeriod(t Type) int {
+	switch t {
+	case RSI:
+		return 14
+	case MACD:
+		return macdSlow
+	default:
+		return 20
+	}
+}
+
+// Point is the value of an indicator at the session starting at Timestamp.
+// MACD also sets Signal and Histogram; Bollinger Bands set Value to the middle
+// band and also set Upper and Lower.
+type Point struct {
+	Timestamp int64    `json:"timestamp"`
+	Value     float64  `json:"value"`
+	Signal    *float64 `json:"signal,omitempty"`
+	Histogram *float64 `json:"histogram,omitempty"`
+	Upper     *float64 `json:"upper,omitempty"`
+	Lower     *float64 `json:"lower,omitempty"`
+}
+
+// calculator consumes closes oldest first, keeping only the state its indicator
+// needs, and reports a point once it has seen enough closes
+type calculator interface {
+	add(close float64) (Point, bool)
+	// lookback is the number of closes to feed before the first point of
+	// interest for its value to be fully formed
+	lookback() int
+}
+
+func newCalculator(t Type, period int) (calculator, error) {
+	switch t {
+	case SMA:
+		return newSMA(period), nil
+	case EMA:
+		return newEMA(period), nil
+	case RSI:
+		return newRSI(period), nil
+	case MACD:
+		return newMACD(macdFast, macdSlow, macdSignal), nil
+	case Bollinger:
+		return newBollinger(period, bollingerWidth), nil
+	default:
+		return nil, fmt.Errorf("%w: unknown type %q, expected one of sma, ema, rsi, macd, bollinger", ErrInvalidIndicator, t)
+	}
+}
+
+// window holds the last closes of a fixed size
+type window struct {
+	values []float64
+	next   int
+	full   bool
+	sum    float64
+}
+
+func newWindow(size int) *window {
+	return &window{values: make([]float64, size)}
+}
+
+func (w *window) push(v float64) {
+	w.sum += v - w.values[w.next]
+	w.values[w.next] = v
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func (w *window) mean() float64 {
+	return w.sum / float64(len(w.values))
+}
+
+func (w *window) size() int {
+	return len(w.values)
+}
+
+// oldest returns the first of the values once the window is full
+func (w *window) oldest() float64 {
+	return w.values[w.next]
+}
+
+// deviation returns the sum of the squared deviations of the values from
+// their mean
+func (w *window) deviation() float64 {
+	mean := w.mean()
+	var sum float64
+	for _, v := range w.values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum
+}
+
+type sma struct {
+	w *window
+}
+
+func newSMA(period int) *sma {
+	return &sma{w: newWindow(period)}
+}
+
+func (s *sma) add(close float64) (Point, bool) {
+	s.w.push(close)
+	if !s.w.full {
+		return Point{}, false
+	}
+	return Point{Value: s.w.mean()}, true
+}
+
+func (s *sma) lookback() int {
+	return len(s.w.values)
+}
+
+// ema is seeded with the simple average of its first period values
+type ema struct {
+	period int
+	k      float64
+	n      int
+	value  float64
+}
+
+func newEMA(period int) *ema {
+	return &ema{period: period, k: 2 / float64(period+1)}
+}
+
+func (e *ema) add(close float64) (Point, bool) {
+	e.n++
+	switch {
+	case e.n < e.period:
+		e.value += close / float64(e.period)
+		return Point{}, false
+	case e.n == e.period:
+		e.value += close / float64(e.period)
+	default:
+		e.value += (close - e.value) * e.k
+	}
+	return Point{Value: e.value}, true
+}
+
+// lookback lets the seed's weight decay to under 5% of the value
+func (e *ema) lookback() int {
+	return 4 * e.period
+}
+
+// rsi uses Wilder's smoothing of average gains and losses
+type rsi struct {
+	period           int
+	n                int
+	prev             float64
+	avgGain, avgLoss float64
+}
+
+func newRSI(period int) *rsi {
+	return &rsi{period: period}
+}
+
+func (r *rsi) add(close float64) (Point, bool) {
+	r.n++
+	if r.n == 1 {
+		r.prev = close
+		return Point{}, false
+	}
+
+	change := close - r.prev
+	r.prev = close
+	gain, loss := math.Max(change, 0), math.Max(-change, 0)
+
+	p := float64(r.period)
+	switch {
+	case r.n <= r.period:
+		r.avgGain += gain / p
+		r.avgLoss += loss / p
+		return Point{}, false
+	case r.n == r.period+1:
+		r.avgGain += gain / p
+		r.avgLoss += loss / p
+	default:
+		r.avgGain = (r.avgGain*(p-1) + gain) / p
+		r.avgLoss = (r.avgLoss*(p-1) + loss) / p
+	}
+
+	if r.avgLoss == 0 {
+		return Point{Value: 100}, true
+	}
+	return Point{Value: 100 - 100/(1+r.avgGain/r.avgLoss)}, true
+}
+
+func (r *rsi) lookback() int {
+	return 4*r.period + 1
+}
+
+type macd struct {
+	fast, slow, signal *ema
+}
+
+func newMACD(fast, slow, signal int) *macd {
+	return &macd{fast: newEMA(fast), slow: newEMA(slow), signal: newEMA(signal)}
+}
+
+func (m *macd) add(close float64) (Point, bool) {
+	fast, fastOK := m.fast.add(close)
+	slow, slowOK := m.slow.add(close)
+	if !fastOK || !slowOK {
+		return Point{}, false
+	}
+
+	line := fast.Value - slow.Value
+	signal, ok := m.signal.add(line)
+	if !ok {
+		return Point{}, false
+	}
+
+	histogram := line - signal.Value
+	return Point{Value: line, Signal: &signal.Value, Histogram: &histogram}, true
+}
+
+func (m *macd) lookback() int {
+	return m.slow.lookback() + m.signal.lookback()
+}
+
+type bollinger struct {
+	w     *window
+	width float64
+}
+
+func newBollinger(period int, width float64) *bollinger {
+	return &bollinger{w: newWindow(period), width: width}
+}
+
+func (b *bollinger) add(close float64) (Point, bool) {
+	b.w.push(close)
+	if !b.w.full {
+		return Point{}, false
+	}
+
+	mean := b.w.mean()
+	stddev := math.Sqrt(b.w.deviation() / float64(len(b.w.values)))
+
+	upper, lower := mean+b.width*stddev, mean-b.width*stddev
+	return Point{Value: mean, Upper: &upper, Lower: &lower}, true
+}
+
+func (b *bollinger) lookback() int {
+	return len(b.w.values)
+}
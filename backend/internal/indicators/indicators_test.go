@@ -0,0 +1,247 @@
+package indicators
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// feed returns the points calc reports for closes
+func feed(calc calculator, closes ...float64) []Point {
+	var points []Point
+	for _, c := range closes {
+		if p, ok := calc.add(c); ok {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+func values(points []Point) []float64 {
+	out := make([]float64, len(points))
+	for i, p := range points {
+		out[i] = p.Value
+	}
+	return out
+}
+
+func TestCalculators(t *testing.T) {
+	t.Run("sma", func(t *testing.T) {
+		assert.Equal(t, []float64{2, 3, 4}, values(feed(newSMA(3), 1, 2, 3, 4, 5)))
+	})
+
+	t.Run("ema is seeded with the sma", func(t *testing.T) {
+		assert.Equal(t, []float64{2, 3, 4}, values(feed(newEMA(3), 1, 2, 3, 4, 5)))
+	})
+
+	t.Run("rsi uses wilder smoothing", func(t *testing.T) {
+		got := values(feed(newRSI(2), 1, 2, 1, 2, 1))
+		require.Len(t, got, 3)
+		assert.InDelta(t, 50, got[0], 1e-9)
+		assert.InDelta(t, 75, got[1], 1e-9)
+		assert.InDelta(t, 37.5, got[2], 1e-9)
+
+		assert.Equal(t, []float64{100}, values(feed(newRSI(2), 1, 2, 3)), "no losses")
+	})
+
+	t.Run("macd starts once the signal line is formed", func(t *testing.T) {
+		closes := make([]float64, macdSlow+macdSignal-1)
+		for i := range closes {
+			closes[i] = 50
+		}
+		points := feed(newMACD(macdFast, macdSlow, macdSignal), closes...)
+		require.Len(t, points, 1)
+		assert.InDelta(t, 0, points[0].Value, 1e-9)
+		require.NotNil(t, points[0].Signal)
+		require.NotNil(t, points[0].Histogram)
+		assert.InDelta(t, 0, *points[0].Histogram, 1e-9)
+	})
+
+	t.Run("bollinger", func(t *testing.T) {
+		points := feed(newBollinger(2, 2), 1, 3)
+		require.Len(t, points, 1)
+		assert.Equal(t, 2.0, points[0].Value)
+		assert.Equal(t, 4.0, *points[0].Upper)
+		assert.Equal(t, 0.0, *points[0].Lower)
+	})
+}
+
+// closeSeries is a random walk of positive closes, generated for property tests
+type closeSeries []float64
+
+func (closeSeries) Generate(r *rand.Rand, size int) reflect.Value {
+	closes := make(closeSeries, 1+r.Intn(3*MaxPeriod))
+	price := 1 + r.Float64()*500
+	for i := range closes {
+		price *= 1 + (r.Float64()-0.5)/10
+		closes[i] = price
+	}
+	return reflect.ValueOf(closes)
+}
+
+// period maps a generated byte to a period of at least 1
+func period(p uint8) int {
+	return 1 + int(p)%50
+}
+
+func TestCalculatorProperties(t *testing.T) {
+	types := []Type{SMA, EMA, RSI, MACD, Bollinger}
+
+	t.Run("constant closes", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(p uint8, c uint16) bool {
+			value := 1 + float64(c)/100
+			closes := make([]float64, 3*MaxPeriod)
+			for i := range closes {
+				closes[i] = value
+			}
+			for _, typ := range types {
+				calc, _ := newCalculator(typ, period(p))
+				for _, point := range feed(calc, closes...) {
+					want := value
+					switch typ {
+					case RSI:
+						want = 100
+					case MACD:
+						want = 0
+					}
+					if math.Abs(point.Value-want) > 1e-9*value {
+						t.Logf("%s(%d) of constant %g is %g", typ, period(p), value, point.Value)
+						return false
+					}
+					if typ == Bollinger && (*point.Upper-*point.Lower) > 1e-9*value {
+						t.Logf("bands of constant %g are %g apart", value, *point.Upper-*point.Lower)
+						return false
+					}
+				}
+			}
+			return true
+		}, nil))
+	})
+
+	t.Run("averages stay within the closes", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(closes closeSeries, p uint8) bool {
+			low, high := slices.Min(closes), slices.Max(closes)
+			for _, typ := range []Type{SMA, EMA, Bollinger} {
+				calc, _ := newCalculator(typ, period(p))
+				for _, point := range feed(calc, closes...) {
+					if point.Value < low*(1-1e-12) || point.Value > high*(1+1e-12) {
+						t.Logf("%s(%d) is %g outside [%g, %g]", typ, period(p), point.Value, low, high)
+						return false
+					}
+					if typ == Bollinger && (*point.Lower > point.Value || *point.Upper < point.Value) {
+						return false
+					}
+				}
+			}
+			return true
+		}, nil))
+	})
+
+	t.Run("rsi is a percentage", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(closes closeSeries, p uint8) bool {
+			for _, point := range feed(newRSI(period(p)), closes...) {
+				if point.Value < 0 || point.Value > 100 {
+					return false
+				}
+			}
+			return true
+		}, nil))
+	})
+
+	// Restating closes for a split scales price indicators by the split
+	// ratio and leaves the RSI as it is
+	t.Run("scaled closes", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(closes closeSeries, p uint8, ratio uint8) bool {
+			k := 1 / float64(1+ratio%20)
+			scaled := make([]float64, len(closes))
+			for i, c := range closes {
+				scaled[i] = c * k
+			}
+			for _, typ := range types {
+				calc, _ := newCalculator(typ, period(p))
+				scaledCalc, _ := newCalculator(typ, period(p))
+				want, got := feed(calc, closes...), feed(scaledCalc, scaled...)
+				if len(want) != len(got) {
+					return false
+				}
+				factor := k
+				if typ == RSI {
+					factor = 1
+				}
+				for i := range want {
+					if math.Abs(got[i].Value-want[i].Value*factor) > 1e-6*(1+math.Abs(want[i].Value)) {
+						t.Logf("%s(%d) of closes scaled by %g is %g, want %g", typ, period(p), k, got[i].Value, want[i].Value*factor)
+						return false
+					}
+				}
+			}
+			return true
+		}, nil))
+	})
+}
+
+// streamRepository serves summaries through EachSummary only
+type streamRepository struct {
+	repository.DailySummaryRepository
+	summaries []models.DailySummary
+	from      int64
+}
+
+func (r *streamRepository) EachSummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error {
+	r.from = from
+	for _, s := range r.summaries {
+		if s.Timestamp >= from && s.Timestamp <= to {
+			if err := fn(s); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestService_Compute(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &streamRepository{}
+	for i := 0; i < 60; i++ {
+		day := start.AddDate(0, 0, i)
+		repo.summaries = append(repo.summaries, models.DailySummary{Ticker: "AAPL", Timestamp: day.Unix(), Close: float32(i + 1)})
+	}
+	svc := NewService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	from := start.AddDate(0, 0, 50).Unix()
+	to := start.AddDate(0, 0, 52).Unix()
+	points, err := svc.Compute(ctx, "AAPL", SMA, 5, from, to)
+	require.NoError(t, err)
+
+	// Closes before from warm the average up; only points in range are returned
+	require.Len(t, points, 3)
+	assert.Equal(t, from, points[0].Timestamp)
+	assert.Equal(t, 49.0, points[0].Value)
+	assert.Less(t, repo.from, from-5*24*60*60)
+
+	_, err = svc.Compute(ctx, "AAPL", "vwap", 5, from, to)
+	assert.ErrorIs(t, err, ErrInvalidIndicator)
+
+	_, err = svc.Compute(ctx, "AAPL", RSI, MaxPeriod+1, from, to)
+	assert.ErrorIs(t, err, ErrInvalidIndicator)
+
+	_, err = svc.Compute(ctx, "AAPL", RSI, 14, to, from)
+	assert.ErrorIs(t, err, service.ErrInvalidRange)
+
+	_, err = svc.Compute(ctx, "", RSI, 14, from, to)
+	assert.ErrorIs(t, err, service.ErrInvalidTicker)
+}
@@ -0,0 +1,108 @@
+package indicators
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	indicatorService Service
+	log              *zap.SugaredLogger
+}
+
+func NewHandler(indicators Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		indicatorService: indicators,
+		log:              log,
+	}
+}
+
+// Wire builds the indicators module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	return NewHandler(NewService(deps.DailySummaryRepository(), deps.Log), deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.GET("/tickers/:symbol/indicators", middleware.RequireScope(models.ScopeReadMarket), h.GetIndicator)
+	api.GET("/tickers/:symbol/features", middleware.RequireScope(models.ScopeReadMarket), h.GetFeatures)
+	api.GET("/tickers/:symbol/forecast", middleware.RequireScope(models.ScopeReadMarket), h.GetForecast)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/indicators", &openapi.Operation{
+		Tags:        []string{"Daily bars"},
+		Summary:     "Compute a technical indicator over a ticker's daily closes",
+		Description: "MACD uses the 12/26/9 periods and sets signal and histogram; Bollinger Bands set upper and lower around the middle band in value.",
+		Parameters: append([]openapi.Parameter{
+			openapi.PathParam("symbol", "Ticker symbol, case insensitive"),
+			{Name: "type", In: "query", Required: true, Schema: &openapi.Schema{
+				Type: "string",
+				Enum: []any{SMA, EMA, RSI, MACD, Bollinger},
+			}},
+			openapi.QueryParam("period", "Lookback in sessions (defaults to 14 for RSI, 20 otherwise)", &openapi.Schema{Type: "integer"}),
+		}, api.DateRangeParams()...),
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker": {Type: "string"},
+			"type":   {Type: "string"},
+			"period": {Type: "integer"},
+			"points": {Type: "array", Items: doc.Schema(Point{})},
+			"count":  {Type: "integer"},
+		}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+
+	columns := make(map[string]*openapi.Schema, len(featureCSVHeader))
+	columns["date"] = &openapi.Schema{Type: "array", Items: api.DateSchema}
+	columns["timestamp"] = &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "integer"}}
+	for _, name := range FeatureColumns {
+		columns[name] = &openapi.Schema{Type: "array", Items: &openapi.Schema{Type: "number", Nullable: true}}
+	}
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/features", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "Export a wide table of model features of a ticker's sessions",
+		Description: "Simple returns over 1, 5 and 20 sessions, the log return, annualized 20-session volatility, " +
+			"the 20-session SMA and EMA, RSI(14), MACD(12/26/9), 20-session Bollinger Bands and the volume's z-score " +
+			"against the 20 sessions before it, computed like the indicators from bars before the range. " +
+			"JSON holds a column per feature in data, in the order of columns, with null where a ticker's history " +
+			"is too short for a feature; CSV leaves those cells empty.",
+		Parameters: append([]openapi.Parameter{
+			openapi.PathParam("symbol", "Ticker symbol, case insensitive"),
+			api.FormatParam(),
+		}, api.DateRangeParams()...),
+		Responses: api.WithCSV(api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker":  {Type: "string"},
+			"columns": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			"data":    openapi.Object(columns),
+			"count":   {Type: "integer"},
+		}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+			http.StatusOK, "Features as CSV with a header row, one session per line"),
+	})
+
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/forecast", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "Forecast a ticker's closes with a statistical baseline",
+		Description: "Fits a baseline to the closes in the date range and forecasts the sessions after the last of them, " +
+			"with 80% and 95% bands. drift is a random walk with the mean daily log return; ses is simple exponential " +
+			"smoothing with the factor that best forecast the range a session ahead. Both are baselines for comparison, " +
+			"labeled by kind and notice, not predictions. The range needs at least 30 sessions.",
+		Parameters: append([]openapi.Parameter{
+			openapi.PathParam("symbol", "Ticker symbol, case insensitive"),
+			openapi.QueryParam("horizon", "Sessions (d), weeks of 5 sessions (w) or months of 21 sessions (m) ahead, up to 252 sessions; defaults to 30d", &openapi.Schema{Type: "string"}),
+			{Name: "method", In: "query", Schema: &openapi.Schema{
+				Type: "string",
+				Enum: []any{ForecastDrift, ForecastSES},
+			}},
+		}, api.DateRangeParams()...),
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker":   {Type: "string"},
+			"forecast": doc.Schema(Forecast{}),
+		}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,179 @@
+package indicators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// defaultRange is the range returned when no start of range is given
+const defaultRange = 365 * 24 * time.Hour
+
+var ErrInvalidIndicator = errors.New("invalid indicator")
+
+type Service interface {
+	Compute(ctx context.Context, symbol string, t Type, period int, from, to int64) ([]Point, error)
+	Features(ctx context.Context, symbol string, from, to int64) (*FeatureTable, error)
+	Forecast(ctx context.Context, symbol string, method ForecastMethod, horizon int, from, to int64) (*Forecast, error)
+}
+
+type indicatorService struct {
+	summaries repository.DailySummaryRepository
+	log       *zap.SugaredLogger
+}
+
+func NewService(summaries repository.DailySummaryRepository, log *zap.SugaredLogger) Service {
+	return &indicatorService{
+		summaries: summaries,
+		log:       log,
+	}
+}
+
+// Compute returns indicator t of symbol for the sessions with timestamps in
+// [from, to], oldest first. A zero to means now and a zero from means one year
+// before to, or the start of the history the caller's plan allows. Closes are
+// streamed from the repository, starting early enough for the first point to
+// be fully formed.
+func (s *indicatorService) Compute(ctx context.Context, symbol string, t Type, period int, from, to int64) ([]Point, error) {
+	if symbol == "" {
+		return nil, service.ErrInvalidTicker
+	}
+	if period < 1 || period > MaxPeriod {
+		return nil, fmt.Errorf("%w: period must be between 1 and %d", ErrInvalidIndicator, MaxPeriod)
+	}
+
+	from, to, err := resolveRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	calc, err := newCalculator(t, period)
+	if err != nil {
+		return nil, err
+	}
+
+	var points []Point
+	err = s.summaries.EachSummary(ctx, symbol, warmupStart(from, calc.lookback()), to, func(summary models.DailySummary) error {
+		point, ok := calc.add(float64(summary.Close))
+		if ok && summary.Timestamp >= from {
+			point.Timestamp = summary.Timestamp
+			points = append(points, point)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to stream daily summaries for indicator", "symbol", symbol, "type", t, "error", err)
+		return nil, fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+
+	return points, nil
+}
+
+// Features returns the features of symbol's sessions with timestamps in
+// [from, to], oldest first, over the range Compute takes. Like the
+// indicators, the features are computed from bars streamed from early enough
+// for the first session's to be fully formed.
+func (s *indicatorService) Features(ctx context.Context, symbol string, from, to int64) (*FeatureTable, error) {
+	if symbol == "" {
+		return nil, service.ErrInvalidTicker
+	}
+	from, to, err := resolveRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	f := newFeatures()
+	table := newFeatureTable()
+	err = s.summaries.EachSummary(ctx, symbol, warmupStart(from, f.lookback()), to, func(summary models.DailySummary) error {
+		row := f.add(summary)
+		if summary.Timestamp >= from {
+			table.append(summary.Timestamp, row)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to stream daily summaries for features", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+
+	return table, nil
+}
+
+// Forecast fits a statistical baseline to symbol's closes with timestamps in
+// [from, to], over the range Compute takes, and forecasts the closes of the
+// horizon sessions after the last of them
+func (s *indicatorService) Forecast(ctx context.Context, symbol string, method ForecastMethod, horizon int, from, to int64) (*Forecast, error) {
+	if symbol == "" {
+		return nil, service.ErrInvalidTicker
+	}
+	if horizon < 1 || horizon > MaxForecastHorizon {
+		return nil, fmt.Errorf("%w: horizon must be between 1 and %d sessions", ErrInvalidIndicator, MaxForecastHorizon)
+	}
+	if err := checkForecastMethod(method); err != nil {
+		return nil, err
+	}
+	from, to, err := resolveRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var closes []float64
+	var asOf int64
+	err = s.summaries.EachSummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		if summary.Close > 0 {
+			closes = append(closes, float64(summary.Close))
+			asOf = summary.Timestamp
+		}
+		return nil
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to stream daily summaries for forecast", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+	if len(closes) == 0 {
+		return nil, service.ErrTickerNotFound
+	}
+	if len(closes) < MinForecastHistory {
+		return nil, fmt.Errorf("%w: a forecast needs at least %d sessions of closes, the range has %d", ErrInvalidIndicator, MinForecastHistory, len(closes))
+	}
+
+	return forecast(method, closes, asOf, horizon)
+}
+
+// resolveRange defaults a zero to to now and a zero from to one year before
+// to, or the start of the history the caller's plan allows, and checks the
+// range against the plan
+func resolveRange(ctx context.Context, from, to int64) (int64, int64, error) {
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - int64(defaultRange/time.Second)
+		// A default range is cut to the plan's history rather than rejected
+		if start := service.PlanHistoryStart(ctx); start > from && start <= to {
+			from = start
+		}
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("%w: from must not be after to", service.ErrInvalidRange)
+	}
+	if err := service.CheckHistoryDepth(ctx, from); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// warmupStart returns a timestamp at least sessions trading days before from,
+// allowing for weekends and holidays
+func warmupStart(from int64, sessions int) int64 {
+	days := sessions*7/5 + 7
+	return time.Unix(from, 0).UTC().AddDate(0, 0, -days).Unix()
+}
@@ -0,0 +1,315 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/ratelimit"
+)
+
+const (
+	// DefaultAlphaVantageBaseURL is the Alpha Vantage API
+	DefaultAlphaVantageBaseURL = "https://www.alphavantage.co"
+	// alphaVantageCompactDays is how far back the compact daily series, the
+	// latest 100 trading days, reaches for certain
+	alphaVantageCompactDays = 130
+)
+
+// AlphaVantage fetches market data from the Alpha Vantage API. Prices are
+// split adjusted like Polygon's. Its plans limit requests per minute, so every
+// request waits for the client's throttle; a process should share one client.
+// Alpha Vantage serves no whole market daily bars, so it cannot run the
+// end-of-day ingest.
+type AlphaVantage struct {
+	baseURL  string
+	apiKey   string
+	client   *http.Client
+	throttle *ratelimit.Limiter
+	clock    clock.Clock
+}
+
+// NewAlphaVantage creates an Alpha Vantage client making at most
+// requestsPerMinute requests, evenly spaced. An empty baseURL uses
+// DefaultAlphaVantageBaseURL. Ranges are measured back from c's now to pick
+// the series requested.
+func NewAlphaVantage(baseURL, apiKey string, timeout time.Duration, requestsPerMinute int, c clock.Clock) *AlphaVantage {
+	if baseURL == "" {
+		baseURL = DefaultAlphaVantageBaseURL
+	}
+	return &AlphaVantage{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+		throttle: ratelimit.New(ratelimit.Limit{Rate: float64(requestsPerMinute) / 60, Burst: 1}),
+		clock:    c,
+	}
+}
+
+// alphaVantageMessage is the body Alpha Vantage answers with, with status
+// 200, instead of the data: an error, or a note that the key's plan does not
+// allow the request, e.g. once its daily requests are used up
+type alphaVantageMessage struct {
+	ErrorMessage string `json:"Error Message"`
+	Note         string `json:"Note"`
+	Information  string `json:"Information"`
+}
+
+// alphaVantageBar is a day of the daily adjusted series. Its prices are as
+// traded; the split coefficient is the ratio of a split effective that day.
+type alphaVantageBar struct {
+	Open             string `json:"1. open"`
+	High             string `json:"2. high"`
+	Low              string `json:"3. low"`
+	Close            string `json:"4. close"`
+	Volume           string `json:"6. volume"`
+	SplitCoefficient string `json:"8. split coefficient"`
+}
+
+type alphaVantageDailyResponse struct {
+	TimeSeries map[string]alphaVantageBar `json:"Time Series (Daily)"`
+}
+
+type alphaVantageMatch struct {
+	Symbol   string `json:"1. symbol"`
+	Name     string `json:"2. name"`
+	Type     string `json:"3. type"`
+	Region   string `json:"4. region"`
+	Currency string `json:"8. currency"`
+}
+
+type alphaVantageSearchResponse struct {
+	BestMatches []alphaVantageMatch `json:"bestMatches"`
+}
+
+// alphaVantageExchanges maps the exchanges of the listings to the MIC codes
+// Polygon names primary exchanges by
+var alphaVantageExchanges = map[string]string{
+	"NYSE":      "XNYS",
+	"NASDAQ":    "XNAS",
+	"NYSE ARCA": "ARCX",
+	"NYSE MKT":  "XASE",
+	"BATS":      "BATS",
+}
+
+// alphaVantageTypes maps asset types to Polygon's ticker types
+var alphaVantageTypes = map[string]string{
+	"Stock":  "CS",
+	"Equity": "CS",
+	"ETF":    "ETF",
+}
+
+// GroupedDaily is not served by Alpha Vantage, which only has the daily bars
+// of one ticker at a time
+func (a *AlphaVantage) GroupedDaily(ctx context.Context, date time.Time) ([]models.DailySummary, error) {
+	return nil, fmt.Errorf("alpha vantage serves no grouped daily bars: %w", errors.ErrUnsupported)
+}
+
+// FetchDailySummaries returns the daily summaries of one ticker over [from,
+// to], oldest first, from its daily adjusted series. The compact series is
+// requested when it reaches back to from.
+func (a *AlphaVantage) FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error) {
+	outputSize := "full"
+	if a.clock.Now().Sub(from) < alphaVantageCompactDays*24*time.Hour {
+		outputSize = "compact"
+	}
+
+	var resp alphaVantageDailyResponse
+	if err := a.getJSON(ctx, url.Values{
+		"function":   {"TIME_SERIES_DAILY_ADJUSTED"},
+		"symbol":     {symbol},
+		"outputsize": {outputSize},
+	}, &resp); err != nil {
+		return nil, err
+	}
+
+	dates := make([]string, 0, len(resp.TimeSeries))
+	for date := range resp.TimeSeries {
+		dates = append(dates, date)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dates)))
+
+	// Walking back from the latest day, each split scales the days before it
+	first, last := from.Format(models.DateLayout), to.Format(models.DateLayout)
+	var summaries []models.DailySummary
+	factor := 1.0
+	for _, date := range dates {
+		bar := resp.TimeSeries[date]
+		if date >= first && date <= last {
+			summary, err := bar.summary(symbol, date, factor)
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, summary)
+		}
+		if split, err := strconv.ParseFloat(bar.SplitCoefficient, 64); err == nil && split > 0 {
+			factor *= split
+		}
+	}
+
+	for i, j := 0, len(summaries)-1; i < j; i, j = i+1, j-1 {
+		summaries[i], summaries[j] = summaries[j], summaries[i]
+	}
+	return summaries, nil
+}
+
+// Tickers returns the reference data of every active US stock and ETF listing
+func (a *AlphaVantage) Tickers(ctx context.Context) ([]models.Ticker, error) {
+	body, err := a.get(ctx, url.Values{"function": {"LISTING_STATUS"}})
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode alpha vantage listings: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// The columns are symbol, name, exchange, assetType, ipoDate,
+	// delistingDate and status
+	tickers := make([]models.Ticker, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if len(record) < 7 || record[6] != "Active" {
+			continue
+		}
+		tickers = append(tickers, models.Ticker{
+			Ticker:          record[0],
+			Name:            record[1],
+			Market:          "stocks",
+			Locale:          "us",
+			PrimaryExchange: alphaVantageExchanges[record[2]],
+			Type:            alphaVantageTypes[record[3]],
+			Active:          1,
+			Currency:        "USD",
+		})
+	}
+	return tickers, nil
+}
+
+// SearchSymbols returns the tickers whose symbol or name best match keywords,
+// best first
+func (a *AlphaVantage) SearchSymbols(ctx context.Context, keywords string) ([]models.Ticker, error) {
+	var resp alphaVantageSearchResponse
+	if err := a.getJSON(ctx, url.Values{"function": {"SYMBOL_SEARCH"}, "keywords": {keywords}}, &resp); err != nil {
+		return nil, err
+	}
+
+	tickers := make([]models.Ticker, 0, len(resp.BestMatches))
+	for _, m := range resp.BestMatches {
+		locale := "global"
+		if m.Region == "United States" {
+			locale = "us"
+		}
+		tickers = append(tickers, models.Ticker{
+			Ticker:   m.Symbol,
+			Name:     m.Name,
+			Market:   "stocks",
+			Locale:   locale,
+			Type:     alphaVantageTypes[m.Type],
+			Active:   1,
+			Currency: strings.ToUpper(m.Currency),
+		})
+	}
+	return tickers, nil
+}
+
+// summary returns the bar of symbol on date with its prices divided, and its
+// volume multiplied, by the splits after it
+func (bar alphaVantageBar) summary(symbol, date string, splits float64) (models.DailySummary, error) {
+	day, err := time.ParseInLocation(models.DateLayout, date, marketcalendar.Location())
+	if err != nil {
+		return models.DailySummary{}, fmt.Errorf("invalid alpha vantage date %q: %w", date, err)
+	}
+
+	var values [5]float64
+	for i, field := range []string{bar.Open, bar.High, bar.Low, bar.Close, bar.Volume} {
+		if values[i], err = strconv.ParseFloat(field, 64); err != nil {
+			return models.DailySummary{}, fmt.Errorf("invalid alpha vantage bar of %s on %s: %w", symbol, date, err)
+		}
+	}
+
+	return models.DailySummary{
+		Ticker:    symbol,
+		Open:      float32(values[0] / splits),
+		High:      float32(values[1] / splits),
+		Low:       float32(values[2] / splits),
+		Close:     float32(values[3] / splits),
+		Volume:    float32(values[4] * splits),
+		Timestamp: day.Unix(),
+	}, nil
+}
+
+// getJSON requests a function and decodes its JSON response into out
+func (a *AlphaVantage) getJSON(ctx context.Context, params url.Values, out any) error {
+	body, err := a.get(ctx, params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to decode alpha vantage response: %w", err)
+	}
+	return nil
+}
+
+// get requests a function once the throttle allows and returns the response
+// body, failing on the errors and limit notes Alpha Vantage answers with
+func (a *AlphaVantage) get(ctx context.Context, params url.Values) ([]byte, error) {
+	if err := a.throttle.Wait(ctx, a.apiKey); err != nil {
+		return nil, err
+	}
+
+	params.Set("apikey", a.apiKey)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.baseURL+"/query?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build alpha vantage request: %w", err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		// The request URL carries the API key, so errors name the endpoint only
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			urlErr.URL = a.baseURL + "/query"
+		}
+		return nil, fmt.Errorf("failed to call alpha vantage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("alpha vantage responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alpha vantage response: %w", err)
+	}
+
+	var msg alphaVantageMessage
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) && json.Unmarshal(body, &msg) == nil {
+		switch {
+		case msg.ErrorMessage != "":
+			return nil, fmt.Errorf("alpha vantage rejected %s: %s", params.Get("function"), msg.ErrorMessage)
+		case msg.Note != "":
+			return nil, fmt.Errorf("alpha vantage declined %s: %s", params.Get("function"), msg.Note)
+		case msg.Information != "":
+			return nil, fmt.Errorf("alpha vantage declined %s: %s", params.Get("function"), msg.Information)
+		}
+	}
+	return body, nil
+}
@@ -0,0 +1,159 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// alphaVantageNow is the time the test clients measure ranges back from
+var alphaVantageNow = time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestAlphaVantage(t *testing.T, requestsPerMinute int, handler http.HandlerFunc) *AlphaVantage {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return NewAlphaVantage(srv.URL, "secret", time.Second, requestsPerMinute, clock.NewFake(alphaVantageNow))
+}
+
+func TestAlphaVantage_FetchDailySummaries(t *testing.T) {
+	a := newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/query", r.URL.Path)
+		assert.Equal(t, "TIME_SERIES_DAILY_ADJUSTED", r.URL.Query().Get("function"))
+		assert.Equal(t, "AAPL", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "full", r.URL.Query().Get("outputsize"), "ranges past the compact series need the full one")
+		assert.Equal(t, "secret", r.URL.Query().Get("apikey"))
+		_, _ = w.Write([]byte(`{"Meta Data":{"2. Symbol":"AAPL"},"Time Series (Daily)":{
+			"2025-03-10":{"1. open":"51","2. high":"52","3. low":"50","4. close":"51.5","5. adjusted close":"51.5","6. volume":"4000","7. dividend amount":"0.0000","8. split coefficient":"1.0"},
+			"2025-03-07":{"1. open":"50","2. high":"51","3. low":"49","4. close":"50","5. adjusted close":"50","6. volume":"4000","7. dividend amount":"0.0000","8. split coefficient":"2.0"},
+			"2025-03-06":{"1. open":"98","2. high":"102","3. low":"96","4. close":"100","5. adjusted close":"50","6. volume":"1000","7. dividend amount":"0.0000","8. split coefficient":"1.0"},
+			"2025-03-05":{"1. open":"90","2. high":"99","3. low":"90","4. close":"98","5. adjusted close":"49","6. volume":"1000","7. dividend amount":"0.0000","8. split coefficient":"1.0"}
+		}}`))
+	})
+
+	summaries, err := a.FetchDailySummaries(context.Background(), "AAPL",
+		time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []models.DailySummary{
+		{Ticker: "AAPL", Open: 49, High: 51, Low: 48, Close: 50, Volume: 2000, Timestamp: 1741237200},
+		{Ticker: "AAPL", Open: 50, High: 51, Low: 49, Close: 50, Volume: 4000, Timestamp: 1741323600},
+	}, summaries, "oldest first, with the days before a split adjusted for it")
+	assert.Equal(t, "2025-03-06", summaries[0].Date())
+	assert.Equal(t, "2025-03-07", summaries[1].Date())
+
+	t.Run("reports errors", func(t *testing.T) {
+		a := newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"Error Message":"Invalid API call."}`))
+		})
+		_, err := a.FetchDailySummaries(context.Background(), "NOPE", alphaVantageNow, alphaVantageNow)
+		assert.ErrorContains(t, err, "Invalid API call.")
+
+		a = newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "compact", r.URL.Query().Get("outputsize"))
+			_, _ = w.Write([]byte(`{"Information":"Our standard API rate limit is 25 requests per day."}`))
+		})
+		_, err = a.FetchDailySummaries(context.Background(), "AAPL", alphaVantageNow, alphaVantageNow)
+		assert.ErrorContains(t, err, "25 requests per day")
+	})
+
+	t.Run("network errors do not reveal the api key", func(t *testing.T) {
+		a := NewAlphaVantage("http://127.0.0.1:1", "secret", time.Second, 6000, clock.NewFake(alphaVantageNow))
+		_, err := a.FetchDailySummaries(context.Background(), "AAPL", alphaVantageNow, alphaVantageNow)
+		require.Error(t, err)
+		assert.NotContains(t, err.Error(), "secret")
+		assert.ErrorContains(t, err, "http://127.0.0.1:1/query")
+	})
+}
+
+func TestAlphaVantage_OutputSize(t *testing.T) {
+	var outputSize string
+	a := newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+		outputSize = r.URL.Query().Get("outputsize")
+		_, _ = w.Write([]byte(`{"Time Series (Daily)":{}}`))
+	})
+
+	for days, want := range map[int]string{0: "compact", alphaVantageCompactDays - 1: "compact", alphaVantageCompactDays: "full", 365: "full"} {
+		from := alphaVantageNow.AddDate(0, 0, -days)
+		_, err := a.FetchDailySummaries(context.Background(), "AAPL", from, alphaVantageNow)
+		require.NoError(t, err)
+		assert.Equal(t, want, outputSize, "ranges from %d days ago", days)
+	}
+}
+
+func TestAlphaVantage_Throttle(t *testing.T) {
+	calls := 0
+	a := newTestAlphaVantage(t, 1200, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"bestMatches":[]}`))
+	})
+
+	start := time.Now()
+	for range 3 {
+		_, err := a.SearchSymbols(context.Background(), "apple")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 3, calls)
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "requests are spaced a minute over the limit apart")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := a.SearchSymbols(ctx, "apple")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 3, calls, "requests waiting for the throttle stop with their context")
+}
+
+func TestAlphaVantage_SearchSymbols(t *testing.T) {
+	a := newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "SYMBOL_SEARCH", r.URL.Query().Get("function"))
+		assert.Equal(t, "apple", r.URL.Query().Get("keywords"))
+		_, _ = w.Write([]byte(`{"bestMatches":[
+			{"1. symbol":"AAPL","2. name":"Apple Inc","3. type":"Equity","4. region":"United States","8. currency":"USD","9. matchScore":"0.8889"},
+			{"1. symbol":"APC.DEX","2. name":"Apple Inc","3. type":"Equity","4. region":"XETRA","8. currency":"EUR","9. matchScore":"0.7143"}
+		]}`))
+	})
+
+	tickers, err := a.SearchSymbols(context.Background(), "apple")
+	require.NoError(t, err)
+	assert.Equal(t, []models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc", Market: "stocks", Locale: "us", Type: "CS", Active: 1, Currency: "USD"},
+		{Ticker: "APC.DEX", Name: "Apple Inc", Market: "stocks", Locale: "global", Type: "CS", Active: 1, Currency: "EUR"},
+	}, tickers)
+	for _, ticker := range tickers {
+		assert.NoError(t, ticker.Validate())
+	}
+}
+
+func TestAlphaVantage_Tickers(t *testing.T) {
+	a := newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "LISTING_STATUS", r.URL.Query().Get("function"))
+		_, _ = w.Write([]byte("symbol,name,exchange,assetType,ipoDate,delistingDate,status\r\n" +
+			"AAPL,Apple Inc,NASDAQ,Stock,1980-12-12,null,Active\r\n" +
+			"SPY,SPDR S&P 500 ETF Trust,NYSE ARCA,ETF,1993-01-29,null,Active\r\n" +
+			"OLD,Delisted Corp,NYSE,Stock,1990-01-02,2020-01-02,Delisted\r\n"))
+	})
+
+	tickers, err := a.Tickers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc", Market: "stocks", Locale: "us", PrimaryExchange: "XNAS", Type: "CS", Active: 1, Currency: "USD"},
+		{Ticker: "SPY", Name: "SPDR S&P 500 ETF Trust", Market: "stocks", Locale: "us", PrimaryExchange: "ARCX", Type: "ETF", Active: 1, Currency: "USD"},
+	}, tickers)
+}
+
+func TestAlphaVantage_GroupedDaily(t *testing.T) {
+	a := newTestAlphaVantage(t, 6000, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("grouped daily bars are not requested")
+	})
+	_, err := a.GroupedDaily(context.Background(), time.Now())
+	assert.True(t, errors.Is(err, errors.ErrUnsupported))
+}
@@ -0,0 +1,201 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+)
+
+const (
+	// backfillJobPrefix starts the checkpoint job name of every daily backfill
+	backfillJobPrefix = "daily-backfill:"
+	// backfillChunkDays bounds the range of one fetch, so a long backfill of
+	// a ticker is checkpointed along the way
+	backfillChunkDays = 366
+)
+
+// Checkpoints store the progress of long-running jobs, as the settings
+// service does
+type Checkpoints interface {
+	GetCheckpoint(ctx context.Context, job string) (*models.Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, job string, checkpoint models.Checkpoint) error
+	ClearCheckpoint(ctx context.Context, job string) error
+}
+
+// BackfillResult is the outcome of a backfill
+type BackfillResult struct {
+	// Job names the backfill's checkpoint
+	Job string
+	// Completed counts the tickers this run backfilled, and Resumed those an
+	// interrupted run had completed before
+	Completed int
+	Resumed   int
+	Stored    int
+	// Failed lists the tickers whose bars could not be fetched or stored
+	Failed []string
+}
+
+// BackfillJob names the checkpoint of a backfill of symbols over [from, to],
+// so running the same backfill again, with the symbols in any order, resumes it
+func BackfillJob(symbols []string, from, to time.Time) string {
+	h := fnv.New32a()
+	for _, symbol := range normalizeSymbols(symbols) {
+		h.Write([]byte(symbol))
+		h.Write([]byte{','})
+	}
+	return fmt.Sprintf("%s%s:%s:%08x", backfillJobPrefix,
+		from.UTC().Format(models.DateLayout), to.UTC().Format(models.DateLayout), h.Sum32())
+}
+
+// Backfill stores the provider's daily summaries of symbols over [from, to],
+// one ticker at a time in symbol order and at most backfillChunkDays per
+// fetch. Progress is checkpointed after every chunk, so a backfill interrupted
+// by a crash or ^C resumes after the last chunk stored. A ticker that fails is
+// reported and skipped, so one delisted symbol does not hold up the rest.
+func (i *Ingester) Backfill(ctx context.Context, checkpoints Checkpoints, symbols []string, from, to time.Time) (BackfillResult, error) {
+	symbols = normalizeSymbols(symbols)
+	if len(symbols) == 0 {
+		return BackfillResult{}, service.ErrInvalidTicker
+	}
+	from, to = service.StartOfDay(from), service.StartOfDay(to)
+	if from.After(to) {
+		return BackfillResult{}, fmt.Errorf("%w: from must not be after to", service.ErrInvalidRange)
+	}
+
+	job := BackfillJob(symbols, from, to)
+	result := BackfillResult{Job: job}
+	first, resumeFrom, err := i.resumeBackfill(ctx, checkpoints, job, symbols, from, to)
+	if err != nil {
+		return result, err
+	}
+	result.Resumed = first
+
+	for n := first; n < len(symbols); n++ {
+		symbol := symbols[n]
+		start := from
+		if n == first {
+			start = resumeFrom
+		}
+
+		stored, err := i.backfillTicker(ctx, checkpoints, job, symbol, start, to)
+		result.Stored += stored
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			i.log.Errorw("ticker backfill failed", "job", job, "symbol", symbol, "error", err)
+			result.Failed = append(result.Failed, symbol)
+			i.saveCheckpoint(ctx, checkpoints, job, models.Checkpoint{Ticker: symbol, Date: to.Format(models.DateLayout)})
+			continue
+		}
+
+		result.Completed++
+		if stored > 0 {
+			service.PublishEvent(ctx, i.events, i.log, models.EventDailySummaryIngested, models.EventDailySummaryIngestedVersion, models.DailySummaryIngestedEvent{
+				Scope:  models.IngestScopeTicker,
+				Symbol: symbol,
+				From:   start.Format(models.DateLayout),
+				To:     to.Format(models.DateLayout),
+				Stored: stored,
+			})
+		}
+	}
+
+	if err := checkpoints.ClearCheckpoint(ctx, job); err != nil {
+		i.log.Warnw("failed to clear backfill checkpoint", "job", job, "error", err)
+	}
+	i.log.Infow("daily backfill completed", "job", job, "completed", result.Completed, "resumed", result.Resumed,
+		"stored", result.Stored, "failed", len(result.Failed))
+	return result, nil
+}
+
+// resumeBackfill returns the index of the first ticker left to backfill and
+// the day to start it from, recording the job before any work when it is new
+func (i *Ingester) resumeBackfill(ctx context.Context, checkpoints Checkpoints, job string, symbols []string, from, to time.Time) (int, time.Time, error) {
+	checkpoint, err := checkpoints.GetCheckpoint(ctx, job)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		if err := checkpoints.SaveCheckpoint(ctx, job, models.Checkpoint{}); err != nil {
+			return 0, time.Time{}, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+		return 0, from, nil
+	}
+	if checkpoint.Ticker == "" {
+		return 0, from, nil
+	}
+
+	n, found := slices.BinarySearch(symbols, checkpoint.Ticker)
+	done, err := time.Parse(models.DateLayout, checkpoint.Date)
+	if !found || err != nil {
+		return 0, time.Time{}, fmt.Errorf("malformed checkpoint for %s: %+v", job, *checkpoint)
+	}
+	i.log.Infow("resuming daily backfill", "job", job, "symbol", checkpoint.Ticker, "after", checkpoint.Date)
+	if !done.Before(to) {
+		return n + 1, from, nil
+	}
+	return n, done.AddDate(0, 0, 1), nil
+}
+
+// backfillTicker stores a ticker's daily summaries over [from, to] a chunk at
+// a time and returns how many were stored. Summaries that fail validation are
+// skipped.
+func (i *Ingester) backfillTicker(ctx context.Context, checkpoints Checkpoints, job, symbol string, from, to time.Time) (int, error) {
+	stored := 0
+	for start := from; !start.After(to); {
+		end := start.AddDate(0, 0, backfillChunkDays-1)
+		if end.After(to) {
+			end = to
+		}
+
+		fetched, err := i.provider.FetchDailySummaries(ctx, symbol, start, end)
+		if err != nil {
+			return stored, fmt.Errorf("failed to fetch daily summaries: %w", err)
+		}
+		summaries := make([]models.DailySummary, 0, len(fetched))
+		for _, summary := range fetched {
+			summary.Ticker = symbol
+			if err := summary.Validate(); err != nil {
+				i.log.Warnw("skipping invalid daily summary", "symbol", symbol, "timestamp", summary.Timestamp, "error", err)
+				continue
+			}
+			summaries = append(summaries, summary)
+		}
+		if err := i.summaries.PutSummaries(repository.WithRevisionSource(ctx, RevisionSourceBackfill), summaries); err != nil {
+			return stored, fmt.Errorf("failed to store daily summaries: %w", err)
+		}
+		stored += len(summaries)
+
+		i.saveCheckpoint(ctx, checkpoints, job, models.Checkpoint{Ticker: symbol, Date: end.Format(models.DateLayout)})
+		start = end.AddDate(0, 0, 1)
+	}
+	return stored, nil
+}
+
+// saveCheckpoint records progress, even once ctx is cancelled right after a
+// chunk was stored; a lost checkpoint only costs refetching a chunk on resume
+func (i *Ingester) saveCheckpoint(ctx context.Context, checkpoints Checkpoints, job string, checkpoint models.Checkpoint) {
+	if err := checkpoints.SaveCheckpoint(context.WithoutCancel(ctx), job, checkpoint); err != nil {
+		i.log.Warnw("failed to save backfill checkpoint", "job", job, "symbol", checkpoint.Ticker, "date", checkpoint.Date, "error", err)
+	}
+}
+
+// normalizeSymbols uppercases, sorts and dedupes symbols, dropping blank ones
+func normalizeSymbols(symbols []string) []string {
+	normalized := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		if symbol = strings.ToUpper(strings.TrimSpace(symbol)); symbol != "" {
+			normalized = append(normalized, symbol)
+		}
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
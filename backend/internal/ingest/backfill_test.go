@@ -0,0 +1,123 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// rangeProvider serves one bar per day of the requested range and records the
+// fetches; fetches of symbols in fail fail
+type rangeProvider struct {
+	fakeProvider
+	fail    map[string]bool
+	fetches []string
+	// cancel is called after the fetch numbered cancelAfter
+	cancel      context.CancelFunc
+	cancelAfter int
+}
+
+func (p *rangeProvider) FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error) {
+	p.fetches = append(p.fetches, symbol+" "+from.Format(models.DateLayout)+" "+to.Format(models.DateLayout))
+	if p.cancel != nil && len(p.fetches) == p.cancelAfter {
+		p.cancel()
+		return nil, ctx.Err()
+	}
+	if p.fail[symbol] {
+		return nil, errors.New("unknown ticker")
+	}
+
+	var bars []models.DailySummary
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		bars = append(bars, models.DailySummary{Timestamp: day.Unix(), Open: 1, High: 2, Low: 1, Close: 2, Volume: 10})
+	}
+	return bars, nil
+}
+
+// memoryCheckpoints keeps checkpoints in a map
+type memoryCheckpoints map[string]models.Checkpoint
+
+func (m memoryCheckpoints) GetCheckpoint(ctx context.Context, job string) (*models.Checkpoint, error) {
+	if checkpoint, ok := m[job]; ok {
+		return &checkpoint, nil
+	}
+	return nil, nil
+}
+
+func (m memoryCheckpoints) SaveCheckpoint(ctx context.Context, job string, checkpoint models.Checkpoint) error {
+	m[job] = checkpoint
+	return nil
+}
+
+func (m memoryCheckpoints) ClearCheckpoint(ctx context.Context, job string) error {
+	delete(m, job)
+	return nil
+}
+
+func TestIngester_Backfill(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	provider := &rangeProvider{fail: map[string]bool{"GONE": true}}
+	summaries := &fakeSummaries{}
+	publisher := &eventstest.RecordingPublisher{}
+	checkpoints := memoryCheckpoints{}
+
+	result, err := New(provider, nil, summaries, nil, publisher, zap.NewNop().Sugar()).
+		Backfill(context.Background(), checkpoints, []string{"msft", "AAPL", "GONE", "aapl"}, from, to)
+
+	require.NoError(t, err)
+	assert.Equal(t, BackfillJob([]string{"AAPL", "GONE", "MSFT"}, from, to), result.Job)
+	assert.Equal(t, 2, result.Completed)
+	assert.Equal(t, 6, result.Stored)
+	assert.Equal(t, []string{"GONE"}, result.Failed, "a failing ticker does not stop the others")
+	assert.Equal(t, []string{"AAPL 2024-01-01 2024-01-03", "GONE 2024-01-01 2024-01-03", "MSFT 2024-01-01 2024-01-03"}, provider.fetches)
+	assert.Equal(t, "AAPL", summaries.stored[0].Ticker)
+	assert.Len(t, publisher.Events(), 2)
+	assert.Empty(t, checkpoints, "a finished backfill clears its checkpoint")
+
+	_, err = New(provider, nil, summaries, nil, nil, zap.NewNop().Sugar()).Backfill(context.Background(), checkpoints, []string{" "}, from, to)
+	assert.ErrorIs(t, err, service.ErrInvalidTicker)
+	_, err = New(provider, nil, summaries, nil, nil, zap.NewNop().Sugar()).Backfill(context.Background(), checkpoints, []string{"AAPL"}, to, from)
+	assert.ErrorIs(t, err, service.ErrInvalidRange)
+}
+
+func TestIngester_BackfillResumes(t *testing.T) {
+	from := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 30, 0, 0, 0, 0, time.UTC)
+	symbols := []string{"AAPL", "MSFT", "NVDA"}
+	checkpoints := memoryCheckpoints{}
+	summaries := &fakeSummaries{}
+
+	// Interrupted while fetching the second chunk of MSFT
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupted := &rangeProvider{cancel: cancel, cancelAfter: 4}
+	result, err := New(interrupted, nil, summaries, nil, nil, zap.NewNop().Sugar()).Backfill(ctx, checkpoints, symbols, from, to)
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, result.Completed)
+	assert.Equal(t, []string{
+		"AAPL 2022-01-01 2023-01-01", "AAPL 2023-01-02 2023-06-30",
+		"MSFT 2022-01-01 2023-01-01", "MSFT 2023-01-02 2023-06-30",
+	}, interrupted.fetches, "long ranges are fetched a chunk at a time")
+	assert.Equal(t, models.Checkpoint{Ticker: "MSFT", Date: "2023-01-01"}, checkpoints[BackfillJob(symbols, from, to)])
+
+	resumed := &rangeProvider{}
+	result, err = New(resumed, nil, summaries, nil, nil, zap.NewNop().Sugar()).
+		Backfill(context.Background(), checkpoints, []string{"NVDA", "MSFT", "AAPL"}, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Resumed)
+	assert.Equal(t, 2, result.Completed)
+	assert.Equal(t, []string{
+		"MSFT 2023-01-02 2023-06-30",
+		"NVDA 2022-01-01 2023-01-01", "NVDA 2023-01-02 2023-06-30",
+	}, resumed.fetches, "the backfill resumes after the last chunk stored")
+	assert.Empty(t, checkpoints)
+}
@@ -0,0 +1,202 @@
+// Package ingest loads end-of-day market data from a market data provider into
+// the tickers and daily summary tables, on a schedule after the market closes,
+// and stores the market data messages of an SQS queue as they arrive.
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events"
+
+	"go.uber.org/zap"
+)
+
+// Revision sources name the writers of the daily summaries this package
+// stores, in the corrections they make
+const (
+	RevisionSourceDaily    = "daily-ingest"
+	RevisionSourceBackfill = "backfill"
+	RevisionSourceQueue    = "ingest-queue"
+)
+
+// Ingester writes provider data through the repositories
+type Ingester struct {
+	provider  MarketDataProvider
+	tickers   repository.TickerRepository
+	summaries repository.DailySummaryRepository
+	anomalies service.AnomalyService
+	events    events.Publisher
+	log       *zap.SugaredLogger
+}
+
+// New returns an ingester that publishes a DailySummaryIngested event for
+// every trading day it stores; a nil publisher publishes none. The summaries
+// are screened through anomalies first, unless it is nil.
+func New(provider MarketDataProvider, tickers repository.TickerRepository, summaries repository.DailySummaryRepository, anomalies service.AnomalyService, publisher events.Publisher, log *zap.SugaredLogger) *Ingester {
+	return &Ingester{
+		provider:  provider,
+		tickers:   tickers,
+		summaries: summaries,
+		anomalies: anomalies,
+		events:    publisher,
+		log:       log,
+	}
+}
+
+// Wire builds an ingester reading from Polygon.io or Alpha Vantage, whichever
+// has an API key configured, or returns nil when neither has
+func Wire(deps app.Deps) *Ingester {
+	cfg := deps.Config
+	var provider MarketDataProvider
+	switch {
+	case cfg.PolygonAPIKey != "":
+		provider = NewPolygon(cfg.PolygonBaseURL, cfg.PolygonAPIKey, cfg.PolygonTimeout)
+	case cfg.AlphaVantageAPIKey != "":
+		provider = NewAlphaVantage(cfg.AlphaVantageBaseURL, cfg.AlphaVantageAPIKey, cfg.AlphaVantageTimeout, cfg.AlphaVantageRequestsPerMinute, deps.Clock)
+	default:
+		return nil
+	}
+
+	return New(provider, deps.TickerRepository(), deps.DailySummaryRepository(), deps.ScreenedIngestion(), deps.Events, deps.Log)
+}
+
+// Provider returns the market data provider the ingester reads from
+func (i *Ingester) Provider() MarketDataProvider {
+	return i.provider
+}
+
+// EODJobs returns the jobs loading a trading day's data. They must run before
+// the jobs computing on it, and refresh the tickers before their summaries.
+func (i *Ingester) EODJobs() []jobs.Job {
+	return []jobs.Job{
+		jobs.NewJob("ingest-tickers", func(ctx context.Context, date time.Time) error {
+			_, err := i.RefreshTickers(ctx)
+			return err
+		}),
+		jobs.NewJob("ingest-daily-summaries", func(ctx context.Context, date time.Time) error {
+			_, err := i.IngestDay(ctx, date)
+			return err
+		}),
+	}
+}
+
+// RefreshTickers stores the provider's active tickers that changed and returns
+// how many were stored; unchanged ones are not rewritten, so they are not
+// synced to clients again. Sector and industry, which the provider does not
+// supply, are kept from the stored tickers. Stored tickers the provider no
+// longer lists are deactivated.
+func (i *Ingester) RefreshTickers(ctx context.Context) (int, error) {
+	fetched, err := i.provider.Tickers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch tickers: %w", err)
+	}
+	if len(fetched) == 0 {
+		// An empty listing is a provider fault; it must not deactivate everything
+		return 0, fmt.Errorf("provider listed no active tickers")
+	}
+
+	stored, err := i.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stored tickers: %w", err)
+	}
+	existing := make(map[string]models.Ticker, len(stored))
+	for _, t := range stored {
+		existing[t.Ticker] = t
+	}
+
+	tickers := make([]models.Ticker, 0, len(fetched))
+	for _, t := range fetched {
+		if prev, ok := existing[t.Ticker]; ok {
+			t.Sector, t.Industry = prev.Sector, prev.Industry
+			delete(existing, t.Ticker)
+			if t == prev {
+				continue
+			}
+		}
+		if err := t.Validate(); err != nil {
+			i.log.Warnw("skipping invalid ticker", "symbol", t.Ticker, "error", err)
+			continue
+		}
+		tickers = append(tickers, t)
+	}
+
+	now := time.Now().Unix()
+	for _, t := range existing {
+		t.Active = 0
+		t.DelistedUTC = now
+		tickers = append(tickers, t)
+	}
+
+	if err := i.tickers.PutTickers(ctx, tickers); err != nil {
+		return 0, fmt.Errorf("failed to store tickers: %w", err)
+	}
+
+	i.log.Infow("tickers refreshed", "changed", len(tickers)-len(existing), "deactivated", len(existing))
+	return len(tickers), nil
+}
+
+// IngestDay stores the daily summaries of the active tickers for date and
+// returns how many were stored. Tickers that are not active are skipped.
+func (i *Ingester) IngestDay(ctx context.Context, date time.Time) (int, error) {
+	fetched, err := i.provider.GroupedDaily(ctx, date)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch daily summaries for %s: %w", date.Format(models.DateLayout), err)
+	}
+
+	active, err := i.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+	tracked := make(map[string]bool, len(active))
+	for _, t := range active {
+		tracked[t.Ticker] = true
+	}
+
+	summaries := make([]models.DailySummary, 0, len(active))
+	invalid := 0
+	for _, summary := range fetched {
+		if !tracked[summary.Ticker] {
+			continue
+		}
+		if err := summary.Validate(); err != nil {
+			invalid++
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	valid := len(summaries)
+	if i.anomalies != nil {
+		if summaries, err = i.anomalies.Screen(ctx, RevisionSourceDaily, summaries); err != nil {
+			return 0, fmt.Errorf("failed to screen daily summaries: %w", err)
+		}
+	}
+
+	if err := i.summaries.PutSummaries(repository.WithRevisionSource(ctx, RevisionSourceDaily), summaries); err != nil {
+		return 0, fmt.Errorf("failed to store daily summaries: %w", err)
+	}
+
+	day := date.Format(models.DateLayout)
+	i.log.Infow("daily summaries ingested",
+		"date", day,
+		"stored", len(summaries),
+		"invalid", invalid,
+		"held", valid-len(summaries),
+		"untracked", len(fetched)-valid-invalid,
+	)
+	if len(summaries) > 0 {
+		service.PublishEvent(ctx, i.events, i.log, models.EventDailySummaryIngested, models.EventDailySummaryIngestedVersion, models.DailySummaryIngestedEvent{
+			Scope:  models.IngestScopeMarket,
+			From:   day,
+			To:     day,
+			Stored: len(summaries),
+		})
+	}
+	return len(summaries), nil
+}
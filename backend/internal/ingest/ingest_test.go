@@ -0,0 +1,141 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeProvider struct {
+	daily   []models.DailySummary
+	tickers []models.Ticker
+}
+
+func (f *fakeProvider) GroupedDaily(ctx context.Context, date time.Time) ([]models.DailySummary, error) {
+	return f.daily, nil
+}
+
+func (f *fakeProvider) FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error) {
+	return nil, nil
+}
+
+func (f *fakeProvider) Tickers(ctx context.Context) ([]models.Ticker, error) {
+	return f.tickers, nil
+}
+
+// fakeSummaries records stored summaries; other methods are not used
+type fakeSummaries struct {
+	repository.DailySummaryRepository
+	stored []models.DailySummary
+}
+
+func (f *fakeSummaries) PutSummaries(ctx context.Context, summaries []models.DailySummary) error {
+	f.stored = append(f.stored, summaries...)
+	return nil
+}
+
+func ticker(symbol string, active int32) models.Ticker {
+	return models.Ticker{Ticker: symbol, Name: symbol, Market: "stocks", Locale: "us", Active: active}
+}
+
+func TestIngester_RefreshTickers(t *testing.T) {
+	tickers := repository.NewMockTickerRepository()
+	aapl := ticker("AAPL", 1)
+	aapl.Sector = "Technology"
+	tickers.SetTickers([]models.Ticker{aapl, ticker("GONE", 1)})
+
+	provider := &fakeProvider{tickers: []models.Ticker{ticker("AAPL", 1), ticker("MSFT", 1), {Ticker: "BAD"}}}
+	ingester := New(provider, tickers, &fakeSummaries{}, nil, nil, zap.NewNop().Sugar())
+
+	stored, err := ingester.RefreshTickers(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored, "MSFT is added and GONE deactivated")
+	assert.ElementsMatch(t, []string{"MSFT", "GONE"}, []string{tickers.Calls.PutTickers[0][0].Ticker, tickers.Calls.PutTickers[0][1].Ticker},
+		"the unchanged AAPL is not rewritten")
+
+	got, err := tickers.GetTicker(context.Background(), "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "Technology", got.Sector, "sector is kept")
+
+	gone, err := tickers.GetTicker(context.Background(), "GONE")
+	require.NoError(t, err)
+	assert.Zero(t, gone.Active)
+	assert.NotZero(t, gone.DelistedUTC)
+
+	_, err = tickers.GetTicker(context.Background(), "BAD")
+	assert.Error(t, err, "invalid tickers are skipped")
+
+	t.Run("refuses an empty listing", func(t *testing.T) {
+		_, err := New(&fakeProvider{}, tickers, &fakeSummaries{}, nil, nil, zap.NewNop().Sugar()).RefreshTickers(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestIngester_IngestDay(t *testing.T) {
+	tickers := repository.NewMockTickerRepository()
+	tickers.SetTickers([]models.Ticker{ticker("AAPL", 1), ticker("MSFT", 1)})
+
+	valid := models.DailySummary{Ticker: "AAPL", Open: 1, High: 2, Low: 1, Close: 2, Volume: 10, Timestamp: 1741323600}
+	provider := &fakeProvider{daily: []models.DailySummary{
+		valid,
+		{Ticker: "MSFT", Timestamp: 1741323600},
+		{Ticker: "WARRANT.WS", Open: 1, High: 1, Low: 1, Close: 1, Timestamp: 1741323600},
+	}}
+	summaries := &fakeSummaries{}
+
+	publisher := &eventstest.RecordingPublisher{}
+	date := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	stored, err := New(provider, tickers, summaries, nil, publisher, zap.NewNop().Sugar()).IngestDay(context.Background(), date)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	assert.Equal(t, []models.DailySummary{valid}, summaries.stored)
+	require.Len(t, publisher.Events(), 1)
+	assert.Equal(t, models.DailySummaryIngestedEvent{
+		Scope: models.IngestScopeMarket, From: "2025-03-07", To: "2025-03-07", Stored: 1,
+	}, publisher.Events()[0].Data)
+}
+
+// holdingScreen holds back the summaries of held tickers, as an
+// AnomalyService holding anomalies would
+type holdingScreen struct {
+	service.AnomalyService
+	held    map[string]bool
+	sources []string
+}
+
+func (s *holdingScreen) Screen(ctx context.Context, source string, summaries []models.DailySummary) ([]models.DailySummary, error) {
+	s.sources = append(s.sources, source)
+	var screened []models.DailySummary
+	for _, summary := range summaries {
+		if !s.held[summary.Ticker] {
+			screened = append(screened, summary)
+		}
+	}
+	return screened, nil
+}
+
+func TestIngester_IngestDayScreens(t *testing.T) {
+	tickers := repository.NewMockTickerRepository()
+	tickers.SetTickers([]models.Ticker{ticker("AAPL", 1), ticker("MSFT", 1)})
+	aapl := models.DailySummary{Ticker: "AAPL", Open: 1, High: 2, Low: 1, Close: 2, Volume: 10, Timestamp: 1741323600}
+	msft := models.DailySummary{Ticker: "MSFT", Open: 1, High: 9, Low: 1, Close: 9, Volume: 10, Timestamp: 1741323600}
+	provider := &fakeProvider{daily: []models.DailySummary{aapl, msft}}
+	summaries := &fakeSummaries{}
+	screen := &holdingScreen{held: map[string]bool{"MSFT": true}}
+
+	stored, err := New(provider, tickers, summaries, screen, nil, zap.NewNop().Sugar()).IngestDay(context.Background(), time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	assert.Equal(t, []models.DailySummary{aapl}, summaries.stored)
+	assert.Equal(t, []string{RevisionSourceDaily}, screen.sources)
+}
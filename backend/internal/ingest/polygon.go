@@ -0,0 +1,224 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/models"
+)
+
+const (
+	// DefaultPolygonBaseURL is the Polygon.io REST API
+	DefaultPolygonBaseURL = "https://api.polygon.io"
+	// polygonMaxAttempts bounds how often a rate limited request is tried
+	polygonMaxAttempts = 4
+	// polygonTickersPageSize is the largest page the reference API serves
+	polygonTickersPageSize = 1000
+)
+
+// Polygon fetches market data from the Polygon.io REST API. Prices are split
+// adjusted. Rate limited requests are retried with exponential backoff.
+type Polygon struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+	// backoff is the wait before the first retry of a rate limited request
+	backoff time.Duration
+}
+
+// NewPolygon creates a Polygon client. An empty baseURL uses DefaultPolygonBaseURL.
+func NewPolygon(baseURL, apiKey string, timeout time.Duration) *Polygon {
+	if baseURL == "" {
+		baseURL = DefaultPolygonBaseURL
+	}
+	return &Polygon{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+		backoff: time.Second,
+	}
+}
+
+// polygonAggregate is a bar of the aggregates APIs
+type polygonAggregate struct {
+	Ticker       string  `json:"T"`
+	Open         float32 `json:"o"`
+	High         float32 `json:"h"`
+	Low          float32 `json:"l"`
+	Close        float32 `json:"c"`
+	Volume       float32 `json:"v"`
+	VWAP         float32 `json:"vw"`
+	Timestamp    int64   `json:"t"`
+	Transactions int32   `json:"n"`
+	OTC          bool    `json:"otc"`
+}
+
+type polygonAggregatesResponse struct {
+	Status  string             `json:"status"`
+	Results []polygonAggregate `json:"results"`
+}
+
+type polygonTicker struct {
+	Ticker          string `json:"ticker"`
+	Name            string `json:"name"`
+	Market          string `json:"market"`
+	Locale          string `json:"locale"`
+	PrimaryExchange string `json:"primary_exchange"`
+	Type            string `json:"type"`
+	Active          bool   `json:"active"`
+	CurrencyName    string `json:"currency_name"`
+	Cik             string `json:"cik"`
+	CompositeFigi   string `json:"composite_figi"`
+	ShareClassFigi  string `json:"share_class_figi"`
+	LastUpdatedUTC  string `json:"last_updated_utc"`
+}
+
+type polygonTickersResponse struct {
+	Status  string          `json:"status"`
+	Results []polygonTicker `json:"results"`
+	NextURL string          `json:"next_url"`
+}
+
+// GroupedDaily returns the daily summary of every US stock traded on date
+func (p *Polygon) GroupedDaily(ctx context.Context, date time.Time) ([]models.DailySummary, error) {
+	path := "/v2/aggs/grouped/locale/us/market/stocks/" + date.Format(models.DateLayout)
+
+	var resp polygonAggregatesResponse
+	if err := p.get(ctx, p.baseURL+path+"?adjusted=true", &resp); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.DailySummary, 0, len(resp.Results))
+	for _, bar := range resp.Results {
+		summaries = append(summaries, bar.summary(bar.Ticker))
+	}
+	return summaries, nil
+}
+
+// FetchDailySummaries returns the daily summaries of one ticker over [from, to], oldest first
+func (p *Polygon) FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error) {
+	path := fmt.Sprintf("/v2/aggs/ticker/%s/range/1/day/%s/%s", url.PathEscape(symbol),
+		from.Format(models.DateLayout), to.Format(models.DateLayout))
+
+	var resp polygonAggregatesResponse
+	if err := p.get(ctx, p.baseURL+path+"?adjusted=true&sort=asc&limit=50000", &resp); err != nil {
+		return nil, err
+	}
+
+	summaries := make([]models.DailySummary, 0, len(resp.Results))
+	for _, bar := range resp.Results {
+		summaries = append(summaries, bar.summary(symbol))
+	}
+	return summaries, nil
+}
+
+// Tickers returns the reference data of every active US stock ticker,
+// following the API's pagination
+func (p *Polygon) Tickers(ctx context.Context) ([]models.Ticker, error) {
+	next := fmt.Sprintf("%s/v3/reference/tickers?market=stocks&active=true&limit=%d", p.baseURL, polygonTickersPageSize)
+
+	var tickers []models.Ticker
+	for next != "" {
+		var resp polygonTickersResponse
+		if err := p.get(ctx, next, &resp); err != nil {
+			return nil, err
+		}
+
+		for _, t := range resp.Results {
+			tickers = append(tickers, t.ticker())
+		}
+		next = resp.NextURL
+	}
+	return tickers, nil
+}
+
+func (bar polygonAggregate) summary(symbol string) models.DailySummary {
+	return models.DailySummary{
+		Ticker:           symbol,
+		Open:             bar.Open,
+		High:             bar.High,
+		Low:              bar.Low,
+		Close:            bar.Close,
+		Volume:           bar.Volume,
+		VWAP:             bar.VWAP,
+		Timestamp:        bar.Timestamp / 1000,
+		TransactionCount: bar.Transactions,
+		OTC:              bar.OTC,
+	}
+}
+
+func (t polygonTicker) ticker() models.Ticker {
+	ticker := models.Ticker{
+		Ticker:          t.Ticker,
+		Name:            t.Name,
+		Market:          t.Market,
+		Locale:          t.Locale,
+		PrimaryExchange: t.PrimaryExchange,
+		Type:            t.Type,
+		Cik:             t.Cik,
+		CompositeFigi:   t.CompositeFigi,
+		ShareClassFigi:  t.ShareClassFigi,
+		Currency:        strings.ToUpper(t.CurrencyName),
+	}
+	if t.Active {
+		ticker.Active = 1
+	}
+	if updated, err := time.Parse(time.RFC3339, t.LastUpdatedUTC); err == nil {
+		ticker.LastUpdatedUTC = updated.Unix()
+	}
+	return ticker
+}
+
+// get requests rawURL and decodes the JSON response into out, retrying rate
+// limited requests
+func (p *Polygon) get(ctx context.Context, rawURL string, out any) error {
+	backoff := p.backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := p.try(ctx, rawURL, out)
+		if !retry || attempt == polygonMaxAttempts {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// try performs one request, reporting whether it was rate limited
+func (p *Polygon) try(ctx context.Context, rawURL string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to build polygon request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to call polygon: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return true, fmt.Errorf("polygon rate limit exceeded")
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return false, fmt.Errorf("polygon responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("failed to decode polygon response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,96 @@
+package ingest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPolygon(t *testing.T, handler http.HandlerFunc) *Polygon {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	p := NewPolygon(srv.URL, "secret", time.Second)
+	p.backoff = time.Millisecond
+	return p
+}
+
+func TestPolygon_GroupedDaily(t *testing.T) {
+	calls := 0
+	p := newTestPolygon(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.Equal(t, "/v2/aggs/grouped/locale/us/market/stocks/2025-03-07", r.URL.Path)
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"OK","results":[
+			{"T":"AAPL","o":1,"h":3,"l":0.5,"c":2,"v":1000,"vw":1.8,"t":1741323600000,"n":42}
+		]}`))
+	})
+
+	summaries, err := p.GroupedDaily(context.Background(), time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "rate limited requests are retried")
+	assert.Equal(t, []models.DailySummary{{
+		Ticker: "AAPL", Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 1000, VWAP: 1.8,
+		Timestamp: 1741323600, TransactionCount: 42,
+	}}, summaries)
+	assert.Equal(t, "2025-03-07", summaries[0].Date())
+}
+
+func TestPolygon_FetchDailySummaries(t *testing.T) {
+	p := newTestPolygon(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/aggs/ticker/AAPL/range/1/day/2025-03-03/2025-03-07", r.URL.Path)
+		_, _ = w.Write([]byte(`{"status":"OK","results":[{"o":1,"h":1,"l":1,"c":1,"v":1,"t":1741323600000}]}`))
+	})
+
+	summaries, err := p.FetchDailySummaries(context.Background(), "AAPL",
+		time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "AAPL", summaries[0].Ticker)
+
+	t.Run("reports errors", func(t *testing.T) {
+		p := newTestPolygon(t, func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"status":"ERROR","error":"Unknown API Key"}`, http.StatusUnauthorized)
+		})
+		_, err := p.FetchDailySummaries(context.Background(), "AAPL", time.Now(), time.Now())
+		assert.ErrorContains(t, err, "status 401")
+	})
+}
+
+func TestPolygon_Tickers(t *testing.T) {
+	var srvURL string
+	p := newTestPolygon(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cursor") == "" {
+			assert.Equal(t, "true", r.URL.Query().Get("active"))
+			_, _ = w.Write([]byte(`{"status":"OK","next_url":"` + srvURL + `/v3/reference/tickers?cursor=abc","results":[
+				{"ticker":"AAPL","name":"Apple Inc.","market":"stocks","locale":"us","active":true,"currency_name":"usd","last_updated_utc":"2025-03-07T00:00:00Z"}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"OK","results":[
+			{"ticker":"MSFT","name":"Microsoft Corp","market":"stocks","locale":"us","active":true}
+		]}`))
+	})
+	srvURL = p.baseURL
+
+	tickers, err := p.Tickers(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tickers, 2)
+	assert.Equal(t, models.Ticker{
+		Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", Active: 1,
+		Currency: "USD", LastUpdatedUTC: 1741305600,
+	}, tickers[0])
+	assert.Equal(t, "MSFT", tickers[1].Ticker)
+}
@@ -0,0 +1,27 @@
+package ingest
+
+import (
+	"context"
+	"time"
+
+	"profitify-backend/internal/models"
+)
+
+// MarketDataProvider fetches end-of-day market data from an upstream vendor.
+// Summaries are returned with timestamps in unix seconds, like stored ones.
+type MarketDataProvider interface {
+	// GroupedDaily returns the daily summary of every ticker traded on date
+	GroupedDaily(ctx context.Context, date time.Time) ([]models.DailySummary, error)
+	// FetchDailySummaries returns the daily summaries of one ticker over the
+	// inclusive date range [from, to]
+	FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error)
+	// Tickers returns the reference data of every active stock ticker
+	Tickers(ctx context.Context) ([]models.Ticker, error)
+}
+
+// SymbolSearcher is implemented by providers that look up tickers by symbol
+// or company name
+type SymbolSearcher interface {
+	// SearchSymbols returns the tickers best matching keywords, best first
+	SearchSymbols(ctx context.Context, keywords string) ([]models.Ticker, error)
+}
@@ -0,0 +1,293 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/sqs"
+
+	"go.uber.org/zap"
+)
+
+// Types of the market data an ingestion queue message carries
+const (
+	MessageTickers        = "tickers"
+	MessageDailySummaries = "daily_summaries"
+	MessageIntradayBars   = "intraday_bars"
+)
+
+const (
+	// queueRetryDelay is how long the worker waits after failing to receive
+	queueRetryDelay = 10 * time.Second
+	// Messages that failed to be stored are retried after queueBackoff,
+	// doubling with every receive up to maxQueueBackoff
+	queueBackoff    = 10 * time.Second
+	maxQueueBackoff = 15 * time.Minute
+)
+
+// ErrInvalidMessage rejects a queued message that no retry could store
+var ErrInvalidMessage = errors.New("invalid ingestion message")
+
+// QueueMessage is the body of an ingestion queue message: tickers, daily
+// summaries or intraday bars, as Type says. Every item must be valid, or none
+// is stored.
+type QueueMessage struct {
+	Type      string                `json:"type"`
+	Tickers   []models.Ticker       `json:"tickers,omitempty"`
+	Summaries []models.DailySummary `json:"summaries,omitempty"`
+	Bars      []models.IntradayBar  `json:"bars,omitempty"`
+}
+
+// Queue is the queue market data is ingested from, as an sqs.Queue
+type Queue interface {
+	Receive(ctx context.Context, max int, wait time.Duration) ([]sqs.Message, error)
+	ChangeVisibility(ctx context.Context, receiptHandle string, timeout time.Duration) error
+	Delete(ctx context.Context, receiptHandle string) error
+}
+
+// DeadLetters takes the bodies of the messages the worker gives up on, as an
+// sqs.Queue
+type DeadLetters interface {
+	Send(ctx context.Context, body string) error
+}
+
+// QueueConfig tells the worker how the queue treats received messages
+type QueueConfig struct {
+	// Visibility is the queue's visibility timeout: how long a received
+	// message is hidden from other consumers
+	Visibility time.Duration
+	// MaxReceives is how many times a message that fails to be stored is
+	// received before it is dead lettered
+	MaxReceives int
+}
+
+// QueueWorker stores the market data messages of a queue through the
+// repositories
+type QueueWorker struct {
+	queue       Queue
+	deadLetters DeadLetters
+	tickers     repository.TickerRepository
+	summaries   repository.DailySummaryRepository
+	bars        repository.IntradayBarRepository
+	anomalies   service.AnomalyService
+	events      events.Publisher
+	cfg         QueueConfig
+	clock       clock.Clock
+	log         *zap.SugaredLogger
+}
+
+// NewQueueWorker returns a worker consuming queue. Messages it gives up on are
+// sent to deadLetters; with none, invalid messages are dropped and failing
+// ones retried until the queue's own redrive policy moves them. Daily
+// summaries are screened through anomalies first, unless it is nil.
+func NewQueueWorker(queue Queue, deadLetters DeadLetters, tickers repository.TickerRepository, summaries repository.DailySummaryRepository, bars repository.IntradayBarRepository, anomalies service.AnomalyService, publisher events.Publisher, cfg QueueConfig, c clock.Clock, log *zap.SugaredLogger) *QueueWorker {
+	return &QueueWorker{
+		queue:       queue,
+		deadLetters: deadLetters,
+		tickers:     tickers,
+		summaries:   summaries,
+		bars:        bars,
+		anomalies:   anomalies,
+		events:      publisher,
+		cfg:         cfg,
+		clock:       c,
+		log:         log,
+	}
+}
+
+// WireQueue builds a worker storing the messages of queue through the
+// module's repositories; deadLetters may be nil
+func WireQueue(deps app.Deps, queue Queue, deadLetters DeadLetters) *QueueWorker {
+	return NewQueueWorker(queue, deadLetters,
+		deps.TickerRepository(), deps.DailySummaryRepository(), deps.IntradayBarRepository(), deps.ScreenedIngestion(), deps.Events,
+		QueueConfig{Visibility: deps.Config.IngestQueueVisibility, MaxReceives: deps.Config.IngestQueueMaxReceives},
+		deps.Clock, deps.Log)
+}
+
+// Run stores the queue's messages until ctx is cancelled. A batch received
+// before then is drained: the message being stored is finished, and the rest
+// are made visible again for another consumer.
+func (w *QueueWorker) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		messages, err := w.queue.Receive(ctx, sqs.MaxMessages, sqs.MaxWait)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			w.log.Warnw("failed to receive ingestion messages", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-w.clock.After(queueRetryDelay):
+			}
+			continue
+		}
+
+		received := w.clock.Now()
+		work := context.WithoutCancel(ctx)
+		for n, message := range messages {
+			if ctx.Err() != nil {
+				w.release(work, messages[n:])
+				break
+			}
+			w.process(work, message, received)
+		}
+	}
+	return ctx.Err()
+}
+
+// process stores one message and deletes it, dead letters it, or leaves it to
+// be retried. Storing must finish while the message is still hidden, so it
+// is given until a fifth of the visibility timeout remains.
+func (w *QueueWorker) process(ctx context.Context, message sqs.Message, received time.Time) {
+	log := w.log.With("message", message.ID, "receives", message.ReceiveCount())
+	remaining := received.Add(w.cfg.Visibility - w.cfg.Visibility/5).Sub(w.clock.Now())
+	if remaining <= 0 {
+		w.release(ctx, []sqs.Message{message})
+		return
+	}
+
+	handleCtx, cancel := context.WithTimeout(logger.NewContext(ctx, log), remaining)
+	stored, err := w.Handle(handleCtx, []byte(message.Body))
+	cancel()
+
+	switch {
+	case err == nil:
+		if err := w.queue.Delete(ctx, message.ReceiptHandle); err != nil {
+			log.Warnw("failed to delete ingestion message", "error", err)
+		}
+		log.Debugw("ingestion message stored", "stored", stored)
+	case errors.Is(err, ErrInvalidMessage):
+		w.deadLetter(ctx, log, message, err)
+	case w.deadLetters != nil && message.ReceiveCount() >= w.cfg.MaxReceives:
+		w.deadLetter(ctx, log, message, err)
+	default:
+		backoff := min(queueBackoff<<(message.ReceiveCount()-1), maxQueueBackoff)
+		log.Warnw("failed to store ingestion message, retrying", "error", err, "retryIn", backoff)
+		if err := w.queue.ChangeVisibility(ctx, message.ReceiptHandle, backoff); err != nil {
+			log.Warnw("failed to delay ingestion message", "error", err)
+		}
+	}
+}
+
+// deadLetter moves a message to the dead letters, or drops it without any.
+// A message the dead letters do not take is left to be received again.
+func (w *QueueWorker) deadLetter(ctx context.Context, log *zap.SugaredLogger, message sqs.Message, cause error) {
+	if w.deadLetters == nil {
+		log.Errorw("dropping ingestion message", "error", cause, "body", message.Body)
+	} else {
+		if err := w.deadLetters.Send(ctx, message.Body); err != nil {
+			log.Errorw("failed to dead letter ingestion message", "error", err, "cause", cause)
+			return
+		}
+		log.Errorw("dead lettered ingestion message", "error", cause)
+	}
+	if err := w.queue.Delete(ctx, message.ReceiptHandle); err != nil {
+		log.Warnw("failed to delete ingestion message", "error", err)
+	}
+}
+
+// release makes messages visible again at once, so another consumer need not
+// wait out their visibility timeout
+func (w *QueueWorker) release(ctx context.Context, messages []sqs.Message) {
+	for _, message := range messages {
+		if err := w.queue.ChangeVisibility(ctx, message.ReceiptHandle, 0); err != nil {
+			w.log.Warnw("failed to release ingestion message", "message", message.ID, "error", err)
+		}
+	}
+}
+
+// Handle stores the market data of one QueueMessage body and returns how many
+// items were stored. Malformed and invalid messages fail with ErrInvalidMessage.
+func (w *QueueWorker) Handle(ctx context.Context, body []byte) (int, error) {
+	var message QueueMessage
+	if err := json.Unmarshal(body, &message); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidMessage, err)
+	}
+
+	switch message.Type {
+	case MessageTickers:
+		if err := validateAll(message.Tickers); err != nil {
+			return 0, err
+		}
+		if err := w.tickers.PutTickers(ctx, message.Tickers); err != nil {
+			return 0, fmt.Errorf("failed to store tickers: %w", err)
+		}
+		return len(message.Tickers), nil
+
+	case MessageDailySummaries:
+		if err := validateAll(message.Summaries); err != nil {
+			return 0, err
+		}
+		summaries := message.Summaries
+		if w.anomalies != nil {
+			var err error
+			if summaries, err = w.anomalies.Screen(ctx, RevisionSourceQueue, summaries); err != nil {
+				return 0, fmt.Errorf("failed to screen daily summaries: %w", err)
+			}
+			// A message whose summaries are all held stores nothing
+			if len(summaries) == 0 {
+				return 0, nil
+			}
+		}
+		if err := w.summaries.PutSummaries(repository.WithRevisionSource(ctx, RevisionSourceQueue), summaries); err != nil {
+			return 0, fmt.Errorf("failed to store daily summaries: %w", err)
+		}
+		service.PublishEvent(ctx, w.events, logger.FromContext(ctx, w.log), models.EventDailySummaryIngested, models.EventDailySummaryIngestedVersion, ingestedEvent(summaries))
+		return len(summaries), nil
+
+	case MessageIntradayBars:
+		if err := validateAll(message.Bars); err != nil {
+			return 0, err
+		}
+		if err := w.bars.PutBars(ctx, message.Bars); err != nil {
+			return 0, fmt.Errorf("failed to store intraday bars: %w", err)
+		}
+		return len(message.Bars), nil
+	}
+	return 0, fmt.Errorf("%w: unknown type %q", ErrInvalidMessage, message.Type)
+}
+
+// validateAll checks that a message carries items and that every one is valid
+func validateAll[T any, P interface {
+	*T
+	Validate() error
+}](items []T) error {
+	if len(items) == 0 {
+		return fmt.Errorf("%w: no items", ErrInvalidMessage)
+	}
+	for i := range items {
+		if err := P(&items[i]).Validate(); err != nil {
+			return fmt.Errorf("%w: item %d: %v", ErrInvalidMessage, i, err)
+		}
+	}
+	return nil
+}
+
+// ingestedEvent describes stored summaries: of one ticker, or of the market
+func ingestedEvent(summaries []models.DailySummary) models.DailySummaryIngestedEvent {
+	event := models.DailySummaryIngestedEvent{
+		Scope:  models.IngestScopeTicker,
+		Symbol: summaries[0].Ticker,
+		Stored: len(summaries),
+	}
+	first, last := summaries[0].Timestamp, summaries[0].Timestamp
+	for _, s := range summaries {
+		if s.Ticker != event.Symbol {
+			event.Scope, event.Symbol = models.IngestScopeMarket, ""
+		}
+		first, last = min(first, s.Timestamp), max(last, s.Timestamp)
+	}
+	event.From = time.Unix(first, 0).UTC().Format(models.DateLayout)
+	event.To = time.Unix(last, 0).UTC().Format(models.DateLayout)
+	return event
+}
@@ -0,0 +1,217 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/events/eventstest"
+	"profitify-backend/pkg/sqs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeQueue hands out its batches, then cancels the worker
+type fakeQueue struct {
+	batches    [][]sqs.Message
+	cancel     context.CancelFunc
+	deleted    []string
+	visibility map[string]time.Duration
+	sent       []string
+}
+
+func newFakeQueue(cancel context.CancelFunc, batches ...[]sqs.Message) *fakeQueue {
+	return &fakeQueue{batches: batches, cancel: cancel, visibility: map[string]time.Duration{}}
+}
+
+func (q *fakeQueue) Receive(ctx context.Context, max int, wait time.Duration) ([]sqs.Message, error) {
+	if len(q.batches) == 0 {
+		q.cancel()
+		return nil, ctx.Err()
+	}
+	batch := q.batches[0]
+	q.batches = q.batches[1:]
+	return batch, nil
+}
+
+func (q *fakeQueue) ChangeVisibility(ctx context.Context, receiptHandle string, timeout time.Duration) error {
+	q.visibility[receiptHandle] = timeout
+	return nil
+}
+
+func (q *fakeQueue) Delete(ctx context.Context, receiptHandle string) error {
+	q.deleted = append(q.deleted, receiptHandle)
+	return nil
+}
+
+func (q *fakeQueue) Send(ctx context.Context, body string) error {
+	q.sent = append(q.sent, body)
+	return nil
+}
+
+// fakeBars stores bars through put; other methods are not used
+type fakeBars struct {
+	repository.IntradayBarRepository
+	put func(bars []models.IntradayBar) error
+}
+
+func (f *fakeBars) PutBars(ctx context.Context, bars []models.IntradayBar) error {
+	return f.put(bars)
+}
+
+func message(id string, receives int, body string) sqs.Message {
+	return sqs.Message{
+		ID:            id,
+		ReceiptHandle: "rh-" + id,
+		Body:          body,
+		Attributes:    map[string]string{"ApproximateReceiveCount": fmt.Sprint(receives)},
+	}
+}
+
+const (
+	summariesBody = `{"type":"daily_summaries","summaries":[{"ticker":"AAPL","timestamp":1741323600,"open":1,"high":2,"low":1,"close":2,"volume":10}]}`
+	barsBody      = `{"type":"intraday_bars","bars":[{"ticker":"AAPL","timestamp":1741357800,"open":1,"high":2,"low":1,"close":2,"volume":10}]}`
+	tickersBody   = `{"type":"tickers","tickers":[{"ticker":"AAPL","name":"Apple","market":"stocks","locale":"us","active":1}]}`
+)
+
+var testQueueConfig = QueueConfig{Visibility: 30 * time.Second, MaxReceives: 5}
+
+func TestQueueWorker_Handle(t *testing.T) {
+	tickers := repository.NewMockTickerRepository()
+	worker := NewQueueWorker(nil, nil, tickers, &fakeSummaries{}, &fakeBars{}, nil, nil, testQueueConfig, clock.System, zap.NewNop().Sugar())
+
+	stored, err := worker.Handle(context.Background(), []byte(tickersBody))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stored)
+	got, err := tickers.GetTicker(context.Background(), "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "Apple", got.Name)
+
+	for name, body := range map[string]string{
+		"malformed":    `{"type":`,
+		"unknown type": `{"type":"quotes"}`,
+		"no items":     `{"type":"tickers","tickers":[]}`,
+		"invalid item": `{"type":"intraday_bars","bars":[{"ticker":"AAPL","timestamp":1,"open":1,"high":1,"low":2,"close":1}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := worker.Handle(context.Background(), []byte(body))
+			assert.ErrorIs(t, err, ErrInvalidMessage)
+		})
+	}
+}
+
+func TestQueueWorker_Run(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := newFakeQueue(cancel, []sqs.Message{
+		message("stored", 1, summariesBody),
+		message("invalid", 1, `not json`),
+		message("failing", 1, barsBody),
+		message("failing-again", 3, barsBody),
+		message("exhausted", 5, barsBody),
+	})
+	summaries := &fakeSummaries{}
+	publisher := &eventstest.RecordingPublisher{}
+	bars := &fakeBars{put: func([]models.IntradayBar) error { return errors.New("throttled") }}
+
+	worker := NewQueueWorker(queue, queue, repository.NewMockTickerRepository(), summaries, bars, nil, publisher, testQueueConfig, clock.NewFake(time.Now()), zap.NewNop().Sugar())
+	assert.ErrorIs(t, worker.Run(ctx), context.Canceled)
+
+	assert.Len(t, summaries.stored, 1)
+	require.Len(t, publisher.Events(), 1)
+	assert.Equal(t, models.DailySummaryIngestedEvent{
+		Scope: models.IngestScopeTicker, Symbol: "AAPL", From: "2025-03-07", To: "2025-03-07", Stored: 1,
+	}, publisher.Events()[0].Data)
+
+	assert.Equal(t, []string{"rh-stored", "rh-invalid", "rh-exhausted"}, queue.deleted)
+	assert.Equal(t, []string{`not json`, barsBody}, queue.sent, "invalid and exhausted messages are dead lettered")
+	assert.Equal(t, map[string]time.Duration{
+		"rh-failing":       10 * time.Second,
+		"rh-failing-again": 40 * time.Second,
+	}, queue.visibility, "failing messages back off with every receive")
+}
+
+func TestQueueWorker_RunWithoutDeadLetters(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	queue := newFakeQueue(cancel, []sqs.Message{
+		message("invalid", 1, `{"type":"quotes"}`),
+		message("failing", 12, barsBody),
+	})
+	bars := &fakeBars{put: func([]models.IntradayBar) error { return errors.New("throttled") }}
+
+	worker := NewQueueWorker(queue, nil, repository.NewMockTickerRepository(), &fakeSummaries{}, bars, nil, nil, testQueueConfig, clock.NewFake(time.Now()), zap.NewNop().Sugar())
+	assert.ErrorIs(t, worker.Run(ctx), context.Canceled)
+
+	assert.Equal(t, []string{"rh-invalid"}, queue.deleted, "invalid messages are dropped")
+	assert.Equal(t, map[string]time.Duration{"rh-failing": maxQueueBackoff}, queue.visibility,
+		"failing messages are retried until the queue's redrive policy takes them")
+}
+
+func TestQueueWorker_RunDrains(t *testing.T) {
+	t.Run("on shutdown", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		queue := newFakeQueue(cancel, []sqs.Message{
+			message("in-flight", 1, tickersBody),
+			message("pending", 1, tickersBody),
+		})
+		tickers := repository.NewMockTickerRepository()
+		tickers.PutTickersFunc = func(ctx context.Context, _ []models.Ticker) error {
+			cancel()
+			return ctx.Err()
+		}
+
+		worker := NewQueueWorker(queue, queue, tickers, &fakeSummaries{}, &fakeBars{}, nil, nil, testQueueConfig, clock.NewFake(time.Now()), zap.NewNop().Sugar())
+		assert.ErrorIs(t, worker.Run(ctx), context.Canceled)
+
+		assert.Len(t, tickers.Calls.PutTickers, 1)
+		assert.Equal(t, []string{"rh-in-flight"}, queue.deleted, "the message being stored is finished")
+		assert.Equal(t, map[string]time.Duration{"rh-pending": 0}, queue.visibility, "the rest are handed back")
+	})
+
+	t.Run("once the visibility timeout runs low", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		queue := newFakeQueue(cancel, []sqs.Message{
+			message("slow", 1, barsBody),
+			message("late", 1, barsBody),
+		})
+		now := clock.NewFake(time.Now())
+		stored := 0
+		bars := &fakeBars{put: func([]models.IntradayBar) error {
+			stored++
+			now.Advance(25 * time.Second)
+			return nil
+		}}
+
+		worker := NewQueueWorker(queue, queue, repository.NewMockTickerRepository(), &fakeSummaries{}, bars, nil, nil, testQueueConfig, now, zap.NewNop().Sugar())
+		assert.ErrorIs(t, worker.Run(ctx), context.Canceled)
+
+		assert.Equal(t, 1, stored)
+		assert.Equal(t, []string{"rh-slow"}, queue.deleted)
+		assert.Equal(t, map[string]time.Duration{"rh-late": 0}, queue.visibility,
+			"a message that could not be stored before another consumer sees it is released")
+	})
+}
+
+func TestQueueWorker_HandleHeldSummaries(t *testing.T) {
+	summaries := &fakeSummaries{}
+	publisher := &eventstest.RecordingPublisher{}
+	screen := &holdingScreen{held: map[string]bool{"AAPL": true}}
+	worker := NewQueueWorker(nil, nil, repository.NewMockTickerRepository(), summaries, &fakeBars{}, screen, publisher, testQueueConfig, clock.System, zap.NewNop().Sugar())
+
+	stored, err := worker.Handle(context.Background(), []byte(summariesBody))
+	require.NoError(t, err, "a message whose summaries are all held is handled")
+	assert.Zero(t, stored)
+	assert.Empty(t, summaries.stored)
+	assert.Empty(t, publisher.Events())
+	assert.Equal(t, []string{RevisionSourceQueue}, screen.sources)
+}
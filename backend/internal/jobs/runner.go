@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/lock"
+	"profitify-backend/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+// jobLockHold is how long a completed job's lock stays held so that replicas
+// firing late do not run the same job for the same date again
+const jobLockHold = 12 * time.Hour
+
+// Locker runs a function while holding a named lock across replicas
+type Locker interface {
+	Run(ctx context.Context, name string, hold time.Duration, fn func(ctx context.Context) error) error
+}
+
+// Job is a unit of work run once per trading day after the market closes
+type Job interface {
+	Name() string
+	Run(ctx context.Context, date time.Time) error
+}
+
+type funcJob struct {
+	name string
+	fn   func(ctx context.Context, date time.Time) error
+}
+
+// NewJob adapts a function to the Job interface
+func NewJob(name string, fn func(ctx context.Context, date time.Time) error) Job {
+	return &funcJob{name: name, fn: fn}
+}
+
+func (j *funcJob) Name() string {
+	return j.name
+}
+
+func (j *funcJob) Run(ctx context.Context, date time.Time) error {
+	return j.fn(ctx, date)
+}
+
+// DailyRunner runs its jobs in order once per trading day at a fixed time of
+// day in market time, skipping weekends and market holidays
+type DailyRunner struct {
+	runAt  time.Duration
+	loc    *time.Location
+	jobs   []Job
+	locker Locker
+	clock  clock.Clock
+	log    *zap.SugaredLogger
+}
+
+// NewDailyRunner creates a runner firing runAt after midnight America/New_York
+func NewDailyRunner(runAt time.Duration, log *zap.SugaredLogger, jobs ...Job) *DailyRunner {
+	return &DailyRunner{
+		runAt: runAt,
+		loc:   marketcalendar.Location(),
+		jobs:  jobs,
+		clock: clock.System,
+		log:   log,
+	}
+}
+
+// WithLocker makes each job run on a single replica per date
+func (r *DailyRunner) WithLocker(locker Locker) *DailyRunner {
+	r.locker = locker
+	return r
+}
+
+// WithClock schedules the runs by c instead of the system clock
+func (r *DailyRunner) WithClock(c clock.Clock) *DailyRunner {
+	r.clock = c
+	return r
+}
+
+// Start blocks, running the jobs at each scheduled time until ctx is cancelled
+func (r *DailyRunner) Start(ctx context.Context) {
+	for {
+		now := r.clock.Now()
+		next := r.next(now)
+		r.log.Infow("next post-close run scheduled", "at", next, "jobs", len(r.jobs))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.clock.After(next.Sub(now)):
+			r.RunAll(ctx, next)
+		}
+	}
+}
+
+// RunAll runs every job for date, continuing past failures
+func (r *DailyRunner) RunAll(ctx context.Context, date time.Time) {
+	_ = r.runJobs(ctx, r.jobs, date)
+}
+
+// runJobs runs jobs in order for the market date of date, continuing past
+// failures, which are logged and returned joined. Jobs running or run on
+// another replica are skipped.
+func (r *DailyRunner) runJobs(ctx context.Context, jobs []Job, date time.Time) error {
+	y, m, d := date.In(r.loc).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	var errs []error
+	for _, job := range jobs {
+		if ctx.Err() != nil {
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+		start := r.clock.Now()
+		if err := r.run(ctx, job, day); err != nil {
+			if errors.Is(err, lock.ErrLocked) {
+				r.log.Infow("job skipped, running on another replica", "job", job.Name(), "date", day.Format("2006-01-02"))
+				continue
+			}
+			r.log.Errorw("job failed", "job", job.Name(), "date", day.Format("2006-01-02"), "error", err)
+			errs = append(errs, fmt.Errorf("job %s failed: %w", job.Name(), err))
+			continue
+		}
+		r.log.Infow("job completed", "job", job.Name(), "date", day.Format("2006-01-02"), "duration", r.clock.Now().Sub(start))
+	}
+	return errors.Join(errs...)
+}
+
+// run runs a job, under its per-date lock when a locker is configured. Each
+// run is traced as the root span of the calls the job makes.
+func (r *DailyRunner) run(ctx context.Context, job Job, day time.Time) (err error) {
+	ctx, span := tracing.Start(ctx, "job "+job.Name(), attribute.String("profitify.job.date", day.Format("2006-01-02")))
+	defer func() {
+		if errors.Is(err, lock.ErrLocked) {
+			span.SetAttributes(attribute.Bool("profitify.job.skipped", true))
+			tracing.End(span, nil)
+			return
+		}
+		tracing.End(span, err)
+	}()
+
+	if r.locker == nil {
+		return job.Run(ctx, day)
+	}
+
+	name := "post-close:" + job.Name() + ":" + day.Format("2006-01-02")
+	return r.locker.Run(ctx, name, jobLockHold, func(ctx context.Context) error {
+		return job.Run(ctx, day)
+	})
+}
+
+// next returns the first run time on a trading day strictly after now
+func (r *DailyRunner) next(now time.Time) time.Time {
+	y, m, d := now.In(r.loc).Date()
+	// Wall-clock arithmetic keeps the run time stable across DST changes
+	seconds := int(r.runAt / time.Second)
+	candidate := time.Date(y, m, d, 0, 0, seconds, 0, r.loc)
+
+	for !candidate.After(now) || !marketcalendar.IsTradingDay(candidate) {
+		d++
+		candidate = time.Date(y, m, d, 0, 0, seconds, 0, r.loc)
+	}
+	return candidate
+}
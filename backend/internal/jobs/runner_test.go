@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/lock"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestDailyRunner_Next(t *testing.T) {
+	r := NewDailyRunner(16*time.Hour+30*time.Minute, zap.NewNop().Sugar())
+	ny := r.loc
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{
+			name: "later the same weekday",
+			now:  time.Date(2025, 3, 5, 10, 0, 0, 0, ny),
+			want: time.Date(2025, 3, 5, 16, 30, 0, 0, ny),
+		},
+		{
+			name: "after the run time rolls to next day",
+			now:  time.Date(2025, 3, 5, 16, 30, 0, 0, ny),
+			want: time.Date(2025, 3, 6, 16, 30, 0, 0, ny),
+		},
+		{
+			name: "friday evening skips the weekend",
+			now:  time.Date(2025, 3, 7, 18, 0, 0, 0, ny),
+			want: time.Date(2025, 3, 10, 16, 30, 0, 0, ny),
+		},
+		{
+			name: "market holidays are skipped",
+			now:  time.Date(2025, 4, 17, 18, 0, 0, 0, ny),
+			want: time.Date(2025, 4, 21, 16, 30, 0, 0, ny),
+		},
+		{
+			name: "wall clock time is kept across DST change",
+			now:  time.Date(2025, 3, 8, 12, 0, 0, 0, ny),
+			want: time.Date(2025, 3, 10, 16, 30, 0, 0, ny),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.True(t, tt.want.Equal(r.next(tt.now)), "got %v, want %v", r.next(tt.now), tt.want)
+		})
+	}
+}
+
+func TestDailyRunner_RunAll(t *testing.T) {
+	var ran []string
+	var gotDate time.Time
+	r := NewDailyRunner(0, zap.NewNop().Sugar(),
+		NewJob("failing", func(ctx context.Context, date time.Time) error {
+			ran = append(ran, "failing")
+			return errors.New("boom")
+		}),
+		NewJob("next", func(ctx context.Context, date time.Time) error {
+			ran = append(ran, "next")
+			gotDate = date
+			return nil
+		}),
+	)
+
+	// 01:00 UTC on the 6th is still the 5th in New York
+	r.RunAll(context.Background(), time.Date(2025, 3, 6, 1, 0, 0, 0, time.UTC))
+
+	assert.Equal(t, []string{"failing", "next"}, ran)
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), gotDate)
+}
+
+type fakeLocker struct {
+	held  map[string]bool
+	names []string
+}
+
+func (f *fakeLocker) Run(ctx context.Context, name string, hold time.Duration, fn func(ctx context.Context) error) error {
+	f.names = append(f.names, name)
+	if f.held[name] {
+		return lock.ErrLocked
+	}
+	return fn(ctx)
+}
+
+func TestDailyRunner_RunAllWithLocker(t *testing.T) {
+	date := time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC)
+	locker := &fakeLocker{held: map[string]bool{"post-close:scanner:2025-03-07": true}}
+
+	var ran []string
+	record := func(name string) Job {
+		return NewJob(name, func(ctx context.Context, date time.Time) error {
+			ran = append(ran, name)
+			return nil
+		})
+	}
+
+	runner := NewDailyRunner(16*time.Hour, zap.NewNop().Sugar(), record("scanner"), record("heatmap")).WithLocker(locker)
+	runner.RunAll(context.Background(), date)
+
+	assert.Equal(t, []string{"heatmap"}, ran)
+	assert.Equal(t, []string{"post-close:scanner:2025-03-07", "post-close:heatmap:2025-03-07"}, locker.names)
+}
+
+func TestDailyRunner_Start(t *testing.T) {
+	ny := marketcalendar.Location()
+	fake := clock.NewFake(time.Date(2025, 3, 7, 15, 0, 0, 0, ny))
+
+	dates := make(chan time.Time, 1)
+	runner := NewDailyRunner(16*time.Hour, zap.NewNop().Sugar(),
+		NewJob("scanner", func(ctx context.Context, date time.Time) error {
+			dates <- date
+			return nil
+		})).WithClock(fake)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		runner.Start(ctx)
+		close(stopped)
+	}()
+	waiting := func() bool { return fake.Waiters() == 1 }
+
+	assert.Eventually(t, waiting, time.Second, time.Millisecond)
+	fake.Advance(59 * time.Minute)
+	assert.Empty(t, dates, "the run waits for its time")
+	fake.Advance(time.Minute)
+	assert.Equal(t, time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC), <-dates)
+
+	// Friday's run is followed by Monday's
+	assert.Eventually(t, waiting, time.Second, time.Millisecond)
+	fake.Set(time.Date(2025, 3, 9, 16, 0, 0, 0, ny))
+	assert.Empty(t, dates, "weekends are skipped")
+	fake.Set(time.Date(2025, 3, 10, 16, 0, 0, 0, ny))
+	assert.Equal(t, time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), <-dates)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("Start did not return once cancelled")
+	}
+}
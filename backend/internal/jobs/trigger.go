@@ -0,0 +1,176 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/pkg/sqs"
+)
+
+// Modes post-close jobs are scheduled in
+const (
+	// SchedulerInternal runs them on the runner's in-process timer
+	SchedulerInternal = "internal"
+	// SchedulerSQS runs them when a trigger arrives on an SQS queue
+	SchedulerSQS = "sqs"
+	// SchedulerLambda runs them when the process is invoked as a Lambda function
+	SchedulerLambda = "lambda"
+)
+
+// ErrInvalidTrigger rejects trigger payloads that cannot be run
+var ErrInvalidTrigger = errors.New("invalid trigger")
+
+// Trigger asks the runner to run jobs for a trading date. Serverless
+// deployments send them from an EventBridge schedule instead of running the
+// in-process timer, either as the detail of the scheduled event or as the
+// rule's constant input.
+type Trigger struct {
+	// Jobs names the jobs to run, which run in the runner's order; empty runs
+	// them all
+	Jobs []string `json:"jobs,omitempty"`
+	// Date is the trading date to run the jobs for, YYYY-MM-DD; it defaults
+	// to the day the event fired on in market time
+	Date string `json:"date,omitempty"`
+}
+
+// scheduledEvent is an EventBridge event, or a bare trigger when DetailType
+// is empty
+type scheduledEvent struct {
+	DetailType string          `json:"detail-type"`
+	Time       time.Time       `json:"time"`
+	Detail     json.RawMessage `json:"detail"`
+	Trigger
+}
+
+// ParseTrigger reads a trigger from an EventBridge event or a bare trigger,
+// and returns it with the time the jobs run for: its date, or else when the
+// event fired, or else now
+func ParseTrigger(payload []byte, now time.Time) (Trigger, time.Time, error) {
+	var event scheduledEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return Trigger{}, time.Time{}, fmt.Errorf("%w: %v", ErrInvalidTrigger, err)
+	}
+
+	trigger := event.Trigger
+	at := now
+	if event.DetailType != "" {
+		trigger = Trigger{}
+		if len(event.Detail) > 0 && string(event.Detail) != "null" {
+			if err := json.Unmarshal(event.Detail, &trigger); err != nil {
+				return Trigger{}, time.Time{}, fmt.Errorf("%w: detail: %v", ErrInvalidTrigger, err)
+			}
+		}
+		if !event.Time.IsZero() {
+			at = event.Time
+		}
+	}
+
+	if trigger.Date != "" {
+		date, err := time.Parse("2006-01-02", trigger.Date)
+		if err != nil {
+			return Trigger{}, time.Time{}, fmt.Errorf("%w: date %q is not YYYY-MM-DD", ErrInvalidTrigger, trigger.Date)
+		}
+		// Noon keeps the date the same in market time
+		at = date.Add(12 * time.Hour)
+	}
+	return trigger, at, nil
+}
+
+// RunTrigger runs the jobs a trigger payload names, continuing past failures,
+// which are returned joined so the sender may retry. Jobs completed for the
+// date before are skipped by their locks. Schedules fire on market holidays
+// too, so triggers without a date are ignored on days the market is closed.
+func (r *DailyRunner) RunTrigger(ctx context.Context, payload []byte) error {
+	trigger, at, err := ParseTrigger(payload, r.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	jobs := r.jobs
+	if len(trigger.Jobs) > 0 {
+		jobs = nil
+		for _, job := range r.jobs {
+			if slices.Contains(trigger.Jobs, job.Name()) {
+				jobs = append(jobs, job)
+			}
+		}
+		if len(jobs) != len(trigger.Jobs) {
+			return fmt.Errorf("%w: unknown job in %v", ErrInvalidTrigger, trigger.Jobs)
+		}
+	}
+
+	if trigger.Date == "" && !marketcalendar.IsTradingDay(at.In(r.loc)) {
+		r.log.Infow("skipping triggered jobs, the market is closed", "at", at)
+		return nil
+	}
+
+	r.log.Infow("running triggered jobs", "jobs", len(jobs), "at", at)
+	return r.runJobs(ctx, jobs, at)
+}
+
+// HandleInvocation runs the triggers of a Lambda invocation: a scheduled event
+// targeting the function, or a batch of queued ones from an SQS event source
+func (r *DailyRunner) HandleInvocation(ctx context.Context, payload []byte) error {
+	var batch struct {
+		Records []struct {
+			Body string `json:"body"`
+		} `json:"Records"`
+	}
+	if err := json.Unmarshal(payload, &batch); err != nil || len(batch.Records) == 0 {
+		return r.RunTrigger(ctx, payload)
+	}
+
+	var errs []error
+	for _, record := range batch.Records {
+		errs = append(errs, r.RunTrigger(ctx, []byte(record.Body)))
+	}
+	return errors.Join(errs...)
+}
+
+// TriggerQueue is the queue scheduled events are delivered to
+type TriggerQueue interface {
+	Receive(ctx context.Context, max int, wait time.Duration) ([]sqs.Message, error)
+	Delete(ctx context.Context, receiptHandle string) error
+}
+
+// triggerRetryDelay is how long ConsumeQueue waits after failing to receive
+const triggerRetryDelay = 10 * time.Second
+
+// ConsumeQueue runs the triggers delivered to queue until ctx is cancelled.
+// Triggers are deleted once run, or once found invalid; a trigger whose jobs
+// failed is left to be redelivered after its visibility timeout.
+func (r *DailyRunner) ConsumeQueue(ctx context.Context, queue TriggerQueue) error {
+	for ctx.Err() == nil {
+		messages, err := queue.Receive(ctx, 1, sqs.MaxWait)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			r.log.Warnw("failed to receive triggers", "error", err)
+			select {
+			case <-ctx.Done():
+			case <-r.clock.After(triggerRetryDelay):
+			}
+			continue
+		}
+
+		for _, message := range messages {
+			switch err := r.RunTrigger(ctx, []byte(message.Body)); {
+			case errors.Is(err, ErrInvalidTrigger):
+				r.log.Errorw("dropping invalid trigger", "message", message.ID, "error", err)
+			case err != nil:
+				r.log.Errorw("triggered jobs failed, leaving the trigger to be redelivered", "message", message.ID, "error", err)
+				continue
+			}
+			if err := queue.Delete(context.WithoutCancel(ctx), message.ReceiptHandle); err != nil {
+				r.log.Warnw("failed to delete trigger", "message", message.ID, "error", err)
+			}
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,167 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/pkg/sqs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseTrigger(t *testing.T) {
+	now := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		payload string
+		want    Trigger
+		wantAt  time.Time
+		wantErr bool
+	}{
+		{
+			name:    "scheduled event runs all jobs when it fired",
+			payload: `{"detail-type":"Scheduled Event","time":"2025-03-07T21:30:00Z","detail":{}}`,
+			wantAt:  time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "event detail names jobs and a date",
+			payload: `{"detail-type":"RunPostCloseJobs","time":"2025-03-07T21:30:00Z","detail":{"jobs":["scanner"],"date":"2025-03-05"}}`,
+			want:    Trigger{Jobs: []string{"scanner"}, Date: "2025-03-05"},
+			wantAt:  time.Date(2025, 3, 5, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "bare trigger runs now",
+			payload: `{"jobs":["heatmap"]}`,
+			want:    Trigger{Jobs: []string{"heatmap"}},
+			wantAt:  now,
+		},
+		{
+			name:    "malformed date",
+			payload: `{"date":"03/05/2025"}`,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			payload: `run`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trigger, at, err := ParseTrigger([]byte(tt.payload), now)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTrigger)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, trigger)
+			assert.True(t, tt.wantAt.Equal(at), "got %v, want %v", at, tt.wantAt)
+		})
+	}
+}
+
+func TestDailyRunner_RunTrigger(t *testing.T) {
+	var ran []string
+	var gotDate time.Time
+	record := func(name string, err error) Job {
+		return NewJob(name, func(ctx context.Context, date time.Time) error {
+			ran = append(ran, name)
+			gotDate = date
+			return err
+		})
+	}
+	runner := NewDailyRunner(16*time.Hour, zap.NewNop().Sugar(),
+		record("ingest", nil), record("scanner", nil), record("heatmap", errors.New("boom")))
+
+	err := runner.RunTrigger(context.Background(), []byte(`{"jobs":["scanner","ingest"],"date":"2025-03-05"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ingest", "scanner"}, ran, "jobs run in the runner's order")
+	assert.Equal(t, time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), gotDate)
+
+	ran = nil
+	err = runner.RunTrigger(context.Background(), []byte(`{"date":"2025-03-05"}`))
+	assert.ErrorContains(t, err, "heatmap")
+	assert.Equal(t, []string{"ingest", "scanner", "heatmap"}, ran)
+
+	ran = nil
+	err = runner.RunTrigger(context.Background(), []byte(`{"jobs":["scanner","rollup"]}`))
+	assert.ErrorIs(t, err, ErrInvalidTrigger)
+	assert.Empty(t, ran)
+}
+
+func TestDailyRunner_HandleInvocation(t *testing.T) {
+	var dates []time.Time
+	runner := NewDailyRunner(16*time.Hour, zap.NewNop().Sugar(),
+		NewJob("scanner", func(ctx context.Context, date time.Time) error {
+			dates = append(dates, date)
+			return nil
+		}))
+
+	batch := `{"Records":[{"body":"{\"date\":\"2025-03-05\"}"},{"body":"{\"date\":\"2025-03-06\"}"}]}`
+	require.NoError(t, runner.HandleInvocation(context.Background(), []byte(batch)))
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 3, 6, 0, 0, 0, 0, time.UTC),
+	}, dates)
+
+	dates = nil
+	require.NoError(t, runner.HandleInvocation(context.Background(), []byte(`{"detail-type":"Scheduled Event","time":"2025-03-07T21:30:00Z","detail":{}}`)))
+	assert.Equal(t, []time.Time{time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)}, dates)
+
+	dates = nil
+	require.NoError(t, runner.HandleInvocation(context.Background(), []byte(`{"detail-type":"Scheduled Event","time":"2025-04-18T21:30:00Z","detail":{}}`)))
+	assert.Empty(t, dates, "schedules firing on market holidays are skipped")
+	require.NoError(t, runner.HandleInvocation(context.Background(), []byte(`{"date":"2025-04-18"}`)))
+	assert.Equal(t, []time.Time{time.Date(2025, 4, 18, 0, 0, 0, 0, time.UTC)}, dates, "explicit dates always run")
+}
+
+type fakeQueue struct {
+	messages []sqs.Message
+	deleted  []string
+	cancel   context.CancelFunc
+}
+
+func (f *fakeQueue) Receive(ctx context.Context, max int, wait time.Duration) ([]sqs.Message, error) {
+	if len(f.messages) == 0 {
+		f.cancel()
+		return nil, ctx.Err()
+	}
+	message := f.messages[0]
+	f.messages = f.messages[1:]
+	return []sqs.Message{message}, nil
+}
+
+func (f *fakeQueue) Delete(ctx context.Context, receiptHandle string) error {
+	f.deleted = append(f.deleted, receiptHandle)
+	return nil
+}
+
+func TestDailyRunner_ConsumeQueue(t *testing.T) {
+	runner := NewDailyRunner(16*time.Hour, zap.NewNop().Sugar(),
+		NewJob("scanner", func(ctx context.Context, date time.Time) error {
+			if date.Day() == 6 {
+				return errors.New("boom")
+			}
+			return nil
+		}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue := &fakeQueue{
+		cancel: cancel,
+		messages: []sqs.Message{
+			{ID: "1", ReceiptHandle: "ran", Body: `{"date":"2025-03-05"}`},
+			{ID: "2", ReceiptHandle: "failed", Body: `{"date":"2025-03-06"}`},
+			{ID: "3", ReceiptHandle: "invalid", Body: `{"jobs":["rollup"]}`},
+		},
+	}
+
+	err := runner.ConsumeQueue(ctx, queue)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"ran", "invalid"}, queue.deleted, "failed triggers are left to be redelivered")
+}
@@ -0,0 +1,83 @@
+package market
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultCalendarPastDays and defaultCalendarAheadDays bound the calendar
+	// returned when no range is given
+	defaultCalendarPastDays  = 90
+	defaultCalendarAheadDays = 30
+)
+
+type ingestEventsRequest struct {
+	Events []models.EconomicEvent `json:"events"`
+}
+
+func (h *Handler) GetEconomicCalendar(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	today := h.clock.Now().UTC().Truncate(24 * time.Hour)
+	if from == 0 {
+		from = today.AddDate(0, 0, -defaultCalendarPastDays).Unix()
+	}
+	if to == 0 {
+		to = today.AddDate(0, 0, defaultCalendarAheadDays+1).Unix() - 1
+	}
+
+	events, err := h.economicCalendarService.GetEvents(c.Request.Context(), c.Query("country"), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to get economic calendar", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve economic calendar")
+		return
+	}
+
+	if events == nil {
+		events = []models.EconomicEvent{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
+
+func (h *Handler) IngestEconomicEvents(c *gin.Context) {
+	var req ingestEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	count, err := h.economicCalendarService.IngestEvents(c.Request.Context(), req.Events)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidEvent) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to ingest economic events", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to ingest economic events")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ingested": count,
+	})
+}
@@ -0,0 +1,177 @@
+package market
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/lock"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultBreadthDays is the breadth history returned when no from date is given
+const defaultBreadthDays = 90
+
+// PostCloseJobs returns the jobs to run once the market has closed each trading day
+func (h *Handler) PostCloseJobs() []jobs.Job {
+	return []jobs.Job{
+		jobs.NewJob("market-scanner", func(ctx context.Context, date time.Time) error {
+			_, err := h.signalService.Scan(ctx, date)
+			return err
+		}),
+		jobs.NewJob("heatmap", func(ctx context.Context, date time.Time) error {
+			return h.heatmapService.Refresh(ctx)
+		}),
+		jobs.NewJob("market-breadth", func(ctx context.Context, date time.Time) error {
+			_, err := h.breadthService.Compute(ctx, date)
+			return err
+		}),
+	}
+}
+
+func (h *Handler) GetMarketSignals(c *gin.Context) {
+	date, err := api.ParseDateQuery(c, "date")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if date.IsZero() {
+		date = h.clock.Now().UTC()
+	}
+
+	signals, err := h.signalService.GetSignals(c.Request.Context(), date)
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to get market signals", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve signals")
+		return
+	}
+
+	if signals == nil {
+		signals = []models.Signal{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"date":    date.Format(api.DateLayout),
+		"signals": signals,
+		"count":   len(signals),
+	})
+}
+
+func (h *Handler) GetMarketHeatmap(c *gin.Context) {
+	window := c.DefaultQuery("window", "1d")
+
+	heatmap, err := h.heatmapService.GetHeatmap(c.Request.Context(), window)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidWindow) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to get market heatmap", "window", window, "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve heatmap")
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
+
+func (h *Handler) GetMarketBreadth(c *gin.Context) {
+	from, err := api.ParseDateQuery(c, "from")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	to, err := api.ParseDateQuery(c, "to")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if to.IsZero() {
+		to = h.clock.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultBreadthDays)
+	}
+
+	series, err := h.breadthService.GetBreadth(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to get market breadth", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve breadth")
+		return
+	}
+
+	if series == nil {
+		series = []models.MarketBreadth{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"breadth": series,
+		"count":   len(series),
+	})
+}
+
+// BackfillMarketBreadth starts recomputing breadth over an inclusive date range
+// as a background task. Progress is checkpointed, so repeating the request for
+// the same range resumes an interrupted backfill.
+func (h *Handler) BackfillMarketBreadth(c *gin.Context) {
+	from, err := api.ParseDateQuery(c, "from")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	to, err := api.ParseDateQuery(c, "to")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if from.IsZero() || to.IsZero() {
+		problem.Respond(c, problem.ValidationFailed, "from and to are required")
+		return
+	}
+	if from.After(to) || to.Sub(from) > service.MaxBreadthBackfillDays*24*time.Hour {
+		problem.Respond(c, problem.ValidationFailed, fmt.Sprintf("from must not be after to, nor more than %d days before it", service.MaxBreadthBackfillDays))
+		return
+	}
+
+	job := service.BreadthBackfillJob(from, to)
+	started := h.backfills.Start(job, func(ctx context.Context) error {
+		return h.backfill(ctx, job, from, to)
+	})
+	if !started {
+		problem.Respond(c, problem.Conflict, "A backfill of this range is already running")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"from": from.Format(api.DateLayout),
+		"to":   to.Format(api.DateLayout),
+	})
+}
+
+// backfill runs a breadth backfill under the lock named job, so that a range
+// is backfilled by one replica at a time
+func (h *Handler) backfill(ctx context.Context, job string, from, to time.Time) error {
+	run := func(ctx context.Context) error {
+		_, err := h.breadthService.Backfill(ctx, from, to)
+		return err
+	}
+	if h.locker == nil {
+		return run(ctx)
+	}
+
+	err := h.locker.Run(ctx, job, 0, run)
+	if errors.Is(err, lock.ErrLocked) {
+		h.log.Infow("breadth backfill skipped, running on another replica", "job", job)
+		return nil
+	}
+	return err
+}
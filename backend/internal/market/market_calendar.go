@@ -0,0 +1,35 @@
+package market
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMarketStatus answers with the phase of the trading day and the next open
+// and close
+func (h *Handler) GetMarketStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, marketcalendar.StatusAt(h.clock.Now()))
+}
+
+// GetMarketCalendar answers with the holidays and early closes of ?year=,
+// the current year in market time by default
+func (h *Handler) GetMarketCalendar(c *gin.Context) {
+	year := h.clock.Now().In(marketcalendar.Location()).Year()
+	if value := c.Query("year"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < marketcalendar.MinYear || parsed > marketcalendar.MaxYear {
+			problem.Respond(c, problem.ValidationFailed,
+				fmt.Sprintf("year must be between %d and %d", marketcalendar.MinYear, marketcalendar.MaxYear))
+			return
+		}
+		year = parsed
+	}
+
+	c.JSON(http.StatusOK, marketcalendar.YearOf(year))
+}
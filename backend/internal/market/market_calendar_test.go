@@ -0,0 +1,52 @@
+package market
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_MarketCalendarClock(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ny := marketcalendar.Location()
+	now := clock.NewFake(time.Date(2025, 3, 5, 10, 0, 0, 0, ny))
+	h := &Handler{clock: now}
+
+	r := gin.New()
+	r.GET("/market/status", h.GetMarketStatus)
+	r.GET("/market/calendar", h.GetMarketCalendar)
+	get := func(path string, v any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), v))
+	}
+
+	var status marketcalendar.Status
+	get("/market/status", &status)
+	assert.Equal(t, marketcalendar.PhaseOpen, status.Phase)
+	assert.True(t, status.Time.Equal(now.Now()))
+
+	now.Set(time.Date(2025, 4, 18, 10, 0, 0, 0, ny))
+	status = marketcalendar.Status{}
+	get("/market/status", &status)
+	assert.Equal(t, marketcalendar.PhaseClosed, status.Phase)
+	assert.Equal(t, "Good Friday", status.Holiday)
+
+	// The calendar defaults to the year in market time: still 2025 in New
+	// York at 03:00 UTC on New Year's Day
+	now.Set(time.Date(2026, 1, 1, 3, 0, 0, 0, time.UTC))
+	var year marketcalendar.Year
+	get("/market/calendar", &year)
+	assert.Equal(t, 2025, year.Year)
+}
@@ -0,0 +1,170 @@
+// Package market serves market-wide data: the market's status and trading
+// calendar, scanner signals, the sector heatmap, breadth and the economic
+// calendar. It also owns the post-close jobs that compute them.
+package market
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/openapi"
+	"profitify-backend/pkg/tasks"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	// backfills run as background tasks, under a lock across replicas unless
+	// locker is nil
+	backfills               *tasks.Manager
+	locker                  jobs.Locker
+	signalService           service.SignalService
+	heatmapService          service.HeatmapService
+	breadthService          service.BreadthService
+	economicCalendarService service.EconomicCalendarService
+	// clock tells the market's status and the dates defaulted to today
+	clock clock.Clock
+	log   *zap.SugaredLogger
+}
+
+// Wire builds the market module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	cfg := deps.Config
+	tickerRepo := deps.TickerRepository()
+	summaryRepo := deps.DailySummaryRepository()
+
+	h := &Handler{
+		backfills: deps.Tasks,
+		signalService: service.NewSignalService(tickerRepo, summaryRepo, deps.SignalRepository(), service.ScannerConfig{
+			GapPercent:     cfg.ScannerGapPercent,
+			VolumeMultiple: cfg.ScannerVolumeMultiple,
+			VolumeLookback: cfg.ScannerVolumeLookback,
+		}, deps.Log),
+		heatmapService: service.NewHeatmapService(tickerRepo, summaryRepo, cfg.HeatmapCacheTTL, deps.Clock, deps.Log),
+		breadthService: service.NewBreadthService(tickerRepo, summaryRepo,
+			repository.NewBreadthRepository(deps.DB, cfg.BreadthTable), deps.SettingsService(), deps.Log),
+		economicCalendarService: service.NewEconomicCalendarService(
+			repository.NewEconomicEventRepository(deps.DB, cfg.EconomicEventsTable), deps.Log),
+		clock: deps.Clock,
+		log:   deps.Log,
+	}
+	if deps.Locker != nil {
+		h.locker = deps.Locker
+	}
+	return h
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	market := api.Group("/market", middleware.RequireScope(models.ScopeReadMarket))
+	market.GET("/signals", h.GetMarketSignals)
+	market.GET("/heatmap", h.GetMarketHeatmap)
+	market.GET("/breadth", h.GetMarketBreadth)
+	market.GET("/status", h.GetMarketStatus)
+	market.GET("/calendar", h.GetMarketCalendar)
+
+	api.GET("/calendar/economic", middleware.RequireScope(models.ScopeReadMarket), h.GetEconomicCalendar)
+
+	admin.POST("/market/breadth/backfill", h.BackfillMarketBreadth)
+	admin.POST("/calendar/economic", h.IngestEconomicEvents)
+}
+
+// ResumeBackfills runs the breadth backfills left unfinished by a previous process
+func (h *Handler) ResumeBackfills(ctx context.Context) error {
+	return h.breadthService.ResumeBackfills(ctx)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	marketTags, calendarTags := []string{"Market"}, []string{"Economic calendar"}
+
+	doc.Add(http.MethodGet, "/api/v1/market/signals", &openapi.Operation{
+		Tags:    marketTags,
+		Summary: "List the gap and unusual-volume signals flagged by the post-close scanner",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("date", "Trading day, YYYY-MM-DD (defaults to today)", api.DateSchema),
+		},
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"date":    api.DateSchema,
+			"signals": {Type: "array", Items: doc.Schema(models.Signal{})},
+			"count":   {Type: "integer"},
+		}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/market/heatmap", &openapi.Operation{
+		Tags:    marketTags,
+		Summary: "Get the sector and industry performance tree",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("window", "Performance window (defaults to 1d)", &openapi.Schema{
+				Type: "string",
+				Enum: []any{"1d", "1w", "1m"},
+			}),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(models.Heatmap{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/market/breadth", &openapi.Operation{
+		Tags:        marketTags,
+		Summary:     "List daily market breadth",
+		Description: "Advancers and decliners, the share above the 50 and 200-day SMA and new 52-week highs and lows. Defaults to the last 90 days.",
+		Parameters:  api.DateRangeParams(),
+		Responses:   api.Responses(http.StatusOK, api.List(doc, "breadth", models.MarketBreadth{}), http.StatusBadRequest),
+	})
+
+	doc.Add(http.MethodGet, "/api/v1/market/status", &openapi.Operation{
+		Tags:    marketTags,
+		Summary: "Get whether the US market is open and when it next opens and closes",
+		Description: "Phases are pre_market (from 4:00), open (9:30 to 16:00, or 13:00 on early closes), after_hours " +
+			"(four hours after the close) and closed, in America/New_York. Weekends and NYSE holidays are closed all day.",
+		Responses: api.Responses(http.StatusOK, doc.Schema(marketcalendar.Status{})),
+	})
+	doc.Add(http.MethodGet, "/api/v1/market/calendar", &openapi.Operation{
+		Tags:    marketTags,
+		Summary: "List a year's US market holidays and early closes",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("year", fmt.Sprintf("Year, %d to %d (defaults to the current year)",
+				marketcalendar.MinYear, marketcalendar.MaxYear), &openapi.Schema{Type: "integer"}),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(marketcalendar.Year{}), http.StatusBadRequest),
+	})
+
+	doc.Add(http.MethodGet, "/api/v1/calendar/economic", &openapi.Operation{
+		Tags:        calendarTags,
+		Summary:     "List macro events in a date range, oldest first",
+		Description: "Defaults to 90 days back through 30 days ahead.",
+		Parameters: append(api.DateRangeParams(),
+			openapi.QueryParam("country", "ISO country code, e.g. US", nil),
+		),
+		Responses: api.Responses(http.StatusOK, api.List(doc, "events", models.EconomicEvent{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/calendar/economic", &openapi.Operation{
+		Tags:        calendarTags,
+		Summary:     "Ingest a batch of macro events",
+		Description: "Re-ingesting the same country, time and type replaces the event.",
+		RequestBody: openapi.JSONBody(doc.Inline(ingestEventsRequest{})),
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ingested": {Type: "integer"},
+		}), http.StatusBadRequest),
+	})
+
+	doc.Add(http.MethodPost, "/api/v1/admin/market/breadth/backfill", &openapi.Operation{
+		Tags:        []string{"Admin"},
+		Summary:     "Recompute market breadth over a date range in the background",
+		Description: "Progress is checkpointed, so repeating the request for the same range resumes an interrupted backfill. A range is backfilled by one replica at a time.",
+		Parameters: []openapi.Parameter{
+			{Name: "from", In: "query", Required: true, Description: "First day, YYYY-MM-DD", Schema: api.DateSchema},
+			{Name: "to", In: "query", Required: true, Description: "Last day, YYYY-MM-DD", Schema: api.DateSchema},
+		},
+		Responses: api.Responses(http.StatusAccepted, openapi.Object(map[string]*openapi.Schema{
+			"from": api.DateSchema,
+			"to":   api.DateSchema,
+		}), http.StatusBadRequest, http.StatusConflict),
+	})
+}
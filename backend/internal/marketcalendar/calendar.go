@@ -0,0 +1,352 @@
+// Package marketcalendar knows the trading days and session hours of the US
+// equity markets: NYSE holidays, early closes and the extended-hours sessions
+// around the regular one, all in America/New_York.
+package marketcalendar
+
+import (
+	"sync"
+	"time"
+	_ "time/tzdata" // sessions are defined in America/New_York
+)
+
+// The years whose holidays the rules below reproduce
+const (
+	MinYear = 2000
+	MaxYear = 2099
+)
+
+// Session times as offsets from midnight market time
+const (
+	preMarketOpen    = 4 * time.Hour
+	regularOpen      = 9*time.Hour + 30*time.Minute
+	regularClose     = 16 * time.Hour
+	earlyClose       = 13 * time.Hour
+	afterHoursLength = 4 * time.Hour
+)
+
+// Phase is the part of the trading day the market is in
+type Phase string
+
+const (
+	PhasePreMarket  Phase = "pre_market"
+	PhaseOpen       Phase = "open"
+	PhaseAfterHours Phase = "after_hours"
+	PhaseClosed     Phase = "closed"
+)
+
+// Session is a trading day's hours. After an early close the after-hours
+// session ends early as well.
+type Session struct {
+	Date            string    `json:"date"`
+	PreMarketOpen   time.Time `json:"preMarketOpen"`
+	Open            time.Time `json:"open"`
+	Close           time.Time `json:"close"`
+	AfterHoursClose time.Time `json:"afterHoursClose"`
+	EarlyClose      bool      `json:"earlyClose,omitempty"`
+}
+
+// Status is where the market stands at a moment
+type Status struct {
+	Time  time.Time `json:"time"`
+	Phase Phase     `json:"phase"`
+	// Open reports whether the regular session is open
+	Open bool `json:"open"`
+	// Holiday names the holiday the market is closed for today
+	Holiday string `json:"holiday,omitempty"`
+	// Session is today's, unless the market is closed all day
+	Session   *Session  `json:"session,omitempty"`
+	NextOpen  time.Time `json:"nextOpen"`
+	NextClose time.Time `json:"nextClose"`
+}
+
+// Day is a weekday on which the market closes all day or early
+type Day struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+	// Close is the time of an early close
+	Close *time.Time `json:"close,omitempty"`
+}
+
+// Year lists a year's holidays and early closes
+type Year struct {
+	Year        int   `json:"year"`
+	TradingDays int   `json:"tradingDays"`
+	Holidays    []Day `json:"holidays"`
+	EarlyCloses []Day `json:"earlyCloses"`
+}
+
+var location = func() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}()
+
+// Location returns America/New_York, the time zone of the sessions
+func Location() *time.Location {
+	return location
+}
+
+// unscheduledClosures are the days the exchanges closed outside their holiday
+// rules
+var unscheduledClosures = map[time.Time]string{
+	date(2001, time.September, 11): "September 11 attacks",
+	date(2001, time.September, 12): "September 11 attacks",
+	date(2001, time.September, 13): "September 11 attacks",
+	date(2001, time.September, 14): "September 11 attacks",
+	date(2004, time.June, 11):      "National Day of Mourning for Ronald Reagan",
+	date(2007, time.January, 2):    "National Day of Mourning for Gerald Ford",
+	date(2012, time.October, 29):   "Hurricane Sandy",
+	date(2012, time.October, 30):   "Hurricane Sandy",
+	date(2018, time.December, 5):   "National Day of Mourning for George H.W. Bush",
+	date(2025, time.January, 9):    "National Day of Mourning for Jimmy Carter",
+}
+
+// Holiday returns the name of the holiday the market is closed for on the
+// calendar day of t, in t's location. Weekends are not holidays.
+func Holiday(t time.Time) (string, bool) {
+	day := day(t)
+	if name, ok := unscheduledClosures[day]; ok {
+		return name, true
+	}
+	name, ok := rulesOf(day.Year()).holidays[day]
+	return name, ok
+}
+
+// EarlyClose returns the occasion the market closes early for on the calendar
+// day of t, in t's location
+func EarlyClose(t time.Time) (string, bool) {
+	day := day(t)
+	if !IsTradingDay(day) {
+		return "", false
+	}
+	name, ok := rulesOf(day.Year()).earlyCloses[day]
+	return name, ok
+}
+
+// IsTradingDay reports whether the market opens on the calendar day of t, in
+// t's location
+func IsTradingDay(t time.Time) bool {
+	day := day(t)
+	if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		return false
+	}
+	_, closed := Holiday(day)
+	return !closed
+}
+
+// NextTradingDay returns midnight, in t's location, of the first trading day
+// after the calendar day of t
+func NextTradingDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	next := time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+	for !IsTradingDay(next) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// PreviousTradingDay returns midnight, in t's location, of the last trading
+// day before the calendar day of t
+func PreviousTradingDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	previous := time.Date(y, m, d-1, 0, 0, 0, 0, t.Location())
+	for !IsTradingDay(previous) {
+		previous = previous.AddDate(0, 0, -1)
+	}
+	return previous
+}
+
+// SessionOn returns the hours of the calendar day of t, in t's location, if
+// the market opens that day
+func SessionOn(t time.Time) (Session, bool) {
+	if !IsTradingDay(t) {
+		return Session{}, false
+	}
+
+	y, m, d := t.Date()
+	at := func(offset time.Duration) time.Time {
+		// Wall-clock arithmetic keeps the hours right on DST changes
+		return time.Date(y, m, d, 0, 0, int(offset/time.Second), 0, location)
+	}
+	closeAt := regularClose
+	_, early := EarlyClose(t)
+	if early {
+		closeAt = earlyClose
+	}
+	return Session{
+		Date:            date(y, m, d).Format("2006-01-02"),
+		PreMarketOpen:   at(preMarketOpen),
+		Open:            at(regularOpen),
+		Close:           at(closeAt),
+		AfterHoursClose: at(closeAt + afterHoursLength),
+		EarlyClose:      early,
+	}, true
+}
+
+// StatusAt returns where the market stands at now
+func StatusAt(now time.Time) Status {
+	now = now.In(location)
+	status := Status{Time: now, Phase: PhaseClosed}
+	status.Holiday, _ = Holiday(now)
+
+	session, ok := SessionOn(now)
+	if ok {
+		status.Session = &session
+		switch {
+		case now.Before(session.PreMarketOpen):
+		case now.Before(session.Open):
+			status.Phase = PhasePreMarket
+		case now.Before(session.Close):
+			status.Phase, status.Open = PhaseOpen, true
+		case now.Before(session.AfterHoursClose):
+			status.Phase = PhaseAfterHours
+		}
+	}
+
+	// The next open is today's until it has passed, and the next close is
+	// that of the open session or of the next one
+	next := session
+	if !ok || !now.Before(session.Close) {
+		next, _ = SessionOn(NextTradingDay(now))
+	}
+	status.NextClose = next.Close
+	status.NextOpen = next.Open
+	if !now.Before(next.Open) {
+		following, _ := SessionOn(NextTradingDay(now))
+		status.NextOpen = following.Open
+	}
+	return status
+}
+
+// YearOf lists the holidays and early closes of year, which must lie within
+// [MinYear, MaxYear]
+func YearOf(year int) Year {
+	y := Year{Year: year, Holidays: []Day{}, EarlyCloses: []Day{}}
+	for t := date(year, time.January, 1); t.Year() == year; t = t.AddDate(0, 0, 1) {
+		if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+			continue
+		}
+		if name, ok := Holiday(t); ok {
+			y.Holidays = append(y.Holidays, Day{Date: t.Format("2006-01-02"), Name: name})
+			continue
+		}
+		y.TradingDays++
+		if name, ok := EarlyClose(t); ok {
+			session, _ := SessionOn(t)
+			y.EarlyCloses = append(y.EarlyCloses, Day{Date: t.Format("2006-01-02"), Name: name, Close: &session.Close})
+		}
+	}
+	return y
+}
+
+// yearRules are the holidays and early closes of a year by their dates
+type yearRules struct {
+	holidays    map[time.Time]string
+	earlyCloses map[time.Time]string
+}
+
+// rules caches the yearRules of the years looked up
+var rules sync.Map
+
+func rulesOf(year int) *yearRules {
+	if r, ok := rules.Load(year); ok {
+		return r.(*yearRules)
+	}
+	r, _ := rules.LoadOrStore(year, &yearRules{holidays: holidays(year), earlyCloses: earlyCloses(year)})
+	return r.(*yearRules)
+}
+
+// holidays returns the NYSE holidays of year, on the days they are observed.
+// A holiday on a Saturday is observed the Friday before, except New Year's
+// Day, and one on a Sunday the Monday after.
+func holidays(year int) map[time.Time]string {
+	days := make(map[time.Time]string)
+	observe := func(t time.Time, name string) {
+		switch t.Weekday() {
+		case time.Saturday:
+			if t.Month() == time.January && t.Day() == 1 {
+				return
+			}
+			t = t.AddDate(0, 0, -1)
+		case time.Sunday:
+			t = t.AddDate(0, 0, 1)
+		}
+		days[t] = name
+	}
+
+	observe(date(year, time.January, 1), "New Year's Day")
+	if year >= 1998 {
+		days[nthWeekday(year, time.January, time.Monday, 3)] = "Martin Luther King Jr. Day"
+	}
+	days[nthWeekday(year, time.February, time.Monday, 3)] = "Washington's Birthday"
+	days[easter(year).AddDate(0, 0, -2)] = "Good Friday"
+	days[lastWeekday(year, time.May, time.Monday)] = "Memorial Day"
+	if year >= 2022 {
+		observe(date(year, time.June, 19), "Juneteenth National Independence Day")
+	}
+	observe(date(year, time.July, 4), "Independence Day")
+	days[nthWeekday(year, time.September, time.Monday, 1)] = "Labor Day"
+	days[nthWeekday(year, time.November, time.Thursday, 4)] = "Thanksgiving Day"
+	observe(date(year, time.December, 25), "Christmas Day")
+	return days
+}
+
+// earlyCloses returns the days of year the market closes at 1 p.m.: the day
+// before Independence Day and Christmas Eve when they fall Monday to
+// Thursday, and the day after Thanksgiving
+func earlyCloses(year int) map[time.Time]string {
+	days := map[time.Time]string{
+		nthWeekday(year, time.November, time.Thursday, 4).AddDate(0, 0, 1): "Day after Thanksgiving",
+	}
+	for t, name := range map[time.Time]string{
+		date(year, time.July, 3):      "Independence Day eve",
+		date(year, time.December, 24): "Christmas Eve",
+	} {
+		if t.Weekday() >= time.Monday && t.Weekday() <= time.Thursday {
+			days[t] = name
+		}
+	}
+	return days
+}
+
+// date returns midnight UTC of a calendar day, the key of the maps above
+func date(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// day returns the calendar day of t, in t's location, as a date key
+func day(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return date(y, m, d)
+}
+
+// nthWeekday returns the nth weekday of a month, counting from one
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int) time.Time {
+	first := date(year, month, 1)
+	offset := (int(weekday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+7*(n-1))
+}
+
+// lastWeekday returns the last weekday of a month
+func lastWeekday(year int, month time.Month, weekday time.Weekday) time.Time {
+	last := date(year, month+1, 0)
+	offset := (int(last.Weekday()) - int(weekday) + 7) % 7
+	return last.AddDate(0, 0, -offset)
+}
+
+// easter returns Easter Sunday of year by the anonymous Gregorian algorithm
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	return date(year, time.Month(month), (h+l-7*m+114)%31+1)
+}
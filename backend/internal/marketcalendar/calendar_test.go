@@ -0,0 +1,146 @@
+package marketcalendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func dates(days []Day) []string {
+	var out []string
+	for _, d := range days {
+		out = append(out, d.Date)
+	}
+	return out
+}
+
+func TestYearOf(t *testing.T) {
+	tests := []struct {
+		year        int
+		tradingDays int
+		holidays    []string
+		earlyCloses []string
+	}{
+		{
+			year:        2025,
+			tradingDays: 250,
+			holidays: []string{"2025-01-01", "2025-01-09", "2025-01-20", "2025-02-17", "2025-04-18", "2025-05-26",
+				"2025-06-19", "2025-07-04", "2025-09-01", "2025-11-27", "2025-12-25"},
+			earlyCloses: []string{"2025-07-03", "2025-11-28", "2025-12-24"},
+		},
+		{
+			// Independence Day on a Saturday is observed the Friday before
+			year:        2026,
+			tradingDays: 251,
+			holidays: []string{"2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25",
+				"2026-06-19", "2026-07-03", "2026-09-07", "2026-11-26", "2026-12-25"},
+			earlyCloses: []string{"2026-11-27", "2026-12-24"},
+		},
+		{
+			// Juneteenth and Christmas fall on Saturdays; New Year's Day 2028
+			// does too, but is not observed on December 31
+			year:        2027,
+			tradingDays: 251,
+			holidays: []string{"2027-01-01", "2027-01-18", "2027-02-15", "2027-03-26", "2027-05-31",
+				"2027-06-18", "2027-07-05", "2027-09-06", "2027-11-25", "2027-12-24"},
+			earlyCloses: []string{"2027-11-26"},
+		},
+	}
+
+	for _, tt := range tests {
+		year := YearOf(tt.year)
+		assert.Equal(t, tt.holidays, dates(year.Holidays), "%d holidays", tt.year)
+		assert.Equal(t, tt.earlyCloses, dates(year.EarlyCloses), "%d early closes", tt.year)
+		assert.Equal(t, tt.tradingDays, year.TradingDays, "%d trading days", tt.year)
+	}
+
+	early := YearOf(2025).EarlyCloses[0]
+	assert.Equal(t, "Independence Day eve", early.Name)
+	require.NotNil(t, early.Close)
+	assert.Equal(t, time.Date(2025, 7, 3, 17, 0, 0, 0, time.UTC), early.Close.UTC())
+}
+
+func TestTradingDays(t *testing.T) {
+	assert.True(t, IsTradingDay(time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, IsTradingDay(time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC)), "saturday")
+	assert.False(t, IsTradingDay(time.Date(2025, 4, 18, 12, 0, 0, 0, Location())), "good friday")
+
+	name, ok := Holiday(time.Date(2025, 11, 27, 0, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Thanksgiving Day", name)
+
+	assert.Equal(t, time.Date(2025, 4, 21, 0, 0, 0, 0, time.UTC), NextTradingDay(time.Date(2025, 4, 17, 15, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), PreviousTradingDay(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestStatusAt(t *testing.T) {
+	ny := Location()
+	at := func(y int, m time.Month, d, h, min int) time.Time { return time.Date(y, m, d, h, min, 0, 0, ny) }
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		phase     Phase
+		holiday   string
+		nextOpen  time.Time
+		nextClose time.Time
+	}{
+		{
+			name:      "overnight",
+			now:       at(2025, 3, 5, 3, 0),
+			phase:     PhaseClosed,
+			nextOpen:  at(2025, 3, 5, 9, 30),
+			nextClose: at(2025, 3, 5, 16, 0),
+		},
+		{
+			name:      "pre-market",
+			now:       at(2025, 3, 5, 8, 0),
+			phase:     PhasePreMarket,
+			nextOpen:  at(2025, 3, 5, 9, 30),
+			nextClose: at(2025, 3, 5, 16, 0),
+		},
+		{
+			name:      "regular session",
+			now:       at(2025, 3, 5, 10, 0),
+			phase:     PhaseOpen,
+			nextOpen:  at(2025, 3, 6, 9, 30),
+			nextClose: at(2025, 3, 5, 16, 0),
+		},
+		{
+			name:      "after an early close",
+			now:       at(2025, 11, 28, 14, 0),
+			phase:     PhaseAfterHours,
+			nextOpen:  at(2025, 12, 1, 9, 30),
+			nextClose: at(2025, 12, 1, 16, 0),
+		},
+		{
+			name:      "holiday",
+			now:       at(2025, 12, 25, 11, 0),
+			phase:     PhaseClosed,
+			holiday:   "Christmas Day",
+			nextOpen:  at(2025, 12, 26, 9, 30),
+			nextClose: at(2025, 12, 26, 16, 0),
+		},
+		{
+			name:      "friday night",
+			now:       at(2025, 3, 7, 21, 0),
+			phase:     PhaseClosed,
+			nextOpen:  at(2025, 3, 10, 9, 30),
+			nextClose: at(2025, 3, 10, 16, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := StatusAt(tt.now.UTC())
+			assert.Equal(t, tt.phase, status.Phase)
+			assert.Equal(t, tt.phase == PhaseOpen, status.Open)
+			assert.Equal(t, tt.holiday, status.Holiday)
+			assert.True(t, tt.nextOpen.Equal(status.NextOpen), "next open %v, want %v", status.NextOpen, tt.nextOpen)
+			assert.True(t, tt.nextClose.Equal(status.NextClose), "next close %v, want %v", status.NextClose, tt.nextClose)
+			assert.Equal(t, tt.holiday == "", status.Session != nil, "only holidays have no session")
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageRecorder counts API requests for the request analytics
+type UsageRecorder interface {
+	Record(route, keyID, symbol string, at time.Time)
+}
+
+// Analytics records each matched request by method and route template, API
+// key and, for routes with a :symbol parameter, ticker symbol. It must run
+// before APIKeyAuth so that rejected requests are counted too.
+func Analytics(recorder UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			return
+		}
+
+		var keyID string
+		if key, ok := APIKeyFromContext(c); ok {
+			keyID = key.ID
+		}
+		symbol := strings.ToUpper(strings.TrimSpace(c.Param("symbol")))
+
+		recorder.Record(c.Request.Method+" "+route, keyID, symbol, time.Now())
+	}
+}
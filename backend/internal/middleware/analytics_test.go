@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type usage struct {
+	route, keyID, symbol string
+}
+
+type recordedUsage []usage
+
+func (r *recordedUsage) Record(route, keyID, symbol string, at time.Time) {
+	*r = append(*r, usage{route, keyID, symbol})
+}
+
+func TestAnalytics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	recorded := &recordedUsage{}
+	r := gin.New()
+	r.Use(Analytics(recorded))
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader(APIKeyHeader) == "k" {
+			c.Set(apiKeyContextKey, &models.APIKey{ID: "key-id"})
+		}
+	})
+	r.GET("/api/tickers/:symbol", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/api/tickers/aapl", nil)
+	req.Header.Set(APIKeyHeader, "k")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/api/tickers/MSFT", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/wp-login.php", nil))
+
+	assert.Equal(t, recordedUsage{
+		{route: "GET /api/tickers/:symbol", keyID: "key-id", symbol: "AAPL"},
+		{route: "GET /api/tickers/:symbol", symbol: "MSFT"},
+	}, *recorded, "unmatched requests are not recorded")
+}
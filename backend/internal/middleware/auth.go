@@ -0,0 +1,144 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIKeyHeader is the request header carrying the API key
+	APIKeyHeader = "X-API-Key"
+	// apiKeyContextKey is where the authenticated key is stored on the gin context
+	apiKeyContextKey = "apiKey"
+)
+
+// Authenticator resolves a plaintext API key to its stored record
+type Authenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+// APIKeyAuth rejects requests without a valid, unrevoked X-API-Key header,
+// unless SignedRequestAuth already authenticated them
+func APIKeyAuth(auth Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := APIKeyFromContext(c); ok {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			problem.Abort(c, problem.Unauthenticated, "Missing API key")
+			return
+		}
+
+		record, err := auth.Authenticate(c.Request.Context(), key)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidAPIKey) {
+				problem.Abort(c, problem.Unauthenticated, "Invalid API key")
+				return
+			}
+			_ = c.Error(err)
+			problem.Abort(c, problem.Internal, "Failed to authenticate request")
+			return
+		}
+
+		// Services enforce plan limits against the key on the request context
+		c.Request = c.Request.WithContext(authenticated(c, record))
+		c.Next()
+	}
+}
+
+// authenticated stores the key a request authenticated as on the gin context
+// and returns the request context carrying it as the account, with a logger
+// annotated with its key_id and fields
+func authenticated(c *gin.Context, record *models.APIKey, fields ...any) context.Context {
+	c.Set(apiKeyContextKey, record)
+	ctx := service.WithAccount(c.Request.Context(), record)
+	log := logger.FromContext(ctx, logger.Get()).With(append([]any{"key_id", record.ID}, fields...)...)
+	return logger.NewContext(ctx, log)
+}
+
+// RequireAdmin rejects requests whose API key is not an admin key, or is one
+// restricted to scopes other than admin. It must run after APIKeyAuth.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := APIKeyFromContext(c)
+		if !ok || !key.Admin || !key.HasScope(models.ScopeAdmin) {
+			problem.Abort(c, problem.Forbidden, "Admin API key required")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireScope rejects requests whose API key is restricted to scopes other
+// than scope. Requests without a key, which reach routes only when API keys
+// are not required, pass.
+func RequireScope(scope models.APIKeyScope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := APIKeyFromContext(c); ok && !key.HasScope(scope) {
+			problem.Abort(c, problem.Forbidden, "API key lacks the "+string(scope)+" scope")
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireFullAccess rejects requests whose API key is restricted to scopes,
+// guarding the account routes no scope grants
+func RequireFullAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key, ok := APIKeyFromContext(c); ok && len(key.Scopes) > 0 {
+			problem.Abort(c, problem.Forbidden, "API key is restricted to scopes")
+			return
+		}
+		c.Next()
+	}
+}
+
+// QuotaEnforcer counts requests against the daily quota of the key on the
+// request context
+type QuotaEnforcer interface {
+	ConsumeRequest(ctx context.Context) error
+}
+
+// RequestQuota rejects requests once the daily quota of the key's plan is used
+// up, with 402 when a higher tier has a larger quota and 403 otherwise. It must
+// run after APIKeyAuth.
+func RequestQuota(quotas QuotaEnforcer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := quotas.ConsumeRequest(c.Request.Context())
+		switch {
+		case errors.Is(err, service.ErrUpgradeRequired):
+			problem.Abort(c, problem.UpgradeRequired, err.Error())
+			return
+		case errors.Is(err, service.ErrPlanLimitExceeded):
+			problem.Abort(c, problem.PlanLimitExceeded, err.Error())
+			return
+		case err != nil:
+			_ = c.Error(err)
+			problem.Abort(c, problem.Internal, "Failed to check request quota")
+			return
+		}
+		c.Next()
+	}
+}
+
+// APIKeyFromContext returns the key authenticated by APIKeyAuth or
+// SignedRequestAuth, if any
+func APIKeyFromContext(c *gin.Context) (*models.APIKey, bool) {
+	value, ok := c.Get(apiKeyContextKey)
+	if !ok {
+		return nil, false
+	}
+	key, ok := value.(*models.APIKey)
+	return key, ok
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAuthenticator map[string]*models.APIKey
+
+func (f fakeAuthenticator) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if key == "broken" {
+		return nil, errors.New("table unavailable")
+	}
+	record, ok := f[key]
+	if !ok {
+		return nil, service.ErrInvalidAPIKey
+	}
+	return record, nil
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := fakeAuthenticator{
+		"user-key":  {ID: "u", Name: "user"},
+		"admin-key": {ID: "a", Name: "admin", Admin: true},
+	}
+
+	engine := gin.New()
+	api := engine.Group("/api", APIKeyAuth(auth))
+	api.GET("/tickers", func(c *gin.Context) {
+		key, ok := APIKeyFromContext(c)
+		require.True(t, ok)
+		account, ok := service.AccountFromContext(c.Request.Context())
+		require.True(t, ok)
+		assert.Equal(t, key, account)
+		c.JSON(http.StatusOK, gin.H{"key": key.Name})
+	})
+	api.GET("/admin/keys", RequireAdmin(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		key            string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "valid key", path: "/api/tickers", key: "user-key", expectedStatus: http.StatusOK},
+		{name: "missing key", path: "/api/tickers", expectedStatus: http.StatusUnauthorized, expectedError: "Missing API key"},
+		{name: "unknown key", path: "/api/tickers", key: "guess", expectedStatus: http.StatusUnauthorized, expectedError: "Invalid API key"},
+		{name: "lookup failure", path: "/api/tickers", key: "broken", expectedStatus: http.StatusInternalServerError, expectedError: "Failed to authenticate request"},
+		{name: "admin route with user key", path: "/api/admin/keys", key: "user-key", expectedStatus: http.StatusForbidden, expectedError: "Admin API key required"},
+		{name: "admin route with admin key", path: "/api/admin/keys", key: "admin-key", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(APIKeyHeader, tt.key)
+			}
+
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.expectedError, response["detail"])
+			}
+		})
+	}
+}
+
+type fakeQuotas map[string]error
+
+func (f fakeQuotas) ConsumeRequest(ctx context.Context) error {
+	key, _ := service.AccountFromContext(ctx)
+	return f[key.Name]
+}
+
+func TestRequestQuota(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := fakeAuthenticator{
+		"ok-key":   {ID: "o", Name: "ok"},
+		"free-key": {ID: "f", Name: "free"},
+		"pro-key":  {ID: "p", Name: "pro"},
+	}
+	quotas := fakeQuotas{
+		"free": fmt.Errorf("%w: quota used up", service.ErrUpgradeRequired),
+		"pro":  fmt.Errorf("%w: quota used up", service.ErrPlanLimitExceeded),
+	}
+
+	engine := gin.New()
+	engine.GET("/api/tickers", APIKeyAuth(auth), RequestQuota(quotas), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for key, want := range map[string]int{
+		"ok-key":   http.StatusOK,
+		"free-key": http.StatusPaymentRequired,
+		"pro-key":  http.StatusForbidden,
+	} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/tickers", nil)
+		req.Header.Set(APIKeyHeader, key)
+
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, key)
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := fakeAuthenticator{
+		"full-key":   {ID: "f", Name: "full"},
+		"market-key": {ID: "m", Name: "market", Scopes: []models.APIKeyScope{models.ScopeReadMarket}},
+		"admin-key":  {ID: "a", Name: "admin", Admin: true, Scopes: []models.APIKeyScope{models.ScopeReadMarket}},
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	engine := gin.New()
+	engine.GET("/open", RequireScope(models.ScopeReadMarket), RequireFullAccess(), ok)
+	api := engine.Group("/api", APIKeyAuth(auth))
+	api.GET("/tickers", RequireScope(models.ScopeReadMarket), ok)
+	api.GET("/portfolios", RequireScope(models.ScopeWritePortfolio), ok)
+	api.GET("/alerts", RequireFullAccess(), ok)
+	api.GET("/admin/keys", RequireAdmin(), ok)
+
+	tests := []struct {
+		path           string
+		key            string
+		expectedStatus int
+	}{
+		{path: "/open", expectedStatus: http.StatusOK},
+		{path: "/api/tickers", key: "full-key", expectedStatus: http.StatusOK},
+		{path: "/api/portfolios", key: "full-key", expectedStatus: http.StatusOK},
+		{path: "/api/alerts", key: "full-key", expectedStatus: http.StatusOK},
+		{path: "/api/tickers", key: "market-key", expectedStatus: http.StatusOK},
+		{path: "/api/portfolios", key: "market-key", expectedStatus: http.StatusForbidden},
+		{path: "/api/alerts", key: "market-key", expectedStatus: http.StatusForbidden},
+		{path: "/api/admin/keys", key: "admin-key", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tt.path, nil)
+		if tt.key != "" {
+			req.Header.Set(APIKeyHeader, tt.key)
+		}
+
+		engine.ServeHTTP(w, req)
+
+		assert.Equal(t, tt.expectedStatus, w.Code, "%s with %q", tt.path, tt.key)
+	}
+}
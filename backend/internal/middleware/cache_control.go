@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheControl sets the Cache-Control header of GET and HEAD requests to
+// their route template's policy, e.g. "private, max-age=60" for /api/tickers.
+// Problem responses drop it, so errors are not cached.
+func CacheControl(policies map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			if policy, ok := policies[c.FullPath()]; ok {
+				c.Header("Cache-Control", policy)
+			}
+		}
+		c.Next()
+	}
+}
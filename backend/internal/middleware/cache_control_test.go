@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheControl(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CacheControl(map[string]string{"/api/tickers/:symbol": "private, max-age=60"}))
+	r.GET("/api/tickers/:symbol", func(c *gin.Context) {
+		if c.Param("symbol") == "NOPE" {
+			problem.Respond(c, problem.TickerNotFound, "ticker not found")
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"ticker": c.Param("symbol")})
+	})
+	r.POST("/api/tickers/:symbol", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/api/other", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for name, tc := range map[string]struct {
+		method, path string
+		want         string
+	}{
+		"route with a policy": {http.MethodGet, "/api/tickers/AAPL", "private, max-age=60"},
+		"problem response":    {http.MethodGet, "/api/tickers/NOPE", ""},
+		"unsafe method":       {http.MethodPost, "/api/tickers/AAPL", ""},
+		"route without one":   {http.MethodGet, "/api/other", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, tc.want, w.Header().Get("Cache-Control"))
+		})
+	}
+}
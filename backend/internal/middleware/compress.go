@@ -0,0 +1,251 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// encoder compresses a response body. The writers of compress/gzip and
+// compress/flate are pooled and reset onto each response.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// encoders are the content codings responses are compressed with, in order
+// of preference when a client accepts several equally
+var encoders = []struct {
+	name string
+	pool *sync.Pool
+}{
+	{"gzip", &sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}},
+	{"deflate", &sync.Pool{New: func() any {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	}}},
+}
+
+// Compress compresses responses of at least minSize bytes with the best
+// content coding the request's Accept-Encoding allows. Requests whose path
+// starts with one of excluded, such as routes that hijack the connection or
+// compress themselves, pass through, as do responses whose handler set their
+// own Content-Encoding.
+func Compress(minSize int, excluded []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, prefix := range excluded {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		coding, pool := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if pool == nil {
+			c.Next()
+			return
+		}
+
+		w := &compressWriter{
+			ResponseWriter: c.Writer,
+			coding:         coding,
+			pool:           pool,
+			minSize:        minSize,
+			status:         http.StatusOK,
+		}
+		c.Writer = w
+		// A panicking handler's buffered response is dropped, for the
+		// recovery handler to answer on the underlying writer
+		completed := false
+		defer func() {
+			c.Writer = w.ResponseWriter
+			if completed {
+				w.finish()
+			}
+		}()
+		c.Next()
+		completed = true
+	}
+}
+
+// negotiateEncoding returns the accepted coding of the highest quality and the
+// pool of its encoder, or a nil pool when the client accepts none of them
+func negotiateEncoding(header string) (string, *sync.Pool) {
+	qualities := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		qualities[coding] = quality(params)
+	}
+
+	var best string
+	var bestPool *sync.Pool
+	bestQ := 0.0
+	for _, e := range encoders {
+		q, ok := qualities[e.name]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQ {
+			best, bestPool, bestQ = e.name, e.pool, q
+		}
+	}
+	return best, bestPool
+}
+
+// quality returns the q parameter of a coding, 1 when absent or malformed
+func quality(params string) float64 {
+	for _, param := range strings.Split(params, ";") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(param), "q=")
+		if !ok {
+			continue
+		}
+		q, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 1
+		}
+		return q
+	}
+	return 1
+}
+
+// compressWriter buffers a response until it reaches minSize bytes, then
+// sends it compressed. Smaller responses are sent as they are once the
+// handler returns, since compressing them saves too little to pay for itself.
+type compressWriter struct {
+	gin.ResponseWriter
+	coding  string
+	pool    *sync.Pool
+	minSize int
+
+	status  int
+	buf     []byte
+	size    int
+	written bool
+	// started is set once the header is sent, with enc set when compressing
+	started bool
+	enc     encoder
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if !w.started {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow defers the header until the response is large enough to
+// compress or complete
+func (w *compressWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	w.written = true
+	w.size += len(p)
+	if w.started {
+		return w.body().Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.minSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Status() int {
+	if w.started {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+// Size is the uncompressed size of the body written so far
+func (w *compressWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.size
+}
+
+func (w *compressWriter) Written() bool {
+	return w.written
+}
+
+// Flush sends the response so far, compressed, for handlers streaming it
+func (w *compressWriter) Flush() {
+	if !w.started {
+		if err := w.start(true); err != nil {
+			return
+		}
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// start sends the header and the buffered body, compressing from here on
+// when compress is set and the response has a body not encoded by its handler
+func (w *compressWriter) start(compress bool) error {
+	w.started = true
+	header := w.Header()
+	if compress && header.Get("Content-Encoding") == "" && bodyAllowed(w.status) {
+		header.Set("Content-Encoding", w.coding)
+		header.Del("Content-Length")
+		w.enc = w.pool.Get().(encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		w.ResponseWriter.WriteHeaderNow()
+		return nil
+	}
+	_, err := w.body().Write(buf)
+	return err
+}
+
+// finish sends a response too small to compress, or ends the compressed one
+func (w *compressWriter) finish() {
+	if !w.started {
+		_ = w.start(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.pool.Put(w.enc)
+		w.enc = nil
+	}
+}
+
+func (w *compressWriter) body() io.Writer {
+	if w.enc != nil {
+		return w.enc
+	}
+	return w.ResponseWriter
+}
+
+// bodyAllowed reports whether responses of status carry a body
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
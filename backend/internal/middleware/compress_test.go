@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	large := strings.Repeat(`{"symbol":"AAPL"},`, 200)
+
+	r := gin.New()
+	r.Use(Compress(1024, []string{"/ws"}))
+	r.GET("/large", func(c *gin.Context) {
+		c.String(http.StatusOK, large)
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"ok": true})
+	})
+	r.GET("/empty", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/encoded", func(c *gin.Context) {
+		c.Header("Content-Encoding", "gzip")
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+	r.GET("/ws", func(c *gin.Context) {
+		c.String(http.StatusOK, large)
+	})
+	return r
+}
+
+func get(r http.Handler, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompress(t *testing.T) {
+	r := compressRouter()
+	large := strings.Repeat(`{"symbol":"AAPL"},`, 200)
+
+	w := get(r, "/large", "gzip, deflate, br")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Less(t, w.Body.Len(), len(large))
+	zr, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	w = get(r, "/large", "gzip;q=0.5, deflate")
+	assert.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+	body, err = io.ReadAll(flate.NewReader(w.Body))
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body))
+
+	// The pooled encoder is reset between responses
+	w = get(r, "/large", "gzip")
+	zr, err = gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err = io.ReadAll(zr)
+	require.NoError(t, err)
+	assert.Equal(t, large, string(body))
+}
+
+func TestCompress_PassesThrough(t *testing.T) {
+	r := compressRouter()
+	large := strings.Repeat(`{"symbol":"AAPL"},`, 200)
+
+	for name, tc := range map[string]struct {
+		path, acceptEncoding string
+		status               int
+		body                 string
+	}{
+		"not accepted":       {"/large", "", http.StatusOK, large},
+		"refused":            {"/large", "gzip;q=0, *;q=0", http.StatusOK, large},
+		"unsupported":        {"/large", "br", http.StatusOK, large},
+		"below minimum size": {"/small", "gzip", http.StatusCreated, `{"ok":true}`},
+		"no body":            {"/empty", "gzip", http.StatusNoContent, ""},
+		"excluded":           {"/ws", "gzip", http.StatusOK, large},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := get(r, tc.path, tc.acceptEncoding)
+			assert.Equal(t, tc.status, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tc.body, w.Body.String())
+		})
+	}
+
+	w := get(r, "/encoded", "gzip")
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, large, w.Body.String(), "bodies encoded by their handler are not encoded again")
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for header, want := range map[string]string{
+		"gzip":                   "gzip",
+		"deflate, gzip":          "gzip",
+		"deflate;q=1, gzip;q=.8": "deflate",
+		"*":                      "gzip",
+		"gzip;q=0, *":            "deflate",
+		"identity":               "",
+		"":                       "",
+	} {
+		coding, _ := negotiateEncoding(header)
+		assert.Equal(t, want, coding, header)
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation announces that a route is deprecated, in the Deprecation header
+// (RFC 9745), when it stops being served, in the Sunset header (RFC 8594), and
+// which route replaces it, in a successor-version link
+type Deprecation struct {
+	// Since is when the route was deprecated
+	Since time.Time
+	// Sunset is when the route is removed; zero announces no date
+	Sunset time.Time
+	// Successor is the path of the route replacing it, if any
+	Successor string
+}
+
+// ParseDeprecation parses the dates of a deprecation as YYYY-MM-DD, or as
+// YYYY-MM-DD/YYYY-MM-DD to also announce its sunset
+func ParseDeprecation(value string) (Deprecation, error) {
+	since, sunset, hasSunset := strings.Cut(value, "/")
+	var d Deprecation
+	var err error
+	if d.Since, err = time.Parse(time.DateOnly, since); err != nil {
+		return Deprecation{}, fmt.Errorf("deprecation %q is not YYYY-MM-DD or YYYY-MM-DD/YYYY-MM-DD", value)
+	}
+	if hasSunset {
+		if d.Sunset, err = time.Parse(time.DateOnly, sunset); err != nil || d.Sunset.Before(d.Since) {
+			return Deprecation{}, fmt.Errorf("deprecation %q does not end in a sunset on or after it", value)
+		}
+	}
+	return d, nil
+}
+
+// SetHeaders announces the deprecation on the response
+func (d Deprecation) SetHeaders(c *gin.Context) {
+	c.Header("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	if !d.Sunset.IsZero() {
+		c.Header("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Successor != "" {
+		c.Header("Link", "<"+d.Successor+`>; rel="successor-version"`)
+	}
+}
+
+// Deprecate announces d on every response of the routes below it
+func Deprecate(d Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		d.SetHeaders(c)
+		c.Next()
+	}
+}
+
+// Deprecations announces the deprecation of each route in routes, keyed by
+// method and route template, e.g. "GET /api/v1/prices"
+func Deprecations(routes map[string]Deprecation) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d, ok := routes[c.Request.Method+" "+c.FullPath()]; ok {
+			d.SetHeaders(c)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	d, err := ParseDeprecation("2026-10-01")
+	require.NoError(t, err)
+	assert.Equal(t, Deprecation{Since: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)}, d)
+
+	d, err = ParseDeprecation("2026-10-01/2027-04-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC), d.Sunset)
+
+	for _, value := range []string{"", "10/01/2026", "2026-10-01/", "2026-10-01/2026-09-30"} {
+		_, err := ParseDeprecation(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestDeprecations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Deprecations(map[string]Deprecation{
+		"GET /api/v1/prices": {
+			Since:     time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+			Sunset:    time.Date(2027, 4, 1, 0, 0, 0, 0, time.UTC),
+			Successor: "/api/v1/quotes",
+		},
+	}))
+	r.GET("/api/v1/prices", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api/v1/prices", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/prices", nil))
+	assert.Equal(t, "@1790812800", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/quotes>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/prices", nil))
+	assert.Empty(t, w.Header().Get("Deprecation"), "deprecations are per method")
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/errorlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorRecorder keeps the server errors answered for the recent errors summary
+type ErrorRecorder interface {
+	Record(entry errorlog.Entry)
+}
+
+// RecordErrors records each response with a 5xx status by method and route
+// template and the problem code answered. Panics are recorded as internal
+// errors before the recovery middleware answers them.
+func RecordErrors(recorder ErrorRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		record := func(status int, code problem.Code) {
+			recorder.Record(errorlog.Entry{
+				At:     time.Now(),
+				Route:  c.Request.Method + " " + c.FullPath(),
+				Status: status,
+				Code:   string(code),
+			})
+		}
+
+		defer func() {
+			if p := recover(); p != nil {
+				record(http.StatusInternalServerError, problem.Internal)
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		if status := c.Writer.Status(); status >= http.StatusInternalServerError {
+			code, _ := problem.CodeOf(c)
+			record(status, code)
+		}
+	}
+}
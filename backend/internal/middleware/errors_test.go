@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/errorlog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	log := errorlog.New(10)
+	engine := gin.New()
+	engine.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		problem.Abort(c, problem.Internal, "Internal server error")
+	}))
+	engine.Use(RecordErrors(log))
+	engine.GET("/api/tickers/:symbol", func(c *gin.Context) {
+		if c.Param("symbol") == "PANIC" {
+			panic("boom")
+		}
+		if c.Param("symbol") == "FAIL" {
+			problem.Respond(c, problem.Internal, "Failed to retrieve ticker")
+			return
+		}
+		problem.Respond(c, problem.TickerNotFound, "Ticker not found")
+	})
+
+	// The panic is recorded before the recovery middleware answers it
+	for _, path := range []string{"/api/tickers/FAIL", "/api/tickers/ZZZZ", "/api/tickers/PANIC"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	summary := log.Summarize(time.Minute, time.Now())
+	assert.Equal(t, 2, summary.Total, "client errors are not recorded")
+	assert.Equal(t, []errorlog.Group{{
+		Route:       "GET /api/tickers/:symbol",
+		Code:        "INTERNAL_ERROR",
+		Status:      http.StatusInternalServerError,
+		Count:       2,
+		LastSeenUTC: summary.Groups[0].LastSeenUTC,
+	}}, summary.Groups)
+}
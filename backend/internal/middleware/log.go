@@ -8,9 +8,16 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Log logs the completion of every request. The request's logger, available
+// to handlers and services through logger.FromContext, carries the route
+// matched, and the key and user once authenticated.
 func Log() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		log := logger.Get()
+		log := logger.FromContext(c.Request.Context(), logger.Get())
+		if route := c.FullPath(); route != "" {
+			log = log.With("route", route)
+			c.Request = c.Request.WithContext(logger.NewContext(c.Request.Context(), log))
+		}
 		c.Set("logger", log)
 
 		start := time.Now()
@@ -38,7 +45,11 @@ func Log() gin.HandlerFunc {
 			"user_agent": c.Request.UserAgent(),
 		}
 
-		logWithFields := logger.WithFields(fields)
+		// Authentication annotates the logger of the request once it is known
+		logWithFields := logger.FromContext(c.Request.Context(), log)
+		for k, v := range fields {
+			logWithFields = logWithFields.With(k, v)
+		}
 
 		if len(c.Errors) > 0 {
 			logWithFields.Errorf("Request failed: %s", errorMessage)
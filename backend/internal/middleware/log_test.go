@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLog_RequestFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	r := gin.New()
+	r.Use(RequestID(zap.New(core).Sugar()), Log())
+	api := r.Group("/api", APIKeyAuth(fakeAuthenticator{"user-key": {ID: "u", Name: "user"}}))
+	api.GET("/tickers/:symbol", func(c *gin.Context) {
+		// Services log with the logger of the context they are passed
+		logger.FromContext(c.Request.Context(), zap.NewNop().Sugar()).Info("handled")
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/health", func(c *gin.Context) {
+		logger.FromContext(c.Request.Context(), zap.NewNop().Sugar()).Info("checked")
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("authenticated route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/tickers/AAPL", nil)
+		req.Header.Set(APIKeyHeader, "user-key")
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		for _, message := range []string{"handled", "Request completed"} {
+			entries := logs.FilterMessage(message).TakeAll()
+			if assert.Len(t, entries, 1, message) {
+				fields := entries[0].ContextMap()
+				assert.Equal(t, w.Header().Get(RequestIDHeader), fields["request_id"], message)
+				assert.Equal(t, "/api/tickers/:symbol", fields["route"], message)
+				assert.Equal(t, "u", fields["key_id"], message)
+			}
+		}
+	})
+
+	t.Run("unauthenticated route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		entries := logs.FilterMessage("checked").TakeAll()
+		if assert.Len(t, entries, 1) {
+			fields := entries[0].ContextMap()
+			assert.Equal(t, "/health", fields["route"])
+			assert.NotContains(t, fields, "key_id")
+		}
+	})
+
+	t.Run("unmatched route", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/missing", nil))
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		entries := logs.FilterMessage("Client error").TakeAll()
+		if assert.Len(t, entries, 1) {
+			assert.NotContains(t, entries[0].ContextMap(), "route")
+		}
+	})
+}
+
+func TestAuthenticated_UserFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	r := gin.New()
+	r.Use(RequestID(zap.New(core).Sugar()))
+	r.GET("/", func(c *gin.Context) {
+		c.Request = c.Request.WithContext(authenticated(c, &models.APIKey{ID: "k"}, "user_id", "user-1"))
+		logger.FromContext(c.Request.Context(), zap.NewNop().Sugar()).Info("handled")
+		c.Status(http.StatusNoContent)
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	entries := logs.FilterMessage("handled").All()
+	if assert.Len(t, entries, 1) {
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "k", fields["key_id"])
+		assert.Equal(t, "user-1", fields["user_id"])
+		assert.Contains(t, fields, "request_id")
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"profitify-backend/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unmatchedRoute labels requests that matched no route, so probes of random
+// paths cannot grow the number of series
+const unmatchedRoute = "unmatched"
+
+// Metrics records request count, latency and in-flight requests by route
+// template, e.g. /api/v1/tickers/:symbol, and status
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		done := m.RequestStarted(c.Request.Method, route)
+		defer func() {
+			done(c.Writer.Status())
+		}()
+
+		c.Next()
+	}
+}
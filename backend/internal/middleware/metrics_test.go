@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := metrics.New()
+	r := gin.New()
+	r.Use(Metrics(m))
+	r.GET("/api/tickers/:symbol", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET("/metrics", gin.WrapH(m.Handler()))
+
+	for _, path := range []string{"/api/tickers/AAPL", "/api/tickers/MSFT", "/wp-login.php"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body := w.Body.String()
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, body, `profitify_http_requests_total{method="GET",route="/api/tickers/:symbol",status="200"} 2`)
+	assert.Contains(t, body, `profitify_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `profitify_http_request_duration_seconds_count{method="GET",route="/api/tickers/:symbol",status="200"} 2`)
+	assert.Contains(t, body, `profitify_http_requests_in_flight{method="GET",route="/api/tickers/:symbol"} 0`)
+	// The scrape itself is in flight while it is served
+	assert.Contains(t, body, `profitify_http_requests_in_flight{method="GET",route="/metrics"} 1`)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rate limit response headers
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimit rejects requests with 429 once their bucket in limiter is empty.
+// Requests are keyed by their API key when APIKeyAuth ran before, and by
+// client IP otherwise. Every response carries the X-RateLimit-* headers, with
+// the reset in seconds until the bucket is full; rejections add Retry-After.
+func RateLimit(limiter *ratelimit.Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := "ip:" + c.ClientIP()
+		if record, ok := APIKeyFromContext(c); ok {
+			key = "key:" + record.ID
+		}
+
+		d := limiter.Allow(key)
+		c.Header(RateLimitLimitHeader, strconv.Itoa(d.Limit))
+		c.Header(RateLimitRemainingHeader, strconv.Itoa(d.Remaining))
+		c.Header(RateLimitResetHeader, seconds(d.Reset))
+
+		if !d.Allowed {
+			c.Header("Retry-After", seconds(d.RetryAfter))
+			problem.Abort(c, problem.RateLimited, "Rate limit exceeded")
+			return
+		}
+		c.Next()
+	}
+}
+
+// seconds formats d as whole seconds, rounded up
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
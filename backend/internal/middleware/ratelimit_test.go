@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := fakeAuthenticator{
+		"user-key":  {ID: "u", Name: "user"},
+		"other-key": {ID: "o", Name: "other"},
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	engine := gin.New()
+	engine.GET("/public", RateLimit(ratelimit.New(ratelimit.Limit{Rate: 1, Burst: 2})), ok)
+	engine.GET("/private", APIKeyAuth(auth), RateLimit(ratelimit.New(ratelimit.Limit{Rate: 1, Burst: 2})), ok)
+
+	serve := func(path, key, ip string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = ip + ":1234"
+		if key != "" {
+			req.Header.Set(APIKeyHeader, key)
+		}
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("keyed by client ip without a key", func(t *testing.T) {
+		w := serve("/public", "", "10.0.0.1")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "2", w.Header().Get(RateLimitLimitHeader))
+		assert.Equal(t, "1", w.Header().Get(RateLimitRemainingHeader))
+		assert.Equal(t, "1", w.Header().Get(RateLimitResetHeader))
+
+		assert.Equal(t, http.StatusOK, serve("/public", "", "10.0.0.1").Code)
+		w = serve("/public", "", "10.0.0.1")
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "1", w.Header().Get("Retry-After"))
+		assert.Equal(t, "0", w.Header().Get(RateLimitRemainingHeader))
+
+		assert.Equal(t, http.StatusOK, serve("/public", "", "10.0.0.2").Code, "other clients are unaffected")
+	})
+
+	t.Run("keyed by api key when authenticated", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("/private", "user-key", "10.0.0.1").Code)
+		assert.Equal(t, http.StatusOK, serve("/private", "user-key", "10.0.0.2").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve("/private", "user-key", "10.0.0.3").Code, "a key is limited across addresses")
+		assert.Equal(t, http.StatusOK, serve("/private", "other-key", "10.0.0.1").Code)
+	})
+}
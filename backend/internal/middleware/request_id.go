@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"profitify-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDHeader is the request and response header carrying the request ID
+	RequestIDHeader = "X-Request-ID"
+	// requestIDContextKey is where the request ID is stored on the gin context
+	requestIDContextKey = "requestID"
+	// maxRequestIDLength bounds IDs accepted from clients
+	maxRequestIDLength = 128
+)
+
+// RequestID accepts the client's X-Request-ID, or generates one when it is
+// missing or malformed, and echoes it in the response. The request's logger,
+// derived from log and available through logger.FromContext, carries it as
+// request_id.
+func RequestID(log *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		c.Set(requestIDContextKey, id)
+		c.Header(RequestIDHeader, id)
+
+		ctx := logger.NewContext(c.Request.Context(), log.With("request_id", id))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+// RequestIDFromContext returns the ID assigned by RequestID
+func RequestIDFromContext(c *gin.Context) string {
+	return c.GetString(requestIDContextKey)
+}
+
+// validRequestID accepts IDs made of characters that are safe to log and echo
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"profitify-backend/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "generates when missing", incoming: ""},
+		{name: "keeps client id", incoming: "client-7f3a:retry.1", keep: true},
+		{name: "replaces unsafe id", incoming: "abc\nforged=1"},
+		{name: "replaces oversized id", incoming: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(zapcore.InfoLevel)
+
+			r := gin.New()
+			r.Use(RequestID(zap.New(core).Sugar()))
+			var fromContext string
+			r.GET("/", func(c *gin.Context) {
+				fromContext = RequestIDFromContext(c)
+				logger.FromContext(c.Request.Context(), zap.NewNop().Sugar()).Info("handled")
+				c.Status(http.StatusNoContent)
+			})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/", nil)
+			if tt.incoming != "" {
+				req.Header.Set(RequestIDHeader, tt.incoming)
+			}
+			r.ServeHTTP(w, req)
+
+			id := w.Header().Get(RequestIDHeader)
+			assert.NotEmpty(t, id)
+			assert.Equal(t, id, fromContext)
+			if tt.keep {
+				assert.Equal(t, tt.incoming, id)
+			} else {
+				assert.NotEqual(t, tt.incoming, id)
+				assert.Len(t, id, 32)
+			}
+
+			entries := logs.FilterMessage("handled").All()
+			if assert.Len(t, entries, 1) {
+				assert.Equal(t, id, entries[0].ContextMap()["request_id"])
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bearerPrefix introduces the session or user token in the Authorization header
+const bearerPrefix = "Bearer "
+
+// SessionAuthenticator resolves a session token to the API key the session
+// was opened with, and the session's ID
+type SessionAuthenticator interface {
+	AuthenticateSession(ctx context.Context, token string, client service.SessionClient) (*models.APIKey, string, error)
+}
+
+// SessionAuth authenticates requests carrying an `Authorization: Bearer`
+// session token as the key the session was opened with, and passes requests
+// without one on to APIKeyAuth. Unknown, expired and revoked sessions are
+// rejected with 401.
+func SessionAuth(sessions SessionAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := APIKeyFromContext(c); ok {
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		record, id, err := sessions.AuthenticateSession(c.Request.Context(), token, service.SessionClient{
+			IP:        c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidSession) {
+				problem.Abort(c, problem.Unauthenticated, "Invalid session")
+				return
+			}
+			_ = c.Error(err)
+			problem.Abort(c, problem.Internal, "Failed to authenticate request")
+			return
+		}
+
+		c.Request = c.Request.WithContext(service.WithSession(authenticated(c, record, "session_id", id), id))
+		c.Next()
+	}
+}
+
+// bearerToken returns the token of an `Authorization: Bearer` header
+func bearerToken(c *gin.Context) (string, bool) {
+	header := c.GetHeader("Authorization")
+	if len(header) < len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(bearerPrefix):]), true
+}
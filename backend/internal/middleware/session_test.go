@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSessions accepts the session token "phone-token" of key "u"
+type fakeSessions struct{}
+
+func (fakeSessions) AuthenticateSession(ctx context.Context, token string, client service.SessionClient) (*models.APIKey, string, error) {
+	switch token {
+	case "phone-token":
+		return &models.APIKey{ID: "u", Name: "session user"}, "phone", nil
+	case "broken":
+		return nil, "", errors.New("table unavailable")
+	}
+	return nil, "", service.ErrInvalidSession
+}
+
+func TestSessionAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	api := engine.Group("/api", SessionAuth(fakeSessions{}), APIKeyAuth(fakeAuthenticator{"user-key": {ID: "u", Name: "user"}}))
+	api.GET("/watchlists", func(c *gin.Context) {
+		key, _ := APIKeyFromContext(c)
+		session, _ := service.SessionFromContext(c.Request.Context())
+		c.String(http.StatusOK, key.Name+" "+session)
+	})
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "session token",
+			headers:        map[string]string{"Authorization": "Bearer phone-token"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "session user phone",
+		},
+		{
+			name:           "scheme is case insensitive",
+			headers:        map[string]string{"Authorization": "bearer phone-token"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "session user phone",
+		},
+		{
+			name:           "revoked session",
+			headers:        map[string]string{"Authorization": "Bearer revoked"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "session store failure",
+			headers:        map[string]string{"Authorization": "Bearer broken"},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:           "requests without a session fall back to the API key",
+			headers:        map[string]string{APIKeyHeader: "user-key"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "user ",
+		},
+		{
+			name:           "other schemes fall back to the API key",
+			headers:        map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/watchlists", nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
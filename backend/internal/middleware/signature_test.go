@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeVerifier accepts requests signed "valid" for the body it expects
+type fakeVerifier struct {
+	body string
+}
+
+func (f fakeVerifier) Verify(ctx context.Context, r service.SignedRequest) (*models.APIKey, error) {
+	if r.Signature != "valid" || string(r.Body) != f.body || r.Target != "/api/portfolios?x=1" {
+		return nil, service.ErrInvalidSignature
+	}
+	if r.Nonce == "used" {
+		return nil, service.ErrReplayedRequest
+	}
+	return &models.APIKey{ID: r.KeyID, Name: "partner"}, nil
+}
+
+func TestSignedRequestAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	api := engine.Group("/api", SignedRequestAuth(fakeVerifier{body: `{"name":"Core"}`}), APIKeyAuth(fakeAuthenticator{"user-key": {ID: "u", Name: "user"}}))
+	api.POST("/portfolios", func(c *gin.Context) {
+		key, _ := APIKeyFromContext(c)
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, key.Name+" "+string(body))
+	})
+
+	tests := []struct {
+		name           string
+		headers        map[string]string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "signed request",
+			headers:        map[string]string{KeyIDHeader: "p", NonceHeader: "fresh", SignatureHeader: "valid"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `partner {"name":"Core"}`,
+		},
+		{
+			name:           "forged signature",
+			headers:        map[string]string{KeyIDHeader: "p", NonceHeader: "fresh", SignatureHeader: "forged"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "replayed request",
+			headers:        map[string]string{KeyIDHeader: "p", NonceHeader: "used", SignatureHeader: "valid"},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "unsigned requests fall back to the API key",
+			headers:        map[string]string{APIKeyHeader: "user-key"},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `user {"name":"Core"}`,
+		},
+		{
+			name:           "unsigned requests without a key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/portfolios?x=1", strings.NewReader(`{"name":"Core"}`))
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String(), "the body is still readable after verification")
+			}
+		})
+	}
+}
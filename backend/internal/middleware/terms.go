@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// termsVersionContextKey is where CurrentTerms stores the terms version on the
+// gin context
+const termsVersionContextKey = "termsVersion"
+
+// CurrentTerms makes version the terms version RequireAcceptedTerms enforces
+// on the routes below it. An empty version enforces none.
+func CurrentTerms(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(termsVersionContextKey, version)
+		c.Next()
+	}
+}
+
+// RequireAcceptedTerms rejects requests whose API key's holder has not
+// accepted the version of the terms set by CurrentTerms, guarding the account
+// routes. Requests without a key, which reach routes only when API keys are
+// not required, pass.
+func RequireAcceptedTerms() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		version := c.GetString(termsVersionContextKey)
+		if version == "" {
+			c.Next()
+			return
+		}
+		if key, ok := APIKeyFromContext(c); ok {
+			if _, accepted := key.AcceptedTerms(version); !accepted {
+				problem.Abort(c, problem.TermsNotAccepted, "Terms version "+version+" must be accepted at /api/v1/account/terms")
+				return
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAcceptedTerms(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	auth := fakeAuthenticator{
+		"new-key":      {ID: "n", Name: "new"},
+		"outdated-key": {ID: "o", Name: "outdated", Terms: []models.TermsAcceptance{{Version: "2026-01", AcceptedUTC: 1}}},
+		"current-key": {ID: "c", Name: "current", Terms: []models.TermsAcceptance{
+			{Version: "2026-01", AcceptedUTC: 1},
+			{Version: "2026-06", AcceptedUTC: 2},
+		}},
+	}
+
+	newEngine := func(version string) *gin.Engine {
+		engine := gin.New()
+		api := engine.Group("/api", CurrentTerms(version), APIKeyAuth(auth))
+		api.GET("/watchlists", RequireAcceptedTerms(), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return engine
+	}
+
+	tests := []struct {
+		name           string
+		version        string
+		key            string
+		expectedStatus int
+	}{
+		{name: "never accepted", version: "2026-06", key: "new-key", expectedStatus: http.StatusForbidden},
+		{name: "accepted an earlier version", version: "2026-06", key: "outdated-key", expectedStatus: http.StatusForbidden},
+		{name: "accepted the current version", version: "2026-06", key: "current-key", expectedStatus: http.StatusOK},
+		{name: "no version enforced", version: "", key: "new-key", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/watchlists", nil)
+			req.Header.Set(APIKeyHeader, tt.key)
+			newEngine(tt.version).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusForbidden {
+				assert.Contains(t, w.Body.String(), `"code":"TERMS_NOT_ACCEPTED"`)
+			}
+		})
+	}
+}
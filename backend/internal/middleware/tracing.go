@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing starts a server span for each request, continuing the caller's trace
+// when it sends a traceparent header. The services and DynamoDB calls the
+// handler makes are traced as its children, and the request's logger carries
+// the trace ID as trace_id.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracing.StartKind(ctx, c.Request.Method+" "+route, trace.SpanKindServer,
+			semconv.HTTPRequestMethodKey.String(c.Request.Method),
+			semconv.HTTPRoute(route),
+		)
+		defer span.End()
+
+		if spanContext := span.SpanContext(); spanContext.IsSampled() {
+			log := logger.FromContext(ctx, logger.Get()).With("trace_id", spanContext.TraceID().String())
+			ctx = logger.NewContext(ctx, log)
+		}
+		if id := RequestIDFromContext(c); id != "" {
+			span.SetAttributes(attribute.String("http.request.header.x-request-id", id))
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", status))
+		}
+	}
+}
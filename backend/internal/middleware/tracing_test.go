@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// recordSpans installs a tracer provider recording every span for the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+		_ = provider.Shutdown(context.Background())
+	})
+	return recorder
+}
+
+func TestTracing(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := recordSpans(t)
+	core, logs := observer.New(zapcore.InfoLevel)
+
+	r := gin.New()
+	r.Use(RequestID(zap.New(core).Sugar()))
+	r.Use(Tracing())
+	r.GET("/api/tickers/:symbol", func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "TickerService.GetTicker")
+		span.End()
+		logger.FromContext(c.Request.Context(), nil).Info("handled")
+		c.Status(http.StatusBadGateway)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/tickers/AAPL", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+
+	assert.Equal(t, "GET /api/tickers/:symbol", server.Name())
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, traceID, server.SpanContext().TraceID().String(), "continues the caller's trace")
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent().SpanID().String())
+	assert.Equal(t, codes.Error, server.Status().Code)
+	assert.Contains(t, server.Attributes(), semconv.HTTPResponseStatusCode(http.StatusBadGateway))
+
+	assert.Equal(t, server.SpanContext().SpanID(), child.Parent().SpanID(), "handler spans are children of the request's")
+
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, traceID, logs.All()[0].ContextMap()["trace_id"])
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserAuthenticator resolves a user's JWT to the API key the user
+// authenticates as, and the user's ID
+type UserAuthenticator interface {
+	AuthenticateUser(ctx context.Context, token string) (*models.APIKey, string, error)
+}
+
+// UserAuth authenticates requests carrying an `Authorization: Bearer` JWT as
+// the user's API key, so the watchlists, portfolios and alerts a user creates
+// are owned by their key like any other. Bearer tokens that are not JWTs,
+// such as session tokens, are passed on to SessionAuth. Invalid and expired
+// tokens are rejected with 401.
+func UserAuth(users UserAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := APIKeyFromContext(c); ok {
+			c.Next()
+			return
+		}
+
+		token, ok := bearerToken(c)
+		if !ok || strings.Count(token, ".") != 2 {
+			c.Next()
+			return
+		}
+
+		record, id, err := users.AuthenticateUser(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, service.ErrInvalidUserToken) {
+				problem.Abort(c, problem.Unauthenticated, "Invalid access token")
+				return
+			}
+			_ = c.Error(err)
+			problem.Abort(c, problem.Internal, "Failed to authenticate request")
+			return
+		}
+
+		c.Request = c.Request.WithContext(service.WithUser(authenticated(c, record, "user_id", id), id))
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeUsers accepts the JWT "a.b.c" of user "ada", who holds key "u"
+type fakeUsers struct{}
+
+func (fakeUsers) AuthenticateUser(ctx context.Context, token string) (*models.APIKey, string, error) {
+	switch token {
+	case "a.b.c":
+		return &models.APIKey{ID: "u", Name: "ada's key"}, "ada", nil
+	case "broken.jwt.store":
+		return nil, "", errors.New("table unavailable")
+	}
+	return nil, "", service.ErrInvalidUserToken
+}
+
+func TestUserAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	api := engine.Group("/api", UserAuth(fakeUsers{}), SessionAuth(fakeSessions{}), APIKeyAuth(fakeAuthenticator{}))
+	api.GET("/watchlists", func(c *gin.Context) {
+		key, _ := APIKeyFromContext(c)
+		user, _ := service.UserFromContext(c.Request.Context())
+		c.String(http.StatusOK, key.Name+" "+user)
+	})
+
+	tests := []struct {
+		name           string
+		token          string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "user token", token: "a.b.c", expectedStatus: http.StatusOK, expectedBody: "ada's key ada"},
+		{name: "expired user token", token: "x.y.z", expectedStatus: http.StatusUnauthorized},
+		{name: "user store failure", token: "broken.jwt.store", expectedStatus: http.StatusInternalServerError},
+		{name: "session tokens are left to the sessions", token: "phone-token", expectedStatus: http.StatusOK, expectedBody: "session user "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/watchlists", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			w := httptest.NewRecorder()
+			engine.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			}
+		})
+	}
+}
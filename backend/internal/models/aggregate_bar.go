@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+)
+
+// Resolution is the period daily summaries are resampled to
+type Resolution string
+
+const (
+	// ResolutionWeek aggregates the sessions of a week starting on Monday
+	ResolutionWeek Resolution = "week"
+	// ResolutionMonth aggregates the sessions of a calendar month
+	ResolutionMonth Resolution = "month"
+)
+
+// Valid reports whether r is a supported resolution
+func (r Resolution) Valid() bool {
+	return r == ResolutionWeek || r == ResolutionMonth
+}
+
+// PeriodStart returns midnight UTC of the first day of the period the trading
+// date of timestamp falls in
+func (r Resolution) PeriodStart(timestamp int64) time.Time {
+	y, m, d := time.Unix(timestamp, 0).UTC().Date()
+	if r == ResolutionMonth {
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+	}
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	// Weeks start on Monday; Go's weekdays start on Sunday
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+// NextPeriodStart returns the start of the period after the one starting at start
+func (r Resolution) NextPeriodStart(start time.Time) time.Time {
+	if r == ResolutionMonth {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 7)
+}
+
+// AggregateBar is the OHLCV of the sessions of one week or month
+type AggregateBar struct {
+	// Timestamp is midnight UTC of the period's first day, and Date that day
+	Timestamp int64   `json:"timestamp" dynamodbav:"timestamp"`
+	Date      string  `json:"date" dynamodbav:"date"`
+	Open      float32 `json:"open" dynamodbav:"open"`
+	High      float32 `json:"high" dynamodbav:"high"`
+	Low       float32 `json:"low" dynamodbav:"low"`
+	Close     float32 `json:"close" dynamodbav:"close"`
+	Volume    float64 `json:"volume" dynamodbav:"volume"`
+	// Sessions is how many daily summaries the bar aggregates; the first and
+	// last periods of a range may be partial
+	Sessions int `json:"sessions" dynamodbav:"sessions"`
+}
+
+// Add folds the next session of the period into the bar: the first session
+// opens it, later ones extend its range and close it
+func (b *AggregateBar) Add(summary DailySummary) {
+	if b.Sessions == 0 {
+		b.Open, b.High, b.Low = summary.Open, summary.High, summary.Low
+	}
+	b.High = max(b.High, summary.High)
+	b.Low = min(b.Low, summary.Low)
+	b.Close = summary.Close
+	b.Volume += float64(summary.Volume)
+	b.Sessions++
+}
+
+// AppendSession folds the next daily summary into bars, oldest first,
+// starting a new bar when the summary opens a new period
+func AppendSession(bars []AggregateBar, resolution Resolution, summary DailySummary) []AggregateBar {
+	start := resolution.PeriodStart(summary.Timestamp)
+	if n := len(bars); n == 0 || bars[n-1].Timestamp != start.Unix() {
+		bars = append(bars, AggregateBar{Timestamp: start.Unix(), Date: start.Format(DateLayout)})
+	}
+	bars[len(bars)-1].Add(summary)
+	return bars
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"strings"
+)
+
+// UsageDimension is what request analytics are aggregated by
+type UsageDimension string
+
+const (
+	// UsageByEndpoint counts requests by method and route template
+	UsageByEndpoint UsageDimension = "endpoint"
+	// UsageByKey counts requests by API key ID; unauthenticated requests are
+	// counted under UsageAnonymousKey
+	UsageByKey UsageDimension = "key"
+	// UsageBySymbol counts requests to routes with a ticker symbol, by symbol
+	UsageBySymbol UsageDimension = "symbol"
+)
+
+// UsageAnonymousKey is the key dimension value of requests without an API key
+const UsageAnonymousKey = "anonymous"
+
+// usageMetricSeparator joins a dimension and its value in a metric name
+const usageMetricSeparator = "#"
+
+// Valid reports whether d is a known dimension
+func (d UsageDimension) Valid() bool {
+	switch d {
+	case UsageByEndpoint, UsageByKey, UsageBySymbol:
+		return true
+	}
+	return false
+}
+
+// UsageCounter is the number of requests counted for one metric on one UTC
+// day. Counters are keyed by date and metric, e.g. "symbol#AAPL".
+type UsageCounter struct {
+	Date   string `json:"date" dynamodbav:"date"`
+	Metric string `json:"metric" dynamodbav:"metric"`
+	Count  int64  `json:"count" dynamodbav:"count"`
+}
+
+// UsageMetric returns the metric name of a dimension value
+func UsageMetric(dimension UsageDimension, value string) string {
+	return UsageMetricPrefix(dimension) + value
+}
+
+// UsageMetricPrefix returns the prefix shared by every metric of a dimension
+func UsageMetricPrefix(dimension UsageDimension) string {
+	return string(dimension) + usageMetricSeparator
+}
+
+// Value returns the dimension value the counter's metric was recorded for
+func (u *UsageCounter) Value() string {
+	_, value, _ := strings.Cut(u.Metric, usageMetricSeparator)
+	return value
+}
+
+// UsageTotal is the request count of a dimension value over a date range
+type UsageTotal struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// DailyUsage is the number of requests counted on a UTC day
+type DailyUsage struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+// UsageReport ranks the values of a dimension by request count over a date
+// range, together with the total number of requests per day
+type UsageReport struct {
+	Dimension UsageDimension `json:"dimension"`
+	From      string         `json:"from"`
+	To        string         `json:"to"`
+	Totals    []UsageTotal   `json:"totals"`
+	Daily     []DailyUsage   `json:"daily"`
+}
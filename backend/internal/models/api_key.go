@@ -0,0 +1,150 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+)
+
+// APIKeyScope grants a key access to a group of routes
+type APIKeyScope string
+
+const (
+	// ScopeReadMarket reads tickers, daily bars, quotes, indicators and market overviews
+	ScopeReadMarket APIKeyScope = "read:market"
+	// ScopeWritePortfolio manages portfolios, custom assets and the net worth built from them
+	ScopeWritePortfolio APIKeyScope = "write:portfolio"
+	// ScopeAdmin calls the admin API; only admin keys may hold it
+	ScopeAdmin APIKeyScope = "admin"
+)
+
+// APIKeyScopes lists the valid scopes
+var APIKeyScopes = []APIKeyScope{ScopeReadMarket, ScopeWritePortfolio, ScopeAdmin}
+
+// APIKey is a credential accepted in the X-API-Key header. Only the SHA-256
+// of the key is stored; it doubles as the key's ID.
+type APIKey struct {
+	ID          string `json:"id" dynamodbav:"id"`
+	Name        string `json:"name" dynamodbav:"name"`
+	Admin       bool   `json:"admin" dynamodbav:"admin"`
+	CreatedUTC  int64  `json:"createdUTC" dynamodbav:"createdUTC"`
+	RevokedUTC  int64  `json:"revokedUTC,omitempty" dynamodbav:"revokedUTC,omitempty"`
+	LastUsedUTC int64  `json:"lastUsedUTC,omitempty" dynamodbav:"lastUsedUTC,omitempty"`
+	// DeletedUTC is when the key's holder deleted their account. The key no
+	// longer authenticates and its data is quarantined until the account is
+	// restored or PurgedUTC, when the data was deleted for good.
+	DeletedUTC int64 `json:"deletedUTC,omitempty" dynamodbav:"deletedUTC,omitempty"`
+	PurgedUTC  int64 `json:"purgedUTC,omitempty" dynamodbav:"purgedUTC,omitempty"`
+	// Tier is the key's plan; admin keys are not limited by it
+	Tier PlanTier `json:"tier" dynamodbav:"tier,omitempty"`
+	// Scopes restrict the key to the routes of those scopes; a key without
+	// scopes has full access
+	Scopes []APIKeyScope `json:"scopes,omitempty" dynamodbav:"scopes,omitempty"`
+	// SigningSecret verifies the HMAC signatures of requests signed by the key
+	// holder instead of sending the key; empty until a secret is issued
+	SigningSecret string `json:"-" dynamodbav:"signingSecret,omitempty"`
+	// Terms are the versions of the terms of service and privacy policy the
+	// key's holder accepted, oldest first
+	Terms []TermsAcceptance `json:"terms,omitempty" dynamodbav:"terms,omitempty"`
+}
+
+// TermsAcceptance records when a version of the terms was accepted
+type TermsAcceptance struct {
+	Version     string `json:"version" dynamodbav:"version"`
+	AcceptedUTC int64  `json:"acceptedUTC" dynamodbav:"acceptedUTC"`
+}
+
+// IssuedAPIKey is a newly created key together with its plaintext, which is
+// only ever returned once
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+// CanSign reports whether requests may be signed with the key's signing secret
+func (k *APIKey) CanSign() bool {
+	return k.SigningSecret != ""
+}
+
+// Revoked reports whether the key has been revoked
+func (k *APIKey) Revoked() bool {
+	return k.RevokedUTC != 0
+}
+
+// AccountState is the stage of a key's account in its deletion
+type AccountState string
+
+const (
+	// AccountActive accounts authenticate
+	AccountActive AccountState = "active"
+	// AccountDeleted accounts are quarantined: restorable until purged
+	AccountDeleted AccountState = "deleted"
+	// AccountPurged accounts had their data deleted for good
+	AccountPurged AccountState = "purged"
+)
+
+// State returns the stage of the key's account in its deletion
+func (k *APIKey) State() AccountState {
+	switch {
+	case k.PurgedUTC != 0:
+		return AccountPurged
+	case k.DeletedUTC != 0:
+		return AccountDeleted
+	default:
+		return AccountActive
+	}
+}
+
+// Disabled reports whether the key no longer authenticates, because it was
+// revoked or its account deleted
+func (k *APIKey) Disabled() bool {
+	return k.Revoked() || k.State() != AccountActive
+}
+
+// HasScope reports whether the key may call routes of scope
+func (k *APIKey) HasScope(scope APIKeyScope) bool {
+	return len(k.Scopes) == 0 || slices.Contains(k.Scopes, scope)
+}
+
+// AcceptedTerms returns when the key's holder accepted version of the terms,
+// if they did
+func (k *APIKey) AcceptedTerms(version string) (TermsAcceptance, bool) {
+	for _, acceptance := range k.Terms {
+		if acceptance.Version == version {
+			return acceptance, true
+		}
+	}
+	return TermsAcceptance{}, false
+}
+
+// Plan returns the plan the key is on
+func (k *APIKey) Plan() Plan {
+	return PlanFor(k.Tier)
+}
+
+// Validate checks if the API key is valid
+func (k *APIKey) Validate() error {
+	if k.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+
+	if k.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if k.Tier != "" {
+		if err := k.Tier.Validate(); err != nil {
+			return err
+		}
+	}
+
+	for _, scope := range k.Scopes {
+		if !slices.Contains(APIKeyScopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
+		if scope == ScopeAdmin && !k.Admin {
+			return fmt.Errorf("only admin keys may have the %s scope", ScopeAdmin)
+		}
+	}
+
+	return nil
+}
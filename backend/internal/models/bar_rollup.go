@@ -0,0 +1,56 @@
+package models
+
+import (
+	"strings"
+)
+
+// Sources a bar query plan reads from
+const (
+	BarSourceCache = "cache"
+	BarSourceDaily = "daily"
+)
+
+// BarRollup is the stored weekly or monthly bar of a ticker's closed period.
+// Ticker and Resolution are stored as its series.
+type BarRollup struct {
+	Ticker     string     `json:"ticker" dynamodbav:"-"`
+	Resolution Resolution `json:"resolution" dynamodbav:"-"`
+	AggregateBar
+}
+
+// RollupSeries keys the rollups of a ticker at a resolution, e.g. "AAPL#week"
+func RollupSeries(ticker string, resolution Resolution) string {
+	return ticker + "#" + string(resolution)
+}
+
+// RollupCoverage is the contiguous run of periods, by the dates they start,
+// rolled up for every active ticker
+type RollupCoverage struct {
+	From    string `json:"from"`
+	Through string `json:"through"`
+}
+
+// BarSegment is a date range of a bar query and the source it is read from
+type BarSegment struct {
+	Source string
+	From   string
+	To     string
+}
+
+// BarQueryPlan lists the segments a bar query was answered from, oldest first
+type BarQueryPlan []BarSegment
+
+// String renders the plan for the X-Query-Plan header, e.g.
+// "daily 2024-01-03..2024-01-07; rollup-week 2024-01-08..2024-12-29"
+func (p BarQueryPlan) String() string {
+	parts := make([]string, len(p))
+	for i, segment := range p {
+		parts[i] = segment.Source + " " + segment.From + ".." + segment.To
+	}
+	return strings.Join(parts, "; ")
+}
+
+// RollupSource names the rollup table's bars of a resolution in a plan
+func RollupSource(resolution Resolution) string {
+	return "rollup-" + string(resolution)
+}
@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Corporate action kinds, the prefixes of their storage sort keys
+const (
+	CorporateActionSplit    = "split"
+	CorporateActionDividend = "dividend"
+)
+
+// Split is a change in a ticker's share count, effective from the session of
+// its execution date. A 4-for-1 split has SplitFrom 1 and SplitTo 4.
+type Split struct {
+	Ticker string `json:"ticker" dynamodbav:"ticker"`
+	ID     string `json:"-" dynamodbav:"id"`
+	// Timestamp is midnight UTC of the execution date
+	Timestamp int64   `json:"timestamp" dynamodbav:"timestamp"`
+	SplitFrom float64 `json:"splitFrom" dynamodbav:"splitFrom"`
+	SplitTo   float64 `json:"splitTo" dynamodbav:"splitTo"`
+}
+
+// Dividend is a cash distribution per share. Shares bought from the session of
+// the ex-dividend date on do not receive it.
+type Dividend struct {
+	Ticker string `json:"ticker" dynamodbav:"ticker"`
+	ID     string `json:"-" dynamodbav:"id"`
+	// Timestamp is midnight UTC of the ex-dividend date
+	Timestamp  int64   `json:"timestamp" dynamodbav:"timestamp"`
+	CashAmount float64 `json:"cashAmount" dynamodbav:"cashAmount"`
+	Currency   string  `json:"currency,omitempty" dynamodbav:"currency,omitempty"`
+	// PayTimestamp is midnight UTC of the pay date, when known
+	PayTimestamp int64 `json:"payTimestamp,omitempty" dynamodbav:"payTimestamp,omitempty"`
+	// Frequency is the number of payments a year, 0 for a special dividend
+	Frequency int `json:"frequency,omitempty" dynamodbav:"frequency,omitempty"`
+}
+
+// CorporateActionID derives the storage sort key of an action. Timestamps are
+// zero-padded so that the IDs of each kind sort chronologically.
+func CorporateActionID(kind string, timestamp int64) string {
+	return fmt.Sprintf("%s#%010d", kind, timestamp)
+}
+
+// Date returns the split's execution date as YYYY-MM-DD
+func (s *Split) Date() string {
+	return time.Unix(s.Timestamp, 0).UTC().Format(DateLayout)
+}
+
+// Validate checks if the split is valid
+func (s *Split) Validate() error {
+	if s.Ticker == "" {
+		return fmt.Errorf("ticker is required")
+	}
+
+	if s.Timestamp <= 0 {
+		return fmt.Errorf("timestamp must be positive")
+	}
+
+	if s.SplitFrom <= 0 || s.SplitTo <= 0 {
+		return fmt.Errorf("splitFrom and splitTo must be positive")
+	}
+
+	return nil
+}
+
+// Date returns the dividend's ex-dividend date as YYYY-MM-DD
+func (d *Dividend) Date() string {
+	return time.Unix(d.Timestamp, 0).UTC().Format(DateLayout)
+}
+
+// Validate checks if the dividend is valid
+func (d *Dividend) Validate() error {
+	if d.Ticker == "" {
+		return fmt.Errorf("ticker is required")
+	}
+
+	if d.Timestamp <= 0 {
+		return fmt.Errorf("timestamp must be positive")
+	}
+
+	if d.CashAmount <= 0 {
+		return fmt.Errorf("cashAmount must be positive")
+	}
+
+	if d.PayTimestamp != 0 && d.PayTimestamp < d.Timestamp {
+		return fmt.Errorf("payTimestamp must not be before the ex-dividend date")
+	}
+
+	return nil
+}
+
+// Adjustment scales the bars of the sessions before Timestamp, the effective
+// date of a corporate action
+type Adjustment struct {
+	Timestamp int64
+	// Price multiplies prices and Volume multiplies volumes
+	Price  float64
+	Volume float64
+}
+
+// SplitAdjustment restates earlier bars in post-split shares
+func SplitAdjustment(s Split) Adjustment {
+	return Adjustment{
+		Timestamp: s.Timestamp,
+		Price:     s.SplitFrom / s.SplitTo,
+		Volume:    s.SplitTo / s.SplitFrom,
+	}
+}
+
+// DividendAdjustment discounts earlier prices by the dividend's share of the
+// previous session's close, the close it was paid out of. A dividend at or
+// above that close, or without one, leaves prices as they are.
+func DividendAdjustment(d Dividend, previousClose float32) Adjustment {
+	adj := Adjustment{Timestamp: d.Timestamp, Price: 1, Volume: 1}
+	if previousClose > 0 && d.CashAmount < float64(previousClose) {
+		adj.Price = 1 - d.CashAmount/float64(previousClose)
+	}
+	return adj
+}
+
+// Adjustments restate bars as if every action among them had always been in
+// effect. Build them with NewAdjustments.
+type Adjustments struct {
+	// timestamps are ascending, and factors[i] combines the adjustments from
+	// i on
+	timestamps []int64
+	factors    []Adjustment
+}
+
+// NewAdjustments combines adjustments given in any order
+func NewAdjustments(adjustments []Adjustment) *Adjustments {
+	sorted := append([]Adjustment(nil), adjustments...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp < sorted[j].Timestamp })
+
+	a := &Adjustments{
+		timestamps: make([]int64, len(sorted)),
+		factors:    make([]Adjustment, len(sorted)),
+	}
+	price, volume := 1.0, 1.0
+	for i := len(sorted) - 1; i >= 0; i-- {
+		price *= sorted[i].Price
+		volume *= sorted[i].Volume
+		a.timestamps[i] = sorted[i].Timestamp
+		a.factors[i] = Adjustment{Timestamp: sorted[i].Timestamp, Price: price, Volume: volume}
+	}
+	return a
+}
+
+// Apply returns the summary scaled by the adjustments effective after its
+// session
+func (a *Adjustments) Apply(d DailySummary) DailySummary {
+	i := sort.Search(len(a.timestamps), func(i int) bool { return a.timestamps[i] > d.Timestamp })
+	if i == len(a.timestamps) {
+		return d
+	}
+
+	price, volume := a.factors[i].Price, a.factors[i].Volume
+	d.Open = float32(float64(d.Open) * price)
+	d.High = float32(float64(d.High) * price)
+	d.Low = float32(float64(d.Low) * price)
+	d.Close = float32(float64(d.Close) * price)
+	d.VWAP = float32(float64(d.VWAP) * price)
+	d.Volume = float32(float64(d.Volume) * volume)
+	return d
+}
@@ -2,8 +2,12 @@ package models
 
 import (
 	"fmt"
+	"time"
 )
 
+// DateLayout is the layout of trading dates derived from summary timestamps
+const DateLayout = "2006-01-02"
+
 // DailySummary represents daily aggregated stock data for a ticker
 type DailySummary struct {
 	Ticker           string  `json:"ticker" dynamodbav:"ticker"`
@@ -16,6 +20,26 @@ type DailySummary struct {
 	TransactionCount int32   `json:"transactionCount,omitempty" dynamodbav:"transactionCount,omitempty"`
 	OTC              bool    `json:"otc,omitempty" dynamodbav:"otc,omitempty"`
 	VWAP             float32 `json:"vwap,omitempty" dynamodbav:"vwap,omitempty"`
+	// Anomaly describes the improbable move ingestion flagged the summary
+	// for, as PriceAnomaly.String does; empty for ordinary sessions
+	Anomaly string `json:"anomaly,omitempty" dynamodbav:"anomaly,omitempty"`
+	// UpdatedUTC is when the summary was last written, its revision
+	UpdatedUTC int64 `json:"-" dynamodbav:"updatedUTC,omitempty"`
+}
+
+// Revision returns when the summary was last written. Summaries stored before
+// writes were stamped count as written the day after their session, the
+// earliest they could have been.
+func (d *DailySummary) Revision() time.Time {
+	if d.UpdatedUTC != 0 {
+		return time.Unix(d.UpdatedUTC, 0)
+	}
+	return time.Unix(d.Timestamp, 0).AddDate(0, 0, 1)
+}
+
+// Date returns the trading date of the summary as YYYY-MM-DD in UTC
+func (d *DailySummary) Date() string {
+	return time.Unix(d.Timestamp, 0).UTC().Format(DateLayout)
 }
 
 // Validate checks if the stock data is valid
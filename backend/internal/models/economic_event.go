@@ -0,0 +1,68 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Economic event types tracked by the calendar
+const (
+	EconomicEventFOMC        = "fomc"
+	EconomicEventCPI         = "cpi"
+	EconomicEventPPI         = "ppi"
+	EconomicEventJobs        = "jobs"
+	EconomicEventGDP         = "gdp"
+	EconomicEventPCE         = "pce"
+	EconomicEventRetailSales = "retail_sales"
+)
+
+// economicEventTypes is the set of accepted event types
+var economicEventTypes = map[string]bool{
+	EconomicEventFOMC:        true,
+	EconomicEventCPI:         true,
+	EconomicEventPPI:         true,
+	EconomicEventJobs:        true,
+	EconomicEventGDP:         true,
+	EconomicEventPCE:         true,
+	EconomicEventRetailSales: true,
+}
+
+// EconomicEvent is a scheduled macro release or meeting, keyed by country and
+// release time. Actual is empty until the figure has been published.
+type EconomicEvent struct {
+	Country   string   `json:"country" dynamodbav:"country"`
+	ID        string   `json:"id" dynamodbav:"id"`
+	Timestamp int64    `json:"timestamp" dynamodbav:"timestamp"`
+	Type      string   `json:"type" dynamodbav:"type"`
+	Title     string   `json:"title" dynamodbav:"title"`
+	Unit      string   `json:"unit,omitempty" dynamodbav:"unit,omitempty"`
+	Actual    *float64 `json:"actual,omitempty" dynamodbav:"actual,omitempty"`
+	Forecast  *float64 `json:"forecast,omitempty" dynamodbav:"forecast,omitempty"`
+	Previous  *float64 `json:"previous,omitempty" dynamodbav:"previous,omitempty"`
+}
+
+// EconomicEventID derives the storage sort key of an event. Timestamps are
+// zero-padded so that IDs sort chronologically.
+func EconomicEventID(timestamp int64, eventType string) string {
+	return fmt.Sprintf("%010d#%s", timestamp, eventType)
+}
+
+// Validate checks if the economic event is valid
+func (e *EconomicEvent) Validate() error {
+	if e.Country == "" {
+		return fmt.Errorf("country is required")
+	}
+
+	if !economicEventTypes[e.Type] {
+		return fmt.Errorf("unknown event type %q", e.Type)
+	}
+
+	if e.Timestamp <= 0 {
+		return fmt.Errorf("timestamp must be positive")
+	}
+
+	if e.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+
+	return nil
+}
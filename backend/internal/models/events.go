@@ -0,0 +1,212 @@
+package models
+
+import "embed"
+
+// Types of the domain events published to the event bus, and the version of
+// each type's payload schema. A payload change that is not backwards
+// compatible bumps its version; fields are only ever added within a version.
+const (
+	EventTickerUpdated               = "TickerUpdated"
+	EventTickerUpdatedVersion        = 1
+	EventDailySummaryIngested        = "DailySummaryIngested"
+	EventDailySummaryIngestedVersion = 1
+	EventAlertTriggered              = "AlertTriggered"
+	EventAlertTriggeredVersion       = 1
+	EventTransactionRecorded         = "PortfolioTransactionRecorded"
+	EventTransactionRecordedVersion  = 1
+	EventLoggedIn                    = "LoggedIn"
+	EventLoggedInVersion             = 1
+
+	// Stream events are high volume market data for streaming consumers
+	EventQuoteUpdated        = "QuoteUpdated"
+	EventQuoteUpdatedVersion = 1
+	EventBarClosed           = "BarClosed"
+	EventBarClosedVersion    = 1
+)
+
+//go:generate go run ../../cmd/schemagen --dir schemas
+
+// EventSchemaFiles holds the generated Avro (.avsc) and Protocol Buffers
+// (.proto) schemas of every published version of every event's payload,
+// named <Type>.v<Version>
+//
+//go:embed schemas
+var EventSchemaFiles embed.FS
+
+// Changes a TickerUpdatedEvent reports
+const (
+	TickerChangeCreated = "created"
+	TickerChangeUpdated = "updated"
+	TickerChangeDeleted = "deleted"
+)
+
+// Scopes of a DailySummaryIngestedEvent
+const (
+	IngestScopeMarket = "market"
+	IngestScopeTicker = "ticker"
+)
+
+// TickerUpdatedEvent is published when a ticker is created, updated or
+// deleted through the API
+type TickerUpdatedEvent struct {
+	Symbol string `json:"symbol"`
+	// Change is "created", "updated" or "deleted"
+	Change string `json:"change"`
+	// The ticker's fields after the change; empty once it is deleted
+	Name            string `json:"name,omitempty"`
+	Market          string `json:"market,omitempty"`
+	PrimaryExchange string `json:"primaryExchange,omitempty"`
+	Type            string `json:"type,omitempty"`
+	Active          bool   `json:"active"`
+}
+
+// DailySummaryIngestedEvent is published when daily summaries were stored:
+// a whole market's trading day by the end-of-day ingest, or one ticker's
+// range by an on-demand refresh
+type DailySummaryIngestedEvent struct {
+	// Scope is "market" or "ticker"
+	Scope string `json:"scope"`
+	// Symbol is the refreshed ticker of a ticker scoped ingest
+	Symbol string `json:"symbol,omitempty"`
+	// From and To are the inclusive range of trading dates, YYYY-MM-DD
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Stored int    `json:"stored"`
+	// JobID is the on-demand ingest job that stored the summaries
+	JobID string `json:"jobId,omitempty"`
+	// KeyID is the API key that queued the job
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// AlertTriggeredEvent is published when an alert fires on a close
+type AlertTriggeredEvent struct {
+	AlertID   string  `json:"alertId"`
+	KeyID     string  `json:"keyId,omitempty"`
+	Symbol    string  `json:"symbol"`
+	Condition string  `json:"condition"`
+	Threshold float64 `json:"threshold,omitempty"`
+	// SignalType is the scanner signal of a signal alert
+	SignalType     string  `json:"signalType,omitempty"`
+	TriggeredUTC   int64   `json:"triggeredUTC"`
+	TriggeredClose float64 `json:"triggeredClose"`
+}
+
+// TransactionRecordedEvent is published when a buy or sell is recorded in a
+// portfolio
+type TransactionRecordedEvent struct {
+	PortfolioID   string  `json:"portfolioId"`
+	TransactionID string  `json:"transactionId"`
+	KeyID         string  `json:"keyId,omitempty"`
+	Symbol        string  `json:"symbol"`
+	Type          string  `json:"type"`
+	Quantity      float64 `json:"quantity"`
+	Price         float64 `json:"price"`
+	Fee           float64 `json:"fee,omitempty"`
+	// Timestamp is when the transaction happened, which may be backdated
+	Timestamp  int64 `json:"timestamp"`
+	CreatedUTC int64 `json:"createdUTC"`
+}
+
+// How a LoggedInEvent's account logged in
+const (
+	LoginMethodPassword = "password"
+	LoginMethodSession  = "session"
+)
+
+// LoggedInEvent is published when an account logs in: a local user with their
+// password, or a client opening a session with the account's key
+type LoggedInEvent struct {
+	KeyID string `json:"keyId"`
+	// Method is "password" or "session"
+	Method string `json:"method"`
+	// UserID is the user who logged in with a password
+	UserID string `json:"userId,omitempty"`
+	// SessionID and SessionName are the session opened
+	SessionID   string `json:"sessionId,omitempty"`
+	SessionName string `json:"sessionName,omitempty"`
+	LoggedInUTC int64  `json:"loggedInUTC"`
+}
+
+// QuoteUpdatedEvent is streamed when a symbol's latest session changes
+type QuoteUpdatedEvent struct {
+	Symbol string `json:"symbol"`
+	// Date is the session's trading date, YYYY-MM-DD
+	Date          string  `json:"date"`
+	Close         float32 `json:"close"`
+	PreviousClose float32 `json:"previousClose"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"changePercent"`
+	Volume        float32 `json:"volume"`
+	TimestampUTC  int64   `json:"timestampUTC"`
+}
+
+// BarClosedEvent is streamed when a daily, weekly or monthly bar of a symbol
+// is complete
+type BarClosedEvent struct {
+	Symbol string `json:"symbol"`
+	// Resolution is "day", "week" or "month"
+	Resolution string `json:"resolution"`
+	AggregateBar
+}
+
+// EventSchema describes a published event type for its consumers. The
+// payload schema of every version is generated into schemas/ as Avro and
+// Protocol Buffers by go generate, which refuses incompatible changes to a
+// published version.
+type EventSchema struct {
+	Type        string `json:"type"`
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	// Stream events are for streaming consumers rather than the event bus
+	Stream bool `json:"stream,omitempty"`
+	// Payload is a zero value of the event's data, to document its schema
+	Payload any `json:"-"`
+}
+
+// EventCatalog lists the event types published, at their current versions
+var EventCatalog = []EventSchema{
+	{
+		Type:        EventTickerUpdated,
+		Version:     EventTickerUpdatedVersion,
+		Description: "A ticker was created, updated or deleted through the API.",
+		Payload:     TickerUpdatedEvent{},
+	},
+	{
+		Type:        EventDailySummaryIngested,
+		Version:     EventDailySummaryIngestedVersion,
+		Description: "Daily summaries were stored, for a whole trading day or one ticker's date range.",
+		Payload:     DailySummaryIngestedEvent{},
+	},
+	{
+		Type:        EventAlertTriggered,
+		Version:     EventAlertTriggeredVersion,
+		Description: "A price, change or signal alert fired on a close.",
+		Payload:     AlertTriggeredEvent{},
+	},
+	{
+		Type:        EventTransactionRecorded,
+		Version:     EventTransactionRecordedVersion,
+		Description: "A buy or sell was recorded in a portfolio.",
+		Payload:     TransactionRecordedEvent{},
+	},
+	{
+		Type:        EventLoggedIn,
+		Version:     EventLoggedInVersion,
+		Description: "An account logged in with a user's password, or opened a session for a client.",
+		Payload:     LoggedInEvent{},
+	},
+	{
+		Type:        EventQuoteUpdated,
+		Version:     EventQuoteUpdatedVersion,
+		Description: "A symbol's latest quote changed.",
+		Stream:      true,
+		Payload:     QuoteUpdatedEvent{},
+	},
+	{
+		Type:        EventBarClosed,
+		Version:     EventBarClosedVersion,
+		Description: "A daily, weekly or monthly bar of a symbol closed.",
+		Stream:      true,
+		Payload:     BarClosedEvent{},
+	},
+}
@@ -0,0 +1,50 @@
+package models
+
+// Heatmap windows and the number of sessions each spans
+const (
+	HeatmapWindowDay   = "1d"
+	HeatmapWindowWeek  = "1w"
+	HeatmapWindowMonth = "1m"
+)
+
+// HeatmapSessions maps each heatmap window to the sessions it looks back over
+var HeatmapSessions = map[string]int{
+	HeatmapWindowDay:   1,
+	HeatmapWindowWeek:  5,
+	HeatmapWindowMonth: 21,
+}
+
+// Heatmap is sector/industry performance over a window, shaped for treemap rendering.
+// Weights are shares of the parent node's dollar volume over the window.
+type Heatmap struct {
+	Window        string          `json:"window"`
+	AsOf          int64           `json:"asOf"`
+	GeneratedUTC  int64           `json:"generatedUTC"`
+	ChangePercent float64         `json:"changePercent"`
+	Sectors       []HeatmapSector `json:"sectors"`
+}
+
+// HeatmapSector is a sector node of the heatmap
+type HeatmapSector struct {
+	Sector        string            `json:"sector"`
+	ChangePercent float64           `json:"changePercent"`
+	Weight        float64           `json:"weight"`
+	Industries    []HeatmapIndustry `json:"industries"`
+}
+
+// HeatmapIndustry is an industry node within a sector
+type HeatmapIndustry struct {
+	Industry      string          `json:"industry"`
+	ChangePercent float64         `json:"changePercent"`
+	Weight        float64         `json:"weight"`
+	Tickers       []HeatmapTicker `json:"tickers"`
+}
+
+// HeatmapTicker is a leaf of the heatmap
+type HeatmapTicker struct {
+	Ticker        string  `json:"ticker"`
+	Name          string  `json:"name"`
+	Close         float32 `json:"close"`
+	ChangePercent float64 `json:"changePercent"`
+	Weight        float64 `json:"weight"`
+}
@@ -0,0 +1,26 @@
+package models
+
+// Ingest job statuses
+const (
+	IngestStatusQueued    = "queued"
+	IngestStatusRunning   = "running"
+	IngestStatusCompleted = "completed"
+	IngestStatusFailed    = "failed"
+)
+
+// IngestJob tracks an on-demand refresh of a ticker's daily summaries over an
+// inclusive date range. Stored counts the summaries written.
+type IngestJob struct {
+	ID           string `json:"id"`
+	Ticker       string `json:"ticker"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Status       string `json:"status"`
+	Stored       int    `json:"stored"`
+	Error        string `json:"error,omitempty"`
+	CreatedUTC   int64  `json:"createdUTC"`
+	StartedUTC   int64  `json:"startedUTC,omitempty"`
+	CompletedUTC int64  `json:"completedUTC,omitempty"`
+	// KeyID is the API key that queued the job
+	KeyID string `json:"keyId,omitempty"`
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"fmt"
+)
+
+// IntradayBar represents an intraday OHLCV bar for a ticker, keyed by the bar's start time
+type IntradayBar struct {
+	Ticker           string  `json:"ticker" dynamodbav:"ticker"`
+	Timestamp        int64   `json:"timestamp" dynamodbav:"timestamp"`
+	Open             float32 `json:"open" dynamodbav:"open"`
+	High             float32 `json:"high" dynamodbav:"high"`
+	Low              float32 `json:"low" dynamodbav:"low"`
+	Close            float32 `json:"close" dynamodbav:"close"`
+	Volume           float32 `json:"volume" dynamodbav:"volume"`
+	VWAP             float32 `json:"vwap,omitempty" dynamodbav:"vwap,omitempty"`
+	TransactionCount int32   `json:"transactionCount,omitempty" dynamodbav:"transactionCount,omitempty"`
+}
+
+// Resolutions intraday bars are served in
+const (
+	IntradayResolution1m  = "1m"
+	IntradayResolution5m  = "5m"
+	IntradayResolution15m = "15m"
+)
+
+// IntradayResolutions maps each resolution to the minutes its bars span
+var IntradayResolutions = map[string]int{
+	IntradayResolution1m:  1,
+	IntradayResolution5m:  5,
+	IntradayResolution15m: 15,
+}
+
+// IntradayBars are the bars of a ticker over one trading day, extended hours
+// included, at a resolution
+type IntradayBars struct {
+	Ticker     string        `json:"ticker"`
+	Date       string        `json:"date"`
+	Resolution string        `json:"resolution"`
+	Bars       []IntradayBar `json:"bars"`
+	Count      int           `json:"count"`
+}
+
+// VWAPPoint is the session and anchored VWAP at the close of an intraday bar
+type VWAPPoint struct {
+	Timestamp    int64   `json:"timestamp"`
+	Close        float32 `json:"close"`
+	SessionVWAP  float64 `json:"sessionVwap"`
+	AnchoredVWAP float64 `json:"anchoredVwap"`
+}
+
+// VWAPSeries is the VWAP series for a ticker from an anchor time
+type VWAPSeries struct {
+	Ticker string      `json:"ticker"`
+	Anchor int64       `json:"anchor"`
+	Points []VWAPPoint `json:"points"`
+}
+
+// Validate checks if the intraday bar is valid
+func (b *IntradayBar) Validate() error {
+	if b.Ticker == "" {
+		return fmt.Errorf("ticker is required")
+	}
+
+	if b.Timestamp <= 0 {
+		return fmt.Errorf("timestamp must be positive")
+	}
+
+	if b.High < b.Low {
+		return fmt.Errorf("high price cannot be less than low price")
+	}
+
+	if b.Open <= 0 || b.Close <= 0 || b.High <= 0 || b.Low <= 0 {
+		return fmt.Errorf("prices must be positive")
+	}
+
+	if b.Volume < 0 {
+		return fmt.Errorf("volume cannot be negative")
+	}
+
+	return nil
+}
+
+// TypicalPrice returns the bar's VWAP when known, otherwise (high+low+close)/3
+func (b *IntradayBar) TypicalPrice() float64 {
+	if b.VWAP > 0 {
+		return float64(b.VWAP)
+	}
+	return (float64(b.High) + float64(b.Low) + float64(b.Close)) / 3
+}
@@ -0,0 +1,19 @@
+package models
+
+// BreadthMarket is the partition under which US equity breadth is stored
+const BreadthMarket = "US"
+
+// MarketBreadth holds market-wide breadth indicators for a trading date
+type MarketBreadth struct {
+	Market             string  `json:"-" dynamodbav:"market"`
+	Date               string  `json:"date" dynamodbav:"date"`
+	Tickers            int     `json:"tickers" dynamodbav:"tickers"`
+	Advancers          int     `json:"advancers" dynamodbav:"advancers"`
+	Decliners          int     `json:"decliners" dynamodbav:"decliners"`
+	Unchanged          int     `json:"unchanged" dynamodbav:"unchanged"`
+	AboveSMA50Percent  float64 `json:"aboveSma50Percent" dynamodbav:"aboveSma50Percent"`
+	AboveSMA200Percent float64 `json:"aboveSma200Percent" dynamodbav:"aboveSma200Percent"`
+	NewHighs           int     `json:"newHighs" dynamodbav:"newHighs"`
+	NewLows            int     `json:"newLows" dynamodbav:"newLows"`
+	ComputedUTC        int64   `json:"computedUTC" dynamodbav:"computedUTC"`
+}
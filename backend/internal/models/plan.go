@@ -0,0 +1,72 @@
+package models
+
+import (
+	"fmt"
+)
+
+// PlanTier is the billing plan of an API key
+type PlanTier string
+
+const (
+	PlanFree PlanTier = "free"
+	PlanPro  PlanTier = "pro"
+)
+
+// QuotaMetricPrefix prefixes the request analytics metric counting a key's
+// requests against its daily quota
+const QuotaMetricPrefix = "quota#"
+
+// MaxWatchlistSymbols bounds the number of tickers in a watchlist on any plan
+const MaxWatchlistSymbols = 100
+
+// Plan holds the limits of a tier. A zero limit is unlimited.
+type Plan struct {
+	Tier PlanTier `json:"tier"`
+	// WatchlistSymbols bounds the symbols of each watchlist
+	WatchlistSymbols int `json:"watchlistSymbols"`
+	// HistoryDays bounds how far back daily history may be read
+	HistoryDays int `json:"historyDays"`
+	// Alerts bounds the active alerts created with a key
+	Alerts int `json:"alerts"`
+	// RequestsPerDay bounds the API requests of a key per UTC day
+	RequestsPerDay int `json:"requestsPerDay"`
+}
+
+// Plans lists the tiers from the cheapest up
+var Plans = []Plan{
+	{
+		Tier:             PlanFree,
+		WatchlistSymbols: 20,
+		HistoryDays:      365,
+		Alerts:           5,
+		RequestsPerDay:   1000,
+	},
+	{
+		Tier:             PlanPro,
+		WatchlistSymbols: MaxWatchlistSymbols,
+		HistoryDays:      0,
+		Alerts:           100,
+		RequestsPerDay:   50000,
+	},
+}
+
+// PlanFor returns the plan of tier. Keys created before tiers existed have no
+// tier and are on the free plan.
+func PlanFor(tier PlanTier) Plan {
+	for _, plan := range Plans {
+		if plan.Tier == tier {
+			return plan
+		}
+	}
+	return Plans[0]
+}
+
+// Validate checks if the tier is a known plan
+func (t PlanTier) Validate() error {
+	for _, plan := range Plans {
+		if plan.Tier == t {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown plan tier %q", t)
+}
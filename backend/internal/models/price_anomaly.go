@@ -0,0 +1,37 @@
+package models
+
+import "fmt"
+
+// PriceAnomaly describes a daily close whose move from the previous close is
+// improbable against the ticker's recent daily moves
+type PriceAnomaly struct {
+	// Return is the session's simple return from the previous close
+	Return float64 `json:"return" dynamodbav:"return"`
+	// ZScore is how many standard deviations Return lies from the mean of
+	// the recent returns
+	ZScore float64 `json:"zScore" dynamodbav:"zScore"`
+	// LowerFence and UpperFence bound the ordinary returns: the recent
+	// returns' quartiles widened by a multiple of their interquartile range
+	LowerFence float64 `json:"lowerFence" dynamodbav:"lowerFence"`
+	UpperFence float64 `json:"upperFence" dynamodbav:"upperFence"`
+	// Sessions is the number of recent returns the move was measured against
+	Sessions int `json:"sessions" dynamodbav:"sessions"`
+}
+
+func (a PriceAnomaly) String() string {
+	return fmt.Sprintf("return of %+.2f%% is %.1f standard deviations from the mean and outside [%+.2f%%, %+.2f%%] of the last %d sessions",
+		a.Return*100, a.ZScore, a.LowerFence*100, a.UpperFence*100, a.Sessions)
+}
+
+// HeldSummary is a daily summary flagged as anomalous that ingestion holds
+// back from the daily summaries until an admin approves or rejects it
+type HeldSummary struct {
+	Ticker string `json:"ticker" dynamodbav:"ticker"`
+	// Date is the trading date of the summary
+	Date    string       `json:"date" dynamodbav:"date"`
+	Summary DailySummary `json:"summary" dynamodbav:"summary"`
+	Anomaly PriceAnomaly `json:"anomaly" dynamodbav:"anomaly"`
+	// Source names the ingestion that held the summary, as a revision source
+	Source  string `json:"source" dynamodbav:"source"`
+	HeldUTC int64  `json:"heldUTC" dynamodbav:"heldUTC"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// Purge job statuses
+const (
+	PurgeStatusRunning   = "running"
+	PurgeStatusCompleted = "completed"
+	PurgeStatusFailed    = "failed"
+)
+
+// PurgeConfirmation is issued when a purge is requested and must be presented
+// to start it
+type PurgeConfirmation struct {
+	Ticker     string `json:"ticker"`
+	Token      string `json:"confirmationToken"`
+	ExpiresUTC int64  `json:"expiresUTC"`
+}
+
+// PurgeJob tracks the asynchronous deletion of a ticker's stored data.
+// Deleted counts the items removed so far per dataset.
+type PurgeJob struct {
+	ID           string         `json:"id"`
+	Ticker       string         `json:"ticker"`
+	Status       string         `json:"status"`
+	Deleted      map[string]int `json:"deleted"`
+	Error        string         `json:"error,omitempty"`
+	CreatedUTC   int64          `json:"createdUTC"`
+	CompletedUTC int64          `json:"completedUTC,omitempty"`
+}
@@ -0,0 +1,24 @@
+package models
+
+// Quote is the latest daily summary of a ticker with its change against the
+// prior trading day's close
+type Quote struct {
+	DailySummary
+	PreviousClose float32 `json:"previousClose"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"changePercent"`
+}
+
+// NewQuote builds a quote for latest given the close of the session before it.
+// A non-positive previous close leaves the change fields zero.
+func NewQuote(latest DailySummary, previousClose float32) Quote {
+	quote := Quote{
+		DailySummary:  latest,
+		PreviousClose: previousClose,
+	}
+	if previousClose > 0 {
+		quote.Change = float64(latest.Close) - float64(previousClose)
+		quote.ChangePercent = quote.Change / float64(previousClose) * 100
+	}
+	return quote
+}
@@ -0,0 +1,102 @@
+package models
+
+import (
+	"math"
+)
+
+// ReturnType is how the return of each session is measured
+type ReturnType string
+
+const (
+	// ReturnSimple is the change of each close from the previous one
+	ReturnSimple ReturnType = "simple"
+	// ReturnLog is the natural log of each close over the previous one
+	ReturnLog ReturnType = "log"
+	// ReturnCumulative is the change of each close from the first one
+	ReturnCumulative ReturnType = "cumulative"
+)
+
+// TradingDaysPerYear annualizes daily returns
+const TradingDaysPerYear = 252
+
+// Valid reports whether t is a supported return type
+func (t ReturnType) Valid() bool {
+	return t == ReturnSimple || t == ReturnLog || t == ReturnCumulative
+}
+
+// ReturnPoint is the return of the session starting at Timestamp
+type ReturnPoint struct {
+	Timestamp int64   `json:"timestamp"`
+	Date      string  `json:"date"`
+	Value     float64 `json:"value"`
+}
+
+// ReturnSummary sums up the returns of a range of sessions
+type ReturnSummary struct {
+	// TotalReturn is the change from the first close to the last
+	TotalReturn float64 `json:"totalReturn"`
+	// AnnualizedReturn compounds TotalReturn to TradingDaysPerYear sessions
+	AnnualizedReturn float64 `json:"annualizedReturn"`
+	// Volatility is the sample standard deviation of the daily log returns,
+	// annualized; it is unset with fewer than two returns
+	Volatility *float64 `json:"volatility,omitempty"`
+	// Sessions counts the daily returns, one less than the closes
+	Sessions   int     `json:"sessions"`
+	StartClose float32 `json:"startClose"`
+	EndClose   float32 `json:"endClose"`
+}
+
+// ComputeReturns measures the return of each session of bars, oldest first,
+// after the first, and sums them up. The summary is nil with fewer than two
+// bars, or when a close is not positive, since no return can be measured.
+func ComputeReturns(bars []DailySummary, t ReturnType) ([]ReturnPoint, *ReturnSummary) {
+	points := []ReturnPoint{}
+	if len(bars) < 2 {
+		return points, nil
+	}
+	for _, bar := range bars {
+		if bar.Close <= 0 {
+			return points, nil
+		}
+	}
+
+	first := float64(bars[0].Close)
+	logReturns := make([]float64, 0, len(bars)-1)
+	for i := 1; i < len(bars); i++ {
+		previous, current := float64(bars[i-1].Close), float64(bars[i].Close)
+		logReturn := math.Log(current / previous)
+		logReturns = append(logReturns, logReturn)
+
+		value := current/previous - 1
+		switch t {
+		case ReturnLog:
+			value = logReturn
+		case ReturnCumulative:
+			value = current/first - 1
+		}
+		points = append(points, ReturnPoint{Timestamp: bars[i].Timestamp, Date: bars[i].Date(), Value: value})
+	}
+
+	last := float64(bars[len(bars)-1].Close)
+	summary := &ReturnSummary{
+		TotalReturn:      last/first - 1,
+		AnnualizedReturn: math.Pow(last/first, float64(TradingDaysPerYear)/float64(len(logReturns))) - 1,
+		Sessions:         len(logReturns),
+		StartClose:       bars[0].Close,
+		EndClose:         bars[len(bars)-1].Close,
+	}
+	if len(logReturns) > 1 {
+		mean := 0.0
+		for _, r := range logReturns {
+			mean += r
+		}
+		mean /= float64(len(logReturns))
+		variance := 0.0
+		for _, r := range logReturns {
+			variance += (r - mean) * (r - mean)
+		}
+		volatility := math.Sqrt(variance/float64(len(logReturns)-1)) * math.Sqrt(TradingDaysPerYear)
+		summary.Volatility = &volatility
+	}
+	return points, summary
+}
@@ -0,0 +1,88 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SettingKeySchemaVersion holds the applied data schema version
+const SettingKeySchemaVersion = "schema:version"
+
+// Setting key namespaces
+const (
+	settingPrefixFlag       = "flag:"
+	settingPrefixCheckpoint = "checkpoint:"
+	settingPrefixWatermark  = "watermark:ingest:"
+	settingPrefixIngestJob  = "job:ingest:"
+	settingPrefixPurgeJob   = "job:purge:"
+	settingPrefixPurgeToken = "purge:confirm:"
+	settingPrefixRollup     = "rollup:coverage:"
+)
+
+// Setting is a small piece of application state stored by key. Version is
+// incremented on every write and enables compare-and-swap updates.
+type Setting struct {
+	Key        string `json:"key" dynamodbav:"key"`
+	Value      string `json:"value" dynamodbav:"value"`
+	Version    int64  `json:"version" dynamodbav:"version"`
+	UpdatedUTC int64  `json:"updatedUTC" dynamodbav:"updatedUTC"`
+}
+
+// Checkpoint is the stored progress of a long-running job: the last ticker or
+// date it completed, so a restarted job resumes after it instead of starting over
+type Checkpoint struct {
+	Ticker     string `json:"ticker,omitempty"`
+	Date       string `json:"date,omitempty"`
+	UpdatedUTC int64  `json:"updatedUTC"`
+}
+
+// FeatureFlagKey returns the setting key of a feature flag
+func FeatureFlagKey(name string) string {
+	return settingPrefixFlag + name
+}
+
+// CheckpointKey returns the setting key of a job's progress checkpoint
+func CheckpointKey(job string) string {
+	return settingPrefixCheckpoint + job
+}
+
+// IngestWatermarkKey returns the setting key of a ticker's last ingested timestamp
+func IngestWatermarkKey(ticker string) string {
+	return settingPrefixWatermark + ticker
+}
+
+// IngestJobKey returns the setting key of an on-demand ingest job; an empty
+// id gives the prefix of every ingest job
+func IngestJobKey(id string) string {
+	return settingPrefixIngestJob + id
+}
+
+// PurgeJobKey returns the setting key of a ticker purge job; an empty id gives
+// the prefix of every purge job
+func PurgeJobKey(id string) string {
+	return settingPrefixPurgeJob + id
+}
+
+// PurgeConfirmationKey returns the setting key of a pending purge confirmation
+// token; an empty token gives the prefix of every pending confirmation
+func PurgeConfirmationKey(token string) string {
+	return settingPrefixPurgeToken + token
+}
+
+// RollupCoverageKey returns the setting key of the periods rolled up at a resolution
+func RollupCoverageKey(resolution Resolution) string {
+	return settingPrefixRollup + string(resolution)
+}
+
+// ValidateSettingKey checks that a key is non-empty and free of whitespace
+func ValidateSettingKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("key is required")
+	}
+
+	if strings.ContainsAny(key, " \t\r\n") {
+		return fmt.Errorf("key must not contain whitespace")
+	}
+
+	return nil
+}
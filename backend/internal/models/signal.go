@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+)
+
+// Signal types flagged by the market scanner
+const (
+	SignalGapUp         = "gap_up"
+	SignalGapDown       = "gap_down"
+	SignalUnusualVolume = "unusual_volume"
+)
+
+// Signal is a market scanner hit for a ticker on a trading date
+type Signal struct {
+	Date          string  `json:"date" dynamodbav:"date"`
+	ID            string  `json:"-" dynamodbav:"id"`
+	Ticker        string  `json:"ticker" dynamodbav:"ticker"`
+	Type          string  `json:"type" dynamodbav:"type"`
+	Value         float64 `json:"value" dynamodbav:"value"`
+	Open          float32 `json:"open" dynamodbav:"open"`
+	Close         float32 `json:"close" dynamodbav:"close"`
+	PreviousClose float32 `json:"previousClose" dynamodbav:"previousClose"`
+	Volume        float32 `json:"volume" dynamodbav:"volume"`
+	AverageVolume float64 `json:"averageVolume" dynamodbav:"averageVolume"`
+	CreatedUTC    int64   `json:"createdUTC" dynamodbav:"createdUTC"`
+}
+
+// NewSignal creates a signal with its storage sort key derived from ticker and type
+func NewSignal(date, ticker, signalType string) Signal {
+	return Signal{
+		Date:   date,
+		ID:     fmt.Sprintf("%s#%s", ticker, signalType),
+		Ticker: ticker,
+		Type:   signalType,
+	}
+}
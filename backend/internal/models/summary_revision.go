@@ -0,0 +1,42 @@
+package models
+
+// SummaryRevision records a daily summary overwritten with different values,
+// such as a provider's correction of a session ingested the night before
+type SummaryRevision struct {
+	Ticker string `json:"ticker" dynamodbav:"ticker"`
+	// Date is the trading date of the revised summary
+	Date      string `json:"date" dynamodbav:"date"`
+	Timestamp int64  `json:"timestamp" dynamodbav:"timestamp"`
+	// Changed names the fields whose values differ between Old and New
+	Changed []string     `json:"changed" dynamodbav:"changed"`
+	Old     DailySummary `json:"old" dynamodbav:"old"`
+	New     DailySummary `json:"new" dynamodbav:"new"`
+	// Source names the writer of New, e.g. "daily-ingest" or
+	// "admin-ingest:<job id>"
+	Source     string `json:"source" dynamodbav:"source"`
+	RevisedUTC int64  `json:"revisedUTC" dynamodbav:"revisedUTC"`
+}
+
+// ChangedSummaryFields names the market data fields whose values differ
+// between old and new, in a fixed order
+func ChangedSummaryFields(old, new DailySummary) []string {
+	var changed []string
+	for _, field := range []struct {
+		name     string
+		old, new any
+	}{
+		{"open", old.Open, new.Open},
+		{"high", old.High, new.High},
+		{"low", old.Low, new.Low},
+		{"close", old.Close, new.Close},
+		{"volume", old.Volume, new.Volume},
+		{"vwap", old.VWAP, new.VWAP},
+		{"transactionCount", old.TransactionCount, new.TransactionCount},
+		{"otc", old.OTC, new.OTC},
+	} {
+		if field.old != field.new {
+			changed = append(changed, field.name)
+		}
+	}
+	return changed
+}
@@ -13,6 +13,8 @@ type Ticker struct {
 	PrimaryExchange string `json:"primaryExchange,omitempty" dynamodbav:"primaryExchange,omitempty"`
 	ShareClassFigi  string `json:"shareClassFigi,omitempty" dynamodbav:"shareClassFigi,omitempty"`
 	Type            string `json:"type,omitempty" dynamodbav:"type,omitempty"`
+	Sector          string `json:"sector,omitempty" dynamodbav:"sector,omitempty"`
+	Industry        string `json:"industry,omitempty" dynamodbav:"industry,omitempty"`
 	Active          int32  `json:"active,omitempty" dynamodbav:"active,omitempty"`
 	Cik             string `json:"cik,omitempty" dynamodbav:"cik,omitempty"`
 	CompositeFigi   string `json:"compositeFigi,omitempty" dynamodbav:"compositeFigi,omitempty"`
@@ -56,3 +58,31 @@ func (t *Ticker) Validate() error {
 
 	return nil
 }
+
+// TickerChange records a write of a ticker, so clients can sync the tickers
+// changed since they last did. Ticker is the written ticker, nil once deleted.
+type TickerChange struct {
+	// Seq orders the changes: the change's time in nanoseconds, zero padded,
+	// then the symbol
+	Seq        string  `json:"-" dynamodbav:"seq"`
+	Symbol     string  `json:"symbol" dynamodbav:"symbol"`
+	Change     string  `json:"change" dynamodbav:"change"`
+	Ticker     *Ticker `json:"ticker,omitempty" dynamodbav:"ticker,omitempty"`
+	ChangedUTC int64   `json:"changedUTC" dynamodbav:"changedUTC"`
+}
+
+// TickerSync is a page of the ticker changes since a sync cursor, applied to
+// a client's copy of the active tickers
+type TickerSync struct {
+	// Full reports that Tickers are all active tickers, replacing the client's
+	// copy, rather than the ones changed since the cursor
+	Full bool `json:"full"`
+	// Tickers were created or updated and are active
+	Tickers []Ticker `json:"tickers"`
+	// Removed are the symbols deleted or deactivated
+	Removed []string `json:"removed"`
+	// Cursor is passed as since to fetch the changes after this page
+	Cursor string `json:"cursor"`
+	// HasMore reports that more changes follow the cursor right away
+	HasMore bool `json:"hasMore"`
+}
@@ -0,0 +1,35 @@
+package models
+
+// TickerStats are statistics derived from a ticker's daily bars as of its
+// latest session. They are materialized by the ticker-stats post-close job,
+// recomputed on demand after a correction of the bars, and computed on the fly
+// when read before the job has caught up with the latest session.
+type TickerStats struct {
+	Ticker string `json:"ticker" dynamodbav:"ticker"`
+	// AsOf is the date of the latest session the stats include
+	AsOf string `json:"asOf" dynamodbav:"asOf"`
+	// High52Week and Low52Week span the sessions of the 52 weeks up to AsOf,
+	// Sessions of them
+	High52Week float32 `json:"high52Week" dynamodbav:"high52Week"`
+	Low52Week  float32 `json:"low52Week" dynamodbav:"low52Week"`
+	Sessions   int     `json:"sessions" dynamodbav:"sessions"`
+	// SMA50 and SMA200 average the last 50 and 200 closes; they are unset
+	// for tickers with fewer sessions
+	SMA50  *float64 `json:"sma50,omitempty" dynamodbav:"sma50,omitempty"`
+	SMA200 *float64 `json:"sma200,omitempty" dynamodbav:"sma200,omitempty"`
+	// AvgVolume30 and AvgVolume90 average the volume of the last 30 and 90
+	// sessions; they are unset for tickers with fewer sessions
+	AvgVolume30 *float64 `json:"avgVolume30,omitempty" dynamodbav:"avgVolume30,omitempty"`
+	AvgVolume90 *float64 `json:"avgVolume90,omitempty" dynamodbav:"avgVolume90,omitempty"`
+	// YTDReturn is the change from the last close of the year before AsOf's
+	// to the latest close; it is unset for tickers listed since
+	YTDReturn *float64 `json:"ytdReturn,omitempty" dynamodbav:"ytdReturn,omitempty"`
+	// Volatility30 is the sample standard deviation of the last 30 daily log
+	// returns, annualized; it is unset for tickers with fewer
+	Volatility30 *float64 `json:"volatility30,omitempty" dynamodbav:"volatility30,omitempty"`
+	// Beta measures the ticker's daily returns of the 52 weeks against those
+	// of Benchmark; it is unset without enough sessions in common
+	Beta        *float64 `json:"beta,omitempty" dynamodbav:"beta,omitempty"`
+	Benchmark   string   `json:"benchmark,omitempty" dynamodbav:"benchmark,omitempty"`
+	ComputedUTC int64    `json:"computedUTC" dynamodbav:"computedUTC"`
+}
@@ -0,0 +1,67 @@
+package portfolios
+
+import (
+	"fmt"
+)
+
+// CustomAsset represents a user-tracked asset without a market price feed,
+// such as real estate, vehicles or private holdings
+type CustomAsset struct {
+	ID                      string  `json:"id" dynamodbav:"id"`
+	Name                    string  `json:"name" dynamodbav:"name"`
+	Category                string  `json:"category,omitempty" dynamodbav:"category,omitempty"`
+	Currency                string  `json:"currency" dynamodbav:"currency"`
+	CurrentValue            float64 `json:"currentValue" dynamodbav:"currentValue"`
+	LastValuedUTC           int64   `json:"lastValuedUTC,omitempty" dynamodbav:"lastValuedUTC,omitempty"`
+	RevaluationIntervalDays int32   `json:"revaluationIntervalDays,omitempty" dynamodbav:"revaluationIntervalDays,omitempty"`
+	NextRevaluationUTC      int64   `json:"nextRevaluationUTC,omitempty" dynamodbav:"nextRevaluationUTC,omitempty"`
+	CreatedUTC              int64   `json:"createdUTC" dynamodbav:"createdUTC"`
+	// KeyID is the API key that created the asset, the only one it is visible to
+	KeyID string `json:"-" dynamodbav:"keyId,omitempty"`
+}
+
+// AssetValuation is a single manual valuation entry in a custom asset's history
+type AssetValuation struct {
+	AssetID   string  `json:"assetId" dynamodbav:"assetId"`
+	Timestamp int64   `json:"timestamp" dynamodbav:"timestamp"`
+	Value     float64 `json:"value" dynamodbav:"value"`
+	Note      string  `json:"note,omitempty" dynamodbav:"note,omitempty"`
+}
+
+// Validate checks if the custom asset data is valid
+func (a *CustomAsset) Validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("asset name is required")
+	}
+
+	if a.Currency == "" {
+		return fmt.Errorf("currency is required")
+	}
+
+	if a.CurrentValue < 0 {
+		return fmt.Errorf("current value cannot be negative")
+	}
+
+	if a.RevaluationIntervalDays < 0 {
+		return fmt.Errorf("revaluation interval cannot be negative")
+	}
+
+	return nil
+}
+
+// Validate checks if the valuation entry is valid
+func (v *AssetValuation) Validate() error {
+	if v.AssetID == "" {
+		return fmt.Errorf("asset id is required")
+	}
+
+	if v.Timestamp <= 0 {
+		return fmt.Errorf("timestamp must be positive")
+	}
+
+	if v.Value < 0 {
+		return fmt.Errorf("value cannot be negative")
+	}
+
+	return nil
+}
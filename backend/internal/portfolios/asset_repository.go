@@ -0,0 +1,247 @@
+package portfolios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CustomAssetRepository defines the interface for custom asset data operations
+type CustomAssetRepository interface {
+	GetAsset(ctx context.Context, id string) (*CustomAsset, error)
+	ListAssets(ctx context.Context) ([]CustomAsset, error)
+	PutAsset(ctx context.Context, asset *CustomAsset) error
+	PutValuation(ctx context.Context, valuation *AssetValuation) error
+	GetValuations(ctx context.Context, assetID string, from, to int64) ([]AssetValuation, error)
+}
+
+// customAssetRepository implements CustomAssetRepository using DynamoDB
+type customAssetRepository struct {
+	client          *dynamodb.Client
+	tableName       string
+	valuationsTable string
+}
+
+// NewCustomAssetRepository creates a new DynamoDB-backed custom asset repository
+func NewCustomAssetRepository(client *dynamodb.Client, tableName, valuationsTable string) CustomAssetRepository {
+	return &customAssetRepository{
+		client:          client,
+		tableName:       tableName,
+		valuationsTable: valuationsTable,
+	}
+}
+
+// GetAsset retrieves a single custom asset by ID
+func (r *customAssetRepository) GetAsset(ctx context.Context, id string) (*CustomAsset, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get asset %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAssetNotFound, id)
+	}
+
+	var asset CustomAsset
+	if err := attributevalue.UnmarshalMap(result.Item, &asset); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal asset: %w", err)
+	}
+
+	return &asset, nil
+}
+
+// ListAssets retrieves the custom assets of every key
+func (r *customAssetRepository) ListAssets(ctx context.Context) ([]CustomAsset, error) {
+	var assets []CustomAsset
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assets: %w", err)
+		}
+
+		var batch []CustomAsset
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal assets: %w", err)
+		}
+
+		assets = append(assets, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return assets, nil
+}
+
+// PutAsset creates or replaces a custom asset
+func (r *customAssetRepository) PutAsset(ctx context.Context, asset *CustomAsset) error {
+	item, err := attributevalue.MarshalMap(asset)
+	if err != nil {
+		return fmt.Errorf("failed to marshal asset: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put asset %s: %w", asset.ID, err)
+	}
+
+	return nil
+}
+
+// PutValuation stores a valuation entry, rejecting duplicates for the same timestamp
+func (r *customAssetRepository) PutValuation(ctx context.Context, valuation *AssetValuation) error {
+	item, err := attributevalue.MarshalMap(valuation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal valuation: %w", err)
+	}
+
+	cond := expression.AttributeNotExists(expression.Name("timestamp"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.valuationsTable),
+		Item:                     item,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: valuation already recorded at %d", ErrInvalidAsset, valuation.Timestamp)
+		}
+		return fmt.Errorf("failed to put valuation for asset %s: %w", valuation.AssetID, err)
+	}
+
+	return nil
+}
+
+// GetValuations retrieves the valuation history of an asset within [from, to], oldest first
+func (r *customAssetRepository) GetValuations(ctx context.Context, assetID string, from, to int64) ([]AssetValuation, error) {
+	keyCond := expression.Key("assetId").Equal(expression.Value(assetID)).
+		And(expression.Key("timestamp").Between(expression.Value(from), expression.Value(to)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var valuations []AssetValuation
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.valuationsTable),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query valuations for asset %s: %w", assetID, err)
+		}
+
+		var batch []AssetValuation
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal valuations: %w", err)
+		}
+
+		valuations = append(valuations, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return valuations, nil
+}
+
+// customAssetPurger deletes the custom assets an API key owns with their
+// valuation histories
+type customAssetPurger struct {
+	client          *dynamodb.Client
+	tableName       string
+	valuationsTable string
+}
+
+// NewCustomAssetPurger creates a purger of the custom assets API keys own
+func NewCustomAssetPurger(client *dynamodb.Client, tableName, valuationsTable string) service.AccountPurger {
+	return &customAssetPurger{
+		client:          client,
+		tableName:       tableName,
+		valuationsTable: valuationsTable,
+	}
+}
+
+// PurgeAccount deletes the valuations of every asset owned by keyID, then the
+// assets, so a failed purge is picked up again by the next one. It returns
+// how many items were deleted.
+func (p *customAssetPurger) PurgeAccount(ctx context.Context, keyID string) (int, error) {
+	ids, err := ownedIDs(ctx, p.client, p.tableName, keyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan assets: %w", err)
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		keyCond := expression.Key("assetId").Equal(expression.Value(id))
+		proj := expression.NamesList(expression.Name("assetId"), expression.Name("timestamp"))
+		expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithProjection(proj).Build()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to build expression: %w", err)
+		}
+
+		n, err := repository.QueryDelete(ctx, p.client, &dynamodb.QueryInput{
+			TableName:                 aws.String(p.valuationsTable),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ProjectionExpression:      expr.Projection(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}, nil)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete valuations of asset %s: %w", id, err)
+		}
+	}
+
+	n, err := repository.NewOwnedItemPurger(p.client, p.tableName).PurgeAccount(ctx, keyID)
+	return deleted + n, err
+}
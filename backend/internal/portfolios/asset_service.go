@@ -0,0 +1,219 @@
+package portfolios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrAssetNotFound = errors.New("asset not found")
+	ErrInvalidAsset  = errors.New("invalid asset")
+)
+
+type CustomAssetService interface {
+	CreateAsset(ctx context.Context, asset *CustomAsset) (*CustomAsset, error)
+	GetAsset(ctx context.Context, id string) (*CustomAsset, error)
+	ListAssets(ctx context.Context) ([]CustomAsset, error)
+	RecordValuation(ctx context.Context, valuation *AssetValuation) (*CustomAsset, error)
+	GetValuationHistory(ctx context.Context, id string, from, to int64) ([]AssetValuation, error)
+	GetDueRevaluations(ctx context.Context, now time.Time) ([]CustomAsset, error)
+}
+
+type customAssetService struct {
+	repo CustomAssetRepository
+	log  *zap.SugaredLogger
+}
+
+func NewCustomAssetService(repo CustomAssetRepository, log *zap.SugaredLogger) CustomAssetService {
+	return &customAssetService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *customAssetService) CreateAsset(ctx context.Context, asset *CustomAsset) (*CustomAsset, error) {
+	if err := asset.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAsset, err)
+	}
+
+	id, err := service.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	created := *asset
+	created.ID = id
+	created.KeyID = service.CallerKeyID(ctx)
+	created.CreatedUTC = now
+	created.LastValuedUTC = 0
+	created.NextRevaluationUTC = 0
+
+	// The initial value, if any, is the first entry in the valuation history
+	if created.CurrentValue > 0 {
+		created.LastValuedUTC = now
+		if err := s.repo.PutValuation(ctx, &AssetValuation{
+			AssetID:   id,
+			Timestamp: now,
+			Value:     created.CurrentValue,
+			Note:      "initial valuation",
+		}); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to record initial valuation", "asset", id, "error", err)
+			return nil, fmt.Errorf("failed to record initial valuation: %w", err)
+		}
+	}
+	created.NextRevaluationUTC = nextRevaluation(created.LastValuedUTC, now, created.RevaluationIntervalDays)
+
+	if err := s.repo.PutAsset(ctx, &created); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to create asset", "name", created.Name, "error", err)
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created custom asset", "asset", id, "name", created.Name)
+	return &created, nil
+}
+
+func (s *customAssetService) GetAsset(ctx context.Context, id string) (*CustomAsset, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidAsset)
+	}
+
+	asset, err := s.repo.GetAsset(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrAssetNotFound) {
+			return nil, ErrAssetNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get asset", "asset", id, "error", err)
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+	// Another key's asset is reported as missing rather than forbidden
+	if asset.KeyID != service.CallerKeyID(ctx) {
+		return nil, ErrAssetNotFound
+	}
+
+	return asset, nil
+}
+
+// ListAssets returns the caller's assets
+func (s *customAssetService) ListAssets(ctx context.Context) ([]CustomAsset, error) {
+	assets, err := listOwnAssets(ctx, s.repo)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list assets", "error", err)
+		return nil, fmt.Errorf("failed to list assets: %w", err)
+	}
+	return assets, nil
+}
+
+func (s *customAssetService) RecordValuation(ctx context.Context, valuation *AssetValuation) (*CustomAsset, error) {
+	if valuation.Timestamp == 0 {
+		valuation.Timestamp = time.Now().Unix()
+	}
+
+	if err := valuation.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAsset, err)
+	}
+
+	asset, err := s.GetAsset(ctx, valuation.AssetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PutValuation(ctx, valuation); err != nil {
+		if errors.Is(err, ErrInvalidAsset) {
+			return nil, err
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to record valuation", "asset", asset.ID, "error", err)
+		return nil, fmt.Errorf("failed to record valuation: %w", err)
+	}
+
+	// Backdated entries extend the history without replacing a newer current value
+	if valuation.Timestamp >= asset.LastValuedUTC {
+		asset.CurrentValue = valuation.Value
+		asset.LastValuedUTC = valuation.Timestamp
+		asset.NextRevaluationUTC = nextRevaluation(asset.LastValuedUTC, asset.CreatedUTC, asset.RevaluationIntervalDays)
+
+		if err := s.repo.PutAsset(ctx, asset); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to update asset value", "asset", asset.ID, "error", err)
+			return nil, fmt.Errorf("failed to update asset: %w", err)
+		}
+	}
+
+	logger.FromContext(ctx, s.log).Debugw("recorded valuation", "asset", asset.ID, "value", valuation.Value)
+	return asset, nil
+}
+
+func (s *customAssetService) GetValuationHistory(ctx context.Context, id string, from, to int64) ([]AssetValuation, error) {
+	if _, err := s.GetAsset(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if to == 0 {
+		to = math.MaxInt64
+	}
+	if from > to {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidAsset)
+	}
+
+	valuations, err := s.repo.GetValuations(ctx, id, from, to)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get valuations", "asset", id, "error", err)
+		return nil, fmt.Errorf("failed to get valuations: %w", err)
+	}
+
+	return valuations, nil
+}
+
+// GetDueRevaluations returns the assets whose revaluation reminder is due at now
+func (s *customAssetService) GetDueRevaluations(ctx context.Context, now time.Time) ([]CustomAsset, error) {
+	assets, err := s.ListAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([]CustomAsset, 0)
+	for _, a := range assets {
+		if a.NextRevaluationUTC > 0 && a.NextRevaluationUTC <= now.Unix() {
+			due = append(due, a)
+		}
+	}
+
+	logger.FromContext(ctx, s.log).Debugw("checked revaluation reminders", "total", len(assets), "due", len(due))
+	return due, nil
+}
+
+// listOwnAssets returns the custom assets of the calling key
+func listOwnAssets(ctx context.Context, repo CustomAssetRepository) ([]CustomAsset, error) {
+	all, err := repo.ListAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := service.CallerKeyID(ctx)
+	assets := make([]CustomAsset, 0, len(all))
+	for _, asset := range all {
+		if asset.KeyID == keyID {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, nil
+}
+
+// nextRevaluation schedules the next reminder one interval after the last
+// valuation, or after creation for assets that have never been valued
+func nextRevaluation(lastValued, created int64, intervalDays int32) int64 {
+	if intervalDays <= 0 {
+		return 0
+	}
+	base := lastValued
+	if base == 0 {
+		base = created
+	}
+	return time.Unix(base, 0).AddDate(0, 0, int(intervalDays)).Unix()
+}
@@ -0,0 +1,245 @@
+package portfolios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockCustomAssetRepository mocks the CustomAssetRepository interface
+type MockCustomAssetRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomAssetRepository) GetAsset(ctx context.Context, id string) (*CustomAsset, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CustomAsset), args.Error(1)
+}
+
+func (m *MockCustomAssetRepository) ListAssets(ctx context.Context) ([]CustomAsset, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]CustomAsset), args.Error(1)
+}
+
+func (m *MockCustomAssetRepository) PutAsset(ctx context.Context, asset *CustomAsset) error {
+	return m.Called(ctx, asset).Error(0)
+}
+
+func (m *MockCustomAssetRepository) PutValuation(ctx context.Context, valuation *AssetValuation) error {
+	return m.Called(ctx, valuation).Error(0)
+}
+
+func (m *MockCustomAssetRepository) GetValuations(ctx context.Context, assetID string, from, to int64) ([]AssetValuation, error) {
+	args := m.Called(ctx, assetID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]AssetValuation), args.Error(1)
+}
+
+func TestCustomAssetService_CreateAsset(t *testing.T) {
+	tests := []struct {
+		name      string
+		asset     CustomAsset
+		mockSetup func(*MockCustomAssetRepository)
+		wantErr   error
+		check     func(*testing.T, *CustomAsset)
+	}{
+		{
+			name:  "records initial valuation and schedules reminder",
+			asset: CustomAsset{Name: "House", Currency: "USD", CurrentValue: 500000, RevaluationIntervalDays: 90},
+			mockSetup: func(m *MockCustomAssetRepository) {
+				m.On("PutValuation", mock.Anything, mock.MatchedBy(func(v *AssetValuation) bool {
+					return v.Value == 500000
+				})).Return(nil)
+				m.On("PutAsset", mock.Anything, mock.Anything).Return(nil)
+			},
+			check: func(t *testing.T, a *CustomAsset) {
+				assert.NotEmpty(t, a.ID)
+				assert.Equal(t, a.CreatedUTC, a.LastValuedUTC)
+				assert.Equal(t, time.Unix(a.LastValuedUTC, 0).AddDate(0, 0, 90).Unix(), a.NextRevaluationUTC)
+			},
+		},
+		{
+			name:  "asset without value or schedule",
+			asset: CustomAsset{Name: "Art", Currency: "USD"},
+			mockSetup: func(m *MockCustomAssetRepository) {
+				m.On("PutAsset", mock.Anything, mock.Anything).Return(nil)
+			},
+			check: func(t *testing.T, a *CustomAsset) {
+				assert.Zero(t, a.LastValuedUTC)
+				assert.Zero(t, a.NextRevaluationUTC)
+			},
+		},
+		{
+			name:      "rejects invalid asset",
+			asset:     CustomAsset{Currency: "USD"},
+			mockSetup: func(m *MockCustomAssetRepository) {},
+			wantErr:   ErrInvalidAsset,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCustomAssetRepository)
+			tt.mockSetup(repo)
+			svc := NewCustomAssetService(repo, zap.NewNop().Sugar())
+
+			asset, err := svc.CreateAsset(context.Background(), &tt.asset)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			tt.check(t, asset)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCustomAssetService_ScopesAssetsToTheCallingKey(t *testing.T) {
+	as := func(keyID string) context.Context {
+		return service.WithAccount(context.Background(), &models.APIKey{ID: keyID})
+	}
+
+	repo := new(MockCustomAssetRepository)
+	repo.On("ListAssets", mock.Anything).Return([]CustomAsset{
+		{ID: "a1", Name: "House", KeyID: "alice"},
+		{ID: "a2", Name: "Boat", KeyID: "bob"},
+	}, nil)
+	repo.On("GetAsset", mock.Anything, "a2").Return(&CustomAsset{ID: "a2", KeyID: "bob"}, nil)
+	repo.On("PutAsset", mock.Anything, mock.Anything).Return(nil)
+	svc := NewCustomAssetService(repo, zap.NewNop().Sugar())
+
+	assets, err := svc.ListAssets(as("alice"))
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	assert.Equal(t, "a1", assets[0].ID)
+
+	_, err = svc.GetAsset(as("alice"), "a2")
+	assert.ErrorIs(t, err, ErrAssetNotFound, "another key's asset is not found")
+	_, err = svc.RecordValuation(as("alice"), &AssetValuation{AssetID: "a2", Timestamp: 1, Value: 10})
+	assert.ErrorIs(t, err, ErrAssetNotFound, "another key's asset is not revalued")
+	repo.AssertNotCalled(t, "PutValuation", mock.Anything, mock.Anything)
+	_, err = svc.GetAsset(as("bob"), "a2")
+	assert.NoError(t, err)
+
+	created, err := svc.CreateAsset(as("alice"), &CustomAsset{Name: "Art", Currency: "USD"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", created.KeyID)
+}
+
+func TestCustomAssetService_RecordValuation(t *testing.T) {
+	lastValued := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC).Unix()
+
+	tests := []struct {
+		name        string
+		timestamp   int64
+		mockSetup   func(*MockCustomAssetRepository)
+		wantErr     error
+		wantValue   float64
+		wantUpdated bool
+	}{
+		{
+			name:      "newer valuation updates current value",
+			timestamp: lastValued + 86400,
+			mockSetup: func(m *MockCustomAssetRepository) {
+				m.On("PutValuation", mock.Anything, mock.Anything).Return(nil)
+				m.On("PutAsset", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantValue:   120,
+			wantUpdated: true,
+		},
+		{
+			name:      "backdated valuation keeps current value",
+			timestamp: lastValued - 86400,
+			mockSetup: func(m *MockCustomAssetRepository) {
+				m.On("PutValuation", mock.Anything, mock.Anything).Return(nil)
+			},
+			wantValue: 100,
+		},
+		{
+			name:      "duplicate timestamp is invalid",
+			timestamp: lastValued,
+			mockSetup: func(m *MockCustomAssetRepository) {
+				m.On("PutValuation", mock.Anything, mock.Anything).Return(fmt.Errorf("%w: %s", ErrInvalidAsset, "duplicate"))
+			},
+			wantErr: ErrInvalidAsset,
+		},
+		{
+			name:      "storage failure",
+			timestamp: lastValued + 1,
+			mockSetup: func(m *MockCustomAssetRepository) {
+				m.On("PutValuation", mock.Anything, mock.Anything).Return(errors.New("boom"))
+			},
+			wantErr: errors.New("failed to record valuation: boom"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCustomAssetRepository)
+			repo.On("GetAsset", mock.Anything, "a1").Return(&CustomAsset{
+				ID: "a1", Name: "Car", Currency: "USD", CurrentValue: 100,
+				LastValuedUTC: lastValued, RevaluationIntervalDays: 30,
+			}, nil)
+			tt.mockSetup(repo)
+			svc := NewCustomAssetService(repo, zap.NewNop().Sugar())
+
+			asset, err := svc.RecordValuation(context.Background(), &AssetValuation{
+				AssetID: "a1", Timestamp: tt.timestamp, Value: 120,
+			})
+
+			if tt.wantErr != nil {
+				require.Error(t, err)
+				if errors.Is(tt.wantErr, ErrInvalidAsset) {
+					assert.ErrorIs(t, err, ErrInvalidAsset)
+				} else {
+					assert.EqualError(t, err, tt.wantErr.Error())
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantValue, asset.CurrentValue)
+			if tt.wantUpdated {
+				assert.Equal(t, time.Unix(tt.timestamp, 0).AddDate(0, 0, 30).Unix(), asset.NextRevaluationUTC)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCustomAssetService_GetDueRevaluations(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	repo := new(MockCustomAssetRepository)
+	repo.On("ListAssets", mock.Anything).Return([]CustomAsset{
+		{ID: "overdue", NextRevaluationUTC: now.Add(-time.Hour).Unix()},
+		{ID: "upcoming", NextRevaluationUTC: now.Add(time.Hour).Unix()},
+		{ID: "unscheduled"},
+	}, nil)
+	svc := NewCustomAssetService(repo, zap.NewNop().Sugar())
+
+	due, err := svc.GetDueRevaluations(context.Background(), now)
+
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, "overdue", due[0].ID)
+}
@@ -0,0 +1,144 @@
+package portfolios
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createAssetRequest struct {
+	Name                    string  `json:"name"`
+	Category                string  `json:"category"`
+	Currency                string  `json:"currency"`
+	CurrentValue            float64 `json:"currentValue"`
+	RevaluationIntervalDays int32   `json:"revaluationIntervalDays"`
+}
+
+type recordValuationRequest struct {
+	Value     float64 `json:"value"`
+	Note      string  `json:"note"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+func (h *Handler) ListCustomAssets(c *gin.Context) {
+	assets, err := h.customAssetService.ListAssets(c.Request.Context())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to list assets", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve assets")
+		return
+	}
+
+	if assets == nil {
+		assets = []CustomAsset{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"assets": assets,
+		"count":  len(assets),
+	})
+}
+
+func (h *Handler) CreateCustomAsset(c *gin.Context) {
+	var req createAssetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	asset, err := h.customAssetService.CreateAsset(c.Request.Context(), &CustomAsset{
+		Name:                    req.Name,
+		Category:                req.Category,
+		Currency:                req.Currency,
+		CurrentValue:            req.CurrentValue,
+		RevaluationIntervalDays: req.RevaluationIntervalDays,
+	})
+	if err != nil {
+		h.respondAssetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, asset)
+}
+
+func (h *Handler) GetCustomAsset(c *gin.Context) {
+	asset, err := h.customAssetService.GetAsset(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondAssetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, asset)
+}
+
+func (h *Handler) RecordAssetValuation(c *gin.Context) {
+	var req recordValuationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	asset, err := h.customAssetService.RecordValuation(c.Request.Context(), &AssetValuation{
+		AssetID:   c.Param("id"),
+		Timestamp: req.Timestamp,
+		Value:     req.Value,
+		Note:      req.Note,
+	})
+	if err != nil {
+		h.respondAssetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, asset)
+}
+
+func (h *Handler) GetAssetValuations(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	valuations, err := h.customAssetService.GetValuationHistory(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		h.respondAssetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valuations": valuations,
+		"count":      len(valuations),
+	})
+}
+
+func (h *Handler) GetAssetRevaluationReminders(c *gin.Context) {
+	assets, err := h.customAssetService.GetDueRevaluations(c.Request.Context(), time.Now())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to get revaluation reminders", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve revaluation reminders")
+		return
+	}
+
+	if assets == nil {
+		assets = []CustomAsset{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"assets": assets,
+		"count":  len(assets),
+	})
+}
+
+func (h *Handler) respondAssetError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrAssetNotFound):
+		problem.Respond(c, problem.AssetNotFound, "Asset not found")
+	case errors.Is(err, ErrInvalidAsset):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("custom asset request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process asset request")
+	}
+}
@@ -0,0 +1,138 @@
+package portfolios
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type createPortfolioRequest struct {
+	Name     string `json:"name"`
+	Currency string `json:"currency"`
+}
+
+type recordTransactionRequest struct {
+	Symbol    string  `json:"symbol"`
+	Type      string  `json:"type"`
+	Quantity  float64 `json:"quantity"`
+	Price     float64 `json:"price"`
+	Fee       float64 `json:"fee"`
+	Timestamp int64   `json:"timestamp"`
+}
+
+func (h *Handler) ListPortfolios(c *gin.Context) {
+	portfolios, err := h.portfolioService.ListPortfolios(c.Request.Context())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to list portfolios", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve portfolios")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"portfolios": portfolios,
+		"count":      len(portfolios),
+	})
+}
+
+func (h *Handler) CreatePortfolio(c *gin.Context) {
+	var req createPortfolioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	portfolio, err := h.portfolioService.CreatePortfolio(c.Request.Context(), &Portfolio{
+		Name:     req.Name,
+		Currency: req.Currency,
+	})
+	if err != nil {
+		h.respondPortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, portfolio)
+}
+
+func (h *Handler) GetPortfolio(c *gin.Context) {
+	portfolio, err := h.portfolioService.GetPortfolio(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondPortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, portfolio)
+}
+
+func (h *Handler) RecordTransaction(c *gin.Context) {
+	var req recordTransactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	transaction, err := h.portfolioService.RecordTransaction(c.Request.Context(), &Transaction{
+		PortfolioID: c.Param("id"),
+		Symbol:      req.Symbol,
+		Type:        TransactionType(req.Type),
+		Quantity:    req.Quantity,
+		Price:       req.Price,
+		Fee:         req.Fee,
+		Timestamp:   req.Timestamp,
+	})
+	if err != nil {
+		h.respondPortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+func (h *Handler) GetTransactions(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	transactions, err := h.portfolioService.GetTransactions(c.Request.Context(), c.Param("id"), from, to)
+	if err != nil {
+		h.respondPortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"transactions": transactions,
+		"count":        len(transactions),
+	})
+}
+
+// GetPositions returns the open holdings of a portfolio with cost basis and
+// unrealized P&L at the latest daily close
+func (h *Handler) GetPositions(c *gin.Context) {
+	positions, err := h.portfolioService.GetPositions(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondPortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, positions)
+}
+
+func (h *Handler) respondPortfolioError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrPortfolioNotFound):
+		problem.Respond(c, problem.PortfolioNotFound, "Portfolio not found")
+	case errors.Is(err, ErrInvalidPortfolio),
+		errors.Is(err, ErrInvalidTransaction),
+		errors.Is(err, service.ErrInvalidRange):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("portfolio request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process portfolio request")
+	}
+}
@@ -0,0 +1,165 @@
+// Package portfolios serves securities portfolios with their transactions and
+// positions, custom assets with their valuations, and the account's net worth.
+package portfolios
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	customAssetService CustomAssetService
+	netWorthService    NetWorthService
+	portfolioService   PortfolioService
+	log                *zap.SugaredLogger
+}
+
+func NewHandler(
+	customAssets CustomAssetService,
+	netWorth NetWorthService,
+	portfolios PortfolioService,
+	log *zap.SugaredLogger,
+) *Handler {
+	return &Handler{
+		customAssetService: customAssets,
+		netWorthService:    netWorth,
+		portfolioService:   portfolios,
+		log:                log,
+	}
+}
+
+// Wire builds the portfolios module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	cfg := deps.Config
+	customAssetRepo := NewCustomAssetRepository(deps.DB, cfg.CustomAssetsTable, cfg.AssetValuationsTable)
+	portfolioRepo := NewPortfolioRepository(deps.DB, cfg.PortfoliosTable, cfg.PortfolioTransactionsTable)
+
+	return NewHandler(
+		NewCustomAssetService(customAssetRepo, deps.Log),
+		NewNetWorthService(deps.Log,
+			NewPortfolioValuationSource(portfolioRepo, deps.DailySummaryRepository()),
+			NewCustomAssetValuationSource(customAssetRepo),
+		),
+		NewPortfolioService(portfolioRepo, service.NewDailySummaryService(deps.DailySummaryRepository(), deps.Log), deps.Events, deps.Log),
+		deps.Log,
+	)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	scope, terms := middleware.RequireScope(models.ScopeWritePortfolio), middleware.RequireAcceptedTerms()
+
+	assets := api.Group("/assets", scope, terms)
+	assets.GET("", h.ListCustomAssets)
+	assets.POST("", h.CreateCustomAsset)
+	assets.GET("/reminders", h.GetAssetRevaluationReminders)
+	assets.GET("/:id", h.GetCustomAsset)
+	assets.GET("/:id/valuations", h.GetAssetValuations)
+	assets.POST("/:id/valuations", h.RecordAssetValuation)
+
+	api.GET("/account/net-worth", scope, terms, h.GetNetWorth)
+
+	portfolios := api.Group("/portfolios", scope, terms)
+	portfolios.GET("", h.ListPortfolios)
+	portfolios.POST("", h.CreatePortfolio)
+	portfolios.GET("/:id", h.GetPortfolio)
+	portfolios.GET("/:id/transactions", h.GetTransactions)
+	portfolios.POST("/:id/transactions", h.RecordTransaction)
+	portfolios.GET("/:id/positions", h.GetPositions)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	assetTags, portfolioTags := []string{"Custom assets"}, []string{"Portfolios"}
+	assetID := openapi.PathParam("id", "Custom asset ID")
+	portfolioID := openapi.PathParam("id", "Portfolio ID")
+
+	doc.Add(http.MethodGet, "/api/v1/assets", &openapi.Operation{
+		Tags:      assetTags,
+		Summary:   "List custom assets",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "assets", CustomAsset{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/assets", &openapi.Operation{
+		Tags:        assetTags,
+		Summary:     "Create a custom asset",
+		RequestBody: openapi.JSONBody(doc.Inline(createAssetRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(CustomAsset{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/assets/reminders", &openapi.Operation{
+		Tags:      assetTags,
+		Summary:   "List custom assets due for revaluation",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "assets", CustomAsset{})),
+	})
+	doc.Add(http.MethodGet, "/api/v1/assets/:id", &openapi.Operation{
+		Tags:       assetTags,
+		Summary:    "Get a custom asset",
+		Parameters: []openapi.Parameter{assetID},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(CustomAsset{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/assets/:id/valuations", &openapi.Operation{
+		Tags:       assetTags,
+		Summary:    "List a custom asset's valuations",
+		Parameters: append([]openapi.Parameter{assetID}, api.DateRangeParams()...),
+		Responses:  api.Responses(http.StatusOK, api.List(doc, "valuations", AssetValuation{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/assets/:id/valuations", &openapi.Operation{
+		Tags:        assetTags,
+		Summary:     "Record a custom asset's valuation",
+		Description: "Returns the asset with its current value updated.",
+		Parameters:  []openapi.Parameter{assetID},
+		RequestBody: openapi.JSONBody(doc.Inline(recordValuationRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(CustomAsset{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+
+	doc.Add(http.MethodGet, "/api/v1/account/net-worth", &openapi.Operation{
+		Tags:        []string{"Account"},
+		Summary:     "Get the daily net worth series across asset classes",
+		Description: "Sums the securities held in portfolios, crypto included, at each day's close and custom assets at their latest valuation. Cash is not included. Defaults to the 90 days up to today.",
+		Parameters:  api.DateRangeParams(),
+		Responses:   api.Responses(http.StatusOK, doc.Schema(NetWorth{}), http.StatusBadRequest),
+	})
+
+	doc.Add(http.MethodGet, "/api/v1/portfolios", &openapi.Operation{
+		Tags:      portfolioTags,
+		Summary:   "List portfolios",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "portfolios", Portfolio{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/portfolios", &openapi.Operation{
+		Tags:        portfolioTags,
+		Summary:     "Create a portfolio",
+		RequestBody: openapi.JSONBody(doc.Inline(createPortfolioRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(Portfolio{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/portfolios/:id", &openapi.Operation{
+		Tags:       portfolioTags,
+		Summary:    "Get a portfolio",
+		Parameters: []openapi.Parameter{portfolioID},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(Portfolio{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/portfolios/:id/transactions", &openapi.Operation{
+		Tags:       portfolioTags,
+		Summary:    "List a portfolio's transactions",
+		Parameters: append([]openapi.Parameter{portfolioID}, api.DateRangeParams()...),
+		Responses:  api.Responses(http.StatusOK, api.List(doc, "transactions", Transaction{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/portfolios/:id/transactions", &openapi.Operation{
+		Tags:        portfolioTags,
+		Summary:     "Record a transaction in a portfolio",
+		Parameters:  []openapi.Parameter{portfolioID},
+		RequestBody: openapi.JSONBody(doc.Inline(recordTransactionRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(Transaction{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/portfolios/:id/positions", &openapi.Operation{
+		Tags:       portfolioTags,
+		Summary:    "Get a portfolio's positions valued at the latest closes",
+		Parameters: []openapi.Parameter{portfolioID},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(PortfolioPositions{}), http.StatusNotFound),
+	})
+}
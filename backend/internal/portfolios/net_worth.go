@@ -0,0 +1,50 @@
+package portfolios
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultNetWorthDays is the series length returned when no from date is given
+const defaultNetWorthDays = 90
+
+func (h *Handler) GetNetWorth(c *gin.Context) {
+	from, err := api.ParseDateQuery(c, "from")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	to, err := api.ParseDateQuery(c, "to")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -defaultNetWorthDays)
+	}
+
+	netWorth, err := h.netWorthService.GetNetWorth(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRange) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to get net worth", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to compute net worth")
+		return
+	}
+
+	c.JSON(http.StatusOK, netWorth)
+}
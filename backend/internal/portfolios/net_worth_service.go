@@ -0,0 +1,288 @@
+package portfolios
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Asset classes aggregated into net worth. Crypto traded through portfolios
+// is valued with the securities. Cash balances are not tracked by any asset
+// subsystem yet, so they are not part of net worth.
+const (
+	AssetClassSecurities = "securities"
+	AssetClassCustom     = "custom"
+)
+
+// NetWorthPoint is the aggregated value of all asset classes at the end of a day
+type NetWorthPoint struct {
+	Timestamp int64              `json:"timestamp"`
+	Total     float64            `json:"total"`
+	ByClass   map[string]float64 `json:"byClass"`
+}
+
+// AllocationSlice is one asset class's share of the latest net worth
+type AllocationSlice struct {
+	AssetClass string  `json:"assetClass"`
+	Value      float64 `json:"value"`
+	Weight     float64 `json:"weight"`
+}
+
+// NetWorth is a daily net worth time series with the allocation breakdown of its last point
+type NetWorth struct {
+	Series     []NetWorthPoint   `json:"series"`
+	Allocation []AllocationSlice `json:"allocation"`
+	Total      float64           `json:"total"`
+}
+
+// maxNetWorthDays bounds the length of a net worth series
+const maxNetWorthDays = 3660
+
+// ValuationSource values one asset class over time so that net worth can be
+// aggregated uniformly across asset subsystems
+type ValuationSource interface {
+	AssetClass() string
+	// ValuesAt returns the class's total value at each of the given instants
+	ValuesAt(ctx context.Context, at []time.Time) ([]float64, error)
+}
+
+type NetWorthService interface {
+	GetNetWorth(ctx context.Context, from, to time.Time) (*NetWorth, error)
+}
+
+type netWorthService struct {
+	sources []ValuationSource
+	log     *zap.SugaredLogger
+}
+
+func NewNetWorthService(log *zap.SugaredLogger, sources ...ValuationSource) NetWorthService {
+	return &netWorthService{
+		sources: sources,
+		log:     log,
+	}
+}
+
+// GetNetWorth returns one point per day in [from, to], each valued at the end of that day.
+// Values are summed as recorded; no currency conversion is applied.
+func (s *netWorthService) GetNetWorth(ctx context.Context, from, to time.Time) (*NetWorth, error) {
+	from = service.StartOfDay(from)
+	to = service.StartOfDay(to)
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from must not be after to", service.ErrInvalidRange)
+	}
+
+	var days []time.Time
+	var instants []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+		instants = append(instants, d.AddDate(0, 0, 1).Add(-time.Second))
+	}
+	if len(days) > maxNetWorthDays {
+		return nil, fmt.Errorf("%w: range exceeds %d days", service.ErrInvalidRange, maxNetWorthDays)
+	}
+
+	series := make([]NetWorthPoint, len(days))
+	for i, d := range days {
+		series[i] = NetWorthPoint{
+			Timestamp: d.Unix(),
+			ByClass:   make(map[string]float64, len(s.sources)),
+		}
+	}
+
+	for _, src := range s.sources {
+		values, err := src.ValuesAt(ctx, instants)
+		if err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to value asset class", "class", src.AssetClass(), "error", err)
+			return nil, fmt.Errorf("failed to value %s assets: %w", src.AssetClass(), err)
+		}
+		for i, v := range values {
+			series[i].ByClass[src.AssetClass()] += v
+			series[i].Total += v
+		}
+	}
+
+	last := series[len(series)-1]
+	allocation := make([]AllocationSlice, 0, len(last.ByClass))
+	for class, value := range last.ByClass {
+		slice := AllocationSlice{AssetClass: class, Value: value}
+		if last.Total != 0 {
+			slice.Weight = value / last.Total
+		}
+		allocation = append(allocation, slice)
+	}
+	sort.Slice(allocation, func(i, j int) bool {
+		return allocation[i].Value > allocation[j].Value
+	})
+
+	logger.FromContext(ctx, s.log).Debugw("computed net worth", "days", len(series), "total", last.Total)
+	return &NetWorth{
+		Series:     series,
+		Allocation: allocation,
+		Total:      last.Total,
+	}, nil
+}
+
+// customAssetSource values the caller's custom assets from their manual
+// valuation history
+type customAssetSource struct {
+	repo CustomAssetRepository
+}
+
+// NewCustomAssetValuationSource creates a ValuationSource for custom assets
+func NewCustomAssetValuationSource(repo CustomAssetRepository) ValuationSource {
+	return &customAssetSource{repo: repo}
+}
+
+func (c *customAssetSource) AssetClass() string {
+	return AssetClassCustom
+}
+
+// ValuesAt carries the most recent valuation of each of the caller's assets
+// forward to every instant
+func (c *customAssetSource) ValuesAt(ctx context.Context, at []time.Time) ([]float64, error) {
+	totals := make([]float64, len(at))
+	if len(at) == 0 {
+		return totals, nil
+	}
+
+	assets, err := listOwnAssets(ctx, c.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	until := at[len(at)-1].Unix()
+	for _, asset := range assets {
+		valuations, err := c.repo.GetValuations(ctx, asset.ID, 0, until)
+		if err != nil {
+			return nil, err
+		}
+
+		j := -1
+		for i, t := range at {
+			for j+1 < len(valuations) && valuations[j+1].Timestamp <= t.Unix() {
+				j++
+			}
+			if j >= 0 {
+				totals[i] += valuations[j].Value
+			}
+		}
+	}
+
+	return totals, nil
+}
+
+// portfolioSource values the holdings of the caller's portfolios at historical closes
+type portfolioSource struct {
+	repo      PortfolioRepository
+	summaries repository.DailySummaryRepository
+}
+
+// NewPortfolioValuationSource creates a ValuationSource for the securities
+// held in portfolios
+func NewPortfolioValuationSource(repo PortfolioRepository, summaries repository.DailySummaryRepository) ValuationSource {
+	return &portfolioSource{repo: repo, summaries: summaries}
+}
+
+func (p *portfolioSource) AssetClass() string {
+	return AssetClassSecurities
+}
+
+// ValuesAt replays each portfolio's transactions up to every instant and
+// values the quantities held at the latest daily close at or before it.
+// Securities without a close by then are left out, as in the market value of
+// positions.
+func (p *portfolioSource) ValuesAt(ctx context.Context, at []time.Time) ([]float64, error) {
+	totals := make([]float64, len(at))
+	if len(at) == 0 {
+		return totals, nil
+	}
+
+	portfolios, err := listOwnPortfolios(ctx, p.repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// held[symbol][i] is the quantity held across portfolios at at[i]
+	until := at[len(at)-1].Unix()
+	held := make(map[string][]float64)
+	for _, portfolio := range portfolios {
+		transactions, err := p.repo.GetTransactions(ctx, portfolio.ID, 0, until)
+		if err != nil {
+			return nil, err
+		}
+
+		quantities := make(map[string]float64)
+		j := 0
+		for i, t := range at {
+			for ; j < len(transactions) && transactions[j].Timestamp <= t.Unix(); j++ {
+				switch tx := transactions[j]; tx.Type {
+				case TransactionBuy:
+					quantities[tx.Symbol] += tx.Quantity
+				case TransactionSell:
+					quantities[tx.Symbol] -= tx.Quantity
+				}
+			}
+			for symbol, quantity := range quantities {
+				if quantity <= quantityEpsilon {
+					continue
+				}
+				if held[symbol] == nil {
+					held[symbol] = make([]float64, len(at))
+				}
+				held[symbol][i] += quantity
+			}
+		}
+	}
+
+	for symbol, quantities := range held {
+		closes, err := p.closes(ctx, symbol, at)
+		if err != nil {
+			return nil, err
+		}
+		for i, quantity := range quantities {
+			totals[i] += quantity * closes[i]
+		}
+	}
+
+	return totals, nil
+}
+
+// closes returns the latest daily close of symbol at or before each instant,
+// or zero before its first close
+func (p *portfolioSource) closes(ctx context.Context, symbol string, at []time.Time) ([]float64, error) {
+	first, until := at[0].Unix(), at[len(at)-1].Unix()
+
+	var last float64
+	latest, err := p.summaries.GetLatestSummaries(ctx, symbol, first, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(latest) > 0 {
+		last = float64(latest[0].Close)
+	}
+
+	closes := make([]float64, len(at))
+	i := 0
+	err = p.summaries.EachSummary(ctx, symbol, first+1, until, func(summary models.DailySummary) error {
+		for ; i < len(at) && at[i].Unix() < summary.Timestamp; i++ {
+			closes[i] = last
+		}
+		last = float64(summary.Close)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for ; i < len(at); i++ {
+		closes[i] = last
+	}
+
+	return closes, nil
+}
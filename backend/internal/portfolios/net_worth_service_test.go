@@ -0,0 +1,130 @@
+package portfolios
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fixedSource struct {
+	class string
+	value float64
+	err   error
+}
+
+func (f fixedSource) AssetClass() string { return f.class }
+
+func (f fixedSource) ValuesAt(ctx context.Context, at []time.Time) ([]float64, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	values := make([]float64, len(at))
+	for i := range values {
+		values[i] = f.value
+	}
+	return values, nil
+}
+
+func TestNetWorthService_GetNetWorth(t *testing.T) {
+	from := time.Date(2025, 1, 1, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC)
+
+	svc := NewNetWorthService(zap.NewNop().Sugar(),
+		fixedSource{class: "custom", value: 300},
+		fixedSource{class: "cash", value: 100},
+	)
+
+	nw, err := svc.GetNetWorth(context.Background(), from, to)
+
+	require.NoError(t, err)
+	require.Len(t, nw.Series, 3)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), nw.Series[0].Timestamp)
+	assert.Equal(t, 400.0, nw.Total)
+	require.Len(t, nw.Allocation, 2)
+	assert.Equal(t, "custom", nw.Allocation[0].AssetClass)
+	assert.InDelta(t, 0.75, nw.Allocation[0].Weight, 1e-9)
+	assert.InDelta(t, 0.25, nw.Allocation[1].Weight, 1e-9)
+}
+
+func TestNetWorthService_Errors(t *testing.T) {
+	day := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc := NewNetWorthService(zap.NewNop().Sugar())
+	_, err := svc.GetNetWorth(context.Background(), day.AddDate(0, 0, 1), day)
+	assert.ErrorIs(t, err, service.ErrInvalidRange)
+
+	_, err = svc.GetNetWorth(context.Background(), day.AddDate(-20, 0, 0), day)
+	assert.ErrorIs(t, err, service.ErrInvalidRange)
+
+	svc = NewNetWorthService(zap.NewNop().Sugar(), fixedSource{class: "custom", err: errors.New("boom")})
+	_, err = svc.GetNetWorth(context.Background(), day, day)
+	assert.EqualError(t, err, "failed to value custom assets: boom")
+}
+
+func TestCustomAssetSource_ValuesAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 23, 59, 59, 0, time.UTC) }
+
+	repo := new(MockCustomAssetRepository)
+	repo.On("ListAssets", mock.Anything).Return([]CustomAsset{{ID: "house"}, {ID: "car"}, {ID: "boat", KeyID: "bob"}}, nil)
+	repo.On("GetValuations", mock.Anything, "house", int64(0), day(4).Unix()).Return([]AssetValuation{
+		{AssetID: "house", Timestamp: day(1).Add(-time.Hour).Unix(), Value: 100},
+		{AssetID: "house", Timestamp: day(3).Add(-time.Hour).Unix(), Value: 150},
+	}, nil)
+	repo.On("GetValuations", mock.Anything, "car", int64(0), day(4).Unix()).Return([]AssetValuation{
+		{AssetID: "car", Timestamp: day(2).Add(-time.Hour).Unix(), Value: 20},
+	}, nil)
+
+	values, err := NewCustomAssetValuationSource(repo).ValuesAt(context.Background(),
+		[]time.Time{day(1), day(2), day(3), day(4)})
+
+	require.NoError(t, err)
+	assert.Equal(t, []float64{100, 120, 170, 170}, values)
+	repo.AssertNotCalled(t, "GetValuations", mock.Anything, "boat", mock.Anything, mock.Anything)
+}
+
+func TestPortfolioSource_ValuesAt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 23, 59, 59, 0, time.UTC) }
+	at := func(d int, before time.Duration) int64 { return day(d).Add(-before).Unix() }
+
+	repo := new(MockPortfolioRepository)
+	repo.On("ListPortfolios", mock.Anything).Return([]Portfolio{{ID: "p1"}, {ID: "p2"}}, nil)
+	repo.On("GetTransactions", mock.Anything, "p1", int64(0), day(4).Unix()).Return([]Transaction{
+		transaction(at(1, time.Hour), "AAPL", TransactionBuy, 10, 90, 0),
+		transaction(at(1, time.Hour), "UNPRICED", TransactionBuy, 3, 10, 0),
+		transaction(at(3, time.Hour), "AAPL", TransactionSell, 5, 115, 0),
+	}, nil)
+	repo.On("GetTransactions", mock.Anything, "p2", int64(0), day(4).Unix()).Return([]Transaction{
+		transaction(at(2, time.Hour), "X:BTCUSD", TransactionBuy, 1, 49000, 0),
+	}, nil)
+
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", day(1).Unix(), int32(1)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: at(1, 2*time.Hour), Close: 100},
+	}, nil)
+	summaries.On("GetSummaries", mock.Anything, "AAPL", day(1).Unix()+1, day(4).Unix()).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: at(2, time.Hour), Close: 110},
+		{Ticker: "AAPL", Timestamp: at(4, time.Hour), Close: 120},
+	}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "X:BTCUSD", day(1).Unix(), int32(1)).Return([]models.DailySummary{}, nil)
+	summaries.On("GetSummaries", mock.Anything, "X:BTCUSD", day(1).Unix()+1, day(4).Unix()).Return([]models.DailySummary{
+		{Ticker: "X:BTCUSD", Timestamp: at(2, 30*time.Minute), Close: 50000},
+	}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "UNPRICED", day(1).Unix(), int32(1)).Return([]models.DailySummary{}, nil)
+	summaries.On("GetSummaries", mock.Anything, "UNPRICED", day(1).Unix()+1, day(4).Unix()).Return([]models.DailySummary{}, nil)
+
+	values, err := NewPortfolioValuationSource(repo, summaries).ValuesAt(context.Background(),
+		[]time.Time{day(1), day(2), day(3), day(4)})
+
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1000, 51100, 50550, 50600}, values, "holdings are valued at the close of each day, unpriced ones at nothing")
+}
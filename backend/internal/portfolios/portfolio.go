@@ -0,0 +1,115 @@
+package portfolios
+
+import (
+	"fmt"
+)
+
+// Portfolio groups the securities transactions of one brokerage account or strategy
+type Portfolio struct {
+	ID         string `json:"id" dynamodbav:"id"`
+	Name       string `json:"name" dynamodbav:"name"`
+	Currency   string `json:"currency" dynamodbav:"currency"`
+	CreatedUTC int64  `json:"createdUTC" dynamodbav:"createdUTC"`
+	// KeyID is the API key that created the portfolio, the only one it is visible to
+	KeyID string `json:"-" dynamodbav:"keyId,omitempty"`
+}
+
+// TransactionType is the side of a portfolio transaction
+type TransactionType string
+
+const (
+	TransactionBuy  TransactionType = "buy"
+	TransactionSell TransactionType = "sell"
+)
+
+// Transaction is a buy or sell of a security in a portfolio. IDs sort in
+// chronological order within a portfolio.
+type Transaction struct {
+	PortfolioID string          `json:"portfolioId" dynamodbav:"portfolioId"`
+	ID          string          `json:"id" dynamodbav:"id"`
+	Symbol      string          `json:"symbol" dynamodbav:"symbol"`
+	Type        TransactionType `json:"type" dynamodbav:"type"`
+	Quantity    float64         `json:"quantity" dynamodbav:"quantity"`
+	Price       float64         `json:"price" dynamodbav:"price"`
+	Fee         float64         `json:"fee,omitempty" dynamodbav:"fee,omitempty"`
+	Timestamp   int64           `json:"timestamp" dynamodbav:"timestamp"`
+	CreatedUTC  int64           `json:"createdUTC" dynamodbav:"createdUTC"`
+}
+
+// Holding is the open position in one security, at average cost. The market
+// fields are only set when the security has a daily close.
+type Holding struct {
+	Symbol              string   `json:"symbol"`
+	Quantity            float64  `json:"quantity"`
+	CostBasis           float64  `json:"costBasis"`
+	AverageCost         float64  `json:"averageCost"`
+	RealizedPL          float64  `json:"realizedPL"`
+	LastClose           *float64 `json:"lastClose,omitempty"`
+	LastCloseUTC        int64    `json:"lastCloseUTC,omitempty"`
+	MarketValue         *float64 `json:"marketValue,omitempty"`
+	UnrealizedPL        *float64 `json:"unrealizedPL,omitempty"`
+	UnrealizedPLPercent *float64 `json:"unrealizedPLPercent,omitempty"`
+}
+
+// PortfolioPositions are the open holdings of a portfolio with their totals.
+// MarketValue and UnrealizedPL only sum holdings that have a daily close.
+type PortfolioPositions struct {
+	PortfolioID  string    `json:"portfolioId"`
+	Holdings     []Holding `json:"holdings"`
+	CostBasis    float64   `json:"costBasis"`
+	MarketValue  float64   `json:"marketValue"`
+	UnrealizedPL float64   `json:"unrealizedPL"`
+	RealizedPL   float64   `json:"realizedPL"`
+}
+
+// Validate checks if the portfolio data is valid
+func (p *Portfolio) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("portfolio name is required")
+	}
+
+	if p.Currency == "" {
+		return fmt.Errorf("currency is required")
+	}
+
+	return nil
+}
+
+// Validate checks if the transaction data is valid
+func (t *Transaction) Validate() error {
+	if t.PortfolioID == "" {
+		return fmt.Errorf("portfolio id is required")
+	}
+
+	if t.Symbol == "" {
+		return fmt.Errorf("symbol is required")
+	}
+
+	if t.Type != TransactionBuy && t.Type != TransactionSell {
+		return fmt.Errorf("type must be buy or sell")
+	}
+
+	if t.Quantity <= 0 {
+		return fmt.Errorf("quantity must be positive")
+	}
+
+	if t.Price < 0 {
+		return fmt.Errorf("price cannot be negative")
+	}
+
+	if t.Fee < 0 {
+		return fmt.Errorf("fee cannot be negative")
+	}
+
+	if t.Timestamp <= 0 {
+		return fmt.Errorf("timestamp must be positive")
+	}
+
+	return nil
+}
+
+// TransactionIDPrefix is the part of a transaction ID encoding its timestamp,
+// padded so that IDs sort chronologically
+func TransactionIDPrefix(timestamp int64) string {
+	return fmt.Sprintf("%011d", timestamp)
+}
@@ -0,0 +1,278 @@
+package portfolios
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// PortfolioRepository defines the interface for portfolio and transaction data operations
+type PortfolioRepository interface {
+	GetPortfolio(ctx context.Context, id string) (*Portfolio, error)
+	ListPortfolios(ctx context.Context) ([]Portfolio, error)
+	PutPortfolio(ctx context.Context, portfolio *Portfolio) error
+	PutTransaction(ctx context.Context, transaction *Transaction) error
+	GetTransactions(ctx context.Context, portfolioID string, from, to int64) ([]Transaction, error)
+}
+
+// portfolioRepository implements PortfolioRepository using DynamoDB
+type portfolioRepository struct {
+	client            *dynamodb.Client
+	tableName         string
+	transactionsTable string
+}
+
+// NewPortfolioRepository creates a new DynamoDB-backed portfolio repository
+func NewPortfolioRepository(client *dynamodb.Client, tableName, transactionsTable string) PortfolioRepository {
+	return &portfolioRepository{
+		client:            client,
+		tableName:         tableName,
+		transactionsTable: transactionsTable,
+	}
+}
+
+// GetPortfolio retrieves a single portfolio by ID
+func (r *portfolioRepository) GetPortfolio(ctx context.Context, id string) (*Portfolio, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get portfolio %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPortfolioNotFound, id)
+	}
+
+	var portfolio Portfolio
+	if err := attributevalue.UnmarshalMap(result.Item, &portfolio); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal portfolio: %w", err)
+	}
+
+	return &portfolio, nil
+}
+
+// ListPortfolios retrieves all portfolios
+func (r *portfolioRepository) ListPortfolios(ctx context.Context) ([]Portfolio, error) {
+	var portfolios []Portfolio
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan portfolios: %w", err)
+		}
+
+		var batch []Portfolio
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal portfolios: %w", err)
+		}
+
+		portfolios = append(portfolios, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return portfolios, nil
+}
+
+// PutPortfolio creates or replaces a portfolio
+func (r *portfolioRepository) PutPortfolio(ctx context.Context, portfolio *Portfolio) error {
+	item, err := attributevalue.MarshalMap(portfolio)
+	if err != nil {
+		return fmt.Errorf("failed to marshal portfolio: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put portfolio %s: %w", portfolio.ID, err)
+	}
+
+	return nil
+}
+
+// PutTransaction stores a transaction
+func (r *portfolioRepository) PutTransaction(ctx context.Context, transaction *Transaction) error {
+	item, err := attributevalue.MarshalMap(transaction)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.transactionsTable),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put transaction for portfolio %s: %w", transaction.PortfolioID, err)
+	}
+
+	return nil
+}
+
+// GetTransactions retrieves the transactions of a portfolio with timestamps in [from, to], oldest first
+func (r *portfolioRepository) GetTransactions(ctx context.Context, portfolioID string, from, to int64) ([]Transaction, error) {
+	// IDs start with their timestamp, so IDs at to+1 sort after the upper bound
+	keyCond := expression.Key("portfolioId").Equal(expression.Value(portfolioID)).
+		And(expression.Key("id").Between(
+			expression.Value(TransactionIDPrefix(from)),
+			expression.Value(TransactionIDPrefix(to+1)),
+		))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var transactions []Transaction
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.transactionsTable),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			ConsistentRead:            aws.Bool(repository.ConsistentRead(ctx)),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query transactions for portfolio %s: %w", portfolioID, err)
+		}
+
+		var batch []Transaction
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal transactions: %w", err)
+		}
+
+		transactions = append(transactions, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return transactions, nil
+}
+
+// portfolioPurger deletes the portfolios an API key owns with their
+// transactions
+type portfolioPurger struct {
+	client            *dynamodb.Client
+	tableName         string
+	transactionsTable string
+}
+
+// NewPortfolioPurger creates a purger of the portfolios API keys own
+func NewPortfolioPurger(client *dynamodb.Client, tableName, transactionsTable string) service.AccountPurger {
+	return &portfolioPurger{
+		client:            client,
+		tableName:         tableName,
+		transactionsTable: transactionsTable,
+	}
+}
+
+// PurgeAccount deletes the transactions of every portfolio owned by keyID,
+// then the portfolios, so a failed purge is picked up again by the next one.
+// It returns how many items were deleted.
+func (p *portfolioPurger) PurgeAccount(ctx context.Context, keyID string) (int, error) {
+	ids, err := ownedIDs(ctx, p.client, p.tableName, keyID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan portfolios: %w", err)
+	}
+
+	deleted := 0
+	for _, id := range ids {
+		keyCond := expression.Key("portfolioId").Equal(expression.Value(id))
+		txProj := expression.NamesList(expression.Name("portfolioId"), expression.Name("id"))
+		txExpr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithProjection(txProj).Build()
+		if err != nil {
+			return deleted, fmt.Errorf("failed to build expression: %w", err)
+		}
+
+		n, err := repository.QueryDelete(ctx, p.client, &dynamodb.QueryInput{
+			TableName:                 aws.String(p.transactionsTable),
+			KeyConditionExpression:    txExpr.KeyCondition(),
+			ProjectionExpression:      txExpr.Projection(),
+			ExpressionAttributeNames:  txExpr.Names(),
+			ExpressionAttributeValues: txExpr.Values(),
+		}, nil)
+		deleted += n
+		if err != nil {
+			return deleted, fmt.Errorf("failed to delete transactions of portfolio %s: %w", id, err)
+		}
+	}
+
+	n, err := repository.NewOwnedItemPurger(p.client, p.tableName).PurgeAccount(ctx, keyID)
+	return deleted + n, err
+}
+
+// ownedIDs returns the IDs of the items keyID owns in a table keyed by "id"
+func ownedIDs(ctx context.Context, client *dynamodb.Client, tableName, keyID string) ([]string, error) {
+	filter := expression.Name("keyId").Equal(expression.Value(keyID))
+	proj := expression.NamesList(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(proj).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var ids []string
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(tableName),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	for {
+		result, err := client.Scan(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []struct {
+			ID string `dynamodbav:"id"`
+		}
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal items: %w", err)
+		}
+		for _, item := range batch {
+			ids = append(ids, item.ID)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return ids, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
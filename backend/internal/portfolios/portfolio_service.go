@@ -0,0 +1,340 @@
+package portfolios
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// quantityEpsilon absorbs floating point error when comparing share quantities
+const quantityEpsilon = 1e-9
+
+var (
+	ErrPortfolioNotFound  = errors.New("portfolio not found")
+	ErrInvalidPortfolio   = errors.New("invalid portfolio")
+	ErrInvalidTransaction = errors.New("invalid transaction")
+)
+
+type PortfolioService interface {
+	CreatePortfolio(ctx context.Context, portfolio *Portfolio) (*Portfolio, error)
+	GetPortfolio(ctx context.Context, id string) (*Portfolio, error)
+	ListPortfolios(ctx context.Context) ([]Portfolio, error)
+	RecordTransaction(ctx context.Context, transaction *Transaction) (*Transaction, error)
+	GetTransactions(ctx context.Context, id string, from, to int64) ([]Transaction, error)
+	GetPositions(ctx context.Context, id string) (*PortfolioPositions, error)
+}
+
+type portfolioService struct {
+	repo   PortfolioRepository
+	quotes service.DailySummaryService
+	events events.Publisher
+	log    *zap.SugaredLogger
+}
+
+// NewPortfolioService publishes a PortfolioTransactionRecorded event for every
+// transaction recorded; a nil publisher publishes none
+func NewPortfolioService(repo PortfolioRepository, quotes service.DailySummaryService, publisher events.Publisher, log *zap.SugaredLogger) PortfolioService {
+	return &portfolioService{
+		repo:   repo,
+		quotes: quotes,
+		events: publisher,
+		log:    log,
+	}
+}
+
+func (s *portfolioService) CreatePortfolio(ctx context.Context, portfolio *Portfolio) (*Portfolio, error) {
+	created := *portfolio
+	created.Name = strings.TrimSpace(created.Name)
+	created.Currency = strings.ToUpper(strings.TrimSpace(created.Currency))
+	if err := created.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPortfolio, err)
+	}
+
+	id, err := service.NewID()
+	if err != nil {
+		return nil, err
+	}
+	created.ID = id
+	created.CreatedUTC = time.Now().Unix()
+	created.KeyID = service.CallerKeyID(ctx)
+
+	if err := s.repo.PutPortfolio(ctx, &created); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to create portfolio", "name", created.Name, "error", err)
+		return nil, fmt.Errorf("failed to create portfolio: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created portfolio", "portfolio", id, "name", created.Name)
+	return &created, nil
+}
+
+func (s *portfolioService) GetPortfolio(ctx context.Context, id string) (*Portfolio, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidPortfolio)
+	}
+
+	portfolio, err := s.repo.GetPortfolio(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrPortfolioNotFound) {
+			return nil, ErrPortfolioNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get portfolio", "portfolio", id, "error", err)
+		return nil, fmt.Errorf("failed to get portfolio: %w", err)
+	}
+	// Another key's portfolio is reported as missing rather than forbidden
+	if portfolio.KeyID != service.CallerKeyID(ctx) {
+		return nil, ErrPortfolioNotFound
+	}
+
+	return portfolio, nil
+}
+
+// ListPortfolios returns the caller's portfolios, sorted by name
+func (s *portfolioService) ListPortfolios(ctx context.Context) ([]Portfolio, error) {
+	portfolios, err := listOwnPortfolios(ctx, s.repo)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list portfolios", "error", err)
+		return nil, fmt.Errorf("failed to list portfolios: %w", err)
+	}
+
+	sort.Slice(portfolios, func(i, j int) bool {
+		return portfolios[i].Name < portfolios[j].Name
+	})
+	return portfolios, nil
+}
+
+// RecordTransaction stores a buy or sell. Transactions may be backdated, but a
+// sell must not exceed the quantity held at any point of the history.
+func (s *portfolioService) RecordTransaction(ctx context.Context, transaction *Transaction) (*Transaction, error) {
+	now := time.Now().Unix()
+
+	recorded := *transaction
+	recorded.Symbol = strings.ToUpper(strings.TrimSpace(recorded.Symbol))
+	recorded.Type = TransactionType(strings.ToLower(string(recorded.Type)))
+	if recorded.Timestamp == 0 {
+		recorded.Timestamp = now
+	}
+	if err := recorded.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTransaction, err)
+	}
+	if recorded.Timestamp > now {
+		return nil, fmt.Errorf("%w: timestamp cannot be in the future", ErrInvalidTransaction)
+	}
+
+	if _, err := s.GetPortfolio(ctx, recorded.PortfolioID); err != nil {
+		return nil, err
+	}
+
+	id, err := service.NewID()
+	if err != nil {
+		return nil, err
+	}
+	recorded.ID = TransactionIDPrefix(recorded.Timestamp) + "-" + id[:8]
+	recorded.CreatedUTC = now
+
+	if recorded.Type == TransactionSell {
+		// The history must include transactions recorded just before this one
+		history, err := s.transactions(repository.WithConsistentRead(ctx), recorded.PortfolioID, 0, now)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := replayHoldings(insertTransaction(history, recorded)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.PutTransaction(ctx, &recorded); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to record transaction", "portfolio", recorded.PortfolioID, "error", err)
+		return nil, fmt.Errorf("failed to record transaction: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("recorded transaction",
+		"portfolio", recorded.PortfolioID,
+		"symbol", recorded.Symbol,
+		"type", recorded.Type,
+		"quantity", recorded.Quantity,
+	)
+	service.PublishEvent(ctx, s.events, s.log, models.EventTransactionRecorded, models.EventTransactionRecordedVersion, models.TransactionRecordedEvent{
+		PortfolioID:   recorded.PortfolioID,
+		TransactionID: recorded.ID,
+		KeyID:         service.CallerKeyID(ctx),
+		Symbol:        recorded.Symbol,
+		Type:          string(recorded.Type),
+		Quantity:      recorded.Quantity,
+		Price:         recorded.Price,
+		Fee:           recorded.Fee,
+		Timestamp:     recorded.Timestamp,
+		CreatedUTC:    recorded.CreatedUTC,
+	})
+	return &recorded, nil
+}
+
+// GetTransactions returns the transactions of a portfolio with timestamps in
+// [from, to], oldest first. A zero to means now.
+func (s *portfolioService) GetTransactions(ctx context.Context, id string, from, to int64) ([]Transaction, error) {
+	if _, err := s.GetPortfolio(ctx, id); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if to == 0 || to > now {
+		to = now
+	}
+	if from > to {
+		return nil, fmt.Errorf("%w: from must not be after to", service.ErrInvalidRange)
+	}
+
+	return s.transactions(ctx, id, from, to)
+}
+
+// GetPositions returns the open holdings of a portfolio, valued at the latest
+// daily close of each security
+func (s *portfolioService) GetPositions(ctx context.Context, id string) (*PortfolioPositions, error) {
+	if _, err := s.GetPortfolio(ctx, id); err != nil {
+		return nil, err
+	}
+
+	history, err := s.transactions(ctx, id, 0, time.Now().Unix())
+	if err != nil {
+		return nil, err
+	}
+	holdings, err := replayHoldings(history)
+	if err != nil {
+		// Stored histories are validated as they are recorded
+		logger.FromContext(ctx, s.log).Errorw("inconsistent transaction history", "portfolio", id, "error", err)
+		return nil, fmt.Errorf("failed to compute holdings: %w", err)
+	}
+
+	positions := &PortfolioPositions{
+		PortfolioID: id,
+		Holdings:    make([]Holding, 0, len(holdings)),
+	}
+	var symbols []string
+	for _, h := range holdings {
+		positions.RealizedPL += h.RealizedPL
+		if h.Quantity > quantityEpsilon {
+			symbols = append(symbols, h.Symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	quotes, errs := service.LatestQuotes(ctx, s.quotes, symbols)
+	for i, symbol := range symbols {
+		holding := *holdings[symbol]
+		switch {
+		case errs[i] == nil:
+			valueHolding(&holding, quotes[i])
+			positions.MarketValue += *holding.MarketValue
+			positions.UnrealizedPL += *holding.UnrealizedPL
+		case errors.Is(errs[i], service.ErrTickerNotFound):
+			// Unpriced holdings are reported at cost only
+		default:
+			return nil, fmt.Errorf("failed to get quote for %s: %w", symbol, errs[i])
+		}
+		positions.CostBasis += holding.CostBasis
+		positions.Holdings = append(positions.Holdings, holding)
+	}
+
+	return positions, nil
+}
+
+func (s *portfolioService) transactions(ctx context.Context, id string, from, to int64) ([]Transaction, error) {
+	transactions, err := s.repo.GetTransactions(ctx, id, from, to)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get transactions", "portfolio", id, "error", err)
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+	return transactions, nil
+}
+
+// insertTransaction returns history, which is in ID order, with t inserted in order
+func insertTransaction(history []Transaction, t Transaction) []Transaction {
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].ID > t.ID
+	})
+	out := make([]Transaction, 0, len(history)+1)
+	out = append(out, history[:i]...)
+	out = append(out, t)
+	return append(out, history[i:]...)
+}
+
+// replayHoldings applies transactions, oldest first, at average cost. Fees add
+// to the cost of buys and reduce the proceeds of sells. Closed positions are
+// kept for their realized P&L.
+func replayHoldings(transactions []Transaction) (map[string]*Holding, error) {
+	holdings := make(map[string]*Holding)
+	for _, t := range transactions {
+		h, ok := holdings[t.Symbol]
+		if !ok {
+			h = &Holding{Symbol: t.Symbol}
+			holdings[t.Symbol] = h
+		}
+
+		switch t.Type {
+		case TransactionBuy:
+			h.Quantity += t.Quantity
+			h.CostBasis += t.Quantity*t.Price + t.Fee
+		case TransactionSell:
+			// Nothing held, as after a full sale, would divide the cost by zero
+			if h.Quantity <= quantityEpsilon || t.Quantity > h.Quantity+quantityEpsilon {
+				return nil, fmt.Errorf("%w: selling %g %s exceeds the %g held at %d",
+					ErrInvalidTransaction, t.Quantity, t.Symbol, h.Quantity, t.Timestamp)
+			}
+			cost := h.CostBasis * t.Quantity / h.Quantity
+			h.RealizedPL += t.Quantity*t.Price - t.Fee - cost
+			h.CostBasis -= cost
+			h.Quantity -= t.Quantity
+			if h.Quantity <= quantityEpsilon {
+				h.Quantity, h.CostBasis = 0, 0
+			}
+		}
+
+		h.AverageCost = 0
+		if h.Quantity > 0 {
+			h.AverageCost = h.CostBasis / h.Quantity
+		}
+	}
+	return holdings, nil
+}
+
+// valueHolding sets the market fields of h from the latest quote of its security
+func valueHolding(h *Holding, quote *models.Quote) {
+	lastClose := float64(quote.Close)
+	marketValue := h.Quantity * lastClose
+	unrealized := marketValue - h.CostBasis
+
+	h.LastClose = &lastClose
+	h.LastCloseUTC = quote.Timestamp
+	h.MarketValue = &marketValue
+	h.UnrealizedPL = &unrealized
+	if h.CostBasis > 0 {
+		percent := unrealized / h.CostBasis * 100
+		h.UnrealizedPLPercent = &percent
+	}
+}
+
+// listOwnPortfolios returns the portfolios of the calling key
+func listOwnPortfolios(ctx context.Context, repo PortfolioRepository) ([]Portfolio, error) {
+	all, err := repo.ListPortfolios(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	keyID := service.CallerKeyID(ctx)
+	portfolios := make([]Portfolio, 0, len(all))
+	for _, portfolio := range all {
+		if portfolio.KeyID == keyID {
+			portfolios = append(portfolios, portfolio)
+		}
+	}
+	return portfolios, nil
+}
@@ -0,0 +1,334 @@
+package portfolios
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockPortfolioRepository mocks the PortfolioRepository interface
+type MockPortfolioRepository struct {
+	mock.Mock
+}
+
+func (m *MockPortfolioRepository) GetPortfolio(ctx context.Context, id string) (*Portfolio, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Portfolio), args.Error(1)
+}
+
+func (m *MockPortfolioRepository) ListPortfolios(ctx context.Context) ([]Portfolio, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Portfolio), args.Error(1)
+}
+
+func (m *MockPortfolioRepository) PutPortfolio(ctx context.Context, portfolio *Portfolio) error {
+	return m.Called(ctx, portfolio).Error(0)
+}
+
+func (m *MockPortfolioRepository) PutTransaction(ctx context.Context, transaction *Transaction) error {
+	return m.Called(ctx, transaction).Error(0)
+}
+
+func (m *MockPortfolioRepository) GetTransactions(ctx context.Context, portfolioID string, from, to int64) ([]Transaction, error) {
+	args := m.Called(ctx, portfolioID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Transaction), args.Error(1)
+}
+
+func transaction(ts int64, symbol string, side TransactionType, quantity, price, fee float64) Transaction {
+	return Transaction{
+		PortfolioID: "p1",
+		ID:          TransactionIDPrefix(ts) + "-00000000",
+		Symbol:      symbol,
+		Type:        side,
+		Quantity:    quantity,
+		Price:       price,
+		Fee:         fee,
+		Timestamp:   ts,
+	}
+}
+
+func TestReplayHoldings(t *testing.T) {
+	holdings, err := replayHoldings([]Transaction{
+		transaction(1, "AAPL", TransactionBuy, 10, 100, 5),
+		transaction(2, "AAPL", TransactionBuy, 10, 110, 5),
+		transaction(3, "AAPL", TransactionSell, 5, 120, 2),
+		transaction(4, "MSFT", TransactionBuy, 1, 400, 0),
+		transaction(5, "MSFT", TransactionSell, 1, 390, 0),
+	})
+	require.NoError(t, err)
+
+	aapl := holdings["AAPL"]
+	assert.InDelta(t, 15, aapl.Quantity, 1e-9)
+	assert.InDelta(t, 105.5, aapl.AverageCost, 1e-9)
+	assert.InDelta(t, 1582.5, aapl.CostBasis, 1e-9)
+	assert.InDelta(t, 5*120-2-5*105.5, aapl.RealizedPL, 1e-9)
+
+	msft := holdings["MSFT"]
+	assert.Zero(t, msft.Quantity)
+	assert.Zero(t, msft.CostBasis)
+	assert.InDelta(t, -10, msft.RealizedPL, 1e-9)
+
+	_, err = replayHoldings([]Transaction{
+		transaction(1, "AAPL", TransactionBuy, 10, 100, 0),
+		transaction(2, "AAPL", TransactionSell, 11, 100, 0),
+	})
+	assert.ErrorIs(t, err, ErrInvalidTransaction)
+
+	_, err = replayHoldings([]Transaction{
+		transaction(1, "AAPL", TransactionBuy, 10, 100, 0),
+		transaction(2, "AAPL", TransactionSell, 10, 100, 0),
+		transaction(3, "AAPL", TransactionSell, 1e-10, 100, 0),
+	})
+	assert.ErrorIs(t, err, ErrInvalidTransaction, "selling out of a closed position is rejected rather than priced at NaN")
+}
+
+// history is a random valid transaction history of up to three symbols,
+// generated for property tests. Sells are of a part of the holding or all of it.
+type history []Transaction
+
+func (history) Generate(r *rand.Rand, size int) reflect.Value {
+	symbols := []string{"AAPL", "MSFT", "NVDA"}
+	held := make(map[string]float64)
+	var h history
+	for i := range 1 + r.Intn(40) {
+		symbol := symbols[r.Intn(len(symbols))]
+		price := float64(1+r.Intn(100000)) / 100
+		fee := float64(r.Intn(1000)) / 100
+		switch {
+		case held[symbol] > 0.01 && r.Intn(3) == 0:
+			quantity := held[symbol]
+			if r.Intn(2) == 0 {
+				quantity = math.Round(quantity*r.Float64()*100) / 100
+			}
+			if quantity <= 0 {
+				continue
+			}
+			held[symbol] -= quantity
+			h = append(h, transaction(int64(i+1), symbol, TransactionSell, quantity, price, fee))
+		default:
+			quantity := float64(1+r.Intn(10000)) / 100
+			held[symbol] += quantity
+			h = append(h, transaction(int64(i+1), symbol, TransactionBuy, quantity, price, fee))
+		}
+	}
+	return reflect.ValueOf(h)
+}
+
+// closeTo compares money amounts, which are summed from many transactions
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-6*(1+math.Abs(a)+math.Abs(b))
+}
+
+func TestReplayHoldingsProperties(t *testing.T) {
+	t.Run("realized P&L and cost basis account for every cash flow", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(h history) bool {
+			holdings, err := replayHoldings(h)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+			cash := make(map[string]float64)
+			for _, tx := range h {
+				if tx.Type == TransactionBuy {
+					cash[tx.Symbol] -= tx.Quantity*tx.Price + tx.Fee
+				} else {
+					cash[tx.Symbol] += tx.Quantity*tx.Price - tx.Fee
+				}
+			}
+			for symbol, holding := range holdings {
+				if holding.Quantity < 0 || holding.CostBasis < -1e-9 || !closeTo(holding.RealizedPL-holding.CostBasis, cash[symbol]) {
+					t.Logf("%s: %+v against cash flows of %g", symbol, *holding, cash[symbol])
+					return false
+				}
+			}
+			return true
+		}, nil))
+	})
+
+	// A split restates the whole history in post-split shares, multiplying
+	// quantities and dividing prices by its ratio
+	t.Run("split restatement", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(h history, to, from uint8) bool {
+			ratio := float64(1+to%10) / float64(1+from%10)
+			split := make([]Transaction, len(h))
+			for i, tx := range h {
+				tx.Quantity *= ratio
+				tx.Price /= ratio
+				split[i] = tx
+			}
+
+			want, err := replayHoldings(h)
+			if err != nil {
+				return false
+			}
+			got, err := replayHoldings(split)
+			if err != nil {
+				t.Log(err)
+				return false
+			}
+			for symbol, w := range want {
+				g := got[symbol]
+				if !closeTo(g.Quantity, w.Quantity*ratio) || !closeTo(g.CostBasis, w.CostBasis) ||
+					!closeTo(g.AverageCost*ratio, w.AverageCost) || !closeTo(g.RealizedPL, w.RealizedPL) {
+					t.Logf("%s split %g: %+v, want %+v", symbol, ratio, *g, *w)
+					return false
+				}
+			}
+			return true
+		}, nil))
+	})
+}
+
+func TestPortfolioService_ScopesPortfoliosToTheCallingKey(t *testing.T) {
+	as := func(keyID string) context.Context {
+		return service.WithAccount(context.Background(), &models.APIKey{ID: keyID})
+	}
+
+	repo := new(MockPortfolioRepository)
+	repo.On("ListPortfolios", mock.Anything).Return([]Portfolio{
+		{ID: "p1", Name: "Core", KeyID: "alice"},
+		{ID: "p2", Name: "Growth", KeyID: "bob"},
+	}, nil)
+	repo.On("GetPortfolio", mock.Anything, "p2").Return(&Portfolio{ID: "p2", KeyID: "bob"}, nil)
+	repo.On("PutPortfolio", mock.Anything, mock.Anything).Return(nil)
+	svc := NewPortfolioService(repo, nil, nil, zap.NewNop().Sugar())
+
+	portfolios, err := svc.ListPortfolios(as("alice"))
+	require.NoError(t, err)
+	require.Len(t, portfolios, 1)
+	assert.Equal(t, "p1", portfolios[0].ID)
+
+	_, err = svc.GetPortfolio(as("alice"), "p2")
+	assert.ErrorIs(t, err, ErrPortfolioNotFound, "another key's portfolio is not found")
+	_, err = svc.GetPortfolio(as("bob"), "p2")
+	assert.NoError(t, err)
+
+	created, err := svc.CreatePortfolio(as("alice"), &Portfolio{Name: "New", Currency: "usd"})
+	require.NoError(t, err)
+	assert.Equal(t, "alice", created.KeyID)
+}
+
+func TestPortfolioService_RecordTransaction(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Unix()
+	history := []Transaction{
+		transaction(now-300, "AAPL", TransactionBuy, 10, 100, 0),
+		transaction(now-100, "AAPL", TransactionSell, 8, 110, 0),
+	}
+
+	newService := func() (*MockPortfolioRepository, PortfolioService, *eventstest.RecordingPublisher) {
+		repo := new(MockPortfolioRepository)
+		repo.On("GetPortfolio", mock.Anything, "p1").Return(&Portfolio{ID: "p1"}, nil)
+		repo.On("GetPortfolio", mock.Anything, "missing").Return(nil, fmt.Errorf("%w: %s", ErrPortfolioNotFound, "missing"))
+		repo.On("GetTransactions", mock.Anything, "p1", int64(0), mock.Anything).Return(history, nil)
+		repo.On("PutTransaction", mock.Anything, mock.Anything).Return(nil)
+		publisher := &eventstest.RecordingPublisher{}
+		return repo, NewPortfolioService(repo, service.NewDailySummaryService(new(repository.MockDailySummaryRepository), zap.NewNop().Sugar()), publisher, zap.NewNop().Sugar()), publisher
+	}
+
+	t.Run("normalizes and stores a sell within the position", func(t *testing.T) {
+		repo, svc, publisher := newService()
+		recorded, err := svc.RecordTransaction(ctx, &Transaction{PortfolioID: "p1", Symbol: "aapl", Type: "SELL", Quantity: 2, Price: 120})
+		require.NoError(t, err)
+		assert.Equal(t, "AAPL", recorded.Symbol)
+		assert.Equal(t, TransactionSell, recorded.Type)
+		assert.Contains(t, recorded.ID, TransactionIDPrefix(recorded.Timestamp)+"-")
+		repo.AssertCalled(t, "PutTransaction", mock.Anything, mock.Anything)
+
+		require.Len(t, publisher.Events(), 1)
+		assert.Equal(t, models.EventTransactionRecorded, publisher.Events()[0].Type)
+		assert.Equal(t, models.TransactionRecordedEvent{
+			PortfolioID:   "p1",
+			TransactionID: recorded.ID,
+			Symbol:        "AAPL",
+			Type:          "sell",
+			Quantity:      2,
+			Price:         120,
+			Timestamp:     recorded.Timestamp,
+			CreatedUTC:    recorded.CreatedUTC,
+		}, publisher.Events()[0].Data)
+	})
+
+	t.Run("rejects a backdated sell the position did not cover", func(t *testing.T) {
+		repo, svc, publisher := newService()
+		_, err := svc.RecordTransaction(ctx, &Transaction{PortfolioID: "p1", Symbol: "AAPL", Type: "sell", Quantity: 5, Price: 120, Timestamp: now - 50})
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+
+		// 10 were held before the later sell of 8, which would then oversell
+		_, err = svc.RecordTransaction(ctx, &Transaction{PortfolioID: "p1", Symbol: "AAPL", Type: "sell", Quantity: 5, Price: 120, Timestamp: now - 200})
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+		repo.AssertNotCalled(t, "PutTransaction", mock.Anything, mock.Anything)
+		assert.Empty(t, publisher.Events(), "rejected transactions are not published")
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		_, svc, _ := newService()
+		_, err := svc.RecordTransaction(ctx, &Transaction{PortfolioID: "p1", Symbol: "AAPL", Type: "hold", Quantity: 1})
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+		_, err = svc.RecordTransaction(ctx, &Transaction{PortfolioID: "p1", Symbol: "AAPL", Type: "buy", Quantity: 1, Timestamp: now + 3600})
+		assert.ErrorIs(t, err, ErrInvalidTransaction)
+		_, err = svc.RecordTransaction(ctx, &Transaction{PortfolioID: "missing", Symbol: "AAPL", Type: "buy", Quantity: 1})
+		assert.ErrorIs(t, err, ErrPortfolioNotFound)
+	})
+}
+
+func TestPortfolioService_GetPositions(t *testing.T) {
+	repo := new(MockPortfolioRepository)
+	repo.On("GetPortfolio", mock.Anything, "p1").Return(&Portfolio{ID: "p1"}, nil)
+	repo.On("GetTransactions", mock.Anything, "p1", int64(0), mock.Anything).Return([]Transaction{
+		transaction(1, "AAPL", TransactionBuy, 10, 100, 0),
+		transaction(2, "PRIV", TransactionBuy, 5, 20, 0),
+		transaction(3, "MSFT", TransactionBuy, 1, 400, 0),
+		transaction(4, "MSFT", TransactionSell, 1, 450, 0),
+	}, nil)
+
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 100, Close: 120},
+	}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "PRIV", mock.Anything, int32(2)).Return([]models.DailySummary{}, nil)
+
+	log := zap.NewNop().Sugar()
+	positions, err := NewPortfolioService(repo, service.NewDailySummaryService(summaries, log), nil, log).GetPositions(context.Background(), "p1")
+	require.NoError(t, err)
+
+	require.Len(t, positions.Holdings, 2, "closed positions are left out")
+	aapl := positions.Holdings[0]
+	assert.Equal(t, "AAPL", aapl.Symbol)
+	assert.Equal(t, 1200.0, *aapl.MarketValue)
+	assert.Equal(t, 200.0, *aapl.UnrealizedPL)
+	assert.InDelta(t, 20.0, *aapl.UnrealizedPLPercent, 1e-9)
+	assert.Equal(t, int64(100), aapl.LastCloseUTC)
+
+	priv := positions.Holdings[1]
+	assert.Nil(t, priv.MarketValue, "holdings without a close are reported at cost")
+
+	assert.Equal(t, 1100.0, positions.CostBasis)
+	assert.Equal(t, 1200.0, positions.MarketValue)
+	assert.Equal(t, 200.0, positions.UnrealizedPL)
+	assert.Equal(t, 50.0, positions.RealizedPL)
+}
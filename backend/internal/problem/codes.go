@@ -0,0 +1,122 @@
+package problem
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Code identifies a problem to clients; each code answers with one status
+type Code string
+
+// Codes answered by the API. Clients branch on these rather than on the
+// human-readable detail.
+const (
+	// ValidationFailed rejects parameters or body fields with invalid values
+	ValidationFailed Code = "VALIDATION_FAILED"
+	// MalformedBody rejects bodies that are not the expected JSON
+	MalformedBody Code = "MALFORMED_BODY"
+	// Unauthenticated rejects requests without a valid API key, session or
+	// signature
+	Unauthenticated Code = "UNAUTHENTICATED"
+	// UpgradeRequired rejects requests a higher plan tier allows
+	UpgradeRequired Code = "UPGRADE_REQUIRED"
+	// Forbidden rejects requests the caller's key may not make, and mailed
+	// links with an invalid token
+	Forbidden Code = "FORBIDDEN"
+	// PlanLimitExceeded rejects requests no plan tier allows
+	PlanLimitExceeded Code = "PLAN_LIMIT_EXCEEDED"
+	// TermsNotAccepted rejects account requests until the key's holder
+	// accepted the current terms
+	TermsNotAccepted Code = "TERMS_NOT_ACCEPTED"
+
+	NotFound          Code = "NOT_FOUND"
+	TickerNotFound    Code = "TICKER_NOT_FOUND"
+	PortfolioNotFound Code = "PORTFOLIO_NOT_FOUND"
+	AssetNotFound     Code = "ASSET_NOT_FOUND"
+	WatchlistNotFound Code = "WATCHLIST_NOT_FOUND"
+	AlertNotFound     Code = "ALERT_NOT_FOUND"
+	DigestNotFound    Code = "DIGEST_NOT_FOUND"
+	DeviceNotFound    Code = "DEVICE_NOT_FOUND"
+	SessionNotFound   Code = "SESSION_NOT_FOUND"
+	APIKeyNotFound    Code = "API_KEY_NOT_FOUND"
+	SettingNotFound   Code = "SETTING_NOT_FOUND"
+	JobNotFound       Code = "JOB_NOT_FOUND"
+	// HeldSummaryNotFound answers reviews of daily summaries no longer held
+	HeldSummaryNotFound Code = "HELD_SUMMARY_NOT_FOUND"
+
+	// Conflict rejects writes that conflict with the stored state or with
+	// work already running
+	Conflict     Code = "CONFLICT"
+	TickerExists Code = "TICKER_EXISTS"
+
+	// SyncCursorExpired rejects sync cursors older than the change log's
+	// retention; the client syncs from scratch
+	SyncCursorExpired Code = "SYNC_CURSOR_EXPIRED"
+
+	PayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+	// ResponseTooLarge rejects list requests whose response would exceed the
+	// configured size; the client narrows the query
+	ResponseTooLarge Code = "RESPONSE_TOO_LARGE"
+	// RateLimited rejects requests over the key's or client's rate limit
+	RateLimited Code = "RATE_LIMITED"
+	// QueueFull rejects work while its queue is full
+	QueueFull Code = "QUEUE_FULL"
+	// Internal answers failures of the server, which are logged with the
+	// request ID
+	Internal Code = "INTERNAL_ERROR"
+	// Unavailable answers requests of features that are not configured
+	Unavailable Code = "UNAVAILABLE"
+)
+
+var statuses = map[Code]int{
+	ValidationFailed:    http.StatusBadRequest,
+	MalformedBody:       http.StatusBadRequest,
+	Unauthenticated:     http.StatusUnauthorized,
+	UpgradeRequired:     http.StatusPaymentRequired,
+	Forbidden:           http.StatusForbidden,
+	PlanLimitExceeded:   http.StatusForbidden,
+	TermsNotAccepted:    http.StatusForbidden,
+	NotFound:            http.StatusNotFound,
+	TickerNotFound:      http.StatusNotFound,
+	PortfolioNotFound:   http.StatusNotFound,
+	AssetNotFound:       http.StatusNotFound,
+	WatchlistNotFound:   http.StatusNotFound,
+	AlertNotFound:       http.StatusNotFound,
+	DigestNotFound:      http.StatusNotFound,
+	DeviceNotFound:      http.StatusNotFound,
+	SessionNotFound:     http.StatusNotFound,
+	APIKeyNotFound:      http.StatusNotFound,
+	SettingNotFound:     http.StatusNotFound,
+	JobNotFound:         http.StatusNotFound,
+	HeldSummaryNotFound: http.StatusNotFound,
+	Conflict:            http.StatusConflict,
+	TickerExists:        http.StatusConflict,
+	SyncCursorExpired:   http.StatusGone,
+	PayloadTooLarge:     http.StatusRequestEntityTooLarge,
+	ResponseTooLarge:    http.StatusRequestEntityTooLarge,
+	RateLimited:         http.StatusTooManyRequests,
+	QueueFull:           http.StatusTooManyRequests,
+	Internal:            http.StatusInternalServerError,
+	Unavailable:         http.StatusServiceUnavailable,
+}
+
+// Status returns the HTTP status answered with the code; unknown codes are
+// internal errors
+func (c Code) Status() int {
+	if status, ok := statuses[c]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Codes returns the codes answered with status, sorted, to document them
+func Codes(status int) []Code {
+	var codes []Code
+	for code, s := range statuses {
+		if s == status {
+			codes = append(codes, code)
+		}
+	}
+	slices.Sort(codes)
+	return codes
+}
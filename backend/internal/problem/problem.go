@@ -0,0 +1,75 @@
+// Package problem writes the error responses of every handler and middleware
+// as RFC 7807 problem details, extended with a machine-readable code and the
+// request's ID.
+package problem
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// requestIDHeader is set on the response by the request ID middleware before
+// any handler runs
+const requestIDHeader = "X-Request-ID"
+
+// codeContextKey is where Respond stores the code answered on the gin context
+const codeContextKey = "problemCode"
+
+// Problem is the body of every error response
+type Problem struct {
+	// Type is "about:blank": the code identifies the problem
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail explains this occurrence of the problem to a human
+	Detail string `json:"detail,omitempty"`
+	// Instance is the path of the request
+	Instance  string `json:"instance,omitempty"`
+	Code      Code   `json:"code"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// New describes a problem with the request in c. Its status is the code's.
+func New(c *gin.Context, code Code, detail string) Problem {
+	status := code.Status()
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      code,
+		RequestID: c.Writer.Header().Get(requestIDHeader),
+	}
+}
+
+// Respond writes the problem with code as the response
+func Respond(c *gin.Context, code Code, detail string) {
+	p := New(c, code, detail)
+	c.Set(codeContextKey, code)
+	c.Header("Content-Type", ContentType)
+	// Caching policies of the route are for its successful responses
+	c.Writer.Header().Del("Cache-Control")
+	c.JSON(p.Status, p)
+}
+
+// Abort writes the problem with code as the response and stops the handler
+// chain, for middleware rejecting requests
+func Abort(c *gin.Context, code Code, detail string) {
+	c.Abort()
+	Respond(c, code, detail)
+}
+
+// CodeOf returns the code of the problem answered to the request in c, if any
+func CodeOf(c *gin.Context) (Code, bool) {
+	code, ok := c.Get(codeContextKey)
+	if !ok {
+		return "", false
+	}
+	value, ok := code.(Code)
+	return value, ok
+}
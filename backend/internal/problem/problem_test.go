@@ -0,0 +1,63 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRespond(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		c.Header(requestIDHeader, "req-1")
+	})
+	engine.GET("/api/tickers/:symbol", func(c *gin.Context) {
+		Respond(c, TickerNotFound, "Ticker not found")
+		code, ok := CodeOf(c)
+		assert.True(t, ok)
+		assert.Equal(t, TickerNotFound, code)
+	})
+	engine.GET("/api/admin", func(c *gin.Context) {
+		Abort(c, Forbidden, "Admin API key required")
+	}, func(c *gin.Context) {
+		t.Error("aborted requests do not reach later handlers")
+	})
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tickers/ZZZZ?x=1", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	var body Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, Problem{
+		Type:      "about:blank",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "Ticker not found",
+		Instance:  "/api/tickers/ZZZZ",
+		Code:      TickerNotFound,
+		RequestID: "req-1",
+	}, body)
+
+	w = httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestCode_Status(t *testing.T) {
+	for code, status := range statuses {
+		assert.NotEmpty(t, http.StatusText(status), code)
+		assert.GreaterOrEqual(t, status, 400, code)
+		assert.Contains(t, Codes(status), code)
+	}
+	assert.Equal(t, http.StatusInternalServerError, Code("UNKNOWN").Status())
+	assert.Equal(t, []Code{Forbidden, PlanLimitExceeded, TermsNotAccepted}, Codes(http.StatusForbidden), "sorted")
+}
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// APIKeyRepository defines the interface for API key data operations
+type APIKeyRepository interface {
+	GetKey(ctx context.Context, id string) (*models.APIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	PutKey(ctx context.Context, key *models.APIKey) error
+	RevokeKey(ctx context.Context, id string, at int64) error
+	TouchKey(ctx context.Context, id string, at int64) error
+	SetSigningSecret(ctx context.Context, id, secret string) error
+	SetTier(ctx context.Context, id string, tier models.PlanTier) error
+	AcceptTerms(ctx context.Context, id string, acceptance models.TermsAcceptance) error
+	MarkDeleted(ctx context.Context, id string, at int64) error
+	RestoreKey(ctx context.Context, id string) error
+	MarkPurged(ctx context.Context, id string, at int64) error
+}
+
+// apiKeyRepository implements APIKeyRepository using DynamoDB
+type apiKeyRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewAPIKeyRepository creates a new DynamoDB-backed API key repository
+func NewAPIKeyRepository(client *dynamodb.Client, tableName string) APIKeyRepository {
+	return &apiKeyRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetKey retrieves a single API key by ID
+func (r *apiKeyRepository) GetKey(ctx context.Context, id string) (*models.APIKey, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrAPIKeyNotFound{ID: id}
+	}
+
+	var key models.APIKey
+	if err := attributevalue.UnmarshalMap(result.Item, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal api key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListKeys retrieves all API keys, including revoked ones
+func (r *apiKeyRepository) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan api keys: %w", err)
+		}
+
+		var batch []models.APIKey
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal api keys: %w", err)
+		}
+
+		keys = append(keys, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return keys, nil
+}
+
+// PutKey creates or replaces an API key
+func (r *apiKeyRepository) PutKey(ctx context.Context, key *models.APIKey) error {
+	item, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal api key: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put api key: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeKey marks an existing API key as revoked at the given time
+func (r *apiKeyRepository) RevokeKey(ctx context.Context, id string, at int64) error {
+	return r.setAttribute(ctx, id, "revokedUTC", at)
+}
+
+// TouchKey records that an existing API key was used at the given time
+func (r *apiKeyRepository) TouchKey(ctx context.Context, id string, at int64) error {
+	return r.setAttribute(ctx, id, "lastUsedUTC", at)
+}
+
+// SetTier moves an existing API key to a plan tier
+func (r *apiKeyRepository) SetTier(ctx context.Context, id string, tier models.PlanTier) error {
+	return r.setAttribute(ctx, id, "tier", tier)
+}
+
+// SetSigningSecret replaces the signing secret of an existing API key
+func (r *apiKeyRepository) SetSigningSecret(ctx context.Context, id, secret string) error {
+	return r.setAttribute(ctx, id, "signingSecret", secret)
+}
+
+// AcceptTerms appends an acceptance of the terms to those of an existing API
+// key
+func (r *apiKeyRepository) AcceptTerms(ctx context.Context, id string, acceptance models.TermsAcceptance) error {
+	terms := expression.Name("terms")
+	return r.update(ctx, id, expression.Set(terms, expression.ListAppend(
+		expression.IfNotExists(terms, expression.Value([]models.TermsAcceptance{})),
+		expression.Value([]models.TermsAcceptance{acceptance}),
+	)))
+}
+
+// MarkDeleted records that the account of an existing API key was deleted at
+// the given time
+func (r *apiKeyRepository) MarkDeleted(ctx context.Context, id string, at int64) error {
+	return r.setAttribute(ctx, id, "deletedUTC", at)
+}
+
+// RestoreKey undoes the deletion of an existing API key's account
+func (r *apiKeyRepository) RestoreKey(ctx context.Context, id string) error {
+	return r.update(ctx, id, expression.Remove(expression.Name("deletedUTC")))
+}
+
+// MarkPurged records that the data of an existing API key's deleted account
+// was purged at the given time
+func (r *apiKeyRepository) MarkPurged(ctx context.Context, id string, at int64) error {
+	return r.setAttribute(ctx, id, "purgedUTC", at)
+}
+
+// setAttribute sets an attribute of an existing key
+func (r *apiKeyRepository) setAttribute(ctx context.Context, id, attribute string, value any) error {
+	return r.update(ctx, id, expression.Set(expression.Name(attribute), expression.Value(value)))
+}
+
+// update applies an update to an existing key
+func (r *apiKeyRepository) update(ctx context.Context, id string, update expression.UpdateBuilder) error {
+	cond := expression.AttributeExists(expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrAPIKeyNotFound{ID: id}
+		}
+		return fmt.Errorf("failed to update api key: %w", err)
+	}
+
+	return nil
+}
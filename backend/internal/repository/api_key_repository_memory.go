@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"profitify-backend/internal/models"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// memoryAPIKeyRepository implements APIKeyRepository in process memory
+type memoryAPIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[string]models.APIKey
+}
+
+// NewMemoryAPIKeyRepository creates an empty API key repository in process
+// memory. Keys are copied in and out, so callers never share them.
+func NewMemoryAPIKeyRepository() APIKeyRepository {
+	return &memoryAPIKeyRepository{keys: make(map[string]models.APIKey)}
+}
+
+// GetKey retrieves a single API key by ID
+func (r *memoryAPIKeyRepository) GetKey(ctx context.Context, id string) (*models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound{ID: id}
+	}
+	return &key, nil
+}
+
+// ListKeys retrieves all API keys, including revoked ones, ordered by ID
+func (r *memoryAPIKeyRepository) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := make([]models.APIKey, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].ID < keys[j].ID })
+	return keys, nil
+}
+
+// PutKey creates or replaces an API key
+func (r *memoryAPIKeyRepository) PutKey(ctx context.Context, key *models.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.ID] = *key
+	return nil
+}
+
+// RevokeKey marks an existing API key as revoked at the given time
+func (r *memoryAPIKeyRepository) RevokeKey(ctx context.Context, id string, at int64) error {
+	return r.update(id, func(key *models.APIKey) { key.RevokedUTC = at })
+}
+
+// TouchKey records that an existing API key was used at the given time
+func (r *memoryAPIKeyRepository) TouchKey(ctx context.Context, id string, at int64) error {
+	return r.update(id, func(key *models.APIKey) { key.LastUsedUTC = at })
+}
+
+// SetTier moves an existing API key to a plan tier
+func (r *memoryAPIKeyRepository) SetTier(ctx context.Context, id string, tier models.PlanTier) error {
+	return r.update(id, func(key *models.APIKey) { key.Tier = tier })
+}
+
+// SetSigningSecret replaces the signing secret of an existing API key
+func (r *memoryAPIKeyRepository) SetSigningSecret(ctx context.Context, id, secret string) error {
+	return r.update(id, func(key *models.APIKey) { key.SigningSecret = secret })
+}
+
+// AcceptTerms appends an acceptance of the terms to those of an existing API
+// key
+func (r *memoryAPIKeyRepository) AcceptTerms(ctx context.Context, id string, acceptance models.TermsAcceptance) error {
+	// Clipped so the append never writes to a slice handed out by GetKey
+	return r.update(id, func(key *models.APIKey) { key.Terms = append(slices.Clip(key.Terms), acceptance) })
+}
+
+// MarkDeleted records that the account of an existing API key was deleted at
+// the given time
+func (r *memoryAPIKeyRepository) MarkDeleted(ctx context.Context, id string, at int64) error {
+	return r.update(id, func(key *models.APIKey) { key.DeletedUTC = at })
+}
+
+// RestoreKey undoes the deletion of an existing API key's account
+func (r *memoryAPIKeyRepository) RestoreKey(ctx context.Context, id string) error {
+	return r.update(id, func(key *models.APIKey) { key.DeletedUTC = 0 })
+}
+
+// MarkPurged records that the data of an existing API key's deleted account
+// was purged at the given time
+func (r *memoryAPIKeyRepository) MarkPurged(ctx context.Context, id string, at int64) error {
+	return r.update(id, func(key *models.APIKey) { key.PurgedUTC = at })
+}
+
+// update applies fn to an existing key
+func (r *memoryAPIKeyRepository) update(id string, fn func(key *models.APIKey)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key, ok := r.keys[id]
+	if !ok {
+		return ErrAPIKeyNotFound{ID: id}
+	}
+	fn(&key)
+	r.keys[id] = key
+	return nil
+}
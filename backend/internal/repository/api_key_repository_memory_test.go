@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryAPIKeyRepository()
+
+	require.NoError(t, repo.PutKey(ctx, &models.APIKey{ID: "b", Name: "second"}))
+	require.NoError(t, repo.PutKey(ctx, &models.APIKey{ID: "a", Name: "first"}))
+
+	require.NoError(t, repo.RevokeKey(ctx, "a", 100))
+	require.NoError(t, repo.SetSigningSecret(ctx, "a", "s3cret"))
+	key, err := repo.GetKey(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, key.Revoked())
+	assert.Equal(t, "s3cret", key.SigningSecret)
+
+	require.NoError(t, repo.AcceptTerms(ctx, "a", models.TermsAcceptance{Version: "2026-01", AcceptedUTC: 200}))
+	require.NoError(t, repo.AcceptTerms(ctx, "a", models.TermsAcceptance{Version: "2026-06", AcceptedUTC: 300}))
+	key, _ = repo.GetKey(ctx, "a")
+	assert.Equal(t, []models.TermsAcceptance{{Version: "2026-01", AcceptedUTC: 200}, {Version: "2026-06", AcceptedUTC: 300}}, key.Terms)
+
+	key.Name = "changed"
+	stored, _ := repo.GetKey(ctx, "a")
+	assert.Equal(t, "first", stored.Name, "returned keys are copies")
+
+	keys, err := repo.ListKeys(ctx)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "a", keys[0].ID)
+
+	assert.Equal(t, repository.ErrAPIKeyNotFound{ID: "missing"}, repo.TouchKey(ctx, "missing", 1))
+	_, err = repo.GetKey(ctx, "missing")
+	assert.Equal(t, repository.ErrAPIKeyNotFound{ID: "missing"}, err)
+}
+
+func TestMemoryNonceRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryNonceRepository()
+
+	fresh, err := repo.Remember(ctx, "k#n1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, fresh)
+
+	fresh, err = repo.Remember(ctx, "k#n1", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, fresh, "unexpired nonces are replays")
+
+	fresh, _ = repo.Remember(ctx, "k#n2", time.Now().Add(-time.Second))
+	assert.True(t, fresh)
+	fresh, _ = repo.Remember(ctx, "k#n2", time.Now().Add(time.Minute))
+	assert.True(t, fresh, "expired nonces may be stored again")
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// BarRollupRepository defines the interface for weekly and monthly rollup
+// operations. Rollups are keyed by series, the ticker and resolution, and the
+// timestamp their period starts at.
+type BarRollupRepository interface {
+	PutRollups(ctx context.Context, rollups []models.BarRollup) error
+	GetRollups(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, error)
+}
+
+// barRollupRepository implements BarRollupRepository using DynamoDB
+type barRollupRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewBarRollupRepository creates a new DynamoDB-backed bar rollup repository
+func NewBarRollupRepository(client *dynamodb.Client, tableName string) BarRollupRepository {
+	return &barRollupRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PutRollups stores rollups, replacing those of the same series and period
+func (r *barRollupRepository) PutRollups(ctx context.Context, rollups []models.BarRollup) error {
+	requests := make([]types.WriteRequest, 0, len(rollups))
+	for i := range rollups {
+		item, err := attributevalue.MarshalMap(rollups[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal bar rollup: %w", err)
+		}
+		item["series"] = &types.AttributeValueMemberS{Value: models.RollupSeries(rollups[i].Ticker, rollups[i].Resolution)}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// GetRollups retrieves the rollups of a ticker at a resolution whose periods
+// start in [from, to], oldest first
+func (r *barRollupRepository) GetRollups(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, error) {
+	keyCond := expression.Key("series").Equal(expression.Value(models.RollupSeries(symbol, resolution))).
+		And(expression.Key("timestamp").Between(expression.Value(from), expression.Value(to)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var items []map[string]types.AttributeValue
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s rollups for %s: %w", resolution, symbol, err)
+		}
+
+		items = append(items, result.Items...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	bars := []models.AggregateBar{}
+	if err := attributevalue.UnmarshalListOfMaps(items, &bars); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bar rollups: %w", err)
+	}
+	return bars, nil
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// maxBatchWriteItems is the DynamoDB limit of items per BatchWriteItem call
+	maxBatchWriteItems = 25
+	// maxBatchRetries bounds how often unprocessed items are resubmitted
+	maxBatchRetries = 5
+)
+
+// batchWrite submits write requests in chunks of 25, resubmitting unprocessed
+// items with exponential backoff
+func batchWrite(ctx context.Context, client *dynamodb.Client, tableName string, requests []types.WriteRequest) error {
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		pending := map[string][]types.WriteRequest{tableName: requests[start:end]}
+		backoff := 50 * time.Millisecond
+
+		for attempt := 0; len(pending[tableName]) > 0; attempt++ {
+			if attempt > maxBatchRetries {
+				return fmt.Errorf("failed to write %d items to %s after %d retries", len(pending[tableName]), tableName, maxBatchRetries)
+			}
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(backoff):
+				}
+				backoff *= 2
+			}
+
+			result, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+				RequestItems: pending,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to batch write to %s: %w", tableName, err)
+			}
+			pending = result.UnprocessedItems
+		}
+	}
+
+	return nil
+}
+
+// Throttle blocks until n more items may be written. Bulk deletes call it
+// before each batch so that they stay within the table's write capacity.
+type Throttle func(ctx context.Context, n int) error
+
+// deleteKeys deletes items by primary key in throttled batches
+func deleteKeys(ctx context.Context, client *dynamodb.Client, tableName string, keys []map[string]types.AttributeValue, throttle Throttle) error {
+	for start := 0; start < len(keys); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		if throttle != nil {
+			if err := throttle(ctx, end-start); err != nil {
+				return err
+			}
+		}
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, key := range keys[start:end] {
+			requests = append(requests, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{Key: key},
+			})
+		}
+
+		if err := batchWrite(ctx, client, tableName, requests); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QueryDelete deletes every item matched by a query whose projection is the
+// table's primary key, one result page at a time. It returns the number of
+// items deleted, including those deleted before a failure. A nil throttle
+// deletes as fast as the table allows.
+func QueryDelete(ctx context.Context, client *dynamodb.Client, input *dynamodb.QueryInput, throttle Throttle) (int, error) {
+	deleted := 0
+	for {
+		result, err := client.Query(ctx, input)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to query %s for deletion: %w", aws.ToString(input.TableName), err)
+		}
+
+		if err := deleteKeys(ctx, client, aws.ToString(input.TableName), result.Items, throttle); err != nil {
+			return deleted, err
+		}
+		deleted += len(result.Items)
+
+		if result.LastEvaluatedKey == nil {
+			return deleted, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// ScanDelete is QueryDelete for tables that can only be filtered by scanning
+func ScanDelete(ctx context.Context, client *dynamodb.Client, input *dynamodb.ScanInput, throttle Throttle) (int, error) {
+	deleted := 0
+	for {
+		result, err := client.Scan(ctx, input)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to scan %s for deletion: %w", aws.ToString(input.TableName), err)
+		}
+
+		if err := deleteKeys(ctx, client, aws.ToString(input.TableName), result.Items, throttle); err != nil {
+			return deleted, err
+		}
+		deleted += len(result.Items)
+
+		if result.LastEvaluatedKey == nil {
+			return deleted, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
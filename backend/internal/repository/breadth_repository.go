@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// BreadthRepository defines the interface for market breadth data operations
+type BreadthRepository interface {
+	PutBreadth(ctx context.Context, breadth *models.MarketBreadth) error
+	GetBreadth(ctx context.Context, fromDate, toDate string) ([]models.MarketBreadth, error)
+}
+
+// breadthRepository implements BreadthRepository using DynamoDB
+type breadthRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewBreadthRepository creates a new DynamoDB-backed market breadth repository
+func NewBreadthRepository(client *dynamodb.Client, tableName string) BreadthRepository {
+	return &breadthRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PutBreadth stores the breadth of a date, replacing an earlier computation
+func (r *breadthRepository) PutBreadth(ctx context.Context, breadth *models.MarketBreadth) error {
+	item, err := attributevalue.MarshalMap(breadth)
+	if err != nil {
+		return fmt.Errorf("failed to marshal breadth: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put breadth for %s: %w", breadth.Date, err)
+	}
+
+	return nil
+}
+
+// GetBreadth retrieves the breadth of every date in [fromDate, toDate], oldest first
+func (r *breadthRepository) GetBreadth(ctx context.Context, fromDate, toDate string) ([]models.MarketBreadth, error) {
+	keyCond := expression.Key("market").Equal(expression.Value(models.BreadthMarket)).
+		And(expression.Key("date").Between(expression.Value(fromDate), expression.Value(toDate)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var series []models.MarketBreadth
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query breadth: %w", err)
+		}
+
+		var batch []models.MarketBreadth
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal breadth: %w", err)
+		}
+
+		series = append(series, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return series, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import "context"
+
+type consistentReadKey struct{}
+
+// WithConsistentRead returns ctx whose reads see every write made before
+// them, for requests that must not miss a write they just made
+func WithConsistentRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, consistentReadKey{}, true)
+}
+
+// ConsistentRead reports whether ctx came from WithConsistentRead, so the
+// read is made strongly consistent
+func ConsistentRead(ctx context.Context) bool {
+	required, _ := ctx.Value(consistentReadKey{}).(bool)
+	return required
+}
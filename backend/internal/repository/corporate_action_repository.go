@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// CorporateActionRepository defines the interface for split and dividend data
+// operations. Both kinds share a table, keyed by ticker and an id prefixed
+// with the kind.
+type CorporateActionRepository interface {
+	PutSplits(ctx context.Context, splits []models.Split) error
+	PutDividends(ctx context.Context, dividends []models.Dividend) error
+	GetSplits(ctx context.Context, symbol string) ([]models.Split, error)
+	GetDividends(ctx context.Context, symbol string) ([]models.Dividend, error)
+}
+
+// corporateActionRepository implements CorporateActionRepository using DynamoDB
+type corporateActionRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewCorporateActionRepository creates a new DynamoDB-backed corporate action repository
+func NewCorporateActionRepository(client *dynamodb.Client, tableName string) CorporateActionRepository {
+	return &corporateActionRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PutSplits stores splits, replacing those of the same ticker and execution date
+func (r *corporateActionRepository) PutSplits(ctx context.Context, splits []models.Split) error {
+	requests := make([]types.WriteRequest, 0, len(splits))
+	for i := range splits {
+		item, err := attributevalue.MarshalMap(splits[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal split: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// PutDividends stores dividends, replacing those of the same ticker and ex-dividend date
+func (r *corporateActionRepository) PutDividends(ctx context.Context, dividends []models.Dividend) error {
+	requests := make([]types.WriteRequest, 0, len(dividends))
+	for i := range dividends {
+		item, err := attributevalue.MarshalMap(dividends[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal dividend: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// GetSplits retrieves every split of a ticker, oldest first
+func (r *corporateActionRepository) GetSplits(ctx context.Context, symbol string) ([]models.Split, error) {
+	var splits []models.Split
+	if err := r.query(ctx, symbol, models.CorporateActionSplit, &splits); err != nil {
+		return nil, err
+	}
+	return splits, nil
+}
+
+// GetDividends retrieves every dividend of a ticker, oldest first
+func (r *corporateActionRepository) GetDividends(ctx context.Context, symbol string) ([]models.Dividend, error) {
+	var dividends []models.Dividend
+	if err := r.query(ctx, symbol, models.CorporateActionDividend, &dividends); err != nil {
+		return nil, err
+	}
+	return dividends, nil
+}
+
+// query unmarshals the actions of one kind of a ticker into out, a pointer to
+// a slice of that kind
+func (r *corporateActionRepository) query(ctx context.Context, symbol, kind string, out any) error {
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol)).
+		And(expression.Key("id").BeginsWith(kind + "#"))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var items []map[string]types.AttributeValue
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to query %ss for %s: %w", kind, symbol, err)
+		}
+
+		items = append(items, result.Items...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	if err := attributevalue.UnmarshalListOfMaps(items, out); err != nil {
+		return fmt.Errorf("failed to unmarshal %ss: %w", kind, err)
+	}
+	return nil
+}
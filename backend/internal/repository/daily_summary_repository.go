@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// DailySummaryRepository defines the interface for daily summary data operations
+type DailySummaryRepository interface {
+	GetSummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error)
+	EachSummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error
+	GetLatestSummaries(ctx context.Context, symbol string, before int64, limit int32) ([]models.DailySummary, error)
+	PutSummaries(ctx context.Context, summaries []models.DailySummary) error
+	DeleteSummaries(ctx context.Context, symbol string, throttle Throttle) (int, error)
+}
+
+// dailySummaryRepository implements DailySummaryRepository using DynamoDB
+type dailySummaryRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewDailySummaryRepository creates a new DynamoDB-backed daily summary repository
+func NewDailySummaryRepository(client *dynamodb.Client, tableName string) DailySummaryRepository {
+	return &dailySummaryRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetSummaries retrieves the daily summaries of a ticker with timestamps in [from, to], oldest first
+func (r *dailySummaryRepository) GetSummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	var summaries []models.DailySummary
+	err := r.EachSummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		summaries = append(summaries, summary)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// EachSummary calls fn with the daily summaries of a ticker with timestamps in
+// [from, to], oldest first, holding only one page of the query in memory. An
+// error returned by fn stops the iteration and is returned as is.
+func (r *dailySummaryRepository) EachSummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error {
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol)).
+		And(expression.Key("timestamp").Between(expression.Value(from), expression.Value(to)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to query daily summaries for %s: %w", symbol, err)
+		}
+
+		var batch []models.DailySummary
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return fmt.Errorf("failed to unmarshal daily summaries: %w", err)
+		}
+
+		for _, summary := range batch {
+			if err := fn(summary); err != nil {
+				return err
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return nil
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+}
+
+// GetLatestSummaries retrieves up to limit daily summaries of a ticker with
+// timestamps at or before before, newest first
+func (r *dailySummaryRepository) GetLatestSummaries(ctx context.Context, symbol string, before int64, limit int32) ([]models.DailySummary, error) {
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol)).
+		And(expression.Key("timestamp").LessThanEqual(expression.Value(before)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	result, err := r.client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest daily summaries for %s: %w", symbol, err)
+	}
+
+	var summaries []models.DailySummary
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &summaries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal daily summaries: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// PutSummaries stores daily summaries, replacing those of the same ticker and
+// timestamp. Summaries without an UpdatedUTC are stamped with the time written.
+func (r *dailySummaryRepository) PutSummaries(ctx context.Context, summaries []models.DailySummary) error {
+	now := time.Now().Unix()
+	requests := make([]types.WriteRequest, 0, len(summaries))
+	for i := range summaries {
+		summary := summaries[i]
+		if summary.UpdatedUTC == 0 {
+			summary.UpdatedUTC = now
+		}
+		item, err := attributevalue.MarshalMap(summary)
+		if err != nil {
+			return fmt.Errorf("failed to marshal daily summary: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// DeleteSummaries deletes every daily summary of a ticker and returns how many were deleted
+func (r *dailySummaryRepository) DeleteSummaries(ctx context.Context, symbol string, throttle Throttle) (int, error) {
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol))
+	proj := expression.NamesList(expression.Name("ticker"), expression.Name("timestamp"))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithProjection(proj).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	return QueryDelete(ctx, r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, throttle)
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDailySummaryRepository mocks the DailySummaryRepository interface
+type MockDailySummaryRepository struct {
+	mock.Mock
+}
+
+func (m *MockDailySummaryRepository) GetSummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	args := m.Called(ctx, symbol, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailySummary), args.Error(1)
+}
+
+func (m *MockDailySummaryRepository) EachSummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error {
+	summaries, err := m.GetSummaries(ctx, symbol, from, to)
+	if err != nil {
+		return err
+	}
+	for _, summary := range summaries {
+		if err := fn(summary); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockDailySummaryRepository) GetLatestSummaries(ctx context.Context, symbol string, before int64, limit int32) ([]models.DailySummary, error) {
+	args := m.Called(ctx, symbol, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailySummary), args.Error(1)
+}
+
+func (m *MockDailySummaryRepository) PutSummaries(ctx context.Context, summaries []models.DailySummary) error {
+	return m.Called(ctx, summaries).Error(0)
+}
+
+func (m *MockDailySummaryRepository) DeleteSummaries(ctx context.Context, symbol string, throttle Throttle) (int, error) {
+	args := m.Called(ctx, symbol, throttle)
+	return args.Int(0), args.Error(1)
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"time"
+)
+
+// revisionLookupWorkers bounds the queries reading the summaries a batch
+// overwrites, one per ticker
+const revisionLookupWorkers = 8
+
+// revisionSourceKey carries the writer of daily summaries in a context
+type revisionSourceKey struct{}
+
+// UnknownRevisionSource is the source of revisions written without one
+const UnknownRevisionSource = "unknown"
+
+// WithRevisionSource names the writer of the daily summaries stored with ctx,
+// recorded as the source of the revisions they make
+func WithRevisionSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, revisionSourceKey{}, source)
+}
+
+// RevisionSource returns the writer WithRevisionSource named, or
+// UnknownRevisionSource
+func RevisionSource(ctx context.Context) string {
+	if source, ok := ctx.Value(revisionSourceKey{}).(string); ok && source != "" {
+		return source
+	}
+	return UnknownRevisionSource
+}
+
+// revisionLoggingDailySummaryRepository records the daily summaries written
+// through it that overwrite stored ones with different values
+type revisionLoggingDailySummaryRepository struct {
+	DailySummaryRepository
+	revisions SummaryRevisionRepository
+	now       func() time.Time
+}
+
+// NewRevisionLoggingDailySummaryRepository wraps repo so summaries it
+// overwrites with different values are recorded in revisions. Each write
+// first reads the summaries it replaces. A revision that fails to be recorded
+// fails the write, although the summaries were stored; writing them again
+// does not record it, since the stored values are then the new ones.
+func NewRevisionLoggingDailySummaryRepository(repo DailySummaryRepository, revisions SummaryRevisionRepository) DailySummaryRepository {
+	return &revisionLoggingDailySummaryRepository{
+		DailySummaryRepository: repo,
+		revisions:              revisions,
+		now:                    time.Now,
+	}
+}
+
+// summaryKey identifies a stored daily summary
+type summaryKey struct {
+	ticker    string
+	timestamp int64
+}
+
+func (r *revisionLoggingDailySummaryRepository) PutSummaries(ctx context.Context, summaries []models.DailySummary) error {
+	stored, err := r.stored(ctx, summaries)
+	if err != nil {
+		return err
+	}
+	if err := r.DailySummaryRepository.PutSummaries(ctx, summaries); err != nil {
+		return err
+	}
+
+	now := r.now()
+	source := RevisionSource(ctx)
+	var revisions []models.SummaryRevision
+	for _, summary := range summaries {
+		old, ok := stored[summaryKey{summary.Ticker, summary.Timestamp}]
+		if !ok {
+			continue
+		}
+		changed := models.ChangedSummaryFields(old, summary)
+		if len(changed) == 0 {
+			continue
+		}
+		revisions = append(revisions, models.SummaryRevision{
+			Ticker:     summary.Ticker,
+			Date:       summary.Date(),
+			Timestamp:  summary.Timestamp,
+			Changed:    changed,
+			Old:        old,
+			New:        summary,
+			Source:     source,
+			RevisedUTC: now.Unix(),
+		})
+	}
+	if len(revisions) == 0 {
+		return nil
+	}
+	if err := r.revisions.Record(ctx, revisions); err != nil {
+		return fmt.Errorf("daily summaries written but their revisions were not recorded: %w", err)
+	}
+	return nil
+}
+
+// stored reads the summaries that summaries would overwrite, querying each
+// ticker's range of timestamps
+func (r *revisionLoggingDailySummaryRepository) stored(ctx context.Context, summaries []models.DailySummary) (map[summaryKey]models.DailySummary, error) {
+	type span struct{ from, to int64 }
+	spans := make(map[string]span)
+	for _, summary := range summaries {
+		s, ok := spans[summary.Ticker]
+		if !ok {
+			s = span{summary.Timestamp, summary.Timestamp}
+		}
+		spans[summary.Ticker] = span{min(s.from, summary.Timestamp), max(s.to, summary.Timestamp)}
+	}
+
+	type result struct {
+		summaries []models.DailySummary
+		err       error
+	}
+	results := make(chan result, len(spans))
+	sem := make(chan struct{}, revisionLookupWorkers)
+	for ticker, s := range spans {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			found, err := r.DailySummaryRepository.GetSummaries(ctx, ticker, s.from, s.to)
+			results <- result{found, err}
+		}()
+	}
+
+	stored := make(map[summaryKey]models.DailySummary)
+	var firstErr error
+	for range spans {
+		res := <-results
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to read the daily summaries being overwritten: %w", res.err)
+			}
+			continue
+		}
+		for _, summary := range res.summaries {
+			stored[summaryKey{summary.Ticker, summary.Timestamp}] = summary
+		}
+	}
+	return stored, firstErr
+}
@@ -0,0 +1,111 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// summaryStore keeps daily summaries in a map; methods other than
+// GetSummaries and PutSummaries are not used
+type summaryStore struct {
+	repository.DailySummaryRepository
+	mu     sync.Mutex
+	stored map[string]map[int64]models.DailySummary
+	putErr error
+}
+
+func (s *summaryStore) GetSummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []models.DailySummary
+	for ts, summary := range s.stored[symbol] {
+		if ts >= from && ts <= to {
+			found = append(found, summary)
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].Timestamp < found[j].Timestamp })
+	return found, nil
+}
+
+func (s *summaryStore) PutSummaries(ctx context.Context, summaries []models.DailySummary) error {
+	if s.putErr != nil {
+		return s.putErr
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, summary := range summaries {
+		if s.stored[summary.Ticker] == nil {
+			s.stored[summary.Ticker] = make(map[int64]models.DailySummary)
+		}
+		s.stored[summary.Ticker][summary.Timestamp] = summary
+	}
+	return nil
+}
+
+// recordedRevisions keeps the revisions recorded
+type recordedRevisions struct {
+	repository.SummaryRevisionRepository
+	revisions []models.SummaryRevision
+}
+
+func (r *recordedRevisions) Record(ctx context.Context, revisions []models.SummaryRevision) error {
+	r.revisions = append(r.revisions, revisions...)
+	return nil
+}
+
+func TestRevisionLoggingDailySummaryRepository(t *testing.T) {
+	const day = 1741305600 // 2025-03-07
+	bar := func(ticker string, ts int64, close float32) models.DailySummary {
+		return models.DailySummary{Ticker: ticker, Timestamp: ts, Open: 10, High: 12, Low: 9, Close: close, Volume: 100}
+	}
+	store := &summaryStore{stored: map[string]map[int64]models.DailySummary{}}
+	revisions := &recordedRevisions{}
+	repo := repository.NewRevisionLoggingDailySummaryRepository(store, revisions)
+
+	ctx := repository.WithRevisionSource(context.Background(), "daily-ingest")
+	require.NoError(t, repo.PutSummaries(ctx, []models.DailySummary{bar("AAPL", day, 11), bar("MSFT", day, 11)}))
+	assert.Empty(t, revisions.revisions, "first writes are not revisions")
+
+	corrected := bar("AAPL", day, 11.5)
+	corrected.Volume = 120
+	require.NoError(t, repo.PutSummaries(repository.WithRevisionSource(context.Background(), "backfill"), []models.DailySummary{
+		corrected,
+		bar("AAPL", day+86400, 11),
+		bar("MSFT", day, 11),
+	}))
+	require.Len(t, revisions.revisions, 1, "unchanged and new bars are not revisions")
+	revision := revisions.revisions[0]
+	assert.Equal(t, "AAPL", revision.Ticker)
+	assert.Equal(t, "2025-03-07", revision.Date)
+	assert.Equal(t, []string{"close", "volume"}, revision.Changed)
+	assert.Equal(t, float32(11), revision.Old.Close)
+	assert.Equal(t, float32(11.5), revision.New.Close)
+	assert.Equal(t, "backfill", revision.Source)
+	assert.NotZero(t, revision.RevisedUTC)
+
+	require.NoError(t, repo.PutSummaries(context.Background(), []models.DailySummary{bar("MSFT", day, 10)}))
+	assert.Equal(t, repository.UnknownRevisionSource, revisions.revisions[1].Source)
+
+	store.putErr = errors.New("throttled")
+	assert.Error(t, repo.PutSummaries(ctx, []models.DailySummary{bar("MSFT", day, 9)}))
+	assert.Len(t, revisions.revisions, 2, "failed writes make no revision")
+}
+
+func TestSummaryRevisionKey(t *testing.T) {
+	early := repository.SummaryRevisionKey("2025-03-07", time.Unix(1741400000, 0))
+	late := repository.SummaryRevisionKey("2025-03-07", time.Unix(1741500000, 0))
+	assert.Equal(t, "2025-03-07#1741400000000000000", early)
+	assert.Less(t, early, late)
+	assert.Less(t, late, repository.SummaryRevisionKey("2025-03-08", time.Unix(0, 1)))
+	assert.Less(t, late, "2025-03-07~", "a date's keys sort before its bound")
+}
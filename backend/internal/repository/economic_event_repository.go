@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// EconomicEventRepository defines the interface for economic calendar data operations
+type EconomicEventRepository interface {
+	PutEvents(ctx context.Context, events []models.EconomicEvent) error
+	GetEvents(ctx context.Context, country string, from, to int64) ([]models.EconomicEvent, error)
+}
+
+// economicEventRepository implements EconomicEventRepository using DynamoDB
+type economicEventRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewEconomicEventRepository creates a new DynamoDB-backed economic event repository
+func NewEconomicEventRepository(client *dynamodb.Client, tableName string) EconomicEventRepository {
+	return &economicEventRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PutEvents stores events, replacing earlier versions of the same release
+func (r *economicEventRepository) PutEvents(ctx context.Context, events []models.EconomicEvent) error {
+	requests := make([]types.WriteRequest, 0, len(events))
+	for i := range events {
+		item, err := attributevalue.MarshalMap(events[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal economic event: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// GetEvents retrieves the events of a country scheduled in [from, to], oldest first
+func (r *economicEventRepository) GetEvents(ctx context.Context, country string, from, to int64) ([]models.EconomicEvent, error) {
+	// "~" sorts after every event type, so the upper bound includes all events at to
+	keyCond := expression.Key("country").Equal(expression.Value(country)).
+		And(expression.Key("id").Between(
+			expression.Value(models.EconomicEventID(from, "")),
+			expression.Value(models.EconomicEventID(to, "~")),
+		))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var events []models.EconomicEvent
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query economic events: %w", err)
+		}
+
+		var batch []models.EconomicEvent
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal economic events: %w", err)
+		}
+
+		events = append(events, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return events, nil
+}
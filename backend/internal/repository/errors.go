@@ -11,6 +11,15 @@ func (e ErrTickerNotFound) Error() string {
 	return fmt.Sprintf("ticker not found: %s", e.Symbol)
 }
 
+// ErrTickerExists is returned when creating a ticker whose symbol is taken
+type ErrTickerExists struct {
+	Symbol string
+}
+
+func (e ErrTickerExists) Error() string {
+	return fmt.Sprintf("ticker already exists: %s", e.Symbol)
+}
+
 // ErrInvalidTicker is returned when ticker data is invalid
 type ErrInvalidTicker struct {
 	Reason string
@@ -19,3 +28,41 @@ type ErrInvalidTicker struct {
 func (e ErrInvalidTicker) Error() string {
 	return fmt.Sprintf("invalid ticker: %s", e.Reason)
 }
+
+// ErrAPIKeyNotFound is returned when an API key is not found in the repository
+type ErrAPIKeyNotFound struct {
+	ID string
+}
+
+func (e ErrAPIKeyNotFound) Error() string {
+	return fmt.Sprintf("api key not found: %s", e.ID)
+}
+
+// ErrSettingNotFound is returned when a setting is not found in the repository
+type ErrSettingNotFound struct {
+	Key string
+}
+
+func (e ErrSettingNotFound) Error() string {
+	return fmt.Sprintf("setting not found: %s", e.Key)
+}
+
+// ErrSettingConflict is returned when a setting changed since it was read
+type ErrSettingConflict struct {
+	Key string
+}
+
+func (e ErrSettingConflict) Error() string {
+	return fmt.Sprintf("setting was modified concurrently: %s", e.Key)
+}
+
+// ErrHeldSummaryNotFound is returned when no summary of a ticker and date is
+// held for review
+type ErrHeldSummaryNotFound struct {
+	Symbol string
+	Date   string
+}
+
+func (e ErrHeldSummaryNotFound) Error() string {
+	return fmt.Sprintf("held summary not found: %s on %s", e.Symbol, e.Date)
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// HeldSummaryRepository keeps the daily summaries held back from ingestion
+// for review, one per ticker and date
+type HeldSummaryRepository interface {
+	// PutHeld stores held summaries, replacing any held for the same ticker
+	// and date
+	PutHeld(ctx context.Context, held []models.HeldSummary) error
+	GetHeld(ctx context.Context, symbol, date string) (*models.HeldSummary, error)
+	ListHeld(ctx context.Context) ([]models.HeldSummary, error)
+	// DeleteHeld removes a held summary; deleting a missing one is not an error
+	DeleteHeld(ctx context.Context, symbol, date string) error
+}
+
+// heldSummaryRepository implements HeldSummaryRepository using a DynamoDB
+// table keyed on "ticker" and "date"
+type heldSummaryRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewHeldSummaryRepository creates a DynamoDB-backed held summary repository
+func NewHeldSummaryRepository(client *dynamodb.Client, tableName string) HeldSummaryRepository {
+	return &heldSummaryRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+func (r *heldSummaryRepository) PutHeld(ctx context.Context, held []models.HeldSummary) error {
+	requests := make([]types.WriteRequest, 0, len(held))
+	for _, h := range held {
+		item, err := attributevalue.MarshalMap(h)
+		if err != nil {
+			return fmt.Errorf("failed to marshal held summary: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+func (r *heldSummaryRepository) GetHeld(ctx context.Context, symbol, date string) (*models.HeldSummary, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.tableName),
+		Key:            heldSummaryKey(symbol, date),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held summary of %s on %s: %w", symbol, date, err)
+	}
+	if result.Item == nil {
+		return nil, ErrHeldSummaryNotFound{Symbol: symbol, Date: date}
+	}
+
+	var held models.HeldSummary
+	if err := attributevalue.UnmarshalMap(result.Item, &held); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal held summary: %w", err)
+	}
+	return &held, nil
+}
+
+// ListHeld scans the table, which only holds the summaries awaiting review
+func (r *heldSummaryRepository) ListHeld(ctx context.Context) ([]models.HeldSummary, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+
+	held := []models.HeldSummary{}
+	for {
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan held summaries: %w", err)
+		}
+
+		var batch []models.HeldSummary
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal held summaries: %w", err)
+		}
+		held = append(held, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			return held, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+func (r *heldSummaryRepository) DeleteHeld(ctx context.Context, symbol, date string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key:       heldSummaryKey(symbol, date),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete held summary of %s on %s: %w", symbol, date, err)
+	}
+	return nil
+}
+
+func heldSummaryKey(symbol, date string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ticker": &types.AttributeValueMemberS{Value: symbol},
+		"date":   &types.AttributeValueMemberS{Value: date},
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// IntradayBarRepository defines the interface for intraday bar data operations
+type IntradayBarRepository interface {
+	GetBars(ctx context.Context, symbol string, from, to int64) ([]models.IntradayBar, error)
+	PutBars(ctx context.Context, bars []models.IntradayBar) error
+	DeleteBars(ctx context.Context, symbol string, throttle Throttle) (int, error)
+}
+
+// intradayBarRepository implements IntradayBarRepository using DynamoDB
+type intradayBarRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewIntradayBarRepository creates a new DynamoDB-backed intraday bar repository
+func NewIntradayBarRepository(client *dynamodb.Client, tableName string) IntradayBarRepository {
+	return &intradayBarRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetBars retrieves the bars of a ticker with start times in [from, to], oldest first
+func (r *intradayBarRepository) GetBars(ctx context.Context, symbol string, from, to int64) ([]models.IntradayBar, error) {
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol)).
+		And(expression.Key("timestamp").Between(expression.Value(from), expression.Value(to)))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var bars []models.IntradayBar
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query intraday bars for %s: %w", symbol, err)
+		}
+
+		var batch []models.IntradayBar
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal intraday bars: %w", err)
+		}
+
+		bars = append(bars, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return bars, nil
+}
+
+// PutBars stores intraday bars, replacing those of the same ticker and timestamp
+func (r *intradayBarRepository) PutBars(ctx context.Context, bars []models.IntradayBar) error {
+	requests := make([]types.WriteRequest, 0, len(bars))
+	for _, bar := range bars {
+		item, err := attributevalue.MarshalMap(bar)
+		if err != nil {
+			return fmt.Errorf("failed to marshal intraday bar: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// DeleteBars deletes every intraday bar of a ticker and returns how many were deleted
+func (r *intradayBarRepository) DeleteBars(ctx context.Context, symbol string, throttle Throttle) (int, error) {
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol))
+	proj := expression.NamesList(expression.Name("ticker"), expression.Name("timestamp"))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithProjection(proj).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	return QueryDelete(ctx, r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.tableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, throttle)
+}
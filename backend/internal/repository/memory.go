@@ -0,0 +1,60 @@
+package repository
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"profitify-backend/internal/models"
+	"time"
+)
+
+// Storage backends selectable by configuration
+const (
+	BackendDynamoDB = "dynamodb"
+	BackendMemory   = "memory"
+)
+
+// tickerFixture seeds the in-memory ticker repository
+//
+//go:embed fixtures/tickers.json
+var tickerFixture []byte
+
+// MemoryStore holds the repositories kept in process memory by the memory
+// storage backend. Its data is private to the process and lost on restart, so
+// it suits demos and local development; repositories without an in-memory
+// implementation yet still use DynamoDB.
+type MemoryStore struct {
+	// Tickers records its writes in TickerChanges
+	Tickers       TickerRepository
+	TickerChanges TickerChangeRepository
+	// APIKeys starts empty; BOOTSTRAP_ADMIN_API_KEY provides the first key
+	APIKeys APIKeyRepository
+	Nonces  NonceRepository
+}
+
+// OpenMemoryStore returns the in-memory repositories of backend, or nil for
+// BackendDynamoDB. With seed, they start with the embedded fixture data, which
+// is not recorded as changes. Ticker changes are kept for changeRetention.
+func OpenMemoryStore(backend string, seed bool, changeRetention time.Duration) (*MemoryStore, error) {
+	switch backend {
+	case BackendDynamoDB:
+		return nil, nil
+	case BackendMemory:
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", backend)
+	}
+
+	var tickers []models.Ticker
+	if seed {
+		if err := json.Unmarshal(tickerFixture, &tickers); err != nil {
+			return nil, fmt.Errorf("failed to load ticker fixture: %w", err)
+		}
+	}
+	changes := NewMemoryTickerChangeRepository(changeRetention)
+	return &MemoryStore{
+		Tickers:       NewChangeLoggingTickerRepository(NewMemoryTickerRepository(tickers), changes),
+		TickerChanges: changes,
+		APIKeys:       NewMemoryAPIKeyRepository(),
+		Nonces:        NewMemoryNonceRepository(),
+	}, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// NonceRepository remembers the nonces of signed requests until they expire,
+// so a captured request cannot be replayed
+type NonceRepository interface {
+	// Remember stores nonce until expires. It reports false, without error,
+	// when the nonce is already stored and has not expired.
+	Remember(ctx context.Context, nonce string, expires time.Time) (bool, error)
+}
+
+// nonceRepository implements NonceRepository using a DynamoDB table keyed on
+// "id", whose "ttl" attribute lets DynamoDB delete expired nonces
+type nonceRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewNonceRepository creates a new DynamoDB-backed nonce repository
+func NewNonceRepository(client *dynamodb.Client, tableName string) NonceRepository {
+	return &nonceRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Remember stores the nonce unless an unexpired copy exists. DynamoDB deletes
+// expired items lazily, so expired copies are overwritten.
+func (r *nonceRepository) Remember(ctx context.Context, nonce string, expires time.Time) (bool, error) {
+	cond := expression.AttributeNotExists(expression.Name("id")).
+		Or(expression.Name("expiresAt").LessThan(expression.Value(time.Now().UnixMilli())))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return false, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item: map[string]types.AttributeValue{
+			"id":        &types.AttributeValueMemberS{Value: nonce},
+			"expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.UnixMilli(), 10)},
+			"ttl":       &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.Unix(), 10)},
+		},
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to store nonce: %w", err)
+	}
+
+	return true, nil
+}
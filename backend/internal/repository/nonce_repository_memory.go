@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryNonceRepository implements NonceRepository in process memory
+type memoryNonceRepository struct {
+	mu     sync.Mutex
+	nonces map[string]time.Time
+	now    func() time.Time
+}
+
+// NewMemoryNonceRepository creates an empty nonce repository in process memory
+func NewMemoryNonceRepository() NonceRepository {
+	return &memoryNonceRepository{nonces: make(map[string]time.Time), now: time.Now}
+}
+
+// Remember stores the nonce unless an unexpired copy exists. Expired nonces
+// are dropped as new ones are stored, so memory stays bounded by the nonces
+// of the accepted window.
+func (r *memoryNonceRepository) Remember(ctx context.Context, nonce string, expires time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if stored, ok := r.nonces[nonce]; ok && stored.After(now) {
+		return false, nil
+	}
+	for n, at := range r.nonces {
+		if !at.After(now) {
+			delete(r.nonces, n)
+		}
+	}
+	r.nonces[nonce] = expires
+	return true, nil
+}
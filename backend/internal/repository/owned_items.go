@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// OwnedItemPurger deletes the items an API key owns in a table keyed by "id"
+// that records the owning key's ID in "keyId", as the tables of watchlists,
+// alerts and the other account features do
+type OwnedItemPurger struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewOwnedItemPurger creates a purger of the items API keys own in tableName
+func NewOwnedItemPurger(client *dynamodb.Client, tableName string) *OwnedItemPurger {
+	return &OwnedItemPurger{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PurgeAccount deletes every item owned by keyID and returns how many were
+// deleted. Items are not keyed by their owner, so this scans the table.
+func (p *OwnedItemPurger) PurgeAccount(ctx context.Context, keyID string) (int, error) {
+	filter := expression.Name("keyId").Equal(expression.Value(keyID))
+	proj := expression.NamesList(expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(proj).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	return ScanDelete(ctx, p.client, &dynamodb.ScanInput{
+		TableName:                 aws.String(p.tableName),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, nil)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// MaxScanSegments is the most segments DynamoDB divides a parallel scan into
+const MaxScanSegments = 1000000
+
+// scanBuffer is how many scanned items wait for their reader before the
+// segments stop reading pages
+const scanBuffer = 256
+
+// Scanner scans a table, as *dynamodb.Client does
+type Scanner interface {
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+}
+
+// ScanAll reads every item input scans for, in segments scanned in parallel,
+// and sends them to items as their pages arrive, in no particular order. It
+// closes items once every segment is read, or once one fails, which stops
+// the others and is returned. input is not modified; its Segment,
+// TotalSegments and ExclusiveStartKey are set per segment.
+func ScanAll(ctx context.Context, client Scanner, input *dynamodb.ScanInput, segments int, items chan<- map[string]types.AttributeValue) error {
+	defer close(items)
+	segments = min(max(segments, 1), MaxScanSegments)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, segments)
+	for segment := range segments {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := scanSegment(ctx, client, input, segment, segments, items); err != nil {
+				errs <- err
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	close(errs)
+	// The first error cancels the other segments, which fail with ctx.Err()
+	if err, failed := <-errs; failed {
+		return err
+	}
+	return nil
+}
+
+// scanSegment reads the pages of one segment of a parallel scan
+func scanSegment(ctx context.Context, client Scanner, input *dynamodb.ScanInput, segment, segments int, items chan<- map[string]types.AttributeValue) error {
+	segmentInput := *input
+	if segments > 1 {
+		segmentInput.Segment = aws.Int32(int32(segment))
+		segmentInput.TotalSegments = aws.Int32(int32(segments))
+	}
+
+	for {
+		result, err := client.Scan(ctx, &segmentInput)
+		if err != nil {
+			return fmt.Errorf("failed to scan segment %d of %s: %w", segment, aws.ToString(input.TableName), err)
+		}
+		for _, item := range result.Items {
+			select {
+			case items <- item:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return nil
+		}
+		segmentInput.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// scanAllInto decodes every item of a parallel scan into a T, in no
+// particular order
+func scanAllInto[T any](ctx context.Context, client Scanner, input *dynamodb.ScanInput, segments int) ([]T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	items := make(chan map[string]types.AttributeValue, scanBuffer)
+	scanned := make(chan error, 1)
+	go func() {
+		scanned <- ScanAll(ctx, client, input, segments, items)
+	}()
+
+	var decoded []T
+	var decodeErr error
+	for item := range items {
+		if decodeErr != nil {
+			continue
+		}
+		var v T
+		if err := attributevalue.UnmarshalMap(item, &v); err != nil {
+			decodeErr = fmt.Errorf("failed to unmarshal an item of %s: %w", aws.ToString(input.TableName), err)
+			// Stop the scan; the items already sent are drained
+			cancel()
+			continue
+		}
+		decoded = append(decoded, v)
+	}
+
+	if err := <-scanned; decodeErr == nil && err != nil {
+		return nil, err
+	}
+	if decodeErr != nil {
+		return nil, decodeErr
+	}
+	return decoded, nil
+}
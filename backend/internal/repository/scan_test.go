@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// segmentedTable serves the items of a table divided into segments by the
+// hash of their position, a page of pageSize items at a time
+type segmentedTable struct {
+	symbols  []string
+	pageSize int
+	// failSegment fails the scans of a segment when not negative
+	failSegment int
+
+	mu     sync.Mutex
+	inputs []dynamodb.ScanInput
+}
+
+func (s *segmentedTable) Scan(ctx context.Context, input *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	s.mu.Lock()
+	s.inputs = append(s.inputs, *input)
+	s.mu.Unlock()
+
+	segment, segments := int(aws.ToInt32(input.Segment)), int(aws.ToInt32(input.TotalSegments))
+	if segments == 0 {
+		segments = 1
+	}
+	if segment == s.failSegment {
+		return nil, errors.New("throttled")
+	}
+
+	var items []map[string]types.AttributeValue
+	for i, symbol := range s.symbols {
+		if i%segments == segment {
+			items = append(items, map[string]types.AttributeValue{
+				"ticker": &types.AttributeValueMemberS{Value: symbol},
+				"pos":    &types.AttributeValueMemberN{Value: strconv.Itoa(i)},
+			})
+		}
+	}
+	start := 0
+	if key := input.ExclusiveStartKey; key != nil {
+		start, _ = strconv.Atoi(key["page"].(*types.AttributeValueMemberN).Value)
+	}
+	end := min(start+s.pageSize, len(items))
+	out := &dynamodb.ScanOutput{Items: items[start:end]}
+	if end < len(items) {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{"page": &types.AttributeValueMemberN{Value: strconv.Itoa(end)}}
+	}
+	return out, nil
+}
+
+func symbols(n int) []string {
+	symbols := make([]string, n)
+	for i := range symbols {
+		symbols[i] = "T" + strconv.Itoa(i)
+	}
+	return symbols
+}
+
+func TestScanAll(t *testing.T) {
+	table := &segmentedTable{symbols: symbols(50), pageSize: 4, failSegment: -1}
+	input := &dynamodb.ScanInput{TableName: aws.String("tickers")}
+
+	items := make(chan map[string]types.AttributeValue)
+	scanned := make(chan error, 1)
+	go func() {
+		scanned <- ScanAll(context.Background(), table, input, 3, items)
+	}()
+	var got []string
+	for item := range items {
+		got = append(got, item["ticker"].(*types.AttributeValueMemberS).Value)
+	}
+	require.NoError(t, <-scanned)
+
+	assert.ElementsMatch(t, table.symbols, got)
+	assert.Nil(t, input.Segment, "the caller's input is not modified")
+	assert.Nil(t, input.ExclusiveStartKey)
+
+	segments := map[int32]int{}
+	for _, in := range table.inputs {
+		assert.Equal(t, int32(3), aws.ToInt32(in.TotalSegments))
+		segments[aws.ToInt32(in.Segment)]++
+	}
+	// 17, 17 and 16 items in pages of 4
+	assert.Equal(t, map[int32]int{0: 5, 1: 5, 2: 4}, segments)
+}
+
+func TestScanAll_Sequential(t *testing.T) {
+	table := &segmentedTable{symbols: symbols(5), pageSize: 2, failSegment: -1}
+	tickers, err := scanAllInto[models.Ticker](context.Background(), table, &dynamodb.ScanInput{TableName: aws.String("tickers")}, 0)
+	require.NoError(t, err)
+
+	got := make([]string, len(tickers))
+	for i, ticker := range tickers {
+		got[i] = ticker.Ticker
+	}
+	assert.Equal(t, table.symbols, got, "one segment reads the pages in order")
+	for _, in := range table.inputs {
+		assert.Nil(t, in.Segment)
+		assert.Nil(t, in.TotalSegments)
+	}
+}
+
+func TestScanAll_SegmentFails(t *testing.T) {
+	table := &segmentedTable{symbols: symbols(1000), pageSize: 10, failSegment: 2}
+	_, err := scanAllInto[models.Ticker](context.Background(), table, &dynamodb.ScanInput{TableName: aws.String("tickers")}, 4)
+	assert.EqualError(t, err, "failed to scan segment 2 of tickers: throttled", "the failure is returned, not the cancellation of the others")
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SettingsRepository defines the interface for key-value settings operations
+type SettingsRepository interface {
+	GetSetting(ctx context.Context, key string) (*models.Setting, error)
+	ListSettings(ctx context.Context, prefix string) ([]models.Setting, error)
+	PutSetting(ctx context.Context, key, value string) (*models.Setting, error)
+	PutSettingIfVersion(ctx context.Context, key, value string, version int64) (*models.Setting, error)
+	DeleteSetting(ctx context.Context, key string) error
+}
+
+// settingsRepository implements SettingsRepository using DynamoDB
+type settingsRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewSettingsRepository creates a new DynamoDB-backed settings repository
+func NewSettingsRepository(client *dynamodb.Client, tableName string) SettingsRepository {
+	return &settingsRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetSetting retrieves a single setting by key
+func (r *settingsRepository) GetSetting(ctx context.Context, key string) (*models.Setting, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: key},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+
+	if result.Item == nil {
+		return nil, ErrSettingNotFound{Key: key}
+	}
+
+	var setting models.Setting
+	if err := attributevalue.UnmarshalMap(result.Item, &setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal setting: %w", err)
+	}
+
+	return &setting, nil
+}
+
+// ListSettings retrieves all settings whose key starts with prefix
+func (r *settingsRepository) ListSettings(ctx context.Context, prefix string) ([]models.Setting, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(r.tableName),
+	}
+	if prefix != "" {
+		expr, err := expression.NewBuilder().
+			WithFilter(expression.Name("key").BeginsWith(prefix)).
+			Build()
+		if err != nil {
+			return nil, fmt.Errorf("failed to build expression: %w", err)
+		}
+		input.FilterExpression = expr.Filter()
+		input.ExpressionAttributeNames = expr.Names()
+		input.ExpressionAttributeValues = expr.Values()
+	}
+
+	var settings []models.Setting
+	for {
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan settings: %w", err)
+		}
+
+		var batch []models.Setting
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+		}
+
+		settings = append(settings, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	return settings, nil
+}
+
+// PutSetting creates or overwrites a setting regardless of its version
+func (r *settingsRepository) PutSetting(ctx context.Context, key, value string) (*models.Setting, error) {
+	return r.update(ctx, key, value, nil)
+}
+
+// PutSettingIfVersion writes a setting only if its stored version still equals
+// version, with version 0 meaning the setting must not exist yet. Otherwise it
+// returns ErrSettingConflict.
+func (r *settingsRepository) PutSettingIfVersion(ctx context.Context, key, value string, version int64) (*models.Setting, error) {
+	cond := expression.Name("version").Equal(expression.Value(version))
+	if version == 0 {
+		cond = expression.AttributeNotExists(expression.Name("key"))
+	}
+	return r.update(ctx, key, value, &cond)
+}
+
+// update writes value and bumps the version, optionally under a condition
+func (r *settingsRepository) update(ctx context.Context, key, value string, cond *expression.ConditionBuilder) (*models.Setting, error) {
+	update := expression.Set(expression.Name("value"), expression.Value(value)).
+		Set(expression.Name("updatedUTC"), expression.Value(time.Now().Unix())).
+		Add(expression.Name("version"), expression.Value(1))
+
+	builder := expression.NewBuilder().WithUpdate(update)
+	if cond != nil {
+		builder = builder.WithCondition(*cond)
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	result, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: key},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return nil, ErrSettingConflict{Key: key}
+		}
+		return nil, fmt.Errorf("failed to put setting %s: %w", key, err)
+	}
+
+	var setting models.Setting
+	if err := attributevalue.UnmarshalMap(result.Attributes, &setting); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal setting: %w", err)
+	}
+
+	return &setting, nil
+}
+
+// DeleteSetting removes a setting; deleting a missing setting is not an error
+func (r *settingsRepository) DeleteSetting(ctx context.Context, key string) error {
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: key},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete setting %s: %w", key, err)
+	}
+
+	return nil
+}
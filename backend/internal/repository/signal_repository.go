@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SignalRepository defines the interface for market scanner signal operations
+type SignalRepository interface {
+	PutSignals(ctx context.Context, signals []models.Signal) error
+	GetSignals(ctx context.Context, date string) ([]models.Signal, error)
+	DeleteTickerSignals(ctx context.Context, symbol string, throttle Throttle) (int, error)
+}
+
+// signalRepository implements SignalRepository using DynamoDB
+type signalRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewSignalRepository creates a new DynamoDB-backed signal repository
+func NewSignalRepository(client *dynamodb.Client, tableName string) SignalRepository {
+	return &signalRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PutSignals stores signals, replacing earlier scans of the same ticker, type and date
+func (r *signalRepository) PutSignals(ctx context.Context, signals []models.Signal) error {
+	requests := make([]types.WriteRequest, 0, len(signals))
+	for i := range signals {
+		item, err := attributevalue.MarshalMap(signals[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal signal: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// GetSignals retrieves all signals flagged for a trading date
+func (r *signalRepository) GetSignals(ctx context.Context, date string) ([]models.Signal, error) {
+	keyCond := expression.Key("date").Equal(expression.Value(date))
+
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var signals []models.Signal
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query signals for %s: %w", date, err)
+		}
+
+		var batch []models.Signal
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signals: %w", err)
+		}
+
+		signals = append(signals, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return signals, nil
+}
+
+// DeleteTickerSignals deletes every signal flagged for a ticker and returns how
+// many were deleted. Signals are keyed by date, so this scans the table.
+func (r *signalRepository) DeleteTickerSignals(ctx context.Context, symbol string, throttle Throttle) (int, error) {
+	filter := expression.Name("ticker").Equal(expression.Value(symbol))
+	proj := expression.NamesList(expression.Name("date"), expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithFilter(filter).WithProjection(proj).Build()
+	if err != nil {
+		return 0, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	return ScanDelete(ctx, r.client, &dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          expr.Filter(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, throttle)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// SummaryRevisionRepository is the log of daily summaries overwritten with
+// different values, per ticker
+type SummaryRevisionRepository interface {
+	// Record appends revisions to the log
+	Record(ctx context.Context, revisions []models.SummaryRevision) error
+	// GetRevisions returns the revisions of the summaries of symbol dated from
+	// from to to, YYYY-MM-DD, ordered by date and then by when they were made
+	GetRevisions(ctx context.Context, symbol, from, to string) ([]models.SummaryRevision, error)
+}
+
+// SummaryRevisionKey orders the revision at t of the summary of date after
+// the summary's earlier revisions
+func SummaryRevisionKey(date string, t time.Time) string {
+	return fmt.Sprintf("%s#%019d", date, t.UnixNano())
+}
+
+// summaryRevisionItem is a revision as stored, under its revision key
+type summaryRevisionItem struct {
+	Revision string `dynamodbav:"revision"`
+	models.SummaryRevision
+}
+
+// summaryRevisionRepository implements SummaryRevisionRepository using a
+// DynamoDB table keyed on "ticker" and "revision"
+type summaryRevisionRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewSummaryRevisionRepository creates a DynamoDB-backed summary revision log
+func NewSummaryRevisionRepository(client *dynamodb.Client, tableName string) SummaryRevisionRepository {
+	return &summaryRevisionRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// Record keys the revisions by the time of the call, which a batch of
+// summaries revises each date of a ticker once within
+func (r *summaryRevisionRepository) Record(ctx context.Context, revisions []models.SummaryRevision) error {
+	now := time.Now()
+	requests := make([]types.WriteRequest, 0, len(revisions))
+	for _, revision := range revisions {
+		item, err := attributevalue.MarshalMap(summaryRevisionItem{
+			Revision:        SummaryRevisionKey(revision.Date, now),
+			SummaryRevision: revision,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal summary revision: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+func (r *summaryRevisionRepository) GetRevisions(ctx context.Context, symbol, from, to string) ([]models.SummaryRevision, error) {
+	// Keys of the dates up to to sort before to's followed by "~"
+	keyCond := expression.Key("ticker").Equal(expression.Value(symbol)).
+		And(expression.Key("revision").Between(expression.Value(from), expression.Value(to+"~")))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	revisions := []models.SummaryRevision{}
+	var lastEvaluatedKey map[string]types.AttributeValue
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query summary revisions for %s: %w", symbol, err)
+		}
+
+		var batch []summaryRevisionItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal summary revisions: %w", err)
+		}
+		for _, item := range batch {
+			revisions = append(revisions, item.SummaryRevision)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return revisions, nil
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"profitify-backend/pkg/config"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// tableWait bounds how long to wait for a table to be created or deleted
+const tableWait = 2 * time.Minute
+
+// Table is a table the backend reads, and the attribute DynamoDB expires its
+// items by, if any
+type Table struct {
+	Input        *dynamodb.CreateTableInput
+	TTLAttribute string
+}
+
+// Name is the table's name
+func (t Table) Name() string {
+	return aws.ToString(t.Input.TableName)
+}
+
+// Tables returns every table of the configuration, keyed as the repositories
+// query them
+func Tables(cfg *config.Config) []Table {
+	tickers := tickersTable(cfg.TickersTable)
+	summaries := keyedTable(cfg.DailySummaryTable, "ticker", types.ScalarAttributeTypeS, "timestamp", types.ScalarAttributeTypeN)
+	// The streams consumer reads the changes of the tickers and daily summaries
+	if cfg.StreamsEnabled {
+		tickers.StreamSpecification = changeStream()
+		summaries.StreamSpecification = changeStream()
+	}
+	return []Table{
+		{Input: tickers},
+		{Input: summaries},
+		{Input: keyedTable(cfg.IntradayBarsTable, "ticker", types.ScalarAttributeTypeS, "timestamp", types.ScalarAttributeTypeN)},
+		{Input: keyedTable(cfg.CustomAssetsTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.AssetValuationsTable, "assetId", types.ScalarAttributeTypeS, "timestamp", types.ScalarAttributeTypeN)},
+		{Input: keyedTable(cfg.SignalsTable, "date", types.ScalarAttributeTypeS, "id", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.BreadthTable, "market", types.ScalarAttributeTypeS, "date", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.EconomicEventsTable, "country", types.ScalarAttributeTypeS, "id", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.CorporateActionsTable, "ticker", types.ScalarAttributeTypeS, "id", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.BarRollupsTable, "series", types.ScalarAttributeTypeS, "timestamp", types.ScalarAttributeTypeN)},
+		{Input: keyedTable(cfg.TickerStatsTable, "ticker", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.SummaryRevisionsTable, "ticker", types.ScalarAttributeTypeS, "revision", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.HeldSummariesTable, "ticker", types.ScalarAttributeTypeS, "date", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.APIKeysTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.SettingsTable, "key", types.ScalarAttributeTypeS, "", "")},
+		// Expired leases linger until DynamoDB removes them by their ttl
+		{Input: keyedTable(cfg.LocksTable, "name", types.ScalarAttributeTypeS, "", ""), TTLAttribute: "ttl"},
+		{Input: keyedTable(cfg.WatchlistsTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.PortfoliosTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.PortfolioTransactionsTable, "portfolioId", types.ScalarAttributeTypeS, "id", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.AlertsTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.AnalyticsTable, "date", types.ScalarAttributeTypeS, "metric", types.ScalarAttributeTypeS)},
+		{Input: keyedTable(cfg.DigestsTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.DevicesTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.NoncesTable, "id", types.ScalarAttributeTypeS, "", ""), TTLAttribute: "ttl"},
+		{Input: keyedTable(cfg.SessionsTable, "id", types.ScalarAttributeTypeS, "", ""), TTLAttribute: "ttl"},
+		{Input: keyedTable(cfg.UsersTable, "id", types.ScalarAttributeTypeS, "", "")},
+		{Input: keyedTable(cfg.TickerChangesTable, "stream", types.ScalarAttributeTypeS, "seq", types.ScalarAttributeTypeS), TTLAttribute: "ttl"},
+		{Input: keyedTable(cfg.ActivityTable, "keyId", types.ScalarAttributeTypeS, "seq", types.ScalarAttributeTypeS), TTLAttribute: "ttl"},
+	}
+}
+
+// EnsureTables creates the tables of the configuration that do not exist, adds
+// the indexes existing ones lack and turns on their TTL and streams, reporting
+// progress to out. With recreate, existing tables are dropped and created empty.
+func EnsureTables(ctx context.Context, client *dynamodb.Client, cfg *config.Config, recreate bool, out io.Writer) error {
+	for _, table := range Tables(cfg) {
+		if err := ensureTable(ctx, client, table.Input, recreate, out); err != nil {
+			return err
+		}
+		if table.TTLAttribute != "" {
+			if err := ensureTTL(ctx, client, table.Name(), table.TTLAttribute, out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ensureTable creates a table unless it exists, in which case the indexes it
+// lacks are added; with recreate an existing table is dropped first
+func ensureTable(ctx context.Context, client *dynamodb.Client, input *dynamodb.CreateTableInput, recreate bool, out io.Writer) error {
+	name := aws.ToString(input.TableName)
+
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName})
+	var notFound *types.ResourceNotFoundException
+	switch {
+	case errors.As(err, &notFound):
+	case err != nil:
+		return fmt.Errorf("failed to describe table %s: %w", name, err)
+	case !recreate:
+		fmt.Fprintf(out, "Table %s exists\n", name)
+		if err := ensureStream(ctx, client, input, described.Table, out); err != nil {
+			return err
+		}
+		return ensureIndexes(ctx, client, input, described.Table, out)
+	default:
+		fmt.Fprintf(out, "Deleting table %s...\n", name)
+		if _, err := client.DeleteTable(ctx, &dynamodb.DeleteTableInput{TableName: input.TableName}); err != nil {
+			return fmt.Errorf("failed to delete table %s: %w", name, err)
+		}
+		waiter := dynamodb.NewTableNotExistsWaiter(client)
+		if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName}, tableWait); err != nil {
+			return fmt.Errorf("failed waiting for table %s to be deleted: %w", name, err)
+		}
+	}
+
+	fmt.Fprintf(out, "Creating table %s...\n", name)
+	if _, err := client.CreateTable(ctx, input); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", name, err)
+	}
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName}, tableWait); err != nil {
+		return fmt.Errorf("failed waiting for table %s to be created: %w", name, err)
+	}
+	return nil
+}
+
+// ensureIndexes adds the global secondary indexes of input that the existing
+// table lacks. DynamoDB builds one new index of a table at a time, so each is
+// awaited before the next is created.
+func ensureIndexes(ctx context.Context, client *dynamodb.Client, input *dynamodb.CreateTableInput, existing *types.TableDescription, out io.Writer) error {
+	name := aws.ToString(input.TableName)
+	have := make(map[string]bool, len(existing.GlobalSecondaryIndexes))
+	for _, index := range existing.GlobalSecondaryIndexes {
+		have[aws.ToString(index.IndexName)] = true
+	}
+
+	for _, index := range input.GlobalSecondaryIndexes {
+		indexName := aws.ToString(index.IndexName)
+		if have[indexName] {
+			continue
+		}
+
+		fmt.Fprintf(out, "Creating index %s on table %s...\n", indexName, name)
+		_, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+			TableName:            input.TableName,
+			AttributeDefinitions: input.AttributeDefinitions,
+			GlobalSecondaryIndexUpdates: []types.GlobalSecondaryIndexUpdate{{
+				Create: &types.CreateGlobalSecondaryIndexAction{
+					IndexName:  index.IndexName,
+					KeySchema:  index.KeySchema,
+					Projection: index.Projection,
+				},
+			}},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create index %s on table %s: %w", indexName, name, err)
+		}
+		if err := waitForIndex(ctx, client, name, indexName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureStream turns on the stream of input on the existing table unless it
+// has one. A stream of other images is left alone; it is replaced by hand.
+func ensureStream(ctx context.Context, client *dynamodb.Client, input *dynamodb.CreateTableInput, existing *types.TableDescription, out io.Writer) error {
+	if input.StreamSpecification == nil {
+		return nil
+	}
+	if spec := existing.StreamSpecification; spec != nil && aws.ToBool(spec.StreamEnabled) {
+		return nil
+	}
+
+	name := aws.ToString(input.TableName)
+	fmt.Fprintf(out, "Enabling the stream of table %s...\n", name)
+	_, err := client.UpdateTable(ctx, &dynamodb.UpdateTableInput{
+		TableName:           input.TableName,
+		StreamSpecification: input.StreamSpecification,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable the stream of table %s: %w", name, err)
+	}
+	waiter := dynamodb.NewTableExistsWaiter(client)
+	if err := waiter.Wait(ctx, &dynamodb.DescribeTableInput{TableName: input.TableName}, tableWait); err != nil {
+		return fmt.Errorf("failed waiting for the stream of table %s: %w", name, err)
+	}
+	return nil
+}
+
+// StreamARN returns the ARN of the latest stream of the table
+func StreamARN(ctx context.Context, client *dynamodb.Client, name string) (string, error) {
+	described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(name)})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe table %s: %w", name, err)
+	}
+	arn := aws.ToString(described.Table.LatestStreamArn)
+	if arn == "" {
+		return "", fmt.Errorf("table %s has no stream", name)
+	}
+	return arn, nil
+}
+
+// changeStream records the items before and after each change
+func changeStream() *types.StreamSpecification {
+	return &types.StreamSpecification{
+		StreamEnabled:  aws.Bool(true),
+		StreamViewType: types.StreamViewTypeNewAndOldImages,
+	}
+}
+
+// waitForIndex polls until the table's index is active, for at most tableWait
+func waitForIndex(ctx context.Context, client *dynamodb.Client, table, index string) error {
+	ctx, cancel := context.WithTimeout(ctx, tableWait)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		described, err := client.DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+		if err != nil {
+			return fmt.Errorf("failed waiting for index %s on table %s: %w", index, table, err)
+		}
+		for _, gsi := range described.Table.GlobalSecondaryIndexes {
+			if aws.ToString(gsi.IndexName) == index && gsi.IndexStatus == types.IndexStatusActive {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed waiting for index %s on table %s: %w", index, table, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ensureTTL expires the table's items by attribute unless TTL is already on
+func ensureTTL(ctx context.Context, client *dynamodb.Client, name, attribute string, out io.Writer) error {
+	described, err := client.DescribeTimeToLive(ctx, &dynamodb.DescribeTimeToLiveInput{TableName: aws.String(name)})
+	if err != nil {
+		return fmt.Errorf("failed to describe TTL of table %s: %w", name, err)
+	}
+	if d := described.TimeToLiveDescription; d != nil &&
+		(d.TimeToLiveStatus == types.TimeToLiveStatusEnabled || d.TimeToLiveStatus == types.TimeToLiveStatusEnabling) {
+		return nil
+	}
+
+	fmt.Fprintf(out, "Enabling TTL on %s.%s...\n", name, attribute)
+	_, err = client.UpdateTimeToLive(ctx, &dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(name),
+		TimeToLiveSpecification: &types.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable TTL on table %s: %w", name, err)
+	}
+	return nil
+}
+
+// keyedTable is an on-demand table keyed by a hash key and, unless rangeKey
+// is empty, a range key
+func keyedTable(name, hashKey string, hashType types.ScalarAttributeType, rangeKey string, rangeType types.ScalarAttributeType) *dynamodb.CreateTableInput {
+	input := &dynamodb.CreateTableInput{
+		TableName: aws.String(name),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String(hashKey), AttributeType: hashType},
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	}
+	if rangeKey != "" {
+		input.KeySchema = append(input.KeySchema, types.KeySchemaElement{AttributeName: aws.String(rangeKey), KeyType: types.KeyTypeRange})
+		input.AttributeDefinitions = append(input.AttributeDefinitions, types.AttributeDefinition{AttributeName: aws.String(rangeKey), AttributeType: rangeType})
+	}
+	return input
+}
+
+func tickersTable(name string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName: aws.String(name),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("ticker"), KeyType: types.KeyTypeHash},
+		},
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("ticker"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("active"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("primaryExchange"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("market"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			// Sparse index over the active attribute, queried by GetActiveTickers
+			tickerIndex("active-index", "active"),
+			// Queried by GetTickersByExchange; tickers without a primary
+			// exchange are left out
+			tickerIndex("exchange-index", "primaryExchange"),
+			// Queried by GetTickersByMarket
+			tickerIndex("market-index", "market"),
+		},
+		BillingMode: types.BillingModePayPerRequest,
+	}
+}
+
+// tickerIndex is a GSI of the tickers table keyed on attribute and ranged by
+// symbol, so queries return tickers ordered by symbol
+func tickerIndex(name, attribute string) types.GlobalSecondaryIndex {
+	return types.GlobalSecondaryIndex{
+		IndexName: aws.String(name),
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String(attribute), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("ticker"), KeyType: types.KeyTypeRange},
+		},
+		Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+	}
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"profitify-backend/pkg/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTables_CoverTheConfiguration(t *testing.T) {
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	names := make(map[string]string)
+	for _, table := range Tables(cfg) {
+		assert.NotContains(t, names, table.Name(), "table %s is created twice", table.Name())
+		names[table.Name()] = table.TTLAttribute
+	}
+
+	// Every *Table field of the configuration names a created table
+	v := reflect.ValueOf(*cfg)
+	for i := 0; i < v.NumField(); i++ {
+		if field := v.Type().Field(i); strings.HasSuffix(field.Name, "Table") {
+			assert.Contains(t, names, v.Field(i).String(), "%s is not created", field.Name)
+		}
+	}
+	assert.Equal(t, "ttl", names[cfg.LocksTable], "expired locks are removed by TTL")
+}
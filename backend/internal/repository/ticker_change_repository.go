@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// tickerChangeStream is the partition holding the ticker change log. Ticker
+// writes are rare outside the daily refresh, whose batch writes back off
+// when the partition throttles.
+const tickerChangeStream = "tickers"
+
+// TickerChangeRepository is the log of ticker writes that clients sync their
+// copy of the tickers from
+type TickerChangeRepository interface {
+	// Record appends changes to the log
+	Record(ctx context.Context, changes []models.TickerChange) error
+	// After returns up to limit changes ordered after the seq after and up to
+	// until, oldest first
+	After(ctx context.Context, after, until string, limit int) ([]models.TickerChange, error)
+}
+
+// TickerChangeSeq orders the change of symbol at t. An empty symbol sorts
+// after every change at t, bounding the changes up to t.
+func TickerChangeSeq(t time.Time, symbol string) string {
+	if symbol == "" {
+		return fmt.Sprintf("%019d~", t.UnixNano())
+	}
+	return fmt.Sprintf("%019d#%s", t.UnixNano(), symbol)
+}
+
+// TickerChangeTime returns the time a seq of TickerChangeSeq orders at
+func TickerChangeTime(seq string) (time.Time, error) {
+	nanos, ok := strings.CutSuffix(seq, "~")
+	if !ok {
+		nanos, _, ok = strings.Cut(seq, "#")
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if !ok || len(nanos) != 19 || err != nil {
+		return time.Time{}, fmt.Errorf("invalid ticker change seq %q", seq)
+	}
+	return time.Unix(0, n), nil
+}
+
+// tickerChangeItem is a change as stored, in the stream partition with the
+// ttl DynamoDB expires it by
+type tickerChangeItem struct {
+	Stream string `dynamodbav:"stream"`
+	models.TickerChange
+	TTL int64 `dynamodbav:"ttl"`
+}
+
+// tickerChangeRepository implements TickerChangeRepository using a DynamoDB
+// table keyed on "stream" and "seq"
+type tickerChangeRepository struct {
+	client    *dynamodb.Client
+	tableName string
+	retention time.Duration
+}
+
+// NewTickerChangeRepository creates a DynamoDB-backed ticker change log whose
+// changes expire after retention
+func NewTickerChangeRepository(client *dynamodb.Client, tableName string, retention time.Duration) TickerChangeRepository {
+	return &tickerChangeRepository{
+		client:    client,
+		tableName: tableName,
+		retention: retention,
+	}
+}
+
+func (r *tickerChangeRepository) Record(ctx context.Context, changes []models.TickerChange) error {
+	requests := make([]types.WriteRequest, 0, len(changes))
+	for _, change := range changes {
+		item, err := attributevalue.MarshalMap(tickerChangeItem{
+			Stream:       tickerChangeStream,
+			TickerChange: change,
+			TTL:          time.Unix(change.ChangedUTC, 0).Add(r.retention).Unix(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal ticker change: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+	}
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+func (r *tickerChangeRepository) After(ctx context.Context, after, until string, limit int) ([]models.TickerChange, error) {
+	keyCond := expression.Key("stream").Equal(expression.Value(tickerChangeStream)).
+		And(expression.Key("seq").Between(expression.Value(after), expression.Value(until)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var changes []models.TickerChange
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for len(changes) < limit {
+		input := &dynamodb.QueryInput{
+			TableName:                 aws.String(r.tableName),
+			KeyConditionExpression:    expr.KeyCondition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+			// One more than needed, as the range includes the change at after
+			Limit: aws.Int32(int32(limit - len(changes) + 1)),
+		}
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query ticker changes: %w", err)
+		}
+
+		var batch []tickerChangeItem
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal ticker changes: %w", err)
+		}
+		for _, item := range batch {
+			if item.Seq != after && len(changes) < limit {
+				changes = append(changes, item.TickerChange)
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"profitify-backend/internal/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryTickerChangeRepository implements TickerChangeRepository in process
+// memory, ordered by seq
+type memoryTickerChangeRepository struct {
+	mu        sync.RWMutex
+	changes   []models.TickerChange
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewMemoryTickerChangeRepository creates an empty ticker change log in
+// process memory whose changes are dropped after retention
+func NewMemoryTickerChangeRepository(retention time.Duration) TickerChangeRepository {
+	return &memoryTickerChangeRepository{retention: retention, now: time.Now}
+}
+
+// Record appends changes, dropping the expired ones so memory stays bounded
+// by the changes of the retention
+func (r *memoryTickerChangeRepository) Record(ctx context.Context, changes []models.TickerChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := r.now().Add(-r.retention).Unix()
+	kept := r.changes[:0]
+	for _, change := range r.changes {
+		if change.ChangedUTC > expired {
+			kept = append(kept, change)
+		}
+	}
+	r.changes = append(kept, changes...)
+	sort.SliceStable(r.changes, func(i, j int) bool { return r.changes[i].Seq < r.changes[j].Seq })
+	return nil
+}
+
+func (r *memoryTickerChangeRepository) After(ctx context.Context, after, until string, limit int) ([]models.TickerChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	start := sort.Search(len(r.changes), func(i int) bool { return r.changes[i].Seq > after })
+	var changes []models.TickerChange
+	for _, change := range r.changes[start:] {
+		if change.Seq > until || len(changes) == limit {
+			break
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"profitify-backend/internal/models"
 
@@ -16,20 +17,46 @@ import (
 type TickerRepository interface {
 	GetTicker(ctx context.Context, symbol string) (*models.Ticker, error)
 	GetActiveTickers(ctx context.Context) ([]models.Ticker, error)
+	// GetTickersByExchange retrieves the active tickers listed on the exchange,
+	// a MIC such as XNAS
+	GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error)
+	// GetTickersByMarket retrieves the active tickers of the market, such as
+	// stocks or crypto
+	GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error)
+	PutTickers(ctx context.Context, tickers []models.Ticker) error
+	PutTicker(ctx context.Context, ticker *models.Ticker) error
+	UpdateTicker(ctx context.Context, ticker *models.Ticker) error
+	DeleteTicker(ctx context.Context, symbol string) error
+}
+
+// TickerIndexes name the GSIs of the tickers table. An empty name falls back
+// to scanning the table for the tickers the index would return.
+type TickerIndexes struct {
+	// Active is keyed on the sparse active attribute
+	Active string
+	// Exchange is keyed on primaryExchange, which not every ticker has
+	Exchange string
+	// Market is keyed on market
+	Market string
+	// ScanSegments is how many segments the table is scanned in parallel in
+	// without an index; below 2 scans it sequentially
+	ScanSegments int
 }
 
 // tickerRepository implements TickerRepository using DynamoDB
 type tickerRepository struct {
 	client    *dynamodb.Client
 	tableName string
+	indexes   TickerIndexes
 }
 
 // NewTickerRepository creates a new DynamoDB-backed ticker repository
-func NewTickerRepository(client *dynamodb.Client) TickerRepository {
-	tableName := "stocks-data"
+// querying the indexes for active tickers and tickers by exchange and market
+func NewTickerRepository(client *dynamodb.Client, tableName string, indexes TickerIndexes) TickerRepository {
 	return &tickerRepository{
 		client:    client,
 		tableName: tableName,
+		indexes:   indexes,
 	}
 }
 
@@ -72,9 +99,40 @@ func (r *tickerRepository) GetTicker(ctx context.Context, symbol string) (*model
 
 // GetActiveTickers retrieves all active tickers
 func (r *tickerRepository) GetActiveTickers(ctx context.Context) ([]models.Ticker, error) {
-	// Build filter expression for active tickers
-	filt := expression.Name("active").Equal(expression.Value(1))
-	expr, err := expression.NewBuilder().WithFilter(filt).Build()
+	active := expression.Name("active").Equal(expression.Value(1))
+	if r.indexes.Active == "" {
+		return r.scanTickers(ctx, active)
+	}
+	return r.queryIndex(ctx, r.indexes.Active, expression.Key("active").Equal(expression.Value(1)), nil)
+}
+
+// GetTickersByExchange retrieves the active tickers listed on exchange
+func (r *tickerRepository) GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error) {
+	active := expression.Name("active").Equal(expression.Value(1))
+	if r.indexes.Exchange == "" {
+		return r.scanTickers(ctx, expression.Name("primaryExchange").Equal(expression.Value(exchange)).And(active))
+	}
+	return r.queryIndex(ctx, r.indexes.Exchange, expression.Key("primaryExchange").Equal(expression.Value(exchange)), &active)
+}
+
+// GetTickersByMarket retrieves the active tickers of market
+func (r *tickerRepository) GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error) {
+	active := expression.Name("active").Equal(expression.Value(1))
+	if r.indexes.Market == "" {
+		return r.scanTickers(ctx, expression.Name("market").Equal(expression.Value(market)).And(active))
+	}
+	return r.queryIndex(ctx, r.indexes.Market, expression.Key("market").Equal(expression.Value(market)), &active)
+}
+
+// queryIndex retrieves the tickers matching keyCond in index, and filter when
+// it is set. The indexes are ranged by ticker, so tickers come ordered by
+// symbol.
+func (r *tickerRepository) queryIndex(ctx context.Context, index string, keyCond expression.KeyConditionBuilder, filter *expression.ConditionBuilder) ([]models.Ticker, error) {
+	builder := expression.NewBuilder().WithKeyCondition(keyCond)
+	if filter != nil {
+		builder = builder.WithFilter(*filter)
+	}
+	expr, err := builder.Build()
 	if err != nil {
 		return nil, fmt.Errorf("failed to build expression: %w", err)
 	}
@@ -83,21 +141,22 @@ func (r *tickerRepository) GetActiveTickers(ctx context.Context) ([]models.Ticke
 	var lastEvaluatedKey map[string]types.AttributeValue
 
 	for {
-		input := &dynamodb.ScanInput{
+		input := &dynamodb.QueryInput{
 			TableName:                 aws.String(r.tableName),
+			IndexName:                 aws.String(index),
+			KeyConditionExpression:    expr.KeyCondition(),
 			FilterExpression:          expr.Filter(),
 			ExpressionAttributeNames:  expr.Names(),
 			ExpressionAttributeValues: expr.Values(),
-			Limit:                     aws.Int32(100),
 		}
 
 		if lastEvaluatedKey != nil {
 			input.ExclusiveStartKey = lastEvaluatedKey
 		}
 
-		result, err := r.client.Scan(ctx, input)
+		result, err := r.client.Query(ctx, input)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan active tickers: %w", err)
+			return nil, fmt.Errorf("failed to query tickers from %s: %w", index, err)
 		}
 
 		var batch []models.Ticker
@@ -116,3 +175,118 @@ func (r *tickerRepository) GetActiveTickers(ctx context.Context) ([]models.Ticke
 
 	return tickers, nil
 }
+
+// PutTickers creates or replaces tickers
+func (r *tickerRepository) PutTickers(ctx context.Context, tickers []models.Ticker) error {
+	requests := make([]types.WriteRequest, 0, len(tickers))
+	for i := range tickers {
+		item, err := attributevalue.MarshalMap(tickers[i])
+		if err != nil {
+			return fmt.Errorf("failed to marshal ticker: %w", err)
+		}
+		requests = append(requests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: item},
+		})
+	}
+
+	return batchWrite(ctx, r.client, r.tableName, requests)
+}
+
+// PutTicker creates a ticker, failing if its symbol already exists
+func (r *tickerRepository) PutTicker(ctx context.Context, ticker *models.Ticker) error {
+	cond := expression.AttributeNotExists(expression.Name("ticker"))
+	if err := r.putTicker(ctx, ticker, cond); err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrTickerExists{Symbol: ticker.Ticker}
+		}
+		return fmt.Errorf("failed to put ticker %s: %w", ticker.Ticker, err)
+	}
+	return nil
+}
+
+// UpdateTicker replaces an existing ticker
+func (r *tickerRepository) UpdateTicker(ctx context.Context, ticker *models.Ticker) error {
+	cond := expression.AttributeExists(expression.Name("ticker"))
+	if err := r.putTicker(ctx, ticker, cond); err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrTickerNotFound{Symbol: ticker.Ticker}
+		}
+		return fmt.Errorf("failed to update ticker %s: %w", ticker.Ticker, err)
+	}
+	return nil
+}
+
+// putTicker validates ticker and writes it if cond holds
+func (r *tickerRepository) putTicker(ctx context.Context, ticker *models.Ticker, cond expression.ConditionBuilder) error {
+	if err := ticker.Validate(); err != nil {
+		return fmt.Errorf("invalid ticker: %w", err)
+	}
+
+	item, err := attributevalue.MarshalMap(ticker)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ticker: %w", err)
+	}
+
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	return err
+}
+
+// DeleteTicker deletes a ticker, failing if it does not exist. Its daily
+// summaries are kept; the admin purge removes those.
+func (r *tickerRepository) DeleteTicker(ctx context.Context, symbol string) error {
+	cond := expression.AttributeExists(expression.Name("ticker"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"ticker": &types.AttributeValueMemberS{Value: symbol},
+		},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return ErrTickerNotFound{Symbol: symbol}
+		}
+		return fmt.Errorf("failed to delete ticker %s: %w", symbol, err)
+	}
+
+	return nil
+}
+
+// scanTickers retrieves the tickers matching filter from tables without the
+// index that would hold them
+func (r *tickerRepository) scanTickers(ctx context.Context, filter expression.ConditionBuilder) ([]models.Ticker, error) {
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	tickers, err := scanAllInto[models.Ticker](ctx, r.client, &dynamodb.ScanInput{
+		TableName:                 aws.String(r.tableName),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}, r.indexes.ScanSegments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan tickers: %w", err)
+	}
+	return tickers, nil
+}
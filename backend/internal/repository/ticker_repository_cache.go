@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/cache"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// tickerCachePrefix namespaces the cached tickers; bump its version when
+	// the cached encoding changes
+	tickerCachePrefix = "tickers:v1:"
+	activeTickersKey  = tickerCachePrefix + "active"
+)
+
+// TickerCacheTTLs bound how long cached ticker reads are served
+type TickerCacheTTLs struct {
+	Ticker        time.Duration
+	ActiveTickers time.Duration
+}
+
+// CachedTickerRepository is a TickerRepository reading through a cache
+type CachedTickerRepository interface {
+	TickerRepository
+	// Invalidate drops the cached symbols and the cached active tickers. Write
+	// paths that bypass this repository must call it.
+	Invalidate(ctx context.Context, symbols ...string) error
+}
+
+// cachedTickerRepository caches GetTicker and GetActiveTickers. Unknown
+// symbols are not cached. A failing cache is logged and bypassed.
+type cachedTickerRepository struct {
+	repo  TickerRepository
+	cache cache.Cache
+	ttls  TickerCacheTTLs
+	log   *zap.SugaredLogger
+}
+
+// NewCachedTickerRepository wraps repo in a read-through cache
+func NewCachedTickerRepository(repo TickerRepository, c cache.Cache, ttls TickerCacheTTLs, log *zap.SugaredLogger) CachedTickerRepository {
+	return &cachedTickerRepository{
+		repo:  repo,
+		cache: c,
+		ttls:  ttls,
+		log:   log,
+	}
+}
+
+// GetTicker retrieves a single ticker by symbol, from the cache when present
+func (r *cachedTickerRepository) GetTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	key := tickerKey(symbol)
+
+	var ticker models.Ticker
+	if r.load(ctx, key, &ticker) {
+		return &ticker, nil
+	}
+
+	fetched, err := r.repo.GetTicker(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, key, fetched, r.ttls.Ticker)
+	return fetched, nil
+}
+
+// GetActiveTickers retrieves all active tickers, from the cache when present
+func (r *cachedTickerRepository) GetActiveTickers(ctx context.Context) ([]models.Ticker, error) {
+	var tickers []models.Ticker
+	if r.load(ctx, activeTickersKey, &tickers) {
+		return tickers, nil
+	}
+
+	fetched, err := r.repo.GetActiveTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.store(ctx, activeTickersKey, fetched, r.ttls.ActiveTickers)
+	return fetched, nil
+}
+
+// GetTickersByExchange reads through to the repository uncached: the lists
+// of every exchange would have to be invalidated on each write
+func (r *cachedTickerRepository) GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error) {
+	return r.repo.GetTickersByExchange(ctx, exchange)
+}
+
+// GetTickersByMarket reads through to the repository uncached
+func (r *cachedTickerRepository) GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error) {
+	return r.repo.GetTickersByMarket(ctx, market)
+}
+
+// PutTickers writes through to the repository and invalidates the written tickers
+func (r *cachedTickerRepository) PutTickers(ctx context.Context, tickers []models.Ticker) error {
+	if err := r.repo.PutTickers(ctx, tickers); err != nil {
+		return err
+	}
+
+	symbols := make([]string, len(tickers))
+	for i, t := range tickers {
+		symbols[i] = t.Ticker
+	}
+	r.invalidate(ctx, symbols...)
+	return nil
+}
+
+// PutTicker writes through to the repository and invalidates the ticker
+func (r *cachedTickerRepository) PutTicker(ctx context.Context, ticker *models.Ticker) error {
+	if err := r.repo.PutTicker(ctx, ticker); err != nil {
+		return err
+	}
+	r.invalidate(ctx, ticker.Ticker)
+	return nil
+}
+
+// UpdateTicker writes through to the repository and invalidates the ticker
+func (r *cachedTickerRepository) UpdateTicker(ctx context.Context, ticker *models.Ticker) error {
+	if err := r.repo.UpdateTicker(ctx, ticker); err != nil {
+		return err
+	}
+	r.invalidate(ctx, ticker.Ticker)
+	return nil
+}
+
+// DeleteTicker deletes through the repository and invalidates the ticker
+func (r *cachedTickerRepository) DeleteTicker(ctx context.Context, symbol string) error {
+	if err := r.repo.DeleteTicker(ctx, symbol); err != nil {
+		return err
+	}
+	r.invalidate(ctx, symbol)
+	return nil
+}
+
+func (r *cachedTickerRepository) Invalidate(ctx context.Context, symbols ...string) error {
+	keys := make([]string, 0, len(symbols)+1)
+	keys = append(keys, activeTickersKey)
+	for _, symbol := range symbols {
+		keys = append(keys, tickerKey(symbol))
+	}
+	return r.cache.Delete(ctx, keys...)
+}
+
+// invalidate drops the written symbols after a write. A failure is only
+// logged; stale entries expire with their TTL.
+func (r *cachedTickerRepository) invalidate(ctx context.Context, symbols ...string) {
+	if err := r.Invalidate(ctx, symbols...); err != nil {
+		r.log.Warnw("failed to invalidate cached tickers", "count", len(symbols), "error", err)
+	}
+}
+
+// load decodes the cached value of key into out, reporting whether it was found
+func (r *cachedTickerRepository) load(ctx context.Context, key string, out any) bool {
+	data, ok, err := r.cache.Get(ctx, key)
+	if err != nil {
+		r.log.Warnw("ticker cache read failed", "key", key, "error", err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		r.log.Warnw("dropping undecodable cached ticker data", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// store caches value under key; a zero ttl disables caching
+func (r *cachedTickerRepository) store(ctx context.Context, key string, value any, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		r.log.Warnw("failed to encode ticker data for the cache", "key", key, "error", err)
+		return
+	}
+	if err := r.cache.Set(ctx, key, data, ttl); err != nil {
+		r.log.Warnw("ticker cache write failed", "key", key, "error", err)
+	}
+}
+
+func tickerKey(symbol string) string {
+	return tickerCachePrefix + "ticker:" + symbol
+}
@@ -0,0 +1,152 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newCachedTickers(t *testing.T) (repository.CachedTickerRepository, *repository.MockTickerRepository) {
+	t.Helper()
+	mockRepo := repository.NewMockTickerRepository()
+	mockRepo.SetTickers([]models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc.", Active: 1},
+		{Ticker: "MSFT", Name: "Microsoft Corp", Active: 1},
+	})
+
+	cached := repository.NewCachedTickerRepository(mockRepo, cache.NewMemory(), repository.TickerCacheTTLs{
+		Ticker:        time.Minute,
+		ActiveTickers: time.Minute,
+	}, zap.NewNop().Sugar())
+	return cached, mockRepo
+}
+
+func TestCachedTickerRepository_ReadsThrough(t *testing.T) {
+	ctx := context.Background()
+	cached, mockRepo := newCachedTickers(t)
+
+	for i := 0; i < 3; i++ {
+		ticker, err := cached.GetTicker(ctx, "AAPL")
+		require.NoError(t, err)
+		assert.Equal(t, "Apple Inc.", ticker.Name)
+
+		tickers, err := cached.GetActiveTickers(ctx)
+		require.NoError(t, err)
+		assert.Len(t, tickers, 2)
+	}
+	assert.Len(t, mockRepo.Calls.GetTicker, 1)
+	assert.Len(t, mockRepo.Calls.GetActiveTickers, 1)
+
+	// unknown symbols are not cached
+	for i := 0; i < 2; i++ {
+		_, err := cached.GetTicker(ctx, "NOPE")
+		assert.ErrorIs(t, err, repository.ErrTickerNotFound{Symbol: "NOPE"})
+	}
+	assert.Len(t, mockRepo.Calls.GetTicker, 3)
+}
+
+func TestCachedTickerRepository_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	cached, mockRepo := newCachedTickers(t)
+
+	_, err := cached.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	_, err = cached.GetActiveTickers(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, cached.PutTickers(ctx, []models.Ticker{{Ticker: "AAPL", Name: "Apple", Active: 1}}))
+
+	ticker, err := cached.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "Apple", ticker.Name, "writes invalidate the written tickers")
+	_, err = cached.GetActiveTickers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, mockRepo.Calls.GetActiveTickers, 2, "writes invalidate the active list")
+
+	// writes bypassing the decorator are picked up after an explicit invalidation
+	mockRepo.SetTickers([]models.Ticker{{Ticker: "MSFT", Name: "Microsoft", Active: 1}})
+	require.NoError(t, cached.Invalidate(ctx, "MSFT"))
+	ticker, err = cached.GetTicker(ctx, "MSFT")
+	require.NoError(t, err)
+	assert.Equal(t, "Microsoft", ticker.Name)
+}
+
+func TestCachedTickerRepository_SingleTickerWrites(t *testing.T) {
+	ctx := context.Background()
+	cached, mockRepo := newCachedTickers(t)
+
+	_, err := cached.GetActiveTickers(ctx)
+	require.NoError(t, err)
+	require.NoError(t, cached.PutTicker(ctx, &models.Ticker{Ticker: "NVDA", Name: "NVIDIA", Active: 1}))
+	tickers, err := cached.GetActiveTickers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, tickers, 3, "creates invalidate the active list")
+
+	_, err = cached.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	require.NoError(t, cached.UpdateTicker(ctx, &models.Ticker{Ticker: "AAPL", Name: "Apple", Active: 1}))
+	ticker, err := cached.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "Apple", ticker.Name, "updates invalidate the ticker")
+
+	require.NoError(t, cached.DeleteTicker(ctx, "AAPL"))
+	_, err = cached.GetTicker(ctx, "AAPL")
+	assert.ErrorIs(t, err, repository.ErrTickerNotFound{Symbol: "AAPL"}, "deletes invalidate the ticker")
+
+	// failed writes leave the cache alone
+	_, err = cached.GetActiveTickers(ctx)
+	require.NoError(t, err)
+	err = cached.UpdateTicker(ctx, &models.Ticker{Ticker: "NOPE", Name: "Nope"})
+	assert.ErrorIs(t, err, repository.ErrTickerNotFound{Symbol: "NOPE"})
+	_, err = cached.GetActiveTickers(ctx)
+	require.NoError(t, err)
+	assert.Len(t, mockRepo.Calls.GetActiveTickers, 3)
+}
+
+// TestCachedTickerRepository_Concurrent reads through the cache while tickers
+// are written and invalidated, for the race detector: go test -race
+func TestCachedTickerRepository_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	cached, _ := newCachedTickers(t)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				switch (w + i) % 4 {
+				case 0:
+					assert.NoError(t, cached.UpdateTicker(ctx, &models.Ticker{Ticker: "AAPL", Name: fmt.Sprintf("Apple %d", i), Active: 1}))
+				case 1:
+					assert.NoError(t, cached.Invalidate(ctx, "AAPL", "MSFT"))
+				case 2:
+					tickers, err := cached.GetActiveTickers(ctx)
+					assert.NoError(t, err)
+					assert.Len(t, tickers, 2)
+				default:
+					ticker, err := cached.GetTicker(ctx, "AAPL")
+					if assert.NoError(t, err) {
+						assert.Equal(t, "AAPL", ticker.Ticker)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, cached.UpdateTicker(ctx, &models.Ticker{Ticker: "AAPL", Name: "Apple", Active: 1}))
+	ticker, err := cached.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "Apple", ticker.Name, "the last write is read once the writers are done")
+}
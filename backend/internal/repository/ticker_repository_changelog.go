@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"time"
+)
+
+// changeLoggingTickerRepository records every ticker written through it in a
+// change log, after the write succeeded
+type changeLoggingTickerRepository struct {
+	TickerRepository
+	changes TickerChangeRepository
+	now     func() time.Time
+}
+
+// NewChangeLoggingTickerRepository wraps repo so its writes are recorded in
+// changes. A change that fails to be recorded fails the write, although the
+// ticker was stored; writing it again records it.
+func NewChangeLoggingTickerRepository(repo TickerRepository, changes TickerChangeRepository) TickerRepository {
+	return &changeLoggingTickerRepository{
+		TickerRepository: repo,
+		changes:          changes,
+		now:              time.Now,
+	}
+}
+
+// PutTickers writes tickers and records each as updated
+func (r *changeLoggingTickerRepository) PutTickers(ctx context.Context, tickers []models.Ticker) error {
+	if err := r.TickerRepository.PutTickers(ctx, tickers); err != nil {
+		return err
+	}
+	now := r.now()
+	changes := make([]models.TickerChange, len(tickers))
+	for i := range tickers {
+		changes[i] = tickerChange(now, tickers[i].Ticker, models.TickerChangeUpdated, &tickers[i])
+	}
+	return r.record(ctx, changes...)
+}
+
+func (r *changeLoggingTickerRepository) PutTicker(ctx context.Context, ticker *models.Ticker) error {
+	if err := r.TickerRepository.PutTicker(ctx, ticker); err != nil {
+		return err
+	}
+	return r.record(ctx, tickerChange(r.now(), ticker.Ticker, models.TickerChangeCreated, ticker))
+}
+
+func (r *changeLoggingTickerRepository) UpdateTicker(ctx context.Context, ticker *models.Ticker) error {
+	if err := r.TickerRepository.UpdateTicker(ctx, ticker); err != nil {
+		return err
+	}
+	return r.record(ctx, tickerChange(r.now(), ticker.Ticker, models.TickerChangeUpdated, ticker))
+}
+
+func (r *changeLoggingTickerRepository) DeleteTicker(ctx context.Context, symbol string) error {
+	if err := r.TickerRepository.DeleteTicker(ctx, symbol); err != nil {
+		return err
+	}
+	return r.record(ctx, tickerChange(r.now(), symbol, models.TickerChangeDeleted, nil))
+}
+
+func (r *changeLoggingTickerRepository) record(ctx context.Context, changes ...models.TickerChange) error {
+	if err := r.changes.Record(ctx, changes); err != nil {
+		return fmt.Errorf("ticker written but its change was not recorded: %w", err)
+	}
+	return nil
+}
+
+func tickerChange(at time.Time, symbol, change string, ticker *models.Ticker) models.TickerChange {
+	var written *models.Ticker
+	if ticker != nil {
+		copied := *ticker
+		written = &copied
+	}
+	return models.TickerChange{
+		Seq:        TickerChangeSeq(at, symbol),
+		Symbol:     symbol,
+		Change:     change,
+		Ticker:     written,
+		ChangedUTC: at.Unix(),
+	}
+}
@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTickerChangeSeq(t *testing.T) {
+	at := time.Unix(1741323600, 123)
+	seq := repository.TickerChangeSeq(at, "AAPL")
+	assert.Equal(t, "1741323600000000123#AAPL", seq)
+
+	parsed, err := repository.TickerChangeTime(seq)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(parsed))
+
+	bound := repository.TickerChangeSeq(at, "")
+	assert.Greater(t, bound, repository.TickerChangeSeq(at, "ZZZZ"), "the bound follows every change at its time")
+	assert.Less(t, bound, repository.TickerChangeSeq(at.Add(time.Nanosecond), "A"))
+	parsed, err = repository.TickerChangeTime(bound)
+	require.NoError(t, err)
+	assert.True(t, at.Equal(parsed))
+
+	for _, invalid := range []string{"", "AAPL", "123#AAPL", "17413236000000001x3#AAPL"} {
+		_, err := repository.TickerChangeTime(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+// failingChanges fails to record changes
+type failingChanges struct {
+	repository.TickerChangeRepository
+}
+
+func (failingChanges) Record(ctx context.Context, changes []models.TickerChange) error {
+	return errors.New("table unavailable")
+}
+
+func TestChangeLoggingTickerRepository(t *testing.T) {
+	ctx := context.Background()
+	changes := repository.NewMemoryTickerChangeRepository(time.Hour)
+	repo := repository.NewChangeLoggingTickerRepository(repository.NewMemoryTickerRepository(nil), changes)
+
+	nvda := &models.Ticker{Ticker: "NVDA", Name: "NVIDIA", Market: "stocks", Locale: "us", Active: 1}
+	require.NoError(t, repo.PutTicker(ctx, nvda))
+	nvda.Sector = "Technology"
+	require.NoError(t, repo.UpdateTicker(ctx, nvda))
+	require.NoError(t, repo.PutTickers(ctx, []models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", Active: 1},
+	}))
+	require.NoError(t, repo.DeleteTicker(ctx, "NVDA"))
+	assert.Error(t, repo.DeleteTicker(ctx, "NVDA"), "failed writes are not recorded")
+
+	recorded, err := changes.After(ctx, "", repository.TickerChangeSeq(time.Now().Add(time.Second), ""), 10)
+	require.NoError(t, err)
+	require.Len(t, recorded, 4)
+	assert.Equal(t, []string{"NVDA", "NVDA", "AAPL", "NVDA"},
+		[]string{recorded[0].Symbol, recorded[1].Symbol, recorded[2].Symbol, recorded[3].Symbol})
+	assert.Equal(t, []string{models.TickerChangeCreated, models.TickerChangeUpdated, models.TickerChangeUpdated, models.TickerChangeDeleted},
+		[]string{recorded[0].Change, recorded[1].Change, recorded[2].Change, recorded[3].Change})
+	assert.Empty(t, recorded[0].Ticker.Sector, "the change holds a copy of the written ticker")
+	assert.Equal(t, "Technology", recorded[1].Ticker.Sector)
+	assert.Nil(t, recorded[3].Ticker)
+
+	after, err := changes.After(ctx, recorded[1].Seq, recorded[3].Seq, 1)
+	require.NoError(t, err)
+	assert.Equal(t, recorded[2:3], after, "changes follow after, up to limit")
+
+	failing := repository.NewChangeLoggingTickerRepository(repository.NewMemoryTickerRepository(nil), failingChanges{})
+	assert.ErrorContains(t, failing.PutTicker(ctx, nvda), "change was not recorded")
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"sort"
+	"sync"
+)
+
+// memoryTickerRepository implements TickerRepository in process memory
+type memoryTickerRepository struct {
+	mu      sync.RWMutex
+	tickers map[string]models.Ticker
+}
+
+// NewMemoryTickerRepository creates a ticker repository in process memory
+// holding tickers. Tickers are copied in and out, so callers never share them.
+func NewMemoryTickerRepository(tickers []models.Ticker) TickerRepository {
+	r := &memoryTickerRepository{tickers: make(map[string]models.Ticker, len(tickers))}
+	for _, t := range tickers {
+		r.tickers[t.Ticker] = t
+	}
+	return r
+}
+
+// GetTicker retrieves a single ticker by symbol
+func (r *memoryTickerRepository) GetTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ticker, ok := r.tickers[symbol]
+	if !ok {
+		return nil, ErrTickerNotFound{Symbol: symbol}
+	}
+	return &ticker, nil
+}
+
+// GetActiveTickers retrieves all active tickers ordered by symbol, as the
+// active index returns them
+func (r *memoryTickerRepository) GetActiveTickers(ctx context.Context) ([]models.Ticker, error) {
+	return r.activeTickers(func(t *models.Ticker) bool { return true }), nil
+}
+
+// GetTickersByExchange retrieves the active tickers listed on exchange
+// ordered by symbol
+func (r *memoryTickerRepository) GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error) {
+	return r.activeTickers(func(t *models.Ticker) bool { return t.PrimaryExchange == exchange }), nil
+}
+
+// GetTickersByMarket retrieves the active tickers of market ordered by symbol
+func (r *memoryTickerRepository) GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error) {
+	return r.activeTickers(func(t *models.Ticker) bool { return t.Market == market }), nil
+}
+
+// activeTickers returns the active tickers matching match ordered by symbol
+func (r *memoryTickerRepository) activeTickers(match func(t *models.Ticker) bool) []models.Ticker {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var tickers []models.Ticker
+	for _, t := range r.tickers {
+		if t.Active == 1 && match(&t) {
+			tickers = append(tickers, t)
+		}
+	}
+	sort.Slice(tickers, func(i, j int) bool { return tickers[i].Ticker < tickers[j].Ticker })
+	return tickers
+}
+
+// PutTickers creates or replaces tickers
+func (r *memoryTickerRepository) PutTickers(ctx context.Context, tickers []models.Ticker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range tickers {
+		r.tickers[t.Ticker] = t
+	}
+	return nil
+}
+
+// PutTicker creates a ticker, failing if its symbol already exists
+func (r *memoryTickerRepository) PutTicker(ctx context.Context, ticker *models.Ticker) error {
+	return r.putTicker(ticker, false)
+}
+
+// UpdateTicker replaces an existing ticker
+func (r *memoryTickerRepository) UpdateTicker(ctx context.Context, ticker *models.Ticker) error {
+	return r.putTicker(ticker, true)
+}
+
+// putTicker validates ticker and writes it if its symbol's existence matches exists
+func (r *memoryTickerRepository) putTicker(ticker *models.Ticker, exists bool) error {
+	if err := ticker.Validate(); err != nil {
+		return fmt.Errorf("invalid ticker: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.tickers[ticker.Ticker]
+	switch {
+	case ok && !exists:
+		return ErrTickerExists{Symbol: ticker.Ticker}
+	case !ok && exists:
+		return ErrTickerNotFound{Symbol: ticker.Ticker}
+	}
+	r.tickers[ticker.Ticker] = *ticker
+	return nil
+}
+
+// DeleteTicker deletes a ticker, failing if it does not exist
+func (r *memoryTickerRepository) DeleteTicker(ctx context.Context, symbol string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tickers[symbol]; !ok {
+		return ErrTickerNotFound{Symbol: symbol}
+	}
+	delete(r.tickers, symbol)
+	return nil
+}
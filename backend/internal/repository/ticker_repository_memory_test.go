@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTickerRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryTickerRepository([]models.Ticker{
+		{Ticker: "MSFT", Name: "Microsoft", Market: "stocks", Locale: "us", PrimaryExchange: "XNAS", Active: 1},
+		{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", PrimaryExchange: "XNAS", Active: 1},
+		{Ticker: "IBM", Name: "IBM", Market: "stocks", Locale: "us", PrimaryExchange: "XNYS", Active: 1},
+		{Ticker: "X:BTCUSD", Name: "Bitcoin", Market: "crypto", Locale: "global", Active: 1},
+		{Ticker: "OLD", Name: "Delisted", Market: "stocks", Locale: "us", PrimaryExchange: "XNAS"},
+	})
+
+	active, err := repo.GetActiveTickers(ctx)
+	require.NoError(t, err)
+	require.Len(t, active, 4)
+	assert.Equal(t, "AAPL", active[0].Ticker, "active tickers are ordered by symbol")
+	assert.Equal(t, "MSFT", active[2].Ticker)
+
+	nasdaq, err := repo.GetTickersByExchange(ctx, "XNAS")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAPL", "MSFT"}, []string{nasdaq[0].Ticker, nasdaq[1].Ticker}, "only active tickers are listed")
+	assert.Len(t, nasdaq, 2)
+	crypto, err := repo.GetTickersByMarket(ctx, "crypto")
+	require.NoError(t, err)
+	require.Len(t, crypto, 1)
+	assert.Equal(t, "X:BTCUSD", crypto[0].Ticker)
+
+	ticker, err := repo.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	ticker.Name = "changed"
+	stored, _ := repo.GetTicker(ctx, "AAPL")
+	assert.Equal(t, "Apple Inc.", stored.Name, "returned tickers are copies")
+
+	nvda := &models.Ticker{Ticker: "NVDA", Name: "NVIDIA", Market: "stocks", Locale: "us", Active: 1}
+	require.NoError(t, repo.PutTicker(ctx, nvda))
+	assert.Equal(t, repository.ErrTickerExists{Symbol: "NVDA"}, repo.PutTicker(ctx, nvda))
+	assert.Error(t, repo.PutTicker(ctx, &models.Ticker{Ticker: "BAD"}), "tickers are validated")
+
+	nvda.Sector = "Technology"
+	require.NoError(t, repo.UpdateTicker(ctx, nvda))
+	stored, _ = repo.GetTicker(ctx, "NVDA")
+	assert.Equal(t, "Technology", stored.Sector)
+	assert.Equal(t, repository.ErrTickerNotFound{Symbol: "TSLA"},
+		repo.UpdateTicker(ctx, &models.Ticker{Ticker: "TSLA", Name: "Tesla", Market: "stocks", Locale: "us"}))
+
+	require.NoError(t, repo.DeleteTicker(ctx, "NVDA"))
+	assert.Equal(t, repository.ErrTickerNotFound{Symbol: "NVDA"}, repo.DeleteTicker(ctx, "NVDA"))
+}
+
+func TestOpenMemoryStore(t *testing.T) {
+	store, err := repository.OpenMemoryStore(repository.BackendDynamoDB, true, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, store)
+
+	_, err = repository.OpenMemoryStore("sqlite", true, time.Hour)
+	assert.Error(t, err)
+
+	store, err = repository.OpenMemoryStore(repository.BackendMemory, true, time.Hour)
+	require.NoError(t, err)
+	tickers, err := store.Tickers.GetActiveTickers(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, tickers, "the fixture is loaded")
+	for _, ticker := range tickers {
+		assert.NoError(t, ticker.Validate(), ticker.Ticker)
+	}
+
+	store, err = repository.OpenMemoryStore(repository.BackendMemory, false, time.Hour)
+	require.NoError(t, err)
+	tickers, err = store.Tickers.GetActiveTickers(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, tickers)
+}
@@ -12,8 +12,14 @@ type MockTickerRepository struct {
 	tickers map[string]*models.Ticker
 
 	// Function fields for custom behavior in tests
-	GetTickerFunc        func(ctx context.Context, symbol string) (*models.Ticker, error)
-	GetActiveTickersFunc func(ctx context.Context) ([]models.Ticker, error)
+	GetTickerFunc            func(ctx context.Context, symbol string) (*models.Ticker, error)
+	GetActiveTickersFunc     func(ctx context.Context) ([]models.Ticker, error)
+	GetTickersByExchangeFunc func(ctx context.Context, exchange string) ([]models.Ticker, error)
+	GetTickersByMarketFunc   func(ctx context.Context, market string) ([]models.Ticker, error)
+	PutTickersFunc           func(ctx context.Context, tickers []models.Ticker) error
+	PutTickerFunc            func(ctx context.Context, ticker *models.Ticker) error
+	UpdateTickerFunc         func(ctx context.Context, ticker *models.Ticker) error
+	DeleteTickerFunc         func(ctx context.Context, symbol string) error
 
 	// Call tracking
 	Calls struct {
@@ -21,7 +27,13 @@ type MockTickerRepository struct {
 			Ctx    context.Context
 			Symbol string
 		}
-		GetActiveTickers []context.Context
+		GetActiveTickers     []context.Context
+		GetTickersByExchange []string
+		GetTickersByMarket   []string
+		PutTickers           [][]models.Ticker
+		PutTicker            []models.Ticker
+		UpdateTicker         []models.Ticker
+		DeleteTicker         []string
 	}
 }
 
@@ -79,6 +91,138 @@ func (m *MockTickerRepository) GetActiveTickers(ctx context.Context) ([]models.T
 	return tickers, nil
 }
 
+// GetTickersByExchange mock implementation
+func (m *MockTickerRepository) GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error) {
+	m.mu.Lock()
+	m.Calls.GetTickersByExchange = append(m.Calls.GetTickersByExchange, exchange)
+	m.mu.Unlock()
+
+	if m.GetTickersByExchangeFunc != nil {
+		return m.GetTickersByExchangeFunc(ctx, exchange)
+	}
+
+	// Default implementation
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tickers []models.Ticker
+	for _, ticker := range m.tickers {
+		if ticker.Active == 1 && ticker.PrimaryExchange == exchange {
+			tickers = append(tickers, *ticker)
+		}
+	}
+	return tickers, nil
+}
+
+// GetTickersByMarket mock implementation
+func (m *MockTickerRepository) GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error) {
+	m.mu.Lock()
+	m.Calls.GetTickersByMarket = append(m.Calls.GetTickersByMarket, market)
+	m.mu.Unlock()
+
+	if m.GetTickersByMarketFunc != nil {
+		return m.GetTickersByMarketFunc(ctx, market)
+	}
+
+	// Default implementation
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tickers []models.Ticker
+	for _, ticker := range m.tickers {
+		if ticker.Active == 1 && ticker.Market == market {
+			tickers = append(tickers, *ticker)
+		}
+	}
+	return tickers, nil
+}
+
+// PutTickers mock implementation
+func (m *MockTickerRepository) PutTickers(ctx context.Context, tickers []models.Ticker) error {
+	m.mu.Lock()
+	m.Calls.PutTickers = append(m.Calls.PutTickers, tickers)
+	m.mu.Unlock()
+
+	if m.PutTickersFunc != nil {
+		return m.PutTickersFunc(ctx, tickers)
+	}
+
+	// Default implementation
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range tickers {
+		ticker := tickers[i]
+		m.tickers[ticker.Ticker] = &ticker
+	}
+	return nil
+}
+
+// PutTicker mock implementation
+func (m *MockTickerRepository) PutTicker(ctx context.Context, ticker *models.Ticker) error {
+	m.mu.Lock()
+	m.Calls.PutTicker = append(m.Calls.PutTicker, *ticker)
+	m.mu.Unlock()
+
+	if m.PutTickerFunc != nil {
+		return m.PutTickerFunc(ctx, ticker)
+	}
+
+	// Default implementation
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tickers[ticker.Ticker]; exists {
+		return ErrTickerExists{Symbol: ticker.Ticker}
+	}
+	stored := *ticker
+	m.tickers[ticker.Ticker] = &stored
+	return nil
+}
+
+// UpdateTicker mock implementation
+func (m *MockTickerRepository) UpdateTicker(ctx context.Context, ticker *models.Ticker) error {
+	m.mu.Lock()
+	m.Calls.UpdateTicker = append(m.Calls.UpdateTicker, *ticker)
+	m.mu.Unlock()
+
+	if m.UpdateTickerFunc != nil {
+		return m.UpdateTickerFunc(ctx, ticker)
+	}
+
+	// Default implementation
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tickers[ticker.Ticker]; !exists {
+		return ErrTickerNotFound{Symbol: ticker.Ticker}
+	}
+	stored := *ticker
+	m.tickers[ticker.Ticker] = &stored
+	return nil
+}
+
+// DeleteTicker mock implementation
+func (m *MockTickerRepository) DeleteTicker(ctx context.Context, symbol string) error {
+	m.mu.Lock()
+	m.Calls.DeleteTicker = append(m.Calls.DeleteTicker, symbol)
+	m.mu.Unlock()
+
+	if m.DeleteTickerFunc != nil {
+		return m.DeleteTickerFunc(ctx, symbol)
+	}
+
+	// Default implementation
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.tickers[symbol]; !exists {
+		return ErrTickerNotFound{Symbol: symbol}
+	}
+	delete(m.tickers, symbol)
+	return nil
+}
+
 // Reset clears all calls and data
 func (m *MockTickerRepository) Reset() {
 	m.mu.Lock()
@@ -87,6 +231,10 @@ func (m *MockTickerRepository) Reset() {
 	m.tickers = make(map[string]*models.Ticker)
 	m.Calls.GetTicker = nil
 	m.Calls.GetActiveTickers = nil
+	m.Calls.PutTickers = nil
+	m.Calls.PutTicker = nil
+	m.Calls.UpdateTicker = nil
+	m.Calls.DeleteTicker = nil
 }
 
 // SetTickers sets the initial tickers for testing
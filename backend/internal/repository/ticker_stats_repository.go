@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// TickerStatsRepository defines the interface for materialized ticker
+// statistics, one item per ticker
+type TickerStatsRepository interface {
+	PutStats(ctx context.Context, stats *models.TickerStats) error
+	GetStats(ctx context.Context, symbol string) (*models.TickerStats, error)
+}
+
+// tickerStatsRepository implements TickerStatsRepository using DynamoDB
+type tickerStatsRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewTickerStatsRepository creates a new DynamoDB-backed ticker stats repository
+func NewTickerStatsRepository(client *dynamodb.Client, tableName string) TickerStatsRepository {
+	return &tickerStatsRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// PutStats stores the stats of a ticker, replacing the previous computation
+func (r *tickerStatsRepository) PutStats(ctx context.Context, stats *models.TickerStats) error {
+	item, err := attributevalue.MarshalMap(stats)
+	if err != nil {
+		return fmt.Errorf("failed to marshal ticker stats: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put stats for %s: %w", stats.Ticker, err)
+	}
+
+	return nil
+}
+
+// GetStats retrieves the stats of a ticker, or nil when none were computed
+func (r *tickerStatsRepository) GetStats(ctx context.Context, symbol string) (*models.TickerStats, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"ticker": &types.AttributeValueMemberS{Value: symbol},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats for %s: %w", symbol, err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var stats models.TickerStats
+	if err := attributevalue.UnmarshalMap(result.Item, &stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ticker stats: %w", err)
+	}
+	return &stats, nil
+}
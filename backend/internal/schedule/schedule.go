@@ -0,0 +1,109 @@
+// Package schedule computes the runs of schedules kept as a local time of day
+// in a time zone, like 08:00 in America/Toronto, rather than as fixed UTC
+// times, so they follow their zone across daylight saving time changes.
+package schedule
+
+import (
+	"fmt"
+	"sync"
+	"time"
+	_ "time/tzdata" // users pick any IANA zone, hosts may lack zoneinfo
+)
+
+// localTimeLayout is the layout of a schedule's local time
+const localTimeLayout = "15:04"
+
+// Schedule runs once a day at a wall-clock time in a time zone
+type Schedule struct {
+	// Timezone is an IANA time zone name, like America/Toronto
+	Timezone string `json:"timezone" dynamodbav:"timezone"`
+	// LocalTime is the time of day in the zone, HH:MM on a 24-hour clock
+	LocalTime string `json:"localTime" dynamodbav:"localTime"`
+}
+
+// locations caches the zones schedules were loaded in, by name
+var locations sync.Map
+
+// Validate checks that the schedule names a known zone and a time of day
+func (s Schedule) Validate() error {
+	_, _, err := s.parse()
+	return err
+}
+
+func (s Schedule) parse() (*time.Location, time.Duration, error) {
+	if s.Timezone == "" || s.Timezone == "Local" {
+		return nil, 0, fmt.Errorf("timezone must be an IANA time zone name, like America/Toronto")
+	}
+	loc, err := location(s.Timezone)
+	if err != nil {
+		return nil, 0, fmt.Errorf("timezone %q is not a known IANA time zone", s.Timezone)
+	}
+
+	t, err := time.Parse(localTimeLayout, s.LocalTime)
+	if err != nil || len(s.LocalTime) != len(localTimeLayout) {
+		return nil, 0, fmt.Errorf("local time must be HH:MM on a 24-hour clock")
+	}
+	return loc, time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func location(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locations.Store(name, loc)
+	return loc, nil
+}
+
+// Next returns the first run of the schedule strictly after t
+func (s Schedule) Next(t time.Time) (time.Time, error) {
+	loc, at, err := s.parse()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	y, m, d := t.In(loc).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	for {
+		y, m, d := day.Date()
+		if run := on(loc, y, m, d, at); run.After(t) {
+			return run, nil
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// on returns when the wall clock of loc reads at on the date. A time the
+// clocks skip, going forward, runs as late as the skip, as 02:30 runs at
+// 03:30 when 02:00 becomes 03:00; a time they read twice, going back, runs
+// the first time.
+func on(loc *time.Location, y int, m time.Month, d int, at time.Duration) time.Time {
+	wall := time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Add(at)
+	// A change of offset around the time shows as different offsets half a
+	// day either side of it
+	probe := time.Date(y, m, d, 0, 0, 0, 0, loc).Add(at)
+	_, before := probe.Add(-12 * time.Hour).Zone()
+	_, after := probe.Add(12 * time.Hour).Zone()
+
+	early, late := wall.Add(-time.Duration(before)*time.Second), wall.Add(-time.Duration(after)*time.Second)
+	if late.Before(early) {
+		early, late = late, early
+	}
+	for _, run := range []time.Time{early, late} {
+		if reads(run.In(loc), y, m, d, at) {
+			return run
+		}
+	}
+	// Skipped: the offset before the change puts the run the skip later
+	return wall.Add(-time.Duration(before) * time.Second)
+}
+
+// reads reports whether t, in its zone, is the date and time of day
+func reads(t time.Time, y int, m time.Month, d int, at time.Duration) bool {
+	ty, tm, td := t.Date()
+	return ty == y && tm == m && td == d &&
+		time.Duration(t.Hour())*time.Hour+time.Duration(t.Minute())*time.Minute == at && t.Second() == 0
+}
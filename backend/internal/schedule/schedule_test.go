@@ -0,0 +1,106 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Validate(t *testing.T) {
+	assert.NoError(t, Schedule{Timezone: "America/Toronto", LocalTime: "08:00"}.Validate())
+	assert.NoError(t, Schedule{Timezone: "UTC", LocalTime: "23:59"}.Validate())
+
+	for _, s := range []Schedule{
+		{LocalTime: "08:00"},
+		{Timezone: "Local", LocalTime: "08:00"},
+		{Timezone: "Mars/Olympus_Mons", LocalTime: "08:00"},
+		{Timezone: "Europe/Paris"},
+		{Timezone: "Europe/Paris", LocalTime: "8:00"},
+		{Timezone: "Europe/Paris", LocalTime: "24:00"},
+		{Timezone: "Europe/Paris", LocalTime: "8am"},
+	} {
+		assert.Error(t, s.Validate(), "%+v", s)
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		after    time.Time
+		want     time.Time
+	}{
+		{
+			name:     "later the same day",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "08:00"},
+			after:    time.Date(2025, 3, 5, 6, 0, 0, 0, toronto),
+			want:     time.Date(2025, 3, 5, 8, 0, 0, 0, toronto),
+		},
+		{
+			name:     "strictly after",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "08:00"},
+			after:    time.Date(2025, 3, 5, 8, 0, 0, 0, toronto),
+			want:     time.Date(2025, 3, 6, 8, 0, 0, 0, toronto),
+		},
+		{
+			name:     "the date is the zone's",
+			schedule: Schedule{Timezone: "Asia/Kolkata", LocalTime: "07:30"},
+			after:    time.Date(2025, 3, 5, 23, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 3, 6, 7, 30, 0, 0, kolkata),
+		},
+		{
+			name:     "8am stays 8am across spring forward",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "08:00"},
+			after:    time.Date(2025, 3, 8, 9, 0, 0, 0, toronto),
+			want:     time.Date(2025, 3, 9, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "8am stays 8am across fall back",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "08:00"},
+			after:    time.Date(2025, 11, 1, 9, 0, 0, 0, toronto),
+			want:     time.Date(2025, 11, 2, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "a skipped time runs as late as the skip",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "02:30"},
+			after:    time.Date(2025, 3, 8, 12, 0, 0, 0, toronto),
+			want:     time.Date(2025, 3, 9, 7, 30, 0, 0, time.UTC), // 03:30 EDT
+		},
+		{
+			name:     "a repeated time runs the first time",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "01:30"},
+			after:    time.Date(2025, 11, 1, 12, 0, 0, 0, toronto),
+			want:     time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC), // 01:30 EDT
+		},
+		{
+			name:     "not again the second time",
+			schedule: Schedule{Timezone: "America/Toronto", LocalTime: "01:30"},
+			after:    time.Date(2025, 11, 2, 5, 30, 0, 0, time.UTC),
+			want:     time.Date(2025, 11, 3, 6, 30, 0, 0, time.UTC),
+		},
+		{
+			name:     "the end of a month",
+			schedule: Schedule{Timezone: "Europe/London", LocalTime: "18:00"},
+			after:    time.Date(2025, 1, 31, 19, 0, 0, 0, time.UTC),
+			want:     time.Date(2025, 2, 1, 18, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.schedule.Next(tt.after)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "want %s, got %s", tt.want.UTC(), got.UTC())
+		})
+	}
+
+	_, err = Schedule{Timezone: "Nowhere"}.Next(time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,396 @@
+package screener
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidExpression rejects a filter that does not parse
+var ErrInvalidExpression = errors.New("invalid filter expression")
+
+// Bounds on filter expressions, so a request cannot make parsing or
+// evaluating them over every ticker expensive
+const (
+	MaxExpressionLength = 1000
+	maxComparisons      = 50
+	maxDepth            = 20
+)
+
+// Expr is a parsed filter expression, evaluated against the fields of one
+// ticker
+type Expr interface {
+	// Eval reports whether the fields satisfy the expression. A comparison
+	// of a field the ticker lacks is false.
+	Eval(fields map[string]float64) bool
+	String() string
+}
+
+// operand is a field name or a number
+type operand struct {
+	field string
+	value float64
+}
+
+func (o operand) resolve(fields map[string]float64) (float64, bool) {
+	if o.field == "" {
+		return o.value, true
+	}
+	v, ok := fields[o.field]
+	return v, ok
+}
+
+func (o operand) String() string {
+	if o.field != "" {
+		return o.field
+	}
+	return strconv.FormatFloat(o.value, 'g', -1, 64)
+}
+
+type comparison struct {
+	left, right operand
+	op          string
+}
+
+func (c comparison) Eval(fields map[string]float64) bool {
+	l, ok := c.left.resolve(fields)
+	if !ok {
+		return false
+	}
+	r, ok := c.right.resolve(fields)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case ">":
+		return l > r
+	case ">=":
+		return l >= r
+	case "<":
+		return l < r
+	case "<=":
+		return l <= r
+	case "=":
+		return l == r
+	case "!=":
+		return l != r
+	}
+	return false
+}
+
+func (c comparison) String() string {
+	return c.left.String() + " " + c.op + " " + c.right.String()
+}
+
+type and struct{ left, right Expr }
+
+func (e and) Eval(fields map[string]float64) bool {
+	return e.left.Eval(fields) && e.right.Eval(fields)
+}
+
+func (e and) String() string { return "(" + e.left.String() + " AND " + e.right.String() + ")" }
+
+type or struct{ left, right Expr }
+
+func (e or) Eval(fields map[string]float64) bool {
+	return e.left.Eval(fields) || e.right.Eval(fields)
+}
+
+func (e or) String() string { return "(" + e.left.String() + " OR " + e.right.String() + ")" }
+
+type not struct{ expr Expr }
+
+func (e not) Eval(fields map[string]float64) bool { return !e.expr.Eval(fields) }
+
+func (e not) String() string { return "NOT " + e.expr.String() }
+
+// all matches every ticker; it is the expression of an empty filter
+type all struct{}
+
+func (all) Eval(map[string]float64) bool { return true }
+
+func (all) String() string { return "" }
+
+// Parse parses a filter such as "close > 100 AND volume > 5M", combining
+// comparisons of fields and numbers with AND, OR, NOT and parentheses. AND
+// binds tighter than OR; keywords and fields are case insensitive. Numbers
+// take an exponent, a K, M or B suffix for thousands, millions and billions,
+// or % for hundredths. Fields must be among known.
+func Parse(filter string, known map[string]string) (Expr, error) {
+	if len(filter) > MaxExpressionLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidExpression, MaxExpressionLength)
+	}
+	return parse(filter, known)
+}
+
+// parse parses a filter of any length
+func parse(filter string, known map[string]string) (Expr, error) {
+	tokens, err := lex(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 1 {
+		return all{}, nil
+	}
+
+	p := &parser{tokens: tokens, known: known}
+	expr, err := p.or(0)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.text)
+	}
+	return expr, nil
+}
+
+// Fields returns the fields expr refers to
+func Fields(expr Expr) []string {
+	var fields []string
+	var walk func(Expr)
+	walk = func(e Expr) {
+		switch e := e.(type) {
+		case comparison:
+			for _, o := range []operand{e.left, e.right} {
+				if o.field != "" {
+					fields = append(fields, o.field)
+				}
+			}
+		case and:
+			walk(e.left)
+			walk(e.right)
+		case or:
+			walk(e.left)
+			walk(e.right)
+		case not:
+			walk(e.expr)
+		}
+	}
+	walk(expr)
+	return fields
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	// pos is the byte offset of the token in the filter
+	pos int
+}
+
+// lex splits a filter into tokens, ending with a tokenEOF
+func lex(filter string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(filter); {
+		ch := filter[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+			i++
+		case ch == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case ch == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case ch == '>' || ch == '<' || ch == '=' || ch == '!':
+			start := i
+			op := filter[i : i+1]
+			i++
+			if i < len(filter) && filter[i] == '=' {
+				op += "="
+				i++
+			}
+			switch op {
+			case "==":
+				op = "="
+			case "!":
+				return nil, fmt.Errorf("%w: at %d: expected !=", ErrInvalidExpression, start)
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start})
+		case isDigit(ch) || ch == '.' || ch == '-':
+			start := i
+			i++
+			for i < len(filter) && (isDigit(filter[i]) || filter[i] == '.') {
+				i++
+			}
+			// An exponent, as numbers are printed
+			if i+1 < len(filter) && filter[i]|0x20 == 'e' {
+				exp := i + 1
+				if filter[exp] == '+' || filter[exp] == '-' {
+					exp++
+				}
+				if exp < len(filter) && isDigit(filter[exp]) {
+					for i = exp; i < len(filter) && isDigit(filter[i]); i++ {
+					}
+				}
+			}
+			value, err := strconv.ParseFloat(filter[start:i], 64)
+			if i < len(filter) {
+				if scale, ok := numberSuffixes[filter[i]|0x20]; ok && !(i+1 < len(filter) && isIdent(filter[i+1])) {
+					value *= scale
+					i++
+				} else if filter[i] == '%' {
+					value /= 100
+					i++
+				}
+			}
+			if err != nil || math.IsInf(value, 0) || (i < len(filter) && isIdent(filter[i])) {
+				end := i
+				for end < len(filter) && (isIdent(filter[end]) || isDigit(filter[end])) {
+					end++
+				}
+				return nil, fmt.Errorf("%w: at %d: invalid number %q", ErrInvalidExpression, start, filter[start:end])
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: filter[start:i], value: value, pos: start})
+		case isIdent(ch):
+			start := i
+			for i < len(filter) && (isIdent(filter[i]) || isDigit(filter[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: strings.ToLower(filter[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("%w: at %d: unexpected %q", ErrInvalidExpression, i, string(rune(ch)))
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of filter", pos: len(filter)}), nil
+}
+
+// numberSuffixes scale numbers, by their lower-case suffix
+var numberSuffixes = map[byte]float64{'k': 1e3, 'm': 1e6, 'b': 1e9}
+
+func isDigit(ch byte) bool { return ch >= '0' && ch <= '9' }
+
+func isIdent(ch byte) bool { return ch == '_' || (ch|0x20 >= 'a' && ch|0x20 <= 'z') }
+
+// parser is a recursive descent parser over the tokens of a filter
+type parser struct {
+	tokens      []token
+	next        int
+	comparisons int
+	known       map[string]string
+}
+
+func (p *parser) peek() token { return p.tokens[p.next] }
+
+func (p *parser) take() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) keyword(word string) bool {
+	if t := p.peek(); t.kind == tokenIdent && t.text == word {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...any) error {
+	return fmt.Errorf("%w: at %d: %s", ErrInvalidExpression, t.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) or(depth int) (Expr, error) {
+	left, err := p.and(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.and(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = or{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) and(depth int) (Expr, error) {
+	left, err := p.unary(depth)
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.unary(depth)
+		if err != nil {
+			return nil, err
+		}
+		left = and{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary(depth int) (Expr, error) {
+	if depth > maxDepth {
+		return nil, p.errorf(p.peek(), "nested deeper than %d", maxDepth)
+	}
+	if p.keyword("not") {
+		expr, err := p.unary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return not{expr}, nil
+	}
+	if t := p.peek(); t.kind == tokenLParen {
+		p.take()
+		expr, err := p.or(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if t := p.take(); t.kind != tokenRParen {
+			return nil, p.errorf(t, "expected ) but found %q", t.text)
+		}
+		return expr, nil
+	}
+	return p.comparison()
+}
+
+func (p *parser) comparison() (Expr, error) {
+	p.comparisons++
+	if p.comparisons > maxComparisons {
+		return nil, p.errorf(p.peek(), "more than %d comparisons", maxComparisons)
+	}
+
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	op := p.take()
+	if op.kind != tokenOp {
+		return nil, p.errorf(op, "expected a comparison but found %q", op.text)
+	}
+	right, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	return comparison{left: left, right: right, op: op.text}, nil
+}
+
+func (p *parser) operand() (operand, error) {
+	t := p.take()
+	switch t.kind {
+	case tokenNumber:
+		return operand{value: t.value}, nil
+	case tokenIdent:
+		if _, ok := p.known[t.text]; !ok {
+			return operand{}, p.errorf(t, "unknown field %q", t.text)
+		}
+		return operand{field: t.text}, nil
+	}
+	return operand{}, p.errorf(t, "expected a field or number but found %q", t.text)
+}
@@ -0,0 +1,100 @@
+package screener
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	fields := map[string]float64{"close": 120, "volume": 6e6, "pct_change_30d": 0.12}
+
+	for filter, want := range map[string]bool{
+		"close > 100 AND volume > 5M AND pct_change_30d > 0.1":  true,
+		"CLOSE > 100 and Volume >= 6m":                          true,
+		"close > 200 OR volume > 5M AND pct_change_30d > 10%":   true,
+		"(close > 200 OR volume > 5M) AND pct_change_30d > 20%": false,
+		"NOT close > 100":       false,
+		"NOT NOT close == 120":  true,
+		"close != 120":          false,
+		"close <= -1.5":         false,
+		"close > volume":        false,
+		"sma200 < close":        false,
+		"NOT sma200 < close":    true,
+		"volume < 6.5M":         true,
+		"volume = 6e+06":        true,
+		"volume = 6E6":          true,
+		"pct_change_30d > 0.1k": false,
+		"":                      true,
+		"  ":                    true,
+	} {
+		expr, err := Parse(filter, knownFields)
+		require.NoError(t, err, filter)
+		assert.Equal(t, want, expr.Eval(fields), filter)
+	}
+
+	expr, err := Parse("close > 100 AND (volume > 5M OR NOT beta > 1)", knownFields)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"close", "volume", "beta"}, Fields(expr))
+	assert.Equal(t, "(close > 100 AND (volume > 5e+06 OR NOT beta > 1))", expr.String())
+}
+
+func TestParse_Errors(t *testing.T) {
+	for filter, want := range map[string]string{
+		"price > 100":                         `at 0: unknown field "price"`,
+		"close > ":                            `at 8: expected a field or number but found "end of filter"`,
+		"close 100":                           `at 6: expected a comparison but found "100"`,
+		"(close > 100":                        `at 12: expected ) but found "end of filter"`,
+		"close > 100)":                        `at 11: unexpected ")"`,
+		"close ! 100":                         `at 6: expected !=`,
+		"close > 1.2.3":                       `at 8: invalid number "1.2.3"`,
+		"close > 5mb":                         `at 8: invalid number "5mb"`,
+		"close > 1e400":                       `at 8: invalid number "1e400"`,
+		"close > 1e":                          `at 8: invalid number "1e"`,
+		"close > 100 AND":                     `expected a field or number`,
+		"close > 100 # comment":               `at 12: unexpected "#"`,
+		strings.Repeat("(", 30):               "nested deeper than 20",
+		strings.Repeat("NOT ", 30):            "nested deeper than 20",
+		strings.Repeat("x", 1001):             "longer than 1000 characters",
+		"close > " + strings.Repeat("9", 400): "invalid number",
+		strings.Repeat("close > 1 OR ", 50) + "close > 1": "more than 50 comparisons",
+	} {
+		_, err := Parse(filter, knownFields)
+		assert.ErrorIs(t, err, ErrInvalidExpression, filter)
+		assert.ErrorContains(t, err, want, filter)
+	}
+}
+
+// FuzzParse checks that no filter panics the parser or evaluation, and that
+// parsed filters print as an equivalent filter
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"close > 100 AND volume > 5M AND pct_change_30d > 0.1",
+		"NOT (sma50 > sma200 OR beta < 0.5%)",
+		"((((close > 1))))",
+		"close >= -.5K",
+		"close ! = 1",
+		"",
+	} {
+		f.Add(seed)
+	}
+	fields := map[string]float64{"close": 120, "volume": 6e6, "sma50": 110}
+
+	f.Fuzz(func(t *testing.T, filter string) {
+		expr, err := Parse(filter, knownFields)
+		if err != nil {
+			assert.ErrorIs(t, err, ErrInvalidExpression)
+			return
+		}
+		assert.LessOrEqual(t, len(Fields(expr)), 2*maxComparisons)
+
+		// Printing adds parentheses and expands suffixes, so it may be
+		// longer than a filter may be
+		printed, err := parse(expr.String(), knownFields)
+		require.NoError(t, err, "%q printed as %q", filter, expr.String())
+		assert.Equal(t, expr.String(), printed.String())
+		assert.Equal(t, expr.Eval(fields), printed.Eval(fields))
+	})
+}
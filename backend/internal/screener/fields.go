@@ -0,0 +1,104 @@
+package screener
+
+import (
+	"time"
+
+	"profitify-backend/internal/models"
+)
+
+// Fields filters and results can refer to, with their descriptions. Changes
+// are fractions, so 0.1 is a 10% rise.
+var (
+	summaryFields = map[string]string{
+		"open":           "Open of the latest session",
+		"high":           "High of the latest session",
+		"low":            "Low of the latest session",
+		"close":          "Close of the latest session",
+		"volume":         "Volume of the latest session",
+		"vwap":           "Volume-weighted average price of the latest session, when known",
+		"dollar_volume":  "Close times volume of the latest session",
+		"change":         "Close less the previous session's close",
+		"pct_change":     "Change of the close from the previous session's",
+		"pct_change_5d":  "Change of the close from the last close at least 5 days earlier",
+		"pct_change_30d": "Change of the close from the last close at least 30 days earlier",
+		"avg_volume_20":  "Average volume of the last 20 sessions",
+	}
+	statsFields = map[string]string{
+		"high_52w": "52-week high, from the ticker stats of the last post-close job",
+		"low_52w":  "52-week low, from the ticker stats",
+		"sma50":    "Average of the last 50 closes, from the ticker stats",
+		"sma200":   "Average of the last 200 closes, from the ticker stats",
+		"beta":     "Beta against STATS_BENCHMARK, from the ticker stats",
+	}
+	knownFields = func() map[string]string {
+		known := make(map[string]string, len(summaryFields)+len(statsFields))
+		for name, description := range summaryFields {
+			known[name] = description
+		}
+		for name, description := range statsFields {
+			known[name] = description
+		}
+		return known
+	}()
+)
+
+// historyDays covers the longest change, pct_change_30d, and the sessions of
+// avg_volume_20, with room for weekends and holidays
+const historyDays = 45
+
+// summaryValues derives the summary fields from the bars of a ticker, oldest
+// first. Fields that cannot be measured, such as changes from a close that is
+// not positive, are left out.
+func summaryValues(bars []models.DailySummary) map[string]float64 {
+	latest := bars[len(bars)-1]
+	close := float64(latest.Close)
+	values := map[string]float64{
+		"open":          float64(latest.Open),
+		"high":          float64(latest.High),
+		"low":           float64(latest.Low),
+		"close":         close,
+		"volume":        float64(latest.Volume),
+		"dollar_volume": close * float64(latest.Volume),
+	}
+	if latest.VWAP > 0 {
+		values["vwap"] = float64(latest.VWAP)
+	}
+
+	if len(bars) > 1 {
+		previous := float64(bars[len(bars)-2].Close)
+		values["change"] = close - previous
+		if previous > 0 {
+			values["pct_change"] = close/previous - 1
+		}
+	}
+	for field, days := range map[string]int{"pct_change_5d": 5, "pct_change_30d": 30} {
+		before := time.Unix(latest.Timestamp, 0).AddDate(0, 0, -days).Unix()
+		for i := len(bars) - 2; i >= 0; i-- {
+			if bars[i].Timestamp <= before {
+				if base := float64(bars[i].Close); base > 0 {
+					values[field] = close/base - 1
+				}
+				break
+			}
+		}
+	}
+	if len(bars) >= 20 {
+		sum := 0.0
+		for _, bar := range bars[len(bars)-20:] {
+			sum += float64(bar.Volume)
+		}
+		values["avg_volume_20"] = sum / 20
+	}
+	return values
+}
+
+// addStats adds the stats fields a ticker's stats have
+func addStats(values map[string]float64, stats *models.TickerStats) {
+	values["high_52w"] = float64(stats.High52Week)
+	values["low_52w"] = float64(stats.Low52Week)
+	for field, value := range map[string]*float64{"sma50": stats.SMA50, "sma200": stats.SMA200, "beta": stats.Beta} {
+		if value != nil {
+			values[field] = *value
+		}
+	}
+}
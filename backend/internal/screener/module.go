@@ -0,0 +1,75 @@
+// Package screener serves screens of the active tickers by a filter
+// expression over their latest daily summaries and materialized stats.
+package screener
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	screenerService Service
+	log             *zap.SugaredLogger
+}
+
+func NewHandler(screener Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		screenerService: screener,
+		log:             log,
+	}
+}
+
+// Wire builds the screener module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	return NewHandler(NewService(
+		deps.TickerRepository(),
+		deps.DailySummaryRepository(),
+		repository.NewTickerStatsRepository(deps.DB, deps.Config.TickerStatsTable),
+		deps.Config.ScreenerCacheTTL,
+		deps.Clock,
+		deps.Log,
+	), deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.POST("/screener", middleware.RequireScope(models.ScopeReadMarket), h.Screen)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	sorts := []any{"ticker"}
+	var fields strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(knownFields)) {
+		sorts = append(sorts, name)
+		fields.WriteString("\n- `" + name + "`: " + knownFields[name])
+	}
+
+	query := doc.Inline(Query{})
+	query.Properties["sort"].Enum = sorts
+	query.Properties["order"].Enum = []any{"asc", "desc"}
+	query.Properties["filter"].Example = "close > 100 AND volume > 5M AND pct_change_30d > 0.1"
+
+	doc.Add(http.MethodPost, "/api/v1/screener", &openapi.Operation{
+		Tags:    []string{"Screener"},
+		Summary: "Screen the active tickers with a filter expression",
+		Description: "Filters compare fields and numbers with >, >=, <, <=, = and != and combine the comparisons with AND, OR, NOT " +
+			"and parentheses, e.g. `close > 100 AND volume > 5M AND pct_change_30d > 0.1`. Numbers take a K, M or B suffix, or % " +
+			"for hundredths; changes are fractions. A comparison of a field a ticker lacks is false, and an empty filter matches " +
+			"every ticker. Fields are read for every active ticker once per SCREENER_CACHE_TTL, the stats fields only for screens " +
+			"that use them, and cursors page through the same read. Fields:" + fields.String(),
+		Parameters:  []openapi.Parameter{api.FieldsParam(resultFields)},
+		RequestBody: openapi.JSONBody(query),
+		Responses:   api.Responses(http.StatusOK, doc.Schema(Page{}), http.StatusBadRequest),
+	})
+}
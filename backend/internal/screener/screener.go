@@ -0,0 +1,100 @@
+package screener
+
+import (
+	"errors"
+	"maps"
+	"net/http"
+	"slices"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Screen answers a page of the active tickers matching the filter of the
+// request body. ?fields= selects the fields of the results, including the
+// screener fields within fields.
+func (h *Handler) Screen(c *gin.Context) {
+	selected, err := api.ParseFields(c, resultFields)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	var q Query
+	if err := c.ShouldBindJSON(&q); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	page, err := h.screenerService.Screen(c.Request.Context(), q)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidExpression), errors.Is(err, ErrInvalidQuery):
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+		default:
+			api.Logger(c, h.log).Errorw("failed to screen tickers", "filter", q.Filter, "error", err)
+			problem.Respond(c, problem.Internal, "Failed to screen tickers")
+		}
+		return
+	}
+
+	if selected.All() {
+		c.JSON(http.StatusOK, page)
+		return
+	}
+	results, err := api.Select(resultSelection(selected), sparseResults(page.Results, selected))
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to select result fields", "fields", selected.String(), "error", err)
+		problem.Respond(c, problem.Internal, "Failed to screen tickers")
+		return
+	}
+	c.JSON(http.StatusOK, sparsePage{Page: page, Results: results})
+}
+
+// sparsePage is a page of results with only the selected fields
+type sparsePage struct {
+	*Page
+	Results any `json:"results"`
+}
+
+// resultFields are the fields of a result ?fields= selects from: its own,
+// then the screener fields within fields
+var resultFields = append(api.JSONFields(Result{}), slices.Sorted(maps.Keys(knownFields))...)
+
+// resultSelection returns the fields of a result to keep for selected, with
+// fields kept when any screener field is selected
+func resultSelection(selected api.Fields) api.Fields {
+	var own api.Fields
+	for _, name := range selected {
+		if _, ok := knownFields[name]; !ok {
+			own = append(own, name)
+		}
+	}
+	if !slices.Contains(own, "fields") && len(own) < len(selected) {
+		own = append(own, "fields")
+	}
+	return own
+}
+
+// sparseResults copies results with only the screener fields selected within
+// their fields, or as they are when fields itself is selected. The results
+// may be the cache's, so they are not changed.
+func sparseResults(results []Result, selected api.Fields) []Result {
+	if slices.Contains(selected, "fields") {
+		return results
+	}
+	sparse := make([]Result, len(results))
+	for i, r := range results {
+		values := make(map[string]float64)
+		for _, name := range selected {
+			if v, ok := r.Fields[name]; ok {
+				values[name] = v
+			}
+		}
+		r.Fields = values
+		sparse[i] = r
+	}
+	return sparse
+}
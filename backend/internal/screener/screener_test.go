@@ -0,0 +1,25 @@
+package screener
+
+import (
+	"testing"
+
+	"profitify-backend/internal/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSparseResults(t *testing.T) {
+	results := []Result{{Ticker: "AAPL", Name: "Apple Inc.", Date: "2025-03-03", Fields: map[string]float64{"close": 190, "volume": 5e7}}}
+
+	selected := api.Fields{"ticker", "name", "close"}
+	assert.Equal(t, api.Fields{"ticker", "name", "fields"}, resultSelection(selected))
+	sparse := sparseResults(results, selected)
+	assert.Equal(t, map[string]float64{"close": 190}, sparse[0].Fields)
+	assert.Len(t, results[0].Fields, 2, "the cached results are not changed")
+
+	selected = api.Fields{"ticker", "fields"}
+	assert.Equal(t, selected, resultSelection(selected))
+	assert.Equal(t, results, sparseResults(results, selected), "fields itself keeps every screener field")
+
+	assert.Equal(t, api.Fields{"date"}, resultSelection(api.Fields{"date"}))
+}
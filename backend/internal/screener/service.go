@@ -0,0 +1,262 @@
+package screener
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidQuery rejects a sort, order, limit or cursor out of range
+var ErrInvalidQuery = errors.New("invalid screener query")
+
+// Page sizes of screener results
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// Query screens the active tickers with Filter, an expression of Parse, and
+// returns a page of the matches in the order of Sort, a field or "ticker"
+type Query struct {
+	Filter string `json:"filter"`
+	Sort   string `json:"sort,omitempty"`
+	// Order is asc or desc; it defaults to desc when sorting by a field and
+	// to asc by ticker
+	Order string `json:"order,omitempty"`
+	// Limit defaults to DefaultLimit, at most MaxLimit
+	Limit int `json:"limit,omitempty"`
+	// Cursor is the nextCursor of the previous page
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Result is a ticker matching a screen with the fields it has, as of the
+// session on Date
+type Result struct {
+	Ticker string             `json:"ticker"`
+	Name   string             `json:"name"`
+	Date   string             `json:"date"`
+	Fields map[string]float64 `json:"fields"`
+}
+
+// Page is one page of the matches of a screen
+type Page struct {
+	Results []Result `json:"results"`
+	Count   int      `json:"count"`
+	// Matched counts the matches over every page
+	Matched int `json:"matched"`
+	// GeneratedUTC is when the fields screened were read; cursors continue
+	// the same snapshot until it is rebuilt
+	GeneratedUTC int64 `json:"generatedUTC"`
+	// NextCursor is set when more matches follow
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+type Service interface {
+	Screen(ctx context.Context, q Query) (*Page, error)
+}
+
+// snapshot holds the fields of every active ticker with bars. It is not
+// modified once built.
+type snapshot struct {
+	results   []Result
+	withStats bool
+	built     time.Time
+}
+
+type screenerService struct {
+	tickers   repository.TickerRepository
+	summaries repository.DailySummaryRepository
+	stats     repository.TickerStatsRepository
+	ttl       time.Duration
+	clock     clock.Clock
+	log       *zap.SugaredLogger
+
+	// mu guards snapshot; only one request rebuilds it, the rest wait and
+	// reuse it
+	mu       sync.Mutex
+	snapshot *snapshot
+}
+
+// NewService returns a screener evaluating filters against the latest daily
+// summaries of the active tickers and, for filters or sorts on stats fields,
+// their materialized stats. Both are read once per ttl.
+func NewService(
+	tickers repository.TickerRepository,
+	summaries repository.DailySummaryRepository,
+	stats repository.TickerStatsRepository,
+	ttl time.Duration,
+	c clock.Clock,
+	log *zap.SugaredLogger,
+) Service {
+	return &screenerService{
+		tickers:   tickers,
+		summaries: summaries,
+		stats:     stats,
+		ttl:       ttl,
+		clock:     c,
+		log:       log,
+	}
+}
+
+func (s *screenerService) Screen(ctx context.Context, q Query) (*Page, error) {
+	expr, err := Parse(q.Filter, knownFields)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.Sort == "" {
+		q.Sort = "ticker"
+	}
+	if _, ok := knownFields[q.Sort]; !ok && q.Sort != "ticker" {
+		return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidQuery, q.Sort)
+	}
+	desc := q.Sort != "ticker"
+	switch q.Order {
+	case "":
+	case "asc", "desc":
+		desc = q.Order == "desc"
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc", ErrInvalidQuery)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit < 1 || q.Limit > MaxLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidQuery, MaxLimit)
+	}
+	offset := 0
+	if q.Cursor != "" {
+		if offset, err = strconv.Atoi(q.Cursor); err != nil || offset < 0 {
+			return nil, fmt.Errorf("%w: invalid cursor %q", ErrInvalidQuery, q.Cursor)
+		}
+	}
+
+	needStats := false
+	for _, field := range append(Fields(expr), q.Sort) {
+		if _, ok := statsFields[field]; ok {
+			needStats = true
+		}
+	}
+	snap, err := s.snapshotFor(ctx, needStats)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := make([]Result, 0)
+	for _, r := range snap.results {
+		if expr.Eval(r.Fields) {
+			matched = append(matched, r)
+		}
+	}
+	sortResults(matched, q.Sort, desc)
+
+	page := &Page{Matched: len(matched), GeneratedUTC: snap.built.Unix()}
+	end := min(offset+q.Limit, len(matched))
+	page.Results = matched[min(offset, end):end]
+	page.Count = len(page.Results)
+	if end < len(matched) {
+		page.NextCursor = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+// sortResults orders results by field, with those lacking it last, and then
+// by ticker
+func sortResults(results []Result, field string, desc bool) {
+	slices.SortFunc(results, func(a, b Result) int {
+		if field != "ticker" {
+			av, aok := a.Fields[field]
+			bv, bok := b.Fields[field]
+			switch {
+			case aok && !bok:
+				return -1
+			case !aok && bok:
+				return 1
+			case aok && bok && av != bv:
+				if desc {
+					return cmp.Compare(bv, av)
+				}
+				return cmp.Compare(av, bv)
+			}
+			return cmp.Compare(a.Ticker, b.Ticker)
+		}
+		if desc {
+			return cmp.Compare(b.Ticker, a.Ticker)
+		}
+		return cmp.Compare(a.Ticker, b.Ticker)
+	})
+}
+
+// snapshotFor returns a snapshot younger than the ttl, with stats when
+// needStats is set, building one when there is none
+func (s *screenerService) snapshotFor(ctx context.Context, needStats bool) (*snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if snap := s.snapshot; snap != nil && s.clock.Now().Sub(snap.built) < s.ttl && (snap.withStats || !needStats) {
+		return snap, nil
+	}
+	snap, err := s.build(ctx, needStats)
+	if err != nil {
+		return nil, err
+	}
+	s.snapshot = snap
+	return snap, nil
+}
+
+// build reads the fields of every active ticker. Tickers whose bars or stats
+// fail to read are logged and left out.
+func (s *screenerService) build(ctx context.Context, withStats bool) (*snapshot, error) {
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers for the screener", "error", err)
+		return nil, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+
+	now := s.clock.Now()
+	from := now.AddDate(0, 0, -historyDays).Unix()
+	snap := &snapshot{results: make([]Result, 0, len(tickers)), withStats: withStats, built: now}
+	for _, t := range tickers {
+		bars, err := s.summaries.GetSummaries(ctx, t.Ticker, from, now.Unix())
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.FromContext(ctx, s.log).Warnw("skipping ticker in the screener", "symbol", t.Ticker, "error", err)
+			continue
+		}
+		if len(bars) == 0 {
+			continue
+		}
+
+		values := summaryValues(bars)
+		if withStats {
+			stats, err := s.stats.GetStats(ctx, t.Ticker)
+			if err != nil {
+				logger.FromContext(ctx, s.log).Warnw("screening ticker without its stats", "symbol", t.Ticker, "error", err)
+			} else if stats != nil {
+				addStats(values, stats)
+			}
+		}
+		snap.results = append(snap.results, Result{
+			Ticker: t.Ticker,
+			Name:   t.Name,
+			Date:   bars[len(bars)-1].Date(),
+			Fields: values,
+		})
+	}
+
+	logger.FromContext(ctx, s.log).Infow("screener snapshot built", "tickers", len(tickers), "screened", len(snap.results), "stats", withStats)
+	return snap, nil
+}
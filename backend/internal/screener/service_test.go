@@ -0,0 +1,206 @@
+package screener
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingStats serves stats from a map, counting the reads
+type countingStats struct {
+	stats map[string]models.TickerStats
+	reads int
+}
+
+func (s *countingStats) PutStats(ctx context.Context, stats *models.TickerStats) error {
+	s.stats[stats.Ticker] = *stats
+	return nil
+}
+
+func (s *countingStats) GetStats(ctx context.Context, symbol string) (*models.TickerStats, error) {
+	s.reads++
+	if stats, ok := s.stats[symbol]; ok {
+		return &stats, nil
+	}
+	return nil, nil
+}
+
+// daily returns a bar of symbol on each of the last days, oldest first,
+// closing at close(n) on the nth
+func daily(symbol string, days int, close func(n int) float32) []models.DailySummary {
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	bars := make([]models.DailySummary, days)
+	for n := range bars {
+		bars[n] = models.DailySummary{
+			Ticker: symbol, Timestamp: end.AddDate(0, 0, n-days+1).Unix(), Close: close(n), Volume: float32(1e6 * (n + 1)),
+		}
+	}
+	return bars
+}
+
+func newTestService(t *testing.T, c clock.Clock) (Service, *repository.MockDailySummaryRepository, *countingStats) {
+	summaries := new(repository.MockDailySummaryRepository)
+	// AAPL rises 1 a day from 100, MSFT falls 1 a day from 400, NEW has a
+	// single session and GONE none
+	summaries.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).
+		Return(daily("AAPL", 40, func(n int) float32 { return 100 + float32(n) }), nil)
+	summaries.On("GetSummaries", mock.Anything, "MSFT", mock.Anything, mock.Anything).
+		Return(daily("MSFT", 40, func(n int) float32 { return 400 - float32(n) }), nil)
+	summaries.On("GetSummaries", mock.Anything, "NEW", mock.Anything, mock.Anything).
+		Return(daily("NEW", 1, func(int) float32 { return 10 }), nil)
+	summaries.On("GetSummaries", mock.Anything, "GONE", mock.Anything, mock.Anything).Return([]models.DailySummary{}, nil)
+	tickers := repository.NewMemoryTickerRepository([]models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc.", Active: 1},
+		{Ticker: "MSFT", Name: "Microsoft Corp", Active: 1},
+		{Ticker: "NEW", Active: 1},
+		{Ticker: "GONE", Active: 1},
+	})
+	sma200 := 300.0
+	stats := &countingStats{stats: map[string]models.TickerStats{
+		"MSFT": {Ticker: "MSFT", High52Week: 420, Low52Week: 300, SMA200: &sma200},
+	}}
+	return NewService(tickers, summaries, stats, time.Minute, c, zap.NewNop().Sugar()), summaries, stats
+}
+
+func TestService_Screen(t *testing.T) {
+	svc, summaries, stats := newTestService(t, clock.System)
+	ctx := context.Background()
+
+	page, err := svc.Screen(ctx, Query{Filter: "close > 100 AND volume > 5M AND pct_change_30d > 0.1"})
+	require.NoError(t, err)
+	require.Len(t, page.Results, 1)
+	aapl := page.Results[0]
+	assert.Equal(t, "AAPL", aapl.Ticker)
+	assert.Equal(t, "Apple Inc.", aapl.Name)
+	assert.Equal(t, 139.0, aapl.Fields["close"])
+	assert.Equal(t, 1.0, aapl.Fields["change"])
+	assert.InDelta(t, 139.0/109-1, aapl.Fields["pct_change_30d"], 1e-9)
+	assert.InDelta(t, 30.5e6, aapl.Fields["avg_volume_20"], 1e-3)
+	assert.NotContains(t, aapl.Fields, "sma200", "stats are only read for screens using them")
+	assert.Zero(t, stats.reads)
+
+	page, err = svc.Screen(ctx, Query{Sort: "pct_change"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, page.Matched, "tickers without bars are left out")
+	assert.Equal(t, []string{"AAPL", "MSFT", "NEW"}, tickerSymbols(page.Results), "NEW has no change and sorts last")
+
+	page, err = svc.Screen(ctx, Query{Filter: "close < sma200 OR low_52w >= 300", Sort: "close", Order: "asc"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MSFT"}, tickerSymbols(page.Results))
+	assert.Equal(t, 300.0, page.Results[0].Fields["sma200"])
+	assert.Equal(t, 3, stats.reads, "stats are read for the tickers with bars")
+
+	// Each snapshot read the bars once
+	summaries.AssertNumberOfCalls(t, "GetSummaries", 8)
+
+	page, err = svc.Screen(ctx, Query{Sort: "ticker", Order: "desc", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"NEW", "MSFT"}, tickerSymbols(page.Results))
+	assert.Equal(t, "2", page.NextCursor)
+	page, err = svc.Screen(ctx, Query{Sort: "ticker", Order: "desc", Limit: 2, Cursor: page.NextCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAPL"}, tickerSymbols(page.Results))
+	assert.Empty(t, page.NextCursor)
+	assert.Equal(t, 1, page.Count)
+	assert.Equal(t, 3, page.Matched)
+
+	page, err = svc.Screen(ctx, Query{Cursor: "10"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Results)
+	assert.NotNil(t, page.Results)
+}
+
+func TestService_ScreenSnapshotTTL(t *testing.T) {
+	now := clock.NewFake(time.Now())
+	svc, summaries, _ := newTestService(t, now)
+	ctx := context.Background()
+
+	page, err := svc.Screen(ctx, Query{})
+	require.NoError(t, err)
+	assert.Equal(t, now.Now().Unix(), page.GeneratedUTC)
+
+	now.Advance(time.Minute - time.Second)
+	page, err = svc.Screen(ctx, Query{})
+	require.NoError(t, err)
+	summaries.AssertNumberOfCalls(t, "GetSummaries", 4)
+
+	now.Advance(time.Second)
+	page, err = svc.Screen(ctx, Query{})
+	require.NoError(t, err)
+	summaries.AssertNumberOfCalls(t, "GetSummaries", 8)
+	assert.Equal(t, now.Now().Unix(), page.GeneratedUTC, "snapshots are rebuilt once the ttl has passed")
+}
+
+func TestService_ScreenRejectsInvalidQueries(t *testing.T) {
+	svc, _, _ := newTestService(t, clock.System)
+
+	for _, q := range []Query{
+		{Filter: "price > 1"},
+		{Sort: "price"},
+		{Order: "up"},
+		{Limit: MaxLimit + 1},
+		{Limit: -1},
+		{Cursor: "-1"},
+		{Cursor: "next"},
+	} {
+		_, err := svc.Screen(context.Background(), q)
+		assert.Error(t, err, "%+v", q)
+	}
+	_, err := svc.Screen(context.Background(), Query{Filter: "close >"})
+	assert.ErrorIs(t, err, ErrInvalidExpression)
+	_, err = svc.Screen(context.Background(), Query{Limit: -1})
+	assert.ErrorIs(t, err, ErrInvalidQuery)
+}
+
+// TestService_ScreenConcurrent screens at once with and without stats fields,
+// rebuilding the shared snapshot, for the race detector: go test -race
+func TestService_ScreenConcurrent(t *testing.T) {
+	svc, _, _ := newTestService(t, clock.System)
+	ctx := context.Background()
+
+	queries := []Query{
+		{Filter: "close > 200", Sort: "close"},
+		{Filter: "sma200 > 0"},
+		{Filter: "", Sort: "pct_change", Limit: 1},
+	}
+	want := [][]string{{"MSFT"}, {"MSFT"}, {"AAPL"}}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				n := (w + i) % len(queries)
+				page, err := svc.Screen(ctx, queries[n])
+				if assert.NoError(t, err) {
+					assert.Equal(t, want[n], tickerSymbols(page.Results), queries[n].Filter)
+					// Results share the snapshot's fields, which must not
+					// change under other requests
+					for _, r := range page.Results {
+						_ = r.Fields["close"]
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func tickerSymbols(results []Result) []string {
+	symbols := make([]string, len(results))
+	for i, r := range results {
+		symbols[i] = r.Ticker
+	}
+	return symbols
+}
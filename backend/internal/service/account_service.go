@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrAccountRequired rejects deletions of requests without an API key
+	ErrAccountRequired = errors.New("api key required to delete the account")
+	// ErrAccountNotDeleted rejects restores of accounts that are active
+	ErrAccountNotDeleted = errors.New("account is not deleted")
+	// ErrRestoreWindowClosed rejects restores of accounts deleted longer ago
+	// than the retention, or already purged
+	ErrRestoreWindowClosed = errors.New("account can no longer be restored")
+)
+
+// AccountPurger deletes the data an API key owns in the tables of one feature
+type AccountPurger interface {
+	PurgeAccount(ctx context.Context, keyID string) (int, error)
+}
+
+// AccountDeletion reports a deleted account and until when it is restorable
+type AccountDeletion struct {
+	ID         string `json:"id"`
+	DeletedUTC int64  `json:"deletedUTC"`
+	// PurgeAfterUTC is when the account's data becomes due for deletion for
+	// good; it is restorable until then
+	PurgeAfterUTC int64 `json:"purgeAfterUTC"`
+}
+
+// AccountService moves API key accounts through their deletion: active
+// accounts are deleted into quarantine, from which support may restore them
+// within the retention, after which the purge job deletes their data.
+type AccountService interface {
+	Delete(ctx context.Context) (*AccountDeletion, error)
+	Restore(ctx context.Context, id string) (*models.APIKey, error)
+	PurgeExpired(ctx context.Context) (int, error)
+}
+
+type accountService struct {
+	repo      repository.APIKeyRepository
+	purgers   []AccountPurger
+	retention time.Duration
+	log       *zap.SugaredLogger
+	now       func() time.Time
+}
+
+// NewAccountService keeps deleted accounts restorable for retention before
+// their data is deleted by purgers
+func NewAccountService(repo repository.APIKeyRepository, purgers []AccountPurger, retention time.Duration, log *zap.SugaredLogger) AccountService {
+	return &accountService{
+		repo:      repo,
+		purgers:   purgers,
+		retention: retention,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Delete deletes the caller's account: its key stops authenticating at once,
+// and its data is kept for the retention
+func (s *accountService) Delete(ctx context.Context) (*AccountDeletion, error) {
+	key, ok := AccountFromContext(ctx)
+	if !ok {
+		return nil, ErrAccountRequired
+	}
+
+	// Only active keys authenticate, so the caller's account is active
+	now := s.now().Unix()
+	if err := s.repo.MarkDeleted(ctx, key.ID, now); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to delete account", "key", key.Name, "error", err)
+		return nil, fmt.Errorf("failed to delete account: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("deleted account", "key", key.Name, "retention", s.retention)
+	return &AccountDeletion{
+		ID:            key.ID,
+		DeletedUTC:    now,
+		PurgeAfterUTC: now + int64(s.retention/time.Second),
+	}, nil
+}
+
+// Restore reactivates a deleted account within the retention, with its data
+func (s *accountService) Restore(ctx context.Context, id string) (*models.APIKey, error) {
+	key, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	switch key.State() {
+	case models.AccountActive:
+		return nil, ErrAccountNotDeleted
+	case models.AccountPurged:
+		return nil, fmt.Errorf("%w: its data was purged", ErrRestoreWindowClosed)
+	}
+	if s.purgeDue(key) {
+		return nil, fmt.Errorf("%w: it was deleted more than %s ago", ErrRestoreWindowClosed, s.retention)
+	}
+
+	if err := s.repo.RestoreKey(ctx, id); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to restore account", "key", key.Name, "error", err)
+		return nil, fmt.Errorf("failed to restore account: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("restored account", "key", key.Name)
+	key.DeletedUTC = 0
+	return key, nil
+}
+
+// PurgeExpired deletes the data of every account deleted longer ago than the
+// retention and returns how many accounts were purged. An account whose purge
+// fails stays deleted and is purged again by the next run.
+func (s *accountService) PurgeExpired(ctx context.Context) (int, error) {
+	keys, err := s.repo.ListKeys(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list api keys: %w", err)
+	}
+
+	purged := 0
+	var errs []error
+	for _, key := range keys {
+		if key.State() != models.AccountDeleted || !s.purgeDue(&key) {
+			continue
+		}
+		if err := s.purge(ctx, &key); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to purge account", "key", key.Name, "error", err)
+			errs = append(errs, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+func (s *accountService) purge(ctx context.Context, key *models.APIKey) error {
+	items := 0
+	for _, purger := range s.purgers {
+		n, err := purger.PurgeAccount(ctx, key.ID)
+		items += n
+		if err != nil {
+			return fmt.Errorf("failed to purge account %s: %w", key.ID, err)
+		}
+	}
+
+	if err := s.repo.MarkPurged(ctx, key.ID, s.now().Unix()); err != nil {
+		return fmt.Errorf("failed to mark account %s purged: %w", key.ID, err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("purged account", "key", key.Name, "items", items)
+	return nil
+}
+
+// purgeDue reports whether a deleted account's retention has passed
+func (s *accountService) purgeDue(key *models.APIKey) bool {
+	return !s.now().Before(time.Unix(key.DeletedUTC, 0).Add(s.retention))
+}
+
+// DeletedAccounts returns which of keyIDs belong to deleted accounts, so the
+// background jobs skip the alerts and digests quarantined with them. The
+// empty ID of items created without an API key, and IDs of keys that are not
+// stored, are never deleted.
+func DeletedAccounts(ctx context.Context, repo repository.APIKeyRepository, keyIDs []string) (map[string]bool, error) {
+	deleted := make(map[string]bool)
+	seen := make(map[string]bool)
+	for _, id := range keyIDs {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		key, err := repo.GetKey(ctx, id)
+		if err != nil {
+			var notFound repository.ErrAPIKeyNotFound
+			if errors.As(err, &notFound) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get api key %s: %w", id, err)
+		}
+		if key.State() != models.AccountActive {
+			deleted[id] = true
+		}
+	}
+	return deleted, nil
+}
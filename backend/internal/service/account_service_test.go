@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingPurger records the keys it purged, failing for those in fail
+type recordingPurger struct {
+	purged []string
+	fail   map[string]bool
+}
+
+func (p *recordingPurger) PurgeAccount(ctx context.Context, keyID string) (int, error) {
+	if p.fail[keyID] {
+		return 1, errors.New("table unavailable")
+	}
+	p.purged = append(p.purged, keyID)
+	return 2, nil
+}
+
+func TestAccountService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryAPIKeyRepository()
+	require.NoError(t, repo.PutKey(ctx, &models.APIKey{ID: "k", Name: "app"}))
+	purger := &recordingPurger{}
+
+	now := time.Unix(1_700_000_000, 0)
+	svc := NewAccountService(repo, []AccountPurger{purger}, 30*24*time.Hour, zap.NewNop().Sugar()).(*accountService)
+	svc.now = func() time.Time { return now }
+
+	_, err := svc.Delete(ctx)
+	assert.ErrorIs(t, err, ErrAccountRequired)
+	_, err = svc.Restore(ctx, "k")
+	assert.ErrorIs(t, err, ErrAccountNotDeleted)
+
+	key, _ := repo.GetKey(ctx, "k")
+	deletion, err := svc.Delete(WithAccount(ctx, key))
+	require.NoError(t, err)
+	assert.Equal(t, now.Unix(), deletion.DeletedUTC)
+	assert.Equal(t, now.AddDate(0, 0, 30).Unix(), deletion.PurgeAfterUTC)
+
+	key, _ = repo.GetKey(ctx, "k")
+	assert.Equal(t, models.AccountDeleted, key.State())
+	assert.True(t, key.Disabled(), "deleted keys stop authenticating")
+
+	restored, err := svc.Restore(ctx, "k")
+	require.NoError(t, err)
+	assert.Equal(t, models.AccountActive, restored.State())
+	key, _ = repo.GetKey(ctx, "k")
+	assert.False(t, key.Disabled())
+
+	_, err = svc.Delete(WithAccount(ctx, key))
+	require.NoError(t, err)
+
+	// Within the retention nothing is purged
+	purged, err := svc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	now = now.AddDate(0, 0, 30)
+	_, err = svc.Restore(ctx, "k")
+	assert.ErrorIs(t, err, ErrRestoreWindowClosed)
+
+	purged, err = svc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, []string{"k"}, purger.purged)
+
+	key, _ = repo.GetKey(ctx, "k")
+	assert.Equal(t, models.AccountPurged, key.State())
+	_, err = svc.Restore(ctx, "k")
+	assert.ErrorIs(t, err, ErrRestoreWindowClosed)
+
+	purged, err = svc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged, "purged accounts are not purged again")
+}
+
+func TestAccountService_PurgeExpiredRetriesFailures(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMemoryAPIKeyRepository()
+	require.NoError(t, repo.PutKey(ctx, &models.APIKey{ID: "a", Name: "a", DeletedUTC: 1}))
+	require.NoError(t, repo.PutKey(ctx, &models.APIKey{ID: "b", Name: "b", DeletedUTC: 1}))
+	require.NoError(t, repo.PutKey(ctx, &models.APIKey{ID: "c", Name: "active"}))
+	purger := &recordingPurger{fail: map[string]bool{"a": true}}
+
+	svc := NewAccountService(repo, []AccountPurger{purger}, time.Hour, zap.NewNop().Sugar())
+
+	purged, err := svc.PurgeExpired(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, 1, purged)
+	assert.Equal(t, []string{"b"}, purger.purged)
+
+	failed, _ := repo.GetKey(ctx, "a")
+	assert.Equal(t, models.AccountDeleted, failed.State(), "purged again by the next run")
+}
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/notify"
+
+	"go.uber.org/zap"
+)
+
+// Actions taken on the summaries flagged as anomalous, as counted by the
+// AnomalyObserver
+const (
+	AnomalyTagged = "tagged"
+	AnomalyHeld   = "held"
+)
+
+// RevisionSourceAnomalyReview names the writes of held summaries an admin
+// approved, in the revisions they make
+const RevisionSourceAnomalyReview = "anomaly-review"
+
+// anomalyHistoryWorkers bounds the tickers whose recent summaries are read at
+// once while screening a batch
+const anomalyHistoryWorkers = 8
+
+var ErrHeldSummaryNotFound = errors.New("held summary not found")
+
+// AnomalyConfig tells the detector what counts as an improbable move. A
+// return is flagged when it lies more than ZScore standard deviations from
+// the mean of the last Lookback returns and outside their quartiles widened
+// by IQRFactor interquartile ranges. Returns of tickers with fewer than
+// MinHistory earlier returns are not screened. With Hold, flagged summaries
+// are held for review instead of returned to be stored.
+type AnomalyConfig struct {
+	ZScore     float64
+	IQRFactor  float64
+	Lookback   int
+	MinHistory int
+	Hold       bool
+}
+
+// AnomalyObserver counts the summaries flagged, by ingestion source and action
+type AnomalyObserver interface {
+	IngestAnomaly(source, action string)
+}
+
+type AnomalyService interface {
+	// Screen flags the summaries of an ingestion from source whose close
+	// moved improbably far from the previous one, tagging them and notifying
+	// each. It returns the summaries to store: all of them, or with Hold all
+	// but the flagged ones, which are held for review.
+	Screen(ctx context.Context, source string, summaries []models.DailySummary) ([]models.DailySummary, error)
+	// ListHeld returns the held summaries, oldest first
+	ListHeld(ctx context.Context) ([]models.HeldSummary, error)
+	// Approve stores a held summary, still tagged, and releases it
+	Approve(ctx context.Context, symbol, date string) (*models.DailySummary, error)
+	// Reject discards a held summary without storing it
+	Reject(ctx context.Context, symbol, date string) error
+}
+
+type anomalyService struct {
+	summaries repository.DailySummaryRepository
+	held      repository.HeldSummaryRepository
+	cfg       AnomalyConfig
+	notifier  notify.Notifier
+	templates *notify.Templates
+	observer  AnomalyObserver
+	events    events.Publisher
+	log       *zap.SugaredLogger
+	now       func() time.Time
+}
+
+// NewAnomalyService screens ingested summaries against the recent summaries
+// of their tickers. Flagged ones are notified through notifier, composed with
+// templates, and counted by observer, which may be nil. Approved summaries
+// are announced with a DailySummaryIngested event; a nil publisher publishes
+// none.
+func NewAnomalyService(summaries repository.DailySummaryRepository, held repository.HeldSummaryRepository, cfg AnomalyConfig, notifier notify.Notifier, templates *notify.Templates, observer AnomalyObserver, publisher events.Publisher, log *zap.SugaredLogger) AnomalyService {
+	return &anomalyService{
+		summaries: summaries,
+		held:      held,
+		cfg:       cfg,
+		notifier:  notifier,
+		templates: templates,
+		observer:  observer,
+		events:    publisher,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// anomalyNotification is the data of an anomaly notification
+type anomalyNotification struct {
+	Summary models.DailySummary `json:"summary"`
+	Anomaly models.PriceAnomaly `json:"anomaly"`
+	Source  string              `json:"source"`
+	Held    bool                `json:"held"`
+}
+
+func (s *anomalyService) Screen(ctx context.Context, source string, summaries []models.DailySummary) ([]models.DailySummary, error) {
+	if len(summaries) == 0 {
+		return summaries, nil
+	}
+	anomalies, err := s.detect(ctx, summaries)
+	if err != nil {
+		return nil, err
+	}
+	if len(anomalies) == 0 {
+		return summaries, nil
+	}
+
+	log := logger.FromContext(ctx, s.log)
+	now := s.now().Unix()
+	screened := make([]models.DailySummary, 0, len(summaries))
+	var held []models.HeldSummary
+	for i, summary := range summaries {
+		anomaly, ok := anomalies[i]
+		if !ok {
+			screened = append(screened, summary)
+			continue
+		}
+		summary.Anomaly = anomaly.String()
+		if s.cfg.Hold {
+			held = append(held, models.HeldSummary{
+				Ticker:  summary.Ticker,
+				Date:    summary.Date(),
+				Summary: summary,
+				Anomaly: anomaly,
+				Source:  source,
+				HeldUTC: now,
+			})
+		} else {
+			screened = append(screened, summary)
+		}
+	}
+	if len(held) > 0 {
+		if err := s.held.PutHeld(ctx, held); err != nil {
+			return nil, fmt.Errorf("failed to hold anomalous daily summaries: %w", err)
+		}
+	}
+
+	action := AnomalyTagged
+	if s.cfg.Hold {
+		action = AnomalyHeld
+	}
+	for i, summary := range summaries {
+		anomaly, ok := anomalies[i]
+		if !ok {
+			continue
+		}
+		log.Warnw("anomalous daily summary", "symbol", summary.Ticker, "date", summary.Date(), "source", source,
+			"action", action, "return", anomaly.Return, "zScore", anomaly.ZScore)
+		if s.observer != nil {
+			s.observer.IngestAnomaly(source, action)
+		}
+		summary.Anomaly = anomaly.String()
+		n, err := s.templates.Compose(notify.KindAnomaly, anomalyNotification{Summary: summary, Anomaly: anomaly, Source: source, Held: s.cfg.Hold})
+		if err == nil {
+			n.SentUTC = now
+			err = s.notifier.Notify(ctx, n)
+		}
+		if err != nil {
+			log.Warnw("failed to notify anomalous daily summary", "symbol", summary.Ticker, "date", summary.Date(), "error", err)
+		}
+	}
+	return screened, nil
+}
+
+// detect returns the anomalies of summaries by index. Each ticker's summaries
+// are screened in order of their sessions, together with the stored ones
+// before and between them that they do not replace.
+func (s *anomalyService) detect(ctx context.Context, summaries []models.DailySummary) (map[int]models.PriceAnomaly, error) {
+	byTicker := make(map[string][]int)
+	for i, summary := range summaries {
+		byTicker[summary.Ticker] = append(byTicker[summary.Ticker], i)
+	}
+
+	type result struct {
+		anomalies map[int]models.PriceAnomaly
+		err       error
+	}
+	results := make(chan result, len(byTicker))
+	sem := make(chan struct{}, anomalyHistoryWorkers)
+	for ticker, indexes := range byTicker {
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			anomalies, err := s.detectTicker(ctx, ticker, summaries, indexes)
+			results <- result{anomalies, err}
+		}()
+	}
+
+	anomalies := make(map[int]models.PriceAnomaly)
+	var firstErr error
+	for range byTicker {
+		res := <-results
+		if res.err != nil {
+			if firstErr == nil {
+				firstErr = res.err
+			}
+			continue
+		}
+		for i, anomaly := range res.anomalies {
+			anomalies[i] = anomaly
+		}
+	}
+	return anomalies, firstErr
+}
+
+func (s *anomalyService) detectTicker(ctx context.Context, ticker string, summaries []models.DailySummary, indexes []int) (map[int]models.PriceAnomaly, error) {
+	first, last := summaries[indexes[0]].Timestamp, summaries[indexes[0]].Timestamp
+	incoming := make(map[int64]int, len(indexes))
+	for _, i := range indexes {
+		first, last = min(first, summaries[i].Timestamp), max(last, summaries[i].Timestamp)
+		incoming[summaries[i].Timestamp] = i
+	}
+
+	// Enough calendar days for Lookback sessions and the close before them,
+	// allowing for weekends and holidays
+	days := (s.cfg.Lookback+1)*7/5 + 7
+	from := time.Unix(first, 0).UTC().AddDate(0, 0, -days).Unix()
+	stored, err := s.summaries.GetSummaries(ctx, ticker, from, last)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent daily summaries of %s: %w", ticker, err)
+	}
+
+	// index is the session's in summaries, or -1 for stored sessions
+	type session struct {
+		timestamp int64
+		close     float64
+		index     int
+	}
+	sessions := make([]session, 0, len(stored)+len(indexes))
+	for _, summary := range stored {
+		if _, replaced := incoming[summary.Timestamp]; !replaced {
+			sessions = append(sessions, session{summary.Timestamp, float64(summary.Close), -1})
+		}
+	}
+	for _, i := range indexes {
+		sessions = append(sessions, session{summaries[i].Timestamp, float64(summaries[i].Close), i})
+	}
+	sort.SliceStable(sessions, func(a, b int) bool { return sessions[a].timestamp < sessions[b].timestamp })
+
+	anomalies := make(map[int]models.PriceAnomaly)
+	var returns []float64
+	previous := 0.0
+	for _, current := range sessions {
+		if previous <= 0 || current.close <= 0 {
+			previous = current.close
+			continue
+		}
+		r := current.close/previous - 1
+		previous = current.close
+
+		if current.index >= 0 && len(returns) >= s.cfg.MinHistory {
+			recent := returns[max(len(returns)-s.cfg.Lookback, 0):]
+			if anomaly, ok := detectAnomaly(recent, r, s.cfg); ok {
+				anomalies[current.index] = anomaly
+			}
+		}
+		returns = append(returns, r)
+	}
+	return anomalies, nil
+}
+
+// detectAnomaly reports whether r is improbable against returns by both its
+// z-score and the interquartile fences. Flat histories, whose moves have no
+// spread to measure against, flag nothing.
+func detectAnomaly(returns []float64, r float64, cfg AnomalyConfig) (models.PriceAnomaly, bool) {
+	var mean float64
+	for _, v := range returns {
+		mean += v
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for _, v := range returns {
+		variance += (v - mean) * (v - mean)
+	}
+	stddev := math.Sqrt(variance / float64(len(returns)-1))
+	if stddev == 0 {
+		return models.PriceAnomaly{}, false
+	}
+
+	sorted := append([]float64(nil), returns...)
+	sort.Float64s(sorted)
+	q1, q3 := quantile(sorted, 0.25), quantile(sorted, 0.75)
+	iqr := q3 - q1
+
+	anomaly := models.PriceAnomaly{
+		Return:     r,
+		ZScore:     (r - mean) / stddev,
+		LowerFence: q1 - cfg.IQRFactor*iqr,
+		UpperFence: q3 + cfg.IQRFactor*iqr,
+		Sessions:   len(returns),
+	}
+	outside := r < anomaly.LowerFence || r > anomaly.UpperFence
+	return anomaly, outside && math.Abs(anomaly.ZScore) > cfg.ZScore
+}
+
+// quantile interpolates the q quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	return sorted[lower] + (pos-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+func (s *anomalyService) ListHeld(ctx context.Context) ([]models.HeldSummary, error) {
+	held, err := s.held.ListHeld(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list held summaries", "error", err)
+		return nil, fmt.Errorf("failed to list held summaries: %w", err)
+	}
+	sort.Slice(held, func(i, j int) bool {
+		if held[i].HeldUTC != held[j].HeldUTC {
+			return held[i].HeldUTC < held[j].HeldUTC
+		}
+		return held[i].Ticker < held[j].Ticker
+	})
+	return held, nil
+}
+
+func (s *anomalyService) Approve(ctx context.Context, symbol, date string) (*models.DailySummary, error) {
+	held, err := s.getHeld(ctx, symbol, date)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := held.Summary
+	if err := s.summaries.PutSummaries(repository.WithRevisionSource(ctx, RevisionSourceAnomalyReview), []models.DailySummary{summary}); err != nil {
+		return nil, fmt.Errorf("failed to store approved summary: %w", err)
+	}
+	// A summary stored but still held is approved again harmlessly
+	if err := s.held.DeleteHeld(ctx, symbol, date); err != nil {
+		return nil, fmt.Errorf("approved summary stored but not released: %w", err)
+	}
+
+	log := logger.FromContext(ctx, s.log)
+	log.Infow("held summary approved", "symbol", symbol, "date", date)
+	PublishEvent(ctx, s.events, log, models.EventDailySummaryIngested, models.EventDailySummaryIngestedVersion, models.DailySummaryIngestedEvent{
+		Scope:  models.IngestScopeTicker,
+		Symbol: symbol,
+		From:   date,
+		To:     date,
+		Stored: 1,
+	})
+	return &summary, nil
+}
+
+func (s *anomalyService) Reject(ctx context.Context, symbol, date string) error {
+	if _, err := s.getHeld(ctx, symbol, date); err != nil {
+		return err
+	}
+	if err := s.held.DeleteHeld(ctx, symbol, date); err != nil {
+		return fmt.Errorf("failed to discard held summary: %w", err)
+	}
+	logger.FromContext(ctx, s.log).Infow("held summary rejected", "symbol", symbol, "date", date)
+	return nil
+}
+
+func (s *anomalyService) getHeld(ctx context.Context, symbol, date string) (*models.HeldSummary, error) {
+	held, err := s.held.GetHeld(ctx, symbol, date)
+	var notFound repository.ErrHeldSummaryNotFound
+	if errors.As(err, &notFound) {
+		return nil, fmt.Errorf("%w: %s on %s", ErrHeldSummaryNotFound, symbol, date)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held summary: %w", err)
+	}
+	return held, nil
+}
@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/notify"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// historyRepository serves stored summaries through GetSummaries and records
+// PutSummaries; other methods are not used
+type historyRepository struct {
+	repository.DailySummaryRepository
+	mu     sync.Mutex
+	stored []models.DailySummary
+	put    []models.DailySummary
+	err    error
+}
+
+func (r *historyRepository) GetSummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	var found []models.DailySummary
+	for _, s := range r.stored {
+		if s.Ticker == symbol && s.Timestamp >= from && s.Timestamp <= to {
+			found = append(found, s)
+		}
+	}
+	return found, nil
+}
+
+func (r *historyRepository) PutSummaries(ctx context.Context, summaries []models.DailySummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.put = append(r.put, summaries...)
+	return nil
+}
+
+// heldRepository keeps held summaries in a map
+type heldRepository struct {
+	mu   sync.Mutex
+	held map[string]models.HeldSummary
+}
+
+func (r *heldRepository) PutHeld(ctx context.Context, held []models.HeldSummary) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.held == nil {
+		r.held = make(map[string]models.HeldSummary)
+	}
+	for _, h := range held {
+		r.held[h.Ticker+"#"+h.Date] = h
+	}
+	return nil
+}
+
+func (r *heldRepository) GetHeld(ctx context.Context, symbol, date string) (*models.HeldSummary, error) {
+	h, ok := r.held[symbol+"#"+date]
+	if !ok {
+		return nil, repository.ErrHeldSummaryNotFound{Symbol: symbol, Date: date}
+	}
+	return &h, nil
+}
+
+func (r *heldRepository) ListHeld(ctx context.Context) ([]models.HeldSummary, error) {
+	held := []models.HeldSummary{}
+	for _, h := range r.held {
+		held = append(held, h)
+	}
+	return held, nil
+}
+
+func (r *heldRepository) DeleteHeld(ctx context.Context, symbol, date string) error {
+	delete(r.held, symbol+"#"+date)
+	return nil
+}
+
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []notify.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification notify.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+	return nil
+}
+
+type countingObserver map[string]int
+
+func (o countingObserver) IngestAnomaly(source, action string) {
+	o[source+"/"+action]++
+}
+
+var testAnomalyConfig = AnomalyConfig{ZScore: 6, IQRFactor: 3, Lookback: 60, MinHistory: 20}
+
+// anomalyStart is the session after the stored history of wiggle
+var anomalyStart = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)
+
+// wiggle returns sessions days of closes of symbol before anomalyStart moving
+// up and down by about 1% a day
+func wiggle(symbol string, sessions int) []models.DailySummary {
+	bars := make([]models.DailySummary, sessions)
+	close := 100.0
+	for i := range bars {
+		close *= 1 + 0.01*math.Sin(float64(i))
+		bars[i] = models.DailySummary{Ticker: symbol, Timestamp: anomalyStart.AddDate(0, 0, i-sessions).Unix(), Close: float32(close)}
+	}
+	return bars
+}
+
+func session(symbol string, day int, close float32) models.DailySummary {
+	return models.DailySummary{Ticker: symbol, Timestamp: anomalyStart.AddDate(0, 0, day).Unix(), Open: close, High: close, Low: close, Close: close, Volume: 1000}
+}
+
+func TestDetectAnomaly(t *testing.T) {
+	returns := []float64{0.01, -0.01, 0.005, -0.005, 0.012, -0.008, 0.003, -0.002}
+
+	anomaly, ok := detectAnomaly(returns, 0.2, testAnomalyConfig)
+	assert.True(t, ok)
+	assert.Equal(t, 0.2, anomaly.Return)
+	assert.Greater(t, anomaly.ZScore, 6.0)
+	assert.Less(t, anomaly.UpperFence, 0.2)
+	assert.Equal(t, len(returns), anomaly.Sessions)
+
+	_, ok = detectAnomaly(returns, 0.015, testAnomalyConfig)
+	assert.False(t, ok, "an ordinary move")
+
+	_, ok = detectAnomaly([]float64{0, 0, 0, 0}, 0.5, testAnomalyConfig)
+	assert.False(t, ok, "a flat history has no spread to measure against")
+
+	// A single outlier in the history widens the z-score's deviation but not
+	// the quartiles, so both tests must agree
+	spiky := append([]float64{0.5}, returns...)
+	_, ok = detectAnomaly(spiky, 0.2, testAnomalyConfig)
+	assert.False(t, ok)
+}
+
+func TestQuantile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4, 5}
+	assert.Equal(t, 2.0, quantile(sorted, 0.25))
+	assert.Equal(t, 4.0, quantile(sorted, 0.75))
+	assert.Equal(t, 5.0, quantile(sorted, 1))
+	assert.Equal(t, 2.5, quantile([]float64{1, 2, 3, 4}, 0.5))
+}
+
+func TestAnomalyService_ScreenTags(t *testing.T) {
+	repo := &historyRepository{stored: append(wiggle("AAPL", 40), wiggle("MSFT", 40)...)}
+	last := repo.stored[39].Close
+	notifier := &recordingNotifier{}
+	observer := countingObserver{}
+	svc := NewAnomalyService(repo, &heldRepository{}, testAnomalyConfig, notifier, nil, observer, nil, zap.NewNop().Sugar())
+
+	incoming := []models.DailySummary{
+		session("AAPL", 0, last*1.005),
+		session("AAPL", 1, last*1.5), // a 49% jump
+		session("MSFT", 0, repo.stored[79].Close*1.01),
+		session("NEW", 0, 10), // no history to judge by
+	}
+	screened, err := svc.Screen(context.Background(), "daily-ingest", incoming)
+	require.NoError(t, err)
+
+	require.Len(t, screened, 4, "tagged summaries are still stored")
+	assert.Empty(t, screened[0].Anomaly)
+	assert.Contains(t, screened[1].Anomaly, "return of +49.25%")
+	assert.Empty(t, screened[2].Anomaly)
+	assert.Empty(t, screened[3].Anomaly)
+	assert.Empty(t, incoming[1].Anomaly, "the caller's summaries are not modified")
+
+	require.Len(t, notifier.notifications, 1)
+	n := notifier.notifications[0]
+	assert.Equal(t, notify.KindAnomaly, n.Kind)
+	assert.Contains(t, n.Subject, "AAPL")
+	assert.Contains(t, n.Body, "stored tagged")
+	assert.Equal(t, countingObserver{"daily-ingest/tagged": 1}, observer)
+}
+
+func TestAnomalyService_ScreenReplacesStoredSessions(t *testing.T) {
+	history := wiggle("AAPL", 40)
+	// The stored session being corrected is the anomaly; its correction is not
+	history = append(history, session("AAPL", 0, history[39].Close*3))
+	repo := &historyRepository{stored: history}
+	svc := NewAnomalyService(repo, &heldRepository{}, testAnomalyConfig, &recordingNotifier{}, nil, nil, nil, zap.NewNop().Sugar())
+
+	screened, err := svc.Screen(context.Background(), "ingest-queue", []models.DailySummary{session("AAPL", 0, history[39].Close*1.01)})
+	require.NoError(t, err)
+	require.Len(t, screened, 1)
+	assert.Empty(t, screened[0].Anomaly)
+}
+
+func TestAnomalyService_HoldAndReview(t *testing.T) {
+	repo := &historyRepository{stored: wiggle("AAPL", 40)}
+	held := &heldRepository{}
+	cfg := testAnomalyConfig
+	cfg.Hold = true
+	observer := countingObserver{}
+	svc := NewAnomalyService(repo, held, cfg, &recordingNotifier{}, nil, observer, nil, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	jump := session("AAPL", 0, repo.stored[39].Close*0.4)
+	screened, err := svc.Screen(ctx, "ingest-queue", []models.DailySummary{jump})
+	require.NoError(t, err)
+	assert.Empty(t, screened, "held summaries are not stored")
+	assert.Equal(t, countingObserver{"ingest-queue/held": 1}, observer)
+
+	list, err := svc.ListHeld(ctx)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, "2025-03-03", list[0].Date)
+	assert.Equal(t, "ingest-queue", list[0].Source)
+	assert.Less(t, list[0].Anomaly.ZScore, -6.0)
+
+	_, err = svc.Approve(ctx, "AAPL", "2025-03-04")
+	assert.ErrorIs(t, err, ErrHeldSummaryNotFound)
+
+	approved, err := svc.Approve(ctx, "AAPL", "2025-03-03")
+	require.NoError(t, err)
+	assert.Equal(t, jump.Close, approved.Close)
+	assert.NotEmpty(t, approved.Anomaly, "approved summaries stay tagged")
+	assert.Equal(t, []models.DailySummary{*approved}, repo.put)
+	assert.Empty(t, held.held)
+
+	_, err = svc.Screen(ctx, "daily-ingest", []models.DailySummary{jump})
+	require.NoError(t, err)
+	require.NoError(t, svc.Reject(ctx, "AAPL", "2025-03-03"))
+	assert.Empty(t, held.held)
+	assert.Len(t, repo.put, 1, "rejected summaries are not stored")
+	assert.ErrorIs(t, svc.Reject(ctx, "AAPL", "2025-03-03"), ErrHeldSummaryNotFound)
+}
+
+func TestAnomalyService_ScreenErrors(t *testing.T) {
+	repo := &historyRepository{err: errors.New("throttled")}
+	svc := NewAnomalyService(repo, &heldRepository{}, testAnomalyConfig, &recordingNotifier{}, nil, nil, nil, zap.NewNop().Sugar())
+
+	_, err := svc.Screen(context.Background(), "daily-ingest", []models.DailySummary{session("AAPL", 0, 10)})
+	assert.ErrorContains(t, err, "throttled")
+}
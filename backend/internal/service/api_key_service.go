@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid api key")
+	ErrAPIKeyNotFound = errors.New("api key not found")
+)
+
+// lastUsedResolution limits how often a key's lastUsedUTC is written
+const lastUsedResolution = time.Minute
+
+type APIKeyService interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+	CreateKey(ctx context.Context, name string, admin bool, scopes []models.APIKeyScope) (*models.IssuedAPIKey, error)
+	ListKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeKey(ctx context.Context, id string) error
+	EnsureKey(ctx context.Context, key, name string, admin bool) error
+	SetTier(ctx context.Context, id string, tier models.PlanTier) (*models.APIKey, error)
+	IssueSigningSecret(ctx context.Context, id string) (string, error)
+}
+
+type apiKeyService struct {
+	repo repository.APIKeyRepository
+	log  *zap.SugaredLogger
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository, log *zap.SugaredLogger) APIKeyService {
+	return &apiKeyService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// Authenticate resolves a plaintext key to its record. Unknown and revoked keys
+// are rejected with ErrInvalidAPIKey.
+func (s *apiKeyService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if key == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	record, err := s.repo.GetKey(ctx, hashAPIKey(key))
+	if err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to look up api key", "error", err)
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	if record.Disabled() {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if now.Sub(time.Unix(record.LastUsedUTC, 0)) >= lastUsedResolution {
+		// Usage tracking is best effort and must not fail the request
+		if err := s.repo.TouchKey(ctx, record.ID, now.Unix()); err != nil {
+			logger.FromContext(ctx, s.log).Warnw("failed to record api key usage", "key", record.Name, "error", err)
+		} else {
+			record.LastUsedUTC = now.Unix()
+		}
+	}
+
+	return record, nil
+}
+
+// CreateKey issues a new random key, restricted to scopes unless none are
+// given. The plaintext is only returned here.
+func (s *apiKeyService) CreateKey(ctx context.Context, name string, admin bool, scopes []models.APIKeyScope) (*models.IssuedAPIKey, error) {
+	plaintext, err := generateAPIKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+
+	key := models.APIKey{
+		ID:         hashAPIKey(plaintext),
+		Name:       strings.TrimSpace(name),
+		Admin:      admin,
+		Tier:       models.PlanFree,
+		Scopes:     scopes,
+		CreatedUTC: time.Now().Unix(),
+	}
+	if err := key.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAPIKey, err)
+	}
+
+	if err := s.repo.PutKey(ctx, &key); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to create api key", "name", key.Name, "error", err)
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created api key", "name", key.Name, "admin", key.Admin, "scopes", key.Scopes)
+	return &models.IssuedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+func (s *apiKeyService) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	keys, err := s.repo.ListKeys(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list api keys", "error", err)
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	for i := range keys {
+		keys[i].Tier = keys[i].Plan().Tier
+	}
+	return keys, nil
+}
+
+func (s *apiKeyService) RevokeKey(ctx context.Context, id string) error {
+	if err := s.repo.RevokeKey(ctx, id, time.Now().Unix()); err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return ErrAPIKeyNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to revoke api key", "id", id, "error", err)
+		return fmt.Errorf("failed to revoke api key: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("revoked api key", "id", id)
+	return nil
+}
+
+// SetTier moves a key to another plan tier, effective from its next request
+func (s *apiKeyService) SetTier(ctx context.Context, id string, tier models.PlanTier) (*models.APIKey, error) {
+	if err := tier.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAPIKey, err)
+	}
+
+	if err := s.repo.SetTier(ctx, id, tier); err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to set api key tier", "id", id, "tier", tier, "error", err)
+		return nil, fmt.Errorf("failed to set api key tier: %w", err)
+	}
+
+	key, err := s.repo.GetKey(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("changed api key tier", "name", key.Name, "tier", tier)
+	return key, nil
+}
+
+// IssueSigningSecret generates a new signing secret for a key, replacing any
+// previous one, so its holder can sign requests instead of sending the key.
+// The secret is only returned here.
+func (s *apiKeyService) IssueSigningSecret(ctx context.Context, id string) (string, error) {
+	secret, err := generateAPIKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+
+	if err := s.repo.SetSigningSecret(ctx, id, secret); err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return "", ErrAPIKeyNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to set api key signing secret", "id", id, "error", err)
+		return "", fmt.Errorf("failed to set api key signing secret: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("issued api key signing secret", "id", id)
+	return secret, nil
+}
+
+// EnsureKey stores a key supplied out of band, such as a bootstrap admin key
+// from the environment, unless it already exists. A revoked key stays revoked.
+func (s *apiKeyService) EnsureKey(ctx context.Context, key, name string, admin bool) error {
+	if key == "" {
+		return ErrInvalidAPIKey
+	}
+
+	id := hashAPIKey(key)
+	_, err := s.repo.GetKey(ctx, id)
+	if err == nil {
+		return nil
+	}
+	var notFound repository.ErrAPIKeyNotFound
+	if !errors.As(err, &notFound) {
+		return fmt.Errorf("failed to look up api key: %w", err)
+	}
+
+	if err := s.repo.PutKey(ctx, &models.APIKey{
+		ID:         id,
+		Name:       name,
+		Admin:      admin,
+		CreatedUTC: time.Now().Unix(),
+	}); err != nil {
+		return fmt.Errorf("failed to store api key: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("stored api key", "name", name, "admin", admin)
+	return nil
+}
+
+// generateAPIKey returns 32 random bytes hex encoded, the format produced by
+// scripts/generate_api_key.go
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockAPIKeyRepository mocks the APIKeyRepository interface
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) GetKey(ctx context.Context, id string) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) ListKeys(ctx context.Context) ([]models.APIKey, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) PutKey(ctx context.Context, key *models.APIKey) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) RevokeKey(ctx context.Context, id string, at int64) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchKey(ctx context.Context, id string, at int64) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) SetSigningSecret(ctx context.Context, id, secret string) error {
+	args := m.Called(ctx, id, secret)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) SetTier(ctx context.Context, id string, tier models.PlanTier) error {
+	args := m.Called(ctx, id, tier)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) AcceptTerms(ctx context.Context, id string, acceptance models.TermsAcceptance) error {
+	args := m.Called(ctx, id, acceptance)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) MarkDeleted(ctx context.Context, id string, at int64) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) RestoreKey(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) MarkPurged(ctx context.Context, id string, at int64) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	id := hashAPIKey("secret")
+	recent := time.Now().Unix()
+
+	tests := []struct {
+		name      string
+		key       string
+		mockSetup func(*MockAPIKeyRepository)
+		wantErr   error
+		wantName  string
+	}{
+		{
+			name: "valid key records usage",
+			key:  "secret",
+			mockSetup: func(m *MockAPIKeyRepository) {
+				m.On("GetKey", mock.Anything, id).Return(&models.APIKey{ID: id, Name: "ci"}, nil)
+				m.On("TouchKey", mock.Anything, id, mock.AnythingOfType("int64")).Return(nil)
+			},
+			wantName: "ci",
+		},
+		{
+			name: "recently used key is not touched",
+			key:  "secret",
+			mockSetup: func(m *MockAPIKeyRepository) {
+				m.On("GetKey", mock.Anything, id).Return(&models.APIKey{ID: id, Name: "ci", LastUsedUTC: recent}, nil)
+			},
+			wantName: "ci",
+		},
+		{
+			name: "usage tracking failure is ignored",
+			key:  "secret",
+			mockSetup: func(m *MockAPIKeyRepository) {
+				m.On("GetKey", mock.Anything, id).Return(&models.APIKey{ID: id, Name: "ci"}, nil)
+				m.On("TouchKey", mock.Anything, id, mock.AnythingOfType("int64")).Return(errors.New("throttled"))
+			},
+			wantName: "ci",
+		},
+		{
+			name: "revoked key",
+			key:  "secret",
+			mockSetup: func(m *MockAPIKeyRepository) {
+				m.On("GetKey", mock.Anything, id).Return(&models.APIKey{ID: id, Name: "ci", RevokedUTC: recent}, nil)
+			},
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name: "unknown key",
+			key:  "secret",
+			mockSetup: func(m *MockAPIKeyRepository) {
+				m.On("GetKey", mock.Anything, id).Return(nil, repository.ErrAPIKeyNotFound{ID: id})
+			},
+			wantErr: ErrInvalidAPIKey,
+		},
+		{
+			name:      "empty key",
+			mockSetup: func(m *MockAPIKeyRepository) {},
+			wantErr:   ErrInvalidAPIKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockAPIKeyRepository)
+			tt.mockSetup(repo)
+			svc := NewAPIKeyService(repo, zap.NewNop().Sugar())
+
+			key, err := svc.Authenticate(context.Background(), tt.key)
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantName, key.Name)
+			}
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAPIKeyService_CreateKey(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("PutKey", mock.Anything, mock.AnythingOfType("*models.APIKey")).Return(nil)
+	svc := NewAPIKeyService(repo, zap.NewNop().Sugar())
+
+	issued, err := svc.CreateKey(context.Background(), " ci ", true, nil)
+
+	require.NoError(t, err)
+	assert.Len(t, issued.Key, 64)
+	assert.Equal(t, hashAPIKey(issued.Key), issued.ID)
+	assert.Equal(t, "ci", issued.Name)
+	assert.True(t, issued.Admin)
+	assert.Equal(t, models.PlanFree, issued.Tier)
+
+	stored := repo.Calls[0].Arguments.Get(1).(*models.APIKey)
+	assert.NotEqual(t, issued.Key, stored.ID, "plaintext key must not be stored")
+
+	_, err = svc.CreateKey(context.Background(), "", false, nil)
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+
+	scoped, err := svc.CreateKey(context.Background(), "script", false, []models.APIKeyScope{models.ScopeReadMarket})
+	require.NoError(t, err)
+	assert.True(t, scoped.HasScope(models.ScopeReadMarket))
+	assert.False(t, scoped.HasScope(models.ScopeWritePortfolio))
+
+	_, err = svc.CreateKey(context.Background(), "script", false, []models.APIKeyScope{"write:everything"})
+	assert.ErrorIs(t, err, ErrInvalidAPIKey, "scopes must be known")
+	_, err = svc.CreateKey(context.Background(), "script", false, []models.APIKeyScope{models.ScopeAdmin})
+	assert.ErrorIs(t, err, ErrInvalidAPIKey, "only admin keys may have the admin scope")
+}
+
+func TestAPIKeyService_RevokeKey(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("RevokeKey", mock.Anything, "known", mock.AnythingOfType("int64")).Return(nil)
+	repo.On("RevokeKey", mock.Anything, "missing", mock.AnythingOfType("int64")).Return(repository.ErrAPIKeyNotFound{ID: "missing"})
+	svc := NewAPIKeyService(repo, zap.NewNop().Sugar())
+
+	assert.NoError(t, svc.RevokeKey(context.Background(), "known"))
+	assert.ErrorIs(t, svc.RevokeKey(context.Background(), "missing"), ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_SetTier(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("SetTier", mock.Anything, "known", models.PlanPro).Return(nil)
+	repo.On("GetKey", mock.Anything, "known").Return(&models.APIKey{ID: "known", Tier: models.PlanPro}, nil)
+	repo.On("SetTier", mock.Anything, "missing", models.PlanPro).Return(repository.ErrAPIKeyNotFound{ID: "missing"})
+	svc := NewAPIKeyService(repo, zap.NewNop().Sugar())
+
+	key, err := svc.SetTier(context.Background(), "known", models.PlanPro)
+	require.NoError(t, err)
+	assert.Equal(t, models.PlanPro, key.Tier)
+
+	_, err = svc.SetTier(context.Background(), "missing", models.PlanPro)
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+
+	_, err = svc.SetTier(context.Background(), "known", "enterprise")
+	assert.ErrorIs(t, err, ErrInvalidAPIKey)
+}
+
+func TestAPIKeyService_IssueSigningSecret(t *testing.T) {
+	repo := new(MockAPIKeyRepository)
+	repo.On("SetSigningSecret", mock.Anything, "known", mock.Anything).Return(nil)
+	repo.On("SetSigningSecret", mock.Anything, "missing", mock.Anything).Return(repository.ErrAPIKeyNotFound{ID: "missing"})
+	svc := NewAPIKeyService(repo, zap.NewNop().Sugar())
+
+	first, err := svc.IssueSigningSecret(context.Background(), "known")
+	require.NoError(t, err)
+	assert.Len(t, first, 64)
+	second, err := svc.IssueSigningSecret(context.Background(), "known")
+	require.NoError(t, err)
+	assert.NotEqual(t, first, second, "each call rotates the secret")
+	repo.AssertCalled(t, "SetSigningSecret", mock.Anything, "known", second)
+
+	_, err = svc.IssueSigningSecret(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrAPIKeyNotFound)
+}
+
+func TestAPIKeyService_EnsureKey(t *testing.T) {
+	id := hashAPIKey("bootstrap")
+
+	repo := new(MockAPIKeyRepository)
+	repo.On("GetKey", mock.Anything, id).Return(nil, repository.ErrAPIKeyNotFound{ID: id}).Once()
+	repo.On("PutKey", mock.Anything, mock.MatchedBy(func(k *models.APIKey) bool {
+		return k.ID == id && k.Admin
+	})).Return(nil).Once()
+	svc := NewAPIKeyService(repo, zap.NewNop().Sugar())
+
+	require.NoError(t, svc.EnsureKey(context.Background(), "bootstrap", "bootstrap-admin", true))
+
+	// an existing key, even a revoked one, is left untouched
+	repo.On("GetKey", mock.Anything, id).Return(&models.APIKey{ID: id, RevokedUTC: 1}, nil).Once()
+	require.NoError(t, svc.EnsureKey(context.Background(), "bootstrap", "bootstrap-admin", true))
+
+	repo.AssertExpectations(t)
+}
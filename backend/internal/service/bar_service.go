@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/cache"
+	"profitify-backend/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// minRollupPeriods is the fewest whole periods worth reading from the
+	// rollup table rather than resampling their daily bars
+	minRollupPeriods = 2
+	// rollupCoverageTTL is how long a replica keeps the rollup coverage it read
+	rollupCoverageTTL = time.Minute
+	// barCachePrefix starts the cache key of every answered bar range
+	barCachePrefix = "bars:"
+)
+
+// BarService answers weekly and monthly bars from the cheapest source that
+// has them: a cached answer of the same past range, the stored rollups of
+// closed periods, or the daily bars resampled
+type BarService interface {
+	// GetBars returns the bars AggregateBars would, with the plan they were read by
+	GetBars(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, models.BarQueryPlan, error)
+	// RollUp stores the rollups of the periods the session on date closes
+	RollUp(ctx context.Context, date time.Time) (int, error)
+}
+
+type barService struct {
+	daily    DailySummaryService
+	tickers  repository.TickerRepository
+	rollups  repository.BarRollupRepository
+	settings SettingsService
+	cache    cache.Cache
+	cacheTTL time.Duration
+	log      *zap.SugaredLogger
+
+	mu       sync.Mutex
+	coverage map[models.Resolution]coverageSpan
+}
+
+// coverageSpan is a RollupCoverage as period starts, zero when nothing is
+// rolled up, and when it was read
+type coverageSpan struct {
+	from, through time.Time
+	loaded        time.Time
+}
+
+// NewBarService returns the service planning bar queries over daily, the
+// rollups and c, which caches the bars of ranges ending before today for
+// cacheTTL. A nil c or zero cacheTTL disables caching.
+func NewBarService(
+	daily DailySummaryService,
+	tickers repository.TickerRepository,
+	rollups repository.BarRollupRepository,
+	settings SettingsService,
+	c cache.Cache,
+	cacheTTL time.Duration,
+	log *zap.SugaredLogger,
+) BarService {
+	return &barService{
+		daily:    daily,
+		tickers:  tickers,
+		rollups:  rollups,
+		settings: settings,
+		cache:    c,
+		cacheTTL: cacheTTL,
+		log:      log,
+		coverage: make(map[models.Resolution]coverageSpan),
+	}
+}
+
+// GetBars resamples the daily bars of symbol in [from, to], defaulted as for
+// GetDailySummaries, into weekly or monthly bars, oldest first. A past range
+// answered before is served from the cache. Otherwise the whole periods of
+// the range that were rolled up are read from the rollup table, when there
+// are at least minRollupPeriods of them, and the partial periods at either
+// end from the daily bars.
+func (s *barService) GetBars(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, models.BarQueryPlan, error) {
+	if !resolution.Valid() {
+		return nil, nil, fmt.Errorf("%w: must be %s or %s", ErrInvalidResolution, models.ResolutionWeek, models.ResolutionMonth)
+	}
+	if symbol == "" {
+		return nil, nil, ErrInvalidTicker
+	}
+	from, to, err := resolveRange(ctx, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Bars of ranges ending today change with every session ingested
+	key := ""
+	if to < StartOfDay(time.Now()).Unix() {
+		key = fmt.Sprintf("%s%s:%s:%d:%d", barCachePrefix, symbol, resolution, from, to)
+		if bars, ok := s.load(ctx, key); ok {
+			return bars, models.BarQueryPlan{barSegment(models.BarSourceCache, from, to)}, nil
+		}
+	}
+
+	bars, plan, err := s.read(ctx, symbol, resolution, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if key != "" {
+		s.store(ctx, key, bars)
+	}
+	logger.FromContext(ctx, s.log).Debugw("planned bar query", "symbol", symbol, "resolution", resolution, "plan", plan.String())
+	return bars, plan, nil
+}
+
+// read answers a bar query from the rollups and daily bars
+func (s *barService) read(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, models.BarQueryPlan, error) {
+	bars := []models.AggregateBar{}
+	var plan models.BarQueryPlan
+
+	start, end, ok := s.rollupSpan(ctx, resolution, from, to)
+	if !ok {
+		return s.appendDaily(ctx, bars, plan, symbol, resolution, from, to)
+	}
+
+	bars, plan, err := s.appendDaily(ctx, bars, plan, symbol, resolution, from, start.Unix()-1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rolled, err := s.rollups.GetRollups(ctx, symbol, resolution, start.Unix(), end.Unix()-1)
+	if err != nil {
+		// The daily bars answer the same periods, only slower
+		logger.FromContext(ctx, s.log).Warnw("failed to read bar rollups", "symbol", symbol, "resolution", resolution, "error", err)
+		rolled = nil
+	}
+	// Periods before the ticker's first rollup, e.g. of a ticker activated
+	// after they were rolled up, are resampled
+	rolledFrom := end
+	if len(rolled) > 0 {
+		rolledFrom = time.Unix(rolled[0].Timestamp, 0).UTC()
+	}
+	if bars, plan, err = s.appendDaily(ctx, bars, plan, symbol, resolution, start.Unix(), rolledFrom.Unix()-1); err != nil {
+		return nil, nil, err
+	}
+	if len(rolled) > 0 {
+		bars = append(bars, rolled...)
+		plan = append(plan, barSegment(models.RollupSource(resolution), rolledFrom.Unix(), end.Unix()-1))
+	}
+
+	return s.appendDaily(ctx, bars, plan, symbol, resolution, end.Unix(), to)
+}
+
+// appendDaily resamples the daily bars of [from, to] onto bars, extending the
+// plan's last segment when it read the daily bars just before from
+func (s *barService) appendDaily(ctx context.Context, bars []models.AggregateBar, plan models.BarQueryPlan, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, models.BarQueryPlan, error) {
+	if from > to {
+		return bars, plan, nil
+	}
+
+	err := s.daily.EachDailySummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		bars = models.AppendSession(bars, resolution, summary)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	segment := barSegment(models.BarSourceDaily, from, to)
+	if n := len(plan); n > 0 && plan[n-1].Source == models.BarSourceDaily {
+		plan[n-1].To = segment.To
+		return bars, plan, nil
+	}
+	return bars, append(plan, segment), nil
+}
+
+// rollupSpan returns the whole periods of [from, to] that were rolled up, by
+// the start of the first and of the one after the last. ok is false when
+// fewer than minRollupPeriods were.
+func (s *barService) rollupSpan(ctx context.Context, resolution models.Resolution, from, to int64) (start, end time.Time, ok bool) {
+	coverage := s.loadCoverage(ctx, resolution)
+	if coverage.from.IsZero() {
+		return time.Time{}, time.Time{}, false
+	}
+
+	start = resolution.PeriodStart(from)
+	if start.Unix() < from {
+		start = resolution.NextPeriodStart(start)
+	}
+	end = resolution.PeriodStart(to)
+	if next := resolution.NextPeriodStart(end); next.Unix()-1 <= to {
+		end = next
+	}
+
+	if start.Before(coverage.from) {
+		start = coverage.from
+	}
+	if through := resolution.NextPeriodStart(coverage.through); end.After(through) {
+		end = through
+	}
+
+	periods := 0
+	for period := start; period.Before(end) && periods < minRollupPeriods; period = resolution.NextPeriodStart(period) {
+		periods++
+	}
+	return start, end, periods >= minRollupPeriods
+}
+
+// loadCoverage returns the rollup coverage of resolution, read from the
+// settings at most every rollupCoverageTTL. A failed read is logged and
+// answers no coverage, so queries fall back to the daily bars.
+func (s *barService) loadCoverage(ctx context.Context, resolution models.Resolution) coverageSpan {
+	s.mu.Lock()
+	span, ok := s.coverage[resolution]
+	s.mu.Unlock()
+	if ok && time.Since(span.loaded) < rollupCoverageTTL {
+		return span
+	}
+
+	var coverage models.RollupCoverage
+	found, err := s.settings.GetJSON(ctx, models.RollupCoverageKey(resolution), &coverage)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to read rollup coverage", "resolution", resolution, "error", err)
+		return coverageSpan{}
+	}
+
+	span = coverageSpan{loaded: time.Now()}
+	if found {
+		from, fromErr := time.Parse(models.DateLayout, coverage.From)
+		through, throughErr := time.Parse(models.DateLayout, coverage.Through)
+		if fromErr == nil && throughErr == nil && !through.Before(from) {
+			span.from, span.through = from, through
+		} else {
+			logger.FromContext(ctx, s.log).Warnw("ignoring malformed rollup coverage", "resolution", resolution, "coverage", coverage)
+		}
+	}
+
+	s.mu.Lock()
+	s.coverage[resolution] = span
+	s.mu.Unlock()
+	return span
+}
+
+// RollUp stores the weekly and monthly bars of every active ticker for the
+// periods the session on date closes, i.e. when date is the last trading day
+// of its week or month, and extends the coverage queries read rollups
+// within. Tickers that fail to load are logged and skipped, but then the
+// coverage is left as is, since the rollups of the period are incomplete. It
+// returns how many rollups were stored.
+func (s *barService) RollUp(ctx context.Context, date time.Time) (int, error) {
+	day := StartOfDay(date)
+	if !marketcalendar.IsTradingDay(day) {
+		return 0, nil
+	}
+	next := marketcalendar.NextTradingDay(day)
+
+	var closed []models.Resolution
+	for _, resolution := range []models.Resolution{models.ResolutionWeek, models.ResolutionMonth} {
+		if !resolution.PeriodStart(next.Unix()).Equal(resolution.PeriodStart(day.Unix())) {
+			closed = append(closed, resolution)
+		}
+	}
+	if len(closed) == 0 {
+		return 0, nil
+	}
+
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers for bar rollups", "error", err)
+		return 0, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+
+	stored := 0
+	for _, resolution := range closed {
+		n, err := s.rollUp(ctx, tickers, resolution, resolution.PeriodStart(day.Unix()))
+		stored += n
+		if err != nil {
+			return stored, err
+		}
+	}
+	return stored, nil
+}
+
+// rollUp stores the rollups of the period starting at start
+func (s *barService) rollUp(ctx context.Context, tickers []models.Ticker, resolution models.Resolution, start time.Time) (int, error) {
+	end := resolution.NextPeriodStart(start).Unix() - 1
+
+	var rollups []models.BarRollup
+	failed := 0
+	for _, ticker := range tickers {
+		bars, err := AggregateBars(ctx, s.daily, ticker.Ticker, resolution, start.Unix(), end)
+		if err != nil {
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			logger.FromContext(ctx, s.log).Warnw("failed to roll up bars", "symbol", ticker.Ticker, "resolution", resolution, "period", start.Format(models.DateLayout), "error", err)
+			failed++
+			continue
+		}
+		for _, bar := range bars {
+			rollups = append(rollups, models.BarRollup{Ticker: ticker.Ticker, Resolution: resolution, AggregateBar: bar})
+		}
+	}
+
+	if err := s.rollups.PutRollups(ctx, rollups); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store bar rollups", "resolution", resolution, "period", start.Format(models.DateLayout), "error", err)
+		return 0, fmt.Errorf("failed to store %s rollups: %w", resolution, err)
+	}
+	if failed > 0 {
+		return len(rollups), fmt.Errorf("failed to roll up the %s of %s for %d tickers", resolution, start.Format(models.DateLayout), failed)
+	}
+	if err := s.extendCoverage(ctx, resolution, start); err != nil {
+		return len(rollups), err
+	}
+
+	logger.FromContext(ctx, s.log).Infow("bars rolled up", "resolution", resolution, "period", start.Format(models.DateLayout), "rollups", len(rollups))
+	return len(rollups), nil
+}
+
+// extendCoverage adds the period starting at start to the rollup coverage of
+// resolution. A period that does not follow the coverage restarts it, since
+// queries cannot read rollups across the gap.
+func (s *barService) extendCoverage(ctx context.Context, resolution models.Resolution, start time.Time) error {
+	key := models.RollupCoverageKey(resolution)
+	var coverage models.RollupCoverage
+	found, err := s.settings.GetJSON(ctx, key, &coverage)
+	if err != nil {
+		return fmt.Errorf("failed to read rollup coverage: %w", err)
+	}
+
+	period := start.Format(models.DateLayout)
+	through, parseErr := time.Parse(models.DateLayout, coverage.Through)
+	switch {
+	case found && parseErr == nil && coverage.From <= period && period <= coverage.Through:
+		// Rolled up again, e.g. by a rerun of the job
+		return nil
+	case found && parseErr == nil && resolution.NextPeriodStart(through).Equal(start):
+		coverage.Through = period
+	default:
+		if found {
+			logger.FromContext(ctx, s.log).Warnw("restarting rollup coverage after a gap", "resolution", resolution, "coverage", coverage, "period", period)
+		}
+		coverage = models.RollupCoverage{From: period, Through: period}
+	}
+
+	if err := s.settings.PutJSON(ctx, key, coverage); err != nil {
+		return fmt.Errorf("failed to save rollup coverage: %w", err)
+	}
+	s.mu.Lock()
+	delete(s.coverage, resolution)
+	s.mu.Unlock()
+	return nil
+}
+
+// load returns the cached bars under key. A failing cache is logged and
+// bypassed.
+func (s *barService) load(ctx context.Context, key string) ([]models.AggregateBar, bool) {
+	if s.cache == nil || s.cacheTTL <= 0 {
+		return nil, false
+	}
+	data, ok, err := s.cache.Get(ctx, key)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to read cached bars", "key", key, "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	bars := []models.AggregateBar{}
+	if err := json.Unmarshal(data, &bars); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("dropping undecodable cached bars", "key", key, "error", err)
+		return nil, false
+	}
+	return bars, true
+}
+
+func (s *barService) store(ctx context.Context, key string, bars []models.AggregateBar) {
+	if s.cache == nil || s.cacheTTL <= 0 {
+		return
+	}
+	data, err := json.Marshal(bars)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to encode bars for the cache", "key", key, "error", err)
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, s.cacheTTL); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to cache bars", "key", key, "error", err)
+	}
+}
+
+// barSegment is the plan segment of source over [from, to]
+func barSegment(source string, from, to int64) models.BarSegment {
+	return models.BarSegment{
+		Source: source,
+		From:   time.Unix(from, 0).UTC().Format(models.DateLayout),
+		To:     time.Unix(to, 0).UTC().Format(models.DateLayout),
+	}
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// sessionHistory is a DailySummaryService over a fixed history that counts
+// the ranges read; symbols in fail fail
+type sessionHistory struct {
+	summaries []models.DailySummary
+	fail      map[string]bool
+	reads     int
+}
+
+func (h *sessionHistory) GetDailySummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	var summaries []models.DailySummary
+	err := h.EachDailySummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		summaries = append(summaries, summary)
+		return nil
+	})
+	return summaries, err
+}
+
+func (h *sessionHistory) EachDailySummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error {
+	h.reads++
+	if h.fail[symbol] {
+		return errors.New("throttled")
+	}
+	for _, summary := range h.summaries {
+		if summary.Ticker == symbol && summary.Timestamp >= from && summary.Timestamp <= to {
+			if err := fn(summary); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (h *sessionHistory) GetQuote(ctx context.Context, symbol string) (*models.Quote, error) {
+	return nil, ErrTickerNotFound
+}
+
+// memoryRollups keeps rollups in a map by series
+type memoryRollups map[string][]models.AggregateBar
+
+func (m memoryRollups) PutRollups(ctx context.Context, rollups []models.BarRollup) error {
+	for _, rollup := range rollups {
+		series := models.RollupSeries(rollup.Ticker, rollup.Resolution)
+		m[series] = append(m[series], rollup.AggregateBar)
+	}
+	return nil
+}
+
+func (m memoryRollups) GetRollups(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, error) {
+	bars := []models.AggregateBar{}
+	for _, bar := range m[models.RollupSeries(symbol, resolution)] {
+		if bar.Timestamp >= from && bar.Timestamp <= to {
+			bars = append(bars, bar)
+		}
+	}
+	return bars, nil
+}
+
+// weekdayHistory returns a session of AAPL on every weekday of [from, to]
+// with prices that differ from day to day
+func weekdayHistory(from, to time.Time) []models.DailySummary {
+	var summaries []models.DailySummary
+	for day, n := from, 0; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+			continue
+		}
+		n++
+		price := float32(100 + n%17)
+		summaries = append(summaries, models.DailySummary{
+			Ticker: "AAPL", Timestamp: day.Unix(), Open: price, High: price + 2, Low: price - float32(n%3), Close: price + 1, Volume: float32(n),
+		})
+	}
+	return summaries
+}
+
+func newTestBarService(history *sessionHistory, rollups memoryRollups, c cache.Cache, symbols ...string) BarService {
+	tickers := make([]models.Ticker, len(symbols))
+	for i, symbol := range symbols {
+		tickers[i] = models.Ticker{Ticker: symbol, Active: 1}
+	}
+	log := zap.NewNop().Sugar()
+	return NewBarService(history, repository.NewMemoryTickerRepository(tickers), rollups,
+		NewSettingsService(newMemorySettings(), log), c, time.Hour, log)
+}
+
+func date(s string) time.Time {
+	t, _ := time.Parse(models.DateLayout, s)
+	return t
+}
+
+func TestBarService_GetBarsPlansRollups(t *testing.T) {
+	ctx := context.Background()
+	history := &sessionHistory{summaries: weekdayHistory(date("2024-01-01"), date("2024-04-30"))}
+	rollups := memoryRollups{}
+	svc := newTestBarService(history, rollups, nil, "AAPL")
+
+	// Roll up every session of the first quarter, as the post-close job does
+	stored := 0
+	for day := date("2024-01-01"); day.Before(date("2024-04-01")); day = day.AddDate(0, 0, 1) {
+		n, err := svc.RollUp(ctx, day)
+		require.NoError(t, err)
+		stored += n
+	}
+	// 13 weeks through the one of Good Friday, 2024-03-29, and 3 months
+	assert.Equal(t, 16, stored)
+
+	tests := []struct {
+		name       string
+		resolution models.Resolution
+		from, to   string
+		plan       string
+	}{
+		{
+			name: "partial weeks around rollups", resolution: models.ResolutionWeek, from: "2024-01-03", to: "2024-03-13",
+			plan: "daily 2024-01-03..2024-01-07; rollup-week 2024-01-08..2024-03-10; daily 2024-03-11..2024-03-13",
+		},
+		{
+			name: "whole months", resolution: models.ResolutionMonth, from: "2024-01-01", to: "2024-03-31",
+			plan: "rollup-month 2024-01-01..2024-03-31",
+		},
+		{
+			name: "beyond the rollups", resolution: models.ResolutionWeek, from: "2024-03-11", to: "2024-04-24",
+			plan: "rollup-week 2024-03-11..2024-03-31; daily 2024-04-01..2024-04-24",
+		},
+		{
+			name: "too few whole periods", resolution: models.ResolutionWeek, from: "2024-01-08", to: "2024-01-16",
+			plan: "daily 2024-01-08..2024-01-16",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := date(tt.from).Unix(), date(tt.to).AddDate(0, 0, 1).Unix()-1
+
+			bars, plan, err := svc.GetBars(ctx, "AAPL", tt.resolution, from, to)
+			require.NoError(t, err)
+			assert.Equal(t, tt.plan, plan.String())
+
+			resampled, err := AggregateBars(ctx, history, "AAPL", tt.resolution, from, to)
+			require.NoError(t, err)
+			assert.Equal(t, resampled, bars, "the plan answers the daily bars resampled")
+		})
+	}
+}
+
+func TestBarService_GetBarsFillsMissingRollups(t *testing.T) {
+	ctx := context.Background()
+	history := &sessionHistory{summaries: weekdayHistory(date("2024-01-01"), date("2024-02-29"))}
+	rollups := memoryRollups{}
+	svc := newTestBarService(history, rollups, nil, "AAPL")
+	for day := date("2024-01-01"); day.Before(date("2024-03-01")); day = day.AddDate(0, 0, 1) {
+		_, err := svc.RollUp(ctx, day)
+		require.NoError(t, err)
+	}
+	// As for a ticker activated after its first weeks were rolled up
+	rollups[models.RollupSeries("AAPL", models.ResolutionWeek)] = rollups[models.RollupSeries("AAPL", models.ResolutionWeek)][3:]
+
+	from, to := date("2024-01-01").Unix(), date("2024-03-01").Unix()-1
+	bars, plan, err := svc.GetBars(ctx, "AAPL", models.ResolutionWeek, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "daily 2024-01-01..2024-01-21; rollup-week 2024-01-22..2024-02-25; daily 2024-02-26..2024-02-29", plan.String())
+	resampled, err := AggregateBars(ctx, history, "AAPL", models.ResolutionWeek, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, resampled, bars)
+}
+
+func TestBarService_GetBarsCachesPastRanges(t *testing.T) {
+	ctx := context.Background()
+	history := &sessionHistory{summaries: weekdayHistory(date("2024-01-01"), date("2024-01-31"))}
+	svc := newTestBarService(history, memoryRollups{}, cache.NewMemory(), "AAPL")
+	from, to := date("2024-01-01").Unix(), date("2024-02-01").Unix()-1
+
+	first, plan, err := svc.GetBars(ctx, "AAPL", models.ResolutionWeek, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "daily 2024-01-01..2024-01-31", plan.String())
+	reads := history.reads
+
+	second, plan, err := svc.GetBars(ctx, "AAPL", models.ResolutionWeek, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, "cache 2024-01-01..2024-01-31", plan.String())
+	assert.Equal(t, first, second)
+	assert.Equal(t, reads, history.reads, "a cached range reads no daily bars")
+
+	// Ranges ending today are not cached
+	now := time.Now().Unix()
+	_, _, err = svc.GetBars(ctx, "AAPL", models.ResolutionWeek, now-86400, now)
+	require.NoError(t, err)
+	_, plan, err = svc.GetBars(ctx, "AAPL", models.ResolutionWeek, now-86400, now)
+	require.NoError(t, err)
+	assert.Equal(t, models.BarSourceDaily, plan[0].Source)
+
+	_, _, err = svc.GetBars(ctx, "AAPL", "day", from, to)
+	assert.ErrorIs(t, err, ErrInvalidResolution)
+}
+
+func TestBarService_RollUpCoverage(t *testing.T) {
+	ctx := context.Background()
+	history := &sessionHistory{summaries: weekdayHistory(date("2024-01-01"), date("2024-01-31")), fail: map[string]bool{}}
+	log := zap.NewNop().Sugar()
+	settings := NewSettingsService(newMemorySettings(), log)
+	svc := NewBarService(history, repository.NewMemoryTickerRepository([]models.Ticker{{Ticker: "AAPL", Active: 1}, {Ticker: "MSFT", Active: 1}}),
+		memoryRollups{}, settings, nil, 0, log)
+	coverage := func() models.RollupCoverage {
+		var coverage models.RollupCoverage
+		_, err := settings.GetJSON(ctx, models.RollupCoverageKey(models.ResolutionWeek), &coverage)
+		require.NoError(t, err)
+		return coverage
+	}
+
+	// Wednesday closes no period
+	n, err := svc.RollUp(ctx, date("2024-01-03"))
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	_, err = svc.RollUp(ctx, date("2024-01-05"))
+	require.NoError(t, err)
+	_, err = svc.RollUp(ctx, date("2024-01-12"))
+	require.NoError(t, err)
+	assert.Equal(t, models.RollupCoverage{From: "2024-01-01", Through: "2024-01-08"}, coverage())
+
+	// Incomplete rollups leave the coverage as is
+	history.fail["MSFT"] = true
+	_, err = svc.RollUp(ctx, date("2024-01-19"))
+	assert.Error(t, err)
+	assert.Equal(t, models.RollupCoverage{From: "2024-01-01", Through: "2024-01-08"}, coverage())
+
+	// and the next week restarts it after the gap
+	history.fail["MSFT"] = false
+	_, err = svc.RollUp(ctx, date("2024-01-26"))
+	require.NoError(t, err)
+	assert.Equal(t, models.RollupCoverage{From: "2024-01-22", Through: "2024-01-22"}, coverage())
+}
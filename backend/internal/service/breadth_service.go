@@ -0,0 +1,353 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/marketcalendar"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// breadthLookbackDays covers 200 sessions for the long SMA and 52 weeks of highs/lows
+const breadthLookbackDays = 400
+
+// MaxBreadthBackfillDays bounds the range a single breadth backfill may recompute
+const MaxBreadthBackfillDays = 5 * 366
+
+// breadthBackfillPrefix starts the checkpoint job name of every breadth backfill
+const breadthBackfillPrefix = "breadth-backfill:"
+
+type BreadthService interface {
+	Compute(ctx context.Context, date time.Time) (*models.MarketBreadth, error)
+	GetBreadth(ctx context.Context, from, to time.Time) ([]models.MarketBreadth, error)
+	Backfill(ctx context.Context, from, to time.Time) (int, error)
+	ResumeBackfills(ctx context.Context) error
+}
+
+type breadthService struct {
+	tickers   repository.TickerRepository
+	summaries repository.DailySummaryRepository
+	breadth   repository.BreadthRepository
+	settings  SettingsService
+	log       *zap.SugaredLogger
+}
+
+func NewBreadthService(
+	tickers repository.TickerRepository,
+	summaries repository.DailySummaryRepository,
+	breadth repository.BreadthRepository,
+	settings SettingsService,
+	log *zap.SugaredLogger,
+) BreadthService {
+	return &breadthService{
+		tickers:   tickers,
+		summaries: summaries,
+		breadth:   breadth,
+		settings:  settings,
+		log:       log,
+	}
+}
+
+// Compute evaluates breadth across active tickers for the session on date and stores it
+func (s *breadthService) Compute(ctx context.Context, date time.Time) (*models.MarketBreadth, error) {
+	tickers, err := s.activeTickers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	breadth, err := s.compute(ctx, StartOfDay(date), tickers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.store(ctx, breadth); err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Infow("market breadth computed",
+		"date", breadth.Date,
+		"tickers", breadth.Tickers,
+		"advancers", breadth.Advancers,
+		"decliners", breadth.Decliners,
+	)
+	return breadth, nil
+}
+
+func (s *breadthService) GetBreadth(ctx context.Context, from, to time.Time) ([]models.MarketBreadth, error) {
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidRange)
+	}
+
+	series, err := s.breadth.GetBreadth(ctx, from.UTC().Format(models.DateLayout), to.UTC().Format(models.DateLayout))
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get breadth", "error", err)
+		return nil, fmt.Errorf("failed to get breadth: %w", err)
+	}
+
+	return series, nil
+}
+
+// Backfill recomputes breadth for every weekday in [from, to] and returns how
+// many sessions were stored. Progress is checkpointed after each day, so a
+// backfill interrupted by a deploy or crash resumes after the last completed
+// day. Days without sessions, such as holidays, are not stored.
+func (s *breadthService) Backfill(ctx context.Context, from, to time.Time) (int, error) {
+	from, to = StartOfDay(from), StartOfDay(to)
+	if from.After(to) {
+		return 0, fmt.Errorf("%w: from must not be after to", ErrInvalidRange)
+	}
+	if to.Sub(from) > MaxBreadthBackfillDays*24*time.Hour {
+		return 0, fmt.Errorf("%w: backfill spans more than %d days", ErrInvalidRange, MaxBreadthBackfillDays)
+	}
+
+	job := BreadthBackfillJob(from, to)
+	start := from
+
+	checkpoint, err := s.settings.GetCheckpoint(ctx, job)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	if checkpoint == nil {
+		// Record the job before any work so it is resumed even if the first day is interrupted
+		if err := s.settings.SaveCheckpoint(ctx, job, models.Checkpoint{}); err != nil {
+			return 0, fmt.Errorf("failed to save checkpoint: %w", err)
+		}
+	} else if checkpoint.Date != "" {
+		done, err := time.Parse(models.DateLayout, checkpoint.Date)
+		if err != nil {
+			return 0, fmt.Errorf("malformed checkpoint for %s: %w", job, err)
+		}
+		start = done.AddDate(0, 0, 1)
+		logger.FromContext(ctx, s.log).Infow("resuming breadth backfill", "job", job, "after", checkpoint.Date)
+	}
+
+	tickers, err := s.activeTickers(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for day := start; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if !marketcalendar.IsTradingDay(day) {
+			continue
+		}
+
+		breadth, err := s.compute(ctx, day, tickers)
+		if err != nil {
+			return stored, err
+		}
+		if breadth.Tickers > 0 {
+			if err := s.store(ctx, breadth); err != nil {
+				return stored, err
+			}
+			stored++
+		}
+
+		// A lost checkpoint only costs recomputing days on resume
+		if err := s.settings.SaveCheckpoint(ctx, job, models.Checkpoint{Date: breadth.Date}); err != nil {
+			logger.FromContext(ctx, s.log).Warnw("failed to save breadth backfill checkpoint", "job", job, "date", breadth.Date, "error", err)
+		}
+	}
+
+	if err := s.settings.ClearCheckpoint(ctx, job); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to clear breadth backfill checkpoint", "job", job, "error", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("breadth backfill completed", "job", job, "sessions", stored)
+	return stored, nil
+}
+
+// ResumeBackfills runs every breadth backfill left unfinished by a previous process
+func (s *breadthService) ResumeBackfills(ctx context.Context) error {
+	checkpoints, err := s.settings.ListCheckpoints(ctx, breadthBackfillPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list breadth backfills: %w", err)
+	}
+
+	jobs := make([]string, 0, len(checkpoints))
+	for job := range checkpoints {
+		jobs = append(jobs, job)
+	}
+	sort.Strings(jobs)
+
+	for _, job := range jobs {
+		from, to, ok := parseBreadthBackfillJob(job)
+		if !ok {
+			logger.FromContext(ctx, s.log).Warnw("skipping unrecognized breadth backfill checkpoint", "job", job)
+			continue
+		}
+
+		if _, err := s.Backfill(ctx, from, to); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			logger.FromContext(ctx, s.log).Errorw("resumed breadth backfill failed", "job", job, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *breadthService) activeTickers(ctx context.Context) ([]models.Ticker, error) {
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers for breadth", "error", err)
+		return nil, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+	return tickers, nil
+}
+
+// compute evaluates breadth across tickers for the session on day. Tickers that
+// fail to load are skipped, unless ctx is done, since the result would be partial.
+func (s *breadthService) compute(ctx context.Context, day time.Time, tickers []models.Ticker) (*models.MarketBreadth, error) {
+	from := day.AddDate(0, 0, -breadthLookbackDays).Unix()
+	to := day.AddDate(0, 0, 1).Unix() - 1
+
+	acc := newBreadthAccumulator(day.Format(models.DateLayout))
+	for _, t := range tickers {
+		history, err := s.summaries.GetSummaries(ctx, t.Ticker, from, to)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.FromContext(ctx, s.log).Warnw("skipping ticker in breadth", "symbol", t.Ticker, "error", err)
+			continue
+		}
+		acc.add(history)
+	}
+
+	breadth := acc.result()
+	breadth.ComputedUTC = time.Now().Unix()
+	return breadth, nil
+}
+
+func (s *breadthService) store(ctx context.Context, breadth *models.MarketBreadth) error {
+	if err := s.breadth.PutBreadth(ctx, breadth); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store breadth", "date", breadth.Date, "error", err)
+		return fmt.Errorf("failed to store breadth: %w", err)
+	}
+	return nil
+}
+
+// BreadthBackfillJob names the checkpoint, lock and task of a backfill over
+// [from, to]
+func BreadthBackfillJob(from, to time.Time) string {
+	return breadthBackfillPrefix + from.Format(models.DateLayout) + ":" + to.Format(models.DateLayout)
+}
+
+func parseBreadthBackfillJob(job string) (from, to time.Time, ok bool) {
+	fromStr, toStr, found := strings.Cut(strings.TrimPrefix(job, breadthBackfillPrefix), ":")
+	if !found {
+		return time.Time{}, time.Time{}, false
+	}
+	from, err := time.Parse(models.DateLayout, fromStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	to, err = time.Parse(models.DateLayout, toStr)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
+
+// breadthAccumulator tallies breadth indicators one ticker history at a time
+type breadthAccumulator struct {
+	date                  string
+	breadth               models.MarketBreadth
+	above50, eligible50   int
+	above200, eligible200 int
+}
+
+func newBreadthAccumulator(date string) *breadthAccumulator {
+	return &breadthAccumulator{
+		date: date,
+		breadth: models.MarketBreadth{
+			Market: models.BreadthMarket,
+			Date:   date,
+		},
+	}
+}
+
+// add counts a ticker whose history (oldest first) ends with a session on the date
+func (a *breadthAccumulator) add(history []models.DailySummary) {
+	if len(history) < 2 {
+		return
+	}
+	last := history[len(history)-1]
+	if last.Date() != a.date {
+		return
+	}
+	prev := history[len(history)-2]
+	a.breadth.Tickers++
+
+	switch {
+	case last.Close > prev.Close:
+		a.breadth.Advancers++
+	case last.Close < prev.Close:
+		a.breadth.Decliners++
+	default:
+		a.breadth.Unchanged++
+	}
+
+	if sma, ok := closeSMA(history, 50); ok {
+		a.eligible50++
+		if float64(last.Close) > sma {
+			a.above50++
+		}
+	}
+	if sma, ok := closeSMA(history, 200); ok {
+		a.eligible200++
+		if float64(last.Close) > sma {
+			a.above200++
+		}
+	}
+
+	yearAgo := time.Unix(last.Timestamp, 0).AddDate(-1, 0, 0).Unix()
+	newHigh, newLow, compared := true, true, false
+	for _, d := range history[:len(history)-1] {
+		if d.Timestamp < yearAgo {
+			continue
+		}
+		compared = true
+		if d.High >= last.High {
+			newHigh = false
+		}
+		if d.Low <= last.Low {
+			newLow = false
+		}
+	}
+	if compared && newHigh {
+		a.breadth.NewHighs++
+	}
+	if compared && newLow {
+		a.breadth.NewLows++
+	}
+}
+
+func (a *breadthAccumulator) result() *models.MarketBreadth {
+	breadth := a.breadth
+	if a.eligible50 > 0 {
+		breadth.AboveSMA50Percent = float64(a.above50) / float64(a.eligible50) * 100
+	}
+	if a.eligible200 > 0 {
+		breadth.AboveSMA200Percent = float64(a.above200) / float64(a.eligible200) * 100
+	}
+	return &breadth
+}
+
+// closeSMA returns the simple moving average of the last period closes
+func closeSMA(history []models.DailySummary, period int) (float64, bool) {
+	if len(history) < period {
+		return 0, false
+	}
+	var sum float64
+	for _, d := range history[len(history)-period:] {
+		sum += float64(d.Close)
+	}
+	return sum / float64(period), true
+}
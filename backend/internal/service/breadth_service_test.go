@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBreadthAccumulator(t *testing.T) {
+	end := time.Date(2025, 3, 7, 14, 0, 0, 0, time.UTC)
+
+	// series builds n daily sessions with closes from close(i), ending on the returned date
+	series := func(n int, close func(i int) float32) ([]models.DailySummary, string) {
+		start := end.AddDate(0, 0, 1-n)
+		history := make([]models.DailySummary, n)
+		for i := range history {
+			c := close(i)
+			history[i] = models.DailySummary{
+				Ticker:    "T",
+				Timestamp: start.AddDate(0, 0, i).Unix(),
+				High:      c + 1,
+				Low:       c - 1,
+				Close:     c,
+			}
+		}
+		return history, history[n-1].Date()
+	}
+
+	rising, date := series(220, func(i int) float32 { return float32(100 + i) })
+	falling, _ := series(220, func(i int) float32 { return float32(400 - i) })
+	flat, _ := series(60, func(i int) float32 { return 50 })
+	stale := rising[:len(rising)-1]
+
+	acc := newBreadthAccumulator(date)
+	for _, h := range [][]models.DailySummary{rising, falling, flat, stale, rising[:1]} {
+		acc.add(h)
+	}
+	got := acc.result()
+
+	assert.Equal(t, models.BreadthMarket, got.Market)
+	assert.Equal(t, date, got.Date)
+	assert.Equal(t, 3, got.Tickers)
+	assert.Equal(t, 1, got.Advancers)
+	assert.Equal(t, 1, got.Decliners)
+	assert.Equal(t, 1, got.Unchanged)
+	assert.InDelta(t, 100.0/3, got.AboveSMA50Percent, 1e-9)
+	assert.InDelta(t, 50.0, got.AboveSMA200Percent, 1e-9)
+	assert.Equal(t, 1, got.NewHighs)
+	assert.Equal(t, 1, got.NewLows)
+}
+
+func TestBreadthAccumulator_Empty(t *testing.T) {
+	got := newBreadthAccumulator("2025-03-07").result()
+
+	assert.Zero(t, got.Tickers)
+	assert.Zero(t, got.AboveSMA50Percent)
+	assert.Zero(t, got.AboveSMA200Percent)
+}
+
+// MockBreadthRepository mocks the BreadthRepository interface
+type MockBreadthRepository struct {
+	mock.Mock
+}
+
+func (m *MockBreadthRepository) PutBreadth(ctx context.Context, breadth *models.MarketBreadth) error {
+	args := m.Called(ctx, breadth)
+	return args.Error(0)
+}
+
+func (m *MockBreadthRepository) GetBreadth(ctx context.Context, fromDate, toDate string) ([]models.MarketBreadth, error) {
+	args := m.Called(ctx, fromDate, toDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.MarketBreadth), args.Error(1)
+}
+
+func TestBreadthService_BackfillResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	job := "checkpoint:breadth-backfill:2025-03-03:2025-03-10"
+
+	tickerRepo := repository.NewMockTickerRepository()
+	tickerRepo.SetTickers([]models.Ticker{{Ticker: "AAPL", Active: 1}})
+
+	// Each session closes one dollar up; 2025-03-07 has no session
+	summaryRepo := new(repository.MockDailySummaryRepository)
+	for _, date := range []string{"2025-03-06", "2025-03-07", "2025-03-10"} {
+		day, _ := time.Parse(models.DateLayout, date)
+		var history []models.DailySummary
+		if date != "2025-03-07" {
+			history = []models.DailySummary{
+				{Ticker: "AAPL", Timestamp: day.AddDate(0, 0, -1).Unix(), Close: 100},
+				{Ticker: "AAPL", Timestamp: day.Unix(), Close: 101},
+			}
+		}
+		summaryRepo.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, day.AddDate(0, 0, 1).Unix()-1).Return(history, nil).Once()
+	}
+
+	breadthRepo := new(MockBreadthRepository)
+	for _, date := range []string{"2025-03-06", "2025-03-10"} {
+		breadthRepo.On("PutBreadth", mock.Anything, mock.MatchedBy(func(b *models.MarketBreadth) bool {
+			return b.Date == date && b.Advancers == 1
+		})).Return(nil).Once()
+	}
+
+	settingsRepo := new(MockSettingsRepository)
+	settingsRepo.On("GetSetting", mock.Anything, job).Return(&models.Setting{Key: job, Value: `{"date":"2025-03-05"}`}, nil)
+	for _, date := range []string{"2025-03-06", "2025-03-07", "2025-03-10"} {
+		settingsRepo.On("PutSetting", mock.Anything, job, mock.MatchedBy(func(value string) bool {
+			return strings.Contains(value, `"date":"`+date+`"`)
+		})).Return(&models.Setting{Key: job}, nil).Once()
+	}
+	settingsRepo.On("DeleteSetting", mock.Anything, job).Return(nil)
+
+	log := zap.NewNop().Sugar()
+	svc := NewBreadthService(tickerRepo, summaryRepo, breadthRepo, NewSettingsService(settingsRepo, log), log)
+
+	stored, err := svc.Backfill(ctx, time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 2, stored)
+
+	summaryRepo.AssertExpectations(t)
+	breadthRepo.AssertExpectations(t)
+	settingsRepo.AssertExpectations(t)
+}
+
+func TestBreadthService_BackfillInvalidRange(t *testing.T) {
+	log := zap.NewNop().Sugar()
+	svc := NewBreadthService(nil, nil, nil, nil, log)
+
+	_, err := svc.Backfill(context.Background(), time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = svc.Backfill(context.Background(), time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestParseBreadthBackfillJob(t *testing.T) {
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	gotFrom, gotTo, ok := parseBreadthBackfillJob(BreadthBackfillJob(from, to))
+	require.True(t, ok)
+	assert.Equal(t, from, gotFrom)
+	assert.Equal(t, to, gotTo)
+
+	_, _, ok = parseBreadthBackfillJob("breadth-backfill:2024-01-02")
+	assert.False(t, ok)
+}
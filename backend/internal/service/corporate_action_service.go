@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidCorporateAction = errors.New("invalid corporate action")
+
+// maxCorporateActionsPerIngest bounds the size of a single ingestion batch
+const maxCorporateActionsPerIngest = 1000
+
+type CorporateActionService interface {
+	IngestActions(ctx context.Context, splits []models.Split, dividends []models.Dividend) (int, error)
+	GetSplits(ctx context.Context, symbol string) ([]models.Split, error)
+	GetDividends(ctx context.Context, symbol string) ([]models.Dividend, error)
+	// Adjustments returns the adjustments of the splits and dividends of
+	// symbol that took effect after since, up to now
+	Adjustments(ctx context.Context, symbol string, since int64) (*models.Adjustments, error)
+}
+
+type corporateActionService struct {
+	repo      repository.CorporateActionRepository
+	summaries repository.DailySummaryRepository
+	log       *zap.SugaredLogger
+	now       func() time.Time
+}
+
+// NewCorporateActionService returns the service storing splits and dividends
+// in repo. Dividends are priced against the closes read from summaries.
+func NewCorporateActionService(repo repository.CorporateActionRepository, summaries repository.DailySummaryRepository, log *zap.SugaredLogger) CorporateActionService {
+	return &corporateActionService{
+		repo:      repo,
+		summaries: summaries,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// IngestActions validates and upserts a batch of splits and dividends.
+// Re-ingesting an action of the same ticker and date replaces it.
+func (s *corporateActionService) IngestActions(ctx context.Context, splits []models.Split, dividends []models.Dividend) (int, error) {
+	count := len(splits) + len(dividends)
+	if count == 0 {
+		return 0, fmt.Errorf("%w: no splits or dividends given", ErrInvalidCorporateAction)
+	}
+	if count > maxCorporateActionsPerIngest {
+		return 0, fmt.Errorf("%w: at most %d actions may be ingested at once", ErrInvalidCorporateAction, maxCorporateActionsPerIngest)
+	}
+
+	normalizedSplits := make([]models.Split, len(splits))
+	for i, split := range splits {
+		split.Ticker = strings.ToUpper(strings.TrimSpace(split.Ticker))
+		if err := split.Validate(); err != nil {
+			return 0, fmt.Errorf("%w: split %d: %s", ErrInvalidCorporateAction, i, err.Error())
+		}
+		split.ID = models.CorporateActionID(models.CorporateActionSplit, split.Timestamp)
+		normalizedSplits[i] = split
+	}
+
+	normalizedDividends := make([]models.Dividend, len(dividends))
+	for i, dividend := range dividends {
+		dividend.Ticker = strings.ToUpper(strings.TrimSpace(dividend.Ticker))
+		dividend.Currency = strings.ToUpper(strings.TrimSpace(dividend.Currency))
+		if err := dividend.Validate(); err != nil {
+			return 0, fmt.Errorf("%w: dividend %d: %s", ErrInvalidCorporateAction, i, err.Error())
+		}
+		dividend.ID = models.CorporateActionID(models.CorporateActionDividend, dividend.Timestamp)
+		normalizedDividends[i] = dividend
+	}
+
+	if err := s.repo.PutSplits(ctx, normalizedSplits); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store splits", "count", len(normalizedSplits), "error", err)
+		return 0, fmt.Errorf("failed to store splits: %w", err)
+	}
+	if err := s.repo.PutDividends(ctx, normalizedDividends); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store dividends", "count", len(normalizedDividends), "error", err)
+		return 0, fmt.Errorf("failed to store dividends: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("ingested corporate actions", "splits", len(normalizedSplits), "dividends", len(normalizedDividends))
+	return count, nil
+}
+
+// GetSplits returns the splits of symbol, oldest first
+func (s *corporateActionService) GetSplits(ctx context.Context, symbol string) ([]models.Split, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+
+	splits, err := s.repo.GetSplits(ctx, symbol)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get splits", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get splits: %w", err)
+	}
+	return splits, nil
+}
+
+// GetDividends returns the dividends of symbol, oldest first
+func (s *corporateActionService) GetDividends(ctx context.Context, symbol string) ([]models.Dividend, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+
+	dividends, err := s.repo.GetDividends(ctx, symbol)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get dividends", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get dividends: %w", err)
+	}
+	return dividends, nil
+}
+
+// Adjustments prices each dividend against the close of the session before
+// its ex-dividend date, looked up concurrently. A dividend without such a
+// close adjusts nothing. Announced actions yet to take effect are left out.
+func (s *corporateActionService) Adjustments(ctx context.Context, symbol string, since int64) (*models.Adjustments, error) {
+	splits, err := s.GetSplits(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	dividends, err := s.GetDividends(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now().Unix()
+	effective := func(timestamp int64) bool { return timestamp > since && timestamp <= now }
+
+	var adjustments []models.Adjustment
+	for _, split := range splits {
+		if effective(split.Timestamp) {
+			adjustments = append(adjustments, models.SplitAdjustment(split))
+		}
+	}
+
+	var priced []models.Dividend
+	for _, dividend := range dividends {
+		if effective(dividend.Timestamp) {
+			priced = append(priced, dividend)
+		}
+	}
+	closes, err := s.previousCloses(ctx, symbol, priced)
+	if err != nil {
+		return nil, err
+	}
+	for i, dividend := range priced {
+		adjustments = append(adjustments, models.DividendAdjustment(dividend, closes[i]))
+	}
+	return models.NewAdjustments(adjustments), nil
+}
+
+// previousCloses looks up the close of the session before each dividend's
+// ex-dividend date, zero when there is none. The closes are aligned with
+// dividends.
+func (s *corporateActionService) previousCloses(ctx context.Context, symbol string, dividends []models.Dividend) ([]float32, error) {
+	closes := make([]float32, len(dividends))
+	errs := make([]error, len(dividends))
+
+	sem := make(chan struct{}, quoteWorkers)
+	var wg sync.WaitGroup
+	for i, dividend := range dividends {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			previous, err := s.summaries.GetLatestSummaries(ctx, symbol, dividend.Timestamp-1, 1)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if len(previous) > 0 {
+				closes[i] = previous[0].Close
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get closes before dividends", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get closes before dividends: %w", err)
+	}
+	return closes, nil
+}
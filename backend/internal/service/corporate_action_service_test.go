@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockCorporateActionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCorporateActionRepository) PutSplits(ctx context.Context, splits []models.Split) error {
+	args := m.Called(ctx, splits)
+	return args.Error(0)
+}
+
+func (m *MockCorporateActionRepository) PutDividends(ctx context.Context, dividends []models.Dividend) error {
+	args := m.Called(ctx, dividends)
+	return args.Error(0)
+}
+
+func (m *MockCorporateActionRepository) GetSplits(ctx context.Context, symbol string) ([]models.Split, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Split), args.Error(1)
+}
+
+func (m *MockCorporateActionRepository) GetDividends(ctx context.Context, symbol string) ([]models.Dividend, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Dividend), args.Error(1)
+}
+
+func TestCorporateActionService_IngestActions(t *testing.T) {
+	repo := new(MockCorporateActionRepository)
+	repo.On("PutSplits", mock.Anything, []models.Split{
+		{Ticker: "AAPL", ID: "split#1598832000", Timestamp: 1598832000, SplitFrom: 1, SplitTo: 4},
+	}).Return(nil)
+	repo.On("PutDividends", mock.Anything, []models.Dividend{
+		{Ticker: "MSFT", ID: "dividend#1707955200", Timestamp: 1707955200, CashAmount: 0.75, Currency: "USD", Frequency: 4},
+	}).Return(nil)
+
+	svc := NewCorporateActionService(repo, nil, zap.NewNop().Sugar())
+	count, err := svc.IngestActions(context.Background(),
+		[]models.Split{{Ticker: " aapl", Timestamp: 1598832000, SplitFrom: 1, SplitTo: 4}},
+		[]models.Dividend{{Ticker: "msft", Timestamp: 1707955200, CashAmount: 0.75, Currency: "usd", Frequency: 4}},
+	)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	repo.AssertExpectations(t)
+}
+
+func TestCorporateActionService_IngestActionsErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		splits    []models.Split
+		dividends []models.Dividend
+		wantErr   error
+	}{
+		{
+			name:    "empty batch",
+			wantErr: ErrInvalidCorporateAction,
+		},
+		{
+			name:    "split without ratio",
+			splits:  []models.Split{{Ticker: "AAPL", Timestamp: 1598832000, SplitTo: 4}},
+			wantErr: ErrInvalidCorporateAction,
+		},
+		{
+			name:      "dividend without cash",
+			dividends: []models.Dividend{{Ticker: "MSFT", Timestamp: 1707955200}},
+			wantErr:   ErrInvalidCorporateAction,
+		},
+		{
+			name:      "paid before going ex",
+			dividends: []models.Dividend{{Ticker: "MSFT", Timestamp: 1707955200, PayTimestamp: 1707868800, CashAmount: 0.75}},
+			wantErr:   ErrInvalidCorporateAction,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCorporateActionRepository)
+			svc := NewCorporateActionService(repo, nil, zap.NewNop().Sugar())
+
+			_, err := svc.IngestActions(context.Background(), tt.splits, tt.dividends)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			repo.AssertNotCalled(t, "PutSplits", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestCorporateActionService_Adjustments(t *testing.T) {
+	const day = int64(24 * 60 * 60)
+	// Sessions are stamped at 05:00 UTC, actions at midnight UTC
+	session := func(n int64) int64 { return 1704171600 + n*day }
+	effective := func(n int64) int64 { return 1704153600 + n*day }
+
+	repo := new(MockCorporateActionRepository)
+	repo.On("GetSplits", mock.Anything, "AAPL").Return([]models.Split{
+		{Ticker: "AAPL", Timestamp: effective(-10), SplitFrom: 1, SplitTo: 3},
+		{Ticker: "AAPL", Timestamp: effective(3), SplitFrom: 1, SplitTo: 2},
+	}, nil)
+	repo.On("GetDividends", mock.Anything, "AAPL").Return([]models.Dividend{
+		{Ticker: "AAPL", Timestamp: effective(2), CashAmount: 1},
+		// Announced, but not yet in effect
+		{Ticker: "AAPL", Timestamp: effective(30), CashAmount: 1},
+	}, nil)
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", effective(2)-1, int32(1)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: session(1), Close: 200},
+	}, nil)
+
+	svc := NewCorporateActionService(repo, summaries, zap.NewNop().Sugar()).(*corporateActionService)
+	svc.now = func() time.Time { return time.Unix(effective(10), 0) }
+
+	adjust, err := svc.Adjustments(context.Background(), "AAPL", session(0))
+	require.NoError(t, err)
+
+	first := adjust.Apply(models.DailySummary{Timestamp: session(0), Open: 190, High: 202, Low: 188, Close: 200, VWAP: 196, Volume: 1000})
+	assert.InDelta(t, 200*0.995/2, first.Close, 1e-3, "split and dividend apply before both")
+	assert.InDelta(t, 190*0.995/2, first.Open, 1e-3)
+	assert.InDelta(t, 202*0.995/2, first.High, 1e-3)
+	assert.InDelta(t, 188*0.995/2, first.Low, 1e-3)
+	assert.InDelta(t, 196*0.995/2, first.VWAP, 1e-3)
+	assert.InDelta(t, 2000, first.Volume, 1e-3, "volumes are restated in post-split shares")
+
+	between := adjust.Apply(models.DailySummary{Timestamp: session(2), Close: 198, Volume: 1000})
+	assert.InDelta(t, 99, between.Close, 1e-3, "bars from the ex-dividend date on are only split-adjusted")
+	assert.InDelta(t, 2000, between.Volume, 1e-3)
+
+	after := models.DailySummary{Timestamp: session(3), Close: 100, Volume: 2000}
+	assert.Equal(t, after, adjust.Apply(after), "bars from the split on are unchanged")
+	summaries.AssertNumberOfCalls(t, "GetLatestSummaries", 1)
+}
+
+func TestCorporateActionService_AdjustmentsErrors(t *testing.T) {
+	repo := new(MockCorporateActionRepository)
+	repo.On("GetSplits", mock.Anything, "AAPL").Return([]models.Split{}, nil)
+	repo.On("GetDividends", mock.Anything, "AAPL").Return([]models.Dividend{
+		{Ticker: "AAPL", Timestamp: 1704153600, CashAmount: 1},
+	}, nil)
+	repo.On("GetSplits", mock.Anything, "MSFT").Return(nil, errors.New("throttled"))
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(1)).Return(nil, errors.New("throttled"))
+	svc := NewCorporateActionService(repo, summaries, zap.NewNop().Sugar())
+
+	_, err := svc.Adjustments(context.Background(), "AAPL", 0)
+	assert.ErrorContains(t, err, "failed to get closes before dividends")
+
+	_, err = svc.Adjustments(context.Background(), "MSFT", 0)
+	assert.ErrorContains(t, err, "failed to get splits")
+
+	_, err = svc.Adjustments(context.Background(), "", 0)
+	assert.ErrorIs(t, err, ErrInvalidTicker)
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidRange = errors.New("invalid date range")
+	// ErrInvalidResolution rejects periods daily bars cannot be resampled to,
+	// and resolutions intraday bars are not served in
+	ErrInvalidResolution = errors.New("invalid resolution")
+	// ErrInvalidReturnType rejects unknown ways of measuring returns
+	ErrInvalidReturnType = errors.New("invalid return type")
+)
+
+// defaultHistoryRange is the lookback used when no start of range is given
+const defaultHistoryRange = 365 * 24 * time.Hour
+
+// quoteWorkers bounds the concurrent lookups of LatestQuotes
+const quoteWorkers = 8
+
+type DailySummaryService interface {
+	GetDailySummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error)
+	EachDailySummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error
+	GetQuote(ctx context.Context, symbol string) (*models.Quote, error)
+}
+
+type dailySummaryService struct {
+	repo repository.DailySummaryRepository
+	log  *zap.SugaredLogger
+}
+
+// NewDailySummaryService returns the service reading daily bars from repo.
+// Each call is traced.
+func NewDailySummaryService(repo repository.DailySummaryRepository, log *zap.SugaredLogger) DailySummaryService {
+	return tracedDailySummaryService{next: &dailySummaryService{
+		repo: repo,
+		log:  log,
+	}}
+}
+
+// GetDailySummaries returns the daily bars of symbol with timestamps in [from, to],
+// oldest first. A zero to means now and a zero from means one year before to,
+// or the start of the history the caller's plan allows.
+func (s *dailySummaryService) GetDailySummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	var summaries []models.DailySummary
+	err := s.EachDailySummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		summaries = append(summaries, summary)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// EachDailySummary calls fn with the daily bars GetDailySummaries would return,
+// streamed from the repository one query page at a time. The range is checked
+// before fn is first called. An error returned by fn stops the iteration and
+// is returned as is.
+func (s *dailySummaryService) EachDailySummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error {
+	if symbol == "" {
+		return ErrInvalidTicker
+	}
+
+	from, to, err := resolveRange(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	logger.FromContext(ctx, s.log).Debugw("fetching daily summaries", "symbol", symbol, "from", from, "to", to)
+
+	var fnErr error
+	err = s.repo.EachSummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		fnErr = fn(summary)
+		return fnErr
+	})
+	if err != nil {
+		if fnErr != nil {
+			return fnErr
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get daily summaries", "symbol", symbol, "error", err)
+		return fmt.Errorf("failed to get daily summaries: %w", err)
+	}
+
+	return nil
+}
+
+// resolveRange applies the defaults of a daily bar range: a zero to means now
+// and a zero from means one year before to, or the start of the history the
+// caller's plan allows. The range must fit the plan's history.
+func resolveRange(ctx context.Context, from, to int64) (int64, int64, error) {
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from == 0 {
+		from = to - int64(defaultHistoryRange/time.Second)
+		// A default range is cut to the plan's history rather than rejected
+		if start := PlanHistoryStart(ctx); start > from && start <= to {
+			from = start
+		}
+	}
+	if from > to {
+		return 0, 0, fmt.Errorf("%w: from must not be after to", ErrInvalidRange)
+	}
+	if err := CheckHistoryDepth(ctx, from); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// GetQuote returns the latest daily summary of symbol with its change against
+// the previous session, both read in a single query
+func (s *dailySummaryService) GetQuote(ctx context.Context, symbol string) (*models.Quote, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+
+	latest, err := s.repo.GetLatestSummaries(ctx, symbol, time.Now().Unix(), 2)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get latest daily summaries", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get latest daily summaries: %w", err)
+	}
+	if len(latest) == 0 {
+		return nil, fmt.Errorf("%w: no daily summaries for %s", ErrTickerNotFound, symbol)
+	}
+
+	var previousClose float32
+	if len(latest) > 1 {
+		previousClose = latest[1].Close
+	}
+
+	quote := models.NewQuote(latest[0], previousClose)
+	return &quote, nil
+}
+
+// LatestQuotes looks up the quote of every symbol concurrently. The quotes and
+// errors are aligned with symbols.
+func LatestQuotes(ctx context.Context, quotes DailySummaryService, symbols []string) ([]*models.Quote, []error) {
+	results := make([]*models.Quote, len(symbols))
+	errs := make([]error, len(symbols))
+
+	sem := make(chan struct{}, quoteWorkers)
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i], errs[i] = quotes.GetQuote(ctx, symbol)
+		}()
+	}
+	wg.Wait()
+
+	return results, errs
+}
+
+// AggregateBars resamples the daily bars GetDailySummaries would return into
+// weekly or monthly bars, oldest first. The daily bars are streamed, so only
+// the aggregated bars are held in memory.
+func AggregateBars(ctx context.Context, quotes DailySummaryService, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, error) {
+	if !resolution.Valid() {
+		return nil, fmt.Errorf("%w: must be %s or %s", ErrInvalidResolution, models.ResolutionWeek, models.ResolutionMonth)
+	}
+
+	bars := []models.AggregateBar{}
+	err := quotes.EachDailySummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		bars = models.AppendSession(bars, resolution, summary)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return bars, nil
+}
+
+// Returns measures the returns of symbol over the daily bars GetDailySummaries
+// would return, with their summary. With actions, the bars are first adjusted
+// for the splits and dividends taking effect after the first of them, so that
+// neither shows as a loss.
+func Returns(ctx context.Context, quotes DailySummaryService, actions CorporateActionService, symbol string, t models.ReturnType, from, to int64) ([]models.ReturnPoint, *models.ReturnSummary, error) {
+	if !t.Valid() {
+		return nil, nil, fmt.Errorf("%w: must be %s, %s or %s", ErrInvalidReturnType, models.ReturnSimple, models.ReturnLog, models.ReturnCumulative)
+	}
+
+	summaries, err := quotes.GetDailySummaries(ctx, symbol, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	if actions != nil && len(summaries) > 0 {
+		adjust, err := actions.Adjustments(ctx, symbol, summaries[0].Timestamp)
+		if err != nil {
+			return nil, nil, err
+		}
+		for i := range summaries {
+			summaries[i] = adjust.Apply(summaries[i])
+		}
+	}
+
+	points, summary := models.ComputeReturns(summaries, t)
+	return points, summary, nil
+}
+
+// StartOfDay truncates t to midnight UTC
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
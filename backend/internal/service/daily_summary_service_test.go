@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDailySummaryService_GetDailySummaries(t *testing.T) {
+	t.Run("defaults from to one year before to", func(t *testing.T) {
+		repo := new(repository.MockDailySummaryRepository)
+		to := int64(1735689600)
+		repo.On("GetSummaries", mock.Anything, "AAPL", to-365*24*3600, to).Return([]models.DailySummary{{Ticker: "AAPL"}}, nil)
+		svc := NewDailySummaryService(repo, zap.NewNop().Sugar())
+
+		summaries, err := svc.GetDailySummaries(context.Background(), "AAPL", 0, to)
+
+		require.NoError(t, err)
+		assert.Len(t, summaries, 1)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		svc := NewDailySummaryService(new(repository.MockDailySummaryRepository), zap.NewNop().Sugar())
+
+		_, err := svc.GetDailySummaries(context.Background(), "", 0, 0)
+		assert.ErrorIs(t, err, ErrInvalidTicker)
+
+		_, err = svc.GetDailySummaries(context.Background(), "AAPL", 20, 10)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+}
+
+func TestDailySummaryService_GetQuote(t *testing.T) {
+	tests := []struct {
+		name          string
+		latest        []models.DailySummary
+		wantErr       error
+		wantPrevious  float32
+		wantChange    float64
+		wantChangePct float64
+	}{
+		{
+			name: "change against previous session",
+			latest: []models.DailySummary{
+				{Ticker: "AAPL", Timestamp: 1741305600, Close: 110},
+				{Ticker: "AAPL", Timestamp: 1741219200, Close: 100},
+			},
+			wantPrevious:  100,
+			wantChange:    10,
+			wantChangePct: 10,
+		},
+		{
+			name:   "first session has no change",
+			latest: []models.DailySummary{{Ticker: "AAPL", Timestamp: 1741305600, Close: 110}},
+		},
+		{
+			name:    "no data",
+			latest:  []models.DailySummary{},
+			wantErr: ErrTickerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(repository.MockDailySummaryRepository)
+			repo.On("GetLatestSummaries", mock.Anything, "AAPL", mock.AnythingOfType("int64"), int32(2)).Return(tt.latest, nil)
+			svc := NewDailySummaryService(repo, zap.NewNop().Sugar())
+
+			quote, err := svc.GetQuote(context.Background(), "AAPL")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.latest[0].Close, quote.Close)
+			assert.Equal(t, tt.wantPrevious, quote.PreviousClose)
+			assert.InDelta(t, tt.wantChange, quote.Change, 1e-9)
+			assert.InDelta(t, tt.wantChangePct, quote.ChangePercent, 1e-9)
+		})
+	}
+}
+
+func TestAggregateBars(t *testing.T) {
+	day := func(date string, open, high, low, close, volume float32) models.DailySummary {
+		d, err := time.Parse(models.DateLayout, date)
+		require.NoError(t, err)
+		// Sessions are stamped at the open, after midnight UTC
+		return models.DailySummary{Ticker: "AAPL", Timestamp: d.Add(14 * time.Hour).Unix(), Open: open, High: high, Low: low, Close: close, Volume: volume}
+	}
+	repo := new(repository.MockDailySummaryRepository)
+	repo.On("GetSummaries", mock.Anything, "AAPL", int64(1), int64(2)).Return([]models.DailySummary{
+		day("2024-01-29", 10, 12, 9, 11, 100), // Monday
+		day("2024-01-31", 11, 15, 10, 14, 200),
+		day("2024-02-02", 14, 14, 8, 9, 300), // Friday
+		day("2024-02-05", 9, 10, 7, 8, 400),  // next Monday
+	}, nil)
+	svc := NewDailySummaryService(repo, zap.NewNop().Sugar())
+	ctx := context.Background()
+
+	weeks, err := AggregateBars(ctx, svc, "AAPL", models.ResolutionWeek, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, weeks, 2)
+	assert.Equal(t, models.AggregateBar{
+		Timestamp: time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC).Unix(),
+		Date:      "2024-01-29",
+		Open:      10, High: 15, Low: 8, Close: 9, Volume: 600, Sessions: 3,
+	}, weeks[0])
+	assert.Equal(t, "2024-02-05", weeks[1].Date)
+
+	months, err := AggregateBars(ctx, svc, "AAPL", models.ResolutionMonth, 1, 2)
+	require.NoError(t, err)
+	require.Len(t, months, 2)
+	assert.Equal(t, models.AggregateBar{
+		Timestamp: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Date:      "2024-01-01",
+		Open:      10, High: 15, Low: 9, Close: 14, Volume: 300, Sessions: 2,
+	}, months[0])
+	assert.Equal(t, models.AggregateBar{
+		Timestamp: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC).Unix(),
+		Date:      "2024-02-01",
+		Open:      14, High: 14, Low: 7, Close: 8, Volume: 700, Sessions: 2,
+	}, months[1])
+
+	_, err = AggregateBars(ctx, svc, "AAPL", "day", 1, 2)
+	assert.ErrorIs(t, err, ErrInvalidResolution)
+	_, err = AggregateBars(ctx, svc, "", models.ResolutionWeek, 1, 2)
+	assert.ErrorIs(t, err, ErrInvalidTicker)
+}
+
+func TestResolution_PeriodStart(t *testing.T) {
+	sunday := time.Date(2024, 2, 4, 23, 0, 0, 0, time.UTC).Unix()
+	assert.Equal(t, "2024-01-29", models.ResolutionWeek.PeriodStart(sunday).Format(models.DateLayout), "Sundays end the week")
+	assert.Equal(t, "2024-02-01", models.ResolutionMonth.PeriodStart(sunday).Format(models.DateLayout))
+}
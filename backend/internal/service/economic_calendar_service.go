@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidEvent = errors.New("invalid economic event")
+
+const (
+	// defaultEventCountry is assumed for events ingested without a country
+	defaultEventCountry = "US"
+	// maxEventsPerIngest bounds the size of a single ingestion batch
+	maxEventsPerIngest = 1000
+	// maxEventRange bounds how much of the calendar a single query may span
+	maxEventRange = 2 * 366 * 24 * time.Hour
+)
+
+// defaultEventTitles names events ingested without a title
+var defaultEventTitles = map[string]string{
+	models.EconomicEventFOMC:        "FOMC Rate Decision",
+	models.EconomicEventCPI:         "Consumer Price Index",
+	models.EconomicEventPPI:         "Producer Price Index",
+	models.EconomicEventJobs:        "Nonfarm Payrolls",
+	models.EconomicEventGDP:         "Gross Domestic Product",
+	models.EconomicEventPCE:         "PCE Price Index",
+	models.EconomicEventRetailSales: "Retail Sales",
+}
+
+type EconomicCalendarService interface {
+	IngestEvents(ctx context.Context, events []models.EconomicEvent) (int, error)
+	GetEvents(ctx context.Context, country string, from, to int64) ([]models.EconomicEvent, error)
+}
+
+type economicCalendarService struct {
+	repo repository.EconomicEventRepository
+	log  *zap.SugaredLogger
+}
+
+func NewEconomicCalendarService(repo repository.EconomicEventRepository, log *zap.SugaredLogger) EconomicCalendarService {
+	return &economicCalendarService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// IngestEvents validates and upserts a batch of events. Re-ingesting a release
+// with the same country, time and type replaces it, e.g. to fill in the actual.
+func (s *economicCalendarService) IngestEvents(ctx context.Context, events []models.EconomicEvent) (int, error) {
+	if len(events) == 0 {
+		return 0, fmt.Errorf("%w: no events given", ErrInvalidEvent)
+	}
+	if len(events) > maxEventsPerIngest {
+		return 0, fmt.Errorf("%w: at most %d events may be ingested at once", ErrInvalidEvent, maxEventsPerIngest)
+	}
+
+	normalized := make([]models.EconomicEvent, len(events))
+	for i, event := range events {
+		event.Country = strings.ToUpper(strings.TrimSpace(event.Country))
+		if event.Country == "" {
+			event.Country = defaultEventCountry
+		}
+		event.Type = strings.ToLower(strings.TrimSpace(event.Type))
+		if event.Title == "" {
+			event.Title = defaultEventTitles[event.Type]
+		}
+		if err := event.Validate(); err != nil {
+			return 0, fmt.Errorf("%w: event %d: %s", ErrInvalidEvent, i, err.Error())
+		}
+		event.ID = models.EconomicEventID(event.Timestamp, event.Type)
+		normalized[i] = event
+	}
+
+	if err := s.repo.PutEvents(ctx, normalized); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store economic events", "count", len(normalized), "error", err)
+		return 0, fmt.Errorf("failed to store economic events: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("ingested economic events", "count", len(normalized))
+	return len(normalized), nil
+}
+
+// GetEvents returns a country's events scheduled in [from, to], oldest first
+func (s *economicCalendarService) GetEvents(ctx context.Context, country string, from, to int64) ([]models.EconomicEvent, error) {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" {
+		country = defaultEventCountry
+	}
+
+	if from > to {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidRange)
+	}
+	if time.Duration(to-from)*time.Second > maxEventRange {
+		return nil, fmt.Errorf("%w: range exceeds %d days", ErrInvalidRange, int(maxEventRange.Hours()/24))
+	}
+
+	logger.FromContext(ctx, s.log).Debugw("fetching economic events", "country", country, "from", from, "to", to)
+
+	events, err := s.repo.GetEvents(ctx, country, from, to)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get economic events", "country", country, "error", err)
+		return nil, fmt.Errorf("failed to get economic events: %w", err)
+	}
+
+	return events, nil
+}
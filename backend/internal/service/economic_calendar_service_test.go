@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockEconomicEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockEconomicEventRepository) PutEvents(ctx context.Context, events []models.EconomicEvent) error {
+	args := m.Called(ctx, events)
+	return args.Error(0)
+}
+
+func (m *MockEconomicEventRepository) GetEvents(ctx context.Context, country string, from, to int64) ([]models.EconomicEvent, error) {
+	args := m.Called(ctx, country, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.EconomicEvent), args.Error(1)
+}
+
+func TestEconomicCalendarService_IngestEvents(t *testing.T) {
+	repo := new(MockEconomicEventRepository)
+	repo.On("PutEvents", mock.Anything, []models.EconomicEvent{
+		{Country: "US", ID: "1741354200#jobs", Timestamp: 1741354200, Type: "jobs", Title: "Nonfarm Payrolls"},
+		{Country: "CA", ID: "1741354200#cpi", Timestamp: 1741354200, Type: "cpi", Title: "CPI y/y"},
+	}).Return(nil)
+
+	svc := NewEconomicCalendarService(repo, zap.NewNop().Sugar())
+	count, err := svc.IngestEvents(context.Background(), []models.EconomicEvent{
+		{Timestamp: 1741354200, Type: "JOBS"},
+		{Country: "ca", Timestamp: 1741354200, Type: "cpi", Title: "CPI y/y"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	repo.AssertExpectations(t)
+}
+
+func TestEconomicCalendarService_IngestEventsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		events  []models.EconomicEvent
+		repoErr error
+		wantErr error
+	}{
+		{
+			name:    "empty batch",
+			wantErr: ErrInvalidEvent,
+		},
+		{
+			name:    "unknown type",
+			events:  []models.EconomicEvent{{Timestamp: 1741354200, Type: "earnings"}},
+			wantErr: ErrInvalidEvent,
+		},
+		{
+			name:    "missing timestamp",
+			events:  []models.EconomicEvent{{Type: "cpi"}},
+			wantErr: ErrInvalidEvent,
+		},
+		{
+			name:    "repository error",
+			events:  []models.EconomicEvent{{Timestamp: 1741354200, Type: "fomc"}},
+			repoErr: errors.New("throttled"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockEconomicEventRepository)
+			repo.On("PutEvents", mock.Anything, mock.Anything).Return(tt.repoErr)
+
+			svc := NewEconomicCalendarService(repo, zap.NewNop().Sugar())
+			_, err := svc.IngestEvents(context.Background(), tt.events)
+
+			require.Error(t, err)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "PutEvents", mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestEconomicCalendarService_GetEvents(t *testing.T) {
+	repo := new(MockEconomicEventRepository)
+	events := []models.EconomicEvent{{Country: "US", Type: "fomc", Timestamp: 1742320800}}
+	repo.On("GetEvents", mock.Anything, "US", int64(1740787200), int64(1743465599)).Return(events, nil)
+
+	svc := NewEconomicCalendarService(repo, zap.NewNop().Sugar())
+
+	got, err := svc.GetEvents(context.Background(), "", 1740787200, 1743465599)
+	require.NoError(t, err)
+	assert.Equal(t, events, got)
+
+	_, err = svc.GetEvents(context.Background(), "US", 1743465599, 1740787200)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = svc.GetEvents(context.Background(), "US", 0, 1743465599)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"profitify-backend/pkg/events"
+
+	"go.uber.org/zap"
+)
+
+// PublishEvent publishes a domain event of a type and schema version. The
+// change it reports is already stored, so a failure is logged rather than
+// failing the change; a nil publisher publishes nothing.
+func PublishEvent(ctx context.Context, publisher events.Publisher, log *zap.SugaredLogger, eventType string, version int, data any) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.Publish(ctx, events.New(eventType, version, data)); err != nil {
+		log.Warnw("failed to publish event", "type", eventType, "error", err)
+	}
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidWindow = errors.New("invalid window")
+
+// unclassified groups tickers without sector or industry metadata
+const unclassified = "Unclassified"
+
+type HeatmapService interface {
+	GetHeatmap(ctx context.Context, window string) (*models.Heatmap, error)
+	Refresh(ctx context.Context) error
+}
+
+type heatmapService struct {
+	tickers   repository.TickerRepository
+	summaries repository.DailySummaryRepository
+	ttl       time.Duration
+	clock     clock.Clock
+	log       *zap.SugaredLogger
+
+	refreshMu sync.Mutex
+	mu        sync.RWMutex
+	heatmaps  map[string]*models.Heatmap
+	refreshed time.Time
+}
+
+// NewHeatmapService creates a heatmap service whose precomputed payloads are
+// served for ttl before being rebuilt on demand
+func NewHeatmapService(
+	tickers repository.TickerRepository,
+	summaries repository.DailySummaryRepository,
+	ttl time.Duration,
+	c clock.Clock,
+	log *zap.SugaredLogger,
+) HeatmapService {
+	return &heatmapService{
+		tickers:   tickers,
+		summaries: summaries,
+		ttl:       ttl,
+		clock:     c,
+		log:       log,
+	}
+}
+
+func (s *heatmapService) GetHeatmap(ctx context.Context, window string) (*models.Heatmap, error) {
+	if _, ok := models.HeatmapSessions[window]; !ok {
+		return nil, fmt.Errorf("%w: %q, expected 1d, 1w or 1m", ErrInvalidWindow, window)
+	}
+
+	if heatmap, ok := s.cached(window); ok {
+		return heatmap, nil
+	}
+
+	// Only one request rebuilds a stale cache; the rest wait and reuse it
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if heatmap, ok := s.cached(window); ok {
+		return heatmap, nil
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	heatmap, _ := s.cached(window)
+	return heatmap, nil
+}
+
+func (s *heatmapService) cached(window string) (*models.Heatmap, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.heatmaps == nil || s.clock.Now().Sub(s.refreshed) >= s.ttl {
+		return nil, false
+	}
+	return s.heatmaps[window], true
+}
+
+// Refresh rebuilds the heatmaps of every window in a single pass over the active tickers
+func (s *heatmapService) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	return s.refresh(ctx)
+}
+
+func (s *heatmapService) refresh(ctx context.Context) error {
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers for heatmap", "error", err)
+		return fmt.Errorf("failed to get active tickers: %w", err)
+	}
+
+	maxSessions := 0
+	for _, n := range models.HeatmapSessions {
+		if n > maxSessions {
+			maxSessions = n
+		}
+	}
+
+	// Calendar days covering the longest window plus weekends and holidays
+	now := s.clock.Now()
+	to := now.Unix()
+	from := now.AddDate(0, 0, -(maxSessions*2 + 10)).Unix()
+
+	histories := make(map[string][]models.DailySummary, len(tickers))
+	for _, t := range tickers {
+		history, err := s.summaries.GetSummaries(ctx, t.Ticker, from, to)
+		if err != nil {
+			logger.FromContext(ctx, s.log).Warnw("skipping ticker in heatmap", "symbol", t.Ticker, "error", err)
+			continue
+		}
+		histories[t.Ticker] = history
+	}
+
+	generated := s.clock.Now().Unix()
+	heatmaps := make(map[string]*models.Heatmap, len(models.HeatmapSessions))
+	for window, sessions := range models.HeatmapSessions {
+		heatmap := buildHeatmap(tickers, histories, sessions)
+		heatmap.Window = window
+		heatmap.GeneratedUTC = generated
+		heatmaps[window] = heatmap
+	}
+
+	s.mu.Lock()
+	s.heatmaps = heatmaps
+	s.refreshed = s.clock.Now()
+	s.mu.Unlock()
+
+	logger.FromContext(ctx, s.log).Infow("heatmaps refreshed", "tickers", len(tickers), "priced", len(histories))
+	return nil
+}
+
+// heatmapNode accumulates dollar-volume weighted performance for a tree node
+type heatmapNode struct {
+	dollarVolume float64
+	weightedPct  float64
+}
+
+func (n *heatmapNode) add(dollarVolume, pct float64) {
+	n.dollarVolume += dollarVolume
+	n.weightedPct += dollarVolume * pct
+}
+
+func (n *heatmapNode) changePercent() float64 {
+	if n.dollarVolume == 0 {
+		return 0
+	}
+	return n.weightedPct / n.dollarVolume
+}
+
+func (n *heatmapNode) share(of heatmapNode) float64 {
+	if of.dollarVolume == 0 {
+		return 0
+	}
+	return n.dollarVolume / of.dollarVolume
+}
+
+// buildHeatmap computes the change over the last sessions for every ticker with
+// enough history and aggregates it into the sector > industry > ticker tree
+func buildHeatmap(tickers []models.Ticker, histories map[string][]models.DailySummary, sessions int) *models.Heatmap {
+	type leaf struct {
+		ticker models.HeatmapTicker
+		node   heatmapNode
+	}
+	type industry struct {
+		node   heatmapNode
+		leaves []leaf
+	}
+	type sector struct {
+		node       heatmapNode
+		industries map[string]*industry
+	}
+
+	var total heatmapNode
+	var asOf int64
+	sectors := make(map[string]*sector)
+
+	for _, t := range tickers {
+		history := histories[t.Ticker]
+		if len(history) <= sessions {
+			continue
+		}
+
+		last := history[len(history)-1]
+		base := history[len(history)-1-sessions]
+		if base.Close <= 0 {
+			continue
+		}
+		if last.Timestamp > asOf {
+			asOf = last.Timestamp
+		}
+
+		pct := (float64(last.Close) - float64(base.Close)) / float64(base.Close) * 100
+		var dollarVolume float64
+		for _, d := range history[len(history)-sessions:] {
+			dollarVolume += float64(d.Close) * float64(d.Volume)
+		}
+
+		sectorName, industryName := t.Sector, t.Industry
+		if sectorName == "" {
+			sectorName = unclassified
+		}
+		if industryName == "" {
+			industryName = unclassified
+		}
+
+		sec, ok := sectors[sectorName]
+		if !ok {
+			sec = &sector{industries: make(map[string]*industry)}
+			sectors[sectorName] = sec
+		}
+		ind, ok := sec.industries[industryName]
+		if !ok {
+			ind = &industry{}
+			sec.industries[industryName] = ind
+		}
+
+		l := leaf{ticker: models.HeatmapTicker{
+			Ticker:        t.Ticker,
+			Name:          t.Name,
+			Close:         last.Close,
+			ChangePercent: pct,
+		}}
+		l.node.add(dollarVolume, pct)
+		ind.leaves = append(ind.leaves, l)
+		ind.node.add(dollarVolume, pct)
+		sec.node.add(dollarVolume, pct)
+		total.add(dollarVolume, pct)
+	}
+
+	heatmap := &models.Heatmap{
+		AsOf:          asOf,
+		ChangePercent: total.changePercent(),
+		Sectors:       make([]models.HeatmapSector, 0, len(sectors)),
+	}
+
+	for sectorName, sec := range sectors {
+		hs := models.HeatmapSector{
+			Sector:        sectorName,
+			ChangePercent: sec.node.changePercent(),
+			Weight:        sec.node.share(total),
+		}
+		for industryName, ind := range sec.industries {
+			hi := models.HeatmapIndustry{
+				Industry:      industryName,
+				ChangePercent: ind.node.changePercent(),
+				Weight:        ind.node.share(sec.node),
+			}
+			for _, l := range ind.leaves {
+				l.ticker.Weight = l.node.share(ind.node)
+				hi.Tickers = append(hi.Tickers, l.ticker)
+			}
+			sort.Slice(hi.Tickers, func(i, j int) bool { return hi.Tickers[i].Weight > hi.Tickers[j].Weight })
+			hs.Industries = append(hs.Industries, hi)
+		}
+		sort.Slice(hs.Industries, func(i, j int) bool { return hs.Industries[i].Weight > hs.Industries[j].Weight })
+		heatmap.Sectors = append(heatmap.Sectors, hs)
+	}
+	sort.Slice(heatmap.Sectors, func(i, j int) bool { return heatmap.Sectors[i].Weight > heatmap.Sectors[j].Weight })
+
+	return heatmap
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestBuildHeatmap(t *testing.T) {
+	tickers := []models.Ticker{
+		{Ticker: "AAPL", Sector: "Technology", Industry: "Hardware"},
+		{Ticker: "MSFT", Sector: "Technology", Industry: "Software"},
+		{Ticker: "KO", Sector: "Consumer Defensive", Industry: "Beverages"},
+		{Ticker: "NEW"},
+		{Ticker: "THIN", Sector: "Technology", Industry: "Software"},
+	}
+	bars := func(closes ...float32) []models.DailySummary {
+		out := make([]models.DailySummary, len(closes))
+		for i, c := range closes {
+			out[i] = models.DailySummary{Timestamp: int64(i + 1), Close: c, Volume: 10}
+		}
+		return out
+	}
+	histories := map[string][]models.DailySummary{
+		"AAPL": bars(100, 110), // +10%, $1100 dollar volume
+		"MSFT": bars(100, 90),  // -10%, $900
+		"KO":   bars(50, 50),   // 0%, $500
+		"NEW":  bars(10, 20),   // +100%, $200
+		"THIN": bars(10),       // not enough history
+	}
+
+	heatmap := buildHeatmap(tickers, histories, 1)
+
+	require.Len(t, heatmap.Sectors, 3)
+	tech := heatmap.Sectors[0]
+	assert.Equal(t, "Technology", tech.Sector)
+	assert.InDelta(t, 2000.0/2700, tech.Weight, 1e-9)
+	assert.InDelta(t, (1100*10-900*10)/2000.0, tech.ChangePercent, 1e-9)
+	require.Len(t, tech.Industries, 2)
+	assert.Equal(t, "Hardware", tech.Industries[0].Industry)
+	assert.InDelta(t, 0.55, tech.Industries[0].Weight, 1e-9)
+	assert.Len(t, tech.Industries[1].Tickers, 1, "tickers without enough history are left out")
+
+	assert.Equal(t, unclassified, heatmap.Sectors[2].Sector)
+	assert.Equal(t, unclassified, heatmap.Sectors[2].Industries[0].Industry)
+	assert.Equal(t, int64(2), heatmap.AsOf)
+}
+
+func TestHeatmapService_GetHeatmap(t *testing.T) {
+	tickerRepo := repository.NewMockTickerRepository()
+	tickerRepo.SetTickers([]models.Ticker{{Ticker: "AAPL", Active: 1, Sector: "Technology"}})
+	summaryRepo := new(repository.MockDailySummaryRepository)
+	summaryRepo.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).Return([]models.DailySummary{
+		{Timestamp: 1, Close: 100, Volume: 1},
+		{Timestamp: 2, Close: 105, Volume: 1},
+	}, nil)
+	now := clock.NewFake(time.Now())
+	svc := NewHeatmapService(tickerRepo, summaryRepo, time.Minute, now, zap.NewNop().Sugar())
+
+	_, err := svc.GetHeatmap(context.Background(), "2y")
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+
+	heatmap, err := svc.GetHeatmap(context.Background(), models.HeatmapWindowDay)
+	require.NoError(t, err)
+	assert.Equal(t, "1d", heatmap.Window)
+	assert.InDelta(t, 5.0, heatmap.ChangePercent, 1e-4)
+
+	weekly, err := svc.GetHeatmap(context.Background(), models.HeatmapWindowWeek)
+	require.NoError(t, err)
+	assert.Empty(t, weekly.Sectors)
+
+	// Both windows were served from a single refresh
+	assert.Len(t, tickerRepo.Calls.GetActiveTickers, 1)
+
+	now.Advance(time.Minute)
+	heatmap, err = svc.GetHeatmap(context.Background(), models.HeatmapWindowDay)
+	require.NoError(t, err)
+	assert.Len(t, tickerRepo.Calls.GetActiveTickers, 2, "heatmaps are refreshed once the ttl has passed")
+	assert.Equal(t, now.Now().Unix(), heatmap.GeneratedUTC)
+}
+
+// TestHeatmapService_Concurrent requests every window at once from a cold
+// cache, for the race detector: go test -race
+func TestHeatmapService_Concurrent(t *testing.T) {
+	tickerRepo := repository.NewMockTickerRepository()
+	tickerRepo.SetTickers([]models.Ticker{{Ticker: "AAPL", Active: 1, Sector: "Technology"}})
+	summaryRepo := new(repository.MockDailySummaryRepository)
+	summaryRepo.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).Return([]models.DailySummary{
+		{Timestamp: 1, Close: 100, Volume: 1},
+		{Timestamp: 2, Close: 105, Volume: 1},
+	}, nil)
+	svc := NewHeatmapService(tickerRepo, summaryRepo, time.Minute, clock.System, zap.NewNop().Sugar())
+
+	windows := []string{models.HeatmapWindowDay, models.HeatmapWindowWeek}
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				window := windows[(w+i)%len(windows)]
+				heatmap, err := svc.GetHeatmap(context.Background(), window)
+				if assert.NoError(t, err) {
+					assert.Equal(t, window, heatmap.Window)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, tickerRepo.Calls.GetActiveTickers, 1, "a single request refreshes the cache")
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrIngestionUnavailable = errors.New("no market data source is configured")
+	ErrIngestJobNotFound    = errors.New("ingest job not found")
+	ErrIngestQueueFull      = errors.New("too many ingest jobs are queued")
+)
+
+const (
+	// MaxIngestDays bounds the range a single on-demand ingest may refresh
+	MaxIngestDays = 5 * 366
+	// ingestQueueSize bounds how many ingest jobs may wait for the worker
+	ingestQueueSize = 32
+	// ingestJobRetention is how long finished ingest jobs remain queryable
+	ingestJobRetention = 24 * time.Hour
+)
+
+// SummarySource fetches the daily summaries of a ticker over an inclusive date
+// range from an upstream market data provider
+type SummarySource interface {
+	FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error)
+}
+
+type IngestService interface {
+	Enqueue(ctx context.Context, symbol string, from, to time.Time) (*models.IngestJob, error)
+	GetJob(ctx context.Context, id string) (*models.IngestJob, error)
+	// Work runs queued jobs one at a time until ctx is cancelled
+	Work(ctx context.Context) error
+}
+
+type ingestRequest struct {
+	job      *models.IngestJob
+	from, to time.Time
+}
+
+type ingestService struct {
+	source    SummarySource
+	summaries repository.DailySummaryRepository
+	settings  SettingsService
+	events    events.Publisher
+	log       *zap.SugaredLogger
+	queue     chan ingestRequest
+}
+
+// NewIngestService queues ingest jobs for a worker, run with Work, that runs
+// them one at a time so that on-demand refreshes do not compete for the
+// provider's rate limit. Jobs are stored in the settings table, so any replica
+// reports them. A nil source disables ingestion. Completed jobs that stored
+// summaries publish a DailySummaryIngested event.
+func NewIngestService(source SummarySource, summaries repository.DailySummaryRepository, settings SettingsService, publisher events.Publisher, log *zap.SugaredLogger) IngestService {
+	return &ingestService{
+		source:    source,
+		summaries: summaries,
+		settings:  settings,
+		events:    publisher,
+		log:       log,
+		queue:     make(chan ingestRequest, ingestQueueSize),
+	}
+}
+
+// Enqueue queues a refresh of a ticker's daily summaries over [from, to]. The
+// returned job can be polled with GetJob.
+func (s *ingestService) Enqueue(ctx context.Context, symbol string, from, to time.Time) (*models.IngestJob, error) {
+	if s.source == nil {
+		return nil, ErrIngestionUnavailable
+	}
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidRange)
+	}
+	if to.Sub(from) > MaxIngestDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: ingest spans more than %d days", ErrInvalidRange, MaxIngestDays)
+	}
+
+	id, err := NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	pruneFinishedJobs(ctx, s.settings, models.IngestJobKey(""), ingestJobRetention, s.log)
+
+	job := &models.IngestJob{
+		ID:         id,
+		Ticker:     symbol,
+		From:       from.Format(models.DateLayout),
+		To:         to.Format(models.DateLayout),
+		Status:     models.IngestStatusQueued,
+		CreatedUTC: time.Now().Unix(),
+	}
+	if key, ok := AccountFromContext(ctx); ok {
+		job.KeyID = key.ID
+	}
+	// Stored before it is queued, so the worker's updates come after
+	if err := s.settings.PutJSON(ctx, models.IngestJobKey(id), job); err != nil {
+		return nil, fmt.Errorf("failed to store ingest job: %w", err)
+	}
+	snapshot := *job
+
+	select {
+	case s.queue <- ingestRequest{job: job, from: from, to: to}:
+	default:
+		if err := s.settings.DeleteSetting(ctx, models.IngestJobKey(id)); err != nil {
+			logger.FromContext(ctx, s.log).Warnw("failed to delete rejected ingest job", "job", id, "error", err)
+		}
+		return nil, ErrIngestQueueFull
+	}
+
+	logger.FromContext(ctx, s.log).Infow("ingest queued", "symbol", symbol, "job", id, "from", job.From, "to", job.To)
+	return &snapshot, nil
+}
+
+func (s *ingestService) GetJob(ctx context.Context, id string) (*models.IngestJob, error) {
+	var job models.IngestJob
+	found, err := s.settings.GetJSON(ctx, models.IngestJobKey(id), &job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingest job: %w", err)
+	}
+	if !found {
+		return nil, ErrIngestJobNotFound
+	}
+	return &job, nil
+}
+
+// Work runs queued jobs until ctx is cancelled. Jobs still queued then are
+// marked failed, so they do not appear to wait forever.
+func (s *ingestService) Work(ctx context.Context) error {
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case req := <-s.queue:
+			s.run(ctx, req)
+		}
+	}
+
+	for {
+		select {
+		case req := <-s.queue:
+			s.finish(ctx, req.job, 0, errors.New("server shut down before the job ran"))
+		default:
+			return ctx.Err()
+		}
+	}
+}
+
+// run fetches and stores a job's summaries. Summaries that fail validation are
+// skipped rather than failing the whole refresh.
+func (s *ingestService) run(ctx context.Context, req ingestRequest) {
+	job := req.job
+	job.Status = models.IngestStatusRunning
+	job.StartedUTC = time.Now().Unix()
+	s.save(ctx, job)
+
+	stored, err := s.ingest(ctx, req)
+	s.finish(ctx, job, stored, err)
+}
+
+// finish records the outcome of a job, even once ctx is cancelled
+func (s *ingestService) finish(ctx context.Context, job *models.IngestJob, stored int, err error) {
+	job.Stored = stored
+	job.CompletedUTC = time.Now().Unix()
+	if err != nil {
+		job.Status = models.IngestStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = models.IngestStatusCompleted
+	}
+	s.save(context.WithoutCancel(ctx), job)
+
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("ingest failed", "symbol", job.Ticker, "job", job.ID, "error", err)
+		return
+	}
+	logger.FromContext(ctx, s.log).Infow("ingest completed", "symbol", job.Ticker, "job", job.ID, "stored", stored)
+	if stored > 0 {
+		PublishEvent(ctx, s.events, s.log, models.EventDailySummaryIngested, models.EventDailySummaryIngestedVersion, models.DailySummaryIngestedEvent{
+			Scope:  models.IngestScopeTicker,
+			Symbol: job.Ticker,
+			From:   job.From,
+			To:     job.To,
+			Stored: stored,
+			JobID:  job.ID,
+			KeyID:  job.KeyID,
+		})
+	}
+}
+
+// save stores the job's progress; a failure only leaves its reported status stale
+func (s *ingestService) save(ctx context.Context, job *models.IngestJob) {
+	if err := s.settings.PutJSON(ctx, models.IngestJobKey(job.ID), job); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to store ingest job", "job", job.ID, "error", err)
+	}
+}
+
+func (s *ingestService) ingest(ctx context.Context, req ingestRequest) (int, error) {
+	symbol := req.job.Ticker
+
+	fetched, err := s.source.FetchDailySummaries(ctx, symbol, req.from, req.to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch daily summaries: %w", err)
+	}
+
+	summaries := make([]models.DailySummary, 0, len(fetched))
+	for _, summary := range fetched {
+		summary.Ticker = symbol
+		if err := summary.Validate(); err != nil {
+			logger.FromContext(ctx, s.log).Warnw("skipping invalid daily summary", "symbol", symbol, "job", req.job.ID, "timestamp", summary.Timestamp, "error", err)
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+
+	if err := s.summaries.PutSummaries(repository.WithRevisionSource(ctx, "admin-ingest:"+req.job.ID), summaries); err != nil {
+		return 0, fmt.Errorf("failed to store daily summaries: %w", err)
+	}
+	return len(summaries), nil
+}
+
+// pruneFinishedJobs deletes the jobs stored under prefix that finished more
+// than retention ago. Failures are logged, as they only leave old jobs behind.
+func pruneFinishedJobs(ctx context.Context, settings SettingsService, prefix string, retention time.Duration, log *zap.SugaredLogger) {
+	stored, err := settings.ListSettings(ctx, prefix)
+	if err != nil {
+		log.Warnw("failed to list finished jobs", "prefix", prefix, "error", err)
+		return
+	}
+
+	cutoff := time.Now().Add(-retention).Unix()
+	for _, setting := range stored {
+		var job struct {
+			CompletedUTC int64 `json:"completedUTC"`
+		}
+		if json.Unmarshal([]byte(setting.Value), &job) != nil || job.CompletedUTC == 0 || job.CompletedUTC > cutoff {
+			continue
+		}
+		if err := settings.DeleteSetting(ctx, setting.Key); err != nil {
+			log.Warnw("failed to delete finished job", "key", setting.Key, "error", err)
+		}
+	}
+}
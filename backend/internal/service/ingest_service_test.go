@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockSummarySource mocks the SummarySource interface
+type MockSummarySource struct {
+	mock.Mock
+}
+
+func (m *MockSummarySource) FetchDailySummaries(ctx context.Context, symbol string, from, to time.Time) ([]models.DailySummary, error) {
+	args := m.Called(ctx, symbol, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailySummary), args.Error(1)
+}
+
+// waitForIngest polls a job until it finishes
+func waitForIngest(t *testing.T, svc IngestService, id string) *models.IngestJob {
+	t.Helper()
+	var job *models.IngestJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = svc.GetJob(context.Background(), id)
+		require.NoError(t, err)
+		return job.CompletedUTC != 0
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestIngestService_Enqueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	source := new(MockSummarySource)
+	source.On("FetchDailySummaries", mock.Anything, "AAPL", from, to).Return([]models.DailySummary{
+		{Timestamp: from.Unix(), Open: 1, High: 2, Low: 1, Close: 2, Volume: 10},
+		{Timestamp: from.Unix() + 86400, Open: 2, High: 1, Low: 2, Close: 2},
+	}, nil)
+	source.On("FetchDailySummaries", mock.Anything, "DOWN", from, to).Return(nil, errors.New("rate limited"))
+
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("PutSummaries", mock.Anything, mock.Anything).Return(nil)
+
+	publisher := &eventstest.RecordingPublisher{}
+	svc := NewIngestService(source, summaries, NewSettingsService(newMemorySettings(), zap.NewNop().Sugar()), publisher, zap.NewNop().Sugar())
+	worked := make(chan error)
+	go func() { worked <- svc.Work(ctx) }()
+
+	t.Run("stores the valid summaries fetched", func(t *testing.T) {
+		job, err := svc.Enqueue(ctx, "AAPL", from, to)
+		require.NoError(t, err)
+		assert.Equal(t, models.IngestStatusQueued, job.Status)
+		assert.Equal(t, "2024-01-01", job.From)
+
+		job = waitForIngest(t, svc, job.ID)
+		assert.Equal(t, models.IngestStatusCompleted, job.Status)
+		assert.Equal(t, 1, job.Stored)
+		summaries.AssertCalled(t, "PutSummaries", mock.Anything, []models.DailySummary{
+			{Ticker: "AAPL", Timestamp: from.Unix(), Open: 1, High: 2, Low: 1, Close: 2, Volume: 10},
+		})
+
+		require.Eventually(t, func() bool { return len(publisher.Events()) == 1 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, models.DailySummaryIngestedEvent{
+			Scope: models.IngestScopeTicker, Symbol: "AAPL", From: "2024-01-01", To: "2024-01-03", Stored: 1, JobID: job.ID,
+		}, publisher.Events()[0].Data)
+	})
+
+	t.Run("records provider failures", func(t *testing.T) {
+		job, err := svc.Enqueue(ctx, "DOWN", from, to)
+		require.NoError(t, err)
+
+		job = waitForIngest(t, svc, job.ID)
+		assert.Equal(t, models.IngestStatusFailed, job.Status)
+		assert.Contains(t, job.Error, "rate limited")
+		assert.Len(t, publisher.Events(), 1, "failed jobs publish nothing")
+	})
+
+	t.Run("validates", func(t *testing.T) {
+		_, err := svc.Enqueue(ctx, "", from, to)
+		assert.ErrorIs(t, err, ErrInvalidTicker)
+		_, err = svc.Enqueue(ctx, "AAPL", to, from)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+		_, err = svc.Enqueue(ctx, "AAPL", from.AddDate(-6, 0, 0), to)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+		_, err = svc.GetJob(ctx, "missing")
+		assert.ErrorIs(t, err, ErrIngestJobNotFound)
+	})
+
+	t.Run("is unavailable without a source", func(t *testing.T) {
+		_, err := NewIngestService(nil, summaries, NewSettingsService(newMemorySettings(), zap.NewNop().Sugar()), nil, zap.NewNop().Sugar()).Enqueue(ctx, "AAPL", from, to)
+		assert.ErrorIs(t, err, ErrIngestionUnavailable)
+	})
+
+	t.Run("fails jobs still queued at shutdown", func(t *testing.T) {
+		cancel()
+		assert.ErrorIs(t, <-worked, context.Canceled)
+
+		job, err := svc.Enqueue(context.Background(), "AAPL", from, to)
+		require.NoError(t, err)
+		go func() { worked <- svc.Work(ctx) }()
+		<-worked
+
+		job, err = svc.GetJob(context.Background(), job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, models.IngestStatusFailed, job.Status)
+	})
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/logger"
+	"time"
+	_ "time/tzdata" // session boundaries need America/New_York on hosts without zoneinfo
+
+	"go.uber.org/zap"
+)
+
+// maxVWAPRange bounds how far back an anchored VWAP may start
+const maxVWAPRange = 92 * 24 * time.Hour
+
+// marketLocation is the timezone whose calendar days delimit trading sessions
+var marketLocation = loadMarketLocation()
+
+func loadMarketLocation() *time.Location {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+type IntradayService interface {
+	GetBars(ctx context.Context, symbol string, date time.Time, resolution string) (*models.IntradayBars, error)
+	GetVWAP(ctx context.Context, symbol string, anchor, to time.Time) (*models.VWAPSeries, error)
+}
+
+type intradayService struct {
+	repo  repository.IntradayBarRepository
+	clock clock.Clock
+	log   *zap.SugaredLogger
+}
+
+// NewIntradayService serves intraday bars and VWAP; days and times left out
+// of requests default to the current ones of c
+func NewIntradayService(repo repository.IntradayBarRepository, c clock.Clock, log *zap.SugaredLogger) IntradayService {
+	return &intradayService{
+		repo:  repo,
+		clock: c,
+		log:   log,
+	}
+}
+
+// GetBars returns the minute bars of symbol over the trading day of date's
+// calendar date, pre-market to after hours, resampled to resolution. A zero
+// date is today in market time.
+func (s *intradayService) GetBars(ctx context.Context, symbol string, date time.Time, resolution string) (*models.IntradayBars, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+	minutes, ok := models.IntradayResolutions[resolution]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q, expected 1m, 5m or 15m", ErrInvalidResolution, resolution)
+	}
+
+	var day time.Time
+	if date.IsZero() {
+		day = sessionStart(s.clock.Now())
+	} else {
+		y, m, d := date.Date()
+		day = time.Date(y, m, d, 0, 0, 0, 0, marketLocation)
+	}
+
+	bars, err := s.repo.GetBars(ctx, symbol, day.Unix(), day.AddDate(0, 0, 1).Unix()-1)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get intraday bars", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get intraday bars: %w", err)
+	}
+
+	resampled := resampleIntraday(bars, minutes)
+	return &models.IntradayBars{
+		Ticker:     symbol,
+		Date:       day.Format(models.DateLayout),
+		Resolution: resolution,
+		Bars:       resampled,
+		Count:      len(resampled),
+	}, nil
+}
+
+// resampleIntraday merges minute bars ordered by time into bars of the given
+// minutes, each starting at a multiple of them. Sessions open on the half
+// hour, so 5 and 15 minute bars start with the session. The VWAP of a merged
+// bar weighs its minutes' typical prices by volume.
+func resampleIntraday(bars []models.IntradayBar, minutes int) []models.IntradayBar {
+	if minutes <= 1 {
+		if bars == nil {
+			return []models.IntradayBar{}
+		}
+		return bars
+	}
+
+	span := int64(minutes) * 60
+	resampled := make([]models.IntradayBar, 0, len(bars)/minutes+1)
+	var pv, volume float64
+	for i := range bars {
+		bar := &bars[i]
+		start := bar.Timestamp - bar.Timestamp%span
+		if n := len(resampled); n == 0 || resampled[n-1].Timestamp != start {
+			if n > 0 && volume > 0 {
+				resampled[n-1].VWAP = float32(pv / volume)
+			}
+			pv, volume = 0, 0
+			resampled = append(resampled, models.IntradayBar{
+				Ticker:    bar.Ticker,
+				Timestamp: start,
+				Open:      bar.Open,
+				High:      bar.High,
+				Low:       bar.Low,
+			})
+		}
+
+		merged := &resampled[len(resampled)-1]
+		merged.High = max(merged.High, bar.High)
+		merged.Low = min(merged.Low, bar.Low)
+		merged.Close = bar.Close
+		merged.Volume += bar.Volume
+		merged.TransactionCount += bar.TransactionCount
+		pv += bar.TypicalPrice() * float64(bar.Volume)
+		volume += float64(bar.Volume)
+	}
+	if n := len(resampled); n > 0 && volume > 0 {
+		resampled[n-1].VWAP = float32(pv / volume)
+	}
+	return resampled
+}
+
+// GetVWAP computes session VWAP (reset at each trading day) and VWAP anchored at
+// the start of anchor's calendar day for every intraday bar up to to. A zero
+// anchor anchors at the start of to's session; a zero to means now.
+func (s *intradayService) GetVWAP(ctx context.Context, symbol string, anchor, to time.Time) (*models.VWAPSeries, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+
+	if to.IsZero() {
+		to = s.clock.Now()
+	}
+	if anchor.IsZero() {
+		anchor = sessionStart(to)
+	} else {
+		y, m, d := anchor.Date()
+		anchor = time.Date(y, m, d, 0, 0, 0, 0, marketLocation)
+	}
+	if anchor.After(to) {
+		return nil, fmt.Errorf("%w: anchor must not be after to", ErrInvalidRange)
+	}
+	if to.Sub(anchor) > maxVWAPRange {
+		return nil, fmt.Errorf("%w: anchor is more than %d days before to", ErrInvalidRange, int(maxVWAPRange.Hours()/24))
+	}
+
+	logger.FromContext(ctx, s.log).Debugw("fetching intraday bars", "symbol", symbol, "anchor", anchor, "to", to)
+
+	bars, err := s.repo.GetBars(ctx, symbol, anchor.Unix(), to.Unix())
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get intraday bars", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get intraday bars: %w", err)
+	}
+
+	return &models.VWAPSeries{
+		Ticker: symbol,
+		Anchor: anchor.Unix(),
+		Points: computeVWAP(bars),
+	}, nil
+}
+
+// computeVWAP accumulates price*volume over bars ordered by time. Bars without
+// volume carry the previous VWAP forward.
+func computeVWAP(bars []models.IntradayBar) []models.VWAPPoint {
+	points := make([]models.VWAPPoint, 0, len(bars))
+
+	var anchoredPV, anchoredVol float64
+	var sessionPV, sessionVol float64
+	var session time.Time
+
+	for i := range bars {
+		bar := &bars[i]
+		if day := sessionStart(time.Unix(bar.Timestamp, 0)); !day.Equal(session) {
+			session = day
+			sessionPV, sessionVol = 0, 0
+		}
+
+		pv := bar.TypicalPrice() * float64(bar.Volume)
+		anchoredPV += pv
+		anchoredVol += float64(bar.Volume)
+		sessionPV += pv
+		sessionVol += float64(bar.Volume)
+
+		point := models.VWAPPoint{
+			Timestamp: bar.Timestamp,
+			Close:     bar.Close,
+		}
+		if sessionVol > 0 {
+			point.SessionVWAP = sessionPV / sessionVol
+		}
+		if anchoredVol > 0 {
+			point.AnchoredVWAP = anchoredPV / anchoredVol
+		}
+		points = append(points, point)
+	}
+
+	return points
+}
+
+// sessionStart returns midnight of t's trading day in the market timezone
+func sessionStart(t time.Time) time.Time {
+	y, m, d := t.In(marketLocation).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, marketLocation)
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockIntradayBarRepository mocks the IntradayBarRepository interface
+type MockIntradayBarRepository struct {
+	mock.Mock
+}
+
+func (m *MockIntradayBarRepository) GetBars(ctx context.Context, symbol string, from, to int64) ([]models.IntradayBar, error) {
+	args := m.Called(ctx, symbol, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.IntradayBar), args.Error(1)
+}
+
+func (m *MockIntradayBarRepository) PutBars(ctx context.Context, bars []models.IntradayBar) error {
+	return m.Called(ctx, bars).Error(0)
+}
+
+func (m *MockIntradayBarRepository) DeleteBars(ctx context.Context, symbol string, throttle repository.Throttle) (int, error) {
+	args := m.Called(ctx, symbol, throttle)
+	return args.Int(0), args.Error(1)
+}
+
+func TestComputeVWAP(t *testing.T) {
+	day1 := time.Date(2024, 1, 2, 9, 30, 0, 0, marketLocation)
+	day2 := day1.AddDate(0, 0, 1)
+
+	bars := []models.IntradayBar{
+		{Timestamp: day1.Unix(), High: 11, Low: 9, Close: 10, Volume: 100},
+		{Timestamp: day1.Add(time.Minute).Unix(), High: 13, Low: 11, Close: 12, Volume: 300},
+		{Timestamp: day1.Add(2 * time.Minute).Unix(), High: 12, Low: 12, Close: 12, Volume: 0},
+		{Timestamp: day2.Unix(), High: 21, Low: 19, Close: 20, VWAP: 20.5, Volume: 400},
+	}
+
+	points := computeVWAP(bars)
+
+	require.Len(t, points, 4)
+	assert.InDelta(t, 10.0, points[0].SessionVWAP, 1e-9)
+	assert.InDelta(t, 11.5, points[1].SessionVWAP, 1e-9)
+	assert.InDelta(t, 11.5, points[2].SessionVWAP, 1e-9, "zero-volume bar carries vwap forward")
+	assert.InDelta(t, 20.5, points[3].SessionVWAP, 1e-9, "session resets on a new trading day")
+	assert.InDelta(t, (1000.0+3600+8200)/800, points[3].AnchoredVWAP, 1e-9)
+}
+
+func TestIntradayService_GetVWAP(t *testing.T) {
+	to := time.Date(2024, 1, 10, 20, 0, 0, 0, time.UTC)
+
+	t.Run("anchors at start of anchor date in market time", func(t *testing.T) {
+		repo := new(MockIntradayBarRepository)
+		wantFrom := time.Date(2024, 1, 2, 0, 0, 0, 0, marketLocation).Unix()
+		repo.On("GetBars", mock.Anything, "AAPL", wantFrom, to.Unix()).Return([]models.IntradayBar{}, nil)
+		svc := NewIntradayService(repo, clock.System, zap.NewNop().Sugar())
+
+		series, err := svc.GetVWAP(context.Background(), "AAPL", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), to)
+
+		require.NoError(t, err)
+		assert.Equal(t, wantFrom, series.Anchor)
+		assert.Empty(t, series.Points)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		svc := NewIntradayService(new(MockIntradayBarRepository), clock.System, zap.NewNop().Sugar())
+
+		_, err := svc.GetVWAP(context.Background(), "", time.Time{}, to)
+		assert.ErrorIs(t, err, ErrInvalidTicker)
+
+		_, err = svc.GetVWAP(context.Background(), "AAPL", to.AddDate(0, 0, 2), to)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+
+		_, err = svc.GetVWAP(context.Background(), "AAPL", to.AddDate(-1, 0, 0), to)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+}
+
+func TestResampleIntraday(t *testing.T) {
+	open := time.Date(2024, 1, 2, 9, 30, 0, 0, marketLocation)
+	minute := func(n int, price, volume float32) models.IntradayBar {
+		return models.IntradayBar{
+			Ticker:           "AAPL",
+			Timestamp:        open.Add(time.Duration(n) * time.Minute).Unix(),
+			Open:             price,
+			High:             price + 1,
+			Low:              price - 1,
+			Close:            price + 0.5,
+			Volume:           volume,
+			TransactionCount: 1,
+		}
+	}
+	// A gap at 9:37 and 9:38 leaves the second bar with three minutes
+	bars := []models.IntradayBar{
+		minute(0, 10, 100), minute(1, 12, 300), minute(2, 11, 0), minute(3, 9, 100), minute(4, 10, 0),
+		minute(5, 20, 50), minute(6, 21, 0), minute(9, 22, 50),
+	}
+
+	assert.Equal(t, bars, resampleIntraday(bars, 1))
+	assert.NotNil(t, resampleIntraday(nil, 1))
+	assert.NotNil(t, resampleIntraday(nil, 5))
+
+	fives := resampleIntraday(bars, 5)
+	require.Len(t, fives, 2)
+	first := fives[0]
+	assert.Equal(t, open.Unix(), first.Timestamp, "bars start with the session")
+	assert.Equal(t, float32(10), first.Open)
+	assert.Equal(t, float32(13), first.High)
+	assert.Equal(t, float32(8), first.Low)
+	assert.Equal(t, float32(10.5), first.Close)
+	assert.Equal(t, float32(500), first.Volume)
+	assert.Equal(t, int32(5), first.TransactionCount)
+	// Typical prices, (high+low+close)/3, are a sixth above the opens
+	assert.InDelta(t, (10.0*100+12*300+9*100)/500+1.0/6, first.VWAP, 1e-4)
+
+	second := fives[1]
+	assert.Equal(t, open.Add(5*time.Minute).Unix(), second.Timestamp)
+	assert.Equal(t, float32(20), second.Open)
+	assert.Equal(t, float32(22.5), second.Close)
+	assert.Equal(t, float32(100), second.Volume)
+	assert.Equal(t, int32(3), second.TransactionCount)
+
+	fifteens := resampleIntraday(bars, 15)
+	require.Len(t, fifteens, 1)
+	assert.Equal(t, float32(23), fifteens[0].High)
+	assert.Equal(t, float32(600), fifteens[0].Volume)
+
+	// Minutes without volume leave the VWAP out
+	quiet := resampleIntraday([]models.IntradayBar{minute(0, 10, 0)}, 5)
+	assert.Zero(t, quiet[0].VWAP)
+}
+
+func TestIntradayService_GetBars(t *testing.T) {
+	dayStart := time.Date(2024, 1, 2, 0, 0, 0, 0, marketLocation)
+	dayEnd := dayStart.AddDate(0, 0, 1).Unix() - 1
+	bars := []models.IntradayBar{
+		{Ticker: "AAPL", Timestamp: dayStart.Add(9*time.Hour + 30*time.Minute).Unix(), Open: 10, High: 11, Low: 9, Close: 10, Volume: 1},
+		{Ticker: "AAPL", Timestamp: dayStart.Add(9*time.Hour + 31*time.Minute).Unix(), Open: 10, High: 12, Low: 9, Close: 11, Volume: 1},
+	}
+
+	t.Run("reads the trading day of the date in market time", func(t *testing.T) {
+		repo := new(MockIntradayBarRepository)
+		repo.On("GetBars", mock.Anything, "AAPL", dayStart.Unix(), dayEnd).Return(bars, nil)
+		svc := NewIntradayService(repo, clock.System, zap.NewNop().Sugar())
+
+		got, err := svc.GetBars(context.Background(), "AAPL", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), models.IntradayResolution5m)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-02", got.Date)
+		assert.Equal(t, "5m", got.Resolution)
+		assert.Equal(t, 1, got.Count)
+		assert.Equal(t, float32(12), got.Bars[0].High)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("defaults to today in market time", func(t *testing.T) {
+		repo := new(MockIntradayBarRepository)
+		repo.On("GetBars", mock.Anything, "AAPL", dayStart.Unix(), dayEnd).Return([]models.IntradayBar{}, nil)
+		// 02:00 UTC on the 3rd is still the 2nd in New York
+		now := clock.NewFake(time.Date(2024, 1, 3, 2, 0, 0, 0, time.UTC))
+		svc := NewIntradayService(repo, now, zap.NewNop().Sugar())
+
+		got, err := svc.GetBars(context.Background(), "AAPL", time.Time{}, models.IntradayResolution1m)
+		require.NoError(t, err)
+		assert.Equal(t, "2024-01-02", got.Date)
+		assert.NotNil(t, got.Bars)
+		assert.Zero(t, got.Count)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		svc := NewIntradayService(new(MockIntradayBarRepository), clock.System, zap.NewNop().Sugar())
+
+		_, err := svc.GetBars(context.Background(), "", time.Time{}, models.IntradayResolution1m)
+		assert.ErrorIs(t, err, ErrInvalidTicker)
+		_, err = svc.GetBars(context.Background(), "AAPL", time.Time{}, "1h")
+		assert.ErrorIs(t, err, ErrInvalidResolution)
+	})
+}
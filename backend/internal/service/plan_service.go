@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUpgradeRequired rejects requests a higher plan tier allows
+	ErrUpgradeRequired = errors.New("plan upgrade required")
+	// ErrPlanLimitExceeded rejects requests no plan tier allows
+	ErrPlanLimitExceeded = errors.New("plan limit exceeded")
+)
+
+type accountContextKey struct{}
+
+// WithAccount returns ctx carrying the API key the request was authenticated
+// with. Plan limits are enforced against the key's tier.
+func WithAccount(ctx context.Context, key *models.APIKey) context.Context {
+	return context.WithValue(ctx, accountContextKey{}, key)
+}
+
+// AccountFromContext returns the API key stored by WithAccount, if any
+func AccountFromContext(ctx context.Context) (*models.APIKey, bool) {
+	key, ok := ctx.Value(accountContextKey{}).(*models.APIKey)
+	return key, ok && key != nil
+}
+
+// CallerKeyID returns the ID of the calling API key, or "" when the request
+// carries none. Items owned by keys are only visible to the key that created
+// them.
+func CallerKeyID(ctx context.Context) string {
+	if key, ok := AccountFromContext(ctx); ok {
+		return key.ID
+	}
+	return ""
+}
+
+// LimitedPlan returns the plan limiting the caller. Requests without a key,
+// which happens when authentication is disabled, and admin keys are unlimited.
+func LimitedPlan(ctx context.Context) (models.Plan, bool) {
+	key, ok := AccountFromContext(ctx)
+	if !ok || key.Admin {
+		return models.Plan{}, false
+	}
+	return key.Plan(), true
+}
+
+// EnforcePlanLimit checks n against the limit of the caller's plan picked by
+// limit. Exceeding it is ErrUpgradeRequired when a higher tier allows n.
+func EnforcePlanLimit(ctx context.Context, n int, what string, limit func(models.Plan) int) error {
+	plan, ok := LimitedPlan(ctx)
+	if !ok || withinLimit(n, limit(plan)) {
+		return nil
+	}
+
+	higher := false
+	for _, p := range models.Plans {
+		if p.Tier == plan.Tier {
+			higher = true
+			continue
+		}
+		if higher && withinLimit(n, limit(p)) {
+			return fmt.Errorf("%w: the %s plan allows at most %d %s, the %s plan allows more",
+				ErrUpgradeRequired, plan.Tier, limit(plan), what, p.Tier)
+		}
+	}
+	return fmt.Errorf("%w: the %s plan allows at most %d %s", ErrPlanLimitExceeded, plan.Tier, limit(plan), what)
+}
+
+func withinLimit(n, limit int) bool {
+	return limit == 0 || n <= limit
+}
+
+// PlanHistoryStart returns the earliest unix timestamp the caller's plan may
+// read daily history from, or zero when history is unlimited
+func PlanHistoryStart(ctx context.Context) int64 {
+	plan, ok := LimitedPlan(ctx)
+	if !ok || plan.HistoryDays == 0 {
+		return 0
+	}
+	return time.Now().AddDate(0, 0, -plan.HistoryDays).Unix()
+}
+
+// CheckHistoryDepth rejects reading daily history from further back than the
+// caller's plan allows, counted in whole days
+func CheckHistoryDepth(ctx context.Context, from int64) error {
+	days := int((time.Now().Unix() - from) / int64(24*time.Hour/time.Second))
+	return EnforcePlanLimit(ctx, days, "days of history", func(p models.Plan) int {
+		return p.HistoryDays
+	})
+}
+
+// QuotaService counts requests against the daily quota of the caller's plan
+type QuotaService interface {
+	ConsumeRequest(ctx context.Context) error
+}
+
+// UsageStore adds to the shared daily usage counters requests are counted in
+type UsageStore interface {
+	AddUsage(ctx context.Context, counter models.UsageCounter) (int64, error)
+}
+
+type quotaService struct {
+	repo UsageStore
+	log  *zap.SugaredLogger
+}
+
+func NewQuotaService(repo UsageStore, log *zap.SugaredLogger) QuotaService {
+	return &quotaService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// ConsumeRequest counts a request of the caller on the current UTC day and
+// rejects it once the day's quota is used up. Counts are shared by all
+// replicas. A failure to count is logged and the request allowed.
+func (s *quotaService) ConsumeRequest(ctx context.Context) error {
+	plan, ok := LimitedPlan(ctx)
+	if !ok || plan.RequestsPerDay == 0 {
+		return nil
+	}
+	key, _ := AccountFromContext(ctx)
+
+	count, err := s.repo.AddUsage(ctx, models.UsageCounter{
+		Date:   time.Now().UTC().Format(models.DateLayout),
+		Metric: models.QuotaMetricPrefix + key.ID,
+		Count:  1,
+	})
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to count request against quota", "key", key.Name, "error", err)
+		return nil
+	}
+
+	return EnforcePlanLimit(ctx, int(count), "requests per day", func(p models.Plan) int {
+		return p.RequestsPerDay
+	})
+}
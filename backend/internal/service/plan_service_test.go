@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockUsageStore mocks the UsageStore interface
+type MockUsageStore struct {
+	mock.Mock
+}
+
+func (m *MockUsageStore) AddUsage(ctx context.Context, counter models.UsageCounter) (int64, error) {
+	args := m.Called(ctx, counter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func accountContext(tier models.PlanTier, admin bool) context.Context {
+	return WithAccount(context.Background(), &models.APIKey{ID: "key", Name: "test", Tier: tier, Admin: admin})
+}
+
+func TestPlanLimits_HistoryDepth(t *testing.T) {
+	free := models.PlanFor(models.PlanFree)
+	ctx := accountContext(models.PlanFree, false)
+	now := time.Now()
+
+	assert.NoError(t, CheckHistoryDepth(ctx, now.AddDate(0, 0, -free.HistoryDays).Unix()))
+	assert.ErrorIs(t, CheckHistoryDepth(ctx, now.AddDate(0, 0, -free.HistoryDays-1).Unix()), ErrUpgradeRequired)
+	assert.NoError(t, CheckHistoryDepth(accountContext(models.PlanPro, false), now.AddDate(-20, 0, 0).Unix()))
+
+	t.Run("default ranges are cut to the plan", func(t *testing.T) {
+		repo := new(repository.MockDailySummaryRepository)
+		repo.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).Return([]models.DailySummary{}, nil)
+		svc := NewDailySummaryService(repo, zap.NewNop().Sugar())
+
+		_, err := svc.GetDailySummaries(ctx, "AAPL", 0, now.AddDate(0, 0, 1).Unix())
+		require.NoError(t, err)
+
+		from := repo.Calls[0].Arguments.Get(2).(int64)
+		assert.GreaterOrEqual(t, from, now.AddDate(0, 0, -free.HistoryDays).Unix())
+	})
+}
+
+func TestQuotaService_ConsumeRequest(t *testing.T) {
+	limit := int64(models.PlanFor(models.PlanFree).RequestsPerDay)
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		count    int64
+		countErr error
+		wantErr  error
+	}{
+		{name: "within quota", ctx: accountContext(models.PlanFree, false), count: limit},
+		{name: "quota used up", ctx: accountContext(models.PlanFree, false), count: limit + 1, wantErr: ErrUpgradeRequired},
+		{name: "highest tier used up", ctx: accountContext(models.PlanPro, false), count: 1 << 40, wantErr: ErrPlanLimitExceeded},
+		{name: "counting failures allow the request", ctx: accountContext(models.PlanFree, false), countErr: errors.New("throttled")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUsageStore)
+			repo.On("AddUsage", mock.Anything, mock.MatchedBy(func(c models.UsageCounter) bool {
+				return c.Metric == models.QuotaMetricPrefix+"key" && c.Count == 1
+			})).Return(tt.count, tt.countErr)
+			svc := NewQuotaService(repo, zap.NewNop().Sugar())
+
+			err := svc.ConsumeRequest(tt.ctx)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("admin keys are not counted", func(t *testing.T) {
+		repo := new(MockUsageStore)
+		svc := NewQuotaService(repo, zap.NewNop().Sugar())
+
+		assert.NoError(t, svc.ConsumeRequest(accountContext(models.PlanFree, true)))
+		repo.AssertNotCalled(t, "AddUsage", mock.Anything, mock.Anything)
+	})
+}
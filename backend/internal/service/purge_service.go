@@ -0,0 +1,276 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidConfirmation = errors.New("invalid or expired confirmation token")
+	ErrPurgeJobNotFound    = errors.New("purge job not found")
+)
+
+// purgeJobRetention is how long finished purge jobs remain queryable
+const purgeJobRetention = 24 * time.Hour
+
+// PurgeConfig controls how ticker purges are confirmed and paced
+type PurgeConfig struct {
+	// WritesPerSecond caps delete throughput; zero or less disables pacing
+	WritesPerSecond int
+	// ConfirmationTTL is how long a confirmation token stays valid
+	ConfirmationTTL time.Duration
+}
+
+type PurgeService interface {
+	RequestPurge(ctx context.Context, symbol string) (*models.PurgeConfirmation, error)
+	ConfirmPurge(ctx context.Context, symbol, token string) (*models.PurgeJob, error)
+	GetJob(ctx context.Context, id string) (*models.PurgeJob, error)
+}
+
+// TaskStarter starts one-off background tasks, such as jobs started on
+// request, and reports whether a task of the name was not already running
+type TaskStarter interface {
+	Start(name string, fn func(ctx context.Context) error) bool
+}
+
+// purgeTarget is a dataset that can be purged for a ticker
+type purgeTarget struct {
+	name  string
+	purge func(ctx context.Context, symbol string, throttle repository.Throttle) (int, error)
+}
+
+type purgeService struct {
+	targets  []purgeTarget
+	settings SettingsService
+	tasks    TaskStarter
+	cfg      PurgeConfig
+	log      *zap.SugaredLogger
+}
+
+// NewPurgeService purges tickers as background tasks. Confirmation tokens and
+// jobs are stored in the settings table, so a purge requested on one replica
+// can be confirmed and polled on any other.
+func NewPurgeService(
+	summaries repository.DailySummaryRepository,
+	intraday repository.IntradayBarRepository,
+	signals repository.SignalRepository,
+	settings SettingsService,
+	tasks TaskStarter,
+	cfg PurgeConfig,
+	log *zap.SugaredLogger,
+) PurgeService {
+	return &purgeService{
+		targets: []purgeTarget{
+			{name: "dailySummaries", purge: summaries.DeleteSummaries},
+			{name: "intradayBars", purge: intraday.DeleteBars},
+			{name: "signals", purge: signals.DeleteTickerSignals},
+		},
+		settings: settings,
+		tasks:    tasks,
+		cfg:      cfg,
+		log:      log,
+	}
+}
+
+// RequestPurge issues a single-use token that must be presented to ConfirmPurge
+// before the ticker's data is deleted
+func (s *purgeService) RequestPurge(ctx context.Context, symbol string) (*models.PurgeConfirmation, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+
+	token, err := NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate confirmation token: %w", err)
+	}
+
+	s.pruneExpiredConfirmations(ctx)
+
+	expires := time.Now().Add(s.cfg.ConfirmationTTL)
+	confirmation := &models.PurgeConfirmation{
+		Ticker:     symbol,
+		Token:      token,
+		ExpiresUTC: expires.Unix(),
+	}
+	if err := s.settings.PutJSON(ctx, models.PurgeConfirmationKey(token), confirmation); err != nil {
+		return nil, fmt.Errorf("failed to store confirmation token: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("purge requested", "symbol", symbol, "expires", expires)
+	return confirmation, nil
+}
+
+// ConfirmPurge consumes a confirmation token and starts deleting the ticker's
+// data in the background. The returned job can be polled with GetJob.
+func (s *purgeService) ConfirmPurge(ctx context.Context, symbol, token string) (*models.PurgeJob, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+	if err := s.consume(ctx, symbol, token); err != nil {
+		return nil, err
+	}
+
+	id, err := NewID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	pruneFinishedJobs(ctx, s.settings, models.PurgeJobKey(""), purgeJobRetention, s.log)
+
+	job := &models.PurgeJob{
+		ID:         id,
+		Ticker:     symbol,
+		Status:     models.PurgeStatusRunning,
+		Deleted:    make(map[string]int, len(s.targets)),
+		CreatedUTC: time.Now().Unix(),
+	}
+	if err := s.settings.PutJSON(ctx, models.PurgeJobKey(id), job); err != nil {
+		return nil, fmt.Errorf("failed to store purge job: %w", err)
+	}
+	snapshot := copyPurgeJob(job)
+
+	s.tasks.Start("purge:"+id, func(ctx context.Context) error {
+		return s.run(ctx, job)
+	})
+	logger.FromContext(ctx, s.log).Infow("purge started", "symbol", symbol, "job", id)
+
+	return snapshot, nil
+}
+
+func (s *purgeService) GetJob(ctx context.Context, id string) (*models.PurgeJob, error) {
+	var job models.PurgeJob
+	found, err := s.settings.GetJSON(ctx, models.PurgeJobKey(id), &job)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purge job: %w", err)
+	}
+	if !found {
+		return nil, ErrPurgeJobNotFound
+	}
+	return &job, nil
+}
+
+// consume checks a confirmation token against symbol and spends it. Tokens are
+// spent with a versioned write, so concurrent confirmations cannot both win.
+func (s *purgeService) consume(ctx context.Context, symbol, token string) error {
+	key := models.PurgeConfirmationKey(token)
+	setting, err := s.settings.GetSetting(ctx, key)
+	if errors.Is(err, ErrSettingNotFound) {
+		return ErrInvalidConfirmation
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get confirmation token: %w", err)
+	}
+
+	var confirmation models.PurgeConfirmation
+	if json.Unmarshal([]byte(setting.Value), &confirmation) != nil ||
+		confirmation.Ticker != symbol || time.Now().Unix() > confirmation.ExpiresUTC {
+		return ErrInvalidConfirmation
+	}
+
+	if _, err := s.settings.PutSetting(ctx, key, "", &setting.Version); err != nil {
+		if errors.Is(err, ErrSettingConflict) {
+			return ErrInvalidConfirmation
+		}
+		return fmt.Errorf("failed to spend confirmation token: %w", err)
+	}
+	if err := s.settings.DeleteSetting(ctx, key); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to delete spent confirmation token", "error", err)
+	}
+	return nil
+}
+
+// pruneExpiredConfirmations deletes confirmation tokens that can no longer be used
+func (s *purgeService) pruneExpiredConfirmations(ctx context.Context) {
+	stored, err := s.settings.ListSettings(ctx, models.PurgeConfirmationKey(""))
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to list confirmation tokens", "error", err)
+		return
+	}
+
+	now := time.Now().Unix()
+	for _, setting := range stored {
+		var confirmation models.PurgeConfirmation
+		if json.Unmarshal([]byte(setting.Value), &confirmation) == nil && confirmation.ExpiresUTC >= now {
+			continue
+		}
+		if err := s.settings.DeleteSetting(ctx, setting.Key); err != nil {
+			logger.FromContext(ctx, s.log).Warnw("failed to delete expired confirmation token", "error", err)
+		}
+	}
+}
+
+// run deletes each dataset in turn, stopping at the first failure. Progress is
+// stored after each dataset.
+func (s *purgeService) run(ctx context.Context, job *models.PurgeJob) error {
+	throttle := newWriteThrottle(s.cfg.WritesPerSecond)
+	saveCtx := context.WithoutCancel(ctx)
+
+	for _, target := range s.targets {
+		deleted, err := target.purge(ctx, job.Ticker, throttle)
+		job.Deleted[target.name] = deleted
+		if err != nil {
+			job.Status = models.PurgeStatusFailed
+			job.Error = fmt.Sprintf("failed to purge %s: %v", target.name, err)
+			job.CompletedUTC = time.Now().Unix()
+			s.save(saveCtx, job)
+			return errors.New(job.Error)
+		}
+		s.save(saveCtx, job)
+	}
+
+	job.Status = models.PurgeStatusCompleted
+	job.CompletedUTC = time.Now().Unix()
+	s.save(saveCtx, job)
+
+	logger.FromContext(ctx, s.log).Infow("purge completed", "symbol", job.Ticker, "job", job.ID)
+	return nil
+}
+
+// save stores the job's progress; a failure only leaves its reported status stale
+func (s *purgeService) save(ctx context.Context, job *models.PurgeJob) {
+	if err := s.settings.PutJSON(ctx, models.PurgeJobKey(job.ID), job); err != nil {
+		logger.FromContext(ctx, s.log).Warnw("failed to store purge job", "job", job.ID, "error", err)
+	}
+}
+
+// newWriteThrottle paces writes to perSecond items per second across calls
+func newWriteThrottle(perSecond int) repository.Throttle {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	var next time.Time
+	return func(ctx context.Context, n int) error {
+		now := time.Now()
+		if next.Before(now) {
+			next = now
+		}
+		wait := next.Sub(now)
+		next = next.Add(time.Duration(n) * time.Second / time.Duration(perSecond))
+
+		if wait <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+			return nil
+		}
+	}
+}
+
+func copyPurgeJob(job *models.PurgeJob) *models.PurgeJob {
+	c := *job
+	c.Deleted = maps.Clone(job.Deleted)
+	return &c
+}
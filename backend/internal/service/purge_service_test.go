@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockSignalRepository mocks the SignalRepository interface
+type MockSignalRepository struct {
+	mock.Mock
+}
+
+func (m *MockSignalRepository) PutSignals(ctx context.Context, signals []models.Signal) error {
+	args := m.Called(ctx, signals)
+	return args.Error(0)
+}
+
+func (m *MockSignalRepository) GetSignals(ctx context.Context, date string) ([]models.Signal, error) {
+	args := m.Called(ctx, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Signal), args.Error(1)
+}
+
+func (m *MockSignalRepository) DeleteTickerSignals(ctx context.Context, symbol string, throttle repository.Throttle) (int, error) {
+	args := m.Called(ctx, symbol, throttle)
+	return args.Int(0), args.Error(1)
+}
+
+// goTasks starts every task in its own goroutine
+type goTasks struct{}
+
+func (goTasks) Start(name string, fn func(ctx context.Context) error) bool {
+	go fn(context.Background())
+	return true
+}
+
+func newTestPurgeService(summaries *repository.MockDailySummaryRepository, intraday *MockIntradayBarRepository, signals *MockSignalRepository, ttl time.Duration) PurgeService {
+	return newTestPurgeServiceOn(newMemorySettings(), summaries, intraday, signals, ttl)
+}
+
+func newTestPurgeServiceOn(settings *memorySettings, summaries *repository.MockDailySummaryRepository, intraday *MockIntradayBarRepository, signals *MockSignalRepository, ttl time.Duration) PurgeService {
+	return NewPurgeService(summaries, intraday, signals, NewSettingsService(settings, zap.NewNop().Sugar()), goTasks{},
+		PurgeConfig{ConfirmationTTL: ttl}, zap.NewNop().Sugar())
+}
+
+// waitForPurge polls a job until it leaves the running state
+func waitForPurge(t *testing.T, svc PurgeService, id string) *models.PurgeJob {
+	t.Helper()
+	var job *models.PurgeJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = svc.GetJob(context.Background(), id)
+		require.NoError(t, err)
+		return job.Status != models.PurgeStatusRunning
+	}, time.Second, 5*time.Millisecond)
+	return job
+}
+
+func TestPurgeService_ConfirmedPurge(t *testing.T) {
+	summaries := new(repository.MockDailySummaryRepository)
+	intraday := new(MockIntradayBarRepository)
+	signals := new(MockSignalRepository)
+	summaries.On("DeleteSummaries", mock.Anything, "BAD", mock.Anything).Return(250, nil)
+	intraday.On("DeleteBars", mock.Anything, "BAD", mock.Anything).Return(4000, nil)
+	signals.On("DeleteTickerSignals", mock.Anything, "BAD", mock.Anything).Return(3, nil)
+
+	svc := newTestPurgeService(summaries, intraday, signals, time.Minute)
+
+	confirmation, err := svc.RequestPurge(context.Background(), "BAD")
+	require.NoError(t, err)
+	assert.NotEmpty(t, confirmation.Token)
+
+	job, err := svc.ConfirmPurge(context.Background(), "BAD", confirmation.Token)
+	require.NoError(t, err)
+	assert.Equal(t, models.PurgeStatusRunning, job.Status)
+
+	job = waitForPurge(t, svc, job.ID)
+	assert.Equal(t, models.PurgeStatusCompleted, job.Status)
+	assert.Equal(t, map[string]int{"dailySummaries": 250, "intradayBars": 4000, "signals": 3}, job.Deleted)
+
+	// tokens are single use
+	_, err = svc.ConfirmPurge(context.Background(), "BAD", confirmation.Token)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+}
+
+func TestPurgeService_SharesStateAcrossReplicas(t *testing.T) {
+	summaries := new(repository.MockDailySummaryRepository)
+	intraday := new(MockIntradayBarRepository)
+	signals := new(MockSignalRepository)
+	summaries.On("DeleteSummaries", mock.Anything, "BAD", mock.Anything).Return(1, nil)
+	intraday.On("DeleteBars", mock.Anything, "BAD", mock.Anything).Return(2, nil)
+	signals.On("DeleteTickerSignals", mock.Anything, "BAD", mock.Anything).Return(3, nil)
+
+	settings := newMemorySettings()
+	requested := newTestPurgeServiceOn(settings, summaries, intraday, signals, time.Minute)
+	confirmed := newTestPurgeServiceOn(settings, summaries, intraday, signals, time.Minute)
+	polled := newTestPurgeServiceOn(settings, summaries, intraday, signals, time.Minute)
+
+	confirmation, err := requested.RequestPurge(context.Background(), "BAD")
+	require.NoError(t, err)
+	job, err := confirmed.ConfirmPurge(context.Background(), "BAD", confirmation.Token)
+	require.NoError(t, err)
+
+	job = waitForPurge(t, polled, job.ID)
+	assert.Equal(t, models.PurgeStatusCompleted, job.Status)
+	_, err = requested.ConfirmPurge(context.Background(), "BAD", confirmation.Token)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation, "tokens are spent on every replica")
+}
+
+func TestPurgeService_FailedPurge(t *testing.T) {
+	summaries := new(repository.MockDailySummaryRepository)
+	intraday := new(MockIntradayBarRepository)
+	signals := new(MockSignalRepository)
+	summaries.On("DeleteSummaries", mock.Anything, "BAD", mock.Anything).Return(250, nil)
+	intraday.On("DeleteBars", mock.Anything, "BAD", mock.Anything).Return(100, errors.New("throttled"))
+
+	svc := newTestPurgeService(summaries, intraday, signals, time.Minute)
+	confirmation, err := svc.RequestPurge(context.Background(), "BAD")
+	require.NoError(t, err)
+	job, err := svc.ConfirmPurge(context.Background(), "BAD", confirmation.Token)
+	require.NoError(t, err)
+
+	job = waitForPurge(t, svc, job.ID)
+	assert.Equal(t, models.PurgeStatusFailed, job.Status)
+	assert.Equal(t, "failed to purge intradayBars: throttled", job.Error)
+	assert.Equal(t, map[string]int{"dailySummaries": 250, "intradayBars": 100}, job.Deleted)
+	signals.AssertNotCalled(t, "DeleteTickerSignals", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPurgeService_Errors(t *testing.T) {
+	svc := newTestPurgeService(new(repository.MockDailySummaryRepository), new(MockIntradayBarRepository), new(MockSignalRepository), time.Minute)
+	ctx := context.Background()
+
+	_, err := svc.RequestPurge(ctx, "")
+	assert.ErrorIs(t, err, ErrInvalidTicker)
+
+	confirmation, err := svc.RequestPurge(ctx, "BAD")
+	require.NoError(t, err)
+
+	_, err = svc.ConfirmPurge(ctx, "GOOD", confirmation.Token)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation, "token is bound to its ticker")
+
+	_, err = svc.ConfirmPurge(ctx, "BAD", "guess")
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+
+	_, err = svc.GetJob(ctx, "missing")
+	assert.ErrorIs(t, err, ErrPurgeJobNotFound)
+
+	expired := newTestPurgeService(new(repository.MockDailySummaryRepository), new(MockIntradayBarRepository), new(MockSignalRepository), -time.Second)
+	confirmation, err = expired.RequestPurge(ctx, "BAD")
+	require.NoError(t, err)
+	_, err = expired.ConfirmPurge(ctx, "BAD", confirmation.Token)
+	assert.ErrorIs(t, err, ErrInvalidConfirmation)
+}
+
+func TestWriteThrottle(t *testing.T) {
+	assert.Nil(t, newWriteThrottle(0))
+
+	throttle := newWriteThrottle(1000)
+	start := time.Now()
+	require.NoError(t, throttle(context.Background(), 25))
+	require.NoError(t, throttle(context.Background(), 25))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, throttle(ctx, 25), context.Canceled)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidSession rejects session tokens that are unknown, expired or
+// revoked, or whose API key was revoked
+var ErrInvalidSession = errors.New("invalid session")
+
+// SessionClient is the client presenting a session token, recorded as the
+// session's last-seen metadata
+type SessionClient struct {
+	IP        string
+	UserAgent string
+}
+
+type sessionContextKey struct{}
+
+// WithSession returns ctx carrying the ID of the session the request was
+// authenticated with
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionContextKey{}, id)
+}
+
+// SessionFromContext returns the session ID stored by WithSession, if any
+func SessionFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(sessionContextKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSettingNotFound = errors.New("setting not found")
+	ErrSettingConflict = errors.New("setting was modified concurrently")
+	ErrInvalidSetting  = errors.New("invalid setting")
+)
+
+// SettingsService stores small bits of application state (feature flags, job
+// checkpoints, schema version, ingest watermarks) under namespaced keys
+type SettingsService interface {
+	GetSetting(ctx context.Context, key string) (*models.Setting, error)
+	ListSettings(ctx context.Context, prefix string) ([]models.Setting, error)
+	PutSetting(ctx context.Context, key, value string, version *int64) (*models.Setting, error)
+	DeleteSetting(ctx context.Context, key string) error
+	GetJSON(ctx context.Context, key string, dst any) (bool, error)
+	PutJSON(ctx context.Context, key string, value any) error
+	FeatureEnabled(ctx context.Context, name string, defaultValue bool) bool
+	GetCheckpoint(ctx context.Context, job string) (*models.Checkpoint, error)
+	ListCheckpoints(ctx context.Context, jobPrefix string) (map[string]models.Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, job string, checkpoint models.Checkpoint) error
+	ClearCheckpoint(ctx context.Context, job string) error
+}
+
+type settingsService struct {
+	repo repository.SettingsRepository
+	log  *zap.SugaredLogger
+}
+
+func NewSettingsService(repo repository.SettingsRepository, log *zap.SugaredLogger) SettingsService {
+	return &settingsService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+func (s *settingsService) GetSetting(ctx context.Context, key string) (*models.Setting, error) {
+	setting, err := s.repo.GetSetting(ctx, key)
+	if err != nil {
+		return nil, s.mapError(key, err)
+	}
+	return setting, nil
+}
+
+func (s *settingsService) ListSettings(ctx context.Context, prefix string) ([]models.Setting, error) {
+	settings, err := s.repo.ListSettings(ctx, prefix)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list settings", "prefix", prefix, "error", err)
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+	return settings, nil
+}
+
+// PutSetting writes a setting. With a non-nil version the write only succeeds
+// if the stored version still matches, 0 meaning the setting must not exist.
+func (s *settingsService) PutSetting(ctx context.Context, key, value string, version *int64) (*models.Setting, error) {
+	if err := models.ValidateSettingKey(key); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSetting, err)
+	}
+
+	var setting *models.Setting
+	var err error
+	if version != nil {
+		setting, err = s.repo.PutSettingIfVersion(ctx, key, value, *version)
+	} else {
+		setting, err = s.repo.PutSetting(ctx, key, value)
+	}
+	if err != nil {
+		return nil, s.mapError(key, err)
+	}
+
+	logger.FromContext(ctx, s.log).Debugw("setting updated", "key", key, "version", setting.Version)
+	return setting, nil
+}
+
+func (s *settingsService) DeleteSetting(ctx context.Context, key string) error {
+	if err := s.repo.DeleteSetting(ctx, key); err != nil {
+		return s.mapError(key, err)
+	}
+	return nil
+}
+
+// GetJSON decodes a JSON setting into dst and reports whether it was found
+func (s *settingsService) GetJSON(ctx context.Context, key string, dst any) (bool, error) {
+	setting, err := s.GetSetting(ctx, key)
+	if errors.Is(err, ErrSettingNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if err := json.Unmarshal([]byte(setting.Value), dst); err != nil {
+		return false, fmt.Errorf("failed to decode setting %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// PutJSON stores value encoded as JSON, overwriting any previous value
+func (s *settingsService) PutJSON(ctx context.Context, key string, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode setting %s: %w", key, err)
+	}
+
+	_, err = s.PutSetting(ctx, key, string(encoded), nil)
+	return err
+}
+
+// FeatureEnabled reports whether a feature flag is on. Missing, malformed or
+// unreadable flags yield defaultValue.
+func (s *settingsService) FeatureEnabled(ctx context.Context, name string, defaultValue bool) bool {
+	setting, err := s.GetSetting(ctx, models.FeatureFlagKey(name))
+	if err != nil {
+		if !errors.Is(err, ErrSettingNotFound) {
+			logger.FromContext(ctx, s.log).Warnw("failed to read feature flag", "flag", name, "error", err)
+		}
+		return defaultValue
+	}
+
+	enabled, err := strconv.ParseBool(setting.Value)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warnw("malformed feature flag", "flag", name, "value", setting.Value)
+		return defaultValue
+	}
+	return enabled
+}
+
+// GetCheckpoint returns a job's checkpoint, or nil if the job has none
+func (s *settingsService) GetCheckpoint(ctx context.Context, job string) (*models.Checkpoint, error) {
+	var checkpoint models.Checkpoint
+	found, err := s.GetJSON(ctx, models.CheckpointKey(job), &checkpoint)
+	if err != nil || !found {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// ListCheckpoints returns the checkpoints of every job whose name starts with
+// jobPrefix, keyed by job name. Undecodable checkpoints are skipped.
+func (s *settingsService) ListCheckpoints(ctx context.Context, jobPrefix string) (map[string]models.Checkpoint, error) {
+	settings, err := s.ListSettings(ctx, models.CheckpointKey(jobPrefix))
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make(map[string]models.Checkpoint, len(settings))
+	for _, setting := range settings {
+		var checkpoint models.Checkpoint
+		if err := json.Unmarshal([]byte(setting.Value), &checkpoint); err != nil {
+			logger.FromContext(ctx, s.log).Warnw("skipping malformed checkpoint", "key", setting.Key, "error", err)
+			continue
+		}
+		checkpoints[strings.TrimPrefix(setting.Key, models.CheckpointKey(""))] = checkpoint
+	}
+	return checkpoints, nil
+}
+
+// SaveCheckpoint records a job's progress
+func (s *settingsService) SaveCheckpoint(ctx context.Context, job string, checkpoint models.Checkpoint) error {
+	checkpoint.UpdatedUTC = time.Now().Unix()
+	return s.PutJSON(ctx, models.CheckpointKey(job), checkpoint)
+}
+
+// ClearCheckpoint removes a finished job's checkpoint
+func (s *settingsService) ClearCheckpoint(ctx context.Context, job string) error {
+	return s.DeleteSetting(ctx, models.CheckpointKey(job))
+}
+
+// mapError translates repository errors into service errors
+func (s *settingsService) mapError(key string, err error) error {
+	var notFound repository.ErrSettingNotFound
+	if errors.As(err, &notFound) {
+		return fmt.Errorf("%w: %s", ErrSettingNotFound, key)
+	}
+	var conflict repository.ErrSettingConflict
+	if errors.As(err, &conflict) {
+		return fmt.Errorf("%w: %s", ErrSettingConflict, key)
+	}
+	s.log.Errorw("settings operation failed", "key", key, "error", err)
+	return fmt.Errorf("settings operation failed: %w", err)
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockSettingsRepository mocks the SettingsRepository interface
+type MockSettingsRepository struct {
+	mock.Mock
+}
+
+func (m *MockSettingsRepository) GetSetting(ctx context.Context, key string) (*models.Setting, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Setting), args.Error(1)
+}
+
+func (m *MockSettingsRepository) ListSettings(ctx context.Context, prefix string) ([]models.Setting, error) {
+	args := m.Called(ctx, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Setting), args.Error(1)
+}
+
+func (m *MockSettingsRepository) PutSetting(ctx context.Context, key, value string) (*models.Setting, error) {
+	args := m.Called(ctx, key, value)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Setting), args.Error(1)
+}
+
+func (m *MockSettingsRepository) PutSettingIfVersion(ctx context.Context, key, value string, version int64) (*models.Setting, error) {
+	args := m.Called(ctx, key, value, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Setting), args.Error(1)
+}
+
+func (m *MockSettingsRepository) DeleteSetting(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+// memorySettings is a SettingsRepository over a map, for services that keep
+// their state in settings
+type memorySettings struct {
+	mu       sync.Mutex
+	settings map[string]models.Setting
+}
+
+func newMemorySettings() *memorySettings {
+	return &memorySettings{settings: make(map[string]models.Setting)}
+}
+
+func (m *memorySettings) GetSetting(ctx context.Context, key string) (*models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	setting, ok := m.settings[key]
+	if !ok {
+		return nil, repository.ErrSettingNotFound{Key: key}
+	}
+	return &setting, nil
+}
+
+func (m *memorySettings) ListSettings(ctx context.Context, prefix string) ([]models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var settings []models.Setting
+	for key, setting := range m.settings {
+		if strings.HasPrefix(key, prefix) {
+			settings = append(settings, setting)
+		}
+	}
+	return settings, nil
+}
+
+func (m *memorySettings) PutSetting(ctx context.Context, key, value string) (*models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.put(key, value), nil
+}
+
+func (m *memorySettings) PutSettingIfVersion(ctx context.Context, key, value string, version int64) (*models.Setting, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.settings[key].Version != version {
+		return nil, repository.ErrSettingConflict{Key: key}
+	}
+	return m.put(key, value), nil
+}
+
+func (m *memorySettings) put(key, value string) *models.Setting {
+	setting := models.Setting{Key: key, Value: value, Version: m.settings[key].Version + 1}
+	m.settings[key] = setting
+	return &setting
+}
+
+func (m *memorySettings) DeleteSetting(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.settings, key)
+	return nil
+}
+
+func TestSettingsService_PutSetting(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSettingsRepository)
+	repo.On("PutSetting", mock.Anything, "flag:heatmap", "true").Return(&models.Setting{Key: "flag:heatmap", Value: "true", Version: 3}, nil)
+	repo.On("PutSettingIfVersion", mock.Anything, "schema:version", "2", int64(1)).Return(nil, repository.ErrSettingConflict{Key: "schema:version"})
+	svc := NewSettingsService(repo, zap.NewNop().Sugar())
+
+	setting, err := svc.PutSetting(ctx, "flag:heatmap", "true", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), setting.Version)
+
+	version := int64(1)
+	_, err = svc.PutSetting(ctx, "schema:version", "2", &version)
+	assert.ErrorIs(t, err, ErrSettingConflict)
+
+	_, err = svc.PutSetting(ctx, "bad key", "x", nil)
+	assert.ErrorIs(t, err, ErrInvalidSetting)
+}
+
+func TestSettingsService_JSON(t *testing.T) {
+	ctx := context.Background()
+	type checkpoint struct {
+		Ticker string `json:"ticker"`
+	}
+
+	repo := new(MockSettingsRepository)
+	repo.On("PutSetting", mock.Anything, "checkpoint:backfill", `{"ticker":"MSFT"}`).Return(&models.Setting{Key: "checkpoint:backfill"}, nil)
+	repo.On("GetSetting", mock.Anything, "checkpoint:backfill").Return(&models.Setting{Value: `{"ticker":"MSFT"}`}, nil)
+	repo.On("GetSetting", mock.Anything, "checkpoint:missing").Return(nil, repository.ErrSettingNotFound{Key: "checkpoint:missing"})
+	svc := NewSettingsService(repo, zap.NewNop().Sugar())
+
+	require.NoError(t, svc.PutJSON(ctx, "checkpoint:backfill", checkpoint{Ticker: "MSFT"}))
+
+	var got checkpoint
+	found, err := svc.GetJSON(ctx, "checkpoint:backfill", &got)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "MSFT", got.Ticker)
+
+	found, err = svc.GetJSON(ctx, "checkpoint:missing", &got)
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestSettingsService_FeatureEnabled(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSettingsRepository)
+	repo.On("GetSetting", mock.Anything, "flag:on").Return(&models.Setting{Value: "true"}, nil)
+	repo.On("GetSetting", mock.Anything, "flag:off").Return(&models.Setting{Value: "0"}, nil)
+	repo.On("GetSetting", mock.Anything, "flag:garbage").Return(&models.Setting{Value: "maybe"}, nil)
+	repo.On("GetSetting", mock.Anything, "flag:missing").Return(nil, repository.ErrSettingNotFound{Key: "flag:missing"})
+	repo.On("GetSetting", mock.Anything, "flag:broken").Return(nil, errors.New("throttled"))
+	svc := NewSettingsService(repo, zap.NewNop().Sugar())
+
+	assert.True(t, svc.FeatureEnabled(ctx, "on", false))
+	assert.False(t, svc.FeatureEnabled(ctx, "off", true))
+	assert.True(t, svc.FeatureEnabled(ctx, "garbage", true))
+	assert.True(t, svc.FeatureEnabled(ctx, "missing", true))
+	assert.False(t, svc.FeatureEnabled(ctx, "broken", false))
+}
+
+func TestSettingsService_Checkpoints(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockSettingsRepository)
+	repo.On("GetSetting", mock.Anything, "checkpoint:export").Return(nil, repository.ErrSettingNotFound{Key: "checkpoint:export"})
+	repo.On("GetSetting", mock.Anything, "checkpoint:recompute").Return(&models.Setting{Value: `{"ticker":"MSFT","updatedUTC":1700000000}`}, nil)
+	repo.On("ListSettings", mock.Anything, "checkpoint:backfill:").Return([]models.Setting{
+		{Key: "checkpoint:backfill:a", Value: `{"date":"2025-03-05"}`},
+		{Key: "checkpoint:backfill:b", Value: `not json`},
+	}, nil)
+	repo.On("PutSetting", mock.Anything, "checkpoint:export", mock.MatchedBy(func(value string) bool {
+		return strings.HasPrefix(value, `{"ticker":"AAPL","updatedUTC":`)
+	})).Return(&models.Setting{Key: "checkpoint:export"}, nil)
+	svc := NewSettingsService(repo, zap.NewNop().Sugar())
+
+	checkpoint, err := svc.GetCheckpoint(ctx, "export")
+	require.NoError(t, err)
+	assert.Nil(t, checkpoint)
+
+	checkpoint, err = svc.GetCheckpoint(ctx, "recompute")
+	require.NoError(t, err)
+	assert.Equal(t, &models.Checkpoint{Ticker: "MSFT", UpdatedUTC: 1700000000}, checkpoint)
+
+	checkpoints, err := svc.ListCheckpoints(ctx, "backfill:")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]models.Checkpoint{"backfill:a": {Date: "2025-03-05"}}, checkpoints)
+
+	require.NoError(t, svc.SaveCheckpoint(ctx, "export", models.Checkpoint{Ticker: "AAPL"}))
+	repo.AssertExpectations(t)
+}
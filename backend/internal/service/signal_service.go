@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ScannerConfig holds the thresholds of the gap and unusual-volume scanner
+type ScannerConfig struct {
+	// GapPercent flags opens at least this many percent away from the previous close
+	GapPercent float64
+	// VolumeMultiple flags volume at least this multiple of the average volume
+	VolumeMultiple float64
+	// VolumeLookback is the number of prior sessions averaged for volume
+	VolumeLookback int
+}
+
+type SignalService interface {
+	Scan(ctx context.Context, date time.Time) ([]models.Signal, error)
+	GetSignals(ctx context.Context, date time.Time) ([]models.Signal, error)
+}
+
+type signalService struct {
+	tickers   repository.TickerRepository
+	summaries repository.DailySummaryRepository
+	signals   repository.SignalRepository
+	cfg       ScannerConfig
+	log       *zap.SugaredLogger
+}
+
+func NewSignalService(
+	tickers repository.TickerRepository,
+	summaries repository.DailySummaryRepository,
+	signals repository.SignalRepository,
+	cfg ScannerConfig,
+	log *zap.SugaredLogger,
+) SignalService {
+	return &signalService{
+		tickers:   tickers,
+		summaries: summaries,
+		signals:   signals,
+		cfg:       cfg,
+		log:       log,
+	}
+}
+
+// Scan evaluates every active ticker's session on date against the scanner
+// thresholds and persists the resulting signals. Tickers that fail to load are
+// logged and skipped so one bad series doesn't block the market-wide scan.
+func (s *signalService) Scan(ctx context.Context, date time.Time) ([]models.Signal, error) {
+	day := StartOfDay(date)
+	dateStr := day.Format(models.DateLayout)
+
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers for scan", "error", err)
+		return nil, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+
+	// Calendar days covering the volume lookback plus weekends and holidays
+	from := day.AddDate(0, 0, -(s.cfg.VolumeLookback*2 + 10)).Unix()
+	to := day.AddDate(0, 0, 1).Unix() - 1
+	now := time.Now().Unix()
+
+	signals := make([]models.Signal, 0)
+	failed := 0
+	for _, t := range tickers {
+		history, err := s.summaries.GetSummaries(ctx, t.Ticker, from, to)
+		if err != nil {
+			failed++
+			logger.FromContext(ctx, s.log).Warnw("skipping ticker in scan", "symbol", t.Ticker, "error", err)
+			continue
+		}
+
+		for _, sig := range evaluateSignals(history, dateStr, s.cfg) {
+			sig.CreatedUTC = now
+			signals = append(signals, sig)
+		}
+	}
+
+	if len(signals) > 0 {
+		if err := s.signals.PutSignals(ctx, signals); err != nil {
+			logger.FromContext(ctx, s.log).Errorw("failed to store signals", "date", dateStr, "error", err)
+			return nil, fmt.Errorf("failed to store signals: %w", err)
+		}
+	}
+
+	logger.FromContext(ctx, s.log).Infow("market scan completed",
+		"date", dateStr,
+		"tickers", len(tickers),
+		"failed", failed,
+		"signals", len(signals),
+	)
+	return signals, nil
+}
+
+func (s *signalService) GetSignals(ctx context.Context, date time.Time) ([]models.Signal, error) {
+	dateStr := StartOfDay(date).Format(models.DateLayout)
+
+	signals, err := s.signals.GetSignals(ctx, dateStr)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get signals", "date", dateStr, "error", err)
+		return nil, fmt.Errorf("failed to get signals: %w", err)
+	}
+
+	return signals, nil
+}
+
+// evaluateSignals flags the session on date, which must be the last entry of
+// history (ordered oldest first), against the preceding sessions
+func evaluateSignals(history []models.DailySummary, date string, cfg ScannerConfig) []models.Signal {
+	if len(history) < 2 {
+		return nil
+	}
+
+	last := history[len(history)-1]
+	if last.Date() != date {
+		return nil
+	}
+	prior := history[:len(history)-1]
+	prev := prior[len(prior)-1]
+
+	if len(prior) > cfg.VolumeLookback {
+		prior = prior[len(prior)-cfg.VolumeLookback:]
+	}
+	var totalVolume float64
+	for _, d := range prior {
+		totalVolume += float64(d.Volume)
+	}
+	avgVolume := totalVolume / float64(len(prior))
+
+	newSignal := func(signalType string, value float64) models.Signal {
+		sig := models.NewSignal(date, last.Ticker, signalType)
+		sig.Value = value
+		sig.Open = last.Open
+		sig.Close = last.Close
+		sig.PreviousClose = prev.Close
+		sig.Volume = last.Volume
+		sig.AverageVolume = avgVolume
+		return sig
+	}
+
+	var signals []models.Signal
+
+	if prev.Close > 0 {
+		gap := (float64(last.Open) - float64(prev.Close)) / float64(prev.Close) * 100
+		if math.Abs(gap) >= cfg.GapPercent {
+			signalType := models.SignalGapUp
+			if gap < 0 {
+				signalType = models.SignalGapDown
+			}
+			signals = append(signals, newSignal(signalType, gap))
+		}
+	}
+
+	if avgVolume > 0 {
+		multiple := float64(last.Volume) / avgVolume
+		if multiple >= cfg.VolumeMultiple {
+			signals = append(signals, newSignal(models.SignalUnusualVolume, multiple))
+		}
+	}
+
+	return signals
+}
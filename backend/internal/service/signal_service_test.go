@@ -0,0 +1,78 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateSignals(t *testing.T) {
+	cfg := ScannerConfig{GapPercent: 4, VolumeMultiple: 3, VolumeLookback: 3}
+	day := func(d int) int64 { return time.Date(2025, 3, d, 14, 0, 0, 0, time.UTC).Unix() }
+	history := func(lastOpen, lastVolume float32) []models.DailySummary {
+		return []models.DailySummary{
+			{Ticker: "AAPL", Timestamp: day(3), Close: 90, Volume: 9000},
+			{Ticker: "AAPL", Timestamp: day(4), Close: 100, Volume: 1000},
+			{Ticker: "AAPL", Timestamp: day(5), Close: 100, Volume: 1000},
+			{Ticker: "AAPL", Timestamp: day(6), Close: 100, Volume: 1000},
+			{Ticker: "AAPL", Timestamp: day(7), Open: lastOpen, Close: lastOpen, Volume: lastVolume},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		history   []models.DailySummary
+		date      string
+		wantTypes []string
+		wantValue []float64
+	}{
+		{
+			name:      "gap up with unusual volume",
+			history:   history(105, 3500),
+			date:      "2025-03-07",
+			wantTypes: []string{models.SignalGapUp, models.SignalUnusualVolume},
+			wantValue: []float64{5, 3.5},
+		},
+		{
+			name:      "gap down",
+			history:   history(95, 1000),
+			date:      "2025-03-07",
+			wantTypes: []string{models.SignalGapDown},
+			wantValue: []float64{-5},
+		},
+		{
+			name:    "quiet session",
+			history: history(101, 1200),
+			date:    "2025-03-07",
+		},
+		{
+			name:    "no session on scan date",
+			history: history(110, 9000),
+			date:    "2025-03-08",
+		},
+		{
+			name:    "not enough history",
+			history: history(110, 9000)[4:],
+			date:    "2025-03-07",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signals := evaluateSignals(tt.history, tt.date, cfg)
+
+			require.Len(t, signals, len(tt.wantTypes))
+			for i, sig := range signals {
+				assert.Equal(t, tt.wantTypes[i], sig.Type)
+				assert.InDelta(t, tt.wantValue[i], sig.Value, 1e-6)
+				assert.Equal(t, "AAPL#"+tt.wantTypes[i], sig.ID)
+				assert.Equal(t, float32(100), sig.PreviousClose)
+				assert.InDelta(t, 1000, sig.AverageVolume, 1e-6)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrInvalidSignature rejects signed requests of unknown keys, keys without
+	// a signing secret, and signatures that do not match the request
+	ErrInvalidSignature = errors.New("invalid request signature")
+	// ErrStaleRequest rejects signed requests whose timestamp is further from
+	// the server's clock than the allowed skew
+	ErrStaleRequest = errors.New("request timestamp outside the allowed clock skew")
+	// ErrReplayedRequest rejects signed requests whose nonce was already used
+	ErrReplayedRequest = errors.New("request nonce already used")
+)
+
+// maxNonceLength bounds the nonces of signed requests
+const maxNonceLength = 128
+
+// Reasons signed requests are rejected for, as reported to the SignatureObserver
+const (
+	RejectedInvalid = "invalid"
+	RejectedStale   = "stale"
+	RejectedReplay  = "replay"
+)
+
+// SignedRequest is a request authenticated by an HMAC-SHA256 signature made
+// with the signing secret of the key KeyID instead of the key itself
+type SignedRequest struct {
+	KeyID string
+	// Timestamp is the Unix time, in seconds, the request was signed at
+	Timestamp string
+	// Nonce is unique per request of a key
+	Nonce     string
+	Signature string
+	Method    string
+	// Target is the request path with its query string
+	Target string
+	Body   []byte
+}
+
+// StringToSign returns what is signed: the method, target, timestamp, nonce
+// and hex SHA-256 of the body, each on its own line
+func (r SignedRequest) StringToSign() string {
+	body := sha256.Sum256(r.Body)
+	return r.Method + "\n" + r.Target + "\n" + r.Timestamp + "\n" + r.Nonce + "\n" + hex.EncodeToString(body[:])
+}
+
+// SignRequest returns the base64 HMAC-SHA256 signature of the request under
+// secret, as clients compute it
+func SignRequest(secret string, r SignedRequest) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(r.StringToSign()))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SignatureObserver counts the signed requests rejected, by reason
+type SignatureObserver interface {
+	SignatureRejected(reason string)
+}
+
+type SignatureService interface {
+	Verify(ctx context.Context, r SignedRequest) (*models.APIKey, error)
+}
+
+type signatureService struct {
+	keys     repository.APIKeyRepository
+	nonces   repository.NonceRepository
+	skew     time.Duration
+	observer SignatureObserver
+	log      *zap.SugaredLogger
+	now      func() time.Time
+}
+
+// NewSignatureService verifies signed requests against the keys' signing
+// secrets. Requests are accepted within skew of the server's clock, and each
+// nonce is remembered for as long as its request would be accepted.
+func NewSignatureService(keys repository.APIKeyRepository, nonces repository.NonceRepository, skew time.Duration, observer SignatureObserver, log *zap.SugaredLogger) SignatureService {
+	return &signatureService{
+		keys:     keys,
+		nonces:   nonces,
+		skew:     skew,
+		observer: observer,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// Verify returns the key that signed the request. The signature is checked
+// before the nonce is stored, so forged requests cannot burn nonces.
+func (s *signatureService) Verify(ctx context.Context, r SignedRequest) (*models.APIKey, error) {
+	signedAt, err := strconv.ParseInt(r.Timestamp, 10, 64)
+	if err != nil || r.KeyID == "" || r.Nonce == "" || len(r.Nonce) > maxNonceLength {
+		return nil, s.reject(RejectedInvalid, ErrInvalidSignature)
+	}
+
+	now := s.now()
+	if skew := now.Sub(time.Unix(signedAt, 0)); skew > s.skew || skew < -s.skew {
+		return nil, s.reject(RejectedStale, ErrStaleRequest)
+	}
+
+	key, err := s.keys.GetKey(ctx, r.KeyID)
+	if err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return nil, s.reject(RejectedInvalid, ErrInvalidSignature)
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to look up api key", "error", err)
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	if key.Disabled() || !key.CanSign() {
+		return nil, s.reject(RejectedInvalid, ErrInvalidSignature)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(r.Signature)
+	want, _ := base64.StdEncoding.DecodeString(SignRequest(key.SigningSecret, r))
+	if err != nil || !hmac.Equal(signature, want) {
+		return nil, s.reject(RejectedInvalid, ErrInvalidSignature)
+	}
+
+	// Nonces are scoped to the key and kept until the request's timestamp
+	// leaves the accepted window
+	fresh, err := s.nonces.Remember(ctx, key.ID+"#"+r.Nonce, time.Unix(signedAt, 0).Add(s.skew))
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store request nonce", "error", err)
+		return nil, fmt.Errorf("failed to store request nonce: %w", err)
+	}
+	if !fresh {
+		logger.FromContext(ctx, s.log).Warnw("rejected replayed request", "key", key.Name, "target", r.Target)
+		return nil, s.reject(RejectedReplay, ErrReplayedRequest)
+	}
+
+	return key, nil
+}
+
+func (s *signatureService) reject(reason string, err error) error {
+	if s.observer != nil {
+		s.observer.SignatureRejected(reason)
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryNonces is a NonceRepository over a map
+type memoryNonces map[string]time.Time
+
+func (m memoryNonces) Remember(ctx context.Context, nonce string, expires time.Time) (bool, error) {
+	if stored, ok := m[nonce]; ok && stored.After(time.Now()) {
+		return false, nil
+	}
+	m[nonce] = expires
+	return true, nil
+}
+
+// rejections counts the rejected signed requests by reason
+type rejections map[string]int
+
+func (r rejections) SignatureRejected(reason string) {
+	r[reason]++
+}
+
+func TestSignatureService_Verify(t *testing.T) {
+	now := time.Now()
+	keys := new(MockAPIKeyRepository)
+	keys.On("GetKey", mock.Anything, "signer").Return(&models.APIKey{ID: "signer", Name: "partner", SigningSecret: "s3cret"}, nil)
+	keys.On("GetKey", mock.Anything, "unsigned").Return(&models.APIKey{ID: "unsigned", Name: "plain"}, nil)
+	keys.On("GetKey", mock.Anything, "revoked").Return(&models.APIKey{ID: "revoked", SigningSecret: "s3cret", RevokedUTC: 1}, nil)
+	keys.On("GetKey", mock.Anything, "missing").Return(nil, repository.ErrAPIKeyNotFound{ID: "missing"})
+
+	rejected := rejections{}
+	svc := NewSignatureService(keys, memoryNonces{}, time.Minute, rejected, zap.NewNop().Sugar()).(*signatureService)
+	svc.now = func() time.Time { return now }
+
+	signed := func(keyID, nonce string, at time.Time) SignedRequest {
+		r := SignedRequest{
+			KeyID:     keyID,
+			Timestamp: strconv.FormatInt(at.Unix(), 10),
+			Nonce:     nonce,
+			Method:    "POST",
+			Target:    "/api/portfolios?x=1",
+			Body:      []byte(`{"name":"Core"}`),
+		}
+		r.Signature = SignRequest("s3cret", r)
+		return r
+	}
+
+	key, err := svc.Verify(context.Background(), signed("signer", "n1", now))
+	require.NoError(t, err)
+	assert.Equal(t, "partner", key.Name)
+
+	tests := []struct {
+		name    string
+		request SignedRequest
+		wantErr error
+		reason  string
+	}{
+		{name: "replayed nonce", request: signed("signer", "n1", now), wantErr: ErrReplayedRequest, reason: RejectedReplay},
+		{name: "too old", request: signed("signer", "n2", now.Add(-2*time.Minute)), wantErr: ErrStaleRequest, reason: RejectedStale},
+		{name: "from the future", request: signed("signer", "n3", now.Add(2*time.Minute)), wantErr: ErrStaleRequest, reason: RejectedStale},
+		{name: "tampered body", request: func() SignedRequest {
+			r := signed("signer", "n4", now)
+			r.Body = []byte(`{"name":"Other"}`)
+			return r
+		}(), wantErr: ErrInvalidSignature, reason: RejectedInvalid},
+		{name: "key without a signing secret", request: signed("unsigned", "n5", now), wantErr: ErrInvalidSignature, reason: RejectedInvalid},
+		{name: "revoked key", request: signed("revoked", "n6", now), wantErr: ErrInvalidSignature, reason: RejectedInvalid},
+		{name: "unknown key", request: signed("missing", "n7", now), wantErr: ErrInvalidSignature, reason: RejectedInvalid},
+		{name: "missing nonce", request: signed("signer", "", now), wantErr: ErrInvalidSignature, reason: RejectedInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := rejected[tt.reason]
+			_, err := svc.Verify(context.Background(), tt.request)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, before+1, rejected[tt.reason])
+		})
+	}
+
+	t.Run("forged requests do not burn nonces", func(t *testing.T) {
+		forged := signed("signer", "n8", now)
+		forged.Signature = SignRequest("guess", forged)
+		_, err := svc.Verify(context.Background(), forged)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+
+		_, err = svc.Verify(context.Background(), signed("signer", "n8", now))
+		assert.NoError(t, err)
+	})
+
+	t.Run("nonce store failures fail the request", func(t *testing.T) {
+		svc := NewSignatureService(keys, failingNonces{}, time.Minute, nil, zap.NewNop().Sugar())
+		_, err := svc.Verify(context.Background(), signed("signer", "n9", time.Now()))
+		assert.ErrorContains(t, err, "throttled")
+	})
+}
+
+type failingNonces struct{}
+
+func (failingNonces) Remember(ctx context.Context, nonce string, expires time.Time) (bool, error) {
+	return false, errors.New("throttled")
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type SummaryRevisionService interface {
+	// GetRevisions returns the revisions of the daily bars of symbol in the
+	// range, by date and then by when they were made
+	GetRevisions(ctx context.Context, symbol string, from, to int64) ([]models.SummaryRevision, error)
+}
+
+type summaryRevisionService struct {
+	repo repository.SummaryRevisionRepository
+	log  *zap.SugaredLogger
+}
+
+// NewSummaryRevisionService returns the service reading the revisions of the
+// daily bars recorded in repo
+func NewSummaryRevisionService(repo repository.SummaryRevisionRepository, log *zap.SugaredLogger) SummaryRevisionService {
+	return &summaryRevisionService{
+		repo: repo,
+		log:  log,
+	}
+}
+
+// GetRevisions resolves the range as the daily bars do: it defaults to the
+// year up to now and is bounded by the plan's history
+func (s *summaryRevisionService) GetRevisions(ctx context.Context, symbol string, from, to int64) ([]models.SummaryRevision, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+	from, to, err := resolveRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	fromDate := time.Unix(from, 0).UTC().Format(models.DateLayout)
+	toDate := time.Unix(to, 0).UTC().Format(models.DateLayout)
+	revisions, err := s.repo.GetRevisions(ctx, symbol, fromDate, toDate)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get summary revisions", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get summary revisions: %w", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type MockSummaryRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSummaryRevisionRepository) Record(ctx context.Context, revisions []models.SummaryRevision) error {
+	args := m.Called(ctx, revisions)
+	return args.Error(0)
+}
+
+func (m *MockSummaryRevisionRepository) GetRevisions(ctx context.Context, symbol, from, to string) ([]models.SummaryRevision, error) {
+	args := m.Called(ctx, symbol, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.SummaryRevision), args.Error(1)
+}
+
+func TestSummaryRevisionService_GetRevisions(t *testing.T) {
+	revisions := []models.SummaryRevision{{Ticker: "AAPL", Date: "2025-03-07", Changed: []string{"close"}, Source: "backfill"}}
+	repo := new(MockSummaryRevisionRepository)
+	// The bounds are dates in UTC, whatever the time of day of the range
+	repo.On("GetRevisions", mock.Anything, "AAPL", "2025-03-03", "2025-03-07").Return(revisions, nil)
+
+	svc := NewSummaryRevisionService(repo, zap.NewNop().Sugar())
+	got, err := svc.GetRevisions(context.Background(), "AAPL", 1740996000, 1741388399)
+
+	require.NoError(t, err)
+	assert.Equal(t, revisions, got)
+	repo.AssertExpectations(t)
+}
+
+func TestSummaryRevisionService_GetRevisionsErrors(t *testing.T) {
+	repo := new(MockSummaryRevisionRepository)
+	svc := NewSummaryRevisionService(repo, zap.NewNop().Sugar())
+
+	_, err := svc.GetRevisions(context.Background(), "", 0, 0)
+	assert.ErrorIs(t, err, ErrInvalidTicker)
+
+	_, err = svc.GetRevisions(context.Background(), "AAPL", 1741388399, 1740996000)
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	repo.On("GetRevisions", mock.Anything, "AAPL", "2025-03-03", "2025-03-07").Return(nil, errors.New("throttled"))
+	_, err = svc.GetRevisions(context.Background(), "AAPL", 1740996000, 1741388399)
+	assert.ErrorContains(t, err, "throttled")
+}
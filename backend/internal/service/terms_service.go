@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrTermsNotConfigured rejects acceptances while no terms version is set
+	ErrTermsNotConfigured = errors.New("terms acceptance is not configured")
+	// ErrStaleTermsVersion rejects acceptances of any but the current version,
+	// so clients cannot accept terms they did not show
+	ErrStaleTermsVersion = errors.New("terms version is not the current version")
+	// ErrTermsAccountRequired rejects requests without an API key, which have
+	// no one to record an acceptance for
+	ErrTermsAccountRequired = errors.New("api key required to accept the terms")
+)
+
+// TermsStatus reports whether the caller accepted the current terms
+type TermsStatus struct {
+	// CurrentVersion is the version that must be accepted; empty when
+	// acceptance is not enforced
+	CurrentVersion string `json:"currentVersion"`
+	Accepted       bool   `json:"accepted"`
+	// AcceptedUTC is when the current version was accepted
+	AcceptedUTC int64 `json:"acceptedUTC,omitempty"`
+	// History lists every version the key's holder accepted, oldest first
+	History []models.TermsAcceptance `json:"history"`
+}
+
+type TermsService interface {
+	Status(ctx context.Context) (*TermsStatus, error)
+	Accept(ctx context.Context, version string) (*TermsStatus, error)
+}
+
+type termsService struct {
+	repo    repository.APIKeyRepository
+	version string
+	log     *zap.SugaredLogger
+}
+
+// NewTermsService records acceptances of the terms on the calling key. version
+// is the current version of the terms; empty disables enforcement.
+func NewTermsService(repo repository.APIKeyRepository, version string, log *zap.SugaredLogger) TermsService {
+	return &termsService{
+		repo:    repo,
+		version: version,
+		log:     log,
+	}
+}
+
+// Status reports the caller's acceptances against the current version
+func (s *termsService) Status(ctx context.Context) (*TermsStatus, error) {
+	key, ok := AccountFromContext(ctx)
+	if !ok {
+		return nil, ErrTermsAccountRequired
+	}
+	return s.status(key.Terms), nil
+}
+
+// Accept records that the caller accepted version, which must be the current
+// one. Accepting a version again keeps the first acceptance.
+func (s *termsService) Accept(ctx context.Context, version string) (*TermsStatus, error) {
+	key, ok := AccountFromContext(ctx)
+	if !ok {
+		return nil, ErrTermsAccountRequired
+	}
+	if s.version == "" {
+		return nil, ErrTermsNotConfigured
+	}
+	if version != s.version {
+		return nil, fmt.Errorf("%w: the current version is %q", ErrStaleTermsVersion, s.version)
+	}
+	if _, accepted := key.AcceptedTerms(version); accepted {
+		return s.status(key.Terms), nil
+	}
+
+	acceptance := models.TermsAcceptance{Version: version, AcceptedUTC: time.Now().Unix()}
+	if err := s.repo.AcceptTerms(ctx, key.ID, acceptance); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to record terms acceptance", "key", key.Name, "version", version, "error", err)
+		return nil, fmt.Errorf("failed to record terms acceptance: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("accepted terms", "key", key.Name, "version", version)
+	return s.status(append(slices.Clip(key.Terms), acceptance)), nil
+}
+
+func (s *termsService) status(history []models.TermsAcceptance) *TermsStatus {
+	status := &TermsStatus{
+		CurrentVersion: s.version,
+		Accepted:       s.version == "",
+		History:        history,
+	}
+	if status.History == nil {
+		status.History = []models.TermsAcceptance{}
+	}
+	for _, acceptance := range history {
+		if acceptance.Version == s.version {
+			status.Accepted = true
+			status.AcceptedUTC = acceptance.AcceptedUTC
+		}
+	}
+	return status
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTermsService_Accept(t *testing.T) {
+	repo := repository.NewMemoryAPIKeyRepository()
+	earlier := models.TermsAcceptance{Version: "2026-01", AcceptedUTC: 100}
+	require.NoError(t, repo.PutKey(context.Background(), &models.APIKey{ID: "k", Name: "app", Terms: []models.TermsAcceptance{earlier}}))
+	svc := NewTermsService(repo, "2026-06", zap.NewNop().Sugar())
+
+	account := func() context.Context {
+		key, err := repo.GetKey(context.Background(), "k")
+		require.NoError(t, err)
+		return WithAccount(context.Background(), key)
+	}
+
+	status, err := svc.Status(account())
+	require.NoError(t, err)
+	assert.Equal(t, "2026-06", status.CurrentVersion)
+	assert.False(t, status.Accepted, "only an earlier version was accepted")
+
+	_, err = svc.Accept(account(), "2026-01")
+	assert.ErrorIs(t, err, ErrStaleTermsVersion)
+
+	status, err = svc.Accept(account(), "2026-06")
+	require.NoError(t, err)
+	assert.True(t, status.Accepted)
+	assert.NotZero(t, status.AcceptedUTC)
+	require.Len(t, status.History, 2)
+	assert.Equal(t, earlier, status.History[0])
+
+	again, err := svc.Accept(account(), "2026-06")
+	require.NoError(t, err)
+	assert.Equal(t, status, again, "accepting again keeps the first acceptance")
+
+	key, _ := repo.GetKey(context.Background(), "k")
+	_, accepted := key.AcceptedTerms("2026-06")
+	assert.True(t, accepted)
+
+	_, err = svc.Accept(context.Background(), "2026-06")
+	assert.ErrorIs(t, err, ErrTermsAccountRequired)
+}
+
+func TestTermsService_NotConfigured(t *testing.T) {
+	svc := NewTermsService(repository.NewMemoryAPIKeyRepository(), "", zap.NewNop().Sugar())
+	ctx := WithAccount(context.Background(), &models.APIKey{ID: "k"})
+
+	status, err := svc.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, status.Accepted, "nothing to accept")
+	assert.Empty(t, status.History)
+
+	_, err = svc.Accept(ctx, "2026-06")
+	assert.ErrorIs(t, err, ErrTermsNotConfigured)
+}
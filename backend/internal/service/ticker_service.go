@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"profitify-backend/internal/models"
 	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -13,23 +17,37 @@ import (
 var (
 	ErrTickerNotFound = errors.New("ticker not found")
 	ErrInvalidTicker  = errors.New("invalid ticker symbol")
+	ErrTickerExists   = errors.New("ticker already exists")
+	// ErrInvalidTickerData is returned when a written ticker fails validation
+	ErrInvalidTickerData = errors.New("invalid ticker")
 )
 
 type TickerService interface {
 	GetTicker(ctx context.Context, symbol string) (*models.Ticker, error)
 	GetActiveTickers(ctx context.Context) ([]models.Ticker, error)
+	// GetTickersByExchange returns the active tickers listed on exchange
+	GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error)
+	// GetTickersByMarket returns the active tickers of market
+	GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error)
+	CreateTicker(ctx context.Context, ticker *models.Ticker) (*models.Ticker, error)
+	UpdateTicker(ctx context.Context, symbol string, ticker *models.Ticker) (*models.Ticker, error)
+	DeleteTicker(ctx context.Context, symbol string) error
 }
 
 type tickerService struct {
-	repo repository.TickerRepository
-	log  *zap.SugaredLogger
+	repo   repository.TickerRepository
+	events events.Publisher
+	log    *zap.SugaredLogger
 }
 
-func NewTickerService(repo repository.TickerRepository, log *zap.SugaredLogger) TickerService {
-	return &tickerService{
-		repo: repo,
-		log:  log,
-	}
+// NewTickerService publishes a TickerUpdated event for every ticker written
+// through it; a nil publisher publishes none. Each call is traced.
+func NewTickerService(repo repository.TickerRepository, publisher events.Publisher, log *zap.SugaredLogger) TickerService {
+	return tracedTickerService{next: &tickerService{
+		repo:   repo,
+		events: publisher,
+		log:    log,
+	}}
 }
 
 func (s *tickerService) GetTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
@@ -37,14 +55,14 @@ func (s *tickerService) GetTicker(ctx context.Context, symbol string) (*models.T
 		return nil, ErrInvalidTicker
 	}
 
-	s.log.Debugw("fetching ticker", "symbol", symbol)
+	logger.FromContext(ctx, s.log).Debugw("fetching ticker", "symbol", symbol)
 
 	ticker, err := s.repo.GetTicker(ctx, symbol)
 	if err != nil {
 		if errors.Is(err, repository.ErrTickerNotFound{Symbol: symbol}) {
 			return nil, ErrTickerNotFound
 		}
-		s.log.Errorw("failed to get ticker", "symbol", symbol, "error", err)
+		logger.FromContext(ctx, s.log).Errorw("failed to get ticker", "symbol", symbol, "error", err)
 		return nil, fmt.Errorf("failed to get ticker: %w", err)
 	}
 
@@ -52,11 +70,11 @@ func (s *tickerService) GetTicker(ctx context.Context, symbol string) (*models.T
 }
 
 func (s *tickerService) GetActiveTickers(ctx context.Context) ([]models.Ticker, error) {
-	s.log.Debug("fetching active tickers")
+	logger.FromContext(ctx, s.log).Debug("fetching active tickers")
 
 	tickers, err := s.repo.GetActiveTickers(ctx)
 	if err != nil {
-		s.log.Errorw("failed to get active tickers", "error", err)
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers", "error", err)
 		return nil, fmt.Errorf("failed to get active tickers: %w", err)
 	}
 
@@ -67,6 +85,118 @@ func (s *tickerService) GetActiveTickers(ctx context.Context) ([]models.Ticker,
 		}
 	}
 
-	s.log.Debugw("fetched active tickers", "total", len(tickers), "active", activeCount)
+	logger.FromContext(ctx, s.log).Debugw("fetched active tickers", "total", len(tickers), "active", activeCount)
+	return tickers, nil
+}
+
+func (s *tickerService) GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error) {
+	logger.FromContext(ctx, s.log).Debugw("fetching tickers by exchange", "exchange", exchange)
+
+	tickers, err := s.repo.GetTickersByExchange(ctx, exchange)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get tickers by exchange", "exchange", exchange, "error", err)
+		return nil, fmt.Errorf("failed to get tickers of exchange %s: %w", exchange, err)
+	}
+	return tickers, nil
+}
+
+func (s *tickerService) GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error) {
+	logger.FromContext(ctx, s.log).Debugw("fetching tickers by market", "market", market)
+
+	tickers, err := s.repo.GetTickersByMarket(ctx, market)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get tickers by market", "market", market, "error", err)
+		return nil, fmt.Errorf("failed to get tickers of market %s: %w", market, err)
+	}
 	return tickers, nil
 }
+
+// CreateTicker adds a ticker, failing if its symbol already exists
+func (s *tickerService) CreateTicker(ctx context.Context, ticker *models.Ticker) (*models.Ticker, error) {
+	created, err := prepareTicker(ticker)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PutTicker(ctx, created); err != nil {
+		if errors.Is(err, repository.ErrTickerExists{Symbol: created.Ticker}) {
+			return nil, ErrTickerExists
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to create ticker", "symbol", created.Ticker, "error", err)
+		return nil, fmt.Errorf("failed to create ticker: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created ticker", "symbol", created.Ticker)
+	s.publish(ctx, created.Ticker, models.TickerChangeCreated, created)
+	return created, nil
+}
+
+// UpdateTicker replaces the ticker of symbol; the symbol itself cannot change
+func (s *tickerService) UpdateTicker(ctx context.Context, symbol string, ticker *models.Ticker) (*models.Ticker, error) {
+	if symbol == "" {
+		return nil, ErrInvalidTicker
+	}
+
+	replacement := *ticker
+	replacement.Ticker = symbol
+	updated, err := prepareTicker(&replacement)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTicker(ctx, updated); err != nil {
+		if errors.Is(err, repository.ErrTickerNotFound{Symbol: updated.Ticker}) {
+			return nil, ErrTickerNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to update ticker", "symbol", updated.Ticker, "error", err)
+		return nil, fmt.Errorf("failed to update ticker: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("updated ticker", "symbol", updated.Ticker)
+	s.publish(ctx, updated.Ticker, models.TickerChangeUpdated, updated)
+	return updated, nil
+}
+
+// DeleteTicker removes the ticker of symbol. Its daily summaries are kept.
+func (s *tickerService) DeleteTicker(ctx context.Context, symbol string) error {
+	if symbol == "" {
+		return ErrInvalidTicker
+	}
+
+	if err := s.repo.DeleteTicker(ctx, symbol); err != nil {
+		if errors.Is(err, repository.ErrTickerNotFound{Symbol: symbol}) {
+			return ErrTickerNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to delete ticker", "symbol", symbol, "error", err)
+		return fmt.Errorf("failed to delete ticker: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("deleted ticker", "symbol", symbol)
+	s.publish(ctx, symbol, models.TickerChangeDeleted, nil)
+	return nil
+}
+
+// prepareTicker returns a validated copy of ticker with its symbol normalized
+// and its update time set to now
+func prepareTicker(ticker *models.Ticker) (*models.Ticker, error) {
+	prepared := *ticker
+	prepared.Ticker = strings.ToUpper(strings.TrimSpace(prepared.Ticker))
+	prepared.LastUpdatedUTC = time.Now().Unix()
+	if err := prepared.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTickerData, err)
+	}
+	return &prepared, nil
+}
+
+// publish reports a ticker's change; ticker is nil once it is deleted
+func (s *tickerService) publish(ctx context.Context, symbol, change string, ticker *models.Ticker) {
+	event := models.TickerUpdatedEvent{Symbol: symbol, Change: change}
+	if ticker != nil {
+		event.Name = ticker.Name
+		event.Market = ticker.Market
+		event.PrimaryExchange = ticker.PrimaryExchange
+		event.Type = ticker.Type
+		event.Active = ticker.Active == 1
+	}
+	PublishEvent(ctx, s.events, s.log, models.EventTickerUpdated, models.EventTickerUpdatedVersion, event)
+}
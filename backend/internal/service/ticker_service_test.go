@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTickerService_Writes(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockTickerRepository()
+	repo.SetTickers([]models.Ticker{{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", Active: 1}})
+	publisher := &eventstest.RecordingPublisher{}
+	svc := NewTickerService(repo, publisher, zap.NewNop().Sugar())
+
+	created, err := svc.CreateTicker(ctx, &models.Ticker{Ticker: " nvda ", Name: "NVIDIA Corp", Market: "stocks", Locale: "us", Active: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "NVDA", created.Ticker)
+	assert.NotZero(t, created.LastUpdatedUTC)
+
+	_, err = svc.CreateTicker(ctx, &models.Ticker{Ticker: "AAPL", Name: "Apple", Market: "stocks", Locale: "us"})
+	assert.ErrorIs(t, err, ErrTickerExists)
+
+	_, err = svc.CreateTicker(ctx, &models.Ticker{Ticker: "MSFT", Name: "Microsoft"})
+	assert.ErrorIs(t, err, ErrInvalidTickerData, "tickers are validated")
+	assert.Len(t, repo.Calls.PutTicker, 2, "invalid tickers are not written")
+
+	updated, err := svc.UpdateTicker(ctx, "AAPL", &models.Ticker{Ticker: "IGNORED", Name: "Apple", Market: "stocks", Locale: "us", Sector: "Technology"})
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", updated.Ticker, "the path symbol wins")
+	stored, err := repo.GetTicker(ctx, "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "Technology", stored.Sector)
+
+	_, err = svc.UpdateTicker(ctx, "MSFT", &models.Ticker{Name: "Microsoft", Market: "stocks", Locale: "us"})
+	assert.ErrorIs(t, err, ErrTickerNotFound)
+
+	require.NoError(t, svc.DeleteTicker(ctx, "AAPL"))
+	assert.ErrorIs(t, svc.DeleteTicker(ctx, "AAPL"), ErrTickerNotFound)
+	assert.ErrorIs(t, svc.DeleteTicker(ctx, ""), ErrInvalidTicker)
+
+	published := publisher.Events()
+	require.Len(t, published, 3, "only successful writes are published")
+	assert.Equal(t, models.EventTickerUpdated, published[0].Type)
+	assert.Equal(t, models.EventTickerUpdatedVersion, published[0].Version)
+	assert.Equal(t, models.TickerUpdatedEvent{
+		Symbol: "NVDA", Change: models.TickerChangeCreated, Name: "NVIDIA Corp", Market: "stocks", Active: true,
+	}, published[0].Data)
+	assert.Equal(t, models.TickerChangeUpdated, published[1].Data.(models.TickerUpdatedEvent).Change)
+	assert.Equal(t, models.TickerUpdatedEvent{Symbol: "AAPL", Change: models.TickerChangeDeleted}, published[2].Data)
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"profitify-backend/internal/models"
+	"profitify-backend/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// symbolAttribute is the span attribute naming the ticker a call is for
+const symbolAttribute = "profitify.symbol"
+
+// tracedTickerService traces each call of a TickerService as a span between
+// the request's and those of its DynamoDB calls
+type tracedTickerService struct {
+	next TickerService
+}
+
+func (s tracedTickerService) GetTicker(ctx context.Context, symbol string) (_ *models.Ticker, err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.GetTicker", attribute.String(symbolAttribute, symbol))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetTicker(ctx, symbol)
+}
+
+func (s tracedTickerService) GetActiveTickers(ctx context.Context) (_ []models.Ticker, err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.GetActiveTickers")
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetActiveTickers(ctx)
+}
+
+func (s tracedTickerService) GetTickersByExchange(ctx context.Context, exchange string) (_ []models.Ticker, err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.GetTickersByExchange", attribute.String("profitify.exchange", exchange))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetTickersByExchange(ctx, exchange)
+}
+
+func (s tracedTickerService) GetTickersByMarket(ctx context.Context, market string) (_ []models.Ticker, err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.GetTickersByMarket", attribute.String("profitify.market", market))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetTickersByMarket(ctx, market)
+}
+
+func (s tracedTickerService) CreateTicker(ctx context.Context, ticker *models.Ticker) (_ *models.Ticker, err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.CreateTicker", attribute.String(symbolAttribute, ticker.Ticker))
+	defer func() { tracing.End(span, err) }()
+	return s.next.CreateTicker(ctx, ticker)
+}
+
+func (s tracedTickerService) UpdateTicker(ctx context.Context, symbol string, ticker *models.Ticker) (_ *models.Ticker, err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.UpdateTicker", attribute.String(symbolAttribute, symbol))
+	defer func() { tracing.End(span, err) }()
+	return s.next.UpdateTicker(ctx, symbol, ticker)
+}
+
+func (s tracedTickerService) DeleteTicker(ctx context.Context, symbol string) (err error) {
+	ctx, span := tracing.Start(ctx, "TickerService.DeleteTicker", attribute.String(symbolAttribute, symbol))
+	defer func() { tracing.End(span, err) }()
+	return s.next.DeleteTicker(ctx, symbol)
+}
+
+// tracedDailySummaryService traces each call of a DailySummaryService
+type tracedDailySummaryService struct {
+	next DailySummaryService
+}
+
+func (s tracedDailySummaryService) GetDailySummaries(ctx context.Context, symbol string, from, to int64) (_ []models.DailySummary, err error) {
+	ctx, span := tracing.Start(ctx, "DailySummaryService.GetDailySummaries", attribute.String(symbolAttribute, symbol))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetDailySummaries(ctx, symbol, from, to)
+}
+
+func (s tracedDailySummaryService) EachDailySummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) (err error) {
+	ctx, span := tracing.Start(ctx, "DailySummaryService.EachDailySummary", attribute.String(symbolAttribute, symbol))
+	defer func() { tracing.End(span, err) }()
+	return s.next.EachDailySummary(ctx, symbol, from, to, fn)
+}
+
+func (s tracedDailySummaryService) GetQuote(ctx context.Context, symbol string) (_ *models.Quote, err error) {
+	ctx, span := tracing.Start(ctx, "DailySummaryService.GetQuote", attribute.String(symbolAttribute, symbol))
+	defer func() { tracing.End(span, err) }()
+	return s.next.GetQuote(ctx, symbol)
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidUserToken rejects user tokens that are malformed, badly signed or
+// expired, or whose user or API key is gone
+var ErrInvalidUserToken = errors.New("invalid user token")
+
+type userContextKey struct{}
+
+// WithUser returns ctx carrying the ID of the user the request was
+// authenticated as
+func WithUser(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userContextKey{}, id)
+}
+
+// UserFromContext returns the user ID stored by WithUser, if any
+func UserFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(userContextKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,72 @@
+// Package sessions serves the sessions API keys open on clients such as
+// mobile apps, which then authenticate with a revocable session token instead
+// of the key.
+package sessions
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	sessionService Service
+	log            *zap.SugaredLogger
+}
+
+func NewHandler(sessions Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		sessionService: sessions,
+		log:            log,
+	}
+}
+
+// Wire builds the sessions module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	repo := NewRepository(deps.DB, deps.Config.SessionsTable)
+	return NewHandler(NewService(repo, deps.APIKeyRepository(), deps.Config.SessionTTL, deps.Events, deps.Log), deps.Log)
+}
+
+// Sessions returns the service resolving session tokens, which authenticates
+// requests
+func (h *Handler) Sessions() Service {
+	return h.sessionService
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	sessions := api.Group("/account/sessions", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms())
+	sessions.GET("", h.ListSessions)
+	sessions.POST("", h.OpenSession)
+	sessions.DELETE("/:id", h.RevokeSession)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Account"}
+
+	doc.Add(http.MethodGet, "/api/v1/account/sessions", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "List the active sessions of the calling key",
+		Description: "Most recently seen first, with the client each session was last used from. `current` marks the session the request was made with.",
+		Responses:   api.Responses(http.StatusOK, api.List(doc, "sessions", Session{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/account/sessions", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Open a session for the calling key",
+		Description: "The token is only returned in this response. Clients send it as `Authorization: Bearer <token>` " +
+			"instead of X-API-Key; a session expires SESSION_TTL after its last use, when it is revoked, or when its key is revoked.",
+		RequestBody: openapi.JSONBody(doc.Inline(openSessionRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(IssuedSession{}), http.StatusBadRequest, http.StatusUnauthorized),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/account/sessions/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Revoke a session, logging its client out",
+		Parameters: []openapi.Parameter{openapi.PathParam("id", "Session ID")},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+}
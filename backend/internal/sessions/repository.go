@@ -0,0 +1,208 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for session data operations
+type Repository interface {
+	GetSession(ctx context.Context, id string) (*Session, error)
+	ListSessions(ctx context.Context, keyID string) ([]Session, error)
+	PutSession(ctx context.Context, session *Session) error
+	// TouchSession records a use of an existing session and moves its expiry
+	TouchSession(ctx context.Context, id string, seen Sighting) error
+	DeleteSession(ctx context.Context, id string) error
+}
+
+// Sighting is a use of a session, recorded as its last-seen metadata
+type Sighting struct {
+	SeenUTC    int64
+	IP         string
+	UserAgent  string
+	ExpiresUTC int64
+}
+
+// sessionRepository implements Repository using a DynamoDB table keyed on
+// "id", whose "ttl" attribute lets DynamoDB delete expired sessions
+type sessionRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed session repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &sessionRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetSession retrieves a single session by ID
+func (r *sessionRepository) GetSession(ctx context.Context, id string) (*Session, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+
+	var session Session
+	if err := attributevalue.UnmarshalMap(result.Item, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+
+	return &session, nil
+}
+
+// ListSessions retrieves the sessions of an API key, including expired ones
+// DynamoDB has not deleted yet
+func (r *sessionRepository) ListSessions(ctx context.Context, keyID string) ([]Session, error) {
+	expr, err := expression.NewBuilder().
+		WithFilter(expression.Name("keyId").Equal(expression.Value(keyID))).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	var sessions []Session
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName:                 aws.String(r.tableName),
+			FilterExpression:          expr.Filter(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sessions: %w", err)
+		}
+
+		var batch []Session
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sessions: %w", err)
+		}
+
+		sessions = append(sessions, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return sessions, nil
+}
+
+// PutSession creates a session, failing if its ID is taken
+func (r *sessionRepository) PutSession(ctx context.Context, session *Session) error {
+	item, err := attributevalue.MarshalMap(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeNotExists(expression.Name("id"))).
+		Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put session %s: %w", session.ID, err)
+	}
+
+	return nil
+}
+
+// TouchSession updates the last-seen metadata and expiry of an existing session
+func (r *sessionRepository) TouchSession(ctx context.Context, id string, seen Sighting) error {
+	update := expression.Set(expression.Name("lastSeenUTC"), expression.Value(seen.SeenUTC)).
+		Set(expression.Name("ttl"), expression.Value(seen.ExpiresUTC))
+	if seen.IP != "" {
+		update = update.Set(expression.Name("lastSeenIP"), expression.Value(seen.IP))
+	}
+	if seen.UserAgent != "" {
+		update = update.Set(expression.Name("userAgent"), expression.Value(seen.UserAgent))
+	}
+	cond := expression.AttributeExists(expression.Name("id"))
+
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		return fmt.Errorf("failed to touch session %s: %w", id, err)
+	}
+
+	return nil
+}
+
+// DeleteSession deletes a session, failing if it does not exist
+func (r *sessionRepository) DeleteSession(ctx context.Context, id string) error {
+	cond := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+		}
+		return fmt.Errorf("failed to delete session %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,239 @@
+package sessions
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+	ErrInvalidName     = errors.New("invalid session name")
+	// ErrAPIKeyRequired rejects opening a session without an API key, which
+	// happens when API keys are not required
+	ErrAPIKeyRequired = errors.New("sessions are opened with an API key")
+)
+
+// lastSeenResolution limits how often a session's last-seen metadata is
+// written while its client does not change
+const lastSeenResolution = time.Minute
+
+type Service interface {
+	Open(ctx context.Context, name string, client service.SessionClient) (*IssuedSession, error)
+	AuthenticateSession(ctx context.Context, token string, client service.SessionClient) (*models.APIKey, string, error)
+	ListSessions(ctx context.Context) ([]Session, error)
+	Revoke(ctx context.Context, id string) error
+}
+
+type sessionService struct {
+	repo   Repository
+	keys   repository.APIKeyRepository
+	ttl    time.Duration
+	events events.Publisher
+	log    *zap.SugaredLogger
+	now    func() time.Time
+}
+
+// NewService opens sessions for API keys. A session expires ttl after it was
+// last used, and with the key it was opened with. Opening one publishes a
+// LoggedIn event.
+func NewService(repo Repository, keys repository.APIKeyRepository, ttl time.Duration, publisher events.Publisher, log *zap.SugaredLogger) Service {
+	return &sessionService{
+		repo:   repo,
+		keys:   keys,
+		ttl:    ttl,
+		events: publisher,
+		log:    log,
+		now:    time.Now,
+	}
+}
+
+// Open issues a session token for the calling API key. The token is only
+// returned here.
+func (s *sessionService) Open(ctx context.Context, name string, client service.SessionClient) (*IssuedSession, error) {
+	key, ok := service.AccountFromContext(ctx)
+	if !ok {
+		return nil, ErrAPIKeyRequired
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session token: %w", err)
+	}
+
+	now := s.now()
+	session := Session{
+		ID:          sessionID(token),
+		Name:        strings.TrimSpace(name),
+		CreatedUTC:  now.Unix(),
+		LastSeenUTC: now.Unix(),
+		LastSeenIP:  client.IP,
+		UserAgent:   truncate(client.UserAgent, maxUserAgentLength),
+		ExpiresUTC:  now.Add(s.ttl).Unix(),
+		KeyID:       key.ID,
+	}
+	if err := session.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidName, err)
+	}
+
+	if err := s.repo.PutSession(ctx, &session); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to open session", "key", key.Name, "error", err)
+		return nil, fmt.Errorf("failed to open session: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("opened session", "key", key.Name, "session", session.ID, "name", session.Name)
+	service.PublishEvent(ctx, s.events, s.log, models.EventLoggedIn, models.EventLoggedInVersion, models.LoggedInEvent{
+		KeyID:       key.ID,
+		Method:      models.LoginMethodSession,
+		SessionID:   session.ID,
+		SessionName: session.Name,
+		LoggedInUTC: session.CreatedUTC,
+	})
+	return &IssuedSession{Session: session, Token: token}, nil
+}
+
+// AuthenticateSession resolves a session token to the unrevoked key it was
+// opened with and the session's ID, and records the use on the session.
+// Unknown and expired sessions, and sessions of revoked keys, are rejected
+// with service.ErrInvalidSession.
+func (s *sessionService) AuthenticateSession(ctx context.Context, token string, client service.SessionClient) (*models.APIKey, string, error) {
+	if token == "" {
+		return nil, "", service.ErrInvalidSession
+	}
+
+	session, err := s.repo.GetSession(ctx, sessionID(token))
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return nil, "", service.ErrInvalidSession
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to look up session", "error", err)
+		return nil, "", fmt.Errorf("failed to look up session: %w", err)
+	}
+
+	now := s.now()
+	if session.Expired(now.Unix()) {
+		return nil, "", service.ErrInvalidSession
+	}
+
+	key, err := s.keys.GetKey(ctx, session.KeyID)
+	if err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return nil, "", service.ErrInvalidSession
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to look up api key", "error", err)
+		return nil, "", fmt.Errorf("failed to look up api key: %w", err)
+	}
+	if key.Disabled() {
+		return nil, "", service.ErrInvalidSession
+	}
+
+	userAgent := truncate(client.UserAgent, maxUserAgentLength)
+	moved := client.IP != session.LastSeenIP || userAgent != session.UserAgent
+	if moved || now.Sub(time.Unix(session.LastSeenUTC, 0)) >= lastSeenResolution {
+		// Recording the use is best effort and must not fail the request
+		err := s.repo.TouchSession(ctx, session.ID, Sighting{
+			SeenUTC:    now.Unix(),
+			IP:         client.IP,
+			UserAgent:  userAgent,
+			ExpiresUTC: now.Add(s.ttl).Unix(),
+		})
+		if err != nil {
+			logger.FromContext(ctx, s.log).Warnw("failed to record session use", "session", session.ID, "error", err)
+		}
+	}
+
+	return key, session.ID, nil
+}
+
+// ListSessions returns the unexpired sessions of the calling key, most
+// recently seen first, marking the one the request was made with
+func (s *sessionService) ListSessions(ctx context.Context) ([]Session, error) {
+	all, err := s.repo.ListSessions(ctx, service.CallerKeyID(ctx))
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list sessions", "error", err)
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	now := s.now().Unix()
+	current, _ := service.SessionFromContext(ctx)
+	sessions := make([]Session, 0, len(all))
+	for _, session := range all {
+		if session.Expired(now) {
+			continue
+		}
+		session.Current = session.ID == current
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenUTC > sessions[j].LastSeenUTC
+	})
+	return sessions, nil
+}
+
+// Revoke deletes a session of the calling key, logging its client out. Other
+// keys' sessions are reported missing so that their IDs cannot be probed.
+func (s *sessionService) Revoke(ctx context.Context, id string) error {
+	if id == "" {
+		return ErrSessionNotFound
+	}
+
+	session, err := s.repo.GetSession(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get session", "session", id, "error", err)
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.KeyID != service.CallerKeyID(ctx) {
+		return ErrSessionNotFound
+	}
+
+	if err := s.repo.DeleteSession(ctx, id); err != nil {
+		if errors.Is(err, ErrSessionNotFound) {
+			return ErrSessionNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to revoke session", "session", id, "error", err)
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("revoked session", "session", id)
+	return nil
+}
+
+// generateToken returns 32 random bytes hex encoded
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// sessionID derives a session's ID from its token without exposing it
+func sessionID(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
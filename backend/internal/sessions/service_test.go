@@ -0,0 +1,207 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events/eventstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRepository is a Repository over a map that counts touches
+type memoryRepository struct {
+	sessions map[string]Session
+	touches  int
+}
+
+func (m *memoryRepository) GetSession(ctx context.Context, id string) (*Session, error) {
+	session, ok := m.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	return &session, nil
+}
+
+func (m *memoryRepository) ListSessions(ctx context.Context, keyID string) ([]Session, error) {
+	var sessions []Session
+	for _, session := range m.sessions {
+		if session.KeyID == keyID {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (m *memoryRepository) PutSession(ctx context.Context, session *Session) error {
+	if _, ok := m.sessions[session.ID]; ok {
+		return errors.New("session exists")
+	}
+	m.sessions[session.ID] = *session
+	return nil
+}
+
+func (m *memoryRepository) TouchSession(ctx context.Context, id string, seen Sighting) error {
+	session, ok := m.sessions[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	m.touches++
+	session.LastSeenUTC = seen.SeenUTC
+	session.LastSeenIP = seen.IP
+	session.UserAgent = seen.UserAgent
+	session.ExpiresUTC = seen.ExpiresUTC
+	m.sessions[id] = session
+	return nil
+}
+
+func (m *memoryRepository) DeleteSession(ctx context.Context, id string) error {
+	if _, ok := m.sessions[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrSessionNotFound, id)
+	}
+	delete(m.sessions, id)
+	return nil
+}
+
+func newTestService(t *testing.T) (*sessionService, *memoryRepository, repository.APIKeyRepository, *time.Time) {
+	t.Helper()
+
+	keys := repository.NewMemoryAPIKeyRepository()
+	for _, key := range []models.APIKey{{ID: "alice", Name: "alice"}, {ID: "bob", Name: "bob"}} {
+		require.NoError(t, keys.PutKey(context.Background(), &key))
+	}
+
+	repo := &memoryRepository{sessions: make(map[string]Session)}
+	now := time.Unix(1_700_000_000, 0)
+	svc := NewService(repo, keys, time.Hour, nil, zap.NewNop().Sugar()).(*sessionService)
+	svc.now = func() time.Time { return now }
+	return svc, repo, keys, &now
+}
+
+func keyContext(id string) context.Context {
+	return service.WithAccount(context.Background(), &models.APIKey{ID: id, Name: id})
+}
+
+func TestService_OpenAndAuthenticate(t *testing.T) {
+	svc, repo, keys, now := newTestService(t)
+	publisher := &eventstest.RecordingPublisher{}
+	svc.events = publisher
+	phone := service.SessionClient{IP: "203.0.113.7", UserAgent: "Profitify/2.1 (iOS)"}
+
+	issued, err := svc.Open(keyContext("alice"), "  iPhone  ", phone)
+	require.NoError(t, err)
+	assert.Equal(t, "iPhone", issued.Name)
+	assert.Len(t, issued.Token, 64)
+	assert.NotContains(t, issued.ID, issued.Token, "the ID does not reveal the token")
+	assert.Equal(t, now.Add(time.Hour).Unix(), issued.ExpiresUTC)
+
+	require.Len(t, publisher.Events(), 1)
+	assert.Equal(t, models.LoggedInEvent{
+		KeyID:       "alice",
+		Method:      models.LoginMethodSession,
+		SessionID:   issued.ID,
+		SessionName: "iPhone",
+		LoggedInUTC: now.Unix(),
+	}, publisher.Events()[0].Data)
+
+	key, id, err := svc.AuthenticateSession(context.Background(), issued.Token, phone)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", key.ID)
+	assert.Equal(t, issued.ID, id)
+	assert.Zero(t, repo.touches, "uses within a minute from the same client are not written")
+
+	t.Run("uses move the expiry and record the client", func(t *testing.T) {
+		*now = now.Add(50 * time.Minute)
+		_, _, err := svc.AuthenticateSession(context.Background(), issued.Token, service.SessionClient{IP: "198.51.100.1", UserAgent: phone.UserAgent})
+		require.NoError(t, err)
+
+		stored := repo.sessions[issued.ID]
+		assert.Equal(t, "198.51.100.1", stored.LastSeenIP)
+		assert.Equal(t, now.Unix(), stored.LastSeenUTC)
+		assert.Equal(t, now.Add(time.Hour).Unix(), stored.ExpiresUTC)
+	})
+
+	t.Run("expired sessions are rejected", func(t *testing.T) {
+		*now = now.Add(2 * time.Hour)
+		_, _, err := svc.AuthenticateSession(context.Background(), issued.Token, phone)
+		assert.ErrorIs(t, err, service.ErrInvalidSession)
+	})
+
+	t.Run("sessions of revoked keys are rejected", func(t *testing.T) {
+		issued, err := svc.Open(keyContext("bob"), "laptop", phone)
+		require.NoError(t, err)
+		require.NoError(t, keys.RevokeKey(context.Background(), "bob", now.Unix()))
+
+		_, _, err = svc.AuthenticateSession(context.Background(), issued.Token, phone)
+		assert.ErrorIs(t, err, service.ErrInvalidSession)
+	})
+
+	t.Run("unknown tokens are rejected", func(t *testing.T) {
+		_, _, err := svc.AuthenticateSession(context.Background(), "guess", phone)
+		assert.ErrorIs(t, err, service.ErrInvalidSession)
+		_, _, err = svc.AuthenticateSession(context.Background(), "", phone)
+		assert.ErrorIs(t, err, service.ErrInvalidSession)
+	})
+}
+
+func TestService_Open(t *testing.T) {
+	svc, _, _, _ := newTestService(t)
+
+	_, err := svc.Open(context.Background(), "phone", service.SessionClient{})
+	assert.ErrorIs(t, err, ErrAPIKeyRequired)
+
+	_, err = svc.Open(keyContext("alice"), strings.Repeat("x", 101), service.SessionClient{})
+	assert.ErrorIs(t, err, ErrInvalidName)
+
+	issued, err := svc.Open(keyContext("alice"), "", service.SessionClient{UserAgent: strings.Repeat("a", 1000)})
+	require.NoError(t, err)
+	assert.Len(t, issued.UserAgent, maxUserAgentLength)
+}
+
+func TestService_ListAndRevoke(t *testing.T) {
+	svc, _, _, now := newTestService(t)
+	alice := keyContext("alice")
+
+	phone, err := svc.Open(alice, "phone", service.SessionClient{})
+	require.NoError(t, err)
+	*now = now.Add(time.Minute)
+	laptop, err := svc.Open(alice, "laptop", service.SessionClient{})
+	require.NoError(t, err)
+	bobs, err := svc.Open(keyContext("bob"), "tablet", service.SessionClient{})
+	require.NoError(t, err)
+
+	sessions, err := svc.ListSessions(service.WithSession(alice, phone.ID))
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, laptop.ID, sessions[0].ID, "most recently seen first")
+	assert.False(t, sessions[0].Current)
+	assert.True(t, sessions[1].Current)
+
+	assert.ErrorIs(t, svc.Revoke(alice, bobs.ID), ErrSessionNotFound, "other keys' sessions cannot be revoked")
+	assert.ErrorIs(t, svc.Revoke(alice, "missing"), ErrSessionNotFound)
+
+	require.NoError(t, svc.Revoke(alice, phone.ID))
+	_, _, err = svc.AuthenticateSession(context.Background(), phone.Token, service.SessionClient{})
+	assert.ErrorIs(t, err, service.ErrInvalidSession, "revoked sessions log their client out")
+
+	sessions, err = svc.ListSessions(alice)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, laptop.ID, sessions[0].ID)
+
+	t.Run("expired sessions are not listed", func(t *testing.T) {
+		*now = now.Add(2 * time.Hour)
+		sessions, err := svc.ListSessions(alice)
+		require.NoError(t, err)
+		assert.Empty(t, sessions)
+	})
+}
@@ -0,0 +1,53 @@
+package sessions
+
+import (
+	"fmt"
+)
+
+// Session is a login of an API key on one client, such as a mobile app,
+// which authenticates with the session's token instead of the key. Only a
+// hash of the token is stored; it doubles as the session's ID.
+type Session struct {
+	ID string `json:"id" dynamodbav:"id"`
+	// Name labels the client, e.g. "Pixel 8"
+	Name        string `json:"name,omitempty" dynamodbav:"name,omitempty"`
+	CreatedUTC  int64  `json:"createdUTC" dynamodbav:"createdUTC"`
+	LastSeenUTC int64  `json:"lastSeenUTC" dynamodbav:"lastSeenUTC"`
+	LastSeenIP  string `json:"lastSeenIP,omitempty" dynamodbav:"lastSeenIP,omitempty"`
+	UserAgent   string `json:"userAgent,omitempty" dynamodbav:"userAgent,omitempty"`
+	// ExpiresUTC moves forward with each use of the session; DynamoDB deletes
+	// the session after it through the ttl attribute
+	ExpiresUTC int64 `json:"expiresUTC" dynamodbav:"ttl"`
+	// KeyID is the API key the session authenticates as
+	KeyID string `json:"-" dynamodbav:"keyId"`
+	// Current marks the session the request listing it was made with
+	Current bool `json:"current" dynamodbav:"-"`
+}
+
+// IssuedSession is a newly opened session together with its token, which is
+// only ever returned once
+type IssuedSession struct {
+	Session
+	Token string `json:"token"`
+}
+
+// maxUserAgentLength bounds the user agents recorded on sessions
+const maxUserAgentLength = 256
+
+// Validate checks if the session data is valid
+func (s *Session) Validate() error {
+	if s.ID == "" {
+		return fmt.Errorf("id is required")
+	}
+
+	if len(s.Name) > 100 {
+		return fmt.Errorf("session name must be at most 100 characters")
+	}
+
+	return nil
+}
+
+// Expired reports whether the session expired by now, in Unix seconds
+func (s *Session) Expired(now int64) bool {
+	return s.ExpiresUTC <= now
+}
@@ -0,0 +1,72 @@
+package sessions
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type openSessionRequest struct {
+	// Name labels the client the session is opened on, e.g. "Pixel 8"
+	Name string `json:"name,omitempty"`
+}
+
+func (h *Handler) ListSessions(c *gin.Context) {
+	sessions, err := h.sessionService.ListSessions(c.Request.Context())
+	if err != nil {
+		h.respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sessions": sessions,
+		"count":    len(sessions),
+	})
+}
+
+func (h *Handler) OpenSession(c *gin.Context) {
+	var req openSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	session, err := h.sessionService.Open(c.Request.Context(), req.Name, service.SessionClient{
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	})
+	if err != nil {
+		h.respondSessionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+func (h *Handler) RevokeSession(c *gin.Context) {
+	if err := h.sessionService.Revoke(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondSessionError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondSessionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrSessionNotFound):
+		problem.Respond(c, problem.SessionNotFound, "Session not found")
+	case errors.Is(err, ErrInvalidName):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, ErrAPIKeyRequired):
+		problem.Respond(c, problem.Unauthenticated, "API key required to open a session")
+	default:
+		api.Logger(c, h.log).Errorw("session request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process session request")
+	}
+}
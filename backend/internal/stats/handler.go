@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTickerStats responds with a ticker's stats as of its latest session
+func (h *Handler) GetTickerStats(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	stats, err := h.statsService.Get(c.Request.Context(), symbol)
+	if err != nil {
+		h.respondStatsError(c, symbol, "failed to get ticker stats", "Failed to get ticker stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// RecomputeTickerStats rebuilds one ticker's stats on demand, e.g. after a
+// correction of its daily bars, and responds with them
+func (h *Handler) RecomputeTickerStats(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	stats, err := h.statsService.Recompute(c.Request.Context(), symbol)
+	if err != nil {
+		h.respondStatsError(c, symbol, "failed to recompute ticker stats", "Failed to recompute ticker stats", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// respondStatsError maps an error of the stats service to a problem, logging
+// unexpected ones with message
+func (h *Handler) respondStatsError(c *gin.Context, symbol, message, detail string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTicker):
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+	case errors.Is(err, service.ErrTickerNotFound):
+		problem.Respond(c, problem.TickerNotFound, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw(message, "symbol", symbol, "error", err)
+		problem.Respond(c, problem.Internal, detail)
+	}
+}
@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	statsService Service
+	log          *zap.SugaredLogger
+}
+
+func NewHandler(stats Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		statsService: stats,
+		log:          log,
+	}
+}
+
+// Wire builds the stats module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	return NewHandler(NewService(
+		deps.TickerRepository(),
+		deps.DailySummaryRepository(),
+		repository.NewTickerStatsRepository(deps.DB, deps.Config.TickerStatsTable),
+		deps.Config.StatsBenchmark,
+		deps.Log,
+	), deps.Log)
+}
+
+// Stats returns the service the module serves ticker stats from
+func (h *Handler) Stats() Service {
+	return h.statsService
+}
+
+// PostCloseJobs returns the job refreshing every active ticker's stats with
+// the day's session
+func (h *Handler) PostCloseJobs() []jobs.Job {
+	return []jobs.Job{
+		jobs.NewJob("ticker-stats", func(ctx context.Context, date time.Time) error {
+			_, err := h.statsService.Refresh(ctx, date)
+			return err
+		}),
+	}
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.GET("/tickers/:symbol/stats", middleware.RequireScope(models.ScopeReadMarket), h.GetTickerStats)
+
+	admin.POST("/tickers/:symbol/recompute", h.RecomputeTickerStats)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/stats", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "Get a ticker's summary statistics",
+		Description: "The 52-week high and low, 50- and 200-session SMAs, 30- and 90-session average volumes, year-to-date return, " +
+			"annualized 30-session volatility of daily log returns and beta against STATS_BENCHMARK, as of the ticker's latest " +
+			"session. They are read from the stats the ticker-stats post-close job materializes, or computed from the daily bars " +
+			"when the job has not yet included the latest session. Statistics needing more sessions than the ticker has are omitted.",
+		Parameters: []openapi.Parameter{openapi.PathParam("symbol", "Ticker symbol, case insensitive")},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.TickerStats{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/tickers/:symbol/recompute", &openapi.Operation{
+		Tags:    []string{"Admin"},
+		Summary: "Recompute a ticker's derived stats",
+		Description: "Rebuilds the stats GET /api/v1/tickers/{symbol}/stats serves from the ticker's " +
+			"daily bars up to now, as the ticker-stats post-close job does for every active ticker, so a correction of the bars " +
+			"shows without waiting for the next close.",
+		Parameters: []openapi.Parameter{openapi.PathParam("symbol", "Ticker symbol, case insensitive")},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.TickerStats{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,171 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	// Get returns the stats of one ticker as of its latest session
+	Get(ctx context.Context, symbol string) (*models.TickerStats, error)
+	// Recompute rebuilds and stores the stats of one ticker from its bars up to now
+	Recompute(ctx context.Context, symbol string) (*models.TickerStats, error)
+	// Refresh rebuilds the stats of every active ticker as of date
+	Refresh(ctx context.Context, date time.Time) (int, error)
+}
+
+type statsService struct {
+	tickers   repository.TickerRepository
+	summaries repository.DailySummaryRepository
+	stats     repository.TickerStatsRepository
+	benchmark string
+	log       *zap.SugaredLogger
+}
+
+// NewService returns the service materializing ticker stats, with betas
+// measured against the benchmark ticker; an empty benchmark leaves them unset
+func NewService(
+	tickers repository.TickerRepository,
+	summaries repository.DailySummaryRepository,
+	stats repository.TickerStatsRepository,
+	benchmark string,
+	log *zap.SugaredLogger,
+) Service {
+	return &statsService{
+		tickers:   tickers,
+		summaries: summaries,
+		stats:     stats,
+		benchmark: benchmark,
+		log:       log,
+	}
+}
+
+// Get returns the stats of symbol as of its latest session: the materialized
+// ones when they include it, else stats computed from its bars on the spot
+// and stored for the next read, as after a day's close before the
+// ticker-stats job has run
+func (s *statsService) Get(ctx context.Context, symbol string) (*models.TickerStats, error) {
+	if symbol == "" {
+		return nil, service.ErrInvalidTicker
+	}
+
+	stored, err := s.stats.GetStats(ctx, symbol)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get ticker stats", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to get ticker stats: %w", err)
+	}
+	to := time.Now().Unix()
+	latest, err := s.summaries.GetLatestSummaries(ctx, symbol, to, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the latest daily summary of %s: %w", symbol, err)
+	}
+	if len(latest) == 0 {
+		return nil, fmt.Errorf("%w: no daily summaries for %s", service.ErrTickerNotFound, symbol)
+	}
+	if stored != nil && stored.AsOf == latest[0].Date() {
+		return stored, nil
+	}
+
+	benchmark, err := s.history(ctx, s.benchmark, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.compute(ctx, symbol, to, benchmark)
+}
+
+// Recompute rebuilds the stats of symbol right away, e.g. after its bars were
+// corrected, rather than waiting for the next refresh
+func (s *statsService) Recompute(ctx context.Context, symbol string) (*models.TickerStats, error) {
+	if symbol == "" {
+		return nil, service.ErrInvalidTicker
+	}
+
+	to := time.Now().Unix()
+	benchmark, err := s.history(ctx, s.benchmark, to)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.compute(ctx, symbol, to, benchmark)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Infow("ticker stats recomputed", "symbol", symbol, "asOf", stats.AsOf)
+	return stats, nil
+}
+
+// Refresh rebuilds the stats of every active ticker from their bars up to the
+// session on date. Tickers that fail or have no bars are logged and skipped,
+// unless ctx is done. It returns how many tickers' stats were stored.
+func (s *statsService) Refresh(ctx context.Context, date time.Time) (int, error) {
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers for stats", "error", err)
+		return 0, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+
+	to := service.StartOfDay(date).AddDate(0, 0, 1).Unix() - 1
+	benchmark, err := s.history(ctx, s.benchmark, to)
+	if err != nil {
+		return 0, err
+	}
+
+	stored := 0
+	for _, ticker := range tickers {
+		if _, err := s.compute(ctx, ticker.Ticker, to, benchmark); err != nil {
+			if ctx.Err() != nil {
+				return stored, ctx.Err()
+			}
+			logger.FromContext(ctx, s.log).Warnw("failed to refresh ticker stats", "symbol", ticker.Ticker, "error", err)
+			continue
+		}
+		stored++
+	}
+
+	logger.FromContext(ctx, s.log).Infow("ticker stats refreshed", "date", date.Format(models.DateLayout), "tickers", len(tickers), "stored", stored)
+	return stored, nil
+}
+
+// compute stores the stats of symbol from its bars up to to
+func (s *statsService) compute(ctx context.Context, symbol string, to int64, benchmark []models.DailySummary) (*models.TickerStats, error) {
+	bars, err := s.history(ctx, symbol, to)
+	if err != nil {
+		return nil, err
+	}
+	if len(bars) == 0 {
+		return nil, fmt.Errorf("%w: no daily summaries for %s", service.ErrTickerNotFound, symbol)
+	}
+
+	stats := Compute(bars, benchmark)
+	if stats.Beta != nil {
+		stats.Benchmark = s.benchmark
+	}
+	stats.ComputedUTC = time.Now().Unix()
+	if err := s.stats.PutStats(ctx, &stats); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to store ticker stats", "symbol", symbol, "error", err)
+		return nil, fmt.Errorf("failed to store ticker stats: %w", err)
+	}
+	return &stats, nil
+}
+
+// history reads the bars of symbol over the historyDays up to to; an empty
+// symbol has none
+func (s *statsService) history(ctx context.Context, symbol string, to int64) ([]models.DailySummary, error) {
+	if symbol == "" {
+		return nil, nil
+	}
+	from := time.Unix(to, 0).UTC().AddDate(0, 0, -historyDays).Unix()
+	bars, err := s.summaries.GetSummaries(ctx, symbol, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily summaries of %s: %w", symbol, err)
+	}
+	return bars, nil
+}
@@ -0,0 +1,155 @@
+// Package stats materializes statistics derived from each ticker's daily bars
+// (52-week range, moving averages, average volumes, year-to-date return,
+// volatility, beta), serves them and recomputes them on demand.
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"profitify-backend/internal/models"
+)
+
+const (
+	// yearDays is the 52 weeks the range and beta are measured over
+	yearDays = 52 * 7
+	// historyDays is the history read to compute stats, enough for the
+	// longest moving average
+	historyDays = 366
+	// minBetaReturns is the fewest daily returns in common with the benchmark
+	// a beta is estimated from
+	minBetaReturns = 60
+	// volatilityReturns is the daily returns volatility is measured over
+	volatilityReturns = 30
+)
+
+// Compute derives the stats of bars, oldest first and not empty, as of their
+// latest session. Beta is measured against benchmark, the benchmark's bars
+// over the same history; without them it is left unset.
+func Compute(bars, benchmark []models.DailySummary) models.TickerStats {
+	latest := bars[len(bars)-1]
+	start := time.Unix(latest.Timestamp, 0).UTC().AddDate(0, 0, -yearDays).Unix()
+	first := sort.Search(len(bars), func(i int) bool { return bars[i].Timestamp > start })
+	year := bars[first:]
+
+	stats := models.TickerStats{
+		Ticker:       latest.Ticker,
+		AsOf:         latest.Date(),
+		High52Week:   latest.High,
+		Low52Week:    latest.Low,
+		Sessions:     len(year),
+		SMA50:        sma(bars, 50),
+		SMA200:       sma(bars, 200),
+		AvgVolume30:  averageVolume(bars, 30),
+		AvgVolume90:  averageVolume(bars, 90),
+		YTDReturn:    ytdReturn(bars),
+		Volatility30: volatility(bars, volatilityReturns),
+		Beta:         beta(year, benchmark),
+	}
+	for _, bar := range year {
+		stats.High52Week = max(stats.High52Week, bar.High)
+		stats.Low52Week = min(stats.Low52Week, bar.Low)
+	}
+	return stats
+}
+
+// sma averages the last period closes of bars, or is nil with fewer bars
+func sma(bars []models.DailySummary, period int) *float64 {
+	if len(bars) < period {
+		return nil
+	}
+	sum := 0.0
+	for _, bar := range bars[len(bars)-period:] {
+		sum += float64(bar.Close)
+	}
+	mean := sum / float64(period)
+	return &mean
+}
+
+// averageVolume averages the volume of the last period bars, or is nil with
+// fewer bars
+func averageVolume(bars []models.DailySummary, period int) *float64 {
+	if len(bars) < period {
+		return nil
+	}
+	sum := 0.0
+	for _, bar := range bars[len(bars)-period:] {
+		sum += float64(bar.Volume)
+	}
+	mean := sum / float64(period)
+	return &mean
+}
+
+// ytdReturn is the change from the close of the last bar of the previous
+// calendar year to the latest close, or nil when bars start this year
+func ytdReturn(bars []models.DailySummary) *float64 {
+	latest := bars[len(bars)-1]
+	year := time.Date(time.Unix(latest.Timestamp, 0).UTC().Year(), time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	first := sort.Search(len(bars), func(i int) bool { return bars[i].Timestamp >= year })
+	if first == 0 || bars[first-1].Close <= 0 {
+		return nil
+	}
+	r := float64(latest.Close)/float64(bars[first-1].Close) - 1
+	return &r
+}
+
+// volatility is the annualized volatility of the last returns daily returns
+// of bars, as the returns endpoint measures it, or nil with fewer
+func volatility(bars []models.DailySummary, returns int) *float64 {
+	if len(bars) < returns+1 {
+		return nil
+	}
+	_, summary := models.ComputeReturns(bars[len(bars)-returns-1:], models.ReturnLog)
+	if summary == nil {
+		return nil
+	}
+	return summary.Volatility
+}
+
+// beta is the covariance of the daily returns of bars and benchmark over
+// their sessions in common, divided by the variance of the benchmark's. It is
+// nil with fewer than minBetaReturns returns or a flat benchmark.
+func beta(bars, benchmark []models.DailySummary) *float64 {
+	closes := make(map[int64]float32, len(benchmark))
+	for _, bar := range benchmark {
+		closes[bar.Timestamp] = bar.Close
+	}
+
+	var returns, benchmarkReturns []float64
+	var previous, previousBenchmark float32
+	for _, bar := range bars {
+		benchmarkClose, ok := closes[bar.Timestamp]
+		if !ok {
+			continue
+		}
+		if previous > 0 && previousBenchmark > 0 {
+			returns = append(returns, float64(bar.Close)/float64(previous)-1)
+			benchmarkReturns = append(benchmarkReturns, float64(benchmarkClose)/float64(previousBenchmark)-1)
+		}
+		previous, previousBenchmark = bar.Close, benchmarkClose
+	}
+	if len(returns) < minBetaReturns {
+		return nil
+	}
+
+	mean, benchmarkMean := average(returns), average(benchmarkReturns)
+	var covariance, variance float64
+	for i := range returns {
+		covariance += (returns[i] - mean) * (benchmarkReturns[i] - benchmarkMean)
+		variance += (benchmarkReturns[i] - benchmarkMean) * (benchmarkReturns[i] - benchmarkMean)
+	}
+	if variance < math.SmallestNonzeroFloat64 {
+		return nil
+	}
+	b := covariance / variance
+	return &b
+}
+
+func average(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
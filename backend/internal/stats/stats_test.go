@@ -0,0 +1,167 @@
+package stats
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// sessions returns a bar of symbol on each of the days before end, oldest
+// first, closing at close(n) on the nth
+func sessions(symbol string, end time.Time, days int, close func(n int) float32) []models.DailySummary {
+	bars := make([]models.DailySummary, days)
+	for n := range bars {
+		c := close(n)
+		bars[n] = models.DailySummary{
+			Ticker: symbol, Timestamp: end.AddDate(0, 0, n-days+1).Unix(), Open: c, High: c + 1, Low: c - 1, Close: c,
+		}
+	}
+	return bars
+}
+
+func TestCompute(t *testing.T) {
+	end := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	// Alternating benchmark returns, with the ticker moving twice as far
+	benchmark := sessions("SPY", end, 400, func(n int) float32 { return 100 + float32(n%2) })
+	bars := sessions("AAPL", end, 400, func(n int) float32 { return 100 + 2*float32(n%2) })
+	// A high outside the 52 weeks does not count
+	bars[0].High = 500
+	bars[len(bars)-10].Low = 50
+
+	stats := Compute(bars, benchmark)
+
+	assert.Equal(t, "AAPL", stats.Ticker)
+	assert.Equal(t, "2025-03-07", stats.AsOf)
+	assert.Equal(t, 364, stats.Sessions)
+	assert.Equal(t, float32(103), stats.High52Week)
+	assert.Equal(t, float32(50), stats.Low52Week)
+	require.NotNil(t, stats.SMA50)
+	assert.InDelta(t, 101, *stats.SMA50, 1e-9)
+	require.NotNil(t, stats.SMA200)
+	assert.InDelta(t, 101, *stats.SMA200, 1e-9)
+	require.NotNil(t, stats.Beta)
+	assert.InDelta(t, 2, *stats.Beta, 0.05)
+
+	short := Compute(bars[len(bars)-30:], benchmark)
+	assert.Nil(t, short.SMA50, "too few sessions for the average")
+	assert.Nil(t, short.Beta, "too few returns for a beta")
+	assert.Nil(t, Compute(bars, nil).Beta)
+}
+
+func TestCompute_VolumeReturnAndVolatility(t *testing.T) {
+	end := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	// Closes alternate between 100 and 110, ending on 100 on 2025-03-07
+	bars := sessions("AAPL", end, 120, func(n int) float32 { return 100 + 10*float32((n+1)%2) })
+	for n := range bars {
+		bars[n].Volume = float32(n + 1)
+	}
+	lastOf2024 := &bars[len(bars)-1-66]
+	require.Equal(t, "2024-12-31", lastOf2024.Date())
+	lastOf2024.Close = 80
+
+	stats := Compute(bars, nil)
+
+	require.NotNil(t, stats.AvgVolume30)
+	assert.InDelta(t, 105.5, *stats.AvgVolume30, 1e-9, "the last 30 sessions trade 91 to 120")
+	require.NotNil(t, stats.AvgVolume90)
+	assert.InDelta(t, 75.5, *stats.AvgVolume90, 1e-9)
+	require.NotNil(t, stats.YTDReturn)
+	assert.InDelta(t, 0.25, *stats.YTDReturn, 1e-6)
+	require.NotNil(t, stats.Volatility30)
+	// Log returns alternate between +-ln(1.1), whose sample deviation over 30
+	// returns is ln(1.1)*sqrt(30/29)
+	assert.InDelta(t, math.Log(1.1)*math.Sqrt(30.0/29)*math.Sqrt(models.TradingDaysPerYear), *stats.Volatility30, 1e-4)
+
+	listed := Compute(bars[len(bars)-20:], nil)
+	assert.Nil(t, listed.AvgVolume30, "too few sessions for the average")
+	assert.Nil(t, listed.YTDReturn, "no close before the year")
+	assert.Nil(t, listed.Volatility30, "too few returns for the volatility")
+}
+
+// memoryStats keeps stats in a map
+type memoryStats map[string]models.TickerStats
+
+func (m memoryStats) PutStats(ctx context.Context, stats *models.TickerStats) error {
+	m[stats.Ticker] = *stats
+	return nil
+}
+
+func (m memoryStats) GetStats(ctx context.Context, symbol string) (*models.TickerStats, error) {
+	if stats, ok := m[symbol]; ok {
+		return &stats, nil
+	}
+	return nil, nil
+}
+
+func TestService_RecomputeAndRefresh(t *testing.T) {
+	end := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	bars := sessions("AAPL", end, 120, func(n int) float32 { return 100 + float32(n) })
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).Return(bars, nil)
+	summaries.On("GetSummaries", mock.Anything, "SPY", mock.Anything, mock.Anything).Return(bars, nil)
+	summaries.On("GetSummaries", mock.Anything, "GONE", mock.Anything, mock.Anything).Return([]models.DailySummary{}, nil)
+	tickers := repository.NewMemoryTickerRepository([]models.Ticker{{Ticker: "AAPL", Active: 1}, {Ticker: "GONE", Active: 1}})
+	stored := memoryStats{}
+	svc := NewService(tickers, summaries, stored, "SPY", zap.NewNop().Sugar())
+
+	stats, err := svc.Recompute(context.Background(), "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-07", stats.AsOf)
+	assert.Equal(t, "SPY", stats.Benchmark)
+	assert.NotZero(t, stats.ComputedUTC)
+	assert.Equal(t, *stats, stored["AAPL"])
+
+	_, err = svc.Recompute(context.Background(), "GONE")
+	assert.ErrorIs(t, err, service.ErrTickerNotFound)
+	_, err = svc.Recompute(context.Background(), "")
+	assert.ErrorIs(t, err, service.ErrInvalidTicker)
+
+	delete(stored, "AAPL")
+	n, err := svc.Refresh(context.Background(), end)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "tickers without bars are skipped")
+	assert.Contains(t, stored, "AAPL")
+	summaries.AssertCalled(t, "GetSummaries", mock.Anything, "AAPL", end.AddDate(0, 0, 1-historyDays).Unix()-1, end.AddDate(0, 0, 1).Unix()-1)
+}
+
+func TestService_Get(t *testing.T) {
+	end := time.Date(2025, 3, 7, 0, 0, 0, 0, time.UTC)
+	bars := sessions("AAPL", end, 120, func(n int) float32 { return 100 + float32(n) })
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(1)).Return(bars[len(bars)-1:], nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "GONE", mock.Anything, int32(1)).Return([]models.DailySummary{}, nil)
+	summaries.On("GetSummaries", mock.Anything, "AAPL", mock.Anything, mock.Anything).Return(bars, nil)
+	stored := memoryStats{}
+	svc := NewService(repository.NewMemoryTickerRepository(nil), summaries, stored, "", zap.NewNop().Sugar())
+
+	// Stats the job computed before the latest session are computed anew
+	stored["AAPL"] = models.TickerStats{Ticker: "AAPL", AsOf: "2025-03-06", ComputedUTC: 1}
+	stats, err := svc.Get(context.Background(), "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, "2025-03-07", stats.AsOf)
+	assert.NotNil(t, stats.SMA50)
+	assert.Equal(t, *stats, stored["AAPL"], "computed stats are stored for the next read")
+	summaries.AssertNumberOfCalls(t, "GetSummaries", 1)
+
+	// Stats including the latest session are served as stored
+	stored["AAPL"] = models.TickerStats{Ticker: "AAPL", AsOf: "2025-03-07", ComputedUTC: 1}
+	stats, err = svc.Get(context.Background(), "AAPL")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.ComputedUTC)
+	summaries.AssertNumberOfCalls(t, "GetSummaries", 1)
+
+	_, err = svc.Get(context.Background(), "GONE")
+	assert.ErrorIs(t, err, service.ErrTickerNotFound)
+	_, err = svc.Get(context.Background(), "")
+	assert.ErrorIs(t, err, service.ErrInvalidTicker)
+}
@@ -0,0 +1,139 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/stats"
+	"profitify-backend/pkg/dynamostreams"
+	"profitify-backend/pkg/events"
+
+	"go.uber.org/zap"
+)
+
+// barResolution is the Resolution of the BarClosed events of daily summaries
+const barResolution = "day"
+
+// TickerInvalidator drops cached tickers, as the cached TickerRepository does
+type TickerInvalidator interface {
+	Invalidate(ctx context.Context, symbols ...string) error
+}
+
+// InvalidateTickers drops the changed tickers from the cache, including the
+// writes of other replicas and of tools writing to the table directly
+func InvalidateTickers(cache TickerInvalidator) dynamostreams.Handler {
+	return dynamostreams.HandlerFunc(func(ctx context.Context, records []dynamostreams.Record) error {
+		symbols := symbolsOf(records, func(dynamostreams.Record) bool { return true })
+		if len(symbols) == 0 {
+			return nil
+		}
+		return cache.Invalidate(ctx, symbols...)
+	})
+}
+
+// RecomputeStats rebuilds the stats of the tickers whose stored daily
+// summaries were corrected or removed. Newly stored sessions need nothing:
+// their stats are computed on the first read and by the post-close job.
+func RecomputeStats(svc stats.Service, log *zap.SugaredLogger) dynamostreams.Handler {
+	return dynamostreams.HandlerFunc(func(ctx context.Context, records []dynamostreams.Record) error {
+		symbols := symbolsOf(records, func(r dynamostreams.Record) bool {
+			switch r.EventName {
+			case dynamostreams.EventRemove:
+				return true
+			case dynamostreams.EventModify:
+				_, changed := barChange(r)
+				return changed
+			default:
+				return false
+			}
+		})
+
+		var errs []error
+		for _, symbol := range symbols {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			// A ticker whose bars were all removed has no stats to recompute
+			if _, err := svc.Recompute(ctx, symbol); err != nil && !errors.Is(err, service.ErrTickerNotFound) {
+				errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+			}
+		}
+		if len(errs) > 0 {
+			log.Warnw("failed to recompute the stats of corrected tickers", "failed", len(errs), "tickers", len(symbols))
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// PublishBars publishes a BarClosed event of the day for each daily summary
+// stored or corrected, for streaming consumers such as WebSocket gateways
+func PublishBars(publisher events.Publisher) dynamostreams.Handler {
+	return dynamostreams.HandlerFunc(func(ctx context.Context, records []dynamostreams.Record) error {
+		var published []events.Event
+		for _, r := range records {
+			bar, changed := barChange(r)
+			if !changed || r.EventName == dynamostreams.EventRemove {
+				continue
+			}
+			published = append(published, events.New(models.EventBarClosed, models.EventBarClosedVersion, models.BarClosedEvent{
+				Symbol:     bar.Ticker,
+				Resolution: barResolution,
+				AggregateBar: models.AggregateBar{
+					Timestamp: bar.Timestamp,
+					Date:      bar.Date(),
+					Open:      bar.Open,
+					High:      bar.High,
+					Low:       bar.Low,
+					Close:     bar.Close,
+					Volume:    float64(bar.Volume),
+					Sessions:  1,
+				},
+			}))
+		}
+		if len(published) == 0 {
+			return nil
+		}
+		return publisher.Publish(ctx, published...)
+	})
+}
+
+// barChange decodes the daily summary a record leaves stored, or removes,
+// and reports whether its bar changed. Writes that only restamp a summary,
+// such as a re-ingest of the same values, change nothing.
+func barChange(r dynamostreams.Record) (models.DailySummary, bool) {
+	var after, before models.DailySummary
+	image := r.Change.NewImage
+	if r.EventName == dynamostreams.EventRemove {
+		image = r.Change.OldImage
+	}
+	if image == nil || image.Unmarshal(&after) != nil {
+		return after, false
+	}
+	if r.EventName != dynamostreams.EventModify || r.Change.OldImage == nil {
+		return after, true
+	}
+	if r.Change.OldImage.Unmarshal(&before) != nil {
+		return after, true
+	}
+	return after, before.Open != after.Open || before.High != after.High || before.Low != after.Low ||
+		before.Close != after.Close || before.Volume != after.Volume
+}
+
+// symbolsOf returns the distinct tickers keyed by the records matching, in
+// the order first seen
+func symbolsOf(records []dynamostreams.Record, match func(dynamostreams.Record) bool) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, r := range records {
+		symbol := r.Change.Keys.String("ticker")
+		if symbol == "" || seen[symbol] || !match(r) {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
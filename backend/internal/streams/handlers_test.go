@@ -0,0 +1,146 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/stats"
+	"profitify-backend/pkg/dynamostreams"
+	"profitify-backend/pkg/events"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type invalidations [][]string
+
+func (i *invalidations) Invalidate(ctx context.Context, symbols ...string) error {
+	*i = append(*i, symbols)
+	return nil
+}
+
+// recomputingStats records the tickers recomputed; other methods are not used
+type recomputingStats struct {
+	stats.Service
+	recomputed []string
+	missing    string
+}
+
+func (s *recomputingStats) Recompute(ctx context.Context, symbol string) (*models.TickerStats, error) {
+	s.recomputed = append(s.recomputed, symbol)
+	if symbol == s.missing {
+		return nil, service.ErrTickerNotFound
+	}
+	if symbol == "FAIL" {
+		return nil, errors.New("throttled")
+	}
+	return &models.TickerStats{Ticker: symbol}, nil
+}
+
+type publishedEvents []events.Event
+
+func (p *publishedEvents) Publish(ctx context.Context, published ...events.Event) error {
+	*p = append(*p, published...)
+	return nil
+}
+
+var session = time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC).Unix()
+
+// image is the streamed item of a daily summary of symbol
+func image(symbol string, close, volume string) dynamostreams.Item {
+	return dynamostreams.Item{
+		"ticker":     {S: aws.String(symbol)},
+		"timestamp":  {N: aws.String("1740960000")},
+		"open":       {N: aws.String("100")},
+		"high":       {N: aws.String("110")},
+		"low":        {N: aws.String("95")},
+		"close":      {N: aws.String(close)},
+		"volume":     {N: aws.String(volume)},
+		"updatedUTC": {N: aws.String("1741000000")},
+	}
+}
+
+func change(event, symbol string, before, after dynamostreams.Item) dynamostreams.Record {
+	return dynamostreams.Record{EventName: event, Change: dynamostreams.Change{
+		Keys:     dynamostreams.Item{"ticker": {S: aws.String(symbol)}, "timestamp": {N: aws.String("1740960000")}},
+		OldImage: before,
+		NewImage: after,
+	}}
+}
+
+func restamped(item dynamostreams.Item) dynamostreams.Item {
+	copied := dynamostreams.Item{}
+	for name, v := range item {
+		copied[name] = v
+	}
+	copied["updatedUTC"] = dynamostreams.AttributeValue{N: aws.String("1741100000")}
+	return copied
+}
+
+var summaryRecords = []dynamostreams.Record{
+	change(dynamostreams.EventInsert, "NEW", nil, image("NEW", "105", "1000")),
+	change(dynamostreams.EventModify, "AAPL", image("AAPL", "105", "1000"), image("AAPL", "107", "1000")),
+	change(dynamostreams.EventModify, "AAPL", image("AAPL", "107", "1000"), image("AAPL", "107", "1200")),
+	change(dynamostreams.EventModify, "SAME", image("SAME", "105", "1000"), restamped(image("SAME", "105", "1000"))),
+	change(dynamostreams.EventRemove, "GONE", image("GONE", "105", "1000"), nil),
+	change(dynamostreams.EventRemove, "FAIL", image("FAIL", "105", "1000"), nil),
+}
+
+func TestInvalidateTickers(t *testing.T) {
+	var cache invalidations
+	handler := InvalidateTickers(&cache)
+	records := []dynamostreams.Record{
+		change(dynamostreams.EventModify, "AAPL", nil, nil),
+		change(dynamostreams.EventInsert, "MSFT", nil, nil),
+		change(dynamostreams.EventRemove, "AAPL", nil, nil),
+	}
+	require.NoError(t, handler.HandleRecords(context.Background(), records))
+	assert.Equal(t, invalidations{{"AAPL", "MSFT"}}, cache)
+
+	require.NoError(t, handler.HandleRecords(context.Background(), nil))
+	assert.Len(t, cache, 1, "nothing to drop")
+}
+
+func TestRecomputeStats(t *testing.T) {
+	svc := &recomputingStats{missing: "GONE"}
+	err := RecomputeStats(svc, zap.NewNop().Sugar()).HandleRecords(context.Background(), summaryRecords)
+
+	assert.Equal(t, []string{"AAPL", "GONE", "FAIL"}, svc.recomputed,
+		"corrected and removed bars are recomputed once per ticker; new and restamped ones are not")
+	assert.ErrorContains(t, err, "FAIL: throttled")
+	assert.NotContains(t, err.Error(), "GONE", "tickers left without bars have no stats")
+}
+
+func TestPublishBars(t *testing.T) {
+	var published publishedEvents
+	require.NoError(t, PublishBars(&published).HandleRecords(context.Background(), summaryRecords))
+
+	require.Len(t, published, 3, "restamped and removed bars are not published")
+	symbols := make([]string, len(published))
+	for i, e := range published {
+		assert.Equal(t, models.EventBarClosed, e.Type)
+		symbols[i] = e.Data.(models.BarClosedEvent).Symbol
+	}
+	assert.Equal(t, []string{"NEW", "AAPL", "AAPL"}, symbols)
+
+	assert.Equal(t, models.BarClosedEvent{
+		Symbol:     "AAPL",
+		Resolution: "day",
+		AggregateBar: models.AggregateBar{
+			Timestamp: session,
+			Date:      "2025-03-03",
+			Open:      100,
+			High:      110,
+			Low:       95,
+			Close:     107,
+			Volume:    1200,
+			Sessions:  1,
+		},
+	}, published[2].Data)
+}
@@ -0,0 +1,93 @@
+// Package streams consumes the DynamoDB Streams of the tickers and daily
+// summaries tables, keeping the state derived from them consistent as they
+// change: cached tickers, ticker stats and the bars streamed to clients.
+package streams
+
+import (
+	"context"
+	"fmt"
+
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/stats"
+	"profitify-backend/pkg/dynamostreams"
+)
+
+// Consumers hold the consumers of the streams: the ones every replica runs
+// for its own cache, and the ones the leader runs to update shared state once
+type Consumers struct {
+	replica []*dynamostreams.Consumer
+	leader  []*dynamostreams.Consumer
+}
+
+// Wire builds the consumers of the streams read through api, looking up the
+// ARNs of the streams not configured from their tables. Stats of corrected
+// tickers are recomputed through statsService.
+func Wire(ctx context.Context, deps app.Deps, api dynamostreams.API, statsService stats.Service) (*Consumers, error) {
+	cfg := deps.Config
+	summariesARN, err := streamARN(ctx, deps, cfg.DailySummaryStreamARN, cfg.DailySummaryTable)
+	if err != nil {
+		return nil, err
+	}
+
+	consumers := &Consumers{}
+	// Every replica drops the tickers changed from its cache, as a cache in
+	// process memory is private to each
+	if cached, ok := deps.TickerRepository().(repository.CachedTickerRepository); ok {
+		tickersARN, err := streamARN(ctx, deps, cfg.TickersStreamARN, cfg.TickersTable)
+		if err != nil {
+			return nil, err
+		}
+		consumers.replica = append(consumers.replica,
+			dynamostreams.NewConsumer(api, "tickers", tickersARN, cfg.StreamsPollInterval, deps.Log).
+				Register("ticker-cache", InvalidateTickers(cached)))
+	}
+
+	summaries := dynamostreams.NewConsumer(api, "daily-summaries", summariesARN, cfg.StreamsPollInterval, deps.Log).
+		Register("ticker-stats", RecomputeStats(statsService, deps.Log))
+	if cfg.StreamsFanout && deps.Events != nil {
+		summaries.Register("bar-fanout", PublishBars(deps.Events))
+	}
+	consumers.leader = append(consumers.leader, summaries)
+	return consumers, nil
+}
+
+// RunReplica runs the consumers every replica runs until ctx is done; it
+// returns at once without a ticker cache to keep
+func (c *Consumers) RunReplica(ctx context.Context) error {
+	return run(ctx, c.replica)
+}
+
+// RunLeader runs the consumers of the leader until ctx is done, e.g. when
+// leadership is lost
+func (c *Consumers) RunLeader(ctx context.Context) error {
+	return run(ctx, c.leader)
+}
+
+func run(ctx context.Context, consumers []*dynamostreams.Consumer) error {
+	done := make(chan error, len(consumers))
+	for _, consumer := range consumers {
+		go func() {
+			done <- consumer.Run(ctx)
+		}()
+	}
+	var first error
+	for range consumers {
+		if err := <-done; err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// streamARN returns configured, else the ARN of the latest stream of table
+func streamARN(ctx context.Context, deps app.Deps, configured, table string) (string, error) {
+	if configured != "" {
+		return configured, nil
+	}
+	arn, err := repository.StreamARN(ctx, deps.DB, table)
+	if err != nil {
+		return "", fmt.Errorf("failed to find the stream of %s: %w", table, err)
+	}
+	return arn, nil
+}
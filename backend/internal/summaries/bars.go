@@ -0,0 +1,47 @@
+package summaries
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryPlanHeader reports the sources a bar query was answered from
+const QueryPlanHeader = "X-Query-Plan"
+
+// GetTickerBars resamples a ticker's daily bars into weekly or monthly bars, so
+// charts of long ranges need not download every session
+func (h *Handler) GetTickerBars(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	resolution := models.Resolution(strings.ToLower(c.DefaultQuery("resolution", string(models.ResolutionWeek))))
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	bars, plan, err := h.barService.GetBars(c.Request.Context(), symbol, resolution, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidResolution) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		h.respondDailySummaryError(c, symbol, err)
+		return
+	}
+
+	c.Header(QueryPlanHeader, plan.String())
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":     symbol,
+		"resolution": resolution,
+		"bars":       bars,
+		"count":      len(bars),
+	})
+}
@@ -0,0 +1,86 @@
+package summaries
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ingestCorporateActionsRequest struct {
+	Splits    []models.Split    `json:"splits"`
+	Dividends []models.Dividend `json:"dividends"`
+}
+
+func (h *Handler) GetTickerSplits(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	splits, err := h.corporateActionService.GetSplits(c.Request.Context(), symbol)
+	if err != nil {
+		h.respondCorporateActionError(c, symbol, err)
+		return
+	}
+
+	if splits == nil {
+		splits = []models.Split{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"ticker": symbol,
+		"splits": splits,
+		"count":  len(splits),
+	})
+}
+
+func (h *Handler) GetTickerDividends(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	dividends, err := h.corporateActionService.GetDividends(c.Request.Context(), symbol)
+	if err != nil {
+		h.respondCorporateActionError(c, symbol, err)
+		return
+	}
+
+	if dividends == nil {
+		dividends = []models.Dividend{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":    symbol,
+		"dividends": dividends,
+		"count":     len(dividends),
+	})
+}
+
+func (h *Handler) IngestCorporateActions(c *gin.Context) {
+	var req ingestCorporateActionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	count, err := h.corporateActionService.IngestActions(c.Request.Context(), req.Splits, req.Dividends)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCorporateAction) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		api.Logger(c, h.log).Errorw("failed to ingest corporate actions", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to ingest corporate actions")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ingested": count,
+	})
+}
+
+func (h *Handler) respondCorporateActionError(c *gin.Context, symbol string, err error) {
+	if errors.Is(err, service.ErrInvalidTicker) {
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+		return
+	}
+	api.Logger(c, h.log).Errorw("failed to get corporate actions", "symbol", symbol, "error", err)
+	problem.Respond(c, problem.Internal, "Failed to retrieve corporate actions")
+}
@@ -0,0 +1,156 @@
+package summaries
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockCorporateActionService mocks the CorporateActionService interface
+type MockCorporateActionService struct {
+	mock.Mock
+}
+
+func (m *MockCorporateActionService) IngestActions(ctx context.Context, splits []models.Split, dividends []models.Dividend) (int, error) {
+	args := m.Called(ctx, splits, dividends)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockCorporateActionService) GetSplits(ctx context.Context, symbol string) ([]models.Split, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Split), args.Error(1)
+}
+
+func (m *MockCorporateActionService) GetDividends(ctx context.Context, symbol string) ([]models.Dividend, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Dividend), args.Error(1)
+}
+
+func (m *MockCorporateActionService) Adjustments(ctx context.Context, symbol string, since int64) (*models.Adjustments, error) {
+	args := m.Called(ctx, symbol, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Adjustments), args.Error(1)
+}
+
+func newCorporateActionRouter(summaries *MockDailySummaryService, actions *MockCorporateActionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewHandler(summaries, nil, nil, actions, nil, api.ResponseLimits{}, clock.System, zap.NewNop().Sugar())
+	h.RegisterRoutes(r.Group("/api"), r.Group("/api/admin"))
+	return r
+}
+
+func serveRequest(r *gin.Engine, method, target, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return w
+}
+
+func TestHandler_GetTickerCorporateActions(t *testing.T) {
+	actions := new(MockCorporateActionService)
+	actions.On("GetSplits", mock.Anything, "AAPL").Return([]models.Split{
+		{Ticker: "AAPL", Timestamp: 1598832000, SplitFrom: 1, SplitTo: 4},
+	}, nil)
+	actions.On("GetDividends", mock.Anything, "AAPL").Return(nil, nil)
+	actions.On("GetSplits", mock.Anything, "MSFT").Return(nil, errors.New("throttled"))
+	r := newCorporateActionRouter(new(MockDailySummaryService), actions)
+
+	w := serveRequest(r, http.MethodGet, "/api/tickers/aapl/splits", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var splits struct {
+		Ticker string         `json:"ticker"`
+		Splits []models.Split `json:"splits"`
+		Count  int            `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &splits))
+	assert.Equal(t, "AAPL", splits.Ticker)
+	assert.Equal(t, 1, splits.Count)
+	assert.Equal(t, float64(4), splits.Splits[0].SplitTo)
+
+	w = serveRequest(r, http.MethodGet, "/api/tickers/aapl/dividends", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"ticker":"AAPL","dividends":[],"count":0}`, w.Body.String())
+
+	assert.Equal(t, http.StatusInternalServerError, serveRequest(r, http.MethodGet, "/api/tickers/msft/splits", "").Code)
+}
+
+func TestHandler_IngestCorporateActions(t *testing.T) {
+	actions := new(MockCorporateActionService)
+	actions.On("IngestActions", mock.Anything, []models.Split{{Ticker: "AAPL", Timestamp: 1598832000, SplitFrom: 1, SplitTo: 4}},
+		[]models.Dividend(nil)).Return(1, nil).Once()
+	actions.On("IngestActions", mock.Anything, []models.Split(nil), []models.Dividend(nil)).
+		Return(0, service.ErrInvalidCorporateAction).Once()
+	r := newCorporateActionRouter(new(MockDailySummaryService), actions)
+
+	w := serveRequest(r, http.MethodPost, "/api/admin/corporate-actions",
+		`{"splits":[{"ticker":"AAPL","timestamp":1598832000,"splitFrom":1,"splitTo":4}]}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"ingested":1}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serveRequest(r, http.MethodPost, "/api/admin/corporate-actions", `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, serveRequest(r, http.MethodPost, "/api/admin/corporate-actions", `{`).Code)
+	actions.AssertExpectations(t)
+}
+
+func TestHandler_GetDailySummariesAdjusted(t *testing.T) {
+	bars := []models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704153600, Close: 400, Volume: 100},
+		{Ticker: "AAPL", Timestamp: 1704240000, Close: 100, Volume: 400},
+	}
+	summaries := new(MockDailySummaryService)
+	summaries.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704153600), int64(1704326399)).
+		Return(append([]models.DailySummary(nil), bars...), nil)
+	summaries.On("EachDailySummary", mock.Anything, "AAPL", int64(1704153600), int64(1704326399)).Return(bars, nil)
+	actions := new(MockCorporateActionService)
+	actions.On("Adjustments", mock.Anything, "AAPL", int64(1704153600)).Return(models.NewAdjustments([]models.Adjustment{
+		models.SplitAdjustment(models.Split{Timestamp: 1704240000, SplitFrom: 1, SplitTo: 4}),
+	}), nil)
+	r := newCorporateActionRouter(summaries, actions)
+
+	w := serveRequest(r, http.MethodGet, "/api/tickers/aapl/daily?from=2024-01-02&to=2024-01-03&adjusted=true", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Bars     []models.DailySummary `json:"bars"`
+		Adjusted bool                  `json:"adjusted"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.True(t, body.Adjusted)
+	require.Len(t, body.Bars, 2)
+	assert.Equal(t, float32(100), body.Bars[0].Close, "bars before the split are restated")
+	assert.Equal(t, float32(400), body.Bars[0].Volume)
+	assert.Equal(t, bars[1], body.Bars[1])
+	assert.Empty(t, w.Header().Get("Last-Modified"), "adjusted bars change without being rewritten")
+
+	w = serveRequest(r, http.MethodGet, "/api/tickers/aapl/daily?from=2024-01-02&to=2024-01-03&adjusted=1&format=csv", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "2024-01-02,AAPL,0,0,0,100,400,,", lines[1])
+
+	w = serveRequest(r, http.MethodGet, "/api/tickers/aapl/daily?adjusted=maybe", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	actions.AssertNumberOfCalls(t, "Adjustments", 2)
+}
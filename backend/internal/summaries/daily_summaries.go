@@ -0,0 +1,218 @@
+package summaries
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetDailySummaries lists a ticker's daily bars in the date range. A page cut
+// to the response limits continues from ?cursor=, the date of its next bar,
+// and ?fields= selects the fields of the bars in JSON.
+func (h *Handler) GetDailySummaries(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	fields, err := api.ParseFields(c, dailySummaryFields)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	cursor, err := api.ParseDateQuery(c, "cursor")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	if !cursor.IsZero() {
+		from = cursor.Unix()
+	}
+
+	adjusted, err := api.ParseBoolQuery(c, "adjusted")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	if api.WantsCSV(c) {
+		h.streamDailySummariesCSV(c, symbol, from, to, adjusted)
+		return
+	}
+
+	summaries, err := h.dailySummaryService.GetDailySummaries(c.Request.Context(), symbol, from, to)
+	if err != nil {
+		h.respondDailySummaryError(c, symbol, err)
+		return
+	}
+	if adjusted && len(summaries) > 0 {
+		adjust, err := h.corporateActionService.Adjustments(c.Request.Context(), symbol, summaries[0].Timestamp)
+		if err != nil {
+			h.respondDailySummaryError(c, symbol, err)
+			return
+		}
+		for i := range summaries {
+			summaries[i] = adjust.Apply(summaries[i])
+		}
+	}
+	// Adjusted bars also change with actions taking effect, which are not
+	// revisions of the bars
+	if !adjusted && isHistorical(from, to, h.clock.Now()) && len(summaries) > 0 && api.NotModified(c, lastRevision(summaries)) {
+		return
+	}
+
+	summaries, next, ok := api.LimitPage(c, h.limits, summaries, func(d models.DailySummary) string { return d.Date() })
+	if !ok {
+		return
+	}
+	if summaries == nil {
+		summaries = []models.DailySummary{}
+	}
+	bars, err := api.Select(fields, summaries)
+	if err != nil {
+		h.respondDailySummaryError(c, symbol, err)
+		return
+	}
+	body := gin.H{
+		"ticker":   symbol,
+		"bars":     bars,
+		"count":    len(summaries),
+		"adjusted": adjusted,
+	}
+	if next != "" {
+		body["nextCursor"] = next
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// dailySummaryFields are the fields of a bar ?fields= selects from
+var dailySummaryFields = api.JSONFields(models.DailySummary{})
+
+// isHistorical reports whether an explicit date range ends before the UTC day
+// of now, so its bars only change when corrected
+func isHistorical(from, to int64, now time.Time) bool {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return from != 0 && to != 0 && to < today.Unix()
+}
+
+// lastRevision returns when the latest of the summaries was written
+func lastRevision(summaries []models.DailySummary) time.Time {
+	var latest time.Time
+	for i := range summaries {
+		if revision := summaries[i].Revision(); revision.After(latest) {
+			latest = revision
+		}
+	}
+	return latest
+}
+
+// streamDailySummariesCSV writes the bars as CSV as they are read from the
+// repository, so a long range is never held in memory. Adjusted bars are
+// adjusted for the actions after the first bar, looked up as it is read.
+func (h *Handler) streamDailySummariesCSV(c *gin.Context, symbol string, from, to int64, adjusted bool) {
+	ctx := c.Request.Context()
+	w := api.NewCSVWriter(c, symbol+"-daily.csv", dailySummaryCSVHeader)
+	var adjust *models.Adjustments
+	err := h.dailySummaryService.EachDailySummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		if adjusted {
+			if adjust == nil {
+				var err error
+				if adjust, err = h.corporateActionService.Adjustments(ctx, symbol, summary.Timestamp); err != nil {
+					return err
+				}
+			}
+			summary = adjust.Apply(summary)
+		}
+		return w.Write(dailySummaryCSVRecord(&summary))
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if w.Started() {
+		api.Logger(c, h.log).Warnw("failed to stream daily summaries csv", "symbol", symbol, "error", err)
+		return
+	}
+	h.respondDailySummaryError(c, symbol, err)
+}
+
+func (h *Handler) respondDailySummaryError(c *gin.Context, symbol string, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidTicker):
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+	case errors.Is(err, service.ErrInvalidRange):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, service.ErrUpgradeRequired):
+		problem.Respond(c, problem.UpgradeRequired, err.Error())
+	case errors.Is(err, service.ErrPlanLimitExceeded):
+		problem.Respond(c, problem.PlanLimitExceeded, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("failed to get daily summaries", "symbol", symbol, "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve daily summaries")
+	}
+}
+
+// dailySummaryCSVHeader names the columns of a daily bars CSV export
+var dailySummaryCSVHeader = []string{
+	"date", "ticker", "open", "high", "low", "close", "volume", "vwap", "transactionCount",
+}
+
+// dailySummaryCSVRecord formats a bar as a CSV row. Fields the JSON omits when
+// unset are left empty.
+func dailySummaryCSVRecord(d *models.DailySummary) []string {
+	record := []string{
+		d.Date(),
+		d.Ticker,
+		formatPrice(d.Open),
+		formatPrice(d.High),
+		formatPrice(d.Low),
+		formatPrice(d.Close),
+		strconv.FormatFloat(float64(d.Volume), 'f', -1, 32),
+		"",
+		"",
+	}
+	if d.VWAP != 0 {
+		record[7] = formatPrice(d.VWAP)
+	}
+	if d.TransactionCount != 0 {
+		record[8] = strconv.FormatInt(int64(d.TransactionCount), 10)
+	}
+	return record
+}
+
+// formatPrice formats a price with the fewest digits that read back as the
+// same float32, so CSV values match the JSON ones
+func formatPrice(v float32) string {
+	return strconv.FormatFloat(float64(v), 'f', -1, 32)
+}
+
+func (h *Handler) GetTickerQuote(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	quote, err := h.dailySummaryService.GetQuote(c.Request.Context(), symbol)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTicker):
+			problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+		case errors.Is(err, service.ErrTickerNotFound):
+			problem.Respond(c, problem.TickerNotFound, "No data for ticker")
+		default:
+			api.Logger(c, h.log).Errorw("failed to get quote", "symbol", symbol, "error", err)
+			problem.Respond(c, problem.Internal, "Failed to retrieve quote")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, quote)
+}
@@ -0,0 +1,629 @@
+package summaries
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockDailySummaryService mocks the DailySummaryService interface
+type MockDailySummaryService struct {
+	mock.Mock
+}
+
+func (m *MockDailySummaryService) GetDailySummaries(ctx context.Context, symbol string, from, to int64) ([]models.DailySummary, error) {
+	args := m.Called(ctx, symbol, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DailySummary), args.Error(1)
+}
+
+func (m *MockDailySummaryService) EachDailySummary(ctx context.Context, symbol string, from, to int64, fn func(models.DailySummary) error) error {
+	args := m.Called(ctx, symbol, from, to)
+	if summaries, ok := args.Get(0).([]models.DailySummary); ok {
+		for _, summary := range summaries {
+			if err := fn(summary); err != nil {
+				return err
+			}
+		}
+	}
+	return args.Error(1)
+}
+
+func (m *MockDailySummaryService) GetQuote(ctx context.Context, symbol string) (*models.Quote, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Quote), args.Error(1)
+}
+
+func TestHandler_GetDailySummaries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		symbol         string
+		query          string
+		mockSetup      func(*MockDailySummaryService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "returns bars for range",
+			symbol: "aapl",
+			query:  "from=2024-01-02&to=2024-01-03",
+			mockSetup: func(m *MockDailySummaryService) {
+				// to is inclusive of the whole day
+				m.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704153600), int64(1704326399)).Return([]models.DailySummary{
+					{Ticker: "AAPL", Timestamp: 1704153600, Close: 185},
+					{Ticker: "AAPL", Timestamp: 1704240000, Close: 184},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"ticker": "AAPL",
+				"count":  float64(2),
+			},
+		},
+		{
+			name:   "sparse fields",
+			symbol: "AAPL",
+			query:  "fields=timestamp,close",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("GetDailySummaries", mock.Anything, "AAPL", int64(0), int64(0)).Return([]models.DailySummary{
+					{Ticker: "AAPL", Timestamp: 1704153600, Open: 187, Close: 185, Volume: 1000},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"bars":  []interface{}{map[string]interface{}{"timestamp": float64(1704153600), "close": float64(185)}},
+				"count": float64(1),
+			},
+		},
+		{
+			name:           "unknown field",
+			symbol:         "AAPL",
+			query:          "fields=close,name",
+			mockSetup:      func(m *MockDailySummaryService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"code": "VALIDATION_FAILED",
+			},
+		},
+		{
+			name:           "malformed date",
+			symbol:         "AAPL",
+			query:          "from=01/02/2024",
+			mockSetup:      func(m *MockDailySummaryService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"detail": `invalid from date "01/02/2024", expected YYYY-MM-DD`,
+			},
+		},
+		{
+			name:   "invalid range",
+			symbol: "AAPL",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("GetDailySummaries", mock.Anything, "AAPL", int64(0), int64(0)).Return(
+					nil, fmt.Errorf("%w: from must not be after to", service.ErrInvalidRange))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"detail": "invalid date range: from must not be after to",
+			},
+		},
+		{
+			name:   "service error",
+			symbol: "AAPL",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("GetDailySummaries", mock.Anything, "AAPL", int64(0), int64(0)).Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"detail": "Failed to retrieve daily summaries",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDailySummaryService)
+			tt.mockSetup(mockService)
+
+			handler := &Handler{
+				dailySummaryService: mockService,
+				clock:               clock.System,
+				log:                 zap.NewNop().Sugar(),
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/tickers/"+tt.symbol+"/daily?"+tt.query, nil)
+			c.Params = gin.Params{{Key: "symbol", Value: tt.symbol}}
+
+			handler.GetDailySummaries(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key])
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetDailySummariesConditional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := new(MockDailySummaryService)
+	m.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704153600), int64(1704326399)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704153600, Close: 185, UpdatedUTC: 1704300000},
+		// Unstamped bars count as written the day after their session
+		{Ticker: "AAPL", Timestamp: 1704240000, Close: 184},
+	}, nil)
+	m.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704153600), int64(0)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704153600, Close: 185, UpdatedUTC: 1704300000},
+	}, nil)
+	handler := &Handler{dailySummaryService: m, clock: clock.NewFake(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)), log: zap.NewNop().Sugar()}
+
+	get := func(query, ifModifiedSince string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers/aapl/daily?"+query, nil)
+		if ifModifiedSince != "" {
+			c.Request.Header.Set("If-Modified-Since", ifModifiedSince)
+		}
+		c.Params = gin.Params{{Key: "symbol", Value: "aapl"}}
+		handler.GetDailySummaries(c)
+		return w
+	}
+
+	const historical = "from=2024-01-02&to=2024-01-03"
+	w := get(historical, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	lastModified := w.Header().Get("Last-Modified")
+	assert.Equal(t, "Thu, 04 Jan 2024 00:00:00 GMT", lastModified)
+
+	w = get(historical, lastModified)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	assert.Equal(t, http.StatusOK, get(historical, "Wed, 03 Jan 2024 23:59:59 GMT").Code, "bars written since are sent")
+	assert.Equal(t, http.StatusOK, get(historical, "yesterday").Code, "unparsable dates are ignored")
+
+	w = get("from=2024-01-02", lastModified)
+	assert.Equal(t, http.StatusOK, w.Code, "ranges running to today may still change")
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+}
+
+func TestIsHistorical(t *testing.T) {
+	// 2024-01-03 00:00:00 and 23:59:59 UTC
+	const from, to = int64(1704240000), int64(1704326399)
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want bool
+	}{
+		{name: "the day after the range", now: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "the range's last second", now: time.Date(2024, 1, 3, 23, 59, 59, 0, time.UTC)},
+		{name: "the range's day", now: time.Date(2024, 1, 3, 12, 0, 0, 0, time.UTC)},
+		{name: "in another zone, the day after in UTC", now: time.Date(2024, 1, 3, 19, 0, 0, 0, time.FixedZone("EST", -5*3600)), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isHistorical(from, to, tt.now))
+		})
+	}
+	assert.False(t, isHistorical(0, to, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)), "open ranges are not historical")
+	assert.False(t, isHistorical(from, 0, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)), "ranges running to today are not")
+}
+
+func TestHandler_GetDailySummariesPages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := new(MockDailySummaryService)
+	m.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704153600), int64(1704412799)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704153600, Close: 185},
+		{Ticker: "AAPL", Timestamp: 1704240000, Close: 184},
+		{Ticker: "AAPL", Timestamp: 1704326400, Close: 182},
+	}, nil)
+	m.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704326400), int64(1704412799)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704326400, Close: 182},
+	}, nil)
+	handler := &Handler{dailySummaryService: m, limits: api.ResponseLimits{MaxItems: 2}, clock: clock.System, log: zap.NewNop().Sugar()}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers/aapl/daily?"+query, nil)
+		c.Params = gin.Params{{Key: "symbol", Value: "aapl"}}
+		handler.GetDailySummaries(c)
+		return w
+	}
+
+	w := get("from=2024-01-02&to=2024-01-04")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page struct {
+		Bars       []models.DailySummary `json:"bars"`
+		Count      int                   `json:"count"`
+		NextCursor string                `json:"nextCursor"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.Equal(t, 2, page.Count)
+	assert.Equal(t, "2024-01-04", page.NextCursor, "the cursor is the date of the next bar")
+	assert.NotEmpty(t, w.Header().Get("Warning"))
+
+	w = get("from=2024-01-02&to=2024-01-04&cursor=" + page.NextCursor)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "nextCursor")
+
+	assert.Equal(t, http.StatusBadRequest, get("cursor=soon").Code)
+	m.AssertExpectations(t)
+}
+
+func TestHandler_GetDailySummariesCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	bars := []models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704153600, Open: 187.15, High: 188.44, Low: 183.885, Close: 185.64, Volume: 82488700, VWAP: 185.9, TransactionCount: 1009074},
+		{Ticker: "AAPL", Timestamp: 1704240000, Open: 184.22, High: 185.88, Low: 183.43, Close: 184.25, Volume: 58414500},
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		accept         string
+		mockSetup      func(*MockDailySummaryService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:  "format query",
+			query: "format=csv",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("EachDailySummary", mock.Anything, "AAPL", int64(0), int64(0)).Return(bars, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: "date,ticker,open,high,low,close,volume,vwap,transactionCount\n" +
+				"2024-01-02,AAPL,187.15,188.44,183.885,185.64,82488700,185.9,1009074\n" +
+				"2024-01-03,AAPL,184.22,185.88,183.43,184.25,58414500,,\n",
+		},
+		{
+			name:   "accept header",
+			accept: "text/csv",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("EachDailySummary", mock.Anything, "AAPL", int64(0), int64(0)).Return([]models.DailySummary{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "date,ticker,open,high,low,close,volume,vwap,transactionCount\n",
+		},
+		{
+			name:   "format query wins over accept",
+			query:  "format=json",
+			accept: "text/csv",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("GetDailySummaries", mock.Anything, "AAPL", int64(0), int64(0)).Return(bars, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "errors before the first row are still json",
+			query: "format=csv",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("EachDailySummary", mock.Anything, "AAPL", int64(0), int64(0)).Return(nil, service.ErrUpgradeRequired)
+			},
+			expectedStatus: http.StatusPaymentRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDailySummaryService)
+			tt.mockSetup(mockService)
+
+			handler := &Handler{
+				dailySummaryService: mockService,
+				clock:               clock.System,
+				log:                 zap.NewNop().Sugar(),
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/tickers/aapl/daily?"+tt.query, nil)
+			if tt.accept != "" {
+				c.Request.Header.Set("Accept", tt.accept)
+			}
+			c.Params = gin.Params{{Key: "symbol", Value: "aapl"}}
+
+			handler.GetDailySummaries(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != "" {
+				assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="AAPL-daily.csv"`, w.Header().Get("Content-Disposition"))
+				assert.Equal(t, tt.expectedBody, w.Body.String())
+			} else {
+				assert.Contains(t, w.Header().Get("Content-Type"), "json")
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetTickerQuote(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		symbol         string
+		mockSetup      func(*MockDailySummaryService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "returns quote with change",
+			symbol: "msft",
+			mockSetup: func(m *MockDailySummaryService) {
+				quote := models.NewQuote(models.DailySummary{Ticker: "MSFT", Timestamp: 1704240000, Close: 110}, 100)
+				m.On("GetQuote", mock.Anything, "MSFT").Return(&quote, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"ticker":        "MSFT",
+				"close":         float64(110),
+				"previousClose": float64(100),
+				"change":        float64(10),
+				"changePercent": float64(10),
+			},
+		},
+		{
+			name:   "no data",
+			symbol: "ZZZZ",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("GetQuote", mock.Anything, "ZZZZ").Return(nil, service.ErrTickerNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"detail": "No data for ticker",
+			},
+		},
+		{
+			name:   "service error",
+			symbol: "MSFT",
+			mockSetup: func(m *MockDailySummaryService) {
+				m.On("GetQuote", mock.Anything, "MSFT").Return(nil, errors.New("db down"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"detail": "Failed to retrieve quote",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockDailySummaryService)
+			tt.mockSetup(mockService)
+
+			handler := &Handler{
+				dailySummaryService: mockService,
+				log:                 zap.NewNop().Sugar(),
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/tickers/"+tt.symbol+"/quote", nil)
+			c.Params = gin.Params{{Key: "symbol", Value: tt.symbol}}
+
+			handler.GetTickerQuote(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key])
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_LatestIsTheQuote(t *testing.T) {
+	summaries := new(MockDailySummaryService)
+	quote := models.NewQuote(models.DailySummary{Ticker: "MSFT", Timestamp: 1704240000, Close: 110}, 100)
+	summaries.On("GetQuote", mock.Anything, "MSFT").Return(&quote, nil)
+	summaries.On("GetQuote", mock.Anything, "ZZZZ").Return(nil, service.ErrTickerNotFound)
+	r := newCorporateActionRouter(summaries, nil)
+
+	for symbol, status := range map[string]int{"msft": http.StatusOK, "zzzz": http.StatusNotFound} {
+		t.Run(symbol, func(t *testing.T) {
+			quoted := serveRequest(r, http.MethodGet, "/api/tickers/"+symbol+"/quote", "")
+			latest := serveRequest(r, http.MethodGet, "/api/tickers/"+symbol+"/latest", "")
+			assert.Equal(t, status, latest.Code)
+			assert.Equal(t, quoted.Code, latest.Code)
+			assert.Equal(t, quoted.Header().Get("Content-Type"), latest.Header().Get("Content-Type"))
+
+			var quotedBody, latestBody map[string]any
+			require.NoError(t, json.Unmarshal(quoted.Body.Bytes(), &quotedBody))
+			require.NoError(t, json.Unmarshal(latest.Body.Bytes(), &latestBody))
+			if status != http.StatusOK {
+				// Problems name the path they answer, the one difference
+				assert.Equal(t, "/api/tickers/"+symbol+"/latest", latestBody["instance"])
+				delete(quotedBody, "instance")
+				delete(latestBody, "instance")
+			}
+			assert.Equal(t, quotedBody, latestBody)
+		})
+	}
+}
+
+func TestHandler_GetPrices(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(m *MockDailySummaryService, query string) *httptest.ResponseRecorder {
+		handler := &Handler{
+			dailySummaryService: m,
+			log:                 zap.NewNop().Sugar(),
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/prices?"+query, nil)
+		handler.GetPrices(c)
+		return w
+	}
+
+	t.Run("returns quotes in order with missing symbols", func(t *testing.T) {
+		m := new(MockDailySummaryService)
+		aapl := models.NewQuote(models.DailySummary{Ticker: "AAPL", Close: 200}, 190)
+		msft := models.NewQuote(models.DailySummary{Ticker: "MSFT", Close: 110}, 100)
+		m.On("GetQuote", mock.Anything, "AAPL").Return(&aapl, nil)
+		m.On("GetQuote", mock.Anything, "MSFT").Return(&msft, nil)
+		m.On("GetQuote", mock.Anything, "ZZZZ").Return(nil, service.ErrTickerNotFound)
+
+		w := serve(m, "symbols=aapl,ZZZZ,%20msft,AAPL")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Quotes  []models.Quote `json:"quotes"`
+			Missing []string       `json:"missing"`
+			Count   int            `json:"count"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, 2, body.Count)
+		assert.Equal(t, []models.Quote{aapl, msft}, body.Quotes)
+		assert.Equal(t, []string{"ZZZZ"}, body.Missing)
+		m.AssertNumberOfCalls(t, "GetQuote", 3)
+	})
+
+	t.Run("validates the symbols", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serve(new(MockDailySummaryService), "symbols=,").Code)
+
+		many := make([]string, maxPriceSymbols+1)
+		for i := range many {
+			many[i] = fmt.Sprintf("S%d", i)
+		}
+		assert.Equal(t, http.StatusBadRequest, serve(new(MockDailySummaryService), "symbols="+strings.Join(many, ",")).Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		m := new(MockDailySummaryService)
+		m.On("GetQuote", mock.Anything, "MSFT").Return(nil, errors.New("db down"))
+
+		assert.Equal(t, http.StatusInternalServerError, serve(m, "symbols=MSFT").Code)
+	})
+}
+
+// MockBarService mocks the BarService interface
+type MockBarService struct {
+	mock.Mock
+}
+
+func (m *MockBarService) GetBars(ctx context.Context, symbol string, resolution models.Resolution, from, to int64) ([]models.AggregateBar, models.BarQueryPlan, error) {
+	args := m.Called(ctx, symbol, resolution, from, to)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]models.AggregateBar), args.Get(1).(models.BarQueryPlan), args.Error(2)
+}
+
+func (m *MockBarService) RollUp(ctx context.Context, date time.Time) (int, error) {
+	args := m.Called(ctx, date)
+	return args.Int(0), args.Error(1)
+}
+
+func TestHandler_GetTickerBars(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(m *MockBarService, query string) *httptest.ResponseRecorder {
+		handler := &Handler{
+			barService: m,
+			log:        zap.NewNop().Sugar(),
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers/aapl/bars?"+query, nil)
+		c.Params = gin.Params{{Key: "symbol", Value: "aapl"}}
+		handler.GetTickerBars(c)
+		return w
+	}
+
+	t.Run("answers the planned bars of the range", func(t *testing.T) {
+		bar := models.AggregateBar{
+			Timestamp: 1704067200, Date: "2024-01-01", Open: 187, High: 190, Low: 183, Close: 184, Volume: 30, Sessions: 2,
+		}
+		m := new(MockBarService)
+		m.On("GetBars", mock.Anything, "AAPL", models.ResolutionMonth, int64(1704067200), int64(1706745599)).Return(
+			[]models.AggregateBar{bar},
+			models.BarQueryPlan{{Source: models.BarSourceDaily, From: "2024-01-01", To: "2024-01-31"}},
+			nil,
+		)
+
+		w := serve(m, "resolution=MONTH&from=2024-01-01&to=2024-01-31")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "daily 2024-01-01..2024-01-31", w.Header().Get(QueryPlanHeader))
+		var body struct {
+			Ticker     string                `json:"ticker"`
+			Resolution string                `json:"resolution"`
+			Bars       []models.AggregateBar `json:"bars"`
+			Count      int                   `json:"count"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "AAPL", body.Ticker)
+		assert.Equal(t, "month", body.Resolution)
+		require.Equal(t, 1, body.Count)
+		assert.Equal(t, bar, body.Bars[0])
+	})
+
+	t.Run("defaults to weekly bars", func(t *testing.T) {
+		m := new(MockBarService)
+		m.On("GetBars", mock.Anything, "AAPL", models.ResolutionWeek, int64(0), int64(0)).Return([]models.AggregateBar{}, models.BarQueryPlan{}, nil)
+
+		w := serve(m, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ticker":"AAPL","resolution":"week","bars":[],"count":0}`, w.Body.String())
+	})
+
+	t.Run("rejects unknown resolutions", func(t *testing.T) {
+		m := new(MockBarService)
+		m.On("GetBars", mock.Anything, "AAPL", models.Resolution("day"), int64(0), int64(0)).Return(nil, nil, service.ErrInvalidResolution)
+		w := serve(m, "resolution=day")
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("plan history limits", func(t *testing.T) {
+		m := new(MockBarService)
+		m.On("GetBars", mock.Anything, "AAPL", models.ResolutionWeek, int64(0), int64(0)).Return(nil, nil, service.ErrUpgradeRequired)
+		assert.Equal(t, http.StatusPaymentRequired, serve(m, "resolution=week").Code)
+	})
+}
@@ -0,0 +1,124 @@
+package summaries
+
+import (
+	"context"
+	"errors"
+
+	profitifyv1 "profitify-backend/api/proto/profitify/v1"
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterGRPC serves the daily summary service over gRPC
+func (h *Handler) RegisterGRPC(s grpc.ServiceRegistrar) {
+	profitifyv1.RegisterDailySummaryServiceServer(s, dailySummaryServer{h: h})
+}
+
+// dailySummaryServer serves the daily bars and quote routes over gRPC
+type dailySummaryServer struct {
+	profitifyv1.UnimplementedDailySummaryServiceServer
+	h *Handler
+}
+
+func (s dailySummaryServer) ListDailySummaries(ctx context.Context, req *profitifyv1.ListDailySummariesRequest) (*profitifyv1.ListDailySummariesResponse, error) {
+	from, to, err := api.DateRange(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	symbol := api.NormalizeSymbol(req.GetSymbol())
+	summaries, err := s.h.dailySummaryService.GetDailySummaries(ctx, symbol, from, to)
+	if err != nil {
+		return nil, s.dailySummaryError(ctx, symbol, err)
+	}
+
+	resp := &profitifyv1.ListDailySummariesResponse{Ticker: symbol, Bars: make([]*profitifyv1.DailySummary, len(summaries))}
+	for i := range summaries {
+		resp.Bars[i] = dailySummaryMessage(&summaries[i])
+	}
+	return resp, nil
+}
+
+// StreamDailySummaries sends the bars as they are read from the repository,
+// so a long range is never held in memory
+func (s dailySummaryServer) StreamDailySummaries(req *profitifyv1.ListDailySummariesRequest, stream grpc.ServerStreamingServer[profitifyv1.DailySummary]) error {
+	from, to, err := api.DateRange(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	ctx := stream.Context()
+	symbol := api.NormalizeSymbol(req.GetSymbol())
+	var sendErr error
+	err = s.h.dailySummaryService.EachDailySummary(ctx, symbol, from, to, func(summary models.DailySummary) error {
+		sendErr = stream.Send(dailySummaryMessage(&summary))
+		return sendErr
+	})
+	if err == nil || (sendErr != nil && errors.Is(err, sendErr)) {
+		// The stream reports its own failure, usually the caller going away
+		return err
+	}
+	return s.dailySummaryError(ctx, symbol, err)
+}
+
+func (s dailySummaryServer) GetQuote(ctx context.Context, req *profitifyv1.GetQuoteRequest) (*profitifyv1.Quote, error) {
+	symbol := api.NormalizeSymbol(req.GetSymbol())
+	quote, err := s.h.dailySummaryService.GetQuote(ctx, symbol)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTicker):
+			return nil, status.Error(codes.InvalidArgument, "invalid ticker symbol")
+		case errors.Is(err, service.ErrTickerNotFound):
+			return nil, status.Error(codes.NotFound, "no data for ticker")
+		default:
+			logger.FromContext(ctx, s.h.log).Errorw("failed to get quote", "symbol", symbol, "error", err)
+			return nil, status.Error(codes.Internal, "failed to retrieve quote")
+		}
+	}
+
+	return &profitifyv1.Quote{
+		Latest:        dailySummaryMessage(&quote.DailySummary),
+		PreviousClose: quote.PreviousClose,
+		Change:        quote.Change,
+		ChangePercent: quote.ChangePercent,
+	}, nil
+}
+
+// dailySummaryError maps the errors respondDailySummaryError answers over
+// HTTP to status codes. Both plan errors deny the call: gRPC has no code for
+// payment required.
+func (s dailySummaryServer) dailySummaryError(ctx context.Context, symbol string, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidTicker):
+		return status.Error(codes.InvalidArgument, "invalid ticker symbol")
+	case errors.Is(err, service.ErrInvalidRange):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrUpgradeRequired), errors.Is(err, service.ErrPlanLimitExceeded):
+		return status.Error(codes.PermissionDenied, err.Error())
+	default:
+		logger.FromContext(ctx, s.h.log).Errorw("failed to get daily summaries", "symbol", symbol, "error", err)
+		return status.Error(codes.Internal, "failed to retrieve daily summaries")
+	}
+}
+
+func dailySummaryMessage(d *models.DailySummary) *profitifyv1.DailySummary {
+	return &profitifyv1.DailySummary{
+		Ticker:           d.Ticker,
+		Date:             d.Date(),
+		Timestamp:        d.Timestamp,
+		Open:             d.Open,
+		High:             d.High,
+		Low:              d.Low,
+		Close:            d.Close,
+		Volume:           d.Volume,
+		Vwap:             d.VWAP,
+		TransactionCount: d.TransactionCount,
+		Otc:              d.OTC,
+	}
+}
@@ -0,0 +1,134 @@
+package summaries
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	profitifyv1 "profitify-backend/api/proto/profitify/v1"
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/grpcserver"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dailySummaryClient serves the handler's gRPC services over an in-memory
+// listener and returns a client of them
+func dailySummaryClient(t *testing.T, svc service.DailySummaryService) profitifyv1.DailySummaryServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpcserver.New(grpcserver.AuthConfig{}, time.Second, zap.NewNop().Sugar(), NewHandler(svc, nil, nil, nil, nil, api.ResponseLimits{}, clock.System, zap.NewNop().Sugar()))
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		<-served
+	})
+	return profitifyv1.NewDailySummaryServiceClient(conn)
+}
+
+func TestDailySummaryServer_ListDailySummaries(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockDailySummaryService)
+	client := dailySummaryClient(t, svc)
+
+	svc.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704153600), int64(1704326399)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704171600, Open: 187.15, Close: 185.64, VWAP: 185.9},
+		{Ticker: "AAPL", Timestamp: 1704258000, Open: 184.22, Close: 184.25},
+	}, nil)
+	resp, err := client.ListDailySummaries(ctx, &profitifyv1.ListDailySummariesRequest{Symbol: "aapl", From: "2024-01-02", To: "2024-01-03"})
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", resp.GetTicker())
+	require.Len(t, resp.GetBars(), 2)
+	assert.Equal(t, "2024-01-02", resp.GetBars()[0].GetDate())
+	assert.Equal(t, float32(185.9), resp.GetBars()[0].GetVwap())
+
+	_, err = client.ListDailySummaries(ctx, &profitifyv1.ListDailySummariesRequest{Symbol: "AAPL", From: "01/02/2024"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	svc.On("GetDailySummaries", mock.Anything, "MSFT", int64(0), int64(0)).Return(nil, service.ErrUpgradeRequired)
+	_, err = client.ListDailySummaries(ctx, &profitifyv1.ListDailySummariesRequest{Symbol: "MSFT"})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestDailySummaryServer_StreamDailySummaries(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockDailySummaryService)
+	client := dailySummaryClient(t, svc)
+
+	recv := func(symbol string) ([]*profitifyv1.DailySummary, error) {
+		stream, err := client.StreamDailySummaries(ctx, &profitifyv1.ListDailySummariesRequest{Symbol: symbol})
+		require.NoError(t, err)
+		var bars []*profitifyv1.DailySummary
+		for {
+			bar, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return bars, nil
+			}
+			if err != nil {
+				return bars, err
+			}
+			bars = append(bars, bar)
+		}
+	}
+
+	svc.On("EachDailySummary", mock.Anything, "AAPL", int64(0), int64(0)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704171600},
+		{Ticker: "AAPL", Timestamp: 1704258000},
+	}, nil)
+	bars, err := recv("AAPL")
+	require.NoError(t, err)
+	require.Len(t, bars, 2)
+	assert.Equal(t, "2024-01-03", bars[1].GetDate())
+
+	svc.On("EachDailySummary", mock.Anything, "", int64(0), int64(0)).Return(nil, service.ErrInvalidTicker)
+	_, err = recv("")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDailySummaryServer_GetQuote(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockDailySummaryService)
+	client := dailySummaryClient(t, svc)
+
+	svc.On("GetQuote", mock.Anything, "AAPL").Return(&models.Quote{
+		DailySummary:  models.DailySummary{Ticker: "AAPL", Timestamp: 1704258000, Close: 184.25},
+		PreviousClose: 185.64,
+		Change:        -1.39,
+		ChangePercent: -0.75,
+	}, nil)
+	svc.On("GetQuote", mock.Anything, "NOPE").Return(nil, service.ErrTickerNotFound)
+	svc.On("GetQuote", mock.Anything, "MSFT").Return(nil, errors.New("table unavailable"))
+
+	quote, err := client.GetQuote(ctx, &profitifyv1.GetQuoteRequest{Symbol: "aapl"})
+	require.NoError(t, err)
+	assert.Equal(t, float32(184.25), quote.GetLatest().GetClose())
+	assert.Equal(t, float32(185.64), quote.GetPreviousClose())
+	assert.Equal(t, -1.39, quote.GetChange())
+
+	_, err = client.GetQuote(ctx, &profitifyv1.GetQuoteRequest{Symbol: "nope"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.GetQuote(ctx, &profitifyv1.GetQuoteRequest{Symbol: "msft"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,65 @@
+package summaries
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTickerIntraday answers with the intraday bars of ?date= at ?resolution=
+func (h *Handler) GetTickerIntraday(c *gin.Context) {
+	date, err := api.ParseDateQuery(c, "date")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	bars, err := h.intradayService.GetBars(c.Request.Context(), symbol, date, c.DefaultQuery("resolution", models.IntradayResolution1m))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTicker):
+			problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+		case errors.Is(err, service.ErrInvalidResolution):
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+		default:
+			api.Logger(c, h.log).Errorw("failed to get intraday bars", "symbol", symbol, "error", err)
+			problem.Respond(c, problem.Internal, "Failed to retrieve intraday bars")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, bars)
+}
+
+func (h *Handler) GetTickerVWAP(c *gin.Context) {
+	anchor, err := api.ParseDateQuery(c, "anchor")
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	series, err := h.intradayService.GetVWAP(c.Request.Context(), symbol, anchor, time.Time{})
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTicker):
+			problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+		case errors.Is(err, service.ErrInvalidRange):
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+		default:
+			api.Logger(c, h.log).Errorw("failed to compute vwap", "symbol", symbol, "error", err)
+			problem.Respond(c, problem.Internal, "Failed to compute VWAP")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
@@ -0,0 +1,275 @@
+// Package summaries serves a ticker's daily bars, their weekly and monthly
+// resamples and revisions, latest quote and intraday VWAP.
+package summaries
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	dailySummaryService    service.DailySummaryService
+	barService             service.BarService
+	intradayService        service.IntradayService
+	corporateActionService service.CorporateActionService
+	summaryRevisionService service.SummaryRevisionService
+	limits                 api.ResponseLimits
+	// clock dates today, which ranges must end before to be historical
+	clock clock.Clock
+	log   *zap.SugaredLogger
+}
+
+func NewHandler(dailySummaries service.DailySummaryService, bars service.BarService, intraday service.IntradayService, corporateActions service.CorporateActionService, revisions service.SummaryRevisionService, limits api.ResponseLimits, c clock.Clock, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		dailySummaryService:    dailySummaries,
+		barService:             bars,
+		intradayService:        intraday,
+		corporateActionService: corporateActions,
+		summaryRevisionService: revisions,
+		limits:                 limits,
+		clock:                  c,
+		log:                    log,
+	}
+}
+
+// Wire builds the summaries module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	summaryRepo := deps.DailySummaryRepository()
+	dailySummaries := service.NewDailySummaryService(summaryRepo, deps.Log)
+	return NewHandler(
+		dailySummaries,
+		service.NewBarService(dailySummaries, deps.TickerRepository(),
+			repository.NewBarRollupRepository(deps.DB, deps.Config.BarRollupsTable), deps.SettingsService(),
+			deps.Cache, deps.Config.BarsCacheTTL, deps.Log),
+		service.NewIntradayService(deps.IntradayBarRepository(), deps.Clock, deps.Log),
+		service.NewCorporateActionService(
+			repository.NewCorporateActionRepository(deps.DB, deps.Config.CorporateActionsTable), summaryRepo, deps.Log),
+		service.NewSummaryRevisionService(deps.SummaryRevisionRepository(), deps.Log),
+		api.LimitsFromConfig(deps.Config),
+		deps.Clock,
+		deps.Log,
+	)
+}
+
+// PostCloseJobs returns the job rolling up the weeks and months each trading
+// day closes
+func (h *Handler) PostCloseJobs() []jobs.Job {
+	return []jobs.Job{
+		jobs.NewJob("bar-rollups", func(ctx context.Context, date time.Time) error {
+			_, err := h.barService.RollUp(ctx, date)
+			return err
+		}),
+	}
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	ticker := api.Group("/tickers/:symbol", middleware.RequireScope(models.ScopeReadMarket))
+	ticker.GET("/daily", h.GetDailySummaries)
+	ticker.GET("/bars", h.GetTickerBars)
+	ticker.GET("/returns", h.GetTickerReturns)
+	ticker.GET("/quote", h.GetTickerQuote)
+	// latest is the quote under the name clients of other market data APIs
+	// look for
+	ticker.GET("/latest", h.GetTickerQuote)
+	ticker.GET("/intraday", h.GetTickerIntraday)
+	ticker.GET("/vwap", h.GetTickerVWAP)
+	ticker.GET("/splits", h.GetTickerSplits)
+	ticker.GET("/dividends", h.GetTickerDividends)
+	ticker.GET("/revisions", h.GetTickerRevisions)
+
+	api.GET("/prices", middleware.RequireScope(models.ScopeReadMarket), h.GetPrices)
+
+	admin.POST("/corporate-actions", h.IngestCorporateActions)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	symbol := openapi.PathParam("symbol", "Ticker symbol, case insensitive")
+
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/daily", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "List a ticker's daily bars",
+		Description: "Bars in the date range, oldest first. Without from the range starts a year before to, cut to the key's plan history. " +
+			"With adjusted=true, bars before a split are restated in post-split shares and prices before an ex-dividend date are " +
+			"discounted by the dividend's share of the previous close. Unadjusted JSON responses for a from/to range ending before " +
+			"today carry Last-Modified, when the latest of their bars was written, and answer 304 to an If-Modified-Since no older than it. " +
+			"JSON bars beyond RESPONSE_MAX_ITEMS or RESPONSE_MAX_BYTES are cut with a Warning header and a nextCursor, the date to " +
+			"continue from, or answer 413 with RESPONSE_OVERSIZE=reject; CSV is streamed whole.",
+		Parameters: append([]openapi.Parameter{
+			symbol,
+			api.FormatParam(),
+			openapi.QueryParam("adjusted", "Adjust the bars for splits and dividends (default false)", &openapi.Schema{Type: "boolean"}),
+			api.IfModifiedSinceParam(),
+			openapi.QueryParam("cursor", "nextCursor of the previous page, the date it continues from in place of from", api.DateSchema),
+			api.FieldsParam(dailySummaryFields),
+		}, api.DateRangeParams()...),
+		Responses: api.WithNotModified(api.WithCSV(api.Responses(http.StatusOK, api.Paged(openapi.Object(map[string]*openapi.Schema{
+			"ticker":   {Type: "string"},
+			"bars":     {Type: "array", Items: doc.Schema(models.DailySummary{})},
+			"count":    {Type: "integer"},
+			"adjusted": {Type: "boolean"},
+		})), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden, http.StatusRequestEntityTooLarge),
+			http.StatusOK, "Bars as CSV with a header row, one bar per line")),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/bars", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "List a ticker's weekly or monthly bars",
+		Description: "The daily bars of the date range resampled server-side: each bar opens at its period's first session, " +
+			"spans the highest high and lowest low, closes at its last session and sums the volume. Weeks start on Monday. " +
+			"The range defaults as for /daily, so the first and last bars may cover part of their period. " +
+			"Bars are read from the cheapest source: a cached answer of the same range ending before today, the weekly and monthly " +
+			"rollups stored as each period closes, or the daily bars. X-Query-Plan lists the sources by date range, e.g. " +
+			"\"daily 2024-01-03..2024-01-07; rollup-week 2024-01-08..2024-12-29\".",
+		Parameters: append([]openapi.Parameter{
+			symbol,
+			openapi.QueryParam("resolution", "Period of the bars (default week)", &openapi.Schema{
+				Type: "string",
+				Enum: []any{string(models.ResolutionWeek), string(models.ResolutionMonth)},
+			}),
+		}, api.DateRangeParams()...),
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker":     {Type: "string"},
+			"resolution": {Type: "string"},
+			"bars":       {Type: "array", Items: doc.Schema(models.AggregateBar{})},
+			"count":      {Type: "integer"},
+		}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/revisions", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "List the corrections of a ticker's daily bars",
+		Description: "Each time a stored bar dated in the range was overwritten with different values, by date and then by when: " +
+			"the bar before (old) and after (new), the fields that changed and the source that wrote it, e.g. daily-ingest, " +
+			"backfill, ingest-queue or admin-ingest:<job id>. Bars written for the first time are not revisions. " +
+			"The range defaults as for /daily.",
+		Parameters: append([]openapi.Parameter{symbol}, api.DateRangeParams()...),
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker":    {Type: "string"},
+			"revisions": {Type: "array", Items: doc.Schema(models.SummaryRevision{})},
+			"count":     {Type: "integer"},
+		}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+	returns := openapi.Object(map[string]*openapi.Schema{
+		"ticker":   {Type: "string"},
+		"type":     {Type: "string"},
+		"adjusted": {Type: "boolean"},
+		"points":   {Type: "array", Items: doc.Schema(models.ReturnPoint{})},
+		"count":    {Type: "integer"},
+		"summary":  doc.Schema(models.ReturnSummary{}),
+	})
+	// The summary is omitted for ranges without bars
+	returns.Required = slices.DeleteFunc(returns.Required, func(name string) bool { return name == "summary" })
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/returns", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "Measure a ticker's returns over a date range",
+		Description: "One point per session after the first of the range: the change from the previous close (simple), its natural " +
+			"log (log) or the change from the first close (cumulative). The summary has the total return, the return annualized " +
+			"over 252 sessions a year and the annualized volatility of the daily log returns; it is null with fewer than two " +
+			"sessions. Bars are adjusted for splits and dividends unless adjusted=false. The range defaults as for /daily.",
+		Parameters: append([]openapi.Parameter{
+			symbol,
+			openapi.QueryParam("type", "How each session's return is measured (default simple)", &openapi.Schema{
+				Type: "string",
+				Enum: []any{string(models.ReturnSimple), string(models.ReturnLog), string(models.ReturnCumulative)},
+			}),
+			openapi.QueryParam("adjusted", "Adjust the bars for splits and dividends (default true)", &openapi.Schema{Type: "boolean"}),
+		}, api.DateRangeParams()...),
+		Responses: api.Responses(http.StatusOK, returns, http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/quote", &openapi.Operation{
+		Tags:       []string{"Daily bars"},
+		Summary:    "Get a ticker's latest close and daily change",
+		Parameters: []openapi.Parameter{symbol},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.Quote{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/latest", &openapi.Operation{
+		Tags:        []string{"Daily bars"},
+		Summary:     "Get a ticker's most recent daily bar with its change",
+		Description: "Same as /quote: the newest daily summary, read newest first with the session before it in one query, with previousClose, change and changePercent.",
+		Parameters:  []openapi.Parameter{symbol},
+		Responses:   api.Responses(http.StatusOK, doc.Schema(models.Quote{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/prices", &openapi.Operation{
+		Tags:        []string{"Daily bars"},
+		Summary:     "Get the latest close and daily change of several tickers",
+		Description: "Quotes in the order of symbols; symbols without daily bars are listed in missing.",
+		Parameters: []openapi.Parameter{
+			{Name: "symbols", In: "query", Required: true, Description: "Comma-separated ticker symbols, case insensitive, at most 100", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"quotes":  {Type: "array", Items: doc.Schema(models.Quote{})},
+			"missing": {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			"count":   {Type: "integer"},
+		}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/intraday", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "List a ticker's intraday bars over a trading day",
+		Description: "The minute bars of the day in market time, pre-market through after hours, oldest first. At 5m and 15m they " +
+			"are resampled server-side: each bar opens at its first minute, closes at its last and starts on a multiple of its " +
+			"resolution, with the volume of its minutes and their volume-weighted VWAP. Days without bars answer an empty list.",
+		Parameters: []openapi.Parameter{
+			symbol,
+			openapi.QueryParam("date", "Trading day, YYYY-MM-DD (defaults to today in market time)", api.DateSchema),
+			openapi.QueryParam("resolution", "Minutes each bar spans (default 1m)", &openapi.Schema{
+				Type: "string",
+				Enum: []any{models.IntradayResolution1m, models.IntradayResolution5m, models.IntradayResolution15m},
+			}),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(models.IntradayBars{}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/vwap", &openapi.Operation{
+		Tags:    []string{"Daily bars"},
+		Summary: "Get a ticker's anchored intraday VWAP",
+		Parameters: []openapi.Parameter{
+			symbol,
+			openapi.QueryParam("anchor", "Day the VWAP is anchored to, YYYY-MM-DD (defaults to today)", api.DateSchema),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(models.VWAPSeries{}), http.StatusBadRequest),
+	})
+
+	actionTags := []string{"Corporate actions"}
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/splits", &openapi.Operation{
+		Tags:       actionTags,
+		Summary:    "List a ticker's stock splits, oldest first",
+		Parameters: []openapi.Parameter{symbol},
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker": {Type: "string"},
+			"splits": {Type: "array", Items: doc.Schema(models.Split{})},
+			"count":  {Type: "integer"},
+		}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol/dividends", &openapi.Operation{
+		Tags:        actionTags,
+		Summary:     "List a ticker's cash dividends, oldest first",
+		Description: "Includes announced dividends whose ex-dividend date is yet to come.",
+		Parameters:  []openapi.Parameter{symbol},
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ticker":    {Type: "string"},
+			"dividends": {Type: "array", Items: doc.Schema(models.Dividend{})},
+			"count":     {Type: "integer"},
+		}), http.StatusBadRequest),
+	})
+	doc.Add(http.MethodPost, "/api/v1/admin/corporate-actions", &openapi.Operation{
+		Tags:        actionTags,
+		Summary:     "Ingest a batch of splits and dividends",
+		Description: "Timestamps are midnight UTC of the execution or ex-dividend date. Re-ingesting an action of the same ticker, kind and date replaces it.",
+		RequestBody: openapi.JSONBody(doc.Inline(ingestCorporateActionsRequest{})),
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"ingested": {Type: "integer"},
+		}), http.StatusBadRequest),
+	})
+}
@@ -0,0 +1,76 @@
+package summaries
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPriceSymbols caps the symbols of one prices request, each of which is a
+// query of its own
+const maxPriceSymbols = 100
+
+// GetPrices returns the latest close and daily change of up to
+// maxPriceSymbols symbols, so dashboards need one request instead of one per
+// symbol
+func (h *Handler) GetPrices(c *gin.Context) {
+	symbols, err := parseSymbolList(c.Query("symbols"))
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	quotes, errs := service.LatestQuotes(c.Request.Context(), h.dailySummaryService, symbols)
+
+	found := make([]models.Quote, 0, len(symbols))
+	missing := make([]string, 0)
+	for i, symbol := range symbols {
+		switch {
+		case errs[i] == nil:
+			found = append(found, *quotes[i])
+		case errors.Is(errs[i], service.ErrTickerNotFound):
+			missing = append(missing, symbol)
+		default:
+			api.Logger(c, h.log).Errorw("failed to get quote", "symbol", symbol, "error", errs[i])
+			problem.Respond(c, problem.Internal, "Failed to retrieve prices")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"quotes":  found,
+		"missing": missing,
+		"count":   len(found),
+	})
+}
+
+// parseSymbolList splits a comma-separated list of symbols, normalizing them
+// and dropping duplicates in order
+func parseSymbolList(value string) ([]string, error) {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, part := range strings.Split(value, ",") {
+		symbol := api.NormalizeSymbol(part)
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("symbols is required, e.g. symbols=AAPL,MSFT")
+	}
+	if len(symbols) > maxPriceSymbols {
+		return nil, fmt.Errorf("at most %d symbols may be requested at once, got %d", maxPriceSymbols, len(symbols))
+	}
+	return symbols, nil
+}
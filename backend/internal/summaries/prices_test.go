@@ -0,0 +1,35 @@
+package summaries
+
+import (
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/api"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FuzzParseSymbolList checks that any symbols query value either fails or
+// yields a bounded list of distinct, normalized symbols
+func FuzzParseSymbolList(f *testing.F) {
+	for _, seed := range []string{"AAPL,MSFT", " aapl , AAPL ,msft", ",,,", "", strings.Repeat("A,", maxPriceSymbols+1), "X:BTCUSD,\xff"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		symbols, err := parseSymbolList(value)
+		if err != nil {
+			assert.Empty(t, symbols)
+			return
+		}
+		assert.NotEmpty(t, symbols)
+		assert.LessOrEqual(t, len(symbols), maxPriceSymbols)
+		seen := make(map[string]bool)
+		for _, symbol := range symbols {
+			assert.NotEmpty(t, symbol)
+			assert.Equal(t, api.NormalizeSymbol(symbol), symbol)
+			assert.False(t, seen[symbol], "duplicate symbol %q", symbol)
+			seen[symbol] = true
+		}
+	})
+}
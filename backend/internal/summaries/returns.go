@@ -0,0 +1,62 @@
+package summaries
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTickerReturns measures a ticker's returns over the date range, session by
+// session, with their total, annualized return and volatility. Bars are
+// adjusted for splits and dividends unless ?adjusted=false.
+func (h *Handler) GetTickerReturns(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	adjusted := true
+	if c.Query("adjusted") != "" {
+		if adjusted, err = api.ParseBoolQuery(c, "adjusted"); err != nil {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+	}
+	actions := h.corporateActionService
+	if !adjusted {
+		actions = nil
+	}
+
+	t := models.ReturnType(strings.ToLower(c.DefaultQuery("type", string(models.ReturnSimple))))
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	points, summary, err := service.Returns(c.Request.Context(), h.dailySummaryService, actions, symbol, t, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidReturnType) {
+			problem.Respond(c, problem.ValidationFailed, err.Error())
+			return
+		}
+		h.respondDailySummaryError(c, symbol, err)
+		return
+	}
+
+	body := gin.H{
+		"ticker":   symbol,
+		"type":     t,
+		"adjusted": adjusted,
+		"points":   points,
+		"count":    len(points),
+	}
+	// A range without bars has no summary
+	if summary != nil {
+		body["summary"] = summary
+	}
+	c.JSON(http.StatusOK, body)
+}
@@ -0,0 +1,163 @@
+package summaries
+
+import (
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net/http"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"profitify-backend/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_GetTickerReturns(t *testing.T) {
+	// A 1:2 split on 2024-01-04 halves the closes recorded after it
+	bars := []models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 1704240000, Close: 200},
+		{Ticker: "AAPL", Timestamp: 1704326400, Close: 110},
+		{Ticker: "AAPL", Timestamp: 1704412800, Close: 99},
+	}
+	summaries := new(MockDailySummaryService)
+	actions := new(MockCorporateActionService)
+	actions.On("Adjustments", mock.Anything, "AAPL", int64(1704240000)).Return(models.NewAdjustments([]models.Adjustment{
+		models.SplitAdjustment(models.Split{Timestamp: 1704326400, SplitFrom: 1, SplitTo: 2}),
+	}), nil)
+	r := newCorporateActionRouter(summaries, actions)
+
+	type response struct {
+		Type     string                `json:"type"`
+		Adjusted bool                  `json:"adjusted"`
+		Points   []models.ReturnPoint  `json:"points"`
+		Count    int                   `json:"count"`
+		Summary  *models.ReturnSummary `json:"summary"`
+	}
+	get := func(query string) response {
+		// Each request adjusts its own copy of the bars
+		summaries.On("GetDailySummaries", mock.Anything, "AAPL", int64(1704240000), int64(1704499199)).
+			Return(append([]models.DailySummary(nil), bars...), nil).Once()
+		w := serveRequest(r, http.MethodGet, "/api/tickers/aapl/returns?from=2024-01-03&to=2024-01-05&"+query, "")
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+	values := func(points []models.ReturnPoint) []float64 {
+		out := make([]float64, len(points))
+		for i, p := range points {
+			out[i] = p.Value
+		}
+		return out
+	}
+
+	simple := get("")
+	assert.Equal(t, "simple", simple.Type)
+	assert.True(t, simple.Adjusted, "returns are adjusted by default")
+	require.Equal(t, 2, simple.Count)
+	assert.Equal(t, "2024-01-04", simple.Points[0].Date)
+	assert.InDeltaSlice(t, []float64{0.1, -0.1}, values(simple.Points), 1e-6, "the split is no loss")
+	require.NotNil(t, simple.Summary)
+	assert.InDelta(t, -0.01, simple.Summary.TotalReturn, 1e-6)
+	assert.InDelta(t, math.Pow(0.99, 126)-1, simple.Summary.AnnualizedReturn, 1e-6)
+	require.NotNil(t, simple.Summary.Volatility)
+	assert.InDelta(t, math.Abs(math.Log(1.1)-math.Log(0.9))/math.Sqrt2*math.Sqrt(252), *simple.Summary.Volatility, 1e-5)
+	assert.Equal(t, 2, simple.Summary.Sessions)
+
+	assert.InDeltaSlice(t, []float64{math.Log(1.1), math.Log(0.9)}, values(get("type=LOG").Points), 1e-6)
+	assert.InDeltaSlice(t, []float64{0.1, -0.01}, values(get("type=cumulative").Points), 1e-6)
+
+	unadjusted := get("adjusted=false")
+	assert.False(t, unadjusted.Adjusted)
+	assert.InDelta(t, -0.45, unadjusted.Points[0].Value, 1e-6)
+	actions.AssertNumberOfCalls(t, "Adjustments", 3)
+
+	w := serveRequest(r, http.MethodGet, "/api/tickers/aapl/returns?type=excess", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = serveRequest(r, http.MethodGet, "/api/tickers/aapl/returns?adjusted=maybe", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+// barSeries is a random walk of daily closes, generated for property tests
+type barSeries []models.DailySummary
+
+func (barSeries) Generate(r *rand.Rand, size int) reflect.Value {
+	bars := make(barSeries, 2+r.Intn(250))
+	price := 1 + r.Float64()*500
+	for i := range bars {
+		price *= 1 + (r.Float64()-0.5)/10
+		bars[i] = models.DailySummary{Ticker: "AAPL", Timestamp: 1704240000 + int64(i)*86400, Close: float32(price)}
+	}
+	return reflect.ValueOf(bars)
+}
+
+func TestComputeReturnsProperties(t *testing.T) {
+	closeTo := func(a, b float64) bool { return math.Abs(a-b) <= 1e-9*(1+math.Abs(a)+math.Abs(b)) }
+
+	t.Run("session returns chain to the total return", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(bars barSeries) bool {
+			simple, summary := models.ComputeReturns(bars, models.ReturnSimple)
+			logs, _ := models.ComputeReturns(bars, models.ReturnLog)
+			cumulative, _ := models.ComputeReturns(bars, models.ReturnCumulative)
+
+			growth, logSum := 1.0, 0.0
+			for i := range simple {
+				growth *= 1 + simple[i].Value
+				logSum += logs[i].Value
+				if !closeTo(growth-1, cumulative[i].Value) {
+					return false
+				}
+			}
+			return summary.Sessions == len(bars)-1 &&
+				closeTo(growth-1, summary.TotalReturn) &&
+				closeTo(math.Exp(logSum)-1, summary.TotalReturn)
+		}, nil))
+	})
+
+	// Like a time-weighted return, the total does not depend on where the
+	// range is cut into periods
+	t.Run("periods chain to the whole range", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(bars barSeries, cut uint8) bool {
+			i := 1 + int(cut)%(len(bars)-1)
+			_, whole := models.ComputeReturns(bars, models.ReturnSimple)
+			_, first := models.ComputeReturns(bars[:i+1], models.ReturnSimple)
+			_, second := models.ComputeReturns(bars[i:], models.ReturnSimple)
+			if second == nil {
+				// Cut at the last session
+				return closeTo(first.TotalReturn, whole.TotalReturn)
+			}
+			return closeTo((1+first.TotalReturn)*(1+second.TotalReturn)-1, whole.TotalReturn)
+		}, nil))
+	})
+
+	// Closes recorded in pre-split shares before a split and adjusted for it
+	// return what the unsplit closes do
+	t.Run("split adjusted closes", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(bars barSeries, at uint8, to uint8) bool {
+			split := models.Split{Ticker: "AAPL", Timestamp: bars[int(at)%len(bars)].Timestamp, SplitFrom: 1, SplitTo: float64(2 + to%9)}
+			adjustments := models.NewAdjustments([]models.Adjustment{models.SplitAdjustment(split)})
+			recorded := make([]models.DailySummary, len(bars))
+			for i, bar := range bars {
+				if bar.Timestamp >= split.Timestamp {
+					bar.Close = float32(float64(bar.Close) / split.SplitTo)
+				}
+				recorded[i] = adjustments.Apply(bar)
+			}
+
+			want, _ := models.ComputeReturns(bars, models.ReturnSimple)
+			got, _ := models.ComputeReturns(recorded, models.ReturnSimple)
+			for i := range want {
+				// Closes are float32, so they round differently once divided
+				if math.Abs(got[i].Value-want[i].Value) > 1e-5 {
+					t.Logf("session %d returned %g, want %g", i, got[i].Value, want[i].Value)
+					return false
+				}
+			}
+			return true
+		}, nil))
+	})
+}
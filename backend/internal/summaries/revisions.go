@@ -0,0 +1,34 @@
+package summaries
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTickerRevisions lists the corrections of a ticker's daily bars dated in
+// the range: the old and new values of each revised bar, which fields
+// changed, and which writer revised it
+func (h *Handler) GetTickerRevisions(c *gin.Context) {
+	from, to, err := api.ParseDateRange(c)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+	revisions, err := h.summaryRevisionService.GetRevisions(c.Request.Context(), symbol, from, to)
+	if err != nil {
+		h.respondDailySummaryError(c, symbol, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"ticker":    symbol,
+		"revisions": revisions,
+		"count":     len(revisions),
+	})
+}
@@ -0,0 +1,80 @@
+package tickers
+
+import (
+	"context"
+	"errors"
+
+	profitifyv1 "profitify-backend/api/proto/profitify/v1"
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RegisterGRPC serves the ticker service over gRPC
+func (h *Handler) RegisterGRPC(s grpc.ServiceRegistrar) {
+	profitifyv1.RegisterTickerServiceServer(s, tickerServer{h: h})
+}
+
+// tickerServer serves the tickers routes' reads over gRPC
+type tickerServer struct {
+	profitifyv1.UnimplementedTickerServiceServer
+	h *Handler
+}
+
+func (s tickerServer) GetTicker(ctx context.Context, req *profitifyv1.GetTickerRequest) (*profitifyv1.Ticker, error) {
+	symbol := api.NormalizeSymbol(req.GetSymbol())
+
+	ticker, err := s.h.tickerService.GetTicker(ctx, symbol)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTickerNotFound):
+			return nil, status.Error(codes.NotFound, "ticker not found")
+		case errors.Is(err, service.ErrInvalidTicker):
+			return nil, status.Error(codes.InvalidArgument, "invalid ticker symbol")
+		default:
+			logger.FromContext(ctx, s.h.log).Errorw("failed to get ticker", "symbol", symbol, "error", err)
+			return nil, status.Error(codes.Internal, "failed to retrieve ticker")
+		}
+	}
+
+	return tickerMessage(ticker), nil
+}
+
+func (s tickerServer) ListActiveTickers(ctx context.Context, _ *profitifyv1.ListActiveTickersRequest) (*profitifyv1.ListActiveTickersResponse, error) {
+	tickers, err := s.h.tickerService.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.h.log).Errorw("failed to get tickers", "error", err)
+		return nil, status.Error(codes.Internal, "failed to retrieve tickers")
+	}
+
+	resp := &profitifyv1.ListActiveTickersResponse{Tickers: make([]*profitifyv1.Ticker, len(tickers))}
+	for i := range tickers {
+		resp.Tickers[i] = tickerMessage(&tickers[i])
+	}
+	return resp, nil
+}
+
+func tickerMessage(t *models.Ticker) *profitifyv1.Ticker {
+	return &profitifyv1.Ticker{
+		Ticker:          t.Ticker,
+		Name:            t.Name,
+		Market:          t.Market,
+		Locale:          t.Locale,
+		PrimaryExchange: t.PrimaryExchange,
+		ShareClassFigi:  t.ShareClassFigi,
+		Type:            t.Type,
+		Sector:          t.Sector,
+		Industry:        t.Industry,
+		Active:          t.Active == 1,
+		Cik:             t.Cik,
+		CompositeFigi:   t.CompositeFigi,
+		Currency:        t.Currency,
+		DelistedUtc:     t.DelistedUTC,
+		LastUpdatedUtc:  t.LastUpdatedUTC,
+	}
+}
@@ -0,0 +1,63 @@
+package tickers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	profitifyv1 "profitify-backend/api/proto/profitify/v1"
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTickerServer_GetTicker(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockTickerService)
+	server := tickerServer{h: NewHandler(svc, nil, api.ResponseLimits{}, zap.NewNop().Sugar())}
+
+	svc.On("GetTicker", mock.Anything, "AAPL").Return(&models.Ticker{
+		Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", Active: 1, LastUpdatedUTC: 1741323600,
+	}, nil)
+	svc.On("GetTicker", mock.Anything, "NOPE").Return(nil, service.ErrTickerNotFound)
+	svc.On("GetTicker", mock.Anything, "").Return(nil, service.ErrInvalidTicker)
+	svc.On("GetTicker", mock.Anything, "MSFT").Return(nil, errors.New("table unavailable"))
+
+	ticker, err := server.GetTicker(ctx, &profitifyv1.GetTickerRequest{Symbol: " aapl "})
+	require.NoError(t, err)
+	assert.Equal(t, "AAPL", ticker.GetTicker())
+	assert.Equal(t, "Apple Inc.", ticker.GetName())
+	assert.True(t, ticker.GetActive())
+	assert.Equal(t, int64(1741323600), ticker.GetLastUpdatedUtc())
+
+	for symbol, code := range map[string]codes.Code{"nope": codes.NotFound, "": codes.InvalidArgument, "msft": codes.Internal} {
+		_, err := server.GetTicker(ctx, &profitifyv1.GetTickerRequest{Symbol: symbol})
+		assert.Equal(t, code, status.Code(err), symbol)
+	}
+}
+
+func TestTickerServer_ListActiveTickers(t *testing.T) {
+	ctx := context.Background()
+	svc := new(MockTickerService)
+	server := tickerServer{h: NewHandler(svc, nil, api.ResponseLimits{}, zap.NewNop().Sugar())}
+
+	svc.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{
+		{Ticker: "AAPL", Active: 1},
+		{Ticker: "MSFT", Active: 1},
+	}, nil).Once()
+	resp, err := server.ListActiveTickers(ctx, &profitifyv1.ListActiveTickersRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.GetTickers(), 2)
+	assert.Equal(t, "MSFT", resp.GetTickers()[1].GetTicker())
+
+	svc.On("GetActiveTickers", mock.Anything).Return(nil, errors.New("table unavailable")).Once()
+	_, err = server.ListActiveTickers(ctx, &profitifyv1.ListActiveTickersRequest{})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
@@ -0,0 +1,119 @@
+// Package tickers serves the ticker reference data and its admin maintenance.
+package tickers
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	tickerService service.TickerService
+	syncService   SyncService
+	limits        api.ResponseLimits
+	log           *zap.SugaredLogger
+}
+
+func NewHandler(tickers service.TickerService, sync SyncService, limits api.ResponseLimits, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		tickerService: tickers,
+		syncService:   sync,
+		limits:        limits,
+		log:           log,
+	}
+}
+
+// Wire builds the tickers module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	repo := deps.TickerRepository()
+	return NewHandler(
+		service.NewTickerService(repo, deps.Events, deps.Log),
+		NewSyncService(repo, deps.TickerChangeRepository(), deps.Config.TickerChangeRetention, deps.Log),
+		api.LimitsFromConfig(deps.Config),
+		deps.Log,
+	)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	tickers := api.Group("/tickers", middleware.RequireScope(models.ScopeReadMarket))
+	tickers.GET("", h.GetAllTickers)
+	tickers.GET("/:symbol", h.GetTicker)
+	api.GET("/sync/tickers", middleware.RequireScope(models.ScopeReadMarket), h.SyncTickers)
+
+	admin.POST("/tickers", h.CreateTicker)
+	admin.PUT("/tickers/:symbol", h.UpdateTicker)
+	admin.DELETE("/tickers/:symbol", h.DeleteTicker)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	symbol := openapi.PathParam("symbol", "Ticker symbol, case insensitive")
+
+	doc.Add(http.MethodGet, "/api/v1/tickers", &openapi.Operation{
+		Tags:    []string{"Tickers"},
+		Summary: "List the active tickers",
+		Description: "Tickers are sorted by symbol. A JSON list longer than RESPONSE_MAX_ITEMS or RESPONSE_MAX_BYTES is cut " +
+			"with a Warning header and a nextCursor to continue from, or answers 413 with RESPONSE_OVERSIZE=reject; CSV is streamed whole. " +
+			"Responses carry a weak ETag; send it back as If-None-Match to get 304 while the list is unchanged.",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("exchange", "Only the tickers whose primary exchange is this MIC, such as XNAS", nil),
+			openapi.QueryParam("market", "Only the tickers of this market, such as stocks or crypto", nil),
+			api.CursorParam(),
+			api.FieldsParam(tickerFields),
+			api.FormatParam(),
+			api.IfNoneMatchParam(),
+		},
+		Responses: api.WithNotModified(api.WithCSV(api.Responses(http.StatusOK, api.Paged(api.List(doc, "tickers", models.Ticker{})),
+			http.StatusBadRequest, http.StatusRequestEntityTooLarge),
+			http.StatusOK, "Tickers as CSV with a header row, one ticker per line")),
+	})
+	doc.Add(http.MethodGet, "/api/v1/tickers/:symbol", &openapi.Operation{
+		Tags:       []string{"Tickers"},
+		Summary:    "Get a ticker",
+		Parameters: []openapi.Parameter{symbol},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(models.Ticker{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/sync/tickers", &openapi.Operation{
+		Tags:    []string{"Tickers"},
+		Summary: "Sync the tickers changed since a cursor",
+		Description: "Without since, every active ticker is returned with full set, replacing the client's copy. " +
+			"Otherwise the tickers created or updated since the cursor are returned, and the symbols deleted or deactivated " +
+			"are removed. Pass the returned cursor as since next time; while hasMore is set, more changes follow it right away. " +
+			"Changes are kept for TICKER_CHANGE_RETENTION; an older cursor answers 410 and the client syncs from scratch.",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("since", "Cursor of the previous sync", nil),
+		},
+		Responses: api.Responses(http.StatusOK, doc.Schema(models.TickerSync{}), http.StatusBadRequest, http.StatusGone),
+	})
+
+	admin := []string{"Admin"}
+	doc.Add(http.MethodPost, "/api/v1/admin/tickers", &openapi.Operation{
+		Tags:        admin,
+		Summary:     "Create a ticker",
+		Description: "The symbol is upper cased and lastUpdatedUTC set to now. Fails if the symbol already exists.",
+		RequestBody: openapi.JSONBody(doc.Schema(models.Ticker{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(models.Ticker{}), http.StatusBadRequest, http.StatusConflict),
+	})
+	doc.Add(http.MethodPut, "/api/v1/admin/tickers/:symbol", &openapi.Operation{
+		Tags:        admin,
+		Summary:     "Replace a ticker",
+		Description: "The symbol of the path wins over the body's.",
+		Parameters:  []openapi.Parameter{symbol},
+		RequestBody: openapi.JSONBody(doc.Schema(models.Ticker{})),
+		Responses:   api.Responses(http.StatusOK, doc.Schema(models.Ticker{}), http.StatusBadRequest, http.StatusNotFound),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/admin/tickers/:symbol", &openapi.Operation{
+		Tags:        admin,
+		Summary:     "Delete a ticker",
+		Description: "Its daily summaries are kept; purge the ticker to remove them as well.",
+		Parameters:  []openapi.Parameter{symbol},
+		Responses:   api.Responses(http.StatusNoContent, nil, http.StatusBadRequest, http.StatusNotFound),
+	})
+}
@@ -0,0 +1,31 @@
+package tickers
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SyncTickers returns the tickers changed since the since cursor, or all
+// active tickers without one
+func (h *Handler) SyncTickers(c *gin.Context) {
+	page, err := h.syncService.Sync(c.Request.Context(), c.Query("since"))
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidCursor):
+			problem.Respond(c, problem.ValidationFailed, "Invalid since cursor")
+		case errors.Is(err, ErrCursorExpired):
+			problem.Respond(c, problem.SyncCursorExpired, "The since cursor expired; sync again without it")
+		default:
+			api.Logger(c, h.log).Errorw("failed to sync tickers", "error", err)
+			problem.Respond(c, problem.Internal, "Failed to sync tickers")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,142 @@
+package tickers
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// syncPageSize bounds the changes read for one sync response
+	syncPageSize = 1000
+	// syncSettle holds back the changes of the last seconds. Writers stamp a
+	// change before storing it, so one stamped earlier may land after a later
+	// one was synced; waiting this long lets it land before the cursor passes.
+	syncSettle = 5 * time.Second
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid sync cursor")
+	// ErrCursorExpired rejects cursors older than the change log's retention,
+	// whose changes may have expired; the client syncs from scratch instead
+	ErrCursorExpired = errors.New("sync cursor expired")
+)
+
+// SyncService serves the changes of the tickers since a client last synced,
+// so mobile clients can keep an offline copy without downloading every ticker
+type SyncService interface {
+	// Sync returns the changes since cursor, or every active ticker when the
+	// cursor is empty
+	Sync(ctx context.Context, cursor string) (*models.TickerSync, error)
+}
+
+type syncService struct {
+	tickers   repository.TickerRepository
+	changes   repository.TickerChangeRepository
+	retention time.Duration
+	log       *zap.SugaredLogger
+	now       func() time.Time
+}
+
+// NewSyncService syncs the tickers of repo from the changes it recorded,
+// which are kept for retention
+func NewSyncService(tickers repository.TickerRepository, changes repository.TickerChangeRepository, retention time.Duration, log *zap.SugaredLogger) SyncService {
+	return &syncService{
+		tickers:   tickers,
+		changes:   changes,
+		retention: retention,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+func (s *syncService) Sync(ctx context.Context, cursor string) (*models.TickerSync, error) {
+	now := s.now()
+	until := repository.TickerChangeSeq(now.Add(-syncSettle), "")
+	if cursor == "" {
+		return s.snapshot(ctx, until)
+	}
+
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	at, _ := repository.TickerChangeTime(after)
+	if at.Before(now.Add(-s.retention)) {
+		return nil, ErrCursorExpired
+	}
+	if after >= until {
+		// The client synced within the settle window; nothing new settled
+		return &models.TickerSync{Tickers: []models.Ticker{}, Removed: []string{}, Cursor: cursor}, nil
+	}
+
+	changes, err := s.changes.After(ctx, after, until, syncPageSize+1)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to read ticker changes", "error", err)
+		return nil, fmt.Errorf("failed to read ticker changes: %w", err)
+	}
+
+	page := &models.TickerSync{Tickers: []models.Ticker{}, Removed: []string{}, Cursor: encodeCursor(until)}
+	if len(changes) > syncPageSize {
+		changes = changes[:syncPageSize]
+		page.HasMore = true
+		page.Cursor = encodeCursor(changes[len(changes)-1].Seq)
+	}
+
+	// The latest change of each symbol wins
+	latest := make(map[string]*models.Ticker, len(changes))
+	for _, change := range changes {
+		latest[change.Symbol] = change.Ticker
+	}
+	for symbol, ticker := range latest {
+		if ticker == nil || ticker.Active != 1 {
+			page.Removed = append(page.Removed, symbol)
+			continue
+		}
+		page.Tickers = append(page.Tickers, *ticker)
+	}
+	sort.Strings(page.Removed)
+	sort.Slice(page.Tickers, func(i, j int) bool { return page.Tickers[i].Ticker < page.Tickers[j].Ticker })
+
+	logger.FromContext(ctx, s.log).Debugw("synced ticker changes", "changes", len(changes), "tickers", len(page.Tickers), "removed", len(page.Removed))
+	return page, nil
+}
+
+// snapshot returns every active ticker with a cursor at until. Changes after
+// until that the snapshot already holds are synced again, which clients
+// apply as no-ops.
+func (s *syncService) snapshot(ctx context.Context, until string) (*models.TickerSync, error) {
+	tickers, err := s.tickers.GetActiveTickers(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to get active tickers", "error", err)
+		return nil, fmt.Errorf("failed to get active tickers: %w", err)
+	}
+	if tickers == nil {
+		tickers = []models.Ticker{}
+	}
+	return &models.TickerSync{Full: true, Tickers: tickers, Removed: []string{}, Cursor: encodeCursor(until)}, nil
+}
+
+// encodeCursor makes a change seq an opaque cursor
+func encodeCursor(seq string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(seq))
+}
+
+func decodeCursor(cursor string) (string, error) {
+	seq, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	if _, err := repository.TickerChangeTime(string(seq)); err != nil {
+		return "", ErrInvalidCursor
+	}
+	return string(seq), nil
+}
@@ -0,0 +1,150 @@
+package tickers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func syncTicker(symbol string, active int32) *models.Ticker {
+	return &models.Ticker{Ticker: symbol, Name: symbol, Market: "stocks", Locale: "us", Active: active}
+}
+
+// newSyncFixture returns a change-logging ticker repository and a sync
+// service over it whose clock is set by the returned function
+func newSyncFixture() (repository.TickerRepository, *syncService, func(time.Time)) {
+	changes := repository.NewMemoryTickerChangeRepository(24 * time.Hour)
+	logged := repository.NewChangeLoggingTickerRepository(repository.NewMemoryTickerRepository(nil), changes)
+	svc := NewSyncService(logged, changes, 24*time.Hour, zap.NewNop().Sugar()).(*syncService)
+	return logged, svc, func(now time.Time) { svc.now = func() time.Time { return now } }
+}
+
+func TestSyncService_Sync(t *testing.T) {
+	ctx := context.Background()
+	repo, svc, setNow := newSyncFixture()
+
+	require.NoError(t, repo.PutTicker(ctx, syncTicker("AAPL", 1)))
+	require.NoError(t, repo.PutTicker(ctx, syncTicker("MSFT", 1)))
+	setNow(time.Now().Add(syncSettle))
+
+	full, err := svc.Sync(ctx, "")
+	require.NoError(t, err)
+	assert.True(t, full.Full)
+	assert.Len(t, full.Tickers, 2)
+	assert.Empty(t, full.Removed)
+
+	nothing, err := svc.Sync(ctx, full.Cursor)
+	require.NoError(t, err)
+	assert.False(t, nothing.Full)
+	assert.Empty(t, nothing.Tickers)
+	assert.Empty(t, nothing.Removed)
+
+	updated := syncTicker("AAPL", 1)
+	updated.Sector = "Technology"
+	require.NoError(t, repo.UpdateTicker(ctx, updated))
+	require.NoError(t, repo.PutTicker(ctx, syncTicker("NVDA", 1)))
+	require.NoError(t, repo.DeleteTicker(ctx, "NVDA"))
+	require.NoError(t, repo.PutTickers(ctx, []models.Ticker{*syncTicker("MSFT", 0), *syncTicker("TSLA", 1)}))
+
+	unsettled, err := svc.Sync(ctx, nothing.Cursor)
+	require.NoError(t, err)
+	assert.Empty(t, unsettled.Tickers, "changes within the settle window are held back")
+	assert.Equal(t, nothing.Cursor, unsettled.Cursor)
+
+	setNow(time.Now().Add(syncSettle))
+	delta, err := svc.Sync(ctx, nothing.Cursor)
+	require.NoError(t, err)
+	assert.False(t, delta.HasMore)
+	require.Len(t, delta.Tickers, 2)
+	assert.Equal(t, "AAPL", delta.Tickers[0].Ticker)
+	assert.Equal(t, "Technology", delta.Tickers[0].Sector)
+	assert.Equal(t, "TSLA", delta.Tickers[1].Ticker)
+	assert.Equal(t, []string{"MSFT", "NVDA"}, delta.Removed, "deleted and deactivated tickers are removed")
+
+	again, err := svc.Sync(ctx, delta.Cursor)
+	require.NoError(t, err)
+	assert.Empty(t, again.Tickers)
+	assert.Empty(t, again.Removed)
+}
+
+func TestSyncService_Pages(t *testing.T) {
+	ctx := context.Background()
+	repo, svc, setNow := newSyncFixture()
+	setNow(time.Now())
+	start, err := svc.Sync(ctx, "")
+	require.NoError(t, err)
+
+	tickers := make([]models.Ticker, syncPageSize+1)
+	for i := range tickers {
+		tickers[i] = *syncTicker(string(rune('A'+i/26/26))+string(rune('A'+i/26%26))+string(rune('A'+i%26)), 1)
+	}
+	require.NoError(t, repo.PutTickers(ctx, tickers))
+	setNow(time.Now().Add(syncSettle))
+
+	first, err := svc.Sync(ctx, start.Cursor)
+	require.NoError(t, err)
+	assert.True(t, first.HasMore)
+	assert.Len(t, first.Tickers, syncPageSize)
+
+	second, err := svc.Sync(ctx, first.Cursor)
+	require.NoError(t, err)
+	assert.False(t, second.HasMore)
+	require.Len(t, second.Tickers, 1)
+	assert.Equal(t, tickers[syncPageSize].Ticker, second.Tickers[0].Ticker)
+}
+
+func TestSyncService_Cursors(t *testing.T) {
+	ctx := context.Background()
+	_, svc, setNow := newSyncFixture()
+
+	_, err := svc.Sync(ctx, "not a cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = svc.Sync(ctx, encodeCursor("AAPL"))
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	now := time.Now()
+	setNow(now)
+	_, err = svc.Sync(ctx, encodeCursor(repository.TickerChangeSeq(now.Add(-25*time.Hour), "")))
+	assert.ErrorIs(t, err, ErrCursorExpired, "changes past the retention may have expired")
+}
+
+func TestHandler_SyncTickers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	repo, svc, setNow := newSyncFixture()
+	require.NoError(t, repo.PutTicker(ctx, syncTicker("AAPL", 1)))
+	setNow(time.Now().Add(syncSettle))
+
+	r := gin.New()
+	NewHandler(new(MockTickerService), svc, api.ResponseLimits{}, zap.NewNop().Sugar()).RegisterRoutes(r.Group("/api"), r.Group("/api/admin"))
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/sync/tickers"+query, nil))
+		return w
+	}
+
+	w := get("")
+	require.Equal(t, http.StatusOK, w.Code)
+	var page models.TickerSync
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	assert.True(t, page.Full)
+	assert.Len(t, page.Tickers, 1)
+	assert.NotEmpty(t, page.Cursor)
+
+	assert.Equal(t, http.StatusOK, get("?since="+page.Cursor).Code)
+	assert.Equal(t, http.StatusBadRequest, get("?since=bogus").Code)
+	expired := encodeCursor(repository.TickerChangeSeq(time.Now().Add(-48*time.Hour), ""))
+	assert.Equal(t, http.StatusGone, get("?since="+expired).Code)
+}
@@ -0,0 +1,223 @@
+package tickers
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAllTickers lists the active tickers, or those of the exchange or market
+// query parameters, each read from its own index. The list is sorted by
+// symbol and starts at ?cursor=, the symbol a cut page continues from.
+// ?fields= selects the fields of the tickers in JSON.
+func (h *Handler) GetAllTickers(c *gin.Context) {
+	fields, err := api.ParseFields(c, tickerFields)
+	if err != nil {
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+		return
+	}
+	exchange := strings.ToUpper(strings.TrimSpace(c.Query("exchange")))
+	market := strings.ToLower(strings.TrimSpace(c.Query("market")))
+	api.Logger(c, h.log).Infow("Getting tickers", "exchange", exchange, "market", market)
+
+	var tickers []models.Ticker
+	switch {
+	case exchange != "":
+		tickers, err = h.tickerService.GetTickersByExchange(c.Request.Context(), exchange)
+		if market != "" {
+			// An exchange lists far fewer tickers than a market
+			tickers = slices.DeleteFunc(tickers, func(t models.Ticker) bool { return t.Market != market })
+		}
+	case market != "":
+		tickers, err = h.tickerService.GetTickersByMarket(c.Request.Context(), market)
+	default:
+		tickers, err = h.tickerService.GetActiveTickers(c.Request.Context())
+	}
+
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to get tickers", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve tickers")
+		return
+	}
+
+	api.Logger(c, h.log).Infow("retrieved tickers", "count", len(tickers))
+
+	// The lists may be shared with the cache, so they are sorted as copies
+	tickers = slices.Clone(tickers)
+	slices.SortFunc(tickers, func(a, b models.Ticker) int { return strings.Compare(a.Ticker, b.Ticker) })
+	if cursor := api.NormalizeSymbol(c.Query("cursor")); cursor != "" {
+		start, _ := slices.BinarySearchFunc(tickers, cursor, func(t models.Ticker, symbol string) int {
+			return strings.Compare(t.Ticker, symbol)
+		})
+		tickers = tickers[start:]
+	}
+
+	csv := api.WantsCSV(c)
+	if api.NotModifiedETag(c, tickersETag(tickers, csv, fields)) {
+		return
+	}
+	if csv {
+		h.writeTickersCSV(c, tickers)
+		return
+	}
+
+	tickers, next, ok := api.LimitPage(c, h.limits, tickers, func(t models.Ticker) string { return t.Ticker })
+	if !ok {
+		return
+	}
+	selected, err := api.Select(fields, tickers)
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to select ticker fields", "fields", fields.String(), "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve tickers")
+		return
+	}
+	body := gin.H{
+		"tickers": selected,
+		"count":   len(tickers),
+	}
+	if next != "" {
+		body["nextCursor"] = next
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// tickerFields are the fields ?fields= selects from
+var tickerFields = api.JSONFields(models.Ticker{})
+
+// tickersETag is a weak ETag of a ticker list in CSV, or in JSON with the
+// selected fields. Every write of a ticker sets its update time, so hashing
+// the symbols and update times detects changes without encoding the list.
+func tickersETag(tickers []models.Ticker, csv bool, fields api.Fields) string {
+	h := fnv.New64a()
+	if csv {
+		_, _ = h.Write([]byte("csv\n"))
+	} else if !fields.All() {
+		_, _ = h.Write([]byte("fields=" + fields.String() + "\n"))
+	}
+	var buf []byte
+	for i := range tickers {
+		buf = append(buf[:0], tickers[i].Ticker...)
+		buf = append(buf, 0)
+		buf = strconv.AppendInt(buf, tickers[i].LastUpdatedUTC, 10)
+		buf = append(buf, '\n')
+		_, _ = h.Write(buf)
+	}
+	return fmt.Sprintf(`W/"%016x"`, h.Sum64())
+}
+
+func (h *Handler) GetTicker(c *gin.Context) {
+	symbol := api.NormalizeSymbol(c.Param("symbol"))
+
+	ticker, err := h.tickerService.GetTicker(c.Request.Context(), symbol)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrTickerNotFound):
+			problem.Respond(c, problem.TickerNotFound, "Ticker not found")
+		case errors.Is(err, service.ErrInvalidTicker):
+			problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+		default:
+			api.Logger(c, h.log).Errorw("failed to get ticker", "symbol", symbol, "error", err)
+			problem.Respond(c, problem.Internal, "Failed to retrieve ticker")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, ticker)
+}
+
+func (h *Handler) writeTickersCSV(c *gin.Context, tickers []models.Ticker) {
+	w := api.NewCSVWriter(c, "tickers.csv", tickerCSVHeader)
+	for i := range tickers {
+		if err := w.Write(tickerCSVRecord(&tickers[i])); err != nil {
+			api.Logger(c, h.log).Warnw("failed to stream tickers csv", "error", err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		api.Logger(c, h.log).Warnw("failed to stream tickers csv", "error", err)
+	}
+}
+
+// tickerCSVHeader names the columns of a ticker CSV export
+var tickerCSVHeader = []string{
+	"ticker", "name", "market", "locale", "primaryExchange", "type",
+	"sector", "industry", "currency", "cik", "compositeFigi", "shareClassFigi",
+}
+
+func tickerCSVRecord(t *models.Ticker) []string {
+	return []string{
+		t.Ticker, t.Name, t.Market, t.Locale, t.PrimaryExchange, t.Type,
+		t.Sector, t.Industry, t.Currency, t.Cik, t.CompositeFigi, t.ShareClassFigi,
+	}
+}
+
+// CreateTicker adds a ticker to the reference data (admin only)
+func (h *Handler) CreateTicker(c *gin.Context) {
+	var ticker models.Ticker
+	if err := c.ShouldBindJSON(&ticker); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	created, err := h.tickerService.CreateTicker(c.Request.Context(), &ticker)
+	if err != nil {
+		h.respondTickerWriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateTicker replaces a ticker of the reference data (admin only)
+func (h *Handler) UpdateTicker(c *gin.Context) {
+	var ticker models.Ticker
+	if err := c.ShouldBindJSON(&ticker); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	updated, err := h.tickerService.UpdateTicker(c.Request.Context(), api.NormalizeSymbol(c.Param("symbol")), &ticker)
+	if err != nil {
+		h.respondTickerWriteError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteTicker removes a ticker from the reference data (admin only)
+func (h *Handler) DeleteTicker(c *gin.Context) {
+	if err := h.tickerService.DeleteTicker(c.Request.Context(), api.NormalizeSymbol(c.Param("symbol"))); err != nil {
+		h.respondTickerWriteError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) respondTickerWriteError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrTickerNotFound):
+		problem.Respond(c, problem.TickerNotFound, "Ticker not found")
+	case errors.Is(err, service.ErrTickerExists):
+		problem.Respond(c, problem.TickerExists, "Ticker already exists")
+	case errors.Is(err, service.ErrInvalidTicker):
+		problem.Respond(c, problem.ValidationFailed, "Invalid ticker symbol")
+	case errors.Is(err, service.ErrInvalidTickerData):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("ticker write failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to write ticker")
+	}
+}
@@ -0,0 +1,639 @@
+package tickers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// MockTickerService mocks the TickerService interface
+type MockTickerService struct {
+	mock.Mock
+}
+
+func (m *MockTickerService) GetTicker(ctx context.Context, symbol string) (*models.Ticker, error) {
+	args := m.Called(ctx, symbol)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ticker), args.Error(1)
+}
+
+func (m *MockTickerService) GetActiveTickers(ctx context.Context) ([]models.Ticker, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Ticker), args.Error(1)
+}
+
+func (m *MockTickerService) GetTickersByExchange(ctx context.Context, exchange string) ([]models.Ticker, error) {
+	args := m.Called(ctx, exchange)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Ticker), args.Error(1)
+}
+
+func (m *MockTickerService) GetTickersByMarket(ctx context.Context, market string) ([]models.Ticker, error) {
+	args := m.Called(ctx, market)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Ticker), args.Error(1)
+}
+
+func (m *MockTickerService) CreateTicker(ctx context.Context, ticker *models.Ticker) (*models.Ticker, error) {
+	args := m.Called(ctx, ticker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ticker), args.Error(1)
+}
+
+func (m *MockTickerService) UpdateTicker(ctx context.Context, symbol string, ticker *models.Ticker) (*models.Ticker, error) {
+	args := m.Called(ctx, symbol, ticker)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Ticker), args.Error(1)
+}
+
+func (m *MockTickerService) DeleteTicker(ctx context.Context, symbol string) error {
+	return m.Called(ctx, symbol).Error(0)
+}
+
+func TestHandler_GetAllTickers(t *testing.T) {
+	// Set Gin to test mode
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		query          string
+		mockSetup      func(*MockTickerService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+		wantErr        bool
+	}{
+		{
+			name: "successful retrieval with tickers",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{
+					{
+						Ticker: "AAPL",
+						Name:   "Apple Inc.",
+						Active: 1,
+					},
+					{
+						Ticker: "GOOGL",
+						Name:   "Alphabet Inc.",
+						Active: 1,
+					},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"count": float64(2),
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty result",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"count": float64(0),
+			},
+			wantErr: false,
+		},
+		{
+			name:  "by exchange",
+			query: "?exchange=xnas",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTickersByExchange", mock.Anything, "XNAS").Return([]models.Ticker{
+					{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", PrimaryExchange: "XNAS", Active: 1},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"count": float64(1),
+			},
+		},
+		{
+			name:  "by market",
+			query: "?market=Crypto",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTickersByMarket", mock.Anything, "crypto").Return([]models.Ticker{
+					{Ticker: "X:BTCUSD", Name: "Bitcoin", Market: "crypto", Active: 1},
+					{Ticker: "X:ETHUSD", Name: "Ethereum", Market: "crypto", Active: 1},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"count": float64(2),
+			},
+		},
+		{
+			name:  "by exchange and market",
+			query: "?exchange=XNAS&market=otc",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTickersByExchange", mock.Anything, "XNAS").Return([]models.Ticker{
+					{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", PrimaryExchange: "XNAS", Active: 1},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"count": float64(0),
+			},
+		},
+		{
+			name:  "exchange query error",
+			query: "?exchange=XNYS",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTickersByExchange", mock.Anything, "XNYS").Return(nil, errors.New("index missing"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"detail": "Failed to retrieve tickers",
+			},
+			wantErr: true,
+		},
+		{
+			name: "general service error",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetActiveTickers", mock.Anything).Return(
+					([]models.Ticker)(nil),
+					errors.New("database connection error"),
+				)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"detail": "Failed to retrieve tickers",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Create mock service
+			mockService := new(MockTickerService)
+			tt.mockSetup(mockService)
+
+			// Create handler with mock
+			handler := &Handler{
+				tickerService: mockService,
+				log:           zap.NewNop().Sugar(),
+			}
+
+			// Create test HTTP request
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/tickers"+tt.query, nil)
+
+			// Execute handler
+			handler.GetAllTickers(c)
+
+			// Assertions
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			// Parse response body
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+
+			// Check expected fields
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key])
+			}
+
+			// If we expect tickers, verify they exist and have correct structure
+			if !tt.wantErr && tt.expectedStatus == http.StatusOK {
+				tickers, ok := response["tickers"]
+				assert.True(t, ok, "response should contain 'tickers' field")
+				assert.NotNil(t, tickers)
+			}
+
+			// Verify mock expectations
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_GetAllTickersCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockTickerService)
+	mockService.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Locale: "us", PrimaryExchange: "XNAS", Type: "CS", Currency: "usd", Active: 1},
+		{Ticker: "BRK.A", Name: "Berkshire Hathaway, Inc.", Market: "stocks", Locale: "us", Active: 1},
+	}, nil)
+
+	handler := &Handler{
+		tickerService: mockService,
+		log:           zap.NewNop().Sugar(),
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/tickers", nil)
+	c.Request.Header.Set("Accept", "text/csv, application/json;q=0.5")
+
+	handler.GetAllTickers(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="tickers.csv"`, w.Header().Get("Content-Disposition"))
+	assert.Equal(t, "ticker,name,market,locale,primaryExchange,type,sector,industry,currency,cik,compositeFigi,shareClassFigi\n"+
+		"AAPL,Apple Inc.,stocks,us,XNAS,CS,,,usd,,,\n"+
+		"BRK.A,\"Berkshire Hathaway, Inc.\",stocks,us,,,,,,,,\n", w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestHandler_GetAllTickersPages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockTickerService)
+	mockService.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{
+		{Ticker: "MSFT", Active: 1},
+		{Ticker: "AAPL", Active: 1},
+		{Ticker: "NVDA", Active: 1},
+		{Ticker: "GOOGL", Active: 1},
+	}, nil)
+
+	get := func(limits api.ResponseLimits, query string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		handler := &Handler{tickerService: mockService, limits: limits, log: zap.NewNop().Sugar()}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers"+query, nil)
+		handler.GetAllTickers(c)
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return w, response
+	}
+	symbols := func(response map[string]interface{}) []string {
+		var out []string
+		for _, ticker := range response["tickers"].([]interface{}) {
+			out = append(out, ticker.(map[string]interface{})["ticker"].(string))
+		}
+		return out
+	}
+
+	w, response := get(api.ResponseLimits{MaxItems: 3}, "")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"AAPL", "GOOGL", "MSFT"}, symbols(response), "pages are sorted by symbol")
+	assert.Equal(t, "NVDA", response["nextCursor"])
+	assert.Contains(t, w.Header().Get("Warning"), "truncated to 3 of 4")
+
+	w, response = get(api.ResponseLimits{MaxItems: 3}, "?cursor=nvda")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"NVDA"}, symbols(response))
+	assert.NotContains(t, response, "nextCursor")
+	assert.Empty(t, w.Header().Get("Warning"))
+
+	w, response = get(api.ResponseLimits{MaxItems: 3, Reject: true}, "")
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Equal(t, "RESPONSE_TOO_LARGE", response["code"])
+}
+
+func TestHandler_GetAllTickersFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockTickerService)
+	mockService.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{
+		{Ticker: "AAPL", Name: "Apple Inc.", Market: "stocks", Sector: "Technology", Active: 1, LastUpdatedUTC: 1700000000},
+		{Ticker: "BRK.A", Name: "Berkshire Hathaway, Inc.", Market: "stocks", Active: 1, LastUpdatedUTC: 1700000000},
+	}, nil)
+	handler := &Handler{tickerService: mockService, log: zap.NewNop().Sugar()}
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers"+query, nil)
+		handler.GetAllTickers(c)
+		return w
+	}
+
+	w := get("?fields=ticker,%20name,sector")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"count":2,"tickers":[
+		{"ticker":"AAPL","name":"Apple Inc.","sector":"Technology"},
+		{"ticker":"BRK.A","name":"Berkshire Hathaway, Inc."}
+	]}`, w.Body.String(), "empty fields stay out")
+	assert.NotEqual(t, get("").Header().Get("ETag"), w.Header().Get("ETag"), "each fieldset has its own tag")
+
+	w = get("?fields=ticker,close")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `unknown field \"close\"`)
+}
+
+func TestHandler_GetAllTickersETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	listed := []models.Ticker{
+		{Ticker: "AAPL", Active: 1, LastUpdatedUTC: 1700000000},
+		{Ticker: "MSFT", Active: 1, LastUpdatedUTC: 1700000000},
+	}
+	mockService := new(MockTickerService)
+	handler := &Handler{tickerService: mockService, log: zap.NewNop().Sugar()}
+
+	get := func(ifNoneMatch, accept string) *httptest.ResponseRecorder {
+		mockService.On("GetActiveTickers", mock.Anything).Return(slices.Clone(listed), nil).Once()
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers", nil)
+		c.Request.Header.Set("If-None-Match", ifNoneMatch)
+		c.Request.Header.Set("Accept", accept)
+		handler.GetAllTickers(c)
+		return w
+	}
+
+	w := get("", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), etag)
+
+	w = get(`"other", `+strings.TrimPrefix(etag, "W/"), "")
+	assert.Equal(t, http.StatusNotModified, w.Code, "If-None-Match compares weakly")
+	assert.Empty(t, w.Body.String())
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+
+	w = get(etag, "text/csv")
+	assert.Equal(t, http.StatusOK, w.Code, "the CSV representation has its own tag")
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+
+	listed = append(listed[:1:1], models.Ticker{Ticker: "MSFT", Active: 1, LastUpdatedUTC: 1700000001})
+	w = get(etag, "")
+	assert.Equal(t, http.StatusOK, w.Code, "an updated ticker changes the tag")
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+}
+
+func TestHandler_GetTicker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		symbol         string
+		mockSetup      func(*MockTickerService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "successful ticker retrieval",
+			symbol: "AAPL",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTicker", mock.Anything, "AAPL").Return(&models.Ticker{
+					Ticker: "AAPL",
+					Name:   "Apple Inc.",
+					Active: 1,
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"ticker": "AAPL",
+				"name":   "Apple Inc.",
+				"active": float64(1),
+			},
+		},
+		{
+			name:   "ticker not found",
+			symbol: "INVALID",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTicker", mock.Anything, "INVALID").Return(
+					(*models.Ticker)(nil),
+					service.ErrTickerNotFound,
+				)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: map[string]interface{}{
+				"detail": "Ticker not found",
+				"code":   "TICKER_NOT_FOUND",
+				"status": float64(http.StatusNotFound),
+			},
+		},
+		{
+			name:   "symbol is normalized",
+			symbol: " msft ",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTicker", mock.Anything, "MSFT").Return(&models.Ticker{
+					Ticker: "MSFT",
+					Name:   "Microsoft Corporation",
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: map[string]interface{}{
+				"ticker": "MSFT",
+			},
+		},
+		{
+			name:   "service error",
+			symbol: "AAPL",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTicker", mock.Anything, "AAPL").Return(
+					(*models.Ticker)(nil),
+					errors.New("database connection error"),
+				)
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedBody: map[string]interface{}{
+				"detail": "Failed to retrieve ticker",
+			},
+		},
+		{
+			name:   "invalid ticker symbol",
+			symbol: "",
+			mockSetup: func(m *MockTickerService) {
+				m.On("GetTicker", mock.Anything, "").Return(
+					(*models.Ticker)(nil),
+					service.ErrInvalidTicker,
+				)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: map[string]interface{}{
+				"detail": "Invalid ticker symbol",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTickerService)
+			tt.mockSetup(mockService)
+
+			handler := &Handler{
+				tickerService: mockService,
+				log:           zap.NewNop().Sugar(),
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("GET", "/api/tickers/"+url.PathEscape(tt.symbol), nil)
+			c.Params = gin.Params{{Key: "symbol", Value: tt.symbol}}
+
+			handler.GetTicker(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+
+			var response map[string]interface{}
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			for key, expectedValue := range tt.expectedBody {
+				assert.Equal(t, expectedValue, response[key])
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandler_TickerWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		mockSetup      func(*MockTickerService)
+		expectedStatus int
+		expectedBody   map[string]interface{}
+	}{
+		{
+			name:   "create",
+			method: http.MethodPost,
+			path:   "/api/admin/tickers",
+			body:   `{"ticker":"NVDA","name":"NVIDIA Corp","market":"stocks","locale":"us","active":1}`,
+			mockSetup: func(m *MockTickerService) {
+				m.On("CreateTicker", mock.Anything, mock.MatchedBy(func(t *models.Ticker) bool {
+					return t.Ticker == "NVDA" && t.Active == 1
+				})).Return(&models.Ticker{Ticker: "NVDA", Name: "NVIDIA Corp"}, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   map[string]interface{}{"ticker": "NVDA"},
+		},
+		{
+			name:   "create existing",
+			method: http.MethodPost,
+			path:   "/api/admin/tickers",
+			body:   `{"ticker":"AAPL","name":"Apple","market":"stocks","locale":"us"}`,
+			mockSetup: func(m *MockTickerService) {
+				m.On("CreateTicker", mock.Anything, mock.Anything).Return(nil, service.ErrTickerExists)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   map[string]interface{}{"detail": "Ticker already exists", "code": "TICKER_EXISTS"},
+		},
+		{
+			name:           "create malformed",
+			method:         http.MethodPost,
+			path:           "/api/admin/tickers",
+			body:           `{"ticker":`,
+			mockSetup:      func(m *MockTickerService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"detail": "Invalid request body"},
+		},
+		{
+			name:   "update invalid",
+			method: http.MethodPut,
+			path:   "/api/admin/tickers/aapl",
+			body:   `{"name":"Apple"}`,
+			mockSetup: func(m *MockTickerService) {
+				m.On("UpdateTicker", mock.Anything, "AAPL", mock.Anything).
+					Return(nil, fmt.Errorf("%w: market is required", service.ErrInvalidTickerData))
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   map[string]interface{}{"detail": "invalid ticker: market is required"},
+		},
+		{
+			name:   "update missing",
+			method: http.MethodPut,
+			path:   "/api/admin/tickers/nope",
+			body:   `{"name":"Nope","market":"stocks","locale":"us"}`,
+			mockSetup: func(m *MockTickerService) {
+				m.On("UpdateTicker", mock.Anything, "NOPE", mock.Anything).Return(nil, service.ErrTickerNotFound)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   map[string]interface{}{"detail": "Ticker not found"},
+		},
+		{
+			name:   "delete",
+			method: http.MethodDelete,
+			path:   "/api/admin/tickers/aapl",
+			mockSetup: func(m *MockTickerService) {
+				m.On("DeleteTicker", mock.Anything, "AAPL").Return(nil)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockTickerService)
+			tt.mockSetup(mockService)
+
+			r := gin.New()
+			handler := NewHandler(mockService, nil, api.ResponseLimits{}, zap.NewNop().Sugar())
+			handler.RegisterRoutes(r.Group("/api"), r.Group("/api/admin"))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedBody != nil {
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				for key, expectedValue := range tt.expectedBody {
+					assert.Equal(t, expectedValue, response[key])
+				}
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// BenchmarkGetAllTickers benchmarks the GetAllTickers handler
+func BenchmarkGetAllTickers(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	mockService := new(MockTickerService)
+	mockService.On("GetActiveTickers", mock.Anything).Return([]models.Ticker{
+		{
+			Ticker: "AAPL",
+			Name:   "Apple Inc.",
+			Active: 1,
+		},
+	}, nil)
+
+	handler := &Handler{
+		tickerService: mockService,
+		log:           zap.NewNop().Sugar(),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/tickers", nil)
+		handler.GetAllTickers(c)
+	}
+}
@@ -0,0 +1,106 @@
+// Package users serves user accounts: people who register and log in with an
+// email and password, or through Cognito, and authenticate with the JWT they
+// get as an API key of their own.
+package users
+
+import (
+	"net/http"
+	"time"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/jwt"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// tokenIssuer is the issuer of the tokens of local users
+const tokenIssuer = "profitify"
+
+// jwksTimeout bounds the requests fetching Cognito's signing keys
+const jwksTimeout = 10 * time.Second
+
+type Handler struct {
+	// userService is nil when user accounts are disabled
+	userService Service
+	log         *zap.SugaredLogger
+}
+
+func NewHandler(users Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		userService: users,
+		log:         log,
+	}
+}
+
+// Wire builds the users module from the shared dependencies, authenticating
+// users as configured by USER_AUTH
+func Wire(deps app.Deps) *Handler {
+	cfg := deps.Config
+	repo := NewRepository(deps.DB, cfg.UsersTable)
+	keyRepo := deps.APIKeyRepository()
+	keys := service.NewAPIKeyService(keyRepo, deps.Log)
+
+	switch cfg.UserAuth {
+	case ProviderLocal:
+		signer := jwt.NewHMAC([]byte(cfg.JWTSecret), tokenIssuer)
+		return NewHandler(NewLocalService(repo, keys, keyRepo, signer, cfg.UserTokenTTL, deps.Events, deps.Log), deps.Log)
+	case ProviderCognito:
+		verifier := jwt.NewCognito(cfg.AWSRegion, cfg.CognitoUserPoolID, cfg.CognitoClientID, &http.Client{Timeout: jwksTimeout})
+		return NewHandler(NewCognitoService(repo, keys, keyRepo, verifier, deps.Log), deps.Log)
+	default:
+		return NewHandler(nil, deps.Log)
+	}
+}
+
+// Users returns the service resolving user tokens, which authenticates
+// requests, or nil when user accounts are disabled
+func (h *Handler) Users() middleware.UserAuthenticator {
+	if h.userService == nil {
+		return nil
+	}
+	return h.userService
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.GET("/account/user", middleware.RequireFullAccess(), h.GetCurrentUser)
+}
+
+// RegisterPublicRoutes serves registration and login, which are made before
+// the user holds a token
+func (h *Handler) RegisterPublicRoutes(public *gin.RouterGroup) {
+	users := public.Group("/users")
+	users.POST("", h.Register)
+	users.POST("/login", h.Login)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Account"}
+
+	doc.Add(http.MethodGet, "/api/v1/account/user", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Get the user the request was made as",
+		Description: "Requires a user token sent as `Authorization: Bearer <token>`; requests made with an API key or session answer 403.",
+		Responses:   api.Responses(http.StatusOK, doc.Schema(User{}), http.StatusForbidden, http.StatusServiceUnavailable),
+	})
+	doc.Add(http.MethodPost, "/api/v1/public/users", &openapi.Operation{
+		Tags:    tags,
+		Summary: "Register a user with an email and password",
+		Description: "Served without an API key when USER_AUTH=local. The user gets an API key of their own, which owns the " +
+			"watchlists, portfolios and alerts they create, and is logged in: send the token as `Authorization: Bearer <token>` " +
+			"until it expires after USER_TOKEN_TTL, then log in again.",
+		RequestBody: openapi.JSONBody(doc.Inline(credentialsRequest{})),
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(IssuedToken{}), http.StatusBadRequest, http.StatusConflict, http.StatusServiceUnavailable),
+	})
+	doc.Add(http.MethodPost, "/api/v1/public/users/login", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Log a user in for a token",
+		Description: "Served without an API key when USER_AUTH=local. With USER_AUTH=cognito, users log in through the user pool and send its tokens instead.",
+		RequestBody: openapi.JSONBody(doc.Inline(credentialsRequest{})),
+		Responses:   api.Responses(http.StatusOK, doc.Schema(IssuedToken{}), http.StatusUnauthorized, http.StatusServiceUnavailable),
+	})
+}
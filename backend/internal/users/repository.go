@@ -0,0 +1,89 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for user data operations
+type Repository interface {
+	GetUser(ctx context.Context, id string) (*User, error)
+	// CreateUser stores a new user, failing with ErrUserExists if the ID is taken
+	CreateUser(ctx context.Context, user *User) error
+}
+
+// userRepository implements Repository using a DynamoDB table keyed on "id"
+type userRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed user repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &userRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetUser retrieves a single user by ID
+func (r *userRepository) GetUser(ctx context.Context, id string) (*User, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+
+	var user User
+	if err := attributevalue.UnmarshalMap(result.Item, &user); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user: %w", err)
+	}
+
+	return &user, nil
+}
+
+// CreateUser stores a new user, failing if its ID is taken
+func (r *userRepository) CreateUser(ctx context.Context, user *User) error {
+	item, err := attributevalue.MarshalMap(user)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user: %w", err)
+	}
+
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeNotExists(expression.Name("id"))).
+		Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.tableName),
+		Item:                     item,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrUserExists, user.ID)
+		}
+		return fmt.Errorf("failed to put user %s: %w", user.ID, err)
+	}
+
+	return nil
+}
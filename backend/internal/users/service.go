@@ -0,0 +1,286 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/jwt"
+	"profitify-backend/pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrUserExists   = errors.New("user already exists")
+	ErrInvalidUser  = errors.New("invalid user")
+	// ErrInvalidCredentials rejects logins with an unknown email or a wrong
+	// password, without telling which
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	// ErrUsersDisabled rejects user requests when USER_AUTH is none
+	ErrUsersDisabled = errors.New("user accounts are disabled")
+	// ErrLocalAccountsDisabled rejects registrations and logins when users
+	// log in through Cognito
+	ErrLocalAccountsDisabled = errors.New("users log in through the identity provider")
+	// ErrNotAUser rejects account requests made with an API key or session
+	// rather than a user token
+	ErrNotAUser = errors.New("request not made by a user")
+)
+
+// tokenType is the scheme user tokens are sent with
+const tokenType = "Bearer"
+
+type Service interface {
+	Register(ctx context.Context, email, password string) (*IssuedToken, error)
+	Login(ctx context.Context, email, password string) (*IssuedToken, error)
+	AuthenticateUser(ctx context.Context, token string) (*models.APIKey, string, error)
+	CurrentUser(ctx context.Context) (*User, error)
+}
+
+type userService struct {
+	repo     Repository
+	keys     service.APIKeyService
+	keyRepo  repository.APIKeyRepository
+	provider string
+	// signer issues and verifies the tokens of local users; nil with Cognito
+	signer   *jwt.HMAC
+	verifier jwt.Verifier
+	ttl      time.Duration
+	cost     int
+	events   events.Publisher
+	log      *zap.SugaredLogger
+	now      func() time.Time
+	// dummyHash is compared against on logins of unknown emails, so they take
+	// as long as those of known ones
+	dummyHash []byte
+}
+
+// NewLocalService registers users with an email and password and logs them in
+// for tokens signed by signer, which expire after ttl. Logins publish a
+// LoggedIn event.
+func NewLocalService(repo Repository, keys service.APIKeyService, keyRepo repository.APIKeyRepository, signer *jwt.HMAC, ttl time.Duration, publisher events.Publisher, log *zap.SugaredLogger) Service {
+	s := newService(repo, keys, keyRepo, ProviderLocal, signer, log)
+	s.signer = signer
+	s.ttl = ttl
+	s.events = publisher
+	return s
+}
+
+// NewCognitoService accepts the tokens of a Cognito user pool checked by
+// verifier, creating the user of a token's subject on its first request
+func NewCognitoService(repo Repository, keys service.APIKeyService, keyRepo repository.APIKeyRepository, verifier jwt.Verifier, log *zap.SugaredLogger) Service {
+	return newService(repo, keys, keyRepo, ProviderCognito, verifier, log)
+}
+
+func newService(repo Repository, keys service.APIKeyService, keyRepo repository.APIKeyRepository, provider string, verifier jwt.Verifier, log *zap.SugaredLogger) *userService {
+	dummyHash, _ := bcrypt.GenerateFromPassword([]byte("profitify-dummy-password"), bcrypt.DefaultCost)
+	return &userService{
+		repo:      repo,
+		keys:      keys,
+		keyRepo:   keyRepo,
+		provider:  provider,
+		verifier:  verifier,
+		cost:      bcrypt.DefaultCost,
+		log:       log,
+		now:       time.Now,
+		dummyHash: dummyHash,
+	}
+}
+
+// Register creates a local user with its own API key and logs it in
+func (s *userService) Register(ctx context.Context, email, password string) (*IssuedToken, error) {
+	if s.signer == nil {
+		return nil, ErrLocalAccountsDisabled
+	}
+
+	email = normalizeEmail(email)
+	if err := validateCredentials(email, password); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	// Checked first so that taken emails do not leave keys behind
+	if _, err := s.repo.GetUser(ctx, email); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUserExists, email)
+	} else if !errors.Is(err, ErrUserNotFound) {
+		logger.FromContext(ctx, s.log).Errorw("failed to look up user", "error", err)
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+	user, err := s.create(ctx, User{ID: email, Email: email, Provider: ProviderLocal, PasswordHash: string(hash)})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Infow("registered user", "user", user.ID)
+	return s.issue(user)
+}
+
+// Login checks a local user's password and issues a token. Users whose key was
+// revoked or whose account was deleted cannot log in.
+func (s *userService) Login(ctx context.Context, email, password string) (*IssuedToken, error) {
+	if s.signer == nil {
+		return nil, ErrLocalAccountsDisabled
+	}
+
+	user, err := s.repo.GetUser(ctx, normalizeEmail(email))
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			_ = bcrypt.CompareHashAndPassword(s.dummyHash, []byte(password))
+			return nil, ErrInvalidCredentials
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to look up user", "error", err)
+		return nil, fmt.Errorf("failed to look up user: %w", err)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	if _, err := s.activeKey(ctx, user); err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	issued, err := s.issue(user)
+	if err != nil {
+		return nil, err
+	}
+	service.PublishEvent(ctx, s.events, s.log, models.EventLoggedIn, models.EventLoggedInVersion, models.LoggedInEvent{
+		KeyID:       user.KeyID,
+		Method:      models.LoginMethodPassword,
+		UserID:      user.ID,
+		LoggedInUTC: s.now().Unix(),
+	})
+	return issued, nil
+}
+
+// AuthenticateUser resolves a user token to the unrevoked key of its user and
+// the user's ID. The users of Cognito tokens are created on first use.
+// Invalid tokens, and tokens of unknown local users or of disabled keys, are
+// rejected with service.ErrInvalidUserToken.
+func (s *userService) AuthenticateUser(ctx context.Context, token string) (*models.APIKey, string, error) {
+	claims, err := s.verifier.Verify(ctx, token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			return nil, "", service.ErrInvalidUserToken
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to verify user token", "error", err)
+		return nil, "", fmt.Errorf("failed to verify user token: %w", err)
+	}
+
+	id := claims.Subject
+	if s.provider == ProviderCognito {
+		id = ProviderCognito + ":" + claims.Subject
+	}
+	user, err := s.repo.GetUser(ctx, id)
+	switch {
+	case errors.Is(err, ErrUserNotFound) && s.provider == ProviderCognito:
+		user, err = s.provision(ctx, id, claims.Email)
+		if err != nil {
+			return nil, "", err
+		}
+	case errors.Is(err, ErrUserNotFound):
+		return nil, "", service.ErrInvalidUserToken
+	case err != nil:
+		logger.FromContext(ctx, s.log).Errorw("failed to look up user", "error", err)
+		return nil, "", fmt.Errorf("failed to look up user: %w", err)
+	}
+
+	key, err := s.activeKey(ctx, user)
+	if err != nil {
+		return nil, "", err
+	}
+	return key, user.ID, nil
+}
+
+// CurrentUser returns the user the request was authenticated as
+func (s *userService) CurrentUser(ctx context.Context) (*User, error) {
+	id, ok := service.UserFromContext(ctx)
+	if !ok {
+		return nil, ErrNotAUser
+	}
+
+	user, err := s.repo.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get user", "user", id, "error", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// provision creates the user of a Cognito subject seen for the first time.
+// When another request created it meanwhile, that user is returned.
+func (s *userService) provision(ctx context.Context, id, email string) (*User, error) {
+	user, err := s.create(ctx, User{ID: id, Email: normalizeEmail(email), Provider: ProviderCognito})
+	if errors.Is(err, ErrUserExists) {
+		return s.repo.GetUser(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created user on first login", "user", user.ID)
+	return user, nil
+}
+
+// create stores user with a new API key of its own. The key is revoked again
+// when the user cannot be stored.
+func (s *userService) create(ctx context.Context, user User) (*User, error) {
+	key, err := s.keys.CreateKey(ctx, "user "+user.ID, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key of user: %w", err)
+	}
+	user.KeyID = key.ID
+	user.CreatedUTC = s.now().Unix()
+
+	if err := s.repo.CreateUser(ctx, &user); err != nil {
+		if revokeErr := s.keys.RevokeKey(ctx, key.ID); revokeErr != nil {
+			logger.FromContext(ctx, s.log).Warnw("failed to revoke api key of unstored user", "user", user.ID, "error", revokeErr)
+		}
+		if errors.Is(err, ErrUserExists) {
+			return nil, err
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to create user", "user", user.ID, "error", err)
+		return nil, fmt.Errorf("failed to create user: %w", err)
+	}
+	return &user, nil
+}
+
+// activeKey returns the API key of user, rejecting disabled keys with
+// service.ErrInvalidUserToken
+func (s *userService) activeKey(ctx context.Context, user *User) (*models.APIKey, error) {
+	key, err := s.keyRepo.GetKey(ctx, user.KeyID)
+	if err != nil {
+		var notFound repository.ErrAPIKeyNotFound
+		if errors.As(err, &notFound) {
+			return nil, service.ErrInvalidUserToken
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to look up api key of user", "user", user.ID, "error", err)
+		return nil, fmt.Errorf("failed to look up api key: %w", err)
+	}
+	if key.Disabled() {
+		return nil, service.ErrInvalidUserToken
+	}
+	return key, nil
+}
+
+func (s *userService) issue(user *User) (*IssuedToken, error) {
+	token, claims, err := s.signer.Sign(user.ID, user.Email, s.ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign user token: %w", err)
+	}
+	return &IssuedToken{User: *user, Token: token, TokenType: tokenType, ExpiresUTC: claims.ExpiresAt}, nil
+}
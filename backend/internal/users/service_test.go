@@ -0,0 +1,164 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/events/eventstest"
+	"profitify-backend/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// memoryUsers keeps users in a map
+type memoryUsers struct {
+	mu    sync.Mutex
+	users map[string]User
+}
+
+func newMemoryUsers() *memoryUsers {
+	return &memoryUsers{users: make(map[string]User)}
+}
+
+func (m *memoryUsers) GetUser(ctx context.Context, id string) (*User, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	user, ok := m.users[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUserNotFound, id)
+	}
+	return &user, nil
+}
+
+func (m *memoryUsers) CreateUser(ctx context.Context, user *User) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.users[user.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrUserExists, user.ID)
+	}
+	m.users[user.ID] = *user
+	return nil
+}
+
+// claimsVerifier accepts any token as the claims it maps to
+type claimsVerifier map[string]jwt.Claims
+
+func (v claimsVerifier) Verify(ctx context.Context, token string) (*jwt.Claims, error) {
+	claims, ok := v[token]
+	if !ok {
+		return nil, jwt.ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+func newLocalService() (Service, repository.APIKeyRepository, *eventstest.RecordingPublisher) {
+	log := zap.NewNop().Sugar()
+	keyRepo := repository.NewMemoryAPIKeyRepository()
+	signer := jwt.NewHMAC([]byte("0123456789abcdef0123456789abcdef"), tokenIssuer)
+	publisher := &eventstest.RecordingPublisher{}
+	svc := NewLocalService(newMemoryUsers(), service.NewAPIKeyService(keyRepo, log), keyRepo, signer, time.Hour, publisher, log)
+	svc.(*userService).cost = bcrypt.MinCost
+	return svc, keyRepo, publisher
+}
+
+func TestService_RegisterAndLogin(t *testing.T) {
+	ctx := context.Background()
+	svc, keyRepo, publisher := newLocalService()
+
+	issued, err := svc.Register(ctx, " Ada@Example.com ", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", issued.User.ID)
+	assert.Equal(t, ProviderLocal, issued.User.Provider)
+	assert.Equal(t, "Bearer", issued.TokenType)
+	assert.NotEqual(t, "correct horse", issued.User.PasswordHash)
+
+	key, id, err := svc.AuthenticateUser(ctx, issued.Token)
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", id)
+	assert.Equal(t, issued.User.KeyID, key.ID, "users authenticate as their own key")
+	assert.False(t, key.Admin)
+
+	_, err = svc.Register(ctx, "ada@example.com", "another password")
+	assert.ErrorIs(t, err, ErrUserExists)
+	_, err = svc.Register(ctx, "not-an-email", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidUser)
+	_, err = svc.Register(ctx, "bob@example.com", "short")
+	assert.ErrorIs(t, err, ErrInvalidUser)
+
+	assert.Empty(t, publisher.Events(), "registering is not a login")
+	loggedIn, err := svc.Login(ctx, "ADA@example.com", "correct horse")
+	require.NoError(t, err)
+	assert.Equal(t, issued.User.ID, loggedIn.User.ID)
+	require.Len(t, publisher.Events(), 1)
+	assert.Equal(t, models.EventLoggedIn, publisher.Events()[0].Type)
+	login := publisher.Events()[0].Data.(models.LoggedInEvent)
+	assert.Equal(t, key.ID, login.KeyID)
+	assert.Equal(t, models.LoginMethodPassword, login.Method)
+	assert.Equal(t, "ada@example.com", login.UserID)
+	_, err = svc.Login(ctx, "ada@example.com", "wrong password")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	_, err = svc.Login(ctx, "nobody@example.com", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.Len(t, publisher.Events(), 1, "failed logins are not published")
+
+	_, _, err = svc.AuthenticateUser(ctx, issued.Token+"x")
+	assert.ErrorIs(t, err, service.ErrInvalidUserToken)
+
+	// Revoking the key logs the user out
+	require.NoError(t, keyRepo.RevokeKey(ctx, key.ID, time.Now().Unix()))
+	_, _, err = svc.AuthenticateUser(ctx, issued.Token)
+	assert.ErrorIs(t, err, service.ErrInvalidUserToken)
+	_, err = svc.Login(ctx, "ada@example.com", "correct horse")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestService_CurrentUser(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newLocalService()
+	issued, err := svc.Register(ctx, "ada@example.com", "correct horse")
+	require.NoError(t, err)
+
+	user, err := svc.CurrentUser(service.WithUser(ctx, issued.User.ID))
+	require.NoError(t, err)
+	assert.Equal(t, "ada@example.com", user.Email)
+
+	_, err = svc.CurrentUser(ctx)
+	assert.ErrorIs(t, err, ErrNotAUser)
+}
+
+func TestService_Cognito(t *testing.T) {
+	ctx := context.Background()
+	log := zap.NewNop().Sugar()
+	keyRepo := repository.NewMemoryAPIKeyRepository()
+	users := newMemoryUsers()
+	verifier := claimsVerifier{"id-token": {Subject: "sub-1", Email: "Ada@Example.com"}}
+	svc := NewCognitoService(users, service.NewAPIKeyService(keyRepo, log), keyRepo, verifier, log)
+
+	key, id, err := svc.AuthenticateUser(ctx, "id-token")
+	require.NoError(t, err)
+	assert.Equal(t, "cognito:sub-1", id)
+	created := users.users[id]
+	assert.Equal(t, "ada@example.com", created.Email)
+	assert.Equal(t, created.KeyID, key.ID)
+
+	again, _, err := svc.AuthenticateUser(ctx, "id-token")
+	require.NoError(t, err)
+	assert.Equal(t, key.ID, again.ID, "the user is created once")
+	keys, err := keyRepo.ListKeys(ctx)
+	require.NoError(t, err)
+	assert.Len(t, keys, 1)
+
+	_, _, err = svc.AuthenticateUser(ctx, "forged")
+	assert.ErrorIs(t, err, service.ErrInvalidUserToken)
+	_, err = svc.Login(ctx, "ada@example.com", "password")
+	assert.ErrorIs(t, err, ErrLocalAccountsDisabled)
+}
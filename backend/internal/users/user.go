@@ -0,0 +1,59 @@
+package users
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Providers authenticating users
+const (
+	ProviderLocal   = "local"
+	ProviderCognito = "cognito"
+)
+
+// Password lengths accepted on registration; bcrypt ignores bytes past 72
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72
+)
+
+// User is an account a person logs in to, by email and password or through
+// Cognito. Each user authenticates as an API key of their own, which owns
+// the watchlists, portfolios and alerts they create.
+type User struct {
+	// ID is the email of local users and "cognito:" and the subject of
+	// Cognito users
+	ID       string `json:"id" dynamodbav:"id"`
+	Email    string `json:"email,omitempty" dynamodbav:"email,omitempty"`
+	Provider string `json:"provider" dynamodbav:"provider"`
+	// PasswordHash is the bcrypt hash of a local user's password
+	PasswordHash string `json:"-" dynamodbav:"passwordHash,omitempty"`
+	// KeyID is the API key the user authenticates as
+	KeyID      string `json:"-" dynamodbav:"keyId"`
+	CreatedUTC int64  `json:"createdUTC" dynamodbav:"createdUTC"`
+}
+
+// IssuedToken is a token a user logged in for
+type IssuedToken struct {
+	User       User   `json:"user"`
+	Token      string `json:"token"`
+	TokenType  string `json:"tokenType"`
+	ExpiresUTC int64  `json:"expiresUTC"`
+}
+
+// normalizeEmail lowercases email, which identifies local users
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// validateCredentials checks the email and password a user registers with
+func validateCredentials(email, password string) error {
+	at := strings.Index(email, "@")
+	if len(email) > 254 || at < 1 || at == len(email)-1 || strings.Count(email, "@") != 1 || strings.ContainsAny(email, " \t\r\n") {
+		return fmt.Errorf("email is not a valid address")
+	}
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return fmt.Errorf("password must be %d to %d bytes", minPasswordLength, maxPasswordLength)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+type credentialsRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+func (h *Handler) Register(c *gin.Context) {
+	var req credentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+	if h.userService == nil {
+		h.respondUserError(c, ErrUsersDisabled)
+		return
+	}
+
+	token, err := h.userService.Register(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		h.respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (h *Handler) Login(c *gin.Context) {
+	var req credentialsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+	if h.userService == nil {
+		h.respondUserError(c, ErrUsersDisabled)
+		return
+	}
+
+	token, err := h.userService.Login(c.Request.Context(), req.Email, req.Password)
+	if err != nil {
+		h.respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, token)
+}
+
+func (h *Handler) GetCurrentUser(c *gin.Context) {
+	if h.userService == nil {
+		h.respondUserError(c, ErrUsersDisabled)
+		return
+	}
+
+	user, err := h.userService.CurrentUser(c.Request.Context())
+	if err != nil {
+		h.respondUserError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+func (h *Handler) respondUserError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidUser):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, ErrUserExists):
+		problem.Respond(c, problem.Conflict, "An account with this email already exists")
+	case errors.Is(err, ErrInvalidCredentials):
+		problem.Respond(c, problem.Unauthenticated, "Invalid email or password")
+	case errors.Is(err, ErrNotAUser):
+		problem.Respond(c, problem.Forbidden, "Request not made with a user token")
+	case errors.Is(err, ErrUserNotFound):
+		problem.Respond(c, problem.NotFound, "User not found")
+	case errors.Is(err, ErrUsersDisabled):
+		problem.Respond(c, problem.Unavailable, "User accounts are not enabled on this server")
+	case errors.Is(err, ErrLocalAccountsDisabled):
+		problem.Respond(c, problem.Unavailable, "Users register and log in through the identity provider")
+	default:
+		api.Logger(c, h.log).Errorw("user request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process user request")
+	}
+}
@@ -0,0 +1,96 @@
+// Package watchlists serves named lists of tickers and their latest quotes.
+package watchlists
+
+import (
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	watchlistService Service
+	log              *zap.SugaredLogger
+}
+
+func NewHandler(watchlists Service, log *zap.SugaredLogger) *Handler {
+	return &Handler{
+		watchlistService: watchlists,
+		log:              log,
+	}
+}
+
+// Wire builds the watchlists module from the shared dependencies
+func Wire(deps app.Deps) *Handler {
+	return NewHandler(NewService(
+		NewRepository(deps.DB, deps.Config.WatchlistsTable),
+		service.NewDailySummaryService(deps.DailySummaryRepository(), deps.Log),
+		deps.Log,
+	), deps.Log)
+}
+
+func (h *Handler) RegisterRoutes(api, admin *gin.RouterGroup) {
+	watchlists := api.Group("/watchlists", middleware.RequireFullAccess(), middleware.RequireAcceptedTerms())
+	watchlists.GET("", h.ListWatchlists)
+	watchlists.POST("", h.CreateWatchlist)
+	watchlists.GET("/:id", h.GetWatchlist)
+	watchlists.PUT("/:id", h.UpdateWatchlist)
+	watchlists.DELETE("/:id", h.DeleteWatchlist)
+	watchlists.GET("/:id/quotes", h.GetWatchlistQuotes)
+}
+
+func (h *Handler) DocumentRoutes(doc *openapi.Document) {
+	tags := []string{"Watchlists"}
+	id := openapi.PathParam("id", "Watchlist ID")
+	body := openapi.JSONBody(doc.Inline(watchlistRequest{}))
+
+	doc.Add(http.MethodGet, "/api/v1/watchlists", &openapi.Operation{
+		Tags:      tags,
+		Summary:   "List watchlists",
+		Responses: api.Responses(http.StatusOK, api.List(doc, "watchlists", Watchlist{})),
+	})
+	doc.Add(http.MethodPost, "/api/v1/watchlists", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Create a watchlist",
+		RequestBody: body,
+		Responses:   api.Responses(http.StatusCreated, doc.Schema(Watchlist{}), http.StatusBadRequest, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+	doc.Add(http.MethodGet, "/api/v1/watchlists/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Get a watchlist",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusOK, doc.Schema(Watchlist{}), http.StatusNotFound),
+	})
+	doc.Add(http.MethodPut, "/api/v1/watchlists/:id", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Replace a watchlist's name and symbols",
+		Parameters:  []openapi.Parameter{id},
+		RequestBody: body,
+		Responses:   api.Responses(http.StatusOK, doc.Schema(Watchlist{}), http.StatusBadRequest, http.StatusNotFound, http.StatusPaymentRequired, http.StatusForbidden),
+	})
+	doc.Add(http.MethodDelete, "/api/v1/watchlists/:id", &openapi.Operation{
+		Tags:       tags,
+		Summary:    "Delete a watchlist",
+		Parameters: []openapi.Parameter{id},
+		Responses:  api.Responses(http.StatusNoContent, nil, http.StatusNotFound),
+	})
+	doc.Add(http.MethodGet, "/api/v1/watchlists/:id/quotes", &openapi.Operation{
+		Tags:        tags,
+		Summary:     "Get the latest quote of every symbol in a watchlist",
+		Description: "Symbols without daily bars are listed in missing.",
+		Parameters:  []openapi.Parameter{id},
+		Responses: api.Responses(http.StatusOK, openapi.Object(map[string]*openapi.Schema{
+			"watchlist": {Type: "string"},
+			"quotes":    {Type: "array", Items: doc.Schema(models.Quote{})},
+			"missing":   {Type: "array", Items: &openapi.Schema{Type: "string"}},
+			"count":     {Type: "integer"},
+		}), http.StatusNotFound),
+	})
+}
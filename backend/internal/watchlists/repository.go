@@ -0,0 +1,140 @@
+package watchlists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Repository defines the interface for watchlist data operations
+type Repository interface {
+	GetWatchlist(ctx context.Context, id string) (*Watchlist, error)
+	ListWatchlists(ctx context.Context) ([]Watchlist, error)
+	PutWatchlist(ctx context.Context, watchlist *Watchlist) error
+	DeleteWatchlist(ctx context.Context, id string) error
+}
+
+// watchlistRepository implements Repository using DynamoDB
+type watchlistRepository struct {
+	client    *dynamodb.Client
+	tableName string
+}
+
+// NewRepository creates a new DynamoDB-backed watchlist repository
+func NewRepository(client *dynamodb.Client, tableName string) Repository {
+	return &watchlistRepository{
+		client:    client,
+		tableName: tableName,
+	}
+}
+
+// GetWatchlist retrieves a single watchlist by ID
+func (r *watchlistRepository) GetWatchlist(ctx context.Context, id string) (*Watchlist, error) {
+	result, err := r.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchlist %s: %w", id, err)
+	}
+
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrWatchlistNotFound, id)
+	}
+
+	var watchlist Watchlist
+	if err := attributevalue.UnmarshalMap(result.Item, &watchlist); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal watchlist: %w", err)
+	}
+
+	return &watchlist, nil
+}
+
+// ListWatchlists retrieves all watchlists
+func (r *watchlistRepository) ListWatchlists(ctx context.Context) ([]Watchlist, error) {
+	var watchlists []Watchlist
+	var lastEvaluatedKey map[string]types.AttributeValue
+
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tableName),
+			Limit:     aws.Int32(100),
+		}
+
+		if lastEvaluatedKey != nil {
+			input.ExclusiveStartKey = lastEvaluatedKey
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watchlists: %w", err)
+		}
+
+		var batch []Watchlist
+		if err := attributevalue.UnmarshalListOfMaps(result.Items, &batch); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal watchlists: %w", err)
+		}
+
+		watchlists = append(watchlists, batch...)
+
+		if result.LastEvaluatedKey == nil {
+			break
+		}
+		lastEvaluatedKey = result.LastEvaluatedKey
+	}
+
+	return watchlists, nil
+}
+
+// PutWatchlist creates or replaces a watchlist
+func (r *watchlistRepository) PutWatchlist(ctx context.Context, watchlist *Watchlist) error {
+	item, err := attributevalue.MarshalMap(watchlist)
+	if err != nil {
+		return fmt.Errorf("failed to marshal watchlist: %w", err)
+	}
+
+	_, err = r.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put watchlist %s: %w", watchlist.ID, err)
+	}
+
+	return nil
+}
+
+// DeleteWatchlist deletes a watchlist, failing if it does not exist
+func (r *watchlistRepository) DeleteWatchlist(ctx context.Context, id string) error {
+	cond := expression.AttributeExists(expression.Name("id"))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"id": &types.AttributeValueMemberS{Value: id},
+		},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrWatchlistNotFound, id)
+		}
+		return fmt.Errorf("failed to delete watchlist %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package watchlists
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRepository mocks the Repository interface
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetWatchlist(ctx context.Context, id string) (*Watchlist, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Watchlist), args.Error(1)
+}
+
+func (m *MockRepository) ListWatchlists(ctx context.Context) ([]Watchlist, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]Watchlist), args.Error(1)
+}
+
+func (m *MockRepository) PutWatchlist(ctx context.Context, watchlist *Watchlist) error {
+	return m.Called(ctx, watchlist).Error(0)
+}
+
+func (m *MockRepository) DeleteWatchlist(ctx context.Context, id string) error {
+	return m.Called(ctx, id).Error(0)
+}
@@ -0,0 +1,214 @@
+package watchlists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrWatchlistNotFound = errors.New("watchlist not found")
+	ErrInvalidWatchlist  = errors.New("invalid watchlist")
+)
+
+// WatchlistQuotes holds the latest quote of each symbol in a watchlist, in
+// watchlist order. Symbols without daily summaries are listed in Missing.
+type WatchlistQuotes struct {
+	Quotes  []models.Quote
+	Missing []string
+}
+
+type Service interface {
+	CreateWatchlist(ctx context.Context, name string, symbols []string) (*Watchlist, error)
+	GetWatchlist(ctx context.Context, id string) (*Watchlist, error)
+	ListWatchlists(ctx context.Context) ([]Watchlist, error)
+	UpdateWatchlist(ctx context.Context, id, name string, symbols []string) (*Watchlist, error)
+	DeleteWatchlist(ctx context.Context, id string) error
+	GetQuotes(ctx context.Context, id string) (*WatchlistQuotes, error)
+}
+
+type watchlistService struct {
+	repo   Repository
+	quotes service.DailySummaryService
+	log    *zap.SugaredLogger
+}
+
+func NewService(repo Repository, quotes service.DailySummaryService, log *zap.SugaredLogger) Service {
+	return &watchlistService{
+		repo:   repo,
+		quotes: quotes,
+		log:    log,
+	}
+}
+
+func (s *watchlistService) CreateWatchlist(ctx context.Context, name string, symbols []string) (*Watchlist, error) {
+	id, err := service.NewID()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	watchlist := &Watchlist{
+		ID:         id,
+		Name:       strings.TrimSpace(name),
+		Symbols:    normalizeSymbols(symbols),
+		CreatedUTC: now,
+		UpdatedUTC: now,
+		KeyID:      service.CallerKeyID(ctx),
+	}
+	if err := watchlist.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+	}
+	if err := enforceWatchlistLimit(ctx, watchlist); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PutWatchlist(ctx, watchlist); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to create watchlist", "name", watchlist.Name, "error", err)
+		return nil, fmt.Errorf("failed to create watchlist: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("created watchlist", "watchlist", id, "name", watchlist.Name, "symbols", len(watchlist.Symbols))
+	return watchlist, nil
+}
+
+func (s *watchlistService) GetWatchlist(ctx context.Context, id string) (*Watchlist, error) {
+	if id == "" {
+		return nil, fmt.Errorf("%w: id is required", ErrInvalidWatchlist)
+	}
+
+	watchlist, err := s.repo.GetWatchlist(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrWatchlistNotFound) {
+			return nil, ErrWatchlistNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to get watchlist", "watchlist", id, "error", err)
+		return nil, fmt.Errorf("failed to get watchlist: %w", err)
+	}
+	if watchlist.KeyID != service.CallerKeyID(ctx) {
+		return nil, ErrWatchlistNotFound
+	}
+
+	return watchlist, nil
+}
+
+// ListWatchlists returns the caller's watchlists, sorted by name
+func (s *watchlistService) ListWatchlists(ctx context.Context) ([]Watchlist, error) {
+	all, err := s.repo.ListWatchlists(ctx)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to list watchlists", "error", err)
+		return nil, fmt.Errorf("failed to list watchlists: %w", err)
+	}
+
+	keyID := service.CallerKeyID(ctx)
+	watchlists := make([]Watchlist, 0, len(all))
+	for _, watchlist := range all {
+		if watchlist.KeyID == keyID {
+			watchlists = append(watchlists, watchlist)
+		}
+	}
+
+	sort.Slice(watchlists, func(i, j int) bool {
+		return watchlists[i].Name < watchlists[j].Name
+	})
+	return watchlists, nil
+}
+
+// UpdateWatchlist replaces the name and symbols of a watchlist
+func (s *watchlistService) UpdateWatchlist(ctx context.Context, id, name string, symbols []string) (*Watchlist, error) {
+	watchlist, err := s.GetWatchlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	watchlist.Name = strings.TrimSpace(name)
+	watchlist.Symbols = normalizeSymbols(symbols)
+	watchlist.UpdatedUTC = time.Now().Unix()
+	if err := watchlist.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+	}
+	if err := enforceWatchlistLimit(ctx, watchlist); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PutWatchlist(ctx, watchlist); err != nil {
+		logger.FromContext(ctx, s.log).Errorw("failed to update watchlist", "watchlist", id, "error", err)
+		return nil, fmt.Errorf("failed to update watchlist: %w", err)
+	}
+
+	return watchlist, nil
+}
+
+func (s *watchlistService) DeleteWatchlist(ctx context.Context, id string) error {
+	if _, err := s.GetWatchlist(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteWatchlist(ctx, id); err != nil {
+		if errors.Is(err, ErrWatchlistNotFound) {
+			return ErrWatchlistNotFound
+		}
+		logger.FromContext(ctx, s.log).Errorw("failed to delete watchlist", "watchlist", id, "error", err)
+		return fmt.Errorf("failed to delete watchlist: %w", err)
+	}
+
+	logger.FromContext(ctx, s.log).Infow("deleted watchlist", "watchlist", id)
+	return nil
+}
+
+// GetQuotes returns the latest quote of every symbol in a watchlist
+func (s *watchlistService) GetQuotes(ctx context.Context, id string) (*WatchlistQuotes, error) {
+	watchlist, err := s.GetWatchlist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes, errs := service.LatestQuotes(ctx, s.quotes, watchlist.Symbols)
+
+	result := &WatchlistQuotes{
+		Quotes:  make([]models.Quote, 0, len(quotes)),
+		Missing: make([]string, 0),
+	}
+	for i, symbol := range watchlist.Symbols {
+		switch {
+		case errs[i] == nil:
+			result.Quotes = append(result.Quotes, *quotes[i])
+		case errors.Is(errs[i], service.ErrTickerNotFound):
+			result.Missing = append(result.Missing, symbol)
+		default:
+			return nil, fmt.Errorf("failed to get quote for %s: %w", symbol, errs[i])
+		}
+	}
+
+	return result, nil
+}
+
+// enforceWatchlistLimit checks the watchlist's symbols against the caller's plan
+func enforceWatchlistLimit(ctx context.Context, watchlist *Watchlist) error {
+	return service.EnforcePlanLimit(ctx, len(watchlist.Symbols), "symbols per watchlist", func(p models.Plan) int {
+		return p.WatchlistSymbols
+	})
+}
+
+// normalizeSymbols upper-cases symbols and drops duplicates, keeping the first occurrence
+func normalizeSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	out := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		out = append(out, symbol)
+	}
+	return out
+}
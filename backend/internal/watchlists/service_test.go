@@ -0,0 +1,204 @@
+package watchlists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestService(repo *MockRepository, summaries *repository.MockDailySummaryRepository) Service {
+	log := zap.NewNop().Sugar()
+	return NewService(repo, service.NewDailySummaryService(summaries, log), log)
+}
+
+func TestService_CreateWatchlist(t *testing.T) {
+	tests := []struct {
+		name      string
+		list      string
+		symbols   []string
+		mockSetup func(*MockRepository)
+		wantErr   error
+		want      []string
+	}{
+		{
+			name:    "normalizes and dedupes symbols",
+			list:    " Tech ",
+			symbols: []string{"aapl", "MSFT", " AAPL"},
+			mockSetup: func(m *MockRepository) {
+				m.On("PutWatchlist", mock.Anything, mock.Anything).Return(nil)
+			},
+			want: []string{"AAPL", "MSFT"},
+		},
+		{
+			name:      "requires a name",
+			symbols:   []string{"AAPL"},
+			mockSetup: func(m *MockRepository) {},
+			wantErr:   ErrInvalidWatchlist,
+		},
+		{
+			name:      "rejects empty symbols",
+			list:      "Tech",
+			symbols:   []string{" "},
+			mockSetup: func(m *MockRepository) {},
+			wantErr:   ErrInvalidWatchlist,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			tt.mockSetup(repo)
+
+			watchlist, err := newTestService(repo, new(repository.MockDailySummaryRepository)).CreateWatchlist(context.Background(), tt.list, tt.symbols)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "PutWatchlist", mock.Anything, mock.Anything)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.NotEmpty(t, watchlist.ID)
+			assert.Equal(t, "Tech", watchlist.Name)
+			assert.Equal(t, tt.want, watchlist.Symbols)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_UpdateAndDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRepository)
+	repo.On("GetWatchlist", mock.Anything, "missing").Return(nil, fmt.Errorf("%w: missing", ErrWatchlistNotFound))
+	repo.On("DeleteWatchlist", mock.Anything, "missing").Return(fmt.Errorf("%w: missing", ErrWatchlistNotFound))
+	repo.On("GetWatchlist", mock.Anything, "w1").Return(&Watchlist{ID: "w1", Name: "Tech", Symbols: []string{"AAPL"}, CreatedUTC: 1}, nil)
+	repo.On("PutWatchlist", mock.Anything, mock.Anything).Return(nil)
+	svc := newTestService(repo, new(repository.MockDailySummaryRepository))
+
+	_, err := svc.UpdateWatchlist(ctx, "missing", "Tech", nil)
+	assert.ErrorIs(t, err, ErrWatchlistNotFound)
+	assert.ErrorIs(t, svc.DeleteWatchlist(ctx, "missing"), ErrWatchlistNotFound)
+
+	updated, err := svc.UpdateWatchlist(ctx, "w1", "Chips", []string{"nvda"})
+	require.NoError(t, err)
+	assert.Equal(t, "Chips", updated.Name)
+	assert.Equal(t, []string{"NVDA"}, updated.Symbols)
+	assert.Equal(t, int64(1), updated.CreatedUTC)
+	assert.NotZero(t, updated.UpdatedUTC)
+}
+
+func TestService_GetQuotes(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockRepository)
+	repo.On("GetWatchlist", mock.Anything, "w1").Return(&Watchlist{ID: "w1", Symbols: []string{"AAPL", "NEW", "MSFT"}}, nil)
+
+	summaries := new(repository.MockDailySummaryRepository)
+	summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "AAPL", Timestamp: 2, Close: 110},
+		{Ticker: "AAPL", Timestamp: 1, Close: 100},
+	}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "NEW", mock.Anything, int32(2)).Return([]models.DailySummary{}, nil)
+	summaries.On("GetLatestSummaries", mock.Anything, "MSFT", mock.Anything, int32(2)).Return([]models.DailySummary{
+		{Ticker: "MSFT", Timestamp: 2, Close: 400},
+	}, nil)
+
+	quotes, err := newTestService(repo, summaries).GetQuotes(ctx, "w1")
+	require.NoError(t, err)
+	require.Len(t, quotes.Quotes, 2)
+	assert.Equal(t, "AAPL", quotes.Quotes[0].Ticker)
+	assert.InDelta(t, 10.0, quotes.Quotes[0].ChangePercent, 1e-6)
+	assert.Equal(t, "MSFT", quotes.Quotes[1].Ticker)
+	assert.Equal(t, []string{"NEW"}, quotes.Missing)
+
+	t.Run("fails when a lookup fails", func(t *testing.T) {
+		repo := new(MockRepository)
+		repo.On("GetWatchlist", mock.Anything, "w2").Return(&Watchlist{ID: "w2", Symbols: []string{"AAPL"}}, nil)
+		summaries := new(repository.MockDailySummaryRepository)
+		summaries.On("GetLatestSummaries", mock.Anything, "AAPL", mock.Anything, int32(2)).Return(nil, errors.New("throttled"))
+
+		_, err := newTestService(repo, summaries).GetQuotes(ctx, "w2")
+		assert.ErrorContains(t, err, "throttled")
+	})
+}
+
+func TestService_ScopesWatchlistsToTheCallingKey(t *testing.T) {
+	repo := new(MockRepository)
+	repo.On("GetWatchlist", mock.Anything, "mine").Return(&Watchlist{ID: "mine", Name: "Mine", KeyID: "key"}, nil)
+	repo.On("GetWatchlist", mock.Anything, "theirs").Return(&Watchlist{ID: "theirs", Name: "Theirs", KeyID: "other"}, nil)
+	repo.On("ListWatchlists", mock.Anything).Return([]Watchlist{{ID: "mine", KeyID: "key"}, {ID: "theirs", KeyID: "other"}}, nil)
+	repo.On("PutWatchlist", mock.Anything, mock.Anything).Return(nil)
+	repo.On("DeleteWatchlist", mock.Anything, "mine").Return(nil)
+	svc := NewService(repo, nil, zap.NewNop().Sugar())
+	ctx := accountContext(models.PlanPro, false)
+
+	created, err := svc.CreateWatchlist(ctx, "Tech", []string{"AAPL"})
+	require.NoError(t, err)
+	assert.Equal(t, "key", created.KeyID)
+
+	lists, err := svc.ListWatchlists(ctx)
+	require.NoError(t, err)
+	require.Len(t, lists, 1)
+	assert.Equal(t, "mine", lists[0].ID)
+
+	_, err = svc.GetWatchlist(ctx, "theirs")
+	assert.ErrorIs(t, err, ErrWatchlistNotFound)
+	_, err = svc.UpdateWatchlist(ctx, "theirs", "Mine now", nil)
+	assert.ErrorIs(t, err, ErrWatchlistNotFound)
+
+	assert.NoError(t, svc.DeleteWatchlist(ctx, "mine"))
+	assert.ErrorIs(t, svc.DeleteWatchlist(ctx, "theirs"), ErrWatchlistNotFound)
+	repo.AssertNotCalled(t, "DeleteWatchlist", mock.Anything, "theirs")
+}
+
+func accountContext(tier models.PlanTier, admin bool) context.Context {
+	return service.WithAccount(context.Background(), &models.APIKey{ID: "key", Name: "test", Tier: tier, Admin: admin})
+}
+
+func symbols(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("T%d", i)
+	}
+	return out
+}
+
+func TestService_PlanLimits(t *testing.T) {
+	free := models.PlanFor(models.PlanFree)
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		symbols int
+		wantErr error
+	}{
+		{name: "within the free plan", ctx: accountContext(models.PlanFree, false), symbols: free.WatchlistSymbols},
+		{name: "keys without a tier are free", ctx: accountContext("", false), symbols: free.WatchlistSymbols + 1, wantErr: service.ErrUpgradeRequired},
+		{name: "pro allows more", ctx: accountContext(models.PlanPro, false), symbols: free.WatchlistSymbols + 1},
+		{name: "admin keys are unlimited", ctx: accountContext(models.PlanFree, true), symbols: free.WatchlistSymbols + 1},
+		{name: "unauthenticated requests are unlimited", ctx: context.Background(), symbols: free.WatchlistSymbols + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockRepository)
+			repo.On("PutWatchlist", mock.Anything, mock.Anything).Return(nil)
+			svc := NewService(repo, nil, zap.NewNop().Sugar())
+
+			_, err := svc.CreateWatchlist(tt.ctx, "Tech", symbols(tt.symbols))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "PutWatchlist", mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
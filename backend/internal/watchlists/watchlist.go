@@ -0,0 +1,41 @@
+package watchlists
+
+import (
+	"fmt"
+
+	"profitify-backend/internal/models"
+)
+
+// Watchlist is a named list of ticker symbols
+type Watchlist struct {
+	ID         string   `json:"id" dynamodbav:"id"`
+	Name       string   `json:"name" dynamodbav:"name"`
+	Symbols    []string `json:"symbols" dynamodbav:"symbols"`
+	CreatedUTC int64    `json:"createdUTC" dynamodbav:"createdUTC"`
+	UpdatedUTC int64    `json:"updatedUTC" dynamodbav:"updatedUTC"`
+	// KeyID is the API key that created the watchlist; only it can see the list
+	KeyID string `json:"-" dynamodbav:"keyId,omitempty"`
+}
+
+// Validate checks if the watchlist data is valid
+func (w *Watchlist) Validate() error {
+	if w.Name == "" {
+		return fmt.Errorf("watchlist name is required")
+	}
+
+	if len(w.Name) > 100 {
+		return fmt.Errorf("watchlist name must be at most 100 characters")
+	}
+
+	if len(w.Symbols) > models.MaxWatchlistSymbols {
+		return fmt.Errorf("a watchlist holds at most %d symbols", models.MaxWatchlistSymbols)
+	}
+
+	for _, symbol := range w.Symbols {
+		if symbol == "" {
+			return fmt.Errorf("symbols must not be empty")
+		}
+	}
+
+	return nil
+}
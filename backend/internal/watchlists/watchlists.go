@@ -0,0 +1,115 @@
+package watchlists
+
+import (
+	"errors"
+	"net/http"
+
+	"profitify-backend/internal/api"
+	"profitify-backend/internal/problem"
+	"profitify-backend/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type watchlistRequest struct {
+	Name    string   `json:"name"`
+	Symbols []string `json:"symbols"`
+}
+
+func (h *Handler) ListWatchlists(c *gin.Context) {
+	watchlists, err := h.watchlistService.ListWatchlists(c.Request.Context())
+	if err != nil {
+		api.Logger(c, h.log).Errorw("failed to list watchlists", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to retrieve watchlists")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchlists": watchlists,
+		"count":      len(watchlists),
+	})
+}
+
+func (h *Handler) CreateWatchlist(c *gin.Context) {
+	var req watchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	watchlist, err := h.watchlistService.CreateWatchlist(c.Request.Context(), req.Name, req.Symbols)
+	if err != nil {
+		h.respondWatchlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, watchlist)
+}
+
+func (h *Handler) GetWatchlist(c *gin.Context) {
+	watchlist, err := h.watchlistService.GetWatchlist(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.respondWatchlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+func (h *Handler) UpdateWatchlist(c *gin.Context) {
+	var req watchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		problem.Respond(c, problem.MalformedBody, "Invalid request body")
+		return
+	}
+
+	watchlist, err := h.watchlistService.UpdateWatchlist(c.Request.Context(), c.Param("id"), req.Name, req.Symbols)
+	if err != nil {
+		h.respondWatchlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+func (h *Handler) DeleteWatchlist(c *gin.Context) {
+	if err := h.watchlistService.DeleteWatchlist(c.Request.Context(), c.Param("id")); err != nil {
+		h.respondWatchlistError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetWatchlistQuotes returns the latest daily close of every symbol in a watchlist
+func (h *Handler) GetWatchlistQuotes(c *gin.Context) {
+	id := c.Param("id")
+	quotes, err := h.watchlistService.GetQuotes(c.Request.Context(), id)
+	if err != nil {
+		h.respondWatchlistError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchlist": id,
+		"quotes":    quotes.Quotes,
+		"missing":   quotes.Missing,
+		"count":     len(quotes.Quotes),
+	})
+}
+
+func (h *Handler) respondWatchlistError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrWatchlistNotFound):
+		problem.Respond(c, problem.WatchlistNotFound, "Watchlist not found")
+	case errors.Is(err, ErrInvalidWatchlist):
+		problem.Respond(c, problem.ValidationFailed, err.Error())
+	case errors.Is(err, service.ErrUpgradeRequired):
+		problem.Respond(c, problem.UpgradeRequired, err.Error())
+	case errors.Is(err, service.ErrPlanLimitExceeded):
+		problem.Respond(c, problem.PlanLimitExceeded, err.Error())
+	default:
+		api.Logger(c, h.log).Errorw("watchlist request failed", "error", err)
+		problem.Respond(c, problem.Internal, "Failed to process watchlist request")
+	}
+}
@@ -3,12 +3,54 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"net"
 	"os"
-	"profitify-backend/internal/handlers"
+	"profitify-backend/internal/activity"
+	"profitify-backend/internal/admin"
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/analytics"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/auth"
+	"profitify-backend/internal/bundle"
+	"profitify-backend/internal/devices"
+	"profitify-backend/internal/digests"
+	"profitify-backend/internal/indicators"
+	"profitify-backend/internal/ingest"
+	"profitify-backend/internal/jobs"
+	"profitify-backend/internal/market"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/portfolios"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/screener"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/sessions"
+	"profitify-backend/internal/stats"
+	"profitify-backend/internal/streams"
+	"profitify-backend/internal/summaries"
+	"profitify-backend/internal/tickers"
+	"profitify-backend/internal/users"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/awsclient"
+	"profitify-backend/pkg/cache"
+	"profitify-backend/pkg/clock"
 	"profitify-backend/pkg/config"
+	"profitify-backend/pkg/dynamostreams"
+	"profitify-backend/pkg/errorlog"
+	"profitify-backend/pkg/events"
+	"profitify-backend/pkg/grpcserver"
+	"profitify-backend/pkg/lambda"
+	"profitify-backend/pkg/lock"
 	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/metrics"
+	"profitify-backend/pkg/notify"
+	"profitify-backend/pkg/push"
+	"profitify-backend/pkg/ratelimit"
 	"profitify-backend/pkg/router"
 	"profitify-backend/pkg/server"
+	"profitify-backend/pkg/sqs"
+	"profitify-backend/pkg/tasks"
+	"profitify-backend/pkg/tracing"
 )
 
 func main() {
@@ -23,12 +65,19 @@ func run() error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Load configuration first
-	cfg := config.Load()
+	// Load configuration first, from the environment and the optional config
+	// file, and refuse to start with settings that cannot work together
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration: %w", err)
+	}
 
 	// Initialize logger with configuration
 	if err := logger.Init(&logger.Config{
-		Level:       os.Getenv("LOG_LEVEL"),
+		Level:       cfg.LogLevel,
 		Environment: cfg.Environment,
 		OutputPaths: []string{"stdout"},
 	}); err != nil {
@@ -39,19 +88,385 @@ func run() error {
 		_ = logger.Sync()
 	}()
 
-	// Initialize router
-	r := router.New(cfg.Environment)
+	// Log the effective configuration first, so misconfiguration such as a
+	// wrong table name shows up before the first failing request
+	log.Infow("starting profitify-backend", "config", cfg.Summary())
+
+	// Requests and jobs are traced through their service and DynamoDB calls
+	// when a tracing exporter is configured
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:    cfg.TracingExporter,
+		Endpoint:    cfg.TracingEndpoint,
+		SampleRate:  cfg.TracingSampleRate,
+		Environment: cfg.Environment,
+	}, log)
+	if err != nil {
+		return fmt.Errorf("failed to configure tracing: %w", err)
+	}
+	defer func() {
+		// Flush the spans of the last requests
+		flushCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Warnw("failed to flush traces", "error", err)
+		}
+	}()
+
+	// Initialize metrics and router
+	m := metrics.New()
+	r := router.New(cfg.Environment, m)
+	if err := r.WithTrustedProxies(cfg.TrustedProxies); err != nil {
+		return err
+	}
+
+	// STORAGE_BACKEND=memory serves tickers and API keys from process memory
+	// instead of DynamoDB, for demos without AWS credentials. It runs a single
+	// process, so the background workers, which coordinate replicas through
+	// DynamoDB, are not started.
+	memory, err := repository.OpenMemoryStore(cfg.StorageBackend, cfg.StorageSeed, cfg.TickerChangeRetention)
+	if err != nil {
+		return fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	// Create AWS clients. With the memory backend, DynamoDB is only reached by
+	// the features without an in-memory repository. Every repository shares
+	// this client and its connection pool.
+	db, err := awsclient.NewDynamoDB(ctx, awsclient.Config{
+		Region:      cfg.AWSRegion,
+		EndpointURL: cfg.AWSEndpointURL,
+		Observer:    m,
+		HTTP: awsclient.HTTPConfig{
+			MaxConnsPerHost:     cfg.AWSMaxConnsPerHost,
+			MaxIdleConnsPerHost: cfg.AWSMaxIdleConnsPerHost,
+			IdleConnTimeout:     cfg.AWSIdleConnTimeout,
+			DialTimeout:         cfg.AWSDialTimeout,
+			KeepAlive:           cfg.AWSKeepAlive,
+			Timeout:             cfg.AWSHTTPTimeout,
+		},
+		Connections: m,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	// Read-mostly data such as tickers is cached in memory or in Redis
+	appCache, err := cache.Open(cfg.CacheBackend, cfg.RedisURL)
+	if err != nil {
+		return fmt.Errorf("failed to open cache: %w", err)
+	}
+	if closer, ok := appCache.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// Triggered alerts and digests are pushed to registered mobile devices
+	// through FCM and APNs when their credentials are configured
+	pushSenders, err := push.Open(push.Config{
+		FCMCredentialsFile: cfg.FCMCredentialsFile,
+		APNSKeyFile:        cfg.APNSKeyFile,
+		APNSKeyID:          cfg.APNSKeyID,
+		APNSTeamID:         cfg.APNSTeamID,
+		APNSTopic:          cfg.APNSTopic,
+		APNSProduction:     cfg.APNSProduction,
+		Timeout:            cfg.PushTimeout,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to configure push notifications: %w", err)
+	}
+
+	// Notifications are rendered from the shipped templates, overridden by
+	// the deployment's
+	templates, err := notify.LoadTemplates(cfg.NotificationTemplatesDir)
+	if err != nil {
+		return err
+	}
 
-	// Initialize handlers with application context
-	handler, err := handlers.NewHandler(ctx)
+	// Domain events are published to EventBridge or SNS for other systems to
+	// react to, when an events backend is configured
+	publisher, err := events.Open(ctx, events.Config{
+		Backend:     cfg.EventsBackend,
+		BusName:     cfg.EventBusName,
+		TopicARN:    cfg.EventsTopicARN,
+		Source:      cfg.EventSource,
+		Region:      cfg.AWSRegion,
+		EndpointURL: cfg.AWSEndpointURL,
+		Timeout:     cfg.EventsTimeout,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to initialize handlers: %w", err)
+		return fmt.Errorf("failed to configure events: %w", err)
 	}
 
-	// Setup routes
-	r.SetupRoutes(handler)
+	// Replicas hold locks through the locks table, to elect the leader running
+	// the background workers and to run one-off jobs such as backfills once
+	var locker *lock.Locker
+	if memory == nil {
+		locker = lock.New(db, cfg.LocksTable, lock.Owner(), cfg.LockLease, log)
+		if err := m.Register(locker.Collector(metrics.Namespace)); err != nil {
+			return fmt.Errorf("failed to register lock metrics: %w", err)
+		}
+	}
+
+	// Background tasks run for the lifetime of the server, which waits for them
+	// to stop on shutdown
+	background := tasks.New(ctx, log)
+
+	// Redis is a soft dependency: while it is unreachable the server caches in
+	// memory, reports the cache degraded and reconnects in the background
+	if resilient, ok := appCache.(*cache.Resilient); ok {
+		pingCtx, cancel := context.WithTimeout(ctx, cfg.RedisRetryInterval)
+		err := resilient.Connect(pingCtx)
+		cancel()
+		if err != nil {
+			log.Warnw("redis unreachable, caching in memory until it answers", "error", err)
+			background.Go("cache-reconnect", func(ctx context.Context) error {
+				return resilient.Reconnect(ctx, cfg.RedisRetryInterval, log)
+			})
+		}
+		r.WithHealthCheck("cache", resilient.Health)
+	}
+
+	// Wire the feature modules; each builds the repositories and services it owns
+	deps := app.Deps{
+		Config:    cfg,
+		DB:        db,
+		Log:       log,
+		Cache:     appCache,
+		Metrics:   m,
+		Push:      pushSenders,
+		Templates: templates,
+		Memory:    memory,
+		Locker:    locker,
+		Tasks:     background,
+		Events:    publisher,
+		Clock:     clock.System,
+	}
+	// Every module publishes through the activity feed, which records the
+	// events of each account before they reach the event bus
+	activityModule := activity.Wire(deps)
+	deps.Events = activityModule.Record(deps.Events)
+	authModule := auth.Wire(deps)
+	marketModule := market.Wire(deps)
+	alertsModule := alerts.Wire(deps)
+	analyticsModule := analytics.Wire(deps)
+	digestsModule := digests.Wire(deps)
+	sessionsModule := sessions.Wire(deps)
+	usersModule := users.Wire(deps)
+	summariesModule := summaries.Wire(deps)
+	statsModule := stats.Wire(deps)
+
+	// Market data is ingested from Polygon.io or Alpha Vantage when an API
+	// key is configured: on demand through the admin API, and with Polygon
+	// optionally every trading day ahead of the post-close jobs that compute
+	// on it
+	var summarySource service.SummarySource
+	postCloseJobs := append(marketModule.PostCloseJobs(), digestsModule.PostCloseJobs()...)
+	postCloseJobs = append(postCloseJobs, summariesModule.PostCloseJobs()...)
+	postCloseJobs = append(postCloseJobs, statsModule.PostCloseJobs()...)
+	postCloseJobs = append(postCloseJobs, authModule.PurgeJobs()...)
+	if ingester := ingest.Wire(deps); ingester != nil {
+		summarySource = ingester.Provider()
+		if cfg.IngestEODEnabled {
+			postCloseJobs = append(ingester.EODJobs(), postCloseJobs...)
+		}
+	}
+
+	if cfg.BootstrapAdminKey != "" {
+		if err := authModule.Keys().EnsureKey(ctx, cfg.BootstrapAdminKey, "bootstrap-admin", true); err != nil {
+			return fmt.Errorf("failed to store bootstrap admin API key: %w", err)
+		}
+	}
+
+	// Without DynamoDB there is no locks table to elect a leader through, nor
+	// tables for the alerts and request analytics the workers read and write
+	var leadership admin.LeadershipReporter
+	if memory != nil {
+		log.Warnw("background workers, request analytics and quotas are disabled with the memory storage backend")
+	} else {
+		// Replicas elect a leader to run background workers
+		elector := lock.NewElector(locker, "background-workers", log)
+		leadership = elector
+
+		// Run post-close jobs on the leader. Each job is also locked per date in case
+		// leadership changes while it runs. The leader also resumes long jobs
+		// interrupted by a deploy or crash.
+		postClose := jobs.NewDailyRunner(cfg.PostCloseJobsAt, log, postCloseJobs...).WithLocker(locker).WithClock(deps.Clock)
+
+		// Serverless deployments run the post-close jobs when an EventBridge
+		// schedule triggers them instead: as a Lambda function serving no
+		// requests, or by consuming the queue the schedule sends to
+		switch cfg.SchedulerMode {
+		case jobs.SchedulerLambda:
+			api := os.Getenv(lambda.RuntimeAPIEnv)
+			if api == "" {
+				return fmt.Errorf("SCHEDULER_MODE=lambda requires %s, which Lambda sets", lambda.RuntimeAPIEnv)
+			}
+			log.Infow("serving scheduled triggers as a Lambda function")
+			err := lambda.New(api).Serve(ctx, postClose.HandleInvocation)
+			if stopErr := background.Stop(cfg.ShutdownTimeout); stopErr != nil {
+				log.Errorw("failed to stop background tasks", "error", stopErr)
+			}
+			return err
+		case jobs.SchedulerSQS:
+			awsCfg, err := awsclient.LoadConfig(ctx, awsclient.Config{Region: cfg.AWSRegion})
+			if err != nil {
+				return fmt.Errorf("failed to configure the scheduler queue: %w", err)
+			}
+			queue := sqs.New(awsCfg, cfg.SchedulerQueueURL, cfg.AWSEndpointURL, sqs.DefaultTimeout)
+			background.Go("scheduled-triggers", func(ctx context.Context) error {
+				return postClose.ConsumeQueue(ctx, queue)
+			})
+		}
+
+		// The changes of the tickers and daily summaries are consumed from their
+		// tables' streams: by every replica for its cache, and by the leader for
+		// the stats and bars derived from them
+		var changeStreams *streams.Consumers
+		if cfg.StreamsEnabled {
+			awsCfg, err := awsclient.LoadConfig(ctx, awsclient.Config{Region: cfg.AWSRegion})
+			if err != nil {
+				return fmt.Errorf("failed to configure the table streams: %w", err)
+			}
+			changeStreams, err = streams.Wire(ctx, deps,
+				dynamostreams.New(awsCfg, cfg.AWSEndpointURL, dynamostreams.DefaultTimeout), statsModule.Stats())
+			if err != nil {
+				return fmt.Errorf("failed to configure the table streams: %w", err)
+			}
+			background.Go("table-streams", changeStreams.RunReplica)
+		}
+
+		background.Go("leader-election", func(ctx context.Context) error {
+			elector.Run(ctx, func(ctx context.Context) {
+				resumed := make(chan struct{})
+				go func() {
+					defer close(resumed)
+					if err := marketModule.ResumeBackfills(ctx); err != nil && ctx.Err() == nil {
+						log.Errorw("failed to resume breadth backfills", "error", err)
+					}
+				}()
+				consumed := make(chan struct{})
+				go func() {
+					defer close(consumed)
+					if changeStreams != nil {
+						_ = changeStreams.RunLeader(ctx)
+					}
+				}()
+				// Digests scheduled at their subscribers' local times are
+				// sent whether the post-close jobs run in process or from the
+				// scheduler queue
+				scheduled := make(chan struct{})
+				go func() {
+					defer close(scheduled)
+					digestsModule.RunScheduler(ctx)
+				}()
+				if cfg.SchedulerMode == jobs.SchedulerInternal {
+					postClose.Start(ctx)
+				}
+				<-resumed
+				<-consumed
+				<-scheduled
+			})
+			return nil
+		})
+
+		// Every replica evaluates alerts; marking an alert triggered is conditional,
+		// so each alert is notified once
+		background.Go("alert-evaluator", alertsModule.RunEvaluator)
+		background.Go("analytics-flush", analyticsModule.RunFlusher)
+
+		// Every replica stores the market data messages of the ingestion
+		// queue; SQS hands each message to one of them at a time
+		if cfg.IngestQueueURL != "" {
+			awsCfg, err := awsclient.LoadConfig(ctx, awsclient.Config{Region: cfg.AWSRegion})
+			if err != nil {
+				return fmt.Errorf("failed to configure the ingestion queue: %w", err)
+			}
+			var deadLetters ingest.DeadLetters
+			if cfg.IngestDLQURL != "" {
+				deadLetters = sqs.New(awsCfg, cfg.IngestDLQURL, cfg.AWSEndpointURL, sqs.DefaultTimeout)
+			}
+			worker := ingest.WireQueue(deps, sqs.New(awsCfg, cfg.IngestQueueURL, cfg.AWSEndpointURL, sqs.DefaultTimeout), deadLetters)
+			background.Go("ingest-queue", worker.Run)
+		}
+	}
+
+	// Setup routes; each module registers its own. API requests are counted
+	// for the request analytics and rate limited per key or client IP.
+	var quotas middleware.QuotaEnforcer
+	if memory == nil {
+		r.WithAnalytics(analyticsModule.Recorder())
+		quotas = authModule.Quotas()
+	}
+	// Server errors are kept for the admin errors summary
+	errorLog := errorlog.New(errorlog.DefaultCapacity)
+	r.WithErrorLog(errorLog)
+	r.WithRateLimits(
+		ratelimit.Limit{Rate: cfg.RateLimitRPS, Burst: cfg.RateLimitBurst},
+		ratelimit.Limit{Rate: cfg.AdminRateLimitRPS, Burst: cfg.AdminRateLimitBurst},
+	)
+	r.WithClientRateLimit(ratelimit.Limit{Rate: cfg.ClientRateLimitRPS, Burst: cfg.ClientRateLimitBurst})
+	// Large ticker lists and histories are compressed for clients accepting it
+	if cfg.CompressionEnabled {
+		r.WithCompression(cfg.CompressionMinSize, cfg.CompressionExclude)
+	}
+	r.WithCacheControl(cfg.CacheControl)
+	// Routes on their way out announce it, as do the unversioned aliases
+	if err := r.WithDeprecations(cfg.DeprecatedRoutes); err != nil {
+		return err
+	}
+	if err := r.WithLegacySunset(cfg.LegacyAPISunset); err != nil {
+		return err
+	}
+	tickersModule := tickers.Wire(deps)
+	r.SetupRoutes(router.AuthConfig{
+		Authenticator: authModule.Keys(),
+		RequireAPIKey: cfg.AuthEnabled,
+		Quotas:        quotas,
+		Signatures:    authModule.Signatures(),
+		Sessions:      sessionsModule.Sessions(),
+		Users:         usersModule.Users(),
+		TermsVersion:  cfg.TermsVersion,
+	},
+		tickersModule,
+		summariesModule,
+		indicators.Wire(deps),
+		statsModule,
+		screener.Wire(deps),
+		portfolios.Wire(deps),
+		watchlists.Wire(deps),
+		bundle.Wire(deps),
+		alertsModule,
+		digestsModule,
+		devices.Wire(deps),
+		sessionsModule,
+		activityModule,
+		usersModule,
+		analyticsModule,
+		marketModule,
+		authModule,
+		admin.Wire(deps, summarySource, leadership, errorLog),
+	)
+
+	// The gRPC API serves the ticker and daily summary services of the same
+	// modules on its own port
+	if cfg.GRPCPort != "" {
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			return fmt.Errorf("failed to listen for grpc: %w", err)
+		}
+		grpcSrv := grpcserver.New(grpcserver.AuthConfig{
+			Authenticator: authModule.Keys(),
+			RequireAPIKey: cfg.AuthEnabled,
+		}, cfg.ShutdownTimeout, log, tickersModule, summariesModule)
+		background.Go("grpc-server", func(ctx context.Context) error {
+			return grpcSrv.Serve(ctx, lis)
+		})
+	}
 
 	// Create and start server with context
 	srv := server.New(r.Engine(), cfg, log)
-	return srv.Start(ctx)
+	err = srv.Start(ctx)
+
+	if stopErr := background.Stop(cfg.ShutdownTimeout); stopErr != nil {
+		log.Errorw("failed to stop background tasks", "error", stopErr)
+	}
+	return err
 }
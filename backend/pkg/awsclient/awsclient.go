@@ -0,0 +1,139 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"profitify-backend/pkg/tracing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/smithy-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Observer is notified of every DynamoDB API call made through the client
+type Observer interface {
+	ObserveDynamoDB(operation, table string, d time.Duration, err error)
+}
+
+// Config overrides the defaults of the AWS credential chain. Empty fields keep
+// the SDK defaults.
+type Config struct {
+	Region      string
+	EndpointURL string
+	// Observer, when set, records the latency and outcome of each call
+	Observer Observer
+	HTTP     HTTPConfig
+	// Connections, when set, records whether each request reused a connection
+	Connections ConnectionObserver
+}
+
+// LoadConfig resolves the region and credentials of the default AWS credential
+// chain, for clients other than DynamoDB's. Every client made from the result
+// shares one pool of connections.
+func LoadConfig(ctx context.Context, cfg Config) (aws.Config, error) {
+	opts := []func(*config.LoadOptions) error{config.WithHTTPClient(newHTTPClient(cfg))}
+	if cfg.Region != "" {
+		opts = append(opts, config.WithRegion(cfg.Region))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return awsCfg, nil
+}
+
+// NewDynamoDB creates a DynamoDB client from the default AWS credential chain.
+// Clients are safe for concurrent use, and each holds its own connection pool,
+// so a process creates one and shares it between its repositories.
+func NewDynamoDB(ctx context.Context, cfg Config) (*dynamodb.Client, error) {
+	awsCfg, err := LoadConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamodb.NewFromConfig(awsCfg, func(o *dynamodb.Options) {
+		if cfg.EndpointURL != "" {
+			o.BaseEndpoint = aws.String(cfg.EndpointURL)
+		}
+		if cfg.Observer != nil {
+			o.APIOptions = append(o.APIOptions, observe(cfg.Observer))
+		}
+		o.APIOptions = append(o.APIOptions, traceCalls)
+	}), nil
+}
+
+// traceCalls traces each operation as a client span, a child of the span of
+// the request or job making it
+func traceCalls(stack *middleware.Stack) error {
+	operation := stack.ID()
+	return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("TraceDynamoDB",
+		func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+			attrs := []attribute.KeyValue{
+				semconv.DBSystemDynamoDB,
+				semconv.RPCService("DynamoDB"),
+				semconv.RPCMethod(operation),
+			}
+			if table := tableName(in.Parameters); table != "" {
+				attrs = append(attrs, semconv.AWSDynamoDBTableNames(table))
+			}
+			ctx, span := tracing.StartKind(ctx, "DynamoDB."+operation, trace.SpanKindClient, attrs...)
+			out, metadata, err := next.HandleInitialize(ctx, in)
+			tracing.End(span, err)
+			return out, metadata, err
+		}), middleware.Before)
+}
+
+// observe times each operation at the start of the middleware stack, so the
+// recorded latency includes retries
+func observe(observer Observer) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		operation := stack.ID()
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("ObserveDynamoDB",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				start := time.Now()
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				observer.ObserveDynamoDB(operation, tableName(in.Parameters), time.Since(start), err)
+				return out, metadata, err
+			}), middleware.Before)
+	}
+}
+
+// tableName returns the table an operation targets. Batch operations spanning
+// several tables are reported without one.
+func tableName(input any) string {
+	var table *string
+	switch in := input.(type) {
+	case *dynamodb.GetItemInput:
+		table = in.TableName
+	case *dynamodb.PutItemInput:
+		table = in.TableName
+	case *dynamodb.UpdateItemInput:
+		table = in.TableName
+	case *dynamodb.DeleteItemInput:
+		table = in.TableName
+	case *dynamodb.QueryInput:
+		table = in.TableName
+	case *dynamodb.ScanInput:
+		table = in.TableName
+	case *dynamodb.BatchWriteItemInput:
+		if len(in.RequestItems) == 1 {
+			for name := range in.RequestItems {
+				return name
+			}
+		}
+	case *dynamodb.BatchGetItemInput:
+		if len(in.RequestItems) == 1 {
+			for name := range in.RequestItems {
+				return name
+			}
+		}
+	}
+	return aws.ToString(table)
+}
@@ -0,0 +1,130 @@
+package awsclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type call struct {
+	operation string
+	table     string
+	err       error
+}
+
+type recordingObserver struct {
+	calls []call
+}
+
+func (r *recordingObserver) ObserveDynamoDB(operation, table string, d time.Duration, err error) {
+	r.calls = append(r.calls, call{operation: operation, table: table, err: err})
+}
+
+func TestObserve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		if r.Header.Get("X-Amz-Target") == "DynamoDB_20120810.PutItem" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"__type":"com.amazonaws.dynamodb.v20120810#ConditionalCheckFailedException","message":"The conditional request failed"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	observer := &recordingObserver{}
+	client := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+		APIOptions:   []func(*middleware.Stack) error{observe(observer)},
+	})
+
+	ctx := context.Background()
+	key := map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "a"}}
+
+	_, err := client.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("locks"), Key: key})
+	require.NoError(t, err)
+
+	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{TableName: aws.String("locks"), Item: key})
+	var condErr *types.ConditionalCheckFailedException
+	require.ErrorAs(t, err, &condErr)
+
+	require.Len(t, observer.calls, 2)
+	assert.Equal(t, "GetItem", observer.calls[0].operation)
+	assert.Equal(t, "locks", observer.calls[0].table)
+	assert.NoError(t, observer.calls[0].err)
+	assert.Equal(t, "PutItem", observer.calls[1].operation)
+	assert.ErrorAs(t, observer.calls[1].err, &condErr)
+}
+
+func TestTableName(t *testing.T) {
+	assert.Equal(t, "settings", tableName(&dynamodb.QueryInput{TableName: aws.String("settings")}))
+	assert.Equal(t, "bars", tableName(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{"bars": nil},
+	}))
+	assert.Equal(t, "", tableName(&dynamodb.BatchWriteItemInput{
+		RequestItems: map[string][]types.WriteRequest{"bars": nil, "signals": nil},
+	}))
+	assert.Equal(t, "", tableName(&dynamodb.ListTablesInput{}))
+}
+
+type recordingConnections struct {
+	reused []bool
+}
+
+func (r *recordingConnections) ObserveConnection(host string, reused bool) {
+	r.reused = append(r.reused, reused)
+}
+
+func TestHTTPClient_ReusesConnections(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	connections := &recordingConnections{}
+	client := dynamodb.New(dynamodb.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  aws.AnonymousCredentials{},
+		HTTPClient:   newHTTPClient(Config{HTTP: HTTPConfig{MaxIdleConnsPerHost: 2}, Connections: connections}),
+	})
+
+	key := map[string]types.AttributeValue{"name": &types.AttributeValueMemberS{Value: "a"}}
+	for range 3 {
+		_, err := client.GetItem(context.Background(), &dynamodb.GetItemInput{TableName: aws.String("locks"), Key: key})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []bool{false, true, true}, connections.reused, "sequential calls share one pooled connection")
+}
+
+func TestHTTPClient_Transport(t *testing.T) {
+	client := newHTTPClient(Config{HTTP: HTTPConfig{
+		MaxConnsPerHost:     50,
+		MaxIdleConnsPerHost: 200,
+		IdleConnTimeout:     time.Minute,
+		Timeout:             5 * time.Second,
+	}}).(*awshttp.BuildableClient)
+
+	transport := client.GetTransport()
+	assert.Equal(t, 50, transport.MaxConnsPerHost)
+	assert.Equal(t, 200, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 200, transport.MaxIdleConns, "the total idle pool holds every host's")
+	assert.Equal(t, time.Minute, transport.IdleConnTimeout)
+	assert.Equal(t, 5*time.Second, client.GetTimeout())
+
+	defaults := newHTTPClient(Config{}).(*awshttp.BuildableClient).GetTransport()
+	assert.Equal(t, awshttp.DefaultHTTPTransportMaxIdleConnsPerHost, defaults.MaxIdleConnsPerHost)
+}
@@ -0,0 +1,87 @@
+package awsclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// maxErrorBody bounds how much of an error response is quoted in errors
+const maxErrorBody = 512
+
+// SignedClient posts SigV4 signed requests to one AWS service endpoint, for
+// the few calls to services whose SDK clients are not worth depending on
+type SignedClient struct {
+	endpoint    string
+	service     string
+	region      string
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	client      *http.Client
+}
+
+// NewSignedClient targets service in the region of awsCfg, or at endpointURL
+// when set
+func NewSignedClient(awsCfg aws.Config, service, endpointURL string, timeout time.Duration) *SignedClient {
+	endpoint := endpointURL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", service, awsCfg.Region)
+	}
+	return &SignedClient{
+		endpoint:    endpoint,
+		service:     service,
+		region:      awsCfg.Region,
+		credentials: awsCfg.Credentials,
+		signer:      v4.NewSigner(),
+		client:      &http.Client{Timeout: timeout},
+	}
+}
+
+// Post sends body with the headers and returns the body of a 2xx response
+func (a *SignedClient) Post(ctx context.Context, body []byte, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", a.service, err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	if a.credentials == nil {
+		return nil, fmt.Errorf("no AWS credentials to sign %s requests with", a.service)
+	}
+	credentials, err := a.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := a.signer.SignHTTP(ctx, credentials, req, hex.EncodeToString(hash[:]), a.service, a.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign %s request: %w", a.service, err)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s request failed: %w", a.service, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s response: %w", a.service, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		if len(respBody) > maxErrorBody {
+			respBody = respBody[:maxErrorBody]
+		}
+		return nil, fmt.Errorf("%s responded %d: %s", a.service, resp.StatusCode, respBody)
+	}
+	return respBody, nil
+}
@@ -0,0 +1,85 @@
+package awsclient
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+)
+
+// HTTPConfig tunes the connection pool of the HTTP client the AWS SDK clients
+// send their requests with. Zero fields keep the SDK defaults.
+type HTTPConfig struct {
+	// MaxConnsPerHost bounds the connections to one endpoint, dialing or in
+	// use; requests beyond it wait for a connection
+	MaxConnsPerHost int
+	// MaxIdleConnsPerHost is how many connections to one endpoint are kept
+	// open between requests. Bursts of more concurrent requests dial
+	// connections that are closed once done, so it should cover the usual
+	// concurrency.
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	DialTimeout         time.Duration
+	KeepAlive           time.Duration
+	// Timeout bounds each HTTP request, including reading its response
+	Timeout time.Duration
+}
+
+// ConnectionObserver is notified of the connection each request is sent on,
+// to show how often pooled connections are reused
+type ConnectionObserver interface {
+	ObserveConnection(host string, reused bool)
+}
+
+// newHTTPClient builds the HTTP client of cfg
+func newHTTPClient(cfg Config) aws.HTTPClient {
+	h := cfg.HTTP
+	client := awshttp.NewBuildableClient().
+		WithTransportOptions(func(t *http.Transport) {
+			if h.MaxConnsPerHost > 0 {
+				t.MaxConnsPerHost = h.MaxConnsPerHost
+			}
+			if h.MaxIdleConnsPerHost > 0 {
+				t.MaxIdleConnsPerHost = h.MaxIdleConnsPerHost
+				t.MaxIdleConns = max(t.MaxIdleConns, h.MaxIdleConnsPerHost)
+			}
+			if h.IdleConnTimeout > 0 {
+				t.IdleConnTimeout = h.IdleConnTimeout
+			}
+		}).
+		WithDialerOptions(func(d *net.Dialer) {
+			if h.DialTimeout > 0 {
+				d.Timeout = h.DialTimeout
+			}
+			if h.KeepAlive > 0 {
+				d.KeepAlive = h.KeepAlive
+			}
+		})
+	if h.Timeout > 0 {
+		client = client.WithTimeout(h.Timeout)
+	}
+
+	if cfg.Connections == nil {
+		return client
+	}
+	return &observedClient{client: client, observer: cfg.Connections}
+}
+
+// observedClient reports the connection of each request to an observer
+type observedClient struct {
+	client   aws.HTTPClient
+	observer ConnectionObserver
+}
+
+func (c *observedClient) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			c.observer.ObserveConnection(host, info.Reused)
+		},
+	}
+	return c.client.Do(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
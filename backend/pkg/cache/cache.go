@@ -0,0 +1,162 @@
+// Package cache stores short-lived values in process memory or in Redis, for
+// read-through caching in front of slower stores.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Backends selectable by configuration
+const (
+	BackendNone   = "none"
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// Cache stores byte values under string keys until their TTL expires
+type Cache interface {
+	// Get returns the value of key and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Open returns the cache of backend, or nil for BackendNone. The Redis backend
+// connects to redisURL, e.g. redis://localhost:6379/0, and is shared by all
+// replicas; it is returned as a Resilient cache, serving from memory until
+// Redis answers its first ping. The memory backend is private to this process.
+func Open(backend, redisURL string) (Cache, error) {
+	switch backend {
+	case BackendNone:
+		return nil, nil
+	case BackendMemory:
+		return NewMemory(), nil
+	case BackendRedis:
+		opts, err := redis.ParseURL(redisURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis url: %w", err)
+		}
+		return NewResilient(NewRedis(redis.NewClient(opts))), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", backend)
+	}
+}
+
+// Memory is a Cache in process memory. Expired entries are dropped when read
+// and swept when the cache grows.
+type Memory struct {
+	mu        sync.Mutex
+	entries   map[string]memoryEntry
+	sweepSize int
+}
+
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func NewMemory() *Memory {
+	return &Memory{
+		entries:   make(map[string]memoryEntry),
+		sweepSize: 1024,
+	}
+}
+
+func (m *Memory) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *Memory) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if len(m.entries) >= m.sweepSize {
+		for k, entry := range m.entries {
+			if now.After(entry.expires) {
+				delete(m.entries, k)
+			}
+		}
+		// Sweep again once the live entries have doubled
+		m.sweepSize = max(m.sweepSize, 2*len(m.entries))
+	}
+
+	m.entries[key] = memoryEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+func (m *Memory) Delete(ctx context.Context, keys ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.entries, key)
+	}
+	return nil
+}
+
+// Redis is a Cache in Redis
+type Redis struct {
+	client *redis.Client
+}
+
+func NewRedis(client *redis.Client) *Redis {
+	return &Redis{client: client}
+}
+
+func (r *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get %s from redis: %w", key, err)
+	}
+	return value, true, nil
+}
+
+func (r *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set %s in redis: %w", key, err)
+	}
+	return nil
+}
+
+func (r *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete from redis: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that Redis answers
+func (r *Redis) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping redis: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connections to Redis
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
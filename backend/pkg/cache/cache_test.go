@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory()
+
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, c.Set(ctx, "expired", []byte("2"), -time.Second))
+
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	_, ok, _ = c.Get(ctx, "expired")
+	assert.False(t, ok, "expired entries are not served")
+
+	require.NoError(t, c.Delete(ctx, "a", "missing"))
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok)
+}
+
+func TestMemory_Sweep(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory()
+	c.sweepSize = 2
+
+	require.NoError(t, c.Set(ctx, "a", nil, -time.Second))
+	require.NoError(t, c.Set(ctx, "b", nil, -time.Second))
+	require.NoError(t, c.Set(ctx, "c", nil, time.Minute))
+
+	assert.Len(t, c.entries, 1, "expired entries are swept once the cache grows")
+}
+
+// stress runs work on workers goroutines at once, for iterations each, for
+// the race detector to catch unsynchronized access: go test -race
+func stress(workers, iterations int, work func(worker, i int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for i := 0; i < iterations; i++ {
+				work(w, i)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+}
+
+func TestMemory_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemory()
+	c.sweepSize = 8
+
+	// Workers share a few keys, each value naming its key, and expire some
+	// entries at once so the sweeps run alongside the reads
+	stress(8, 500, func(worker, i int) {
+		key := fmt.Sprintf("key-%d", (worker+i)%16)
+		switch i % 4 {
+		case 0:
+			assert.NoError(t, c.Set(ctx, key, []byte(key+"="+fmt.Sprint(worker)), time.Minute))
+		case 1:
+			assert.NoError(t, c.Set(ctx, key, []byte(key+"=expired"), -time.Second))
+		case 2:
+			assert.NoError(t, c.Delete(ctx, key))
+		default:
+			value, ok, err := c.Get(ctx, key)
+			assert.NoError(t, err)
+			if ok {
+				assert.True(t, strings.HasPrefix(string(value), key+"="), "%s holds %s", key, value)
+				assert.NotEqual(t, key+"=expired", string(value))
+			}
+		}
+	})
+}
+
+func TestOpen(t *testing.T) {
+	c, err := Open(BackendNone, "")
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	c, err = Open(BackendMemory, "")
+	require.NoError(t, err)
+	assert.IsType(t, &Memory{}, c)
+
+	c, err = Open(BackendRedis, "redis://localhost:6379/0")
+	require.NoError(t, err)
+	require.IsType(t, &Resilient{}, c)
+	assert.IsType(t, &Redis{}, c.(*Resilient).remote)
+	assert.False(t, c.(*Resilient).Connected(), "redis is used once it answers")
+	require.NoError(t, c.(*Resilient).Close())
+
+	_, err = Open(BackendRedis, "localhost:6379")
+	assert.Error(t, err)
+
+	_, err = Open("memcached", "")
+	assert.Error(t, err)
+}
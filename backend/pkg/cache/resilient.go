@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrNotConnected is reported by Health until a Resilient cache has reached
+// its remote cache
+var ErrNotConnected = errors.New("cache not connected")
+
+// Remote is a Cache in another process, such as Redis, that may be
+// unreachable
+type Remote interface {
+	Cache
+	// Ping checks that the remote cache answers
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// Resilient is a Cache that serves from process memory until its remote cache
+// answers a ping, then switches to it for good. Caching is an optimization, so
+// the server starts with Redis down, degraded rather than failed, and picks
+// Redis up once it is back. Errors of the remote cache after the switch are
+// returned as they are.
+type Resilient struct {
+	remote   Remote
+	fallback *Memory
+
+	mu        sync.RWMutex
+	connected bool
+	lastErr   error
+	deleted   map[string]struct{}
+}
+
+// NewResilient returns a cache serving from memory until Connect reaches remote
+func NewResilient(remote Remote) *Resilient {
+	return &Resilient{
+		remote:   remote,
+		fallback: NewMemory(),
+		lastErr:  ErrNotConnected,
+		deleted:  make(map[string]struct{}),
+	}
+}
+
+// Connect pings the remote cache and, once it answers, switches to it
+func (r *Resilient) Connect(ctx context.Context) error {
+	if r.Connected() {
+		return nil
+	}
+	err := r.remote.Ping(ctx)
+
+	// Deletes are held off while the pending ones are replayed
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil && len(r.deleted) > 0 {
+		keys := make([]string, 0, len(r.deleted))
+		for key := range r.deleted {
+			keys = append(keys, key)
+		}
+		err = r.remote.Delete(ctx, keys...)
+	}
+	if err != nil {
+		r.lastErr = err
+		return err
+	}
+	r.connected = true
+	r.lastErr = nil
+	// The entries cached meanwhile are dropped with the fallback
+	r.fallback = NewMemory()
+	r.deleted = nil
+	return nil
+}
+
+// Reconnect calls Connect every interval until the remote cache answers or
+// ctx is done, for running as a background task
+func (r *Resilient) Reconnect(ctx context.Context, interval time.Duration, log *zap.SugaredLogger) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := r.Connect(pingCtx)
+		cancel()
+		if err == nil {
+			log.Infow("connected to the remote cache, no longer caching in memory")
+			return nil
+		}
+		log.Debugw("remote cache still unreachable", "error", err)
+	}
+}
+
+// Connected reports whether the remote cache is in use
+func (r *Resilient) Connected() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.connected
+}
+
+// Health returns nil once the remote cache is in use, or why it is not
+func (r *Resilient) Health(ctx context.Context) error {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.lastErr
+}
+
+func (r *Resilient) current() Cache {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.connected {
+		return r.remote
+	}
+	return r.fallback
+}
+
+func (r *Resilient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return r.current().Get(ctx, key)
+}
+
+func (r *Resilient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.current().Set(ctx, key, value, ttl)
+}
+
+func (r *Resilient) Delete(ctx context.Context, keys ...string) error {
+	r.mu.Lock()
+	if !r.connected {
+		for _, key := range keys {
+			r.deleted[key] = struct{}{}
+		}
+		defer r.mu.Unlock()
+		return r.fallback.Delete(ctx, keys...)
+	}
+	r.mu.Unlock()
+	return r.remote.Delete(ctx, keys...)
+}
+
+// Close closes the remote cache
+func (r *Resilient) Close() error {
+	return r.remote.Close()
+}
@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakyRemote is a Remote in memory that answers once up is set
+type flakyRemote struct {
+	*Memory
+	up      bool
+	deleted []string
+}
+
+func (f *flakyRemote) Ping(ctx context.Context) error {
+	if !f.up {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *flakyRemote) Delete(ctx context.Context, keys ...string) error {
+	f.deleted = append(f.deleted, keys...)
+	return f.Memory.Delete(ctx, keys...)
+}
+
+func (f *flakyRemote) Close() error {
+	return nil
+}
+
+func TestResilient(t *testing.T) {
+	ctx := context.Background()
+	remote := &flakyRemote{Memory: NewMemory()}
+	require.NoError(t, remote.Set(ctx, "stale", []byte("old"), time.Minute))
+	c := NewResilient(remote)
+
+	assert.ErrorIs(t, c.Health(ctx), ErrNotConnected)
+	assert.EqualError(t, c.Connect(ctx), "connection refused")
+	assert.EqualError(t, c.Health(ctx), "connection refused")
+
+	// Unreachable, values are cached in memory
+	require.NoError(t, c.Set(ctx, "a", []byte("1"), time.Minute))
+	value, ok, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	_, ok, _ = remote.Get(ctx, "a")
+	assert.False(t, ok)
+	require.NoError(t, c.Delete(ctx, "stale"))
+
+	remote.up = true
+	require.NoError(t, c.Connect(ctx))
+	assert.True(t, c.Connected())
+	assert.NoError(t, c.Health(ctx))
+	assert.Equal(t, []string{"stale"}, remote.deleted, "deletes missed while unreachable are replayed")
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok, "the memory entries are dropped on the switch")
+
+	require.NoError(t, c.Set(ctx, "b", []byte("2"), time.Minute))
+	_, ok, _ = remote.Get(ctx, "b")
+	assert.True(t, ok, "connected, values are cached remotely")
+}
+
+func TestResilient_Reconnect(t *testing.T) {
+	remote := &flakyRemote{Memory: NewMemory(), up: true}
+	c := NewResilient(remote)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, c.Reconnect(ctx, time.Millisecond, zap.NewNop().Sugar()))
+	assert.True(t, c.Connected())
+	assert.NoError(t, ctx.Err(), "reconnecting stops once connected")
+
+	down := NewResilient(&flakyRemote{Memory: NewMemory()})
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.NoError(t, down.Reconnect(ctx, time.Millisecond, zap.NewNop().Sugar()))
+	assert.False(t, down.Connected())
+}
+
+// reachableRemote is a Remote in memory that always answers
+type reachableRemote struct {
+	*Memory
+}
+
+func (reachableRemote) Ping(ctx context.Context) error { return nil }
+
+func (reachableRemote) Close() error { return nil }
+
+func TestResilient_ConcurrentConnect(t *testing.T) {
+	ctx := context.Background()
+	remote := reachableRemote{Memory: NewMemory()}
+	c := NewResilient(remote)
+
+	// Reads, writes and deletes go on while the cache switches to the remote
+	// one, which one of the workers connects to midway
+	stress(8, 300, func(worker, i int) {
+		key := fmt.Sprintf("key-%d", i%10)
+		if worker == 0 && i == 150 {
+			assert.NoError(t, c.Connect(ctx))
+		}
+		switch (worker + i) % 3 {
+		case 0:
+			assert.NoError(t, c.Set(ctx, key, []byte(key), time.Minute))
+		case 1:
+			assert.NoError(t, c.Delete(ctx, key))
+		default:
+			value, ok, err := c.Get(ctx, key)
+			assert.NoError(t, err)
+			if ok {
+				assert.Equal(t, key, string(value))
+			}
+			_ = c.Connected()
+			_ = c.Health(ctx)
+		}
+	})
+
+	assert.True(t, c.Connected())
+	require.NoError(t, c.Set(ctx, "after", []byte("1"), time.Minute))
+	_, ok, _ := remote.Get(ctx, "after")
+	assert.True(t, ok, "every write after the switch reaches the remote cache")
+}
@@ -0,0 +1,92 @@
+// Package clock tells the time. Services and schedulers take a Clock instead
+// of calling time.Now, so tests can freeze time and advance it.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass
+type Clock interface {
+	Now() time.Time
+	// After sends the time on the returned channel once d has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// System is the clock of the host
+var System Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Fake is a Clock standing still until it is set or advanced, which fires the
+// waits that have come due. It is safe for concurrent use.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []waiter
+}
+
+type waiter struct {
+	at time.Time
+	c  chan time.Time
+}
+
+// NewFake returns a clock stopped at now
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	if d <= 0 {
+		c <- f.now
+		return c
+	}
+	f.waiters = append(f.waiters, waiter{at: f.now.Add(d), c: c})
+	return c
+}
+
+// Advance moves the clock forward by d
+func (f *Fake) Advance(d time.Duration) {
+	f.Set(f.Now().Add(d))
+}
+
+// Set moves the clock to now, firing the waits due by then. Moving it back
+// fires none.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = now
+	pending := f.waiters[:0]
+	for _, w := range f.waiters {
+		if w.at.After(now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.c <- now
+	}
+	f.waiters = pending
+}
+
+// Waiters counts the waits not yet due, so tests can tell when the code under
+// test has started waiting before advancing the clock past it
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
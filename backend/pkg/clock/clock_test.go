@@ -0,0 +1,46 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake(t *testing.T) {
+	start := time.Date(2025, 3, 5, 16, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+	assert.Equal(t, start, c.Now())
+	assert.Equal(t, start, c.Now(), "the clock stands still")
+
+	soon := c.After(time.Minute)
+	later := c.After(time.Hour)
+	assert.Equal(t, 2, c.Waiters())
+
+	c.Advance(30 * time.Second)
+	assert.Empty(t, soon)
+
+	c.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-soon)
+	assert.Empty(t, later)
+	assert.Equal(t, 1, c.Waiters())
+
+	c.Set(start)
+	assert.Empty(t, later, "moving the clock back fires nothing")
+
+	c.Set(start.Add(2 * time.Hour))
+	assert.Equal(t, start.Add(2*time.Hour), <-later)
+	assert.Zero(t, c.Waiters())
+
+	assert.Equal(t, c.Now(), <-c.After(0), "waits already due fire at once")
+}
+
+func TestSystem(t *testing.T) {
+	before := time.Now()
+	assert.False(t, System.Now().Before(before))
+	select {
+	case <-System.After(time.Millisecond):
+	case <-time.After(time.Second):
+		t.Fatal("System.After did not fire")
+	}
+}
@@ -8,35 +8,511 @@ import (
 type Config struct {
 	Port            string
 	Environment     string
+	LogLevel        string
 	ShutdownTimeout time.Duration
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	IdleTimeout     time.Duration
-}
 
-func Load() *Config {
-	return &Config{
-		Port:            getEnv("PORT", "8080"),
-		Environment:     getEnv("ENVIRONMENT", "development"),
-		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
-		ReadTimeout:     getEnvDuration("READ_TIMEOUT", 15*time.Second),
-		WriteTimeout:    getEnvDuration("WRITE_TIMEOUT", 15*time.Second),
-		IdleTimeout:     getEnvDuration("IDLE_TIMEOUT", 60*time.Second),
-	}
+	// GRPCPort serves the gRPC API on its own port when set; empty disables it
+	GRPCPort string
+
+	// PostCloseJobsAt is the time after midnight (market time) when post-close jobs run
+	PostCloseJobsAt time.Duration
+	// SchedulerMode is "internal", "sqs" or "lambda": whether post-close jobs
+	// run on the in-process timer or when an EventBridge schedule triggers them,
+	// through SchedulerQueueURL or by invoking the process as a Lambda function
+	SchedulerMode         string
+	SchedulerQueueURL     string
+	ScannerGapPercent     float64
+	ScannerVolumeMultiple float64
+	ScannerVolumeLookback int
+	HeatmapCacheTTL       time.Duration
+	ScreenerCacheTTL      time.Duration
+	StatsBenchmark        string
+	PurgeWritesPerSecond  int
+	PurgeConfirmationTTL  time.Duration
+	LockLease             time.Duration
+	AlertEvalInterval     time.Duration
+	// DigestScheduleInterval is how often the leader sends the digests due
+	// at their subscribers' local times
+	DigestScheduleInterval time.Duration
+
+	// AlertWebhookURL receives triggered alerts as JSON when set
+	AlertWebhookURL     string
+	AlertWebhookTimeout time.Duration
+	// SlackWebhookURL is a Slack incoming webhook posted triggered alerts and
+	// anomalous bars when set
+	SlackWebhookURL string
+	// NotificationTemplatesDir holds templates overriding the shipped ones,
+	// laid out as <kind>/<channel>.tmpl like pkg/notify/templates
+	NotificationTemplatesDir string
+
+	// AnalyticsFlushInterval is how often request counts are persisted
+	AnalyticsFlushInterval time.Duration
+
+	// PolygonAPIKey enables market data ingestion from Polygon.io. With
+	// IngestEODEnabled the day's tickers and daily summaries are loaded
+	// before the other post-close jobs run.
+	PolygonAPIKey    string
+	PolygonBaseURL   string
+	PolygonTimeout   time.Duration
+	IngestEODEnabled bool
+	// AlphaVantageAPIKey enables market data ingestion from Alpha Vantage
+	// instead, for one ticker at a time: it serves no whole market daily bars
+	// for the end-of-day ingest. Its requests are paced to
+	// AlphaVantageRequestsPerMinute, the limit of the key's plan.
+	AlphaVantageAPIKey            string
+	AlphaVantageBaseURL           string
+	AlphaVantageTimeout           time.Duration
+	AlphaVantageRequestsPerMinute int
+
+	// IngestQueueURL is an SQS queue of market data messages every replica
+	// stores through the repositories when set. IngestQueueVisibility must
+	// match the queue's visibility timeout. Messages failing
+	// IngestQueueMaxReceives times, or invalid ones, go to IngestDLQURL.
+	IngestQueueURL         string
+	IngestDLQURL           string
+	IngestQueueVisibility  time.Duration
+	IngestQueueMaxReceives int
+
+	// AnomalyDetection screens ingested daily summaries for improbable moves:
+	// a close is flagged when its return from the previous close lies more
+	// than AnomalyZScore standard deviations from the mean of the last
+	// AnomalyLookback returns and beyond AnomalyIQRFactor interquartile
+	// ranges of their quartiles. Tickers with fewer than AnomalyMinHistory
+	// returns are not screened. Flagged summaries are tagged, counted and
+	// notified to the log and AnomalyWebhookURL; with AnomalyHold they are
+	// held in HeldSummariesTable for an admin to approve instead of stored.
+	AnomalyDetection  bool
+	AnomalyZScore     float64
+	AnomalyIQRFactor  float64
+	AnomalyLookback   int
+	AnomalyMinHistory int
+	AnomalyHold       bool
+	AnomalyWebhookURL string
+
+	// StreamsEnabled consumes the DynamoDB Streams of the tickers and daily
+	// summaries tables, polling each shard every StreamsPollInterval: every
+	// replica drops the tickers changed in its cache and the leader
+	// recomputes the stats of corrected tickers. With StreamsFanout the
+	// leader also publishes a BarClosed event per stored daily summary, for
+	// streaming consumers subscribed to the event bus. The streams are looked
+	// up from the tables unless TickersStreamARN or DailySummaryStreamARN are
+	// set.
+	StreamsEnabled        bool
+	StreamsPollInterval   time.Duration
+	StreamsFanout         bool
+	TickersStreamARN      string
+	DailySummaryStreamARN string
+
+	// CacheBackend is "memory", "redis" (at RedisURL) or "none". Tickers are
+	// read through it for TickerCacheTTL, the active list for ActiveTickersCacheTTL,
+	// the cold start bundles for BundleCacheTTL and weekly and monthly bars of
+	// past ranges for BarsCacheTTL. When Redis is unreachable at startup the
+	// process caches in memory and retries every RedisRetryInterval.
+	CacheBackend          string
+	RedisURL              string
+	RedisRetryInterval    time.Duration
+	TickerCacheTTL        time.Duration
+	ActiveTickersCacheTTL time.Duration
+	BundleCacheTTL        time.Duration
+	BarsCacheTTL          time.Duration
+
+	// SMTPHost enables mailing watchlist digests from DigestFrom; without it
+	// digests are only logged
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	SMTPTimeout  time.Duration
+	DigestFrom   string
+	// DigestLinkSecret signs the confirmation and unsubscribe links mailed to
+	// digest subscribers, which point at PublicBaseURL. Without it links are
+	// signed with a per-process secret and stop working after a restart.
+	DigestLinkSecret string
+	PublicBaseURL    string
+
+	// FCMCredentialsFile (a service account key) and APNSKeyFile (a .p8 key)
+	// enable pushing alerts and digests to registered Android and iOS devices
+	FCMCredentialsFile string
+	APNSKeyFile        string
+	APNSKeyID          string
+	APNSTeamID         string
+	APNSTopic          string
+	APNSProduction     bool
+	PushTimeout        time.Duration
+
+	// EventsBackend is "eventbridge", "sns" or "none". Domain events such as
+	// triggered alerts are published to EventBusName (empty is the default
+	// bus) or the SNS topic EventsTopicARN, stamped with EventSource.
+	EventsBackend  string
+	EventBusName   string
+	EventsTopicARN string
+	EventSource    string
+	EventsTimeout  time.Duration
+
+	// TracingExporter is "otlp", "log" or "none". Traces of requests and jobs
+	// are sent to TracingEndpoint, an OTLP/HTTP traces URL, or the collector
+	// the OTEL_EXPORTER_OTLP_* variables name when empty. TracingSampleRate is
+	// the share of new traces recorded.
+	TracingExporter   string
+	TracingEndpoint   string
+	TracingSampleRate float64
+
+	// AuthEnabled requires an API key on all API routes; admin routes always
+	// require an admin key. BootstrapAdminKey is stored as an admin key at startup.
+	AuthEnabled       bool
+	BootstrapAdminKey string
+
+	// RateLimitRPS and RateLimitBurst limit the requests of each API key, or
+	// client IP without one; admin routes are also limited by the admin pair.
+	// Zero disables a limit.
+	RateLimitRPS        float64
+	RateLimitBurst      int
+	AdminRateLimitRPS   float64
+	AdminRateLimitBurst int
+	// ClientRateLimitRPS and ClientRateLimitBurst limit the requests of each
+	// client IP before authentication, so failed authentications are
+	// throttled too. Zero disables the limit.
+	ClientRateLimitRPS   float64
+	ClientRateLimitBurst int
+	// Responses of at least CompressionMinSize bytes are compressed for
+	// clients accepting gzip or deflate, except on the paths starting with
+	// one of CompressionExclude
+	CompressionEnabled bool
+	CompressionMinSize int
+	CompressionExclude []string
+
+	// CacheControl is the Cache-Control header of successful GET responses,
+	// by route template such as /api/v1/tickers
+	CacheControl map[string]string
+	// DeprecatedRoutes are the API routes announced deprecated, by method and
+	// route template such as "GET /api/v1/prices", with the date they were
+	// deprecated and optionally their sunset, as 2026-10-01/2027-04-01
+	DeprecatedRoutes map[string]string
+	// LegacyAPISunset is the date, YYYY-MM-DD, the unversioned /api aliases of
+	// the /api/v1 routes are removed; empty announces none
+	LegacyAPISunset string
+
+	// TrustedProxies are the IPs and CIDRs of the reverse proxies whose
+	// X-Forwarded-For header names the client IP. Without any, the client IP
+	// is the address of the connection.
+	TrustedProxies []string
+
+	// ResponseMaxItems and ResponseMaxBytes bound the JSON bodies of the list
+	// endpoints; zero disables a limit. ResponseOversize is "truncate", which
+	// answers the first page with a nextCursor and a Warning header, or
+	// "reject", which answers 413.
+	ResponseMaxItems int
+	ResponseMaxBytes int
+	ResponseOversize string
+
+	// SignatureClockSkew is how far the timestamp of a signed request may be
+	// from the server's clock; nonces are remembered for as long
+	SignatureClockSkew time.Duration
+
+	// SessionTTL is how long a session token stays valid after its last use
+	SessionTTL time.Duration
+	// TermsVersion is the version of the terms of service and privacy policy
+	// a key's holder must accept before calling the account routes; empty
+	// enforces no acceptance
+	TermsVersion string
+	// AccountRetention is how long a deleted account stays restorable before
+	// the purge job deletes its data
+	AccountRetention time.Duration
+	// UserAuth is "none", "local", where users register and log in for
+	// tokens signed with JWTSecret expiring after UserTokenTTL, or "cognito",
+	// where the tokens of CognitoUserPoolID issued to CognitoClientID are
+	// accepted. Each user authenticates as an API key of their own.
+	UserAuth          string
+	JWTSecret         string
+	UserTokenTTL      time.Duration
+	CognitoUserPoolID string
+	CognitoClientID   string
+
+	// StorageBackend is "dynamodb" or "memory". The memory backend keeps
+	// tickers, seeded from a bundled fixture when StorageSeed is set, API keys
+	// and request nonces in process memory, so a demo runs without AWS. Other
+	// data still lives in DynamoDB, and the background workers, request
+	// analytics and quotas are disabled.
+	StorageBackend string
+	StorageSeed    bool
+
+	// AWSRegion and AWSEndpointURL override the SDK defaults, e.g. to target LocalStack
+	AWSRegion      string
+	AWSEndpointURL string
+
+	// AWS SDK HTTP connection pool, shared by the DynamoDB client and every
+	// repository. Zero keeps the SDK default.
+	AWSMaxConnsPerHost     int
+	AWSMaxIdleConnsPerHost int
+	AWSIdleConnTimeout     time.Duration
+	AWSDialTimeout         time.Duration
+	AWSKeepAlive           time.Duration
+	AWSHTTPTimeout         time.Duration
+
+	// DynamoDB table names
+	TickersTable         string
+	DailySummaryTable    string
+	IntradayBarsTable    string
+	CustomAssetsTable    string
+	AssetValuationsTable string
+	SignalsTable         string
+	BreadthTable         string
+	EconomicEventsTable  string
+	APIKeysTable         string
+	SettingsTable        string
+	LocksTable           string
+	WatchlistsTable      string
+	PortfoliosTable      string
+	// PortfolioTransactionsTable is keyed by portfolioId and a chronologically sorted id
+	PortfolioTransactionsTable string
+	AlertsTable                string
+	// AnalyticsTable is keyed by date and metric, e.g. "symbol#AAPL"
+	AnalyticsTable string
+	// DigestsTable holds the email digest subscriptions
+	DigestsTable string
+	// DevicesTable holds the devices registered for push notifications
+	DevicesTable string
+	// NoncesTable remembers the nonces of signed requests until their TTL
+	NoncesTable string
+	// SessionsTable holds the session tokens issued to API keys until their TTL
+	SessionsTable string
+	// UsersTable holds the user accounts, keyed by id
+	UsersTable string
+	// TickerChangesTable logs ticker writes for delta sync, keyed by stream
+	// and seq, for TickerChangeRetention
+	TickerChangesTable    string
+	TickerChangeRetention time.Duration
+	// ActivityTable holds each API key's activity feed, keyed by keyId and a
+	// chronologically sorted seq, for ActivityRetention
+	ActivityTable     string
+	ActivityRetention time.Duration
+	// CorporateActionsTable holds splits and dividends, keyed by ticker and
+	// an id prefixed with the kind, e.g. "split#"
+	CorporateActionsTable string
+	// BarRollupsTable holds the weekly and monthly bars of closed periods,
+	// keyed by series, e.g. "AAPL#week", and the period's start
+	BarRollupsTable string
+	// TickerStatsTable holds each ticker's derived statistics, keyed by ticker
+	TickerStatsTable string
+	// SummaryRevisionsTable logs daily summaries overwritten with different
+	// values, keyed by ticker and revision, the date followed by when
+	SummaryRevisionsTable string
+	// HeldSummariesTable holds the daily summaries flagged as anomalous that
+	// await review, keyed by ticker and date
+	HeldSummariesTable string
+
+	// TickersActiveIndex is the GSI queried for active tickers; when
+	// TickersUseActiveIndex is false the tickers table is scanned instead
+	TickersActiveIndex    string
+	TickersUseActiveIndex bool
+	// TickersExchangeIndex and TickersMarketIndex are the GSIs queried for the
+	// tickers of an exchange or market; empty scans the table instead
+	TickersExchangeIndex string
+	TickersMarketIndex   string
+
+	// ScanSegments is how many segments full table scans are divided into
+	// and read in parallel, for the reads of tables without an index and
+	// exports
+	ScanSegments int
 }
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+// Load reads the settings from the environment, falling back to the config
+// file named by CONFIG_FILE, or ./config.yaml when it exists. File keys are the
+// variable names in any case. Malformed values and unknown file keys fail the
+// load; Validate checks how the settings combine.
+func Load() (*Config, error) {
+	s, err := newSource(os.Getenv(FileEnv))
+	if err != nil {
+		return nil, err
+	}
+	cfg := s.load()
+	if err := s.err(); err != nil {
+		return nil, err
 	}
-	return defaultValue
+	return cfg, nil
 }
 
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
-		}
+func (s *source) load() *Config {
+	return &Config{
+		Port:            s.getEnv("PORT", "8080"),
+		GRPCPort:        s.getEnv("GRPC_PORT", ""),
+		Environment:     s.getEnv("ENVIRONMENT", "development"),
+		LogLevel:        s.getEnv("LOG_LEVEL", ""),
+		ShutdownTimeout: s.getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+		ReadTimeout:     s.getEnvDuration("READ_TIMEOUT", 15*time.Second),
+		WriteTimeout:    s.getEnvDuration("WRITE_TIMEOUT", 15*time.Second),
+		IdleTimeout:     s.getEnvDuration("IDLE_TIMEOUT", 60*time.Second),
+
+		PostCloseJobsAt:       s.getEnvDuration("POST_CLOSE_JOBS_AT", 16*time.Hour+30*time.Minute),
+		SchedulerMode:         s.getEnv("SCHEDULER_MODE", "internal"),
+		SchedulerQueueURL:     s.getEnv("SCHEDULER_QUEUE_URL", ""),
+		ScannerGapPercent:     s.getEnvFloat("SCANNER_GAP_PERCENT", 4),
+		ScannerVolumeMultiple: s.getEnvFloat("SCANNER_VOLUME_MULTIPLE", 3),
+		ScannerVolumeLookback: s.getEnvInt("SCANNER_VOLUME_LOOKBACK", 20),
+		HeatmapCacheTTL:       s.getEnvDuration("HEATMAP_CACHE_TTL", 15*time.Minute),
+		ScreenerCacheTTL:      s.getEnvDuration("SCREENER_CACHE_TTL", 5*time.Minute),
+		StatsBenchmark:        s.getEnv("STATS_BENCHMARK", "SPY"),
+		PurgeWritesPerSecond:  s.getEnvInt("PURGE_WRITES_PER_SECOND", 100),
+		PurgeConfirmationTTL:  s.getEnvDuration("PURGE_CONFIRMATION_TTL", 5*time.Minute),
+		LockLease:             s.getEnvDuration("LOCK_LEASE", 30*time.Second),
+		AlertEvalInterval:     s.getEnvDuration("ALERT_EVAL_INTERVAL", time.Minute),
+
+		DigestScheduleInterval: s.getEnvDuration("DIGEST_SCHEDULE_INTERVAL", time.Minute),
+
+		AlertWebhookURL:     s.getEnv("ALERT_WEBHOOK_URL", ""),
+		AlertWebhookTimeout: s.getEnvDuration("ALERT_WEBHOOK_TIMEOUT", 10*time.Second),
+
+		SlackWebhookURL:          s.getEnv("SLACK_WEBHOOK_URL", ""),
+		NotificationTemplatesDir: s.getEnv("NOTIFICATION_TEMPLATES_DIR", ""),
+
+		AnalyticsFlushInterval: s.getEnvDuration("ANALYTICS_FLUSH_INTERVAL", time.Minute),
+
+		PolygonAPIKey:    s.getEnv("POLYGON_API_KEY", ""),
+		PolygonBaseURL:   s.getEnv("POLYGON_BASE_URL", "https://api.polygon.io"),
+		PolygonTimeout:   s.getEnvDuration("POLYGON_TIMEOUT", 30*time.Second),
+		IngestEODEnabled: s.getEnvBool("INGEST_EOD_ENABLED", false),
+
+		AlphaVantageAPIKey:            s.getEnv("ALPHA_VANTAGE_API_KEY", ""),
+		AlphaVantageBaseURL:           s.getEnv("ALPHA_VANTAGE_BASE_URL", "https://www.alphavantage.co"),
+		AlphaVantageTimeout:           s.getEnvDuration("ALPHA_VANTAGE_TIMEOUT", 30*time.Second),
+		AlphaVantageRequestsPerMinute: s.getEnvInt("ALPHA_VANTAGE_REQUESTS_PER_MINUTE", 5),
+
+		IngestQueueURL:         s.getEnv("INGEST_QUEUE_URL", ""),
+		IngestDLQURL:           s.getEnv("INGEST_DLQ_URL", ""),
+		IngestQueueVisibility:  s.getEnvDuration("INGEST_QUEUE_VISIBILITY_TIMEOUT", 30*time.Second),
+		IngestQueueMaxReceives: s.getEnvInt("INGEST_QUEUE_MAX_RECEIVES", 5),
+
+		AnomalyDetection:  s.getEnvBool("ANOMALY_DETECTION", true),
+		AnomalyZScore:     s.getEnvFloat("ANOMALY_ZSCORE", 6),
+		AnomalyIQRFactor:  s.getEnvFloat("ANOMALY_IQR_FACTOR", 3),
+		AnomalyLookback:   s.getEnvInt("ANOMALY_LOOKBACK", 60),
+		AnomalyMinHistory: s.getEnvInt("ANOMALY_MIN_HISTORY", 20),
+		AnomalyHold:       s.getEnvBool("ANOMALY_HOLD", false),
+		AnomalyWebhookURL: s.getEnv("ANOMALY_WEBHOOK_URL", ""),
+
+		StreamsEnabled:        s.getEnvBool("STREAMS_ENABLED", false),
+		StreamsPollInterval:   s.getEnvDuration("STREAMS_POLL_INTERVAL", time.Second),
+		StreamsFanout:         s.getEnvBool("STREAMS_FANOUT", false),
+		TickersStreamARN:      s.getEnv("TICKERS_STREAM_ARN", ""),
+		DailySummaryStreamARN: s.getEnv("DAILY_SUMMARY_STREAM_ARN", ""),
+
+		CacheBackend:          s.getEnv("CACHE_BACKEND", "memory"),
+		RedisURL:              s.getEnv("REDIS_URL", "redis://localhost:6379/0"),
+		RedisRetryInterval:    s.getEnvDuration("REDIS_RETRY_INTERVAL", 10*time.Second),
+		TickerCacheTTL:        s.getEnvDuration("TICKER_CACHE_TTL", 10*time.Minute),
+		ActiveTickersCacheTTL: s.getEnvDuration("ACTIVE_TICKERS_CACHE_TTL", 5*time.Minute),
+		BundleCacheTTL:        s.getEnvDuration("BUNDLE_CACHE_TTL", time.Minute),
+		BarsCacheTTL:          s.getEnvDuration("BARS_CACHE_TTL", time.Hour),
+
+		SMTPHost:     s.getEnv("SMTP_HOST", ""),
+		SMTPPort:     s.getEnvInt("SMTP_PORT", 587),
+		SMTPUsername: s.getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: s.getEnv("SMTP_PASSWORD", ""),
+		SMTPTimeout:  s.getEnvDuration("SMTP_TIMEOUT", 30*time.Second),
+		DigestFrom:   s.getEnv("DIGEST_FROM", "Profitify <digests@profitify.local>"),
+
+		DigestLinkSecret: s.getEnv("DIGEST_LINK_SECRET", ""),
+		PublicBaseURL:    s.getEnv("PUBLIC_BASE_URL", "http://localhost:8080"),
+
+		FCMCredentialsFile: s.getEnv("FCM_CREDENTIALS_FILE", ""),
+		APNSKeyFile:        s.getEnv("APNS_KEY_FILE", ""),
+		APNSKeyID:          s.getEnv("APNS_KEY_ID", ""),
+		APNSTeamID:         s.getEnv("APNS_TEAM_ID", ""),
+		APNSTopic:          s.getEnv("APNS_TOPIC", ""),
+		APNSProduction:     s.getEnvBool("APNS_PRODUCTION", false),
+		PushTimeout:        s.getEnvDuration("PUSH_TIMEOUT", 10*time.Second),
+
+		EventsBackend:  s.getEnv("EVENTS_BACKEND", "none"),
+		EventBusName:   s.getEnv("EVENT_BUS_NAME", ""),
+		EventsTopicARN: s.getEnv("EVENTS_TOPIC_ARN", ""),
+		EventSource:    s.getEnv("EVENT_SOURCE", "profitify.api"),
+		EventsTimeout:  s.getEnvDuration("EVENTS_TIMEOUT", 5*time.Second),
+
+		TracingExporter:   s.getEnv("TRACING_EXPORTER", "none"),
+		TracingEndpoint:   s.getEnv("TRACING_ENDPOINT", ""),
+		TracingSampleRate: s.getEnvFloat("TRACING_SAMPLE_RATE", 1),
+
+		AuthEnabled:       s.getEnvBool("AUTH_ENABLED", false),
+		BootstrapAdminKey: s.getEnv("BOOTSTRAP_ADMIN_API_KEY", ""),
+
+		RateLimitRPS:         s.getEnvFloat("RATE_LIMIT_RPS", 10),
+		RateLimitBurst:       s.getEnvInt("RATE_LIMIT_BURST", 20),
+		AdminRateLimitRPS:    s.getEnvFloat("ADMIN_RATE_LIMIT_RPS", 2),
+		AdminRateLimitBurst:  s.getEnvInt("ADMIN_RATE_LIMIT_BURST", 10),
+		ClientRateLimitRPS:   s.getEnvFloat("CLIENT_RATE_LIMIT_RPS", 50),
+		ClientRateLimitBurst: s.getEnvInt("CLIENT_RATE_LIMIT_BURST", 100),
+		CompressionEnabled:   s.getEnvBool("COMPRESSION_ENABLED", true),
+		CompressionMinSize:   s.getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		CompressionExclude:   s.getEnvList("COMPRESSION_EXCLUDE"),
+		CacheControl:         s.getEnvMap("CACHE_CONTROL", "/api/v1/tickers=private, max-age=60"),
+		DeprecatedRoutes:     s.getEnvMap("DEPRECATED_ROUTES", ""),
+		LegacyAPISunset:      s.getEnv("LEGACY_API_SUNSET", ""),
+		TrustedProxies:       s.getEnvList("TRUSTED_PROXIES"),
+		ResponseMaxItems:     s.getEnvInt("RESPONSE_MAX_ITEMS", 10000),
+		ResponseMaxBytes:     s.getEnvInt("RESPONSE_MAX_BYTES", 8<<20),
+		ResponseOversize:     s.getEnv("RESPONSE_OVERSIZE", "truncate"),
+		SignatureClockSkew:   s.getEnvDuration("SIGNATURE_CLOCK_SKEW", 5*time.Minute),
+		SessionTTL:           s.getEnvDuration("SESSION_TTL", 30*24*time.Hour),
+		TermsVersion:         s.getEnv("TERMS_VERSION", ""),
+		AccountRetention:     s.getEnvDuration("ACCOUNT_RETENTION", 30*24*time.Hour),
+		UserAuth:             s.getEnv("USER_AUTH", "none"),
+		JWTSecret:            s.getEnv("JWT_SECRET", ""),
+		UserTokenTTL:         s.getEnvDuration("USER_TOKEN_TTL", time.Hour),
+		CognitoUserPoolID:    s.getEnv("COGNITO_USER_POOL_ID", ""),
+		CognitoClientID:      s.getEnv("COGNITO_CLIENT_ID", ""),
+
+		StorageBackend: s.getEnv("STORAGE_BACKEND", "dynamodb"),
+		StorageSeed:    s.getEnvBool("STORAGE_SEED", true),
+
+		AWSRegion:      s.getEnv("AWS_REGION", ""),
+		AWSEndpointURL: s.getEnv("AWS_ENDPOINT_URL", ""),
+
+		AWSMaxConnsPerHost:     s.getEnvInt("AWS_MAX_CONNS_PER_HOST", 0),
+		AWSMaxIdleConnsPerHost: s.getEnvInt("AWS_MAX_IDLE_CONNS_PER_HOST", 10),
+		AWSIdleConnTimeout:     s.getEnvDuration("AWS_IDLE_CONN_TIMEOUT", 90*time.Second),
+		AWSDialTimeout:         s.getEnvDuration("AWS_DIAL_TIMEOUT", 30*time.Second),
+		AWSKeepAlive:           s.getEnvDuration("AWS_KEEP_ALIVE", 30*time.Second),
+		AWSHTTPTimeout:         s.getEnvDuration("AWS_HTTP_TIMEOUT", 0),
+
+		TickersTable:               s.getEnv("TICKERS_TABLE", "stocks-data"),
+		DailySummaryTable:          s.getEnv("DAILY_SUMMARY_TABLE", "DailySummary"),
+		IntradayBarsTable:          s.getEnv("INTRADAY_BARS_TABLE", "intraday-bars"),
+		CustomAssetsTable:          s.getEnv("CUSTOM_ASSETS_TABLE", "custom-assets"),
+		AssetValuationsTable:       s.getEnv("ASSET_VALUATIONS_TABLE", "custom-asset-valuations"),
+		SignalsTable:               s.getEnv("SIGNALS_TABLE", "market-signals"),
+		BreadthTable:               s.getEnv("BREADTH_TABLE", "market-breadth"),
+		EconomicEventsTable:        s.getEnv("ECONOMIC_EVENTS_TABLE", "economic-events"),
+		CorporateActionsTable:      s.getEnv("CORPORATE_ACTIONS_TABLE", "corporate-actions"),
+		BarRollupsTable:            s.getEnv("BAR_ROLLUPS_TABLE", "bar-rollups"),
+		TickerStatsTable:           s.getEnv("TICKER_STATS_TABLE", "ticker-stats"),
+		SummaryRevisionsTable:      s.getEnv("SUMMARY_REVISIONS_TABLE", "summary-revisions"),
+		HeldSummariesTable:         s.getEnv("HELD_SUMMARIES_TABLE", "held-summaries"),
+		APIKeysTable:               s.getEnv("API_KEYS_TABLE", "api-keys"),
+		SettingsTable:              s.getEnv("SETTINGS_TABLE", "settings"),
+		LocksTable:                 s.getEnv("LOCKS_TABLE", "locks"),
+		WatchlistsTable:            s.getEnv("WATCHLISTS_TABLE", "watchlists"),
+		PortfoliosTable:            s.getEnv("PORTFOLIOS_TABLE", "portfolios"),
+		PortfolioTransactionsTable: s.getEnv("PORTFOLIO_TRANSACTIONS_TABLE", "portfolio-transactions"),
+		AlertsTable:                s.getEnv("ALERTS_TABLE", "alerts"),
+		AnalyticsTable:             s.getEnv("ANALYTICS_TABLE", "request-analytics"),
+		DigestsTable:               s.getEnv("DIGESTS_TABLE", "digest-subscriptions"),
+		DevicesTable:               s.getEnv("DEVICES_TABLE", "devices"),
+		NoncesTable:                s.getEnv("NONCES_TABLE", "request-nonces"),
+		SessionsTable:              s.getEnv("SESSIONS_TABLE", "sessions"),
+		UsersTable:                 s.getEnv("USERS_TABLE", "users"),
+		TickerChangesTable:         s.getEnv("TICKER_CHANGES_TABLE", "ticker-changes"),
+		TickerChangeRetention:      s.getEnvDuration("TICKER_CHANGE_RETENTION", 30*24*time.Hour),
+		ActivityTable:              s.getEnv("ACTIVITY_TABLE", "account-activity"),
+		ActivityRetention:          s.getEnvDuration("ACTIVITY_RETENTION", 90*24*time.Hour),
+
+		TickersActiveIndex:    s.getEnv("TICKERS_ACTIVE_INDEX", "active-index"),
+		TickersUseActiveIndex: s.getEnvBool("TICKERS_USE_ACTIVE_INDEX", true),
+		TickersExchangeIndex:  s.getEnv("TICKERS_EXCHANGE_INDEX", "exchange-index"),
+		TickersMarketIndex:    s.getEnv("TICKERS_MARKET_INDEX", "market-index"),
+
+		ScanSegments: s.getEnvInt("SCAN_SEGMENTS", 4),
 	}
-	return defaultValue
 }
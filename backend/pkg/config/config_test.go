@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeConfigFile writes a config file for the test and points CONFIG_FILE at it
+func writeConfigFile(t *testing.T, name, content string) {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv(FileEnv, path)
+}
+
+func TestLoad_YAMLFileUnderEnvironment(t *testing.T) {
+	writeConfigFile(t, "config.yaml", `
+tickers_table: stocks-file
+daily_summary_table: daily-file
+CACHE_BACKEND: none
+ticker_cache_ttl: 2m
+rate_limit_rps: 2.5
+auth_enabled: true
+trusted_proxies:
+  - 10.0.0.0/8
+  - 192.168.1.1
+`)
+	t.Setenv("DAILY_SUMMARY_TABLE", "daily-env")
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "stocks-file", cfg.TickersTable)
+	assert.Equal(t, "daily-env", cfg.DailySummaryTable, "the environment overrides the file")
+	assert.Equal(t, "none", cfg.CacheBackend, "keys are matched in any case")
+	assert.Equal(t, 2*time.Minute, cfg.TickerCacheTTL)
+	assert.Equal(t, 2.5, cfg.RateLimitRPS)
+	assert.True(t, cfg.AuthEnabled)
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.1"}, cfg.TrustedProxies)
+	assert.Equal(t, "8080", cfg.Port, "unset settings keep their defaults")
+}
+
+func TestLoad_TOMLFile(t *testing.T) {
+	writeConfigFile(t, "profitify.toml", `
+tickers_table = "stocks-toml"
+session_ttl = "1h"
+rate_limit_burst = 40
+`)
+
+	cfg, err := Load()
+	require.NoError(t, err)
+	assert.Equal(t, "stocks-toml", cfg.TickersTable)
+	assert.Equal(t, time.Hour, cfg.SessionTTL)
+	assert.Equal(t, 40, cfg.RateLimitBurst)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Run("unknown file key", func(t *testing.T) {
+		writeConfigFile(t, "config.yaml", "tickers_tabel: stocks\n")
+		_, err := Load()
+		assert.ErrorContains(t, err, "unknown settings in config file: tickers_tabel")
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		t.Setenv("LOCK_LEASE", "30")
+		_, err := Load()
+		assert.ErrorContains(t, err, `LOCK_LEASE="30" is not a duration`)
+	})
+
+	t.Run("nested section", func(t *testing.T) {
+		writeConfigFile(t, "config.yaml", "tables:\n  tickers: stocks\n")
+		_, err := Load()
+		assert.ErrorContains(t, err, "tables")
+	})
+
+	t.Run("malformed map entry", func(t *testing.T) {
+		t.Setenv("CACHE_CONTROL", "/api/tickers")
+		_, err := Load()
+		assert.ErrorContains(t, err, `CACHE_CONTROL="/api/tickers" is not a key=value entry`)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		writeConfigFile(t, "config.json", "{}")
+		_, err := Load()
+		assert.ErrorContains(t, err, "is not .yaml, .yml or .toml")
+	})
+
+	t.Run("missing named file", func(t *testing.T) {
+		t.Setenv(FileEnv, filepath.Join(t.TempDir(), "missing.yaml"))
+		_, err := Load()
+		assert.ErrorContains(t, err, "failed to read config file")
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg, err := Load()
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate(), "the defaults are valid")
+
+	tests := []struct {
+		name   string
+		modify func(c *Config)
+		want   string
+	}{
+		{"unknown cache backend", func(c *Config) { c.CacheBackend = "memcached" }, `CACHE_BACKEND="memcached" is not one of`},
+		{"sqs scheduler without queue", func(c *Config) { c.SchedulerMode = "sqs" }, "SCHEDULER_MODE=sqs requires SCHEDULER_QUEUE_URL"},
+		{"sns without topic", func(c *Config) { c.EventsBackend = "sns" }, "EVENTS_BACKEND=sns requires EVENTS_TOPIC_ARN"},
+		{"ingestion without polygon", func(c *Config) { c.IngestEODEnabled = true }, "INGEST_EOD_ENABLED requires POLYGON_API_KEY"},
+		{"two market data providers", func(c *Config) { c.PolygonAPIKey, c.AlphaVantageAPIKey = "p", "a" }, "POLYGON_API_KEY and ALPHA_VANTAGE_API_KEY select different providers"},
+		{"dead letters without an ingestion queue", func(c *Config) { c.IngestDLQURL = "https://sqs/dlq" }, "INGEST_DLQ_URL requires INGEST_QUEUE_URL"},
+		{"digests scheduled too seldom", func(c *Config) { c.DigestScheduleInterval = 2 * time.Hour }, "DIGEST_SCHEDULE_INTERVAL must be at most 1h"},
+		{"plain http slack webhook", func(c *Config) { c.SlackWebhookURL = "http://hooks.slack.com/services/x" }, "SLACK_WEBHOOK_URL must be an https URL"},
+		{"anomaly history past the lookback", func(c *Config) { c.AnomalyMinHistory = c.AnomalyLookback + 1 }, "ANOMALY_MIN_HISTORY must be at least 4 and at most ANOMALY_LOOKBACK"},
+		{"streams polled too often", func(c *Config) {
+			c.StreamsEnabled = true
+			c.StreamsPollInterval = 10 * time.Millisecond
+		}, "STREAMS_POLL_INTERVAL must be at least 100ms"},
+		{"ingestion queue without receives", func(c *Config) {
+			c.IngestQueueURL = "https://sqs/ingest"
+			c.IngestQueueMaxReceives = 0
+		}, "INGEST_QUEUE_MAX_RECEIVES must be positive"},
+		{"memory storage with a serverless scheduler", func(c *Config) {
+			c.StorageBackend = "memory"
+			c.SchedulerMode = "lambda"
+		}, "SCHEDULER_MODE=lambda requires STORAGE_BACKEND=dynamodb"},
+		{"unknown oversize response mode", func(c *Config) { c.ResponseOversize = "stream" }, `RESPONSE_OVERSIZE="stream" is not one of`},
+		{"no scan segments", func(c *Config) { c.ScanSegments = 0 }, "SCAN_SEGMENTS=0 is not between 1 and 1000"},
+		{"sample rate above one", func(c *Config) { c.TracingSampleRate = 2 }, "TRACING_SAMPLE_RATE=2 is not between 0 and 1"},
+		{"port out of range", func(c *Config) { c.Port = "70000" }, `PORT="70000" is not a TCP port`},
+		{"grpc port not a number", func(c *Config) { c.GRPCPort = "grpc" }, `GRPC_PORT="grpc" is not a TCP port`},
+		{"grpc port shared with http", func(c *Config) { c.GRPCPort = c.Port }, "GRPC_PORT must differ from PORT"},
+		{"cache control outside the api", func(c *Config) { c.CacheControl = map[string]string{"/health": "no-store"} }, `CACHE_CONTROL entry "/health"="no-store" is not an /api route`},
+		{"post close time past midnight", func(c *Config) { c.PostCloseJobsAt = 25 * time.Hour }, "POST_CLOSE_JOBS_AT=25h0m0s is not a time of day"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalid := *cfg
+			tt.modify(&invalid)
+			assert.ErrorContains(t, invalid.Validate(), tt.want)
+		})
+	}
+
+	t.Run("every problem is reported", func(t *testing.T) {
+		invalid := *cfg
+		invalid.IngestEODEnabled = true
+		invalid.EventsBackend = "sns"
+		err := invalid.Validate()
+		assert.ErrorContains(t, err, "POLYGON_API_KEY")
+		assert.ErrorContains(t, err, "EVENTS_TOPIC_ARN")
+	})
+}
+
+func TestLoad_ExampleFile(t *testing.T) {
+	t.Setenv(FileEnv, filepath.Join("..", "..", "config.example.yaml"))
+
+	cfg, err := Load()
+	require.NoError(t, err, "the example names only known settings")
+	assert.NoError(t, cfg.Validate())
+}
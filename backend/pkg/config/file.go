@@ -0,0 +1,217 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the variable pointing at the config file. Without it
+// config.yaml in the working directory is read when it exists.
+const FileEnv = "CONFIG_FILE"
+
+// defaultFile is read when FileEnv is unset
+const defaultFile = "config.yaml"
+
+// source looks settings up in the environment, then in the config file, and
+// remembers the keys looked up and the values that failed to parse
+type source struct {
+	file  map[string]string
+	known map[string]bool
+	errs  []error
+}
+
+// newSource reads the config file at path, or the default one when path is
+// empty. A missing default file is not an error.
+func newSource(path string) (*source, error) {
+	s := &source{file: map[string]string{}, known: map[string]bool{}}
+	if path == "" {
+		if _, err := os.Stat(defaultFile); err != nil {
+			return s, nil
+		}
+		path = defaultFile
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("config file %s is not .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		text, err := fileValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %s: %w", path, key, err)
+		}
+		s.file[strings.ToUpper(key)] = text
+	}
+	return s, nil
+}
+
+// fileValue returns a file value the way it would be set in the environment:
+// lists are comma separated
+func fileValue(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool, int, int64, uint64, float64:
+		return fmt.Sprint(v), nil
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			text, err := fileValue(item)
+			if err != nil {
+				return "", err
+			}
+			items[i] = text
+		}
+		return strings.Join(items, ","), nil
+	default:
+		return "", fmt.Errorf("must be a string, number, boolean or list, not %T", value)
+	}
+}
+
+// lookup returns the environment's value of key, or else the file's
+func (s *source) lookup(key string) string {
+	s.known[key] = true
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return s.file[key]
+}
+
+// invalid records a value that failed to parse
+func (s *source) invalid(key, value, want string) {
+	s.errs = append(s.errs, fmt.Errorf("%s=%q is not %s", key, value, want))
+}
+
+// err reports the values that failed to parse and the file keys no setting
+// reads, which are likely typos
+func (s *source) err() error {
+	errs := s.errs
+	var unknown []string
+	for key := range s.file {
+		if !s.known[key] {
+			unknown = append(unknown, strings.ToLower(key))
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		errs = append(errs, fmt.Errorf("unknown settings in config file: %s", strings.Join(unknown, ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *source) getEnv(key, defaultValue string) string {
+	if value := s.lookup(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvList splits a comma-separated value, dropping empty entries
+func (s *source) getEnvList(key string) []string {
+	var list []string
+	for _, value := range strings.Split(s.lookup(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// getEnvMap parses a value of semicolon-separated key=value entries, so
+// values may hold commas. An empty value yields defaultValue.
+func (s *source) getEnvMap(key, defaultValue string) map[string]string {
+	raw := s.lookup(key)
+	if raw == "" {
+		raw = defaultValue
+	}
+	m := make(map[string]string)
+	for _, entry := range strings.Split(raw, ";") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(entry, "=")
+		if !ok {
+			s.invalid(key, entry, "a key=value entry")
+			continue
+		}
+		m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return m
+}
+
+func (s *source) getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := s.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		s.invalid(key, value, "a duration such as 30s")
+		return defaultValue
+	}
+	return duration
+}
+
+func (s *source) getEnvFloat(key string, defaultValue float64) float64 {
+	value := s.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		s.invalid(key, value, "a number")
+		return defaultValue
+	}
+	return f
+}
+
+func (s *source) getEnvInt(key string, defaultValue int) int {
+	value := s.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		s.invalid(key, value, "an integer")
+		return defaultValue
+	}
+	return i
+}
+
+func (s *source) getEnvBool(key string, defaultValue bool) bool {
+	value := s.lookup(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		s.invalid(key, value, "true or false")
+		return defaultValue
+	}
+	return b
+}
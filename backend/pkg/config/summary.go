@@ -0,0 +1,239 @@
+package config
+
+import (
+	"net/url"
+)
+
+// redacted replaces secrets in the configuration summary
+const redacted = "[redacted]"
+
+// Summary returns the effective configuration for logging at startup, grouped
+// by concern. Secrets are masked and only reported as set or unset.
+func (c *Config) Summary() map[string]any {
+	storage := map[string]any{
+		"backend":  c.StorageBackend,
+		"region":   orDefault(c.AWSRegion, "sdk default"),
+		"endpoint": orDefault(sanitizeURL(c.AWSEndpointURL), "aws"),
+		"http": map[string]any{
+			"maxConnsPerHost":     c.AWSMaxConnsPerHost,
+			"maxIdleConnsPerHost": c.AWSMaxIdleConnsPerHost,
+			"idleConnTimeout":     c.AWSIdleConnTimeout.String(),
+			"dialTimeout":         c.AWSDialTimeout.String(),
+			"keepAlive":           c.AWSKeepAlive.String(),
+			"timeout":             c.AWSHTTPTimeout.String(),
+		},
+	}
+	if c.StorageBackend == "memory" {
+		// Only these are kept in memory; other features still reach DynamoDB,
+		// and the workers coordinating replicas through it are not started
+		storage["seed"] = c.StorageSeed
+		storage["inMemory"] = []string{"tickers", "tickerChanges", "apiKeys", "nonces"}
+		storage["disabled"] = []string{"leaderElection", "postCloseJobs", "alertEvaluator", "requestAnalytics", "quotas"}
+	}
+
+	return map[string]any{
+		"server": map[string]any{
+			"port":            c.Port,
+			"grpcPort":        c.GRPCPort,
+			"environment":     c.Environment,
+			"shutdownTimeout": c.ShutdownTimeout.String(),
+			"readTimeout":     c.ReadTimeout.String(),
+			"writeTimeout":    c.WriteTimeout.String(),
+			"idleTimeout":     c.IdleTimeout.String(),
+		},
+		"features": map[string]any{
+			"authEnabled":           c.AuthEnabled,
+			"signatureClockSkew":    c.SignatureClockSkew.String(),
+			"sessionTTL":            c.SessionTTL.String(),
+			"termsVersion":          c.TermsVersion,
+			"accountRetention":      c.AccountRetention.String(),
+			"userAuth":              c.UserAuth,
+			"jwtSecret":             mask(c.JWTSecret),
+			"userTokenTTL":          c.UserTokenTTL.String(),
+			"cognitoUserPoolID":     orDefault(c.CognitoUserPoolID, "unset"),
+			"cognitoClientID":       orDefault(c.CognitoClientID, "unset"),
+			"tickerChangeRetention": c.TickerChangeRetention.String(),
+			"activityRetention":     c.ActivityRetention.String(),
+			"bootstrapAdminKey":     mask(c.BootstrapAdminKey),
+			"tickersUseActiveIndex": c.TickersUseActiveIndex,
+			"scanSegments":          c.ScanSegments,
+			"polygonAPIKey":         mask(c.PolygonAPIKey),
+			"alphaVantageAPIKey":    mask(c.AlphaVantageAPIKey),
+			"ingestEODEnabled":      c.IngestEODEnabled,
+		},
+		"jobs": map[string]any{
+			"postCloseJobsAt":       c.PostCloseJobsAt.String(),
+			"schedulerMode":         c.SchedulerMode,
+			"schedulerQueueURL":     c.SchedulerQueueURL,
+			"scannerGapPercent":     c.ScannerGapPercent,
+			"scannerVolumeMultiple": c.ScannerVolumeMultiple,
+			"scannerVolumeLookback": c.ScannerVolumeLookback,
+			"heatmapCacheTTL":       c.HeatmapCacheTTL.String(),
+			"screenerCacheTTL":      c.ScreenerCacheTTL.String(),
+			"statsBenchmark":        c.StatsBenchmark,
+			"purgeWritesPerSecond":  c.PurgeWritesPerSecond,
+			"purgeConfirmationTTL":  c.PurgeConfirmationTTL.String(),
+			"lockLease":             c.LockLease.String(),
+			"alertEvalInterval":     c.AlertEvalInterval.String(),
+			"digestSchedule":        c.DigestScheduleInterval.String(),
+			"alertWebhook":          mask(c.AlertWebhookURL),
+			"alertWebhookTimeout":   c.AlertWebhookTimeout.String(),
+			"slackWebhook":          mask(c.SlackWebhookURL),
+			"notificationTemplates": orDefault(c.NotificationTemplatesDir, "shipped"),
+			"analyticsFlush":        c.AnalyticsFlushInterval.String(),
+		},
+		"rateLimits": map[string]any{
+			"rps":            c.RateLimitRPS,
+			"burst":          c.RateLimitBurst,
+			"adminRPS":       c.AdminRateLimitRPS,
+			"adminBurst":     c.AdminRateLimitBurst,
+			"clientRPS":      c.ClientRateLimitRPS,
+			"clientBurst":    c.ClientRateLimitBurst,
+			"trustedProxies": c.TrustedProxies,
+		},
+		"responses": map[string]any{
+			"maxItems": c.ResponseMaxItems,
+			"maxBytes": c.ResponseMaxBytes,
+			"oversize": c.ResponseOversize,
+			"compression": map[string]any{
+				"enabled": c.CompressionEnabled,
+				"minSize": c.CompressionMinSize,
+				"exclude": c.CompressionExclude,
+			},
+			"cacheControl": c.CacheControl,
+		},
+		"deprecations": map[string]any{
+			"routes":          c.DeprecatedRoutes,
+			"legacyAPISunset": orDefault(c.LegacyAPISunset, "unset"),
+		},
+		"ingest": map[string]any{
+			"polygonBaseURL":                sanitizeURL(c.PolygonBaseURL),
+			"polygonTimeout":                c.PolygonTimeout.String(),
+			"alphaVantageBaseURL":           sanitizeURL(c.AlphaVantageBaseURL),
+			"alphaVantageTimeout":           c.AlphaVantageTimeout.String(),
+			"alphaVantageRequestsPerMinute": c.AlphaVantageRequestsPerMinute,
+			"queueURL":                      orDefault(c.IngestQueueURL, "unset"),
+			"dlqURL":                        orDefault(c.IngestDLQURL, "unset"),
+			"queueVisibility":               c.IngestQueueVisibility.String(),
+			"queueMaxReceives":              c.IngestQueueMaxReceives,
+			"anomalies": map[string]any{
+				"enabled":    c.AnomalyDetection,
+				"zScore":     c.AnomalyZScore,
+				"iqrFactor":  c.AnomalyIQRFactor,
+				"lookback":   c.AnomalyLookback,
+				"minHistory": c.AnomalyMinHistory,
+				"hold":       c.AnomalyHold,
+				"webhook":    mask(c.AnomalyWebhookURL),
+			},
+		},
+		"streams": map[string]any{
+			"enabled":            c.StreamsEnabled,
+			"pollInterval":       c.StreamsPollInterval.String(),
+			"fanout":             c.StreamsFanout,
+			"tickersStream":      orDefault(c.TickersStreamARN, "from table"),
+			"dailySummaryStream": orDefault(c.DailySummaryStreamARN, "from table"),
+		},
+		"cache": map[string]any{
+			"backend":          c.CacheBackend,
+			"redisURL":         sanitizeURL(c.RedisURL),
+			"redisRetry":       c.RedisRetryInterval.String(),
+			"tickerTTL":        c.TickerCacheTTL.String(),
+			"activeTickersTTL": c.ActiveTickersCacheTTL.String(),
+			"bundleTTL":        c.BundleCacheTTL.String(),
+			"barsTTL":          c.BarsCacheTTL.String(),
+		},
+		"digest": map[string]any{
+			"smtpHost":      orDefault(c.SMTPHost, "unset"),
+			"smtpPort":      c.SMTPPort,
+			"smtpUsername":  c.SMTPUsername,
+			"smtpPassword":  mask(c.SMTPPassword),
+			"smtpTimeout":   c.SMTPTimeout.String(),
+			"from":          c.DigestFrom,
+			"linkSecret":    mask(c.DigestLinkSecret),
+			"publicBaseURL": sanitizeURL(c.PublicBaseURL),
+		},
+		"push": map[string]any{
+			"fcmCredentials": orDefault(c.FCMCredentialsFile, "unset"),
+			"apnsKey":        orDefault(c.APNSKeyFile, "unset"),
+			"apnsKeyID":      c.APNSKeyID,
+			"apnsTeamID":     c.APNSTeamID,
+			"apnsTopic":      c.APNSTopic,
+			"apnsProduction": c.APNSProduction,
+			"timeout":        c.PushTimeout.String(),
+		},
+		"events": map[string]any{
+			"backend":  c.EventsBackend,
+			"busName":  orDefault(c.EventBusName, "default"),
+			"topicARN": orDefault(c.EventsTopicARN, "unset"),
+			"source":   c.EventSource,
+			"timeout":  c.EventsTimeout.String(),
+		},
+		"tracing": map[string]any{
+			"exporter":   c.TracingExporter,
+			"endpoint":   orDefault(c.TracingEndpoint, "default"),
+			"sampleRate": c.TracingSampleRate,
+		},
+		"storage": storage,
+		"tables": map[string]any{
+			"tickers":               c.TickersTable,
+			"tickersActiveIndex":    c.TickersActiveIndex,
+			"tickersExchangeIndex":  c.TickersExchangeIndex,
+			"tickersMarketIndex":    c.TickersMarketIndex,
+			"dailySummary":          c.DailySummaryTable,
+			"intradayBars":          c.IntradayBarsTable,
+			"customAssets":          c.CustomAssetsTable,
+			"assetValuations":       c.AssetValuationsTable,
+			"signals":               c.SignalsTable,
+			"breadth":               c.BreadthTable,
+			"economicEvents":        c.EconomicEventsTable,
+			"corporateActions":      c.CorporateActionsTable,
+			"barRollups":            c.BarRollupsTable,
+			"tickerStats":           c.TickerStatsTable,
+			"summaryRevisions":      c.SummaryRevisionsTable,
+			"heldSummaries":         c.HeldSummariesTable,
+			"apiKeys":               c.APIKeysTable,
+			"settings":              c.SettingsTable,
+			"locks":                 c.LocksTable,
+			"watchlists":            c.WatchlistsTable,
+			"portfolios":            c.PortfoliosTable,
+			"portfolioTransactions": c.PortfolioTransactionsTable,
+			"alerts":                c.AlertsTable,
+			"analytics":             c.AnalyticsTable,
+			"digests":               c.DigestsTable,
+			"devices":               c.DevicesTable,
+			"nonces":                c.NoncesTable,
+			"sessions":              c.SessionsTable,
+			"users":                 c.UsersTable,
+			"tickerChanges":         c.TickerChangesTable,
+			"activity":              c.ActivityTable,
+		},
+	}
+}
+
+func mask(secret string) string {
+	if secret == "" {
+		return "unset"
+	}
+	return redacted
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// sanitizeURL drops credentials embedded in a URL. Unparseable URLs are
+// masked entirely since they may hold a secret.
+func sanitizeURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	u.User = nil
+	return u.String()
+}
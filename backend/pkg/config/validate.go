@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validate reports settings that cannot work, alone or together, so a
+// misconfigured deployment fails at startup rather than on its first request
+// or job. Every problem is reported, not just the first.
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+	oneOf := func(key, value string, allowed ...string) {
+		check(slices.Contains(allowed, value), "%s=%q is not one of %v", key, value, allowed)
+	}
+
+	port, err := strconv.Atoi(c.Port)
+	check(err == nil && port > 0 && port < 65536, "PORT=%q is not a TCP port", c.Port)
+	if c.GRPCPort != "" {
+		grpcPort, err := strconv.Atoi(c.GRPCPort)
+		check(err == nil && grpcPort > 0 && grpcPort < 65536, "GRPC_PORT=%q is not a TCP port", c.GRPCPort)
+		check(c.GRPCPort != c.Port, "GRPC_PORT must differ from PORT")
+	}
+	check(c.PostCloseJobsAt >= 0 && c.PostCloseJobsAt < 24*time.Hour, "POST_CLOSE_JOBS_AT=%s is not a time of day", c.PostCloseJobsAt)
+	check(c.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT must be positive")
+	check(c.CompressionMinSize >= 0, "COMPRESSION_MIN_SIZE must not be negative")
+	for route, directives := range c.CacheControl {
+		check(strings.HasPrefix(route, "/api/") && directives != "", "CACHE_CONTROL entry %q=%q is not an /api route and its directives", route, directives)
+	}
+	check(c.AWSMaxConnsPerHost >= 0 && c.AWSMaxIdleConnsPerHost >= 0, "AWS_MAX_CONNS_PER_HOST and AWS_MAX_IDLE_CONNS_PER_HOST must not be negative")
+	check(c.AWSMaxConnsPerHost == 0 || c.AWSMaxIdleConnsPerHost <= c.AWSMaxConnsPerHost,
+		"AWS_MAX_IDLE_CONNS_PER_HOST=%d exceeds AWS_MAX_CONNS_PER_HOST=%d", c.AWSMaxIdleConnsPerHost, c.AWSMaxConnsPerHost)
+
+	oneOf("STORAGE_BACKEND", c.StorageBackend, "dynamodb", "memory")
+	oneOf("CACHE_BACKEND", c.CacheBackend, "memory", "redis", "none")
+	oneOf("SCHEDULER_MODE", c.SchedulerMode, "internal", "sqs", "lambda")
+	oneOf("EVENTS_BACKEND", c.EventsBackend, "none", "eventbridge", "sns")
+	oneOf("TRACING_EXPORTER", c.TracingExporter, "none", "otlp", "log")
+	oneOf("RESPONSE_OVERSIZE", c.ResponseOversize, "truncate", "reject")
+	oneOf("USER_AUTH", c.UserAuth, "none", "local", "cognito")
+
+	check(c.SchedulerMode != "sqs" || c.SchedulerQueueURL != "", "SCHEDULER_MODE=sqs requires SCHEDULER_QUEUE_URL")
+	check(c.EventsBackend != "sns" || c.EventsTopicARN != "", "EVENTS_BACKEND=sns requires EVENTS_TOPIC_ARN")
+	check(c.TracingSampleRate >= 0 && c.TracingSampleRate <= 1, "TRACING_SAMPLE_RATE=%v is not between 0 and 1", c.TracingSampleRate)
+	check(!c.IngestEODEnabled || c.PolygonAPIKey != "", "INGEST_EOD_ENABLED requires POLYGON_API_KEY")
+	check(c.PolygonAPIKey == "" || c.AlphaVantageAPIKey == "", "POLYGON_API_KEY and ALPHA_VANTAGE_API_KEY select different providers; set one")
+	check(c.AlphaVantageRequestsPerMinute > 0, "ALPHA_VANTAGE_REQUESTS_PER_MINUTE must be positive")
+	check(c.IngestDLQURL == "" || c.IngestQueueURL != "", "INGEST_DLQ_URL requires INGEST_QUEUE_URL")
+	check(c.IngestQueueURL == "" || c.IngestQueueVisibility >= time.Second, "INGEST_QUEUE_VISIBILITY_TIMEOUT must be at least 1s")
+	check(c.IngestQueueURL == "" || c.IngestQueueMaxReceives > 0, "INGEST_QUEUE_MAX_RECEIVES must be positive")
+	check(!c.AnomalyDetection || (c.AnomalyZScore > 0 && c.AnomalyIQRFactor > 0), "ANOMALY_ZSCORE and ANOMALY_IQR_FACTOR must be positive")
+	check(!c.AnomalyDetection || (c.AnomalyMinHistory >= 4 && c.AnomalyMinHistory <= c.AnomalyLookback),
+		"ANOMALY_MIN_HISTORY must be at least 4 and at most ANOMALY_LOOKBACK")
+	check(!c.StreamsEnabled || c.StreamsPollInterval >= 100*time.Millisecond, "STREAMS_POLL_INTERVAL must be at least 100ms")
+	check(c.UserAuth != "local" || len(c.JWTSecret) >= 32, "USER_AUTH=local requires a JWT_SECRET of at least 32 bytes")
+	check(c.UserAuth != "local" || c.UserTokenTTL > 0, "USER_TOKEN_TTL must be positive")
+	check(c.UserAuth != "cognito" || (c.CognitoUserPoolID != "" && c.CognitoClientID != "" && c.AWSRegion != ""),
+		"USER_AUTH=cognito requires COGNITO_USER_POOL_ID, COGNITO_CLIENT_ID and AWS_REGION")
+	check(c.SMTPHost == "" || c.DigestFrom != "", "SMTP_HOST requires DIGEST_FROM")
+	// Scheduled digests are skipped once two hours late
+	check(c.DigestScheduleInterval <= time.Hour, "DIGEST_SCHEDULE_INTERVAL must be at most 1h")
+	check(c.SlackWebhookURL == "" || strings.HasPrefix(c.SlackWebhookURL, "https://"), "SLACK_WEBHOOK_URL must be an https URL")
+	check(c.APNSKeyFile == "" || (c.APNSKeyID != "" && c.APNSTeamID != "" && c.APNSTopic != ""),
+		"APNS_KEY_FILE requires APNS_KEY_ID, APNS_TEAM_ID and APNS_TOPIC")
+
+	// The memory backend runs one replica without the locks table the
+	// background workers coordinate through
+	if c.StorageBackend == "memory" {
+		check(!c.IngestEODEnabled, "INGEST_EOD_ENABLED is not supported with STORAGE_BACKEND=memory")
+		check(c.IngestQueueURL == "", "INGEST_QUEUE_URL is not supported with STORAGE_BACKEND=memory")
+		check(!c.StreamsEnabled, "STREAMS_ENABLED is not supported with STORAGE_BACKEND=memory")
+		check(c.SchedulerMode == "internal", "SCHEDULER_MODE=%s requires STORAGE_BACKEND=dynamodb", c.SchedulerMode)
+	}
+
+	check(c.RateLimitRPS >= 0 && c.AdminRateLimitRPS >= 0 && c.ClientRateLimitRPS >= 0, "rate limits must not be negative")
+	check(c.RateLimitBurst >= 0 && c.AdminRateLimitBurst >= 0 && c.ClientRateLimitBurst >= 0, "rate limit bursts must not be negative")
+	check(c.ResponseMaxItems >= 0 && c.ResponseMaxBytes >= 0, "response limits must not be negative")
+	check(c.ScannerVolumeLookback > 0, "SCANNER_VOLUME_LOOKBACK must be positive")
+	check(c.PurgeWritesPerSecond > 0, "PURGE_WRITES_PER_SECOND must be positive")
+	check(c.CacheBackend != "redis" || c.RedisRetryInterval > 0, "REDIS_RETRY_INTERVAL must be positive")
+	check(c.LockLease > 0, "LOCK_LEASE must be positive")
+	check(c.TickerChangeRetention > 0, "TICKER_CHANGE_RETENTION must be positive")
+	check(c.ActivityRetention > 0, "ACTIVITY_RETENTION must be positive")
+	check(c.ScanSegments >= 1 && c.ScanSegments <= 1000, "SCAN_SEGMENTS=%d is not between 1 and 1000", c.ScanSegments)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,227 @@
+package dynamostreams
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// shardRefresh is how often the shards of a stream are listed, to find the
+// ones DynamoDB opens as it rolls over shards every few hours
+const shardRefresh = time.Minute
+
+// API is the part of the streams API a Consumer reads through
+type API interface {
+	Shards(ctx context.Context, streamARN string) ([]Shard, error)
+	ShardIterator(ctx context.Context, streamARN, shardID, iteratorType, sequence string) (string, error)
+	Records(ctx context.Context, iterator string) ([]Record, string, error)
+}
+
+// Handler handles a batch of records of one shard, in stream order
+type Handler interface {
+	HandleRecords(ctx context.Context, records []Record) error
+}
+
+// HandlerFunc adapts a function to a Handler
+type HandlerFunc func(ctx context.Context, records []Record) error
+
+func (f HandlerFunc) HandleRecords(ctx context.Context, records []Record) error {
+	return f(ctx, records)
+}
+
+type namedHandler struct {
+	name    string
+	handler Handler
+}
+
+// shardReader tracks how far a shard has been read
+type shardReader struct {
+	parentID string
+	// start is the iterator type of the shard's first iterator
+	start    string
+	iterator string
+	// sequence is the last record read, to resume after once an iterator expires
+	sequence string
+}
+
+// Consumer reads the records of a stream from when it started and passes
+// each batch to its handlers in turn. Nothing is checkpointed: records
+// written while no consumer runs are not seen, and a failing handler is
+// logged without the batch being handed to it again, so handlers maintain
+// state that is also refreshed another way, such as caches with a TTL.
+type Consumer struct {
+	api      API
+	name     string
+	arn      string
+	interval time.Duration
+	handlers []namedHandler
+	log      *zap.SugaredLogger
+
+	shards map[string]*shardReader
+	// ended holds the shards read to their end, or closed before the
+	// consumer started
+	ended map[string]bool
+	// refreshed is when the shards were last listed; zero lists them next poll
+	refreshed time.Time
+	started   bool
+}
+
+// NewConsumer returns a consumer of the stream streamARN, named for logs,
+// reading each of its shards every interval
+func NewConsumer(api API, name, streamARN string, interval time.Duration, log *zap.SugaredLogger) *Consumer {
+	return &Consumer{
+		api:      api,
+		name:     name,
+		arn:      streamARN,
+		interval: interval,
+		log:      log.With("stream", name),
+	}
+}
+
+// Register adds a handler, named for logs, passed the records after the
+// handlers registered before it
+func (c *Consumer) Register(name string, handler Handler) *Consumer {
+	c.handlers = append(c.handlers, namedHandler{name: name, handler: handler})
+	return c
+}
+
+// Run reads the stream until ctx is done. Each run starts after the latest
+// records, as when the consumer runs again on becoming leader.
+func (c *Consumer) Run(ctx context.Context) error {
+	c.reset()
+	c.log.Infow("consuming stream", "arn", c.arn, "handlers", len(c.handlers))
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.poll(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// reset forgets the shards read so far
+func (c *Consumer) reset() {
+	c.shards = make(map[string]*shardReader)
+	c.ended = make(map[string]bool)
+	c.refreshed = time.Time{}
+	c.started = false
+}
+
+// poll reads the records of every shard once, finding new shards first when
+// they are due to be listed
+func (c *Consumer) poll(ctx context.Context) {
+	if time.Since(c.refreshed) >= shardRefresh {
+		if err := c.refresh(ctx); err != nil {
+			if ctx.Err() == nil {
+				c.log.Warnw("failed to list stream shards", "error", err)
+			}
+			return
+		}
+	}
+
+	// A shard whose parent is still being read waits for it, so the records
+	// of an item are handled in order across a shard rollover
+	ids := make([]string, 0, len(c.shards))
+	for id, shard := range c.shards {
+		if _, reading := c.shards[shard.parentID]; !reading {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		c.read(ctx, id, c.shards[id])
+	}
+}
+
+// refresh lists the shards of the stream. On the first listing the consumer
+// starts at the latest record of every open shard; shards found later were
+// opened since, and are read from their start.
+func (c *Consumer) refresh(ctx context.Context) error {
+	shards, err := c.api.Shards(ctx, c.arn)
+	if err != nil {
+		return err
+	}
+	first := !c.started
+	c.started = true
+	c.refreshed = time.Now()
+
+	listed := make(map[string]bool, len(shards))
+	for _, shard := range shards {
+		listed[shard.ID] = true
+		if _, reading := c.shards[shard.ID]; reading || c.ended[shard.ID] {
+			continue
+		}
+		switch {
+		case first && shard.Closed():
+			c.ended[shard.ID] = true
+		case first:
+			c.shards[shard.ID] = &shardReader{parentID: shard.ParentID, start: IteratorLatest}
+		default:
+			c.shards[shard.ID] = &shardReader{parentID: shard.ParentID, start: IteratorTrimHorizon}
+		}
+	}
+	// Shards are trimmed 24 hours after they close
+	for id := range c.ended {
+		if !listed[id] {
+			delete(c.ended, id)
+		}
+	}
+	return nil
+}
+
+// read hands the next records of the shard to the handlers
+func (c *Consumer) read(ctx context.Context, id string, shard *shardReader) {
+	log := c.log.With("shard", id)
+	if shard.iterator == "" {
+		iteratorType := shard.start
+		if shard.sequence != "" {
+			iteratorType = IteratorAfterSequenceNumber
+		}
+		iterator, err := c.api.ShardIterator(ctx, c.arn, id, iteratorType, shard.sequence)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warnw("failed to get shard iterator", "error", err)
+			}
+			return
+		}
+		shard.iterator = iterator
+	}
+
+	records, next, err := c.api.Records(ctx, shard.iterator)
+	if err != nil {
+		if errors.Is(err, ErrExpiredIterator) {
+			shard.iterator = ""
+			return
+		}
+		if ctx.Err() == nil {
+			log.Warnw("failed to read stream records", "error", err)
+		}
+		return
+	}
+
+	if len(records) > 0 {
+		for _, h := range c.handlers {
+			if err := h.handler.HandleRecords(ctx, records); err != nil {
+				log.Errorw("stream handler failed", "handler", h.name, "records", len(records), "error", err)
+			}
+		}
+		shard.sequence = records[len(records)-1].Change.SequenceNumber
+	}
+
+	shard.iterator = next
+	if next == "" {
+		// The shard is closed and read; its children are listed next poll
+		delete(c.shards, id)
+		c.ended[id] = true
+		c.refreshed = time.Time{}
+	}
+}
@@ -0,0 +1,250 @@
+// Package dynamostreams reads the change records of DynamoDB Streams and
+// dispatches them to handlers, for derived state to follow the tables it is
+// derived from without polling them.
+package dynamostreams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"profitify-backend/pkg/awsclient"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	// MaxRecords is the most records one GetRecords call returns
+	MaxRecords = 1000
+	// DefaultTimeout bounds each request to the streams endpoint
+	DefaultTimeout = 10 * time.Second
+)
+
+// Events of a Record
+const (
+	EventInsert = "INSERT"
+	EventModify = "MODIFY"
+	EventRemove = "REMOVE"
+)
+
+// Shard iterator types
+const (
+	// IteratorLatest starts after the most recent record of the shard
+	IteratorLatest = "LATEST"
+	// IteratorTrimHorizon starts at the oldest record of the shard
+	IteratorTrimHorizon = "TRIM_HORIZON"
+	// IteratorAfterSequenceNumber starts after the record of a sequence number
+	IteratorAfterSequenceNumber = "AFTER_SEQUENCE_NUMBER"
+)
+
+// ErrExpiredIterator is returned by GetRecords for an iterator older than
+// the 15 minutes DynamoDB keeps them valid
+var ErrExpiredIterator = errors.New("shard iterator expired")
+
+// Shard is a shard of a stream. A shard is closed once it has an ending
+// sequence number; its records continue in the shards naming it as parent.
+type Shard struct {
+	ID             string
+	ParentID       string
+	EndingSequence string
+}
+
+// Closed reports whether the shard takes no more records
+func (s Shard) Closed() bool {
+	return s.EndingSequence != ""
+}
+
+// Record is a change to an item of a table
+type Record struct {
+	EventID string `json:"eventID"`
+	// EventName is "INSERT", "MODIFY" or "REMOVE"
+	EventName string `json:"eventName"`
+	Change    Change `json:"dynamodb"`
+}
+
+// Change holds the keys of the changed item and, as the stream's view type
+// allows, its images before and after the change
+type Change struct {
+	Keys           Item    `json:"Keys"`
+	NewImage       Item    `json:"NewImage,omitempty"`
+	OldImage       Item    `json:"OldImage,omitempty"`
+	SequenceNumber string  `json:"SequenceNumber"`
+	CreatedUTC     float64 `json:"ApproximateCreationDateTime"`
+}
+
+// Item is an item as streamed, in the DynamoDB JSON encoding
+type Item map[string]AttributeValue
+
+// Unmarshal decodes the item into out through its dynamodbav tags, as the
+// repositories read items
+func (i Item) Unmarshal(out any) error {
+	values := make(map[string]types.AttributeValue, len(i))
+	for name, v := range i {
+		values[name] = v.value()
+	}
+	return attributevalue.UnmarshalMap(values, out)
+}
+
+// String returns the string attribute name, or "" when it is not one
+func (i Item) String(name string) string {
+	if v, ok := i[name]; ok && v.S != nil {
+		return *v.S
+	}
+	return ""
+}
+
+// AttributeValue is an attribute in the DynamoDB JSON encoding; exactly one
+// field is set
+type AttributeValue struct {
+	S    *string                   `json:"S,omitempty"`
+	N    *string                   `json:"N,omitempty"`
+	B    []byte                    `json:"B,omitempty"`
+	BOOL *bool                     `json:"BOOL,omitempty"`
+	NULL *bool                     `json:"NULL,omitempty"`
+	SS   []string                  `json:"SS,omitempty"`
+	NS   []string                  `json:"NS,omitempty"`
+	BS   [][]byte                  `json:"BS,omitempty"`
+	L    []AttributeValue          `json:"L,omitempty"`
+	M    map[string]AttributeValue `json:"M,omitempty"`
+}
+
+func (v AttributeValue) value() types.AttributeValue {
+	switch {
+	case v.S != nil:
+		return &types.AttributeValueMemberS{Value: *v.S}
+	case v.N != nil:
+		return &types.AttributeValueMemberN{Value: *v.N}
+	case v.B != nil:
+		return &types.AttributeValueMemberB{Value: v.B}
+	case v.BOOL != nil:
+		return &types.AttributeValueMemberBOOL{Value: *v.BOOL}
+	case v.SS != nil:
+		return &types.AttributeValueMemberSS{Value: v.SS}
+	case v.NS != nil:
+		return &types.AttributeValueMemberNS{Value: v.NS}
+	case v.BS != nil:
+		return &types.AttributeValueMemberBS{Value: v.BS}
+	case v.L != nil:
+		list := make([]types.AttributeValue, len(v.L))
+		for i, e := range v.L {
+			list[i] = e.value()
+		}
+		return &types.AttributeValueMemberL{Value: list}
+	case v.M != nil:
+		m := make(map[string]types.AttributeValue, len(v.M))
+		for name, e := range v.M {
+			m[name] = e.value()
+		}
+		return &types.AttributeValueMemberM{Value: m}
+	default:
+		return &types.AttributeValueMemberNULL{Value: true}
+	}
+}
+
+// Client calls the DynamoDB Streams API
+type Client struct {
+	api *awsclient.SignedClient
+}
+
+// New returns a client of the streams endpoint of the region of awsCfg, or
+// of endpointURL when set, e.g. to target LocalStack
+func New(awsCfg aws.Config, endpointURL string, timeout time.Duration) *Client {
+	if endpointURL == "" {
+		endpointURL = fmt.Sprintf("https://streams.dynamodb.%s.amazonaws.com/", awsCfg.Region)
+	}
+	return &Client{api: awsclient.NewSignedClient(awsCfg, "dynamodb", endpointURL, timeout)}
+}
+
+// Shards lists every shard of the stream, closed ones included
+func (c *Client) Shards(ctx context.Context, streamARN string) ([]Shard, error) {
+	var shards []Shard
+	input := map[string]any{"StreamArn": streamARN}
+	for {
+		var resp struct {
+			StreamDescription struct {
+				Shards []struct {
+					ShardID             string `json:"ShardId"`
+					ParentShardID       string `json:"ParentShardId"`
+					SequenceNumberRange struct {
+						EndingSequenceNumber string `json:"EndingSequenceNumber"`
+					} `json:"SequenceNumberRange"`
+				} `json:"Shards"`
+				LastEvaluatedShardID string `json:"LastEvaluatedShardId"`
+			} `json:"StreamDescription"`
+		}
+		if err := c.call(ctx, "DescribeStream", input, &resp); err != nil {
+			return nil, err
+		}
+		for _, s := range resp.StreamDescription.Shards {
+			shards = append(shards, Shard{ID: s.ShardID, ParentID: s.ParentShardID, EndingSequence: s.SequenceNumberRange.EndingSequenceNumber})
+		}
+		if resp.StreamDescription.LastEvaluatedShardID == "" {
+			return shards, nil
+		}
+		input["ExclusiveStartShardId"] = resp.StreamDescription.LastEvaluatedShardID
+	}
+}
+
+// ShardIterator returns an iterator of the shard's records of iteratorType;
+// sequence is the record to start after with IteratorAfterSequenceNumber
+func (c *Client) ShardIterator(ctx context.Context, streamARN, shardID, iteratorType, sequence string) (string, error) {
+	input := map[string]any{
+		"StreamArn":         streamARN,
+		"ShardId":           shardID,
+		"ShardIteratorType": iteratorType,
+	}
+	if sequence != "" {
+		input["SequenceNumber"] = sequence
+	}
+	var resp struct {
+		ShardIterator string `json:"ShardIterator"`
+	}
+	if err := c.call(ctx, "GetShardIterator", input, &resp); err != nil {
+		return "", err
+	}
+	return resp.ShardIterator, nil
+}
+
+// Records returns the records at iterator and the iterator of the records
+// after them, which is empty once a closed shard has been read to its end
+func (c *Client) Records(ctx context.Context, iterator string) ([]Record, string, error) {
+	var resp struct {
+		Records           []Record `json:"Records"`
+		NextShardIterator string   `json:"NextShardIterator"`
+	}
+	err := c.call(ctx, "GetRecords", map[string]any{
+		"ShardIterator": iterator,
+		"Limit":         MaxRecords,
+	}, &resp)
+	if err != nil {
+		return nil, "", err
+	}
+	return resp.Records, resp.NextShardIterator, nil
+}
+
+func (c *Client) call(ctx context.Context, action string, input map[string]any, output any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", action, err)
+	}
+	respBody, err := c.api.Post(ctx, body, http.Header{
+		"Content-Type": {"application/x-amz-json-1.0"},
+		"X-Amz-Target": {"DynamoDBStreams_20120810." + action},
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "ExpiredIteratorException") {
+			return fmt.Errorf("dynamodb streams %s failed: %w", action, ErrExpiredIterator)
+		}
+		return fmt.Errorf("dynamodb streams %s failed: %w", action, err)
+	}
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", action, err)
+	}
+	return nil
+}
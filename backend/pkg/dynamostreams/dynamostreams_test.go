@@ -0,0 +1,225 @@
+package dynamostreams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var testAWS = aws.Config{
+	Region: "us-east-1",
+	Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	}),
+}
+
+const testARN = "arn:aws:dynamodb:us-east-1:123:table/tickers/stream/2025-01-01T00:00:00.000"
+
+func TestClient(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-amz-json-1.0", r.Header.Get("Content-Type"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/dynamodb/aws4_request")
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "DynamoDBStreams_20120810.DescribeStream":
+			if body["ExclusiveStartShardId"] == nil {
+				_, _ = io.WriteString(w, `{"StreamDescription":{"Shards":[{"ShardId":"s1","SequenceNumberRange":{"StartingSequenceNumber":"1","EndingSequenceNumber":"9"}}],"LastEvaluatedShardId":"s1"}}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"StreamDescription":{"Shards":[{"ShardId":"s2","ParentShardId":"s1","SequenceNumberRange":{"StartingSequenceNumber":"10"}}]}}`)
+		case "DynamoDBStreams_20120810.GetShardIterator":
+			_, _ = io.WriteString(w, `{"ShardIterator":"it1"}`)
+		case "DynamoDBStreams_20120810.GetRecords":
+			if body["ShardIterator"] == "expired" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = io.WriteString(w, `{"__type":"com.amazonaws.dynamodb.v20120810#ExpiredIteratorException"}`)
+				return
+			}
+			_, _ = io.WriteString(w, `{"Records":[{"eventID":"e1","eventName":"MODIFY","dynamodb":{
+				"Keys":{"ticker":{"S":"AAPL"}},
+				"NewImage":{"ticker":{"S":"AAPL"},"active":{"N":"1"},"tags":{"L":[{"S":"tech"},{"NULL":true}]}},
+				"SequenceNumber":"11","ApproximateCreationDateTime":1735689600}}],"NextShardIterator":"it2"}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"UnknownOperationException"}`)
+		}
+	}))
+	defer server.Close()
+
+	client := New(testAWS, server.URL, time.Second)
+	ctx := context.Background()
+
+	shards, err := client.Shards(ctx, testARN)
+	require.NoError(t, err)
+	assert.Equal(t, []Shard{{ID: "s1", EndingSequence: "9"}, {ID: "s2", ParentID: "s1"}}, shards)
+	assert.True(t, shards[0].Closed())
+	assert.False(t, shards[1].Closed())
+
+	iterator, err := client.ShardIterator(ctx, testARN, "s2", IteratorAfterSequenceNumber, "10")
+	require.NoError(t, err)
+	assert.Equal(t, "it1", iterator)
+
+	records, next, err := client.Records(ctx, iterator)
+	require.NoError(t, err)
+	assert.Equal(t, "it2", next)
+	require.Len(t, records, 1)
+	assert.Equal(t, EventModify, records[0].EventName)
+	assert.Equal(t, "AAPL", records[0].Change.Keys.String("ticker"))
+	assert.Equal(t, "11", records[0].Change.SequenceNumber)
+
+	var item struct {
+		Ticker string    `dynamodbav:"ticker"`
+		Active int       `dynamodbav:"active"`
+		Tags   []*string `dynamodbav:"tags"`
+	}
+	require.NoError(t, records[0].Change.NewImage.Unmarshal(&item))
+	assert.Equal(t, "AAPL", item.Ticker)
+	assert.Equal(t, 1, item.Active)
+	require.Len(t, item.Tags, 2)
+	assert.Equal(t, "tech", *item.Tags[0])
+	assert.Nil(t, item.Tags[1])
+
+	_, _, err = client.Records(ctx, "expired")
+	assert.ErrorIs(t, err, ErrExpiredIterator)
+
+	require.Len(t, requests, 5)
+	assert.Equal(t, map[string]any{"StreamArn": testARN, "ExclusiveStartShardId": "s1"}, requests[1])
+	assert.Equal(t, map[string]any{
+		"StreamArn":         testARN,
+		"ShardId":           "s2",
+		"ShardIteratorType": IteratorAfterSequenceNumber,
+		"SequenceNumber":    "10",
+	}, requests[2])
+	assert.Equal(t, map[string]any{"ShardIterator": "it1", "Limit": float64(MaxRecords)}, requests[3])
+}
+
+// fakeStream serves the records of shards; an iterator is the shard's ID and
+// the index of its next record
+type fakeStream struct {
+	shards  []Shard
+	records map[string][]Record
+	// iterators counts the iterators requested per shard and type
+	iterators map[string]int
+	expire    bool
+}
+
+func (s *fakeStream) Shards(ctx context.Context, streamARN string) ([]Shard, error) {
+	return s.shards, nil
+}
+
+func (s *fakeStream) ShardIterator(ctx context.Context, streamARN, shardID, iteratorType, sequence string) (string, error) {
+	s.iterators[shardID+"/"+iteratorType]++
+	records := s.records[shardID]
+	start := 0
+	switch iteratorType {
+	case IteratorLatest:
+		start = len(records)
+	case IteratorAfterSequenceNumber:
+		for i, r := range records {
+			if r.Change.SequenceNumber == sequence {
+				start = i + 1
+			}
+		}
+	}
+	b, _ := json.Marshal([]any{shardID, start})
+	return string(b), nil
+}
+
+func (s *fakeStream) Records(ctx context.Context, iterator string) ([]Record, string, error) {
+	if s.expire {
+		s.expire = false
+		return nil, "", ErrExpiredIterator
+	}
+	var position []any
+	_ = json.Unmarshal([]byte(iterator), &position)
+	shardID, next := position[0].(string), int(position[1].(float64))
+
+	records := s.records[shardID][next:]
+	for _, shard := range s.shards {
+		if shard.ID == shardID && shard.Closed() && len(records) == 0 {
+			return nil, "", nil
+		}
+	}
+	b, _ := json.Marshal([]any{shardID, next + len(records)})
+	return records, string(b), nil
+}
+
+func record(sequence, symbol string) Record {
+	return Record{EventName: EventInsert, Change: Change{Keys: Item{"ticker": {S: aws.String(symbol)}}, SequenceNumber: sequence}}
+}
+
+func TestConsumer(t *testing.T) {
+	stream := &fakeStream{
+		shards: []Shard{{ID: "old", EndingSequence: "5"}, {ID: "s1"}},
+		records: map[string][]Record{
+			"old": {record("5", "OLD")},
+			"s1":  {record("10", "BEFORE")},
+		},
+		iterators: map[string]int{},
+	}
+
+	var handled []string
+	handle := HandlerFunc(func(ctx context.Context, records []Record) error {
+		for _, r := range records {
+			handled = append(handled, r.Change.Keys.String("ticker"))
+		}
+		return nil
+	})
+	var failing int
+	consumer := NewConsumer(stream, "tickers", testARN, time.Second, zap.NewNop().Sugar()).
+		Register("failing", HandlerFunc(func(ctx context.Context, records []Record) error {
+			failing++
+			return errors.New("unavailable")
+		})).
+		Register("recording", handle)
+	consumer.reset()
+	ctx := context.Background()
+
+	// The consumer starts after the records written before it
+	consumer.poll(ctx)
+	assert.Empty(t, handled)
+	assert.Equal(t, map[string]int{"s1/" + IteratorLatest: 1}, stream.iterators, "closed shards are not read")
+
+	stream.records["s1"] = append(stream.records["s1"], record("11", "AAPL"), record("12", "MSFT"))
+	consumer.poll(ctx)
+	assert.Equal(t, []string{"AAPL", "MSFT"}, handled)
+	assert.Equal(t, 1, failing, "a failing handler does not stop the others")
+
+	// An expired iterator resumes after the last record read
+	stream.expire = true
+	consumer.poll(ctx)
+	stream.records["s1"] = append(stream.records["s1"], record("13", "NVDA"))
+	consumer.poll(ctx)
+	assert.Equal(t, []string{"AAPL", "MSFT", "NVDA"}, handled)
+	assert.Equal(t, 1, stream.iterators["s1/"+IteratorAfterSequenceNumber])
+
+	// A rollover closes the shard; its child is read from its start once the
+	// parent has been read to its end
+	stream.shards = []Shard{{ID: "old", EndingSequence: "5"}, {ID: "s1", EndingSequence: "14"}, {ID: "s2", ParentID: "s1"}}
+	stream.records["s1"] = append(stream.records["s1"], record("14", "TSLA"))
+	stream.records["s2"] = []Record{record("20", "AMZN")}
+	consumer.refreshed = time.Time{}
+	consumer.poll(ctx)
+	assert.Equal(t, []string{"AAPL", "MSFT", "NVDA", "TSLA"}, handled, "the child waits for its parent")
+	consumer.poll(ctx)
+	consumer.poll(ctx)
+	assert.Equal(t, []string{"AAPL", "MSFT", "NVDA", "TSLA", "AMZN"}, handled)
+	assert.Equal(t, 1, stream.iterators["s2/"+IteratorTrimHorizon])
+	assert.NotContains(t, consumer.shards, "s1")
+	assert.True(t, consumer.ended["s1"])
+}
@@ -0,0 +1,125 @@
+// Package errorlog keeps the server errors answered by this replica in a ring
+// buffer, so on-call can summarize recent failures without log access.
+package errorlog
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is how many errors a log keeps by default
+const DefaultCapacity = 1000
+
+// Entry is one error response
+type Entry struct {
+	At time.Time
+	// Route is the method and route template, e.g. "GET /api/v1/tickers/:symbol"
+	Route  string
+	Status int
+	// Code is the problem code answered, if any
+	Code string
+}
+
+// Group counts the errors of one route and code
+type Group struct {
+	Route       string `json:"route"`
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Count       int    `json:"count"`
+	LastSeenUTC int64  `json:"lastSeenUTC"`
+}
+
+// Summary counts the errors of a window, most frequent first
+type Summary struct {
+	FromUTC int64   `json:"fromUTC"`
+	ToUTC   int64   `json:"toUTC"`
+	Total   int     `json:"total"`
+	Groups  []Group `json:"groups"`
+	// Truncated is set when older errors of the window were overwritten, so
+	// counts are lower bounds
+	Truncated bool `json:"truncated"`
+}
+
+// Log is a fixed-size ring buffer of errors, safe for concurrent use. Once
+// full, each error overwrites the oldest.
+type Log struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// New creates a log keeping the last capacity errors
+func New(capacity int) *Log {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Log{entries: make([]Entry, capacity)}
+}
+
+// Record adds an error to the log
+func (l *Log) Record(entry Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries[l.next] = entry
+	l.next = (l.next + 1) % len(l.entries)
+	if l.next == 0 {
+		l.full = true
+	}
+}
+
+// Summarize groups the errors recorded in the window ending at now by route
+// and code
+func (l *Log) Summarize(window time.Duration, now time.Time) Summary {
+	from := now.Add(-window)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	summary := Summary{FromUTC: from.Unix(), ToUTC: now.Unix(), Groups: []Group{}}
+	groups := make(map[[2]string]*Group)
+	var oldest time.Time
+	for i, entry := range l.entries {
+		if !l.full && i >= l.next {
+			break
+		}
+		if oldest.IsZero() || entry.At.Before(oldest) {
+			oldest = entry.At
+		}
+		if entry.At.Before(from) || entry.At.After(now) {
+			continue
+		}
+
+		summary.Total++
+		key := [2]string{entry.Route, entry.Code}
+		group, ok := groups[key]
+		if !ok {
+			group = &Group{Route: entry.Route, Code: entry.Code, Status: entry.Status}
+			groups[key] = group
+		}
+		group.Count++
+		if at := entry.At.Unix(); at > group.LastSeenUTC {
+			group.LastSeenUTC = at
+			group.Status = entry.Status
+		}
+	}
+	summary.Truncated = l.full && oldest.After(from)
+
+	for _, group := range groups {
+		summary.Groups = append(summary.Groups, *group)
+	}
+	sort.Slice(summary.Groups, func(i, j int) bool {
+		a, b := summary.Groups[i], summary.Groups[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		return a.Code < b.Code
+	})
+
+	return summary
+}
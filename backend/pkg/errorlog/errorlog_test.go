@@ -0,0 +1,41 @@
+package errorlog
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLog_Summarize(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	log := New(4)
+
+	log.Record(Entry{At: now.Add(-2 * time.Hour), Route: "GET /api/tickers", Status: 500, Code: "INTERNAL_ERROR"})
+	log.Record(Entry{At: now.Add(-30 * time.Minute), Route: "GET /api/tickers", Status: 500, Code: "INTERNAL_ERROR"})
+	log.Record(Entry{At: now.Add(-20 * time.Minute), Route: "GET /api/alerts", Status: 503, Code: "UNAVAILABLE"})
+	log.Record(Entry{At: now.Add(-10 * time.Minute), Route: "GET /api/tickers", Status: 500, Code: "INTERNAL_ERROR"})
+
+	summary := log.Summarize(time.Hour, now)
+	assert.Equal(t, 3, summary.Total)
+	assert.False(t, summary.Truncated, "the error overwritten next is older than the window")
+	assert.Equal(t, []Group{
+		{Route: "GET /api/tickers", Code: "INTERNAL_ERROR", Status: 500, Count: 2, LastSeenUTC: now.Add(-10 * time.Minute).Unix()},
+		{Route: "GET /api/alerts", Code: "UNAVAILABLE", Status: 503, Count: 1, LastSeenUTC: now.Add(-20 * time.Minute).Unix()},
+	}, summary.Groups)
+
+	// Overwrites the error two hours ago, then the one 30 minutes ago
+	log.Record(Entry{At: now.Add(-5 * time.Minute), Route: "GET /api/alerts", Status: 503, Code: "UNAVAILABLE"})
+	log.Record(Entry{At: now.Add(-time.Minute), Route: "GET /api/alerts", Status: 503, Code: "UNAVAILABLE"})
+
+	summary = log.Summarize(time.Hour, now)
+	assert.Equal(t, 4, summary.Total)
+	assert.True(t, summary.Truncated)
+	assert.Equal(t, "GET /api/alerts", summary.Groups[0].Route)
+	assert.Equal(t, 3, summary.Groups[0].Count)
+
+	empty := New(0).Summarize(time.Hour, now)
+	assert.Zero(t, empty.Total)
+	assert.Empty(t, empty.Groups)
+	assert.False(t, empty.Truncated)
+}
@@ -0,0 +1,103 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"profitify-backend/pkg/awsclient"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// maxEventBridgeEntries is the most entries a PutEvents call accepts
+const maxEventBridgeEntries = 10
+
+type eventBridge struct {
+	api     *awsclient.SignedClient
+	busName string
+	source  string
+}
+
+// NewEventBridge returns a publisher putting events on cfg.BusName. Each
+// entry's detail type is the event type, and its detail the whole envelope.
+func NewEventBridge(awsCfg aws.Config, cfg Config) Publisher {
+	return &eventBridge{
+		api:     awsclient.NewSignedClient(awsCfg, "events", cfg.EndpointURL, cfg.Timeout),
+		busName: cfg.BusName,
+		source:  cfg.Source,
+	}
+}
+
+type putEventsEntry struct {
+	Source       string `json:"Source"`
+	DetailType   string `json:"DetailType"`
+	Detail       string `json:"Detail"`
+	EventBusName string `json:"EventBusName,omitempty"`
+	Time         int64  `json:"Time"`
+}
+
+type putEventsResponse struct {
+	FailedEntryCount int `json:"FailedEntryCount"`
+	Entries          []struct {
+		ErrorCode    string `json:"ErrorCode"`
+		ErrorMessage string `json:"ErrorMessage"`
+	} `json:"Entries"`
+}
+
+// Publish puts the events in batches of up to 10. A failed batch fails the
+// publish; the batches before it are delivered.
+func (p *eventBridge) Publish(ctx context.Context, events ...Event) error {
+	for start := 0; start < len(events); start += maxEventBridgeEntries {
+		batch := events[start:min(start+maxEventBridgeEntries, len(events))]
+		if err := p.put(ctx, batch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *eventBridge) put(ctx context.Context, events []Event) error {
+	entries := make([]putEventsEntry, len(events))
+	for i, event := range events {
+		event = stamp(event, p.source)
+		detail, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+		}
+		entries[i] = putEventsEntry{
+			Source:       event.Source,
+			DetailType:   event.Type,
+			Detail:       string(detail),
+			EventBusName: p.busName,
+			Time:         event.TimeUTC,
+		}
+	}
+
+	body, err := json.Marshal(map[string]any{"Entries": entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode events: %w", err)
+	}
+	respBody, err := p.api.Post(ctx, body, http.Header{
+		"Content-Type": {"application/x-amz-json-1.1"},
+		"X-Amz-Target": {"AWSEvents.PutEvents"},
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp putEventsResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return fmt.Errorf("failed to decode PutEvents response: %w", err)
+	}
+	if resp.FailedEntryCount > 0 {
+		for _, entry := range resp.Entries {
+			if entry.ErrorCode != "" {
+				return fmt.Errorf("%d of %d events were not put: %s: %s", resp.FailedEntryCount, len(events), entry.ErrorCode, entry.ErrorMessage)
+			}
+		}
+		return fmt.Errorf("%d of %d events were not put", resp.FailedEntryCount, len(events))
+	}
+	return nil
+}
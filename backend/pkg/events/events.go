@@ -0,0 +1,111 @@
+// Package events publishes domain events to Amazon EventBridge or SNS, so that
+// other systems react to changes instead of polling the API.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"profitify-backend/pkg/awsclient"
+)
+
+// Backends events are published through
+const (
+	BackendNone        = "none"
+	BackendEventBridge = "eventbridge"
+	BackendSNS         = "sns"
+)
+
+// Event is the envelope every event is published in. Type and Version name
+// the schema of Data; a change that is not backwards compatible is published
+// under a new version.
+type Event struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Version int    `json:"version"`
+	// Source names the system that published the event
+	Source  string `json:"source"`
+	TimeUTC int64  `json:"timeUTC"`
+	Data    any    `json:"data"`
+}
+
+// New returns an event of a type and schema version that happened now
+func New(eventType string, version int, data any) Event {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return Event{
+		ID:      hex.EncodeToString(id),
+		Type:    eventType,
+		Version: version,
+		TimeUTC: time.Now().Unix(),
+		Data:    data,
+	}
+}
+
+// Publisher delivers events to their subscribers
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+// Nop discards events, for deployments without an event bus
+type Nop struct{}
+
+func (Nop) Publish(ctx context.Context, events ...Event) error {
+	return nil
+}
+
+// Config selects the backend events are published through
+type Config struct {
+	// Backend is "eventbridge", "sns" or "none"
+	Backend string
+	// BusName is the EventBridge event bus; empty publishes to the default bus
+	BusName string
+	// TopicARN is the SNS topic
+	TopicARN string
+	// Source is stamped on events published without one
+	Source string
+
+	// Region and EndpointURL override the SDK defaults, e.g. to target LocalStack
+	Region      string
+	EndpointURL string
+	Timeout     time.Duration
+}
+
+// Open returns the publisher of the configured backend, a Nop one when events
+// are disabled
+func Open(ctx context.Context, cfg Config) (Publisher, error) {
+	switch cfg.Backend {
+	case "", BackendNone:
+		return Nop{}, nil
+	case BackendEventBridge, BackendSNS:
+	default:
+		return nil, fmt.Errorf("unknown events backend %q", cfg.Backend)
+	}
+
+	awsCfg, err := awsclient.LoadConfig(ctx, awsclient.Config{Region: cfg.Region})
+	if err != nil {
+		return nil, err
+	}
+	if awsCfg.Region == "" {
+		return nil, fmt.Errorf("an AWS region is required to publish events")
+	}
+
+	if cfg.Backend == BackendSNS {
+		if cfg.TopicARN == "" {
+			return nil, fmt.Errorf("an SNS topic ARN is required to publish events")
+		}
+		return NewSNS(awsCfg, cfg), nil
+	}
+	return NewEventBridge(awsCfg, cfg), nil
+}
+
+// stamp sets the source of events published without one
+func stamp(event Event, source string) Event {
+	if event.Source == "" {
+		event.Source = source
+	}
+	return event
+}
@@ -0,0 +1,120 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAWS = aws.Config{
+	Region: "us-east-1",
+	Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	}),
+}
+
+func TestEventBridge_Publish(t *testing.T) {
+	var batches [][]putEventsEntry
+	fail := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "AWSEvents.PutEvents", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 "))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/events/aws4_request")
+
+		var body struct{ Entries []putEventsEntry }
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		batches = append(batches, body.Entries)
+		if fail {
+			_, _ = io.WriteString(w, `{"FailedEntryCount":1,"Entries":[{"ErrorCode":"InternalFailure","ErrorMessage":"try again"}]}`)
+			return
+		}
+		_, _ = io.WriteString(w, `{"FailedEntryCount":0,"Entries":[]}`)
+	}))
+	defer server.Close()
+
+	publisher := NewEventBridge(testAWS, Config{BusName: "profitify", Source: "profitify.api", EndpointURL: server.URL, Timeout: time.Second})
+
+	events := make([]Event, 12)
+	for i := range events {
+		events[i] = New("TickerUpdated", 1, map[string]string{"symbol": "AAPL"})
+	}
+	events[0].Source = "profitify.ingest"
+	require.NoError(t, publisher.Publish(context.Background(), events...))
+
+	require.Len(t, batches, 2, "at most 10 entries per call")
+	assert.Len(t, batches[0], 10)
+	assert.Len(t, batches[1], 2)
+	entry := batches[0][0]
+	assert.Equal(t, "profitify.ingest", entry.Source)
+	assert.Equal(t, "profitify.api", batches[0][1].Source)
+	assert.Equal(t, "TickerUpdated", entry.DetailType)
+	assert.Equal(t, "profitify", entry.EventBusName)
+
+	var detail Event
+	require.NoError(t, json.Unmarshal([]byte(entry.Detail), &detail))
+	assert.Equal(t, events[0].ID, detail.ID)
+	assert.Equal(t, 1, detail.Version)
+	assert.Equal(t, map[string]any{"symbol": "AAPL"}, detail.Data)
+
+	fail = true
+	err := publisher.Publish(context.Background(), events[0])
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "InternalFailure")
+}
+
+func TestSNS_Publish(t *testing.T) {
+	var forms []url.Values
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/sns/aws4_request")
+		require.NoError(t, r.ParseForm())
+		forms = append(forms, r.PostForm)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	topic := "arn:aws:sns:us-east-1:123456789012:profitify-events"
+	publisher := NewSNS(testAWS, Config{TopicARN: topic, Source: "profitify.api", EndpointURL: server.URL, Timeout: time.Second})
+
+	event := New("AlertTriggered", 2, map[string]string{"alertId": "a1"})
+	require.NoError(t, publisher.Publish(context.Background(), event, event))
+
+	require.Len(t, forms, 2, "one message per event")
+	form := forms[0]
+	assert.Equal(t, "Publish", form.Get("Action"))
+	assert.Equal(t, topic, form.Get("TopicArn"))
+	assert.Equal(t, "type", form.Get("MessageAttributes.entry.1.Name"))
+	assert.Equal(t, "AlertTriggered", form.Get("MessageAttributes.entry.1.Value.StringValue"))
+	assert.Equal(t, "version", form.Get("MessageAttributes.entry.2.Name"))
+	assert.Equal(t, "2", form.Get("MessageAttributes.entry.2.Value.StringValue"))
+
+	var message Event
+	require.NoError(t, json.Unmarshal([]byte(form.Get("Message")), &message))
+	assert.Equal(t, "profitify.api", message.Source)
+	assert.Equal(t, "AlertTriggered", message.Type)
+
+	status = http.StatusForbidden
+	assert.Error(t, publisher.Publish(context.Background(), event))
+}
+
+func TestOpen(t *testing.T) {
+	publisher, err := Open(context.Background(), Config{Backend: BackendNone})
+	require.NoError(t, err)
+	assert.Equal(t, Nop{}, publisher)
+
+	_, err = Open(context.Background(), Config{Backend: "kafka"})
+	assert.Error(t, err)
+
+	_, err = Open(context.Background(), Config{Backend: BackendSNS, Region: "us-east-1"})
+	assert.Error(t, err, "a topic is required")
+}
@@ -0,0 +1,32 @@
+// Package eventstest provides a publisher for tests of code publishing
+// domain events
+package eventstest
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"profitify-backend/pkg/events"
+)
+
+// RecordingPublisher collects the events it is asked to publish. It is safe
+// for concurrent use.
+type RecordingPublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (p *RecordingPublisher) Publish(ctx context.Context, published ...events.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, published...)
+	return nil
+}
+
+// Events returns the events published so far, oldest first
+func (p *RecordingPublisher) Events() []events.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.events)
+}
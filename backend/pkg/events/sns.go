@@ -0,0 +1,73 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"profitify-backend/pkg/awsclient"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+type snsTopic struct {
+	api      *awsclient.SignedClient
+	topicARN string
+	source   string
+}
+
+// NewSNS returns a publisher publishing events to cfg.TopicARN. Each message
+// is the whole envelope, with the event's type and version as message
+// attributes that subscriptions can filter on.
+func NewSNS(awsCfg aws.Config, cfg Config) Publisher {
+	return &snsTopic{
+		api:      awsclient.NewSignedClient(awsCfg, "sns", cfg.EndpointURL, cfg.Timeout),
+		topicARN: cfg.TopicARN,
+		source:   cfg.Source,
+	}
+}
+
+// Publish publishes the events one message at a time, stopping at the first
+// failure
+func (p *snsTopic) Publish(ctx context.Context, events ...Event) error {
+	for _, event := range events {
+		if err := p.publish(ctx, stamp(event, p.source)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *snsTopic) publish(ctx context.Context, event Event) error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event: %w", event.Type, err)
+	}
+
+	form := url.Values{
+		"Action":   {"Publish"},
+		"Version":  {"2010-03-31"},
+		"TopicArn": {p.topicARN},
+		"Message":  {string(message)},
+	}
+	attributes := []struct{ name, dataType, value string }{
+		{"type", "String", event.Type},
+		{"version", "Number", strconv.Itoa(event.Version)},
+	}
+	for i, attribute := range attributes {
+		prefix := fmt.Sprintf("MessageAttributes.entry.%d.", i+1)
+		form.Set(prefix+"Name", attribute.name)
+		form.Set(prefix+"Value.DataType", attribute.dataType)
+		form.Set(prefix+"Value.StringValue", attribute.value)
+	}
+
+	if _, err := p.api.Post(ctx, []byte(form.Encode()), http.Header{
+		"Content-Type": {"application/x-www-form-urlencoded"},
+	}); err != nil {
+		return fmt.Errorf("failed to publish %s event: %w", event.Type, err)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/tracing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// apiKeyMetadata carries the API key of a call, as X-API-Key does for HTTP
+var apiKeyMetadata = strings.ToLower(middleware.APIKeyHeader)
+
+// infrastructurePrefix names the reflection and health services, which are
+// served without an API key
+const infrastructurePrefix = "/grpc."
+
+type interceptors struct {
+	auth AuthConfig
+	log  *zap.SugaredLogger
+}
+
+func (i interceptors) unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	err = i.call(ctx, info.FullMethod, func(ctx context.Context) error {
+		resp, err = handler(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+func (i interceptors) stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return i.call(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+		return handler(srv, contextStream{ServerStream: ss, ctx: ctx})
+	})
+}
+
+// call runs a call traced, logged and authenticated, turning a panic into an
+// INTERNAL error
+func (i interceptors) call(ctx context.Context, method string, fn func(ctx context.Context) error) (err error) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(incomingMetadata(ctx)))
+	service, rpc := splitMethod(method)
+	ctx, span := tracing.StartKind(ctx, method, trace.SpanKindServer,
+		semconv.RPCSystemGRPC, semconv.RPCService(service), semconv.RPCMethod(rpc))
+	defer span.End()
+
+	log := logger.FromContext(ctx, i.log).With("grpc_method", method)
+	if spanContext := span.SpanContext(); spanContext.IsSampled() {
+		log = log.With("trace_id", spanContext.TraceID().String())
+	}
+	ctx = logger.NewContext(ctx, log)
+
+	start := time.Now()
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Errorw("grpc call panicked", "panic", recovered)
+			err = status.Error(grpccodes.Internal, "internal server error")
+		}
+
+		code := status.Code(err)
+		span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+		fields := []any{"code", code.String(), "latency_ms", time.Since(start).Milliseconds()}
+		switch code {
+		case grpccodes.OK:
+			log.Infow("Call completed", fields...)
+		case grpccodes.Internal, grpccodes.Unknown, grpccodes.Unavailable, grpccodes.DataLoss:
+			span.SetStatus(codes.Error, code.String())
+			log.Errorw("Call failed", append(fields, "error", err)...)
+		default:
+			log.Warnw("Client error", append(fields, "error", err)...)
+		}
+	}()
+
+	if !strings.HasPrefix(method, infrastructurePrefix) {
+		if ctx, err = i.authenticate(ctx); err != nil {
+			return err
+		}
+	}
+	return fn(ctx)
+}
+
+// authenticate resolves the call's API key into its account on ctx, which
+// services enforce plan limits against
+func (i interceptors) authenticate(ctx context.Context) (context.Context, error) {
+	keys := incomingMetadata(ctx).Get(apiKeyMetadata)
+	if len(keys) == 0 || keys[0] == "" {
+		if i.auth.RequireAPIKey {
+			return nil, status.Error(grpccodes.Unauthenticated, "missing API key")
+		}
+		return ctx, nil
+	}
+
+	record, err := i.auth.Authenticator.Authenticate(ctx, keys[0])
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAPIKey) {
+			return nil, status.Error(grpccodes.Unauthenticated, "invalid API key")
+		}
+		logger.FromContext(ctx, i.log).Errorw("failed to authenticate call", "error", err)
+		return nil, status.Error(grpccodes.Internal, "failed to authenticate call")
+	}
+	if !record.HasScope(models.ScopeReadMarket) {
+		return nil, status.Error(grpccodes.PermissionDenied, "API key lacks the "+string(models.ScopeReadMarket)+" scope")
+	}
+	return service.WithAccount(ctx, record), nil
+}
+
+func incomingMetadata(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return md
+}
+
+// splitMethod splits /package.Service/Method into its service and method
+func splitMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	return service, method
+}
+
+// contextStream is a server stream whose context carries the call's account,
+// logger and span
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context {
+	return s.ctx
+}
+
+// metadataCarrier reads trace context propagated as gRPC metadata
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,101 @@
+// Package grpcserver serves the gRPC API next to the HTTP server, for internal
+// services that prefer protobuf to parsing JSON. Feature modules register
+// their services, which share the service layer with their HTTP handlers;
+// server reflection and the standard health service are always registered.
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"profitify-backend/internal/middleware"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Registrar registers a feature's gRPC services
+type Registrar interface {
+	RegisterGRPC(s grpc.ServiceRegistrar)
+}
+
+// AuthConfig controls API key authentication of the gRPC calls, which send the
+// key as x-api-key metadata
+type AuthConfig struct {
+	Authenticator middleware.Authenticator
+	// RequireAPIKey rejects calls without a key, as the API routes do
+	RequireAPIKey bool
+}
+
+type Server struct {
+	server          *grpc.Server
+	health          *health.Server
+	shutdownTimeout time.Duration
+	log             *zap.SugaredLogger
+}
+
+// New builds a server of the registrars' services. Calls are logged, traced
+// and authenticated like API requests, and must be allowed the read:market
+// scope, which every service served so far requires. They are neither rate
+// limited nor counted against quotas, so the port is for internal callers.
+func New(auth AuthConfig, shutdownTimeout time.Duration, log *zap.SugaredLogger, registrars ...Registrar) *Server {
+	interceptors := interceptors{auth: auth, log: log}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(interceptors.unary),
+		grpc.ChainStreamInterceptor(interceptors.stream),
+	)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	for _, registrar := range registrars {
+		registrar.RegisterGRPC(server)
+	}
+
+	return &Server{
+		server:          server,
+		health:          healthServer,
+		shutdownTimeout: shutdownTimeout,
+		log:             log,
+	}
+}
+
+// Serve serves calls on lis until ctx is done, then stops gracefully: running
+// calls get the shutdown timeout to finish before they are cancelled
+func (s *Server) Serve(ctx context.Context, lis net.Listener) error {
+	served := make(chan error, 1)
+	go func() {
+		s.log.Infow("starting grpc server", "address", lis.Addr().String())
+		served <- s.server.Serve(lis)
+	}()
+
+	select {
+	case err := <-served:
+		if err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			return fmt.Errorf("grpc server failed: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.health.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(s.shutdownTimeout):
+		s.log.Warnw("grpc calls still running at shutdown timeout, cancelling them", "timeout", s.shutdownTimeout)
+		s.server.Stop()
+	}
+	s.log.Info("grpc server stopped")
+	return nil
+}
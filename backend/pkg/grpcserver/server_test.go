@@ -0,0 +1,154 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	profitifyv1 "profitify-backend/api/proto/profitify/v1"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type fakeAuthenticator map[string]*models.APIKey
+
+func (f fakeAuthenticator) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if key == "broken" {
+		return nil, errors.New("table unavailable")
+	}
+	record, ok := f[key]
+	if !ok {
+		return nil, service.ErrInvalidAPIKey
+	}
+	return record, nil
+}
+
+// accountTickers answers with the ID of the calling account as the ticker name
+type accountTickers struct {
+	profitifyv1.UnimplementedTickerServiceServer
+}
+
+func (accountTickers) GetTicker(ctx context.Context, req *profitifyv1.GetTickerRequest) (*profitifyv1.Ticker, error) {
+	if req.GetSymbol() == "PANIC" {
+		panic("boom")
+	}
+	ticker := &profitifyv1.Ticker{Ticker: req.GetSymbol()}
+	if key, ok := service.AccountFromContext(ctx); ok {
+		ticker.Name = key.ID
+	}
+	return ticker, nil
+}
+
+type registrarFunc func(s grpc.ServiceRegistrar)
+
+func (f registrarFunc) RegisterGRPC(s grpc.ServiceRegistrar) { f(s) }
+
+// serve starts a server over an in-memory listener and returns a connection
+// to it
+func serve(t *testing.T, auth AuthConfig) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := New(auth, time.Second, zap.NewNop().Sugar(), registrarFunc(func(s grpc.ServiceRegistrar) {
+		profitifyv1.RegisterTickerServiceServer(s, accountTickers{})
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- srv.Serve(ctx, lis) }()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		assert.NoError(t, <-served)
+	})
+	return conn
+}
+
+func TestServer_Auth(t *testing.T) {
+	conn := serve(t, AuthConfig{
+		Authenticator: fakeAuthenticator{
+			"valid":     {ID: "key-1"},
+			"portfolio": {ID: "key-2", Scopes: []models.APIKeyScope{models.ScopeWritePortfolio}},
+		},
+		RequireAPIKey: true,
+	})
+	client := profitifyv1.NewTickerServiceClient(conn)
+	call := func(key string) (*profitifyv1.Ticker, error) {
+		ctx := context.Background()
+		if key != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, apiKeyMetadata, key)
+		}
+		return client.GetTicker(ctx, &profitifyv1.GetTickerRequest{Symbol: "AAPL"})
+	}
+
+	ticker, err := call("valid")
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", ticker.GetName(), "the call carries the key's account")
+
+	tests := []struct {
+		key  string
+		code codes.Code
+	}{
+		{"", codes.Unauthenticated},
+		{"unknown", codes.Unauthenticated},
+		{"portfolio", codes.PermissionDenied},
+		{"broken", codes.Internal},
+	}
+	for _, tt := range tests {
+		_, err := call(tt.key)
+		assert.Equal(t, tt.code, status.Code(err), "key %q", tt.key)
+	}
+
+	health, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err, "health checks need no key")
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, health.GetStatus())
+}
+
+func TestServer_Recovery(t *testing.T) {
+	conn := serve(t, AuthConfig{})
+	client := profitifyv1.NewTickerServiceClient(conn)
+
+	_, err := client.GetTicker(context.Background(), &profitifyv1.GetTickerRequest{Symbol: "PANIC"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	ticker, err := client.GetTicker(context.Background(), &profitifyv1.GetTickerRequest{Symbol: "AAPL"})
+	require.NoError(t, err, "calls without a key are served when keys are optional")
+	assert.Empty(t, ticker.GetName())
+}
+
+func TestServer_Reflection(t *testing.T) {
+	conn := serve(t, AuthConfig{RequireAPIKey: true})
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+
+	var services []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		services = append(services, s.GetName())
+	}
+	assert.Contains(t, services, "profitify.v1.TickerService")
+	assert.Contains(t, services, "grpc.health.v1.Health")
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksRefreshInterval limits how often the keys are fetched again for a
+// token signed with an unknown key, as after the provider rotated its keys
+const jwksRefreshInterval = time.Minute
+
+// JWKS verifies RS256 tokens against the JSON Web Key Set an identity
+// provider publishes, fetched on first use and again when it rotates keys
+type JWKS struct {
+	url      string
+	issuer   string
+	audience string
+	client   *http.Client
+	now      func() time.Time
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fetched time.Time
+}
+
+// NewJWKS returns a verifier of tokens issued by issuer for audience, signed
+// with the keys published at url
+func NewJWKS(url, issuer, audience string, client *http.Client) *JWKS {
+	return &JWKS{
+		url:      url,
+		issuer:   issuer,
+		audience: audience,
+		client:   client,
+		now:      time.Now,
+	}
+}
+
+// NewCognito returns a verifier of the ID and access tokens of an Amazon
+// Cognito user pool issued to the app client clientID
+func NewCognito(region, userPoolID, clientID string, client *http.Client) *JWKS {
+	issuer := fmt.Sprintf("https://cognito-idp.%s.amazonaws.com/%s", region, userPoolID)
+	return NewJWKS(issuer+"/.well-known/jwks.json", issuer, clientID, client)
+}
+
+// Verify checks the signature, expiry, issuer and audience of an RS256 token.
+// Cognito access tokens name the app client in client_id instead of aud.
+func (j *JWKS) Verify(ctx context.Context, token string) (*Claims, error) {
+	h, claims, signed, signature, err := parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if h.Alg != "RS256" {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, h.Alg)
+	}
+
+	key, err := j.key(ctx, h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyRS256(key, signed, signature); err != nil {
+		return nil, err
+	}
+	if err := validate(claims, j.issuer, j.now()); err != nil {
+		return nil, err
+	}
+	if !claims.Audience.Contains(j.audience) && claims.ClientID != j.audience {
+		return nil, fmt.Errorf("%w: issued for another client", ErrInvalidToken)
+	}
+	return claims, nil
+}
+
+// key returns the public key kid, fetching the key set when kid is unknown
+func (j *JWKS) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	if !j.fetched.IsZero() && j.now().Sub(j.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+
+	keys, err := j.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	j.keys = keys
+	j.fetched = j.now()
+
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+func (j *JWKS) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build jwks request: %w", err)
+	}
+	resp, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch jwks: status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("failed to decode jwks: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, err := encoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key %q: %w", k.Kid, err)
+		}
+		e, err := encoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return keys, nil
+}
@@ -0,0 +1,202 @@
+// Package jwt signs and verifies the JSON Web Tokens users authenticate with:
+// HS256 tokens the server issues itself, and RS256 tokens of an identity
+// provider such as Amazon Cognito, verified against its published keys.
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken rejects tokens that are malformed, badly signed, expired or
+// issued for someone else
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the registered claims of a token, plus those Cognito adds
+type Claims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	// ClientID names the app client of Cognito access tokens, which carry
+	// no audience
+	ClientID string `json:"client_id,omitempty"`
+	// TokenUse is "id" or "access" on Cognito tokens
+	TokenUse string `json:"token_use,omitempty"`
+}
+
+// Audience is the aud claim, a single string or a list of them
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*a = Audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// Contains reports whether the token was issued for audience
+func (a Audience) Contains(audience string) bool {
+	for _, aud := range a {
+		if aud == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier checks tokens and returns their claims
+type Verifier interface {
+	Verify(ctx context.Context, token string) (*Claims, error)
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+var encoding = base64.RawURLEncoding
+
+// parse splits token into its header and claims, and the signed input and
+// signature they are verified with
+func parse(token string) (*header, *Claims, []byte, []byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, nil, nil, nil, fmt.Errorf("%w: not a JWT", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: bad header: %v", ErrInvalidToken, err)
+	}
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: bad claims: %v", ErrInvalidToken, err)
+	}
+	signature, err := encoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("%w: bad signature encoding", ErrInvalidToken)
+	}
+	return &h, &claims, []byte(parts[0] + "." + parts[1]), signature, nil
+}
+
+func decodeSegment(segment string, v any) error {
+	data, err := encoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// validate checks the time and issuer of claims at now
+func validate(claims *Claims, issuer string, now time.Time) error {
+	if claims.ExpiresAt == 0 || now.Unix() >= claims.ExpiresAt {
+		return fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	if claims.Issuer != issuer {
+		return fmt.Errorf("%w: issued by %q", ErrInvalidToken, claims.Issuer)
+	}
+	if claims.Subject == "" {
+		return fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	return nil
+}
+
+// HMAC signs and verifies HS256 tokens with a shared secret
+type HMAC struct {
+	secret []byte
+	issuer string
+	now    func() time.Time
+}
+
+// NewHMAC returns a signer of tokens issued by issuer. The secret should be
+// at least 32 random bytes.
+func NewHMAC(secret []byte, issuer string) *HMAC {
+	return &HMAC{
+		secret: secret,
+		issuer: issuer,
+		now:    time.Now,
+	}
+}
+
+// Sign issues a token for subject expiring after ttl
+func (s *HMAC) Sign(subject, email string, ttl time.Duration) (string, *Claims, error) {
+	now := s.now()
+	claims := &Claims{
+		Issuer:    s.issuer,
+		Subject:   subject,
+		Email:     email,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", nil, err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, err
+	}
+	signed := encoding.EncodeToString(h) + "." + encoding.EncodeToString(c)
+	return signed + "." + encoding.EncodeToString(s.mac([]byte(signed))), claims, nil
+}
+
+// Verify checks the signature, expiry and issuer of an HS256 token
+func (s *HMAC) Verify(ctx context.Context, token string) (*Claims, error) {
+	h, claims, signed, signature, err := parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if h.Alg != "HS256" {
+		return nil, fmt.Errorf("%w: unexpected algorithm %q", ErrInvalidToken, h.Alg)
+	}
+	if !hmac.Equal(signature, s.mac(signed)) {
+		return nil, fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+	if err := validate(claims, s.issuer, s.now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (s *HMAC) mac(signed []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(signed)
+	return mac.Sum(nil)
+}
+
+// verifyRS256 checks an RS256 signature of signed
+func verifyRS256(key *rsa.PublicKey, signed, signature []byte) error {
+	digest := sha256.Sum256(signed)
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return fmt.Errorf("%w: bad signature", ErrInvalidToken)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMAC(t *testing.T) {
+	ctx := context.Background()
+	signer := NewHMAC([]byte("0123456789abcdef0123456789abcdef"), "profitify")
+
+	token, issued, err := signer.Sign("ada@example.com", "ada@example.com", time.Hour)
+	require.NoError(t, err)
+	claims, err := signer.Verify(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, issued, claims)
+	assert.Equal(t, "ada@example.com", claims.Subject)
+
+	_, err = signer.Verify(ctx, token+"x")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = NewHMAC([]byte("another secret of thirty-two bytes"), "profitify").Verify(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "signed with another secret")
+	_, err = NewHMAC(signer.secret, "someone-else").Verify(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "issued by another issuer")
+	_, err = signer.Verify(ctx, "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	signer.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	_, err = signer.Verify(ctx, token)
+	assert.ErrorIs(t, err, ErrInvalidToken, "expired")
+}
+
+// signRS256 signs claims with key the way an identity provider does
+func signRS256(t *testing.T, key *rsa.PrivateKey, kid string, claims Claims) string {
+	h, err := json.Marshal(header{Alg: "RS256", Kid: kid, Typ: "JWT"})
+	require.NoError(t, err)
+	c, err := json.Marshal(claims)
+	require.NoError(t, err)
+	signed := encoding.EncodeToString(h) + "." + encoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + encoding.EncodeToString(signature)
+}
+
+func TestJWKS(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fetches := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []jsonWebKey{{
+			Kty: "RSA",
+			Kid: "k1",
+			N:   encoding.EncodeToString(key.N.Bytes()),
+			E:   encoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	defer server.Close()
+
+	verifier := NewJWKS(server.URL, "https://issuer", "client", server.Client())
+	exp := time.Now().Add(time.Hour).Unix()
+
+	// An ID token names the client as its audience, an access token in client_id
+	claims, err := verifier.Verify(ctx, signRS256(t, key, "k1", Claims{
+		Issuer: "https://issuer", Subject: "sub-1", Audience: Audience{"client"}, ExpiresAt: exp, Email: "ada@example.com",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "sub-1", claims.Subject)
+	assert.Equal(t, "ada@example.com", claims.Email)
+	_, err = verifier.Verify(ctx, signRS256(t, key, "k1", Claims{
+		Issuer: "https://issuer", Subject: "sub-1", ClientID: "client", TokenUse: "access", ExpiresAt: exp,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, 1, fetches, "the keys are fetched once")
+
+	_, err = verifier.Verify(ctx, signRS256(t, key, "k1", Claims{
+		Issuer: "https://issuer", Subject: "sub-1", Audience: Audience{"other"}, ExpiresAt: exp,
+	}))
+	assert.ErrorIs(t, err, ErrInvalidToken, "issued for another client")
+	_, err = verifier.Verify(ctx, signRS256(t, key, "k2", Claims{
+		Issuer: "https://issuer", Subject: "sub-1", Audience: Audience{"client"}, ExpiresAt: exp,
+	}))
+	assert.ErrorIs(t, err, ErrInvalidToken, "signed with an unknown key")
+	assert.Equal(t, 1, fetches, "unknown keys are not refetched within the refresh interval")
+
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	_, err = verifier.Verify(ctx, signRS256(t, other, "k1", Claims{
+		Issuer: "https://issuer", Subject: "sub-1", Audience: Audience{"client"}, ExpiresAt: exp,
+	}))
+	assert.ErrorIs(t, err, ErrInvalidToken, "forged with another key")
+}
@@ -0,0 +1,133 @@
+// Package lambda runs the process as an AWS Lambda custom runtime, handing the
+// payload of each invocation to a handler.
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RuntimeAPIEnv names the variable Lambda sets to the runtime API's host:port
+const RuntimeAPIEnv = "AWS_LAMBDA_RUNTIME_API"
+
+const (
+	runtimeVersion  = "2018-06-01"
+	requestIDHeader = "Lambda-Runtime-Aws-Request-Id"
+	deadlineHeader  = "Lambda-Runtime-Deadline-Ms"
+	// maxInvocation is Lambda's longest timeout, the deadline of invocations
+	// without one
+	maxInvocation = 15 * time.Minute
+)
+
+// Handler handles the payload of one invocation. An error fails the
+// invocation, which the invoker may retry.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Runtime fetches invocations from the Lambda runtime API
+type Runtime struct {
+	baseURL string
+	// client has no timeout: fetching the next invocation blocks until there
+	// is one
+	client *http.Client
+}
+
+// New returns a runtime talking to the runtime API at api, a host:port
+func New(api string) *Runtime {
+	return &Runtime{
+		baseURL: "http://" + api + "/" + runtimeVersion + "/runtime/invocation/",
+		client:  &http.Client{},
+	}
+}
+
+// Serve handles invocations one at a time until ctx is cancelled or the
+// runtime API fails, after which Lambda replaces the process
+func (r *Runtime) Serve(ctx context.Context, handler Handler) error {
+	for ctx.Err() == nil {
+		id, deadline, payload, err := r.next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			return err
+		}
+
+		invocationCtx, cancel := context.WithDeadline(ctx, deadline)
+		handleErr := handler(invocationCtx, payload)
+		cancel()
+
+		if handleErr != nil {
+			err = r.post(ctx, id+"/error", map[string]string{
+				"errorType":    "HandlerError",
+				"errorMessage": handleErr.Error(),
+			})
+		} else {
+			err = r.post(ctx, id+"/response", map[string]string{"status": "ok"})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// next blocks until the next invocation and returns its request ID, deadline
+// and payload
+func (r *Runtime) next(ctx context.Context) (string, time.Time, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"next", nil)
+	if err != nil {
+		return "", time.Time{}, nil, fmt.Errorf("failed to create next invocation request: %w", err)
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", time.Time{}, nil, fmt.Errorf("failed to get next invocation: %w", err)
+	}
+	defer resp.Body.Close()
+
+	payload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", time.Time{}, nil, fmt.Errorf("failed to read invocation: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", time.Time{}, nil, fmt.Errorf("runtime api responded %d to next invocation: %s", resp.StatusCode, payload)
+	}
+
+	id := resp.Header.Get(requestIDHeader)
+	if id == "" {
+		return "", time.Time{}, nil, fmt.Errorf("invocation without a request id")
+	}
+	deadline := time.Now().Add(maxInvocation)
+	if ms, err := strconv.ParseInt(resp.Header.Get(deadlineHeader), 10, 64); err == nil {
+		deadline = time.UnixMilli(ms)
+	}
+	return id, deadline, payload, nil
+}
+
+// post reports the outcome of an invocation
+func (r *Runtime) post(ctx context.Context, path string, body any) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode invocation outcome: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+path, bytes.NewReader(encoded))
+	if err != nil {
+		return fmt.Errorf("failed to create invocation outcome request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to report invocation outcome: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("runtime api responded %d to invocation outcome", resp.StatusCode)
+	}
+	return nil
+}
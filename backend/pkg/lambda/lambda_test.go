@@ -0,0 +1,69 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntime_Serve(t *testing.T) {
+	payloads := []string{`{"date":"2025-03-05"}`, `{"date":"bad"}`}
+	ids := []string{"req-a", "req-b"}
+	var mu sync.Mutex
+	outcomes := map[string]map[string]string{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "/2018-06-01/runtime/invocation/"
+		require.True(t, strings.HasPrefix(r.URL.Path, prefix), r.URL.Path)
+		path := strings.TrimPrefix(r.URL.Path, prefix)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if path == "next" {
+			if len(payloads) == 0 {
+				cancel()
+				<-r.Context().Done()
+				return
+			}
+			w.Header().Set(requestIDHeader, ids[0])
+			_, _ = io.WriteString(w, payloads[0])
+			ids, payloads = ids[1:], payloads[1:]
+			return
+		}
+
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		outcomes[path] = body
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	var handled []string
+	err := New(strings.TrimPrefix(server.URL, "http://")).Serve(ctx, func(ctx context.Context, payload []byte) error {
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+		handled = append(handled, string(payload))
+		if strings.Contains(string(payload), "bad") {
+			return errors.New("invalid date")
+		}
+		return nil
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{`{"date":"2025-03-05"}`, `{"date":"bad"}`}, handled)
+	assert.Equal(t, map[string]map[string]string{
+		"req-a/response": {"status": "ok"},
+		"req-b/error":    {"errorType": "HandlerError", "errorMessage": "invalid date"},
+	}, outcomes)
+}
@@ -0,0 +1,110 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// LeaderStatus reports the leadership of an election as seen by one replica
+type LeaderStatus struct {
+	Election        string `json:"election"`
+	Leader          string `json:"leader,omitempty"`
+	LeaseExpiresUTC int64  `json:"leaseExpiresUTC,omitempty"`
+	Self            string `json:"self"`
+	IsLeader        bool   `json:"isLeader"`
+	LeaderSinceUTC  int64  `json:"leaderSinceUTC,omitempty"`
+}
+
+// Elector elects a single leader among replicas sharing a lock table. The
+// leader holds the election's lock for as long as it runs; when it stops or
+// dies its lease is released or expires and another replica takes over.
+type Elector struct {
+	locker *Locker
+	name   string
+	log    *zap.SugaredLogger
+
+	mu     sync.Mutex
+	leader bool
+	since  time.Time
+}
+
+// NewElector creates an elector campaigning for the named election
+func NewElector(locker *Locker, name string, log *zap.SugaredLogger) *Elector {
+	return &Elector{
+		locker: locker,
+		name:   name,
+		log:    log,
+	}
+}
+
+// Run campaigns until ctx is cancelled. Whenever this replica wins, lead is
+// called with a context that is cancelled when leadership is lost; lead
+// should return promptly once its context is done.
+func (e *Elector) Run(ctx context.Context, lead func(ctx context.Context)) {
+	retry := e.locker.lease / 3
+	lockName := "leader:" + e.name
+
+	for {
+		err := e.locker.Run(ctx, lockName, 0, func(ctx context.Context) error {
+			e.setLeader(true)
+			defer e.setLeader(false)
+
+			e.log.Infow("elected leader", "election", e.name, "owner", e.locker.owner)
+			lead(ctx)
+			return nil
+		})
+
+		switch {
+		case err == nil, errors.Is(err, ErrLocked):
+		case errors.Is(err, ErrLockLost):
+			e.log.Warnw("lost leadership", "election", e.name, "owner", e.locker.owner)
+		default:
+			e.log.Warnw("leader election failed", "election", e.name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+	}
+}
+
+// Status returns the current leader of the election
+func (e *Elector) Status(ctx context.Context) (*LeaderStatus, error) {
+	holder, err := e.locker.Holder(ctx, "leader:"+e.name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &LeaderStatus{
+		Election: e.name,
+		Self:     e.locker.owner,
+	}
+	if holder != nil {
+		status.Leader = holder.Owner
+		status.LeaseExpiresUTC = holder.ExpiresAt.Unix()
+	}
+
+	e.mu.Lock()
+	if e.leader {
+		status.IsLeader = true
+		status.LeaderSinceUTC = e.since.Unix()
+	}
+	e.mu.Unlock()
+
+	return status, nil
+}
+
+func (e *Elector) setLeader(leader bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.leader = leader
+	if leader {
+		e.since = e.locker.now()
+	}
+}
@@ -0,0 +1,91 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestElector_Failover(t *testing.T) {
+	table := newFakeTable(time.Now)
+	a := NewElector(newTestLocker(table, "a", 30*time.Millisecond, time.Now), "workers", zap.NewNop().Sugar())
+	b := NewElector(newTestLocker(table, "b", 30*time.Millisecond, time.Now), "workers", zap.NewNop().Sugar())
+
+	leading := make(chan string, 2)
+	lead := func(name string) func(ctx context.Context) {
+		return func(ctx context.Context) {
+			leading <- name
+			<-ctx.Done()
+		}
+	}
+
+	ctxA, stopA := context.WithCancel(context.Background())
+	ctxB, stopB := context.WithCancel(context.Background())
+	defer stopB()
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); a.Run(ctxA, lead("a")) }()
+	require.Equal(t, "a", <-leading)
+	go func() { defer wg.Done(); b.Run(ctxB, lead("b")) }()
+
+	// b keeps campaigning while a renews its lease
+	time.Sleep(100 * time.Millisecond)
+	status, err := b.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "a", status.Leader)
+	assert.Equal(t, "b", status.Self)
+	assert.False(t, status.IsLeader)
+
+	status, err = a.Status(context.Background())
+	require.NoError(t, err)
+	assert.True(t, status.IsLeader)
+	assert.NotZero(t, status.LeaderSinceUTC)
+
+	// Stopping the leader releases leadership to b
+	stopA()
+	select {
+	case name := <-leading:
+		assert.Equal(t, "b", name)
+	case <-time.After(time.Second):
+		t.Fatal("b was not elected after a stopped")
+	}
+
+	status, err = b.Status(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "b", status.Leader)
+	assert.True(t, status.IsLeader)
+
+	stopB()
+	wg.Wait()
+}
+
+func TestLocker_Holder(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{t: time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC)}
+	table := newFakeTable(clk.now)
+	l := newTestLocker(table, "a", time.Minute, clk.now)
+
+	holder, err := l.Holder(ctx, "scanner")
+	require.NoError(t, err)
+	assert.Nil(t, holder)
+
+	_, err = l.TryAcquire(ctx, "scanner")
+	require.NoError(t, err)
+
+	holder, err = l.Holder(ctx, "scanner")
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	assert.Equal(t, "a", holder.Owner)
+	assert.Equal(t, clk.now().Add(time.Minute), holder.ExpiresAt.UTC())
+
+	clk.advance(2 * time.Minute)
+	holder, err = l.Holder(ctx, "scanner")
+	require.NoError(t, err)
+	assert.Nil(t, holder, "an expired lease has no holder")
+}
@@ -0,0 +1,368 @@
+// Package lock provides named, lease-based mutual exclusion across replicas
+// backed by a DynamoDB table keyed on "name".
+//
+// A lease is taken with a conditional put that succeeds only if the lock is
+// free or its previous lease has expired, and is kept alive by renewing it
+// before expiry. Every acquisition carries a random token; a renewal or release
+// whose token no longer matches means another owner took the lock over after
+// the lease lapsed, which is reported as a stolen lock.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrLocked is returned when the lock is held by another owner
+	ErrLocked = errors.New("lock is held by another owner")
+	// ErrLockLost is returned when a lease was taken over by another owner
+	ErrLockLost = errors.New("lock lease was lost")
+)
+
+// expiredRetention is how long expired locks linger before DynamoDB TTL removes them
+const expiredRetention = time.Hour
+
+// DynamoDBAPI is the subset of the DynamoDB client used by Locker
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+}
+
+// Stats counts lock operations since the Locker was created
+type Stats struct {
+	Acquired      uint64
+	Contended     uint64
+	TakenOver     uint64
+	Renewed       uint64
+	Released      uint64
+	Stolen        uint64
+	RenewFailures uint64
+}
+
+type counters struct {
+	acquired, contended, takenOver, renewed, released, stolen, renewFailures atomic.Uint64
+}
+
+// Locker acquires leases on named locks on behalf of one owner
+type Locker struct {
+	client    DynamoDBAPI
+	tableName string
+	owner     string
+	lease     time.Duration
+	log       *zap.SugaredLogger
+	stats     counters
+	now       func() time.Time
+}
+
+// New creates a Locker whose leases last lease unless renewed
+func New(client DynamoDBAPI, tableName, owner string, lease time.Duration, log *zap.SugaredLogger) *Locker {
+	return &Locker{
+		client:    client,
+		tableName: tableName,
+		owner:     owner,
+		lease:     lease,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Owner returns an identifier unique to this process, for use as a lock owner
+func Owner() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	token, err := newToken()
+	if err != nil {
+		return host
+	}
+	return host + "-" + token[:8]
+}
+
+// Stats returns a snapshot of the lock operation counters
+func (l *Locker) Stats() Stats {
+	return Stats{
+		Acquired:      l.stats.acquired.Load(),
+		Contended:     l.stats.contended.Load(),
+		TakenOver:     l.stats.takenOver.Load(),
+		Renewed:       l.stats.renewed.Load(),
+		Released:      l.stats.released.Load(),
+		Stolen:        l.stats.stolen.Load(),
+		RenewFailures: l.stats.renewFailures.Load(),
+	}
+}
+
+// Holder describes the current owner of a lock
+type Holder struct {
+	Owner     string
+	ExpiresAt time.Time
+}
+
+// Holder returns the owner of an unexpired lease on the named lock, or nil if
+// the lock is free
+func (l *Locker) Holder(ctx context.Context, name string) (*Holder, error) {
+	result, err := l.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"name": &types.AttributeValueMemberS{Value: name},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get lock %s: %w", name, err)
+	}
+
+	owner, _ := result.Item["owner"].(*types.AttributeValueMemberS)
+	expiresAt, _ := result.Item["expiresAt"].(*types.AttributeValueMemberN)
+	if owner == nil || expiresAt == nil {
+		return nil, nil
+	}
+
+	ms, err := strconv.ParseInt(expiresAt.Value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("malformed expiry on lock %s: %w", name, err)
+	}
+	expires := time.UnixMilli(ms)
+	if expires.Before(l.now()) {
+		return nil, nil
+	}
+
+	return &Holder{Owner: owner.Value, ExpiresAt: expires}, nil
+}
+
+// Lease is a held lock
+type Lease struct {
+	locker *Locker
+	name   string
+	token  string
+}
+
+// Name returns the name of the leased lock
+func (ls *Lease) Name() string {
+	return ls.name
+}
+
+// TryAcquire takes the named lock if it is free or its lease has expired,
+// returning ErrLocked otherwise
+func (l *Locker) TryAcquire(ctx context.Context, name string) (*Lease, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+
+	now := l.now()
+	expires := now.Add(l.lease)
+
+	cond := expression.AttributeNotExists(expression.Name("name")).
+		Or(expression.Name("expiresAt").LessThan(expression.Value(now.UnixMilli())))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	result, err := l.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(l.tableName),
+		Item: map[string]types.AttributeValue{
+			"name":      &types.AttributeValueMemberS{Value: name},
+			"owner":     &types.AttributeValueMemberS{Value: l.owner},
+			"token":     &types.AttributeValueMemberS{Value: token},
+			"expiresAt": millis(expires),
+			"ttl":       &types.AttributeValueMemberN{Value: strconv.FormatInt(expires.Add(expiredRetention).Unix(), 10)},
+		},
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              types.ReturnValueAllOld,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if errors.As(err, &condErr) {
+			l.stats.contended.Add(1)
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	l.stats.acquired.Add(1)
+	if previous, ok := result.Attributes["owner"].(*types.AttributeValueMemberS); ok {
+		l.stats.takenOver.Add(1)
+		l.log.Warnw("took over expired lock", "lock", name, "previousOwner", previous.Value)
+	}
+
+	return &Lease{locker: l, name: name, token: token}, nil
+}
+
+// Renew extends the lease by the Locker's lease duration. ErrLockLost means
+// another owner has taken the lock over.
+func (ls *Lease) Renew(ctx context.Context) error {
+	l := ls.locker
+	update := expression.Set(expression.Name("expiresAt"), expression.Value(l.now().Add(l.lease).UnixMilli())).
+		Set(expression.Name("ttl"), expression.Value(l.now().Add(l.lease+expiredRetention).Unix()))
+	return ls.update(ctx, update, func() { l.stats.renewed.Add(1) })
+}
+
+// Hold keeps the lock held for d without renewal, e.g. so replicas scheduled
+// slightly later skip work that has already been done
+func (ls *Lease) Hold(ctx context.Context, d time.Duration) error {
+	l := ls.locker
+	update := expression.Set(expression.Name("expiresAt"), expression.Value(l.now().Add(d).UnixMilli())).
+		Set(expression.Name("ttl"), expression.Value(l.now().Add(d+expiredRetention).Unix()))
+	return ls.update(ctx, update, func() {})
+}
+
+func (ls *Lease) update(ctx context.Context, update expression.UpdateBuilder, onSuccess func()) error {
+	l := ls.locker
+	expr, err := expression.NewBuilder().WithUpdate(update).WithCondition(ls.owned()).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = l.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(l.tableName),
+		Key:                       ls.key(),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return ls.mapError(err)
+	}
+
+	onSuccess()
+	return nil
+}
+
+// Release frees the lock if this lease still holds it
+func (ls *Lease) Release(ctx context.Context) error {
+	l := ls.locker
+	expr, err := expression.NewBuilder().WithCondition(ls.owned()).Build()
+	if err != nil {
+		return fmt.Errorf("failed to build expression: %w", err)
+	}
+
+	_, err = l.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:                 aws.String(l.tableName),
+		Key:                       ls.key(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return ls.mapError(err)
+	}
+
+	l.stats.released.Add(1)
+	return nil
+}
+
+// Run acquires the named lock and runs fn while renewing the lease in the
+// background. If the lease is lost, fn's context is cancelled and ErrLockLost
+// returned. After fn succeeds the lock is held for hold, or released when hold
+// is zero; after fn fails it is released so the work can be retried.
+func (l *Locker) Run(ctx context.Context, name string, hold time.Duration, fn func(ctx context.Context) error) error {
+	lease, err := l.TryAcquire(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		ticker := time.NewTicker(l.lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+				if err := lease.Renew(runCtx); err != nil {
+					if errors.Is(err, ErrLockLost) {
+						cancel(ErrLockLost)
+						return
+					}
+					if runCtx.Err() == nil {
+						l.stats.renewFailures.Add(1)
+						l.log.Warnw("failed to renew lock", "lock", name, "error", err)
+					}
+				}
+			}
+		}
+	}()
+
+	fnErr := fn(runCtx)
+	lost := errors.Is(context.Cause(runCtx), ErrLockLost)
+	cancel(nil)
+	<-renewed
+
+	if lost {
+		return ErrLockLost
+	}
+
+	// Finish bookkeeping even if ctx was cancelled while fn ran
+	cleanupCtx := context.WithoutCancel(ctx)
+	if fnErr == nil && hold > 0 {
+		if err := lease.Hold(cleanupCtx, hold); err != nil {
+			l.log.Warnw("failed to hold lock after run", "lock", name, "error", err)
+		}
+		return nil
+	}
+	if err := lease.Release(cleanupCtx); err != nil && !errors.Is(err, ErrLockLost) {
+		l.log.Warnw("failed to release lock", "lock", name, "error", err)
+	}
+
+	return fnErr
+}
+
+// owned is the condition that the lease's token still holds the lock
+func (ls *Lease) owned() expression.ConditionBuilder {
+	return expression.Name("token").Equal(expression.Value(ls.token))
+}
+
+func (ls *Lease) key() map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"name": &types.AttributeValueMemberS{Value: ls.name},
+	}
+}
+
+// mapError reports a failed token condition as a stolen lock
+func (ls *Lease) mapError(err error) error {
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		ls.locker.stats.stolen.Add(1)
+		ls.locker.log.Warnw("lock was taken over by another owner", "lock", ls.name, "owner", ls.locker.owner)
+		return ErrLockLost
+	}
+	return fmt.Errorf("failed to update lock %s: %w", ls.name, err)
+}
+
+func millis(t time.Time) types.AttributeValue {
+	return &types.AttributeValueMemberN{Value: strconv.FormatInt(t.UnixMilli(), 10)}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,270 @@
+package lock
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeTable emulates the conditional writes Locker issues against DynamoDB
+type fakeTable struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	items map[string]map[string]types.AttributeValue
+}
+
+func newFakeTable(now func() time.Time) *fakeTable {
+	return &fakeTable{now: now, items: make(map[string]map[string]types.AttributeValue)}
+}
+
+func str(av types.AttributeValue) string {
+	if s, ok := av.(*types.AttributeValueMemberS); ok {
+		return s.Value
+	}
+	return ""
+}
+
+func num(av types.AttributeValue) int64 {
+	if n, ok := av.(*types.AttributeValueMemberN); ok {
+		v, _ := strconv.ParseInt(n.Value, 10, 64)
+		return v
+	}
+	return 0
+}
+
+// ownedBy reports whether the condition values carry the stored token
+func ownedBy(item map[string]types.AttributeValue, values map[string]types.AttributeValue) bool {
+	for _, v := range values {
+		if item != nil && str(v) == str(item["token"]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeTable) PutItem(ctx context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := str(in.Item["name"])
+	old, exists := f.items[name]
+	if exists && num(old["expiresAt"]) >= f.now().UnixMilli() {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	f.items[name] = in.Item
+	return &dynamodb.PutItemOutput{Attributes: old}, nil
+}
+
+func (f *fakeTable) UpdateItem(ctx context.Context, in *dynamodb.UpdateItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item := f.items[str(in.Key["name"])]
+	if !ownedBy(item, in.ExpressionAttributeValues) {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	// Updates set expiresAt (ms) and ttl (s); the larger number is the new expiry
+	var expiresAt int64
+	for _, v := range in.ExpressionAttributeValues {
+		expiresAt = max(expiresAt, num(v))
+	}
+	item["expiresAt"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt, 10)}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (f *fakeTable) GetItem(ctx context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &dynamodb.GetItemOutput{Item: maps.Clone(f.items[str(in.Key["name"])])}, nil
+}
+
+func (f *fakeTable) DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	name := str(in.Key["name"])
+	if !ownedBy(f.items[name], in.ExpressionAttributeValues) {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	delete(f.items, name)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// steal replaces the holder of a lock as if another owner took it over
+func (f *fakeTable) steal(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[name]["token"] = &types.AttributeValueMemberS{Value: "thief"}
+}
+
+func (f *fakeTable) held(name string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.items[name]
+	return ok
+}
+
+type clock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *clock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func newTestLocker(table *fakeTable, owner string, lease time.Duration, now func() time.Time) *Locker {
+	l := New(table, "locks", owner, lease, zap.NewNop().Sugar())
+	l.now = now
+	return l
+}
+
+func TestLocker_Contention(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{t: time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC)}
+	table := newFakeTable(clk.now)
+	a := newTestLocker(table, "a", time.Minute, clk.now)
+	b := newTestLocker(table, "b", time.Minute, clk.now)
+
+	lease, err := a.TryAcquire(ctx, "scanner")
+	require.NoError(t, err)
+
+	_, err = b.TryAcquire(ctx, "scanner")
+	assert.ErrorIs(t, err, ErrLocked)
+
+	require.NoError(t, lease.Renew(ctx))
+	require.NoError(t, lease.Release(ctx))
+
+	_, err = b.TryAcquire(ctx, "scanner")
+	require.NoError(t, err)
+
+	assert.Equal(t, Stats{Acquired: 1, Renewed: 1, Released: 1}, a.Stats())
+	assert.Equal(t, Stats{Acquired: 1, Contended: 1}, b.Stats())
+}
+
+func TestLocker_ExpiredLeaseIsTakenOver(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{t: time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC)}
+	table := newFakeTable(clk.now)
+	a := newTestLocker(table, "a", time.Minute, clk.now)
+	b := newTestLocker(table, "b", time.Minute, clk.now)
+
+	stale, err := a.TryAcquire(ctx, "scanner")
+	require.NoError(t, err)
+
+	clk.advance(2 * time.Minute)
+	_, err = b.TryAcquire(ctx, "scanner")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), b.Stats().TakenOver)
+
+	assert.ErrorIs(t, stale.Renew(ctx), ErrLockLost)
+	assert.ErrorIs(t, stale.Release(ctx), ErrLockLost)
+	assert.Equal(t, uint64(2), a.Stats().Stolen)
+	assert.True(t, table.held("scanner"), "a stale release must not free the new owner's lock")
+}
+
+func TestLocker_Run(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("releases after run", func(t *testing.T) {
+		table := newFakeTable(time.Now)
+		l := newTestLocker(table, "a", time.Minute, time.Now)
+
+		ran := false
+		err := l.Run(ctx, "heatmap", 0, func(ctx context.Context) error {
+			ran = true
+			assert.True(t, table.held("heatmap"))
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.True(t, ran)
+		assert.False(t, table.held("heatmap"))
+	})
+
+	t.Run("holds after successful run", func(t *testing.T) {
+		table := newFakeTable(time.Now)
+		l := newTestLocker(table, "a", time.Minute, time.Now)
+
+		require.NoError(t, l.Run(ctx, "heatmap", time.Hour, func(ctx context.Context) error { return nil }))
+		assert.True(t, table.held("heatmap"))
+	})
+
+	t.Run("skips when locked", func(t *testing.T) {
+		table := newFakeTable(time.Now)
+		other := newTestLocker(table, "b", time.Minute, time.Now)
+		_, err := other.TryAcquire(ctx, "heatmap")
+		require.NoError(t, err)
+
+		l := newTestLocker(table, "a", time.Minute, time.Now)
+		err = l.Run(ctx, "heatmap", 0, func(ctx context.Context) error {
+			t.Fatal("must not run while locked")
+			return nil
+		})
+		assert.ErrorIs(t, err, ErrLocked)
+	})
+
+	t.Run("cancels work when the lease is stolen", func(t *testing.T) {
+		table := newFakeTable(time.Now)
+		l := newTestLocker(table, "a", 30*time.Millisecond, time.Now)
+
+		err := l.Run(ctx, "breadth", 0, func(ctx context.Context) error {
+			table.steal("breadth")
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Second):
+				return nil
+			}
+		})
+
+		assert.ErrorIs(t, err, ErrLockLost)
+		assert.Equal(t, uint64(1), l.Stats().Stolen)
+	})
+}
+
+func TestLocker_Collector(t *testing.T) {
+	ctx := context.Background()
+	clk := &clock{t: time.Date(2025, 3, 7, 21, 30, 0, 0, time.UTC)}
+	table := newFakeTable(clk.now)
+	a := newTestLocker(table, "a", time.Minute, clk.now)
+
+	lease, err := a.TryAcquire(ctx, "scanner")
+	require.NoError(t, err)
+	_, err = a.TryAcquire(ctx, "scanner")
+	assert.ErrorIs(t, err, ErrLocked)
+	require.NoError(t, lease.Renew(ctx))
+
+	expected := `
+# HELP profitify_lock_operations_total Lock operations, by operation: acquired, contended, taken_over, renewed, released, stolen or renew_failure.
+# TYPE profitify_lock_operations_total counter
+profitify_lock_operations_total{operation="acquired"} 1
+profitify_lock_operations_total{operation="contended"} 1
+profitify_lock_operations_total{operation="released"} 0
+profitify_lock_operations_total{operation="renew_failure"} 0
+profitify_lock_operations_total{operation="renewed"} 1
+profitify_lock_operations_total{operation="stolen"} 0
+profitify_lock_operations_total{operation="taken_over"} 0
+`
+	assert.NoError(t, testutil.CollectAndCompare(a.Collector("profitify"), strings.NewReader(expected)))
+}
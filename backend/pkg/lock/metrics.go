@@ -0,0 +1,46 @@
+package lock
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// statsCollector exports the Locker's operation counters to Prometheus
+type statsCollector struct {
+	locker *Locker
+	desc   *prometheus.Desc
+}
+
+// Collector returns a Prometheus collector of the lock operation counters,
+// named <namespace>_lock_operations_total and labelled by operation
+func (l *Locker) Collector(namespace string) prometheus.Collector {
+	return &statsCollector{
+		locker: l,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "lock", "operations_total"),
+			"Lock operations, by operation: acquired, contended, taken_over, renewed, released, stolen or renew_failure.",
+			[]string{"operation"}, nil,
+		),
+	}
+}
+
+func (c *statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *statsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.locker.Stats()
+	for _, counter := range []struct {
+		operation string
+		value     uint64
+	}{
+		{"acquired", stats.Acquired},
+		{"contended", stats.Contended},
+		{"taken_over", stats.TakenOver},
+		{"renewed", stats.Renewed},
+		{"released", stats.Released},
+		{"stolen", stats.Stolen},
+		{"renew_failure", stats.RenewFailures},
+	} {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(counter.value), counter.operation)
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"sync"
@@ -113,3 +114,19 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx carrying log, e.g. a logger annotated with
+// the request ID
+func NewContext(ctx context.Context, log *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, contextKey{}, log)
+}
+
+// FromContext returns the logger carried by ctx, or fallback if there is none
+func FromContext(ctx context.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if log, ok := ctx.Value(contextKey{}).(*zap.SugaredLogger); ok {
+		return log
+	}
+	return fallback
+}
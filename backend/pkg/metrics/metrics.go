@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/aws/smithy-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the names of the backend's collectors
+const Namespace = "profitify"
+
+// dynamoDBBuckets resolves single-digit millisecond DynamoDB latencies
+var dynamoDBBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5}
+
+// Metrics holds the Prometheus collectors of the backend and the registry
+// they are served from
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests        *prometheus.CounterVec
+	httpDuration        *prometheus.HistogramVec
+	httpInFlight        *prometheus.GaugeVec
+	dynamoCalls         *prometheus.CounterVec
+	dynamoDuration      *prometheus.HistogramVec
+	awsConnections      *prometheus.CounterVec
+	signatureRejections *prometheus.CounterVec
+	ingestAnomalies     *prometheus.CounterVec
+}
+
+// New creates the backend's collectors on a fresh registry, together with the
+// standard Go runtime and process collectors
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests handled, by route and status.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency, by route and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		httpInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "HTTP requests currently being handled, by route.",
+		}, []string{"method", "route"}),
+		dynamoCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "dynamodb_calls_total",
+			Help:      "DynamoDB API calls, by operation, table and result code.",
+		}, []string{"operation", "table", "code"}),
+		dynamoDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "dynamodb_call_duration_seconds",
+			Help:      "DynamoDB API call latency including retries, by operation and table.",
+			Buckets:   dynamoDBBuckets,
+		}, []string{"operation", "table"}),
+		awsConnections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "aws_http_connections_total",
+			Help:      "Connections AWS API requests were sent on, by endpoint host and whether the connection was reused from the pool.",
+		}, []string{"host", "reused"}),
+		signatureRejections: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "signed_requests_rejected_total",
+			Help:      "Signed requests rejected, by reason: invalid, stale or replay.",
+		}, []string{"reason"}),
+		ingestAnomalies: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Name:      "ingest_anomalies_total",
+			Help:      "Ingested daily summaries flagged as improbable moves, by ingestion source and action: tagged or held.",
+		}, []string{"source", "action"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.httpInFlight,
+		m.dynamoCalls,
+		m.dynamoDuration,
+		m.awsConnections,
+		m.signatureRejections,
+		m.ingestAnomalies,
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Register adds further collectors to the registry
+func (m *Metrics) Register(cs ...prometheus.Collector) error {
+	for _, c := range cs {
+		if err := m.registry.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RequestStarted marks a request to route as in flight. The returned function
+// records its outcome once the response status is known.
+func (m *Metrics) RequestStarted(method, route string) func(status int) {
+	inFlight := m.httpInFlight.WithLabelValues(method, route)
+	inFlight.Inc()
+	start := time.Now()
+
+	return func(status int) {
+		inFlight.Dec()
+		code := strconv.Itoa(status)
+		m.httpRequests.WithLabelValues(method, route, code).Inc()
+		m.httpDuration.WithLabelValues(method, route, code).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveDynamoDB records one DynamoDB API call. Failed calls are counted
+// under their AWS error code, e.g. ConditionalCheckFailedException.
+func (m *Metrics) ObserveDynamoDB(operation, table string, d time.Duration, err error) {
+	code := "OK"
+	if err != nil {
+		code = "error"
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			code = apiErr.ErrorCode()
+		}
+	}
+
+	m.dynamoCalls.WithLabelValues(operation, table, code).Inc()
+	m.dynamoDuration.WithLabelValues(operation, table).Observe(d.Seconds())
+}
+
+// ObserveConnection counts a connection an AWS API request was sent on. A low
+// share of reused connections under load means the idle pool is too small.
+func (m *Metrics) ObserveConnection(host string, reused bool) {
+	m.awsConnections.WithLabelValues(host, strconv.FormatBool(reused)).Inc()
+}
+
+// SignatureRejected counts a signed request rejected for reason
+func (m *Metrics) SignatureRejected(reason string) {
+	m.signatureRejections.WithLabelValues(reason).Inc()
+}
+
+// IngestAnomaly counts a daily summary ingestion from source flagged as an
+// improbable move and tagged or held for review
+func (m *Metrics) IngestAnomaly(source, action string) {
+	m.ingestAnomalies.WithLabelValues(source, action).Inc()
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig is the mail server email notifications are sent through
+type SMTPConfig struct {
+	Host string
+	Port int
+	// Username and Password authenticate with PLAIN auth when set, which
+	// requires STARTTLS
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// Email returns a notifier that mails notifications as plain text to their
+// To recipients, rendered by the email template of their kind or as their
+// subject and body. Notifications without recipients are skipped.
+func Email(cfg SMTPConfig, templates *Templates) Notifier {
+	return &emailNotifier{cfg: cfg, templates: templates}
+}
+
+type emailNotifier struct {
+	cfg       SMTPConfig
+	templates *Templates
+}
+
+func (e *emailNotifier) Notify(ctx context.Context, n Notification) error {
+	if len(n.To) == 0 {
+		return nil
+	}
+	msg, err := e.templates.Render(n, ChannelEmail)
+	if err != nil {
+		return err
+	}
+	n.Subject, n.Body = msg.Subject, msg.Body
+
+	if e.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, e.cfg.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(e.cfg.Host, strconv.Itoa(e.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, e.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to greet smtp server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: e.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+	if e.cfg.Username != "" {
+		auth := smtp.PlainAuth("", e.cfg.Username, e.cfg.Password, e.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate with smtp server: %w", err)
+		}
+	}
+
+	if err := client.Mail(e.from()); err != nil {
+		return fmt.Errorf("smtp server rejected sender: %w", err)
+	}
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp server rejected recipient %s: %w", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start message: %w", err)
+	}
+	if _, err := w.Write(e.message(n)); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected message: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// from returns the bare sender address of a From like "Name <addr>"
+func (e *emailNotifier) from() string {
+	from := e.cfg.From
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		from = strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+func (e *emailNotifier) message(n Notification) []byte {
+	sent := time.Now()
+	if n.SentUTC != 0 {
+		sent = time.Unix(n.SentUTC, 0)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", e.cfg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", n.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", sent.UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(n.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buf.Bytes()
+}
@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTP accepts one message and sends its envelope and data on received
+func fakeSMTP(t *testing.T) (host string, port int, received chan []string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received = make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+		reply("220 fake ESMTP")
+
+		var lines []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				reply("250 fake")
+			case "MAIL", "RCPT":
+				lines = append(lines, line)
+				reply("250 ok")
+			case "DATA":
+				reply("354 go ahead")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					data = strings.TrimRight(data, "\r\n")
+					if data == "." {
+						break
+					}
+					lines = append(lines, data)
+				}
+				reply("250 queued")
+			case "QUIT":
+				reply("221 bye")
+				received <- lines
+				return
+			default:
+				reply("502 unsupported")
+			}
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	return "127.0.0.1", addr.Port, received
+}
+
+func TestEmail(t *testing.T) {
+	host, port, received := fakeSMTP(t)
+	email := Email(SMTPConfig{Host: host, Port: port, From: "Profitify <digest@example.com>", Timeout: 5 * time.Second}, nil)
+
+	err := email.Notify(context.Background(), Notification{
+		Kind:    "digest",
+		Subject: "Daily digest",
+		Body:    "line one\nline two",
+		To:      []string{"user@example.com"},
+	})
+	require.NoError(t, err)
+
+	lines := <-received
+	assert.Contains(t, lines, "MAIL FROM:<digest@example.com>")
+	assert.Contains(t, lines, "RCPT TO:<user@example.com>")
+	assert.Contains(t, lines, "To: user@example.com")
+	assert.Contains(t, lines, "Subject: Daily digest")
+	assert.Contains(t, lines, "line two")
+}
+
+func TestEmail_SkipsUnaddressed(t *testing.T) {
+	// Nothing listens on port 1; an unaddressed notification must not connect
+	email := Email(SMTPConfig{Host: "127.0.0.1", Port: 1, Timeout: time.Second}, nil)
+	assert.NoError(t, email.Notify(context.Background(), Notification{Kind: "alert"}))
+
+	err := email.Notify(context.Background(), Notification{To: []string{"user@example.com"}})
+	assert.ErrorContains(t, err, "failed to connect")
+}
@@ -0,0 +1,171 @@
+// Package notify delivers notifications raised by background workers, such as
+// triggered alerts and digests, to the log, webhooks, Slack and email. Mobile
+// push is delivered by the service layer, which knows the registered devices.
+// Messages are rendered from the templates of their kind and channel.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notification is a message for a user or operator. Data carries the
+// structured payload it was raised for and is sent to webhooks as is.
+type Notification struct {
+	Kind    string `json:"kind"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+	Data    any    `json:"data,omitempty"`
+	SentUTC int64  `json:"sentUTC"`
+	// To addresses the notification to email recipients
+	To []string `json:"to,omitempty"`
+	// KeyID is the API key the notification was raised for; push notifications
+	// reach the devices it registered
+	KeyID string `json:"keyId,omitempty"`
+}
+
+// Kinds of the notifications raised by background workers
+const (
+	// KindAlert marks notifications raised by triggered alerts
+	KindAlert = "alert"
+	// KindDigest marks watchlist digests
+	KindDigest = "digest"
+	// KindDigestConfirmation marks the emails confirming digest subscriptions
+	KindDigestConfirmation = "digest_confirmation"
+	// KindAnomaly marks ingested daily summaries flagged as improbable moves
+	KindAnomaly = "anomaly"
+)
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Log returns a notifier that writes notifications to the log
+func Log(log *zap.SugaredLogger) Notifier {
+	return logNotifier{log: log}
+}
+
+type logNotifier struct {
+	log *zap.SugaredLogger
+}
+
+func (l logNotifier) Notify(ctx context.Context, n Notification) error {
+	l.log.Infow("notification", "kind", n.Kind, "subject", n.Subject, "body", n.Body)
+	return nil
+}
+
+// Webhook returns a notifier that POSTs notifications as JSON to url: the
+// notification itself, or the payload rendered by the webhook template of
+// its kind. Any non-2xx response is an error.
+func Webhook(url string, timeout time.Duration, templates *Templates) Notifier {
+	return &webhookNotifier{
+		url:       url,
+		client:    &http.Client{Timeout: timeout},
+		templates: templates,
+	}
+}
+
+type webhookNotifier struct {
+	url       string
+	client    *http.Client
+	templates *Templates
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, n Notification) error {
+	if !w.templates.Has(n.Kind, ChannelWebhook) {
+		body, err := json.Marshal(n)
+		if err != nil {
+			return fmt.Errorf("failed to marshal notification: %w", err)
+		}
+		return w.post(ctx, body)
+	}
+
+	msg, err := w.templates.Render(n, ChannelWebhook)
+	if err != nil {
+		return err
+	}
+	if !json.Valid([]byte(msg.Body)) {
+		return fmt.Errorf("webhook template of %s notifications rendered invalid JSON", n.Kind)
+	}
+	return w.post(ctx, []byte(msg.Body))
+}
+
+// Slack returns a notifier that posts notifications to a Slack incoming
+// webhook at url, as the text rendered by the slack template of their kind,
+// or their subject and body. Any non-2xx response is an error.
+func Slack(url string, timeout time.Duration, templates *Templates) Notifier {
+	return &slackNotifier{webhook: webhookNotifier{
+		url:       url,
+		client:    &http.Client{Timeout: timeout},
+		templates: templates,
+	}}
+}
+
+type slackNotifier struct {
+	webhook webhookNotifier
+}
+
+func (s *slackNotifier) Notify(ctx context.Context, n Notification) error {
+	msg, err := s.webhook.templates.Render(n, ChannelSlack)
+	if err != nil {
+		return err
+	}
+	text := msg.Body
+	if msg.Subject != "" {
+		text = strings.TrimSpace("*" + msg.Subject + "*\n" + msg.Body)
+	}
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return fmt.Errorf("failed to marshal slack message: %w", err)
+	}
+	return s.webhook.post(ctx, body)
+}
+
+// post POSTs a JSON body to the webhook
+func (w *webhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Multi returns a notifier that delivers to every notifier, even when some of
+// them fail, and joins their errors
+func Multi(notifiers ...Notifier) Notifier {
+	return multiNotifier(notifiers)
+}
+
+type multiNotifier []Notifier
+
+func (m multiNotifier) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
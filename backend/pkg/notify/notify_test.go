@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(ctx context.Context, n Notification) error {
+	return errors.New("unreachable")
+}
+
+func TestWebhook(t *testing.T) {
+	var received Notification
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	webhook := Webhook(srv.URL, time.Second, nil)
+
+	err := webhook.Notify(context.Background(), Notification{Kind: "alert", Subject: "AAPL above 200"})
+	require.NoError(t, err)
+	assert.Equal(t, "alert", received.Kind)
+	assert.Equal(t, "AAPL above 200", received.Subject)
+
+	status = http.StatusBadGateway
+	assert.ErrorContains(t, webhook.Notify(context.Background(), Notification{Kind: "alert"}), "502")
+}
+
+func TestMulti(t *testing.T) {
+	var delivered int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered++
+	}))
+	defer srv.Close()
+
+	n := Multi(failingNotifier{}, Log(zap.NewNop().Sugar()), Webhook(srv.URL, time.Second, nil))
+	err := n.Notify(context.Background(), Notification{Kind: "alert"})
+	assert.ErrorContains(t, err, "unreachable")
+	assert.Equal(t, 1, delivered, "later notifiers run after a failure")
+}
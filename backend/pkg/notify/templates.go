@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Channels notifications are rendered for. Every kind has a default
+// template; the others override it for their channel.
+const (
+	ChannelDefault = "default"
+	ChannelEmail   = "email"
+	ChannelPush    = "push"
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// defaultTemplates are the templates shipped in templates/
+var defaultTemplates = func() *Templates {
+	sub, err := fs.Sub(templateFS, "templates")
+	if err != nil {
+		panic(err)
+	}
+	t := &Templates{set: map[string]*template.Template{}}
+	if err := t.parse(sub); err != nil {
+		panic(err)
+	}
+	return t
+}()
+
+// Message is a notification rendered for a channel
+type Message struct {
+	// Subject is the subject of an email and the title of a push
+	// notification
+	Subject string
+	Body    string
+}
+
+// Templates render notifications per kind and channel from text templates
+// named <kind>/<channel>.tmpl, executed with the notification's Data. A
+// template's output is the message body; it may define "subject". Raising
+// services compose notifications with the default template of their kind,
+// and channels render them with their own when the kind has one.
+//
+// A nil *Templates holds the templates shipped in templates/.
+type Templates struct {
+	set map[string]*template.Template
+}
+
+// LoadTemplates returns the shipped templates overridden, and extended, by
+// the templates in dir, laid out like templates/. An empty dir loads the
+// shipped templates only.
+func LoadTemplates(dir string) (*Templates, error) {
+	t := &Templates{set: make(map[string]*template.Template, len(defaultTemplates.set))}
+	for name, tmpl := range defaultTemplates.set {
+		t.set[name] = tmpl
+	}
+	if dir == "" {
+		return t, nil
+	}
+	if err := t.parse(os.DirFS(dir)); err != nil {
+		return nil, fmt.Errorf("failed to load notification templates from %s: %w", dir, err)
+	}
+	return t, nil
+}
+
+// templateFuncs are the functions templates can call besides the builtins
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	// json encodes a value, quoting strings, for webhook payloads
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	// date formats a Unix timestamp as its UTC date
+	"date": func(unix int64) string {
+		return time.Unix(unix, 0).UTC().Format(time.DateOnly)
+	},
+}
+
+// parse adds the templates of fsys, replacing those of the same kind and
+// channel
+func (t *Templates) parse(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		kind, file := path.Split(name)
+		if d.IsDir() || path.Ext(file) != ".tmpl" || kind == "" || strings.Count(name, "/") != 1 {
+			return nil
+		}
+		text, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		key := strings.TrimSuffix(name, ".tmpl")
+		tmpl, err := template.New(key).Funcs(templateFuncs).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return err
+		}
+		t.set[key] = tmpl
+		return nil
+	})
+}
+
+func (t *Templates) lookup(kind, channel string) *template.Template {
+	if t == nil {
+		t = defaultTemplates
+	}
+	return t.set[kind+"/"+channel]
+}
+
+// Has reports whether kind has a template for channel
+func (t *Templates) Has(kind, channel string) bool {
+	return t.lookup(kind, channel) != nil
+}
+
+// Compose returns a notification of kind carrying data, with the subject and
+// body of the kind's default template
+func (t *Templates) Compose(kind string, data any) (Notification, error) {
+	tmpl := t.lookup(kind, ChannelDefault)
+	if tmpl == nil {
+		return Notification{}, fmt.Errorf("no default template for %s notifications", kind)
+	}
+	msg, err := execute(tmpl, data)
+	if err != nil {
+		return Notification{}, err
+	}
+	return Notification{Kind: kind, Subject: msg.Subject, Body: msg.Body, Data: data}, nil
+}
+
+// Render renders n for channel with the template of its kind for the
+// channel, or returns its subject and body when the kind has none
+func (t *Templates) Render(n Notification, channel string) (Message, error) {
+	tmpl := t.lookup(n.Kind, channel)
+	if tmpl == nil {
+		return Message{Subject: n.Subject, Body: n.Body}, nil
+	}
+	return execute(tmpl, n.Data)
+}
+
+// execute renders a template's body and, when it defines one, its subject,
+// trimming the space around the subject
+func execute(tmpl *template.Template, data any) (Message, error) {
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return Message{}, fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+	}
+	msg := Message{Body: body.String()}
+
+	if subject := tmpl.Lookup("subject"); subject != nil {
+		var buf bytes.Buffer
+		if err := subject.Execute(&buf, data); err != nil {
+			return Message{}, fmt.Errorf("failed to render the subject of %s: %w", tmpl.Name(), err)
+		}
+		msg.Subject = strings.TrimSpace(buf.String())
+	}
+	return msg, nil
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTemplates lays files out in a templates directory
+func writeTemplates(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, text := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644))
+	}
+	return dir
+}
+
+func TestTemplates_Compose(t *testing.T) {
+	var shipped *Templates
+	n, err := shipped.Compose(KindDigestConfirmation, map[string]string{"Frequency": "weekly", "ConfirmURL": "https://x/confirm"})
+	require.NoError(t, err)
+	assert.Equal(t, KindDigestConfirmation, n.Kind)
+	assert.Equal(t, "Confirm your weekly Profitify digest", n.Subject)
+	assert.Contains(t, n.Body, "confirm the subscription at https://x/confirm\n")
+	assert.Equal(t, map[string]string{"Frequency": "weekly", "ConfirmURL": "https://x/confirm"}, n.Data)
+
+	_, err = shipped.Compose("unknown", nil)
+	assert.EqualError(t, err, "no default template for unknown notifications")
+
+	_, err = shipped.Compose(KindDigestConfirmation, map[string]string{"Frequency": "weekly"})
+	assert.ErrorContains(t, err, "ConfirmURL", "missing data fails rather than rendering <no value>")
+}
+
+func TestLoadTemplates(t *testing.T) {
+	dir := writeTemplates(t, map[string]string{
+		"digest_confirmation/default.tmpl": `{{ define "subject" }}Bitte bestätigen{{ end }}{{ .ConfirmURL }}`,
+		"report/default.tmpl":              `{{ define "subject" }}Report {{ .Name }}{{ end }}Ready`,
+		"report/push.tmpl":                 `{{ define "subject" }}{{ .Name }}{{ end }}Tap to open`,
+		"README.md":                        "not a template",
+	})
+	templates, err := LoadTemplates(dir)
+	require.NoError(t, err)
+
+	n, err := templates.Compose(KindDigestConfirmation, map[string]string{"Frequency": "daily", "ConfirmURL": "https://x"})
+	require.NoError(t, err)
+	assert.Equal(t, Message{Subject: "Bitte bestätigen", Body: "https://x"}, Message{n.Subject, n.Body}, "the deployment's template wins")
+
+	n, err = templates.Compose("report", map[string]string{"Name": "Q3"})
+	require.NoError(t, err)
+	assert.Equal(t, "Report Q3", n.Subject, "new kinds need no code")
+	push, err := templates.Render(n, ChannelPush)
+	require.NoError(t, err)
+	assert.Equal(t, Message{Subject: "Q3", Body: "Tap to open"}, push)
+	email, err := templates.Render(n, ChannelEmail)
+	require.NoError(t, err)
+	assert.Equal(t, Message{Subject: "Report Q3", Body: "Ready"}, email, "channels without a template send the default")
+
+	assert.True(t, templates.Has(KindAlert, ChannelDefault), "the shipped templates are kept")
+	var shipped *Templates
+	assert.False(t, shipped.Has("report", ChannelDefault), "the shipped templates are not changed")
+
+	_, err = LoadTemplates(writeTemplates(t, map[string]string{"alert/default.tmpl": "{{ .alert"}))
+	assert.ErrorContains(t, err, "failed to load notification templates")
+}
+
+func TestWebhook_Template(t *testing.T) {
+	var received []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	templates, err := LoadTemplates(writeTemplates(t, map[string]string{
+		"alert/webhook.tmpl":  `{"text": {{ json .alert.Symbol }}, "close": {{ .quote.Close }}}`,
+		"digest/webhook.tmpl": `{"broken": {{ .Date }}`,
+	}))
+	require.NoError(t, err)
+	webhook := Webhook(srv.URL, time.Second, templates)
+
+	data := map[string]any{"alert": map[string]any{"Symbol": `A"B`}, "quote": map[string]any{"Close": 12.5}}
+	require.NoError(t, webhook.Notify(context.Background(), Notification{Kind: KindAlert, Data: data}))
+	assert.JSONEq(t, `{"text": "A\"B", "close": 12.5}`, string(received))
+
+	err = webhook.Notify(context.Background(), Notification{Kind: KindDigest, Data: map[string]string{"Date": "2025-03-03"}})
+	assert.EqualError(t, err, "webhook template of digest notifications rendered invalid JSON")
+}
+
+func TestSlack(t *testing.T) {
+	var received map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer srv.Close()
+	slack := Slack(srv.URL, time.Second, nil)
+
+	data := map[string]any{
+		"alert": map[string]any{"Symbol": "AAPL", "Condition": "price_above", "Threshold": 200.0, "Note": "trim"},
+		"quote": map[string]any{"Close": 201.5, "ChangePercent": 1.25},
+	}
+	require.NoError(t, slack.Notify(context.Background(), Notification{Kind: KindAlert, Data: data}))
+	assert.Equal(t, ":bell: *AAPL* alert: at or above 200, closing at *201.50* (+1.25%)\n> trim", received["text"])
+
+	require.NoError(t, slack.Notify(context.Background(), Notification{Kind: KindDigest, Subject: "Digest", Body: "body\n"}))
+	assert.Equal(t, "*Digest*\nbody", received["text"], "kinds without a slack template send their subject and body")
+}
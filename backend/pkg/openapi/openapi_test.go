@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type inner struct {
+	Label string `json:"label"`
+}
+
+type Embedded struct {
+	Shared string `json:"shared"`
+}
+
+type node struct {
+	Embedded
+	ID       string            `json:"id"`
+	Note     string            `json:"note,omitempty"`
+	Count    int32             `json:"count"`
+	Ratio    float64           `json:"ratio"`
+	At       time.Time         `json:"at"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]inner  `json:"labels"`
+	Parent   *node             `json:"parent,omitempty"`
+	Children []node            `json:"children"`
+	Secret   string            `json:"-"`
+	Any      any               `json:"any"`
+	ByName   map[string]string `json:"byName,omitempty"`
+	hidden   string
+}
+
+func TestSchema(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+
+	ref := doc.Schema(&node{})
+	assert.Equal(t, "#/components/schemas/node", ref.Ref)
+
+	s := doc.Components.Schemas["node"]
+	require.NotNil(t, s)
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, &Schema{Type: "string"}, s.Properties["shared"], "embedded fields are flattened")
+	assert.Equal(t, &Schema{Type: "integer", Format: "int32"}, s.Properties["count"])
+	assert.Equal(t, &Schema{Type: "string", Format: "date-time"}, s.Properties["at"])
+	assert.Equal(t, "array", s.Properties["tags"].Type)
+	assert.Equal(t, Ref("inner"), s.Properties["labels"].AdditionalProperties)
+	assert.Equal(t, Ref("node"), s.Properties["parent"], "recursive types are referenced")
+	assert.Equal(t, Ref("node"), s.Properties["children"].Items)
+	assert.NotContains(t, s.Properties, "Secret")
+	assert.NotContains(t, s.Properties, "hidden")
+	assert.ElementsMatch(t, []string{"shared", "id", "count", "ratio", "at", "tags", "labels", "children", "any"}, s.Required)
+
+	inline := doc.Inline(inner{})
+	assert.Equal(t, "object", inline.Type)
+	assert.Contains(t, doc.Components.Schemas, "inner", "inner was referenced by node")
+}
+
+func TestAdd(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.Add("GET", "/api/tickers/:symbol", &Operation{Summary: "get"})
+
+	assert.Contains(t, doc.Paths, "/api/tickers/{symbol}")
+	assert.True(t, doc.Has("GET", "/api/tickers/:symbol"))
+	assert.True(t, doc.Has("get", "/api/tickers/{symbol}"))
+	assert.False(t, doc.Has("DELETE", "/api/tickers/:symbol"))
+}
+
+func TestValidateResponse(t *testing.T) {
+	doc := New(Info{Title: "test", Version: "1"})
+	doc.Add("GET", "/nodes/:id", &Operation{Responses: map[string]*Response{
+		"200": JSON("OK", doc.Schema(node{})),
+		"204": {Description: "No Content"},
+	}})
+
+	valid := `{"shared":"s","id":"a","count":1,"ratio":0.5,"at":"2024-01-02T00:00:00Z","tags":[],
+		"labels":{"x":{"label":"y"}},"children":[],"any":null}`
+	assert.NoError(t, doc.ValidateResponse("GET", "/nodes/{id}", 200, "application/json; charset=utf-8", []byte(valid)))
+	assert.NoError(t, doc.ValidateResponse("GET", "/nodes/:id", 204, "", nil))
+
+	err := doc.ValidateResponse("GET", "/nodes/:id", 200, "application/json",
+		[]byte(`{"shared":"s","id":1,"count":1.5,"ratio":0,"at":"","tags":null,"labels":{"x":{}},"children":[{}],"any":1,"extra":true}`))
+	require.Error(t, err)
+	for _, want := range []string{
+		"$.id: number is not a string",
+		"$.count: 1.5 is not an integer",
+		"$.tags: null is not array",
+		`$.labels.x: missing required property "label"`,
+		`$.children[0]: missing required property "id"`,
+		`$: undocumented property "extra"`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	assert.ErrorContains(t, doc.ValidateResponse("GET", "/nodes/:id", 404, "application/json", nil), "status 404 is not documented")
+	assert.ErrorContains(t, doc.ValidateResponse("GET", "/nodes/:id", 200, "text/csv", nil), "content type text/csv")
+	assert.ErrorContains(t, doc.ValidateResponse("POST", "/nodes/:id", 200, "", nil), "not documented")
+}
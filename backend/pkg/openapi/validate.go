@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidateResponse checks a response to method on path, a gin or OpenAPI
+// route template, against its documentation: the status must be documented,
+// with the content type of the body, and JSON bodies must match the schema.
+// Clients generated from the document rely on exactly this.
+func (d *Document) ValidateResponse(method, path string, status int, contentType string, body []byte) error {
+	op, ok := d.Paths[OpenAPIPath(path)][strings.ToLower(method)]
+	if !ok {
+		return fmt.Errorf("%s %s is not documented", method, path)
+	}
+	response, ok := op.Responses[strconv.Itoa(status)]
+	if !ok {
+		return fmt.Errorf("status %d is not documented", status)
+	}
+	if len(response.Content) == 0 {
+		if len(body) > 0 {
+			return fmt.Errorf("status %d is documented without a body but has one", status)
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type %q: %w", contentType, err)
+	}
+	media, ok := response.Content[mediaType]
+	if !ok {
+		return fmt.Errorf("content type %s is not documented for status %d", mediaType, status)
+	}
+	if media.Schema == nil || !isJSON(mediaType) {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	return d.Validate(media.Schema, value)
+}
+
+// Validate checks a value decoded from JSON, with numbers as json.Number,
+// against schema. Every mismatch is reported, by its JSON path.
+func (d *Document) Validate(schema *Schema, value any) error {
+	var errs []error
+	d.validate(schema, value, "$", &errs)
+	return errors.Join(errs...)
+}
+
+func (d *Document) validate(s *Schema, v any, at string, errs *[]error) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, fmt.Errorf("%s: %s", at, fmt.Sprintf(format, args...)))
+	}
+
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		ref, ok := d.Components.Schemas[name]
+		if !ok {
+			fail("unknown schema %s", s.Ref)
+			return
+		}
+		d.validate(ref, v, at, errs)
+		return
+	}
+	if v == nil {
+		if !s.Nullable && s.Type != "" {
+			fail("null is not %s", s.Type)
+		}
+		return
+	}
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(v) }) {
+		fail("%v is not one of %v", v, s.Enum)
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := v.(map[string]any)
+		if !ok {
+			fail("%s is not an object", jsonType(v))
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			switch {
+			case ok:
+				d.validate(property, object[name], at+"."+name, errs)
+			case s.AdditionalProperties != nil:
+				d.validate(s.AdditionalProperties, object[name], at+"."+name, errs)
+			case len(s.Properties) > 0:
+				fail("undocumented property %q", name)
+			}
+		}
+	case "array":
+		array, ok := v.([]any)
+		if !ok {
+			fail("%s is not an array", jsonType(v))
+			return
+		}
+		for i, item := range array {
+			d.validate(s.Items, item, fmt.Sprintf("%s[%d]", at, i), errs)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			fail("%s is not a string", jsonType(v))
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			fail("%s is not an integer", jsonType(v))
+		} else if _, err := n.Int64(); err != nil {
+			fail("%s is not an integer", n)
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			fail("%s is not a number", jsonType(v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			fail("%s is not a boolean", jsonType(v))
+		}
+	}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// isJSON reports whether mediaType is JSON, including problem+json and the
+// like
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,168 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// apnsTokenLifetime is how long a provider token is reused. APNs rejects
+	// tokens older than an hour and refreshes more often than every 20 minutes.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsConfig authorizes an APNs sender with a token signing key
+type APNsConfig struct {
+	// Key is the PEM encoded .p8 signing key
+	Key    []byte
+	KeyID  string
+	TeamID string
+	// Topic is the app's bundle ID
+	Topic string
+	// Production selects the production environment over the sandbox
+	Production bool
+	Timeout    time.Duration
+}
+
+// NewAPNs returns a sender delivering through the APNs HTTP/2 API with token
+// based authentication
+func NewAPNs(cfg APNsConfig) (Sender, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("invalid apns configuration: key id, team id and topic are required")
+	}
+
+	parsed, err := parsePKCS8(cfg.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apns key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid apns key: not an ECDSA key")
+	}
+
+	baseURL := apnsSandboxURL
+	if cfg.Production {
+		baseURL = apnsProductionURL
+	}
+
+	return &apnsSender{
+		key:     key,
+		keyID:   cfg.KeyID,
+		teamID:  cfg.TeamID,
+		topic:   cfg.Topic,
+		baseURL: baseURL,
+		client:  &http.Client{Timeout: cfg.Timeout},
+	}, nil
+}
+
+type apnsSender struct {
+	key     *ecdsa.PrivateKey
+	keyID   string
+	teamID  string
+	topic   string
+	baseURL string
+	client  *http.Client
+
+	mu       sync.Mutex
+	jwt      string
+	issuedAt time.Time
+}
+
+type apnsAlert struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// apnsUnregisteredReasons are the rejections meaning a device token should be forgotten
+var apnsUnregisteredReasons = map[string]bool{
+	"BadDeviceToken":         true,
+	"DeviceTokenNotForTopic": true,
+	"Unregistered":           true,
+}
+
+func (a *apnsSender) Send(ctx context.Context, token string, msg Message) error {
+	jwt, err := a.providerToken()
+	if err != nil {
+		return err
+	}
+
+	// Custom data sits next to the aps dictionary in the payload
+	payload := make(map[string]any, len(msg.Data)+1)
+	for k, v := range msg.Data {
+		payload[k] = v
+	}
+	payload["aps"] = map[string]any{
+		"alert": apnsAlert{Title: msg.Title, Body: msg.Body},
+		"sound": "default",
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal apns payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.baseURL+"/3/device/"+url.PathEscape(token), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build apns request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+jwt)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call apns: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var apnsErr struct {
+		Reason string `json:"reason"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&apnsErr)
+	if resp.StatusCode == http.StatusGone || apnsUnregisteredReasons[apnsErr.Reason] {
+		return fmt.Errorf("%w: apns responded with %s", ErrUnregistered, apnsErr.Reason)
+	}
+	return fmt.Errorf("apns responded with status %d: %s", resp.StatusCode, apnsErr.Reason)
+}
+
+// providerToken returns the signed provider token, reissued once it has been
+// used for apnsTokenLifetime
+func (a *apnsSender) providerToken() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.jwt != "" && now.Sub(a.issuedAt) < apnsTokenLifetime {
+		return a.jwt, nil
+	}
+
+	jwt, err := signJWT(
+		map[string]string{"alg": "ES256", "kid": a.keyID},
+		map[string]any{"iss": a.teamID, "iat": now.Unix()},
+		func(digest []byte) ([]byte, error) {
+			return signES256(a.key, digest)
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	a.jwt = jwt
+	a.issuedAt = now
+	return jwt, nil
+}
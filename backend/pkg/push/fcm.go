@@ -0,0 +1,211 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	fcmBaseURL = "https://fcm.googleapis.com"
+	fcmScope   = "https://www.googleapis.com/auth/firebase.messaging"
+	// fcmTokenLifetime is how long requested access tokens are valid; they
+	// are refreshed a minute early
+	fcmTokenLifetime = time.Hour
+)
+
+// serviceAccount is the subset of a Google service account key FCM needs
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// NewFCM returns a sender delivering through the FCM HTTP v1 API, authorized
+// by the service account JSON key credentials
+func NewFCM(credentials []byte, timeout time.Duration) (Sender, error) {
+	var account serviceAccount
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("invalid fcm credentials: %w", err)
+	}
+	if account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, fmt.Errorf("invalid fcm credentials: project_id, client_email and token_uri are required")
+	}
+
+	parsed, err := parsePKCS8([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid fcm private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid fcm private key: not an RSA key")
+	}
+
+	return &fcmSender{
+		account: account,
+		key:     key,
+		baseURL: fcmBaseURL,
+		client:  &http.Client{Timeout: timeout},
+	}, nil
+}
+
+type fcmSender struct {
+	account serviceAccount
+	key     *rsa.PrivateKey
+	baseURL string
+	client  *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expires     time.Time
+}
+
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// fcmError is the error body of the FCM API. Tokens that are no longer
+// registered are reported with the UNREGISTERED error code.
+type fcmError struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (f *fcmSender) Send(ctx context.Context, token string, msg Message) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to marshal fcm message: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/v1/projects/%s/messages:send", f.baseURL, url.PathEscape(f.account.ProjectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build fcm request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call fcm: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	var fcmErr fcmError
+	_ = json.NewDecoder(resp.Body).Decode(&fcmErr)
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%w: fcm responded with status %d", ErrUnregistered, resp.StatusCode)
+	}
+	for _, detail := range fcmErr.Error.Details {
+		if detail.ErrorCode == "UNREGISTERED" {
+			return fmt.Errorf("%w: %s", ErrUnregistered, fcmErr.Error.Message)
+		}
+	}
+	return fmt.Errorf("fcm responded with status %d: %s", resp.StatusCode, fcmErr.Error.Message)
+}
+
+// token returns an OAuth2 access token for the service account, exchanging a
+// signed assertion for a new one when the cached token is about to expire
+func (f *fcmSender) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.accessToken != "" && now.Before(f.expires) {
+		return f.accessToken, nil
+	}
+
+	assertion, err := signJWT(
+		map[string]string{"alg": "RS256", "typ": "JWT"},
+		map[string]any{
+			"iss":   f.account.ClientEmail,
+			"scope": fcmScope,
+			"aud":   f.account.TokenURI,
+			"iat":   now.Unix(),
+			"exp":   now.Add(fcmTokenLifetime).Unix(),
+		},
+		func(digest []byte) ([]byte, error) {
+			return rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, digest)
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.account.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build fcm token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request fcm access token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return "", fmt.Errorf("fcm token endpoint responded with status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode fcm access token: %w", err)
+	}
+	if result.AccessToken == "" {
+		return "", fmt.Errorf("fcm token endpoint returned no access token")
+	}
+
+	f.accessToken = result.AccessToken
+	f.expires = now.Add(time.Duration(result.ExpiresIn)*time.Second - time.Minute)
+	return f.accessToken, nil
+}
@@ -0,0 +1,92 @@
+// Package push delivers notifications to mobile devices through Firebase Cloud
+// Messaging (Android) and the Apple Push Notification service (iOS).
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Platforms devices register tokens for
+const (
+	PlatformIOS     = "ios"
+	PlatformAndroid = "android"
+)
+
+// ErrUnregistered is returned when a device token is no longer valid, e.g.
+// because the app was uninstalled. Such tokens should be forgotten.
+var ErrUnregistered = errors.New("device token is not registered")
+
+// Message is the alert shown on a device. Data is delivered to the app as is.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender delivers messages to the device tokens of one platform
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// Senders maps platforms to their senders
+type Senders map[string]Sender
+
+// Config holds the credentials of the push platforms; a platform without
+// credentials is disabled
+type Config struct {
+	// FCMCredentialsFile is a Firebase service account JSON key
+	FCMCredentialsFile string
+
+	// APNSKeyFile is an APNs .p8 signing key identified by APNSKeyID and
+	// issued to APNSTeamID. APNSTopic is the app's bundle ID.
+	APNSKeyFile    string
+	APNSKeyID      string
+	APNSTeamID     string
+	APNSTopic      string
+	APNSProduction bool
+
+	Timeout time.Duration
+}
+
+// Open returns the senders of the configured platforms, none when push is not
+// configured
+func Open(cfg Config) (Senders, error) {
+	senders := make(Senders)
+
+	if cfg.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fcm credentials: %w", err)
+		}
+		fcm, err := NewFCM(credentials, cfg.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		senders[PlatformAndroid] = fcm
+	}
+
+	if cfg.APNSKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read apns key: %w", err)
+		}
+		apns, err := NewAPNs(APNsConfig{
+			Key:        key,
+			KeyID:      cfg.APNSKeyID,
+			TeamID:     cfg.APNSTeamID,
+			Topic:      cfg.APNSTopic,
+			Production: cfg.APNSProduction,
+			Timeout:    cfg.Timeout,
+		})
+		if err != nil {
+			return nil, err
+		}
+		senders[PlatformIOS] = apns
+	}
+
+	return senders, nil
+}
@@ -0,0 +1,177 @@
+package push
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pkcs8PEM(t *testing.T, key any) []byte {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+// decodeJWT verifies the ES256 or RS256 signature of jwt with pub and returns its claims
+func decodeJWT(t *testing.T, jwt string, pub any) map[string]any {
+	t.Helper()
+	parts := strings.Split(jwt, ".")
+	require.Len(t, parts, 3)
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	require.NoError(t, err)
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		require.NoError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig))
+	case *ecdsa.PublicKey:
+		require.Len(t, sig, 64)
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		require.True(t, ecdsa.Verify(pub, digest[:], r, s), "signature verifies")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	require.NoError(t, err)
+	var claims map[string]any
+	require.NoError(t, json.Unmarshal(payload, &claims))
+	return claims
+}
+
+func TestFCM(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var tokenRequests int
+	var received fcmRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			require.NoError(t, r.ParseForm())
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", r.PostForm.Get("grant_type"))
+			claims := decodeJWT(t, r.PostForm.Get("assertion"), &key.PublicKey)
+			assert.Equal(t, "push@profitify.iam.gserviceaccount.com", claims["iss"])
+			assert.Equal(t, fcmScope, claims["scope"])
+			_, _ = w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+		case "/v1/projects/profitify/messages:send":
+			assert.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			switch received.Message.Token {
+			case "gone":
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"status":"NOT_FOUND","details":[{"errorCode":"UNREGISTERED"}]}}`))
+			case "broken":
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	credentials, err := json.Marshal(serviceAccount{
+		ProjectID:   "profitify",
+		ClientEmail: "push@profitify.iam.gserviceaccount.com",
+		PrivateKey:  string(pkcs8PEM(t, key)),
+		TokenURI:    srv.URL + "/token",
+	})
+	require.NoError(t, err)
+	sender, err := NewFCM(credentials, time.Second)
+	require.NoError(t, err)
+	sender.(*fcmSender).baseURL = srv.URL
+
+	ctx := context.Background()
+	msg := Message{Title: "AAPL closed above 200", Body: "Take profits", Data: map[string]string{"kind": "alert"}}
+	require.NoError(t, sender.Send(ctx, "device", msg))
+	assert.Equal(t, "device", received.Message.Token)
+	assert.Equal(t, "AAPL closed above 200", received.Message.Notification.Title)
+	assert.Equal(t, "alert", received.Message.Data["kind"])
+
+	assert.ErrorIs(t, sender.Send(ctx, "gone", msg), ErrUnregistered)
+	err = sender.Send(ctx, "broken", msg)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnregistered)
+	assert.Equal(t, 1, tokenRequests, "access tokens are reused until they expire")
+
+	_, err = NewFCM([]byte(`{"project_id":"profitify"}`), time.Second)
+	assert.Error(t, err)
+}
+
+func TestAPNs(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var payload map[string]any
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, 2, r.ProtoMajor, "apns requires http/2")
+		assert.Equal(t, "app.profitify", r.Header.Get("apns-topic"))
+		assert.Equal(t, "alert", r.Header.Get("apns-push-type"))
+		claims := decodeJWT(t, strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), &key.PublicKey)
+		assert.Equal(t, "TEAM", claims["iss"])
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		switch strings.TrimPrefix(r.URL.Path, "/3/device/") {
+		case "gone":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		case "bad":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+		case "throttled":
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"reason":"TooManyRequests"}`))
+		}
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	sender, err := NewAPNs(APNsConfig{Key: pkcs8PEM(t, key), KeyID: "KEY", TeamID: "TEAM", Topic: "app.profitify", Timeout: time.Second})
+	require.NoError(t, err)
+	apns := sender.(*apnsSender)
+	assert.Equal(t, apnsSandboxURL, apns.baseURL)
+	apns.baseURL = srv.URL
+	apns.client = srv.Client()
+
+	ctx := context.Background()
+	msg := Message{Title: "Your daily watchlist digest", Body: "Tech", Data: map[string]string{"kind": "digest"}}
+	require.NoError(t, sender.Send(ctx, "device", msg))
+	assert.Equal(t, "digest", payload["kind"])
+	assert.Equal(t, "Your daily watchlist digest", payload["aps"].(map[string]any)["alert"].(map[string]any)["title"])
+
+	assert.ErrorIs(t, sender.Send(ctx, "gone", msg), ErrUnregistered)
+	assert.ErrorIs(t, sender.Send(ctx, "bad", msg), ErrUnregistered)
+	err = sender.Send(ctx, "throttled", msg)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrUnregistered)
+
+	_, err = NewAPNs(APNsConfig{Key: pkcs8PEM(t, key), KeyID: "KEY", TeamID: "TEAM"})
+	assert.Error(t, err, "topic is required")
+}
+
+func TestOpen(t *testing.T) {
+	senders, err := Open(Config{})
+	require.NoError(t, err)
+	assert.Empty(t, senders)
+
+	_, err = Open(Config{FCMCredentialsFile: t.TempDir() + "/missing.json"})
+	assert.Error(t, err)
+}
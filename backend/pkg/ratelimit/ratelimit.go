@@ -0,0 +1,128 @@
+// Package ratelimit limits request rates with token buckets kept in process
+// memory, so each replica enforces its limits on the requests it serves.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit allows Rate requests per second on average, in bursts of up to Burst
+// requests. A zero Rate or Burst disables limiting.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// Decision is the outcome of taking a token
+type Decision struct {
+	Allowed bool
+	// Limit is the bucket size and Remaining the whole tokens left in it
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until a token is available when not allowed
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+}
+
+// Limiter keeps a token bucket per key. Buckets that have refilled are
+// forgotten when the limiter grows, so idle keys do not accumulate.
+type Limiter struct {
+	limit Limit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	sweepSize int
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func New(limit Limit) *Limiter {
+	return &Limiter{
+		limit:     limit,
+		now:       time.Now,
+		buckets:   make(map[string]*bucket),
+		sweepSize: 1024,
+	}
+}
+
+// Allow takes a token from key's bucket if one is available
+func (l *Limiter) Allow(key string) Decision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	burst := float64(l.limit.Burst)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		l.sweep(now)
+		b = &bucket{tokens: burst, updated: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.updated).Seconds()*l.limit.Rate)
+	b.updated = now
+
+	d := Decision{Limit: l.limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		d.Allowed = true
+	} else {
+		d.RetryAfter = l.duration(1 - b.tokens)
+	}
+	d.Remaining = int(b.tokens)
+	d.Reset = l.duration(burst - b.tokens)
+	return d
+}
+
+// Wait blocks until it takes a token from key's bucket, pacing clients that
+// must stay within a limit rather than be rejected by it. It returns ctx's
+// error if ctx is done first.
+func (l *Limiter) Wait(ctx context.Context, key string) error {
+	for {
+		d := l.Allow(key)
+		if d.Allowed {
+			return nil
+		}
+
+		timer := time.NewTimer(d.RetryAfter)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// duration returns how long refilling tokens takes
+func (l *Limiter) duration(tokens float64) time.Duration {
+	return time.Duration(math.Ceil(tokens / l.limit.Rate * float64(time.Second)))
+}
+
+// sweep drops the buckets that have refilled since their last use once the
+// limiter holds sweepSize buckets
+func (l *Limiter) sweep(now time.Time) {
+	if len(l.buckets) < l.sweepSize {
+		return
+	}
+	for key, b := range l.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*l.limit.Rate >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	// Sweep again once the active buckets have doubled
+	l.sweepSize = max(l.sweepSize, 2*len(l.buckets))
+}
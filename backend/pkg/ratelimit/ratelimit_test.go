@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_Allow(t *testing.T) {
+	now := time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC)
+	l := New(Limit{Rate: 2, Burst: 3})
+	l.now = func() time.Time { return now }
+
+	for i := 2; i >= 0; i-- {
+		d := l.Allow("key")
+		assert.True(t, d.Allowed)
+		assert.Equal(t, 3, d.Limit)
+		assert.Equal(t, i, d.Remaining)
+	}
+
+	d := l.Allow("key")
+	assert.False(t, d.Allowed, "the burst is used up")
+	assert.Equal(t, 500*time.Millisecond, d.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, d.Reset)
+	assert.True(t, l.Allow("other").Allowed, "keys have their own buckets")
+
+	now = now.Add(500 * time.Millisecond)
+	assert.True(t, l.Allow("key").Allowed, "tokens refill at the rate")
+	assert.False(t, l.Allow("key").Allowed)
+
+	now = now.Add(time.Hour)
+	d = l.Allow("key")
+	assert.True(t, d.Allowed)
+	assert.Equal(t, 2, d.Remaining, "buckets hold at most the burst")
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := New(Limit{Rate: 50, Burst: 1})
+
+	start := time.Now()
+	require.NoError(t, l.Wait(context.Background(), "key"))
+	require.NoError(t, l.Wait(context.Background(), "key"))
+	assert.GreaterOrEqual(t, time.Since(start), 15*time.Millisecond, "the second token waits for the bucket to refill")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.Wait(ctx, "key"), context.Canceled)
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	now := time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC)
+	l := New(Limit{Rate: 1, Burst: 1})
+	l.now = func() time.Time { return now }
+	l.sweepSize = 4
+
+	for i := 0; i < 4; i++ {
+		l.Allow(fmt.Sprint(i))
+	}
+	now = now.Add(time.Second)
+	l.Allow("new")
+	assert.Len(t, l.buckets, 1, "refilled buckets are dropped")
+}
+
+func TestLimit_Enabled(t *testing.T) {
+	assert.True(t, Limit{Rate: 1, Burst: 1}.Enabled())
+	assert.False(t, Limit{Rate: 1}.Enabled())
+	assert.False(t, Limit{Burst: 1}.Enabled())
+}
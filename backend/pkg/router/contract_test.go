@@ -0,0 +1,235 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"profitify-backend/internal/activity"
+	"profitify-backend/internal/admin"
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/analytics"
+	"profitify-backend/internal/app"
+	"profitify-backend/internal/auth"
+	"profitify-backend/internal/bundle"
+	"profitify-backend/internal/devices"
+	"profitify-backend/internal/digests"
+	"profitify-backend/internal/indicators"
+	"profitify-backend/internal/market"
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/portfolios"
+	"profitify-backend/internal/repository"
+	"profitify-backend/internal/screener"
+	"profitify-backend/internal/service"
+	"profitify-backend/internal/sessions"
+	"profitify-backend/internal/stats"
+	"profitify-backend/internal/summaries"
+	"profitify-backend/internal/tickers"
+	"profitify-backend/internal/users"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/clock"
+	"profitify-backend/pkg/config"
+	"profitify-backend/pkg/errorlog"
+	"profitify-backend/pkg/metrics"
+	"profitify-backend/pkg/openapi"
+	"profitify-backend/pkg/tasks"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// contractParams fill the path parameters of the requests, by name
+var contractParams = map[string]string{
+	"symbol": "AAPL",
+	"date":   "2024-01-02",
+}
+
+// contractServer serves the API as main wires it, backed by the memory storage
+// backend and a DynamoDB that holds no items and accepts every write. Clients
+// of the API are generated from the document it returns, so every response of
+// the server must match it.
+// Each request authenticates with an admin key of its own, since some, such
+// as deleting the account, revoke the key they are made with.
+func contractServer(t *testing.T) (*httptest.Server, *openapi.Document, service.APIKeyService) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	dynamo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(dynamo.Close)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	cfg.StorageBackend = repository.BackendMemory
+	memory, err := repository.OpenMemoryStore(cfg.StorageBackend, true, cfg.TickerChangeRetention)
+	require.NoError(t, err)
+
+	log := zap.NewNop().Sugar()
+	background := tasks.New(ctx, log)
+	t.Cleanup(func() { _ = background.Stop(time.Second) })
+
+	deps := app.Deps{
+		Config: cfg,
+		DB: dynamodb.New(dynamodb.Options{
+			Region:           "us-east-1",
+			BaseEndpoint:     aws.String(dynamo.URL),
+			Credentials:      aws.AnonymousCredentials{},
+			RetryMaxAttempts: 1,
+		}),
+		Log:     log,
+		Metrics: metrics.New(),
+		Memory:  memory,
+		Tasks:   background,
+		Clock:   clock.System,
+	}
+
+	authModule := auth.Wire(deps)
+	sessionsModule := sessions.Wire(deps)
+	usersModule := users.Wire(deps)
+	authConfig := AuthConfig{
+		Authenticator: authModule.Keys(),
+		RequireAPIKey: true,
+		Signatures:    authModule.Signatures(),
+		Sessions:      sessionsModule.Sessions(),
+		Users:         usersModule.Users(),
+	}
+	registrars := []RouteRegistrar{
+		tickers.Wire(deps),
+		summaries.Wire(deps),
+		indicators.Wire(deps),
+		stats.Wire(deps),
+		screener.Wire(deps),
+		portfolios.Wire(deps),
+		watchlists.Wire(deps),
+		bundle.Wire(deps),
+		alerts.Wire(deps),
+		digests.Wire(deps),
+		devices.Wire(deps),
+		sessionsModule,
+		activity.Wire(deps),
+		usersModule,
+		analytics.Wire(deps),
+		market.Wire(deps),
+		authModule,
+		admin.Wire(deps, nil, nil, errorlog.New(errorlog.DefaultCapacity)),
+	}
+
+	r := New("test", deps.Metrics)
+	r.SetupRoutes(authConfig, registrars...)
+	server := httptest.NewServer(r.Engine())
+	t.Cleanup(server.Close)
+	return server, Document(authConfig, registrars), authModule.Keys()
+}
+
+// TestContract calls every documented operation, with its path parameters
+// filled in and a request body built from its schema, and checks the response
+// against the document
+func TestContract(t *testing.T) {
+	server, doc, keys := contractServer(t)
+
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		for method, op := range doc.Paths[path] {
+			method = strings.ToUpper(method)
+			t.Run(method+" "+path, func(t *testing.T) {
+				key := "contract " + method + " " + path
+				require.NoError(t, keys.EnsureKey(context.Background(), key, "contract", true))
+
+				var body io.Reader
+				if op.RequestBody != nil {
+					if media, ok := op.RequestBody.Content["application/json"]; ok {
+						encoded, err := json.Marshal(example(doc, media.Schema, 0))
+						require.NoError(t, err)
+						body = bytes.NewReader(encoded)
+					}
+				}
+
+				req, err := http.NewRequest(method, server.URL+fillPath(path), body)
+				require.NoError(t, err)
+				req.Header.Set(middleware.APIKeyHeader, key)
+				req.Header.Set("Content-Type", "application/json")
+
+				resp, err := server.Client().Do(req)
+				require.NoError(t, err)
+				defer resp.Body.Close()
+				respBody, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+
+				assert.NoError(t, doc.ValidateResponse(method, path, resp.StatusCode, resp.Header.Get("Content-Type"), respBody),
+					"%d %s", resp.StatusCode, respBody)
+			})
+		}
+	}
+}
+
+// fillPath replaces the parameters of an OpenAPI path template
+func fillPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if name, ok := strings.CutPrefix(s, "{"); ok {
+			name = strings.TrimSuffix(name, "}")
+			value, ok := contractParams[name]
+			if !ok {
+				value = "contract-" + name
+			}
+			segments[i] = value
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// example returns a value of schema: its example, the first of an enum, the
+// required properties of objects and one item of arrays
+func example(doc *openapi.Document, s *openapi.Schema, depth int) any {
+	if s == nil || depth > 8 {
+		return nil
+	}
+	if s.Ref != "" {
+		return example(doc, doc.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")], depth+1)
+	}
+	if s.Example != nil {
+		return s.Example
+	}
+	if len(s.Enum) > 0 {
+		return s.Enum[0]
+	}
+
+	switch s.Type {
+	case "object":
+		object := make(map[string]any)
+		for _, name := range s.Required {
+			object[name] = example(doc, s.Properties[name], depth+1)
+		}
+		return object
+	case "array":
+		return []any{example(doc, s.Items, depth+1)}
+	case "string":
+		if s.Format == "date-time" {
+			return time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+		}
+		return "AAPL"
+	case "integer", "number":
+		return 1
+	case "boolean":
+		return true
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"profitify-backend/internal/middleware"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// docsSpecPath and docsUIPath serve the API documentation. They are public
+	// so the UI can load the document without a key.
+	docsSpecPath = versionPrefix + "/openapi.json"
+	docsUIPath   = versionPrefix + "/docs"
+
+	// apiKeyScheme names the API key security scheme in the document
+	apiKeyScheme = "apiKey"
+)
+
+// docsUI renders the document with Swagger UI, loaded from a CDN
+const docsUI = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Profitify API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "` + docsSpecPath + `",
+      dom_id: "#swagger-ui",
+      persistAuthorization: true,
+    });
+  </script>
+</body>
+</html>`
+
+// Document returns the OpenAPI document of the registrars' routes
+func Document(auth AuthConfig, registrars []RouteRegistrar) *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title: "Profitify API",
+		Description: "Market data, portfolios, watchlists and alerts. Errors respond with " +
+			"`{\"error\": \"...\"}`. Admin routes require an admin key; plan limits respond " +
+			"402 when a higher tier allows the request and 403 otherwise. Requests are rate " +
+			"limited per API key, or per client IP without one, and respond 429 with " +
+			"Retry-After when throttled; X-RateLimit-* headers report the remaining burst. " +
+			"Routes are served under /api/v1; the unversioned /api routes are deprecated aliases " +
+			"that redirect reads permanently. Deprecated routes answer with Deprecation and, once " +
+			"their removal is scheduled, Sunset headers.",
+		Version: "1.0",
+	})
+	doc.Components.SecuritySchemes[apiKeyScheme] = &openapi.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: middleware.APIKeyHeader,
+	}
+
+	required := []openapi.SecurityRequirement{{apiKeyScheme: {}}}
+	doc.Security = required
+	if !auth.RequireAPIKey {
+		// The empty requirement makes the key optional
+		doc.Security = append(required, openapi.SecurityRequirement{})
+	}
+
+	for _, registrar := range registrars {
+		if documenter, ok := registrar.(RouteDocumenter); ok {
+			documenter.DocumentRoutes(doc)
+		}
+	}
+
+	for path, item := range doc.Paths {
+		for _, op := range item {
+			switch {
+			case strings.HasPrefix(path, versionPrefix+"/admin/"):
+				op.Security = required
+			case strings.HasPrefix(path, publicPrefix+"/"):
+				op.Security = []openapi.SecurityRequirement{{}}
+			}
+		}
+	}
+
+	return doc
+}
+
+func (r *Router) setupDocsRoutes(auth AuthConfig, registrars []RouteRegistrar) {
+	doc := Document(auth, registrars)
+	for route := range r.deprecations {
+		method, path, _ := strings.Cut(route, " ")
+		if op, ok := doc.Paths[openapi.OpenAPIPath(path)][strings.ToLower(method)]; ok {
+			op.Deprecated = true
+		}
+	}
+
+	// The unversioned aliases are left out, documented by their versions
+	var undocumented []string
+	for _, route := range r.engine.Routes() {
+		if strings.HasPrefix(route.Path, versionPrefix+"/") && !doc.Has(route.Method, route.Path) &&
+			route.Path != docsSpecPath && route.Path != docsUIPath {
+			undocumented = append(undocumented, route.Method+" "+route.Path)
+		}
+	}
+	if len(undocumented) > 0 {
+		sort.Strings(undocumented)
+		logger.Get().Warnw("API routes missing from the OpenAPI document", "routes", undocumented)
+	}
+
+	spec, err := json.Marshal(doc)
+	if err != nil {
+		logger.Get().Errorw("failed to encode the OpenAPI document", "error", err)
+		return
+	}
+
+	r.engine.GET(docsSpecPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	})
+	r.engine.GET(docsUIPath, func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsUI))
+	})
+	for _, path := range []string{docsSpecPath, docsUIPath} {
+		r.engine.GET(apiPrefix+strings.TrimPrefix(path, versionPrefix), r.legacyAlias())
+	}
+}
@@ -0,0 +1,121 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/activity"
+	"profitify-backend/internal/admin"
+	"profitify-backend/internal/alerts"
+	"profitify-backend/internal/analytics"
+	"profitify-backend/internal/auth"
+	"profitify-backend/internal/devices"
+	"profitify-backend/internal/digests"
+	"profitify-backend/internal/indicators"
+	"profitify-backend/internal/market"
+	"profitify-backend/internal/portfolios"
+	"profitify-backend/internal/screener"
+	"profitify-backend/internal/summaries"
+	"profitify-backend/internal/tickers"
+	"profitify-backend/internal/users"
+	"profitify-backend/internal/watchlists"
+	"profitify-backend/pkg/metrics"
+	"profitify-backend/pkg/openapi"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// modules are the feature modules main registers; routes only reference
+// their handlers' methods, so zero handlers will do
+func modules() []RouteRegistrar {
+	return []RouteRegistrar{
+		&tickers.Handler{},
+		&summaries.Handler{},
+		&indicators.Handler{},
+		&screener.Handler{},
+		&portfolios.Handler{},
+		&watchlists.Handler{},
+		&alerts.Handler{},
+		&digests.Handler{},
+		&devices.Handler{},
+		&users.Handler{},
+		&activity.Handler{},
+		&analytics.Handler{},
+		&market.Handler{},
+		&auth.Handler{},
+		&admin.Handler{},
+	}
+}
+
+func TestDocument_CoversEveryAPIRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := New("test", metrics.New())
+	r.SetupRoutes(AuthConfig{Authenticator: keyAuthenticator{}}, modules()...)
+	doc := Document(AuthConfig{}, modules())
+
+	routes := 0
+	for _, route := range r.Engine().Routes() {
+		if !strings.HasPrefix(route.Path, versionPrefix+"/") || route.Path == docsSpecPath || route.Path == docsUIPath {
+			continue
+		}
+		routes++
+		assert.True(t, doc.Has(route.Method, route.Path), "%s %s is not documented", route.Method, route.Path)
+	}
+
+	documented := 0
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			documented++
+			assert.NotEmpty(t, op.Summary, "%s %s", method, path)
+			for _, param := range op.Parameters {
+				if param.In == "path" {
+					assert.Contains(t, path, "{"+param.Name+"}", "%s %s", method, path)
+				}
+			}
+			if strings.HasPrefix(path, versionPrefix+"/admin/") {
+				assert.Len(t, op.Security, 1, "admin routes always require a key")
+			}
+			if strings.HasPrefix(path, publicPrefix+"/") {
+				assert.Equal(t, []openapi.SecurityRequirement{{}}, op.Security, "public routes never require a key")
+			}
+		}
+	}
+	assert.Equal(t, routes, documented, "every documented operation is served")
+}
+
+func TestDocsRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := New("test", metrics.New())
+	r.SetupRoutes(AuthConfig{Authenticator: keyAuthenticator{}, RequireAPIKey: true}, pingRoutes{}, modules()[0])
+
+	t.Run("the document is served without a key", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, docsSpecPath, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var doc openapi.Document
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &doc))
+		assert.Equal(t, openapi.Version, doc.OpenAPI)
+		assert.Contains(t, doc.Paths, "/api/v1/tickers/{symbol}")
+		assert.Len(t, doc.Security, 1, "keys are required when the API requires them")
+		assert.Contains(t, doc.Components.Schemas, "Ticker")
+	})
+
+	t.Run("the UI loads the document", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, docsUIPath, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), docsSpecPath)
+	})
+
+	t.Run("other routes still require a key", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -1,33 +1,240 @@
 package router
 
 import (
-	"profitify-backend/internal/handlers"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/problem"
+	"profitify-backend/pkg/logger"
+	"profitify-backend/pkg/metrics"
+	"profitify-backend/pkg/openapi"
+	"profitify-backend/pkg/ratelimit"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
-	engine *gin.Engine
+	engine    *gin.Engine
+	metrics   *metrics.Metrics
+	analytics middleware.UsageRecorder
+	errors    middleware.ErrorRecorder
+	// apiLimit and adminLimit rate limit the API and admin routes
+	apiLimit   ratelimit.Limit
+	adminLimit ratelimit.Limit
+	// clientLimit rate limits each client IP before authentication
+	clientLimit ratelimit.Limit
+	// healthChecks report the dependencies on the health routes, by name
+	healthChecks map[string]HealthCheck
+	// compress compresses responses once set by WithCompression
+	compress gin.HandlerFunc
+	// cacheControl is the Cache-Control policy of API routes, by template
+	cacheControl map[string]string
+	// deprecations are the deprecated API routes, by method and template
+	deprecations map[string]middleware.Deprecation
+	// legacySunset is when the unversioned /api aliases are removed; zero
+	// announces no date
+	legacySunset time.Time
 }
 
-func New(mode string) *Router {
+// HealthCheck returns why a dependency is unavailable, or nil when it is
+type HealthCheck func(ctx context.Context) error
+
+func New(mode string, m *metrics.Metrics) *Router {
 	if mode == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	r := gin.New()
-	r.Use(gin.Recovery())
+	// Trust no proxy until configured, so clients cannot pick their IP, and
+	// with it their rate limit bucket, through X-Forwarded-For
+	_ = r.SetTrustedProxies(nil)
+	// Panics and unknown routes are answered with problem details like
+	// every other error
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		problem.Abort(c, problem.Internal, "Internal server error")
+	}))
+	r.Use(middleware.RequestID(logger.Get()))
+	r.Use(middleware.Tracing())
 	r.Use(middleware.Log())
+	r.Use(middleware.Metrics(m))
+	r.NoRoute(func(c *gin.Context) {
+		problem.Respond(c, problem.NotFound, "Route not found")
+	})
 
 	return &Router{
-		engine: r,
+		engine:  r,
+		metrics: m,
+	}
+}
+
+// WithAnalytics counts every API request in recorder
+func (r *Router) WithAnalytics(recorder middleware.UsageRecorder) *Router {
+	r.analytics = recorder
+	return r
+}
+
+// WithErrorLog records the server errors of API requests in recorder
+func (r *Router) WithErrorLog(recorder middleware.ErrorRecorder) *Router {
+	r.errors = recorder
+	return r
+}
+
+// WithRateLimits limits the request rate of each API key, or of each client
+// IP for requests without one, on the API routes and additionally on the admin
+// routes. Disabled limits are skipped.
+func (r *Router) WithRateLimits(api, admin ratelimit.Limit) *Router {
+	r.apiLimit = api
+	r.adminLimit = admin
+	return r
+}
+
+// WithClientRateLimit limits the request rate of each client IP on the API
+// routes before authentication, so requests failing it are throttled too. A
+// disabled limit is skipped.
+func (r *Router) WithClientRateLimit(limit ratelimit.Limit) *Router {
+	r.clientLimit = limit
+	return r
+}
+
+// WithHealthCheck reports the named dependency on /health and /health/ready.
+// The service keeps serving without it, so a failing check reports the
+// service degraded rather than unready, and is not restarted or drained.
+func (r *Router) WithHealthCheck(name string, check HealthCheck) *Router {
+	if r.healthChecks == nil {
+		r.healthChecks = make(map[string]HealthCheck)
+	}
+	r.healthChecks[name] = check
+	return r
+}
+
+// metricsPath serves the Prometheus metrics, which negotiate their own
+// compression
+const metricsPath = "/metrics"
+
+// WithCompression compresses responses of at least minSize bytes for clients
+// accepting gzip or deflate, except on paths starting with one of excluded
+// and on the metrics route
+func (r *Router) WithCompression(minSize int, excluded []string) *Router {
+	r.compress = middleware.Compress(minSize, append([]string{metricsPath}, excluded...))
+	return r
+}
+
+// WithCacheControl sets the Cache-Control header of successful GET responses
+// of the API routes in policies, keyed by route template. Unversioned
+// templates apply to the route in the current version.
+func (r *Router) WithCacheControl(policies map[string]string) *Router {
+	r.cacheControl = make(map[string]string, len(policies))
+	for route, policy := range policies {
+		r.cacheControl[versioned(route)] = policy
+	}
+	return r
+}
+
+// WithDeprecations marks API routes deprecated, keyed by method and route
+// template such as "GET /api/v1/prices", with the date they were deprecated
+// and optionally their sunset, as parsed by middleware.ParseDeprecation. Their
+// responses announce it in Deprecation and Sunset headers, and the OpenAPI
+// document marks their operations deprecated.
+func (r *Router) WithDeprecations(routes map[string]string) error {
+	r.deprecations = make(map[string]middleware.Deprecation, len(routes))
+	for route, value := range routes {
+		method, path, ok := strings.Cut(route, " ")
+		if !ok || !strings.HasPrefix(path, apiPrefix+"/") {
+			return fmt.Errorf("deprecated route %q is not a method and /api route", route)
+		}
+		d, err := middleware.ParseDeprecation(value)
+		if err != nil {
+			return fmt.Errorf("invalid deprecation of %s: %w", route, err)
+		}
+		r.deprecations[strings.ToUpper(method)+" "+versioned(path)] = d
+	}
+	return nil
+}
+
+// WithLegacySunset announces the date, YYYY-MM-DD, the unversioned /api
+// aliases of the routes stop being served; an empty date announces none
+func (r *Router) WithLegacySunset(date string) error {
+	if date == "" {
+		r.legacySunset = time.Time{}
+		return nil
+	}
+	sunset, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return fmt.Errorf("invalid legacy API sunset %q: not YYYY-MM-DD", date)
 	}
+	r.legacySunset = sunset
+	return nil
+}
+
+// WithTrustedProxies trusts the X-Forwarded-For header of requests from the
+// given proxy IPs and CIDRs to name the client IP
+func (r *Router) WithTrustedProxies(proxies []string) error {
+	if err := r.engine.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	return nil
+}
+
+// AuthConfig controls API key authentication of the API routes
+type AuthConfig struct {
+	Authenticator middleware.Authenticator
+	// RequireAPIKey protects every API route; admin routes always require an admin key
+	RequireAPIKey bool
+	// Quotas enforces the daily request quota of each key's plan when API
+	// keys are required
+	Quotas middleware.QuotaEnforcer
+	// Signatures verifies requests signed with a key's signing secret instead
+	// of carrying the key; nil accepts only the X-API-Key header
+	Signatures middleware.SignatureVerifier
+	// Sessions resolves the session tokens sent as `Authorization: Bearer`
+	// instead of the key; nil accepts no session tokens
+	Sessions middleware.SessionAuthenticator
+	// Users resolves the JWTs of user accounts sent as `Authorization:
+	// Bearer` to the users' keys; nil accepts no user tokens
+	Users middleware.UserAuthenticator
+	// TermsVersion is the version of the terms a key's holder must accept
+	// before calling the account routes; empty enforces none
+	TermsVersion string
+}
+
+// RouteRegistrar registers a feature's routes. api is mounted at /api/v1 and
+// admin at /api/v1/admin, behind admin API key authentication. Both are also
+// mounted unversioned at /api, as deprecated aliases, so registrars are called
+// once per mount.
+type RouteRegistrar interface {
+	RegisterRoutes(api, admin *gin.RouterGroup)
+}
+
+// RouteDocumenter is implemented by registrars that document their routes in
+// the OpenAPI document, by their full paths
+type RouteDocumenter interface {
+	DocumentRoutes(doc *openapi.Document)
 }
 
-func (r *Router) SetupRoutes(handler *handlers.Handler) {
+// PublicRouteRegistrar is implemented by registrars serving routes that are
+// reached without an API key, such as links mailed to users. public is
+// mounted at /api/v1/public, and aliased at /api/public; its handlers must
+// authorize requests themselves.
+type PublicRouteRegistrar interface {
+	RegisterPublicRoutes(public *gin.RouterGroup)
+}
+
+// publicPrefix mounts the public routes
+const publicPrefix = versionPrefix + "/public"
+
+func (r *Router) SetupRoutes(auth AuthConfig, registrars ...RouteRegistrar) {
+	// Middleware applies to the routes registered after it
+	if r.compress != nil {
+		r.engine.Use(r.compress)
+	}
 	r.setupHealthRoutes()
-	r.setupAPIRoutes(handler)
+	r.engine.GET(metricsPath, gin.WrapH(r.metrics.Handler()))
+	r.setupAPIRoutes(auth, registrars)
+	r.setupPublicRoutes(registrars)
+	r.setupDocsRoutes(auth, registrars)
 }
 
 func (r *Router) setupHealthRoutes() {
@@ -36,10 +243,86 @@ func (r *Router) setupHealthRoutes() {
 	r.engine.GET("/health/ready", r.readinessCheck)
 }
 
-func (r *Router) setupAPIRoutes(handler *handlers.Handler) {
-	api := r.engine.Group("/api")
-	{
-		api.GET("/tickers", handler.GetAllTickers)
+func (r *Router) setupAPIRoutes(auth AuthConfig, registrars []RouteRegistrar) {
+	handlers := []gin.HandlerFunc{middleware.CurrentTerms(auth.TermsVersion)}
+	if r.errors != nil {
+		handlers = append(handlers, middleware.RecordErrors(r.errors))
+	}
+	if r.analytics != nil {
+		handlers = append(handlers, middleware.Analytics(r.analytics))
+	}
+	if len(r.cacheControl) > 0 {
+		handlers = append(handlers, middleware.CacheControl(r.cacheControl))
+	}
+	if len(r.deprecations) > 0 {
+		handlers = append(handlers, middleware.Deprecations(r.deprecations))
+	}
+	// No key is known yet, so these buckets are keyed by client IP
+	if r.clientLimit.Enabled() {
+		handlers = append(handlers, middleware.RateLimit(ratelimit.New(r.clientLimit)))
+	}
+	if auth.Signatures != nil {
+		handlers = append(handlers, middleware.SignedRequestAuth(auth.Signatures))
+	}
+	if auth.Users != nil {
+		handlers = append(handlers, middleware.UserAuth(auth.Users))
+	}
+	if auth.Sessions != nil {
+		handlers = append(handlers, middleware.SessionAuth(auth.Sessions))
+	}
+	if auth.RequireAPIKey {
+		handlers = append(handlers, middleware.APIKeyAuth(auth.Authenticator))
+	}
+	// Limited after authentication to key the buckets by API key, and before
+	// the quota so throttled requests are not counted against it
+	if r.apiLimit.Enabled() {
+		handlers = append(handlers, middleware.RateLimit(ratelimit.New(r.apiLimit)))
+	}
+	if auth.RequireAPIKey && auth.Quotas != nil {
+		handlers = append(handlers, middleware.RequestQuota(auth.Quotas))
+	}
+
+	var adminHandlers []gin.HandlerFunc
+	if !auth.RequireAPIKey {
+		adminHandlers = append(adminHandlers, middleware.APIKeyAuth(auth.Authenticator))
+	}
+	adminHandlers = append(adminHandlers, middleware.RequireAdmin())
+	if r.adminLimit.Enabled() {
+		adminHandlers = append(adminHandlers, middleware.RateLimit(ratelimit.New(r.adminLimit)))
+	}
+
+	// The aliases share the middleware, and with it the rate limit buckets,
+	// so requests count against the same limits in either version
+	api := r.engine.Group(versionPrefix, handlers...)
+	legacy := r.engine.Group(apiPrefix, append([]gin.HandlerFunc{r.legacyAlias()}, handlers...)...)
+	for _, group := range []*gin.RouterGroup{api, legacy} {
+		admin := group.Group("/admin", adminHandlers...)
+		for _, registrar := range registrars {
+			registrar.RegisterRoutes(group, admin)
+		}
+	}
+}
+
+func (r *Router) setupPublicRoutes(registrars []RouteRegistrar) {
+	var handlers []gin.HandlerFunc
+	if r.errors != nil {
+		handlers = append(handlers, middleware.RecordErrors(r.errors))
+	}
+	if r.analytics != nil {
+		handlers = append(handlers, middleware.Analytics(r.analytics))
+	}
+	if r.apiLimit.Enabled() {
+		handlers = append(handlers, middleware.RateLimit(ratelimit.New(r.apiLimit)))
+	}
+
+	public := r.engine.Group(publicPrefix, handlers...)
+	legacy := r.engine.Group(apiPrefix+"/public", append([]gin.HandlerFunc{r.legacyAlias()}, handlers...)...)
+	for _, group := range []*gin.RouterGroup{public, legacy} {
+		for _, registrar := range registrars {
+			if p, ok := registrar.(PublicRouteRegistrar); ok {
+				p.RegisterPublicRoutes(group)
+			}
+		}
 	}
 }
 
@@ -48,10 +331,12 @@ func (r *Router) Engine() *gin.Engine {
 }
 
 func (r *Router) healthCheck(c *gin.Context) {
-	c.JSON(200, gin.H{
+	body := gin.H{
 		"status":  "healthy",
 		"service": "profitify-backend",
-	})
+	}
+	r.checkDependencies(c, body)
+	c.JSON(200, body)
 }
 
 func (r *Router) livenessCheck(c *gin.Context) {
@@ -61,7 +346,27 @@ func (r *Router) livenessCheck(c *gin.Context) {
 }
 
 func (r *Router) readinessCheck(c *gin.Context) {
-	c.JSON(200, gin.H{
+	body := gin.H{
 		"status": "ready",
-	})
+	}
+	r.checkDependencies(c, body)
+	c.JSON(200, body)
+}
+
+// checkDependencies adds the state of each checked dependency to body, "ok" or
+// the reason it is unavailable, and sets its status to degraded if any is
+func (r *Router) checkDependencies(c *gin.Context, body gin.H) {
+	if len(r.healthChecks) == 0 {
+		return
+	}
+	dependencies := make(map[string]string, len(r.healthChecks))
+	for name, check := range r.healthChecks {
+		if err := check(c.Request.Context()); err != nil {
+			dependencies[name] = err.Error()
+			body["status"] = "degraded"
+			continue
+		}
+		dependencies[name] = "ok"
+	}
+	body["dependencies"] = dependencies
 }
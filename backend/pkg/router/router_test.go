@@ -0,0 +1,275 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"profitify-backend/internal/middleware"
+	"profitify-backend/internal/models"
+	"profitify-backend/internal/service"
+	"profitify-backend/pkg/metrics"
+	"profitify-backend/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyAuthenticator map[string]*models.APIKey
+
+func (a keyAuthenticator) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	if record, ok := a[key]; ok {
+		return record, nil
+	}
+	return nil, service.ErrInvalidAPIKey
+}
+
+type pingRoutes struct{}
+
+func (pingRoutes) RegisterRoutes(api, admin *gin.RouterGroup) {
+	api.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	api.POST("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	admin.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+}
+
+func (pingRoutes) RegisterPublicRoutes(public *gin.RouterGroup) {
+	public.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+}
+
+func TestSetupRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := keyAuthenticator{
+		"user":  {ID: "u"},
+		"admin": {ID: "a", Admin: true},
+	}
+
+	tests := []struct {
+		name           string
+		requireAPIKey  bool
+		path           string
+		key            string
+		expectedStatus int
+	}{
+		{name: "open api route", path: "/api/v1/ping", expectedStatus: http.StatusOK},
+		{name: "admin route without key", path: "/api/v1/admin/ping", expectedStatus: http.StatusUnauthorized},
+		{name: "admin route with user key", path: "/api/v1/admin/ping", key: "user", expectedStatus: http.StatusForbidden},
+		{name: "admin route with admin key", path: "/api/v1/admin/ping", key: "admin", expectedStatus: http.StatusOK},
+		{name: "protected api route without key", requireAPIKey: true, path: "/api/v1/ping", expectedStatus: http.StatusUnauthorized},
+		{name: "protected api route with user key", requireAPIKey: true, path: "/api/v1/ping", key: "user", expectedStatus: http.StatusOK},
+		{name: "protected admin route with user key", requireAPIKey: true, path: "/api/v1/admin/ping", key: "user", expectedStatus: http.StatusForbidden},
+		{name: "protected admin route with admin key", requireAPIKey: true, path: "/api/v1/admin/ping", key: "admin", expectedStatus: http.StatusOK},
+		{name: "public route without key", requireAPIKey: true, path: "/api/v1/public/ping", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New("test", metrics.New())
+			r.SetupRoutes(AuthConfig{Authenticator: auth, RequireAPIKey: tt.requireAPIKey}, pingRoutes{})
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.key != "" {
+				req.Header.Set(middleware.APIKeyHeader, tt.key)
+			}
+			r.Engine().ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+		})
+	}
+}
+
+func TestSetupRoutes_RateLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := keyAuthenticator{"admin": {ID: "a", Admin: true}}
+
+	r := New("test", metrics.New()).WithRateLimits(ratelimit.Limit{Rate: 1, Burst: 3}, ratelimit.Limit{Rate: 1, Burst: 1})
+	r.SetupRoutes(AuthConfig{Authenticator: auth}, pingRoutes{})
+
+	serve := func(path string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set(middleware.APIKeyHeader, "admin")
+		r.Engine().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/api/v1/admin/ping"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/admin/ping"), "admin routes have their own limit")
+	assert.Equal(t, http.StatusOK, serve("/api/v1/ping"))
+	assert.Equal(t, http.StatusTooManyRequests, serve("/api/v1/ping"), "admin requests count against the api limit")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/ping", nil)
+	req.Header.Set(middleware.APIKeyHeader, "admin")
+	r.Engine().ServeHTTP(w, req)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "the unversioned aliases share the limits")
+}
+
+func TestSetupRoutes_ClientRateLimitThrottlesFailedAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := keyAuthenticator{"user": {ID: "u"}}
+
+	r := New("test", metrics.New()).
+		WithRateLimits(ratelimit.Limit{Rate: 1, Burst: 100}, ratelimit.Limit{}).
+		WithClientRateLimit(ratelimit.Limit{Rate: 1, Burst: 3})
+	r.SetupRoutes(AuthConfig{Authenticator: auth, RequireAPIKey: true}, pingRoutes{})
+
+	serve := func(key, ip string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/v1/ping", nil)
+		req.RemoteAddr = ip + ":1234"
+		req.Header.Set(middleware.APIKeyHeader, key)
+		r.Engine().ServeHTTP(w, req)
+		return w.Code
+	}
+
+	for range 3 {
+		assert.Equal(t, http.StatusUnauthorized, serve("guess", "10.0.0.1"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, serve("guess", "10.0.0.1"), "repeated failed authentications are throttled")
+	assert.Equal(t, http.StatusTooManyRequests, serve("user", "10.0.0.1"), "the client is throttled before its key is checked")
+	assert.Equal(t, http.StatusOK, serve("user", "10.0.0.2"), "other clients are not")
+}
+
+func TestSetupRoutes_LegacyAliases(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := New("test", metrics.New())
+	require.NoError(t, r.WithLegacySunset("2027-04-01"))
+	r.SetupRoutes(AuthConfig{Authenticator: keyAuthenticator{}}, pingRoutes{})
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.Engine().ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "/api/ping?verbose=1")
+	assert.Equal(t, http.StatusMovedPermanently, w.Code, "reads are redirected")
+	assert.Equal(t, "/api/v1/ping?verbose=1", w.Header().Get("Location"))
+	assert.Equal(t, "@1792108800", w.Header().Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	assert.Equal(t, `</api/v1/ping>; rel="successor-version"`, w.Header().Get("Link"))
+
+	w = serve(http.MethodPost, "/api/ping")
+	assert.Equal(t, http.StatusOK, w.Code, "writes are served in place")
+	assert.Equal(t, `</api/v1/ping>; rel="successor-version"`, w.Header().Get("Link"))
+
+	assert.Equal(t, "/api/v1/public/ping", serve(http.MethodGet, "/api/public/ping").Header().Get("Location"))
+	assert.Equal(t, docsSpecPath, serve(http.MethodGet, "/api/openapi.json").Header().Get("Location"))
+
+	w = serve(http.MethodGet, "/api/v1/ping")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Deprecation"), "the current version is not deprecated")
+}
+
+func TestWithDeprecations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := New("test", metrics.New())
+	require.NoError(t, r.WithDeprecations(map[string]string{"post /api/ping": "2026-10-01/2027-04-01"}))
+	r.SetupRoutes(AuthConfig{Authenticator: keyAuthenticator{}}, pingRoutes{})
+
+	w := httptest.NewRecorder()
+	r.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/ping", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "@1790812800", w.Header().Get("Deprecation"), "unversioned routes name the current version's")
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+
+	w = httptest.NewRecorder()
+	r.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/ping", nil))
+	assert.Empty(t, w.Header().Get("Deprecation"))
+
+	assert.Error(t, New("test", metrics.New()).WithDeprecations(map[string]string{"/api/v1/ping": "2026-10-01"}))
+	assert.Error(t, New("test", metrics.New()).WithDeprecations(map[string]string{"GET /health": "2026-10-01"}))
+	assert.Error(t, New("test", metrics.New()).WithDeprecations(map[string]string{"GET /api/v1/ping": "soon"}))
+	assert.Error(t, New("test", metrics.New()).WithLegacySunset("04/01/2027"))
+}
+
+func TestWithTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	clientIP := func(r *Router) string {
+		var ip string
+		r.Engine().GET("/ip", func(c *gin.Context) { ip = c.ClientIP() })
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = "10.0.0.2:4000"
+		req.Header.Set("X-Forwarded-For", "203.0.113.9")
+		r.Engine().ServeHTTP(httptest.NewRecorder(), req)
+		return ip
+	}
+
+	assert.Equal(t, "10.0.0.2", clientIP(New("test", metrics.New())), "no proxy is trusted by default")
+
+	r := New("test", metrics.New())
+	assert.NoError(t, r.WithTrustedProxies([]string{"10.0.0.0/8"}))
+	assert.Equal(t, "203.0.113.9", clientIP(r))
+
+	assert.Error(t, New("test", metrics.New()).WithTrustedProxies([]string{"not-an-ip"}))
+}
+
+func TestHealthChecks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var cacheErr error
+	r := New("test", metrics.New()).
+		WithHealthCheck("cache", func(ctx context.Context) error { return cacheErr })
+	r.SetupRoutes(AuthConfig{})
+
+	get := func(path string) string {
+		w := httptest.NewRecorder()
+		r.Engine().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, "a degraded service keeps serving")
+		return w.Body.String()
+	}
+
+	assert.JSONEq(t, `{"status":"ready","dependencies":{"cache":"ok"}}`, get("/health/ready"))
+	cacheErr = errors.New("connection refused")
+	assert.JSONEq(t, `{"status":"degraded","dependencies":{"cache":"connection refused"}}`, get("/health/ready"))
+	assert.Contains(t, get("/health"), `"status":"degraded"`)
+	assert.JSONEq(t, `{"status":"alive"}`, get("/health/live"))
+}
+
+func TestWithCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := New("test", metrics.New()).WithCompression(0, nil)
+	r.SetupRoutes(AuthConfig{})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		r.Engine().ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, "gzip", get("/health/live").Header().Get("Content-Encoding"))
+	assert.Empty(t, get("/metrics").Header().Values("Vary"), "metrics negotiate their own compression")
+}
+
+func TestSetupRoutes_ScopesGuardEveryAPIRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// A key restricted to a scope no route grants must be turned away before
+	// reaching the zero handlers of modules
+	auth := keyAuthenticator{"scoped": {ID: "s", Admin: true, Scopes: []models.APIKeyScope{"test:none"}}}
+
+	r := New("test", metrics.New())
+	r.SetupRoutes(AuthConfig{Authenticator: auth, RequireAPIKey: true}, modules()...)
+
+	for _, route := range r.Engine().Routes() {
+		// Public routes authorize their requests with signed links instead;
+		// the aliases of reads redirect before reaching the handlers
+		if !strings.HasPrefix(route.Path, apiPrefix+"/") || strings.Contains(route.Path, "/public/") ||
+			route.Path == docsSpecPath || route.Path == docsUIPath ||
+			(!strings.HasPrefix(route.Path, versionPrefix+"/") && route.Method == http.MethodGet) {
+			continue
+		}
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(route.Method, strings.ReplaceAll(route.Path, ":", ""), nil)
+		req.Header.Set(middleware.APIKeyHeader, "scoped")
+		r.Engine().ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code, "%s %s does not check scopes", route.Method, route.Path)
+	}
+}
@@ -0,0 +1,55 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"profitify-backend/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// apiPrefix mounts the unversioned aliases of the current version's routes
+	apiPrefix = "/api"
+	// APIVersion is the version of the API the routes are served in
+	APIVersion = "v1"
+	// versionPrefix mounts the routes of the current version
+	versionPrefix = apiPrefix + "/" + APIVersion
+)
+
+// legacyDeprecatedSince is when the unversioned /api routes were deprecated in
+// favor of /api/v1
+var legacyDeprecatedSince = time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+// versioned returns the path of an unversioned /api route in the current
+// version, and any other path unchanged
+func versioned(path string) string {
+	if !strings.HasPrefix(path, apiPrefix+"/") || strings.HasPrefix(path, versionPrefix+"/") {
+		return path
+	}
+	return versionPrefix + strings.TrimPrefix(path, apiPrefix)
+}
+
+// legacyAlias serves an unversioned /api route as an alias of its current
+// version. Reads are permanently redirected, so clients and caches move over;
+// writes, which clients may not repeat after a redirect, are served in place.
+// Both announce the deprecation with the current route as the successor.
+func (r *Router) legacyAlias() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		successor := versioned(c.Request.URL.EscapedPath())
+		d := middleware.Deprecation{Since: legacyDeprecatedSince, Sunset: r.legacySunset, Successor: successor}
+		d.SetHeaders(c)
+
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			if c.Request.URL.RawQuery != "" {
+				successor += "?" + c.Request.URL.RawQuery
+			}
+			c.Redirect(http.StatusMovedPermanently, successor)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
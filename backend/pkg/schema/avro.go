@@ -0,0 +1,177 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type avroRecord struct {
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Doc       string `json:"doc,omitempty"`
+	// Version is the payload version of a top-level record
+	Version int         `json:"version,omitempty"`
+	Fields  []avroField `json:"fields"`
+}
+
+type avroField struct {
+	Name string `json:"name"`
+	Type any    `json:"type"`
+	// Default is null for optional fields, so readers fill in missing ones
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+type avroCollection struct {
+	Type   string `json:"type"`
+	Items  any    `json:"items,omitempty"`
+	Values any    `json:"values,omitempty"`
+}
+
+// Avro renders the schema as an Avro record in namespace. Optional fields are
+// unions with null defaulting to null, and nested records are defined where
+// first used.
+func (s Schema) Avro(namespace string) ([]byte, error) {
+	defined := map[string]bool{s.Name: true}
+	record := avroRecord{
+		Type:      Record,
+		Name:      s.Name,
+		Namespace: namespace,
+		Doc:       s.Doc,
+		Version:   s.Version,
+		Fields:    avroFields(s.Fields, defined),
+	}
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s avro schema: %w", s.Name, err)
+	}
+	return append(data, '\n'), nil
+}
+
+func avroFields(fields []Field, defined map[string]bool) []avroField {
+	avro := make([]avroField, len(fields))
+	for i, field := range fields {
+		avro[i] = avroField{Name: field.Name, Type: avroType(field.Type, defined)}
+		if field.Optional {
+			avro[i].Type = []any{"null", avro[i].Type}
+			avro[i].Default = json.RawMessage("null")
+		}
+	}
+	return avro
+}
+
+func avroType(t Type, defined map[string]bool) any {
+	switch t.Name {
+	case Array:
+		return avroCollection{Type: Array, Items: avroType(*t.Items, defined)}
+	case Map:
+		return avroCollection{Type: Map, Values: avroType(*t.Items, defined)}
+	case Record:
+		// A record type is defined once and referenced by name after
+		if defined[t.RecordName] {
+			return t.RecordName
+		}
+		defined[t.RecordName] = true
+		return avroRecord{Type: Record, Name: t.RecordName, Fields: avroFields(t.Fields, defined)}
+	default:
+		return t.Name
+	}
+}
+
+// ParseAvro reads a schema rendered by Avro back, to check a new revision
+// against it
+func ParseAvro(data []byte) (Schema, error) {
+	var record struct {
+		Name    string            `json:"name"`
+		Doc     string            `json:"doc"`
+		Version int               `json:"version"`
+		Fields  []json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Schema{}, fmt.Errorf("invalid avro schema: %w", err)
+	}
+	records := map[string]Type{}
+	fields, err := parseAvroFields(record.Fields, records)
+	if err != nil {
+		return Schema{}, fmt.Errorf("invalid avro schema %s: %w", record.Name, err)
+	}
+	return Schema{Name: record.Name, Version: record.Version, Doc: record.Doc, Fields: fields}, nil
+}
+
+func parseAvroFields(raw []json.RawMessage, records map[string]Type) ([]Field, error) {
+	fields := make([]Field, len(raw))
+	for i, data := range raw {
+		var field struct {
+			Name string          `json:"name"`
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(data, &field); err != nil {
+			return nil, err
+		}
+		fields[i].Name = field.Name
+
+		// Optional fields are unions with null
+		var union []json.RawMessage
+		if json.Unmarshal(field.Type, &union) == nil {
+			if len(union) != 2 || string(union[0]) != `"null"` {
+				return nil, fmt.Errorf("%s: only unions of null and one type are supported", field.Name)
+			}
+			fields[i].Optional = true
+			field.Type = union[1]
+		}
+
+		fieldType, err := parseAvroType(field.Type, records)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.Name, err)
+		}
+		fields[i].Type = fieldType
+	}
+	return fields, nil
+}
+
+func parseAvroType(data json.RawMessage, records map[string]Type) (Type, error) {
+	var name string
+	if json.Unmarshal(data, &name) == nil {
+		switch name {
+		case Boolean, Int, Long, Float, Double, String:
+			return Type{Name: name}, nil
+		}
+		if record, ok := records[name]; ok {
+			return record, nil
+		}
+		return Type{}, fmt.Errorf("unknown type %q", name)
+	}
+
+	var complex struct {
+		Type   string            `json:"type"`
+		Name   string            `json:"name"`
+		Items  json.RawMessage   `json:"items"`
+		Values json.RawMessage   `json:"values"`
+		Fields []json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &complex); err != nil {
+		return Type{}, err
+	}
+	switch complex.Type {
+	case Array, Map:
+		elem := complex.Items
+		if complex.Type == Map {
+			elem = complex.Values
+		}
+		items, err := parseAvroType(elem, records)
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Name: complex.Type, Items: &items}, nil
+	case Record:
+		fields, err := parseAvroFields(complex.Fields, records)
+		if err != nil {
+			return Type{}, err
+		}
+		record := Type{Name: Record, RecordName: complex.Name, Fields: fields}
+		records[complex.Name] = record
+		return record, nil
+	default:
+		return Type{}, fmt.Errorf("unsupported type %q", complex.Type)
+	}
+}
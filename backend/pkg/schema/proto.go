@@ -0,0 +1,109 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var protoScalars = map[string]string{
+	Boolean: "bool",
+	Int:     "int32",
+	Long:    "int64",
+	Float:   "float",
+	Double:  "double",
+	String:  "string",
+}
+
+// Proto renders the schema as a proto3 message in package pkg. Field numbers
+// follow the order of the fields, which Compatible keeps stable within a
+// version, and each field keeps its JSON name. Nested records are nested
+// messages.
+func (s Schema) Proto(pkg string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Code generated by schemagen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "syntax = \"proto3\";\n\npackage %s;\n\n", pkg)
+	if s.Doc != "" {
+		fmt.Fprintf(&b, "// %s\n", s.Doc)
+	}
+	fmt.Fprintf(&b, "// Version %d of the %s payload.\n", s.Version, s.Name)
+	if err := writeMessage(&b, s.Name, s.Fields, "", map[string]bool{}); err != nil {
+		return "", fmt.Errorf("%s: %w", s.Name, err)
+	}
+	return b.String(), nil
+}
+
+func writeMessage(b *strings.Builder, name string, fields []Field, indent string, written map[string]bool) error {
+	fmt.Fprintf(b, "%smessage %s {\n", indent, name)
+	for _, field := range fields {
+		if field.Type.Name == Record && !written[field.Type.RecordName] {
+			written[field.Type.RecordName] = true
+			if err := writeMessage(b, field.Type.RecordName, field.Type.Fields, indent+"  ", written); err != nil {
+				return err
+			}
+		}
+		if items := field.Type.Items; items != nil && items.Name == Record && !written[items.RecordName] {
+			written[items.RecordName] = true
+			if err := writeMessage(b, items.RecordName, items.Fields, indent+"  ", written); err != nil {
+				return err
+			}
+		}
+	}
+	for i, field := range fields {
+		declared, err := protoType(field)
+		if err != nil {
+			return fmt.Errorf("%s: %w", field.Name, err)
+		}
+		fmt.Fprintf(b, "%s  %s %s = %d [json_name = %q];\n", indent, declared, snakeCase(field.Name), i+1, field.Name)
+	}
+	fmt.Fprintf(b, "%s}\n", indent)
+	return nil
+}
+
+// protoType declares a field's type: optional scalars track presence, lists
+// are repeated and maps are proto maps
+func protoType(field Field) (string, error) {
+	element := func(t Type) (string, error) {
+		if t.Name == Record {
+			return t.RecordName, nil
+		}
+		if scalar, ok := protoScalars[t.Name]; ok {
+			return scalar, nil
+		}
+		return "", fmt.Errorf("nested %s cannot be a protobuf element", t.Name)
+	}
+
+	switch field.Type.Name {
+	case Array:
+		items, err := element(*field.Type.Items)
+		return "repeated " + items, err
+	case Map:
+		values, err := element(*field.Type.Items)
+		return "map<string, " + values + ">", err
+	}
+	declared, err := element(field.Type)
+	if err != nil {
+		return "", err
+	}
+	if field.Optional {
+		declared = "optional " + declared
+	}
+	return declared, nil
+}
+
+// snakeCase converts a JSON name such as triggeredUTC to triggered_utc
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			previous := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
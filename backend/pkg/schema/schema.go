@@ -0,0 +1,224 @@
+// Package schema describes event payloads as versioned records, renders them
+// as Avro and Protocol Buffers schemas for consumers in other languages, and
+// checks that a new revision of a version stays compatible with the old one.
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Types of a field, named after Avro's
+const (
+	Boolean = "boolean"
+	Int     = "int"
+	Long    = "long"
+	Float   = "float"
+	Double  = "double"
+	String  = "string"
+	Array   = "array"
+	Map     = "map"
+	Record  = "record"
+)
+
+// Type is the type of a field. Arrays and maps describe their elements with
+// Items, records their fields with Fields.
+type Type struct {
+	Name   string
+	Items  *Type
+	Fields []Field
+	// RecordName names a record type
+	RecordName string
+}
+
+// Field is a field of a record, named as in the payload's JSON
+type Field struct {
+	Name string
+	Type Type
+	// Optional fields may be missing from a payload; they are omitted when
+	// empty or added after the version was first published
+	Optional bool
+}
+
+// Schema is the schema of one version of an event's payload
+type Schema struct {
+	// Name is the event type, which names the top-level record
+	Name    string
+	Version int
+	Doc     string
+	Fields  []Field
+}
+
+// Reflect returns the schema of struct v's JSON encoding. Fields tagged
+// omitempty, and pointers, are optional; embedded structs are flattened like
+// encoding/json does.
+func Reflect(name string, version int, doc string, v any) (Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return Schema{}, fmt.Errorf("%s payload is %v, not a struct", name, t)
+	}
+	fields, err := structFields(t, map[reflect.Type]bool{})
+	if err != nil {
+		return Schema{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return Schema{Name: name, Version: version, Doc: doc, Fields: fields}, nil
+}
+
+func structFields(t reflect.Type, seen map[reflect.Type]bool) ([]Field, error) {
+	if seen[t] {
+		return nil, fmt.Errorf("%s is recursive", t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded, err := structFields(field.Type, seen)
+			if err != nil {
+				return nil, err
+			}
+			fields = append(fields, embedded...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldType, err := typeOf(field.Type, seen)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		fields = append(fields, Field{
+			Name:     name,
+			Type:     fieldType,
+			Optional: strings.Contains(opts, "omitempty") || field.Type.Kind() == reflect.Pointer,
+		})
+	}
+	return fields, nil
+}
+
+func typeOf(t reflect.Type, seen map[reflect.Type]bool) (Type, error) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeOf(t.Elem(), seen)
+	case reflect.Bool:
+		return Type{Name: Boolean}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return Type{Name: Int}, nil
+	case reflect.Int, reflect.Int64, reflect.Uint32:
+		return Type{Name: Long}, nil
+	case reflect.Float32:
+		return Type{Name: Float}, nil
+	case reflect.Float64:
+		return Type{Name: Double}, nil
+	case reflect.String:
+		return Type{Name: String}, nil
+	case reflect.Slice, reflect.Array:
+		items, err := typeOf(t.Elem(), seen)
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Name: Array, Items: &items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return Type{}, fmt.Errorf("map keys must be strings, not %s", t.Key())
+		}
+		values, err := typeOf(t.Elem(), seen)
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Name: Map, Items: &values}, nil
+	case reflect.Struct:
+		fields, err := structFields(t, seen)
+		if err != nil {
+			return Type{}, err
+		}
+		return Type{Name: Record, RecordName: t.Name(), Fields: fields}, nil
+	default:
+		return Type{}, fmt.Errorf("%s cannot be described", t)
+	}
+}
+
+// Compatible reports the changes from previous to next that break readers of
+// either revision of a version. Fields may only be added, as optional fields
+// after the existing ones, so that Protocol Buffers field numbers stay put.
+func Compatible(previous, next Schema) error {
+	if previous.Name != next.Name || previous.Version != next.Version {
+		return fmt.Errorf("%s v%d is not a revision of %s v%d", next.Name, next.Version, previous.Name, previous.Version)
+	}
+	errs := compatibleFields(next.Name, previous.Fields, next.Fields)
+	if len(errs) > 0 {
+		return fmt.Errorf("%s v%d changed incompatibly, publish the change as v%d: %w",
+			next.Name, next.Version, next.Version+1, errors.Join(errs...))
+	}
+	return nil
+}
+
+func compatibleFields(path string, previous, next []Field) []error {
+	var errs []error
+	for i, old := range previous {
+		if i >= len(next) || next[i].Name != old.Name {
+			if index := fieldIndex(next, old.Name); index >= 0 {
+				errs = append(errs, fmt.Errorf("%s.%s moved from position %d to %d", path, old.Name, i+1, index+1))
+			} else {
+				errs = append(errs, fmt.Errorf("%s.%s was removed", path, old.Name))
+			}
+			continue
+		}
+		current := next[i]
+		if old.Optional != current.Optional {
+			errs = append(errs, fmt.Errorf("%s.%s changed from %s to %s", path, old.Name, optionality(old), optionality(current)))
+		}
+		errs = append(errs, compatibleTypes(path+"."+old.Name, old.Type, current.Type)...)
+	}
+	for _, added := range next[min(len(previous), len(next)):] {
+		if fieldIndex(previous, added.Name) < 0 && !added.Optional {
+			errs = append(errs, fmt.Errorf("%s.%s was added as a required field", path, added.Name))
+		}
+	}
+	return errs
+}
+
+func compatibleTypes(path string, previous, next Type) []error {
+	if previous.Name != next.Name {
+		return []error{fmt.Errorf("%s changed type from %s to %s", path, previous.Name, next.Name)}
+	}
+	switch previous.Name {
+	case Array, Map:
+		return compatibleTypes(path+"[]", *previous.Items, *next.Items)
+	case Record:
+		return compatibleFields(path, previous.Fields, next.Fields)
+	}
+	return nil
+}
+
+func fieldIndex(fields []Field, name string) int {
+	for i, field := range fields {
+		if field.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func optionality(f Field) string {
+	if f.Optional {
+		return "optional"
+	}
+	return "required"
+}
@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBar struct {
+	Open  float32 `json:"open"`
+	Close float32 `json:"close"`
+}
+
+type testEvent struct {
+	Symbol    string             `json:"symbol"`
+	KeyID     string             `json:"keyId,omitempty"`
+	Count     int                `json:"count"`
+	Threshold *float64           `json:"threshold"`
+	Tags      []string           `json:"tags"`
+	Bars      []testBar          `json:"bars"`
+	Latest    testBar            `json:"latest"`
+	Weights   map[string]float64 `json:"weights,omitempty"`
+	ignored   string
+	Skipped   string `json:"-"`
+	testEmbedded
+}
+
+type testEmbedded struct {
+	TriggeredUTC int64 `json:"triggeredUTC"`
+}
+
+func TestReflect(t *testing.T) {
+	s, err := Reflect("Test", 2, "A test event.", testEvent{})
+	require.NoError(t, err)
+
+	bar := Type{Name: Record, RecordName: "testBar", Fields: []Field{
+		{Name: "open", Type: Type{Name: Float}},
+		{Name: "close", Type: Type{Name: Float}},
+	}}
+	assert.Equal(t, Schema{Name: "Test", Version: 2, Doc: "A test event.", Fields: []Field{
+		{Name: "symbol", Type: Type{Name: String}},
+		{Name: "keyId", Type: Type{Name: String}, Optional: true},
+		{Name: "count", Type: Type{Name: Long}},
+		{Name: "threshold", Type: Type{Name: Double}, Optional: true},
+		{Name: "tags", Type: Type{Name: Array, Items: &Type{Name: String}}},
+		{Name: "bars", Type: Type{Name: Array, Items: &bar}},
+		{Name: "latest", Type: bar},
+		{Name: "weights", Type: Type{Name: Map, Items: &Type{Name: Double}}, Optional: true},
+		{Name: "triggeredUTC", Type: Type{Name: Long}},
+	}}, s)
+
+	_, err = Reflect("Test", 1, "", "not a struct")
+	assert.Error(t, err)
+}
+
+func TestAvro_RoundTrip(t *testing.T) {
+	s, err := Reflect("Test", 2, "A test event.", testEvent{})
+	require.NoError(t, err)
+
+	avro, err := s.Avro("profitify.events")
+	require.NoError(t, err)
+	assert.Contains(t, string(avro), `"namespace": "profitify.events"`)
+	assert.Contains(t, string(avro), `"default": null`)
+
+	parsed, err := ParseAvro(avro)
+	require.NoError(t, err)
+	assert.Equal(t, s, parsed, "the record type used twice is defined once and referenced")
+}
+
+func TestProto(t *testing.T) {
+	s, err := Reflect("Test", 2, "A test event.", testEvent{})
+	require.NoError(t, err)
+
+	proto, err := s.Proto("profitify.events.v2")
+	require.NoError(t, err)
+	assert.Contains(t, proto, "package profitify.events.v2;")
+	assert.Contains(t, proto, "message Test {\n  message testBar {\n    float open = 1 [json_name = \"open\"];")
+	assert.Contains(t, proto, `  optional string key_id = 2 [json_name = "keyId"];`)
+	assert.Contains(t, proto, `  repeated testBar bars = 6 [json_name = "bars"];`)
+	assert.Contains(t, proto, `  map<string, double> weights = 8 [json_name = "weights"];`)
+	assert.Contains(t, proto, `  int64 triggered_utc = 9 [json_name = "triggeredUTC"];`)
+
+	_, err = Schema{Name: "Nested", Fields: []Field{
+		{Name: "grid", Type: Type{Name: Array, Items: &Type{Name: Array, Items: &Type{Name: Long}}}},
+	}}.Proto("p")
+	assert.ErrorContains(t, err, "nested array")
+}
+
+func TestCompatible(t *testing.T) {
+	base := Schema{Name: "Test", Version: 1, Fields: []Field{
+		{Name: "symbol", Type: Type{Name: String}},
+		{Name: "close", Type: Type{Name: Double}},
+		{Name: "keyId", Type: Type{Name: String}, Optional: true},
+	}}
+	revise := func(fields ...Field) Schema {
+		return Schema{Name: "Test", Version: 1, Fields: fields}
+	}
+
+	assert.NoError(t, Compatible(base, base))
+	assert.NoError(t, Compatible(base, revise(append(base.Fields, Field{Name: "venue", Type: Type{Name: String}, Optional: true})...)),
+		"optional fields may be appended")
+
+	tests := []struct {
+		name string
+		next Schema
+		want string
+	}{
+		{
+			name: "removed field",
+			next: revise(base.Fields[0], base.Fields[2]),
+			want: "Test.close was removed",
+		},
+		{
+			name: "changed type",
+			next: revise(base.Fields[0], Field{Name: "close", Type: Type{Name: Float}}, base.Fields[2]),
+			want: "Test.close changed type from double to float",
+		},
+		{
+			name: "required field added",
+			next: revise(append(base.Fields, Field{Name: "venue", Type: Type{Name: String}})...),
+			want: "Test.venue was added as a required field",
+		},
+		{
+			name: "optional field made required",
+			next: revise(base.Fields[0], base.Fields[1], Field{Name: "keyId", Type: Type{Name: String}}),
+			want: "Test.keyId changed from optional to required",
+		},
+		{
+			name: "field inserted before existing ones",
+			next: revise(base.Fields[0], Field{Name: "venue", Type: Type{Name: String}, Optional: true}, base.Fields[1], base.Fields[2]),
+			want: "Test.close moved from position 2 to 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Compatible(base, tt.next)
+			assert.ErrorContains(t, err, tt.want)
+			assert.ErrorContains(t, err, "publish the change as v2")
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package sqs sends, receives and deletes the messages of an Amazon SQS queue.
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"profitify-backend/pkg/awsclient"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+const (
+	// MaxMessages is the most messages one receive returns
+	MaxMessages = 10
+	// MaxWait is the longest a receive long polls for messages
+	MaxWait = 20 * time.Second
+	// DefaultTimeout is how long requests may take beyond their wait
+	DefaultTimeout = 10 * time.Second
+	// MaxVisibility is the longest a message can be hidden for
+	MaxVisibility = 12 * time.Hour
+)
+
+// Message is a received message, deleted by its receipt handle once handled
+type Message struct {
+	ID            string            `json:"MessageId"`
+	ReceiptHandle string            `json:"ReceiptHandle"`
+	Body          string            `json:"Body"`
+	Attributes    map[string]string `json:"Attributes,omitempty"`
+}
+
+// ReceiveCount is how many times the message has been received, this time
+// included; it is 1 when SQS did not report it
+func (m Message) ReceiveCount() int {
+	n, err := strconv.Atoi(m.Attributes["ApproximateReceiveCount"])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// Queue is one SQS queue, reached through the SQS JSON protocol
+type Queue struct {
+	api *awsclient.SignedClient
+	url string
+}
+
+// New returns the queue at queueURL. Requests time out after a long poll's
+// wait plus timeout.
+func New(awsCfg aws.Config, queueURL, endpointURL string, timeout time.Duration) *Queue {
+	return &Queue{
+		api: awsclient.NewSignedClient(awsCfg, "sqs", endpointURL, MaxWait+timeout),
+		url: queueURL,
+	}
+}
+
+// Receive long polls for up to max messages, waiting at most wait for the
+// first. Received messages are hidden from other consumers until their
+// visibility timeout passes, unless deleted first.
+func (q *Queue) Receive(ctx context.Context, max int, wait time.Duration) ([]Message, error) {
+	var resp struct {
+		Messages []Message `json:"Messages"`
+	}
+	err := q.call(ctx, "ReceiveMessage", map[string]any{
+		"QueueUrl":                    q.url,
+		"MaxNumberOfMessages":         min(max, MaxMessages),
+		"WaitTimeSeconds":             int(min(wait, MaxWait) / time.Second),
+		"MessageSystemAttributeNames": []string{"ApproximateReceiveCount"},
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Messages, nil
+}
+
+// Delete removes a handled message from the queue
+func (q *Queue) Delete(ctx context.Context, receiptHandle string) error {
+	return q.call(ctx, "DeleteMessage", map[string]any{
+		"QueueUrl":      q.url,
+		"ReceiptHandle": receiptHandle,
+	}, nil)
+}
+
+// ChangeVisibility hides a received message from other consumers for timeout
+// from now, rounded down to whole seconds. Zero makes it visible at once.
+func (q *Queue) ChangeVisibility(ctx context.Context, receiptHandle string, timeout time.Duration) error {
+	return q.call(ctx, "ChangeMessageVisibility", map[string]any{
+		"QueueUrl":          q.url,
+		"ReceiptHandle":     receiptHandle,
+		"VisibilityTimeout": int(min(max(timeout, 0), MaxVisibility) / time.Second),
+	}, nil)
+}
+
+// Send adds a message with body to the queue
+func (q *Queue) Send(ctx context.Context, body string) error {
+	return q.call(ctx, "SendMessage", map[string]any{
+		"QueueUrl":    q.url,
+		"MessageBody": body,
+	}, nil)
+}
+
+func (q *Queue) call(ctx context.Context, action string, input map[string]any, output any) error {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", action, err)
+	}
+	respBody, err := q.api.Post(ctx, body, http.Header{
+		"Content-Type": {"application/x-amz-json-1.0"},
+		"X-Amz-Target": {"AmazonSQS." + action},
+	})
+	if err != nil {
+		return fmt.Errorf("sqs %s failed: %w", action, err)
+	}
+	if output == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", action, err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package sqs
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAWS = aws.Config{
+	Region: "us-east-1",
+	Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	}),
+}
+
+func TestQueue(t *testing.T) {
+	var requests []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-amz-json-1.0", r.Header.Get("Content-Type"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/us-east-1/sqs/aws4_request")
+
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		requests = append(requests, body)
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "AmazonSQS.ReceiveMessage":
+			_, _ = io.WriteString(w, `{"Messages":[{"MessageId":"m1","ReceiptHandle":"rh1","Body":"{}","Attributes":{"ApproximateReceiveCount":"3"}}]}`)
+		case "AmazonSQS.DeleteMessage", "AmazonSQS.ChangeMessageVisibility", "AmazonSQS.SendMessage":
+			_, _ = io.WriteString(w, `{}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = io.WriteString(w, `{"__type":"InvalidAction"}`)
+		}
+	}))
+	defer server.Close()
+
+	queue := New(testAWS, "https://sqs.us-east-1.amazonaws.com/123/triggers", server.URL, time.Second)
+
+	messages, err := queue.Receive(context.Background(), 50, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, Message{ID: "m1", ReceiptHandle: "rh1", Body: "{}", Attributes: map[string]string{"ApproximateReceiveCount": "3"}}, messages[0])
+	assert.Equal(t, 3, messages[0].ReceiveCount())
+	require.NoError(t, queue.ChangeVisibility(context.Background(), "rh1", 90*time.Second+500*time.Millisecond))
+	require.NoError(t, queue.Send(context.Background(), `{"type":"tickers"}`))
+	require.NoError(t, queue.Delete(context.Background(), "rh1"))
+
+	require.Len(t, requests, 4)
+	assert.Equal(t, map[string]any{
+		"QueueUrl":                    "https://sqs.us-east-1.amazonaws.com/123/triggers",
+		"MaxNumberOfMessages":         float64(MaxMessages),
+		"WaitTimeSeconds":             float64(20),
+		"MessageSystemAttributeNames": []any{"ApproximateReceiveCount"},
+	}, requests[0], "the batch size and wait are capped")
+	assert.Equal(t, float64(90), requests[1]["VisibilityTimeout"])
+	assert.Equal(t, `{"type":"tickers"}`, requests[2]["MessageBody"])
+	assert.Equal(t, "rh1", requests[3]["ReceiptHandle"])
+}
+
+func TestMessage_ReceiveCount(t *testing.T) {
+	assert.Equal(t, 1, Message{}.ReceiveCount(), "unreported counts as the first receive")
+	assert.Equal(t, 1, Message{Attributes: map[string]string{"ApproximateReceiveCount": "x"}}.ReceiveCount())
+	assert.Equal(t, 4, Message{Attributes: map[string]string{"ApproximateReceiveCount": "4"}}.ReceiveCount())
+}
@@ -0,0 +1,179 @@
+// Package tasks runs the background goroutines of the server, long-lived ones
+// such as schedulers and consumers as well as one-off jobs started on request,
+// ties them to the server's lifetime and reports their health.
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrStopTimeout is returned by Stop when tasks are still running at the timeout
+var ErrStopTimeout = errors.New("tasks did not stop in time")
+
+// State is the lifecycle state of a task
+type State string
+
+const (
+	StateRunning State = "running"
+	// StateStopped tasks returned without error, or because they were stopped
+	StateStopped State = "stopped"
+	// StateFailed tasks returned an error or panicked
+	StateFailed State = "failed"
+)
+
+// Status reports the health of a task
+type Status struct {
+	Name       string `json:"name"`
+	State      State  `json:"state"`
+	StartedUTC int64  `json:"startedUTC"`
+	StoppedUTC int64  `json:"stoppedUTC,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Manager starts background tasks under a context derived from the server's.
+// Tasks are expected to return once their context is done.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	log    *zap.SugaredLogger
+	wg     sync.WaitGroup
+
+	mu    sync.Mutex
+	tasks map[string]*Status
+}
+
+// New creates a manager whose tasks stop when ctx is cancelled or Stop is called
+func New(ctx context.Context, log *zap.SugaredLogger) *Manager {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Manager{
+		ctx:    ctx,
+		cancel: cancel,
+		log:    log,
+		tasks:  make(map[string]*Status),
+	}
+}
+
+// Go starts fn in its own goroutine as the named task. A task that returns
+// before it is stopped is reported as stopped, or failed if it returned an
+// error or panicked; it is not restarted. Names must be unique.
+func (m *Manager) Go(name string, fn func(ctx context.Context) error) {
+	if !m.start(name, fn, false) {
+		panic(fmt.Sprintf("tasks: duplicate task %q", name))
+	}
+}
+
+// Start starts fn as a one-off named task, such as a job started on request,
+// unless a task of that name is still running, and reports whether it
+// started. A finished task of the same name is replaced.
+func (m *Manager) Start(name string, fn func(ctx context.Context) error) bool {
+	return m.start(name, fn, true)
+}
+
+func (m *Manager) start(name string, fn func(ctx context.Context) error, oneOff bool) bool {
+	status := &Status{Name: name, State: StateRunning, StartedUTC: time.Now().Unix()}
+
+	m.mu.Lock()
+	if existing, exists := m.tasks[name]; exists && (!oneOff || existing.State == StateRunning) {
+		m.mu.Unlock()
+		return false
+	}
+	m.tasks[name] = status
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		err := m.run(fn)
+		if errors.Is(err, context.Canceled) && m.ctx.Err() != nil {
+			err = nil
+		}
+
+		m.mu.Lock()
+		status.StoppedUTC = time.Now().Unix()
+		if err != nil {
+			status.State = StateFailed
+			status.Error = err.Error()
+		} else {
+			status.State = StateStopped
+		}
+		m.mu.Unlock()
+
+		switch {
+		case err != nil:
+			m.log.Errorw("background task failed", "task", name, "error", err)
+		case oneOff && m.ctx.Err() == nil:
+			m.log.Infow("background task completed", "task", name)
+		case m.ctx.Err() == nil:
+			m.log.Warnw("background task returned before shutdown", "task", name)
+		default:
+			m.log.Infow("background task stopped", "task", name)
+		}
+	}()
+
+	m.log.Infow("background task started", "task", name)
+	return true
+}
+
+// run calls fn, turning a panic into an error so one task cannot take down the server
+func (m *Manager) run(fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(m.ctx)
+}
+
+// Statuses returns the status of every task, sorted by name
+func (m *Manager) Statuses() []Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	statuses := make([]Status, 0, len(m.tasks))
+	for _, status := range m.tasks {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Stop cancels every task and blocks until they return or timeout elapses,
+// in which case the tasks still running are named in the ErrStopTimeout error
+func (m *Manager) Stop(timeout time.Duration) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		m.log.Info("background tasks stopped")
+		return nil
+	case <-timer.C:
+	}
+
+	var running []string
+	for _, status := range m.Statuses() {
+		if status.State == StateRunning {
+			running = append(running, status.Name)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrStopTimeout, strings.Join(running, ", "))
+}
@@ -0,0 +1,106 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestManager_Lifecycle(t *testing.T) {
+	m := New(context.Background(), zap.NewNop().Sugar())
+
+	m.Go("scheduler", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	m.Go("consumer", func(ctx context.Context) error {
+		return errors.New("stream closed")
+	})
+	m.Go("evaluator", func(ctx context.Context) error {
+		panic("boom")
+	})
+
+	require.Eventually(t, func() bool {
+		statuses := m.Statuses()
+		return statuses[0].State == StateFailed && statuses[1].State == StateFailed
+	}, time.Second, 5*time.Millisecond)
+
+	statuses := m.Statuses()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "consumer", statuses[0].Name)
+	assert.Equal(t, "stream closed", statuses[0].Error)
+	assert.Equal(t, "panic: boom", statuses[1].Error)
+	assert.Equal(t, StateRunning, statuses[2].State)
+
+	require.NoError(t, m.Stop(time.Second))
+	assert.Equal(t, StateStopped, m.Statuses()[2].State, "cancellation at shutdown is not a failure")
+}
+
+func TestManager_StopTimeout(t *testing.T) {
+	m := New(context.Background(), zap.NewNop().Sugar())
+
+	release := make(chan struct{})
+	defer close(release)
+	m.Go("stuck", func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+	m.Go("prompt", func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+
+	err := m.Stop(20 * time.Millisecond)
+	assert.ErrorIs(t, err, ErrStopTimeout)
+	assert.ErrorContains(t, err, "stuck")
+	assert.NotContains(t, err.Error(), "prompt")
+}
+
+func TestManager_ParentContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	m := New(ctx, zap.NewNop().Sugar())
+
+	stopped := make(chan struct{})
+	m.Go("scheduler", func(ctx context.Context) error {
+		<-ctx.Done()
+		close(stopped)
+		return nil
+	})
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("task did not stop with the server context")
+	}
+}
+
+func TestManager_Start(t *testing.T) {
+	m := New(context.Background(), zap.NewNop().Sugar())
+	defer m.Stop(time.Second)
+
+	release := make(chan struct{})
+	assert.True(t, m.Start("backfill", func(ctx context.Context) error {
+		<-release
+		return nil
+	}))
+	assert.False(t, m.Start("backfill", func(ctx context.Context) error { return nil }), "a running task is not started twice")
+
+	close(release)
+	require.Eventually(t, func() bool {
+		return m.Statuses()[0].State == StateStopped
+	}, time.Second, 5*time.Millisecond)
+
+	assert.True(t, m.Start("backfill", func(ctx context.Context) error {
+		return errors.New("throttled")
+	}), "a finished task can be started again")
+	require.Eventually(t, func() bool {
+		return m.Statuses()[0].State == StateFailed
+	}, time.Second, 5*time.Millisecond)
+	assert.Len(t, m.Statuses(), 1)
+}
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+)
+
+// logExporter writes finished spans to the application log, for development
+// without a collector
+type logExporter struct {
+	log *zap.SugaredLogger
+}
+
+// NewLogExporter returns an exporter logging each span at debug level
+func NewLogExporter(log *zap.SugaredLogger) sdktrace.SpanExporter {
+	return &logExporter{log: log}
+}
+
+func (e *logExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	for _, span := range spans {
+		fields := []any{
+			"name", span.Name(),
+			"trace_id", span.SpanContext().TraceID().String(),
+			"span_id", span.SpanContext().SpanID().String(),
+			"duration", span.EndTime().Sub(span.StartTime()),
+			"status", span.Status().Code.String(),
+		}
+		if span.Parent().IsValid() {
+			fields = append(fields, "parent_id", span.Parent().SpanID().String())
+		}
+		for _, attr := range span.Attributes() {
+			fields = append(fields, string(attr.Key), attr.Value.Emit())
+		}
+		e.log.Debugw("span", fields...)
+	}
+	return nil
+}
+
+func (e *logExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
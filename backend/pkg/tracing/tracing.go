@@ -0,0 +1,109 @@
+// Package tracing sets up OpenTelemetry tracing, so a request is traced from
+// the HTTP handler through the services to its DynamoDB calls, and exported
+// over OTLP.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Exporters spans are sent through
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+	ExporterLog  = "log"
+)
+
+// ServiceName names the service on exported spans
+const ServiceName = "profitify-backend"
+
+// tracerName is the instrumentation scope of the spans started here
+const tracerName = "profitify-backend"
+
+// Config selects the exporter and the share of traces sampled
+type Config struct {
+	// Exporter is "otlp", "log" or "none"
+	Exporter string
+	// Endpoint is the OTLP/HTTP traces URL, e.g. http://localhost:4318/v1/traces;
+	// empty keeps the OTEL_EXPORTER_OTLP_* variables and defaults
+	Endpoint string
+	// SampleRate is the share of new traces recorded, from 0 to 1. Requests
+	// continuing a trace follow the caller's sampling decision.
+	SampleRate  float64
+	Environment string
+}
+
+// Setup installs the global tracer provider and W3C trace context propagation.
+// The returned function flushes buffered spans and stops the exporter.
+func Setup(ctx context.Context, cfg Config, log *zap.SugaredLogger) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+		}
+		otlp, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		}
+		exporter = otlp
+	case ExporterLog:
+		exporter = NewLogExporter(log)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if cfg.SampleRate < 0 || cfg.SampleRate > 1 {
+		return nil, fmt.Errorf("tracing sample rate %v is not between 0 and 1", cfg.SampleRate)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRate))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName(ServiceName),
+			semconv.DeploymentEnvironment(cfg.Environment),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx. Until Setup
+// installs a provider, spans are not recorded.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartKind starts a span of a kind, such as a server span for a request
+func StartKind(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End ends span, marking it failed when err is not nil
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSetup_Invalid(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "jaeger"}, zap.NewNop().Sugar())
+	assert.ErrorContains(t, err, "unknown tracing exporter")
+
+	_, err = Setup(context.Background(), Config{Exporter: ExporterLog, SampleRate: 1.5}, zap.NewNop().Sugar())
+	assert.ErrorContains(t, err, "not between 0 and 1")
+}
+
+func TestSetup_Log(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterLog, SampleRate: 1}, zap.New(core).Sugar())
+	require.NoError(t, err)
+
+	ctx, parent := Start(context.Background(), "job scanner")
+	_, child := Start(ctx, "DynamoDB.Query")
+	End(child, errors.New("throttled"))
+	End(parent, nil)
+	require.NoError(t, shutdown(context.Background()))
+
+	require.Equal(t, 2, logs.Len())
+	childLog, parentLog := logs.All()[0].ContextMap(), logs.All()[1].ContextMap()
+	assert.Equal(t, "DynamoDB.Query", childLog["name"])
+	assert.Equal(t, codes.Error.String(), childLog["status"])
+	assert.Equal(t, parentLog["trace_id"], childLog["trace_id"])
+	assert.Equal(t, parentLog["span_id"], childLog["parent_id"])
+	assert.NotContains(t, parentLog, "parent_id")
+}